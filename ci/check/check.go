@@ -39,7 +39,7 @@ import (
 // Check performs commons checks.
 func Check() {
 	mg.Deps(CheckGenerate)
-	mg.Deps(CheckSwagger)
+	mg.Deps(CheckSwagger, CheckTequilapiClient)
 	mg.Deps(CheckGoImports, CheckDNSMaps, CheckGoLint, CheckGoVet, CheckCopyright)
 }
 
@@ -71,6 +71,19 @@ func CheckSwagger() error {
 	return nil
 }
 
+// CheckTequilapiClient checks whether swagger spec and typed Tequilapi client generated from it match the endpoints.
+func CheckTequilapiClient() error {
+	mg.Deps(packages.GenerateSwagger)
+	mg.Deps(packages.GenerateClient)
+
+	generated := []string{"tequilapi/docs/swagger.json", "tequilapi/client/api_generated.go"}
+	if err := sh.Run("git", append([]string{"diff", "--quiet", "HEAD", "--"}, generated...)...); err != nil {
+		fmt.Println(`Swagger spec or Tequilapi client is out of date, regenerate them with "mage GenerateSwagger GenerateClient"`)
+		return errors.New("tequilapi client is not up-to-date")
+	}
+	return nil
+}
+
 // CheckDNSMaps checks if the given dns maps in the metadata configuration actually point to the given IPS.
 func CheckDNSMaps() error {
 	ipMissmatches := make([]string, 0)
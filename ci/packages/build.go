@@ -99,6 +99,9 @@ func buildBinaryFor(source, target, targetOS, targetArch string, buildStatic boo
 	flags = append(flags, fmt.Sprintf(`-ldflags=-w -s %s`, strings.Join(ldFlags, " ")))
 	if buildStatic {
 		flags = append(flags, "-a", "-tags", "netgo")
+	} else {
+		// SQLite driver requires cgo, which static builds are made without
+		flags = append(flags, "-tags", "sqlite")
 	}
 
 	if targetOS == "windows" {
@@ -30,8 +30,8 @@ import (
 func Generate() {
 	mg.Deps(GenerateProtobuf, GenerateSwagger)
 
-	// Doc generation should occur after swagger generation
-	mg.Deps(GenerateDocs)
+	// Doc and client generation should occur after swagger generation
+	mg.Deps(GenerateDocs, GenerateClient)
 }
 
 // GenerateProtobuf generates Protobuf models.
@@ -47,16 +47,27 @@ func GenerateProtobuf() error {
 	if err := sh.Run("protoc", "-I=.", "--go_out=./pb", "./pb/session.proto"); err != nil {
 		return err
 	}
-	return sh.Run("protoc", "-I=.", "--go_out=./pb", "./pb/payment.proto")
+	if err := sh.Run("protoc", "-I=.", "--go_out=./pb", "./pb/payment.proto"); err != nil {
+		return err
+	}
+	if err := sh.Run("protoc", "-I=.", "--go_out=./pb", "--go-grpc_out=./pb", "./pb/management.proto"); err != nil {
+		return err
+	}
+	return sh.Run("protoc", "-I=.", "--go_out=./pb", "--go-grpc_out=./pb", "./pb/signer.proto")
 }
 
-// GetProtobuf installs protobuf golang compiler.
+// GetProtobuf installs protobuf and gRPC golang compilers.
 func GetProtobuf() error {
 	err := sh.RunV("go", "install", "google.golang.org/protobuf/cmd/protoc-gen-go@v1.25.0")
 	if err != nil {
 		fmt.Println("could not go get 'protoc-gen-go'")
 		return err
 	}
+	err = sh.RunV("go", "install", "google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.2.0")
+	if err != nil {
+		fmt.Println("could not go get 'protoc-gen-go-grpc'")
+		return err
+	}
 	return nil
 }
 
@@ -84,6 +95,11 @@ func GenerateDocs() error {
 	return nil
 }
 
+// GenerateClient generates typed Tequilapi client from the Swagger specification.
+func GenerateClient() error {
+	return sh.RunV("go", "generate", "./tequilapi/client")
+}
+
 // GetSwagger installs swagger tool.
 func GetSwagger() error {
 	err := sh.RunV("go", "install", "github.com/go-swagger/go-swagger/cmd/swagger@v0.29.0")
@@ -29,10 +29,12 @@ import (
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/consumer/entertainment"
 	"github.com/mysteriumnetwork/node/core/node"
+	"github.com/mysteriumnetwork/node/metrics"
 	"github.com/mysteriumnetwork/node/services"
 	"github.com/mysteriumnetwork/node/tequilapi"
 	tequilapi_client "github.com/mysteriumnetwork/node/tequilapi/client"
 	tequilapi_endpoints "github.com/mysteriumnetwork/node/tequilapi/endpoints"
+	"github.com/mysteriumnetwork/node/tequilapi/middlewares"
 	"github.com/mysteriumnetwork/node/ui"
 	uinoop "github.com/mysteriumnetwork/node/ui/noop"
 	"github.com/mysteriumnetwork/node/ui/versionmanager"
@@ -49,12 +51,25 @@ func (di *Dependencies) bootstrapTequilapi(nodeOptions node.Options, listener ne
 		listener,
 		nodeOptions,
 		[]func(engine *gin.Engine) error{
+			func(e *gin.Engine) error {
+				limits, err := middlewares.ParseRateLimits(config.GetStringSlice(config.FlagTequilapiRateLimits))
+				if err != nil {
+					return err
+				}
+				e.Use(
+					middlewares.NewAuditor(di.AuditLog),
+					middlewares.NewScopeAuthorizer(di.APITokens, di.JWTAuthenticator, config.GetBool(config.FlagTequilapiAuthRequired)),
+					middlewares.NewRateLimiter(limits),
+				)
+				return nil
+			},
 			func(e *gin.Engine) error {
 				if err := tequilapi_endpoints.AddRoutesForSSE(e, di.StateKeeper, di.EventBus); err != nil {
 					return err
 				}
 				return nil
 			},
+			tequilapi_endpoints.AddRoutesForEvents(di.EventBus),
 			func(e *gin.Engine) error {
 				if config.GetBool(config.FlagPProfEnable) {
 					tequilapi_endpoints.AddRoutesForPProf(e)
@@ -65,24 +80,74 @@ func (di *Dependencies) bootstrapTequilapi(nodeOptions node.Options, listener ne
 				e.GET("/healthcheck", tequilapi_endpoints.HealthCheckEndpointFactory(time.Now, os.Getpid).HealthCheck)
 				return nil
 			},
+			tequilapi_endpoints.AddRoutesForHealth(di.HealthChecker),
 			tequilapi_endpoints.AddRouteForStop(utils.SoftKiller(di.Shutdown)),
-			tequilapi_endpoints.AddRoutesForAuthentication(di.Authenticator, di.JWTAuthenticator),
-			tequilapi_endpoints.AddRoutesForIdentities(di.IdentityManager, di.IdentitySelector, di.IdentityRegistry, di.ConsumerBalanceTracker, di.AddressProvider, di.HermesChannelRepository, di.BCHelper, di.Transactor, di.BeneficiaryProvider, di.IdentityMover, di.PayoutAddressStorage, di.HermesMigrator),
-			tequilapi_endpoints.AddRoutesForConnection(di.MultiConnectionManager, di.StateKeeper, di.ProposalRepository, di.IdentityRegistry, di.EventBus, di.AddressProvider),
+			tequilapi_endpoints.AddRoutesForAuthentication(di.Authenticator, di.JWTAuthenticator, di.APITokens),
+			tequilapi_endpoints.AddRoutesForAudit(di.AuditLog),
+			tequilapi_endpoints.AddRoutesForIdentities(di.IdentityManager, di.IdentitySelector, di.IdentityRegistry, di.ConsumerBalanceTracker, di.AddressProvider, di.HermesChannelRepository, di.BCHelper, di.Transactor, di.BeneficiaryProvider, di.IdentityMover, di.PayoutAddressStorage, di.HermesMigrator, di.IdentityRotator),
+			tequilapi_endpoints.AddRoutesForConnection(di.MultiConnectionManager, di.StateKeeper, di.ProposalRepository, di.IdentityRegistry, di.EventBus, di.AddressProvider, di.LatencyMeasurer),
+			tequilapi_endpoints.AddRoutesForSpeedTest(di.SpeedTester),
+			tequilapi_endpoints.AddRoutesForConnectionProfiles(di.ConnectionProfiles, di.MultiConnectionManager, di.StateKeeper, di.ProposalRepository, di.IdentityRegistry, di.EventBus, di.AddressProvider, di.LatencyMeasurer),
 			tequilapi_endpoints.AddRoutesForSessions(di.SessionStorage),
+			func(e *gin.Engine) error {
+				if di.SessionAccounting == nil {
+					return nil
+				}
+				return tequilapi_endpoints.AddRoutesForSessionAccounting(di.SessionAccounting)(e)
+			},
+			func(e *gin.Engine) error {
+				if di.TrafficMeter == nil {
+					return nil
+				}
+				return tequilapi_endpoints.AddRoutesForTraffic(di.TrafficMeter)(e)
+			},
+			func(e *gin.Engine) error {
+				if di.SelfCheckAgent == nil {
+					return nil
+				}
+				return tequilapi_endpoints.AddRoutesForSelfCheck(di.SelfCheckAgent)(e)
+			},
+			func(e *gin.Engine) error {
+				if di.StateSyncer == nil {
+					return nil
+				}
+				return tequilapi_endpoints.AddRoutesForStateSync(di.StateSyncer)(e)
+			},
 			tequilapi_endpoints.AddRoutesForConnectionLocation(di.IPResolver, di.LocationResolver, di.LocationResolver),
-			tequilapi_endpoints.AddRoutesForProposals(di.ProposalRepository, di.PricingHelper, di.LocationResolver, di.FilterPresetStorage, di.NATProber),
+			tequilapi_endpoints.AddRoutesForProposals(di.ProposalRepository, di.PricingHelper, di.LocationResolver, di.FilterPresetStorage, di.NATProber, di.LatencyMeasurer),
 			tequilapi_endpoints.AddRoutesForService(di.ServicesManager, services.JSONParsersByType, di.ProposalRepository, tequilaApiClient),
 			tequilapi_endpoints.AddRoutesForAccessPolicies(di.HTTPClient, config.GetString(config.FlagAccessPolicyAddress)),
-			tequilapi_endpoints.AddRoutesForNAT(di.StateKeeper, di.NATProber),
+			tequilapi_endpoints.AddRoutesForNAT(di.StateKeeper, di.NATProber, di.PortMapper),
+			tequilapi_endpoints.AddRoutesForConsumerLists(di.ConsumerLists),
+			tequilapi_endpoints.AddRoutesForRules(di.RulesEngine),
+			tequilapi_endpoints.AddRoutesForSchedule(di.Scheduler),
+			tequilapi_endpoints.AddRoutesForDNS(di.DNSBlocklist),
+			tequilapi_endpoints.AddRoutesForMetrics(metrics.Registry),
 			tequilapi_endpoints.AddRoutesForNodeUI(versionmanager.NewVersionManager(di.UIServer, di.HTTPClient, di.uiVersionConfig)),
-			tequilapi_endpoints.AddRoutesForNode(di.NodeStatusTracker, di.NodeStatsTracker),
+			tequilapi_endpoints.AddRoutesForNode(di.NodeStatusTracker, di.NodeStatsTracker, di.CGNATDetector),
+			func(e *gin.Engine) error {
+				// Binary of the embedded node is updated by the program embedding it.
+				if di.Updater == nil {
+					return nil
+				}
+				return tequilapi_endpoints.AddRoutesForUpdater(di.Updater)(e)
+			},
+			func(e *gin.Engine) error {
+				// Resources are not monitored in consumer mode.
+				if di.ResourceMonitor == nil {
+					return tequilapi_endpoints.AddRoutesForNodeSummary(di.StateKeeper, di.NodeStatusTracker, di.CGNATDetector, di.NATProber, nil)(e)
+				}
+				return tequilapi_endpoints.AddRoutesForNodeSummary(di.StateKeeper, di.NodeStatusTracker, di.CGNATDetector, di.NATProber, di.ResourceMonitor)(e)
+			},
 			tequilapi_endpoints.AddRoutesForTransactor(di.IdentityRegistry, di.Transactor, di.Affiliator, di.HermesPromiseSettler, di.SettlementHistoryStorage, di.AddressProvider, di.BeneficiaryProvider, di.BeneficiarySaver, di.PilvytisAPI),
 			tequilapi_endpoints.AddRoutesForAffiliator(di.Affiliator),
 			tequilapi_endpoints.AddRoutesForConfig,
+			tequilapi_endpoints.AddRoutesForWebhooks(di.Webhooks),
 			tequilapi_endpoints.AddRoutesForMMN(di.MMN),
 			tequilapi_endpoints.AddRoutesForFeedback(di.Reporter),
+			tequilapi_endpoints.AddRoutesForDiagnostics(di.Diagnostics),
 			tequilapi_endpoints.AddRoutesForConnectivityStatus(di.SessionConnectivityStatusStorage),
+			tequilapi_endpoints.AddRoutesForSessionNotices(di.NoticeSender, di.NoticeStorage),
 			tequilapi_endpoints.AddRoutesForDocs,
 			tequilapi_endpoints.AddRoutesForCurrencyExchange(di.PilvytisAPI),
 			tequilapi_endpoints.AddRoutesForPilvytis(di.PilvytisAPI, di.PilvytisOrderIssuer, di.LocationResolver),
@@ -29,44 +29,44 @@ const infoColor = "\033[93m"
 
 // Status prints a message with a given status.
 func Status(label string, items ...interface{}) {
-	fmt.Printf(statusColor+"[%s] \033[0m", label)
-	fmt.Println(sentenceCase(fmt.Sprintln(items...)))
+	fmt.Fprintf(messages, statusColor+"[%s] \033[0m", label)
+	fmt.Fprintln(messages, sentenceCase(fmt.Sprintln(items...)))
 }
 
 // Warn prints a warning.
 func Warn(items ...interface{}) {
-	fmt.Printf(warningColor + "[WARNING] \033[0m")
-	fmt.Println(sentenceCase(fmt.Sprint(items...)))
+	fmt.Fprintf(messages, warningColor+"[WARNING] \033[0m")
+	fmt.Fprintln(messages, sentenceCase(fmt.Sprint(items...)))
 }
 
 // Warnf prints a warning using fmt.Printf.
 func Warnf(format string, items ...interface{}) {
-	fmt.Printf(warningColor + "[WARNING] \033[0m")
-	fmt.Print(sentenceCase(fmt.Sprintf(format, items...)))
+	fmt.Fprintf(messages, warningColor+"[WARNING] \033[0m")
+	fmt.Fprint(messages, sentenceCase(fmt.Sprintf(format, items...)))
 }
 
 // Success prints a success message.
 func Success(items ...interface{}) {
-	fmt.Printf(successColor + "[SUCCESS] \033[0m")
-	fmt.Println(sentenceCase(fmt.Sprint(items...)))
+	fmt.Fprintf(messages, successColor+"[SUCCESS] \033[0m")
+	fmt.Fprintln(messages, sentenceCase(fmt.Sprint(items...)))
 }
 
 // Info prints an information message.
 func Info(items ...interface{}) {
-	fmt.Printf(infoColor + "[INFO] \033[0m")
-	fmt.Println(sentenceCase(fmt.Sprint(items...)))
+	fmt.Fprintf(messages, infoColor+"[INFO] \033[0m")
+	fmt.Fprintln(messages, sentenceCase(fmt.Sprint(items...)))
 }
 
 // Error prints an error message
 func Error(items ...interface{}) {
-	fmt.Printf(warningColor + "[ERROR] \033[0m")
-	fmt.Println(sentenceCase(fmt.Sprint(items...)))
+	fmt.Fprintf(messages, warningColor+"[ERROR] \033[0m")
+	fmt.Fprintln(messages, sentenceCase(fmt.Sprint(items...)))
 }
 
 // Infof prints an information message using fmt.Printf.
 func Infof(format string, items ...interface{}) {
-	fmt.Printf(infoColor + "[INFO] \033[0m")
-	fmt.Print(sentenceCase(fmt.Sprintf(format, items...)))
+	fmt.Fprintf(messages, infoColor+"[INFO] \033[0m")
+	fmt.Fprint(messages, sentenceCase(fmt.Sprintf(format, items...)))
 }
 
 // sentenceCase capitalizes the first letter.
@@ -0,0 +1,221 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Format is a format in which command results are printed.
+type Format string
+
+const (
+	// FormatTable prints results as a human readable table.
+	FormatTable Format = "table"
+	// FormatJSON prints results as JSON documents.
+	FormatJSON Format = "json"
+	// FormatCSV prints results as comma separated values with a header row.
+	FormatCSV Format = "csv"
+)
+
+// FlagOutput selects the format of command results.
+var FlagOutput = cli.StringFlag{
+	Name:    "output",
+	Aliases: []string{"o"},
+	Usage:   "Format of command results: json, table or csv",
+	Value:   string(FormatTable),
+}
+
+var (
+	format                 = FormatTable
+	messages     io.Writer = os.Stdout
+	results      io.Writer = os.Stdout
+	timeType               = reflect.TypeOf(time.Time{})
+	stringerType           = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// ParseFormat parses the given output format name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatTable, FormatJSON, FormatCSV:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, expected one of: json, table, csv", name)
+	}
+}
+
+// SetFormatFromFlag selects the output format given by the output flag.
+func SetFormatFromFlag(ctx *cli.Context) error {
+	f, err := ParseFormat(ctx.String(FlagOutput.Name))
+	if err != nil {
+		return err
+	}
+	SetFormat(f)
+	return nil
+}
+
+// SetFormat selects the format of command results. Machine readable formats
+// move all other messages to stderr, so that stdout only carries results.
+func SetFormat(f Format) {
+	format = f
+	if f == FormatTable {
+		messages = os.Stdout
+	} else {
+		messages = os.Stderr
+	}
+}
+
+// IsMachineReadable returns true if results are printed in a machine readable format.
+func IsMachineReadable() bool {
+	return format != FormatTable
+}
+
+// Messages returns the writer of human oriented messages.
+func Messages() io.Writer {
+	return messages
+}
+
+// PrintResult prints a command result in the selected format. The result
+// must be a struct or a slice of structs, its json tags name the columns.
+func PrintResult(v interface{}) error {
+	return writeResult(results, format, v)
+}
+
+func writeResult(w io.Writer, f Format, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	list := rv.Kind() == reflect.Slice
+	if list && rv.IsNil() {
+		rv = reflect.MakeSlice(rv.Type(), 0, 0)
+		v = rv.Interface()
+	}
+
+	switch f {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case FormatCSV:
+		columns, rows := tabulate(rv)
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	default:
+		columns, rows := tabulate(rv)
+		tw := tabwriter.NewWriter(w, 1, 1, 2, ' ', 0)
+		if list {
+			fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+			for _, row := range rows {
+				fmt.Fprintln(tw, strings.Join(row, "\t"))
+			}
+		} else {
+			for i, column := range columns {
+				fmt.Fprintf(tw, "%s:\t%s\n", column, rows[0][i])
+			}
+		}
+		return tw.Flush()
+	}
+}
+
+// tabulate flattens a struct or a slice of structs into columns and rows.
+func tabulate(rv reflect.Value) (columns []string, rows [][]string) {
+	elemType := rv.Type()
+	if rv.Kind() == reflect.Slice {
+		elemType = elemType.Elem()
+	}
+
+	var fields []int
+	for i := 0; i < elemType.NumField(); i++ {
+		name := columnName(elemType.Field(i))
+		if name == "" {
+			continue
+		}
+		fields = append(fields, i)
+		columns = append(columns, name)
+	}
+
+	addRow := func(elem reflect.Value) {
+		row := make([]string, len(fields))
+		for i, field := range fields {
+			row[i] = formatValue(elem.Field(field))
+		}
+		rows = append(rows, row)
+	}
+	if rv.Kind() == reflect.Slice {
+		for i := 0; i < rv.Len(); i++ {
+			addRow(rv.Index(i))
+		}
+	} else {
+		addRow(rv)
+	}
+
+	return columns, rows
+}
+
+func columnName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
+
+func formatValue(v reflect.Value) string {
+	switch {
+	case v.Type() == timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	case v.Kind() == reflect.Ptr && v.IsNil():
+		return ""
+	case v.Type().Implements(stringerType):
+		return v.Interface().(fmt.Stringer).String()
+	case v.Kind() == reflect.Ptr:
+		return formatValue(v.Elem())
+	case v.Kind() == reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatValue(v.Index(i))
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
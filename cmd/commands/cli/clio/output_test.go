@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clio
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testResult struct {
+	ID       string   `json:"id"`
+	Tags     []string `json:"tags"`
+	Amount   *big.Int `json:"amount"`
+	Active   bool     `json:"active"`
+	internal string
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("JSON")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJSON, f)
+
+	_, err = ParseFormat("yaml")
+	assert.Error(t, err)
+}
+
+func TestWriteResult(t *testing.T) {
+	results := []testResult{
+		{ID: "a", Tags: []string{"x", "y"}, Amount: big.NewInt(10), Active: true},
+		{ID: "b"},
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		value  interface{}
+		want   string
+	}{
+		{
+			name:   "json list",
+			format: FormatJSON,
+			value:  results[:1],
+			want:   "[\n  {\n    \"id\": \"a\",\n    \"tags\": [\n      \"x\",\n      \"y\"\n    ],\n    \"amount\": 10,\n    \"active\": true\n  }\n]\n",
+		},
+		{
+			name:   "json empty list",
+			format: FormatJSON,
+			value:  []testResult(nil),
+			want:   "[]\n",
+		},
+		{
+			name:   "csv list",
+			format: FormatCSV,
+			value:  results,
+			want:   "id,tags,amount,active\na,\"x,y\",10,true\nb,,,false\n",
+		},
+		{
+			name:   "table list",
+			format: FormatTable,
+			value:  results,
+			want:   "ID  TAGS  AMOUNT  ACTIVE\na   x,y   10      true\nb                 false\n",
+		},
+		{
+			name:   "table record",
+			format: FormatTable,
+			value:  results[0],
+			want:   "id:      a\ntags:    x,y\namount:  10\nactive:  true\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.NoError(t, writeResult(&out, test.format, test.value))
+			assert.Equal(t, test.want, out.String())
+		})
+	}
+}
@@ -26,11 +26,11 @@ import (
 // PrintTOSError prints TOS together with a given error
 // asking user to accept them.
 func PrintTOSError(err error) {
-	fmt.Println(metadata.VersionAsSummary(metadata.LicenseCopyright(
+	fmt.Fprintln(messages, metadata.VersionAsSummary(metadata.LicenseCopyright(
 		"type 'license --warranty'",
 		"type 'license --conditions'",
 	)))
-	fmt.Println()
+	fmt.Fprintln(messages)
 	Error(err)
 	Info("If you agree with these Terms & Conditions, run program again with '--agreed-terms-and-conditions' flag")
 }
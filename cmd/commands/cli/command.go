@@ -66,8 +66,12 @@ func NewCommand() *cli.Command {
 	return &cli.Command{
 		Name:  CommandName,
 		Usage: "Starts a CLI client with a Tequilapi",
-		Flags: []cli.Flag{&config.FlagAgreedTermsConditions, &config.FlagTequilapiAddress, &config.FlagTequilapiPort},
+		Flags: []cli.Flag{&config.FlagAgreedTermsConditions, &config.FlagTequilapiAddress, &config.FlagTequilapiPort, &clio.FlagOutput},
 		Action: func(ctx *cli.Context) error {
+			if err := clio.SetFormatFromFlag(ctx); err != nil {
+				return err
+			}
+
 			client, err := clio.NewTequilApiClient(ctx)
 			if err != nil {
 				return err
@@ -78,7 +82,7 @@ func NewCommand() *cli.Command {
 				return err
 			}
 
-			cmdCLI := newCliApp(cfg, client)
+			cmdCLI := newCliApp(cfg, client, clio.TequilAPIAddress(ctx), clio.TequilAPIPort(ctx))
 
 			cmd.RegisterSignalCallback(utils.SoftKiller(cmdCLI.Kill))
 
@@ -96,12 +100,14 @@ func describeQuit(err error) error {
 	return err
 }
 
-func newCliApp(rc *remote.Config, client *tequilapi_client.Client) *cliApp {
+func newCliApp(rc *remote.Config, client *tequilapi_client.Client, tequilapiAddress string, tequilapiPort int) *cliApp {
 	dataDir := rc.GetStringByFlag(config.FlagDataDir)
 	return &cliApp{
-		config:      rc,
-		tequilapi:   client,
-		historyFile: filepath.Join(dataDir, ".cli_history"),
+		config:           rc,
+		tequilapi:        client,
+		tequilapiAddress: tequilapiAddress,
+		tequilapiPort:    tequilapiPort,
+		historyFile:      filepath.Join(dataDir, ".cli_history"),
 	}
 }
 
@@ -110,6 +116,8 @@ type cliApp struct {
 	config           *remote.Config
 	historyFile      string
 	tequilapi        *tequilapi_client.Client
+	tequilapiAddress string
+	tequilapiPort    int
 	fetchedProposals []contract.ProposalDTO
 	completer        *readline.PrefixCompleter
 	reader           *readline.Instance
@@ -242,6 +250,8 @@ func (c *cliApp) handleActions(args []string) error {
 		{"disconnect", c.disconnect},
 		{"stop", c.stopClient},
 		{"version", c.version},
+		{"interactive", c.interactive},
+		{"dashboard", c.dashboard},
 	}
 
 	argCmds := []struct {
@@ -312,7 +322,7 @@ func (c *cliApp) connect(args []string) (err error) {
 
 	for _, arg := range args[3:] {
 		if strings.HasPrefix(arg, "dns=") {
-			kv := strings.Split(arg, "=")
+			kv := strings.SplitN(arg, "=", 2)
 			dns, err = connection.NewDNSOption(kv[1])
 			if err != nil {
 				clio.Info(helpMsg)
@@ -490,29 +500,17 @@ func (c *cliApp) proposals(args []string) (err error) {
 	}
 	clio.Info(fmt.Sprintf("Found %v proposals %s", len(proposals), filterMsg))
 
+	results := make([]proposalResult, 0, len(proposals))
 	for _, proposal := range proposals {
-		country := proposal.Location.Country
-		if country == "" {
-			country = "Unknown"
-		}
-
-		var policies []string
-		if proposal.AccessPolicies != nil {
-			for _, policy := range *proposal.AccessPolicies {
-				policies = append(policies, policy.ID)
-			}
-		}
-
-		msg := fmt.Sprintf("- provider id: %v\ttype: %v\tcountry: %v\taccess policies: %v\tprovider type: %v", proposal.ProviderID, proposal.ServiceType, country, strings.Join(policies, ","), proposal.Location.IPType)
-
+		result := newProposalResult(proposal)
 		if filter == "" ||
-			strings.Contains(proposal.ProviderID, filter) ||
-			strings.Contains(country, filter) {
-			clio.Info(msg)
+			strings.Contains(result.ProviderID, filter) ||
+			strings.Contains(result.Country, filter) {
+			results = append(results, result)
 		}
 	}
 
-	return nil
+	return clio.PrintResult(results)
 }
 
 func (c *cliApp) fetchProposals() []contract.ProposalDTO {
@@ -655,6 +653,7 @@ func newAutocompleter(tequilapi *tequilapi_client.Client, proposals []contract.P
 			readline.PcItem("last-withdrawal", readline.PcItemDynamic(getIdentityOptionList(tequilapi))),
 			readline.PcItem("migrate-hermes", readline.PcItemDynamic(getIdentityOptionList(tequilapi))),
 			readline.PcItem("migrate-hermes-status", readline.PcItemDynamic(getIdentityOptionList(tequilapi))),
+			readline.PcItem("rotate", readline.PcItemDynamic(getIdentityOptionList(tequilapi))),
 		),
 		readline.PcItem("status"),
 		readline.PcItem(
@@ -668,6 +667,8 @@ func newAutocompleter(tequilapi *tequilapi_client.Client, proposals []contract.P
 			readline.PcItem("get-all", readline.PcItemDynamic(getIdentityOptionList(tequilapi))),
 			readline.PcItem("gateways"),
 		),
+		readline.PcItem("interactive"),
+		readline.PcItem("dashboard"),
 		readline.PcItem("healthcheck"),
 		readline.PcItem("nat"),
 		readline.PcItem("proposals"),
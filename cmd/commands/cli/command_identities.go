@@ -62,6 +62,7 @@ func (c *cliApp) identities(args []string) (err error) {
 		"  " + usageLastWithdrawal,
 		"  " + usageMigrateHermesStatus,
 		"  " + usageMigrateHermes,
+		"  " + usageRotateIdentity,
 	}, "\n")
 
 	if len(args) == 0 {
@@ -109,6 +110,8 @@ func (c *cliApp) identities(args []string) (err error) {
 		return c.migrateHermes(actionArgs)
 	case "migrate-hermes-status":
 		return c.migrateHermesStatus(actionArgs)
+	case "rotate":
+		return c.rotateIdentity(actionArgs)
 	default:
 		fmt.Println(usage)
 		return errUnknownSubCommand(args[0])
@@ -127,10 +130,11 @@ func (c *cliApp) listIdentities(args []string) (err error) {
 		return err
 	}
 
+	results := make([]identityResult, 0, len(ids))
 	for _, id := range ids {
-		clio.Status("+", id.Address)
+		results = append(results, identityResult{Address: id.Address})
 	}
-	return nil
+	return clio.PrintResult(results)
 }
 
 const usageGetBalance = "balance <identity>"
@@ -147,8 +151,10 @@ func (c *cliApp) getBalance(actionArgs []string) (err error) {
 		return err
 	}
 
-	clio.Info(fmt.Sprintf("Balance: %s MYST", balance.BalanceTokens))
-	return nil
+	return clio.PrintResult(balanceResult{
+		Address: address,
+		Balance: balance.BalanceTokens.Ether,
+	})
 }
 
 const usageGetIdentity = "get <identity>"
@@ -164,12 +170,14 @@ func (c *cliApp) getIdentity(actionArgs []string) (err error) {
 	if err != nil {
 		return err
 	}
-	clio.Info("Registration Status: ", identityStatus.RegistrationStatus)
-	clio.Info("Channel address: ", identityStatus.ChannelAddress)
-	clio.Info(fmt.Sprintf("Balance: %s MYST", identityStatus.BalanceTokens))
-	clio.Info(fmt.Sprintf("Earnings: %s", money.New(identityStatus.Earnings)))
-	clio.Info(fmt.Sprintf("Earnings total: %s", money.New(identityStatus.EarningsTotal)))
-	return nil
+	return clio.PrintResult(identityStatusResult{
+		Address:            identityStatus.Address,
+		RegistrationStatus: identityStatus.RegistrationStatus,
+		ChannelAddress:     identityStatus.ChannelAddress,
+		Balance:            identityStatus.BalanceTokens.Ether,
+		Earnings:           identityStatus.EarningsTokens.Ether,
+		EarningsTotal:      identityStatus.EarningsTotalTokens.Ether,
+	})
 }
 
 const usageNewIdentity = "new [passphrase]"
@@ -293,17 +301,26 @@ func (c *cliApp) settle(args []string) (err error) {
 	for {
 		select {
 		case <-timeout:
-			fmt.Println()
+			fmt.Fprintln(clio.Messages())
 			return errTimeout
 		case <-time.After(time.Millisecond * 500):
-			fmt.Print(".")
+			fmt.Fprint(clio.Messages(), ".")
 		case err := <-errChan:
-			fmt.Println()
+			fmt.Fprintln(clio.Messages())
 			if err != nil {
 				return fmt.Errorf("settlement failed: %w", err)
 			}
 			clio.Info("settlement succeeded")
-			return nil
+
+			settled := make([]string, 0, len(hermesIDs))
+			for _, h := range hermesIDs {
+				settled = append(settled, h.Hex())
+			}
+			return clio.PrintResult(settlementResult{
+				Identity:  args[0],
+				HermesIDs: settled,
+				Settled:   true,
+			})
 		}
 	}
 }
@@ -678,3 +695,29 @@ func (c *cliApp) migrateHermes(actionArgs []string) error {
 
 	return nil
 }
+
+const usageRotateIdentity = "rotate <identity> [new_passphrase]"
+
+func (c *cliApp) rotateIdentity(actionArgs []string) error {
+	if len(actionArgs) < 1 || len(actionArgs) > 2 {
+		clio.Info("Usage: " + usageRotateIdentity)
+		return errWrongArgumentCount
+	}
+
+	address := actionArgs[0]
+	var passphrase string
+	if len(actionArgs) == 2 {
+		passphrase = actionArgs[1]
+	}
+
+	rotation, err := c.tequilapi.RotateIdentity(address, passphrase, true)
+	if err != nil {
+		return err
+	}
+
+	clio.Success("Identity rotated to:", rotation.To)
+	clio.Info("Earnings of", rotation.From, "will be settled to the new identity, running services keep the old one until restarted")
+	clio.Info("Migration signature of the old identity:", rotation.FromSignature)
+	clio.Info("Migration signature of the new identity:", rotation.ToSignature)
+	return nil
+}
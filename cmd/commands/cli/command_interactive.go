@@ -0,0 +1,211 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/cmd/commands/cli/tui"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+// interactive browses proposals and connects to the picked one with a single key, then shows the dashboard.
+func (c *cliApp) interactive() (err error) {
+	consumer, err := c.tequilapi.CurrentIdentity("", identityDefaultPassphrase)
+	if err != nil {
+		return fmt.Errorf("failed to get consumer identity: %w", err)
+	}
+
+	term, err := tui.NewTerminal(os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	defer term.Restore()
+
+	c.fetchedProposals = c.fetchProposals()
+	browser := tui.NewBrowser(c.fetchedProposals)
+	browser.Title = "Proposals, connecting as " + consumer.Address
+	for {
+		term.Draw(browser.Lines())
+
+		key, err := term.ReadKey()
+		if err != nil {
+			return err
+		}
+		if browser.Searching() {
+			browser.SearchKey(key)
+			continue
+		}
+
+		browser.Message = ""
+		switch key {
+		case 'q', tui.KeyEscape, tui.KeyCtrlC:
+			return nil
+		case 'n':
+			browser.NextPage()
+		case 'p':
+			browser.PrevPage()
+		case 't':
+			browser.CycleServiceType()
+		case 'i':
+			browser.CycleIPType()
+		case 's':
+			browser.ToggleSort()
+		case '/':
+			browser.StartSearch()
+		case 'r':
+			c.fetchedProposals = c.fetchProposals()
+			browser.SetProposals(c.fetchedProposals)
+		case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			proposal, ok := browser.Pick(int(key - '0'))
+			if !ok {
+				continue
+			}
+
+			term.Draw([]string{fmt.Sprintf("Connecting to %s (%s, %s)...", proposal.ProviderID, proposal.ServiceType, proposal.Location.Country)})
+			if err := c.connectTo(consumer.Address, proposal); err != nil {
+				browser.Message = "Could not connect: " + formatForHuman(err)
+				continue
+			}
+			return c.runDashboard(term)
+		}
+	}
+}
+
+// dashboard shows the live connection dashboard.
+func (c *cliApp) dashboard() (err error) {
+	term, err := tui.NewTerminal(os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	defer term.Restore()
+
+	return c.runDashboard(term)
+}
+
+func (c *cliApp) connectTo(consumerID string, proposal contract.ProposalDTO) error {
+	hermesID, err := c.config.GetHermesID()
+	if err != nil {
+		return err
+	}
+
+	// The identity may be protected by a passphrase, connecting reports it anyway.
+	_ = c.tequilapi.Unlock(consumerID, identityDefaultPassphrase)
+
+	if _, err := c.tequilapi.ConnectionCreate(consumerID, proposal.ProviderID, hermesID, proposal.ServiceType, contract.ConnectOptions{}); err != nil {
+		return err
+	}
+	c.currentConsumerID = consumerID
+	return nil
+}
+
+// runDashboard redraws the dashboard on each streamed connection event until the user quits.
+func (c *cliApp) runDashboard(term *tui.Terminal) error {
+	status, err := c.tequilapi.ConnectionStatus(0)
+	if err != nil {
+		return fmt.Errorf("failed to get connection status: %w", err)
+	}
+
+	stream, err := tui.DialEventStream(c.tequilapiAddress, c.tequilapiPort, tui.DashboardEvents...)
+	if err != nil {
+		return err
+	}
+
+	dashboard := tui.NewDashboard(status)
+	var mu sync.Mutex
+	closed := false
+	redraw := func() {
+		if !closed {
+			term.Draw(dashboard.Lines(time.Now()))
+		}
+	}
+	defer func() {
+		mu.Lock()
+		closed = true
+		mu.Unlock()
+		stream.Close()
+	}()
+
+	go func() {
+		for {
+			e, err := stream.Next()
+			if err != nil {
+				mu.Lock()
+				dashboard.Message = "Event stream closed: " + err.Error()
+				redraw()
+				mu.Unlock()
+				return
+			}
+
+			mu.Lock()
+			isStatistics, err := dashboard.Apply(e)
+			if err != nil {
+				dashboard.Message = err.Error()
+			}
+			mu.Unlock()
+
+			// Statistics events carry no spendings, they are fetched along.
+			if isStatistics {
+				if statistics, err := c.tequilapi.ConnectionStatistics(); err == nil {
+					mu.Lock()
+					dashboard.SetSpent(statistics.TokensSpent)
+					mu.Unlock()
+				}
+			}
+
+			mu.Lock()
+			redraw()
+			mu.Unlock()
+		}
+	}()
+
+	mu.Lock()
+	redraw()
+	mu.Unlock()
+
+	for {
+		key, err := term.ReadKey()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case 'q', tui.KeyEscape, tui.KeyCtrlC:
+			return nil
+		case 'd':
+			mu.Lock()
+			dashboard.Message = "Disconnecting..."
+			redraw()
+			mu.Unlock()
+
+			message := "Disconnected"
+			if err := c.tequilapi.ConnectionDestroy(0); err != nil {
+				message = "Could not disconnect: " + formatForHuman(err)
+			} else {
+				c.currentConsumerID = ""
+			}
+
+			mu.Lock()
+			dashboard.Message = message
+			redraw()
+			mu.Unlock()
+		}
+	}
+}
@@ -21,8 +21,6 @@ import (
 	"fmt"
 
 	"github.com/mysteriumnetwork/node/cmd/commands/cli/clio"
-	"github.com/mysteriumnetwork/node/datasize"
-	"github.com/mysteriumnetwork/node/money"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 )
 
@@ -100,13 +98,11 @@ func (c *cliApp) serviceList() (err error) {
 		return fmt.Errorf("failed to get a list of services: %w", err)
 	}
 
+	results := make([]serviceResult, 0, len(services))
 	for _, service := range services {
-		clio.Status(service.Status,
-			"ID: "+service.ID,
-			"ProviderID: "+service.Proposal.ProviderID,
-			"Type: "+service.Proposal.ServiceType)
+		results = append(results, newServiceResult(service))
 	}
-	return nil
+	return clio.PrintResult(results)
 }
 
 func (c *cliApp) serviceSessions() (err error) {
@@ -116,15 +112,11 @@ func (c *cliApp) serviceSessions() (err error) {
 	}
 
 	clio.Status("Current sessions", len(sessions.Items))
+	results := make([]sessionResult, 0, len(sessions.Items))
 	for _, session := range sessions.Items {
-		clio.Status(
-			"ID: "+session.ID,
-			"ConsumerID: "+session.ConsumerID,
-			fmt.Sprintf("Data: %s/%s", datasize.FromBytes(session.BytesReceived).String(), datasize.FromBytes(session.BytesSent).String()),
-			fmt.Sprintf("Tokens: %s", money.New(session.Tokens)),
-		)
+		results = append(results, newSessionResult(session))
 	}
-	return nil
+	return clio.PrintResult(results)
 }
 
 func (c *cliApp) serviceGet(id string) (err error) {
@@ -133,9 +125,5 @@ func (c *cliApp) serviceGet(id string) (err error) {
 		return fmt.Errorf("failed to get service info: %w", err)
 	}
 
-	clio.Status(service.Status,
-		"ID: "+service.ID,
-		"ProviderID: "+service.Proposal.ProviderID,
-		"Type: "+service.Proposal.ServiceType)
-	return nil
+	return clio.PrintResult(newServiceResult(service))
 }
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+// Results printed by the CLI commands. Their json tags name the fields in
+// every output format and are kept stable for scripts, token amounts are
+// given in MYST.
+
+type identityResult struct {
+	Address string `json:"address"`
+}
+
+type identityStatusResult struct {
+	Address            string `json:"address"`
+	RegistrationStatus string `json:"registration_status"`
+	ChannelAddress     string `json:"channel_address"`
+	Balance            string `json:"balance"`
+	Earnings           string `json:"earnings"`
+	EarningsTotal      string `json:"earnings_total"`
+}
+
+type balanceResult struct {
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+}
+
+type settlementResult struct {
+	Identity  string   `json:"identity"`
+	HermesIDs []string `json:"hermes_ids"`
+	Settled   bool     `json:"settled"`
+}
+
+type proposalResult struct {
+	ProviderID     string   `json:"provider_id"`
+	ServiceType    string   `json:"service_type"`
+	Country        string   `json:"country"`
+	IPType         string   `json:"ip_type"`
+	AccessPolicies []string `json:"access_policies"`
+}
+
+type serviceResult struct {
+	ID          string `json:"id"`
+	ProviderID  string `json:"provider_id"`
+	ServiceType string `json:"service_type"`
+	Status      string `json:"status"`
+}
+
+type sessionResult struct {
+	ID            string `json:"id"`
+	ConsumerID    string `json:"consumer_id"`
+	ServiceType   string `json:"service_type"`
+	Status        string `json:"status"`
+	CreatedAt     string `json:"created_at"`
+	Duration      uint64 `json:"duration_seconds"`
+	BytesReceived uint64 `json:"bytes_received"`
+	BytesSent     uint64 `json:"bytes_sent"`
+	Tokens        string `json:"tokens"`
+}
+
+func newProposalResult(proposal contract.ProposalDTO) proposalResult {
+	country := proposal.Location.Country
+	if country == "" {
+		country = "Unknown"
+	}
+
+	policies := []string{}
+	if proposal.AccessPolicies != nil {
+		for _, policy := range *proposal.AccessPolicies {
+			policies = append(policies, policy.ID)
+		}
+	}
+
+	return proposalResult{
+		ProviderID:     proposal.ProviderID,
+		ServiceType:    proposal.ServiceType,
+		Country:        country,
+		IPType:         proposal.Location.IPType,
+		AccessPolicies: policies,
+	}
+}
+
+func newServiceResult(service contract.ServiceInfoDTO) serviceResult {
+	return serviceResult{
+		ID:          service.ID,
+		ProviderID:  service.Proposal.ProviderID,
+		ServiceType: service.Proposal.ServiceType,
+		Status:      service.Status,
+	}
+}
+
+func newSessionResult(session contract.SessionDTO) sessionResult {
+	return sessionResult{
+		ID:            session.ID,
+		ConsumerID:    session.ConsumerID,
+		ServiceType:   session.ServiceType,
+		Status:        session.Status,
+		CreatedAt:     session.CreatedAt,
+		Duration:      session.Duration,
+		BytesReceived: session.BytesReceived,
+		BytesSent:     session.BytesSent,
+		Tokens:        contract.NewTokens(session.Tokens).Ether,
+	}
+}
@@ -0,0 +1,273 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+// PageSize is a number of proposals shown at once, so that each of them is picked with a single digit key.
+const PageSize = 9
+
+// BrowserHelp lists keys handled by the proposal browser.
+const BrowserHelp = "[1-9] connect  [n/p] page  [t] service type  [i] IP type  [s] sort  [/] search  [r] refresh  [q] quit"
+
+// Browser pages through proposals narrowed down by filters.
+type Browser struct {
+	// Title is shown above the proposal list.
+	Title string
+	// Message is shown below the proposal list, e.g. to report an error.
+	Message string
+
+	serviceType string
+	ipType      string
+	search      string
+	searching   bool
+	sortByPrice bool
+	page        int
+
+	proposals []contract.ProposalDTO
+	filtered  []contract.ProposalDTO
+}
+
+// NewBrowser returns a new proposal browser.
+func NewBrowser(proposals []contract.ProposalDTO) *Browser {
+	b := &Browser{}
+	b.SetProposals(proposals)
+	return b
+}
+
+// SetProposals replaces browsed proposals, keeping the filters.
+func (b *Browser) SetProposals(proposals []contract.ProposalDTO) {
+	b.proposals = proposals
+	b.apply()
+}
+
+// CycleServiceType switches the service type filter to the next type offered by proposals.
+func (b *Browser) CycleServiceType() {
+	b.serviceType = next(b.serviceType, distinct(b.proposals, func(p contract.ProposalDTO) string { return p.ServiceType }))
+	b.apply()
+}
+
+// CycleIPType switches the IP type filter to the next type offered by proposals.
+func (b *Browser) CycleIPType() {
+	b.ipType = next(b.ipType, distinct(b.proposals, func(p contract.ProposalDTO) string { return p.Location.IPType }))
+	b.apply()
+}
+
+// ToggleSort switches between sorting by quality and by price.
+func (b *Browser) ToggleSort() {
+	b.sortByPrice = !b.sortByPrice
+	b.apply()
+}
+
+// Searching returns true while the search text is being typed.
+func (b *Browser) Searching() bool {
+	return b.searching
+}
+
+// StartSearch starts typing of the search text.
+func (b *Browser) StartSearch() {
+	b.searching = true
+}
+
+// SearchKey edits the search text, which is applied as it is typed. Enter or Escape finishes the search.
+func (b *Browser) SearchKey(key rune) {
+	switch key {
+	case KeyEnter, KeyEscape, KeyCtrlC:
+		b.searching = false
+		return
+	case KeyBackspace:
+		if runes := []rune(b.search); len(runes) > 0 {
+			b.search = string(runes[:len(runes)-1])
+		}
+	case KeyUnknown:
+		return
+	default:
+		if key < ' ' {
+			return
+		}
+		b.search += string(key)
+	}
+	b.apply()
+}
+
+// NextPage shows the next page of proposals.
+func (b *Browser) NextPage() {
+	if b.page < b.pages()-1 {
+		b.page++
+	}
+}
+
+// PrevPage shows the previous page of proposals.
+func (b *Browser) PrevPage() {
+	if b.page > 0 {
+		b.page--
+	}
+}
+
+// Pick returns the proposal at the given 1-based position of the current page.
+func (b *Browser) Pick(n int) (contract.ProposalDTO, bool) {
+	i := b.page*PageSize + n - 1
+	if n < 1 || n > PageSize || i >= len(b.filtered) {
+		return contract.ProposalDTO{}, false
+	}
+	return b.filtered[i], true
+}
+
+// Lines renders the current page of proposals.
+func (b *Browser) Lines() []string {
+	sortName := "quality"
+	if b.sortByPrice {
+		sortName = "price"
+	}
+	search := fmt.Sprintf("%q", b.search)
+	if b.searching {
+		search += "_"
+	}
+
+	lines := []string{
+		b.Title,
+		fmt.Sprintf("Filters: service type: %s  IP type: %s  search: %s  sort: %s", orAny(b.serviceType), orAny(b.ipType), search, sortName),
+		"",
+		fmt.Sprintf("%-3s%-18s%-12s%-9s%-16s%-13s%-9s%-16s%s", "#", "Provider", "Type", "Country", "City", "IP type", "Quality", "Price/GiB", "Price/hour"),
+	}
+
+	start := b.page * PageSize
+	for i := start; i < len(b.filtered) && i < start+PageSize; i++ {
+		p := b.filtered[i]
+		lines = append(lines, fmt.Sprintf("%-3d%-18s%-12s%-9s%-16s%-13s%-9.2f%-16s%s",
+			i-start+1,
+			shortID(p.ProviderID),
+			p.ServiceType,
+			p.Location.Country,
+			truncate(p.Location.City, 15),
+			p.Location.IPType,
+			p.Quality.Quality,
+			p.Price.PerGiBTokens.Human,
+			p.Price.PerHourTokens.Human,
+		))
+	}
+	if len(b.filtered) == 0 {
+		lines = append(lines, "No proposals match the filters")
+	}
+
+	lines = append(lines, "", fmt.Sprintf("Page %d/%d, %d of %d proposals", b.page+1, b.pages(), len(b.filtered), len(b.proposals)))
+	if b.Message != "" {
+		lines = append(lines, b.Message)
+	}
+	if b.searching {
+		return append(lines, "Type to search by provider, country, city or ISP, [enter] done")
+	}
+	return append(lines, BrowserHelp)
+}
+
+func (b *Browser) pages() int {
+	if len(b.filtered) == 0 {
+		return 1
+	}
+	return (len(b.filtered) + PageSize - 1) / PageSize
+}
+
+func (b *Browser) apply() {
+	search := strings.ToLower(b.search)
+
+	b.filtered = make([]contract.ProposalDTO, 0, len(b.proposals))
+	for _, p := range b.proposals {
+		if b.serviceType != "" && p.ServiceType != b.serviceType {
+			continue
+		}
+		if b.ipType != "" && p.Location.IPType != b.ipType {
+			continue
+		}
+		if search != "" && !matches(p, search) {
+			continue
+		}
+		b.filtered = append(b.filtered, p)
+	}
+
+	sort.SliceStable(b.filtered, func(i, j int) bool {
+		pi, pj := b.filtered[i], b.filtered[j]
+		if b.sortByPrice && pi.Price.PerGiB != pj.Price.PerGiB {
+			return pi.Price.PerGiB < pj.Price.PerGiB
+		}
+		if b.sortByPrice && pi.Price.PerHour != pj.Price.PerHour {
+			return pi.Price.PerHour < pj.Price.PerHour
+		}
+		return pi.Quality.Quality > pj.Quality.Quality
+	})
+
+	if b.page >= b.pages() {
+		b.page = b.pages() - 1
+	}
+}
+
+func matches(p contract.ProposalDTO, search string) bool {
+	for _, field := range []string{p.ProviderID, p.Location.Country, p.Location.City, p.Location.ISP} {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// distinct returns sorted non-empty values of the proposal field.
+func distinct(proposals []contract.ProposalDTO, field func(p contract.ProposalDTO) string) []string {
+	seen := make(map[string]struct{})
+	var values []string
+	for _, p := range proposals {
+		v := field(p)
+		if _, ok := seen[v]; ok || v == "" {
+			continue
+		}
+		seen[v] = struct{}{}
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// next returns the value following the current one, cycling through all values and "any", which is empty.
+func next(current string, values []string) string {
+	for i, v := range values {
+		if v == current && i+1 < len(values) {
+			return values[i+1]
+		}
+	}
+	if current == "" && len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func orAny(filter string) string {
+	if filter == "" {
+		return "any"
+	}
+	return filter
+}
+
+func shortID(id string) string {
+	if len(id) <= 16 {
+		return id
+	}
+	return id[:10] + "…" + id[len(id)-4:]
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+func proposal(providerID, serviceType, country, ipType string, quality float64, perGiB uint64) contract.ProposalDTO {
+	return contract.ProposalDTO{
+		ProviderID:  providerID,
+		ServiceType: serviceType,
+		Location:    contract.ServiceLocationDTO{Country: country, IPType: ipType},
+		Quality:     contract.Quality{Quality: quality},
+		Price:       contract.Price{PerGiB: perGiB},
+	}
+}
+
+func providerIDs(b *Browser) (ids []string) {
+	for n := 1; n <= PageSize; n++ {
+		if p, ok := b.Pick(n); ok {
+			ids = append(ids, p.ProviderID)
+		}
+	}
+	return ids
+}
+
+func TestBrowser_Filters(t *testing.T) {
+	b := NewBrowser([]contract.ProposalDTO{
+		proposal("0x1", "wireguard", "DE", "residential", 1, 30),
+		proposal("0x2", "wireguard", "US", "hosting", 3, 20),
+		proposal("0x3", "scraping", "DE", "hosting", 2, 10),
+	})
+	assert.Equal(t, []string{"0x2", "0x3", "0x1"}, providerIDs(b))
+
+	b.CycleServiceType()
+	assert.Equal(t, []string{"0x3"}, providerIDs(b))
+	b.CycleServiceType()
+	assert.Equal(t, []string{"0x2", "0x1"}, providerIDs(b))
+	b.CycleServiceType()
+	assert.Equal(t, []string{"0x2", "0x3", "0x1"}, providerIDs(b))
+
+	b.CycleIPType()
+	assert.Equal(t, []string{"0x2", "0x3"}, providerIDs(b))
+
+	b.ToggleSort()
+	assert.Equal(t, []string{"0x3", "0x2"}, providerIDs(b))
+
+	b.StartSearch()
+	for _, key := range "dx" {
+		b.SearchKey(key)
+	}
+	assert.Empty(t, providerIDs(b))
+	b.SearchKey(KeyBackspace)
+	assert.Equal(t, []string{"0x3"}, providerIDs(b))
+	b.SearchKey(KeyEnter)
+	assert.False(t, b.Searching())
+	assert.Equal(t, []string{"0x3"}, providerIDs(b))
+}
+
+func TestBrowser_Pages(t *testing.T) {
+	var proposals []contract.ProposalDTO
+	for i := 0; i < PageSize+2; i++ {
+		proposals = append(proposals, proposal(string(rune('a'+i)), "wireguard", "DE", "residential", float64(100-i), 0))
+	}
+	b := NewBrowser(proposals)
+
+	assert.Len(t, providerIDs(b), PageSize)
+	_, ok := b.Pick(0)
+	assert.False(t, ok)
+
+	b.NextPage()
+	assert.Equal(t, []string{"j", "k"}, providerIDs(b))
+	b.NextPage()
+	assert.Equal(t, []string{"j", "k"}, providerIDs(b))
+	_, ok = b.Pick(3)
+	assert.False(t, ok)
+
+	// The page is kept within bounds when filters narrow proposals down.
+	b.StartSearch()
+	b.SearchKey('a')
+	assert.Equal(t, []string{"a"}, providerIDs(b))
+
+	b.PrevPage()
+	assert.Equal(t, []string{"a"}, providerIDs(b))
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/mysteriumnetwork/node/datasize"
+	"github.com/mysteriumnetwork/node/money"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/endpoints"
+)
+
+// DashboardEvents are event types the dashboard is driven by.
+var DashboardEvents = []string{string(endpoints.ConnectionStateEvent), string(endpoints.ConnectionStatisticsEvent)}
+
+// DashboardHelp lists keys handled by the dashboard.
+const DashboardHelp = "[d] disconnect  [q] quit"
+
+// sessionPayload is the part of the streamed connection session used by the dashboard.
+type sessionPayload struct {
+	SessionID string
+	StartedAt time.Time
+	Proposal  struct {
+		ProviderID  string `json:"provider_id"`
+		ServiceType string `json:"service_type"`
+		Location    struct {
+			Country string `json:"country"`
+		} `json:"location"`
+	}
+}
+
+type connectionStatePayload struct {
+	State       string
+	SessionInfo sessionPayload
+}
+
+type connectionStatisticsPayload struct {
+	Stats struct {
+		At            time.Time
+		BytesSent     uint64
+		BytesReceived uint64
+	}
+	SessionInfo sessionPayload
+}
+
+// Dashboard is a live view of the consumer connection.
+type Dashboard struct {
+	// Message is shown below the connection details, e.g. to report an error.
+	Message string
+
+	state       string
+	providerID  string
+	serviceType string
+	country     string
+	sessionID   string
+	startedAt   time.Time
+
+	statsAt       time.Time
+	bytesSent     uint64
+	bytesReceived uint64
+	speedSent     datasize.BitSpeed
+	speedReceived datasize.BitSpeed
+	spent         *big.Int
+}
+
+// NewDashboard returns a dashboard showing the given connection until events arrive.
+func NewDashboard(status contract.ConnectionInfoDTO) *Dashboard {
+	d := &Dashboard{state: status.Status, sessionID: status.SessionID}
+	if status.Proposal != nil {
+		d.providerID = status.Proposal.ProviderID
+		d.serviceType = status.Proposal.ServiceType
+		d.country = status.Proposal.Location.Country
+	}
+	return d
+}
+
+// Apply updates the dashboard with a streamed event. It returns true for connection statistics events.
+func (d *Dashboard) Apply(e Event) (bool, error) {
+	switch e.Type {
+	case string(endpoints.ConnectionStateEvent):
+		var payload connectionStatePayload
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return false, fmt.Errorf("could not parse connection state: %w", err)
+		}
+		d.state = payload.State
+		d.setSession(payload.SessionInfo)
+		if payload.SessionInfo.SessionID == "" {
+			d.resetStatistics()
+		}
+		return false, nil
+
+	case string(endpoints.ConnectionStatisticsEvent):
+		var payload connectionStatisticsPayload
+		if err := json.Unmarshal(e.Payload, &payload); err != nil {
+			return false, fmt.Errorf("could not parse connection statistics: %w", err)
+		}
+		d.setSession(payload.SessionInfo)
+
+		stats := payload.Stats
+		if elapsed := stats.At.Sub(d.statsAt).Seconds(); !d.statsAt.IsZero() && elapsed > 0 {
+			d.speedSent = datasize.BitSpeed(float64(datasize.FromBytes(diff(d.bytesSent, stats.BytesSent))) / elapsed)
+			d.speedReceived = datasize.BitSpeed(float64(datasize.FromBytes(diff(d.bytesReceived, stats.BytesReceived))) / elapsed)
+		}
+		d.statsAt = stats.At
+		d.bytesSent = stats.BytesSent
+		d.bytesReceived = stats.BytesReceived
+		return true, nil
+	}
+	return false, nil
+}
+
+// SetSpent sets tokens spent in the current session.
+func (d *Dashboard) SetSpent(spent *big.Int) {
+	d.spent = spent
+}
+
+// Lines renders the dashboard at the given time.
+func (d *Dashboard) Lines(now time.Time) []string {
+	lines := []string{
+		"Connection",
+		"",
+		fmt.Sprintf("  State:       %s", d.state),
+	}
+	if d.sessionID != "" {
+		duration := time.Duration(0)
+		if !d.startedAt.IsZero() {
+			duration = now.Sub(d.startedAt).Truncate(time.Second)
+		}
+		spent := "-"
+		if d.spent != nil {
+			spent = money.New(d.spent).String()
+		}
+
+		lines = append(lines,
+			fmt.Sprintf("  Provider:    %s", d.providerID),
+			fmt.Sprintf("  Service:     %s", d.serviceType),
+			fmt.Sprintf("  Country:     %s", d.country),
+			fmt.Sprintf("  Session:     %s", d.sessionID),
+			fmt.Sprintf("  Duration:    %s", duration),
+			"",
+			fmt.Sprintf("  Download:    %s (%s)", d.speedReceived, datasize.FromBytes(d.bytesReceived)),
+			fmt.Sprintf("  Upload:      %s (%s)", d.speedSent, datasize.FromBytes(d.bytesSent)),
+			fmt.Sprintf("  Spent:       %s", spent),
+		)
+	}
+
+	lines = append(lines, "")
+	if d.Message != "" {
+		lines = append(lines, d.Message)
+	}
+	return append(lines, DashboardHelp)
+}
+
+func (d *Dashboard) setSession(session sessionPayload) {
+	d.sessionID = session.SessionID
+	if session.SessionID == "" {
+		return
+	}
+	d.startedAt = session.StartedAt
+	if session.Proposal.ProviderID == "" {
+		return
+	}
+	d.providerID = session.Proposal.ProviderID
+	d.serviceType = session.Proposal.ServiceType
+	d.country = session.Proposal.Location.Country
+}
+
+func (d *Dashboard) resetStatistics() {
+	d.statsAt = time.Time{}
+	d.bytesSent, d.bytesReceived = 0, 0
+	d.speedSent, d.speedReceived = 0, 0
+	d.spent = nil
+}
+
+func diff(old, new uint64) uint64 {
+	if new < old {
+		return new
+	}
+	return new - old
+}
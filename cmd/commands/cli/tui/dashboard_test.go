@@ -0,0 +1,80 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package tui
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+func TestDashboard_Apply(t *testing.T) {
+	d := NewDashboard(contract.ConnectionInfoDTO{Status: "NotConnected"})
+
+	isStatistics, err := d.Apply(Event{Type: "connection-state", Payload: []byte(`{
+		"State": "Connected",
+		"SessionInfo": {
+			"SessionID": "session-1",
+			"StartedAt": "2022-05-01T12:00:00Z",
+			"Proposal": {"provider_id": "0x1", "service_type": "wireguard", "location": {"country": "DE"}}
+		}
+	}`)})
+	require.NoError(t, err)
+	assert.False(t, isStatistics)
+
+	for _, stats := range []string{
+		`{"Stats": {"At": "2022-05-01T12:00:10Z", "BytesSent": 1000, "BytesReceived": 4000}, "SessionInfo": {"SessionID": "session-1", "StartedAt": "2022-05-01T12:00:00Z"}}`,
+		`{"Stats": {"At": "2022-05-01T12:00:12Z", "BytesSent": 3000, "BytesReceived": 1052576}, "SessionInfo": {"SessionID": "session-1", "StartedAt": "2022-05-01T12:00:00Z"}}`,
+	} {
+		isStatistics, err = d.Apply(Event{Type: "connection-statistics", Payload: []byte(stats)})
+		require.NoError(t, err)
+		assert.True(t, isStatistics)
+	}
+	d.SetSpent(big.NewInt(1000000000000000000))
+
+	screen := strings.Join(d.Lines(time.Date(2022, 5, 1, 12, 1, 0, 0, time.UTC)), "\n")
+	assert.Contains(t, screen, "State:       Connected")
+	assert.Contains(t, screen, "Provider:    0x1")
+	assert.Contains(t, screen, "Country:     DE")
+	assert.Contains(t, screen, "Duration:    1m0s")
+	assert.Contains(t, screen, "Download:    512.0KiBs (1.0MiB)")
+	assert.Contains(t, screen, "Upload:      1000Bs (2.9KiB)")
+	assert.Contains(t, screen, "Spent:       1.000")
+
+	_, err = d.Apply(Event{Type: "connection-state", Payload: []byte(`{"State": "NotConnected", "SessionInfo": {}}`)})
+	require.NoError(t, err)
+	screen = strings.Join(d.Lines(time.Now()), "\n")
+	assert.Contains(t, screen, "State:       NotConnected")
+	assert.NotContains(t, screen, "Provider:")
+}
+
+func TestDashboard_ApplyInvalidPayload(t *testing.T) {
+	d := NewDashboard(contract.ConnectionInfoDTO{Status: "Connected"})
+
+	_, err := d.Apply(Event{Type: "connection-state", Payload: []byte(`"invalid"`)})
+	assert.Error(t, err)
+
+	isStatistics, err := d.Apply(Event{Type: "earnings", Payload: []byte(`{}`)})
+	assert.NoError(t, err)
+	assert.False(t, isStatistics)
+}
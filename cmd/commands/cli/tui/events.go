@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// Event is an event received from the node event stream.
+type Event struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// EventStream receives node events over the Tequilapi WebSocket.
+type EventStream struct {
+	conn *websocket.Conn
+}
+
+// DialEventStream connects to the event stream of the node, receiving events of the given types only.
+func DialEventStream(address string, port int, types ...string) (*EventStream, error) {
+	u := url.URL{
+		Scheme: "ws",
+		Host:   net.JoinHostPort(address, strconv.Itoa(port)),
+		Path:   "/events/ws",
+	}
+	if len(types) > 0 {
+		u.RawQuery = url.Values{"topics": {strings.Join(types, ",")}}.Encode()
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to event stream: %w", err)
+	}
+	return &EventStream{conn: conn}, nil
+}
+
+// Next blocks until the next event is received.
+func (s *EventStream) Next() (Event, error) {
+	_, data, err := s.conn.ReadMessage()
+	if err != nil {
+		return Event{}, err
+	}
+
+	var e Event
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Event{}, fmt.Errorf("could not parse event: %w", err)
+	}
+	return e, nil
+}
+
+// Close closes the stream, unblocking Next.
+func (s *EventStream) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+)
+
+// Keys reported by ReadKey for control input.
+const (
+	KeyUnknown   rune = 0
+	KeyCtrlC     rune = 0x03
+	KeyEnter     rune = '\r'
+	KeyEscape    rune = 0x1b
+	KeyBackspace rune = 0x7f
+)
+
+const (
+	clearScreen = "\033[H\033[2J"
+	hideCursor  = "\033[?25l"
+	showCursor  = "\033[?25h"
+)
+
+// Terminal reads single key presses in raw mode and redraws the whole screen.
+type Terminal struct {
+	in    *os.File
+	out   io.Writer
+	state *readline.State
+}
+
+// NewTerminal puts the input terminal into raw mode, Restore must be called to leave it.
+func NewTerminal(in *os.File, out io.Writer) (*Terminal, error) {
+	fd := int(in.Fd())
+	if !readline.IsTerminal(fd) {
+		return nil, errors.New("interactive mode requires a terminal")
+	}
+
+	state, err := readline.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("could not switch terminal to raw mode: %w", err)
+	}
+	fmt.Fprint(out, hideCursor)
+
+	return &Terminal{in: in, out: out, state: state}, nil
+}
+
+// Restore clears the screen and returns the terminal to the state it was in before.
+func (t *Terminal) Restore() error {
+	fmt.Fprint(t.out, clearScreen+showCursor)
+	return readline.Restore(int(t.in.Fd()), t.state)
+}
+
+// ReadKey blocks until a key is pressed. Escape sequences, e.g. of arrow keys, are reported as KeyUnknown.
+func (t *Terminal) ReadKey() (rune, error) {
+	var buf [8]byte
+	n, err := t.in.Read(buf[:])
+	if err != nil {
+		return KeyUnknown, err
+	}
+	if buf[0] == byte(KeyEscape) && n > 1 {
+		return KeyUnknown, nil
+	}
+
+	r, _ := utf8.DecodeRune(buf[:n])
+	if r == utf8.RuneError {
+		return KeyUnknown, nil
+	}
+	return r, nil
+}
+
+// Draw replaces the screen contents with the given lines, cutting them to the terminal width.
+func (t *Terminal) Draw(lines []string) {
+	width, _, err := readline.GetSize(int(t.in.Fd()))
+	if err != nil {
+		width = 0
+	}
+
+	var sb strings.Builder
+	sb.WriteString(clearScreen)
+	for i, line := range lines {
+		if i > 0 {
+			// Output post-processing is off in raw mode, so lines have to be returned explicitly.
+			sb.WriteString("\r\n")
+		}
+		sb.WriteString(truncate(line, width))
+	}
+	fmt.Fprint(t.out, sb.String())
+}
+
+func truncate(line string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(line) <= width {
+		return line
+	}
+	return string([]rune(line)[:width])
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/mysteriumnetwork/node/cmd/commands/cli/clio"
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/tequilapi/client"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
 )
 
 // CommandName is the name which is used to call this command
@@ -59,6 +60,12 @@ func NewCommand() *cli.Command {
 				Usage:  "Set node config value",
 				Action: cmd.set,
 			},
+			{
+				Name:      "profile",
+				Usage:     "List config profiles or select one",
+				ArgsUsage: "[profile name | none]",
+				Action:    cmd.profile,
+			},
 		},
 	}
 }
@@ -102,6 +109,39 @@ func (c *command) set(ctx *cli.Context) error {
 	return nil
 }
 
+func (c *command) profile(ctx *cli.Context) error {
+	var profiles contract.ConfigProfilesDTO
+	var err error
+	switch name := ctx.Args().First(); name {
+	case "":
+		profiles, err = c.tc.ConfigProfiles()
+	case "none":
+		profiles, err = c.tc.SetConfigProfile("")
+	default:
+		profiles, err = c.tc.SetConfigProfile(name)
+	}
+	if err != nil {
+		clio.Error("Failed to manage config profiles", err)
+		return err
+	}
+
+	for _, p := range profiles.Profiles {
+		marker := " "
+		if p.Name == profiles.Current {
+			marker = "*"
+		}
+		description := p.Description
+		if p.Custom {
+			description = "custom profile"
+		}
+		fmt.Printf("%s %s: %s\n", marker, p.Name, description)
+	}
+	if ctx.Args().Present() {
+		clio.Info("Values read on node start take effect after restart")
+	}
+	return nil
+}
+
 // Orders keys alphabetically and prints a given map.
 func printMapOrdered(m map[string]string) {
 	keys := make([]string, 0, len(m))
@@ -20,9 +20,7 @@ package connection
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strings"
-	"text/tabwriter"
 	"time"
 
 	"github.com/urfave/cli/v2"
@@ -104,8 +102,11 @@ func NewCommand() *cli.Command {
 			{
 				Name:  "proposals",
 				Usage: "List all possible proposals to which you can connect",
-				Flags: []cli.Flag{&flagCountry, &flagLocationType},
+				Flags: []cli.Flag{&flagCountry, &flagLocationType, &clio.FlagOutput},
 				Action: func(ctx *cli.Context) error {
+					if err := clio.SetFormatFromFlag(ctx); err != nil {
+						return err
+					}
 					cmd.proposals(ctx)
 					return nil
 				},
@@ -161,17 +162,18 @@ func (c *command) proposals(ctx *cli.Context) {
 		return
 	}
 
-	if len(proposals) == 0 {
+	if len(proposals) == 0 && !clio.IsMachineReadable() {
 		clio.Info("No proposals found")
 		return
 	}
 
-	clio.Info("Found proposals:")
-	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+	results := make([]proposalResult, 0, len(proposals))
 	for _, p := range proposals {
-		fmt.Fprintln(w, proposalFormatted(&p))
+		results = append(results, newProposalResult(&p))
+	}
+	if err := clio.PrintResult(results); err != nil {
+		clio.Warn("Failed to print proposal list:", err)
 	}
-	w.Flush()
 }
 
 func (c *command) down(ctx *cli.Context) {
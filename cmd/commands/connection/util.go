@@ -18,17 +18,24 @@
 package connection
 
 import (
-	"fmt"
-
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 )
 
-func proposalFormatted(p *contract.ProposalDTO) string {
-	return fmt.Sprintf("| Identity: %s\t| Type: %s\t| Country: %s\t | Price: %s/hour\t%s/GiB\t|",
-		p.ProviderID,
-		p.Location.IPType,
-		p.Location.Country,
-		p.Price.PerHourTokens.Human,
-		p.Price.PerGiBTokens.Human,
-	)
+// proposalResult is the stable schema of listed proposals, prices are given in MYST.
+type proposalResult struct {
+	ProviderID   string `json:"provider_id"`
+	IPType       string `json:"ip_type"`
+	Country      string `json:"country"`
+	PricePerHour string `json:"price_per_hour"`
+	PricePerGiB  string `json:"price_per_gib"`
+}
+
+func newProposalResult(p *contract.ProposalDTO) proposalResult {
+	return proposalResult{
+		ProviderID:   p.ProviderID,
+		IPType:       p.Location.IPType,
+		Country:      p.Location.Country,
+		PricePerHour: p.Price.PerHourTokens.Ether,
+		PricePerGiB:  p.Price.PerGiBTokens.Ether,
+	}
 }
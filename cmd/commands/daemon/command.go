@@ -20,6 +20,7 @@ package daemon
 import (
 	"github.com/mysteriumnetwork/node/cmd"
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/config/declarative"
 	"github.com/mysteriumnetwork/node/config/urfavecli/clicontext"
 	"github.com/mysteriumnetwork/node/core/node"
 	"github.com/rs/zerolog/log"
@@ -42,6 +43,14 @@ func NewCommand() *cli.Command {
 			config.ParseFlagsServiceWireguard(ctx)
 			config.ParseFlagsServiceNoop(ctx)
 			config.ParseFlagsNode(ctx)
+			if path := config.GetString(config.FlagConfigFile); path != "" {
+				if _, err := declarative.LoadInto(config.Current, path); err != nil {
+					return err
+				}
+			}
+			if err := di.LoadRemoteConfig(); err != nil {
+				return err
+			}
 
 			nodeOptions := node.GetOptions()
 			if err := di.Bootstrap(*nodeOptions); err != nil {
@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package devstack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/mysteriumnetwork/node/cmd"
+	"github.com/mysteriumnetwork/node/testkit/devstack"
+)
+
+// CommandName is the name of the devstack command.
+const CommandName = "devstack"
+
+var (
+	defaults = devstack.DefaultConfig()
+
+	flagHost = cli.StringFlag{
+		Name:  "host",
+		Usage: "Host devstack services listen on",
+		Value: defaults.Host,
+	}
+	flagChainPort = cli.IntFlag{
+		Name:  "chain.port",
+		Usage: "Port of simulated chain JSON-RPC",
+		Value: defaults.ChainPort,
+	}
+	flagHermesPort = cli.IntFlag{
+		Name:  "hermes.port",
+		Usage: "Port of mock hermes",
+		Value: defaults.HermesPort,
+	}
+	flagTransactorPort = cli.IntFlag{
+		Name:  "transactor.port",
+		Usage: "Port of mock transactor",
+		Value: defaults.TransactorPort,
+	}
+	flagControlPort = cli.IntFlag{
+		Name:  "control.port",
+		Usage: "Port of fault injection control API",
+		Value: defaults.ControlPort,
+	}
+	flagKeystore = cli.StringFlag{
+		Name:  "keystore",
+		Usage: "Keystore directory with test identities, point node keystore to it to use them",
+		Value: defaults.KeystoreDir,
+	}
+	flagIdentities = cli.IntFlag{
+		Name:  "identities",
+		Usage: "Number of funded and registered test identities",
+		Value: defaults.Identities,
+	}
+	flagLatency = cli.DurationFlag{
+		Name:  "latency",
+		Usage: "Latency injected into every request to devstack services",
+	}
+	flagFailureRate = cli.Float64Flag{
+		Name:  "failure-rate",
+		Usage: "Share of requests to devstack services failed on purpose, from 0 to 1",
+	}
+)
+
+// NewCommand creates devstack command.
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:      CommandName,
+		Usage:     "Starts local simulated chain, mock hermes and transactor with funded test identities",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			&flagHost, &flagChainPort, &flagHermesPort, &flagTransactorPort, &flagControlPort,
+			&flagKeystore, &flagIdentities, &flagLatency, &flagFailureRate,
+		},
+		Action: run,
+	}
+}
+
+func run(ctx *cli.Context) error {
+	config := devstack.DefaultConfig()
+	config.Host = ctx.String(flagHost.Name)
+	config.ChainPort = ctx.Int(flagChainPort.Name)
+	config.HermesPort = ctx.Int(flagHermesPort.Name)
+	config.TransactorPort = ctx.Int(flagTransactorPort.Name)
+	config.ControlPort = ctx.Int(flagControlPort.Name)
+	config.KeystoreDir = ctx.String(flagKeystore.Name)
+	config.Identities = ctx.Int(flagIdentities.Name)
+	config.Faults = devstack.FaultConfig{
+		Latency:     ctx.Duration(flagLatency.Name),
+		FailureRate: ctx.Float64(flagFailureRate.Name),
+	}
+
+	stack, err := devstack.New(config)
+	if err != nil {
+		return err
+	}
+	defer stack.Close()
+
+	if err := stack.Start(); err != nil {
+		return err
+	}
+
+	out := ctx.App.Writer
+	fmt.Fprintln(out, "Devstack is running. Test identities (keystore passphrase is empty):")
+	for _, id := range stack.Identities() {
+		fmt.Fprintln(out, "  ", id.Hex())
+	}
+	fmt.Fprintln(out, "Start nodes with:")
+	fmt.Fprintln(out, "  ", strings.Join(stack.NodeFlags(), " "))
+	fmt.Fprintf(out, "Inject faults with: curl -X PUT http://%s:%d/faults/<chain|hermes|transactor> -d '{\"latency\":\"500ms\",\"failure_rate\":0.1}'\n",
+		config.Host, config.ControlPort)
+
+	stop := make(chan struct{})
+	cmd.RegisterSignalCallback(func() { close(stop) })
+	<-stop
+
+	return nil
+}
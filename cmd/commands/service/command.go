@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/chzyer/readline"
 	"github.com/mysteriumnetwork/terms/terms-go"
 
 	"github.com/pkg/errors"
@@ -32,6 +33,7 @@ import (
 	"github.com/mysteriumnetwork/node/cmd"
 	"github.com/mysteriumnetwork/node/cmd/commands/cli/clio"
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/config/declarative"
 	"github.com/mysteriumnetwork/node/config/urfavecli/clicontext"
 	"github.com/mysteriumnetwork/node/core/node"
 	"github.com/mysteriumnetwork/node/services"
@@ -40,13 +42,17 @@ import (
 	"github.com/mysteriumnetwork/node/services/wireguard"
 	"github.com/mysteriumnetwork/node/tequilapi/client"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/utils"
 )
 
+// CommandName is the name of the service command
+const CommandName = "service"
+
 // NewCommand function creates service command
 func NewCommand(licenseCommandName string) *cli.Command {
 	var di cmd.Dependencies
 	command := &cli.Command{
-		Name:      "service",
+		Name:      CommandName,
 		Usage:     "Starts and publishes services on Mysterium Network",
 		ArgsUsage: "comma separated list of services to start",
 		Before:    clicontext.LoadUserConfigQuietly,
@@ -57,6 +63,14 @@ func NewCommand(licenseCommandName string) *cli.Command {
 			config.ParseFlagsServiceWireguard(ctx)
 			config.ParseFlagsServiceNoop(ctx)
 			config.ParseFlagsNode(ctx)
+			if path := config.GetString(config.FlagConfigFile); path != "" {
+				if _, err := declarative.LoadInto(config.Current, path); err != nil {
+					return err
+				}
+			}
+			if err := di.LoadRemoteConfig(); err != nil {
+				return err
+			}
 
 			if err := hasAcceptedTOS(ctx); err != nil {
 				clio.PrintTOSError(err)
@@ -85,6 +99,7 @@ func NewCommand(licenseCommandName string) *cli.Command {
 		After: func(ctx *cli.Context) error {
 			return di.Shutdown()
 		},
+		Subcommands: newSystemCommands(),
 	}
 
 	config.RegisterFlagsServiceStart(&command.Flags)
@@ -126,31 +141,98 @@ func (sc *serviceCommand) Run(ctx *cli.Context) (err error) {
 		}
 	}
 
+	bindings, err := parseBindings(config.GetStringSlice(config.FlagServiceIdentities), "<service type>=<identity>")
+	if err != nil {
+		return err
+	}
+	passphrases, err := parseBindings(config.GetStringSlice(config.FlagServiceIdentityPassphrases), "<identity>=<passphrase>")
+	if err != nil {
+		return err
+	}
+
 	sc.tryRememberTOS(ctx, sc.errorChannel)
-	providerID := sc.unlockIdentity(
-		ctx.String(config.FlagIdentity.Name),
-		ctx.String(config.FlagIdentityPassphrase.Name),
-	)
+	passphrase := ctx.String(config.FlagIdentityPassphrase.Name)
+	providerID := sc.unlockIdentity(ctx.String(config.FlagIdentity.Name), passphrase)
 	log.Info().Msgf("Unlocked identity: %v", providerID)
 
+	unlocked := map[string]bool{strings.ToLower(providerID): true}
+	unlockErrs := utils.ErrorCollection{}
+	startRequests := make([]contract.ServiceStartRequest, 0, len(serviceTypes))
 	for _, serviceType := range serviceTypes {
 		serviceOpts, err := services.GetStartOptions(serviceType)
 		if err != nil {
 			return err
 		}
-		startRequest := contract.ServiceStartRequest{
-			ProviderID:     providerID,
-			Type:           serviceType,
-			AccessPolicies: &contract.ServiceAccessPolicies{IDs: serviceOpts.AccessPolicyList},
-			Options:        serviceOpts,
+
+		providerIDs, ok := bindings[serviceType]
+		if !ok {
+			providerIDs = []string{providerID}
+		}
+		for _, id := range providerIDs {
+			if !unlocked[strings.ToLower(id)] {
+				if err := sc.unlockServiceIdentity(id, passphrases, passphrase); err != nil {
+					log.Error().Err(err).Msgf("Could not unlock identity %v for %s service", id, serviceType)
+					unlockErrs.Add(fmt.Errorf("identity %s of %s service: %w", id, serviceType, err))
+					continue
+				}
+				unlocked[strings.ToLower(id)] = true
+				log.Info().Msgf("Unlocked identity %v for %s service", id, serviceType)
+			}
+
+			startRequests = append(startRequests, contract.ServiceStartRequest{
+				ProviderID:     id,
+				Type:           serviceType,
+				AccessPolicies: &contract.ServiceAccessPolicies{IDs: serviceOpts.AccessPolicyList},
+				Options:        serviceOpts,
+			})
 		}
+	}
+	if len(unlockErrs) > 0 {
+		return errors.New(unlockErrs.Stringf("could not unlock service identities: %s", "; "))
+	}
 
+	for _, startRequest := range startRequests {
 		go sc.runService(startRequest)
 	}
 
 	return <-sc.errorChannel
 }
 
+// parseBindings parses "<key>=<value>" bindings, a key may be bound to several values.
+// Keys are matched case-insensitively, as identity addresses are.
+func parseBindings(values []string, format string) (map[string][]string, error) {
+	bindings := make(map[string][]string)
+	for _, value := range values {
+		key, bound, ok := strings.Cut(value, "=")
+		key, bound = strings.TrimSpace(key), strings.TrimSpace(bound)
+		if !ok || key == "" || bound == "" {
+			return nil, fmt.Errorf("invalid binding %q, expected %s", value, format)
+		}
+		key = strings.ToLower(key)
+		bindings[key] = append(bindings[key], bound)
+	}
+	return bindings, nil
+}
+
+// unlockServiceIdentity unlocks an identity with its own passphrase if one was given.
+// Otherwise it tries the main identity passphrase and prompts for the keystore passphrase when running in a terminal.
+func (sc *serviceCommand) unlockServiceIdentity(id string, passphrases map[string][]string, fallback string) error {
+	if own, ok := passphrases[strings.ToLower(id)]; ok {
+		return sc.tequilapi.Unlock(id, own[len(own)-1])
+	}
+
+	err := sc.tequilapi.Unlock(id, fallback)
+	if err == nil || !readline.IsTerminal(int(os.Stdin.Fd())) {
+		return err
+	}
+
+	prompted, promptErr := readline.Password(fmt.Sprintf("Keystore passphrase of identity %s: ", id))
+	if promptErr != nil {
+		return fmt.Errorf("could not read passphrase: %w", promptErr)
+	}
+	return sc.tequilapi.Unlock(id, string(prompted))
+}
+
 func (sc *serviceCommand) unlockIdentity(id, passphrase string) string {
 	const retryRate = 10 * time.Second
 	for {
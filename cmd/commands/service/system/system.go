@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package system
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mysteriumnetwork/node/config"
+)
+
+const (
+	// Name of the node system service.
+	Name = "myst"
+	// DisplayName of the node system service.
+	DisplayName = "Mysterium Node"
+	// Description of the node system service.
+	Description = "Server for Mysterium - decentralised VPN Network"
+)
+
+var (
+	// ErrNotPrivileged is returned when the system service is managed without administrator privileges.
+	ErrNotPrivileged = errors.New("managing system service requires administrator privileges")
+	// ErrNotInstalled is returned when the system service is not installed.
+	ErrNotInstalled = errors.New("system service is not installed")
+	// ErrUnsupported is returned on platforms without supported service manager.
+	ErrUnsupported = errors.New("system service is not supported on this platform")
+)
+
+// Directories are node directories used by the system service.
+type Directories struct {
+	Config  string
+	Data    string
+	Log     string
+	Runtime string
+}
+
+// Options describe the installed system service.
+type Options struct {
+	// Executable is the absolute path of the node binary.
+	Executable string
+	// Args are passed to the node binary, e.g. global flags followed by the command.
+	Args []string
+	// User runs the service instead of the administrator, where supported.
+	User        string
+	Directories Directories
+}
+
+// Status describes the installed system service.
+type Status struct {
+	Installed bool
+	Enabled   bool
+	Running   bool
+	// Manager is the service manager the service is registered with, e.g. systemd.
+	Manager string
+	// Path is the file describing the service, if any.
+	Path string
+}
+
+// String returns a human readable status.
+func (s Status) String() string {
+	if !s.Installed {
+		return fmt.Sprintf("%s service is not installed", Name)
+	}
+
+	state := "stopped"
+	if s.Running {
+		state = "running"
+	}
+	details := []string{state}
+	if s.Enabled {
+		details = append(details, "starts on boot")
+	}
+	if s.Path != "" {
+		details = append(details, s.Path)
+	}
+	return fmt.Sprintf("%s service is installed with %s: %s", Name, s.Manager, strings.Join(details, ", "))
+}
+
+// DirectoryArgs returns global node flags pointing to the service directories.
+func (d Directories) DirectoryArgs() []string {
+	return []string{
+		"--" + config.FlagConfigDir.Name + "=" + d.Config,
+		"--" + config.FlagDataDir.Name + "=" + d.Data,
+		"--" + config.FlagLogDir.Name + "=" + d.Log,
+		"--" + config.FlagRuntimeDir.Name + "=" + d.Runtime,
+	}
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package system
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	manager   = "launchd"
+	label     = "network.mysterium." + Name
+	plistPath = "/Library/LaunchDaemons/" + label + ".plist"
+)
+
+const plistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{.Label}}</string>
+	<key>ProgramArguments</key>
+	<array>
+	{{- range .Args}}
+		<string>{{xml .}}</string>
+	{{- end}}
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>StandardOutPath</key>
+	<string>{{xml .Log}}</string>
+	<key>StandardErrorPath</key>
+	<string>{{xml .Log}}</string>
+</dict>
+</plist>
+`
+
+// DefaultDirectories returns directories of the node running as a system service.
+func DefaultDirectories() Directories {
+	return Directories{
+		Config:  "/Library/Application Support/MysteriumNode/config",
+		Data:    "/Library/Application Support/MysteriumNode/data",
+		Log:     "/Library/Logs/MysteriumNode",
+		Runtime: "/Library/Application Support/MysteriumNode/run",
+	}
+}
+
+// Install writes the launch daemon of the node and loads it.
+func Install(options Options) error {
+	if !IsPrivileged() {
+		return ErrNotPrivileged
+	}
+	if options.User != "" {
+		return errors.New("launch daemon of the node runs as root, user is not supported")
+	}
+	if err := createDirectories(options.Directories); err != nil {
+		return err
+	}
+
+	plist, err := renderPlist(options)
+	if err != nil {
+		return err
+	}
+
+	// Unloaded to reload the previous installation.
+	_, _ = run("launchctl", "unload", plistPath)
+	if err := os.WriteFile(plistPath, plist, 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", plistPath, err)
+	}
+	out, err := run("launchctl", "load", "-w", plistPath)
+	if err == nil && strings.Contains(out, "Invalid property") {
+		err = errors.New("invalid plist file")
+	}
+	return err
+}
+
+// Uninstall unloads the launch daemon of the node and removes it. Node data is kept.
+func Uninstall() error {
+	if !IsPrivileged() {
+		return ErrNotPrivileged
+	}
+	if _, err := os.Stat(plistPath); os.IsNotExist(err) {
+		return ErrNotInstalled
+	}
+
+	if _, err := run("launchctl", "unload", "-w", plistPath); err != nil {
+		return err
+	}
+	return os.Remove(plistPath)
+}
+
+// GetStatus returns the status of the node launch daemon.
+func GetStatus() (Status, error) {
+	status := Status{Manager: manager, Path: plistPath}
+	if _, err := os.Stat(plistPath); errors.Is(err, os.ErrNotExist) {
+		return status, nil
+	} else if err != nil {
+		return status, err
+	}
+	status.Installed = true
+
+	// Fails if the daemon is not loaded, lists its PID while it runs.
+	out, err := run("launchctl", "list", label)
+	status.Enabled = err == nil
+	status.Running = err == nil && strings.Contains(out, `"PID" = `)
+	return status, nil
+}
+
+func renderPlist(options Options) ([]byte, error) {
+	tpl, err := template.New("plist").Funcs(template.FuncMap{"xml": escapeXML}).Parse(plistTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var plist bytes.Buffer
+	err = tpl.Execute(&plist, map[string]interface{}{
+		"Label": label,
+		"Args":  append([]string{options.Executable}, options.Args...),
+		"Log":   filepath.Join(options.Directories.Log, "stdout.log"),
+	})
+	return plist.Bytes(), err
+}
+
+func escapeXML(value string) (string, error) {
+	var escaped strings.Builder
+	err := xml.EscapeText(&escaped, []byte(value))
+	return escaped.String(), err
+}
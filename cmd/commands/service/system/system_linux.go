@@ -0,0 +1,212 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package system
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	manager  = "systemd"
+	unitPath = "/etc/systemd/system/" + Name + ".service"
+)
+
+const unitTemplate = `[Unit]
+Description={{.Description}}
+Documentation=https://mysterium.network/
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+{{- if .User}}
+User={{.User}}
+Group={{.Group}}
+AmbientCapabilities=CAP_NET_ADMIN CAP_NET_RAW CAP_NET_BIND_SERVICE
+{{- end}}
+{{- if .RuntimeDirectory}}
+RuntimeDirectory={{.RuntimeDirectory}}
+{{- end}}
+ExecStart={{.ExecStart}}
+KillMode=process
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// DefaultDirectories returns directories of the node running as a system service.
+func DefaultDirectories() Directories {
+	return Directories{
+		Config:  "/etc/mysterium-node",
+		Data:    "/var/lib/mysterium-node",
+		Log:     "/var/log/mysterium-node",
+		Runtime: "/run/mysterium-node",
+	}
+}
+
+// Install writes the systemd unit of the node, enables and starts it.
+func Install(options Options) error {
+	if !IsPrivileged() {
+		return ErrNotPrivileged
+	}
+
+	if err := createDirectories(options.Directories); err != nil {
+		return err
+	}
+
+	group := ""
+	if options.User != "" {
+		u, err := user.Lookup(options.User)
+		if err != nil {
+			return fmt.Errorf("could not find user %s: %w", options.User, err)
+		}
+		g, err := user.LookupGroupId(u.Gid)
+		if err != nil {
+			return fmt.Errorf("could not find group of user %s: %w", options.User, err)
+		}
+		group = g.Name
+
+		if err := chownDirectories(options.Directories, u); err != nil {
+			return err
+		}
+	}
+
+	unit, err := renderUnit(options, group)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(unitPath, unit, 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", unitPath, err)
+	}
+
+	if _, err := run("systemctl", "daemon-reload"); err != nil {
+		return err
+	}
+	_, err = run("systemctl", "enable", "--now", Name)
+	return err
+}
+
+// Uninstall stops and disables the node service and removes its systemd unit. Node data is kept.
+func Uninstall() error {
+	if !IsPrivileged() {
+		return ErrNotPrivileged
+	}
+	if _, err := os.Stat(unitPath); os.IsNotExist(err) {
+		return ErrNotInstalled
+	}
+
+	if _, err := run("systemctl", "disable", "--now", Name); err != nil {
+		return err
+	}
+	if err := os.Remove(unitPath); err != nil {
+		return fmt.Errorf("could not remove %s: %w", unitPath, err)
+	}
+	_, err := run("systemctl", "daemon-reload")
+	return err
+}
+
+// GetStatus returns the status of the node systemd unit.
+func GetStatus() (Status, error) {
+	status := Status{Manager: manager, Path: unitPath}
+	if _, err := os.Stat(unitPath); errors.Is(err, os.ErrNotExist) {
+		return status, nil
+	} else if err != nil {
+		return status, err
+	}
+	status.Installed = true
+
+	// Both commands exit with non-zero code when the unit is disabled or inactive.
+	enabled, _ := run("systemctl", "is-enabled", Name)
+	status.Enabled = strings.TrimSpace(enabled) == "enabled"
+	active, _ := run("systemctl", "is-active", Name)
+	status.Running = strings.TrimSpace(active) == "active"
+	return status, nil
+}
+
+func renderUnit(options Options, group string) ([]byte, error) {
+	tpl, err := template.New("unit").Parse(unitTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	execStart := make([]string, 0, len(options.Args)+1)
+	for _, arg := range append([]string{options.Executable}, options.Args...) {
+		execStart = append(execStart, quoteSystemd(arg))
+	}
+
+	var unit bytes.Buffer
+	err = tpl.Execute(&unit, map[string]string{
+		"Description":      Description,
+		"User":             options.User,
+		"Group":            group,
+		"RuntimeDirectory": runtimeDirectory(options.Directories.Runtime),
+		"ExecStart":        strings.Join(execStart, " "),
+	})
+	return unit.Bytes(), err
+}
+
+// quoteSystemd quotes command line argument for systemd, which also expands "$" and "%" specifiers.
+func quoteSystemd(arg string) string {
+	arg = strings.NewReplacer("%", "%%", "$", "$$").Replace(arg)
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\;") {
+		return arg
+	}
+	return strconv.Quote(arg)
+}
+
+// runtimeDirectory returns the name of the runtime directory systemd creates under /run on every start,
+// owned by the service user, as /run is cleaned on boot.
+func runtimeDirectory(dir string) string {
+	if filepath.Dir(dir) != "/run" {
+		return ""
+	}
+	return filepath.Base(dir)
+}
+
+func chownDirectories(dirs Directories, u *user.User) error {
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{dirs.Config, dirs.Data, dirs.Log, dirs.Runtime} {
+		err := filepath.Walk(dir, func(path string, _ os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			return os.Lchown(path, uid, gid)
+		})
+		if err != nil {
+			return fmt.Errorf("could not change owner of %s: %w", dir, err)
+		}
+	}
+	return nil
+}
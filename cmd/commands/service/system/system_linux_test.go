@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderUnit(t *testing.T) {
+	options := Options{
+		Executable:  "/usr/bin/myst",
+		Args:        append(DefaultDirectories().DirectoryArgs(), "service", "--agreed-terms-and-conditions", "--identity.passphrase=my secret$%"),
+		Directories: DefaultDirectories(),
+	}
+
+	unit, err := renderUnit(options, "")
+	require.NoError(t, err)
+	assert.Equal(t, `[Unit]
+Description=Server for Mysterium - decentralised VPN Network
+Documentation=https://mysterium.network/
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+RuntimeDirectory=mysterium-node
+ExecStart=/usr/bin/myst --config-dir=/etc/mysterium-node --data-dir=/var/lib/mysterium-node --log-dir=/var/log/mysterium-node --runtime-dir=/run/mysterium-node service --agreed-terms-and-conditions "--identity.passphrase=my secret$$%%"
+KillMode=process
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=multi-user.target
+`, string(unit))
+
+	options.User = "mysterium-node"
+	options.Directories.Runtime = "/var/run/myst"
+	unit, err = renderUnit(options, "mysterium-node")
+	require.NoError(t, err)
+	assert.Contains(t, string(unit), `[Service]
+User=mysterium-node
+Group=mysterium-node
+AmbientCapabilities=CAP_NET_ADMIN CAP_NET_RAW CAP_NET_BIND_SERVICE
+ExecStart=/usr/bin/myst `)
+}
@@ -0,0 +1,45 @@
+//go:build !darwin && !linux && !windows
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package system
+
+// DefaultDirectories returns directories of the node running as a system service.
+func DefaultDirectories() Directories {
+	return Directories{}
+}
+
+// IsPrivileged checks if the process may manage system services.
+func IsPrivileged() bool {
+	return false
+}
+
+// Install is not supported on this platform.
+func Install(Options) error {
+	return ErrUnsupported
+}
+
+// Uninstall is not supported on this platform.
+func Uninstall() error {
+	return ErrUnsupported
+}
+
+// GetStatus is not supported on this platform.
+func GetStatus() (Status, error) {
+	return Status{}, ErrUnsupported
+}
@@ -0,0 +1,52 @@
+//go:build linux || darwin
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// IsPrivileged checks if the process may manage system services.
+func IsPrivileged() bool {
+	return os.Geteuid() == 0
+}
+
+func createDirectories(dirs Directories) error {
+	for _, dir := range []string{dirs.Config, dirs.Data, dirs.Log, dirs.Runtime} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("could not create directory %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+func run(name string, args ...string) (string, error) {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	log.Debug().Msgf("[%s %s] out:\n%s", name, strings.Join(args, " "), output)
+	if err != nil {
+		return string(output), fmt.Errorf("%s %s failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
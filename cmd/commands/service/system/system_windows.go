@@ -0,0 +1,237 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+const (
+	manager     = "Windows service control manager"
+	serviceName = "MysteriumNode"
+)
+
+// DefaultDirectories returns directories of the node running as a system service.
+func DefaultDirectories() Directories {
+	programData := os.Getenv("ProgramData")
+	if programData == "" {
+		programData = `C:\ProgramData`
+	}
+	root := filepath.Join(programData, serviceName)
+	return Directories{
+		Config:  filepath.Join(root, "config"),
+		Data:    filepath.Join(root, "data"),
+		Log:     filepath.Join(root, "logs"),
+		Runtime: filepath.Join(root, "run"),
+	}
+}
+
+// IsPrivileged checks if the process may manage system services.
+func IsPrivileged() bool {
+	return windows.GetCurrentProcessToken().IsElevated()
+}
+
+// Install registers the node as a Windows service running as LocalSystem and starts it.
+// The service is restarted by the service control manager when the node exits with non-zero code.
+func Install(options Options) error {
+	if !IsPrivileged() {
+		return ErrNotPrivileged
+	}
+	if options.User != "" {
+		return errors.New("windows service of the node runs as LocalSystem, user is not supported")
+	}
+	for _, dir := range []string{options.Directories.Config, options.Directories.Data, options.Directories.Log, options.Directories.Runtime} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("could not create directory %s: %w", dir, err)
+		}
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("could not connect to service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	if err := removeService(m); err == nil {
+		log.Info().Msg("Removed previous service installation")
+		if err := waitServiceDeleted(m); err != nil {
+			return err
+		}
+	}
+
+	s, err := m.CreateService(serviceName, options.Executable, mgr.Config{
+		ServiceType:  windows.SERVICE_WIN32_OWN_PROCESS,
+		StartType:    mgr.StartAutomatic,
+		ErrorControl: mgr.ErrorNormal,
+		DisplayName:  DisplayName,
+		Description:  Description,
+		Dependencies: []string{"Nsi"},
+	}, options.Args...)
+	if err != nil {
+		return fmt.Errorf("could not create service: %w", err)
+	}
+	defer s.Close()
+
+	restart := mgr.RecoveryAction{Type: mgr.ServiceRestart, Delay: 5 * time.Second}
+	if err := s.SetRecoveryActions([]mgr.RecoveryAction{restart, restart, restart}, uint32((24 * time.Hour).Seconds())); err != nil {
+		return fmt.Errorf("could not configure service recovery: %w", err)
+	}
+	if err := s.SetRecoveryActionsOnNonCrashFailures(true); err != nil {
+		return fmt.Errorf("could not configure service recovery: %w", err)
+	}
+
+	if err := s.Start(); err != nil {
+		return fmt.Errorf("could not start service: %w", err)
+	}
+	return nil
+}
+
+// Uninstall stops and removes the node Windows service. Node data is kept.
+func Uninstall() error {
+	if !IsPrivileged() {
+		return ErrNotPrivileged
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("could not connect to service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	return removeService(m)
+}
+
+// GetStatus returns the status of the node Windows service.
+func GetStatus() (Status, error) {
+	status := Status{Manager: manager}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return status, fmt.Errorf("could not connect to service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return status, nil
+	}
+	defer s.Close()
+	status.Installed = true
+
+	config, err := s.Config()
+	if err != nil {
+		return status, fmt.Errorf("could not query service config: %w", err)
+	}
+	status.Enabled = config.StartType == mgr.StartAutomatic
+	status.Path = config.BinaryPathName
+
+	state, err := s.Query()
+	if err != nil {
+		return status, fmt.Errorf("could not query service state: %w", err)
+	}
+	status.Running = state.State == svc.Running
+	return status, nil
+}
+
+// IsService checks if the process was started by the service control manager.
+func IsService() bool {
+	isService, err := svc.IsWindowsService()
+	return err == nil && isService
+}
+
+// RunService runs the node under the service control manager. Stop is called when the service
+// is requested to stop, the service stops once run returns.
+func RunService(run func() error, stop func()) error {
+	return svc.Run(serviceName, &handler{run: run, stop: stop})
+}
+
+type handler struct {
+	run  func() error
+	stop func()
+}
+
+func (h *handler) Execute(_ []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+
+	changes <- svc.Status{State: svc.StartPending}
+	done := make(chan error, 1)
+	go func() { done <- h.run() }()
+	changes <- svc.Status{State: svc.Running, Accepts: accepted}
+
+	for {
+		select {
+		case err := <-done:
+			changes <- svc.Status{State: svc.StopPending}
+			if err != nil {
+				log.Error().Err(err).Msg("Node service failed")
+				// Non-zero exit code makes the service control manager apply recovery actions.
+				return true, 1
+			}
+			return false, 0
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				changes <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				h.stop()
+			}
+		}
+	}
+}
+
+func removeService(m *mgr.Mgr) error {
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return ErrNotInstalled
+	}
+	defer s.Close()
+
+	// Stopped service returns error, which doesn't matter as it is deleted anyway.
+	_, _ = s.Control(svc.Stop)
+	if err := s.Delete(); err != nil {
+		return fmt.Errorf("could not mark service for deletion: %w", err)
+	}
+	return nil
+}
+
+// waitServiceDeleted waits until the service can't be opened anymore.
+func waitServiceDeleted(m *mgr.Mgr) error {
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case <-timeout:
+			return errors.New("timeout waiting for service deletion")
+		case <-time.After(100 * time.Millisecond):
+			s, err := m.OpenService(serviceName)
+			if err != nil {
+				return nil
+			}
+			s.Close()
+		}
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/mysteriumnetwork/node/cmd/commands/cli/clio"
+	"github.com/mysteriumnetwork/node/cmd/commands/service/system"
+	"github.com/mysteriumnetwork/node/config"
+)
+
+var flagSystemUser = cli.StringFlag{
+	Name:  "user",
+	Usage: "User the node runs as, systemd only. The node runs as administrator if empty",
+}
+
+// newSystemCommands creates commands registering the node as a system service,
+// a systemd unit on Linux, launch daemon on macOS or Windows service.
+func newSystemCommands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:      "install",
+			Usage:     "Installs the node as a system service which provides services on boot",
+			ArgsUsage: "[service command flags]",
+			Description: "Registers the node with the system service manager, enables and starts it. " +
+				"Flags given after -- are passed to the service command run by the system service, e.g. -- --active-services=wireguard. " +
+				"Unless directory flags are given, the node uses system-wide directories.",
+			Flags:  []cli.Flag{&flagSystemUser, &config.FlagAgreedTermsConditions},
+			Action: installSystemService,
+		},
+		{
+			Name:  "uninstall",
+			Usage: "Stops and removes the node system service, node data is kept",
+			Action: func(ctx *cli.Context) error {
+				if err := system.Uninstall(); err != nil {
+					return err
+				}
+				clio.Success("System service uninstalled")
+				return nil
+			},
+		},
+		{
+			Name:  "status",
+			Usage: "Shows the node system service status",
+			Action: func(ctx *cli.Context) error {
+				status, err := system.GetStatus()
+				if err != nil {
+					return err
+				}
+				clio.Info(status.String())
+				return nil
+			},
+		},
+	}
+}
+
+func installSystemService(ctx *cli.Context) error {
+	if err := hasAcceptedTOS(ctx); err != nil {
+		clio.PrintTOSError(err)
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("could not locate node executable: %w", err)
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return fmt.Errorf("could not locate node executable: %w", err)
+	}
+
+	dirs, err := systemDirectories(ctx)
+	if err != nil {
+		return err
+	}
+
+	args := append(dirs.DirectoryArgs(), CommandName, "--"+config.FlagAgreedTermsConditions.Name)
+	args = append(args, ctx.Args().Slice()...)
+
+	err = system.Install(system.Options{
+		Executable:  executable,
+		Args:        args,
+		User:        ctx.String(flagSystemUser.Name),
+		Directories: dirs,
+	})
+	if err != nil {
+		return err
+	}
+	clio.Success("System service installed and started")
+	return nil
+}
+
+// systemDirectories returns system-wide node directories, overridden by directory flags given explicitly.
+func systemDirectories(ctx *cli.Context) (system.Directories, error) {
+	dirs := system.DefaultDirectories()
+	for _, dir := range []struct {
+		flag cli.StringFlag
+		path *string
+	}{
+		{config.FlagConfigDir, &dirs.Config},
+		{config.FlagDataDir, &dirs.Data},
+		{config.FlagLogDir, &dirs.Log},
+		{config.FlagRuntimeDir, &dirs.Runtime},
+	} {
+		if !ctx.IsSet(dir.flag.Name) {
+			continue
+		}
+		path, err := filepath.Abs(ctx.String(dir.flag.Name))
+		if err != nil {
+			return system.Directories{}, fmt.Errorf("invalid %s: %w", dir.flag.Name, err)
+		}
+		*dir.path = path
+	}
+	return dirs, nil
+}
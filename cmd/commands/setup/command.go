@@ -0,0 +1,411 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package setup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mysteriumnetwork/terms/terms-go"
+	"github.com/urfave/cli/v2"
+
+	"github.com/mysteriumnetwork/node/cmd/commands/cli/clio"
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/node"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/services"
+	"github.com/mysteriumnetwork/node/services/datatransfer"
+	"github.com/mysteriumnetwork/node/services/scraping"
+	"github.com/mysteriumnetwork/node/services/wireguard"
+	"github.com/mysteriumnetwork/node/tequilapi/client"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+// CommandName is the name of this command
+const CommandName = "setup"
+
+var (
+	flagNonInteractive = cli.BoolFlag{
+		Name:  "non-interactive",
+		Usage: "Do not ask questions, take answers from flags and current node config",
+	}
+	flagSkipRegistration = cli.BoolFlag{
+		Name:  "skip-registration",
+		Usage: "Do not register the identity",
+	}
+	flagToken = cli.StringFlag{
+		Name:  "token",
+		Usage: "Referral token used to register the identity",
+	}
+	flagBeneficiary = cli.StringFlag{
+		Name:  "beneficiary",
+		Usage: "Address where earnings of the identity are withdrawn to",
+	}
+	flagServices = cli.StringFlag{
+		Name:  "services",
+		Usage: "Comma separated list of services to enable",
+	}
+	flagSkipChecks = cli.BoolFlag{
+		Name:  "skip-checks",
+		Usage: "Do not validate node connectivity",
+	}
+)
+
+// defaultServices are enabled when neither flags nor node config select services.
+var defaultServices = []string{wireguard.ServiceType, scraping.ServiceType, datatransfer.ServiceType}
+
+// NewCommand function creates setup command.
+func NewCommand() *cli.Command {
+	cmd := &command{}
+	return &cli.Command{
+		Name:  CommandName,
+		Usage: "Set up the node for the first run",
+		Description: "Guides through identity creation, registration and beneficiary setup, selects services to provide " +
+			"and validates node connectivity. Answers may be given by flags for automated setup together with --" + flagNonInteractive.Name,
+		Flags: []cli.Flag{
+			&config.FlagTequilapiAddress,
+			&config.FlagTequilapiPort,
+			&config.FlagIdentity,
+			&config.FlagIdentityPassphrase,
+			&config.FlagAgreedTermsConditions,
+			&flagNonInteractive,
+			&flagSkipRegistration,
+			&flagToken,
+			&flagBeneficiary,
+			&flagServices,
+			&flagSkipChecks,
+		},
+		Before: func(ctx *cli.Context) error {
+			var err error
+			cmd.tequilapi, err = clio.NewTequilApiClient(ctx)
+			if err != nil {
+				return err
+			}
+			cmd.api = client.NewAPI(fmt.Sprintf("http://%s:%d", clio.TequilAPIAddress(ctx), clio.TequilAPIPort(ctx)))
+			cmd.prompt = newPrompter(!ctx.Bool(flagNonInteractive.Name), os.Stdin, os.Stdout)
+			return nil
+		},
+		Action: func(ctx *cli.Context) error {
+			return cmd.run(ctx)
+		},
+	}
+}
+
+type command struct {
+	tequilapi *client.Client
+	api       *client.API
+	prompt    *prompter
+}
+
+func (c *command) run(ctx *cli.Context) error {
+	clio.Status("STEP", "Identity")
+	id, err := c.setupIdentity(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.setupTerms(ctx); err != nil {
+		return err
+	}
+
+	clio.Status("STEP", "Registration")
+	if err := c.setupRegistration(ctx, id); err != nil {
+		return err
+	}
+
+	clio.Status("STEP", "Services")
+	serviceTypes, err := c.selectServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !ctx.Bool(flagSkipChecks.Name) {
+		clio.Status("STEP", "Connectivity")
+		c.checkConnectivity(ctx.Context)
+	}
+
+	if err := c.tequilapi.SetConfig(map[string]interface{}{
+		config.FlagActiveServices.Name: strings.Join(serviceTypes, ","),
+	}); err != nil {
+		return fmt.Errorf("could not save node config: %w", err)
+	}
+
+	clio.Success("Node is set up, enabled services start with `myst service`")
+	return nil
+}
+
+func (c *command) setupIdentity(ctx *cli.Context) (string, error) {
+	address := ctx.String(config.FlagIdentity.Name)
+	passphrase := ctx.String(config.FlagIdentityPassphrase.Name)
+
+	if address == "" {
+		ids, err := c.tequilapi.GetIdentities()
+		if err != nil {
+			return "", fmt.Errorf("could not list identities: %w", err)
+		}
+		if len(ids) > 0 {
+			for _, id := range ids {
+				clio.Info("Found identity: " + id.Address)
+			}
+			address, err = c.prompt.String(`Identity to use, "new" creates one`, ids[0].Address)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if address == "new" {
+		id, err := c.tequilapi.NewIdentity(passphrase)
+		if err != nil {
+			return "", fmt.Errorf("could not create identity: %w", err)
+		}
+		address = id.Address
+	}
+
+	id, err := c.tequilapi.CurrentIdentity(address, passphrase)
+	if err != nil {
+		return "", fmt.Errorf("could not get or create identity: %w", err)
+	}
+	if err := c.tequilapi.Unlock(id.Address, passphrase); err != nil {
+		return "", fmt.Errorf("could not unlock identity %s: %w", id.Address, err)
+	}
+
+	clio.Success("Using identity: " + id.Address)
+	return id.Address, nil
+}
+
+func (c *command) setupTerms(ctx *cli.Context) error {
+	agreed := ctx.Bool(config.FlagAgreedTermsConditions.Name)
+	if !agreed {
+		var err error
+		agreed, err = c.prompt.Confirm(fmt.Sprintf("Do you agree with terms & conditions version %s", terms.TermsVersion), false)
+		if err != nil {
+			return err
+		}
+	}
+	if !agreed {
+		clio.Warn("Terms & conditions are not agreed, the node will not provide services until you agree")
+		return nil
+	}
+
+	agree := true
+	err := c.tequilapi.UpdateTerms(contract.TermsRequest{
+		AgreedProvider: &agree,
+		AgreedConsumer: &agree,
+		AgreedVersion:  terms.TermsVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("could not save agreement with terms & conditions: %w", err)
+	}
+	return nil
+}
+
+func (c *command) setupRegistration(ctx *cli.Context, address string) error {
+	status, err := c.tequilapi.Identity(address)
+	if err != nil {
+		return fmt.Errorf("could not get identity status: %w", err)
+	}
+
+	switch status.RegistrationStatus {
+	case registry.Registered.String():
+		clio.Info("Identity is registered")
+		return c.setupBeneficiary(ctx, address)
+	case registry.InProgress.String():
+		clio.Info("Identity registration is in progress, set up the beneficiary once it completes")
+		return nil
+	}
+
+	register, err := c.prompt.Confirm("Register identity now", !ctx.Bool(flagSkipRegistration.Name))
+	if err != nil {
+		return err
+	}
+	if !register {
+		clio.Warn("Identity is not registered, register it later with `myst account register`")
+		return nil
+	}
+
+	token, err := c.prompt.String("Referral token, leave empty to pay registration fee from the identity balance", ctx.String(flagToken.Name))
+	if err != nil {
+		return err
+	}
+	beneficiary, err := c.askBeneficiary(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	var tokenPtr *string
+	if token != "" {
+		tokenPtr = &token
+	}
+	if err := c.tequilapi.RegisterIdentity(address, beneficiary, tokenPtr); err != nil {
+		return fmt.Errorf("could not register identity: %w", err)
+	}
+
+	clio.Success("Registration started. Top up the identities channel to finish it if no referral token was given.")
+	return nil
+}
+
+func (c *command) setupBeneficiary(ctx *cli.Context, address string) error {
+	current, err := c.tequilapi.Beneficiary(address)
+	if err != nil {
+		return fmt.Errorf("could not get beneficiary: %w", err)
+	}
+
+	beneficiary, err := c.askBeneficiary(ctx, current.Beneficiary)
+	if err != nil {
+		return err
+	}
+	if beneficiary == "" || strings.EqualFold(beneficiary, current.Beneficiary) {
+		return nil
+	}
+
+	if err := c.tequilapi.SettleWithBeneficiary(address, beneficiary, ""); err != nil {
+		return fmt.Errorf("could not change beneficiary: %w", err)
+	}
+	clio.Success(fmt.Sprintf("Beneficiary change to %s started, it completes after the settlement transaction is mined", beneficiary))
+	return nil
+}
+
+func (c *command) askBeneficiary(ctx *cli.Context, current string) (string, error) {
+	def := ctx.String(flagBeneficiary.Name)
+	if def == "" {
+		def = current
+	}
+
+	for {
+		beneficiary, err := c.prompt.String("Beneficiary address to receive earnings, leave empty to keep them in the identity channel", def)
+		if err != nil {
+			return "", err
+		}
+		if beneficiary == "" || common.IsHexAddress(beneficiary) {
+			return beneficiary, nil
+		}
+		if !c.prompt.interactive {
+			return "", fmt.Errorf("invalid beneficiary address: %s", beneficiary)
+		}
+		clio.Warn("Invalid beneficiary address: " + beneficiary)
+	}
+}
+
+func (c *command) selectServices(ctx *cli.Context) ([]string, error) {
+	def := ctx.String(flagServices.Name)
+	if def == "" {
+		cfg, err := c.tequilapi.FetchConfig()
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch node config: %w", err)
+		}
+		def, _ = cfg[config.FlagActiveServices.Name].(string)
+	}
+	if def == "" {
+		def = strings.Join(defaultServices, ",")
+	}
+
+	clio.Info("Available services: " + strings.Join(services.Types(), ", "))
+	for {
+		answer, err := c.prompt.String("Services to enable, comma separated", def)
+		if err != nil {
+			return nil, err
+		}
+
+		serviceTypes, err := parseServices(answer)
+		if err == nil {
+			return serviceTypes, nil
+		}
+		if !c.prompt.interactive {
+			return nil, err
+		}
+		clio.Warn(err.Error())
+	}
+}
+
+// parseServices splits comma separated service types and checks they are known.
+func parseServices(value string) ([]string, error) {
+	var serviceTypes []string
+	seen := make(map[string]struct{})
+	for _, serviceType := range strings.Split(value, ",") {
+		serviceType = strings.TrimSpace(serviceType)
+		if serviceType == "" {
+			continue
+		}
+		if !isKnownService(serviceType) {
+			return nil, fmt.Errorf("unknown service type: %s", serviceType)
+		}
+		if _, ok := seen[serviceType]; ok {
+			continue
+		}
+		seen[serviceType] = struct{}{}
+		serviceTypes = append(serviceTypes, serviceType)
+	}
+
+	if len(serviceTypes) == 0 {
+		return nil, fmt.Errorf("at least one service must be enabled")
+	}
+	return serviceTypes, nil
+}
+
+func isKnownService(serviceType string) bool {
+	for _, known := range services.Types() {
+		if known == serviceType {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConnectivity reports node dependencies, NAT type and port reachability. Failures are reported, not returned,
+// as the node can still be set up and fixed later.
+func (c *command) checkConnectivity(ctx context.Context) {
+	readiness, err := c.api.Readyz(ctx)
+	if err != nil {
+		clio.Warn("Could not check node dependencies: " + err.Error())
+	}
+	for _, dep := range readiness.Dependencies {
+		if dep.Healthy {
+			clio.Success(fmt.Sprintf("%s is reachable (%dms)", dep.Name, dep.LatencyMs))
+		} else {
+			clio.Warn(fmt.Sprintf("%s is not reachable: %s", dep.Name, dep.Error))
+		}
+	}
+
+	natType, err := c.tequilapi.NATType()
+	switch {
+	case err != nil:
+		clio.Warn("Could not detect NAT type: " + err.Error())
+	case natType.Error != "":
+		clio.Warn("Could not detect NAT type: " + natType.Error)
+	default:
+		clio.Info(fmt.Sprintf("NAT type: %s. %s", natType.Name, natType.Reachability))
+	}
+
+	status, err := c.tequilapi.NATStatus()
+	if err != nil {
+		clio.Warn("Could not get port reachability status: " + err.Error())
+		return
+	}
+	switch status.Status {
+	case node.Passed:
+		clio.Success("Node ports are reachable by consumers")
+	case node.Failed:
+		clio.Warn("Node ports are not reachable by consumers, check firewall and port forwarding of your router")
+	default:
+		clio.Info("Port reachability check has not finished yet, check it later with `myst cli`")
+	}
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package setup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/services/datatransfer"
+	"github.com/mysteriumnetwork/node/services/wireguard"
+)
+
+func TestParseServices(t *testing.T) {
+	for name, tc := range map[string]struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		"single service": {
+			value: wireguard.ServiceType,
+			want:  []string{wireguard.ServiceType},
+		},
+		"spaces and duplicates are dropped": {
+			value: " wireguard , data_transfer,,wireguard ",
+			want:  []string{wireguard.ServiceType, datatransfer.ServiceType},
+		},
+		"unknown service": {
+			value:   "wireguard,telnet",
+			wantErr: true,
+		},
+		"no services": {
+			value:   " , ",
+			wantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			serviceTypes, err := parseServices(tc.value)
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, serviceTypes)
+		})
+	}
+}
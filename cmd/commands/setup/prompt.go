@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package setup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// prompter asks questions during interactive setup, answers fall back to defaults when it is not interactive.
+type prompter struct {
+	interactive bool
+	in          *bufio.Reader
+	out         io.Writer
+}
+
+func newPrompter(interactive bool, in io.Reader, out io.Writer) *prompter {
+	return &prompter{
+		interactive: interactive,
+		in:          bufio.NewReader(in),
+		out:         out,
+	}
+}
+
+// String asks for a free form answer, empty answer selects the default one.
+func (p *prompter) String(question, def string) (string, error) {
+	if !p.interactive {
+		return def, nil
+	}
+
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	answer, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		return "", fmt.Errorf("could not read answer: %w", err)
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// Confirm asks a yes or no question until a valid answer is given.
+func (p *prompter) Confirm(question string, def bool) (bool, error) {
+	if !p.interactive {
+		return def, nil
+	}
+
+	options := "y/N"
+	if def {
+		options = "Y/n"
+	}
+	for {
+		answer, err := p.String(question+" ("+options+")", "")
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "Please answer yes or no.")
+	}
+}
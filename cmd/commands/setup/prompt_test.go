@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package setup
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrompter_String(t *testing.T) {
+	for name, tc := range map[string]struct {
+		interactive bool
+		input       string
+		def         string
+		want        string
+		wantOut     string
+	}{
+		"non interactive returns default": {
+			def:  "wireguard",
+			want: "wireguard",
+		},
+		"empty answer selects default": {
+			interactive: true,
+			input:       "\n",
+			def:         "wireguard",
+			want:        "wireguard",
+			wantOut:     "Services [wireguard]: ",
+		},
+		"answer is trimmed": {
+			interactive: true,
+			input:       "  scraping \n",
+			def:         "wireguard",
+			want:        "scraping",
+			wantOut:     "Services [wireguard]: ",
+		},
+		"answer without newline at EOF": {
+			interactive: true,
+			input:       "scraping",
+			want:        "scraping",
+			wantOut:     "Services: ",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			p := newPrompter(tc.interactive, strings.NewReader(tc.input), out)
+
+			answer, err := p.String("Services", tc.def)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, answer)
+			assert.Equal(t, tc.wantOut, out.String())
+		})
+	}
+}
+
+func TestPrompter_StringFailsOnClosedInput(t *testing.T) {
+	p := newPrompter(true, strings.NewReader(""), &bytes.Buffer{})
+
+	_, err := p.String("Services", "wireguard")
+
+	assert.Error(t, err)
+}
+
+func TestPrompter_Confirm(t *testing.T) {
+	for name, tc := range map[string]struct {
+		interactive bool
+		input       string
+		def         bool
+		want        bool
+	}{
+		"non interactive returns default": {def: true, want: true},
+		"empty answer selects default":    {interactive: true, input: "\n", def: true, want: true},
+		"yes":                             {interactive: true, input: "yes\n", want: true},
+		"short no":                        {interactive: true, input: "N\n", def: true, want: false},
+		"repeats on invalid answer":       {interactive: true, input: "maybe\ny\n", want: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := newPrompter(tc.interactive, strings.NewReader(tc.input), &bytes.Buffer{})
+
+			answer, err := p.Confirm("Register identity now", tc.def)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, answer)
+		})
+	}
+}
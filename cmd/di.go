@@ -18,12 +18,17 @@
 package cmd
 
 import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"net/http"
 	"net/url"
 	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -31,15 +36,23 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
+	"github.com/mysteriumnetwork/node/communication"
+	"github.com/mysteriumnetwork/node/communication/gossip"
 	"github.com/mysteriumnetwork/node/communication/nats"
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/config/declarative"
+	"github.com/mysteriumnetwork/node/config/source"
 	"github.com/mysteriumnetwork/node/consumer/migration"
 	consumer_session "github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/abuse"
 	"github.com/mysteriumnetwork/node/core/auth"
 	"github.com/mysteriumnetwork/node/core/beneficiary"
 	"github.com/mysteriumnetwork/node/core/connection"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/connection/profile"
+	"github.com/mysteriumnetwork/node/core/connection/speedtest"
 	"github.com/mysteriumnetwork/node/core/discovery"
+	"github.com/mysteriumnetwork/node/core/discovery/feed"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/core/ip"
 	"github.com/mysteriumnetwork/node/core/location"
@@ -48,29 +61,53 @@ import (
 	"github.com/mysteriumnetwork/node/core/payout"
 	"github.com/mysteriumnetwork/node/core/policy"
 	"github.com/mysteriumnetwork/node/core/port"
+	"github.com/mysteriumnetwork/node/core/power"
 	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/core/quality/reporter"
+	"github.com/mysteriumnetwork/node/core/rules"
+	"github.com/mysteriumnetwork/node/core/schedule"
 	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/core/slo"
 	"github.com/mysteriumnetwork/node/core/state"
+	"github.com/mysteriumnetwork/node/core/statesync"
+	"github.com/mysteriumnetwork/node/core/storage"
 	"github.com/mysteriumnetwork/node/core/storage/boltdb"
 	"github.com/mysteriumnetwork/node/core/storage/boltdb/migrations/history"
 	"github.com/mysteriumnetwork/node/core/storage/boltdb/migrator"
+	"github.com/mysteriumnetwork/node/core/storage/encrypted"
+	"github.com/mysteriumnetwork/node/core/storage/memory"
+	"github.com/mysteriumnetwork/node/core/storage/retention"
+	"github.com/mysteriumnetwork/node/core/storage/schema"
+	"github.com/mysteriumnetwork/node/core/storage/sqlite"
+	"github.com/mysteriumnetwork/node/core/updater"
+	"github.com/mysteriumnetwork/node/diagnostics"
 	"github.com/mysteriumnetwork/node/dns"
 	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/eventbus/export"
+	"github.com/mysteriumnetwork/node/eventbus/webhook"
 	"github.com/mysteriumnetwork/node/feedback"
 	"github.com/mysteriumnetwork/node/firewall"
+	"github.com/mysteriumnetwork/node/grpcapi"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
+	remote_signer "github.com/mysteriumnetwork/node/identity/remote"
 	identity_selector "github.com/mysteriumnetwork/node/identity/selector"
 	"github.com/mysteriumnetwork/node/logconfig"
 	"github.com/mysteriumnetwork/node/market/mysterium"
 	"github.com/mysteriumnetwork/node/metadata"
+	"github.com/mysteriumnetwork/node/metrics"
 	"github.com/mysteriumnetwork/node/mmn"
+	"github.com/mysteriumnetwork/node/monitoring/capacity"
+	"github.com/mysteriumnetwork/node/monitoring/resources"
+	"github.com/mysteriumnetwork/node/monitoring/selfcheck"
 	"github.com/mysteriumnetwork/node/nat"
 	natprobe "github.com/mysteriumnetwork/node/nat/behavior"
+	"github.com/mysteriumnetwork/node/nat/cgnat"
 	"github.com/mysteriumnetwork/node/nat/event"
 	"github.com/mysteriumnetwork/node/nat/mapping"
 	"github.com/mysteriumnetwork/node/nat/upnp"
 	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/p2p/relay"
 	"github.com/mysteriumnetwork/node/pilvytis"
 	"github.com/mysteriumnetwork/node/requests"
 	"github.com/mysteriumnetwork/node/requests/resolver"
@@ -78,11 +115,17 @@ import (
 	service_noop "github.com/mysteriumnetwork/node/services/noop"
 	service_openvpn "github.com/mysteriumnetwork/node/services/openvpn"
 	"github.com/mysteriumnetwork/node/services/wireguard/endpoint"
+	"github.com/mysteriumnetwork/node/session/accounting"
 	"github.com/mysteriumnetwork/node/session/connectivity"
+	"github.com/mysteriumnetwork/node/session/notice"
 	"github.com/mysteriumnetwork/node/session/pingpong"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
 	"github.com/mysteriumnetwork/node/sleep"
 	"github.com/mysteriumnetwork/node/tequilapi"
+	"github.com/mysteriumnetwork/node/tequilapi/remote"
+	"github.com/mysteriumnetwork/node/trace"
 	"github.com/mysteriumnetwork/node/ui/versionmanager"
+	"github.com/mysteriumnetwork/node/utils"
 	"github.com/mysteriumnetwork/node/utils/netutil"
 	paymentClient "github.com/mysteriumnetwork/payments/client"
 	psort "github.com/mysteriumnetwork/payments/client/sort"
@@ -116,63 +159,105 @@ type Dependencies struct {
 
 	BrokerConnector  *nats.BrokerConnector
 	BrokerConnection nats.Connection
+	BrokerTransport  communication.Transport
+	PeerTransport    communication.Transport
+	EventExportConn  nats.Connection
+
+	Webhooks          *webhook.Storage
+	WebhookDispatcher *webhook.Dispatcher
 
 	NATService       nat.NATService
-	NATProber        natprobe.NATProber
-	Storage          *boltdb.Bolt
+	NATProber        *natprobe.CachedNATProber
+	Storage          storage.Store
+	StoragePruner    *retention.Pruner
 	Keystore         *identity.Keystore
 	IdentityManager  identity.Manager
 	SignerFactory    identity.SignerFactory
 	IdentityRegistry registry.IdentityRegistry
 	IdentitySelector identity_selector.Handler
 	IdentityMover    *identity.Mover
+	IdentityRotator  *identity.Rotator
+	HardwareWallets  *identity.HardwareWallets
+	RemoteSigner     identity.SignerFactory
+	remoteSigner     *remote_signer.Client
 
 	DiscoveryFactory    service.DiscoveryFactory
 	ProposalRepository  *discovery.PricedServiceProposalRepository
 	FilterPresetStorage *proposal.FilterPresetStorage
 	DiscoveryWorker     discovery.Worker
+	LatencyMeasurer     *discovery.LatencyMeasurer
+	ProposalsFeed       *feed.Exporter
+	ProposalsCache      *discovery.CachedRepository
 
-	QualityClient *quality.MysteriumMORQA
+	QualityClient    *quality.MysteriumMORQA
+	QualityScores    *quality.Scores
+	QualityHistory   *quality.History
+	QualityReporter  *reporter.Reporter
+	QualityRefresher *quality.Refresher
 
-	IPResolver       ip.Resolver
-	LocationResolver *location.Cache
+	IPResolver        ip.Resolver
+	IPWatcher         *ip.Watcher
+	LocationResolver  *location.Cache
+	LocationDBUpdater *location.DBUpdater
 
 	dnsProxy *dns.Proxy
 
-	PolicyOracle *policy.Oracle
+	PolicyOracle  *policy.Oracle
+	ConsumerLists *policy.ConsumerLists
+	AbuseBans     *abuse.BanList
+	AbuseMonitor  *abuse.ConntrackMonitor
 
 	SessionStorage                   *consumer_session.Storage
 	SessionConnectivityStatusStorage connectivity.StatusStorage
+	NoticeStorage                    *notice.Storage
 
-	EventBus eventbus.EventBus
+	EventBus  eventbus.EventBus
+	PowerMode *power.Mode
 
 	MultiConnectionManager connection.MultiManager
 	ConnectionRegistry     *connection.Registry
+	ConnectionProfiles     *profile.Storage
+	StateSyncer            *statesync.Syncer
+	SpeedTester            *speedtest.Tester
 
 	ServicesManager *service.Manager
 	ServiceRegistry *service.Registry
 	ServiceSessions *service.SessionPool
+	NoticeSender    *service.NoticeSender
 	ServiceFirewall firewall.IncomingTrafficFirewall
 
+	SessionAccounting *accounting.Reconciler
+	TrafficMeter      *accounting.TrafficMeter
+	CapacityMonitor   *capacity.Monitor
+	ResourceMonitor   *resources.Monitor
+	TraceExporter     *trace.OTLPExporter
+
 	WireguardClientFactory *endpoint.WgClientFactory
 
-	PortPool   *port.Pool
-	PortMapper mapping.PortMapper
+	PortPool      *port.Pool
+	PortMapper    mapping.PortMapper
+	CGNATDetector *cgnat.Detector
 
 	StateKeeper *state.Keeper
 
 	P2PDialer   p2p.Dialer
 	P2PListener p2p.Listener
+	RelayServer *relay.Server
 
 	Authenticator    *auth.Authenticator
 	JWTAuthenticator *auth.JWTAuthenticator
+	APITokens        *auth.TokenStore
+	AuditLog         *auth.AuditLog
 	UIServer         UIServer
 	Transactor       *registry.Transactor
 	Affiliator       *registry.Affiliator
 	BCHelper         *paymentClient.MultichainBlockchainClient
 
-	LogCollector *logconfig.Collector
-	Reporter     *feedback.Reporter
+	LogCollector     *logconfig.Collector
+	Reporter         *feedback.Reporter
+	ConnectionErrors *diagnostics.ErrorRecorder
+	Diagnostics      *diagnostics.Collector
+	HealthChecker    *diagnostics.HealthChecker
 
 	BeneficiarySaver    *beneficiary.Saver
 	BeneficiaryProvider *beneficiary.Provider
@@ -186,9 +271,19 @@ type Dependencies struct {
 	HermesURLGetter          *pingpong.HermesURLGetter
 	HermesCaller             *pingpong.HermesCaller
 	HermesPromiseHandler     *pingpong.HermesPromiseHandler
+	SessionKeyDelegations    *identity.Delegations
 	SettlementHistoryStorage *pingpong.SettlementHistoryStorage
 	AddressProvider          *paymentClient.MultiChainAddressProvider
 	HermesStatusChecker      *pingpong.HermesStatusChecker
+	HermesTermsMonitor       *pingpong.HermesTermsMonitor
+	SLOMonitor               *slo.Monitor
+	SelfCheckAgent           *selfcheck.Agent
+	Updater                  *updater.Updater
+	Scheduler                *schedule.Scheduler
+	ConfigReloader           *declarative.Reloader
+	RemoteConfig             *source.Poller
+	DNSBlocklist             *dns.Blocklist
+	RulesEngine              *rules.Engine
 	HermesMigrator           *migration.HermesMigrator
 
 	MMN *mmn.MMN
@@ -199,6 +294,8 @@ type Dependencies struct {
 
 	ObserverAPI *observer.API
 
+	GRPCServer *grpcapi.Server
+
 	ResidentCountry *identity.ResidentCountry
 
 	PayoutAddressStorage *payout.AddressStorage
@@ -227,6 +324,12 @@ func (di *Dependencies) Bootstrap(nodeOptions node.Options) error {
 		return err
 	}
 
+	if !nodeOptions.Embedded {
+		if err := di.bootstrapUpdater(nodeOptions.Directories.Data); err != nil {
+			return err
+		}
+	}
+
 	if err := di.bootstrapFirewall(nodeOptions.Firewall); err != nil {
 		return err
 	}
@@ -241,6 +344,10 @@ func (di *Dependencies) Bootstrap(nodeOptions node.Options) error {
 		return err
 	}
 
+	if err := di.bootstrapTracing(); err != nil {
+		return err
+	}
+
 	if err := di.bootstrapLocationComponents(nodeOptions); err != nil {
 		return err
 	}
@@ -251,7 +358,10 @@ func (di *Dependencies) Bootstrap(nodeOptions node.Options) error {
 		return err
 	}
 
-	if err := di.bootstrapDiscoveryComponents(nodeOptions.Discovery); err != nil {
+	if err := di.bootstrapDiscoveryComponents(nodeOptions.Discovery, nodeOptions.Directories.Data); err != nil {
+		return err
+	}
+	if err := di.bootstrapProposalsFeed(nodeOptions.ProposalsFeed); err != nil {
 		return err
 	}
 
@@ -276,13 +386,20 @@ func (di *Dependencies) Bootstrap(nodeOptions node.Options) error {
 	di.PortPool = port.NewFixedRangePool(portRange)
 
 	di.bootstrapP2P()
+	if err := di.bootstrapRelay(); err != nil {
+		return err
+	}
 	di.SessionConnectivityStatusStorage = connectivity.NewStatusStorage()
+	di.NoticeStorage = notice.NewStorage()
+	if err := di.NoticeStorage.Subscribe(di.EventBus); err != nil {
+		return err
+	}
 
 	if err := di.bootstrapServices(nodeOptions); err != nil {
 		return err
 	}
 
-	if err := di.bootstrapQualityComponents(nodeOptions.Quality); err != nil {
+	if err := di.bootstrapQualityComponents(nodeOptions.Quality, nodeOptions.Directories.Data); err != nil {
 		return err
 	}
 
@@ -299,9 +416,21 @@ func (di *Dependencies) Bootstrap(nodeOptions node.Options) error {
 	}
 
 	config.Current.EnableEventPublishing(di.EventBus)
+	if di.RemoteConfig != nil {
+		go di.RemoteConfig.Start()
+	}
 
 	di.handleNATStatusForPublicIP()
 
+	if di.Updater != nil {
+		di.Updater.ConfirmHealthy(func(ctx context.Context) error {
+			if report := di.HealthChecker.Live(ctx); !report.Healthy {
+				return errors.Errorf("liveness checks failed: %+v", report.Checks)
+			}
+			return nil
+		})
+	}
+
 	log.Info().Msg("Mysterium node started!")
 	return nil
 }
@@ -350,8 +479,44 @@ func (di *Dependencies) bootstrapP2P() {
 		return identity.NewVerifierIdentity(id)
 	}
 
-	di.P2PListener = p2p.NewListener(di.BrokerConnection, di.SignerFactory, identity.NewVerifierSigned(), di.IPResolver, di.EventBus)
-	di.P2PDialer = p2p.NewDialer(di.BrokerConnector, di.SignerFactory, verifierFactory, di.IPResolver, di.PortPool, di.EventBus)
+	di.PortMapper = mapping.NewPortMapper(mapping.DefaultConfig(), di.EventBus)
+
+	var hairpin cgnat.HairpinChecker
+	if stunServers := config.GetStringSlice(config.FlagSTUNservers); len(stunServers) > 0 {
+		hairpin = cgnat.NewSTUNHairpinChecker(stunServers, 5*time.Second)
+	}
+	di.CGNATDetector = cgnat.NewDetector(di.IPResolver, di.PortMapper, hairpin, config.GetStringSlice(config.FlagP2PRelays))
+	if config.GetBool(config.FlagP2PCGNATDetection) {
+		go di.CGNATDetector.Detect(context.Background())
+	}
+
+	di.P2PListener = p2p.NewListener(di.BrokerConnection, di.PeerTransport, di.SignerFactory, identity.NewVerifierSigned(), di.IPResolver, di.EventBus, di.PortMapper, di.CGNATDetector)
+	di.P2PDialer = p2p.NewDialer(di.BrokerConnector, di.PeerTransport, di.SignerFactory, verifierFactory, di.IPResolver, di.PortPool, di.EventBus)
+	di.LatencyMeasurer = discovery.NewLatencyMeasurer(p2p.NewPinger(di.BrokerConnector), discovery.DefaultLatencyConfig())
+}
+
+func (di *Dependencies) bootstrapRelay() error {
+	relayPort := config.GetInt(config.FlagRelayPort)
+	if relayPort == 0 {
+		return nil
+	}
+
+	relayConfig := relay.DefaultServerConfig()
+	relayConfig.MaxRate = config.GetInt(config.FlagRelayMaxRate)
+	server, err := relay.NewServer(relayPort, relayConfig)
+	if err != nil {
+		return errors.Wrap(err, "could not start relay server")
+	}
+	di.RelayServer = server
+
+	go func() {
+		if err := server.Serve(); err != nil {
+			log.Error().Err(err).Msg("Relay server stopped")
+		}
+	}()
+	log.Info().Msgf("Serving as a community relay on %s", server.Addr())
+
+	return nil
 }
 
 func (di *Dependencies) createTequilaListener(nodeOptions node.Options) (net.Listener, error) {
@@ -363,7 +528,50 @@ func (di *Dependencies) createTequilaListener(nodeOptions node.Options) (net.Lis
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("the port %v seems to be taken. Either you're already running a node or it is already used by another application", nodeOptions.TequilapiPort))
 	}
-	return tequilaListener, nil
+	if !nodeOptions.RemoteManagement.Enabled {
+		return tequilaListener, nil
+	}
+
+	remoteListener, err := di.createRemoteManagementListener(nodeOptions)
+	if err != nil {
+		tequilaListener.Close()
+		return nil, err
+	}
+	return tequilapi.NewMultiListener(tequilaListener, remoteListener), nil
+}
+
+func (di *Dependencies) createRemoteManagementListener(nodeOptions node.Options) (net.Listener, error) {
+	options := nodeOptions.RemoteManagement
+
+	allowlist, err := remote.ParseAllowlist(options.AllowedIPs)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid remote management allowlist")
+	}
+
+	tlsConfig, fingerprint, err := remote.TLSConfig(remote.Options{
+		CertFile:     options.CertFile,
+		KeyFile:      options.KeyFile,
+		ACMEDomain:   options.ACMEDomain,
+		ClientCAFile: options.ClientCAFile,
+		DataDir:      nodeOptions.Directories.Data,
+		Hosts:        []string{options.Address},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	address := fmt.Sprintf("%s:%d", options.Address, options.Port)
+	listener, err := remote.NewListener(address, tlsConfig, allowlist)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not listen for remote management on %s", address)
+	}
+
+	if fingerprint != "" {
+		log.Info().Msgf("Remote management API started on: %s, certificate SHA-256 fingerprint: %s", listener.Addr(), fingerprint)
+	} else {
+		log.Info().Msgf("Remote management API started on: %s", listener.Addr())
+	}
+	return listener, nil
 }
 
 func (di *Dependencies) bootstrapStateKeeper(options node.Options) error {
@@ -389,12 +597,13 @@ func (di *Dependencies) bootstrapStateKeeper(options node.Options) error {
 
 func (di *Dependencies) registerOpenvpnConnection(nodeOptions node.Options) {
 	service_openvpn.Bootstrap()
-	connectionFactory := func() (connection.Connection, error) {
+	connectionFactory := func(states connection.StateHandler) (connection.Connection, error) {
 		return service_openvpn.NewClient(
 			// TODO instead of passing binary path here, Openvpn from node options could represent abstract vpn factory itself
 			nodeOptions.Openvpn.BinaryPath(),
 			nodeOptions.Directories.Script,
 			nodeOptions.Directories.Runtime,
+			states,
 			di.SignerFactory,
 			di.IPResolver,
 		)
@@ -426,15 +635,68 @@ func (di *Dependencies) Shutdown() (err error) {
 		}
 	}
 
+	if di.ConfigReloader != nil {
+		di.ConfigReloader.Stop()
+	}
+
+	if di.RemoteConfig != nil {
+		di.RemoteConfig.Stop()
+	}
+
 	if di.ServicesManager != nil {
 		if err := di.ServicesManager.Kill(); err != nil {
 			errs = append(errs, err)
 		}
 	}
 
+	if di.HermesTermsMonitor != nil {
+		di.HermesTermsMonitor.Stop()
+	}
+	if di.SLOMonitor != nil {
+		di.SLOMonitor.Stop()
+	}
+	if di.SelfCheckAgent != nil {
+		di.SelfCheckAgent.Stop()
+	}
+	if di.Updater != nil {
+		di.Updater.Stop()
+	}
+	if di.Scheduler != nil {
+		di.Scheduler.Stop()
+	}
+	if di.DNSBlocklist != nil {
+		di.DNSBlocklist.Stop()
+	}
+	if di.ProposalsFeed != nil {
+		di.ProposalsFeed.Stop()
+	}
+	if di.ProposalsCache != nil {
+		di.ProposalsCache.Stop()
+	}
+	if di.CapacityMonitor != nil {
+		di.CapacityMonitor.Stop()
+	}
+	if di.ResourceMonitor != nil {
+		di.ResourceMonitor.Stop()
+	}
+	if di.TrafficMeter != nil {
+		di.TrafficMeter.Stop()
+	}
+	if di.GRPCServer != nil {
+		di.GRPCServer.Stop()
+	}
+	if di.RelayServer != nil {
+		di.RelayServer.Stop()
+	}
 	if di.PolicyOracle != nil {
 		di.PolicyOracle.Stop()
 	}
+	if di.ConsumerLists != nil {
+		di.ConsumerLists.Stop()
+	}
+	if di.AbuseMonitor != nil {
+		di.AbuseMonitor.Stop()
+	}
 
 	if di.NATService != nil {
 		if err := di.NATService.Disable(); err != nil {
@@ -442,6 +704,12 @@ func (di *Dependencies) Shutdown() (err error) {
 		}
 	}
 
+	if di.remoteSigner != nil {
+		if err := di.remoteSigner.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if di.EtherClientL1 != nil {
 		di.EtherClientL1.Close()
 	}
@@ -462,19 +730,50 @@ func (di *Dependencies) Shutdown() (err error) {
 	if di.PilvytisTracker != nil {
 		di.PilvytisTracker.Stop()
 	}
+	if di.BrokerTransport != nil {
+		di.BrokerTransport.Close()
+	}
 	if di.BrokerConnection != nil {
 		di.BrokerConnection.Close()
 	}
+	if di.EventExportConn != nil {
+		di.EventExportConn.Close()
+	}
+	if di.WebhookDispatcher != nil {
+		di.WebhookDispatcher.Stop()
+	}
+	if di.LocationDBUpdater != nil {
+		di.LocationDBUpdater.Stop()
+	}
+	if di.IPWatcher != nil {
+		di.IPWatcher.Stop()
+	}
 
+	if di.QualityRefresher != nil {
+		di.QualityRefresher.Stop()
+	}
 	if di.QualityClient != nil {
 		di.QualityClient.Stop()
 	}
+	if di.QualityReporter != nil {
+		di.QualityReporter.Stop()
+	}
 
 	if di.ServiceFirewall != nil {
 		di.ServiceFirewall.Teardown()
 	}
 	firewall.Reset()
 
+	if di.StateSyncer != nil {
+		di.StateSyncer.Stop()
+	}
+	if di.TraceExporter != nil {
+		trace.SetExporter(nil)
+		di.TraceExporter.Stop()
+	}
+	if di.StoragePruner != nil {
+		di.StoragePruner.Stop()
+	}
 	if di.Storage != nil {
 		if err := di.Storage.Close(); err != nil {
 			errs = append(errs, err)
@@ -487,18 +786,25 @@ func (di *Dependencies) Shutdown() (err error) {
 }
 
 func (di *Dependencies) bootstrapStorage(path string) error {
-	localStorage, err := boltdb.NewStorage(path)
-	if err != nil {
-		return err
+	passphrase := ""
+	if config.GetBool(config.FlagStorageEncrypt) {
+		if passphrase = config.GetString(config.FlagStoragePassphrase); passphrase == "" {
+			passphrase = config.GetString(config.FlagIdentityPassphrase)
+		}
+		if passphrase == "" {
+			return errors.New("storage encryption requires storage.passphrase or identity.passphrase to be set")
+		}
 	}
 
-	migrator := migrator.NewMigrator(localStorage)
-	err = migrator.RunMigrations(history.Sequence)
+	localStorage, err := openStorage(storage.Backend(config.GetString(config.FlagStorageBackend)), path, passphrase)
 	if err != nil {
 		return err
 	}
 
 	di.Storage = localStorage
+	if err := schema.NewMigrator(di.Storage, path).Migrate(schema.Sequence); err != nil {
+		return err
+	}
 
 	invoiceStorage := pingpong.NewInvoiceStorage(di.Storage)
 	di.ProviderInvoiceStorage = pingpong.NewProviderInvoiceStorage(invoiceStorage)
@@ -506,9 +812,111 @@ func (di *Dependencies) bootstrapStorage(path string) error {
 	di.HermesPromiseStorage = pingpong.NewHermesPromiseStorage(di.Storage)
 	di.SessionStorage = consumer_session.NewSessionStorage(di.Storage)
 	di.SettlementHistoryStorage = pingpong.NewSettlementHistoryStorage(di.Storage)
+
+	di.StoragePruner = retention.NewPruner(
+		di.Storage,
+		6*time.Hour,
+		config.GetDuration(config.FlagStorageCompactionInterval),
+		retention.Policy{
+			Name:   "session history",
+			MaxAge: config.GetDuration(config.FlagStorageRetentionSessions),
+			Prune:  di.SessionStorage.PruneBefore,
+		},
+		retention.Policy{
+			Name:   "settlement history",
+			MaxAge: config.GetDuration(config.FlagStorageRetentionSettlements),
+			Prune:  di.SettlementHistoryStorage.PruneBefore,
+		},
+		retention.Policy{
+			Name:   "self-healing actions",
+			MaxAge: config.GetDuration(config.FlagStorageRetentionSLOActions),
+			Prune:  slo.NewAuditStorage(di.Storage).PruneBefore,
+		},
+		retention.Policy{
+			Name:   "provider traffic",
+			MaxAge: config.GetDuration(config.FlagStorageRetentionTraffic),
+			Prune:  accounting.NewTrafficStorage(di.Storage).PruneBefore,
+		},
+		retention.Policy{
+			Name:   "self-check history",
+			MaxAge: config.GetDuration(config.FlagStorageRetentionSelfChecks),
+			Prune:  selfcheck.NewStorage(di.Storage).PruneBefore,
+		},
+	)
+	di.StoragePruner.Start()
+
 	return di.SessionStorage.Subscribe(di.EventBus)
 }
 
+func (di *Dependencies) bootstrapStateSync() error {
+	syncURL := config.GetString(config.FlagSyncURL)
+	if syncURL == "" {
+		return nil
+	}
+
+	passphrase := config.GetString(config.FlagSyncPassphrase)
+	if passphrase == "" {
+		return errors.New("state sync requires sync.passphrase to be set")
+	}
+
+	remote, err := statesync.NewRemote(di.HTTPClient, syncURL, statesync.RemoteOptions{
+		Username:    config.GetString(config.FlagSyncUsername),
+		Password:    config.GetString(config.FlagSyncPassword),
+		S3AccessKey: config.GetString(config.FlagSyncS3AccessKey),
+		S3SecretKey: config.GetString(config.FlagSyncS3SecretKey),
+		S3Region:    config.GetString(config.FlagSyncS3Region),
+		S3Endpoint:  config.GetString(config.FlagSyncS3Endpoint),
+	})
+	if err != nil {
+		return err
+	}
+
+	di.StateSyncer = statesync.NewSyncer(
+		remote,
+		passphrase,
+		config.GetDuration(config.FlagSyncInterval),
+		di.Storage,
+		di.ConnectionProfiles,
+		di.FilterPresetStorage,
+		di.SessionStorage,
+	)
+	di.StateSyncer.Start()
+	return nil
+}
+
+// openStorage opens the storage backend, encrypting it if the passphrase is set.
+func openStorage(backend storage.Backend, path, passphrase string) (storage.Store, error) {
+	switch backend {
+	case storage.BackendSQLite:
+		if passphrase != "" {
+			return encrypted.NewSQLiteStorage(path, passphrase)
+		}
+		return sqlite.NewStorage(path)
+	case storage.BackendMemory:
+		log.Warn().Msg("Using in-memory storage, nothing will be kept between restarts")
+		return memory.NewStorage(), nil
+	case storage.BackendBolt, "":
+		var localStorage *boltdb.Bolt
+		var err error
+		if passphrase != "" {
+			localStorage, err = encrypted.NewBoltStorage(path, passphrase)
+		} else {
+			localStorage, err = boltdb.NewStorage(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		migrator := migrator.NewMigrator(localStorage)
+		if err := migrator.RunMigrations(history.Sequence); err != nil {
+			return nil, err
+		}
+		return localStorage, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", backend)
+	}
+}
+
 func (di *Dependencies) getHermesURL(nodeOptions node.Options) (string, error) {
 	log.Info().Msgf("Node chain id %v", nodeOptions.ChainID)
 	addr := common.HexToAddress(nodeOptions.Chains.Chain2.HermesID)
@@ -551,6 +959,14 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 	di.PayoutAddressStorage = payout.NewAddressStorage(di.Storage)
 	di.bootstrapBeneficiaryProvider(nodeOptions)
 
+	// Promises signed by session keys are only used with hermes able to verify their delegations.
+	sessionKeysSupported, err := di.HermesCaller.SupportsSessionKeys()
+	if err != nil {
+		log.Debug().Err(err).Msg("Could not check hermes support of session keys")
+	}
+	if sessionKeysSupported {
+		di.SessionKeyDelegations = identity.NewDelegations()
+	}
 	di.HermesPromiseHandler = pingpong.NewHermesPromiseHandler(pingpong.HermesPromiseHandlerDeps{
 		HermesPromiseStorage: di.HermesPromiseStorage,
 		HermesCallerFactory: func(hermesURL string) pingpong.HermesHTTPRequester {
@@ -562,6 +978,7 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 		EventBus:        di.EventBus,
 		Signer:          di.SignerFactory,
 		Chains:          []int64{nodeOptions.Chains.Chain1.ChainID, nodeOptions.Chains.Chain2.ChainID},
+		Delegations:     di.SessionKeyDelegations,
 	})
 
 	if err := di.HermesPromiseHandler.Subscribe(di.EventBus); err != nil {
@@ -573,8 +990,31 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 	}
 
 	di.bootstrapBeneficiarySaver(nodeOptions)
+	if err := di.EventBus.SubscribeAsync(identity.AppTopicIdentityRotated, di.rebindRotatedEarnings); err != nil {
+		return err
+	}
+
+	connectionConfig := connection.DefaultConfig()
+	if sources := config.GetStringSlice(config.FlagDNSBlocklistSources); len(sources) > 0 {
+		di.DNSBlocklist = dns.NewBlocklist(sources, &http.Client{Transport: di.HTTPTransport, Timeout: time.Minute})
+		di.DNSBlocklist.Start(config.GetDuration(config.FlagDNSBlocklistUpdateInterval))
+		connectionConfig.DNSBlocklist = di.DNSBlocklist
+	}
+
+	var sessionKeys *identity.SessionKeys
+	if ttl := nodeOptions.Payments.ConsumerSessionKeyTTL; ttl > 0 {
+		if sessionKeysSupported {
+			sessionKeys = identity.NewSessionKeys(di.SignerFactory, ttl)
+		} else {
+			log.Warn().Msg("Hermes does not accept promises signed by session keys, signing payments with the identity key")
+		}
+	}
 
 	di.ConnectionRegistry = connection.NewRegistry()
+	di.ConnectionProfiles = profile.NewStorage(di.Storage)
+	if err := di.bootstrapStateSync(); err != nil {
+		return err
+	}
 	di.MultiConnectionManager = connection.NewMultiConnectionManager(func() connection.Manager {
 		return connection.NewManager(
 			pingpong.ExchangeFactoryFunc(
@@ -584,12 +1024,13 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 				di.AddressProvider,
 				di.EventBus,
 				nodeOptions.Payments.ConsumerDataLeewayMegabytes,
+				sessionKeys,
 			),
 			di.ConnectionRegistry.CreateConnection,
 			di.EventBus,
 			di.IPResolver,
 			di.LocationResolver,
-			connection.DefaultConfig(),
+			connectionConfig,
 			config.GetDuration(config.FlagStatsReportInterval),
 			connection.NewValidator(
 				di.ConsumerBalanceTracker,
@@ -601,7 +1042,24 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 		)
 	})
 
-	di.NATProber = natprobe.NewNATProber(di.MultiConnectionManager, di.EventBus)
+	locationVerifier := connection.NewLocationVerifier(di.LocationResolver, di.EventBus)
+	if err := locationVerifier.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+
+	speedTestConfig := speedtest.DefaultConfig()
+	speedTestConfig.DownloadURL = config.GetString(config.FlagSpeedTestDownloadURL)
+	speedTestConfig.UploadURL = config.GetString(config.FlagSpeedTestUploadURL)
+	speedTestConfig.Duration = config.GetDuration(config.FlagSpeedTestDuration)
+	di.SpeedTester = speedtest.NewTester(
+		// Stages are limited by their duration instead of the client timeout.
+		requests.NewHTTPClientWithTransport(di.HTTPTransport, 0),
+		di.MultiConnectionManager,
+		di.QualityHistory,
+		speedTestConfig,
+	)
+
+	di.NATProber = natprobe.NewCachedNATProber(natprobe.NewNATProber(di.MultiConnectionManager, di.EventBus), natprobe.DefaultCacheTTL)
 
 	di.LogCollector = logconfig.NewCollector(&logconfig.CurrentLogOptions)
 	reporter, err := feedback.NewReporter(di.LogCollector, di.IdentityManager, di.LocationResolver, nodeOptions.FeedbackURL)
@@ -610,6 +1068,22 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 	}
 	di.Reporter = reporter
 
+	di.ConnectionErrors = diagnostics.NewErrorRecorder(diagnostics.DefaultErrorHistory)
+	if err := di.ConnectionErrors.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+	logFilepath := ""
+	if logconfig.CurrentLogOptions.Filepath != "" {
+		logFilepath = logconfig.CurrentLogOptions.Filepath + ".log"
+	}
+	di.Diagnostics = diagnostics.NewCollector(di.NATProber, di.PortMapper, di.BrokerConnection, nodeOptions.Discovery.Address, di.HTTPClient, di.ConnectionErrors, logFilepath)
+	di.HealthChecker = diagnostics.NewHealthChecker()
+	di.HealthChecker.AddLivenessCheck("keystore", diagnostics.KeystoreCheck(nodeOptions.Directories.Keystore))
+	di.HealthChecker.AddLivenessCheck("storage", diagnostics.StorageCheck(di.Storage))
+	di.HealthChecker.AddReadinessCheck("broker", diagnostics.BrokerCheck(di.BrokerConnection))
+	di.HealthChecker.AddReadinessCheck("discovery", diagnostics.DiscoveryCheck(di.HTTPClient, nodeOptions.Discovery.Address))
+	di.HealthChecker.AddReadinessCheck("blockchain", diagnostics.BlockchainCheck(di.BCHelper, di.AddressProvider, config.GetInt64(config.FlagChainID)))
+
 	if err := di.bootstrapStateKeeper(nodeOptions); err != nil {
 		return err
 	}
@@ -648,10 +1122,17 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 		return fmt.Errorf("error during subscribe: %w", err)
 	}
 
+	if err := di.bootstrapRulesEngine(); err != nil {
+		return err
+	}
+
 	tequilapiHTTPServer, err := di.bootstrapTequilapi(nodeOptions, tequilaListener)
 	if err != nil {
 		return err
 	}
+	if err := di.bootstrapGRPC(); err != nil {
+		return err
+	}
 
 	sleepNotifier := sleep.NewNotifier(di.MultiConnectionManager, di.EventBus)
 	sleepNotifier.Subscribe()
@@ -661,6 +1142,34 @@ func (di *Dependencies) bootstrapNodeComponents(nodeOptions node.Options, tequil
 	return nil
 }
 
+func (di *Dependencies) bootstrapBrokerTransport() {
+	receiverConfig := nats.DefaultReceiverConfig()
+	receiverConfig.Concurrency = config.GetInt(config.FlagBrokerReceiverConcurrency)
+	natsTransport := nats.NewTransportWithConfig(di.BrokerConnection, receiverConfig)
+	if err := metrics.Registry.Register(nats.NewStatsCollector("transport", natsTransport.Stats)); err != nil {
+		log.Warn().Err(err).Msg("Failed to register broker transport metrics")
+	}
+	di.BrokerTransport = natsTransport
+	if config.GetString(config.FlagBrokerTransport) != communication.TransportLibP2P {
+		return
+	}
+
+	gossipTransport, err := gossip.NewTransport(
+		config.GetString(config.FlagBrokerGossipListen),
+		config.GetStringSlice(config.FlagBrokerGossipPeers),
+	)
+	if err == nil {
+		err = gossipTransport.Start()
+	}
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to start libp2p broker transport, using NATS only")
+		return
+	}
+
+	di.PeerTransport = gossipTransport
+	di.BrokerTransport = communication.NewFallbackTransport(gossipTransport, natsTransport)
+}
+
 // function decides on network definition combined from testnet3/localnet flags and possible overrides
 func (di *Dependencies) bootstrapNetworkComponents(options node.Options) (err error) {
 	optionsNetwork := options.OptionsNetwork
@@ -722,10 +1231,22 @@ func (di *Dependencies) bootstrapNetworkComponents(options node.Options) (err er
 		brokerURLs[i] = brokerURL
 	}
 
-	di.BrokerConnector = nats.NewBrokerConnector(dialer.DialContext, resolver)
+	brokerReconnect := nats.DefaultReconnectConfig()
+	brokerReconnect.Wait = config.GetDuration(config.FlagBrokerReconnectWait)
+	brokerReconnect.MaxWait = config.GetDuration(config.FlagBrokerReconnectMaxWait)
+	brokerReconnect.BufferSize = config.GetInt(config.FlagBrokerBufferSize)
+	brokerReconnect.HealthInterval = config.GetDuration(config.FlagBrokerHealthInterval)
+	di.BrokerConnector = nats.NewBrokerConnector(dialer.DialContext, resolver, brokerReconnect, di.EventBus)
 	if di.BrokerConnection, err = di.BrokerConnector.Connect(brokerURLs...); err != nil {
 		return err
 	}
+	di.bootstrapBrokerTransport()
+	if err := di.bootstrapEventExport(); err != nil {
+		return err
+	}
+	if err := di.bootstrapWebhooks(); err != nil {
+		return err
+	}
 
 	log.Info().Msgf("Using L1 Eth endpoints: %v", network.Chain1.EtherClientRPC)
 	log.Info().Msgf("Using L2 Eth endpoints: %v", network.Chain2.EtherClientRPC)
@@ -801,6 +1322,12 @@ func (di *Dependencies) bootstrapNetworkComponents(options node.Options) (err er
 
 	di.HermesCaller = pingpong.NewHermesCaller(di.HTTPClient, hermesURL)
 	di.SignerFactory = func(id identity.Identity) identity.Signer {
+		if di.RemoteSigner != nil {
+			return di.RemoteSigner(id)
+		}
+		if di.HardwareWallets != nil && di.HardwareWallets.Contains(id) {
+			return identity.NewHardwareSigner(di.HardwareWallets, id)
+		}
 		return identity.NewSigner(di.Keystore, id)
 	}
 	di.Transactor = registry.NewTransactor(
@@ -842,8 +1369,125 @@ func (di *Dependencies) bootstrapNetworkComponents(options node.Options) (err er
 	return di.IdentityRegistry.Subscribe(di.EventBus)
 }
 
+func (di *Dependencies) bootstrapGRPC() error {
+	if !config.GetBool(config.FlagGRPCEnabled) {
+		return nil
+	}
+
+	address := net.JoinHostPort(config.GetString(config.FlagGRPCAddress), strconv.Itoa(config.GetInt(config.FlagGRPCPort)))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "could not listen gRPC API on %s", address)
+	}
+
+	management := grpcapi.NewManagement(
+		di.MultiConnectionManager,
+		di.AddressProvider,
+		di.IdentityRegistry,
+		di.StateKeeper,
+		di.ProposalRepository,
+		di.SessionStorage,
+	)
+	if err := management.Subscribe(di.EventBus); err != nil {
+		listener.Close()
+		return err
+	}
+
+	di.GRPCServer = grpcapi.NewServer(listener, di.JWTAuthenticator)
+	management.Register(di.GRPCServer)
+	go func() {
+		if err := di.GRPCServer.Serve(); err != nil {
+			log.Error().Err(err).Msg("gRPC API stopped")
+		}
+	}()
+	return nil
+}
+
+func (di *Dependencies) bootstrapWebhooks() error {
+	di.Webhooks = webhook.NewStorage(di.Storage)
+	di.WebhookDispatcher = webhook.NewDispatcher(di.Webhooks, di.HTTPClient, config.GetBigInt(config.FlagWebhooksBalanceLowThreshold))
+	return di.WebhookDispatcher.Subscribe(di.EventBus)
+}
+
+func (di *Dependencies) bootstrapEventExport() error {
+	address := config.GetString(config.FlagEventsNATSAddress)
+	if address == "" {
+		return nil
+	}
+
+	serverURL, err := nats.ParseServerURL(address)
+	if err != nil {
+		return err
+	}
+	if di.EventExportConn, err = di.BrokerConnector.Connect(serverURL); err != nil {
+		return errors.Wrap(err, "could not connect to event export NATS server")
+	}
+
+	exporter := export.NewExporter(
+		di.EventExportConn,
+		config.GetString(config.FlagEventsNATSSubjectPrefix),
+		config.GetStringSlice(config.FlagEventsNATSGroups),
+	)
+	return exporter.Subscribe(di.EventBus)
+}
+
+func (di *Dependencies) bootstrapRulesEngine() error {
+	executors := map[string]rules.Executor{
+		rules.ActionReconnect: func(params map[string]string, _ rules.Event) error {
+			var id int
+			if connection, ok := params["connection"]; ok {
+				var err error
+				if id, err = strconv.Atoi(connection); err != nil {
+					return fmt.Errorf("invalid connection param %q: %w", connection, err)
+				}
+			}
+			di.MultiConnectionManager.Reconnect(id)
+			return nil
+		},
+		rules.ActionSettle: func(params map[string]string, event rules.Event) error {
+			providerID := identity.FromAddress(params["identity"])
+			if earnings, ok := event.Payload.(pingpongEvent.AppEventEarningsChanged); ok && params["identity"] == "" {
+				providerID = earnings.Identity
+			}
+			if providerID.Address == "" {
+				return errors.New("identity to settle is not known")
+			}
+
+			chainID := config.GetInt64(config.FlagChainID)
+			hermesID, err := di.AddressProvider.GetActiveHermes(chainID)
+			if err != nil {
+				return err
+			}
+			return di.HermesPromiseSettler.ForceSettle(chainID, providerID, hermesID)
+		},
+		rules.ActionPauseService: func(params map[string]string, _ rules.Event) error {
+			if di.ServicesManager == nil {
+				return errors.New("services are not available in consumer mode")
+			}
+
+			errs := utils.ErrorCollection{}
+			for _, instance := range di.ServicesManager.List(false) {
+				if serviceType := params["service_type"]; serviceType != "" && instance.Type != serviceType {
+					continue
+				}
+				errs.Add(di.ServicesManager.Stop(instance.ID))
+			}
+			return errs.Errorf("ErrorCollection(%s)", ", ")
+		},
+		rules.ActionWebhook: rules.NewWebhookExecutor(di.HTTPClient),
+	}
+
+	di.RulesEngine = rules.NewEngine(rules.NewStorage(di.Storage), executors)
+	if err := di.RulesEngine.Load(); err != nil {
+		return errors.Wrap(err, "could not load rules")
+	}
+	return di.RulesEngine.Subscribe(di.EventBus)
+}
+
 func (di *Dependencies) bootstrapEventBus() {
 	di.EventBus = eventbus.New()
+	di.PowerMode = &power.Mode{}
+	_ = di.PowerMode.Subscribe(di.EventBus)
 }
 
 func (di *Dependencies) bootstrapIdentityComponents(options node.Options) error {
@@ -857,24 +1501,130 @@ func (di *Dependencies) bootstrapIdentityComponents(options node.Options) error
 	}
 
 	di.Keystore = identity.NewKeystoreFilesystem(options.Directories.Keystore, ks)
+	if options.Keystore.HardwareWallet != "" {
+		hardwareWallets, err := identity.NewUSBHardwareWallets(options.Keystore.HardwareWallet, options.Keystore.HardwareWalletPaths)
+		if err != nil {
+			return errors.Wrap(err, "could not initialize hardware wallet")
+		}
+		log.Info().Msgf("Using %s hardware wallet for identities it holds", options.Keystore.HardwareWallet)
+		di.HardwareWallets = hardwareWallets
+	}
+	if signer := options.Keystore.RemoteSigner; signer.Address != "" {
+		client, err := remote_signer.NewClient(remote_signer.Options{
+			Address:    signer.Address,
+			CACert:     signer.CACert,
+			ClientCert: signer.ClientCert,
+			ClientKey:  signer.ClientKey,
+			Timeout:    signer.Timeout,
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not initialize remote signer")
+		}
+		log.Info().Msgf("Using remote signer at %s", signer.Address)
+		di.remoteSigner = client
+		di.RemoteSigner = remote_signer.NewSignerFactory(client)
+	}
 	if di.ResidentCountry == nil {
 		return errMissingDependency("di.residentCountry")
 	}
 	di.IdentityManager = identity.NewIdentityManager(di.Keystore, di.EventBus, di.ResidentCountry)
 
+	var unlock identity.UnlockProvider
+	if options.Keystore.Keychain {
+		unlock = identity.NewKeychain(options.Directories.Keystore)
+	}
 	di.IdentitySelector = identity_selector.NewHandler(
 		di.IdentityManager,
 		identity.NewIdentityCache(options.Directories.Keystore, "remember.json"),
 		di.SignerFactory,
+		unlock,
 	)
 	di.IdentityMover = identity.NewMover(
 		di.Keystore,
 		di.EventBus,
 		di.SignerFactory)
+	di.IdentityRotator = identity.NewRotator(
+		di.IdentityManager,
+		di.SignerFactory,
+		di.EventBus,
+		options.Directories.Keystore,
+	)
 	return nil
 }
 
-func (di *Dependencies) bootstrapQualityComponents(options node.OptionsQuality) (err error) {
+// bootstrapUpdater restores the previous node binary if the updated one failed to start too many times.
+func (di *Dependencies) bootstrapUpdater(dataDir string) error {
+	channel, err := updater.ParseChannel(config.GetString(config.FlagUpdaterChannel))
+	if err != nil {
+		return err
+	}
+
+	var publicKey ed25519.PublicKey
+	if key := config.GetString(config.FlagUpdaterPublicKey); key != "" {
+		publicKey, err = hex.DecodeString(key)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return errors.Errorf("invalid updater public key %q, expected hex encoded Ed25519 key", key)
+		}
+	}
+
+	di.Updater = updater.New(
+		&http.Client{Timeout: 5 * time.Minute},
+		metadata.VersionAsString(),
+		updater.Config{
+			Channel:          channel,
+			PublicKey:        publicKey,
+			DataDir:          dataDir,
+			HealthCheckDelay: config.GetDuration(config.FlagUpdaterHealthCheckDelay),
+			MaxStartAttempts: config.GetInt(config.FlagUpdaterMaxStartAttempts),
+		},
+		utils.RestartKiller(di.Shutdown, updater.RestartExitCode),
+	)
+	return di.Updater.Recover()
+}
+
+// LoadRemoteConfig fetches remote configuration, if configured, so that it is applied to the node options.
+// It is checked for changes once the node is bootstrapped.
+func (di *Dependencies) LoadRemoteConfig() error {
+	remoteSource, err := source.NewFromConfig(config.Current)
+	if err != nil || remoteSource == nil {
+		return err
+	}
+
+	di.RemoteConfig = source.NewPoller(remoteSource, config.GetDuration(config.FlagConfigRemoteCheckInterval), config.Current)
+	if err := di.RemoteConfig.Check(); err != nil {
+		log.Error().Err(err).Msg("Could not load remote configuration, starting with local configuration")
+	}
+	return nil
+}
+
+func (di *Dependencies) bootstrapTracing() error {
+	endpoint := config.GetString(config.FlagTracingOTLPEndpoint)
+	if endpoint == "" {
+		return nil
+	}
+	if err := di.AllowURLAccess(endpoint); err != nil {
+		return err
+	}
+
+	headers := make(map[string]string)
+	for _, header := range config.GetStringSlice(config.FlagTracingOTLPHeaders) {
+		name, value, ok := strings.Cut(header, "=")
+		if !ok {
+			return errors.Errorf("invalid OTLP header %q, expected Name=Value", header)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	exporter, err := trace.NewOTLPExporter(endpoint, headers, "myst", metadata.VersionAsString())
+	if err != nil {
+		return err
+	}
+	di.TraceExporter = exporter
+	trace.SetExporter(di.TraceExporter)
+	return nil
+}
+
+func (di *Dependencies) bootstrapQualityComponents(options node.OptionsQuality, dataDir string) (err error) {
 	if err := di.AllowURLAccess(options.Address); err != nil {
 		return err
 	}
@@ -886,6 +1636,16 @@ func (di *Dependencies) bootstrapQualityComponents(options node.OptionsQuality)
 	)
 	go di.QualityClient.Start()
 
+	if options.RefreshInterval > 0 {
+		config := quality.DefaultRefreshConfig()
+		config.Interval = options.RefreshInterval
+		di.QualityRefresher = quality.NewRefresher(di.QualityClient, di.QualityScores, di.ProposalRepository, di.EventBus, config)
+		if err := di.QualityRefresher.Subscribe(di.EventBus); err != nil {
+			return err
+		}
+		di.QualityRefresher.Start()
+	}
+
 	var transport quality.Transport
 	switch options.Type {
 	case node.QualityTypeElastic:
@@ -915,36 +1675,105 @@ func (di *Dependencies) bootstrapQualityComponents(options node.OptionsQuality)
 		return err
 	}
 
+	if options.ReportURL == "" {
+		return nil
+	}
+	if err := di.AllowURLAccess(options.ReportURL); err != nil {
+		return err
+	}
+	di.QualityReporter, err = reporter.NewReporter(
+		reporter.NewHTTPCollector(di.HTTPClient, options.ReportURL),
+		dataDir,
+		metadata.VersionAsString(),
+		options.ReportInterval,
+	)
+	if err != nil {
+		return err
+	}
+	if err := di.QualityReporter.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+	di.QualityReporter.Start()
+
 	return nil
 }
 
+func (di *Dependencies) bootstrapOfflineLocation(options node.Options) (*location.DBResolver, error) {
+	dbPath := filepath.Join(options.Directories.Data, "location.mmdb")
+	resolver, err := location.NewOfflineResolver(dbPath, di.IPResolver)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Location.DBUpdateURL != "" {
+		if err := di.AllowURLAccess(options.Location.DBUpdateURL); err != nil {
+			return nil, errors.Wrap(err, "failed to add firewall exception")
+		}
+		di.LocationDBUpdater = location.NewDBUpdater(di.HTTPClient, options.Location.DBUpdateURL, dbPath, options.Location.DBUpdateInterval, resolver)
+		di.LocationDBUpdater.Start()
+	}
+	return resolver, nil
+}
+
 func (di *Dependencies) bootstrapLocationComponents(options node.Options) (err error) {
 	if err = di.AllowURLAccess(options.Location.IPDetectorURL); err != nil {
 		return errors.Wrap(err, "failed to add firewall exception")
 	}
 
-	ipResolver := ip.NewResolver(di.HTTPClient, options.BindAddress, options.Location.IPDetectorURL, ip.IPFallbackAddresses)
-	di.IPResolver = ip.NewCachedResolver(ipResolver, 5*time.Minute)
+	ipSources := ip.DefaultSources(
+		di.HTTPClient,
+		options.BindAddress,
+		options.Location.IPDetectorURL,
+		ip.IPFallbackAddresses,
+		config.GetStringSlice(config.FlagSTUNservers),
+	)
+	ipResolver := ip.NewChainResolver(
+		di.HTTPClient,
+		options.BindAddress,
+		options.Location.IPDetectorURL,
+		ip.IPFallbackAddresses,
+		ipSources,
+		config.GetInt(config.FlagIPDetectorQueries),
+	)
+	cachedIPResolver := ip.NewCachedResolver(ipResolver, 5*time.Minute, di.EventBus)
+	di.IPResolver = cachedIPResolver
+	if interval := config.GetDuration(config.FlagIPDetectorWatchInterval); interval > 0 {
+		di.IPWatcher = ip.NewWatcher(cachedIPResolver, interval)
+		di.IPWatcher.Start()
+	}
 
 	var resolver location.Resolver
 	switch options.Location.Type {
 	case node.LocationTypeManual:
 		resolver = location.NewStaticResolver(options.Location.Country, options.Location.City, options.Location.IPType, di.IPResolver)
 	case node.LocationTypeBuiltin:
-		resolver, err = location.NewBuiltInResolver(di.IPResolver)
+		resolver, err = di.bootstrapOfflineLocation(options)
 	case node.LocationTypeMMDB:
 		resolver, err = location.NewExternalDBResolver(filepath.Join(options.Directories.Script, options.Location.Address), di.IPResolver)
 	case node.LocationTypeOracle:
 		if err := di.AllowURLAccess(options.Location.Address); err != nil {
 			return err
 		}
-		resolver, err = location.NewOracleResolver(di.HTTPClient, options.Location.Address), nil
+		offline, err := di.bootstrapOfflineLocation(options)
+		if err != nil {
+			return err
+		}
+		resolver = location.NewFallbackResolver([]location.Resolver{
+			location.NewOracleResolver(di.HTTPClient, options.Location.Address),
+			offline,
+		})
 	default:
 		err = errors.Errorf("unknown location provider: %s", options.Location.Type)
 	}
 	if err != nil {
 		return err
 	}
+	prefixCache := location.NewPrefixCache(resolver, di.IPResolver, location.DefaultPrefixCacheConfig())
+	if err := metrics.Registry.Register(location.NewPrefixCacheCollector(prefixCache.Stats)); err != nil {
+		log.Warn().Err(err).Msg("Failed to register location cache metrics")
+	}
+	resolver = prefixCache
+	resolver = location.NewClassifyingResolver(resolver, location.NewIPClassifier())
 
 	di.LocationResolver = location.NewCache(resolver, di.EventBus, time.Minute*5)
 
@@ -960,6 +1789,11 @@ func (di *Dependencies) bootstrapLocationComponents(options node.Options) (err e
 		return err
 	}
 
+	err = di.EventBus.SubscribeAsync(ip.AppTopicPublicIPChanged, di.LocationResolver.HandlePublicIPChanged)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -970,6 +1804,8 @@ func (di *Dependencies) bootstrapAuthenticator() error {
 	}
 	di.Authenticator = auth.NewAuthenticator()
 	di.JWTAuthenticator = auth.NewJWTAuthenticator(key)
+	di.APITokens = auth.NewTokenStore(config.GetString(config.FlagDataDir))
+	di.AuditLog = auth.NewAuditLog(di.Storage, config.GetDuration(config.FlagTequilapiAuditRetention))
 
 	return nil
 }
@@ -1027,6 +1863,22 @@ func (di *Dependencies) bootstrapBeneficiarySaver(options node.Options) {
 	)
 }
 
+// rebindRotatedEarnings settles earnings of the rotated identity to the new one and keeps it as the beneficiary.
+func (di *Dependencies) rebindRotatedEarnings(e identity.AppEventIdentityRotated) {
+	hermeses, err := di.AddressProvider.GetKnownHermeses(e.ChainID)
+	if err != nil {
+		log.Err(err).Msg("Could not get hermeses to settle earnings of rotated identity")
+		return
+	}
+
+	from, to := identity.FromAddress(e.Rotation.From), common.HexToAddress(e.Rotation.To)
+	if err := di.BeneficiarySaver.SettleAndSaveBeneficiary(from, hermeses, to); err != nil {
+		log.Warn().Err(err).Msgf("Could not re-bind earnings of rotated identity %s to %s", from.Address, e.Rotation.To)
+		return
+	}
+	log.Info().Msgf("Earnings of rotated identity %s are re-bound to %s", from.Address, e.Rotation.To)
+}
+
 func (di *Dependencies) bootstrapHermesMigrator() *migration.HermesMigrator {
 	return migration.NewHermesMigrator(
 		di.Transactor,
@@ -18,19 +18,35 @@
 package cmd
 
 import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"runtime"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	mdns "github.com/miekg/dns"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/config/declarative"
+	"github.com/mysteriumnetwork/node/core/abuse"
 	"github.com/mysteriumnetwork/node/core/connection"
 	"github.com/mysteriumnetwork/node/core/node"
 	"github.com/mysteriumnetwork/node/core/policy"
+	"github.com/mysteriumnetwork/node/core/schedule"
 	"github.com/mysteriumnetwork/node/core/service"
 	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/core/slo"
 	"github.com/mysteriumnetwork/node/dns"
+	"github.com/mysteriumnetwork/node/firewall/egress"
+	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/mmn"
+	"github.com/mysteriumnetwork/node/monitoring/capacity"
+	monitoring_resources "github.com/mysteriumnetwork/node/monitoring/resources"
+	"github.com/mysteriumnetwork/node/monitoring/selfcheck"
 	"github.com/mysteriumnetwork/node/nat"
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/services/datatransfer"
@@ -43,7 +59,9 @@ import (
 	"github.com/mysteriumnetwork/node/services/wireguard/endpoint"
 	"github.com/mysteriumnetwork/node/services/wireguard/resources"
 	wireguard_service "github.com/mysteriumnetwork/node/services/wireguard/service"
+	"github.com/mysteriumnetwork/node/session/accounting"
 	"github.com/mysteriumnetwork/node/session/pingpong"
+	"github.com/mysteriumnetwork/node/utils"
 )
 
 // bootstrapServices loads all the components required for running services
@@ -62,10 +80,19 @@ func (di *Dependencies) bootstrapServices(nodeOptions node.Options) error {
 	di.bootstrapServiceNoop(nodeOptions)
 	resourcesAllocator := resources.NewAllocator(di.PortPool, wireguard_service.GetOptions().Subnet)
 
-	dnsHandler, err := dns.ResolveViaSystem()
-	if err != nil {
-		log.Error().Err(err).Msg("Provider DNS are not available")
-		return err
+	var dnsHandler mdns.Handler
+	if servers := config.GetStringSlice(config.FlagDNSUpstream); len(servers) > 0 {
+		dnsHandler = dns.ResolveViaServers(servers)
+	} else {
+		dnsHandler, err = dns.ResolveViaSystem()
+		if err != nil {
+			log.Error().Err(err).Msg("Provider DNS are not available")
+			return err
+		}
+	}
+
+	if domains := egress.PolicyFromConfig().Domains; len(domains) > 0 {
+		dnsHandler = dns.BlockDomains(dnsHandler, dns.NewDomainBlocklist(domains))
 	}
 
 	di.dnsProxy = dns.NewProxy("", config.GetInt(config.FlagDNSListenPort), dnsHandler)
@@ -245,9 +272,18 @@ func (di *Dependencies) bootstrapServiceComponents(nodeOptions node.Options) err
 	if err := di.NATService.Enable(); err != nil {
 		log.Warn().Err(err).Msg("Failed to enable NAT forwarding")
 	}
+	accounter, _ := di.NATService.(nat.SessionAccounter)
+	di.SessionAccounting = accounting.NewReconciler(accounter)
+	if err := di.SessionAccounting.Subscribe(di.EventBus); err != nil {
+		return errors.Wrap(err, "could not subscribe session accounting to relevant events")
+	}
+	if err := di.bootstrapTrafficMeter(); err != nil {
+		return err
+	}
 	di.ServiceRegistry = service.NewRegistry()
 
 	di.ServiceSessions = service.NewSessionPool(di.EventBus)
+	di.NoticeSender = service.NewNoticeSender(di.ServiceSessions)
 
 	di.PolicyOracle = policy.NewOracle(
 		di.HTTPClient,
@@ -256,8 +292,53 @@ func (di *Dependencies) bootstrapServiceComponents(nodeOptions node.Options) err
 	)
 	go di.PolicyOracle.Start()
 
+	consumerLists, err := policy.NewConsumerLists(
+		nodeOptions.Directories.Data,
+		config.GetStringSlice(config.FlagAccessPolicyConsumerAllowlist),
+		config.GetStringSlice(config.FlagAccessPolicyConsumerBlocklist),
+		&http.Client{Transport: di.HTTPTransport, Timeout: time.Minute},
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not load consumer lists")
+	}
+	di.ConsumerLists = consumerLists
+	di.ConsumerLists.Start(config.GetDuration(config.FlagAccessPolicyConsumerListsUpdateInterval))
+
+	if err := di.bootstrapAbuseDetection(nodeOptions.Directories.Data); err != nil {
+		return err
+	}
+
 	di.HermesStatusChecker = pingpong.NewHermesStatusChecker(di.BCHelper, di.ObserverAPI, nodeOptions.Payments.HermesStatusRecheckInterval)
+	di.HermesTermsMonitor = pingpong.NewHermesTermsMonitor(
+		di.BCHelper,
+		di.EventBus,
+		pingpong.HermesTermsBounds{
+			MaxFee:         uint16(nodeOptions.Payments.MaxAllowedPaymentPercentile),
+			MinChannelSize: nodeOptions.Payments.HermesMinChannelSize,
+		},
+		nodeOptions.Payments.HermesStatusRecheckInterval,
+	)
+	go di.HermesTermsMonitor.Start()
 
+	sessionConfig := service.DefaultConfig()
+	sessionIDGenerator, err := service.NewSessionIDGenerator(
+		config.GetString(config.FlagSessionIDGenerator),
+		config.GetString(config.FlagSessionIDSecret),
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not configure session ID generator")
+	}
+	sessionConfig.IDGenerator = sessionIDGenerator
+	sessionConfig.Delegations = di.SessionKeyDelegations
+	sessionConfig.ConsumerLists = di.ConsumerLists
+	sessionConfig.TrafficMeter = di.TrafficMeter
+	sessionConfig.MaxSessions = func() int {
+		return config.GetInt(config.FlagServiceMaxSessions)
+	}
+	sessionConfig.Abuse = abuse.Singleton()
+	sessionConfig.Bans = di.AbuseBans
+
+	consumerPaymentHistory := pingpong.NewConsumerPaymentHistory(nodeOptions.Payments.PromptPaymentLatency, nodeOptions.Payments.TrustedConsumerPayments)
 	newP2PSessionHandler := func(serviceInstance *service.Instance, channel p2p.Channel) *service.SessionManager {
 		paymentEngineFactory := pingpong.InvoiceFactoryCreator(
 			channel, nodeOptions.Payments.ProviderInvoiceFrequency, nodeOptions.Payments.ProviderLimitInvoiceFrequency,
@@ -266,11 +347,15 @@ func (di *Dependencies) bootstrapServiceComponents(nodeOptions node.Options) err
 			uint16(nodeOptions.Payments.MaxAllowedPaymentPercentile),
 			nodeOptions.Payments.MaxUnpaidInvoiceValue,
 			nodeOptions.Payments.LimitUnpaidInvoiceValue,
+			consumerPaymentHistory,
 			di.HermesStatusChecker,
+			di.HermesTermsMonitor,
 			di.EventBus,
 			di.HermesPromiseHandler,
 			di.AddressProvider,
 			di.ObserverAPI,
+			di.SessionKeyDelegations,
+			di.PowerMode,
 		)
 		return service.NewSessionManager(
 			serviceInstance,
@@ -278,11 +363,23 @@ func (di *Dependencies) bootstrapServiceComponents(nodeOptions node.Options) err
 			paymentEngineFactory,
 			di.EventBus,
 			channel,
-			service.DefaultConfig(),
+			sessionConfig,
 			di.PricingHelper,
 		)
 	}
 
+	di.CapacityMonitor, err = capacity.NewMonitor(
+		&http.Client{Transport: di.HTTPTransport, Timeout: 5 * time.Minute},
+		nodeOptions.Capacity.DownloadURLs,
+		nodeOptions.Capacity.UploadURLs,
+		nodeOptions.Capacity.SpeedTestInterval,
+		nodeOptions.Directories.Data,
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not create capacity monitor")
+	}
+	di.CapacityMonitor.Start()
+
 	di.ServicesManager = service.NewManager(
 		di.ServiceRegistry,
 		di.DiscoveryFactory,
@@ -292,16 +389,265 @@ func (di *Dependencies) bootstrapServiceComponents(nodeOptions node.Options) err
 		newP2PSessionHandler,
 		di.SessionConnectivityStatusStorage,
 		di.LocationResolver,
+		di.CGNATDetector,
+		service.NewDialogThrottler(service.DefaultDialogThrottleConfig(), di.EventBus),
+		di.IdentityManager,
+		di.CapacityMonitor,
+		nodeOptions.Mobile,
+		egress.PolicyFromConfig().Proposal(),
 	)
 
+	runningServices := func() []monitoring_resources.Service {
+		var services []monitoring_resources.Service
+		for _, instance := range di.ServicesManager.List(false) {
+			s := monitoring_resources.Service{ID: string(instance.ID), Type: instance.Type}
+			if instance.Type == service_openvpn.ServiceType {
+				s.Process = filepath.Base(nodeOptions.Openvpn.BinaryPath())
+			}
+			services = append(services, s)
+		}
+		return services
+	}
+	di.ResourceMonitor = monitoring_resources.NewMonitor(
+		runningServices,
+		di.EventBus,
+		monitoring_resources.Thresholds{
+			CPUPercent:      nodeOptions.Monitoring.CPUPercent,
+			MemoryBytes:     nodeOptions.Monitoring.MemoryBytes,
+			FileDescriptors: nodeOptions.Monitoring.FileDescriptors,
+			Goroutines:      nodeOptions.Monitoring.Goroutines,
+		},
+		nodeOptions.Monitoring.Interval,
+	)
+	di.ResourceMonitor.Start()
+
+	if config.GetBool(config.FlagSLOEnabled) {
+		if err := di.bootstrapSLOMonitor(); err != nil {
+			return err
+		}
+	}
+
+	if config.GetBool(config.FlagSelfCheckEnabled) {
+		if err := di.bootstrapSelfCheck(nodeOptions.Directories.Data); err != nil {
+			return err
+		}
+	}
+
+	if config.GetString(config.FlagScheduleFeedURL) != "" {
+		if err := di.bootstrapScheduler(); err != nil {
+			return err
+		}
+	}
+
+	if path := config.GetString(config.FlagConfigFile); path != "" {
+		di.ConfigReloader = declarative.NewReloader(
+			path,
+			config.GetDuration(config.FlagConfigFileCheckInterval),
+			config.Current,
+			declarative.NewServiceController(di.ServicesManager, di.IdentitySelector, di.IdentityManager),
+		)
+		go di.ConfigReloader.Start()
+	}
+
 	serviceCleaner := service.Cleaner{SessionStorage: di.ServiceSessions}
 	if err := di.EventBus.Subscribe(servicestate.AppTopicServiceStatus, serviceCleaner.HandleServiceStatus); err != nil {
 		log.Error().Err(err).Msg("Failed to subscribe service cleaner")
 	}
 
+	ipChangeHandler := service.NewPublicIPChangeHandler(di.ServicesManager, di.ServiceSessions)
+	if err := ipChangeHandler.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bootstrapAbuseDetection loads consumers banned for abuse and, unless traffic is forwarded in userspace
+// where the detector sees every flow, watches kernel forwarded flows through conntrack.
+func (di *Dependencies) bootstrapAbuseDetection(dataDir string) error {
+	bans, err := abuse.NewBanList(dataDir, config.GetDuration(config.FlagAbuseBanDuration))
+	if err != nil {
+		return errors.Wrap(err, "could not load abuse bans")
+	}
+	di.AbuseBans = bans
+
+	if !abuse.Singleton().Limits().Enabled() || config.GetBool(config.FlagUserspace) || runtime.GOOS != "linux" {
+		return nil
+	}
+	if !abuse.ConntrackAvailable() {
+		log.Warn().Msg("conntrack is not installed, abusive sessions will only be throttled by the kernel")
+		return nil
+	}
+	di.AbuseMonitor = abuse.NewConntrackMonitor(abuse.Singleton(), abuse.DefaultConntrackInterval)
+	di.AbuseMonitor.Start()
+	return nil
+}
+
+func (di *Dependencies) bootstrapTrafficMeter() error {
+	capConfig := accounting.CapConfig{
+		MonthlyBytes: config.GetUInt64(config.FlagTrafficMonthlyCap) * 1024 * 1024 * 1024,
+		Action:       config.GetString(config.FlagTrafficCapAction),
+		BillingDay:   config.GetInt(config.FlagTrafficBillingDay),
+	}
+	if capConfig.Action != accounting.CapActionReject && capConfig.Action != accounting.CapActionStop {
+		return errors.Errorf("unknown traffic cap action: %s", capConfig.Action)
+	}
+	if capConfig.BillingDay < 1 || capConfig.BillingDay > 28 {
+		return errors.Errorf("traffic billing day must be from 1 to 28, got %d", capConfig.BillingDay)
+	}
+
+	stopServices := func() error {
+		return di.ServicesManager.Kill()
+	}
+	di.TrafficMeter = accounting.NewTrafficMeter(accounting.NewTrafficStorage(di.Storage), capConfig, di.EventBus, stopServices)
+	if err := di.TrafficMeter.Load(); err != nil {
+		return errors.Wrap(err, "could not load provider traffic")
+	}
+	if err := di.TrafficMeter.Subscribe(di.EventBus); err != nil {
+		return errors.Wrap(err, "could not subscribe traffic meter to session events")
+	}
+	di.TrafficMeter.Start()
 	return nil
 }
 
+func (di *Dependencies) bootstrapScheduler() error {
+	rules, err := schedule.ParseRules(config.GetStringSlice(config.FlagScheduleRules))
+	if err != nil {
+		return errors.Wrap(err, "invalid schedule rules")
+	}
+	if len(rules) == 0 {
+		return errors.New("schedule feed is set, but no schedule rules are given")
+	}
+
+	feed := schedule.NewHTTPFeed(
+		di.HTTPClient,
+		config.GetString(config.FlagScheduleFeedURL),
+		config.GetString(config.FlagScheduleFeedPath),
+	)
+	scheduleConfig := schedule.Config{
+		Interval: config.GetDuration(config.FlagScheduleInterval),
+		Timeout:  30 * time.Second,
+		Rules:    rules,
+	}
+
+	di.Scheduler = schedule.NewScheduler(scheduleConfig, feed, schedule.NewServiceController(di.ServicesManager), di.EventBus)
+	go di.Scheduler.Start()
+	return nil
+}
+
+func (di *Dependencies) bootstrapSLOMonitor() error {
+	forEachService := func(fn func(id service.ID) error) error {
+		errs := utils.ErrorCollection{}
+		for _, instance := range di.ServicesManager.List(false) {
+			errs.Add(fn(instance.ID))
+		}
+		return errs.Errorf("ErrorCollection(%s)", ", ")
+	}
+
+	actions := map[string]slo.Action{
+		slo.ActionRedetectNAT: func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			_, err := di.NATProber.Refresh(ctx)
+			return err
+		},
+		slo.ActionReregisterProposal: func() error {
+			return forEachService(di.ServicesManager.Reannounce)
+		},
+		slo.ActionRestartService: func() error {
+			return forEachService(func(id service.ID) error {
+				_, err := di.ServicesManager.Restart(id)
+				return err
+			})
+		},
+	}
+
+	sloConfig := slo.DefaultConfig()
+	sloConfig.MinSessionSuccessRate = config.GetFloat64(config.FlagSLOMinSessionSuccessRate)
+	sloConfig.MaxMedianTTFB = config.GetDuration(config.FlagSLOMaxMedianTTFB)
+	sloConfig.MaxPaymentFailureRate = config.GetFloat64(config.FlagSLOMaxPaymentFailureRate)
+	sloConfig.Actions = config.GetStringSlice(config.FlagSLOActions)
+
+	di.SLOMonitor = slo.NewMonitor(sloConfig, actions, slo.NewAuditStorage(di.Storage), di.EventBus)
+	if err := di.SLOMonitor.Subscribe(di.EventBus); err != nil {
+		return errors.Wrap(err, "could not subscribe SLO monitor to session events")
+	}
+	go di.SLOMonitor.Start()
+
+	return nil
+}
+
+// bootstrapSelfCheck starts the agent dialing running services from a test consumer identity.
+// The identity is kept in a separate keystore, so that it is never listed among node identities.
+func (di *Dependencies) bootstrapSelfCheck(dataDir string) error {
+	threshold := config.GetInt(config.FlagSelfCheckFailureThreshold)
+	if threshold < 1 {
+		return errors.Errorf("self-check failure threshold must be positive, got %d", threshold)
+	}
+
+	keystoreDir := filepath.Join(dataDir, "selfcheck-keystore")
+	ks := identity.NewKeystoreFilesystem(keystoreDir, keystore.NewKeyStore(keystoreDir, keystore.LightScryptN, keystore.LightScryptP))
+	consumer, err := selfCheckIdentity(ks)
+	if err != nil {
+		return errors.Wrap(err, "could not load self-check consumer identity")
+	}
+
+	dialer := p2p.NewDialer(
+		di.BrokerConnector,
+		di.PeerTransport,
+		func(id identity.Identity) identity.Signer {
+			return identity.NewSigner(ks, id)
+		},
+		func(id identity.Identity) identity.Verifier {
+			return identity.NewVerifierIdentity(id)
+		},
+		di.IPResolver,
+		di.PortPool,
+		di.EventBus,
+	)
+	prober := selfcheck.NewProber(di.ProposalRepository, dialer, p2p.NewPinger(di.BrokerConnector), consumer)
+
+	runningServices := func() []selfcheck.Target {
+		var targets []selfcheck.Target
+		for _, instance := range di.ServicesManager.List(false) {
+			if instance.State() != servicestate.Running {
+				continue
+			}
+			targets = append(targets, selfcheck.Target{ProviderID: instance.ProviderID.Address, ServiceType: instance.Type})
+		}
+		return targets
+	}
+
+	agentConfig := selfcheck.Config{
+		Interval:         config.GetDuration(config.FlagSelfCheckInterval),
+		Timeout:          time.Minute,
+		FailureThreshold: threshold,
+	}
+	di.SelfCheckAgent = selfcheck.NewAgent(agentConfig, runningServices, prober, selfcheck.NewStorage(di.Storage), di.EventBus)
+	go di.SelfCheckAgent.Start()
+
+	log.Info().Msgf("Self-checking services every %s from identity %s", agentConfig.Interval, consumer.Address)
+	return nil
+}
+
+func selfCheckIdentity(ks *identity.Keystore) (identity.Identity, error) {
+	var account accounts.Account
+	if existing := ks.Accounts(); len(existing) > 0 {
+		account = existing[0]
+	} else {
+		created, err := ks.NewAccount("")
+		if err != nil {
+			return identity.Identity{}, err
+		}
+		account = created
+	}
+
+	if err := ks.Unlock(account, ""); err != nil {
+		return identity.Identity{}, err
+	}
+	return identity.FromAddress(account.Address.Hex()), nil
+}
+
 func (di *Dependencies) registerConnections(nodeOptions node.Options) {
 	di.registerOpenvpnConnection(nodeOptions)
 	di.registerNoopConnection()
@@ -319,12 +665,12 @@ func (di *Dependencies) registerWireguardConnection(nodeOptions node.Options, re
 	endpointFactory := func() (wireguard.ConnectionEndpoint, error) {
 		return endpoint.NewConnectionEndpoint(resourceAllocator, wgClientFactory)
 	}
-	connFactory := func() (connection.Connection, error) {
+	connFactory := func(states connection.StateHandler) (connection.Connection, error) {
 		opts := wireguard_connection.Options{
 			DNSScriptDir:     nodeOptions.Directories.Script,
 			HandshakeTimeout: 1 * time.Minute,
 		}
-		return wireguard_connection.NewConnection(opts, di.IPResolver, endpointFactory, handshakeWaiter)
+		return wireguard_connection.NewConnection(opts, states, di.IPResolver, endpointFactory, handshakeWaiter)
 	}
 	di.ConnectionRegistry.Register(wireguard.ServiceType, connFactory)
 }
@@ -335,12 +681,12 @@ func (di *Dependencies) registerScrapingConnection(nodeOptions node.Options, res
 	endpointFactory := func() (wireguard.ConnectionEndpoint, error) {
 		return endpoint.NewConnectionEndpoint(resourceAllocator, wgClientFactory)
 	}
-	connFactory := func() (connection.Connection, error) {
+	connFactory := func(states connection.StateHandler) (connection.Connection, error) {
 		opts := wireguard_connection.Options{
 			DNSScriptDir:     nodeOptions.Directories.Script,
 			HandshakeTimeout: 1 * time.Minute,
 		}
-		return wireguard_connection.NewConnection(opts, di.IPResolver, endpointFactory, handshakeWaiter)
+		return wireguard_connection.NewConnection(opts, states, di.IPResolver, endpointFactory, handshakeWaiter)
 	}
 	di.ConnectionRegistry.Register(scraping.ServiceType, connFactory)
 }
@@ -351,12 +697,12 @@ func (di *Dependencies) registerDataTransferConnection(nodeOptions node.Options,
 	endpointFactory := func() (wireguard.ConnectionEndpoint, error) {
 		return endpoint.NewConnectionEndpoint(resourceAllocator, wgClientFactory)
 	}
-	connFactory := func() (connection.Connection, error) {
+	connFactory := func(states connection.StateHandler) (connection.Connection, error) {
 		opts := wireguard_connection.Options{
 			DNSScriptDir:     nodeOptions.Directories.Script,
 			HandshakeTimeout: 1 * time.Minute,
 		}
-		return wireguard_connection.NewConnection(opts, di.IPResolver, endpointFactory, handshakeWaiter)
+		return wireguard_connection.NewConnection(opts, states, di.IPResolver, endpointFactory, handshakeWaiter)
 	}
 	di.ConnectionRegistry.Register(datatransfer.ServiceType, connFactory)
 }
@@ -21,17 +21,20 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/discovery"
 	"github.com/mysteriumnetwork/node/core/discovery/apidiscovery"
 	"github.com/mysteriumnetwork/node/core/discovery/brokerdiscovery"
 	"github.com/mysteriumnetwork/node/core/discovery/dhtdiscovery"
+	"github.com/mysteriumnetwork/node/core/discovery/feed"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/core/node"
+	"github.com/mysteriumnetwork/node/core/quality"
 	"github.com/mysteriumnetwork/node/core/service"
 	"github.com/pkg/errors"
 )
 
-func (di *Dependencies) bootstrapDiscoveryComponents(options node.OptionsDiscovery) error {
+func (di *Dependencies) bootstrapDiscoveryComponents(options node.OptionsDiscovery, dataDir string) error {
 	di.FilterPresetStorage = proposal.NewFilterPresetStorage(di.Storage)
 	proposalRepository := discovery.NewRepository()
 	proposalRegistry := discovery.NewRegistry()
@@ -41,17 +44,26 @@ func (di *Dependencies) bootstrapDiscoveryComponents(options node.OptionsDiscove
 		switch discoveryType {
 		case node.DiscoveryTypeAPI:
 			// Broker is the way to announce node presence currently, so enabled by default no matter the users preferences.
-			proposalRegistry.AddRegistry(brokerdiscovery.NewRegistry(di.BrokerConnection))
-			proposalRepository.Add(apidiscovery.NewRepository(di.MysteriumAPI))
+			proposalRegistry.AddRegistry(brokerdiscovery.NewRegistry(di.BrokerTransport))
+			if options.CacheTTL <= 0 {
+				proposalRepository.Add(apidiscovery.NewRepository(di.MysteriumAPI))
+				break
+			}
+			di.ProposalsCache = discovery.NewCachedRepository(apidiscovery.NewRepository(di.MysteriumAPI), di.MysteriumAPI, options.CacheTTL, dataDir)
+			if err := di.ProposalsCache.Subscribe(di.EventBus); err != nil {
+				return err
+			}
+			di.ProposalsCache.Start(options.FetchInterval)
+			proposalRepository.Add(di.ProposalsCache)
 
 		case node.DiscoveryTypeBroker:
 			storage := brokerdiscovery.NewStorage(di.EventBus)
-			brokerRepository := brokerdiscovery.NewRepository(di.BrokerConnection, storage, options.PingInterval+time.Second, 1*time.Second)
+			brokerRepository := brokerdiscovery.NewRepository(di.BrokerTransport, storage, options.PingInterval+time.Second, 1*time.Second)
 			if options.FetchEnabled {
 				discoveryWorker.AddWorker(brokerRepository)
 			}
 
-			proposalRegistry.AddRegistry(brokerdiscovery.NewRegistry(di.BrokerConnection))
+			proposalRegistry.AddRegistry(brokerdiscovery.NewRegistry(di.BrokerTransport))
 			proposalRepository.Add(brokerRepository)
 
 		case node.DiscoveryTypeDHT:
@@ -77,9 +89,41 @@ func (di *Dependencies) bootstrapDiscoveryComponents(options node.OptionsDiscove
 		return errors.Wrap(err, "failed to start discovery")
 	}
 
-	di.ProposalRepository = discovery.NewPricedServiceProposalRepository(proposalRepository, di.PricingHelper, di.FilterPresetStorage)
+	di.QualityScores = quality.NewScores()
+	qualityHistory, err := quality.NewHistory(dataDir)
+	if err != nil {
+		return errors.Wrap(err, "could not load session history")
+	}
+	di.QualityHistory = qualityHistory
+	if err := di.QualityHistory.Subscribe(di.EventBus); err != nil {
+		return err
+	}
+	verifier := discovery.NewProposalVerifier(di.IdentityRegistry, config.GetInt64(config.FlagChainID), options.RequireSignedProposals)
+	verifiedRepository := discovery.NewVerifiedRepository(proposalRepository, verifier)
+	rankedRepository := quality.NewRankedRepository(verifiedRepository, di.QualityScores, di.QualityHistory)
+	di.ProposalRepository = discovery.NewPricedServiceProposalRepository(rankedRepository, di.PricingHelper, di.FilterPresetStorage)
 	di.DiscoveryFactory = func() service.Discovery {
 		return discovery.NewService(di.IdentityRegistry, proposalRegistry, options.PingInterval, di.SignerFactory, di.EventBus)
 	}
 	return nil
 }
+
+func (di *Dependencies) bootstrapProposalsFeed(options node.OptionsProposalsFeed) error {
+	if len(options.Targets) == 0 {
+		return nil
+	}
+
+	sinks, err := feed.NewSinks(options.Targets, feed.S3Options{Endpoint: options.S3Endpoint, Region: options.S3Region})
+	if err != nil {
+		return errors.Wrap(err, "could not create proposals feed targets")
+	}
+
+	filter := feed.Filter{
+		ServiceType: options.ServiceType,
+		Country:     options.Country,
+		QualityMin:  float32(options.QualityMin),
+	}
+	di.ProposalsFeed = feed.NewExporter(di.ProposalRepository, filter, sinks, options.Interval)
+	di.ProposalsFeed.Start()
+	return nil
+}
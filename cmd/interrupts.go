@@ -20,20 +20,44 @@ package cmd
 import (
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 )
 
 // SignalCallback is invoked when process receives signals defined below
 type SignalCallback func()
 
+var (
+	terminationMu sync.Mutex
+	terminations  []chan os.Signal
+)
+
 // RegisterSignalCallback registers given callback to call on SIGTERM and SIGHUP interrupts
 func RegisterSignalCallback(callback SignalCallback) {
 	sigterm := make(chan os.Signal, 1)
 	signal.Notify(sigterm, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
+	terminationMu.Lock()
+	terminations = append(terminations, sigterm)
+	terminationMu.Unlock()
+
 	go waitTerminationSignal(sigterm, callback)
 }
 
+// Terminate calls registered callbacks as if the process received SIGTERM.
+// Used where termination is not requested by signals, e.g. by Windows service control manager.
+func Terminate() {
+	terminationMu.Lock()
+	defer terminationMu.Unlock()
+
+	for _, sigterm := range terminations {
+		select {
+		case sigterm <- syscall.SIGTERM:
+		default:
+		}
+	}
+}
+
 func waitTerminationSignal(termination chan os.Signal, callback SignalCallback) {
 	<-termination
 	callback()
@@ -26,9 +26,11 @@ import (
 	command_cfg "github.com/mysteriumnetwork/node/cmd/commands/config"
 	"github.com/mysteriumnetwork/node/cmd/commands/connection"
 	"github.com/mysteriumnetwork/node/cmd/commands/daemon"
+	"github.com/mysteriumnetwork/node/cmd/commands/devstack"
 	"github.com/mysteriumnetwork/node/cmd/commands/license"
 	"github.com/mysteriumnetwork/node/cmd/commands/reset"
 	"github.com/mysteriumnetwork/node/cmd/commands/service"
+	"github.com/mysteriumnetwork/node/cmd/commands/setup"
 	"github.com/mysteriumnetwork/node/cmd/commands/version"
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/logconfig"
@@ -53,6 +55,8 @@ var (
 	accountCommand    = account.NewCommand()
 	connectionCommand = connection.NewCommand()
 	configCommand     = command_cfg.NewCommand()
+	devstackCommand   = devstack.NewCommand()
+	setupCommand      = setup.NewCommand()
 )
 
 func main() {
@@ -63,7 +67,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	err = app.Run(os.Args)
+	err = run(app, os.Args)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to execute command: ")
 		os.Exit(1)
@@ -99,6 +103,8 @@ func NewCommand() (*cli.App, error) {
 		accountCommand,
 		connectionCommand,
 		configCommand,
+		devstackCommand,
+		setupCommand,
 	}
 
 	return app, nil
@@ -117,6 +123,7 @@ var uiCommands = map[string]struct{}{
 	connection.CommandName:  {},
 	command_cfg.CommandName: {},
 	reset.CommandName:       {},
+	setup.CommandName:       {},
 }
 
 // configureLogging returns a func which configures global
@@ -0,0 +1,386 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package communication
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+const (
+	// CipherNaClBox is X25519, XSalsa20 and Poly1305 authenticated encryption.
+	CipherNaClBox = "nacl-box"
+	// CipherChaCha20Poly1305 is X25519 key agreement with XChaCha20-Poly1305 authenticated encryption.
+	CipherChaCha20Poly1305 = "x25519-xchacha20poly1305"
+)
+
+// counterSize is the size of message counter prepended to every sealed message.
+const counterSize = 8
+
+// SupportedCiphers returns ciphers supported by this node in the order of preference.
+func SupportedCiphers() []string {
+	return []string{CipherChaCha20Poly1305, CipherNaClBox}
+}
+
+var (
+	// ErrNoCommonCipher is returned when dialog peers do not support any common cipher.
+	ErrNoCommonCipher = errors.New("no common cipher")
+	// ErrReplayedMessage is returned when peer's message counter does not grow, e.g. message was replayed.
+	ErrReplayedMessage = errors.New("replayed message")
+)
+
+// Cipher encrypts and authenticates dialog payloads, so they stay confidential from the broker.
+type Cipher interface {
+	Name() string
+	Seal(plaintext []byte) ([]byte, error)
+	Open(ciphertext []byte) ([]byte, error)
+}
+
+// NewCipher creates cipher of the given name keyed by own private and peer's public X25519 keys.
+// Each direction gets its own key, initiator tells which side of the dialog this cipher seals for.
+func NewCipher(name string, privateKey, peerPublicKey [32]byte, initiator bool) (Cipher, error) {
+	var shared []byte
+	var newAEAD func(key []byte) (cipher.AEAD, error)
+	switch name {
+	case CipherNaClBox:
+		var precomputed [32]byte
+		box.Precompute(&precomputed, &peerPublicKey, &privateKey)
+		shared = precomputed[:]
+		newAEAD = newSecretboxAEAD
+	case CipherChaCha20Poly1305:
+		var err error
+		shared, err = curve25519.X25519(privateKey[:], peerPublicKey[:])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to agree on shared key")
+		}
+		newAEAD = chacha20poly1305.NewX
+	default:
+		return nil, errors.Errorf("unsupported cipher: %s", name)
+	}
+
+	initiatorKey, err := deriveKey(shared, name, "initiator")
+	if err != nil {
+		return nil, err
+	}
+	receiverKey, err := deriveKey(shared, name, "receiver")
+	if err != nil {
+		return nil, err
+	}
+	if !initiator {
+		initiatorKey, receiverKey = receiverKey, initiatorKey
+	}
+
+	c := &counterCipher{name: name}
+	if c.send, err = newAEAD(initiatorKey); err != nil {
+		return nil, err
+	}
+	if c.receive, err = newAEAD(receiverKey); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func deriveKey(shared []byte, name, direction string) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, nil, []byte(name+"|"+direction)), key); err != nil {
+		return nil, errors.Wrap(err, "failed to derive key")
+	}
+	return key, nil
+}
+
+// counterCipher seals messages with a nonce built from a monotonic counter, so nonces never repeat
+// under the same key and replayed or reordered messages are rejected.
+type counterCipher struct {
+	name    string
+	send    cipher.AEAD
+	receive cipher.AEAD
+
+	mu       sync.Mutex
+	sent     uint64
+	received uint64
+}
+
+func (c *counterCipher) Name() string {
+	return c.name
+}
+
+func (c *counterCipher) Seal(plaintext []byte) ([]byte, error) {
+	c.mu.Lock()
+	c.sent++
+	counter := c.sent
+	c.mu.Unlock()
+
+	if counter == 0 {
+		return nil, errors.New("message counter exhausted")
+	}
+
+	sealed := make([]byte, counterSize, counterSize+len(plaintext)+c.send.Overhead())
+	binary.BigEndian.PutUint64(sealed, counter)
+	return c.send.Seal(sealed, counterNonce(c.send, counter), plaintext, nil), nil
+}
+
+func (c *counterCipher) Open(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < counterSize {
+		return nil, errors.New("ciphertext is too short")
+	}
+	counter := binary.BigEndian.Uint64(ciphertext)
+
+	plaintext, err := c.receive.Open(nil, counterNonce(c.receive, counter), ciphertext[counterSize:], nil)
+	if err != nil {
+		return nil, errors.New("could not decrypt message")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if counter <= c.received {
+		return nil, ErrReplayedMessage
+	}
+	c.received = counter
+
+	return plaintext, nil
+}
+
+func counterNonce(aead cipher.AEAD, counter uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-counterSize:], counter)
+	return nonce
+}
+
+// secretboxAEAD adapts NaCl secretbox to AEAD interface, additional data is not supported.
+type secretboxAEAD struct {
+	key [32]byte
+}
+
+func newSecretboxAEAD(key []byte) (cipher.AEAD, error) {
+	a := &secretboxAEAD{}
+	copy(a.key[:], key)
+	return a, nil
+}
+
+func (a *secretboxAEAD) NonceSize() int {
+	return 24
+}
+
+func (a *secretboxAEAD) Overhead() int {
+	return secretbox.Overhead
+}
+
+func (a *secretboxAEAD) Seal(dst, nonce, plaintext, _ []byte) []byte {
+	var n [24]byte
+	copy(n[:], nonce)
+	return secretbox.Seal(dst, plaintext, &n, &a.key)
+}
+
+func (a *secretboxAEAD) Open(dst, nonce, ciphertext, _ []byte) ([]byte, error) {
+	var n [24]byte
+	copy(n[:], nonce)
+	plaintext, ok := secretbox.Open(dst, ciphertext, &n, &a.key)
+	if !ok {
+		return nil, errors.New("could not decrypt message")
+	}
+	return plaintext, nil
+}
+
+// CipherOffer is sent by dialog initiator to propose ciphers and share its ephemeral key.
+type CipherOffer struct {
+	Ciphers   []string `json:"ciphers"`
+	PublicKey string   `json:"public_key"`
+	Signature string   `json:"signature"`
+	// MessageVersions lists message versions understood by initiator, older nodes do not send it.
+	MessageVersions []int `json:"message_versions,omitempty"`
+}
+
+// CipherAnswer is returned by dialog receiver with the chosen cipher and its ephemeral key.
+type CipherAnswer struct {
+	Cipher    string `json:"cipher"`
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+	// MessageVersion is the message version chosen by receiver, zero means legacy bare payloads.
+	MessageVersion int `json:"message_version,omitempty"`
+}
+
+// CipherNegotiator agrees on a dialog cipher and message version. Ephemeral keys are signed by identity keys,
+// so the broker can neither read payloads nor substitute keys of either peer.
+type CipherNegotiator struct {
+	signer     identity.Signer
+	supported  []string
+	privateKey [32]byte
+	publicKey  [32]byte
+	offered    []string
+
+	messageVersions []int
+	messageVersion  int
+}
+
+// NewCipherNegotiator creates negotiator with a fresh ephemeral key pair.
+func NewCipherNegotiator(signer identity.Signer, supported []string) (*CipherNegotiator, error) {
+	pub, priv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate ephemeral key")
+	}
+
+	return &CipherNegotiator{
+		signer:          signer,
+		supported:       supported,
+		privateKey:      *priv,
+		publicKey:       *pub,
+		messageVersions: SupportedMessageVersions(),
+	}, nil
+}
+
+// MessageVersion returns message version agreed during the last Accept or Complete.
+func (n *CipherNegotiator) MessageVersion() int {
+	return n.messageVersion
+}
+
+// Codec returns dialog codec encrypting payloads of the given codec with the agreed cipher
+// and wrapping them into envelopes of the agreed message version.
+func (n *CipherNegotiator) Codec(codec Codec, cipher Cipher) Codec {
+	return NewCodecVersioned(NewCodecEncrypted(codec, cipher), n.messageVersion)
+}
+
+// Offer creates a signed offer of supported ciphers and message versions.
+func (n *CipherNegotiator) Offer() (CipherOffer, error) {
+	publicKey := hex.EncodeToString(n.publicKey[:])
+	signature, err := n.signer.Sign(cipherSigningMessage(n.supported, publicKey, n.messageVersions, ""))
+	if err != nil {
+		return CipherOffer{}, errors.Wrap(err, "could not sign cipher offer")
+	}
+	n.offered = n.supported
+
+	return CipherOffer{
+		Ciphers:         n.supported,
+		PublicKey:       publicKey,
+		Signature:       signature.Base64(),
+		MessageVersions: n.messageVersions,
+	}, nil
+}
+
+// Accept verifies peer's offer and picks the first offered cipher supported locally.
+func (n *CipherNegotiator) Accept(offer CipherOffer, peer identity.Verifier) (CipherAnswer, Cipher, error) {
+	peerKey, err := verifyCipherKey(cipherSigningMessage(offer.Ciphers, offer.PublicKey, offer.MessageVersions, ""), offer.PublicKey, offer.Signature, peer)
+	if err != nil {
+		return CipherAnswer{}, nil, err
+	}
+
+	version, err := NegotiateMessageVersion(offer.MessageVersions, n.messageVersions)
+	if err != nil {
+		return CipherAnswer{}, nil, err
+	}
+
+	name := ""
+	for _, offered := range offer.Ciphers {
+		if contains(n.supported, offered) {
+			name = offered
+			break
+		}
+	}
+	if name == "" {
+		return CipherAnswer{}, nil, ErrNoCommonCipher
+	}
+
+	agreed, err := NewCipher(name, n.privateKey, peerKey, false)
+	if err != nil {
+		return CipherAnswer{}, nil, err
+	}
+
+	publicKey := hex.EncodeToString(n.publicKey[:])
+	signature, err := n.signer.Sign(cipherSigningMessage([]string{name}, publicKey, []int{version}, offer.PublicKey))
+	if err != nil {
+		return CipherAnswer{}, nil, errors.Wrap(err, "could not sign cipher answer")
+	}
+	n.messageVersion = version
+
+	return CipherAnswer{Cipher: name, PublicKey: publicKey, Signature: signature.Base64(), MessageVersion: version}, agreed, nil
+}
+
+// Complete verifies peer's answer to the previously made offer and returns the agreed cipher.
+func (n *CipherNegotiator) Complete(answer CipherAnswer, peer identity.Verifier) (Cipher, error) {
+	if !contains(n.offered, answer.Cipher) {
+		return nil, errors.Errorf("peer chose cipher which was not offered: %s", answer.Cipher)
+	}
+
+	if answer.MessageVersion != MessageVersionLegacy && !containsVersion(n.messageVersions, answer.MessageVersion) {
+		return nil, errors.Wrapf(ErrNoCommonMessageVersion, "peer chose message version %d", answer.MessageVersion)
+	}
+
+	offerKey := hex.EncodeToString(n.publicKey[:])
+	message := cipherSigningMessage([]string{answer.Cipher}, answer.PublicKey, []int{answer.MessageVersion}, offerKey)
+	peerKey, err := verifyCipherKey(message, answer.PublicKey, answer.Signature, peer)
+	if err != nil {
+		return nil, err
+	}
+	n.messageVersion = answer.MessageVersion
+
+	return NewCipher(answer.Cipher, n.privateKey, peerKey, true)
+}
+
+func verifyCipherKey(message []byte, publicKey, signature string, peer identity.Verifier) ([32]byte, error) {
+	var key [32]byte
+	if ok, _ := peer.Verify(message, identity.SignatureBase64(signature)); !ok {
+		return key, errors.New("invalid peer signature of cipher key")
+	}
+
+	raw, err := hex.DecodeString(publicKey)
+	if err != nil || len(raw) != len(key) {
+		return key, errors.New("invalid peer cipher key")
+	}
+	copy(key[:], raw)
+
+	return key, nil
+}
+
+// cipherSigningMessage builds the signed part of offers and answers. Offers cover every offered cipher and
+// message version, so the broker can not strip stronger choices. Answers additionally cover the key of the offer
+// they reply to, proving that the receiver chose from the untampered offer.
+func cipherSigningMessage(ciphers []string, publicKey string, versions []int, offerKey string) []byte {
+	list := make([]string, len(versions))
+	for i, v := range versions {
+		list[i] = strconv.Itoa(v)
+	}
+	message := strings.Join(ciphers, ",") + "|" + publicKey + "|" + strings.Join(list, ",")
+	if offerKey != "" {
+		message += "|" + offerKey
+	}
+	return []byte(message)
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package communication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+func negotiate(t *testing.T, consumerCiphers, providerCiphers []string) (Cipher, Cipher, error) {
+	consumer, err := NewCipherNegotiator(&identity.SignerFake{}, consumerCiphers)
+	assert.NoError(t, err)
+	provider, err := NewCipherNegotiator(&identity.SignerFake{}, providerCiphers)
+	assert.NoError(t, err)
+
+	offer, err := consumer.Offer()
+	assert.NoError(t, err)
+	answer, providerCipher, err := provider.Accept(offer, &identity.VerifierFake{})
+	if err != nil {
+		return nil, nil, err
+	}
+	consumerCipher, err := consumer.Complete(answer, &identity.VerifierFake{})
+
+	return consumerCipher, providerCipher, err
+}
+
+func TestCipherNegotiator_AgreesOnCommonCipher(t *testing.T) {
+	for _, name := range SupportedCiphers() {
+		consumerCipher, providerCipher, err := negotiate(t, []string{"unknown", name}, SupportedCiphers())
+		assert.NoError(t, err)
+		assert.Equal(t, name, consumerCipher.Name())
+		assert.Equal(t, name, providerCipher.Name())
+
+		ciphertext, err := consumerCipher.Seal([]byte("hello"))
+		assert.NoError(t, err)
+		assert.NotContains(t, string(ciphertext), "hello")
+
+		plaintext, err := providerCipher.Open(ciphertext)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hello"), plaintext)
+	}
+}
+
+func TestCipherNegotiator_NoCommonCipher(t *testing.T) {
+	_, _, err := negotiate(t, []string{CipherNaClBox}, []string{CipherChaCha20Poly1305})
+	assert.Equal(t, ErrNoCommonCipher, err)
+}
+
+func TestCipherNegotiator_RejectsSubstitutedKey(t *testing.T) {
+	consumer, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+	provider, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+	attacker, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+
+	offer, _ := consumer.Offer()
+	attackerOffer, _ := attacker.Offer()
+	offer.PublicKey = attackerOffer.PublicKey
+
+	_, _, err := provider.Accept(offer, &identity.VerifierFake{})
+	assert.Error(t, err)
+}
+
+func TestCipherNegotiator_RejectsAnswerToAnotherOffer(t *testing.T) {
+	consumer, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+	other, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+	provider, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+
+	_, _ = consumer.Offer()
+	otherOffer, _ := other.Offer()
+	answer, _, err := provider.Accept(otherOffer, &identity.VerifierFake{})
+	assert.NoError(t, err)
+
+	_, err = consumer.Complete(answer, &identity.VerifierFake{})
+	assert.Error(t, err)
+}
+
+func TestCipher_UsesKeyPerDirection(t *testing.T) {
+	for _, name := range SupportedCiphers() {
+		consumerCipher, providerCipher, err := negotiate(t, []string{name}, SupportedCiphers())
+		assert.NoError(t, err)
+
+		sealed, err := consumerCipher.Seal([]byte("hello"))
+		assert.NoError(t, err)
+		// Own messages reflected back by the broker do not open.
+		_, err = consumerCipher.Open(sealed)
+		assert.Error(t, err)
+
+		reply, err := providerCipher.Seal([]byte("hello"))
+		assert.NoError(t, err)
+		assert.NotEqual(t, sealed[counterSize:], reply[counterSize:])
+	}
+}
+
+func TestCipher_RejectsReplayedMessages(t *testing.T) {
+	consumerCipher, providerCipher, err := negotiate(t, SupportedCiphers(), SupportedCiphers())
+	assert.NoError(t, err)
+
+	first, _ := consumerCipher.Seal([]byte("first"))
+	second, _ := consumerCipher.Seal([]byte("second"))
+
+	plaintext, err := providerCipher.Open(second)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("second"), plaintext)
+
+	_, err = providerCipher.Open(second)
+	assert.Equal(t, ErrReplayedMessage, err)
+	_, err = providerCipher.Open(first)
+	assert.Equal(t, ErrReplayedMessage, err)
+}
+
+func TestCodecEncrypted_PackUnpack(t *testing.T) {
+	consumerCipher, providerCipher, err := negotiate(t, SupportedCiphers(), SupportedCiphers())
+	assert.NoError(t, err)
+
+	packed, err := NewCodecEncrypted(NewCodecJSON(), consumerCipher).Pack(&struct{ Field string }{"secret"})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(packed), "secret")
+
+	var unpacked struct{ Field string }
+	assert.NoError(t, NewCodecEncrypted(NewCodecJSON(), providerCipher).Unpack(packed, &unpacked))
+	assert.Equal(t, "secret", unpacked.Field)
+
+	packed[len(packed)-1] ^= 0xff
+	assert.Error(t, NewCodecEncrypted(NewCodecJSON(), providerCipher).Unpack(packed, &unpacked))
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package communication
+
+// NewCodecEncrypted returns codec which:
+//   - encodes/decodes payloads using the given codec
+//   - encrypts/decrypts encoded payloads with the cipher agreed by dialog peers
+func NewCodecEncrypted(codec Codec, cipher Cipher) *codecEncrypted {
+	return &codecEncrypted{
+		codec:  codec,
+		cipher: cipher,
+	}
+}
+
+type codecEncrypted struct {
+	codec  Codec
+	cipher Cipher
+}
+
+func (codec *codecEncrypted) Pack(payloadPtr interface{}) ([]byte, error) {
+	data, err := codec.codec.Pack(payloadPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	return codec.cipher.Seal(data)
+}
+
+func (codec *codecEncrypted) Unpack(data []byte, payloadPtr interface{}) error {
+	plaintext, err := codec.cipher.Open(data)
+	if err != nil {
+		return err
+	}
+
+	return codec.codec.Unpack(plaintext, payloadPtr)
+}
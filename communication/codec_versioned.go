@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package communication
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MessageVersionLegacy is used with peers which send bare payloads without envelopes.
+	MessageVersionLegacy = 0
+	// MessageVersionCurrent is the newest message schema version of this node.
+	MessageVersionCurrent = 1
+)
+
+// envelopeMagic starts every versioned envelope. It can not start a JSON document,
+// so envelopes are easy to tell apart from bare payloads when debugging.
+var envelopeMagic = []byte("MV")
+
+var (
+	// ErrUnsupportedMessageVersion is returned when peer sends a message of a version newer than supported.
+	ErrUnsupportedMessageVersion = errors.New("unsupported message version")
+	// ErrNoCommonMessageVersion is returned when dialog peers do not share any message version.
+	ErrNoCommonMessageVersion = errors.New("no common message version")
+)
+
+// SupportedMessageVersions returns message schema versions understood by this node, newest first.
+func SupportedMessageVersions() []int {
+	return []int{MessageVersionCurrent, MessageVersionLegacy}
+}
+
+// NegotiateMessageVersion picks the newest version offered by peer and supported locally.
+// Peers which did not offer any version predate envelopes and get the legacy version.
+func NegotiateMessageVersion(offered, supported []int) (int, error) {
+	if len(offered) == 0 {
+		offered = []int{MessageVersionLegacy}
+	}
+
+	best := -1
+	for _, version := range offered {
+		if version > best && containsVersion(supported, version) {
+			best = version
+		}
+	}
+	if best < 0 {
+		return 0, ErrNoCommonMessageVersion
+	}
+
+	return best, nil
+}
+
+func containsVersion(list []int, version int) bool {
+	for _, v := range list {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCodecVersioned returns codec which:
+//   - encodes/decodes payloads using the given codec
+//   - wraps encoded payloads into envelopes marked with the message version negotiated by dialog peers
+//   - sends bare payloads, as older nodes expect, when legacy version was negotiated
+func NewCodecVersioned(codec Codec, version int) *codecVersioned {
+	return &codecVersioned{
+		codec:   codec,
+		version: version,
+	}
+}
+
+type codecVersioned struct {
+	codec   Codec
+	version int
+}
+
+func (codec *codecVersioned) Pack(payloadPtr interface{}) ([]byte, error) {
+	data, err := codec.codec.Pack(payloadPtr)
+	if err != nil || codec.version == MessageVersionLegacy {
+		return data, err
+	}
+
+	envelope := make([]byte, len(envelopeMagic)+binary.MaxVarintLen64, len(envelopeMagic)+binary.MaxVarintLen64+len(data))
+	copy(envelope, envelopeMagic)
+	n := binary.PutUvarint(envelope[len(envelopeMagic):], uint64(codec.version))
+	envelope = append(envelope[:len(envelopeMagic)+n], data...)
+
+	return envelope, nil
+}
+
+func (codec *codecVersioned) Unpack(data []byte, payloadPtr interface{}) error {
+	if codec.version == MessageVersionLegacy {
+		return codec.codec.Unpack(data, payloadPtr)
+	}
+
+	version, payload, err := openEnvelope(data)
+	if err != nil {
+		return err
+	}
+	if version > MessageVersionCurrent {
+		return errors.Wrapf(ErrUnsupportedMessageVersion, "message version %d", version)
+	}
+
+	return codec.codec.Unpack(payload, payloadPtr)
+}
+
+func openEnvelope(data []byte) (int, []byte, error) {
+	if !bytes.HasPrefix(data, envelopeMagic) {
+		return 0, nil, errors.New("message is not wrapped in a versioned envelope")
+	}
+
+	version, n := binary.Uvarint(data[len(envelopeMagic):])
+	if n <= 0 {
+		return 0, nil, errors.New("malformed message envelope")
+	}
+
+	return int(version), data[len(envelopeMagic)+n:], nil
+}
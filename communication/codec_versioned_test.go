@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package communication
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+type versionedPayload struct {
+	Field string `json:"field"`
+}
+
+func TestNegotiateMessageVersion(t *testing.T) {
+	version, err := NegotiateMessageVersion([]int{0, 1, 2}, SupportedMessageVersions())
+	assert.NoError(t, err)
+	assert.Equal(t, MessageVersionCurrent, version)
+
+	version, err = NegotiateMessageVersion(nil, SupportedMessageVersions())
+	assert.NoError(t, err)
+	assert.Equal(t, MessageVersionLegacy, version)
+
+	_, err = NegotiateMessageVersion([]int{2}, SupportedMessageVersions())
+	assert.Equal(t, ErrNoCommonMessageVersion, err)
+}
+
+func TestCodecVersioned_PackUnpack(t *testing.T) {
+	codec := NewCodecVersioned(NewCodecJSON(), MessageVersionCurrent)
+
+	packed, err := codec.Pack(&versionedPayload{Field: "value"})
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(packed, envelopeMagic))
+
+	var unpacked versionedPayload
+	assert.NoError(t, codec.Unpack(packed, &unpacked))
+	assert.Equal(t, "value", unpacked.Field)
+
+	// Newer peers may add fields, they are ignored by older decoders.
+	newer := append(append([]byte{}, packed[:3]...), []byte(`{"field":"value","added":1}`)...)
+	assert.NoError(t, codec.Unpack(newer, &unpacked))
+
+	future := append([]byte("MV"), 2)
+	future = append(future, packed[3:]...)
+	assert.ErrorIs(t, codec.Unpack(future, &unpacked), ErrUnsupportedMessageVersion)
+
+	assert.Error(t, codec.Unpack([]byte(`{"field":"value"}`), &unpacked))
+}
+
+func TestCodecVersioned_LegacySendsBarePayloads(t *testing.T) {
+	codec := NewCodecVersioned(NewCodecJSON(), MessageVersionLegacy)
+
+	packed, err := codec.Pack(&versionedPayload{Field: "value"})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"field":"value"}`, string(packed))
+
+	var unpacked versionedPayload
+	assert.NoError(t, codec.Unpack(packed, &unpacked))
+	assert.Equal(t, "value", unpacked.Field)
+}
+
+func TestCipherNegotiator_AgreesOnMessageVersion(t *testing.T) {
+	consumer, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+	provider, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+
+	offer, _ := consumer.Offer()
+	answer, providerCipher, err := provider.Accept(offer, &identity.VerifierFake{})
+	assert.NoError(t, err)
+	consumerCipher, err := consumer.Complete(answer, &identity.VerifierFake{})
+	assert.NoError(t, err)
+	assert.Equal(t, MessageVersionCurrent, consumer.MessageVersion())
+	assert.Equal(t, MessageVersionCurrent, provider.MessageVersion())
+
+	packed, err := consumer.Codec(NewCodecJSON(), consumerCipher).Pack(&versionedPayload{Field: "secret"})
+	assert.NoError(t, err)
+	var unpacked versionedPayload
+	assert.NoError(t, provider.Codec(NewCodecJSON(), providerCipher).Unpack(packed, &unpacked))
+	assert.Equal(t, "secret", unpacked.Field)
+}
+
+func TestCipherNegotiator_FallsBackToLegacyPeer(t *testing.T) {
+	consumer, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+	provider, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+
+	// Older nodes do not offer message versions.
+	consumer.messageVersions = nil
+	offer, _ := consumer.Offer()
+	answer, _, err := provider.Accept(offer, &identity.VerifierFake{})
+	assert.NoError(t, err)
+	assert.Equal(t, MessageVersionLegacy, provider.MessageVersion())
+
+	_, err = consumer.Complete(answer, &identity.VerifierFake{})
+	assert.NoError(t, err)
+	assert.Equal(t, MessageVersionLegacy, consumer.MessageVersion())
+}
+
+func TestCipherNegotiator_RejectsDowngradedOffer(t *testing.T) {
+	consumer, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+	provider, _ := NewCipherNegotiator(&identity.SignerFake{}, SupportedCiphers())
+
+	offer, _ := consumer.Offer()
+	offer.MessageVersions = []int{MessageVersionLegacy}
+	_, _, err := provider.Accept(offer, &identity.VerifierFake{})
+	assert.Error(t, err)
+
+	offer, _ = consumer.Offer()
+	offer.Ciphers = []string{CipherNaClBox}
+	_, _, err = provider.Accept(offer, &identity.VerifierFake{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,359 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package gossip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/communication"
+)
+
+const (
+	// TopicName is the GossipSub topic carrying published messages of all subjects.
+	TopicName = "/mysterium/broker/1.0.0"
+
+	// ProtocolID identifies streams carrying requests between directly connected nodes.
+	ProtocolID = protocol.ID("/mysterium/gossip/1.0.0")
+
+	streamTimeout = 10 * time.Second
+	maxFrameSize  = 1 << 20
+)
+
+// ErrNoPeers is returned when the node is not connected to any peer yet.
+var ErrNoPeers = errors.New("no connected gossip peers")
+
+type frameKind string
+
+const (
+	framePublish      = frameKind("publish")
+	frameRequest      = frameKind("request")
+	frameReply        = frameKind("reply")
+	frameNoResponders = frameKind("no-responders")
+)
+
+type frame struct {
+	Kind    frameKind         `json:"kind"`
+	Subject string            `json:"subject"`
+	Header  map[string]string `json:"header,omitempty"`
+	Data    []byte            `json:"data,omitempty"`
+}
+
+// Transport exchanges messages with other nodes peer-to-peer over libp2p.
+// Published messages are spread by GossipSub on a single topic and delivered to local subscribers by subject.
+// Requests are answered by directly connected peers only.
+type Transport struct {
+	libP2PConfig   libp2p.Config
+	libP2PNode     host.Host
+	pubSub         *pubsub.PubSub
+	topic          *pubsub.Topic
+	topicSub       *pubsub.Subscription
+	ctx            context.Context
+	cancel         context.CancelFunc
+	bootstrapPeers []*peer.AddrInfo
+
+	mu     sync.RWMutex
+	subs   map[uint64]*subscription
+	nextID uint64
+}
+
+// NewTransport creates gossip transport listening on the given multiaddress and joining the network through bootstrap peers.
+func NewTransport(listenAddress string, bootstrapPeerAddresses []string) (*Transport, error) {
+	t := &Transport{
+		bootstrapPeers: make([]*peer.AddrInfo, len(bootstrapPeerAddresses)),
+		subs:           make(map[uint64]*subscription),
+	}
+
+	listenAddr, err := multiaddr.NewMultiaddr(listenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse gossip listen address: %w", err)
+	}
+
+	for i, peerAddress := range bootstrapPeerAddresses {
+		peerAddr, err := multiaddr.NewMultiaddr(peerAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse gossip peer address: %w", err)
+		}
+
+		if t.bootstrapPeers[i], err = peer.AddrInfoFromP2pAddr(peerAddr); err != nil {
+			return nil, fmt.Errorf("failed to parse gossip peer info: %w", err)
+		}
+	}
+
+	if err = t.libP2PConfig.Apply(
+		libp2p.ListenAddrs(listenAddr),
+		libp2p.FallbackDefaults,
+	); err != nil {
+		return nil, fmt.Errorf("failed to configure gossip node: %w", err)
+	}
+
+	return t, nil
+}
+
+// Start starts libp2p host, joins the GossipSub topic and connects to bootstrap peers.
+func (t *Transport) Start() (err error) {
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+	defer func() {
+		if err != nil {
+			t.Close()
+		}
+	}()
+
+	t.libP2PNode, err = t.libP2PConfig.NewNode()
+	if err != nil {
+		return fmt.Errorf("failed to start gossip node: %w", err)
+	}
+	t.libP2PNode.SetStreamHandler(ProtocolID, t.handleStream)
+
+	// Own messages are sent to all topic peers, without waiting for the mesh to form.
+	if t.pubSub, err = pubsub.NewGossipSub(t.ctx, t.libP2PNode, pubsub.WithFloodPublish(true)); err != nil {
+		return fmt.Errorf("failed to start gossipsub: %w", err)
+	}
+	if t.topic, err = t.pubSub.Join(TopicName); err != nil {
+		return fmt.Errorf("failed to join gossip topic: %w", err)
+	}
+	if t.topicSub, err = t.topic.Subscribe(); err != nil {
+		return fmt.Errorf("failed to subscribe gossip topic: %w", err)
+	}
+	go t.receive(t.topicSub)
+
+	log.Info().Msgf("Gossip node started on %s with ID=%s", t.libP2PNode.Addrs(), t.libP2PNode.ID())
+
+	for _, peerInfo := range t.bootstrapPeers {
+		go t.connectToPeer(*peerInfo)
+	}
+
+	return nil
+}
+
+// Close leaves the GossipSub topic and stops libp2p host.
+func (t *Transport) Close() {
+	if t.cancel == nil {
+		return
+	}
+	t.cancel()
+	if t.topicSub != nil {
+		t.topicSub.Cancel()
+	}
+	if t.topic != nil {
+		if err := t.topic.Close(); err != nil {
+			log.Warn().Err(err).Msg("Failed to leave gossip topic")
+		}
+	}
+	if t.libP2PNode != nil {
+		if err := t.libP2PNode.Close(); err != nil {
+			log.Warn().Err(err).Msg("Failed to close gossip node")
+		}
+	}
+}
+
+func (t *Transport) connectToPeer(peerInfo peer.AddrInfo) {
+	if err := t.libP2PNode.Connect(t.ctx, peerInfo); err != nil {
+		log.Warn().Err(err).Msgf("Failed to contact gossip peer %s", peerInfo.ID)
+		return
+	}
+
+	log.Info().Msgf("Connection established with gossip peer: %v", peerInfo)
+}
+
+// Publish spreads the message to the topic peers, local subscribers receive it through the topic too.
+func (t *Transport) Publish(subject string, data []byte) error {
+	if t.topic == nil || t.ctx.Err() != nil {
+		return communication.ErrTransportClosed
+	}
+	if len(t.topic.ListPeers()) == 0 {
+		return ErrNoPeers
+	}
+
+	payload, err := json.Marshal(frame{Kind: framePublish, Subject: subject, Data: data})
+	if err != nil {
+		return err
+	}
+	return t.topic.Publish(t.ctx, payload)
+}
+
+// Subscribe delivers messages of matching subjects to the handler.
+func (t *Transport) Subscribe(subject string, handler communication.TransportHandler) (communication.TransportSubscription, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.nextID++
+	sub := &subscription{id: t.nextID, pattern: subject, handler: handler, transport: t}
+	t.subs[sub.id] = sub
+	return sub, nil
+}
+
+// Request asks directly connected peers one by one until one of them has a subscriber replying to it.
+func (t *Transport) Request(ctx context.Context, msg *communication.TransportMessage) (*communication.TransportMessage, error) {
+	if t.libP2PNode == nil {
+		return nil, communication.ErrTransportClosed
+	}
+
+	peers := t.libP2PNode.Network().Peers()
+	if len(peers) == 0 {
+		return nil, ErrNoPeers
+	}
+
+	request := frame{Kind: frameRequest, Subject: msg.Subject, Header: msg.Header, Data: msg.Data}
+	for _, peerID := range peers {
+		reply, err := t.request(ctx, peerID, request)
+		if ctx.Err() != nil {
+			return nil, errors.Wrap(communication.ErrRequestTimeout, ctx.Err().Error())
+		}
+		if err != nil {
+			log.Debug().Err(err).Msgf("Gossip peer %s did not answer request %q", peerID, msg.Subject)
+			continue
+		}
+		return communication.NewTransportMessage(reply.Subject, reply.Header, reply.Data, nil), nil
+	}
+
+	return nil, communication.ErrNoResponders
+}
+
+func (t *Transport) request(ctx context.Context, peerID peer.ID, request frame) (*frame, error) {
+	stream, err := t.libP2PNode.NewStream(ctx, peerID, ProtocolID)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(streamTimeout)
+	}
+	if err := stream.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if err := json.NewEncoder(stream).Encode(request); err != nil {
+		return nil, errors.Wrap(err, "failed to write request")
+	}
+
+	var reply frame
+	if err := json.NewDecoder(io.LimitReader(stream, maxFrameSize)).Decode(&reply); err != nil {
+		return nil, errors.Wrap(err, "failed to read reply")
+	}
+	if reply.Kind != frameReply {
+		return nil, communication.ErrNoResponders
+	}
+	return &reply, nil
+}
+
+func (t *Transport) receive(topicSub *pubsub.Subscription) {
+	for {
+		msg, err := topicSub.Next(t.ctx)
+		if err != nil {
+			return
+		}
+
+		var f frame
+		if err := json.Unmarshal(msg.Data, &f); err != nil || f.Kind != framePublish {
+			log.Debug().Err(err).Msgf("Ignoring malformed gossip message from %s", msg.ReceivedFrom)
+			continue
+		}
+		t.deliver(f)
+	}
+}
+
+func (t *Transport) handleStream(stream network.Stream) {
+	defer stream.Close()
+
+	if err := stream.SetDeadline(time.Now().Add(streamTimeout)); err != nil {
+		log.Warn().Err(err).Msg("Failed to set gossip stream deadline")
+		return
+	}
+
+	var request frame
+	if err := json.NewDecoder(io.LimitReader(stream, maxFrameSize)).Decode(&request); err != nil {
+		log.Debug().Err(err).Msg("Failed to read gossip request")
+		return
+	}
+	if request.Kind != frameRequest {
+		return
+	}
+
+	sub, ok := t.firstSubscription(request.Subject)
+	if !ok {
+		if err := json.NewEncoder(stream).Encode(frame{Kind: frameNoResponders}); err != nil {
+			log.Debug().Err(err).Msg("Failed to write gossip reply")
+		}
+		return
+	}
+
+	var once sync.Once
+	respond := func(data []byte) (err error) {
+		err = communication.ErrNoReply
+		once.Do(func() {
+			err = json.NewEncoder(stream).Encode(frame{Kind: frameReply, Subject: request.Subject, Data: data})
+		})
+		return err
+	}
+	sub.handler(communication.NewTransportMessage(request.Subject, request.Header, request.Data, respond))
+}
+
+func (t *Transport) deliver(f frame) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, sub := range t.subs {
+		if communication.MatchSubject(sub.pattern, f.Subject) {
+			go sub.handler(communication.NewTransportMessage(f.Subject, f.Header, f.Data, nil))
+		}
+	}
+}
+
+func (t *Transport) firstSubscription(subject string) (*subscription, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, sub := range t.subs {
+		if communication.MatchSubject(sub.pattern, subject) {
+			return sub, true
+		}
+	}
+	return nil, false
+}
+
+type subscription struct {
+	id        uint64
+	pattern   string
+	handler   communication.TransportHandler
+	transport *Transport
+}
+
+func (s *subscription) Unsubscribe() error {
+	s.transport.mu.Lock()
+	defer s.transport.mu.Unlock()
+
+	delete(s.transport.subs, s.id)
+	return nil
+}
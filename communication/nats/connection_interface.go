@@ -32,5 +32,11 @@ type Connection interface {
 	Publish(subject string, payload []byte) error
 	Subscribe(subject string, handler nats.MsgHandler) (*nats.Subscription, error)
 	Request(subject string, payload []byte, timeout time.Duration) (*nats.Msg, error)
+	RequestMsg(msg *nats.Msg, timeout time.Duration) (*nats.Msg, error)
 	RequestWithContext(ctx context.Context, subj string, data []byte) (*nats.Msg, error)
 }
+
+// closeNotifier is implemented by connections able to notify their users about being closed.
+type closeNotifier interface {
+	OnClose(hook func())
+}
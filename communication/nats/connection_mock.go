@@ -132,20 +132,29 @@ func (conn *ConnectionMock) Subscribe(subject string, handler nats.MsgHandler) (
 
 // Request sends a new request
 func (conn *ConnectionMock) Request(subject string, payload []byte, timeout time.Duration) (*nats.Msg, error) {
+	return conn.RequestMsg(&nats.Msg{Subject: subject, Data: payload}, timeout)
+}
+
+// RequestMsg sends a new request message
+func (conn *ConnectionMock) RequestMsg(msg *nats.Msg, timeout time.Duration) (*nats.Msg, error) {
 	if conn.errorMock != nil {
 		return nil, conn.errorMock
 	}
 
-	subjectReply := subject + "-reply"
-	responseCh := make(chan *nats.Msg)
+	subjectReply := msg.Subject + "-reply"
+	responseCh := make(chan *nats.Msg, 1)
 	conn.Subscribe(subjectReply, func(response *nats.Msg) {
-		responseCh <- response
+		select {
+		case responseCh <- response:
+		default:
+		}
 	})
 
 	conn.requestLast = &nats.Msg{
-		Subject: subject,
+		Subject: msg.Subject,
 		Reply:   subjectReply,
-		Data:    payload,
+		Data:    msg.Data,
+		Header:  msg.Header,
 	}
 	conn.queue <- conn.requestLast
 
@@ -153,7 +162,7 @@ func (conn *ConnectionMock) Request(subject string, payload []byte, timeout time
 	case response := <-responseCh:
 		return response, nil
 	case <-time.After(timeout):
-		return nil, errors.Errorf("request '%s' timeout", subject)
+		return nil, errors.Wrapf(nats.ErrTimeout, "request '%s'", msg.Subject)
 	}
 }
 
@@ -206,12 +215,7 @@ func (conn *ConnectionMock) Servers() []string {
 }
 
 func (conn *ConnectionMock) subscriptionAdd(subject string, handler nats.MsgHandler) {
-	subscriptions, exist := conn.subscriptions[subject]
-	if exist {
-		subscriptions = append(subscriptions, handler)
-	} else {
-		conn.subscriptions[subject] = []nats.MsgHandler{handler}
-	}
+	conn.subscriptions[subject] = append(conn.subscriptions[subject], handler)
 }
 
 func (conn *ConnectionMock) subscriptionsGet(subject string) (*[]nats.MsgHandler, bool) {
@@ -20,9 +20,11 @@ package nats
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"net"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	nats_lib "github.com/nats-io/nats.go"
@@ -37,8 +39,60 @@ const (
 	DefaultBrokerScheme = "nats"
 	// DefaultBrokerPort broker port.
 	DefaultBrokerPort = 4222
+
+	// DefaultReconnectWait is a delay before the first reconnect attempt.
+	DefaultReconnectWait = 1 * time.Second
+	// DefaultReconnectMaxWait is a maximum delay between reconnect attempts.
+	DefaultReconnectMaxWait = 30 * time.Second
+	// DefaultBufferSize is a maximum number of messages kept while disconnected.
+	DefaultBufferSize = 256
+	// DefaultBufferTTL is a time after which buffered messages are considered stale and dropped.
+	DefaultBufferTTL = 1 * time.Minute
+	// DefaultHealthInterval is how often the active broker and more preferred ones are checked.
+	DefaultHealthInterval = 15 * time.Second
+	// DefaultHealthTimeout is a deadline for a single broker health check.
+	DefaultHealthTimeout = 5 * time.Second
 )
 
+// ReconnectConfig holds broker reconnection, failover and outbound buffering settings.
+type ReconnectConfig struct {
+	Wait       time.Duration
+	MaxWait    time.Duration
+	BufferSize int
+	BufferTTL  time.Duration
+	// HealthInterval enables switching away from unresponsive broker and back to more preferred one, zero disables it.
+	HealthInterval time.Duration
+	HealthTimeout  time.Duration
+}
+
+// DefaultReconnectConfig returns default broker reconnection settings.
+func DefaultReconnectConfig() ReconnectConfig {
+	return ReconnectConfig{
+		Wait:           DefaultReconnectWait,
+		MaxWait:        DefaultReconnectMaxWait,
+		BufferSize:     DefaultBufferSize,
+		BufferTTL:      DefaultBufferTTL,
+		HealthInterval: DefaultHealthInterval,
+		HealthTimeout:  DefaultHealthTimeout,
+	}
+}
+
+// delay returns exponential backoff delay for the given reconnect attempt, capped at MaxWait.
+func (rc ReconnectConfig) delay(attempts int) time.Duration {
+	wait := rc.Wait
+	if wait <= 0 {
+		wait = DefaultReconnectWait
+	}
+	for i := 1; i < attempts && wait < rc.MaxWait; i++ {
+		wait *= 2
+	}
+	if rc.MaxWait > 0 && wait > rc.MaxWait {
+		wait = rc.MaxWait
+	}
+
+	return wait
+}
+
 // ParseServerURL validates given NATS server address.
 func ParseServerURL(serverURI string) (*url.URL, error) {
 	// Add scheme first otherwise serverURL.Parse() fails.
@@ -74,40 +128,69 @@ func ParseServerURIs(serverURIs []string) ([]*url.URL, error) {
 	return serverURLs, nil
 }
 
-func newConnection(dialer requests.DialContext, serverURIs ...string) (*ConnectionWrap, error) {
+func newConnection(dialer requests.DialContext, reconnect ReconnectConfig, serverURIs ...string) (*ConnectionWrap, error) {
+	if dialer == nil {
+		dialer = (&net.Dialer{}).DialContext
+	}
+
 	return &ConnectionWrap{
-		servers: serverURIs,
-		onClose: func() {},
-		dialer:  dialer,
+		servers:        serverURIs,
+		onClose:        func() {},
+		onServerChange: func(previous, current string) {},
+		dialer:         &trackingDialer{dialer: dialer},
+		reconnect:      reconnect,
+		buffer:         newPublishBuffer(reconnect.BufferSize, reconnect.BufferTTL),
+		stop:           make(chan struct{}),
 	}, nil
 }
 
 // ConnectionWrap defines wrapped connection to NATS server(s).
+// Servers are tried in the given order, earlier ones are preferred and connection fails back to them once healthy.
+// Messages published while the connection is down are buffered and replayed after reconnect.
 type ConnectionWrap struct {
 	*nats_lib.Conn
 
-	dialer requests.DialContext
+	dialer *trackingDialer
 
-	servers []string
-	onClose func()
+	servers        []string
+	ranks          map[string]int
+	onClose        func()
+	closeHooksMu   sync.Mutex
+	closeHooks     []func()
+	onServerChange func(previous, current string)
+	reconnect      ReconnectConfig
+	buffer         *publishBuffer
+
+	activeMu     sync.Mutex
+	activeServer string
+
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 func (c *ConnectionWrap) connectOptions() nats_lib.Options {
 	options := nats_lib.GetDefaultOptions()
 	options.Servers = c.servers
 	options.MaxReconnect = -1
-	options.ReconnectWait = 1 * time.Second
+	options.ReconnectWait = c.reconnect.Wait
+	options.CustomReconnectDelayCB = c.reconnectDelay
 	options.PingInterval = 10 * time.Second
 	options.Timeout = 10 * time.Second
 	options.RetryOnFailedConnect = true
+	// Keep preference order of servers.
+	options.NoRandomize = true
+	// Disable library buffering, messages are kept in our own buffer which survives failed reconnects.
+	options.ReconnectBufSize = -1
 
 	options.ClosedCB = func(conn *nats_lib.Conn) { log.Warn().Msg("NATS: connection closed") }
 	options.DisconnectedErrCB = func(nc *nats_lib.Conn, err error) { log.Warn().Err(err).Msg("NATS: disconnected") }
-	options.ReconnectedCB = func(nc *nats_lib.Conn) { log.Warn().Msg("NATS: reconnected") }
-
-	if c.dialer != nil {
-		options.CustomDialer = &dialer{c.dialer}
+	// Connection established after failed initial attempts is reported as reconnect too.
+	options.ReconnectedCB = func(nc *nats_lib.Conn) {
+		log.Warn().Msg("NATS: reconnected")
+		c.serverConnected(nc)
+		c.replay(nc)
 	}
+	options.CustomDialer = c.dialer
 
 	return options
 }
@@ -118,17 +201,104 @@ func (c *ConnectionWrap) Open() (err error) {
 	c.Conn, err = c.connectOptions().Connect()
 	if err != nil {
 		log.Warn().Err(err).Msgf("Failed to connect to NATS servers %v, will reconnect again", c.connectOptions().Servers)
+	} else if c.Conn.IsConnected() {
+		log.Info().Msg("NATS: connected")
+		c.serverConnected(c.Conn)
+		c.replay(c.Conn)
+	}
+
+	if c.reconnect.HealthInterval > 0 && len(c.servers) > 1 {
+		go c.watchHealth()
+	}
+
+	return nil
+}
+
+// Publish publishes the message, or buffers it until reconnect if the broker is unreachable.
+func (c *ConnectionWrap) Publish(subject string, payload []byte) error {
+	if c.Conn != nil && c.Conn.IsClosed() {
+		return nats_lib.ErrConnectionClosed
+	}
+
+	if c.Conn != nil && c.Conn.IsConnected() {
+		// Keep ordering: anything queued before must leave first.
+		c.replay(c.Conn)
+		err := c.Conn.Publish(subject, payload)
+		if !isDisconnectedErr(err) {
+			return err
+		}
+	}
+
+	if err := c.buffer.push(subject, payload); err != nil {
+		return err
+	}
+	log.Debug().Msgf("NATS: message %q buffered until reconnect", subject)
+
+	// Connection could have been restored while buffering, do not wait for the next reconnect.
+	if c.Conn != nil && c.Conn.IsConnected() {
+		c.replay(c.Conn)
 	}
 
 	return nil
 }
 
+func (c *ConnectionWrap) replay(nc *nats_lib.Conn) {
+	if c.buffer.len() == 0 {
+		return
+	}
+
+	sent, err := c.buffer.replay(nc.Publish)
+	if sent > 0 {
+		log.Info().Msgf("NATS: replayed %d buffered messages", sent)
+	}
+	if err != nil {
+		log.Warn().Err(err).Msgf("NATS: failed to replay buffered messages, %d left", c.buffer.len())
+	}
+}
+
+func (c *ConnectionWrap) reconnectDelay(attempts int) time.Duration {
+	delay := c.reconnect.delay(attempts)
+	// Spread reconnects of many nodes after a broker restart.
+	jitter := time.Duration(rand.Int63n(int64(delay)/10 + 1))
+
+	return delay + jitter
+}
+
+func isDisconnectedErr(err error) bool {
+	return errors.Is(err, nats_lib.ErrReconnectBufExceeded) || errors.Is(err, nats_lib.ErrConnectionReconnecting) ||
+		errors.Is(err, nats_lib.ErrDisconnected)
+}
+
 // Close destructs the connection.
 func (c *ConnectionWrap) Close() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
 	if c.Conn != nil {
 		c.Conn.Close()
 	}
 	c.onClose()
+
+	c.closeHooksMu.Lock()
+	hooks := c.closeHooks
+	c.closeHooks = nil
+	c.closeHooksMu.Unlock()
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+// OnClose registers a hook run once the connection is closed.
+func (c *ConnectionWrap) OnClose(hook func()) {
+	c.closeHooksMu.Lock()
+	defer c.closeHooksMu.Unlock()
+
+	c.closeHooks = append(c.closeHooks, hook)
+}
+
+// IsConnected tells whether the connection to the broker is currently established.
+func (c *ConnectionWrap) IsConnected() bool {
+	return c.Conn != nil && c.Conn.IsConnected()
 }
 
 // Servers returns list of currently connected servers.
@@ -136,13 +306,37 @@ func (c *ConnectionWrap) Servers() []string {
 	return c.servers
 }
 
-type dialer struct {
+// trackingDialer remembers the last dialed connection, so that it can be dropped to force a failover.
+type trackingDialer struct {
 	dialer requests.DialContext
+
+	mu   sync.Mutex
+	conn net.Conn
 }
 
-func (d *dialer) Dial(network, address string) (net.Conn, error) {
+func (d *trackingDialer) Dial(network, address string) (net.Conn, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), nats_lib.DefaultTimeout)
 	defer cancel()
 
-	return d.dialer(ctx, network, address)
+	conn, err := d.dialer(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.conn = conn
+	d.mu.Unlock()
+
+	return conn, nil
+}
+
+// drop closes the active connection, NATS client then reconnects starting from the most preferred server.
+func (d *trackingDialer) drop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.conn != nil {
+		d.conn.Close()
+		d.conn = nil
+	}
 }
@@ -18,8 +18,10 @@
 package nats
 
 import (
+	"context"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -63,17 +65,59 @@ func TestParseServerURL(t *testing.T) {
 }
 
 func TestConnectionWrap_NewConnection(t *testing.T) {
-	connection, err := newConnection(nil, "nats://127.0.0.1:4222")
+	connection, err := newConnection(nil, DefaultReconnectConfig(), "nats://127.0.0.1:4222")
 	assert.NoError(t, err)
 	assert.Nil(t, connection.Conn)
 	assert.Equal(t, []string{"nats://127.0.0.1:4222"}, connection.Servers())
 
-	connection, err = newConnection(nil, "nats://127.0.0.1:4222", "nats://example.com:4222")
+	connection, err = newConnection(nil, DefaultReconnectConfig(), "nats://127.0.0.1:4222", "nats://example.com:4222")
 	assert.Nil(t, connection.Conn)
 	assert.Equal(t, []string{"nats://127.0.0.1:4222", "nats://example.com:4222"}, connection.Servers())
 }
 
 func TestConnectionWrap_Servers(t *testing.T) {
-	connection, _ := newConnection(nil, "nats://far-server:1234")
+	connection, _ := newConnection(nil, DefaultReconnectConfig(), "nats://far-server:1234")
 	assert.Equal(t, []string{"nats://far-server:1234"}, connection.Servers())
 }
+
+func TestReconnectConfig_Delay(t *testing.T) {
+	config := ReconnectConfig{Wait: time.Second, MaxWait: 10 * time.Second}
+
+	assert.Equal(t, time.Second, config.delay(1))
+	assert.Equal(t, 2*time.Second, config.delay(2))
+	assert.Equal(t, 8*time.Second, config.delay(4))
+	assert.Equal(t, 10*time.Second, config.delay(5))
+	assert.Equal(t, 10*time.Second, config.delay(1000))
+}
+
+func TestBrokerConnector_ResolveServersKeepsPreference(t *testing.T) {
+	resolve := func(_ context.Context, _, addr string) ([]string, error) {
+		switch addr {
+		case "broker-1:4222":
+			return []string{"10.0.0.1:4222"}, nil
+		case "broker-2:4222":
+			return []string{"10.0.0.2:4222", "10.0.0.3:4222"}, nil
+		}
+		return nil, errors.New("unknown host")
+	}
+	connector := NewBrokerConnector(nil, resolve, DefaultReconnectConfig(), nil)
+
+	serverURLs, err := ParseServerURIs([]string{"broker-1", "broker-2"})
+	assert.NoError(t, err)
+
+	servers, ranks, err := connector.resolveServers(serverURLs)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"nats://broker-1:4222",
+		"nats://10.0.0.1:4222",
+		"nats://broker-2:4222",
+		"nats://10.0.0.2:4222",
+		"nats://10.0.0.3:4222",
+	}, servers)
+
+	connection, _ := newConnection(nil, DefaultReconnectConfig(), servers...)
+	connection.ranks = ranks
+	assert.Equal(t, 0, connection.rank("nats://10.0.0.1:4222"))
+	assert.Equal(t, 1, connection.rank("nats://10.0.0.3:4222"))
+	assert.Equal(t, len(servers), connection.rank("nats://unknown:4222"))
+}
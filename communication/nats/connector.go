@@ -26,6 +26,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
+	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/firewall"
 	"github.com/mysteriumnetwork/node/requests"
 	"github.com/mysteriumnetwork/node/requests/resolver"
@@ -37,29 +38,47 @@ type BrokerConnector struct {
 	// If ResolveContext is nil, then the transport dials using package net.
 	resolveContext resolver.ResolveContext
 
-	dialer requests.DialContext
+	dialer    requests.DialContext
+	reconnect ReconnectConfig
+	publisher eventbus.Publisher
 }
 
 // NewBrokerConnector creates a new BrokerConnector.
-func NewBrokerConnector(dialer requests.DialContext, resolveContext resolver.ResolveContext) *BrokerConnector {
+// Publisher (optional) receives AppTopicBrokerChanged events of established connections.
+func NewBrokerConnector(dialer requests.DialContext, resolveContext resolver.ResolveContext, reconnect ReconnectConfig, publisher eventbus.Publisher) *BrokerConnector {
 	return &BrokerConnector{
 		resolveContext: resolveContext,
 		dialer:         dialer,
+		reconnect:      reconnect,
+		publisher:      publisher,
 	}
 }
 
-func (b *BrokerConnector) resolveServers(serverURLs []*url.URL) ([]*url.URL, error) {
-	if b.resolveContext == nil {
-		return serverURLs, nil
+// resolveServers returns servers in preference order, each followed by its resolved addresses,
+// and ranks of them: addresses resolved from the same broker share its rank.
+func (b *BrokerConnector) resolveServers(serverURLs []*url.URL) ([]string, map[string]int, error) {
+	var servers []string
+	ranks := make(map[string]int)
+	add := func(serverURL *url.URL, rank int) {
+		server := serverURL.String()
+		if _, ok := ranks[server]; ok {
+			return
+		}
+		servers = append(servers, server)
+		ranks[server] = rank
 	}
 
-	for _, serverURL := range serverURLs {
-		ctx, cancel := context.WithTimeout(context.Background(), nats_lib.DefaultTimeout)
-		defer cancel()
+	for rank, serverURL := range serverURLs {
+		add(serverURL, rank)
+		if b.resolveContext == nil {
+			continue
+		}
 
+		ctx, cancel := context.WithTimeout(context.Background(), nats_lib.DefaultTimeout)
 		addrs, err := b.resolveContext(ctx, "tcp", serverURL.Host)
+		cancel()
 		if err != nil {
-			return nil, errors.Wrapf(err, `failed to resolve NATS server "%s"`, serverURL.Hostname())
+			return nil, nil, errors.Wrapf(err, `failed to resolve NATS server "%s"`, serverURL.Hostname())
 		}
 
 		cacheBrokerDNS(serverURL.Host, addrs)
@@ -67,36 +86,37 @@ func (b *BrokerConnector) resolveServers(serverURLs []*url.URL) ([]*url.URL, err
 		for _, addr := range addrs {
 			serverURLResolved := *serverURL
 			serverURLResolved.Host = addr
-			serverURLs = append(serverURLs, &serverURLResolved)
+			add(&serverURLResolved, rank)
 		}
 	}
 
-	return serverURLs, nil
+	return servers, ranks, nil
 }
 
 // Connect establishes a new connection to the broker(s).
 func (b *BrokerConnector) Connect(serverURLs ...*url.URL) (Connection, error) {
 	log.Debug().Msgf("Connecting to NATS servers: %v", serverURLs)
 
-	serverURLs, err := b.resolveServers(serverURLs)
+	servers, ranks, err := b.resolveServers(serverURLs)
 	if err != nil {
 		return nil, err
 	}
 
-	servers := make([]string, len(serverURLs))
-	for i, serverURL := range serverURLs {
-		servers[i] = serverURL.String()
-	}
-
 	removeFirewallRule, err := firewall.AllowURLAccess(servers...)
 	if err != nil {
 		return nil, errors.Wrapf(err, `failed to allow NATS servers "%v" in firewall`, servers)
 	}
 
-	conn, err := newConnection(b.dialer, servers...)
+	conn, err := newConnection(b.dialer, b.reconnect, servers...)
 	if err != nil {
 		return nil, err
 	}
+	conn.ranks = ranks
+	if b.publisher != nil {
+		conn.onServerChange = func(previous, current string) {
+			b.publisher.Publish(AppTopicBrokerChanged, BrokerChanged{Previous: previous, Current: current})
+		}
+	}
 
 	if err := conn.Open(); err != nil {
 		return nil, err
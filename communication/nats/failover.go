@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nats
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	nats_lib "github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// AppTopicBrokerChanged is published when the connection switches to another broker.
+const AppTopicBrokerChanged = "broker-changed"
+
+// BrokerChanged describes a switch of the active broker.
+type BrokerChanged struct {
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+}
+
+// ActiveServer returns URL of the broker the connection is currently using.
+func (c *ConnectionWrap) ActiveServer() string {
+	c.activeMu.Lock()
+	defer c.activeMu.Unlock()
+
+	return c.activeServer
+}
+
+func (c *ConnectionWrap) serverConnected(nc *nats_lib.Conn) {
+	current := nc.ConnectedUrl()
+
+	c.activeMu.Lock()
+	previous := c.activeServer
+	c.activeServer = current
+	c.activeMu.Unlock()
+
+	if previous == current {
+		return
+	}
+
+	log.Info().Msgf("NATS: active broker changed from %q to %q", previous, current)
+	c.onServerChange(previous, current)
+}
+
+// rank returns preference of the server, lower is better.
+// Addresses resolved from the same broker host share its rank.
+func (c *ConnectionWrap) rank(server string) int {
+	if rank, ok := c.ranks[server]; ok {
+		return rank
+	}
+	for i, s := range c.servers {
+		if s == server {
+			return i
+		}
+	}
+	return len(c.servers)
+}
+
+func (c *ConnectionWrap) watchHealth() {
+	ticker := time.NewTicker(c.reconnect.HealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.checkHealth()
+		}
+	}
+}
+
+// checkHealth fails over from unresponsive broker and fails back once a more preferred broker is reachable.
+func (c *ConnectionWrap) checkHealth() {
+	nc := c.Conn
+	if nc == nil || !nc.IsConnected() {
+		return
+	}
+
+	timeout := c.reconnect.HealthTimeout
+	if timeout <= 0 {
+		timeout = DefaultHealthTimeout
+	}
+
+	current := nc.ConnectedUrl()
+	if err := nc.FlushTimeout(timeout); err != nil {
+		log.Warn().Err(err).Msgf("NATS: broker %q is unresponsive, failing over", current)
+		c.dialer.drop()
+		return
+	}
+
+	currentRank := c.rank(current)
+	for _, server := range c.servers {
+		if c.rank(server) >= currentRank {
+			return
+		}
+		if c.reachable(server, timeout) {
+			log.Info().Msgf("NATS: preferred broker %q is reachable again, failing back", server)
+			c.dialer.drop()
+			return
+		}
+	}
+}
+
+func (c *ConnectionWrap) reachable(server string, timeout time.Duration) bool {
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := c.dialer.dialer(ctx, "tcp", serverURL.Host)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+
+	return true
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nats
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultIdempotencyTTL is how long responses are kept to answer retried requests.
+const defaultIdempotencyTTL = 2 * time.Minute
+
+type cachedResponse struct {
+	done      chan struct{}
+	data      []byte
+	expiresAt time.Time
+}
+
+// responseCache remembers responses by request idempotency key, so that a retried
+// request is answered with the original response instead of being processed again.
+type responseCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		entries: make(map[string]*cachedResponse),
+	}
+}
+
+// begin returns the entry for the given key and true if the caller is the first one
+// to see the key and is responsible for processing the request.
+func (rc *responseCache) begin(key string) (*cachedResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range rc.entries {
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			delete(rc.entries, k)
+		}
+	}
+
+	if entry, ok := rc.entries[key]; ok {
+		return entry, false
+	}
+
+	entry := &cachedResponse{done: make(chan struct{})}
+	rc.entries[key] = entry
+	return entry, true
+}
+
+// finish stores the response of processed request. Failed requests (nil data) are forgotten,
+// so that a retry gets processed again.
+func (rc *responseCache) finish(key string, entry *cachedResponse, data []byte) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if data == nil {
+		delete(rc.entries, key)
+	} else {
+		entry.data = data
+		entry.expiresAt = time.Now().Add(rc.ttl)
+	}
+	close(entry.done)
+}
@@ -90,3 +90,20 @@ func TestMessageBytesReceive(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []byte("123"), *message.(*[]byte))
 }
+
+func TestMessageBytesReceiveWithTopic(t *testing.T) {
+	connection := StartConnectionMock()
+	defer connection.Close()
+
+	receiver := NewReceiver(connection, communication.NewCodecBytes(), "custom")
+	defer receiver.Stop()
+
+	consumer := &bytesMessageConsumer{messageReceived: make(chan interface{})}
+	err := receiver.Receive(consumer)
+	assert.NoError(t, err)
+
+	connection.Publish("custom.bytes-message", []byte("123"))
+	message, err := connection.MessageWait(consumer.messageReceived)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("123"), *message.(*[]byte))
+}
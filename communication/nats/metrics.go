@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nats
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/mysteriumnetwork/node/metrics"
+)
+
+type poolCollector struct {
+	stats func() ReceiverStats
+
+	queued        *prometheus.Desc
+	inFlight      *prometheus.Desc
+	processed     *prometheus.Desc
+	backpressured *prometheus.Desc
+	dropped       *prometheus.Desc
+}
+
+// NewStatsCollector exposes worker pool statistics as metrics labelled with the given pool name.
+func NewStatsCollector(name string, stats func() ReceiverStats) prometheus.Collector {
+	labels := prometheus.Labels{"pool": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "broker", metric), help, nil, labels)
+	}
+
+	return &poolCollector{
+		stats:         stats,
+		queued:        desc("queued_messages", "Number of broker messages waiting for a free worker."),
+		inFlight:      desc("inflight_messages", "Number of broker messages currently being consumed."),
+		processed:     desc("processed_messages_total", "Total number of consumed broker messages."),
+		backpressured: desc("backpressured_messages_total", "Total number of broker messages which found the worker queue full."),
+		dropped:       desc("dropped_messages_total", "Total number of broker messages dropped without being consumed."),
+	}
+}
+
+// Describe sends descriptors of the pool metrics.
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.queued
+	ch <- c.inFlight
+	ch <- c.processed
+	ch <- c.backpressured
+	ch <- c.dropped
+}
+
+// Collect sends current values of the pool metrics.
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.queued, prometheus.GaugeValue, float64(stats.Queued))
+	ch <- prometheus.MustNewConstMetric(c.inFlight, prometheus.GaugeValue, float64(stats.InFlight))
+	ch <- prometheus.MustNewConstMetric(c.processed, prometheus.CounterValue, float64(stats.Processed))
+	ch <- prometheus.MustNewConstMetric(c.backpressured, prometheus.CounterValue, float64(stats.Backpressured))
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(stats.Dropped))
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrPublishBufferFull is returned when a message is published while disconnected and the outbound buffer is full.
+var ErrPublishBufferFull = errors.New("NATS publish buffer is full")
+
+type bufferedMessage struct {
+	subject  string
+	payload  []byte
+	queuedAt time.Time
+}
+
+// publishBuffer keeps messages published while the broker is unreachable
+// and replays them in the original order once the connection is back.
+type publishBuffer struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu       sync.Mutex
+	messages []bufferedMessage
+}
+
+func newPublishBuffer(size int, ttl time.Duration) *publishBuffer {
+	return &publishBuffer{
+		size: size,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+func (pb *publishBuffer) push(subject string, payload []byte) error {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	pb.dropExpired()
+	if len(pb.messages) >= pb.size {
+		return ErrPublishBufferFull
+	}
+
+	// Caller may reuse the payload slice after Publish returns.
+	data := make([]byte, len(payload))
+	copy(data, payload)
+	pb.messages = append(pb.messages, bufferedMessage{subject: subject, payload: data, queuedAt: pb.now()})
+
+	return nil
+}
+
+func (pb *publishBuffer) len() int {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	return len(pb.messages)
+}
+
+// replay publishes buffered messages in order. Replay stops on the first failure
+// and keeps the failed and remaining messages for the next attempt.
+func (pb *publishBuffer) replay(publish func(subject string, payload []byte) error) (int, error) {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	pb.dropExpired()
+	sent := 0
+	for _, msg := range pb.messages {
+		if err := publish(msg.subject, msg.payload); err != nil {
+			pb.messages = pb.messages[sent:]
+			return sent, err
+		}
+		sent++
+	}
+	pb.messages = nil
+
+	return sent, nil
+}
+
+func (pb *publishBuffer) dropExpired() {
+	if pb.ttl <= 0 {
+		return
+	}
+
+	now := pb.now()
+	expired := 0
+	for _, msg := range pb.messages {
+		if now.Sub(msg.queuedAt) <= pb.ttl {
+			break
+		}
+		expired++
+	}
+	if expired > 0 {
+		log.Warn().Msgf("NATS: dropping %d buffered messages older than %s", expired, pb.ttl)
+		pb.messages = pb.messages[expired:]
+	}
+}
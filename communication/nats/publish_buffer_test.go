@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type publishedMessage struct {
+	subject string
+	payload string
+}
+
+func TestPublishBuffer_ReplaysInOrder(t *testing.T) {
+	buffer := newPublishBuffer(10, time.Minute)
+	payload := []byte("first")
+	assert.NoError(t, buffer.push("a", payload))
+	payload[0] = 'F'
+	assert.NoError(t, buffer.push("b", []byte("second")))
+
+	var published []publishedMessage
+	sent, err := buffer.replay(func(subject string, payload []byte) error {
+		published = append(published, publishedMessage{subject, string(payload)})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, []publishedMessage{{"a", "first"}, {"b", "second"}}, published)
+	assert.Equal(t, 0, buffer.len())
+}
+
+func TestPublishBuffer_KeepsMessagesOnFailedReplay(t *testing.T) {
+	buffer := newPublishBuffer(10, time.Minute)
+	assert.NoError(t, buffer.push("a", []byte("1")))
+	assert.NoError(t, buffer.push("b", []byte("2")))
+	assert.NoError(t, buffer.push("c", []byte("3")))
+
+	sent, err := buffer.replay(func(subject string, _ []byte) error {
+		if subject == "b" {
+			return errors.New("disconnected")
+		}
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 2, buffer.len())
+
+	var subjects []string
+	_, err = buffer.replay(func(subject string, _ []byte) error {
+		subjects = append(subjects, subject)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, subjects)
+}
+
+func TestPublishBuffer_Limits(t *testing.T) {
+	now := time.Now()
+	buffer := newPublishBuffer(2, time.Minute)
+	buffer.now = func() time.Time { return now }
+
+	assert.NoError(t, buffer.push("a", nil))
+	assert.NoError(t, buffer.push("b", nil))
+	assert.Equal(t, ErrPublishBufferFull, buffer.push("c", nil))
+
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, buffer.push("d", nil))
+	assert.Equal(t, 1, buffer.len())
+}
@@ -28,18 +28,37 @@ import (
 
 // NewReceiver constructs new Receiver's instance which works through NATS connection.
 // Codec packs/unpacks messages to byte payloads.
-// Topic (optional) if need to send messages prefixed topic.
+// Topic (optional) if need to receive messages prefixed topic.
 func NewReceiver(connection Connection, codec communication.Codec, topic string) *receiverNATS {
-	return &receiverNATS{
-		connection: connection,
-		codec:      codec,
-		subs:       make(map[string]*nats.Subscription),
+	return NewReceiverWithConfig(connection, codec, topic, DefaultReceiverConfig())
+}
+
+// NewReceiverWithConfig constructs Receiver which consumes messages using a bounded worker pool.
+// The pool is stopped together with the connection, when the connection supports close hooks.
+func NewReceiverWithConfig(connection Connection, codec communication.Codec, topic string, config ReceiverConfig) *receiverNATS {
+	receiver := &receiverNATS{
+		connection:   connection,
+		codec:        codec,
+		messageTopic: topic + ".",
+		subs:         make(map[string]*nats.Subscription),
+		responses:    newResponseCache(defaultIdempotencyTTL),
+		pool:         newWorkerPool(config),
+	}
+	if topic == "" {
+		receiver.messageTopic = ""
+	}
+	if notifier, ok := connection.(closeNotifier); ok {
+		notifier.OnClose(receiver.Stop)
 	}
+	return receiver
 }
 
 type receiverNATS struct {
-	connection Connection
-	codec      communication.Codec
+	connection   Connection
+	codec        communication.Codec
+	messageTopic string
+	responses    *responseCache
+	pool         *workerPool
 
 	mu   sync.Mutex
 	subs map[string]*nats.Subscription
@@ -50,9 +69,9 @@ func (receiver *receiverNATS) Receive(consumer communication.MessageConsumer) er
 	if err != nil {
 		return err
 	}
-	messageTopic := string(messageEndpoint)
+	messageTopic := receiver.messageTopic + string(messageEndpoint)
 
-	messageHandler := func(msg *nats.Msg) {
+	consume := func(msg *nats.Msg) {
 		log.WithLevel(levelFor(messageTopic)).Msgf("Message %q received: %s", messageTopic, msg.Data)
 		messagePtr := consumer.NewMessage()
 		err := receiver.codec.Unpack(msg.Data, messagePtr)
@@ -69,6 +88,9 @@ func (receiver *receiverNATS) Receive(consumer communication.MessageConsumer) er
 			return
 		}
 	}
+	messageHandler := func(msg *nats.Msg) {
+		receiver.dispatch(messageTopic, func() { consume(msg) })
+	}
 
 	receiver.mu.Lock()
 	defer receiver.mu.Unlock()
@@ -86,7 +108,7 @@ func (receiver *receiverNATS) ReceiveUnsubscribe(endpoint communication.MessageE
 	receiver.mu.Lock()
 	defer receiver.mu.Unlock()
 
-	messageTopic := string(endpoint)
+	messageTopic := receiver.messageTopic + string(endpoint)
 	subscription, found := receiver.subs[messageTopic]
 	if !found {
 		log.Error().Msg("Unknown topic to unsubscribe: " + messageTopic)
@@ -119,40 +141,65 @@ func (receiver *receiverNATS) Respond(consumer communication.RequestConsumer) er
 	if err != nil {
 		return err
 	}
-	requestTopic := string(requestEndpoint)
+	requestTopic := receiver.messageTopic + string(requestEndpoint)
 
-	messageHandler := func(msg *nats.Msg) {
+	process := func(msg *nats.Msg) []byte {
 		log.WithLevel(levelFor(requestTopic)).Msgf("Request %q received: %s", requestTopic, msg.Data)
 		requestPtr := consumer.NewRequest()
 		err := receiver.codec.Unpack(msg.Data, requestPtr)
 		if err != nil {
 			err = errors.Wrapf(err, "failed to unpack request '%s'", requestTopic)
 			log.Error().Err(err).Msg("")
-			return
+			return nil
 		}
 
 		response, err := consumer.Consume(requestPtr)
 		if err != nil {
 			err = errors.Wrapf(err, "failed to process request '%s'", requestTopic)
 			log.Error().Err(err).Msg("")
-			return
+			return nil
 		}
 
 		responseData, err := receiver.codec.Pack(response)
 		if err != nil {
 			err = errors.Wrapf(err, "failed to pack response '%s'", requestTopic)
 			log.Error().Err(err).Msg("")
+			return nil
+		}
+
+		return responseData
+	}
+
+	respond := func(msg *nats.Msg) {
+		var responseData []byte
+		if key := msg.Header.Get(IdempotencyKeyHeader); key != "" {
+			entry, first := receiver.responses.begin(key)
+			if first {
+				responseData = process(msg)
+				receiver.responses.finish(key, entry, responseData)
+			} else {
+				log.Debug().Msgf("Request %q is a retry of %s, replying with the original response", requestTopic, key)
+				<-entry.done
+				responseData = entry.data
+			}
+		} else {
+			responseData = process(msg)
+		}
+		if responseData == nil {
 			return
 		}
 
 		log.Debug().Msgf("Request %q response: %s", requestTopic, responseData)
-		err = receiver.connection.Publish(msg.Reply, responseData)
+		err := receiver.connection.Publish(msg.Reply, responseData)
 		if err != nil {
 			err = errors.Wrapf(err, "failed to send response '%s'", requestTopic)
 			log.Error().Err(err).Msg("")
 			return
 		}
 	}
+	messageHandler := func(msg *nats.Msg) {
+		receiver.dispatch(requestTopic, func() { respond(msg) })
+	}
 
 	receiver.mu.Lock()
 	defer receiver.mu.Unlock()
@@ -173,3 +220,28 @@ func (receiver *receiverNATS) Respond(consumer communication.RequestConsumer) er
 	receiver.subs[requestTopic] = subscription
 	return nil
 }
+
+// Stats returns load statistics of the receiver worker pool.
+func (receiver *receiverNATS) Stats() ReceiverStats {
+	if receiver.pool == nil {
+		return ReceiverStats{}
+	}
+	return receiver.pool.stats()
+}
+
+// Stop stops the worker pool, messages received afterwards are dropped.
+func (receiver *receiverNATS) Stop() {
+	if receiver.pool != nil {
+		receiver.pool.stop()
+	}
+}
+
+// dispatch hands the job over to the worker pool, or runs it inline on the
+// subscription callback when the receiver was built without one.
+func (receiver *receiverNATS) dispatch(topic string, job func()) {
+	if receiver.pool == nil {
+		job()
+		return
+	}
+	receiver.pool.submit(topic, job)
+}
@@ -31,13 +31,12 @@ func TestReceiverNew(t *testing.T) {
 	connection := &ConnectionMock{}
 	codec := communication.NewCodecFake()
 
-	assert.Equal(
-		t,
-		&receiverNATS{
-			connection: connection,
-			codec:      codec,
-			subs:       make(map[string]*nats.Subscription),
-		},
-		NewReceiver(connection, codec, "custom"),
-	)
+	receiver := NewReceiver(connection, codec, "custom")
+	assert.Equal(t, connection, receiver.connection)
+	assert.Equal(t, codec, receiver.codec)
+	assert.Equal(t, make(map[string]*nats.Subscription), receiver.subs)
+	assert.Equal(t, newResponseCache(defaultIdempotencyTTL), receiver.responses)
+	assert.Equal(t, "custom.", receiver.messageTopic)
+	assert.Len(t, receiver.pool.queues, DefaultReceiverConfig().Concurrency)
+	assert.Equal(t, DefaultReceiverConfig().QueueSize, cap(receiver.pool.queues[0]))
 }
@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nats
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/communication"
+)
+
+type retriedRequestProducer struct {
+	customRequestProducer
+	options communication.RequestOptions
+}
+
+func (producer *retriedRequestProducer) RequestOptions() communication.RequestOptions {
+	return producer.options
+}
+
+func TestRequest_RetriesWithSameIdempotencyKey(t *testing.T) {
+	connection := StartConnectionMock()
+	defer connection.Close()
+
+	var attempts int32
+	var keysMu sync.Mutex
+	var keys []string
+	connection.Subscribe("custom-request", func(msg *nats.Msg) {
+		keysMu.Lock()
+		keys = append(keys, msg.Header.Get(IdempotencyKeyHeader))
+		keysMu.Unlock()
+
+		// Provider is unresponsive on the first attempt.
+		if atomic.AddInt32(&attempts, 1) > 1 {
+			connection.Publish(msg.Reply, []byte(`{"FieldOut": "RESPONSE"}`))
+		}
+	})
+
+	sender := NewSender(connection, communication.NewCodecJSON())
+	response, err := sender.Request(&retriedRequestProducer{
+		customRequestProducer: customRequestProducer{&customRequest{"REQUEST"}},
+		options:               communication.RequestOptions{Timeout: 50 * time.Millisecond, Retries: 2},
+	})
+	assert.NoError(t, err)
+	assert.Exactly(t, customResponse{"RESPONSE"}, *response.(*customResponse))
+
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	assert.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+}
+
+func TestRequest_FailsWithTimeoutAfterRetries(t *testing.T) {
+	connection := StartConnectionMock()
+	defer connection.Close()
+
+	sender := NewSender(connection, communication.NewCodecJSON())
+	_, err := sender.Request(&retriedRequestProducer{
+		customRequestProducer: customRequestProducer{&customRequest{"REQUEST"}},
+		options:               communication.RequestOptions{Timeout: 10 * time.Millisecond, Retries: 1},
+	})
+	assert.True(t, errors.Is(err, communication.ErrRequestTimeout))
+}
+
+type countingRequestConsumer struct {
+	customRequestConsumer
+	consumed int32
+}
+
+func (consumer *countingRequestConsumer) Consume(requestPtr interface{}) (responsePtr interface{}, err error) {
+	n := atomic.AddInt32(&consumer.consumed, 1)
+	if n == 1 {
+		return &customResponse{"FIRST"}, nil
+	}
+	return &customResponse{"SECOND"}, nil
+}
+
+func TestRespond_RepliesToRetriedRequestWithOriginalResponse(t *testing.T) {
+	connection := StartConnectionMock()
+	defer connection.Close()
+
+	receiver := NewReceiver(connection, communication.NewCodecJSON(), "")
+	consumer := &countingRequestConsumer{}
+	assert.NoError(t, receiver.Respond(consumer))
+
+	request := &nats.Msg{Subject: "custom-response", Data: []byte(`{"FieldIn": "REQUEST"}`), Header: nats.Header{}}
+	request.Header.Set(IdempotencyKeyHeader, "key-1")
+
+	first, err := connection.RequestMsg(request, 100*time.Millisecond)
+	assert.NoError(t, err)
+	retried, err := connection.RequestMsg(request, 100*time.Millisecond)
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, `{"FieldOut": "FIRST"}`, string(first.Data))
+	assert.JSONEq(t, `{"FieldOut": "FIRST"}`, string(retried.Data))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&consumer.consumed))
+}
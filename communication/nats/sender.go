@@ -20,11 +20,17 @@ package nats
 import (
 	"time"
 
-	"github.com/mysteriumnetwork/node/communication"
+	"github.com/gofrs/uuid"
+	"github.com/nats-io/nats.go"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/communication"
 )
 
+// IdempotencyKeyHeader is a message header carrying the key which identifies retried requests.
+const IdempotencyKeyHeader = communication.IdempotencyKeyHeader
+
 // NewSender constructs new Sender's instance which works thru NATS connection.
 // Codec packs/unpacks messages to byte payloads.
 // Topic (optional) if need to send messages prefixed topic.
@@ -80,8 +86,15 @@ func (sender *senderNATS) Request(producer communication.RequestProducer) (respo
 		return
 	}
 
+	options := sender.requestOptions(producer)
+	request := &nats.Msg{Subject: requestTopic, Data: requestData}
+	if options.IdempotencyKey != "" {
+		request.Header = nats.Header{}
+		request.Header.Set(IdempotencyKeyHeader, options.IdempotencyKey)
+	}
+
 	log.WithLevel(levelFor(requestTopic)).Msgf("Request %q sending: %s", requestTopic, requestData)
-	msg, err := sender.connection.Request(requestTopic, requestData, sender.timeoutRequest)
+	msg, err := sender.request(request, options)
 	if err != nil {
 		err = errors.Wrapf(err, "failed to send request '%s'", requestTopic)
 		return
@@ -97,3 +110,43 @@ func (sender *senderNATS) Request(producer communication.RequestProducer) (respo
 
 	return responsePtr, nil
 }
+
+func (sender *senderNATS) requestOptions(producer communication.RequestProducer) communication.RequestOptions {
+	options := communication.RequestOptions{Timeout: sender.timeoutRequest}
+	if optionsProducer, ok := producer.(communication.RequestOptionsProducer); ok {
+		options = optionsProducer.RequestOptions()
+	}
+
+	if options.Timeout <= 0 {
+		options.Timeout = sender.timeoutRequest
+	}
+	if options.Retries < 0 {
+		options.Retries = 0
+	}
+	if options.Retries > 0 && options.IdempotencyKey == "" {
+		if uid, err := uuid.NewV4(); err == nil {
+			options.IdempotencyKey = uid.String()
+		}
+	}
+
+	return options
+}
+
+// request sends the request and retries it if the response does not arrive in time.
+// Only timeouts are retried, any other failure (e.g. no responders) is returned immediately.
+func (sender *senderNATS) request(request *nats.Msg, options communication.RequestOptions) (*nats.Msg, error) {
+	for attempt := 0; ; attempt++ {
+		msg, err := sender.connection.RequestMsg(request, options.Timeout)
+		if err == nil {
+			return msg, nil
+		}
+		if !errors.Is(err, nats.ErrTimeout) {
+			return nil, err
+		}
+		if attempt >= options.Retries {
+			return nil, errors.Wrapf(communication.ErrRequestTimeout, "no response in %d attempt(s) of %s", attempt+1, options.Timeout)
+		}
+
+		log.Debug().Err(err).Msgf("Request %q attempt %d timed out, retrying", request.Subject, attempt+1)
+	}
+}
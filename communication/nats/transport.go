@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nats
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+
+	"github.com/mysteriumnetwork/node/communication"
+)
+
+const defaultTransportTimeout = 10 * time.Second
+
+// NewTransport adapts NATS connection to communication.Transport.
+func NewTransport(connection Connection) *transportNATS {
+	return NewTransportWithConfig(connection, DefaultReceiverConfig())
+}
+
+// NewTransportWithConfig adapts NATS connection to communication.Transport,
+// subscription handlers are run by a bounded worker pool keeping per-subject order.
+func NewTransportWithConfig(connection Connection, config ReceiverConfig) *transportNATS {
+	t := &transportNATS{
+		connection: connection,
+		pool:       newWorkerPool(config),
+	}
+	if notifier, ok := connection.(closeNotifier); ok {
+		notifier.OnClose(t.Close)
+	}
+	return t
+}
+
+type transportNATS struct {
+	connection Connection
+	pool       *workerPool
+}
+
+func (t *transportNATS) Publish(subject string, data []byte) error {
+	return t.connection.Publish(subject, data)
+}
+
+func (t *transportNATS) Subscribe(subject string, handler communication.TransportHandler) (communication.TransportSubscription, error) {
+	sub, err := t.connection.Subscribe(subject, func(msg *nats.Msg) {
+		var respond func(data []byte) error
+		if msg.Reply != "" {
+			reply := msg.Reply
+			respond = func(data []byte) error {
+				return t.connection.Publish(reply, data)
+			}
+		}
+		message := communication.NewTransportMessage(msg.Subject, headerToMap(msg.Header), msg.Data, respond)
+		t.pool.submit(msg.Subject, func() { handler(message) })
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (t *transportNATS) Request(ctx context.Context, msg *communication.TransportMessage) (*communication.TransportMessage, error) {
+	timeout := defaultTransportTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	request := &nats.Msg{Subject: msg.Subject, Data: msg.Data}
+	if len(msg.Header) > 0 {
+		request.Header = nats.Header{}
+		for key, value := range msg.Header {
+			request.Header.Set(key, value)
+		}
+	}
+
+	reply, err := t.connection.RequestMsg(request, timeout)
+	switch {
+	case errors.Is(err, nats.ErrTimeout):
+		return nil, errors.Wrap(communication.ErrRequestTimeout, err.Error())
+	case errors.Is(err, nats.ErrNoResponders):
+		return nil, errors.Wrap(communication.ErrNoResponders, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return communication.NewTransportMessage(reply.Subject, headerToMap(reply.Header), reply.Data, nil), nil
+}
+
+// Stats returns load statistics of the subscription worker pool.
+func (t *transportNATS) Stats() ReceiverStats {
+	return t.pool.stats()
+}
+
+// Close stops the subscription worker pool, the connection is closed by its owner.
+func (t *transportNATS) Close() {
+	t.pool.stop()
+}
+
+func headerToMap(header nats.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(header))
+	for key := range header {
+		result[key] = header.Get(key)
+	}
+	return result
+}
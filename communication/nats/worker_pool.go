@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nats
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ReceiverConfig bounds how dialog messages are handled by the receiver.
+type ReceiverConfig struct {
+	// Concurrency is the number of workers running message and request consumers.
+	// Messages of the same subject are always consumed by the same worker, in the order received.
+	Concurrency int
+	// QueueSize is the number of received messages waiting for each worker.
+	QueueSize int
+	// QueueTimeout is how long a message waits for a place in the full queue before it is dropped.
+	QueueTimeout time.Duration
+}
+
+// DefaultReceiverConfig returns receiver configuration used by NewReceiver.
+func DefaultReceiverConfig() ReceiverConfig {
+	return ReceiverConfig{
+		Concurrency:  16,
+		QueueSize:    256,
+		QueueTimeout: time.Second,
+	}
+}
+
+// ReceiverStats describes the load of the receiver worker pool.
+type ReceiverStats struct {
+	// Queued is the number of messages waiting for a free worker.
+	Queued int
+	// InFlight is the number of messages currently being consumed.
+	InFlight int64
+	// Processed is the total number of consumed messages.
+	Processed uint64
+	// Backpressured is the total number of messages which found the queue full.
+	Backpressured uint64
+	// Dropped is the total number of messages dropped after waiting for the full queue or after the pool was stopped.
+	Dropped uint64
+}
+
+// workerPool runs consumers outside of NATS subscription callbacks,
+// so that a slow consumer holds a single worker instead of the whole subscription.
+// Each subject is pinned to one worker queue to keep its messages in order.
+type workerPool struct {
+	queueTimeout time.Duration
+	queues       []chan func()
+
+	once    sync.Once
+	mu      sync.RWMutex
+	stopped bool
+	workers sync.WaitGroup
+
+	inFlight      int64
+	processed     uint64
+	backpressured uint64
+	dropped       uint64
+}
+
+func newWorkerPool(config ReceiverConfig) *workerPool {
+	if config.Concurrency < 1 {
+		config.Concurrency = 1
+	}
+	if config.QueueSize < 0 {
+		config.QueueSize = 0
+	}
+
+	queues := make([]chan func(), config.Concurrency)
+	for i := range queues {
+		queues[i] = make(chan func(), config.QueueSize)
+	}
+
+	return &workerPool{
+		queueTimeout: config.QueueTimeout,
+		queues:       queues,
+	}
+}
+
+// submit queues the job for the worker owning the topic. When its queue is full submit blocks
+// for up to the queue timeout and drops the job if no place frees up.
+func (wp *workerPool) submit(topic string, job func()) bool {
+	wp.once.Do(wp.start)
+
+	wp.mu.RLock()
+	defer wp.mu.RUnlock()
+
+	if wp.stopped {
+		atomic.AddUint64(&wp.dropped, 1)
+		log.Warn().Msgf("Receiver is stopped, dropping message %q", topic)
+		return false
+	}
+
+	queue := wp.queueOf(topic)
+	select {
+	case queue <- job:
+		return true
+	default:
+	}
+
+	atomic.AddUint64(&wp.backpressured, 1)
+	timer := time.NewTimer(wp.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case queue <- job:
+		return true
+	case <-timer.C:
+		atomic.AddUint64(&wp.dropped, 1)
+		log.Warn().Msgf("Receiver queue is full, dropping message %q", topic)
+		return false
+	}
+}
+
+// stop rejects further jobs, lets the workers finish the queued ones and waits for them to exit.
+func (wp *workerPool) stop() {
+	wp.once.Do(wp.start)
+
+	wp.mu.Lock()
+	if wp.stopped {
+		wp.mu.Unlock()
+		return
+	}
+	wp.stopped = true
+	for _, queue := range wp.queues {
+		close(queue)
+	}
+	wp.mu.Unlock()
+
+	wp.workers.Wait()
+}
+
+func (wp *workerPool) queueOf(topic string) chan func() {
+	hash := fnv.New32a()
+	hash.Write([]byte(topic))
+	return wp.queues[hash.Sum32()%uint32(len(wp.queues))]
+}
+
+func (wp *workerPool) start() {
+	wp.workers.Add(len(wp.queues))
+	for _, queue := range wp.queues {
+		go wp.work(queue)
+	}
+}
+
+func (wp *workerPool) work(queue chan func()) {
+	defer wp.workers.Done()
+
+	for job := range queue {
+		atomic.AddInt64(&wp.inFlight, 1)
+		job()
+		atomic.AddInt64(&wp.inFlight, -1)
+		atomic.AddUint64(&wp.processed, 1)
+	}
+}
+
+func (wp *workerPool) stats() ReceiverStats {
+	queued := 0
+	for _, queue := range wp.queues {
+		queued += len(queue)
+	}
+
+	return ReceiverStats{
+		Queued:        queued,
+		InFlight:      atomic.LoadInt64(&wp.inFlight),
+		Processed:     atomic.LoadUint64(&wp.processed),
+		Backpressured: atomic.LoadUint64(&wp.backpressured),
+		Dropped:       atomic.LoadUint64(&wp.dropped),
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nats
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerPool_SlowJobDoesNotBlockOthers(t *testing.T) {
+	pool := newWorkerPool(ReceiverConfig{Concurrency: 2, QueueSize: 1, QueueTimeout: time.Second})
+
+	release := make(chan struct{})
+	defer close(release)
+	assert.True(t, pool.submit("slow", func() { <-release }))
+
+	done := make(chan struct{})
+	assert.True(t, pool.submit("fast", func() { close(done) }))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job was stalled by a slow one")
+	}
+}
+
+func TestWorkerPool_DropsWhenQueueStaysFull(t *testing.T) {
+	pool := newWorkerPool(ReceiverConfig{Concurrency: 1, QueueSize: 1, QueueTimeout: 10 * time.Millisecond})
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	assert.True(t, pool.submit("slow", func() {
+		close(started)
+		<-release
+	}))
+	<-started
+	assert.True(t, pool.submit("queued", func() {}))
+	assert.False(t, pool.submit("dropped", func() {}))
+
+	stats := pool.stats()
+	assert.Equal(t, 1, stats.Queued)
+	assert.Equal(t, int64(1), stats.InFlight)
+	assert.Equal(t, uint64(1), stats.Backpressured)
+	assert.Equal(t, uint64(1), stats.Dropped)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return pool.stats().Processed == 2
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestWorkerPool_WaitsForFreeQueueSlot(t *testing.T) {
+	pool := newWorkerPool(ReceiverConfig{Concurrency: 1, QueueSize: 1, QueueTimeout: time.Second})
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool.submit("slow", func() {
+		close(started)
+		<-release
+	})
+	<-started
+	pool.submit("queued", func() {})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	assert.True(t, pool.submit("waiting", func() {}))
+	assert.Equal(t, uint64(1), pool.stats().Backpressured)
+	assert.Equal(t, uint64(0), pool.stats().Dropped)
+}
+
+func TestWorkerPool_KeepsSubjectOrder(t *testing.T) {
+	pool := newWorkerPool(ReceiverConfig{Concurrency: 4, QueueSize: 100, QueueTimeout: time.Second})
+	defer pool.stop()
+
+	var mu sync.Mutex
+	received := make(map[string][]int)
+	for i := 0; i < 100; i++ {
+		for _, topic := range []string{"a", "b", "c", "d"} {
+			topic, i := topic, i
+			assert.True(t, pool.submit(topic, func() {
+				mu.Lock()
+				received[topic] = append(received[topic], i)
+				mu.Unlock()
+			}))
+		}
+	}
+
+	assert.Eventually(t, func() bool {
+		return pool.stats().Processed == 400
+	}, time.Second, 5*time.Millisecond)
+	for topic, order := range received {
+		assert.IsIncreasing(t, order, topic)
+	}
+}
+
+func TestWorkerPool_StopFinishesQueuedAndDropsLater(t *testing.T) {
+	pool := newWorkerPool(ReceiverConfig{Concurrency: 1, QueueSize: 2, QueueTimeout: time.Second})
+
+	var processed int32
+	for i := 0; i < 2; i++ {
+		assert.True(t, pool.submit("queued", func() { atomic.AddInt32(&processed, 1) }))
+	}
+	pool.stop()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&processed))
+	assert.False(t, pool.submit("late", func() {}))
+	assert.Equal(t, uint64(1), pool.stats().Dropped)
+	pool.stop()
+}
@@ -17,6 +17,34 @@
 
 package communication
 
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// IdempotencyKeyHeader is a message header carrying the key which identifies retried requests.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// ErrRequestTimeout is returned when no response is received within the request deadline, including all retries.
+var ErrRequestTimeout = errors.New("request timed out")
+
+// RequestOptions defines delivery settings of a single request.
+type RequestOptions struct {
+	// Timeout is a deadline for a single attempt to receive the response.
+	Timeout time.Duration
+	// Retries is a number of additional attempts made when the response does not arrive in time.
+	Retries int
+	// IdempotencyKey lets the receiver recognise retried requests and reply with the same response
+	// instead of processing the request again. Generated by sender when retries are enabled and the key is empty.
+	IdempotencyKey string
+}
+
+// RequestOptionsProducer is implemented by request producers which need other than default delivery settings.
+type RequestOptionsProducer interface {
+	RequestOptions() RequestOptions
+}
+
 // RequestEndpoint is special type that describes unique requests endpoint
 type RequestEndpoint string
 
@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package communication
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TransportNATS exchanges messages through the NATS broker.
+	TransportNATS = "nats"
+	// TransportLibP2P exchanges messages peer-to-peer over libp2p GossipSub alongside NATS, requests fall back to NATS.
+	TransportLibP2P = "libp2p"
+)
+
+var (
+	// ErrNoResponders is returned when nobody is subscribed to the request subject.
+	ErrNoResponders = errors.New("no responders available for request")
+	// ErrTransportClosed is returned when the transport is used after it was closed.
+	ErrTransportClosed = errors.New("transport closed")
+	// ErrNoReply is returned when replying to a message which was not a request.
+	ErrNoReply = errors.New("message does not expect a reply")
+)
+
+// TransportMessage is a payload delivered by Transport to a subject.
+type TransportMessage struct {
+	Subject string
+	Header  map[string]string
+	Data    []byte
+
+	respond func(data []byte) error
+}
+
+// NewTransportMessage creates a message which is answered with the given respond function.
+// Respond is nil for messages which do not expect a reply.
+func NewTransportMessage(subject string, header map[string]string, data []byte, respond func(data []byte) error) *TransportMessage {
+	return &TransportMessage{
+		Subject: subject,
+		Header:  header,
+		Data:    data,
+		respond: respond,
+	}
+}
+
+// Respond replies to the request message.
+func (m *TransportMessage) Respond(data []byte) error {
+	if m.respond == nil {
+		return ErrNoReply
+	}
+	return m.respond(data)
+}
+
+// TransportHandler handles messages received from a subscribed subject.
+type TransportHandler func(msg *TransportMessage)
+
+// TransportSubscription is an active subscription to a subject.
+type TransportSubscription interface {
+	Unsubscribe() error
+}
+
+// Transport delivers raw messages between nodes by subject.
+// Subjects are dot separated, "*" matches a single token and ">" matches all remaining tokens.
+type Transport interface {
+	// Publish sends a message to all subscribers of the subject.
+	Publish(subject string, data []byte) error
+	// Subscribe starts delivering messages of the subject to the handler.
+	Subscribe(subject string, handler TransportHandler) (TransportSubscription, error)
+	// Request sends a message to one of subscribers and waits for its reply until the context is done.
+	// ErrRequestTimeout is returned if the reply does not arrive in time.
+	Request(ctx context.Context, msg *TransportMessage) (*TransportMessage, error)
+	// Close stops the transport.
+	Close()
+}
+
+// MatchSubject checks whether the subject matches subscription pattern.
+func MatchSubject(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) {
+			return false
+		}
+		if token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
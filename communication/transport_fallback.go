@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package communication
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// duplicateTTL is how long a published message is remembered to drop its copies arriving through other transports.
+const duplicateTTL = 30 * time.Second
+
+// NewFallbackTransport creates transport which publishes through all of the given transports,
+// requests through the first one succeeding in the given order and receives messages from all of them.
+// Copies of the same message received through several transports are delivered once.
+func NewFallbackTransport(transports ...Transport) *fallbackTransport {
+	return &fallbackTransport{transports: transports}
+}
+
+type fallbackTransport struct {
+	transports []Transport
+}
+
+// Publish sends the message through every transport and fails only if none of them took it.
+func (ft *fallbackTransport) Publish(subject string, data []byte) (err error) {
+	delivered := false
+	for _, transport := range ft.transports {
+		if publishErr := transport.Publish(subject, data); publishErr != nil {
+			log.Debug().Err(publishErr).Msgf("Failed to publish %q through one of transports", subject)
+			err = publishErr
+			continue
+		}
+		delivered = true
+	}
+	if delivered {
+		return nil
+	}
+	return err
+}
+
+func (ft *fallbackTransport) Subscribe(subject string, handler TransportHandler) (TransportSubscription, error) {
+	filter := newDuplicateFilter(duplicateTTL)
+	deduplicated := func(msg *TransportMessage) {
+		// Requests are sent through a single transport, only published messages come in copies.
+		if msg.respond == nil && filter.seen(msg.Subject, msg.Data) {
+			return
+		}
+		handler(msg)
+	}
+
+	subs := make(fallbackSubscription, 0, len(ft.transports))
+	for _, transport := range ft.transports {
+		sub, err := transport.Subscribe(subject, deduplicated)
+		if err != nil {
+			subs.Unsubscribe()
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+func (ft *fallbackTransport) Request(ctx context.Context, msg *TransportMessage) (reply *TransportMessage, err error) {
+	for i, transport := range ft.transports {
+		if reply, err = transport.Request(ctx, msg); err == nil {
+			return reply, nil
+		}
+		if ctx.Err() != nil {
+			return nil, errors.Wrap(ErrRequestTimeout, ctx.Err().Error())
+		}
+		if i < len(ft.transports)-1 {
+			log.Debug().Err(err).Msgf("Request %q failed, falling back to the next transport", msg.Subject)
+		}
+	}
+	return nil, err
+}
+
+func (ft *fallbackTransport) Close() {
+	for _, transport := range ft.transports {
+		transport.Close()
+	}
+}
+
+type fallbackSubscription []TransportSubscription
+
+func (fs fallbackSubscription) Unsubscribe() (err error) {
+	for _, sub := range fs {
+		if unsubErr := sub.Unsubscribe(); unsubErr != nil {
+			err = unsubErr
+		}
+	}
+	return err
+}
+
+type duplicateFilter struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	received  map[[sha256.Size]byte]time.Time
+	lastPurge time.Time
+}
+
+func newDuplicateFilter(ttl time.Duration) *duplicateFilter {
+	return &duplicateFilter{
+		ttl:      ttl,
+		received: make(map[[sha256.Size]byte]time.Time),
+	}
+}
+
+// seen remembers the message and reports whether the same one was already received within TTL.
+func (df *duplicateFilter) seen(subject string, data []byte) bool {
+	hash := sha256.New()
+	hash.Write([]byte(subject))
+	hash.Write([]byte{0})
+	hash.Write(data)
+	var key [sha256.Size]byte
+	copy(key[:], hash.Sum(nil))
+
+	df.mu.Lock()
+	defer df.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(df.lastPurge) > df.ttl {
+		for k, receivedAt := range df.received {
+			if now.Sub(receivedAt) > df.ttl {
+				delete(df.received, k)
+			}
+		}
+		df.lastPurge = now
+	}
+
+	if receivedAt, ok := df.received[key]; ok && now.Sub(receivedAt) <= df.ttl {
+		return true
+	}
+	df.received[key] = now
+	return false
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package communication
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// NewTransportReceiver constructs Receiver which works through any Transport.
+// Codec packs/unpacks messages to byte payloads.
+func NewTransportReceiver(transport Transport, codec Codec) *transportReceiver {
+	return &transportReceiver{
+		transport: transport,
+		codec:     codec,
+		subs:      make(map[string]TransportSubscription),
+	}
+}
+
+type transportReceiver struct {
+	transport Transport
+	codec     Codec
+
+	mu   sync.Mutex
+	subs map[string]TransportSubscription
+}
+
+func (receiver *transportReceiver) Receive(consumer MessageConsumer) error {
+	messageEndpoint, err := consumer.GetMessageEndpoint()
+	if err != nil {
+		return err
+	}
+	messageTopic := string(messageEndpoint)
+
+	return receiver.subscribe(messageTopic, func(msg *TransportMessage) {
+		log.Trace().Msgf("Message %q received: %s", messageTopic, msg.Data)
+		messagePtr := consumer.NewMessage()
+		if err := receiver.codec.Unpack(msg.Data, messagePtr); err != nil {
+			log.Error().Err(err).Msgf("Failed to unpack message %q", messageTopic)
+			return
+		}
+
+		if err := consumer.Consume(messagePtr); err != nil {
+			log.Error().Err(err).Msgf("Failed to process message %q", messageTopic)
+		}
+	})
+}
+
+func (receiver *transportReceiver) ReceiveUnsubscribe(endpoint MessageEndpoint) {
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+
+	messageTopic := string(endpoint)
+	subscription, found := receiver.subs[messageTopic]
+	if !found {
+		log.Error().Msg("Unknown topic to unsubscribe: " + messageTopic)
+		return
+	}
+
+	if err := subscription.Unsubscribe(); err != nil {
+		log.Error().Err(err).Msg("Failed to unsubscribe from topic: " + messageTopic)
+		return
+	}
+	delete(receiver.subs, messageTopic)
+
+	log.Info().Msg("Unsubscribed from " + messageTopic)
+}
+
+func (receiver *transportReceiver) Unsubscribe() {
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+
+	for topic, subscription := range receiver.subs {
+		if err := subscription.Unsubscribe(); err != nil {
+			log.Error().Err(err).Msg("Failed to unsubscribe from topic: " + topic)
+			continue
+		}
+		delete(receiver.subs, topic)
+		log.Info().Msg("Unsubscribed from " + topic)
+	}
+}
+
+func (receiver *transportReceiver) Respond(consumer RequestConsumer) error {
+	requestEndpoint, err := consumer.GetRequestEndpoint()
+	if err != nil {
+		return err
+	}
+	requestTopic := string(requestEndpoint)
+
+	return receiver.subscribe(requestTopic, func(msg *TransportMessage) {
+		log.Trace().Msgf("Request %q received: %s", requestTopic, msg.Data)
+		requestPtr := consumer.NewRequest()
+		if err := receiver.codec.Unpack(msg.Data, requestPtr); err != nil {
+			log.Error().Err(err).Msgf("Failed to unpack request %q", requestTopic)
+			return
+		}
+
+		response, err := consumer.Consume(requestPtr)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to process request %q", requestTopic)
+			return
+		}
+
+		responseData, err := receiver.codec.Pack(response)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to pack response %q", requestTopic)
+			return
+		}
+
+		if err := msg.Respond(responseData); err != nil {
+			log.Error().Err(err).Msgf("Failed to send response %q", requestTopic)
+		}
+	})
+}
+
+func (receiver *transportReceiver) subscribe(topic string, handler TransportHandler) error {
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+
+	if _, ok := receiver.subs[topic]; ok {
+		log.Debug().Msg("Already subscribed to topic: " + topic)
+		return nil
+	}
+
+	subscription, err := receiver.transport.Subscribe(topic, handler)
+	if err != nil {
+		return errors.Wrapf(err, "failed to subscribe '%s'", topic)
+	}
+	receiver.subs[topic] = subscription
+	return nil
+}
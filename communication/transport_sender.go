@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package communication
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultTransportRequestTimeout = 10 * time.Second
+
+// NewTransportSender constructs Sender which works through any Transport.
+// Codec packs/unpacks messages to byte payloads.
+func NewTransportSender(transport Transport, codec Codec) *transportSender {
+	return &transportSender{
+		transport:      transport,
+		codec:          codec,
+		timeoutRequest: defaultTransportRequestTimeout,
+	}
+}
+
+type transportSender struct {
+	transport      Transport
+	codec          Codec
+	timeoutRequest time.Duration
+}
+
+func (sender *transportSender) Send(producer MessageProducer) error {
+	messageEndpoint, err := producer.GetMessageEndpoint()
+	if err != nil {
+		return err
+	}
+	messageTopic := string(messageEndpoint)
+
+	messageData, err := sender.codec.Pack(producer.Produce())
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode message '%s'", messageTopic)
+	}
+
+	log.Trace().Msgf("Message %q sending: %s", messageTopic, messageData)
+	if err := sender.transport.Publish(messageTopic, messageData); err != nil {
+		return errors.Wrapf(err, "failed to send message '%s'", messageTopic)
+	}
+
+	return nil
+}
+
+func (sender *transportSender) Request(producer RequestProducer) (responsePtr interface{}, err error) {
+	requestEndpoint, err := producer.GetRequestEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	requestTopic := string(requestEndpoint)
+
+	requestData, err := sender.codec.Pack(producer.Produce())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to pack request '%s'", requestTopic)
+	}
+
+	options := RequestOptions{Timeout: sender.timeoutRequest}
+	if optionsProducer, ok := producer.(RequestOptionsProducer); ok {
+		options = optionsProducer.RequestOptions()
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = sender.timeoutRequest
+	}
+
+	var header map[string]string
+	if options.IdempotencyKey != "" {
+		header = map[string]string{IdempotencyKeyHeader: options.IdempotencyKey}
+	}
+	request := NewTransportMessage(requestTopic, header, requestData, nil)
+
+	log.Trace().Msgf("Request %q sending: %s", requestTopic, requestData)
+	var reply *TransportMessage
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), options.Timeout)
+		reply, err = sender.transport.Request(ctx, request)
+		cancel()
+		if err == nil || !errors.Is(err, ErrRequestTimeout) || attempt >= options.Retries {
+			break
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to send request '%s'", requestTopic)
+	}
+
+	responsePtr = producer.NewResponse()
+	if err := sender.codec.Unpack(reply.Data, responsePtr); err != nil {
+		return nil, errors.Wrapf(err, "failed to unpack response '%s'", requestTopic)
+	}
+
+	return responsePtr, nil
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package communication
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchSubject(t *testing.T) {
+	tests := []struct {
+		pattern string
+		subject string
+		match   bool
+	}{
+		{"proposal-unregister.v3", "proposal-unregister.v3", true},
+		{"*.proposal-register.v3", "0x1.proposal-register.v3", true},
+		{"*.proposal-register.v3", "proposal-register.v3", false},
+		{"*.proposal-register.v3", "0x1.proposal-ping.v3", false},
+		{"0x1.>", "0x1.proposal-ping.v3", true},
+		{"0x1.>", "0x1", false},
+		{"a.b", "a.b.c", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, MatchSubject(tt.pattern, tt.subject), tt.pattern+" ~ "+tt.subject)
+	}
+}
+
+type transportFake struct {
+	err       error
+	published []string
+	subjects  []string
+	handler   TransportHandler
+	reply     []byte
+	closed    bool
+}
+
+func (tf *transportFake) Publish(subject string, _ []byte) error {
+	if tf.err != nil {
+		return tf.err
+	}
+	tf.published = append(tf.published, subject)
+	return nil
+}
+
+func (tf *transportFake) Subscribe(subject string, handler TransportHandler) (TransportSubscription, error) {
+	tf.subjects = append(tf.subjects, subject)
+	tf.handler = handler
+	return tf, nil
+}
+
+func (tf *transportFake) Unsubscribe() error {
+	tf.subjects = nil
+	return nil
+}
+
+func (tf *transportFake) Request(_ context.Context, msg *TransportMessage) (*TransportMessage, error) {
+	if tf.err != nil {
+		return nil, tf.err
+	}
+	return NewTransportMessage(msg.Subject, nil, tf.reply, nil), nil
+}
+
+func (tf *transportFake) Close() {
+	tf.closed = true
+}
+
+func TestFallbackTransport(t *testing.T) {
+	primary := &transportFake{err: errors.New("no peers")}
+	fallback := &transportFake{reply: []byte("pong")}
+	transport := NewFallbackTransport(primary, fallback)
+
+	assert.NoError(t, transport.Publish("ping", nil))
+	assert.Empty(t, primary.published)
+	assert.Equal(t, []string{"ping"}, fallback.published)
+
+	reply, err := transport.Request(context.Background(), NewTransportMessage("ping", nil, nil, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("pong"), reply.Data)
+
+	sub, err := transport.Subscribe("*.ping", func(*TransportMessage) {})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"*.ping"}, primary.subjects)
+	assert.Equal(t, []string{"*.ping"}, fallback.subjects)
+	assert.NoError(t, sub.Unsubscribe())
+	assert.Empty(t, primary.subjects)
+
+	primary.err = nil
+	assert.NoError(t, transport.Publish("ping", nil))
+	assert.Equal(t, []string{"ping"}, primary.published)
+	assert.Len(t, fallback.published, 2)
+
+	fallback.err = errors.New("disconnected")
+	assert.NoError(t, transport.Publish("ping", nil))
+	primary.err = errors.New("no peers")
+	assert.Error(t, transport.Publish("ping", nil))
+
+	transport.Close()
+	assert.True(t, primary.closed)
+	assert.True(t, fallback.closed)
+
+	assert.EqualError(t, NewTransportMessage("ping", nil, nil, nil).Respond(nil), ErrNoReply.Error())
+}
+
+func TestFallbackTransport_DeliversCopiesOnce(t *testing.T) {
+	primary := &transportFake{}
+	fallback := &transportFake{}
+	transport := NewFallbackTransport(primary, fallback)
+
+	var received []string
+	_, err := transport.Subscribe("ping", func(msg *TransportMessage) {
+		received = append(received, string(msg.Data))
+	})
+	assert.NoError(t, err)
+
+	primary.handler(NewTransportMessage("ping", nil, []byte("1"), nil))
+	fallback.handler(NewTransportMessage("ping", nil, []byte("1"), nil))
+	fallback.handler(NewTransportMessage("ping", nil, []byte("2"), nil))
+	primary.handler(NewTransportMessage("ping", nil, []byte("2"), nil))
+	assert.Equal(t, []string{"1", "2"}, received)
+
+	respond := func([]byte) error { return nil }
+	primary.handler(NewTransportMessage("ping", nil, []byte("1"), respond))
+	assert.Equal(t, []string{"1", "2", "1"}, received)
+}
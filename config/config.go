@@ -23,6 +23,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/BurntSushi/toml"
 	"github.com/mysteriumnetwork/node/eventbus"
@@ -34,17 +35,30 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
-// Config stores application configuration in 3 separate maps (listed from the lowest priority to the highest):
+// Config stores application configuration in separate maps (listed from the lowest priority to the highest):
 //
 // • Default values
 //
+// • Configuration profile
+//
 // • User configuration (config.toml)
 //
+// • Remote configuration (HTTP or etcd endpoint)
+//
+// • Declarative configuration file (YAML)
+//
+// • Environment variables (MYST_ prefixed, see EnvName)
+//
 // • CLI flags
 type Config struct {
 	userConfigLocation string
 	defaults           map[string]interface{}
+	profile            map[string]interface{}
+	profileName        string
 	user               map[string]interface{}
+	remote             map[string]interface{}
+	file               map[string]interface{}
+	env                map[string]string
 	cli                map[string]interface{}
 	eventBus           eventbus.EventBus
 	mu                 sync.RWMutex
@@ -58,11 +72,41 @@ func NewConfig() *Config {
 	return &Config{
 		userConfigLocation: "",
 		defaults:           make(map[string]interface{}),
+		profile:            make(map[string]interface{}),
 		user:               make(map[string]interface{}),
+		remote:             make(map[string]interface{}),
+		file:               make(map[string]interface{}),
+		env:                make(map[string]string),
 		cli:                make(map[string]interface{}),
 	}
 }
 
+// EnvPrefix is the prefix of environment variables holding configuration values.
+const EnvPrefix = "MYST_"
+
+var envNameReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// EnvName returns the name of the environment variable holding the value for key,
+// e.g. MYST_PAYMENTS_PRICE_GIB for payments.price-gib.
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(envNameReplacer.Replace(key))
+}
+
+// LoadEnv remembers configuration values given by environment variables, in os.Environ format.
+func (cfg *Config) LoadEnv(environ []string) {
+	env := make(map[string]string)
+	for _, variable := range environ {
+		parts := strings.SplitN(variable, "=", 2)
+		if len(parts) == 2 && strings.HasPrefix(parts[0], EnvPrefix) {
+			env[parts[0]] = parts[1]
+		}
+	}
+
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	cfg.env = env
+}
+
 func (cfg *Config) userConfigLoaded() bool {
 	cfg.mu.RLock()
 	defer cfg.mu.RUnlock()
@@ -133,17 +177,48 @@ func (cfg *Config) GetUserConfig() map[string]interface{} {
 	return deepCopyStrMap(cfg.user)
 }
 
+// GetRemoteConfig returns configuration fetched from the remote configuration endpoint.
+func (cfg *Config) GetRemoteConfig() map[string]interface{} {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return deepCopyStrMap(cfg.remote)
+}
+
+// GetFileConfig returns configuration loaded from the declarative configuration file.
+func (cfg *Config) GetFileConfig() map[string]interface{} {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return deepCopyStrMap(cfg.file)
+}
+
 // GetConfig returns current configuration.
 func (cfg *Config) GetConfig() map[string]interface{} {
 	cfg.mu.RLock()
 	defer cfg.mu.RUnlock()
 	config := make(map[string]interface{})
 	mergeMaps(deepCopyStrMap(cfg.defaults), config, nil)
+	mergeMaps(deepCopyStrMap(cfg.profile), config, nil)
 	mergeMaps(deepCopyStrMap(cfg.user), config, nil)
+	mergeMaps(deepCopyStrMap(cfg.remote), config, nil)
+	mergeMaps(deepCopyStrMap(cfg.file), config, nil)
+	cfg.overrideByEnv(config, "")
 	mergeMaps(deepCopyStrMap(cfg.cli), config, nil)
 	return deepCopyStrMap(config)
 }
 
+// overrideByEnv replaces values of known keys with the ones given by environment variables.
+func (cfg *Config) overrideByEnv(config map[string]interface{}, prefix string) {
+	for key, value := range config {
+		if nested, ok := value.(map[string]interface{}); ok {
+			cfg.overrideByEnv(nested, prefix+key+".")
+			continue
+		}
+		if envValue, ok := cfg.env[EnvName(prefix+key)]; ok {
+			config[key] = envValue
+		}
+	}
+}
+
 // SetDefault sets default value for key.
 func (cfg *Config) SetDefault(key string, value interface{}) {
 	cfg.set(cfg.defaults, key, value)
@@ -161,6 +236,18 @@ func (cfg *Config) SetUser(key string, value interface{}) {
 	cfg.set(cfg.user, key, value)
 }
 
+// SetFile sets declarative configuration file value for key.
+func (cfg *Config) SetFile(key string, value interface{}) {
+	cfg.set(cfg.file, key, value)
+	cfg.publish(key)
+}
+
+// SetRemote sets remote configuration value for key.
+func (cfg *Config) SetRemote(key string, value interface{}) {
+	cfg.set(cfg.remote, key, value)
+	cfg.publish(key)
+}
+
 // SetCLI sets value passed via CLI flag for key.
 func (cfg *Config) SetCLI(key string, value interface{}) {
 	cfg.set(cfg.cli, key, value)
@@ -171,11 +258,35 @@ func (cfg *Config) RemoveUser(key string) {
 	cfg.remove(cfg.user, key)
 }
 
+// RemoveFile removes declarative configuration file value for key.
+// Listeners are notified about the value the key falls back to.
+func (cfg *Config) RemoveFile(key string) {
+	cfg.remove(cfg.file, key)
+	cfg.publish(key)
+}
+
+// RemoveRemote removes remote configuration value for key.
+// Listeners are notified about the value the key falls back to.
+func (cfg *Config) RemoveRemote(key string) {
+	cfg.remove(cfg.remote, key)
+	cfg.publish(key)
+}
+
 // RemoveCLI removes configured CLI flag value by key.
 func (cfg *Config) RemoveCLI(key string) {
 	cfg.remove(cfg.cli, key)
 }
 
+// publish notifies event bus listeners about the current value of key.
+func (cfg *Config) publish(key string) {
+	cfg.mu.RLock()
+	eventBus := cfg.eventBus
+	cfg.mu.RUnlock()
+	if eventBus != nil {
+		eventBus.Publish(AppTopicConfig(key), cfg.Get(key))
+	}
+}
+
 // set sets value to a particular configuration value map.
 func (cfg *Config) set(configMap map[string]interface{}, key string, value interface{}) {
 	key = strings.ToLower(key)
@@ -216,11 +327,30 @@ func (cfg *Config) Get(key string) interface{} {
 		log.Debug().Msgf("Returning CLI value %v:%v", key, cliValue)
 		return copyValue(cliValue)
 	}
+	if envValue, ok := cfg.env[EnvName(key)]; ok {
+		log.Debug().Msgf("Returning environment value %v:%v", key, envValue)
+		return envValue
+	}
+	fileValue := SearchMap(cfg.file, segments)
+	if fileValue != nil {
+		log.Debug().Msgf("Returning config file value %v:%v", key, fileValue)
+		return copyValue(fileValue)
+	}
+	remoteValue := SearchMap(cfg.remote, segments)
+	if remoteValue != nil {
+		log.Debug().Msgf("Returning remote config value %v:%v", key, remoteValue)
+		return copyValue(remoteValue)
+	}
 	userValue := SearchMap(cfg.user, segments)
 	if userValue != nil {
 		log.Debug().Msgf("Returning user config value %v:%v", key, userValue)
 		return copyValue(userValue)
 	}
+	profileValue := SearchMap(cfg.profile, segments)
+	if profileValue != nil {
+		log.Debug().Msgf("Returning profile value %v:%v", key, profileValue)
+		return copyValue(profileValue)
+	}
 	defaultValue := SearchMap(cfg.defaults, segments)
 	log.Trace().Msgf("Returning default value %v:%v", key, defaultValue)
 	return copyValue(defaultValue)
@@ -286,8 +416,15 @@ func (cfg *Config) GetString(key string) string {
 }
 
 // GetStringSlice returns config value as []string.
+// String values, e.g. given by environment variables, are split by commas and spaces.
 func (cfg *Config) GetStringSlice(key string) []string {
-	return cast.ToStringSlice(cfg.Get(key))
+	value := cfg.Get(key)
+	if s, ok := value.(string); ok {
+		return strings.FieldsFunc(s, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+	}
+	return cast.ToStringSlice(value)
 }
 
 // ParseBoolFlag parses a cli.BoolFlag from command's context and
@@ -378,6 +515,33 @@ func (cfg *Config) ParseStringSliceFlag(ctx *cli.Context, flag cli.StringSliceFl
 	}
 }
 
+// SetDefaultsFromFlags sets default values of the given flags to the application configuration,
+// for nodes configured without parsing command line.
+func (cfg *Config) SetDefaultsFromFlags(flags []cli.Flag) {
+	for _, f := range flags {
+		switch flag := f.(type) {
+		case *cli.BoolFlag:
+			cfg.SetDefault(flag.Name, flag.Value)
+		case *cli.IntFlag:
+			cfg.SetDefault(flag.Name, flag.Value)
+		case *cli.Uint64Flag:
+			cfg.SetDefault(flag.Name, flag.Value)
+		case *cli.Int64Flag:
+			cfg.SetDefault(flag.Name, flag.Value)
+		case *cli.Float64Flag:
+			cfg.SetDefault(flag.Name, flag.Value)
+		case *cli.DurationFlag:
+			cfg.SetDefault(flag.Name, flag.Value)
+		case *cli.StringFlag:
+			cfg.SetDefault(flag.Name, flag.Value)
+		case *cli.StringSliceFlag:
+			if flag.Value != nil {
+				cfg.SetDefault(flag.Name, flag.Value.Value())
+			}
+		}
+	}
+}
+
 // ParseBlockchainNetworkFlag parses a cli.StringFlag as a blockchain network
 // from command's context and sets default values for network parameters
 // and CLI values for the network to the application configuration.
@@ -23,6 +23,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli/v2"
@@ -81,6 +82,69 @@ func TestUserConfig_Save(t *testing.T) {
 	assert.NotContains(t, string(tomlContent), `proto = "tcp"`)
 }
 
+func TestFileConfig_Priority(t *testing.T) {
+	cfg := NewConfig()
+	cfg.SetDefault("openvpn.port", 55)
+	cfg.SetUser("openvpn.port", 22822)
+
+	// when: declarative file value is set
+	cfg.SetFile("openvpn.port", 30000)
+	// then: it is prioritized over user value
+	assert.Equal(t, 30000, cfg.GetInt("openvpn.port"))
+	assert.Equal(t, map[string]interface{}{"openvpn": map[string]interface{}{"port": 30000}}, cfg.GetFileConfig())
+
+	// when: CLI value is set
+	cfg.SetCLI("openvpn.port", 40000)
+	// then: it is prioritized over declarative file value
+	assert.Equal(t, 40000, cfg.GetInt("openvpn.port"))
+
+	// when: file and CLI values are removed
+	cfg.RemoveCLI("openvpn.port")
+	cfg.RemoveFile("openvpn.port")
+	// then: user value is used again
+	assert.Equal(t, 22822, cfg.GetInt("openvpn.port"))
+}
+
+func TestEnvAndRemoteConfig_Priority(t *testing.T) {
+	cfg := NewConfig()
+	cfg.SetDefault("openvpn.port", 55)
+	cfg.SetUser("openvpn.port", 22822)
+
+	// when: remote value is set
+	cfg.SetRemote("openvpn.port", 25000)
+	// then: it is prioritized over user value
+	assert.Equal(t, 25000, cfg.GetInt("openvpn.port"))
+
+	// when: declarative file value is set
+	cfg.SetFile("openvpn.port", 30000)
+	// then: it is prioritized over remote value
+	assert.Equal(t, 30000, cfg.GetInt("openvpn.port"))
+
+	// when: environment variable is set
+	cfg.LoadEnv([]string{"MYST_OPENVPN_PORT=35000", "MYST_ACCESS_POLICY_LIST=mysterium, custom", "PATH=/bin"})
+	// then: it is prioritized over declarative file value
+	assert.Equal(t, 35000, cfg.GetInt("openvpn.port"))
+	assert.Equal(t, []string{"mysterium", "custom"}, cfg.GetStringSlice("access-policy.list"))
+	assert.Equal(t, map[string]interface{}{"port": "35000"}, cfg.GetConfig()["openvpn"])
+
+	// when: CLI value is set
+	cfg.SetCLI("openvpn.port", 40000)
+	// then: it is prioritized over environment variable
+	assert.Equal(t, 40000, cfg.GetInt("openvpn.port"))
+
+	// when: all but remote values are removed
+	cfg.RemoveCLI("openvpn.port")
+	cfg.LoadEnv(nil)
+	cfg.RemoveFile("openvpn.port")
+	// then: remote value is used again
+	assert.Equal(t, 25000, cfg.GetInt("openvpn.port"))
+	assert.Equal(t, map[string]interface{}{"openvpn": map[string]interface{}{"port": 25000}}, cfg.GetRemoteConfig())
+}
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "MYST_PAYMENTS_PRICE_GIB", EnvName("payments.price-gib"))
+}
+
 func NewTempFileName(t *testing.T) string {
 	file, err := ioutil.TempFile("", "*")
 	assert.NoError(t, err)
@@ -143,6 +207,26 @@ func TestConfig_ParseStringSliceFlag(t *testing.T) {
 	}
 }
 
+func TestConfig_SetDefaultsFromFlags(t *testing.T) {
+	// given
+	cfg := NewConfig()
+	flags := []cli.Flag{
+		&cli.IntFlag{Name: "service.max-sessions", Value: 2},
+		&cli.DurationFlag{Name: "monitoring.interval", Value: time.Minute},
+		&cli.StringSliceFlag{Name: "dns.upstream", Value: cli.NewStringSlice("1.1.1.1")},
+		&cli.StringSliceFlag{Name: "p2p.relays"},
+	}
+
+	// when
+	cfg.SetDefaultsFromFlags(flags)
+
+	// then
+	assert.Equal(t, 2, cfg.GetInt("service.max-sessions"))
+	assert.Equal(t, time.Minute, cfg.GetDuration("monitoring.interval"))
+	assert.Equal(t, []string{"1.1.1.1"}, cfg.GetStringSlice("dns.upstream"))
+	assert.Nil(t, cfg.Get("p2p.relays"))
+}
+
 // this can happen when updating config via tequilapi - json unmarshal
 // translates json number to float64 by default if target type is interface{}
 func TestSimilarTypeMerge(t *testing.T) {
@@ -0,0 +1,304 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package declarative
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mysteriumnetwork/node/config"
+)
+
+// File is a declarative node configuration, e.g.
+//
+//	services:
+//	  - type: wireguard
+//	    access-policies: [mysterium]
+//	  - type: scraping
+//	    identities: ["0x..."]
+//	pricing:
+//	  price-gib: 0.1
+//	  price-hour: 0.00006
+//	payments:
+//	  promise-threshold: 0.1
+//	access-policies:
+//	  consumer-blocklist: [/etc/mysterium/blocklist.txt]
+//	api:
+//	  address: 127.0.0.1
+//	  port: 4050
+//	settings:
+//	  shaper.enabled: true
+type File struct {
+	Services       []Service      `yaml:"services"`
+	Pricing        Pricing        `yaml:"pricing"`
+	Payments       Payments       `yaml:"payments"`
+	AccessPolicies AccessPolicies `yaml:"access-policies"`
+	API            API            `yaml:"api"`
+	// Settings holds any other configuration values by their flag names.
+	Settings map[string]interface{} `yaml:"settings"`
+}
+
+// Service describes a service to provide.
+type Service struct {
+	Type string `yaml:"type"`
+	// Identities the service runs under, the main provider identity is used if empty.
+	Identities []string `yaml:"identities"`
+	// AccessPolicies of the service, supported by wireguard, openvpn and noop services only.
+	AccessPolicies []string `yaml:"access-policies"`
+}
+
+// Pricing holds prices of provided services in MYST.
+type Pricing struct {
+	PricePerGiB  *float64 `yaml:"price-gib"`
+	PricePerHour *float64 `yaml:"price-hour"`
+}
+
+// Payments holds payment and settlement thresholds.
+type Payments struct {
+	PromiseThreshold         *float64 `yaml:"promise-threshold"`
+	ZeroStakeUnsettledAmount *float64 `yaml:"zero-stake-unsettled-amount"`
+	SettleMaxFeePercentage   *float64 `yaml:"settle-max-fee-percentage"`
+	UnsettledMaxAmount       *float64 `yaml:"unsettled-max-amount"`
+}
+
+// AccessPolicies holds access policies applied to all services.
+type AccessPolicies struct {
+	List              []string `yaml:"list"`
+	ConsumerAllowlist []string `yaml:"consumer-allowlist"`
+	ConsumerBlocklist []string `yaml:"consumer-blocklist"`
+}
+
+// API holds TequilAPI settings.
+type API struct {
+	Address          *string  `yaml:"address"`
+	Port             *int     `yaml:"port"`
+	AllowedHostnames []string `yaml:"allowed-hostnames"`
+	RateLimits       []string `yaml:"rate-limits"`
+	Auth             APIAuth  `yaml:"auth"`
+}
+
+// APIAuth holds TequilAPI authentication settings.
+type APIAuth struct {
+	Required *bool   `yaml:"required"`
+	Username *string `yaml:"username"`
+	Password *string `yaml:"password"`
+}
+
+// policyServiceTypes are service types with configurable access policies.
+var policyServiceTypes = map[string]string{
+	"wireguard": config.FlagWireguardAccessPolicies.Name,
+	"openvpn":   config.FlagOpenVPNAccessPolicies.Name,
+	"noop":      config.FlagNoopAccessPolicies.Name,
+}
+
+// restartKeys are prefixes of values read once on the node start.
+// They are applied to the configuration on reload, but take effect after the node restart only.
+var restartKeys = []string{"tequilapi.", "access-policy.consumer-"}
+
+// Load reads and validates declarative configuration file.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read configuration file")
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates declarative configuration.
+func Parse(data []byte) (*File, error) {
+	file := &File{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(file); err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "could not parse configuration file")
+	}
+	if err := file.Validate(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Validate checks the configuration for mistakes which can't be caught by parsing.
+func (f *File) Validate() error {
+	seen := make(map[string]bool)
+	for i, s := range f.Services {
+		if s.Type == "" {
+			return fmt.Errorf("service #%d: type is required", i+1)
+		}
+		if seen[s.Type] {
+			return fmt.Errorf("service %s is listed more than once", s.Type)
+		}
+		seen[s.Type] = true
+		if _, ok := policyServiceTypes[s.Type]; !ok && len(s.AccessPolicies) > 0 {
+			return fmt.Errorf("service %s: access policies can not be configured", s.Type)
+		}
+		for _, id := range s.Identities {
+			if strings.Contains(id, "=") || strings.Contains(id, ",") || strings.TrimSpace(id) == "" {
+				return fmt.Errorf("service %s: invalid identity %q", s.Type, id)
+			}
+		}
+	}
+
+	for _, price := range []*float64{f.Pricing.PricePerGiB, f.Pricing.PricePerHour} {
+		if price != nil && *price < 0 {
+			return errors.New("pricing: prices can not be negative")
+		}
+	}
+	if f.API.Port != nil && (*f.API.Port < 1 || *f.API.Port > 65535) {
+		return fmt.Errorf("api: invalid port %d", *f.API.Port)
+	}
+
+	typed := f.typedValues()
+	for key := range f.Settings {
+		if _, ok := typed[strings.ToLower(key)]; ok {
+			return fmt.Errorf("settings: %s is configured by a dedicated section", key)
+		}
+	}
+	return nil
+}
+
+// Values returns configuration values by their flag names.
+func (f *File) Values() map[string]interface{} {
+	values := make(map[string]interface{})
+	for key, value := range f.Settings {
+		values[strings.ToLower(key)] = value
+	}
+	for key, value := range f.typedValues() {
+		values[key] = value
+	}
+	return values
+}
+
+func (f *File) typedValues() map[string]interface{} {
+	values := make(map[string]interface{})
+	setFloat := func(key string, value *float64) {
+		if value != nil {
+			values[key] = *value
+		}
+	}
+	setString := func(key string, value *string) {
+		if value != nil {
+			values[key] = *value
+		}
+	}
+	setSlice := func(key string, value []string) {
+		if value != nil {
+			values[key] = value
+		}
+	}
+
+	if len(f.Services) > 0 {
+		var types, bindings []string
+		for _, s := range f.Services {
+			types = append(types, s.Type)
+			for _, id := range s.Identities {
+				bindings = append(bindings, s.Type+"="+id)
+			}
+			if key, ok := policyServiceTypes[s.Type]; ok && s.AccessPolicies != nil {
+				values[key] = strings.Join(s.AccessPolicies, ",")
+			}
+		}
+		values[config.FlagActiveServices.Name] = strings.Join(types, ",")
+		values[config.FlagServiceIdentities.Name] = append([]string{}, bindings...)
+	}
+
+	setFloat(config.FlagPaymentPriceGiB.Name, f.Pricing.PricePerGiB)
+	setFloat(config.FlagPaymentPriceHour.Name, f.Pricing.PricePerHour)
+
+	setFloat(config.FlagPaymentsHermesPromiseSettleThreshold.Name, f.Payments.PromiseThreshold)
+	setFloat(config.FlagPaymentsZeroStakeUnsettledAmount.Name, f.Payments.ZeroStakeUnsettledAmount)
+	setFloat(config.FlagPaymentsPromiseSettleMaxFeeThreshold.Name, f.Payments.SettleMaxFeePercentage)
+	setFloat(config.FlagPaymentsUnsettledMaxAmount.Name, f.Payments.UnsettledMaxAmount)
+
+	if f.AccessPolicies.List != nil {
+		values[config.FlagAccessPolicyList.Name] = strings.Join(f.AccessPolicies.List, ",")
+	}
+	setSlice(config.FlagAccessPolicyConsumerAllowlist.Name, f.AccessPolicies.ConsumerAllowlist)
+	setSlice(config.FlagAccessPolicyConsumerBlocklist.Name, f.AccessPolicies.ConsumerBlocklist)
+
+	setString(config.FlagTequilapiAddress.Name, f.API.Address)
+	if f.API.Port != nil {
+		values[config.FlagTequilapiPort.Name] = *f.API.Port
+	}
+	if f.API.AllowedHostnames != nil {
+		values[config.FlagTequilapiAllowedHostnames.Name] = strings.Join(f.API.AllowedHostnames, ",")
+	}
+	setSlice(config.FlagTequilapiRateLimits.Name, f.API.RateLimits)
+	if f.API.Auth.Required != nil {
+		values[config.FlagTequilapiAuthRequired.Name] = *f.API.Auth.Required
+	}
+	setString(config.FlagTequilapiUsername.Name, f.API.Auth.Username)
+	setString(config.FlagTequilapiPassword.Name, f.API.Auth.Password)
+
+	return values
+}
+
+// Apply sets configuration values of the file, removing the ones set by the previously applied file.
+// It returns names of changed values.
+func Apply(cfg *config.Config, previous, next *File) []string {
+	var old, values map[string]interface{}
+	if previous != nil {
+		old = previous.Values()
+	}
+	if next != nil {
+		values = next.Values()
+	}
+
+	var changed []string
+	for key, value := range values {
+		if oldValue, ok := old[key]; ok && fmt.Sprint(oldValue) == fmt.Sprint(value) {
+			continue
+		}
+		cfg.SetFile(key, value)
+		changed = append(changed, key)
+	}
+	for key := range old {
+		if _, ok := values[key]; !ok {
+			cfg.RemoveFile(key)
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// LoadInto loads the declarative configuration file into the configuration.
+func LoadInto(cfg *config.Config, path string) (*File, error) {
+	file, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	Apply(cfg, nil, file)
+	return file, nil
+}
+
+func requiresRestart(key string) bool {
+	for _, prefix := range restartKeys {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package declarative
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Values(t *testing.T) {
+	file, err := Parse([]byte(`
+services:
+  - type: wireguard
+    access-policies: [mysterium, custom]
+  - type: scraping
+    identities: ["0x1", "0x2"]
+pricing:
+  price-gib: 0.2
+payments:
+  promise-threshold: 0.3
+access-policies:
+  consumer-blocklist: [/tmp/blocklist.txt]
+api:
+  port: 4449
+  allowed-hostnames: [localhost, node.lan]
+  auth:
+    required: true
+settings:
+  shaper.enabled: true
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"active-services":                   "wireguard,scraping",
+		"service.identities":                []string{"scraping=0x1", "scraping=0x2"},
+		"wireguard.access-policies":         "mysterium,custom",
+		"payment.price-gib":                 0.2,
+		"payments.hermes.promise.threshold": 0.3,
+		"access-policy.consumer-blocklist":  []string{"/tmp/blocklist.txt"},
+		"tequilapi.port":                    4449,
+		"tequilapi.allowed-hostnames":       "localhost,node.lan",
+		"tequilapi.auth.required":           true,
+		"shaper.enabled":                    true,
+	}, file.Values())
+}
+
+func TestParse_Empty(t *testing.T) {
+	file, err := Parse(nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, file.Values())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown field":            "servces: []",
+		"service without type":     "services: [{identities: [0x1]}]",
+		"duplicate service":        "services: [{type: wireguard}, {type: wireguard}]",
+		"policies of scraping":     "services: [{type: scraping, access-policies: [mysterium]}]",
+		"negative price":           "pricing: {price-hour: -1}",
+		"invalid port":             "api: {port: 70000}",
+		"setting of typed section": "pricing: {price-gib: 0.1}\nsettings: {payment.price-gib: 0.2}",
+		"malformed identity":       `services: [{type: wireguard, identities: ["0x1,0x2"]}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,281 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package declarative
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/utils"
+)
+
+// RunningService is a provided service instance.
+type RunningService struct {
+	ID             string
+	ProviderID     string
+	Type           string
+	AccessPolicies []string
+}
+
+// Services starts and stops provided services on configuration changes.
+type Services interface {
+	// Running lists provided service instances.
+	Running() []RunningService
+	// DefaultProvider returns the main provider identity, used by services without configured identities.
+	DefaultProvider() (string, error)
+	// AccessPolicies returns access policies of the service type according to the current configuration.
+	AccessPolicies(serviceType string) ([]string, error)
+	// Start starts the service with options of the current configuration.
+	Start(providerID, serviceType string) error
+	Stop(id string) error
+	Reannounce(id string) error
+}
+
+// Reloader watches the declarative configuration file and applies its changes to the running node.
+// Invalid files are rejected as a whole, leaving the current configuration in place.
+type Reloader struct {
+	path     string
+	interval time.Duration
+	cfg      *config.Config
+	services Services
+
+	mu   sync.Mutex
+	data []byte
+	file *File
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewReloader returns a new instance of Reloader.
+func NewReloader(path string, interval time.Duration, cfg *config.Config, services Services) *Reloader {
+	return &Reloader{
+		path:     path,
+		interval: interval,
+		cfg:      cfg,
+		services: services,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start checks the file periodically until stopped.
+// The file loaded first is taken as the one services were started with.
+func (r *Reloader) Start() {
+	if err := r.Check(); err != nil {
+		log.Error().Err(err).Msgf("Could not load configuration file %s", r.path)
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			if err := r.Check(); err != nil {
+				log.Error().Err(err).Msgf("Could not reload configuration file %s, keeping current configuration", r.path)
+			}
+		}
+	}
+}
+
+// Stop stops watching the file.
+func (r *Reloader) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// Check reloads the file if its content has changed.
+func (r *Reloader) Check() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return errors.Wrap(err, "could not read configuration file")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.data != nil && bytes.Equal(r.data, data) {
+		return nil
+	}
+
+	next, err := Parse(data)
+	if err != nil {
+		return err
+	}
+
+	previous := r.file
+	r.data, r.file = data, next
+
+	changed := Apply(r.cfg, previous, next)
+	if previous == nil {
+		log.Info().Msgf("Watching configuration file %s", r.path)
+		return nil
+	}
+	if len(changed) == 0 && !servicesChanged(previous, next) {
+		return nil
+	}
+
+	log.Info().Strs("changed", changed).Msg("Configuration file reloaded")
+	for _, key := range changed {
+		if requiresRestart(key) {
+			log.Warn().Msgf("Configuration value %s will take effect after the node restart", key)
+		}
+	}
+
+	return r.reconcile(next, len(previous.Services) > 0 || len(next.Services) > 0, changedAny(changed, isPolicyKey), changedAny(changed, isPriceKey))
+}
+
+type serviceKey struct {
+	serviceType string
+	providerID  string
+}
+
+// reconcile starts and stops services to match the configuration.
+// Services restart to apply changed access policies and re-announce their proposals on price changes.
+func (r *Reloader) reconcile(file *File, manage, policiesChanged, repriced bool) error {
+	var desired []serviceKey
+	if manage {
+		for _, s := range file.Services {
+			ids := s.Identities
+			if len(ids) == 0 {
+				id, err := r.services.DefaultProvider()
+				if err != nil {
+					return errors.Wrap(err, "could not get main provider identity")
+				}
+				ids = []string{id}
+			}
+			for _, id := range ids {
+				desired = append(desired, serviceKey{serviceType: s.Type, providerID: strings.ToLower(id)})
+			}
+		}
+	}
+	isDesired := func(key serviceKey) bool {
+		for _, d := range desired {
+			if d == key {
+				return true
+			}
+		}
+		return false
+	}
+
+	errs := utils.ErrorCollection{}
+	running := make(map[serviceKey]bool)
+	for _, instance := range r.services.Running() {
+		key := serviceKey{serviceType: instance.Type, providerID: strings.ToLower(instance.ProviderID)}
+		if manage && !isDesired(key) {
+			log.Info().Msgf("Stopping %s service of %s removed from configuration file", instance.Type, instance.ProviderID)
+			errs.Add(r.services.Stop(instance.ID))
+			continue
+		}
+		running[key] = true
+
+		if policiesChanged {
+			policies, err := r.services.AccessPolicies(instance.Type)
+			if err != nil {
+				errs.Add(err)
+				continue
+			}
+			if !samePolicies(policies, instance.AccessPolicies) {
+				log.Info().Msgf("Restarting %s service of %s to apply access policies %v", instance.Type, instance.ProviderID, policies)
+				if err := r.services.Stop(instance.ID); err != nil {
+					errs.Add(err)
+					continue
+				}
+				errs.Add(r.services.Start(instance.ProviderID, instance.Type))
+				continue
+			}
+		}
+		if repriced {
+			errs.Add(r.services.Reannounce(instance.ID))
+		}
+	}
+
+	for _, key := range desired {
+		if running[key] {
+			continue
+		}
+		log.Info().Msgf("Starting %s service of %s added to configuration file", key.serviceType, key.providerID)
+		errs.Add(r.services.Start(key.providerID, key.serviceType))
+	}
+
+	return errs.Errorf("could not apply services configuration: %s", ", ")
+}
+
+func servicesChanged(previous, next *File) bool {
+	if len(previous.Services) != len(next.Services) {
+		return true
+	}
+	for i := range previous.Services {
+		if previous.Services[i].Type != next.Services[i].Type ||
+			strings.Join(previous.Services[i].Identities, ",") != strings.Join(next.Services[i].Identities, ",") {
+			return true
+		}
+	}
+	return false
+}
+
+func samePolicies(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, p := range a {
+		seen[p] = true
+	}
+	for _, p := range b {
+		if !seen[p] {
+			return false
+		}
+	}
+	return true
+}
+
+func changedAny(keys []string, match func(key string) bool) bool {
+	for _, key := range keys {
+		if match(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPolicyKey(key string) bool {
+	if key == config.FlagAccessPolicyList.Name {
+		return true
+	}
+	for _, policyKey := range policyServiceTypes {
+		if key == policyKey {
+			return true
+		}
+	}
+	return false
+}
+
+func isPriceKey(key string) bool {
+	return key == config.FlagPaymentPriceGiB.Name || key == config.FlagPaymentPriceHour.Name
+}
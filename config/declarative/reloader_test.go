@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package declarative
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/config"
+)
+
+type mockServices struct {
+	cfg     *config.Config
+	running []RunningService
+	calls   []string
+	nextID  int
+}
+
+func (m *mockServices) Running() []RunningService {
+	return append([]RunningService{}, m.running...)
+}
+
+func (m *mockServices) DefaultProvider() (string, error) {
+	return "0xmain", nil
+}
+
+func (m *mockServices) AccessPolicies(serviceType string) ([]string, error) {
+	policies := m.cfg.GetString(serviceType + ".access-policies")
+	if policies == "" {
+		return nil, nil
+	}
+	return strings.Split(policies, ","), nil
+}
+
+func (m *mockServices) Start(providerID, serviceType string) error {
+	policies, _ := m.AccessPolicies(serviceType)
+	m.nextID++
+	m.running = append(m.running, RunningService{
+		ID:             fmt.Sprint(m.nextID),
+		ProviderID:     providerID,
+		Type:           serviceType,
+		AccessPolicies: policies,
+	})
+	m.calls = append(m.calls, "start "+serviceType+" "+providerID)
+	return nil
+}
+
+func (m *mockServices) Stop(id string) error {
+	for i, s := range m.running {
+		if s.ID == id {
+			m.running = append(m.running[:i], m.running[i+1:]...)
+			m.calls = append(m.calls, "stop "+s.Type+" "+s.ProviderID)
+			return nil
+		}
+	}
+	return fmt.Errorf("no such service %s", id)
+}
+
+func (m *mockServices) Reannounce(id string) error {
+	m.calls = append(m.calls, "reannounce "+id)
+	return nil
+}
+
+func writeFile(t *testing.T, path, data string) {
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+}
+
+func TestReloader_Check(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.yaml")
+	writeFile(t, path, `
+services:
+  - type: wireguard
+  - type: scraping
+pricing:
+  price-gib: 0.1
+`)
+	cfg := config.NewConfig()
+	services := &mockServices{cfg: cfg}
+	reloader := NewReloader(path, time.Minute, cfg, services)
+
+	// when: file is loaded for the first time
+	require.NoError(t, reloader.Check())
+	// then: values are applied, services are left to the service command
+	assert.Equal(t, "wireguard,scraping", cfg.GetString(config.FlagActiveServices.Name))
+	assert.Equal(t, 0.1, cfg.GetFloat64(config.FlagPaymentPriceGiB.Name))
+	assert.Empty(t, services.calls)
+	services.Start("0xmain", "wireguard")
+	services.Start("0xmain", "scraping")
+	services.calls = nil
+
+	// when: file content is the same
+	require.NoError(t, reloader.Check())
+	// then: nothing happens
+	assert.Empty(t, services.calls)
+
+	// when: price changes
+	writeFile(t, path, `
+services:
+  - type: wireguard
+  - type: scraping
+pricing:
+  price-gib: 0.2
+`)
+	require.NoError(t, reloader.Check())
+	// then: proposals are re-announced
+	assert.Equal(t, 0.2, cfg.GetFloat64(config.FlagPaymentPriceGiB.Name))
+	assert.Equal(t, []string{"reannounce 1", "reannounce 2"}, services.calls)
+	services.calls = nil
+
+	// when: service is replaced and access policies of the other change
+	writeFile(t, path, `
+services:
+  - type: wireguard
+    access-policies: [mysterium]
+  - type: data_transfer
+    identities: ["0xOther"]
+`)
+	require.NoError(t, reloader.Check())
+	// then: services are restarted, stopped and started accordingly
+	assert.Equal(t, []string{
+		"stop wireguard 0xmain",
+		"start wireguard 0xmain",
+		"stop scraping 0xmain",
+		"start data_transfer 0xother",
+	}, services.calls)
+	assert.Nil(t, cfg.Get(config.FlagPaymentPriceGiB.Name), "price is removed with the pricing section")
+}
+
+func TestReloader_CheckKeepsConfigurationOnInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.yaml")
+	writeFile(t, path, "pricing: {price-hour: 0.001}")
+	cfg := config.NewConfig()
+	reloader := NewReloader(path, time.Minute, cfg, &mockServices{cfg: cfg})
+	require.NoError(t, reloader.Check())
+
+	writeFile(t, path, "pricing: {price-hour: [")
+	assert.Error(t, reloader.Check())
+
+	assert.Equal(t, 0.001, cfg.GetFloat64(config.FlagPaymentPriceHour.Name))
+}
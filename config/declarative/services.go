@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package declarative
+
+import (
+	"fmt"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/identity"
+	identity_selector "github.com/mysteriumnetwork/node/identity/selector"
+	"github.com/mysteriumnetwork/node/services"
+)
+
+type serviceManager interface {
+	List(includeAll bool) []*service.Instance
+	Start(providerID identity.Identity, serviceType string, policyIDs []string, options service.Options) (service.ID, error)
+	Stop(id service.ID) error
+	Reannounce(id service.ID) error
+}
+
+type identityUnlocker interface {
+	IsUnlocked(address string) bool
+	Unlock(chainID int64, address string, passphrase string) error
+}
+
+// ServiceController starts services with options of the current configuration, as the service command does.
+type ServiceController struct {
+	manager    serviceManager
+	selector   identity_selector.Handler
+	identities identityUnlocker
+}
+
+// NewServiceController returns a new instance of ServiceController.
+func NewServiceController(manager serviceManager, selector identity_selector.Handler, identities identityUnlocker) *ServiceController {
+	return &ServiceController{
+		manager:    manager,
+		selector:   selector,
+		identities: identities,
+	}
+}
+
+// Running lists provided service instances.
+func (c *ServiceController) Running() []RunningService {
+	var running []RunningService
+	for _, instance := range c.manager.List(false) {
+		var policyIDs []string
+		if instance.Proposal.AccessPolicies != nil {
+			for _, p := range *instance.Proposal.AccessPolicies {
+				policyIDs = append(policyIDs, p.ID)
+			}
+		}
+		running = append(running, RunningService{
+			ID:             string(instance.ID),
+			ProviderID:     instance.ProviderID.Address,
+			Type:           instance.Type,
+			AccessPolicies: policyIDs,
+		})
+	}
+	return running
+}
+
+// DefaultProvider returns the identity the service command runs services under.
+func (c *ServiceController) DefaultProvider() (string, error) {
+	id, err := c.selector.UseOrCreate(
+		config.GetString(config.FlagIdentity),
+		config.GetString(config.FlagIdentityPassphrase),
+		config.GetInt64(config.FlagChainID),
+	)
+	if err != nil {
+		return "", err
+	}
+	return id.Address, nil
+}
+
+// AccessPolicies returns access policies of the service type according to the current configuration.
+func (c *ServiceController) AccessPolicies(serviceType string) ([]string, error) {
+	opts, err := services.GetStartOptions(serviceType)
+	if err != nil {
+		return nil, err
+	}
+	return opts.AccessPolicyList, nil
+}
+
+// Start unlocks the provider identity if needed and starts the service.
+func (c *ServiceController) Start(providerID, serviceType string) error {
+	opts, err := services.GetStartOptions(serviceType)
+	if err != nil {
+		return err
+	}
+
+	id := identity.FromAddress(providerID)
+	if !c.identities.IsUnlocked(id.Address) {
+		err := c.identities.Unlock(config.GetInt64(config.FlagChainID), id.Address, config.GetString(config.FlagIdentityPassphrase))
+		if err != nil {
+			return fmt.Errorf("could not unlock identity %s of %s service: %w", id.Address, serviceType, err)
+		}
+	}
+
+	_, err = c.manager.Start(id, serviceType, opts.AccessPolicyList, opts.TypeOptions)
+	return err
+}
+
+// Stop stops the service instance.
+func (c *ServiceController) Stop(id string) error {
+	return c.manager.Stop(service.ID(id))
+}
+
+// Reannounce registers the service proposal again.
+func (c *ServiceController) Reannounce(id string) error {
+	return c.manager.Reannounce(service.ID(id))
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagAbuseDetection enables detection of abusive consumer traffic.
+	FlagAbuseDetection = cli.BoolFlag{
+		Name:  "abuse.detection",
+		Usage: "Throttle and terminate sessions scanning ports or opening new flows excessively through the provider",
+		Value: true,
+	}
+	// FlagAbuseMaxNewFlows limits new flows a session may open within the detection window.
+	FlagAbuseMaxNewFlows = cli.IntFlag{
+		Name:  "abuse.max-new-flows",
+		Usage: "Number of new flows a session may open within the detection window",
+		Value: 1200,
+	}
+	// FlagAbuseMaxPortsPerHost limits distinct ports of a single host a session may reach within the detection window.
+	FlagAbuseMaxPortsPerHost = cli.IntFlag{
+		Name:  "abuse.max-ports-per-host",
+		Usage: "Number of distinct destination ports of a single host a session may reach within the detection window",
+		Value: 100,
+	}
+	// FlagAbuseMaxHostsPerPort limits distinct hosts a session may reach on a single port within the detection window.
+	FlagAbuseMaxHostsPerPort = cli.IntFlag{
+		Name:  "abuse.max-hosts-per-port",
+		Usage: "Number of distinct hosts a session may reach on a single destination port within the detection window",
+		Value: 500,
+	}
+	// FlagAbuseWindow sets the period flows of a session are counted in.
+	FlagAbuseWindow = cli.DurationFlag{
+		Name:  "abuse.window",
+		Usage: "Period new flows of a session are counted in",
+		Value: time.Minute,
+	}
+	// FlagAbuseThrottle sets how long new flows of an abusive session are dropped.
+	FlagAbuseThrottle = cli.DurationFlag{
+		Name:  "abuse.throttle",
+		Usage: "How long new flows of a session are dropped once it exceeds the limits",
+		Value: time.Minute,
+	}
+	// FlagAbuseStrikes sets how many times a session may exceed the limits before it is terminated.
+	FlagAbuseStrikes = cli.IntFlag{
+		Name:  "abuse.strikes",
+		Usage: "How many times a session may exceed the limits before it is terminated and the consumer is banned",
+		Value: 3,
+	}
+	// FlagAbuseBanDuration sets how long consumers of terminated sessions are banned.
+	FlagAbuseBanDuration = cli.DurationFlag{
+		Name:  "abuse.ban-duration",
+		Usage: "How long consumers of sessions terminated for abuse can not start new sessions",
+		Value: 24 * time.Hour,
+	}
+)
+
+// RegisterFlagsAbuse function register abuse detection flags to flag list
+func RegisterFlagsAbuse(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagAbuseDetection,
+		&FlagAbuseMaxNewFlows,
+		&FlagAbuseMaxPortsPerHost,
+		&FlagAbuseMaxHostsPerPort,
+		&FlagAbuseWindow,
+		&FlagAbuseThrottle,
+		&FlagAbuseStrikes,
+		&FlagAbuseBanDuration,
+	)
+}
+
+// ParseFlagsAbuse function fills in abuse detection options from CLI context
+func ParseFlagsAbuse(ctx *cli.Context) {
+	Current.ParseBoolFlag(ctx, FlagAbuseDetection)
+	Current.ParseIntFlag(ctx, FlagAbuseMaxNewFlows)
+	Current.ParseIntFlag(ctx, FlagAbuseMaxPortsPerHost)
+	Current.ParseIntFlag(ctx, FlagAbuseMaxHostsPerPort)
+	Current.ParseDurationFlag(ctx, FlagAbuseWindow)
+	Current.ParseDurationFlag(ctx, FlagAbuseThrottle)
+	Current.ParseIntFlag(ctx, FlagAbuseStrikes)
+	Current.ParseDurationFlag(ctx, FlagAbuseBanDuration)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagCapacityDownloadURLs sets the targets of the download speed test.
+	FlagCapacityDownloadURLs = cli.StringSliceFlag{
+		Name:  "capacity.download-url",
+		Usage: "URLs to download data from when measuring downlink capacity, separated by comma",
+		Value: cli.NewStringSlice("https://speed.cloudflare.com/__down?bytes=25000000"),
+	}
+	// FlagCapacityUploadURLs sets the targets of the upload speed test.
+	FlagCapacityUploadURLs = cli.StringSliceFlag{
+		Name:  "capacity.upload-url",
+		Usage: "URLs to upload data to when measuring uplink capacity, separated by comma",
+		Value: cli.NewStringSlice("https://speed.cloudflare.com/__up"),
+	}
+	// FlagCapacitySpeedTestInterval sets how often the provider capacity is measured.
+	FlagCapacitySpeedTestInterval = cli.DurationFlag{
+		Name:  "capacity.speedtest-interval",
+		Usage: "How often to measure capacity advertised in proposals, 0 disables the speed test",
+		Value: 12 * time.Hour,
+	}
+)
+
+// RegisterFlagsCapacity function register capacity flags to flag list
+func RegisterFlagsCapacity(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagCapacityDownloadURLs,
+		&FlagCapacityUploadURLs,
+		&FlagCapacitySpeedTestInterval,
+	)
+}
+
+// ParseFlagsCapacity function fills in capacity options from CLI context
+func ParseFlagsCapacity(ctx *cli.Context) {
+	Current.ParseStringSliceFlag(ctx, FlagCapacityDownloadURLs)
+	Current.ParseStringSliceFlag(ctx, FlagCapacityUploadURLs)
+	Current.ParseDurationFlag(ctx, FlagCapacitySpeedTestInterval)
+}
@@ -20,6 +20,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/urfave/cli/v2"
 )
@@ -30,6 +31,39 @@ var (
 		Name:  "config-dir",
 		Usage: "Config directory containing all configuration files",
 	}
+	// FlagConfigFile declarative YAML configuration file of the node.
+	FlagConfigFile = cli.StringFlag{
+		Name:  "config-file",
+		Usage: "YAML file describing services, pricing, payment thresholds, access policies and API settings. Changes are applied without restarting the node",
+	}
+	// FlagConfigFileCheckInterval sets how often the declarative configuration file is checked for changes.
+	FlagConfigFileCheckInterval = cli.DurationFlag{
+		Name:  "config-file-check-interval",
+		Usage: "How often to check the declarative configuration file for changes",
+		Value: 5 * time.Second,
+	}
+	// FlagConfigRemoteURL sets the endpoint remote configuration is fetched from.
+	FlagConfigRemoteURL = cli.StringFlag{
+		Name:  "config-remote-url",
+		Usage: "URL of remote configuration shared by a fleet of nodes, e.g. https://config.example.com/node.json or http://etcd:2379/mysterium/node/ for etcd. Its values override user configuration, but not configuration file, environment variables or flags",
+	}
+	// FlagConfigRemoteType sets the kind of the remote configuration endpoint.
+	FlagConfigRemoteType = cli.StringFlag{
+		Name:  "config-remote-type",
+		Usage: "Remote configuration endpoint type: 'http' for a JSON document of values by flag names or 'etcd' for keys under the URL path prefix of etcd v3 JSON gateway",
+		Value: "http",
+	}
+	// FlagConfigRemoteToken sets the token used to authorize remote configuration requests.
+	FlagConfigRemoteToken = cli.StringFlag{
+		Name:  "config-remote-token",
+		Usage: "Token sent in Authorization header of remote configuration requests, a bearer token for 'http' and an auth token for 'etcd' endpoints",
+	}
+	// FlagConfigRemoteCheckInterval sets how often remote configuration is checked for changes.
+	FlagConfigRemoteCheckInterval = cli.DurationFlag{
+		Name:  "config-remote-check-interval",
+		Usage: "How often to check remote configuration for changes",
+		Value: time.Minute,
+	}
 	// FlagDataDir data directory for keystore and other persistent files.
 	FlagDataDir = cli.StringFlag{
 		Name:  "data-dir",
@@ -78,6 +112,12 @@ func RegisterFlagsDirectory(flags *[]cli.Flag) error {
 
 	*flags = append(*flags,
 		&FlagConfigDir,
+		&FlagConfigFile,
+		&FlagConfigFileCheckInterval,
+		&FlagConfigRemoteURL,
+		&FlagConfigRemoteType,
+		&FlagConfigRemoteToken,
+		&FlagConfigRemoteCheckInterval,
 		&FlagDataDir,
 		&FlagLogDir,
 		&FlagRuntimeDir,
@@ -90,6 +130,12 @@ func RegisterFlagsDirectory(flags *[]cli.Flag) error {
 // ParseFlagsDirectory function fills in directory options from CLI context
 func ParseFlagsDirectory(ctx *cli.Context) {
 	Current.ParseStringFlag(ctx, FlagConfigDir)
+	Current.ParseStringFlag(ctx, FlagConfigFile)
+	Current.ParseDurationFlag(ctx, FlagConfigFileCheckInterval)
+	Current.ParseStringFlag(ctx, FlagConfigRemoteURL)
+	Current.ParseStringFlag(ctx, FlagConfigRemoteType)
+	Current.ParseStringFlag(ctx, FlagConfigRemoteToken)
+	Current.ParseDurationFlag(ctx, FlagConfigRemoteCheckInterval)
 	Current.ParseStringFlag(ctx, FlagDataDir)
 	Current.ParseStringFlag(ctx, FlagLogDir)
 	Current.ParseStringFlag(ctx, FlagRuntimeDir)
@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagEgressBlockedPorts lists destination ports consumers can not reach through the provider.
+	FlagEgressBlockedPorts = cli.StringSliceFlag{
+		Name:  "egress.blocked-ports",
+		Usage: "Destination ports blocked for consumer traffic, separated by comma",
+		Value: cli.NewStringSlice("25", "465", "587"),
+	}
+	// FlagEgressBlockedNetworks lists destination networks consumers can not reach through the provider.
+	FlagEgressBlockedNetworks = cli.StringSliceFlag{
+		Name:  "egress.blocked-networks",
+		Usage: "Destination networks in CIDR notation blocked for consumer traffic, separated by comma",
+		Value: cli.NewStringSlice("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"),
+	}
+	// FlagEgressBlockedDomains lists domains consumers can not reach through the provider.
+	FlagEgressBlockedDomains = cli.StringSliceFlag{
+		Name:  "egress.blocked-domains",
+		Usage: "Domains blocked for consumer traffic together with their subdomains, separated by comma",
+	}
+)
+
+// RegisterFlagsEgress function register egress policy flags to flag list
+func RegisterFlagsEgress(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagEgressBlockedPorts,
+		&FlagEgressBlockedNetworks,
+		&FlagEgressBlockedDomains,
+	)
+}
+
+// ParseFlagsEgress function fills in egress policy options from CLI context
+func ParseFlagsEgress(ctx *cli.Context) {
+	Current.ParseStringSliceFlag(ctx, FlagEgressBlockedPorts)
+	Current.ParseStringSliceFlag(ctx, FlagEgressBlockedNetworks)
+	Current.ParseStringSliceFlag(ctx, FlagEgressBlockedDomains)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagEventsNATSAddress sets NATS server address internal events are exported to.
+	FlagEventsNATSAddress = cli.StringFlag{
+		Name:  "events.nats.address",
+		Usage: "NATS server address to export node events to, e.g. nats://127.0.0.1:4222. Export is disabled if empty",
+		Value: "",
+	}
+	// FlagEventsNATSSubjectPrefix sets subject prefix of exported events.
+	FlagEventsNATSSubjectPrefix = cli.StringFlag{
+		Name:  "events.nats.subject-prefix",
+		Usage: "NATS subject prefix of exported events",
+		Value: "myst.events",
+	}
+	// FlagEventsNATSGroups sets groups of exported events.
+	FlagEventsNATSGroups = cli.StringSliceFlag{
+		Name:  "events.nats.groups",
+		Usage: "Comma separated list of exported event groups: sessions, earnings, connectivity",
+		Value: cli.NewStringSlice("sessions", "earnings", "connectivity"),
+	}
+	// FlagWebhooksBalanceLowThreshold sets balance which triggers low balance webhooks.
+	FlagWebhooksBalanceLowThreshold = cli.StringFlag{
+		Name:  "webhooks.balance-low-threshold",
+		Usage: "Balance in wei, dropping below which is delivered to webhooks subscribed to balance_low event",
+		Value: "500000000000000000",
+	}
+)
+
+// RegisterFlagsEvents function register event export and webhook flags to flag list
+func RegisterFlagsEvents(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagEventsNATSAddress,
+		&FlagEventsNATSSubjectPrefix,
+		&FlagEventsNATSGroups,
+		&FlagWebhooksBalanceLowThreshold,
+	)
+}
+
+// ParseFlagsEvents function fills in event export and webhook options from CLI context
+func ParseFlagsEvents(ctx *cli.Context) {
+	Current.ParseStringFlag(ctx, FlagEventsNATSAddress)
+	Current.ParseStringFlag(ctx, FlagEventsNATSSubjectPrefix)
+	Current.ParseStringSliceFlag(ctx, FlagEventsNATSGroups)
+	Current.ParseStringFlag(ctx, FlagWebhooksBalanceLowThreshold)
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagGRPCEnabled enables the gRPC management API.
+	FlagGRPCEnabled = cli.BoolFlag{
+		Name:  "grpc.enabled",
+		Usage: "Enables gRPC management API. Calls are authorized with tequilapi JWT token passed in the authorization metadata",
+		Value: false,
+	}
+	// FlagGRPCAddress sets the address the gRPC management API listens on.
+	FlagGRPCAddress = cli.StringFlag{
+		Name:  "grpc.address",
+		Usage: "IP address to bind gRPC management API to. The API is served over plaintext gRPC, so it should not be exposed to public networks",
+		Value: "127.0.0.1",
+	}
+	// FlagGRPCPort sets the port the gRPC management API listens on.
+	FlagGRPCPort = cli.IntFlag{
+		Name:  "grpc.port",
+		Usage: "Port for listening gRPC management API calls",
+		Value: 4051,
+	}
+)
+
+// RegisterFlagsGRPC function register gRPC API flags to flag list
+func RegisterFlagsGRPC(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagGRPCEnabled,
+		&FlagGRPCAddress,
+		&FlagGRPCPort,
+	)
+}
+
+// ParseFlagsGRPC function fills in gRPC API options from CLI context
+func ParseFlagsGRPC(ctx *cli.Context) {
+	Current.ParseBoolFlag(ctx, FlagGRPCEnabled)
+	Current.ParseStringFlag(ctx, FlagGRPCAddress)
+	Current.ParseIntFlag(ctx, FlagGRPCPort)
+}
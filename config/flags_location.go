@@ -19,6 +19,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/mysteriumnetwork/node/metadata"
 	"github.com/urfave/cli/v2"
@@ -31,10 +32,22 @@ var (
 		Usage: "Address (URL form) of IP detection service",
 		Value: metadata.DefaultNetwork.LocationAddress,
 	}
+	// FlagIPDetectorQueries number of public IP sources queried in parallel.
+	FlagIPDetectorQueries = cli.IntFlag{
+		Name:  "ip-detector.queries",
+		Usage: "Number of public IP sources (HTTP, STUN and DNS) queried in parallel to reach consensus on the public IP",
+		Value: 3,
+	}
+	// FlagIPDetectorWatchInterval how often public IP is checked for changes.
+	FlagIPDetectorWatchInterval = cli.DurationFlag{
+		Name:  "ip-detector.watch-interval",
+		Usage: "How often public IP is checked for changes, e.g. after DHCP renewals. Set to 0 to disable",
+		Value: 5 * time.Minute,
+	}
 	// FlagLocationType location detector type.
 	FlagLocationType = cli.StringFlag{
 		Name:  "location.type",
-		Usage: "Location autodetect adapter. Options: { oracle, builtin, mmdb, manual }",
+		Usage: "Location autodetect adapter. Options: { oracle, builtin, mmdb, manual }. Oracle falls back to builtin database when unreachable",
 		Value: "oracle",
 	}
 	// FlagLocationAddress URL of location detector.
@@ -46,6 +59,17 @@ var (
 		),
 		Value: metadata.DefaultNetwork.LocationAddress,
 	}
+	// FlagLocationDBUpdateURL URL of country database used for offline location detection.
+	FlagLocationDBUpdateURL = cli.StringFlag{
+		Name:  "location.db.update-url",
+		Usage: "URL of MMDB country database (optionally gzip compressed) periodically downloaded for offline location detection. Updates are disabled when empty",
+	}
+	// FlagLocationDBUpdateInterval interval of country database updates.
+	FlagLocationDBUpdateInterval = cli.DurationFlag{
+		Name:  "location.db.update-interval",
+		Usage: "How often to check for country database updates",
+		Value: 7 * 24 * time.Hour,
+	}
 	// FlagLocationCountry service location country.
 	FlagLocationCountry = cli.StringFlag{
 		Name:  "location.country",
@@ -67,8 +91,12 @@ var (
 func RegisterFlagsLocation(flags *[]cli.Flag) {
 	*flags = append(*flags,
 		&FlagIPDetectorURL,
+		&FlagIPDetectorQueries,
+		&FlagIPDetectorWatchInterval,
 		&FlagLocationType,
 		&FlagLocationAddress,
+		&FlagLocationDBUpdateURL,
+		&FlagLocationDBUpdateInterval,
 		&FlagLocationCountry,
 		&FlagLocationCity,
 		&FlagLocationIPType,
@@ -78,8 +106,12 @@ func RegisterFlagsLocation(flags *[]cli.Flag) {
 // ParseFlagsLocation function fills in location options from CLI context.
 func ParseFlagsLocation(ctx *cli.Context) {
 	Current.ParseStringFlag(ctx, FlagIPDetectorURL)
+	Current.ParseIntFlag(ctx, FlagIPDetectorQueries)
+	Current.ParseDurationFlag(ctx, FlagIPDetectorWatchInterval)
 	Current.ParseStringFlag(ctx, FlagLocationType)
 	Current.ParseStringFlag(ctx, FlagLocationAddress)
+	Current.ParseStringFlag(ctx, FlagLocationDBUpdateURL)
+	Current.ParseDurationFlag(ctx, FlagLocationDBUpdateInterval)
 	Current.ParseStringFlag(ctx, FlagLocationCountry)
 	Current.ParseStringFlag(ctx, FlagLocationCity)
 	Current.ParseStringFlag(ctx, FlagLocationIPType)
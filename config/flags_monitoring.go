@@ -0,0 +1,78 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagMonitoringInterval sets how often resource usage of services is collected.
+	FlagMonitoringInterval = cli.DurationFlag{
+		Name:  "monitoring.interval",
+		Usage: "How often to collect resource usage of the node and its services",
+		Value: 30 * time.Second,
+	}
+	// FlagMonitoringCPUThreshold sets the CPU usage threshold.
+	FlagMonitoringCPUThreshold = cli.Float64Flag{
+		Name:  "monitoring.cpu-threshold",
+		Usage: "CPU usage in percent of a single core above which a resource threshold event is published, 0 disables the threshold",
+		Value: 0,
+	}
+	// FlagMonitoringMemoryThreshold sets the memory usage threshold.
+	FlagMonitoringMemoryThreshold = cli.Uint64Flag{
+		Name:  "monitoring.memory-threshold",
+		Usage: "Memory usage in megabytes above which a resource threshold event is published, 0 disables the threshold",
+		Value: 0,
+	}
+	// FlagMonitoringFDThreshold sets the open file descriptors threshold.
+	FlagMonitoringFDThreshold = cli.IntFlag{
+		Name:  "monitoring.fd-threshold",
+		Usage: "Number of open file descriptors above which a resource threshold event is published, 0 disables the threshold",
+		Value: 0,
+	}
+	// FlagMonitoringGoroutineThreshold sets the goroutines threshold.
+	FlagMonitoringGoroutineThreshold = cli.IntFlag{
+		Name:  "monitoring.goroutine-threshold",
+		Usage: "Number of goroutines above which a resource threshold event is published, 0 disables the threshold",
+		Value: 0,
+	}
+)
+
+// RegisterFlagsMonitoring function register resource monitoring flags to flag list
+func RegisterFlagsMonitoring(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagMonitoringInterval,
+		&FlagMonitoringCPUThreshold,
+		&FlagMonitoringMemoryThreshold,
+		&FlagMonitoringFDThreshold,
+		&FlagMonitoringGoroutineThreshold,
+	)
+}
+
+// ParseFlagsMonitoring function fills in resource monitoring options from CLI context
+func ParseFlagsMonitoring(ctx *cli.Context) {
+	Current.ParseDurationFlag(ctx, FlagMonitoringInterval)
+	Current.ParseFloat64Flag(ctx, FlagMonitoringCPUThreshold)
+	Current.ParseUInt64Flag(ctx, FlagMonitoringMemoryThreshold)
+	Current.ParseIntFlag(ctx, FlagMonitoringFDThreshold)
+	Current.ParseIntFlag(ctx, FlagMonitoringGoroutineThreshold)
+}
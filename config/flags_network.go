@@ -59,6 +59,54 @@ var (
 		Usage: "URI of message broker",
 		Value: cli.NewStringSlice(metadata.DefaultNetwork.BrokerAddresses...),
 	}
+	// FlagBrokerReconnectWait delay before the first reconnect attempt to message broker.
+	FlagBrokerReconnectWait = cli.DurationFlag{
+		Name:  "broker.reconnect-wait",
+		Usage: "Delay before the first reconnect attempt to message broker, doubled on each failed attempt",
+		Value: 1 * time.Second,
+	}
+	// FlagBrokerReconnectMaxWait maximum delay between reconnect attempts to message broker.
+	FlagBrokerReconnectMaxWait = cli.DurationFlag{
+		Name:  "broker.reconnect-max-wait",
+		Usage: "Maximum delay between reconnect attempts to message broker",
+		Value: 30 * time.Second,
+	}
+	// FlagBrokerBufferSize number of outbound messages kept while message broker is unreachable.
+	FlagBrokerBufferSize = cli.IntFlag{
+		Name:  "broker.buffer-size",
+		Usage: "Number of outbound messages kept while message broker is unreachable and replayed after reconnect",
+		Value: 256,
+	}
+	// FlagBrokerReceiverConcurrency number of workers consuming received broker messages.
+	FlagBrokerReceiverConcurrency = cli.IntFlag{
+		Name:  "broker.receiver-concurrency",
+		Usage: "Number of workers consuming received broker messages, messages of the same subject are consumed in order by one worker",
+		Value: 16,
+	}
+	// FlagBrokerHealthInterval how often the active broker and more preferred ones are checked.
+	FlagBrokerHealthInterval = cli.DurationFlag{
+		Name:  "broker.health-interval",
+		Usage: "Interval of message broker health checks, used to fail over between broker addresses in preference order (0 to disable)",
+		Value: 15 * time.Second,
+	}
+	// FlagBrokerTransport selects how nodes exchange broker messages.
+	FlagBrokerTransport = cli.StringFlag{
+		Name:  "broker.transport",
+		Usage: "Broker messages transport: 'nats' or 'libp2p' (peer-to-peer gossipsub alongside NATS)",
+		Value: "nats",
+	}
+	// FlagBrokerGossipListen multiaddress of peer-to-peer broker transport.
+	FlagBrokerGossipListen = cli.StringFlag{
+		Name:  "broker.gossip.listen",
+		Usage: "Listen multiaddress of libp2p broker transport",
+		Value: "/ip4/0.0.0.0/tcp/0",
+	}
+	// FlagBrokerGossipPeers bootstrap peers of peer-to-peer broker transport.
+	FlagBrokerGossipPeers = cli.StringSliceFlag{
+		Name:  "broker.gossip.peers",
+		Usage: "Bootstrap peer multiaddresses of libp2p broker transport, e.g. /ip4/1.2.3.4/tcp/4001/p2p/<peer-id>",
+		Value: cli.NewStringSlice(),
+	}
 	// FlagEtherRPCL1 URL or IPC socket to connect to Ethereum node.
 	FlagEtherRPCL1 = cli.StringSliceFlag{
 		Name:  metadata.FlagNames.Chain1Flag.EtherClientRPCFlag,
@@ -96,6 +144,12 @@ var (
 		Usage: "Enables outgoing traffic filtering",
 		Value: false,
 	}
+	// FlagFirewallBackend selects packet filtering framework used for firewall and NAT rules on Linux.
+	FlagFirewallBackend = cli.StringFlag{
+		Name:  "firewall.backend",
+		Usage: "Firewall backend used on Linux: auto, iptables or nftables. Auto uses nftables when iptables is not installed",
+		Value: "auto",
+	}
 	// FlagKeepConnectedOnFail keeps connection active to prevent traffic leaks.
 	FlagKeepConnectedOnFail = cli.BoolFlag{
 		Name:  "keep-connected-on-fail",
@@ -108,12 +162,66 @@ var (
 		Usage: "Restore connection automatically once it failed",
 		Value: false,
 	}
+	// FlagAutoSwitch switches to the next best provider once connection quality degrades.
+	FlagAutoSwitch = cli.BoolFlag{
+		Name:  "auto-switch",
+		Usage: "Switch to the next best provider automatically once connection stalls or its keep alive pings keep failing",
+		Value: false,
+	}
 	// FlagSTUNservers list of STUN server to be used to detect NAT type.
 	FlagSTUNservers = cli.StringSliceFlag{
 		Name:  "stun-servers",
 		Usage: "Comma separated list of STUN server to be used to detect NAT type",
 		Value: cli.NewStringSlice("stun.l.google.com:19302", "stun1.l.google.com:19302", "stun2.l.google.com:19302"),
 	}
+	// FlagP2PRelays list of relay addresses used when NAT hole punching fails.
+	FlagP2PRelays = cli.StringSliceFlag{
+		Name:  "p2p.relays",
+		Usage: "Comma separated list of relay host:port addresses used to carry p2p traffic when NAT hole punching fails",
+		Value: cli.NewStringSlice(),
+	}
+	// FlagP2PIPv6 enables p2p connections over IPv6.
+	FlagP2PIPv6 = cli.BoolFlag{
+		Name:  "p2p.ipv6",
+		Usage: "Use global IPv6 address for p2p connections when both peers have one",
+		Value: true,
+	}
+	// FlagP2PIPv6Direct connects peers over IPv6 directly skipping NAT hole punching.
+	FlagP2PIPv6Direct = cli.BoolFlag{
+		Name:  "p2p.ipv6-direct",
+		Usage: "Connect directly over IPv6 without NAT hole punching when both peers have global IPv6 address, falls back to IPv4 if IPv6 path does not work",
+		Value: true,
+	}
+	// FlagP2PLANDirect connects peers behind the same NAT over LAN.
+	FlagP2PLANDirect = cli.BoolFlag{
+		Name:  "p2p.lan-direct",
+		Usage: "Connect over LAN addresses when both peers are behind the same NAT, falls back to public addresses if LAN path does not work",
+		Value: true,
+	}
+	// FlagP2PCGNATDetection enables carrier-grade NAT detection on startup.
+	FlagP2PCGNATDetection = cli.BoolFlag{
+		Name:  "p2p.cgnat-detection",
+		Usage: "Detect carrier-grade NAT on startup and connect consumers via relay if provider is behind it",
+		Value: true,
+	}
+	// FlagP2PNAT64Prefix NAT64 prefix used to reach IPv4 peers from IPv6-only networks.
+	FlagP2PNAT64Prefix = cli.StringFlag{
+		Name:  "p2p.nat64-prefix",
+		Usage: "NAT64 prefix (e.g. 64:ff9b::/96) used to reach IPv4-only peers when there is no IPv4 connectivity",
+		Value: "",
+	}
+	// FlagRelayPort enables community relay on the given UDP port.
+	FlagRelayPort = cli.IntFlag{
+		Name:  "relay.port",
+		Usage: "Serve as a community relay for peers failing NAT hole punching on the given UDP port, 0 disables relay",
+		Value: 0,
+	}
+	// FlagRelayMaxRate limits relayed bandwidth of a single peer pair.
+	FlagRelayMaxRate = cli.IntFlag{
+		Name:  "relay.max-rate",
+		Usage: "Maximum number of bytes per second relayed for a single peer pair, 0 means unlimited",
+		Value: 1024 * 1024,
+	}
 	// FlagLocalServiceDiscovery enables SSDP and Bonjour local service discovery.
 	FlagLocalServiceDiscovery = cli.BoolFlag{
 		Name:  "local-service-discovery",
@@ -154,6 +262,27 @@ var (
 		Usage: "DNS listen port for services",
 		Value: 11253,
 	}
+
+	// FlagDNSUpstream sets DNS servers the provider forwards consumer queries to.
+	FlagDNSUpstream = cli.StringSliceFlag{
+		Name:  "dns.upstream",
+		Usage: "Comma separated list of DNS server IPs the provider forwards consumer queries to, system DNS configuration is used when empty",
+		Value: cli.NewStringSlice(),
+	}
+
+	// FlagDNSBlocklistSources sets hosts-format or RPZ domain blocklists used by consumer DNS filtering.
+	FlagDNSBlocklistSources = cli.StringSliceFlag{
+		Name:  "dns.blocklist.sources",
+		Usage: "Hosts-format or RPZ domain blocklist URLs or file paths, queries to listed domains are blocked for connections with DNS filtering enabled",
+		Value: cli.NewStringSlice(),
+	}
+
+	// FlagDNSBlocklistUpdateInterval sets how often domain blocklists are reloaded.
+	FlagDNSBlocklistUpdateInterval = cli.DurationFlag{
+		Name:  "dns.blocklist.update-interval",
+		Usage: "Duration between domain blocklist updates",
+		Value: 24 * time.Hour,
+	}
 )
 
 // RegisterFlagsNetwork function register network flags to flag list
@@ -165,20 +294,41 @@ func RegisterFlagsNetwork(flags *[]cli.Flag) {
 		&FlagAPIAddress,
 		&FlagDiscoveryAddress,
 		&FlagBrokerAddress,
+		&FlagBrokerReconnectWait,
+		&FlagBrokerReconnectMaxWait,
+		&FlagBrokerBufferSize,
+		&FlagBrokerReceiverConcurrency,
+		&FlagBrokerHealthInterval,
+		&FlagBrokerTransport,
+		&FlagBrokerGossipListen,
+		&FlagBrokerGossipPeers,
 		&FlagEtherRPCL1,
 		&FlagEtherRPCL2,
 		&FlagIncomingFirewall,
 		&FlagOutgoingFirewall,
+		&FlagFirewallBackend,
 		&FlagChainID,
 		&FlagKeepConnectedOnFail,
 		&FlagAutoReconnect,
+		&FlagAutoSwitch,
 		&FlagSTUNservers,
+		&FlagP2PRelays,
+		&FlagP2PIPv6,
+		&FlagP2PIPv6Direct,
+		&FlagP2PLANDirect,
+		&FlagP2PCGNATDetection,
+		&FlagP2PNAT64Prefix,
+		&FlagRelayPort,
+		&FlagRelayMaxRate,
 		&FlagLocalServiceDiscovery,
 		&FlagUDPListenPorts,
 		&FlagTraversal,
 		&FlagPortCheckServers,
 		&FlagStatsReportInterval,
 		&FlagDNSListenPort,
+		&FlagDNSUpstream,
+		&FlagDNSBlocklistSources,
+		&FlagDNSBlocklistUpdateInterval,
 	)
 }
 
@@ -187,22 +337,43 @@ func ParseFlagsNetwork(ctx *cli.Context) {
 	Current.ParseStringFlag(ctx, FlagAPIAddress)
 	Current.ParseStringFlag(ctx, FlagDiscoveryAddress)
 	Current.ParseStringSliceFlag(ctx, FlagBrokerAddress)
+	Current.ParseDurationFlag(ctx, FlagBrokerReconnectWait)
+	Current.ParseDurationFlag(ctx, FlagBrokerReconnectMaxWait)
+	Current.ParseIntFlag(ctx, FlagBrokerBufferSize)
+	Current.ParseIntFlag(ctx, FlagBrokerReceiverConcurrency)
+	Current.ParseDurationFlag(ctx, FlagBrokerHealthInterval)
+	Current.ParseStringFlag(ctx, FlagBrokerTransport)
+	Current.ParseStringFlag(ctx, FlagBrokerGossipListen)
+	Current.ParseStringSliceFlag(ctx, FlagBrokerGossipPeers)
 	Current.ParseStringSliceFlag(ctx, FlagEtherRPCL1)
 	Current.ParseStringSliceFlag(ctx, FlagEtherRPCL2)
 	Current.ParseBoolFlag(ctx, FlagPortMapping)
 	Current.ParseBoolFlag(ctx, FlagNATHolePunching)
 	Current.ParseBoolFlag(ctx, FlagIncomingFirewall)
 	Current.ParseBoolFlag(ctx, FlagOutgoingFirewall)
+	Current.ParseStringFlag(ctx, FlagFirewallBackend)
 	Current.ParseInt64Flag(ctx, FlagChainID)
 	Current.ParseBoolFlag(ctx, FlagKeepConnectedOnFail)
 	Current.ParseBoolFlag(ctx, FlagAutoReconnect)
+	Current.ParseBoolFlag(ctx, FlagAutoSwitch)
 	Current.ParseStringSliceFlag(ctx, FlagSTUNservers)
+	Current.ParseStringSliceFlag(ctx, FlagP2PRelays)
+	Current.ParseBoolFlag(ctx, FlagP2PIPv6)
+	Current.ParseBoolFlag(ctx, FlagP2PIPv6Direct)
+	Current.ParseBoolFlag(ctx, FlagP2PLANDirect)
+	Current.ParseBoolFlag(ctx, FlagP2PCGNATDetection)
+	Current.ParseStringFlag(ctx, FlagP2PNAT64Prefix)
+	Current.ParseIntFlag(ctx, FlagRelayPort)
+	Current.ParseIntFlag(ctx, FlagRelayMaxRate)
 	Current.ParseBoolFlag(ctx, FlagLocalServiceDiscovery)
 	Current.ParseStringFlag(ctx, FlagUDPListenPorts)
 	Current.ParseStringFlag(ctx, FlagTraversal)
 	Current.ParseStringFlag(ctx, FlagPortCheckServers)
 	Current.ParseDurationFlag(ctx, FlagStatsReportInterval)
 	Current.ParseIntFlag(ctx, FlagDNSListenPort)
+	Current.ParseStringSliceFlag(ctx, FlagDNSUpstream)
+	Current.ParseStringSliceFlag(ctx, FlagDNSBlocklistSources)
+	Current.ParseDurationFlag(ctx, FlagDNSBlocklistUpdateInterval)
 }
 
 // BlockchainNetwork defines a blockchain network
@@ -51,6 +51,18 @@ var (
 		Usage: `Proposal fetch interval { "30s", "3m", "1h20m30s" }`,
 		Value: 180 * time.Second,
 	}
+	// FlagDiscoveryCacheTTL how long proposals cached for discovery outages are served.
+	FlagDiscoveryCacheTTL = cli.DurationFlag{
+		Name:  "discovery.cache-ttl",
+		Usage: `How long proposals cached locally are served while discovery API is unreachable, 0 disables the cache { "30m", "24h" }`,
+		Value: 24 * time.Hour,
+	}
+	// FlagDiscoveryRequireSignedProposals rejects proposals without provider signature.
+	FlagDiscoveryRequireSignedProposals = cli.BoolFlag{
+		Name:  "discovery.require-signed-proposals",
+		Usage: "Reject proposals which are not signed by their providers, including those of providers running older nodes",
+		Value: false,
+	}
 	// FlagDHTAddress IP address of interface to listen for DHT connections.
 	FlagDHTAddress = cli.StringFlag{
 		Name:  "discovery.dht.address",
@@ -116,6 +128,48 @@ var (
 		Usage: "Determines the scrypt memory complexity. If set to true, will use 4MB blocks instead of the standard 256MB ones",
 		Value: true,
 	}
+	// FlagKeystoreKeychain keeps identity passphrases in the platform keychain.
+	FlagKeystoreKeychain = cli.BoolFlag{
+		Name:  "keystore.keychain",
+		Usage: "Keep identity passphrases in the platform keychain (Keychain on macOS, libsecret on Linux, DPAPI on Windows) to unlock identities on boot without a passphrase",
+	}
+	// FlagKeystoreHardwareWallet enables signing with identities held on a hardware wallet.
+	FlagKeystoreHardwareWallet = cli.StringFlag{
+		Name:  "keystore.hardware-wallet",
+		Usage: "Sign with identities held on a USB hardware wallet: ledger or trezor. Other identities are signed with the keystore",
+	}
+	// FlagKeystoreHardwareWalletPaths derivation paths of identities held on a hardware wallet.
+	FlagKeystoreHardwareWalletPaths = cli.StringSliceFlag{
+		Name:  "keystore.hardware-wallet.derivation-paths",
+		Usage: "Derivation paths of identities held on the hardware wallet",
+		Value: cli.NewStringSlice("m/44'/60'/0'/0/0"),
+	}
+	// FlagKeystoreRemoteSigner address of the remote signing service.
+	FlagKeystoreRemoteSigner = cli.StringFlag{
+		Name:  "keystore.remote-signer",
+		Usage: "Address of the remote gRPC signing service holding identity keys, e.g. signer.example.com:8443. Local keystore is not used for signing when set",
+	}
+	// FlagKeystoreRemoteSignerCA CA certificate of the remote signing service.
+	FlagKeystoreRemoteSignerCA = cli.StringFlag{
+		Name:  "keystore.remote-signer.ca",
+		Usage: "Path to PEM encoded CA certificate of the remote signing service, system roots are used when empty",
+	}
+	// FlagKeystoreRemoteSignerCert client certificate for the remote signing service.
+	FlagKeystoreRemoteSignerCert = cli.StringFlag{
+		Name:  "keystore.remote-signer.cert",
+		Usage: "Path to PEM encoded client certificate used to authenticate with the remote signing service over mutual TLS (optional)",
+	}
+	// FlagKeystoreRemoteSignerKey client key for the remote signing service.
+	FlagKeystoreRemoteSignerKey = cli.StringFlag{
+		Name:  "keystore.remote-signer.key",
+		Usage: "Path to PEM encoded client key used to authenticate with the remote signing service over mutual TLS (optional)",
+	}
+	// FlagKeystoreRemoteSignerTimeout timeout of remote signing requests.
+	FlagKeystoreRemoteSignerTimeout = cli.DurationFlag{
+		Name:  "keystore.remote-signer.timeout",
+		Usage: "Timeout of a single remote signing request",
+		Value: 10 * time.Second,
+	}
 	// FlagLogHTTP enables HTTP payload logging.
 	FlagLogHTTP = cli.BoolFlag{
 		Name:  "log.http",
@@ -165,6 +219,42 @@ var (
 		),
 		Value: "https://quality.mysterium.network/api/v3",
 	}
+	// FlagQualityRefreshInterval proposal quality refresh interval.
+	FlagQualityRefreshInterval = cli.DurationFlag{
+		Name:  "quality.refresh-interval",
+		Usage: "How often to re-fetch proposal quality scores and re-rank cached proposals, 0 disables refresh",
+		Value: time.Minute,
+	}
+	// FlagQualityReportURL session quality sample collector URL.
+	FlagQualityReportURL = cli.StringFlag{
+		Name:  "quality.report-url",
+		Usage: "Collector endpoint to ship anonymized session quality samples to, reporting is disabled if empty",
+		Value: "",
+	}
+	// FlagQualityReportInterval session quality sample shipping interval.
+	FlagQualityReportInterval = cli.DurationFlag{
+		Name:  "quality.report-interval",
+		Usage: "How often to ship buffered session quality samples to the collector",
+		Value: 5 * time.Minute,
+	}
+	// FlagSpeedTestDownloadURL speed test download endpoint.
+	FlagSpeedTestDownloadURL = cli.StringFlag{
+		Name:  "speedtest.download-url",
+		Usage: "Endpoint serving a large body to measure download throughput of connections against",
+		Value: "https://speed.cloudflare.com/__down?bytes=1000000000",
+	}
+	// FlagSpeedTestUploadURL speed test upload endpoint.
+	FlagSpeedTestUploadURL = cli.StringFlag{
+		Name:  "speedtest.upload-url",
+		Usage: "Endpoint accepting POST requests to measure upload throughput of connections against",
+		Value: "https://speed.cloudflare.com/__up",
+	}
+	// FlagSpeedTestDuration speed test stage duration.
+	FlagSpeedTestDuration = cli.DurationFlag{
+		Name:  "speedtest.duration",
+		Usage: "How long each of the download and upload stages of connection speed test lasts",
+		Value: 10 * time.Second,
+	}
 	// FlagTequilapiAddress IP address of interface to listen for incoming connections.
 	FlagTequilapiAddress = cli.StringFlag{
 		Name:  "tequilapi.address",
@@ -201,6 +291,78 @@ var (
 		Usage: "Default password for API authentication",
 		Value: "mystberry",
 	}
+	// FlagTequilapiAuthRequired requires a token for all but public API requests.
+	FlagTequilapiAuthRequired = cli.BoolFlag{
+		Name:  "tequilapi.auth.required",
+		Usage: "Require a UI session or scoped API token for API requests. Enable when exposing API beyond localhost",
+		Value: false,
+	}
+	// FlagTequilapiRateLimits limits API requests per client.
+	FlagTequilapiRateLimits = cli.StringSliceFlag{
+		Name:  "tequilapi.rate-limits",
+		Usage: `API request limits per client separated by comma, formatted as "<method> <route>=<requests per minute>". Use "*" to match any method or route`,
+		Value: cli.NewStringSlice("* *=600", "POST /auth/login=10", "POST /auth/authenticate=10", "POST /auth/tokens=10"),
+	}
+	// FlagTequilapiAuditRetention sets how long API audit log entries are kept.
+	FlagTequilapiAuditRetention = cli.DurationFlag{
+		Name:  "tequilapi.audit.retention",
+		Usage: `How long to keep API audit log entries { "168h", "720h" }. Entries are kept forever if zero`,
+		Value: 30 * 24 * time.Hour,
+	}
+	// FlagStorageBackend selects the persistent storage backend.
+	FlagStorageBackend = cli.StringFlag{
+		Name:  "storage.backend",
+		Usage: "Persistent storage backend. Options: (bolt, sqlite - WAL mode for nodes with heavy session churn, requires a build with cgo and -tags sqlite, memory - nothing is kept between restarts)",
+		Value: "bolt",
+	}
+	// FlagStorageEncrypt encrypts the storage with a key derived from the storage passphrase.
+	FlagStorageEncrypt = cli.BoolFlag{
+		Name:  "storage.encrypt",
+		Usage: "Encrypt stored values with a key derived from storage.passphrase, or identity.passphrase if it is not set. Bucket names, record keys and indexes stay in plaintext. Existing data is encrypted on the first start",
+		Value: false,
+	}
+	// FlagStoragePassphrase sets the passphrase the storage encryption key is derived from.
+	FlagStoragePassphrase = cli.StringFlag{
+		Name:  "storage.passphrase",
+		Usage: "Passphrase to derive the storage encryption key from",
+		Value: "",
+	}
+	// FlagStorageRetentionSessions sets how long session history is kept.
+	FlagStorageRetentionSessions = cli.DurationFlag{
+		Name:  "storage.retention.sessions",
+		Usage: "How long to keep session history. Sessions are kept forever if zero",
+		Value: 90 * 24 * time.Hour,
+	}
+	// FlagStorageRetentionSettlements sets how long settlement history is kept.
+	FlagStorageRetentionSettlements = cli.DurationFlag{
+		Name:  "storage.retention.settlements",
+		Usage: "How long to keep settlement history. Settlements are kept forever if zero",
+		Value: 0,
+	}
+	// FlagStorageRetentionSLOActions sets how long records of self-healing actions are kept.
+	FlagStorageRetentionSLOActions = cli.DurationFlag{
+		Name:  "storage.retention.slo-actions",
+		Usage: "How long to keep records of self-healing actions. Records are kept forever if zero",
+		Value: 30 * 24 * time.Hour,
+	}
+	// FlagStorageRetentionTraffic sets how long daily provider traffic totals are kept.
+	FlagStorageRetentionTraffic = cli.DurationFlag{
+		Name:  "storage.retention.traffic",
+		Usage: "How long to keep daily provider traffic totals. Totals are kept forever if zero",
+		Value: 400 * 24 * time.Hour,
+	}
+	// FlagStorageRetentionSelfChecks sets how long provider self-check history is kept.
+	FlagStorageRetentionSelfChecks = cli.DurationFlag{
+		Name:  "storage.retention.self-checks",
+		Usage: "How long to keep provider self-check history. History is kept forever if zero",
+		Value: 90 * 24 * time.Hour,
+	}
+	// FlagStorageCompactionInterval sets how often the storage is compacted.
+	FlagStorageCompactionInterval = cli.DurationFlag{
+		Name:  "storage.compaction-interval",
+		Usage: "How often to compact the storage to reclaim space of deleted data. Never compacted if zero",
+		Value: 7 * 24 * time.Hour,
+	}
 	// FlagPProfEnable enables pprof via TequilAPI.
 	FlagPProfEnable = cli.BoolFlag{
 		Name:  "pprof.enable",
@@ -310,13 +472,28 @@ func RegisterFlagsNode(flags *[]cli.Flag) error {
 	RegisterFlagsChains(flags)
 	RegisterFlagsUI(flags)
 	RegisterFlagsBlockchainNetwork(flags)
+	RegisterFlagsProfile(flags)
 	RegisterFlagsSSE(flags)
+	RegisterFlagsEvents(flags)
+	RegisterFlagsProposalsFeed(flags)
+	RegisterFlagsCapacity(flags)
+	RegisterFlagsEgress(flags)
+	RegisterFlagsAbuse(flags)
+	RegisterFlagsMonitoring(flags)
+	RegisterFlagsTraffic(flags)
+	RegisterFlagsTracing(flags)
+	RegisterFlagsUpdater(flags)
+	RegisterFlagsSync(flags)
+	RegisterFlagsGRPC(flags)
+	RegisterFlagsRemoteManagement(flags)
 
 	*flags = append(*flags,
 		&FlagBindAddress,
 		&FlagDiscoveryType,
 		&FlagDiscoveryPingInterval,
 		&FlagDiscoveryFetchInterval,
+		&FlagDiscoveryCacheTTL,
+		&FlagDiscoveryRequireSignedProposals,
 		&FlagDHTAddress,
 		&FlagDHTPort,
 		&FlagDHTProtocol,
@@ -327,17 +504,43 @@ func RegisterFlagsNode(flags *[]cli.Flag) error {
 		&FlagShaperEnabled,
 		&FlagShaperBandwidth,
 		&FlagKeystoreLightweight,
+		&FlagKeystoreKeychain,
+		&FlagKeystoreHardwareWallet,
+		&FlagKeystoreHardwareWalletPaths,
+		&FlagKeystoreRemoteSigner,
+		&FlagKeystoreRemoteSignerCA,
+		&FlagKeystoreRemoteSignerCert,
+		&FlagKeystoreRemoteSignerKey,
+		&FlagKeystoreRemoteSignerTimeout,
 		&FlagLogHTTP,
 		&FlagLogLevel,
 		&FlagVerbose,
 		&FlagOpenvpnBinary,
 		&FlagQualityType,
 		&FlagQualityAddress,
+		&FlagQualityRefreshInterval,
+		&FlagQualityReportURL,
+		&FlagQualityReportInterval,
+		&FlagSpeedTestDownloadURL,
+		&FlagSpeedTestUploadURL,
+		&FlagSpeedTestDuration,
 		&FlagTequilapiAddress,
 		&FlagTequilapiAllowedHostnames,
 		&FlagTequilapiPort,
 		&FlagTequilapiUsername,
 		&FlagTequilapiPassword,
+		&FlagTequilapiAuthRequired,
+		&FlagTequilapiRateLimits,
+		&FlagTequilapiAuditRetention,
+		&FlagStorageBackend,
+		&FlagStorageEncrypt,
+		&FlagStoragePassphrase,
+		&FlagStorageRetentionSessions,
+		&FlagStorageRetentionSettlements,
+		&FlagStorageRetentionSLOActions,
+		&FlagStorageRetentionTraffic,
+		&FlagStorageRetentionSelfChecks,
+		&FlagStorageCompactionInterval,
 		&FlagPProfEnable,
 		&FlagUserMode,
 		&FlagDVPNMode,
@@ -371,13 +574,29 @@ func ParseFlagsNode(ctx *cli.Context) {
 	ParseFlagsChains(ctx)
 	ParseFlagsUI(ctx)
 	ParseFlagsSSE(ctx)
+	ParseFlagsEvents(ctx)
+	ParseFlagsProposalsFeed(ctx)
+	ParseFlagsCapacity(ctx)
+	ParseFlagsEgress(ctx)
+	ParseFlagsAbuse(ctx)
+	ParseFlagsMonitoring(ctx)
+	ParseFlagsTraffic(ctx)
+	ParseFlagsTracing(ctx)
+	ParseFlagsUpdater(ctx)
+	ParseFlagsSync(ctx)
+	ParseFlagsGRPC(ctx)
+	ParseFlagsRemoteManagement(ctx)
 	//it is important to have this one at the end so it overwrites defaults correctly
 	ParseFlagsBlockchainNetwork(ctx)
+	// profile may switch the network, so it goes after network defaults are set
+	ParseFlagsProfile(ctx)
 
 	Current.ParseStringFlag(ctx, FlagBindAddress)
 	Current.ParseStringSliceFlag(ctx, FlagDiscoveryType)
 	Current.ParseDurationFlag(ctx, FlagDiscoveryPingInterval)
 	Current.ParseDurationFlag(ctx, FlagDiscoveryFetchInterval)
+	Current.ParseDurationFlag(ctx, FlagDiscoveryCacheTTL)
+	Current.ParseBoolFlag(ctx, FlagDiscoveryRequireSignedProposals)
 	Current.ParseStringFlag(ctx, FlagDHTAddress)
 	Current.ParseIntFlag(ctx, FlagDHTPort)
 	Current.ParseStringFlag(ctx, FlagDHTProtocol)
@@ -388,17 +607,43 @@ func ParseFlagsNode(ctx *cli.Context) {
 	Current.ParseBoolFlag(ctx, FlagShaperEnabled)
 	Current.ParseUInt64Flag(ctx, FlagShaperBandwidth)
 	Current.ParseBoolFlag(ctx, FlagKeystoreLightweight)
+	Current.ParseBoolFlag(ctx, FlagKeystoreKeychain)
+	Current.ParseStringFlag(ctx, FlagKeystoreHardwareWallet)
+	Current.ParseStringSliceFlag(ctx, FlagKeystoreHardwareWalletPaths)
+	Current.ParseStringFlag(ctx, FlagKeystoreRemoteSigner)
+	Current.ParseStringFlag(ctx, FlagKeystoreRemoteSignerCA)
+	Current.ParseStringFlag(ctx, FlagKeystoreRemoteSignerCert)
+	Current.ParseStringFlag(ctx, FlagKeystoreRemoteSignerKey)
+	Current.ParseDurationFlag(ctx, FlagKeystoreRemoteSignerTimeout)
 	Current.ParseBoolFlag(ctx, FlagLogHTTP)
 	Current.ParseBoolFlag(ctx, FlagVerbose)
 	Current.ParseStringFlag(ctx, FlagLogLevel)
 	Current.ParseStringFlag(ctx, FlagOpenvpnBinary)
 	Current.ParseStringFlag(ctx, FlagQualityAddress)
 	Current.ParseStringFlag(ctx, FlagQualityType)
+	Current.ParseDurationFlag(ctx, FlagQualityRefreshInterval)
+	Current.ParseStringFlag(ctx, FlagQualityReportURL)
+	Current.ParseDurationFlag(ctx, FlagQualityReportInterval)
+	Current.ParseStringFlag(ctx, FlagSpeedTestDownloadURL)
+	Current.ParseStringFlag(ctx, FlagSpeedTestUploadURL)
+	Current.ParseDurationFlag(ctx, FlagSpeedTestDuration)
 	Current.ParseStringFlag(ctx, FlagTequilapiAddress)
 	Current.ParseStringFlag(ctx, FlagTequilapiAllowedHostnames)
 	Current.ParseIntFlag(ctx, FlagTequilapiPort)
 	Current.ParseStringFlag(ctx, FlagTequilapiUsername)
 	Current.ParseStringFlag(ctx, FlagTequilapiPassword)
+	Current.ParseBoolFlag(ctx, FlagTequilapiAuthRequired)
+	Current.ParseStringSliceFlag(ctx, FlagTequilapiRateLimits)
+	Current.ParseDurationFlag(ctx, FlagTequilapiAuditRetention)
+	Current.ParseStringFlag(ctx, FlagStorageBackend)
+	Current.ParseBoolFlag(ctx, FlagStorageEncrypt)
+	Current.ParseStringFlag(ctx, FlagStoragePassphrase)
+	Current.ParseDurationFlag(ctx, FlagStorageRetentionSessions)
+	Current.ParseDurationFlag(ctx, FlagStorageRetentionSettlements)
+	Current.ParseDurationFlag(ctx, FlagStorageRetentionSLOActions)
+	Current.ParseDurationFlag(ctx, FlagStorageRetentionTraffic)
+	Current.ParseDurationFlag(ctx, FlagStorageRetentionSelfChecks)
+	Current.ParseDurationFlag(ctx, FlagStorageCompactionInterval)
 	Current.ParseBoolFlag(ctx, FlagPProfEnable)
 	Current.ParseBoolFlag(ctx, FlagUserMode)
 	Current.ParseBoolFlag(ctx, FlagDVPNMode)
@@ -124,6 +124,12 @@ var (
 		Usage:  "sets the hermes status recheck interval. Setting this to a lower value will decrease potential loss in case of Hermes getting locked.",
 		Value:  time.Hour * 2,
 	}
+	// FlagPaymentsHermesMinChannelSize sets the lowest hermes max channel size in wei the provider still accepts.
+	FlagPaymentsHermesMinChannelSize = cli.StringFlag{
+		Name:  "payments.provider.hermes-min-channel-size",
+		Usage: "sets the lowest max channel size (in wei) accepted when hermes changes its terms. Hermes offering less is paused. 0 disables the check",
+		Value: "0",
+	}
 	// FlagOffchainBalanceExpiration sets how often we re-check offchain balance on hermes when balance is depleting
 	FlagOffchainBalanceExpiration = cli.DurationFlag{
 		Hidden: true,
@@ -182,6 +188,27 @@ var (
 		Value: time.Minute * 5,
 		Usage: "Determines how often the provider sends invoices.",
 	}
+
+	// FlagPaymentsPromptPaymentLatency sets how fast a consumer has to pay an invoice for the payment to count as prompt.
+	FlagPaymentsPromptPaymentLatency = cli.DurationFlag{
+		Name:  "payments.provider.prompt-payment-latency",
+		Value: time.Second * 10,
+		Usage: "Invoices paid faster than this grow the invoice frequency and unpaid value of the session up to their limits, slower payments shrink them back",
+	}
+
+	// FlagPaymentsTrustedConsumerPayments sets how many prompt payments in a row make a consumer trusted.
+	FlagPaymentsTrustedConsumerPayments = cli.IntFlag{
+		Name:  "payments.provider.trusted-consumer-payments",
+		Value: 10,
+		Usage: "Number of invoices a consumer has to pay promptly in a row for its new sessions to start with the previously reached invoice window",
+	}
+
+	// FlagPaymentsConsumerSessionKeyTTL sets the lifetime of session keys signing consumer payments.
+	FlagPaymentsConsumerSessionKeyTTL = cli.DurationFlag{
+		Name:  "payments.consumer.session-key-ttl",
+		Value: 0,
+		Usage: "Sign payments with short-lived session keys delegated by the identity and valid for the given time (24h at most) instead of the identity key, if hermes supports them. Zero signs with the identity key",
+	}
 )
 
 // RegisterFlagsPayments function register payments flags to flag list.
@@ -202,6 +229,7 @@ func RegisterFlagsPayments(flags *[]cli.Flag) {
 		&FlagPaymentsRegistryTransactorPollInterval,
 		&FlagPaymentsConsumerDataLeewayMegabytes,
 		&FlagPaymentsHermesStatusRecheckInterval,
+		&FlagPaymentsHermesMinChannelSize,
 		&FlagOffchainBalanceExpiration,
 		&FlagPaymentsZeroStakeUnsettledAmount,
 		&FlagPaymentsDuringSessionDebug,
@@ -213,6 +241,11 @@ func RegisterFlagsPayments(flags *[]cli.Flag) {
 
 		&FlagPaymentsUnpaidInvoiceValue,
 		&FlagPaymentsLimitUnpaidInvoiceValue,
+
+		&FlagPaymentsPromptPaymentLatency,
+		&FlagPaymentsTrustedConsumerPayments,
+
+		&FlagPaymentsConsumerSessionKeyTTL,
 	)
 }
 
@@ -233,6 +266,7 @@ func ParseFlagsPayments(ctx *cli.Context) {
 	Current.ParseDurationFlag(ctx, FlagPaymentsRegistryTransactorPollTimeout)
 	Current.ParseUInt64Flag(ctx, FlagPaymentsConsumerDataLeewayMegabytes)
 	Current.ParseDurationFlag(ctx, FlagPaymentsHermesStatusRecheckInterval)
+	Current.ParseStringFlag(ctx, FlagPaymentsHermesMinChannelSize)
 	Current.ParseDurationFlag(ctx, FlagOffchainBalanceExpiration)
 	Current.ParseFloat64Flag(ctx, FlagPaymentsZeroStakeUnsettledAmount)
 	Current.ParseBoolFlag(ctx, FlagPaymentsDuringSessionDebug)
@@ -244,4 +278,9 @@ func ParseFlagsPayments(ctx *cli.Context) {
 
 	Current.ParseStringFlag(ctx, FlagPaymentsLimitUnpaidInvoiceValue)
 	Current.ParseStringFlag(ctx, FlagPaymentsUnpaidInvoiceValue)
+
+	Current.ParseDurationFlag(ctx, FlagPaymentsPromptPaymentLatency)
+	Current.ParseIntFlag(ctx, FlagPaymentsTrustedConsumerPayments)
+
+	Current.ParseDurationFlag(ctx, FlagPaymentsConsumerSessionKeyTTL)
 }
@@ -37,6 +37,24 @@ var (
 		Usage: `Proposal fetch interval { "30s", "3m", "1h20m30s" }`,
 		Value: 10 * time.Minute,
 	}
+	// FlagAccessPolicyConsumerAllowlist sets remote lists of the only consumers allowed to start sessions.
+	FlagAccessPolicyConsumerAllowlist = cli.StringSliceFlag{
+		Name:  "access-policy.consumer-allowlist",
+		Usage: "URLs or file paths of consumer identity lists, one identity per line. Only listed consumers can start sessions when set",
+		Value: cli.NewStringSlice(),
+	}
+	// FlagAccessPolicyConsumerBlocklist sets remote lists of consumers not allowed to start sessions.
+	FlagAccessPolicyConsumerBlocklist = cli.StringSliceFlag{
+		Name:  "access-policy.consumer-blocklist",
+		Usage: "URLs or file paths of consumer identity lists, one identity per line. Listed consumers can not start sessions",
+		Value: cli.NewStringSlice(),
+	}
+	// FlagAccessPolicyConsumerListsUpdateInterval sets how often remote consumer lists are reloaded.
+	FlagAccessPolicyConsumerListsUpdateInterval = cli.DurationFlag{
+		Name:  "access-policy.consumer-lists.update-interval",
+		Usage: "Duration between remote consumer allowlist and blocklist updates",
+		Value: time.Hour,
+	}
 )
 
 // RegisterFlagsPolicy function registers Policy Oracle flags to flag list.
@@ -44,6 +62,9 @@ func RegisterFlagsPolicy(flags *[]cli.Flag) {
 	*flags = append(*flags,
 		&FlagAccessPolicyAddress,
 		&FlagAccessPolicyFetchInterval,
+		&FlagAccessPolicyConsumerAllowlist,
+		&FlagAccessPolicyConsumerBlocklist,
+		&FlagAccessPolicyConsumerListsUpdateInterval,
 	)
 }
 
@@ -51,4 +72,7 @@ func RegisterFlagsPolicy(flags *[]cli.Flag) {
 func ParseFlagsPolicy(ctx *cli.Context) {
 	Current.ParseStringFlag(ctx, FlagAccessPolicyAddress)
 	Current.ParseDurationFlag(ctx, FlagAccessPolicyFetchInterval)
+	Current.ParseStringSliceFlag(ctx, FlagAccessPolicyConsumerAllowlist)
+	Current.ParseStringSliceFlag(ctx, FlagAccessPolicyConsumerBlocklist)
+	Current.ParseDurationFlag(ctx, FlagAccessPolicyConsumerListsUpdateInterval)
 }
@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+)
+
+// FlagProfile selects a named configuration profile.
+var FlagProfile = cli.StringFlag{
+	Name:  "profile",
+	Usage: "Configuration profile layered over defaults: mainnet, testnet, home-provider or a custom one defined in user configuration under [profiles.<name>]",
+}
+
+// RegisterFlagsProfile function registers configuration profile flags to flag list.
+func RegisterFlagsProfile(flags *[]cli.Flag) {
+	*flags = append(*flags, &FlagProfile)
+}
+
+// ParseFlagsProfile selects the configuration profile given by CLI flag or user configuration.
+func ParseFlagsProfile(ctx *cli.Context) {
+	Current.ParseStringFlag(ctx, FlagProfile)
+	if err := Current.UseProfile(GetString(FlagProfile)); err != nil {
+		log.Err(err).Msg("Invalid configuration profile, ignoring")
+	}
+}
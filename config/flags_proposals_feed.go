@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagProposalsFeedTargets sets where the proposals feed is exported to.
+	FlagProposalsFeedTargets = cli.StringSliceFlag{
+		Name:  "proposals-feed.targets",
+		Usage: `Directories or S3-compatible buckets ("s3://bucket/prefix") to export proposals feed to, separated by comma. Empty disables the export`,
+		Value: cli.NewStringSlice(),
+	}
+	// FlagProposalsFeedInterval sets how often the proposals feed is exported.
+	FlagProposalsFeedInterval = cli.DurationFlag{
+		Name:  "proposals-feed.interval",
+		Usage: "How often to export proposals feed",
+		Value: 5 * time.Minute,
+	}
+	// FlagProposalsFeedServiceType filters exported proposals by service type.
+	FlagProposalsFeedServiceType = cli.StringFlag{
+		Name:  "proposals-feed.service-type",
+		Usage: "Export only proposals of the given service type, all service types are exported if empty",
+	}
+	// FlagProposalsFeedCountry filters exported proposals by provider country.
+	FlagProposalsFeedCountry = cli.StringFlag{
+		Name:  "proposals-feed.country",
+		Usage: "Export only proposals of providers located in the given country (ISO 3166-1 alpha-2 code)",
+	}
+	// FlagProposalsFeedQualityMin filters exported proposals by quality.
+	FlagProposalsFeedQualityMin = cli.Float64Flag{
+		Name:  "proposals-feed.quality-min",
+		Usage: "Export only proposals with quality not lower than the given one",
+	}
+	// FlagProposalsFeedS3Endpoint sets the endpoint of S3-compatible storage.
+	FlagProposalsFeedS3Endpoint = cli.StringFlag{
+		Name:  "proposals-feed.s3-endpoint",
+		Usage: "Endpoint URL of S3-compatible storage, AWS S3 is used if empty. Credentials are read from the standard AWS environment variables",
+	}
+	// FlagProposalsFeedS3Region sets the region of S3-compatible storage.
+	FlagProposalsFeedS3Region = cli.StringFlag{
+		Name:  "proposals-feed.s3-region",
+		Usage: "Region of S3-compatible storage",
+		Value: "us-east-1",
+	}
+)
+
+// RegisterFlagsProposalsFeed function register proposals feed flags to flag list
+func RegisterFlagsProposalsFeed(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagProposalsFeedTargets,
+		&FlagProposalsFeedInterval,
+		&FlagProposalsFeedServiceType,
+		&FlagProposalsFeedCountry,
+		&FlagProposalsFeedQualityMin,
+		&FlagProposalsFeedS3Endpoint,
+		&FlagProposalsFeedS3Region,
+	)
+}
+
+// ParseFlagsProposalsFeed function fills in proposals feed options from CLI context
+func ParseFlagsProposalsFeed(ctx *cli.Context) {
+	Current.ParseStringSliceFlag(ctx, FlagProposalsFeedTargets)
+	Current.ParseDurationFlag(ctx, FlagProposalsFeedInterval)
+	Current.ParseStringFlag(ctx, FlagProposalsFeedServiceType)
+	Current.ParseStringFlag(ctx, FlagProposalsFeedCountry)
+	Current.ParseFloat64Flag(ctx, FlagProposalsFeedQualityMin)
+	Current.ParseStringFlag(ctx, FlagProposalsFeedS3Endpoint)
+	Current.ParseStringFlag(ctx, FlagProposalsFeedS3Region)
+}
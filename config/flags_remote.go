@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagRemoteManagementEnabled enables the remote management listener of tequilapi.
+	FlagRemoteManagementEnabled = cli.BoolFlag{
+		Name:  "tequilapi.remote.enabled",
+		Usage: "Serve API over TLS on a non-local interface for managing the node from another machine. Remote requests always require a token",
+		Value: false,
+	}
+	// FlagRemoteManagementAddress sets the address of the remote management listener.
+	FlagRemoteManagementAddress = cli.StringFlag{
+		Name:  "tequilapi.remote.address",
+		Usage: "IP address to bind remote management API to",
+		Value: "0.0.0.0",
+	}
+	// FlagRemoteManagementPort sets the port of the remote management listener.
+	FlagRemoteManagementPort = cli.IntFlag{
+		Name:  "tequilapi.remote.port",
+		Usage: "Port for listening incoming remote management API requests",
+		Value: 4449,
+	}
+	// FlagRemoteManagementCertFile sets the TLS certificate served to remote clients.
+	FlagRemoteManagementCertFile = cli.StringFlag{
+		Name:  "tequilapi.remote.tls-cert",
+		Usage: "PEM encoded TLS certificate file. A self-signed certificate is generated when neither certificate nor ACME domain is set",
+		Value: "",
+	}
+	// FlagRemoteManagementKeyFile sets the private key of the TLS certificate served to remote clients.
+	FlagRemoteManagementKeyFile = cli.StringFlag{
+		Name:  "tequilapi.remote.tls-key",
+		Usage: "PEM encoded private key file of the TLS certificate",
+		Value: "",
+	}
+	// FlagRemoteManagementACMEDomain sets the domain for which a certificate is obtained via ACME.
+	FlagRemoteManagementACMEDomain = cli.StringFlag{
+		Name:  "tequilapi.remote.acme-domain",
+		Usage: "Domain to obtain a Let's Encrypt certificate for. Remote management port must be reachable as 443 for the challenge",
+		Value: "",
+	}
+	// FlagRemoteManagementClientCA requires remote clients to present certificates signed by given CA.
+	FlagRemoteManagementClientCA = cli.StringFlag{
+		Name:  "tequilapi.remote.client-ca",
+		Usage: "PEM encoded CA certificates file. When set, remote clients must present a certificate signed by one of them",
+		Value: "",
+	}
+	// FlagRemoteManagementAllowedIPs restricts remote clients to given networks.
+	FlagRemoteManagementAllowedIPs = cli.StringSliceFlag{
+		Name:  "tequilapi.remote.allowed-ips",
+		Usage: "IP addresses or CIDR networks allowed to connect to remote management API, separated by comma. All are allowed when empty",
+		Value: cli.NewStringSlice(),
+	}
+)
+
+// RegisterFlagsRemoteManagement function register remote management flags to flag list
+func RegisterFlagsRemoteManagement(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagRemoteManagementEnabled,
+		&FlagRemoteManagementAddress,
+		&FlagRemoteManagementPort,
+		&FlagRemoteManagementCertFile,
+		&FlagRemoteManagementKeyFile,
+		&FlagRemoteManagementACMEDomain,
+		&FlagRemoteManagementClientCA,
+		&FlagRemoteManagementAllowedIPs,
+	)
+}
+
+// ParseFlagsRemoteManagement function fills in remote management options from CLI context
+func ParseFlagsRemoteManagement(ctx *cli.Context) {
+	Current.ParseBoolFlag(ctx, FlagRemoteManagementEnabled)
+	Current.ParseStringFlag(ctx, FlagRemoteManagementAddress)
+	Current.ParseIntFlag(ctx, FlagRemoteManagementPort)
+	Current.ParseStringFlag(ctx, FlagRemoteManagementCertFile)
+	Current.ParseStringFlag(ctx, FlagRemoteManagementKeyFile)
+	Current.ParseStringFlag(ctx, FlagRemoteManagementACMEDomain)
+	Current.ParseStringFlag(ctx, FlagRemoteManagementClientCA)
+	Current.ParseStringSliceFlag(ctx, FlagRemoteManagementAllowedIPs)
+}
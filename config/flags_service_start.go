@@ -18,6 +18,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/urfave/cli/v2"
 )
 
@@ -66,6 +68,113 @@ var (
 		Name:  "active-services",
 		Usage: "Comma separated list of active services.",
 	}
+	// FlagServiceIdentities binds services to provider identities.
+	FlagServiceIdentities = cli.StringSliceFlag{
+		Name:  "service.identities",
+		Usage: `Provider identities of services in "<service type>=<identity>" format, e.g. "wireguard=0x...,scraping=0x...". Services without binding run under the main identity`,
+		Value: cli.NewStringSlice(),
+	}
+	// FlagServiceIdentityPassphrases sets passphrases of identities bound to services.
+	FlagServiceIdentityPassphrases = cli.StringSliceFlag{
+		Name:  "service.identity-passphrases",
+		Usage: `Passphrases of service identities in "<identity>=<passphrase>" format. Identities without passphrase are unlocked with --identity.passphrase or prompted for it when running in a terminal`,
+		Value: cli.NewStringSlice(),
+	}
+
+	// FlagSLOEnabled enables service level objectives monitoring with self-healing actions.
+	FlagSLOEnabled = cli.BoolFlag{
+		Name:  "slo.enabled",
+		Usage: "Monitor provider service level objectives and run self-healing actions on breach",
+		Value: false,
+	}
+	// FlagSLOMinSessionSuccessRate sets the lowest acceptable share of sessions acknowledged by consumers.
+	FlagSLOMinSessionSuccessRate = cli.Float64Flag{
+		Name:  "slo.min-session-success-rate",
+		Usage: "Lowest acceptable share of sessions acknowledged by consumers, 0..1",
+		Value: 0.5,
+	}
+	// FlagSLOMaxMedianTTFB sets the highest acceptable median time to the first transferred byte.
+	FlagSLOMaxMedianTTFB = cli.DurationFlag{
+		Name:  "slo.max-median-ttfb",
+		Usage: "Highest acceptable median time from session creation to the first transferred byte",
+		Value: 15 * time.Second,
+	}
+	// FlagSLOMaxPaymentFailureRate sets the highest acceptable share of sessions with failed payments.
+	FlagSLOMaxPaymentFailureRate = cli.Float64Flag{
+		Name:  "slo.max-payment-failure-rate",
+		Usage: "Highest acceptable share of sessions with failed payments, 0..1",
+		Value: 0.3,
+	}
+	// FlagSLOActions sets self-healing actions run on breach.
+	FlagSLOActions = cli.StringSliceFlag{
+		Name:  "slo.actions",
+		Usage: "Self-healing actions run one per breach in the given order: redetect-nat, reregister-proposal, restart-service",
+		Value: cli.NewStringSlice("redetect-nat", "reregister-proposal", "restart-service"),
+	}
+
+	// FlagSelfCheckEnabled enables periodic end-to-end self-checks of provider services.
+	FlagSelfCheckEnabled = cli.BoolFlag{
+		Name:  "selfcheck.enabled",
+		Usage: "Periodically check that running services can be discovered, dialed and pinged by a test consumer identity",
+		Value: false,
+	}
+	// FlagSelfCheckInterval sets how often provider services are self-checked.
+	FlagSelfCheckInterval = cli.DurationFlag{
+		Name:  "selfcheck.interval",
+		Usage: "How often running services are self-checked",
+		Value: 10 * time.Minute,
+	}
+	// FlagSelfCheckFailureThreshold sets the number of consecutive failures after which the self-check failure is reported.
+	FlagSelfCheckFailureThreshold = cli.IntFlag{
+		Name:  "selfcheck.failure-threshold",
+		Usage: "Number of consecutive self-check failures of a service after which webhooks are alerted",
+		Value: 3,
+	}
+
+	// FlagScheduleFeedURL sets the URL of JSON signal feed (e.g. spot electricity price) services follow.
+	FlagScheduleFeedURL = cli.StringFlag{
+		Name:  "schedule.feed-url",
+		Usage: "URL of JSON signal feed (e.g. spot electricity price) used to pause and resume services, empty disables scheduling",
+		Value: "",
+	}
+	// FlagScheduleFeedPath sets the path of the signal value in the feed document.
+	FlagScheduleFeedPath = cli.StringFlag{
+		Name:  "schedule.feed-path",
+		Usage: `Dot separated path to the signal value in the feed document, e.g. "data.0.price"`,
+		Value: "",
+	}
+	// FlagScheduleInterval sets how often the signal feed is polled.
+	FlagScheduleInterval = cli.DurationFlag{
+		Name:  "schedule.interval",
+		Usage: "How often the signal feed is polled",
+		Value: 5 * time.Minute,
+	}
+	// FlagScheduleRules sets rules mapping signal values to serve or pause actions.
+	FlagScheduleRules = cli.StringSliceFlag{
+		Name:  "schedule.rules",
+		Usage: `Rules in "<min>..<max>=<action>" format mapping signal values to serve or pause actions, first matching rule wins, e.g. "..0.15=serve,0.15..=pause"`,
+		Value: cli.NewStringSlice(),
+	}
+
+	// FlagServiceMaxSessions limits concurrent sessions of a service.
+	FlagServiceMaxSessions = cli.IntFlag{
+		Name:  "service.max-sessions",
+		Usage: "Maximum number of concurrent consumer sessions per service, 0 means unlimited",
+		Value: 0,
+	}
+
+	// FlagSessionIDGenerator sets the way provider session IDs are generated.
+	FlagSessionIDGenerator = cli.StringFlag{
+		Name:  "session.id-generator",
+		Usage: "Provider session ID generator: uuidv4 (random), uuidv7 (time ordered) or hmac (derived using session.id-secret)",
+		Value: "uuidv4",
+	}
+	// FlagSessionIDSecret sets the secret key for HMAC derived session IDs.
+	FlagSessionIDSecret = cli.StringFlag{
+		Name:  "session.id-secret",
+		Usage: "Secret key used to derive session IDs by hmac generator",
+		Value: "",
+	}
 )
 
 // RegisterFlagsServiceStart registers CLI flags used to start a service.
@@ -78,6 +187,23 @@ func RegisterFlagsServiceStart(flags *[]cli.Flag) {
 		&FlagPaymentPriceHour,
 		&FlagAccessPolicyList,
 		&FlagActiveServices,
+		&FlagServiceIdentities,
+		&FlagServiceIdentityPassphrases,
+		&FlagSLOEnabled,
+		&FlagSLOMinSessionSuccessRate,
+		&FlagSLOMaxMedianTTFB,
+		&FlagSLOMaxPaymentFailureRate,
+		&FlagSLOActions,
+		&FlagSelfCheckEnabled,
+		&FlagSelfCheckInterval,
+		&FlagSelfCheckFailureThreshold,
+		&FlagScheduleFeedURL,
+		&FlagScheduleFeedPath,
+		&FlagScheduleInterval,
+		&FlagScheduleRules,
+		&FlagServiceMaxSessions,
+		&FlagSessionIDGenerator,
+		&FlagSessionIDSecret,
 	)
 }
 
@@ -90,4 +216,21 @@ func ParseFlagsServiceStart(ctx *cli.Context) {
 	Current.ParseFloat64Flag(ctx, FlagPaymentPriceHour)
 	Current.ParseStringFlag(ctx, FlagAccessPolicyList)
 	Current.ParseStringFlag(ctx, FlagActiveServices)
+	Current.ParseStringSliceFlag(ctx, FlagServiceIdentities)
+	Current.ParseStringSliceFlag(ctx, FlagServiceIdentityPassphrases)
+	Current.ParseBoolFlag(ctx, FlagSLOEnabled)
+	Current.ParseFloat64Flag(ctx, FlagSLOMinSessionSuccessRate)
+	Current.ParseDurationFlag(ctx, FlagSLOMaxMedianTTFB)
+	Current.ParseFloat64Flag(ctx, FlagSLOMaxPaymentFailureRate)
+	Current.ParseStringSliceFlag(ctx, FlagSLOActions)
+	Current.ParseBoolFlag(ctx, FlagSelfCheckEnabled)
+	Current.ParseDurationFlag(ctx, FlagSelfCheckInterval)
+	Current.ParseIntFlag(ctx, FlagSelfCheckFailureThreshold)
+	Current.ParseStringFlag(ctx, FlagScheduleFeedURL)
+	Current.ParseStringFlag(ctx, FlagScheduleFeedPath)
+	Current.ParseDurationFlag(ctx, FlagScheduleInterval)
+	Current.ParseStringSliceFlag(ctx, FlagScheduleRules)
+	Current.ParseIntFlag(ctx, FlagServiceMaxSessions)
+	Current.ParseStringFlag(ctx, FlagSessionIDGenerator)
+	Current.ParseStringFlag(ctx, FlagSessionIDSecret)
 }
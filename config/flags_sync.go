@@ -0,0 +1,110 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagSyncURL is the remote storage consumer state is replicated to.
+	FlagSyncURL = cli.StringFlag{
+		Name:  "sync.url",
+		Usage: `Remote storage to replicate consumer state to, either a WebDAV collection "https://dav.example.com/mysterium/" or S3 "s3://bucket/prefix". Sync is disabled if empty`,
+		Value: "",
+	}
+	// FlagSyncPassphrase is the passphrase the state is encrypted with before it leaves the node.
+	FlagSyncPassphrase = cli.StringFlag{
+		Name:  "sync.passphrase",
+		Usage: "Passphrase to encrypt the replicated state with, the same on every device",
+		Value: "",
+	}
+	// FlagSyncInterval sets how often the state is synchronized.
+	FlagSyncInterval = cli.DurationFlag{
+		Name:  "sync.interval",
+		Usage: "How often to synchronize state with the remote storage",
+		Value: 15 * time.Minute,
+	}
+	// FlagSyncUsername is the WebDAV username.
+	FlagSyncUsername = cli.StringFlag{
+		Name:  "sync.username",
+		Usage: "WebDAV username",
+		Value: "",
+	}
+	// FlagSyncPassword is the WebDAV password.
+	FlagSyncPassword = cli.StringFlag{
+		Name:  "sync.password",
+		Usage: "WebDAV password",
+		Value: "",
+	}
+	// FlagSyncS3AccessKey is the S3 access key ID.
+	FlagSyncS3AccessKey = cli.StringFlag{
+		Name:  "sync.s3.access-key",
+		Usage: "S3 access key ID",
+		Value: "",
+	}
+	// FlagSyncS3SecretKey is the S3 secret access key.
+	FlagSyncS3SecretKey = cli.StringFlag{
+		Name:  "sync.s3.secret-key",
+		Usage: "S3 secret access key",
+		Value: "",
+	}
+	// FlagSyncS3Region is the S3 region.
+	FlagSyncS3Region = cli.StringFlag{
+		Name:  "sync.s3.region",
+		Usage: "S3 region",
+		Value: "us-east-1",
+	}
+	// FlagSyncS3Endpoint is the endpoint of S3 compatible storage.
+	FlagSyncS3Endpoint = cli.StringFlag{
+		Name:  "sync.s3.endpoint",
+		Usage: `Endpoint of S3 compatible storage, e.g. "https://minio.example.com". AWS endpoint of the region is used if empty`,
+		Value: "",
+	}
+)
+
+// RegisterFlagsSync function register state sync flags to flag list
+func RegisterFlagsSync(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagSyncURL,
+		&FlagSyncPassphrase,
+		&FlagSyncInterval,
+		&FlagSyncUsername,
+		&FlagSyncPassword,
+		&FlagSyncS3AccessKey,
+		&FlagSyncS3SecretKey,
+		&FlagSyncS3Region,
+		&FlagSyncS3Endpoint,
+	)
+}
+
+// ParseFlagsSync function fills in state sync options from CLI context
+func ParseFlagsSync(ctx *cli.Context) {
+	Current.ParseStringFlag(ctx, FlagSyncURL)
+	Current.ParseStringFlag(ctx, FlagSyncPassphrase)
+	Current.ParseDurationFlag(ctx, FlagSyncInterval)
+	Current.ParseStringFlag(ctx, FlagSyncUsername)
+	Current.ParseStringFlag(ctx, FlagSyncPassword)
+	Current.ParseStringFlag(ctx, FlagSyncS3AccessKey)
+	Current.ParseStringFlag(ctx, FlagSyncS3SecretKey)
+	Current.ParseStringFlag(ctx, FlagSyncS3Region)
+	Current.ParseStringFlag(ctx, FlagSyncS3Endpoint)
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagTracingOTLPEndpoint sets the OpenTelemetry collector session establishment spans are exported to.
+	FlagTracingOTLPEndpoint = cli.StringFlag{
+		Name:  "tracing.otlp-endpoint",
+		Usage: "OTLP/HTTP endpoint of an OpenTelemetry collector to export session establishment spans to, e.g. http://localhost:4318. Spans are not exported if empty",
+		Value: "",
+	}
+	// FlagTracingOTLPHeaders sets headers of requests to the OpenTelemetry collector.
+	FlagTracingOTLPHeaders = cli.StringSliceFlag{
+		Name:  "tracing.otlp-headers",
+		Usage: "Headers added to OTLP export requests, e.g. for authentication, in the form Name=Value separated by comma",
+		Value: cli.NewStringSlice(),
+	}
+)
+
+// RegisterFlagsTracing function registers tracing flags to flag list
+func RegisterFlagsTracing(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagTracingOTLPEndpoint,
+		&FlagTracingOTLPHeaders,
+	)
+}
+
+// ParseFlagsTracing function fills in tracing options from CLI context
+func ParseFlagsTracing(ctx *cli.Context) {
+	Current.ParseStringFlag(ctx, FlagTracingOTLPEndpoint)
+	Current.ParseStringSliceFlag(ctx, FlagTracingOTLPHeaders)
+}
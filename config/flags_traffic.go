@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagTrafficMonthlyCap sets the monthly traffic cap of provider sessions.
+	FlagTrafficMonthlyCap = cli.Uint64Flag{
+		Name:  "traffic.monthly-cap",
+		Usage: "Traffic of all provider sessions allowed in a month, in GiB. Traffic is not capped if zero",
+		Value: 0,
+	}
+	// FlagTrafficCapAction sets what is done once the monthly traffic cap is reached.
+	FlagTrafficCapAction = cli.StringFlag{
+		Name:  "traffic.cap-action",
+		Usage: `Action taken when the monthly traffic cap is reached { "reject", "stop" }. New sessions are rejected, "stop" also stops running services`,
+		Value: "reject",
+	}
+	// FlagTrafficBillingDay sets the day of month the monthly traffic is counted from.
+	FlagTrafficBillingDay = cli.IntFlag{
+		Name:  "traffic.billing-day",
+		Usage: "Day of month, from 1 to 28, the monthly traffic is counted from (UTC)",
+		Value: 1,
+	}
+)
+
+// RegisterFlagsTraffic function registers provider traffic accounting flags to flag list
+func RegisterFlagsTraffic(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagTrafficMonthlyCap,
+		&FlagTrafficCapAction,
+		&FlagTrafficBillingDay,
+	)
+}
+
+// ParseFlagsTraffic function fills in provider traffic accounting options from CLI context
+func ParseFlagsTraffic(ctx *cli.Context) {
+	Current.ParseUInt64Flag(ctx, FlagTrafficMonthlyCap)
+	Current.ParseStringFlag(ctx, FlagTrafficCapAction)
+	Current.ParseIntFlag(ctx, FlagTrafficBillingDay)
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// FlagUpdaterChannel sets the release channel node updates are taken from.
+	FlagUpdaterChannel = cli.StringFlag{
+		Name:  "updater.channel",
+		Usage: "Release channel node updates are taken from: stable or beta",
+		Value: "stable",
+	}
+	// FlagUpdaterPublicKey sets the key release binaries must be signed with.
+	FlagUpdaterPublicKey = cli.StringFlag{
+		Name:  "updater.public-key",
+		Usage: "Hex encoded Ed25519 public key release binaries must be signed with. Updates are refused if empty",
+		Value: "",
+	}
+	// FlagUpdaterHealthCheckDelay sets how long an updated node runs before its health is checked.
+	FlagUpdaterHealthCheckDelay = cli.DurationFlag{
+		Name:  "updater.health-check-delay",
+		Usage: "How long an updated node runs before its health is checked. The previous binary is restored if the check fails",
+		Value: time.Minute,
+	}
+	// FlagUpdaterMaxStartAttempts sets how many times an updated node may fail to start before the previous binary is restored.
+	FlagUpdaterMaxStartAttempts = cli.IntFlag{
+		Name:  "updater.max-start-attempts",
+		Usage: "Number of times an updated node may fail to start before the previous binary is restored",
+		Value: 3,
+	}
+)
+
+// RegisterFlagsUpdater function registers updater flags to flag list
+func RegisterFlagsUpdater(flags *[]cli.Flag) {
+	*flags = append(
+		*flags,
+		&FlagUpdaterChannel,
+		&FlagUpdaterPublicKey,
+		&FlagUpdaterHealthCheckDelay,
+		&FlagUpdaterMaxStartAttempts,
+	)
+}
+
+// ParseFlagsUpdater function fills in updater options from CLI context
+func ParseFlagsUpdater(ctx *cli.Context) {
+	Current.ParseStringFlag(ctx, FlagUpdaterChannel)
+	Current.ParseStringFlag(ctx, FlagUpdaterPublicKey)
+	Current.ParseDurationFlag(ctx, FlagUpdaterHealthCheckDelay)
+	Current.ParseIntFlag(ctx, FlagUpdaterMaxStartAttempts)
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// profilesKey is the user configuration section holding custom profiles.
+const profilesKey = "profiles"
+
+// Profile is a named set of configuration values.
+// Profile values override defaults, but not user configuration, configuration file or CLI flags.
+type Profile struct {
+	Name        string
+	Description string
+	// Values by their flag names.
+	Values map[string]interface{}
+	// Custom is set for profiles defined in user configuration.
+	Custom bool
+}
+
+// BuiltinProfiles are profiles shipped with the node.
+var BuiltinProfiles = []Profile{
+	{
+		Name:        "mainnet",
+		Description: "Mysterium mainnet",
+		Values:      map[string]interface{}{FlagBlockchainNetwork.Name: string(Mainnet)},
+	},
+	{
+		Name:        "testnet",
+		Description: "Mysterium testnet",
+		Values:      map[string]interface{}{FlagBlockchainNetwork.Name: string(Testnet)},
+	},
+	{
+		Name:        "home-provider",
+		Description: "Mainnet provider sharing a home connection, with bandwidth shaped and monthly traffic capped to 1 TiB",
+		Values: map[string]interface{}{
+			FlagBlockchainNetwork.Name: string(Mainnet),
+			FlagShaperEnabled.Name:     true,
+			FlagTrafficMonthlyCap.Name: uint64(1024),
+		},
+	},
+}
+
+// Profiles returns built-in profiles and custom ones from user configuration, sorted by name.
+// Custom profiles replace built-in ones of the same name.
+func (cfg *Config) Profiles() []Profile {
+	profiles := make(map[string]Profile)
+	for _, p := range BuiltinProfiles {
+		profiles[p.Name] = p
+	}
+
+	cfg.mu.RLock()
+	custom, _ := cfg.user[profilesKey].(map[string]interface{})
+	for name, values := range custom {
+		if values, ok := values.(map[string]interface{}); ok {
+			profiles[strings.ToLower(name)] = Profile{
+				Name:   strings.ToLower(name),
+				Values: flattenMap("", values),
+				Custom: true,
+			}
+		}
+	}
+	cfg.mu.RUnlock()
+
+	result := make([]Profile, 0, len(profiles))
+	for _, p := range profiles {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Profile returns the name of the profile in use, empty if none is used.
+func (cfg *Config) Profile() string {
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.profileName
+}
+
+// UseProfile replaces profile values in the configuration with the ones of the named profile.
+// Empty name removes profile values. Network defaults follow the network of the resulting configuration.
+func (cfg *Config) UseProfile(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	values := make(map[string]interface{})
+	if name != "" {
+		found := false
+		for _, p := range cfg.Profiles() {
+			if p.Name == name {
+				values, found = p.Values, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown configuration profile %q", name)
+		}
+	}
+
+	profile := make(map[string]interface{})
+	for key, value := range values {
+		segments := strings.Split(strings.ToLower(key), ".")
+		deepSearch(profile, segments[:len(segments)-1])[segments[len(segments)-1]] = value
+	}
+
+	cfg.mu.Lock()
+	previous := flattenMap("", cfg.profile)
+	cfg.profile, cfg.profileName = profile, name
+	cfg.mu.Unlock()
+
+	_, hadNetwork := previous[FlagBlockchainNetwork.Name]
+	_, hasNetwork := values[FlagBlockchainNetwork.Name]
+	if hadNetwork || hasNetwork {
+		network, err := ParseBlockchainNetwork(cfg.GetString(FlagBlockchainNetwork.Name))
+		if err != nil {
+			return err
+		}
+		cfg.SetDefaultsByNetwork(network)
+	}
+
+	for key := range previous {
+		if _, ok := values[key]; !ok {
+			cfg.publish(key)
+		}
+	}
+	for key := range values {
+		cfg.publish(key)
+	}
+	return nil
+}
+
+// flattenMap returns values of nested configuration map by their dotted keys.
+func flattenMap(prefix string, m map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{})
+	for key, value := range m {
+		if nested, ok := value.(map[string]interface{}); ok {
+			for k, v := range flattenMap(prefix+key+".", nested) {
+				flat[k] = v
+			}
+			continue
+		}
+		flat[prefix+key] = value
+	}
+	return flat
+}
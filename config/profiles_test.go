@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/metadata"
+)
+
+func TestConfig_UseProfile(t *testing.T) {
+	cfg := NewConfig()
+	cfg.SetDefault(FlagBlockchainNetwork.Name, string(Mainnet))
+	cfg.SetDefaultsByNetwork(Mainnet)
+	cfg.SetDefault(FlagShaperEnabled.Name, false)
+
+	// when: built-in profile is used
+	require.NoError(t, cfg.UseProfile("Testnet"))
+	// then: network and its defaults are switched
+	assert.Equal(t, "testnet", cfg.Profile())
+	assert.Equal(t, "testnet", cfg.GetString(FlagBlockchainNetwork.Name))
+	assert.Equal(t, metadata.TestnetDefinition.MysteriumAPIAddress, cfg.GetString(metadata.FlagNames.MysteriumAPIAddress))
+
+	// when: user configuration sets the value of the profile
+	cfg.SetUser(FlagShaperEnabled.Name, false)
+	require.NoError(t, cfg.UseProfile("home-provider"))
+	// then: user value wins
+	assert.False(t, cfg.GetBool(FlagShaperEnabled.Name))
+	assert.Equal(t, uint64(1024), cfg.GetUInt64(FlagTrafficMonthlyCap.Name))
+	assert.Equal(t, metadata.MainnetDefinition.MysteriumAPIAddress, cfg.GetString(metadata.FlagNames.MysteriumAPIAddress))
+
+	// when: profile is no longer used
+	require.NoError(t, cfg.UseProfile(""))
+	// then: its values are removed
+	assert.Empty(t, cfg.Profile())
+	assert.Nil(t, cfg.Get(FlagTrafficMonthlyCap.Name))
+}
+
+func TestConfig_UseCustomProfile(t *testing.T) {
+	cfg := NewConfig()
+	cfg.SetUser("profiles.office.openvpn.port", 1194)
+	cfg.SetUser("profiles.office.shaper.enabled", true)
+
+	profiles := cfg.Profiles()
+	require.Len(t, profiles, len(BuiltinProfiles)+1)
+	assert.Equal(t, Profile{
+		Name:   "office",
+		Values: map[string]interface{}{"openvpn.port": 1194, "shaper.enabled": true},
+		Custom: true,
+	}, profiles[2])
+
+	require.NoError(t, cfg.UseProfile("office"))
+	assert.Equal(t, 1194, cfg.GetInt("openvpn.port"))
+	assert.True(t, cfg.GetBool(FlagShaperEnabled.Name))
+}
+
+func TestConfig_UseUnknownProfile(t *testing.T) {
+	cfg := NewConfig()
+	require.NoError(t, cfg.UseProfile("testnet"))
+
+	assert.Error(t, cfg.UseProfile("moon"))
+	assert.Equal(t, "testnet", cfg.Profile())
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package source
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Etcd fetches configuration values stored under a key prefix in etcd, using its v3 JSON gateway.
+// Keys are flag names under the prefix, with '/' standing for '.', and values are
+// JSON encoded or plain strings, e.g. /mysterium/node/payments.price-gib = 0.1.
+// Unchanged values are detected by the revision of the store.
+type Etcd struct {
+	client   *http.Client
+	endpoint string
+	prefix   string
+	token    string
+	revision string
+}
+
+// NewEtcd returns a new etcd configuration source. The path of the URL is the key prefix.
+func NewEtcd(client *http.Client, rawURL, token string) (*Etcd, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid etcd URL %q", rawURL)
+	}
+	prefix := u.Path
+	if prefix == "" {
+		prefix = "/"
+	}
+
+	return &Etcd{
+		client:   client,
+		endpoint: u.Scheme + "://" + u.Host + "/v3/kv/range",
+		prefix:   prefix,
+		token:    token,
+	}, nil
+}
+
+type etcdRangeRequest struct {
+	Key      string `json:"key"`
+	RangeEnd string `json:"range_end"`
+}
+
+type etcdRangeResponse struct {
+	Header struct {
+		Revision string `json:"revision"`
+	} `json:"header"`
+	Kvs []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"kvs"`
+}
+
+// Fetch returns configuration values stored under the prefix.
+func (e *Etcd) Fetch() (map[string]interface{}, bool, error) {
+	body, err := json.Marshal(etcdRangeRequest{
+		Key:      base64.StdEncoding.EncodeToString([]byte(e.prefix)),
+		RangeEnd: base64.StdEncoding.EncodeToString(prefixEnd(e.prefix)),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, errors.Wrap(err, "could not create etcd request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.token != "" {
+		req.Header.Set("Authorization", e.token)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "could not fetch configuration from etcd")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("could not fetch configuration from etcd: unexpected response status %s", resp.Status)
+	}
+
+	var result etcdRangeResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&result); err != nil {
+		return nil, false, errors.Wrap(err, "could not parse etcd response")
+	}
+	if result.Header.Revision != "" && result.Header.Revision == e.revision {
+		return nil, false, nil
+	}
+
+	values := make(map[string]interface{})
+	for _, kv := range result.Kvs {
+		key, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "could not decode etcd key")
+		}
+		value, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "could not decode value of etcd key %s", key)
+		}
+
+		name := strings.Trim(strings.TrimPrefix(string(key), e.prefix), "/")
+		if name == "" {
+			continue
+		}
+		values[strings.ToLower(strings.ReplaceAll(name, "/", "."))] = decodeValue(value)
+	}
+	e.revision = result.Header.Revision
+
+	return values, true, nil
+}
+
+// decodeValue decodes JSON encoded values, taking anything else as a plain string.
+func decodeValue(value []byte) interface{} {
+	var decoded interface{}
+	if err := json.Unmarshal(value, &decoded); err != nil {
+		return string(value)
+	}
+	if _, ok := decoded.(map[string]interface{}); ok {
+		return string(value)
+	}
+	return decoded
+}
+
+// prefixEnd returns the end of the key range of all keys with the given prefix.
+func prefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return []byte{0}
+}
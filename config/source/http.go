@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// HTTP fetches a JSON document of configuration values, e.g.
+//
+//	{
+//	  "payments.price-gib": 0.1,
+//	  "shaper": {"enabled": true}
+//	}
+//
+// Unchanged documents are detected by their ETag.
+type HTTP struct {
+	client *http.Client
+	url    string
+	token  string
+	etag   string
+}
+
+// NewHTTP returns a new HTTP configuration source.
+func NewHTTP(client *http.Client, url, token string) *HTTP {
+	return &HTTP{
+		client: client,
+		url:    url,
+		token:  token,
+	}
+}
+
+// Fetch returns configuration values of the document.
+func (h *HTTP) Fetch() (map[string]interface{}, bool, error) {
+	req, err := http.NewRequest(http.MethodGet, h.url, nil)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "could not create remote configuration request")
+	}
+	req.Header.Set("Accept", "application/json")
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+	if h.etag != "" {
+		req.Header.Set("If-None-Match", h.etag)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "could not fetch remote configuration")
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil, false, nil
+	case http.StatusOK:
+	default:
+		return nil, false, fmt.Errorf("could not fetch remote configuration: unexpected response status %s", resp.Status)
+	}
+
+	var document map[string]interface{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&document); err != nil {
+		return nil, false, errors.Wrap(err, "could not parse remote configuration")
+	}
+	h.etag = resp.Header.Get("ETag")
+
+	values := make(map[string]interface{})
+	flatten(document, "", values)
+	return values, true, nil
+}
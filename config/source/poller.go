@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package source
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+)
+
+// Poller periodically fetches remote configuration and applies its changes.
+// Every changed value is announced on the event bus by the configuration, see config.AppTopicConfig.
+type Poller struct {
+	source   Source
+	interval time.Duration
+	cfg      *config.Config
+
+	mu     sync.Mutex
+	values map[string]interface{}
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPoller returns a new instance of Poller.
+func NewPoller(source Source, interval time.Duration, cfg *config.Config) *Poller {
+	return &Poller{
+		source:   source,
+		interval: interval,
+		cfg:      cfg,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start checks remote configuration periodically until stopped.
+func (p *Poller) Start() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if err := p.Check(); err != nil {
+				log.Error().Err(err).Msg("Could not check remote configuration, keeping current configuration")
+			}
+		}
+	}
+}
+
+// Stop stops checking remote configuration.
+func (p *Poller) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+}
+
+// Check fetches remote configuration and applies it if changed.
+// Values removed from remote configuration fall back to lower priority ones.
+func (p *Poller) Check() error {
+	values, changed, err := p.source.Fetch()
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var keys []string
+	for key, value := range values {
+		if old, ok := p.values[key]; ok && fmt.Sprint(old) == fmt.Sprint(value) {
+			continue
+		}
+		p.cfg.SetRemote(key, value)
+		keys = append(keys, key)
+	}
+	for key := range p.values {
+		if _, ok := values[key]; !ok {
+			p.cfg.RemoveRemote(key)
+			keys = append(keys, key)
+		}
+	}
+	p.values = values
+
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		log.Info().Strs("changed", keys).Msg("Remote configuration applied")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package source
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+type mockSource struct {
+	values  map[string]interface{}
+	changed bool
+}
+
+func (m *mockSource) Fetch() (map[string]interface{}, bool, error) {
+	return m.values, m.changed, nil
+}
+
+func TestPoller_Check(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.SetDefault("payments.price-gib", 0.2)
+	cfg.SetDefault("shaper.enabled", false)
+	cfg.SetUser("shaper.enabled", true)
+
+	bus := eventbus.New()
+	cfg.EnableEventPublishing(bus)
+	var mu sync.Mutex
+	events := make(map[string]interface{})
+	for _, key := range []string{"payments.price-gib", "shaper.enabled"} {
+		key := key
+		require.NoError(t, bus.Subscribe(config.AppTopicConfig(key), func(value interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			events[key] = value
+		}))
+	}
+
+	source := &mockSource{
+		values:  map[string]interface{}{"payments.price-gib": 0.1, "shaper.enabled": false},
+		changed: true,
+	}
+	poller := NewPoller(source, 0, cfg)
+
+	require.NoError(t, poller.Check())
+	assert.Equal(t, 0.1, cfg.GetFloat64("payments.price-gib"))
+	assert.False(t, cfg.GetBool("shaper.enabled"))
+
+	mu.Lock()
+	assert.Equal(t, map[string]interface{}{"payments.price-gib": 0.1, "shaper.enabled": false}, events)
+	mu.Unlock()
+
+	source.values = map[string]interface{}{"shaper.enabled": false}
+	require.NoError(t, poller.Check())
+	assert.Equal(t, 0.2, cfg.GetFloat64("payments.price-gib"))
+	assert.False(t, cfg.GetBool("shaper.enabled"))
+
+	mu.Lock()
+	assert.Equal(t, 0.2, events["payments.price-gib"])
+	mu.Unlock()
+}
+
+func TestPoller_Check_Precedence(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.SetUser("payments.price-gib", 0.3)
+	cfg.SetFile("location.country", "DE")
+	cfg.LoadEnv([]string{"MYST_SHAPER_ENABLED=true", "HOME=/root"})
+
+	poller := NewPoller(&mockSource{
+		values: map[string]interface{}{
+			"payments.price-gib": 0.1,
+			"location.country":   "LT",
+			"shaper.enabled":     false,
+		},
+		changed: true,
+	}, 0, cfg)
+	require.NoError(t, poller.Check())
+
+	assert.Equal(t, 0.1, cfg.GetFloat64("payments.price-gib"))
+	assert.Equal(t, "DE", cfg.GetString("location.country"))
+	assert.True(t, cfg.GetBool("shaper.enabled"))
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package source
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mysteriumnetwork/node/config"
+)
+
+// Source is a remote configuration endpoint.
+type Source interface {
+	// Fetch returns configuration values by their flag names.
+	// It returns false if values did not change since the last fetch.
+	Fetch() (values map[string]interface{}, changed bool, err error)
+}
+
+const (
+	typeHTTP = "http"
+	typeEtcd = "etcd"
+)
+
+// maxResponseSize limits the size of remote configuration responses.
+const maxResponseSize = 1 << 20
+
+// New returns the remote configuration source of the given type.
+func New(sourceType, url, token string) (Source, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch strings.ToLower(sourceType) {
+	case typeHTTP, "":
+		return NewHTTP(client, url, token), nil
+	case typeEtcd:
+		return NewEtcd(client, url, token)
+	default:
+		return nil, fmt.Errorf("unknown remote configuration type %q, expected one of: %s, %s", sourceType, typeHTTP, typeEtcd)
+	}
+}
+
+// NewFromConfig returns the remote configuration source configured by flags,
+// or nil if remote configuration is not used.
+func NewFromConfig(cfg *config.Config) (Source, error) {
+	url := cfg.GetString(config.FlagConfigRemoteURL.Name)
+	if url == "" {
+		return nil, nil
+	}
+	return New(
+		cfg.GetString(config.FlagConfigRemoteType.Name),
+		url,
+		cfg.GetString(config.FlagConfigRemoteToken.Name),
+	)
+}
+
+// flatten collects values of a nested document by their dotted key names.
+func flatten(values map[string]interface{}, prefix string, into map[string]interface{}) {
+	for key, value := range values {
+		key = prefix + strings.ToLower(key)
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(nested, key+".", into)
+			continue
+		}
+		into[key] = value
+	}
+}
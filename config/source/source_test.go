@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package source
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_Fetch(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"payments.price-gib": 0.1, "Shaper": {"enabled": true}}`))
+	}))
+	defer server.Close()
+
+	source := NewHTTP(server.Client(), server.URL, "secret")
+
+	values, changed, err := source.Fetch()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]interface{}{
+		"payments.price-gib": 0.1,
+		"shaper.enabled":     true,
+	}, values)
+
+	_, changed, err = source.Fetch()
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, 2, requests)
+}
+
+func TestHTTP_Fetch_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, _, err := NewHTTP(server.Client(), server.URL, "").Fetch()
+	assert.Error(t, err)
+}
+
+func TestEtcd_Fetch(t *testing.T) {
+	encode := func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}
+	revision := "5"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/kv/range", r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+
+		var req etcdRangeRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, encode("/mysterium/node/"), req.Key)
+		assert.Equal(t, encode("/mysterium/node0"), req.RangeEnd)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"header": map[string]string{"revision": revision},
+			"kvs": []map[string]string{
+				{"key": encode("/mysterium/node/payments.price-gib"), "value": encode("0.1")},
+				{"key": encode("/mysterium/node/shaper/enabled"), "value": encode("true")},
+				{"key": encode("/mysterium/node/location.country"), "value": encode("LT")},
+				{"key": encode("/mysterium/node/access-policy.list"), "value": encode(`["mysterium","custom"]`)},
+			},
+		})
+	}))
+	defer server.Close()
+
+	source, err := NewEtcd(server.Client(), server.URL+"/mysterium/node/", "token")
+	require.NoError(t, err)
+
+	values, changed, err := source.Fetch()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]interface{}{
+		"payments.price-gib": 0.1,
+		"shaper.enabled":     true,
+		"location.country":   "LT",
+		"access-policy.list": []interface{}{"mysterium", "custom"},
+	}, values)
+
+	_, changed, err = source.Fetch()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	revision = "6"
+	_, changed, err = source.Fetch()
+	require.NoError(t, err)
+	assert.True(t, changed)
+}
+
+func TestNew_UnknownType(t *testing.T) {
+	_, err := New("consul", "http://localhost:8500", "")
+	assert.Error(t, err)
+}
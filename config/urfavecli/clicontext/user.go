@@ -31,7 +31,10 @@ import (
 
 // LoadUserConfig determines config location from the context
 // and makes sure that the config file actually exists, creating it if necessary.
+// Configuration given by environment variables is loaded as well.
 func LoadUserConfig(ctx *cli.Context) error {
+	config.Current.LoadEnv(os.Environ())
+
 	configDir, configFilePath := resolveLocation(ctx)
 	err := createDirIfNotExists(configDir)
 	if err != nil {
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package session
+
+import (
+	"math/big"
+	"sort"
+)
+
+const (
+	// GroupByDay groups sessions by the day they started.
+	GroupByDay = "day"
+	// GroupByProvider groups sessions by provider identity.
+	GroupByProvider = "provider"
+)
+
+// Aggregate holds statistics of a group of sessions, with tokens split by session direction.
+type Aggregate struct {
+	Key string
+	Stats
+	// SumEarnings is the amount of tokens earned in provided sessions.
+	SumEarnings *big.Int
+	// SumSpendings is the amount of tokens spent in consumed sessions.
+	SumSpendings *big.Int
+}
+
+// NewAggregate initiates zero Aggregate instance.
+func NewAggregate(key string) Aggregate {
+	return Aggregate{
+		Key:          key,
+		Stats:        NewStats(),
+		SumEarnings:  new(big.Int),
+		SumSpendings: new(big.Int),
+	}
+}
+
+// Add accumulates given session to the aggregate.
+func (a *Aggregate) Add(session History) {
+	if session.Tokens == nil {
+		session.Tokens = new(big.Int)
+	}
+	a.Stats.Add(session)
+
+	switch session.Direction {
+	case DirectionProvided:
+		a.SumEarnings = new(big.Int).Add(a.SumEarnings, session.Tokens)
+	case DirectionConsumed:
+		a.SumSpendings = new(big.Int).Add(a.SumSpendings, session.Tokens)
+	}
+}
+
+// AggregateSessions returns totals of given sessions and, if groupBy is set, totals
+// of their groups ordered by group key.
+func AggregateSessions(sessions []History, groupBy string) (Aggregate, []Aggregate) {
+	total := NewAggregate("")
+	groups := make(map[string]*Aggregate)
+
+	for _, se := range sessions {
+		total.Add(se)
+
+		key, ok := groupKey(se, groupBy)
+		if !ok {
+			continue
+		}
+		group, found := groups[key]
+		if !found {
+			aggregate := NewAggregate(key)
+			group = &aggregate
+			groups[key] = group
+		}
+		group.Add(se)
+	}
+
+	result := make([]Aggregate, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return total, result
+}
+
+func groupKey(se History, groupBy string) (string, bool) {
+	switch groupBy {
+	case GroupByDay:
+		return se.Started.UTC().Format("2006-01-02"), true
+	case GroupByProvider:
+		return se.ProviderID.Address, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package session
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+func TestAggregateSessions(t *testing.T) {
+	day1 := time.Date(2020, 6, 17, 10, 0, 0, 0, time.UTC)
+	day2 := time.Date(2020, 6, 18, 23, 0, 0, 0, time.UTC)
+	sessions := []History{
+		{
+			Direction:  DirectionProvided,
+			ConsumerID: identity.FromAddress("consumer1"),
+			ProviderID: identity.FromAddress("provider1"),
+			DataSent:   1 << 30,
+			Tokens:     big.NewInt(100),
+			Started:    day2,
+			Updated:    day2.Add(time.Minute),
+		},
+		{
+			Direction:    DirectionConsumed,
+			ConsumerID:   identity.FromAddress("consumer1"),
+			ProviderID:   identity.FromAddress("provider2"),
+			DataReceived: 1 << 20,
+			Tokens:       big.NewInt(30),
+			Started:      day1,
+			Updated:      day1.Add(time.Hour),
+		},
+		{
+			Direction:  DirectionProvided,
+			ConsumerID: identity.FromAddress("consumer2"),
+			ProviderID: identity.FromAddress("provider1"),
+			DataSent:   1 << 20,
+			Tokens:     big.NewInt(5),
+			Started:    day1.Add(time.Hour),
+			Updated:    day1.Add(2 * time.Hour),
+		},
+	}
+
+	total, groups := AggregateSessions(sessions, "")
+	assert.Empty(t, groups)
+	assert.Equal(t, 3, total.Count)
+	assert.Len(t, total.ConsumerCounts, 2)
+	assert.Equal(t, uint64(1<<30+1<<20), total.SumDataSent)
+	assert.Equal(t, uint64(1<<20), total.SumDataReceived)
+	assert.Equal(t, big.NewInt(105), total.SumEarnings)
+	assert.Equal(t, big.NewInt(30), total.SumSpendings)
+	assert.Equal(t, big.NewInt(135), total.SumTokens)
+
+	_, groups = AggregateSessions(sessions, GroupByDay)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "2020-06-17", groups[0].Key)
+	assert.Equal(t, 2, groups[0].Count)
+	assert.Equal(t, big.NewInt(5), groups[0].SumEarnings)
+	assert.Equal(t, big.NewInt(30), groups[0].SumSpendings)
+	assert.Equal(t, 2*time.Hour, groups[0].SumDuration)
+	assert.Equal(t, "2020-06-18", groups[1].Key)
+	assert.Equal(t, big.NewInt(100), groups[1].SumEarnings)
+
+	_, groups = AggregateSessions(sessions, GroupByProvider)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "provider1", groups[0].Key)
+	assert.Equal(t, 2, groups[0].Count)
+	assert.Equal(t, big.NewInt(105), groups[0].SumEarnings)
+	assert.Equal(t, "provider2", groups[1].Key)
+	assert.Equal(t, big.NewInt(30), groups[1].SumSpendings)
+}
@@ -23,11 +23,11 @@ import (
 	"sync"
 	"time"
 
-	"github.com/asdine/storm/v3"
+	"github.com/asdine/storm/v3/q"
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
-	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/core/storage"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	session_node "github.com/mysteriumnetwork/node/session"
@@ -41,7 +41,7 @@ type timeGetter func() time.Time
 
 // Storage contains functions for storing, getting session objects.
 type Storage struct {
-	storage    *boltdb.Bolt
+	storage    storage.Store
 	timeGetter timeGetter
 
 	mu             sync.RWMutex
@@ -49,7 +49,7 @@ type Storage struct {
 }
 
 // NewSessionStorage creates session repository with given dependencies.
-func NewSessionStorage(storage *boltdb.Bolt) *Storage {
+func NewSessionStorage(storage storage.Store) *Storage {
 	return &Storage{
 		storage:    storage,
 		timeGetter: time.Now,
@@ -85,16 +85,14 @@ func (repo *Storage) GetAll() ([]History, error) {
 
 // List retrieves stored entries.
 func (repo *Storage) List(filter *Filter) (result []History, err error) {
-	repo.storage.RLock()
-	defer repo.storage.RUnlock()
-	query := repo.storage.DB().
-		From(sessionStorageBucketName).
-		Select(filter.toMatcher()).
-		OrderBy("Started").
-		Reverse()
-
-	err = query.Find(&result)
-	if errors.Is(err, storm.ErrNotFound) {
+	query := storage.Query{
+		Where:   filter.toMatcher(),
+		OrderBy: "Started",
+		Reverse: true,
+	}
+
+	err = repo.storage.Find(sessionStorageBucketName, query, &result)
+	if errors.Is(err, storage.ErrNotFound) {
 		return []History{}, nil
 	}
 
@@ -103,56 +101,76 @@ func (repo *Storage) List(filter *Filter) (result []History, err error) {
 
 // Stats fetches aggregated statistics to Filter.Stats.
 func (repo *Storage) Stats(filter *Filter) (result Stats, err error) {
-	repo.storage.RLock()
-	defer repo.storage.RUnlock()
-	query := repo.storage.DB().
-		From(sessionStorageBucketName).
-		Select(filter.toMatcher()).
-		OrderBy("Started").
-		Reverse()
+	if db, ok := repo.storage.(storage.SQLQuerier); ok {
+		stats, err := sqlStats(db, filter, false, time.Now())
+		if err != nil {
+			return result, err
+		}
+		if result, ok = stats[time.Time{}]; !ok {
+			result = NewStats()
+		}
+		return result, nil
+	}
 
-	result = NewStats()
-	err = query.Each(new(History), func(record interface{}) error {
-		session := record.(*History)
+	sessions, err := repo.List(filter)
+	if err != nil {
+		return result, err
+	}
 
-		result.Add(*session)
+	result = NewStats()
+	for _, session := range sessions {
+		result.Add(session)
+	}
+	return result, nil
+}
 
-		return nil
-	})
-	return result, err
+// PruneBefore deletes sessions started before the given time.
+func (repo *Storage) PruneBefore(before time.Time) error {
+	return repo.storage.DeleteMatching(sessionStorageBucketName, q.Lt("Started", before.UTC()), new(History))
 }
 
 const stepDay = 24 * time.Hour
 
 // StatsByDay retrieves aggregated statistics grouped by day to Filter.StatsByDay.
 func (repo *Storage) StatsByDay(filter *Filter) (result map[time.Time]Stats, err error) {
-	repo.storage.RLock()
-	defer repo.storage.RUnlock()
-	query := repo.storage.DB().
-		From(sessionStorageBucketName).
-		Select(filter.toMatcher()).
-		OrderBy("Started").
-		Reverse()
-
-	// fill the period with zeros
-	result = make(map[time.Time]Stats)
-	if filter.StartedFrom != nil && filter.StartedTo != nil {
-		for i := filter.StartedFrom.Truncate(stepDay); !i.After(*filter.StartedTo); i = i.Add(stepDay) {
-			result[i] = NewStats()
+	if db, ok := repo.storage.(storage.SQLQuerier); ok {
+		result, err = sqlStats(db, filter, true, time.Now())
+		if err != nil {
+			return nil, err
 		}
+		fillDays(result, filter)
+		return result, nil
 	}
 
-	err = query.Each(new(History), func(record interface{}) error {
-		session := record.(*History)
+	sessions, err := repo.List(filter)
+	if err != nil {
+		return nil, err
+	}
 
+	result = make(map[time.Time]Stats)
+	fillDays(result, filter)
+	for _, session := range sessions {
 		i := session.Started.Truncate(stepDay)
-		stats := result[i]
-		stats.Add(*session)
+		stats, ok := result[i]
+		if !ok {
+			stats = NewStats()
+		}
+		stats.Add(session)
 		result[i] = stats
+	}
+	return result, nil
+}
 
-		return nil
-	})
-	return result, err
+// fillDays fills days of the filtered period without sessions with zeros.
+func fillDays(result map[time.Time]Stats, filter *Filter) {
+	if filter.StartedFrom == nil || filter.StartedTo == nil {
+		return
+	}
+	for i := filter.StartedFrom.Truncate(stepDay); !i.After(*filter.StartedTo); i = i.Add(stepDay) {
+		if _, ok := result[i]; !ok {
+			result[i] = NewStats()
+		}
+	}
 }
 
 // consumeServiceSessionEvent consumes the provided sessions.
@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package session
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+// zeroTime is how unset time fields are JSON encoded.
+const zeroTime = "0001-01-01T00:00:00Z"
+
+// tokensSplit is the number of last digits of token amounts summed separately, so that sums do not overflow.
+const tokensSplit = 9
+
+var tokensSplitBase = big.NewInt(1_000_000_000)
+
+// sqlStats aggregates session history in the database, grouped by the day sessions started if byDay is set.
+func sqlStats(db storage.SQLQuerier, filter *Filter, byDay bool, now time.Time) (map[time.Time]Stats, error) {
+	day := "''"
+	if byDay {
+		day = "date(value ->> '$.Started')"
+	}
+	where, args := filter.toSQL()
+
+	query := fmt.Sprintf(`SELECT %s, value ->> '$.ConsumerID.address', COUNT(*),
+		SUM(value ->> '$.DataSent'), SUM(value ->> '$.DataReceived'),
+		SUM(CAST(ROUND((julianday(IIF(value ->> '$.Updated' = ?, ?, value ->> '$.Updated')) - julianday(value ->> '$.Started')) * 86400000) AS INTEGER)),
+		SUM(CAST(substr(value -> '$.Tokens', -%[2]d) AS INTEGER)),
+		SUM(CAST(substr(value -> '$.Tokens', 1, max(length(value -> '$.Tokens') - %[2]d, 0)) AS INTEGER))
+		FROM records WHERE %s GROUP BY 1, 2`, day, tokensSplit, where)
+	args = append([]interface{}{zeroTime, now.UTC().Format(time.RFC3339Nano)}, args...)
+
+	rows, err := db.QueryRecords(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[time.Time]Stats)
+	for rows.Next() {
+		var (
+			started, consumer                     string
+			count                                 int
+			sent, received, durationMs, low, high int64
+		)
+		if err := rows.Scan(&started, &consumer, &count, &sent, &received, &durationMs, &low, &high); err != nil {
+			return nil, err
+		}
+
+		var group time.Time
+		if byDay {
+			if group, err = time.Parse("2006-01-02", started); err != nil {
+				return nil, err
+			}
+		}
+		stats, ok := result[group]
+		if !ok {
+			stats = NewStats()
+		}
+		stats.Count += count
+		stats.ConsumerCounts[identity.Identity{Address: consumer}] += count
+		stats.SumDataSent += uint64(sent)
+		stats.SumDataReceived += uint64(received)
+		stats.SumDuration += time.Duration(durationMs) * time.Millisecond
+		tokens := new(big.Int).Mul(big.NewInt(high), tokensSplitBase)
+		stats.SumTokens = tokens.Add(tokens, big.NewInt(low)).Add(tokens, stats.SumTokens)
+		result[group] = stats
+	}
+	return result, rows.Err()
+}
+
+// toSQL returns the condition selecting sessions matched by the filter from records table.
+func (f *Filter) toSQL() (string, []interface{}) {
+	where := []string{"bucket = ?", "value ->> '$.SessionID' IS NOT NULL"}
+	args := []interface{}{sessionStorageBucketName}
+
+	if f.StartedFrom != nil {
+		where = append(where, "julianday(value ->> '$.Started') >= julianday(?)")
+		args = append(args, f.StartedFrom.UTC().Format(time.RFC3339Nano))
+	}
+	if f.StartedTo != nil {
+		where = append(where, "julianday(value ->> '$.Started') <= julianday(?)")
+		args = append(args, f.StartedTo.UTC().Format(time.RFC3339Nano))
+	}
+	eq := func(path string, value *string) {
+		if value != nil {
+			where = append(where, fmt.Sprintf("value ->> '%s' = ?", path))
+			args = append(args, *value)
+		}
+	}
+	eq("$.Direction", f.Direction)
+	if f.ConsumerID != nil {
+		eq("$.ConsumerID.address", &f.ConsumerID.Address)
+	}
+	eq("$.HermesID", f.HermesID)
+	if f.ProviderID != nil {
+		eq("$.ProviderID.address", &f.ProviderID.Address)
+	}
+	eq("$.ServiceType", f.ServiceType)
+	eq("$.Status", f.Status)
+
+	return strings.Join(where, " AND "), args
+}
@@ -0,0 +1,124 @@
+//go:build sqlite
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package session
+
+import (
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/sqlite"
+	"github.com/mysteriumnetwork/node/identity"
+	session_node "github.com/mysteriumnetwork/node/session"
+)
+
+func TestSessionStorage_StatsInSQLMatchStatsInGo(t *testing.T) {
+	largeTokens, _ := new(big.Int).SetString("123456789012345678901234", 10)
+	sessions := []History{
+		{
+			SessionID:    session_node.ID("session1"),
+			Direction:    DirectionProvided,
+			ConsumerID:   identity.FromAddress("consumer1"),
+			ServiceType:  "wireguard",
+			DataSent:     1234,
+			DataReceived: 123,
+			Tokens:       big.NewInt(12),
+			Started:      time.Date(2020, 6, 17, 10, 11, 12, 0, time.UTC),
+			Updated:      time.Date(2020, 6, 17, 10, 11, 32, 500_000_000, time.UTC),
+			Status:       StatusCompleted,
+		},
+		{
+			SessionID:    session_node.ID("session2"),
+			Direction:    DirectionProvided,
+			ConsumerID:   identity.FromAddress("consumer2"),
+			ServiceType:  "wireguard",
+			DataSent:     1 << 40,
+			DataReceived: 1 << 30,
+			Tokens:       largeTokens,
+			Started:      time.Date(2020, 6, 17, 23, 59, 59, 999_000_000, time.UTC),
+			Updated:      time.Date(2020, 6, 18, 1, 0, 0, 0, time.UTC),
+			Status:       StatusCompleted,
+		},
+		{
+			SessionID:    session_node.ID("session3"),
+			Direction:    DirectionConsumed,
+			ConsumerID:   identity.FromAddress("consumer1"),
+			ServiceType:  "scraping",
+			DataSent:     1,
+			DataReceived: 2,
+			Tokens:       new(big.Int).Mul(largeTokens, big.NewInt(3)),
+			Started:      time.Date(2020, 6, 19, 8, 0, 0, 0, time.UTC),
+			Updated:      time.Date(2020, 6, 19, 9, 0, 0, 0, time.UTC),
+			Status:       StatusNew,
+		},
+	}
+
+	inGo, cleanup := newStorageWithSessions(sessions...)
+	defer cleanup()
+	inSQL, cleanup := newSQLiteStorageWithSessions(t, sessions...)
+	defer cleanup()
+
+	filters := []*Filter{
+		NewFilter(),
+		NewFilter().SetDirection(DirectionProvided),
+		NewFilter().SetConsumerID(identity.FromAddress("consumer1")),
+		NewFilter().SetServiceType("scraping").SetStatus(StatusNew),
+		NewFilter().
+			SetStartedFrom(time.Date(2020, 6, 17, 10, 11, 12, 0, time.UTC)).
+			SetStartedTo(time.Date(2020, 6, 20, 0, 0, 0, 0, time.UTC)),
+		NewFilter().
+			SetStartedFrom(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)).
+			SetStartedTo(time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC)),
+	}
+	for _, filter := range filters {
+		expected, err := inGo.Stats(filter)
+		require.NoError(t, err)
+		actual, err := inSQL.Stats(filter)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+
+		expectedByDay, err := inGo.StatsByDay(filter)
+		require.NoError(t, err)
+		actualByDay, err := inSQL.StatsByDay(filter)
+		require.NoError(t, err)
+		assert.Equal(t, expectedByDay, actualByDay)
+	}
+}
+
+func newSQLiteStorageWithSessions(t *testing.T, sessions ...History) (*Storage, func()) {
+	dir, err := os.MkdirTemp("", "sessionStorageTest")
+	require.NoError(t, err)
+
+	db, err := sqlite.NewStorage(dir)
+	require.NoError(t, err)
+
+	storage := NewSessionStorage(db)
+	for _, session := range sessions {
+		require.NoError(t, storage.storage.Store(sessionStorageBucketName, &session))
+	}
+	return storage, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abuse
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+// BanList keeps consumer identities banned for abuse, persisted so that bans survive node restarts.
+type BanList struct {
+	file     string
+	duration time.Duration
+	now      func() time.Time
+
+	mu   sync.Mutex
+	bans map[string]time.Time
+}
+
+// NewBanList returns a ban list kept in the given directory, banning consumers for the given duration.
+func NewBanList(dir string, duration time.Duration) (*BanList, error) {
+	b := &BanList{
+		file:     filepath.Join(dir, "abuse-bans.json"),
+		duration: duration,
+		now:      time.Now,
+		bans:     make(map[string]time.Time),
+	}
+	if err := b.read(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Ban bans the consumer identity for the duration of the list.
+func (b *BanList) Ban(address string) error {
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("invalid identity %q", address)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bans[strings.ToLower(address)] = b.now().Add(b.duration)
+	return b.write()
+}
+
+// Unban lifts the ban of the consumer identity.
+func (b *BanList) Unban(address string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.bans, strings.ToLower(address))
+	return b.write()
+}
+
+// IsBanned checks whether the consumer identity is banned.
+func (b *BanList) IsBanned(id identity.Identity) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	until, ok := b.bans[strings.ToLower(id.Address)]
+	return ok && b.now().Before(until)
+}
+
+// Bans returns banned consumer identities with the time their ban expires.
+func (b *BanList) Bans() map[string]time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	bans := make(map[string]time.Time, len(b.bans))
+	for address, until := range b.bans {
+		if now.Before(until) {
+			bans[address] = until
+		}
+	}
+	return bans
+}
+
+func (b *BanList) read() error {
+	data, err := os.ReadFile(b.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read abuse bans: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &b.bans); err != nil {
+		return fmt.Errorf("could not parse abuse bans: %w", err)
+	}
+	return nil
+}
+
+// write persists the list, dropping expired bans.
+func (b *BanList) write() error {
+	now := b.now()
+	for address, until := range b.bans {
+		if !now.Before(until) {
+			delete(b.bans, address)
+		}
+	}
+
+	data, err := json.MarshalIndent(b.bans, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(b.file, data, 0600); err != nil {
+		return fmt.Errorf("could not write abuse bans: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abuse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+const consumerA = "0x000000000000000000000000000000000000000A"
+
+func TestBanList(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	bans, err := NewBanList(dir, time.Hour)
+	assert.NoError(t, err)
+	bans.now = func() time.Time { return now }
+
+	assert.False(t, bans.IsBanned(identity.FromAddress(consumerA)))
+	assert.NoError(t, bans.Ban(consumerA))
+	assert.Error(t, bans.Ban("0x1"))
+	assert.True(t, bans.IsBanned(identity.FromAddress(consumerA)))
+
+	reloaded, err := NewBanList(dir, time.Hour)
+	assert.NoError(t, err)
+	reloaded.now = func() time.Time { return now }
+	assert.True(t, reloaded.IsBanned(identity.FromAddress("0x000000000000000000000000000000000000000a")))
+	assert.Equal(t, map[string]time.Time{"0x000000000000000000000000000000000000000a": now.Add(time.Hour)}, reloaded.Bans())
+
+	reloaded.now = func() time.Time { return now.Add(time.Hour) }
+	assert.False(t, reloaded.IsBanned(identity.FromAddress(consumerA)), "ban expired")
+	assert.Empty(t, reloaded.Bans())
+
+	reloaded.now = func() time.Time { return now }
+	assert.NoError(t, reloaded.Unban(consumerA))
+	assert.False(t, reloaded.IsBanned(identity.FromAddress(consumerA)))
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abuse
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/utils/cmdutil"
+)
+
+const conntrackPath = "/usr/sbin/conntrack"
+
+// DefaultConntrackInterval is how often conntrack entries are checked for new flows.
+// Unanswered flows of scans stay in conntrack long enough not to be missed.
+const DefaultConntrackInterval = 5 * time.Second
+
+// ConntrackAvailable checks whether conntrack tool is installed.
+func ConntrackAvailable() bool {
+	_, err := os.Stat(conntrackPath)
+	return err == nil
+}
+
+// ConntrackMonitor feeds the detector with new flows of sessions forwarded by the kernel,
+// recognised by the connmark session accounting sets on their conntrack entries.
+// Flows can't be dropped after the fact, so throttling is left to the kernel flow rate limit
+// while the monitor terminates abusive sessions.
+type ConntrackMonitor struct {
+	detector *Detector
+	interval time.Duration
+	list     func() (string, error)
+	seen     map[string]struct{}
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewConntrackMonitor returns a monitor checking conntrack entries at the given interval.
+func NewConntrackMonitor(detector *Detector, interval time.Duration) *ConntrackMonitor {
+	return &ConntrackMonitor{
+		detector: detector,
+		interval: interval,
+		list:     listConntrack,
+		seen:     make(map[string]struct{}),
+		stop:     make(chan struct{}),
+	}
+}
+
+func listConntrack() (string, error) {
+	return cmdutil.ExecOutput("sudo", conntrackPath, "-L", "-f", "ipv4")
+}
+
+// Start starts checking conntrack entries in the background.
+func (m *ConntrackMonitor) Start() {
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				if err := m.check(); err != nil {
+					log.Warn().Err(err).Msg("Could not check session flows")
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops checking conntrack entries.
+func (m *ConntrackMonitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+func (m *ConntrackMonitor) check() error {
+	sessions := m.detector.Sessions()
+	if len(sessions) == 0 {
+		m.seen = make(map[string]struct{})
+		return nil
+	}
+
+	marks := make(map[uint32]string, len(sessions))
+	for _, id := range sessions {
+		marks[nat.SessionMark(id)] = id
+	}
+
+	output, err := m.list()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{})
+	for _, f := range parseConntrack(output) {
+		sessionID, ok := marks[f.mark]
+		if !ok {
+			continue
+		}
+		seen[f.key] = struct{}{}
+		if _, ok := m.seen[f.key]; ok {
+			continue
+		}
+		m.detector.Allow(sessionID, f.dst, f.port)
+	}
+	m.seen = seen
+	return nil
+}
+
+type conntrackFlow struct {
+	key  string
+	dst  net.IP
+	port uint16
+	mark uint32
+}
+
+// parseConntrack parses the output of `conntrack -L`, taking the original direction of every entry.
+func parseConntrack(output string) (flows []conntrackFlow) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		values := make(map[string]string)
+		for _, field := range fields {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			// Keys repeat for the reply direction, which is skipped.
+			if _, ok := values[kv[0]]; !ok {
+				values[kv[0]] = kv[1]
+			}
+		}
+
+		mark, err := strconv.ParseUint(values["mark"], 10, 32)
+		if err != nil {
+			continue
+		}
+		dst := net.ParseIP(values["dst"])
+		if dst == nil {
+			continue
+		}
+		port, _ := strconv.ParseUint(values["dport"], 10, 16)
+
+		flows = append(flows, conntrackFlow{
+			key:  strings.Join([]string{fields[0], values["src"], values["sport"], values["dst"], values["dport"]}, " "),
+			dst:  dst,
+			port: uint16(port),
+			mark: uint32(mark),
+		})
+	}
+	return flows
+}
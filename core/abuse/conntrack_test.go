@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abuse
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/nat"
+)
+
+func TestParseConntrack(t *testing.T) {
+	output := `tcp      6 117 SYN_SENT src=10.182.0.2 dst=93.184.216.34 sport=51234 dport=22 [UNREPLIED] src=93.184.216.34 dst=1.2.3.4 sport=22 dport=51234 mark=1291845692 use=1
+udp      17 29 src=10.182.0.2 dst=1.1.1.1 sport=40000 dport=53 src=1.1.1.1 dst=1.2.3.4 sport=53 dport=40000 mark=0 use=1
+conntrack v1.4.6 (conntrack-tools): 2 flow entries have been shown.`
+
+	assert.Equal(t, []conntrackFlow{
+		{key: "tcp 10.182.0.2 51234 93.184.216.34 22", dst: net.ParseIP("93.184.216.34"), port: 22, mark: 1291845692},
+		{key: "udp 10.182.0.2 40000 1.1.1.1 53", dst: net.ParseIP("1.1.1.1"), port: 53, mark: 0},
+	}, parseConntrack(output))
+}
+
+func TestConntrackMonitor_FeedsNewFlowsOfSessions(t *testing.T) {
+	d := NewDetector(Limits{NewFlows: 3, Window: time.Minute, Throttle: time.Minute})
+	d.Track("session-1", func() {})
+
+	entry := func(sport int, mark uint32) string {
+		return fmt.Sprintf("tcp      6 117 SYN_SENT src=10.182.0.2 dst=1.1.1.1 sport=%d dport=22 [UNREPLIED] mark=%d use=1\n", sport, mark)
+	}
+	mark := nat.SessionMark("session-1")
+
+	m := NewConntrackMonitor(d, time.Second)
+	m.list = func() (string, error) {
+		return entry(1000, mark) + entry(1001, mark) + entry(1002, 0), nil
+	}
+	assert.NoError(t, m.check())
+	assert.NoError(t, m.check(), "known flows are not counted again")
+	assert.True(t, d.Allow("session-1", net.ParseIP("1.1.1.1"), 22))
+	assert.False(t, d.Allow("session-1", net.ParseIP("1.1.1.1"), 22))
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abuse
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	singletonOnce sync.Once
+	singletonInst *Detector
+)
+
+// Singleton returns the globally available detector using limits configured for the node.
+func Singleton() *Detector {
+	singletonOnce.Do(func() {
+		singletonInst = NewDetector(LimitsFromConfig())
+	})
+
+	return singletonInst
+}
+
+// Detector watches new flows of provider sessions for port scans and excessive flow rate.
+// Sessions exceeding the limits are throttled, dropping their new flows for a while,
+// and terminated once they run out of strikes.
+type Detector struct {
+	limits Limits
+	now    func() time.Time
+
+	mu        sync.Mutex
+	sessions  map[string]*sessionFlows
+	addresses map[string]string
+}
+
+type sessionFlows struct {
+	terminate func()
+	address   string
+
+	windowStart    time.Time
+	flows          int
+	hostPorts      map[string]map[uint16]struct{}
+	portHosts      map[uint16]map[string]struct{}
+	strikes        int
+	throttledUntil time.Time
+	terminated     bool
+}
+
+// NewDetector returns a detector enforcing the given limits.
+func NewDetector(limits Limits) *Detector {
+	return &Detector{
+		limits:    limits,
+		now:       time.Now,
+		sessions:  make(map[string]*sessionFlows),
+		addresses: make(map[string]string),
+	}
+}
+
+// Limits returns limits enforced by the detector.
+func (d *Detector) Limits() Limits {
+	return d.limits
+}
+
+// Track starts watching flows of the session, terminate is called once the session runs out of strikes.
+func (d *Detector) Track(sessionID string, terminate func()) {
+	if !d.limits.Enabled() {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sessions[sessionID] = &sessionFlows{terminate: terminate}
+}
+
+// Bind attributes flows from the consumer tunnel address to the tracked session.
+func (d *Detector) Bind(sessionID string, ip net.IP) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, ok := d.sessions[sessionID]
+	if !ok {
+		return
+	}
+	s.address = ip.String()
+	d.addresses[s.address] = sessionID
+}
+
+// Untrack stops watching flows of the session.
+func (d *Detector) Untrack(sessionID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, ok := d.sessions[sessionID]
+	if !ok {
+		return
+	}
+	if s.address != "" && d.addresses[s.address] == sessionID {
+		delete(d.addresses, s.address)
+	}
+	delete(d.sessions, sessionID)
+}
+
+// Sessions returns IDs of the tracked sessions.
+func (d *Detector) Sessions() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ids := make([]string, 0, len(d.sessions))
+	for id := range d.sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// AllowFrom records a new flow from the consumer tunnel address and checks whether it may be forwarded.
+func (d *Detector) AllowFrom(src, dst net.IP, port uint16) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sessionID, ok := d.addresses[src.String()]
+	if !ok {
+		return true
+	}
+	return d.allow(sessionID, dst, port)
+}
+
+// Allow records a new flow of the session and checks whether it may be forwarded.
+// Flows of untracked sessions are always allowed.
+func (d *Detector) Allow(sessionID string, dst net.IP, port uint16) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.allow(sessionID, dst, port)
+}
+
+func (d *Detector) allow(sessionID string, dst net.IP, port uint16) bool {
+	s, ok := d.sessions[sessionID]
+	if !ok {
+		return true
+	}
+	if s.terminated {
+		return false
+	}
+
+	now := d.now()
+	if now.Before(s.throttledUntil) {
+		return false
+	}
+	if s.hostPorts == nil || now.Sub(s.windowStart) >= d.limits.Window {
+		s.reset(now)
+	}
+
+	reason := s.record(dst.String(), port, d.limits)
+	if reason == "" {
+		return true
+	}
+
+	s.strikes++
+	if d.limits.Strikes > 0 && s.strikes >= d.limits.Strikes {
+		log.Warn().Msgf("Session %s exceeded abuse limits (%s) %d times, terminating", sessionID, reason, s.strikes)
+		s.terminated = true
+		go s.terminate()
+		return false
+	}
+
+	log.Warn().Msgf("Session %s exceeded abuse limits (%s), throttling new flows for %s", sessionID, reason, d.limits.Throttle)
+	s.throttledUntil = now.Add(d.limits.Throttle)
+	s.reset(now)
+	return false
+}
+
+func (s *sessionFlows) reset(now time.Time) {
+	s.windowStart = now
+	s.flows = 0
+	s.hostPorts = make(map[string]map[uint16]struct{})
+	s.portHosts = make(map[uint16]map[string]struct{})
+}
+
+// record counts the flow and returns the exceeded limit, if any.
+func (s *sessionFlows) record(host string, port uint16, limits Limits) string {
+	s.flows++
+
+	ports, ok := s.hostPorts[host]
+	if !ok {
+		ports = make(map[uint16]struct{})
+		s.hostPorts[host] = ports
+	}
+	ports[port] = struct{}{}
+
+	hosts, ok := s.portHosts[port]
+	if !ok {
+		hosts = make(map[string]struct{})
+		s.portHosts[port] = hosts
+	}
+	hosts[host] = struct{}{}
+
+	switch {
+	case limits.NewFlows > 0 && s.flows > limits.NewFlows:
+		return fmt.Sprintf("%d new flows", s.flows)
+	case limits.PortsPerHost > 0 && len(ports) > limits.PortsPerHost:
+		return fmt.Sprintf("%d ports of %s", len(ports), host)
+	case limits.HostsPerPort > 0 && len(hosts) > limits.HostsPerPort:
+		return fmt.Sprintf("%d hosts on port %d", len(hosts), port)
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package abuse
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDetector(limits Limits) (*Detector, *time.Time) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := NewDetector(limits)
+	d.now = func() time.Time { return now }
+	return d, &now
+}
+
+func TestDetector_ThrottlesAndTerminatesSession(t *testing.T) {
+	d, now := newTestDetector(Limits{NewFlows: 3, Window: time.Minute, Throttle: 10 * time.Second, Strikes: 2})
+	terminated := make(chan struct{})
+	d.Track("session-1", func() { close(terminated) })
+
+	dst := net.ParseIP("1.1.1.1")
+	for port := uint16(1); port <= 3; port++ {
+		assert.True(t, d.Allow("session-1", dst, port))
+	}
+	assert.False(t, d.Allow("session-1", dst, 4), "flow over the limit")
+
+	*now = now.Add(5 * time.Second)
+	assert.False(t, d.Allow("session-1", dst, 5), "session is throttled")
+
+	*now = now.Add(5 * time.Second)
+	assert.True(t, d.Allow("session-1", dst, 6), "throttle expired")
+	assert.True(t, d.Allow("session-1", dst, 7))
+	assert.True(t, d.Allow("session-1", dst, 8))
+	assert.False(t, d.Allow("session-1", dst, 9), "second strike")
+
+	select {
+	case <-terminated:
+	case <-time.After(time.Second):
+		t.Fatal("session was not terminated")
+	}
+
+	*now = now.Add(time.Hour)
+	assert.False(t, d.Allow("session-1", dst, 10), "terminated session stays blocked")
+}
+
+func TestDetector_DetectsScans(t *testing.T) {
+	d, _ := newTestDetector(Limits{PortsPerHost: 2, HostsPerPort: 2, Window: time.Minute})
+	d.Track("vertical", func() {})
+	d.Track("horizontal", func() {})
+
+	assert.True(t, d.Allow("vertical", net.ParseIP("1.1.1.1"), 22))
+	assert.True(t, d.Allow("vertical", net.ParseIP("1.1.1.1"), 22))
+	assert.True(t, d.Allow("vertical", net.ParseIP("1.1.1.1"), 23))
+	assert.True(t, d.Allow("vertical", net.ParseIP("1.1.1.2"), 24))
+	assert.False(t, d.Allow("vertical", net.ParseIP("1.1.1.1"), 24))
+
+	assert.True(t, d.Allow("horizontal", net.ParseIP("1.1.1.1"), 22))
+	assert.True(t, d.Allow("horizontal", net.ParseIP("1.1.1.2"), 22))
+	assert.True(t, d.Allow("horizontal", net.ParseIP("1.1.1.3"), 23))
+	assert.False(t, d.Allow("horizontal", net.ParseIP("1.1.1.3"), 22))
+}
+
+func TestDetector_WindowExpires(t *testing.T) {
+	d, now := newTestDetector(Limits{NewFlows: 2, Window: time.Minute})
+	d.Track("session-1", func() {})
+
+	dst := net.ParseIP("1.1.1.1")
+	assert.True(t, d.Allow("session-1", dst, 80))
+	assert.True(t, d.Allow("session-1", dst, 80))
+
+	*now = now.Add(time.Minute)
+	assert.True(t, d.Allow("session-1", dst, 80))
+	assert.True(t, d.Allow("session-1", dst, 80))
+}
+
+func TestDetector_AllowFrom(t *testing.T) {
+	d, _ := newTestDetector(Limits{NewFlows: 1, Window: time.Minute})
+	d.Track("session-1", func() {})
+	d.Bind("session-1", net.ParseIP("10.182.0.2"))
+
+	dst := net.ParseIP("1.1.1.1")
+	assert.True(t, d.AllowFrom(net.ParseIP("10.182.0.2"), dst, 80))
+	assert.False(t, d.AllowFrom(net.ParseIP("10.182.0.2"), dst, 80))
+	assert.True(t, d.AllowFrom(net.ParseIP("10.182.1.2"), dst, 80), "unknown consumer address")
+
+	d.Untrack("session-1")
+	assert.True(t, d.AllowFrom(net.ParseIP("10.182.0.2"), dst, 80))
+	assert.Empty(t, d.Sessions())
+}
+
+func TestDetector_Disabled(t *testing.T) {
+	d, _ := newTestDetector(Limits{})
+	d.Track("session-1", func() {})
+
+	assert.Empty(t, d.Sessions())
+	assert.True(t, d.Allow("session-1", net.ParseIP("1.1.1.1"), 80))
+}
+
+func TestLimits_FlowRate(t *testing.T) {
+	assert.Equal(t, 20, Limits{NewFlows: 1200, Window: time.Minute}.FlowRate())
+	assert.Equal(t, 1, Limits{NewFlows: 10, Window: time.Minute}.FlowRate())
+	assert.Equal(t, 0, Limits{PortsPerHost: 10, Window: time.Minute}.FlowRate())
+	assert.Equal(t, 0, Limits{NewFlows: 10}.FlowRate())
+}
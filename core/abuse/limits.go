@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package abuse detects abusive consumer traffic, such as port scans and excessive new flow rate,
+// protecting the reputation of the provider IP.
+package abuse
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/config"
+)
+
+// Limits bound the flows a session may open within the window, zero value disables the respective check.
+type Limits struct {
+	// NewFlows is the number of new flows a session may open.
+	NewFlows int
+	// PortsPerHost is the number of distinct ports of a single host a session may reach, catching vertical scans.
+	PortsPerHost int
+	// HostsPerPort is the number of distinct hosts a session may reach on a single port, catching horizontal scans.
+	HostsPerPort int
+	// Window is the period flows are counted in.
+	Window time.Duration
+	// Throttle is how long new flows of a session exceeding the limits are dropped.
+	Throttle time.Duration
+	// Strikes is how many times a session may exceed the limits before it is terminated, sessions are never terminated when zero.
+	Strikes int
+}
+
+// LimitsFromConfig returns the abuse detection limits configured for the node.
+func LimitsFromConfig() Limits {
+	if !config.GetBool(config.FlagAbuseDetection) {
+		return Limits{}
+	}
+	return Limits{
+		NewFlows:     config.GetInt(config.FlagAbuseMaxNewFlows),
+		PortsPerHost: config.GetInt(config.FlagAbuseMaxPortsPerHost),
+		HostsPerPort: config.GetInt(config.FlagAbuseMaxHostsPerPort),
+		Window:       config.GetDuration(config.FlagAbuseWindow),
+		Throttle:     config.GetDuration(config.FlagAbuseThrottle),
+		Strikes:      config.GetInt(config.FlagAbuseStrikes),
+	}
+}
+
+// Enabled checks whether any flows are limited.
+func (l Limits) Enabled() bool {
+	return l.Window > 0 && (l.NewFlows > 0 || l.PortsPerHost > 0 || l.HostsPerPort > 0)
+}
+
+// FlowRate returns the average number of new flows per second a session may open, zero when not limited.
+// It is enforced by the kernel for sessions forwarded without the detector seeing every flow.
+func (l Limits) FlowRate() int {
+	if !l.Enabled() || l.NewFlows <= 0 {
+		return 0
+	}
+	rate := int(float64(l.NewFlows) / l.Window.Seconds())
+	if rate < 1 {
+		return 1
+	}
+	return rate
+}
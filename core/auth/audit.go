@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/asdine/storm/v3/q"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const auditBucket = "tequilapi-audit"
+
+// AuditEntry records a single management request: who made it, what was requested, when and with what result.
+type AuditEntry struct {
+	ID       int `storm:"id,increment"`
+	Time     time.Time
+	Actor    string
+	RemoteIP string
+	Remote   bool
+	Method   string
+	Route    string
+	Path     string
+	Status   int
+	Duration time.Duration
+}
+
+// AuditFilter narrows down listed audit entries.
+type AuditFilter struct {
+	Since *time.Time
+	Until *time.Time
+	Actor string
+	Limit int
+}
+
+// AuditLog persists audit entries and drops the ones older than the retention period.
+type AuditLog struct {
+	storage   storage.Store
+	retention time.Duration
+	now       func() time.Time
+
+	pruneMu   sync.Mutex
+	lastPrune time.Time
+}
+
+// NewAuditLog returns a new instance of AuditLog. Entries are kept forever if retention is zero.
+func NewAuditLog(storage storage.Store, retention time.Duration) *AuditLog {
+	return &AuditLog{
+		storage:   storage,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Record stores a given audit entry.
+func (al *AuditLog) Record(entry AuditEntry) error {
+	if err := al.prune(); err != nil {
+		return err
+	}
+
+	entry.ID = 0
+	entry.Time = entry.Time.UTC()
+
+	return al.storage.Store(auditBucket, &entry)
+}
+
+// List returns audit entries matching the filter, newest first.
+func (al *AuditLog) List(filter AuditFilter) (result []AuditEntry, err error) {
+	where := make([]q.Matcher, 0)
+	if filter.Since != nil {
+		where = append(where, q.Gte("Time", filter.Since.UTC()))
+	}
+	if filter.Until != nil {
+		where = append(where, q.Lte("Time", filter.Until.UTC()))
+	}
+	if filter.Actor != "" {
+		where = append(where, q.Eq("Actor", filter.Actor))
+	}
+
+	query := storage.Query{
+		Where:   q.And(where...),
+		OrderBy: "ID",
+		Reverse: true,
+		Limit:   filter.Limit,
+	}
+
+	err = al.storage.Find(auditBucket, query, &result)
+	if errors.Is(err, storage.ErrNotFound) {
+		return []AuditEntry{}, nil
+	}
+	return result, err
+}
+
+// prune deletes entries older than the retention period, at most once an hour.
+func (al *AuditLog) prune() error {
+	if al.retention <= 0 {
+		return nil
+	}
+
+	al.pruneMu.Lock()
+	now := al.now()
+	if now.Sub(al.lastPrune) < time.Hour {
+		al.pruneMu.Unlock()
+		return nil
+	}
+	al.lastPrune = now
+	al.pruneMu.Unlock()
+
+	return al.storage.DeleteMatching(auditBucket, q.Lt("Time", now.Add(-al.retention).UTC()), new(AuditEntry))
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+)
+
+func TestAuditLog(t *testing.T) {
+	dir, err := os.MkdirTemp("", "auditLogTest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bolt, err := boltdb.NewStorage(dir)
+	require.NoError(t, err)
+	defer bolt.Close()
+
+	now := time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+	log := NewAuditLog(bolt, 7*24*time.Hour)
+	log.now = func() time.Time { return now }
+
+	entries, err := log.List(AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	require.NoError(t, log.Record(AuditEntry{Time: now.Add(-10 * 24 * time.Hour), Actor: "ui", Method: "PUT", Route: "/connection"}))
+	require.NoError(t, log.Record(AuditEntry{Time: now.Add(-2 * time.Hour), Actor: "ui", Method: "DELETE", Route: "/connection"}))
+	require.NoError(t, log.Record(AuditEntry{Time: now.Add(-time.Hour), Actor: "token:abc", Method: "POST", Route: "/services"}))
+
+	t.Run("lists newest first", func(t *testing.T) {
+		entries, err := log.List(AuditFilter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, "/services", entries[0].Route)
+		assert.Equal(t, "PUT", entries[2].Method)
+	})
+
+	t.Run("filters by time, actor and limit", func(t *testing.T) {
+		since := now.Add(-3 * time.Hour)
+		entries, err := log.List(AuditFilter{Since: &since, Actor: "ui"})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "DELETE", entries[0].Method)
+
+		entries, err = log.List(AuditFilter{Limit: 2})
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("prunes entries older than retention", func(t *testing.T) {
+		now = now.Add(2 * time.Hour)
+		require.NoError(t, log.Record(AuditEntry{Time: now, Actor: "ui", Method: "POST", Route: "/identities"}))
+
+		entries, err := log.List(AuditFilter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, "DELETE", entries[2].Method)
+	})
+}
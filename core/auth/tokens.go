@@ -0,0 +1,324 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scope grants access to a group of API endpoints.
+type Scope string
+
+const (
+	// ScopeRead grants access to endpoints which do not change node state.
+	ScopeRead Scope = "read"
+	// ScopeConnect grants access to managing consumer connections.
+	ScopeConnect Scope = "connect"
+	// ScopePayments grants access to registration, settlement and top-up operations.
+	ScopePayments Scope = "payments"
+	// ScopeAdmin grants access to all endpoints.
+	ScopeAdmin Scope = "admin"
+)
+
+// Scopes lists all known scopes.
+var Scopes = []Scope{ScopeRead, ScopeConnect, ScopePayments, ScopeAdmin}
+
+// ParseScope returns a scope by its name.
+func ParseScope(name string) (Scope, error) {
+	for _, scope := range Scopes {
+		if string(scope) == name {
+			return scope, nil
+		}
+	}
+	return "", fmt.Errorf("unknown scope %q", name)
+}
+
+// Allows checks if granted scopes permit access to endpoints requiring the given scope.
+// Admin scope permits everything and every scope permits reading.
+func Allows(granted []Scope, required Scope) bool {
+	for _, scope := range granted {
+		if scope == required || scope == ScopeAdmin || required == ScopeRead {
+			return true
+		}
+	}
+	return false
+}
+
+// APIToken describes an issued API token. Token secrets are never stored, only their hashes.
+type APIToken struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Scopes    []Scope    `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+}
+
+type storedToken struct {
+	APIToken
+	Hash string `json:"hash"`
+}
+
+// ErrTokenNotFound represents an error when API token with given ID does not exist.
+var ErrTokenNotFound = errors.New("token not found")
+
+const (
+	tokensFile  = "tequilapi-tokens.json"
+	tokenPrefix = "myst_"
+)
+
+// TokenStore issues scoped API tokens and keeps their hashes in the data directory.
+type TokenStore struct {
+	fileLocation string
+
+	mu     sync.Mutex
+	loaded bool
+	tokens []storedToken
+}
+
+// NewTokenStore returns a token store persisting tokens in the given data directory.
+func NewTokenStore(dataDir string) *TokenStore {
+	return &TokenStore{
+		fileLocation: filepath.Join(dataDir, tokensFile),
+	}
+}
+
+// Issue creates a new token with the given scopes and returns its secret.
+func (s *TokenStore) Issue(name string, scopes []Scope) (string, APIToken, error) {
+	if len(scopes) == 0 {
+		return "", APIToken{}, errors.New("at least one scope is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return "", APIToken{}, err
+	}
+
+	id, err := randomString(8)
+	if err != nil {
+		return "", APIToken{}, err
+	}
+	secret, hash, err := newSecret(id)
+	if err != nil {
+		return "", APIToken{}, err
+	}
+
+	token := storedToken{
+		APIToken: APIToken{
+			ID:        id,
+			Name:      name,
+			Scopes:    scopes,
+			CreatedAt: time.Now().UTC(),
+		},
+		Hash: hash,
+	}
+	s.tokens = append(s.tokens, token)
+	if err := s.save(); err != nil {
+		s.tokens = s.tokens[:len(s.tokens)-1]
+		return "", APIToken{}, err
+	}
+
+	return secret, token.APIToken, nil
+}
+
+// Rotate replaces the secret of an existing token, invalidating the previous one.
+func (s *TokenStore) Rotate(id string) (string, APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return "", APIToken{}, err
+	}
+
+	i := s.find(id)
+	if i < 0 {
+		return "", APIToken{}, ErrTokenNotFound
+	}
+
+	secret, hash, err := newSecret(id)
+	if err != nil {
+		return "", APIToken{}, err
+	}
+
+	previous := s.tokens[i]
+	now := time.Now().UTC()
+	s.tokens[i].Hash = hash
+	s.tokens[i].RotatedAt = &now
+	if err := s.save(); err != nil {
+		s.tokens[i] = previous
+		return "", APIToken{}, err
+	}
+
+	return secret, s.tokens[i].APIToken, nil
+}
+
+// Revoke deletes a token.
+func (s *TokenStore) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	i := s.find(id)
+	if i < 0 {
+		return ErrTokenNotFound
+	}
+
+	tokens := make([]storedToken, 0, len(s.tokens)-1)
+	tokens = append(tokens, s.tokens[:i]...)
+	tokens = append(tokens, s.tokens[i+1:]...)
+
+	previous := s.tokens
+	s.tokens = tokens
+	if err := s.save(); err != nil {
+		s.tokens = previous
+		return err
+	}
+	return nil
+}
+
+// List returns all issued tokens.
+func (s *TokenStore) List() ([]APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	result := make([]APIToken, 0, len(s.tokens))
+	for _, token := range s.tokens {
+		result = append(result, token.APIToken)
+	}
+	return result, nil
+}
+
+// Authorize returns the token matching given secret.
+func (s *TokenStore) Authorize(secret string) (APIToken, error) {
+	id, ok := tokenID(secret)
+	if !ok {
+		return APIToken{}, ErrUnauthorized
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return APIToken{}, err
+	}
+
+	i := s.find(id)
+	if i < 0 {
+		return APIToken{}, ErrUnauthorized
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(s.tokens[i].Hash)) != 1 {
+		return APIToken{}, ErrUnauthorized
+	}
+
+	return s.tokens[i].APIToken, nil
+}
+
+func (s *TokenStore) find(id string) int {
+	for i := range s.tokens {
+		if s.tokens[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *TokenStore) load() error {
+	if s.loaded {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.fileLocation)
+	if errors.Is(err, os.ErrNotExist) {
+		s.loaded = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read tokens file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.tokens); err != nil {
+		return fmt.Errorf("could not parse tokens file: %w", err)
+	}
+	s.loaded = true
+	return nil
+}
+
+func (s *TokenStore) save() error {
+	data, err := json.Marshal(s.tokens)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.fileLocation + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("could not write tokens file: %w", err)
+	}
+	return os.Rename(tmp, s.fileLocation)
+}
+
+// newSecret generates a token secret embedding its ID, so that it can be looked up without scanning all hashes.
+func newSecret(id string) (secret, hash string, err error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", err
+	}
+
+	secret = tokenPrefix + id + "." + base64.RawURLEncoding.EncodeToString(random)
+	return secret, hashSecret(secret), nil
+}
+
+func tokenID(secret string) (string, bool) {
+	if !strings.HasPrefix(secret, tokenPrefix) {
+		return "", false
+	}
+	id, _, ok := strings.Cut(strings.TrimPrefix(secret, tokenPrefix), ".")
+	return id, ok && id != ""
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(size int) (string, error) {
+	random := make([]byte, size)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random), nil
+}
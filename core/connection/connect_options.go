@@ -23,6 +23,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/dns"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/session"
 )
@@ -33,6 +34,8 @@ type ConnectParams struct {
 	DisableKillSwitch bool
 	// DNS servers to use
 	DNS DNSOption
+	// DNSBlocklist filters DNS queries with the domain blocklist
+	DNSBlocklist bool
 
 	ProxyPort int
 }
@@ -48,4 +51,5 @@ type ConnectOptions struct {
 	ProviderNATConn *net.UDPConn
 	ChannelConn     *net.UDPConn
 	HermesID        common.Address
+	DNSBlocklist    *dns.Blocklist
 }
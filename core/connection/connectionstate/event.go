@@ -36,6 +36,10 @@ const (
 	AppTopicConnectionStatistics = "Statistics"
 	// AppTopicConnectionSession represents the session lifetime changes
 	AppTopicConnectionSession = "Session"
+	// AppTopicProviderSwitched represents the provider switch due to degraded connection quality
+	AppTopicProviderSwitched = "ProviderSwitched"
+	// AppTopicLocationMismatch represents the exit location not matching the one declared by provider
+	AppTopicLocationMismatch = "LocationMismatch"
 )
 
 // AppEventConnectionState is the struct we'll emit on a AppEventConnectionState topic event
@@ -110,3 +114,21 @@ type AppEventConnectionStatistics struct {
 	Stats       Statistics
 	SessionInfo Status
 }
+
+// AppEventProviderSwitched represents a provider switch event
+type AppEventProviderSwitched struct {
+	UUID   string
+	From   proposal.PricedServiceProposal
+	To     proposal.PricedServiceProposal
+	Reason string
+}
+
+// AppEventLocationMismatch is published when the exit country detected through the tunnel
+// differs from the country declared in the provider proposal.
+type AppEventLocationMismatch struct {
+	UUID            string
+	SessionID       session.ID
+	Proposal        proposal.PricedServiceProposal
+	DeclaredCountry string
+	ExitCountry     string
+}
@@ -20,8 +20,10 @@ package connection
 import (
 	"encoding/json"
 	"net"
+	"net/url"
 	"strings"
 
+	"github.com/mysteriumnetwork/node/dns"
 	"github.com/mysteriumnetwork/node/utils/stringutil"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -37,6 +39,12 @@ const (
 	DNSOptionProvider = DNSOption("provider")
 	// DNSOptionSystem uses DNS servers from client's system configuration
 	DNSOptionSystem = DNSOption("system")
+
+	// DNSStubHost is an address of local DNS stub which forwards queries to encrypted DNS servers
+	// or filters them with domain blocklist.
+	DNSStubHost = "127.0.0.1"
+	// DNSStubPort is a port of local DNS stub, system resolvers can only use the standard one.
+	DNSStubPort = 53
 )
 
 // NewDNSOption creates and validates DNSOption
@@ -47,15 +55,33 @@ func NewDNSOption(str string) (DNSOption, error) {
 		return opt, nil
 	}
 	// It may also be a set of IP addresses, e.g. 1.1.1.1,8.8.8.8
+	// or a set of DoH/DoT endpoints, e.g. https://cloudflare-dns.com/dns-query,tls://9.9.9.9
 	split := strings.Split(str, ",")
+	var plain, encrypted int
 	for _, s := range split {
+		if isEncryptedDNS(s) {
+			encrypted++
+			continue
+		}
 		if ip := net.ParseIP(s); ip == nil {
 			return "", errors.New("invalid IP address provided as a DNS option: " + s)
 		}
+		plain++
+	}
+	if plain > 0 && encrypted > 0 {
+		return "", errors.New("plain and encrypted DNS servers can not be mixed in a DNS option")
 	}
 	return opt, nil
 }
 
+func isEncryptedDNS(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	return u.Scheme == dns.SchemeDoH || u.Scheme == dns.SchemeDoT
+}
+
 // UnmarshalJSON parses JSON → DNSOption
 func (o *DNSOption) UnmarshalJSON(data []byte) error {
 	var str string
@@ -76,9 +102,26 @@ func (o DNSOption) Exact() (servers []string, ok bool) {
 	case DNSOptionAuto, DNSOptionProvider, DNSOptionSystem:
 		return nil, false
 	}
+	if _, encrypted := o.Encrypted(); encrypted {
+		return nil, false
+	}
 	return stringutil.Split(string(o), ','), true
 }
 
+// Encrypted returns a slice of DoH/DoT endpoints, if they were set
+func (o DNSOption) Encrypted() (upstreams []string, ok bool) {
+	upstreams = stringutil.Split(string(o), ',')
+	if len(upstreams) == 0 {
+		return nil, false
+	}
+	for _, u := range upstreams {
+		if !isEncryptedDNS(u) {
+			return nil, false
+		}
+	}
+	return upstreams, true
+}
+
 // ResolveIPs resolves DNS server IPs on the consumer side using self as the
 // consumer preference and `providerDNS` argument as received from the provider
 func (o *DNSOption) ResolveIPs(providerDNS string) ([]string, error) {
@@ -86,6 +129,9 @@ func (o *DNSOption) ResolveIPs(providerDNS string) ([]string, error) {
 	if exact, ok := o.Exact(); ok {
 		return exact, nil
 	}
+	if _, ok := o.Encrypted(); ok {
+		return []string{DNSStubHost}, nil
+	}
 	switch *o {
 	case DNSOptionProvider:
 		return selectProviderDNS(providerDNS)
@@ -36,9 +36,14 @@ func TestNewDNSOption(t *testing.T) {
 		{input: "1.1.1.1,9.9.9.9", expect: DNSOption("1.1.1.1,9.9.9.9")},
 		{input: "1.1.1.1", expect: DNSOption("1.1.1.1")},
 		{input: "", expect: DNSOption("")},
+		{input: "https://cloudflare-dns.com/dns-query", expect: DNSOption("https://cloudflare-dns.com/dns-query")},
+		{input: "tls://9.9.9.9,tls://dns.google:853", expect: DNSOption("tls://9.9.9.9,tls://dns.google:853")},
 		{input: "AA", expectErr: true},
 		{input: "512.512.512.512", expectErr: true},
 		{input: "1.1.1.1,512.512.512.512", expectErr: true},
+		{input: "1.1.1.1,tls://9.9.9.9", expectErr: true},
+		{input: "http://dns.example/dns-query", expectErr: true},
+		{input: "tls://", expectErr: true},
 	}
 	for i, tt := range tests {
 		option, err := NewDNSOption(tt.input)
@@ -86,6 +91,7 @@ func TestDNSOption_Exact(t *testing.T) {
 		{option: DNSOption("1.1.1.1,9.9.9.9"), expectServers: []string{"1.1.1.1", "9.9.9.9"}, expectOK: true},
 		{option: DNSOption("9.9.9.9"), expectServers: []string{"9.9.9.9"}, expectOK: true},
 		{option: DNSOption(""), expectServers: nil, expectOK: true},
+		{option: DNSOption("tls://9.9.9.9"), expectOK: false},
 	}
 	for _, tt := range tests {
 		servers, ok := tt.option.Exact()
@@ -93,3 +99,28 @@ func TestDNSOption_Exact(t *testing.T) {
 		assert.Equal(tt.expectServers, servers)
 	}
 }
+
+func TestDNSOption_Encrypted(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		option          DNSOption
+		expectUpstreams []string
+		expectOK        bool
+	}{
+		{option: DNSOptionAuto, expectOK: false},
+		{option: DNSOption("1.1.1.1"), expectOK: false},
+		{option: DNSOption(""), expectOK: false},
+		{
+			option:          DNSOption("https://cloudflare-dns.com/dns-query,tls://9.9.9.9"),
+			expectUpstreams: []string{"https://cloudflare-dns.com/dns-query", "tls://9.9.9.9"},
+			expectOK:        true,
+		},
+	}
+	for _, tt := range tests {
+		upstreams, ok := tt.option.Encrypted()
+		assert.Equal(tt.expectOK, ok)
+		if tt.expectOK {
+			assert.Equal(tt.expectUpstreams, upstreams)
+		}
+	}
+}
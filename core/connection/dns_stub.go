@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	mdns "github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	"github.com/mysteriumnetwork/node/dns"
+)
+
+// UsesDNSStub checks whether consumer DNS queries are served by the local DNS stub:
+// for forwarding them to encrypted DNS servers or filtering them with the domain blocklist.
+func (o ConnectOptions) UsesDNSStub() bool {
+	_, encrypted := o.Params.DNS.Encrypted()
+	return encrypted || o.DNSBlocklist != nil
+}
+
+// ResolveDNSIPs resolves DNS server IPs to be configured for the tunnel, see DNSOption.ResolveIPs.
+func (o ConnectOptions) ResolveDNSIPs(providerDNS string) ([]string, error) {
+	if o.UsesDNSStub() {
+		return []string{DNSStubHost}, nil
+	}
+	return o.Params.DNS.ResolveIPs(providerDNS)
+}
+
+// StartDNSStub starts local DNS stub forwarding queries to DoH/DoT endpoints or to the resolved
+// DNS servers, with blocklisted domains filtered out. The stub has to be started before
+// system DNS is pointed to it. Returned function stops the stub.
+func (o ConnectOptions) StartDNSStub(providerDNS string) (stop func() error, err error) {
+	handler, err := o.dnsStubResolver(providerDNS)
+	if err != nil {
+		return nil, err
+	}
+	if o.DNSBlocklist != nil {
+		handler = dns.BlockDomains(handler, o.DNSBlocklist)
+	}
+
+	proxy := dns.NewProxy(DNSStubHost, DNSStubPort, handler)
+	if err := proxy.Run(); err != nil {
+		return nil, errors.Wrap(err, "could not start DNS stub")
+	}
+
+	return proxy.Stop, nil
+}
+
+func (o ConnectOptions) dnsStubResolver(providerDNS string) (mdns.Handler, error) {
+	if upstreams, ok := o.Params.DNS.Encrypted(); ok {
+		return dns.ResolveViaEncrypted(upstreams)
+	}
+
+	servers, err := o.Params.DNS.ResolveIPs(providerDNS)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not resolve DNS IPs")
+	}
+	if len(servers) == 0 {
+		return dns.ResolveViaSystem()
+	}
+	return dns.ResolveViaServers(servers), nil
+}
@@ -19,6 +19,7 @@ package connection
 
 import (
 	"context"
+	"net"
 
 	"github.com/ethereum/go-ethereum/common"
 
@@ -35,12 +36,13 @@ type Connection interface {
 	Reconnect(context.Context, ConnectOptions) error
 	Stop()
 	GetConfig() (ConsumerConfig, error)
-	State() <-chan connectionstate.State
 	Statistics() (connectionstate.Statistics, error)
 }
 
-// StateChannel is the channel we receive state change events on
-type StateChannel chan connectionstate.State
+// EndpointUpdater is implemented by connections able to follow a provider endpoint change without reconnecting.
+type EndpointUpdater interface {
+	UpdateEndpoint(ip net.IP) error
+}
 
 // Manager interface provides methods to manage connection
 type Manager interface {
@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/location/locationstate"
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+type exitLocationResolver interface {
+	DetectLocation() (locationstate.Location, error)
+	GetOrigin() locationstate.Location
+}
+
+// LocationVerifier checks through the tunnel that the exit country of an established connection
+// matches the country declared in the provider proposal.
+type LocationVerifier struct {
+	resolver   exitLocationResolver
+	publisher  eventbus.Publisher
+	attempts   int
+	retryDelay time.Duration
+}
+
+// NewLocationVerifier returns a new location verifier.
+func NewLocationVerifier(resolver exitLocationResolver, publisher eventbus.Publisher) *LocationVerifier {
+	return &LocationVerifier{
+		resolver:   resolver,
+		publisher:  publisher,
+		attempts:   3,
+		retryDelay: 5 * time.Second,
+	}
+}
+
+// Subscribe subscribes to connection state changes.
+func (v *LocationVerifier) Subscribe(bus eventbus.Subscriber) error {
+	return bus.SubscribeAsync(connectionstate.AppTopicConnectionState, v.handleConnectionState)
+}
+
+func (v *LocationVerifier) handleConnectionState(e connectionstate.AppEventConnectionState) {
+	if e.State != connectionstate.Connected {
+		return
+	}
+	// Location is detected through the tunnel only when all traffic is routed through it.
+	if config.GetBool(config.FlagProxyMode) || config.GetBool(config.FlagDVPNMode) {
+		return
+	}
+
+	go v.verify(e)
+}
+
+func (v *LocationVerifier) verify(e connectionstate.AppEventConnectionState) {
+	declared := e.SessionInfo.Proposal.Location.Country
+	if declared == "" {
+		return
+	}
+
+	var exit locationstate.Location
+	for i := 1; i <= v.attempts; i++ {
+		if i > 1 {
+			time.Sleep(v.retryDelay)
+		}
+
+		loc, err := v.resolver.DetectLocation()
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to detect exit location")
+			continue
+		}
+		// Traffic not yet routed through the tunnel is reported by the IP check instead.
+		if origin := v.resolver.GetOrigin(); origin.IP != "" && loc.IP == origin.IP {
+			continue
+		}
+		if strings.EqualFold(loc.Country, declared) {
+			return
+		}
+		exit = loc
+	}
+	if exit.Country == "" {
+		return
+	}
+
+	log.Warn().Msgf("Exit country %s does not match country %s declared by provider %s", exit.Country, declared, e.SessionInfo.Proposal.ProviderID)
+	v.publisher.Publish(connectionstate.AppTopicLocationMismatch, connectionstate.AppEventLocationMismatch{
+		UUID:            e.UUID,
+		SessionID:       e.SessionInfo.SessionID,
+		Proposal:        e.SessionInfo.Proposal,
+		DeclaredCountry: declared,
+		ExitCountry:     exit.Country,
+	})
+}
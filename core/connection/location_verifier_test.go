@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/location/locationstate"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/mocks"
+)
+
+type mockExitLocationResolver struct {
+	locations []locationstate.Location
+	origin    locationstate.Location
+	err       error
+}
+
+func (r *mockExitLocationResolver) DetectLocation() (locationstate.Location, error) {
+	if r.err != nil {
+		return locationstate.Location{}, r.err
+	}
+	loc := r.locations[0]
+	if len(r.locations) > 1 {
+		r.locations = r.locations[1:]
+	}
+	return loc, nil
+}
+
+func (r *mockExitLocationResolver) GetOrigin() locationstate.Location {
+	return r.origin
+}
+
+func connectedEvent(country string) connectionstate.AppEventConnectionState {
+	return connectionstate.AppEventConnectionState{
+		UUID:  "uuid",
+		State: connectionstate.Connected,
+		SessionInfo: connectionstate.Status{
+			SessionID: "session",
+			Proposal: proposal.PricedServiceProposal{ServiceProposal: market.ServiceProposal{
+				ProviderID:  "0x1",
+				ServiceType: "wireguard",
+				Location:    market.Location{Country: country},
+			}},
+		},
+	}
+}
+
+func TestLocationVerifier(t *testing.T) {
+	origin := locationstate.Location{IP: "1.1.1.1", Country: "LT"}
+	tests := map[string]struct {
+		resolver *mockExitLocationResolver
+		declared string
+		mismatch *connectionstate.AppEventLocationMismatch
+	}{
+		"matching country": {
+			resolver: &mockExitLocationResolver{origin: origin, locations: []locationstate.Location{{IP: "2.2.2.2", Country: "us"}}},
+			declared: "US",
+		},
+		"mismatching country": {
+			resolver: &mockExitLocationResolver{origin: origin, locations: []locationstate.Location{{IP: "2.2.2.2", Country: "DE"}}},
+			declared: "US",
+			mismatch: &connectionstate.AppEventLocationMismatch{
+				UUID:            "uuid",
+				SessionID:       "session",
+				Proposal:        connectedEvent("US").SessionInfo.Proposal,
+				DeclaredCountry: "US",
+				ExitCountry:     "DE",
+			},
+		},
+		"traffic routed through tunnel after retry": {
+			resolver: &mockExitLocationResolver{origin: origin, locations: []locationstate.Location{origin, {IP: "2.2.2.2", Country: "US"}}},
+			declared: "US",
+		},
+		"traffic never routed through tunnel": {
+			resolver: &mockExitLocationResolver{origin: origin, locations: []locationstate.Location{origin}},
+			declared: "US",
+		},
+		"detection fails": {
+			resolver: &mockExitLocationResolver{origin: origin, err: errors.New("failed")},
+			declared: "US",
+		},
+		"country not declared": {
+			resolver: &mockExitLocationResolver{origin: origin, locations: []locationstate.Location{{IP: "2.2.2.2", Country: "DE"}}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bus := mocks.NewEventBus()
+			verifier := NewLocationVerifier(test.resolver, bus)
+			verifier.retryDelay = time.Millisecond
+
+			verifier.verify(connectedEvent(test.declared))
+
+			if test.mismatch == nil {
+				assert.Empty(t, bus.GetEventHistory())
+				return
+			}
+			assert.Equal(t, []mocks.EventBusEntry{{Topic: connectionstate.AppTopicLocationMismatch, Event: *test.mismatch}}, bus.GetEventHistory())
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net"
 	"sync"
 	"time"
 
@@ -35,7 +36,9 @@ import (
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/core/ip"
 	"github.com/mysteriumnetwork/node/core/location"
+	"github.com/mysteriumnetwork/node/core/power"
 	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/dns"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/firewall"
 	"github.com/mysteriumnetwork/node/identity"
@@ -44,6 +47,7 @@ import (
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/node/session"
 	"github.com/mysteriumnetwork/node/session/connectivity"
+	"github.com/mysteriumnetwork/node/session/notice"
 	"github.com/mysteriumnetwork/node/trace"
 )
 
@@ -79,12 +83,19 @@ type KeepAliveConfig struct {
 	SendInterval    time.Duration
 	SendTimeout     time.Duration
 	MaxSendErrCount int
+	// LowPowerSendInterval is used instead of SendInterval while the node runs in low-power mode.
+	LowPowerSendInterval time.Duration
 }
 
 // Config contains common configuration options for connection manager.
 type Config struct {
 	IPCheck   IPCheckConfig
 	KeepAlive KeepAliveConfig
+	Watchdog  WatchdogConfig
+	// LowPowerStatsReportInterval is used instead of the stats report interval while the node runs in low-power mode.
+	LowPowerStatsReportInterval time.Duration
+	// DNSBlocklist is used by connections requesting DNS filtering, nil disables it.
+	DNSBlocklist *dns.Blocklist
 }
 
 // DefaultConfig returns default params.
@@ -95,15 +106,24 @@ func DefaultConfig() Config {
 			SleepDurationAfterCheck: 3 * time.Second,
 		},
 		KeepAlive: KeepAliveConfig{
-			SendInterval:    5 * time.Second,
-			SendTimeout:     5 * time.Second,
-			MaxSendErrCount: 3,
+			SendInterval:         5 * time.Second,
+			SendTimeout:          5 * time.Second,
+			MaxSendErrCount:      3,
+			LowPowerSendInterval: 30 * time.Second,
 		},
+		Watchdog: WatchdogConfig{
+			CheckInterval:   30 * time.Second,
+			Cooldown:        time.Minute,
+			MinThroughput:   8 * 1024,
+			MaxErrorRate:    0.5,
+			DegradedSamples: 4,
+		},
+		LowPowerStatsReportInterval: 10 * time.Second,
 	}
 }
 
-// Creator creates new connection by given options and uses state channel to report state changes
-type Creator func(serviceType string) (Connection, error)
+// Creator creates new connection by given options and uses state handler to report state changes
+type Creator func(serviceType string, states StateHandler) (Connection, error)
 
 // ConnectionStart start new connection with a given options.
 type ConnectionStart func(context.Context, ConnectOptions) error
@@ -145,6 +165,8 @@ type connectionManager struct {
 	validator            validator
 	p2pDialer            p2p.Dialer
 	timeGetter           TimeGetter
+	noticeLimiter        *notice.Limiter
+	power                power.Mode
 
 	// These are populated by Connect at runtime.
 	ctx                    context.Context
@@ -169,6 +191,9 @@ type connectionManager struct {
 	activeConnection Connection
 	statsTracker     statsTracker
 
+	keepAliveLock  sync.Mutex
+	keepAlivePings keepAliveCounter
+
 	uuid string
 }
 
@@ -204,16 +229,22 @@ func NewManager(
 		validator:            validator,
 		p2pDialer:            p2pDialer,
 		timeGetter:           time.Now,
+		noticeLimiter:        notice.NewLimiter(notice.DefaultInterval),
 		preReconnect:         preReconnect,
 		postReconnect:        postReconnect,
 		uuid:                 uuid.String(),
 	}
 
 	m.eventBus.SubscribeAsync(connectionstate.AppTopicConnectionState, m.reconnectOnHold)
+	m.power.Subscribe(m.eventBus)
 
 	return m
 }
 
+func (m *connectionManager) statsInterval() time.Duration {
+	return m.power.Interval(m.statsReportInterval, m.config.LowPowerStatsReportInterval)
+}
+
 func (m *connectionManager) chainID() int64 {
 	return config.GetInt64(config.FlagChainID)
 }
@@ -221,17 +252,22 @@ func (m *connectionManager) chainID() int64 {
 func (m *connectionManager) Connect(consumerID identity.Identity, hermesID common.Address, proposalLookup ProposalLookup, params ConnectParams) (err error) {
 	var sessionID session.ID
 
-	proposal, err := proposalLookup()
-	if err != nil {
-		return fmt.Errorf("failed to lookup proposal: %w", err)
-	}
-
 	tracer := trace.NewTracer("Consumer whole Connect")
 	defer func() {
+		tracer.SetError(err)
 		traceResult := tracer.Finish(m.eventBus, string(sessionID))
 		log.Debug().Msgf("Consumer connection trace: %s", traceResult)
 	}()
 
+	traceLookup := tracer.StartStage("Consumer proposal lookup")
+	proposal, err := proposalLookup()
+	tracer.EndStage(traceLookup)
+	if err != nil {
+		return fmt.Errorf("failed to lookup proposal: %w", err)
+	}
+	tracer.SetAttribute("provider.id", proposal.ProviderID)
+	tracer.SetAttribute("service.type", proposal.ServiceType)
+
 	// make sure cache is cleared when connect terminates at any stage as part of disconnect
 	// we assume that IPResolver might be used / cache IP before connect
 	m.addCleanup(func() error {
@@ -269,8 +305,12 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 		ProposalLookup: proposalLookup,
 		Params:         params,
 	}
+	if params.DNSBlocklist {
+		m.connectOptions.DNSBlocklist = m.config.DNSBlocklist
+	}
 
-	m.activeConnection, err = m.newConnection(proposal.ServiceType)
+	states := NewStateQueue(DefaultStateQueueSize)
+	m.activeConnection, err = m.newConnection(proposal.ServiceType, states)
 	if err != nil {
 		return err
 	}
@@ -287,12 +327,12 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 		return m.handleStartError(sessionID, err)
 	}
 
-	err = m.waitForConnectedState(m.activeConnection.State())
+	err = m.waitForConnectedState(states)
 	if err != nil {
 		return m.handleStartError(sessionID, err)
 	}
 
-	m.statsTracker = newStatsTracker(m.eventBus, m.statsReportInterval)
+	m.statsTracker = newStatsTracker(m.eventBus, m.statsInterval)
 	go m.statsTracker.start(m, m.activeConnection)
 	m.addCleanup(func() error {
 		log.Trace().Msg("Cleaning: stopping statistics publisher")
@@ -301,8 +341,11 @@ func (m *connectionManager) Connect(consumerID identity.Identity, hermesID commo
 		return nil
 	})
 
-	go m.consumeConnectionStates(m.activeConnection.State())
+	go m.consumeConnectionStates(states)
 	go m.checkSessionIP(m.channel, m.connectOptions.ConsumerID, m.connectOptions.SessionID, originalPublicIP)
+	if config.GetBool(config.FlagAutoSwitch) {
+		go m.watchQuality(m.currentCtx())
+	}
 
 	return nil
 }
@@ -312,6 +355,7 @@ func (m *connectionManager) autoReconnect() (err error) {
 
 	tracer := trace.NewTracer("Consumer whole autoReconnect")
 	defer func() {
+		tracer.SetError(err)
 		traceResult := tracer.Finish(m.eventBus, string(sessionID))
 		log.Debug().Msgf("Consumer connection trace: %s", traceResult)
 	}()
@@ -367,7 +411,9 @@ func (m *connectionManager) initSession(tracer *trace.Tracer, prc market.Price)
 	m.connectOptions.ProviderNATConn = m.channel.ServiceConn()
 	m.connectOptions.ChannelConn = m.channel.Conn()
 
+	tracePayment := tracer.StartStage("Consumer payment setup")
 	paymentSession, err := m.paymentLoop(m.connectOptions, prc)
+	tracer.EndStage(tracePayment)
 	if err != nil {
 		return sessionID, err
 	}
@@ -381,6 +427,8 @@ func (m *connectionManager) initSession(tracer *trace.Tracer, prc market.Price)
 
 	traceStart := tracer.StartStage("Consumer session creation (start)")
 	go m.keepAliveLoop(m.channel, sessionID)
+	m.handleNotices(m.channel, sessionID)
+	m.handleEndpointChanges(m.channel, sessionID)
 	m.setStatus(func(status *connectionstate.Status) {
 		status.SessionID = sessionID
 	})
@@ -837,34 +885,38 @@ func (m *connectionManager) disconnect() {
 	m.cleanAfterDisconnect()
 }
 
-func (m *connectionManager) waitForConnectedState(stateChannel <-chan connectionstate.State) error {
+func (m *connectionManager) waitForConnectedState(states *StateQueue) error {
 	log.Debug().Msg("waiting for connected state")
+	ctx := m.currentCtx()
 	for {
-		select {
-		case state, more := <-stateChannel:
-			if !more {
-				return ErrConnectionFailed
+		state, more := states.Next(ctx)
+		if !more {
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
+			return ErrConnectionFailed
+		}
 
-			switch state {
-			case connectionstate.Connected:
-				log.Debug().Msg("Connected started event received")
-				if m.acknowledge != nil {
-					go m.acknowledge()
-				}
-				m.onStateChanged(state)
-				return nil
-			default:
-				m.onStateChanged(state)
+		switch state {
+		case connectionstate.Connected:
+			log.Debug().Msg("Connected started event received")
+			if m.acknowledge != nil {
+				go m.acknowledge()
 			}
-		case <-m.currentCtx().Done():
-			return m.currentCtx().Err()
+			m.onStateChanged(state)
+			return nil
+		default:
+			m.onStateChanged(state)
 		}
 	}
 }
 
-func (m *connectionManager) consumeConnectionStates(stateChannel <-chan connectionstate.State) {
-	for state := range stateChannel {
+func (m *connectionManager) consumeConnectionStates(states *StateQueue) {
+	for {
+		state, more := states.Next(context.Background())
+		if !more {
+			return
+		}
 		m.onStateChanged(state)
 	}
 }
@@ -942,6 +994,72 @@ func (m *connectionManager) publishStateEvent(state connectionstate.State) {
 	})
 }
 
+func (m *connectionManager) handleNotices(channel p2p.Channel, sessionID session.ID) {
+	m.addCleanup(func() error {
+		m.noticeLimiter.Forget(string(sessionID))
+		return nil
+	})
+
+	channel.Handle(p2p.TopicSessionNotice, func(c p2p.Context) error {
+		var msg pb.SessionNotice
+		if err := c.Request().UnmarshalProto(&msg); err != nil {
+			return err
+		}
+
+		if msg.GetSessionID() != string(sessionID) {
+			return fmt.Errorf("notice for unknown session %s", msg.GetSessionID())
+		}
+		text := notice.Truncate(msg.GetMessage())
+		if err := notice.Validate(text); err != nil {
+			return err
+		}
+		if !m.noticeLimiter.Allow(msg.GetSessionID()) {
+			log.Warn().Msgf("Dropping provider notice for session %s, rate limit exceeded", sessionID)
+			return c.OK()
+		}
+
+		log.Debug().Msgf("Received provider notice for session %s", sessionID)
+		m.eventBus.Publish(notice.AppTopicNoticeReceived, notice.AppEventNoticeReceived{
+			Notice: notice.Notice{
+				PeerID:       c.PeerID(),
+				SessionID:    msg.GetSessionID(),
+				Message:      text,
+				CreatedAtUTC: m.timeGetter().UTC(),
+			},
+		})
+		return c.OK()
+	})
+}
+
+func (m *connectionManager) handleEndpointChanges(channel p2p.Channel, sessionID session.ID) {
+	channel.Handle(p2p.TopicSessionEndpoint, func(c p2p.Context) error {
+		var msg pb.SessionEndpoint
+		if err := c.Request().UnmarshalProto(&msg); err != nil {
+			return err
+		}
+
+		if msg.GetSessionID() != string(sessionID) {
+			return fmt.Errorf("endpoint for unknown session %s", msg.GetSessionID())
+		}
+		endpoint := net.ParseIP(msg.GetEndpoint())
+		if endpoint == nil {
+			return fmt.Errorf("invalid endpoint %q", msg.GetEndpoint())
+		}
+
+		log.Info().Msgf("Provider endpoint changed for session %s", sessionID)
+		updater, ok := m.activeConnection.(EndpointUpdater)
+		if !ok {
+			go m.Reconnect()
+			return c.OK()
+		}
+		if err := updater.UpdateEndpoint(endpoint); err != nil {
+			log.Warn().Err(err).Msg("Failed to update provider endpoint, reconnecting")
+			go m.Reconnect()
+		}
+		return c.OK()
+	})
+}
+
 func (m *connectionManager) keepAliveLoop(channel p2p.Channel, sessionID session.ID) {
 	// Register handler for handling p2p keep alive pings from provider.
 	channel.Handle(p2p.TopicKeepAlive, func(c p2p.Context) error {
@@ -961,9 +1079,11 @@ func (m *connectionManager) keepAliveLoop(channel p2p.Channel, sessionID session
 		case <-m.currentCtx().Done():
 			log.Debug().Msgf("Stopping p2p keepalive: %v", m.currentCtx().Err())
 			return
-		case <-time.After(m.config.KeepAlive.SendInterval):
+		case <-time.After(m.power.Interval(m.config.KeepAlive.SendInterval, m.config.KeepAlive.LowPowerSendInterval)):
 			ctx, cancel := context.WithTimeout(context.Background(), m.config.KeepAlive.SendTimeout)
-			if err := m.sendKeepAlivePing(ctx, channel, sessionID); err != nil {
+			err := m.sendKeepAlivePing(ctx, channel, sessionID)
+			m.countKeepAlive(err)
+			if err != nil {
 				log.Err(err).Msgf("Failed to send p2p keepalive ping. SessionID=%s", sessionID)
 				errCount++
 				if errCount == m.config.KeepAlive.MaxSendErrCount {
@@ -1003,6 +1123,95 @@ func (m *connectionManager) sendKeepAlivePing(ctx context.Context, channel p2p.C
 	return nil
 }
 
+func (m *connectionManager) countKeepAlive(err error) {
+	m.keepAliveLock.Lock()
+	defer m.keepAliveLock.Unlock()
+
+	m.keepAlivePings.sent++
+	if err != nil {
+		m.keepAlivePings.failed++
+	}
+}
+
+func (m *connectionManager) keepAliveCount() keepAliveCounter {
+	m.keepAliveLock.Lock()
+	defer m.keepAliveLock.Unlock()
+
+	return m.keepAlivePings
+}
+
+func (m *connectionManager) watchQuality(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(m.config.Watchdog.Cooldown):
+	}
+
+	watchdog := newQualityWatchdog(m.config.Watchdog)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(m.config.Watchdog.CheckInterval):
+			if m.Status().State != connectionstate.Connected {
+				continue
+			}
+
+			reason := watchdog.sample(m.Stats(), m.keepAliveCount(), m.timeGetter())
+			if reason == "" {
+				continue
+			}
+
+			log.Warn().Msgf("Connection quality degraded: %s", reason)
+			if m.switchProvider(reason) {
+				return
+			}
+		}
+	}
+}
+
+// switchProvider migrates connection to the next best proposal.
+// It returns false if there is no other provider to switch to.
+func (m *connectionManager) switchProvider(reason string) bool {
+	from := m.Status().Proposal
+	lookup := m.connectOptions.ProposalLookup
+
+	candidate, err := lookup()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to find provider to switch to")
+		return false
+	}
+	if candidate.ProviderID == from.ProviderID {
+		log.Info().Msg("No better provider available, keeping current connection")
+		return false
+	}
+
+	picked := false
+	next := func() (*proposal.PricedServiceProposal, error) {
+		if !picked {
+			picked = true
+			return candidate, nil
+		}
+		return lookup()
+	}
+
+	log.Info().Msgf("Switching provider %s -> %s", from.ProviderID, candidate.ProviderID)
+	go func() {
+		if err := m.reconnect(next); err != nil {
+			log.Error().Err(err).Msgf("Failed to switch provider")
+			return
+		}
+
+		m.eventBus.Publish(connectionstate.AppTopicProviderSwitched, connectionstate.AppEventProviderSwitched{
+			UUID:   m.uuid,
+			From:   from,
+			To:     m.Status().Proposal,
+			Reason: reason,
+		})
+	}()
+	return true
+}
+
 func (m *connectionManager) currentCtx() context.Context {
 	m.ctxLock.RLock()
 	defer m.ctxLock.RUnlock()
@@ -1011,6 +1220,12 @@ func (m *connectionManager) currentCtx() context.Context {
 }
 
 func (m *connectionManager) Reconnect() {
+	if err := m.reconnect(m.connectOptions.ProposalLookup); err != nil {
+		log.Error().Err(err).Msgf("Failed to reconnect")
+	}
+}
+
+func (m *connectionManager) reconnect(proposalLookup ProposalLookup) error {
 	err := m.Disconnect()
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to disconnect stale session")
@@ -1020,10 +1235,7 @@ func (m *connectionManager) Reconnect() {
 	m.cleanupFinishedLock.Lock()
 	defer m.cleanupFinishedLock.Unlock()
 	<-m.cleanupFinished
-	err = m.Connect(m.connectOptions.ConsumerID, m.connectOptions.HermesID, m.connectOptions.ProposalLookup, m.connectOptions.Params)
-	if err != nil {
-		log.Error().Err(err).Msgf("Failed to reconnect")
-	}
+	return m.Connect(m.connectOptions.ConsumerID, m.connectOptions.HermesID, proposalLookup, m.connectOptions.Params)
 }
 
 func logDisconnectError(err error) {
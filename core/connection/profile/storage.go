@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package profile
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/mysteriumnetwork/node/core/connection"
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const (
+	bucketName  = "connection-profiles"
+	profilesKey = "profiles"
+)
+
+// ErrNotFound is returned when there's no profile with the requested name.
+var ErrNotFound = errors.New("connection profile not found")
+
+// Profile is a named set of connection parameters, so that many nodes can be managed declaratively.
+type Profile struct {
+	Name        string
+	ConsumerID  string
+	HermesID    string
+	ServiceType string
+
+	Providers               []string
+	CountryCode             string
+	IPType                  string
+	IncludeMonitoringFailed bool
+	SortBy                  string
+	PricePerHourMax         *big.Int
+	PricePerGiBMax          *big.Int
+
+	DisableKillSwitch bool
+	DNS               connection.DNSOption
+	DNSBlocklist      bool
+	ProxyPort         int
+}
+
+type persistentStorage interface {
+	GetValue(bucket string, key interface{}, to interface{}) error
+	SetValue(bucket string, key interface{}, to interface{}) error
+}
+
+// Storage keeps connection profiles. Profiles are stored as a single value, so any change is applied atomically.
+type Storage struct {
+	storage persistentStorage
+	mu      sync.Mutex
+}
+
+// NewStorage returns a new connection profile storage.
+func NewStorage(storage persistentStorage) *Storage {
+	return &Storage{storage: storage}
+}
+
+// List returns all profiles ordered by name.
+func (s *Storage) List() ([]Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profiles, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Profile, 0, len(profiles))
+	for _, p := range profiles {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// Get returns a profile by name.
+func (s *Storage) Get(name string) (Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profiles, err := s.load()
+	if err != nil {
+		return Profile{}, err
+	}
+
+	p, ok := profiles[name]
+	if !ok {
+		return Profile{}, ErrNotFound
+	}
+	return p, nil
+}
+
+// Save creates or replaces a profile.
+func (s *Storage) Save(p Profile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profiles, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	profiles[p.Name] = p
+	return s.storage.SetValue(bucketName, profilesKey, profiles)
+}
+
+// Replace replaces all profiles with the given ones.
+func (s *Storage) Replace(list []Profile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profiles := make(map[string]Profile, len(list))
+	for _, p := range list {
+		profiles[p.Name] = p
+	}
+	return s.storage.SetValue(bucketName, profilesKey, profiles)
+}
+
+// Delete removes a profile by name.
+func (s *Storage) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profiles, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := profiles[name]; !ok {
+		return ErrNotFound
+	}
+	delete(profiles, name)
+	return s.storage.SetValue(bucketName, profilesKey, profiles)
+}
+
+func (s *Storage) load() (map[string]Profile, error) {
+	profiles := make(map[string]Profile)
+	err := s.storage.GetValue(bucketName, profilesKey, &profiles)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+	return profiles, nil
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package profile
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/connection"
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+)
+
+func TestStorage(t *testing.T) {
+	dir, err := os.MkdirTemp("", "connectionProfileStorageTest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bolt, err := boltdb.NewStorage(dir)
+	require.NoError(t, err)
+	defer bolt.Close()
+	storage := NewStorage(bolt)
+
+	list, err := storage.List()
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+
+	_, err = storage.Get("eu")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	eu := Profile{Name: "eu", ConsumerID: "0x1", CountryCode: "DE", PricePerGiBMax: big.NewInt(100), DNS: connection.DNSOptionProvider}
+	require.NoError(t, storage.Save(eu))
+	require.NoError(t, storage.Save(Profile{Name: "asia", CountryCode: "JP"}))
+
+	got, err := storage.Get("eu")
+	assert.NoError(t, err)
+	assert.Equal(t, eu, got)
+
+	list, err = storage.List()
+	assert.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "asia", list[0].Name)
+	assert.Equal(t, "eu", list[1].Name)
+
+	require.NoError(t, storage.Replace([]Profile{{Name: "us", CountryCode: "US"}}))
+	list, err = storage.List()
+	assert.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "us", list[0].Name)
+
+	assert.ErrorIs(t, storage.Delete("eu"), ErrNotFound)
+	assert.NoError(t, storage.Delete("us"))
+	list, err = storage.List()
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+}
@@ -17,8 +17,8 @@
 
 package connection
 
-// Factory represents a connection constructor
-type Factory func() (Connection, error)
+// Factory represents a connection constructor, the connection reports its state changes to the given handler
+type Factory func(states StateHandler) (Connection, error)
 
 // Registry holds of all plugable connections
 type Registry struct {
@@ -38,11 +38,11 @@ func (registry *Registry) Register(serviceType string, creator Factory) {
 }
 
 // CreateConnection create plugable connection
-func (registry *Registry) CreateConnection(serviceType string) (Connection, error) {
+func (registry *Registry) CreateConnection(serviceType string, states StateHandler) (Connection, error) {
 	factory, exists := registry.creators[serviceType]
 	if !exists {
 		return nil, ErrUnsupportedServiceType
 	}
 
-	return factory()
+	return factory(states)
 }
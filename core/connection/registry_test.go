@@ -39,8 +39,8 @@ func TestRegistry_Register(t *testing.T) {
 		creators: map[string]Factory{},
 	}
 
-	registry.Register(serviceType, func() (connection Connection, err error) {
-		return &connectionMock{}, nil
+	registry.Register(serviceType, func(states StateHandler) (connection Connection, err error) {
+		return &connectionMock{states: states}, nil
 	})
 	assert.Len(t, registry.creators, 1)
 }
@@ -48,7 +48,7 @@ func TestRegistry_Register(t *testing.T) {
 func TestRegistry_CreateConnection_NonExisting(t *testing.T) {
 	registry := &Registry{}
 
-	connection, err := registry.CreateConnection(serviceType)
+	connection, err := registry.CreateConnection(serviceType, NewStateQueue(DefaultStateQueueSize))
 	assert.Equal(t, ErrUnsupportedServiceType, err)
 	assert.Nil(t, connection)
 }
@@ -57,13 +57,16 @@ func TestRegistry_CreateConnection_Existing(t *testing.T) {
 	mock := &connectionMock{}
 	registry := Registry{
 		creators: map[string]Factory{
-			"fake-service": func() (connection Connection, err error) {
+			"fake-service": func(states StateHandler) (connection Connection, err error) {
+				mock.states = states
 				return mock, nil
 			},
 		},
 	}
 
-	connection, err := registry.CreateConnection("fake-service")
+	states := NewStateQueue(DefaultStateQueueSize)
+	connection, err := registry.CreateConnection("fake-service", states)
 	assert.NoError(t, err)
 	assert.Equal(t, mock, connection)
+	assert.Equal(t, states, mock.states)
 }
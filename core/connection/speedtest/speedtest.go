@@ -0,0 +1,279 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package speedtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/quality"
+)
+
+const progressInterval = 250 * time.Millisecond
+
+// Stages of the speed test.
+const (
+	StageDownload = "download"
+	StageUpload   = "upload"
+)
+
+// ErrNotConnected is returned when there's no established connection to test.
+var ErrNotConnected = errors.New("no established connection to test")
+
+// Config holds endpoints the speed test is run against.
+type Config struct {
+	// DownloadURL serves a large enough body to be downloaded for Duration.
+	DownloadURL string
+	// UploadURL accepts a POST request body of any size.
+	UploadURL string
+	// Duration limits each of the download and upload stages.
+	Duration time.Duration
+	// UploadSize is the largest amount of bytes uploaded.
+	UploadSize int64
+}
+
+// DefaultConfig returns default speed test settings.
+func DefaultConfig() Config {
+	return Config{
+		DownloadURL: "https://speed.cloudflare.com/__down?bytes=1000000000",
+		UploadURL:   "https://speed.cloudflare.com/__up",
+		Duration:    10 * time.Second,
+		UploadSize:  200 << 20,
+	}
+}
+
+// Progress reports the stage of a running speed test.
+type Progress struct {
+	Stage   string
+	Bytes   uint64
+	Elapsed time.Duration
+	Mbps    float64
+}
+
+// Result is the outcome of the speed test.
+type Result struct {
+	ProviderID   string
+	ServiceType  string
+	StartedAt    time.Time
+	Latency      time.Duration
+	DownloadMbps float64
+	UploadMbps   float64
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+	DoViaProxy(req *http.Request, proxyPort int) (*http.Response, error)
+}
+
+type connectionStatus interface {
+	Status(n int) connectionstate.Status
+}
+
+type resultStorage interface {
+	AddSpeedTest(providerID, serviceType string, test quality.SpeedTest) error
+}
+
+// Tester measures throughput through an established connection.
+type Tester struct {
+	httpClient  httpClient
+	connections connectionStatus
+	results     resultStorage
+	config      Config
+	now         func() time.Time
+}
+
+// NewTester returns a new speed tester. Results are stored per provider in the quality history.
+func NewTester(httpClient httpClient, connections connectionStatus, results resultStorage, config Config) *Tester {
+	return &Tester{
+		httpClient:  httpClient,
+		connections: connections,
+		results:     results,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// Run measures the latency, download and upload throughput through the connection with the given ID.
+// Connections with a proxy port are tested through their proxy, others through the system routes.
+func (t *Tester) Run(ctx context.Context, connectionID int, progress func(Progress)) (Result, error) {
+	status := t.connections.Status(connectionID)
+	if status.State != connectionstate.Connected {
+		return Result{}, ErrNotConnected
+	}
+
+	result := Result{
+		ProviderID:  status.Proposal.ProviderID,
+		ServiceType: status.Proposal.ServiceType,
+		StartedAt:   t.now(),
+	}
+
+	var err error
+	result.Latency, result.DownloadMbps, err = t.download(ctx, connectionID, progress)
+	if err != nil {
+		return Result{}, fmt.Errorf("download failed: %w", err)
+	}
+	result.UploadMbps, err = t.upload(ctx, connectionID, progress)
+	if err != nil {
+		return Result{}, fmt.Errorf("upload failed: %w", err)
+	}
+
+	err = t.results.AddSpeedTest(result.ProviderID, result.ServiceType, quality.SpeedTest{
+		At:           result.StartedAt.UTC(),
+		Latency:      result.Latency,
+		DownloadMbps: result.DownloadMbps,
+		UploadMbps:   result.UploadMbps,
+	})
+	return result, err
+}
+
+func (t *Tester) download(ctx context.Context, connectionID int, progress func(Progress)) (latency time.Duration, mbps float64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, t.config.Duration)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.config.DownloadURL, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	started := t.now()
+	resp, err := t.do(req, connectionID)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+	latency = t.now().Sub(started)
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("download endpoint responded with %s", resp.Status)
+	}
+
+	counter := &countingReader{reader: resp.Body}
+	stop := t.reportProgress(StageDownload, counter, progress)
+	_, err = io.Copy(io.Discard, counter)
+	measured := stop()
+	if err != nil && !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return 0, 0, err
+	}
+	return latency, measured.Mbps, nil
+}
+
+func (t *Tester) upload(ctx context.Context, connectionID int, progress func(Progress)) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.config.Duration)
+	defer cancel()
+
+	counter := &countingReader{reader: io.LimitReader(zeroReader{}, t.config.UploadSize)}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.UploadURL, counter)
+	if err != nil {
+		return 0, err
+	}
+	req.ContentLength = t.config.UploadSize
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	stop := t.reportProgress(StageUpload, counter, progress)
+	resp, err := t.do(req, connectionID)
+	measured := stop()
+	if err != nil {
+		// Upload cut by the deadline still measured the throughput.
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && measured.Bytes > 0 {
+			return measured.Mbps, nil
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, fmt.Errorf("upload endpoint responded with %s", resp.Status)
+	}
+	return measured.Mbps, nil
+}
+
+func (t *Tester) do(req *http.Request, connectionID int) (*http.Response, error) {
+	if connectionID > 0 {
+		return t.httpClient.DoViaProxy(req, connectionID)
+	}
+	return t.httpClient.Do(req)
+}
+
+// reportProgress reports transferred bytes periodically until stopped, stop returns the final measurement.
+func (t *Tester) reportProgress(stage string, counter *countingReader, progress func(Progress)) (stop func() Progress) {
+	started := t.now()
+	measure := func() Progress {
+		elapsed := t.now().Sub(started)
+		p := Progress{Stage: stage, Bytes: counter.count(), Elapsed: elapsed}
+		if elapsed > 0 {
+			p.Mbps = float64(p.Bytes) * 8 / elapsed.Seconds() / 1e6
+		}
+		return p
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				progress(measure())
+			}
+		}
+	}()
+
+	return func() Progress {
+		close(done)
+		<-finished
+
+		final := measure()
+		progress(final)
+		return final
+	}
+}
+
+type countingReader struct {
+	reader io.Reader
+	bytes  uint64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	atomic.AddUint64(&r.bytes, uint64(n))
+	return n, err
+}
+
+func (r *countingReader) count() uint64 {
+	return atomic.LoadUint64(&r.bytes)
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package speedtest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+type mockHTTPClient struct {
+	proxyPort int
+}
+
+func (c *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return http.DefaultClient.Do(req)
+}
+
+func (c *mockHTTPClient) DoViaProxy(req *http.Request, proxyPort int) (*http.Response, error) {
+	c.proxyPort = proxyPort
+	return http.DefaultClient.Do(req)
+}
+
+type mockConnections struct {
+	status connectionstate.Status
+}
+
+func (m *mockConnections) Status(int) connectionstate.Status {
+	return m.status
+}
+
+type mockResults struct {
+	providerID string
+	tests      []quality.SpeedTest
+}
+
+func (m *mockResults) AddSpeedTest(providerID, serviceType string, test quality.SpeedTest) error {
+	m.providerID = providerID
+	m.tests = append(m.tests, test)
+	return nil
+}
+
+func newSpeedTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/down", func(w http.ResponseWriter, r *http.Request) {
+		chunk := make([]byte, 64<<10)
+		for {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+	mux.HandleFunc("/up", func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestTesterMeasuresThroughput(t *testing.T) {
+	server := newSpeedTestServer()
+	defer server.Close()
+
+	client := &mockHTTPClient{}
+	connections := &mockConnections{status: connectionstate.Status{
+		State:    connectionstate.Connected,
+		Proposal: proposal.PricedServiceProposal{ServiceProposal: market.ServiceProposal{ProviderID: "0x1", ServiceType: "wireguard"}},
+	}}
+	results := &mockResults{}
+	tester := NewTester(client, connections, results, Config{
+		DownloadURL: server.URL + "/down",
+		UploadURL:   server.URL + "/up",
+		Duration:    500 * time.Millisecond,
+		UploadSize:  1 << 20,
+	})
+
+	var mu sync.Mutex
+	stages := make(map[string]bool)
+	result, err := tester.Run(context.Background(), 10000, func(p Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		stages[p.Stage] = true
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 10000, client.proxyPort)
+	assert.Equal(t, "0x1", result.ProviderID)
+	assert.Greater(t, result.DownloadMbps, 0.)
+	assert.Greater(t, result.UploadMbps, 0.)
+	assert.Equal(t, map[string]bool{StageDownload: true, StageUpload: true}, stages)
+
+	require.Len(t, results.tests, 1)
+	assert.Equal(t, "0x1", results.providerID)
+	assert.Equal(t, result.DownloadMbps, results.tests[0].DownloadMbps)
+}
+
+func TestTesterRequiresConnection(t *testing.T) {
+	tester := NewTester(&mockHTTPClient{}, &mockConnections{}, &mockResults{}, DefaultConfig())
+
+	_, err := tester.Run(context.Background(), 0, func(Progress) {})
+	assert.ErrorIs(t, err, ErrNotConnected)
+}
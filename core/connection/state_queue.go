@@ -0,0 +1,186 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/metrics"
+)
+
+// DefaultStateQueueSize is the number of connection states buffered for a slow consumer.
+const DefaultStateQueueSize = 100
+
+var droppedStates = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "connection",
+	Name:      "dropped_states_total",
+	Help:      "Total number of intermediate connection states coalesced because the consumer fell behind.",
+})
+
+func init() {
+	metrics.Registry.MustRegister(droppedStates)
+}
+
+// StateHandler receives connection state changes from the connection implementation.
+// Implementations must not block the caller.
+type StateHandler interface {
+	// Handle delivers a new connection state.
+	Handle(state connectionstate.State)
+	// Close signals that the connection will not report any more states.
+	Close()
+}
+
+// StateQueue is a bounded StateHandler which never blocks the producer.
+// When the consumer falls behind, intermediate states are coalesced, while transition
+// and terminal states are always delivered, even beyond the queue size.
+type StateQueue struct {
+	size int
+	wake chan struct{}
+
+	mu      sync.Mutex
+	pending []connectionstate.State
+	closed  bool
+	dropped uint64
+}
+
+var _ StateHandler = &StateQueue{}
+
+// NewStateQueue creates a state queue buffering up to size states.
+func NewStateQueue(size int) *StateQueue {
+	if size < 1 {
+		size = 1
+	}
+	return &StateQueue{
+		size: size,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// Handle queues the state. When the queue is full, a queued intermediate state or the new one,
+// if it repeats the last queued state, is dropped to make room.
+func (q *StateQueue) Handle(state connectionstate.State) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		log.Warn().Msgf("Connection state %s reported after close, ignoring", state)
+		return
+	}
+
+	if len(q.pending) >= q.size && !q.makeRoom(state) {
+		return
+	}
+	q.pending = append(q.pending, state)
+	q.notify()
+}
+
+// makeRoom frees a place for the state in the full queue and tells whether the state should be queued.
+func (q *StateQueue) makeRoom(state connectionstate.State) bool {
+	if isIntermediateState(state) && q.pending[len(q.pending)-1] == state {
+		q.drop(state)
+		return false
+	}
+
+	for i, queued := range q.pending {
+		if isIntermediateState(queued) {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			q.drop(queued)
+			return true
+		}
+	}
+
+	if isIntermediateState(state) {
+		q.drop(state)
+		return false
+	}
+
+	log.Warn().Msgf("Connection state consumer is too slow, queueing state %s over the limit", state)
+	return true
+}
+
+func (q *StateQueue) drop(state connectionstate.State) {
+	q.dropped++
+	droppedStates.Inc()
+	log.Warn().Msgf("Connection state consumer is too slow, dropped state %s", state)
+}
+
+// Close stops accepting states, queued states are still delivered by Next. It is safe to call Close multiple times.
+func (q *StateQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	q.notify()
+}
+
+// Next waits for the next queued state. It returns false once the queue is closed and drained or the context is done.
+// States are meant for a single consumer.
+func (q *StateQueue) Next(ctx context.Context) (connectionstate.State, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.pending) > 0 {
+			state := q.pending[0]
+			q.pending = q.pending[1:]
+			q.mu.Unlock()
+			return state, true
+		}
+		closed := q.closed
+		q.mu.Unlock()
+
+		if closed {
+			return "", false
+		}
+
+		select {
+		case <-q.wake:
+		case <-ctx.Done():
+			return "", false
+		}
+	}
+}
+
+// Dropped returns the number of states dropped because the consumer fell behind.
+func (q *StateQueue) Dropped() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.dropped
+}
+
+func (q *StateQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// isIntermediateState tells whether the state is only a progress report, superseded by any later state.
+func isIntermediateState(state connectionstate.State) bool {
+	switch state {
+	case connectionstate.Reconnecting, connectionstate.Unknown:
+		return true
+	default:
+		return false
+	}
+}
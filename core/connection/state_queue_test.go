@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+)
+
+func TestStateQueue_DeliversStatesInOrder(t *testing.T) {
+	queue := NewStateQueue(3)
+	queue.Handle(connectionstate.Connecting)
+	queue.Handle(connectionstate.Connected)
+	queue.Close()
+
+	assert.Equal(t, []connectionstate.State{connectionstate.Connecting, connectionstate.Connected}, drainStates(queue))
+	assert.Zero(t, queue.Dropped())
+}
+
+func TestStateQueue_CoalescesIntermediateStatesWhenFull(t *testing.T) {
+	queue := NewStateQueue(2)
+	queue.Handle(connectionstate.Connecting)
+	for i := 0; i < 5; i++ {
+		queue.Handle(connectionstate.Reconnecting)
+	}
+	queue.Handle(connectionstate.Connected)
+	queue.Handle(connectionstate.Disconnecting)
+	queue.Handle(connectionstate.NotConnected)
+	queue.Close()
+
+	assert.Equal(t, uint64(5), queue.Dropped())
+	assert.Equal(t, []connectionstate.State{
+		connectionstate.Connecting,
+		connectionstate.Connected,
+		connectionstate.Disconnecting,
+		connectionstate.NotConnected,
+	}, drainStates(queue))
+}
+
+func TestStateQueue_KeepsTransitionStatesOverLimit(t *testing.T) {
+	queue := NewStateQueue(1)
+	queue.Handle(connectionstate.Connecting)
+	queue.Handle(connectionstate.Canceled)
+	queue.Handle(connectionstate.NotConnected)
+	queue.Close()
+
+	assert.Zero(t, queue.Dropped())
+	assert.Equal(t, []connectionstate.State{
+		connectionstate.Connecting,
+		connectionstate.Canceled,
+		connectionstate.NotConnected,
+	}, drainStates(queue))
+}
+
+func TestStateQueue_IgnoresStatesAfterClose(t *testing.T) {
+	queue := NewStateQueue(1)
+	queue.Close()
+	queue.Close()
+	queue.Handle(connectionstate.Connected)
+
+	_, ok := queue.Next(context.Background())
+	assert.False(t, ok)
+}
+
+func TestStateQueue_NextStopsOnContextDone(t *testing.T) {
+	queue := NewStateQueue(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, ok := queue.Next(ctx)
+	assert.False(t, ok)
+}
+
+func drainStates(queue *StateQueue) (states []connectionstate.State) {
+	for {
+		state, ok := queue.Next(context.Background())
+		if !ok {
+			return states
+		}
+		states = append(states, state)
+	}
+}
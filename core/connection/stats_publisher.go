@@ -34,13 +34,13 @@ type statsSupplier interface {
 type statsTracker struct {
 	done     chan struct{}
 	bus      eventbus.Publisher
-	interval time.Duration
+	interval func() time.Duration
 
 	mu        sync.RWMutex
 	lastStats connectionstate.Statistics
 }
 
-func newStatsTracker(bus eventbus.Publisher, interval time.Duration) statsTracker {
+func newStatsTracker(bus eventbus.Publisher, interval func() time.Duration) statsTracker {
 	return statsTracker{
 		done:     make(chan struct{}),
 		bus:      bus,
@@ -51,7 +51,7 @@ func newStatsTracker(bus eventbus.Publisher, interval time.Duration) statsTracke
 func (s *statsTracker) start(sessionSupplier *connectionManager, statsSupplier statsSupplier) {
 	for {
 		select {
-		case <-time.After(s.interval):
+		case <-time.After(s.interval()):
 			stats, err := statsSupplier.Statistics()
 			if err != nil {
 				log.Warn().Err(err).Msg("Could not get connection statistics")
@@ -44,34 +44,34 @@ type connectionFactoryFake struct {
 	mockConnection *connectionMock
 }
 
-func (c *connectionFactoryFake) CreateConnection(serviceType string) (Connection, error) {
+func (c *connectionFactoryFake) CreateConnection(serviceType string, states StateHandler) (Connection, error) {
 	// each test can set this value to simulate connection creation error, this flag is reset BEFORE each test
 	if c.mockError != nil {
 		return nil, c.mockError
 	}
 
-	c.mockConnection.stateChannel = make(chan connectionstate.State, 100)
+	c.mockConnection.states = states
 
 	stateCallback := func(state fakeState) {
 		if state == connectedState {
-			c.mockConnection.stateChannel <- connectionstate.Connected
+			states.Handle(connectionstate.Connected)
 		}
 		if state == exitingState {
-			c.mockConnection.stateChannel <- connectionstate.Disconnecting
+			states.Handle(connectionstate.Disconnecting)
 		}
 		if state == reconnectingState {
-			c.mockConnection.stateChannel <- connectionstate.Reconnecting
+			states.Handle(connectionstate.Reconnecting)
 		}
-		// this is the last state - close channel (according to best practices of go - channel writer controls channel)
+		// this is the last state - close handler
 		if state == processExited {
-			close(c.mockConnection.stateChannel)
+			states.Close()
 		}
 	}
 	c.mockConnection.StateCallback(stateCallback)
 
 	// we copy the values over, so that the factory always returns a new instance of connection
 	copy := connectionMock{
-		stateChannel:        c.mockConnection.stateChannel,
+		states:              c.mockConnection.states,
 		onStartReportStates: c.mockConnection.onStartReportStates,
 		onStartReturnError:  c.mockConnection.onStartReturnError,
 		onStopReportStates:  c.mockConnection.onStopReportStates,
@@ -85,7 +85,7 @@ func (c *connectionFactoryFake) CreateConnection(serviceType string) (Connection
 }
 
 type connectionMock struct {
-	stateChannel        chan connectionstate.State
+	states              StateHandler
 	onStartReturnError  error
 	onStartReportStates []fakeState
 	onStopReportStates  []fakeState
@@ -96,10 +96,6 @@ type connectionMock struct {
 	sync.RWMutex
 }
 
-func (c *connectionMock) State() <-chan connectionstate.State {
-	return c.stateChannel
-}
-
 func (c *connectionMock) Statistics() (connectionstate.Statistics, error) {
 	return c.onStartReportStats, nil
 }
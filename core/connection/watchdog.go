@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/datasize"
+)
+
+// WatchdogConfig contains connection quality watchdog options.
+type WatchdogConfig struct {
+	// CheckInterval is how often connection quality is sampled.
+	CheckInterval time.Duration
+	// Cooldown is a time given for a fresh connection to settle before its quality is judged.
+	Cooldown time.Duration
+	// MinThroughput is the lowest acceptable throughput in bytes per second while keep alive pings fail.
+	MinThroughput uint64
+	// MaxErrorRate is the highest acceptable share of failed keep alive pings within a sample.
+	MaxErrorRate float64
+	// DegradedSamples is a number of consecutive degraded samples after which provider is switched.
+	DegradedSamples int
+}
+
+// keepAliveCounter counts keep alive pings sent to provider.
+type keepAliveCounter struct {
+	sent, failed uint64
+}
+
+type qualityWatchdog struct {
+	config WatchdogConfig
+
+	lastStats connectionstate.Statistics
+	lastPings keepAliveCounter
+	lastAt    time.Time
+	degraded  int
+}
+
+func newQualityWatchdog(config WatchdogConfig) *qualityWatchdog {
+	return &qualityWatchdog{config: config}
+}
+
+// sample evaluates connection quality since the previous sample.
+// It returns a non empty reason once connection stays degraded for the configured number of samples.
+func (w *qualityWatchdog) sample(stats connectionstate.Statistics, pings keepAliveCounter, now time.Time) string {
+	defer func() {
+		w.lastStats = stats
+		w.lastPings = pings
+		w.lastAt = now
+	}()
+
+	if w.lastAt.IsZero() {
+		return ""
+	}
+
+	reason := w.evaluate(w.lastStats.Diff(stats), now.Sub(w.lastAt), keepAliveCounter{
+		sent:   pings.sent - w.lastPings.sent,
+		failed: pings.failed - w.lastPings.failed,
+	})
+	if reason == "" {
+		w.degraded = 0
+		return ""
+	}
+
+	w.degraded++
+	if w.degraded < w.config.DegradedSamples {
+		return ""
+	}
+	w.degraded = 0
+	return reason
+}
+
+func (w *qualityWatchdog) evaluate(traffic connectionstate.Statistics, elapsed time.Duration, pings keepAliveCounter) string {
+	if pings.sent > 0 && w.config.MaxErrorRate > 0 {
+		if rate := float64(pings.failed) / float64(pings.sent); rate > w.config.MaxErrorRate {
+			return fmt.Sprintf("keep alive error rate %.2f exceeds %.2f", rate, w.config.MaxErrorRate)
+		}
+	}
+
+	// Tunnel is stalled if nothing comes back for the traffic sent.
+	if traffic.BytesSent > 0 && traffic.BytesReceived == 0 {
+		return fmt.Sprintf("nothing received for %s sent", datasize.FromBytes(traffic.BytesSent))
+	}
+
+	// Low throughput alone is rather low demand than bad quality,
+	// so it is only counted together with failing keep alive pings.
+	bytes := traffic.BytesSent + traffic.BytesReceived
+	if pings.failed == 0 || bytes == 0 || elapsed <= 0 || w.config.MinThroughput == 0 {
+		return ""
+	}
+	throughput := uint64(float64(bytes) / elapsed.Seconds())
+	if throughput < w.config.MinThroughput {
+		return fmt.Sprintf("throughput %s/s is below %s/s with %d failed keep alive pings", datasize.FromBytes(throughput), datasize.FromBytes(w.config.MinThroughput), pings.failed)
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package connection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+)
+
+func TestQualityWatchdog_LowThroughput(t *testing.T) {
+	watchdog := newQualityWatchdog(WatchdogConfig{MinThroughput: 1000, DegradedSamples: 2})
+	now := time.Now()
+	stats := connectionstate.Statistics{}
+	pings := keepAliveCounter{}
+
+	assert.Empty(t, watchdog.sample(stats, pings, now))
+
+	// idle connection is not degraded
+	now = now.Add(10 * time.Second)
+	assert.Empty(t, watchdog.sample(stats, pings, now))
+
+	// slow traffic with healthy pings is low demand
+	stats.BytesSent += 100
+	stats.BytesReceived += 400
+	pings.sent++
+	now = now.Add(10 * time.Second)
+	assert.Empty(t, watchdog.sample(stats, pings, now))
+
+	stats.BytesSent += 100
+	stats.BytesReceived += 400
+	pings.sent++
+	now = now.Add(10 * time.Second)
+	assert.Empty(t, watchdog.sample(stats, pings, now))
+
+	// slow traffic with failing pings has to be sustained before switching
+	stats.BytesReceived += 500
+	pings.sent++
+	pings.failed++
+	now = now.Add(10 * time.Second)
+	assert.Empty(t, watchdog.sample(stats, pings, now))
+
+	stats.BytesReceived += 500
+	pings.sent++
+	pings.failed++
+	now = now.Add(10 * time.Second)
+	assert.NotEmpty(t, watchdog.sample(stats, pings, now))
+
+	// degradation is counted from scratch after reporting
+	stats.BytesReceived += 500
+	pings.sent++
+	pings.failed++
+	now = now.Add(10 * time.Second)
+	assert.Empty(t, watchdog.sample(stats, pings, now))
+
+	stats.BytesReceived += 100000
+	pings.sent++
+	pings.failed++
+	now = now.Add(10 * time.Second)
+	assert.Empty(t, watchdog.sample(stats, pings, now))
+}
+
+func TestQualityWatchdog_Stalled(t *testing.T) {
+	watchdog := newQualityWatchdog(WatchdogConfig{DegradedSamples: 1})
+	now := time.Now()
+	stats := connectionstate.Statistics{}
+
+	assert.Empty(t, watchdog.sample(stats, keepAliveCounter{}, now))
+
+	stats.BytesSent += 100
+	stats.BytesReceived += 100
+	assert.Empty(t, watchdog.sample(stats, keepAliveCounter{}, now.Add(time.Second)))
+
+	stats.BytesSent += 100
+	assert.NotEmpty(t, watchdog.sample(stats, keepAliveCounter{}, now.Add(2*time.Second)))
+}
+
+func TestQualityWatchdog_ErrorRate(t *testing.T) {
+	watchdog := newQualityWatchdog(WatchdogConfig{MaxErrorRate: 0.5, DegradedSamples: 1})
+	now := time.Now()
+
+	assert.Empty(t, watchdog.sample(connectionstate.Statistics{}, keepAliveCounter{sent: 10, failed: 10}, now))
+	assert.Empty(t, watchdog.sample(connectionstate.Statistics{}, keepAliveCounter{sent: 14, failed: 11}, now.Add(time.Second)))
+	assert.NotEmpty(t, watchdog.sample(connectionstate.Statistics{}, keepAliveCounter{sent: 18, failed: 14}, now.Add(2*time.Second)))
+}
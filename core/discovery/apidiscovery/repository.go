@@ -52,20 +52,46 @@ func (a *apiRepository) Proposal(id market.ProposalID) (*market.ServiceProposal,
 }
 
 // Proposals returns proposals matching filter.
+// Proposals are fetched page by page, so that only matching ones are kept in memory.
 func (a *apiRepository) Proposals(filter *proposal.Filter) ([]market.ServiceProposal, error) {
-	proposals, err := a.discoveryAPI.QueryProposals(filter.ToAPIQuery())
+	filteredProposals := []market.ServiceProposal{}
+	err := a.StreamProposals(filter, func(p market.ServiceProposal) error {
+		filteredProposals = append(filteredProposals, p)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	return filteredProposals, nil
+}
+
+// ProposalsPage returns a page of proposals matching filter and the cursor of the next page.
+// Page may hold less than limit proposals as proposals not matching filter are dropped.
+func (a *apiRepository) ProposalsPage(filter *proposal.Filter, cursor string, limit int) ([]market.ServiceProposal, string, error) {
+	page, err := a.discoveryAPI.QueryProposalsPage(filter.ToAPIQuery(), cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
 	filteredProposals := []market.ServiceProposal{}
-	for _, p := range proposals {
+	for _, p := range page.Proposals {
 		if filter.Matches(p) {
 			filteredProposals = append(filteredProposals, p)
 		}
 	}
 
-	return filteredProposals, nil
+	return filteredProposals, page.NextCursor, nil
+}
+
+// StreamProposals passes proposals matching filter to the callback as pages arrive.
+func (a *apiRepository) StreamProposals(filter *proposal.Filter, fn func(market.ServiceProposal) error) error {
+	return a.discoveryAPI.StreamProposals(filter.ToAPIQuery(), mysterium.DefaultProposalsPageSize, func(p market.ServiceProposal) error {
+		if !filter.Matches(p) {
+			return nil
+		}
+		return fn(p)
+	})
 }
 
 // Countries returns number of proposals matching filter per country.
@@ -19,7 +19,6 @@ package brokerdiscovery
 
 import (
 	"github.com/mysteriumnetwork/node/communication"
-	"github.com/mysteriumnetwork/node/communication/nats"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 )
@@ -29,9 +28,9 @@ type registryBroker struct {
 }
 
 // NewRegistry create an instance of Broker registryBroker
-func NewRegistry(connection nats.Connection) *registryBroker {
+func NewRegistry(transport communication.Transport) *registryBroker {
 	return &registryBroker{
-		sender: nats.NewSender(connection, communication.NewCodecJSON()),
+		sender: communication.NewTransportSender(transport, communication.NewCodecJSON()),
 	}
 }
 
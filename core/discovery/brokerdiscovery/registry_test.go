@@ -34,14 +34,14 @@ var (
 )
 
 func Test_NewRegistry(t *testing.T) {
-	connection := nats.NewConnectionMock()
+	transport := nats.NewTransport(nats.NewConnectionMock())
 
 	assert.Equal(
 		t,
 		&registryBroker{
-			sender: nats.NewSender(connection, communication.NewCodecJSON()),
+			sender: communication.NewTransportSender(transport, communication.NewCodecJSON()),
 		},
-		NewRegistry(connection),
+		NewRegistry(transport),
 	)
 }
 
@@ -49,7 +49,7 @@ func Test_Registry_RegisterProposal(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	registry := NewRegistry(connection)
+	registry := NewRegistry(nats.NewTransport(connection))
 	err := registry.RegisterProposal(newProposal, &identity.SignerFake{})
 	assert.NoError(t, err)
 
@@ -66,7 +66,7 @@ func Test_Registry_UnregisterProposal(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	registry := NewRegistry(connection)
+	registry := NewRegistry(nats.NewTransport(connection))
 	err := registry.UnregisterProposal(newProposal, &identity.SignerFake{})
 	assert.NoError(t, err)
 
@@ -83,7 +83,7 @@ func Test_Registry_PingProposal(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	registry := NewRegistry(connection)
+	registry := NewRegistry(nats.NewTransport(connection))
 	err := registry.PingProposal(newProposal, &identity.SignerFake{})
 	assert.NoError(t, err)
 
@@ -22,7 +22,6 @@ import (
 	"time"
 
 	"github.com/mysteriumnetwork/node/communication"
-	"github.com/mysteriumnetwork/node/communication/nats"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/market"
 )
@@ -43,14 +42,14 @@ type Repository struct {
 
 // NewRepository constructs a new proposal repository (backed by the broker).
 func NewRepository(
-	connection nats.Connection,
+	transport communication.Transport,
 	storage *ProposalStorage,
 	proposalTimeoutInterval time.Duration,
 	proposalCheckInterval time.Duration,
 ) *Repository {
 	return &Repository{
 		storage:         storage,
-		receiver:        nats.NewReceiver(connection, communication.NewCodecJSON(), "*"),
+		receiver:        communication.NewTransportReceiver(transport, communication.NewCodecJSON()),
 		timeoutInterval: proposalTimeoutInterval,
 
 		stopChan:          make(chan struct{}),
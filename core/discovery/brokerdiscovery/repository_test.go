@@ -54,7 +54,7 @@ func Test_Subscriber_StartSyncsNewProposals(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	repo := NewRepository(connection, NewStorage(eventbus.New()), 500*time.Millisecond, 1*time.Second)
+	repo := NewRepository(nats.NewTransport(connection), NewStorage(eventbus.New()), 500*time.Millisecond, 1*time.Second)
 	err := repo.Start()
 	defer repo.Stop()
 	assert.NoError(t, err)
@@ -83,7 +83,7 @@ func Test_Subscriber_SkipUnsupportedProposal(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	repo := NewRepository(connection, NewStorage(eventbus.New()), 500*time.Millisecond, 10*time.Millisecond)
+	repo := NewRepository(nats.NewTransport(connection), NewStorage(eventbus.New()), 500*time.Millisecond, 10*time.Millisecond)
 	err := repo.Start()
 	defer repo.Stop()
 	assert.NoError(t, err)
@@ -101,7 +101,7 @@ func Test_Subscriber_StartSyncsIdleProposals(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	repo := NewRepository(connection, NewStorage(eventbus.New()), 10*time.Millisecond, 10*time.Millisecond)
+	repo := NewRepository(nats.NewTransport(connection), NewStorage(eventbus.New()), 10*time.Millisecond, 10*time.Millisecond)
 	err := repo.Start()
 	defer repo.Stop()
 	assert.NoError(t, err)
@@ -125,7 +125,7 @@ func Test_Subscriber_StartSyncsHealthyProposals(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	repo := NewRepository(connection, NewStorage(eventbus.New()), 100*time.Millisecond, 10*time.Millisecond)
+	repo := NewRepository(nats.NewTransport(connection), NewStorage(eventbus.New()), 100*time.Millisecond, 10*time.Millisecond)
 	err := repo.Start()
 	defer repo.Stop()
 	assert.NoError(t, err)
@@ -168,7 +168,7 @@ func Test_Subscriber_StartSyncsStoppedProposals(t *testing.T) {
 	connection := nats.StartConnectionMock()
 	defer connection.Close()
 
-	repo := NewRepository(connection, NewStorage(eventbus.New()), 500*time.Millisecond, 10*time.Millisecond)
+	repo := NewRepository(nats.NewTransport(connection), NewStorage(eventbus.New()), 500*time.Millisecond, 10*time.Millisecond)
 	repo.storage.AddProposal(proposalFirst(), proposalSecond())
 	err := repo.Start()
 	defer repo.Stop()
@@ -0,0 +1,248 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/power"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/market/mysterium"
+)
+
+// DefaultCacheRefreshInterval is how often cached proposals are refreshed when no interval is given.
+const DefaultCacheRefreshInterval = time.Minute
+
+// ProposalsSource fetches proposals unless they did not change since the given validators.
+type ProposalsSource interface {
+	QueryProposalsSince(query mysterium.ProposalsQuery, etag, lastModified string) (mysterium.ProposalsSnapshot, error)
+}
+
+type proposalsCache struct {
+	Proposals    []market.ServiceProposal `json:"proposals"`
+	ETag         string                   `json:"etag"`
+	LastModified string                   `json:"last_modified"`
+	FetchedAt    time.Time                `json:"fetched_at"`
+}
+
+// CachedRepository serves proposals from the delegate repository and falls back to the latest
+// proposals fetched from the source while the delegate is unreachable.
+// Cached proposals are persisted, so they survive restarts, and expire after TTL.
+type CachedRepository struct {
+	delegate proposal.Repository
+	source   ProposalsSource
+	ttl      time.Duration
+	file     string
+	now      func() time.Time
+
+	mu    sync.RWMutex
+	cache proposalsCache
+
+	power power.Mode
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewCachedRepository returns a repository caching proposals in the given directory.
+func NewCachedRepository(delegate proposal.Repository, source ProposalsSource, ttl time.Duration, dir string) *CachedRepository {
+	r := &CachedRepository{
+		delegate: delegate,
+		source:   source,
+		ttl:      ttl,
+		file:     filepath.Join(dir, "proposals-cache.json"),
+		now:      time.Now,
+		stop:     make(chan struct{}),
+	}
+	if err := r.read(); err != nil {
+		log.Warn().Err(err).Msg("Ignoring proposals cache")
+	}
+	return r
+}
+
+// Subscribe pauses refreshing cached proposals while the node runs in low-power mode.
+func (r *CachedRepository) Subscribe(bus eventbus.Subscriber) error {
+	return r.power.Subscribe(bus)
+}
+
+// Start refreshes cached proposals with the given interval until stopped.
+func (r *CachedRepository) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCacheRefreshInterval
+	}
+
+	go func() {
+		for {
+			if r.power.LowPower() {
+				log.Trace().Msg("Low-power mode, skipping proposals cache refresh")
+			} else if err := r.Refresh(); err != nil {
+				log.Warn().Err(err).Msg("Failed to refresh proposals cache")
+			}
+
+			select {
+			case <-r.stop:
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+// Stop stops refreshing cached proposals.
+func (r *CachedRepository) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// Refresh fetches proposals if they changed since the last refresh.
+func (r *CachedRepository) Refresh() error {
+	r.mu.RLock()
+	etag, lastModified := r.cache.ETag, r.cache.LastModified
+	r.mu.RUnlock()
+
+	snapshot, err := r.source.QueryProposalsSince(mysterium.ProposalsQuery{AccessPolicy: "all"}, etag, lastModified)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !snapshot.NotModified {
+		r.cache.Proposals = snapshot.Proposals
+	}
+	r.cache.ETag = snapshot.ETag
+	r.cache.LastModified = snapshot.LastModified
+	r.cache.FetchedAt = r.now()
+	return r.write()
+}
+
+// Proposal returns a single proposal by its ID.
+func (r *CachedRepository) Proposal(id market.ProposalID) (*market.ServiceProposal, error) {
+	p, err := r.delegate.Proposal(id)
+	if err == nil {
+		return p, nil
+	}
+
+	cached, ok := r.cached()
+	if !ok {
+		return nil, err
+	}
+	for _, p := range cached {
+		if p.UniqueID() == id {
+			log.Debug().Err(err).Msgf("Serving cached proposal %+v", id)
+			return &p, nil
+		}
+	}
+	return nil, err
+}
+
+// Proposals returns proposals matching the filter.
+func (r *CachedRepository) Proposals(filter *proposal.Filter) ([]market.ServiceProposal, error) {
+	proposals, err := r.delegate.Proposals(filter)
+	if err == nil {
+		return proposals, nil
+	}
+
+	cached, ok := r.cached()
+	if !ok {
+		return nil, err
+	}
+	log.Debug().Err(err).Msg("Serving cached proposals")
+	return filterProposals(cached, filter), nil
+}
+
+// Countries returns number of proposals matching filter per country.
+func (r *CachedRepository) Countries(filter *proposal.Filter) (map[string]int, error) {
+	countries, err := r.delegate.Countries(filter)
+	if err == nil {
+		return countries, nil
+	}
+
+	cached, ok := r.cached()
+	if !ok {
+		return nil, err
+	}
+	countries = make(map[string]int)
+	for _, p := range filterProposals(cached, filter) {
+		countries[p.Location.Country]++
+	}
+	return countries, nil
+}
+
+func (r *CachedRepository) cached() ([]market.ServiceProposal, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.cache.FetchedAt.IsZero() || r.now().Sub(r.cache.FetchedAt) > r.ttl {
+		return nil, false
+	}
+	return r.cache.Proposals, true
+}
+
+func filterProposals(proposals []market.ServiceProposal, filter *proposal.Filter) []market.ServiceProposal {
+	// Discovery API returns only public proposals unless an access policy is requested.
+	publicOnly := filter == nil || filter.AccessPolicy == "" && filter.AccessPolicySource == ""
+
+	filtered := []market.ServiceProposal{}
+	for _, p := range proposals {
+		if publicOnly && p.AccessPolicies != nil {
+			continue
+		}
+		if filter == nil || filter.Matches(p) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+func (r *CachedRepository) read() error {
+	data, err := os.ReadFile(r.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read proposals cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &r.cache); err != nil {
+		return fmt.Errorf("could not parse proposals cache: %w", err)
+	}
+	return nil
+}
+
+func (r *CachedRepository) write() error {
+	data, err := json.Marshal(r.cache)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(r.file, data, 0600); err != nil {
+		return fmt.Errorf("could not write proposals cache: %w", err)
+	}
+	return nil
+}
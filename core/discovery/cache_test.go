@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package discovery
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/market/mysterium"
+)
+
+type mockProposalsSource struct {
+	snapshot           mysterium.ProposalsSnapshot
+	err                error
+	etag, lastModified string
+}
+
+func (m *mockProposalsSource) QueryProposalsSince(_ mysterium.ProposalsQuery, etag, lastModified string) (mysterium.ProposalsSnapshot, error) {
+	m.etag, m.lastModified = etag, lastModified
+	return m.snapshot, m.err
+}
+
+func TestCachedRepository_ServesCachedProposalsWhenDelegateFails(t *testing.T) {
+	dir := t.TempDir()
+	delegate := &mockRepository{errToReturn: errors.New("discovery unreachable")}
+	source := &mockProposalsSource{snapshot: mysterium.ProposalsSnapshot{
+		Proposals: []market.ServiceProposal{mockProposal},
+		ETag:      `"v1"`,
+	}}
+
+	repo := NewCachedRepository(delegate, source, time.Hour, dir)
+	_, err := repo.Proposals(&proposal.Filter{})
+	assert.EqualError(t, err, "discovery unreachable")
+
+	assert.NoError(t, repo.Refresh())
+
+	proposals, err := repo.Proposals(&proposal.Filter{ServiceType: mockProposal.ServiceType})
+	assert.NoError(t, err)
+	assert.Len(t, proposals, 1)
+	assert.Equal(t, mockProposal.ProviderID, proposals[0].ProviderID)
+
+	proposals, err = repo.Proposals(&proposal.Filter{ServiceType: "other"})
+	assert.NoError(t, err)
+	assert.Len(t, proposals, 0)
+
+	p, err := repo.Proposal(mockProposal.UniqueID())
+	assert.NoError(t, err)
+	assert.Equal(t, mockProposal.ID, p.ID)
+
+	// Cache is restored after restart and refreshed incrementally.
+	source.snapshot = mysterium.ProposalsSnapshot{ETag: `"v1"`, NotModified: true}
+	repo = NewCachedRepository(delegate, source, time.Hour, dir)
+	assert.NoError(t, repo.Refresh())
+	assert.Equal(t, `"v1"`, source.etag)
+
+	proposals, err = repo.Proposals(nil)
+	assert.NoError(t, err)
+	assert.Len(t, proposals, 1)
+
+	// Expired cache is not served.
+	repo.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = repo.Proposals(nil)
+	assert.EqualError(t, err, "discovery unreachable")
+}
+
+func TestCachedRepository_PrefersDelegate(t *testing.T) {
+	delegate := &mockRepository{proposalsToReturn: []market.ServiceProposal{mockProposal}}
+	source := &mockProposalsSource{err: errors.New("discovery unreachable")}
+
+	repo := NewCachedRepository(delegate, source, time.Hour, t.TempDir())
+	assert.Error(t, repo.Refresh())
+
+	proposals, err := repo.Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []market.ServiceProposal{mockProposal}, proposals)
+}
@@ -18,6 +18,7 @@
 package discovery
 
 import (
+	"bytes"
 	"sync"
 	"time"
 
@@ -46,6 +47,13 @@ const (
 	StatusUndefined
 )
 
+const (
+	// DefaultProposalCheckInterval is how often the proposal is checked for changes.
+	DefaultProposalCheckInterval = 10 * time.Second
+	// DefaultProposalChangeDebounce is how long a proposal change is held back to batch subsequent changes.
+	DefaultProposalChangeDebounce = 30 * time.Second
+)
+
 // Discovery structure holds discovery service state
 type Discovery struct {
 	identityRegistry registry.IdentityRegistry
@@ -63,6 +71,12 @@ type Discovery struct {
 	stop                        chan struct{}
 	once                        sync.Once
 
+	// Changes of location and NAT status are detected by comparing the proposal with the last announced one.
+	checkInterval  time.Duration
+	changeDebounce time.Duration
+	announced      []byte
+	announcedMu    sync.Mutex
+
 	mu sync.RWMutex
 }
 
@@ -84,6 +98,8 @@ func NewService(
 		status:                      StatusUndefined,
 		proposalAnnouncementStopped: &sync.WaitGroup{},
 		stop:                        make(chan struct{}),
+		checkInterval:               DefaultProposalCheckInterval,
+		changeDebounce:              DefaultProposalChangeDebounce,
 	}
 }
 
@@ -102,6 +118,10 @@ func (d *Discovery) Start(ownIdentity identity.Identity, proposal func() market.
 	go d.checkRegistration()
 
 	go d.mainDiscoveryLoop()
+
+	if d.checkInterval > 0 {
+		go d.watchProposalChanges()
+	}
 }
 
 // Wait wait for proposal announcements to stop / unregister
@@ -182,8 +202,16 @@ func (d *Discovery) registerIdentity() {
 	d.changeStatus(WaitingForRegistration)
 }
 
+func (d *Discovery) signedProposal() market.ServiceProposal {
+	proposal, err := SignProposal(d.proposal(), d.signer)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to sign proposal")
+	}
+	return proposal
+}
+
 func (d *Discovery) registerProposal() {
-	proposal := d.proposal()
+	proposal := d.signedProposal()
 	err := d.proposalRegistry.RegisterProposal(proposal, d.signer)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to register proposal, retrying after 1 min")
@@ -195,6 +223,7 @@ func (d *Discovery) registerProposal() {
 			return
 		}
 	}
+	d.setAnnounced(proposal)
 	d.eventBus.Publish(AppTopicProposalAnnounce, proposal)
 	d.changeStatus(PingProposal)
 }
@@ -204,18 +233,81 @@ func (d *Discovery) pingProposal() {
 	case <-d.stop:
 		return
 	case <-time.After(d.proposalPingTTL):
-		proposal := d.proposal()
+		proposal := d.signedProposal()
 		err := d.proposalRegistry.PingProposal(proposal, d.signer)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to ping proposal")
+		} else {
+			d.setAnnounced(proposal)
 		}
 		d.eventBus.Publish(AppTopicProposalAnnounce, proposal)
 		d.changeStatus(PingProposal)
 	}
 }
 
-func (d *Discovery) unregisterProposal() {
+// watchProposalChanges republishes the proposal once it differs from the announced one for the debounce period,
+// so that consumers see location and NAT changes without waiting for the next ping.
+func (d *Discovery) watchProposalChanges() {
+	var changedAt time.Time
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-time.After(d.checkInterval):
+		}
+
+		d.mu.RLock()
+		announcing := d.status == PingProposal
+		d.mu.RUnlock()
+		if !announcing || !d.proposalChanged() {
+			changedAt = time.Time{}
+			continue
+		}
+
+		if changedAt.IsZero() {
+			log.Info().Msg("Proposal changed, republishing it after debounce")
+			changedAt = time.Now()
+		}
+		if time.Since(changedAt) < d.changeDebounce {
+			continue
+		}
+
+		proposal := d.signedProposal()
+		if err := d.proposalRegistry.RegisterProposal(proposal, d.signer); err != nil {
+			log.Error().Err(err).Msg("Failed to republish changed proposal")
+			continue
+		}
+		changedAt = time.Time{}
+		d.setAnnounced(proposal)
+		d.eventBus.Publish(AppTopicProposalAnnounce, proposal)
+	}
+}
+
+func (d *Discovery) proposalChanged() bool {
 	proposal := d.proposal()
+	current, err := proposal.SignedMessage()
+	if err != nil {
+		return false
+	}
+
+	d.announcedMu.Lock()
+	defer d.announcedMu.Unlock()
+	return !bytes.Equal(current, d.announced)
+}
+
+func (d *Discovery) setAnnounced(proposal market.ServiceProposal) {
+	announced, err := proposal.SignedMessage()
+	if err != nil {
+		return
+	}
+
+	d.announcedMu.Lock()
+	defer d.announcedMu.Unlock()
+	d.announced = announced
+}
+
+func (d *Discovery) unregisterProposal() {
+	proposal := d.signedProposal()
 	err := d.proposalRegistry.UnregisterProposal(proposal, d.signer)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to unregister proposal: ")
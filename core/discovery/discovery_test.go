@@ -114,6 +114,35 @@ func TestStartStopUnregisterProposal(t *testing.T) {
 	assert.Equal(t, ProposalUnregistered, actualStatus)
 }
 
+func TestProposalRepublishedOnChange(t *testing.T) {
+	d := discoveryWithMockedDependencies()
+	d.identityRegistry = &identityregistry.FakeRegistry{RegistrationStatus: identityregistry.Registered}
+	registry := &countingProposalRegistry{}
+	d.proposalRegistry = registry
+	d.checkInterval = 10 * time.Millisecond
+	d.changeDebounce = 50 * time.Millisecond
+
+	var mu sync.Mutex
+	current := serviceProposal
+	d.Start(providerID, func() market.ServiceProposal {
+		mu.Lock()
+		defer mu.Unlock()
+		return current
+	})
+	defer d.Stop()
+
+	observeStatus(d, PingProposal)
+	assert.Equal(t, 1, registry.registered())
+
+	mu.Lock()
+	current.Location = market.Location{Country: "LT"}
+	mu.Unlock()
+
+	assert.Eventually(t, func() bool { return registry.registered() == 2 }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 2, registry.registered())
+}
+
 func observeStatus(d *Discovery, status Status) Status {
 	for {
 		d.mu.RLock()
@@ -141,3 +170,22 @@ func (mockedProposalRegistry) UnregisterProposal(proposal market.ServiceProposal
 }
 
 var _ ProposalRegistry = &mockedProposalRegistry{}
+
+type countingProposalRegistry struct {
+	mockedProposalRegistry
+	mu            sync.Mutex
+	registrations int
+}
+
+func (r *countingProposalRegistry) RegisterProposal(proposal market.ServiceProposal, signer identity.Signer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registrations++
+	return nil
+}
+
+func (r *countingProposalRegistry) registered() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.registrations
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+const (
+	// JSONFileName is the name of exported JSON feed.
+	JSONFileName = "proposals.json"
+	// AtomFileName is the name of exported Atom feed.
+	AtomFileName = "proposals.atom"
+
+	atomNamespace = "http://www.w3.org/2005/Atom"
+	feedID        = "urn:mysterium:proposals"
+)
+
+// Document is the JSON feed of proposals. Proposals use the same format as Tequilapi proposals endpoint.
+type Document struct {
+	GeneratedAt time.Time              `json:"generated_at"`
+	Count       int                    `json:"count"`
+	Proposals   []contract.ProposalDTO `json:"proposals"`
+}
+
+// NewDocument creates JSON feed document of the given proposals.
+func NewDocument(proposals []proposal.PricedServiceProposal, generatedAt time.Time) Document {
+	doc := Document{
+		GeneratedAt: generatedAt.UTC(),
+		Count:       len(proposals),
+		Proposals:   make([]contract.ProposalDTO, 0, len(proposals)),
+	}
+	for _, p := range proposals {
+		doc.Proposals = append(doc.Proposals, contract.NewProposalDTO(p))
+	}
+	return doc
+}
+
+// JSON encodes the document as indented JSON.
+func (d Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Summary string      `xml:"summary"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Atom encodes the document as Atom feed with an entry per proposal. Entry content holds the JSON proposal.
+func (d Document) Atom() ([]byte, error) {
+	updated := d.GeneratedAt.Format(time.RFC3339)
+	feed := atomFeed{
+		XMLNS:   atomNamespace,
+		ID:      feedID,
+		Title:   "Mysterium Network proposals",
+		Updated: updated,
+	}
+	for _, p := range d.Proposals {
+		content, err := json.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:      fmt.Sprintf("%s:%s:%s", feedID, p.ProviderID, p.ServiceType),
+			Title:   fmt.Sprintf("%s by %s", p.ServiceType, p.ProviderID),
+			Updated: updated,
+			Summary: summary(p),
+			Content: atomContent{Type: "application/json", Body: string(content)},
+		})
+	}
+
+	out, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func summary(p contract.ProposalDTO) string {
+	return fmt.Sprintf("Country: %s, IP type: %s, quality: %.2f", p.Location.Country, p.Location.IPType, p.Quality.Quality)
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package feed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+)
+
+type proposalRepository interface {
+	Proposals(filter *proposal.Filter) ([]proposal.PricedServiceProposal, error)
+}
+
+// Filter selects proposals included in the feed.
+type Filter struct {
+	ServiceType string
+	Country     string
+	QualityMin  float32
+}
+
+// Exporter periodically exports proposals to static JSON and Atom feeds.
+type Exporter struct {
+	repository proposalRepository
+	filter     Filter
+	sinks      []Sink
+	interval   time.Duration
+	now        func() time.Time
+
+	once sync.Once
+	stop chan struct{}
+}
+
+// NewExporter returns a new proposals feed exporter.
+func NewExporter(repository proposalRepository, filter Filter, sinks []Sink, interval time.Duration) *Exporter {
+	return &Exporter{
+		repository: repository,
+		filter:     filter,
+		sinks:      sinks,
+		interval:   interval,
+		now:        time.Now,
+		stop:       make(chan struct{}),
+	}
+}
+
+// Export fetches filtered proposals and writes feeds to all sinks.
+func (e *Exporter) Export() error {
+	proposals, err := e.repository.Proposals(&proposal.Filter{
+		ServiceType:             e.filter.ServiceType,
+		LocationCountry:         e.filter.Country,
+		QualityMin:              e.filter.QualityMin,
+		ExcludeUnsupported:      true,
+		IncludeMonitoringFailed: false,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not fetch proposals")
+	}
+
+	doc := NewDocument(proposals, e.now())
+	jsonFeed, err := doc.JSON()
+	if err != nil {
+		return errors.Wrap(err, "could not encode JSON feed")
+	}
+	atomFeed, err := doc.Atom()
+	if err != nil {
+		return errors.Wrap(err, "could not encode Atom feed")
+	}
+
+	for _, sink := range e.sinks {
+		if err := sink.Put(JSONFileName, "application/json", jsonFeed); err != nil {
+			return errors.Wrapf(err, "could not export JSON feed to %s", sink)
+		}
+		if err := sink.Put(AtomFileName, "application/atom+xml", atomFeed); err != nil {
+			return errors.Wrapf(err, "could not export Atom feed to %s", sink)
+		}
+	}
+
+	log.Debug().Msgf("Exported %d proposals to %d feed targets", doc.Count, len(e.sinks))
+	return nil
+}
+
+// Start exports feeds immediately and then on every interval until stopped.
+func (e *Exporter) Start() {
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := e.Export(); err != nil {
+				log.Error().Err(err).Msg("Failed to export proposals feed")
+			}
+
+			select {
+			case <-e.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops periodic exports.
+func (e *Exporter) Stop() {
+	e.once.Do(func() {
+		close(e.stop)
+	})
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+var feedProposal = proposal.PricedServiceProposal{
+	ServiceProposal: market.ServiceProposal{
+		ProviderID:  "0x1",
+		ServiceType: "wireguard",
+		Location:    market.Location{Country: "LT", IPType: "residential"},
+		Quality:     market.Quality{Quality: 2.5},
+	},
+	Price: market.Price{PricePerHour: big.NewInt(10), PricePerGiB: big.NewInt(20)},
+}
+
+type mockRepository struct {
+	filter *proposal.Filter
+}
+
+func (mr *mockRepository) Proposals(filter *proposal.Filter) ([]proposal.PricedServiceProposal, error) {
+	mr.filter = filter
+	return []proposal.PricedServiceProposal{feedProposal}, nil
+}
+
+func TestDocument_JSON(t *testing.T) {
+	doc := NewDocument([]proposal.PricedServiceProposal{feedProposal}, time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	data, err := doc.JSON()
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "2022-01-02T03:04:05Z", decoded["generated_at"])
+	assert.Equal(t, float64(1), decoded["count"])
+	proposals := decoded["proposals"].([]interface{})
+	require.Len(t, proposals, 1)
+	assert.Equal(t, "0x1", proposals[0].(map[string]interface{})["provider_id"])
+}
+
+func TestDocument_Atom(t *testing.T) {
+	doc := NewDocument([]proposal.PricedServiceProposal{feedProposal}, time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	data, err := doc.Atom()
+	require.NoError(t, err)
+
+	var feed atomFeed
+	require.NoError(t, xml.Unmarshal(data, &feed))
+	assert.Equal(t, feedID, feed.ID)
+	assert.Equal(t, "2022-01-02T03:04:05Z", feed.Updated)
+	require.Len(t, feed.Entries, 1)
+	assert.Equal(t, "urn:mysterium:proposals:0x1:wireguard", feed.Entries[0].ID)
+	assert.Equal(t, "Country: LT, IP type: residential, quality: 2.50", feed.Entries[0].Summary)
+	assert.Equal(t, "application/json", feed.Entries[0].Content.Type)
+}
+
+func TestNewSinks_LocalDirectories(t *testing.T) {
+	sinks, err := NewSinks([]string{"/tmp/a", " ", "b"}, S3Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []Sink{NewFileSink("/tmp/a"), NewFileSink("b")}, sinks)
+}
+
+func TestExporter_Export(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proposals-feed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	repository := &mockRepository{}
+	exporter := NewExporter(repository, Filter{ServiceType: "wireguard", Country: "LT", QualityMin: 1}, []Sink{NewFileSink(dir)}, time.Minute)
+
+	require.NoError(t, exporter.Export())
+
+	assert.Equal(t, "wireguard", repository.filter.ServiceType)
+	assert.Equal(t, "LT", repository.filter.LocationCountry)
+	assert.Equal(t, float32(1), repository.filter.QualityMin)
+
+	for _, name := range []string{JSONFileName, AtomFileName} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size())
+	}
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2, "temporary files must be cleaned up")
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package feed
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pkg/errors"
+)
+
+const s3Scheme = "s3://"
+
+// Sink stores exported feed files.
+type Sink interface {
+	Put(name, contentType string, data []byte) error
+	String() string
+}
+
+// S3Options configures S3 compatible sinks.
+type S3Options struct {
+	// Endpoint overrides AWS endpoint, for S3 compatible storages. Empty value uses AWS.
+	Endpoint string
+	Region   string
+}
+
+// NewSinks creates sinks from targets. Targets prefixed with s3:// are uploaded
+// to S3 bucket, other targets are treated as local directories.
+func NewSinks(targets []string, s3Opts S3Options) ([]Sink, error) {
+	var sinks []Sink
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(target, s3Scheme) {
+			sinks = append(sinks, NewFileSink(target))
+			continue
+		}
+
+		sink, err := NewS3Sink(strings.TrimPrefix(target, s3Scheme), s3Opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not create sink for %q", target)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// FileSink writes feed files to a local directory.
+type FileSink struct {
+	dir string
+}
+
+// NewFileSink returns a new FileSink writing to the given directory.
+func NewFileSink(dir string) *FileSink {
+	return &FileSink{dir: dir}
+}
+
+// Put writes the file atomically, so that readers never observe a partially written feed.
+func (fs *FileSink) Put(name, _ string, data []byte) error {
+	if err := os.MkdirAll(fs.dir, 0755); err != nil {
+		return errors.Wrap(err, "could not create feed directory")
+	}
+
+	tmp, err := ioutil.TempFile(fs.dir, "."+name+".*")
+	if err != nil {
+		return errors.Wrap(err, "could not create temporary feed file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "could not write feed file")
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "could not set feed file permissions")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "could not close feed file")
+	}
+	return os.Rename(tmp.Name(), filepath.Join(fs.dir, name))
+}
+
+func (fs *FileSink) String() string {
+	return fs.dir
+}
+
+// S3Sink uploads feed files to S3 bucket.
+type S3Sink struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Sink returns a new S3Sink for the "bucket/prefix" location.
+// Credentials are resolved from the default AWS credential chain.
+func NewS3Sink(location string, opts S3Options) (*S3Sink, error) {
+	bucket, prefix := location, ""
+	if i := strings.Index(location, "/"); i >= 0 {
+		bucket, prefix = location[:i], strings.Trim(location[i+1:], "/")
+	}
+	if bucket == "" {
+		return nil, errors.New("bucket is not specified")
+	}
+
+	loadOpts := []func(*config.LoadOptions) error{config.WithRegion(opts.Region)}
+	if opts.Endpoint != "" {
+		resolver := aws.EndpointResolverFunc(func(service, region string) (aws.Endpoint, error) {
+			return aws.Endpoint{
+				URL:           opts.Endpoint,
+				SigningRegion: region,
+			}, nil
+		})
+		loadOpts = append(loadOpts, config.WithEndpointResolver(resolver))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), loadOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load AWS config")
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.UsePathStyle = opts.Endpoint != ""
+	})
+	return &S3Sink{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+// Put uploads the file to the bucket.
+func (ss *S3Sink) Put(name, contentType string, data []byte) error {
+	key := name
+	if ss.prefix != "" {
+		key = ss.prefix + "/" + name
+	}
+
+	_, err := ss.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:       aws.String(ss.bucket),
+		Key:          aws.String(key),
+		Body:         bytes.NewReader(data),
+		ContentType:  aws.String(contentType),
+		CacheControl: aws.String("no-cache"),
+	})
+	return errors.Wrapf(err, "could not upload %s", key)
+}
+
+func (ss *S3Sink) String() string {
+	return s3Scheme + ss.bucket + "/" + ss.prefix
+}
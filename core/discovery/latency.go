@@ -0,0 +1,160 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package discovery
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/p2p"
+)
+
+const (
+	// DefaultLatencyTimeout is a time given for a single provider to reply to ping.
+	DefaultLatencyTimeout = 3 * time.Second
+	// DefaultLatencyCandidates is a maximum number of proposals pinged before connecting.
+	DefaultLatencyCandidates = 10
+	// DefaultLatencyConcurrency is a number of providers pinged in parallel.
+	DefaultLatencyConcurrency = 5
+)
+
+// LatencyConfig holds latency measurement settings.
+type LatencyConfig struct {
+	Timeout     time.Duration
+	Concurrency int
+}
+
+// DefaultLatencyConfig returns default latency measurement settings.
+func DefaultLatencyConfig() LatencyConfig {
+	return LatencyConfig{
+		Timeout:     DefaultLatencyTimeout,
+		Concurrency: DefaultLatencyConcurrency,
+	}
+}
+
+// LatencyMeasurer pings providers in parallel before connecting and attaches measured round trip time to proposals.
+type LatencyMeasurer struct {
+	pinger p2p.Pinger
+	config LatencyConfig
+}
+
+// NewLatencyMeasurer returns a new instance of LatencyMeasurer.
+func NewLatencyMeasurer(pinger p2p.Pinger, config LatencyConfig) *LatencyMeasurer {
+	return &LatencyMeasurer{
+		pinger: pinger,
+		config: config,
+	}
+}
+
+// Measure pings given proposals and returns them with measured round trip time set.
+// Proposals which failed to respond are returned with zero MeasuredRTT.
+func (lm *LatencyMeasurer) Measure(ctx context.Context, proposals []proposal.PricedServiceProposal) []proposal.PricedServiceProposal {
+	result := make([]proposal.PricedServiceProposal, len(proposals))
+	copy(result, proposals)
+
+	concurrency := lm.config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i := range result {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(p *proposal.PricedServiceProposal) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			rtt, err := lm.ping(ctx, *p)
+			if err != nil {
+				log.Debug().Err(err).Msgf("Could not measure latency to provider %s", p.ProviderID)
+				return
+			}
+			p.MeasuredRTT = rtt
+		}(&result[i])
+	}
+	wg.Wait()
+
+	return result
+}
+
+func (lm *LatencyMeasurer) ping(ctx context.Context, p proposal.PricedServiceProposal) (time.Duration, error) {
+	contactDef, err := p2p.ParseContact(p.Contacts)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lm.config.Timeout)
+	defer cancel()
+
+	return lm.pinger.Ping(ctx, identity.FromAddress(p.ProviderID), p.ServiceType, contactDef)
+}
+
+type latencyMeasurer interface {
+	Measure(ctx context.Context, proposals []proposal.PricedServiceProposal) []proposal.PricedServiceProposal
+}
+
+type proposalLister interface {
+	Proposals(filter *proposal.Filter) ([]proposal.PricedServiceProposal, error)
+}
+
+// LatencyMeasuringRepository measures latency to the best rated proposals returned by the underlying repository.
+type LatencyMeasuringRepository struct {
+	ctx        context.Context
+	repo       proposalLister
+	measurer   latencyMeasurer
+	candidates int
+}
+
+// NewLatencyMeasuringRepository returns a new instance of LatencyMeasuringRepository.
+// Measurements are cancelled together with the given context.
+func NewLatencyMeasuringRepository(ctx context.Context, repo proposalLister, measurer latencyMeasurer, candidates int) *LatencyMeasuringRepository {
+	return &LatencyMeasuringRepository{
+		ctx:        ctx,
+		repo:       repo,
+		measurer:   measurer,
+		candidates: candidates,
+	}
+}
+
+// Proposals returns proposals matching the filter with measured round trip time attached.
+// Only a limited number of candidates is pinged, the rest are returned without measured latency.
+func (r *LatencyMeasuringRepository) Proposals(filter *proposal.Filter) ([]proposal.PricedServiceProposal, error) {
+	proposals, err := r.repo.Proposals(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	proposals = proposal.SortByQuality(proposals)
+
+	n := len(proposals)
+	if r.candidates > 0 && n > r.candidates {
+		n = r.candidates
+	}
+
+	measured := r.measurer.Measure(r.ctx, proposals[:n])
+	return append(measured, proposals[n:]...), nil
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/p2p"
+)
+
+type mockPinger struct {
+	rtts map[string]time.Duration
+}
+
+func (mp *mockPinger) Ping(_ context.Context, providerID identity.Identity, _ string, _ p2p.ContactDefinition) (time.Duration, error) {
+	rtt, ok := mp.rtts[providerID.Address]
+	if !ok {
+		return 0, errors.New("timeout")
+	}
+	return rtt, nil
+}
+
+type mockProposalLister struct {
+	proposals []proposal.PricedServiceProposal
+}
+
+func (mpl *mockProposalLister) Proposals(_ *proposal.Filter) ([]proposal.PricedServiceProposal, error) {
+	return mpl.proposals, nil
+}
+
+func latencyProposal(providerID string, quality float64) proposal.PricedServiceProposal {
+	return proposal.PricedServiceProposal{
+		ServiceProposal: market.ServiceProposal{
+			ProviderID:  providerID,
+			ServiceType: "wireguard",
+			Contacts: market.ContactList{
+				{Type: p2p.ContactTypeV1, Definition: p2p.ContactDefinition{BrokerAddresses: []string{"nats://broker"}}},
+			},
+			Quality: market.Quality{Quality: quality},
+		},
+	}
+}
+
+func TestLatencyMeasurer_Measure(t *testing.T) {
+	pinger := &mockPinger{rtts: map[string]time.Duration{
+		"0x1": 30 * time.Millisecond,
+		"0x2": 10 * time.Millisecond,
+	}}
+	measurer := NewLatencyMeasurer(pinger, DefaultLatencyConfig())
+
+	noContact := latencyProposal("0x4", 1)
+	noContact.Contacts = nil
+
+	result := measurer.Measure(context.Background(), []proposal.PricedServiceProposal{
+		latencyProposal("0x1", 1),
+		latencyProposal("0x2", 1),
+		latencyProposal("0x3", 1),
+		noContact,
+	})
+
+	assert.Len(t, result, 4)
+	assert.Equal(t, 30*time.Millisecond, result[0].MeasuredRTT)
+	assert.Equal(t, 10*time.Millisecond, result[1].MeasuredRTT)
+	assert.Zero(t, result[2].MeasuredRTT)
+	assert.Zero(t, result[3].MeasuredRTT)
+
+	sorted := proposal.SortByMeasuredLatency(result)
+	assert.Equal(t, "0x2", sorted[0].ProviderID)
+	assert.Equal(t, "0x1", sorted[1].ProviderID)
+}
+
+func TestLatencyMeasuringRepository_MeasuresOnlyBestCandidates(t *testing.T) {
+	pinger := &mockPinger{rtts: map[string]time.Duration{
+		"0x1": 30 * time.Millisecond,
+		"0x2": 10 * time.Millisecond,
+		"0x3": 20 * time.Millisecond,
+	}}
+	lister := &mockProposalLister{proposals: []proposal.PricedServiceProposal{
+		latencyProposal("0x1", 3),
+		latencyProposal("0x2", 1),
+		latencyProposal("0x3", 2),
+	}}
+	repo := NewLatencyMeasuringRepository(context.Background(), lister, NewLatencyMeasurer(pinger, DefaultLatencyConfig()), 2)
+
+	result, err := repo.Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+
+	rtts := make(map[string]time.Duration)
+	for _, p := range result {
+		rtts[p.ProviderID] = p.MeasuredRTT
+	}
+	assert.Equal(t, 30*time.Millisecond, rtts["0x1"])
+	assert.Equal(t, 20*time.Millisecond, rtts["0x3"])
+	assert.Zero(t, rtts["0x2"])
+}
@@ -77,6 +77,11 @@ func (pspr *PricedServiceProposalRepository) Proposals(filter *proposal.Filter)
 		priced = preset.Filter(priced)
 	}
 
+	if filter != nil {
+		priced = filterByPrice(priced, filter)
+		proposal.SortByKeys(priced, filter.SortBy)
+	}
+
 	return priced, nil
 }
 
@@ -115,3 +120,17 @@ func (pspr *PricedServiceProposalRepository) toPricedProposal(in market.ServiceP
 		Price:           price,
 	}, nil
 }
+
+func filterByPrice(proposals []proposal.PricedServiceProposal, filter *proposal.Filter) []proposal.PricedServiceProposal {
+	if filter.PricePerHourMax == nil && filter.PricePerGiBMax == nil {
+		return proposals
+	}
+
+	res := make([]proposal.PricedServiceProposal, 0, len(proposals))
+	for _, p := range proposals {
+		if filter.MatchesPrice(p.Price) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
@@ -18,6 +18,7 @@
 package proposal
 
 import (
+	"math/big"
 	"sync"
 
 	"github.com/mysteriumnetwork/node/core/discovery/reducer"
@@ -41,6 +42,10 @@ type Filter struct {
 	ExcludeUnsupported                 bool
 	IncludeMonitoringFailed            bool
 	NATCompatibility                   nat.NATType
+	AddressFamilies                    []string
+	UptimeMin                          float64
+	PricePerHourMax, PricePerGiBMax    *big.Int
+	SortBy                             []SortKey
 	condition                          reducer.AndCondition
 	buildOnce                          sync.Once
 }
@@ -76,6 +81,15 @@ func (filter *Filter) Build() {
 				conditions = append(conditions, reducer.AccessPolicy(filter.AccessPolicy, filter.AccessPolicySource))
 			}
 		}
+		if len(filter.AddressFamilies) > 0 {
+			conditions = append(conditions, reducer.AddressFamily(filter.AddressFamilies))
+		}
+		if filter.BandwidthMin > 0 {
+			conditions = append(conditions, reducer.BandwidthAtLeast(filter.BandwidthMin))
+		}
+		if filter.UptimeMin > 0 {
+			conditions = append(conditions, reducer.UptimeAtLeast(filter.UptimeMin))
+		}
 		filter.condition = reducer.And(conditions...)
 	})
 }
@@ -87,6 +101,17 @@ func (filter *Filter) Matches(proposal market.ServiceProposal) bool {
 	return filter.condition(proposal)
 }
 
+// MatchesPrice return flag if price does not exceed price ceilings of the filter
+func (filter *Filter) MatchesPrice(price market.Price) bool {
+	if filter.PricePerHourMax != nil && (price.PricePerHour == nil || price.PricePerHour.Cmp(filter.PricePerHourMax) > 0) {
+		return false
+	}
+	if filter.PricePerGiBMax != nil && (price.PricePerGiB == nil || price.PricePerGiB.Cmp(filter.PricePerGiBMax) > 0) {
+		return false
+	}
+	return true
+}
+
 // ToAPIQuery serialises filter to query of Mysterium API
 func (filter *Filter) ToAPIQuery() mysterium.ProposalsQuery {
 	query := mysterium.ProposalsQuery{
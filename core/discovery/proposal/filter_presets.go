@@ -191,6 +191,11 @@ func (fps *FilterPreset) Filter(proposals []PricedServiceProposal) []PricedServi
 	return fps.filter(proposals) // because of storage, fps.filter can't be exported as a struct property
 }
 
+// IsSystem returns true for predefined presets, which can't be changed.
+func (fps *FilterPreset) IsSystem() bool {
+	return fps.ID != 0 && fps.ID < startingID
+}
+
 func filterPresets(entries []FilterPreset) *FilterPresets {
 	return &FilterPresets{Entries: entries}
 }
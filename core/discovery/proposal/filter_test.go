@@ -18,6 +18,7 @@
 package proposal
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/mysteriumnetwork/node/market"
@@ -161,3 +162,26 @@ func Test_ProposalFilter_Filters_Unsupported(t *testing.T) {
 	assert.False(t, filter.Matches(proposalEmpty))
 	assert.True(t, filter.Matches(proposalSupported))
 }
+
+func Test_ProposalFilter_FiltersByQuality(t *testing.T) {
+	fast := market.ServiceProposal{Quality: market.Quality{Bandwidth: 50, Uptime: 20}}
+	slow := market.ServiceProposal{Quality: market.Quality{Bandwidth: 5, Uptime: 24}}
+
+	filter := &Filter{BandwidthMin: 10}
+	assert.True(t, filter.Matches(fast))
+	assert.False(t, filter.Matches(slow))
+
+	filter = &Filter{UptimeMin: 22}
+	assert.False(t, filter.Matches(fast))
+	assert.True(t, filter.Matches(slow))
+}
+
+func Test_ProposalFilter_MatchesPrice(t *testing.T) {
+	price := market.Price{PricePerHour: big.NewInt(100), PricePerGiB: big.NewInt(1000)}
+
+	assert.True(t, (&Filter{}).MatchesPrice(price))
+	assert.True(t, (&Filter{PricePerHourMax: big.NewInt(100), PricePerGiBMax: big.NewInt(1000)}).MatchesPrice(price))
+	assert.False(t, (&Filter{PricePerHourMax: big.NewInt(99)}).MatchesPrice(price))
+	assert.False(t, (&Filter{PricePerGiBMax: big.NewInt(999)}).MatchesPrice(price))
+	assert.False(t, (&Filter{PricePerGiBMax: big.NewInt(999)}).MatchesPrice(market.Price{}))
+}
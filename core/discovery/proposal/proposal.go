@@ -18,6 +18,8 @@
 package proposal
 
 import (
+	"time"
+
 	"github.com/mysteriumnetwork/node/market"
 )
 
@@ -30,8 +32,20 @@ type Repository interface {
 	Countries(filter *Filter) (map[string]int, error)
 }
 
+// StreamingRepository serves large proposal sets page by page instead of loading them at once.
+type StreamingRepository interface {
+	// ProposalsPage returns at most limit proposals matching the filter starting at the cursor,
+	// along with the cursor of the next page, which is empty on the last page.
+	ProposalsPage(filter *Filter, cursor string, limit int) ([]market.ServiceProposal, string, error)
+	// StreamProposals passes proposals matching the filter to the callback as pages arrive.
+	StreamProposals(filter *Filter, fn func(market.ServiceProposal) error) error
+}
+
 // PricedServiceProposal enriches proposals with price data.
 type PricedServiceProposal struct {
 	market.ServiceProposal
 	Price market.Price `json:"price,omitempty"`
+	// MeasuredRTT is a round trip time to the provider measured by consumer before connecting.
+	// Zero value means latency was not measured or provider did not respond.
+	MeasuredRTT time.Duration `json:"measured_rtt,omitempty"`
 }
@@ -29,6 +29,8 @@ const (
 	SortTypeLatency   = "latency"
 	SortTypePrice     = "price"
 	SortTypeQuality   = "quality"
+	// SortTypeMeasuredLatency sorts by round trip time measured by consumer before connecting.
+	SortTypeMeasuredLatency = "measured_latency"
 )
 
 // ErrUnsupportedSortType indicates unsupported proposals sorting type error.
@@ -48,6 +50,8 @@ func Sort(proposals []PricedServiceProposal, sortType string) ([]PricedServicePr
 		return SortByPrice(proposals), nil
 	case SortTypeQuality:
 		return SortByQuality(proposals), nil
+	case SortTypeMeasuredLatency:
+		return SortByMeasuredLatency(proposals), nil
 	case "": // Assuming zero value to be no sorting.
 		return proposals, nil
 	default:
@@ -61,7 +65,7 @@ func SortByQuality(proposals []PricedServiceProposal) []PricedServiceProposal {
 	copy(tmp, proposals)
 
 	sort.Slice(tmp, func(i, j int) bool {
-		return proposals[i].Quality.Quality > proposals[j].Quality.Quality
+		return tmp[i].Quality.Quality > tmp[j].Quality.Quality
 	})
 
 	return tmp
@@ -73,7 +77,7 @@ func SortByLatency(proposals []PricedServiceProposal) []PricedServiceProposal {
 	copy(tmp, proposals)
 
 	sort.Slice(tmp, func(i, j int) bool {
-		return proposals[i].Quality.Latency < proposals[j].Quality.Latency
+		return tmp[i].Quality.Latency < tmp[j].Quality.Latency
 	})
 
 	return tmp
@@ -85,7 +89,7 @@ func SortByUptime(proposals []PricedServiceProposal) []PricedServiceProposal {
 	copy(tmp, proposals)
 
 	sort.Slice(tmp, func(i, j int) bool {
-		return proposals[i].Quality.Uptime > proposals[j].Quality.Uptime
+		return tmp[i].Quality.Uptime > tmp[j].Quality.Uptime
 	})
 
 	return tmp
@@ -97,7 +101,7 @@ func SortByBandwidth(proposals []PricedServiceProposal) []PricedServiceProposal
 	copy(tmp, proposals)
 
 	sort.Slice(tmp, func(i, j int) bool {
-		return proposals[i].Quality.Bandwidth > proposals[j].Quality.Bandwidth
+		return tmp[i].Quality.Bandwidth > tmp[j].Quality.Bandwidth
 	})
 
 	return tmp
@@ -109,7 +113,26 @@ func SortByPrice(proposals []PricedServiceProposal) []PricedServiceProposal {
 	copy(tmp, proposals)
 
 	sort.Slice(tmp, func(i, j int) bool {
-		return proposals[i].Price.PricePerHour.Cmp(proposals[j].Price.PricePerGiB) == 1
+		if c := tmp[i].Price.PricePerGiB.Cmp(tmp[j].Price.PricePerGiB); c != 0 {
+			return c == 1
+		}
+		return tmp[i].Price.PricePerHour.Cmp(tmp[j].Price.PricePerHour) == 1
+	})
+
+	return tmp
+}
+
+// SortByMeasuredLatency sorts proposals list based on round trip time measured by consumer.
+// Proposals without measured latency are moved to the end of the list.
+func SortByMeasuredLatency(proposals []PricedServiceProposal) []PricedServiceProposal {
+	tmp := make([]PricedServiceProposal, len(proposals))
+	copy(tmp, proposals)
+
+	sort.SliceStable(tmp, func(i, j int) bool {
+		if tmp[i].MeasuredRTT == 0 || tmp[j].MeasuredRTT == 0 {
+			return tmp[j].MeasuredRTT == 0 && tmp[i].MeasuredRTT != 0
+		}
+		return tmp[i].MeasuredRTT < tmp[j].MeasuredRTT
 	})
 
 	return tmp
@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package proposal
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Proposal fields which proposals can be sorted by in addition to the sorting types.
+const (
+	SortTypePricePerHour = "price_per_hour"
+	SortTypePricePerGiB  = "price_per_gib"
+	SortTypeCountry      = "country"
+)
+
+// SortKey is a proposal field to sort by.
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+var sortKeyComparators = map[string]func(a, b PricedServiceProposal) int{
+	SortTypePricePerHour: func(a, b PricedServiceProposal) int {
+		return compareBig(a.Price.PricePerHour, b.Price.PricePerHour)
+	},
+	SortTypePricePerGiB: func(a, b PricedServiceProposal) int {
+		return compareBig(a.Price.PricePerGiB, b.Price.PricePerGiB)
+	},
+	SortTypeQuality: func(a, b PricedServiceProposal) int {
+		return compareFloat(a.Quality.Quality, b.Quality.Quality)
+	},
+	SortTypeBandwidth: func(a, b PricedServiceProposal) int {
+		return compareFloat(a.Quality.Bandwidth, b.Quality.Bandwidth)
+	},
+	SortTypeUptime: func(a, b PricedServiceProposal) int {
+		return compareFloat(a.Quality.Uptime, b.Quality.Uptime)
+	},
+	SortTypeLatency: func(a, b PricedServiceProposal) int {
+		return compareFloat(a.Quality.Latency, b.Quality.Latency)
+	},
+	SortTypeCountry: func(a, b PricedServiceProposal) int {
+		return strings.Compare(a.Location.Country, b.Location.Country)
+	},
+}
+
+// ParseSortKeys parses comma separated proposal fields, e.g. "price_per_gib,-quality".
+// Fields prefixed with "-" are sorted in descending order.
+func ParseSortKeys(value string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		key := SortKey{Field: strings.TrimPrefix(field, "-"), Descending: strings.HasPrefix(field, "-")}
+		if _, ok := sortKeyComparators[key.Field]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedSortType, key.Field)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// SortByKeys sorts proposals by the given keys in place, the first key having the highest priority.
+// Order of proposals equal by all keys is preserved.
+func SortByKeys(proposals []PricedServiceProposal, keys []SortKey) {
+	if len(keys) == 0 {
+		return
+	}
+
+	sort.SliceStable(proposals, func(i, j int) bool {
+		for _, key := range keys {
+			compare, ok := sortKeyComparators[key.Field]
+			if !ok {
+				continue
+			}
+
+			result := compare(proposals[i], proposals[j])
+			if key.Descending {
+				result = -result
+			}
+			if result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	})
+}
+
+// compareBig orders missing values last.
+func compareBig(a, b *big.Int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	default:
+		return a.Cmp(b)
+	}
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
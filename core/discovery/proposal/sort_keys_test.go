@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package proposal
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+func pricedProposal(provider, country string, perHour int64, quality float64) PricedServiceProposal {
+	return PricedServiceProposal{
+		ServiceProposal: market.ServiceProposal{
+			ProviderID: provider,
+			Location:   market.Location{Country: country},
+			Quality:    market.Quality{Quality: quality},
+		},
+		Price: market.Price{PricePerHour: big.NewInt(perHour), PricePerGiB: big.NewInt(1)},
+	}
+}
+
+func providers(proposals []PricedServiceProposal) []string {
+	ids := make([]string, len(proposals))
+	for i, p := range proposals {
+		ids[i] = p.ProviderID
+	}
+	return ids
+}
+
+func TestParseSortKeys(t *testing.T) {
+	keys, err := ParseSortKeys("price_per_gib, -quality,")
+	assert.NoError(t, err)
+	assert.Equal(t, []SortKey{{Field: SortTypePricePerGiB}, {Field: SortTypeQuality, Descending: true}}, keys)
+
+	keys, err = ParseSortKeys("")
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	_, err = ParseSortKeys("fastest,-quality")
+	assert.EqualError(t, err, "unsupported proposal sort type: fastest")
+}
+
+func TestSortByKeys(t *testing.T) {
+	proposals := []PricedServiceProposal{
+		pricedProposal("0x1", "DE", 3, 1),
+		pricedProposal("0x2", "LT", 1, 1),
+		pricedProposal("0x3", "DE", 1, 2),
+		pricedProposal("0x4", "US", 2, 3),
+	}
+
+	SortByKeys(proposals, []SortKey{{Field: SortTypePricePerHour}, {Field: SortTypeQuality, Descending: true}})
+	assert.Equal(t, []string{"0x3", "0x2", "0x4", "0x1"}, providers(proposals))
+
+	SortByKeys(proposals, []SortKey{{Field: SortTypeCountry}})
+	assert.Equal(t, []string{"0x3", "0x1", "0x2", "0x4"}, providers(proposals))
+
+	SortByKeys(proposals, nil)
+	assert.Equal(t, []string{"0x3", "0x1", "0x2", "0x4"}, providers(proposals))
+}
+
+func TestSortByPrice_ComparesSamePriceComponents(t *testing.T) {
+	cheapPerGiB := pricedProposal("0x1", "LT", 100, 0)
+	cheapPerGiB.Price.PricePerGiB = big.NewInt(1)
+	expensivePerGiB := pricedProposal("0x2", "LT", 1, 0)
+	expensivePerGiB.Price.PricePerGiB = big.NewInt(50)
+	expensivePerHour := pricedProposal("0x3", "LT", 200, 0)
+	expensivePerHour.Price.PricePerGiB = big.NewInt(1)
+
+	sorted := SortByPrice([]PricedServiceProposal{cheapPerGiB, expensivePerGiB, expensivePerHour})
+
+	assert.Equal(t, []string{"0x2", "0x3", "0x1"}, providers(sorted))
+}
@@ -72,3 +72,29 @@ func Unsupported() func(market.ServiceProposal) bool {
 		return proposal.IsSupported()
 	}
 }
+
+// AddressFamily returns a matcher for checking if provider is reachable over any of the given address families
+func AddressFamily(families []string) func(market.ServiceProposal) bool {
+	return func(proposal market.ServiceProposal) bool {
+		for _, family := range families {
+			if proposal.SupportsAddressFamily(family) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// BandwidthAtLeast returns a matcher for checking if provider bandwidth is not lower than given
+func BandwidthAtLeast(min float64) func(market.ServiceProposal) bool {
+	return func(proposal market.ServiceProposal) bool {
+		return proposal.Quality.Bandwidth >= min
+	}
+}
+
+// UptimeAtLeast returns a matcher for checking if provider uptime is not lower than given
+func UptimeAtLeast(min float64) func(market.ServiceProposal) bool {
+	return func(proposal market.ServiceProposal) bool {
+		return proposal.Quality.Uptime >= min
+	}
+}
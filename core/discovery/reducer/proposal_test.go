@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/market"
 )
 
 func Test_ProviderID(t *testing.T) {
@@ -85,3 +87,15 @@ func Test_AccessPolicy_FiltersByIDAndSource(t *testing.T) {
 	assert.False(t, match(proposalProvider1Noop))
 	assert.True(t, match(proposalProvider2Streaming))
 }
+
+func Test_AddressFamily_FiltersByFamily(t *testing.T) {
+	proposalIPv6 := market.ServiceProposal{AddressFamilies: []string{market.AddressFamilyIPv6}}
+
+	match := AddressFamily([]string{market.AddressFamilyIPv4})
+	assert.True(t, match(proposalEmpty))
+	assert.False(t, match(proposalIPv6))
+
+	match = AddressFamily([]string{market.AddressFamilyIPv4, market.AddressFamilyIPv6})
+	assert.True(t, match(proposalEmpty))
+	assert.True(t, match(proposalIPv6))
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+var (
+	// ErrProposalNotSigned is returned for proposals without provider signature when signatures are required.
+	ErrProposalNotSigned = errors.New("proposal is not signed")
+	// ErrProposalSignatureInvalid is returned for proposals not signed by their provider.
+	ErrProposalSignatureInvalid = errors.New("proposal signature is invalid")
+	// ErrProviderNotRegistered is returned for proposals of providers with unregistered identity.
+	ErrProviderNotRegistered = errors.New("provider identity is not registered")
+)
+
+// registrationCacheTTL is how long a provider is considered registered after a successful check.
+const registrationCacheTTL = 10 * time.Minute
+
+// SignProposal signs the proposal with the provider identity.
+func SignProposal(p market.ServiceProposal, signer identity.Signer) (market.ServiceProposal, error) {
+	message, err := p.SignedMessage()
+	if err != nil {
+		return p, err
+	}
+
+	signature, err := signer.Sign(message)
+	if err != nil {
+		return p, fmt.Errorf("could not sign proposal: %w", err)
+	}
+
+	p.Signature = signature.Base64()
+	return p, nil
+}
+
+// ProposalVerifier checks that proposals are signed by their providers and that providers are registered.
+type ProposalVerifier struct {
+	registry      registry.IdentityRegistry
+	chainID       int64
+	requireSigned bool
+	now           func() time.Time
+
+	mu         sync.Mutex
+	registered map[string]time.Time
+}
+
+// NewProposalVerifier creates proposal verifier. Unsigned proposals of providers
+// running older nodes are accepted unless signatures are required.
+func NewProposalVerifier(identityRegistry registry.IdentityRegistry, chainID int64, requireSigned bool) *ProposalVerifier {
+	return &ProposalVerifier{
+		registry:      identityRegistry,
+		chainID:       chainID,
+		requireSigned: requireSigned,
+		now:           time.Now,
+		registered:    make(map[string]time.Time),
+	}
+}
+
+// VerifySignature checks the provider signature of the proposal.
+func (v *ProposalVerifier) VerifySignature(p market.ServiceProposal) error {
+	if p.Signature == "" {
+		if v.requireSigned {
+			return fmt.Errorf("%w: %s", ErrProposalNotSigned, p.ProviderID)
+		}
+		return nil
+	}
+
+	message, err := p.SignedMessage()
+	if err != nil {
+		return err
+	}
+	verifier := identity.NewVerifierIdentity(identity.FromAddress(p.ProviderID))
+	if ok, _ := verifier.Verify(message, identity.SignatureBase64(p.Signature)); !ok {
+		return fmt.Errorf("%w: %s", ErrProposalSignatureInvalid, p.ProviderID)
+	}
+	return nil
+}
+
+// Verify checks the provider signature of the proposal and provider registration.
+func (v *ProposalVerifier) Verify(p market.ServiceProposal) error {
+	if err := v.VerifySignature(p); err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	checkedAt, ok := v.registered[p.ProviderID]
+	v.mu.Unlock()
+	if ok && v.now().Sub(checkedAt) < registrationCacheTTL {
+		return nil
+	}
+
+	status, err := v.registry.GetRegistrationStatus(v.chainID, identity.FromAddress(p.ProviderID))
+	if err != nil {
+		return fmt.Errorf("could not check provider %s registration: %w", p.ProviderID, err)
+	}
+	if !status.Registered() {
+		return fmt.Errorf("%w: %s", ErrProviderNotRegistered, p.ProviderID)
+	}
+
+	v.mu.Lock()
+	v.registered[p.ProviderID] = v.now()
+	v.mu.Unlock()
+	return nil
+}
+
+// VerifiedRepository drops proposals failing verification. Listed proposals are checked for signatures only,
+// proposals of requested providers, which are about to be connected to, are fully verified.
+type VerifiedRepository struct {
+	base     proposal.Repository
+	verifier *ProposalVerifier
+}
+
+// NewVerifiedRepository creates proposal repository returning verified proposals only.
+func NewVerifiedRepository(base proposal.Repository, verifier *ProposalVerifier) *VerifiedRepository {
+	return &VerifiedRepository{base: base, verifier: verifier}
+}
+
+// Proposal returns a single proposal by its ID.
+func (vr *VerifiedRepository) Proposal(id market.ProposalID) (*market.ServiceProposal, error) {
+	p, err := vr.base.Proposal(id)
+	if err != nil || p == nil {
+		return p, err
+	}
+
+	if err := vr.verifier.Verify(*p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Proposals returns proposals matching the filter. If all proposals of requested providers
+// fail verification, the verification error is returned.
+func (vr *VerifiedRepository) Proposals(filter *proposal.Filter) ([]market.ServiceProposal, error) {
+	proposals, err := vr.base.Proposals(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := filter != nil && (filter.ProviderID != "" || len(filter.ProviderIDs) > 0)
+	verify := vr.verifier.VerifySignature
+	if requested {
+		verify = vr.verifier.Verify
+	}
+
+	var verifyErr error
+	verified := make([]market.ServiceProposal, 0, len(proposals))
+	for _, p := range proposals {
+		if err := verify(p); err != nil {
+			log.Debug().Err(err).Msgf("Skipping proposal %s of %s", p.ServiceType, p.ProviderID)
+			if verifyErr == nil {
+				verifyErr = err
+			}
+			continue
+		}
+		verified = append(verified, p)
+	}
+
+	if requested && len(verified) == 0 && verifyErr != nil {
+		return nil, verifyErr
+	}
+	return verified, nil
+}
+
+// Countries returns number of proposals per country.
+func (vr *VerifiedRepository) Countries(filter *proposal.Filter) (map[string]int, error) {
+	return vr.base.Countries(filter)
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package discovery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+const signingProvider = "0x53a835143c0ef3bbcbfa796d7eb738ca7dd28f68"
+
+func signedProposal(t *testing.T) market.ServiceProposal {
+	ks := identity.NewMockKeystoreWith(identity.MockKeys)
+	require.NoError(t, ks.Unlock(accounts.Account{Address: common.HexToAddress(signingProvider)}, ""))
+
+	p := market.NewProposal(signingProvider, "wireguard", market.NewProposalOpts{
+		Location: &market.Location{Country: "LT"},
+	})
+	signed, err := SignProposal(p, identity.NewSigner(ks, identity.FromAddress(signingProvider)))
+	require.NoError(t, err)
+	require.NotEmpty(t, signed.Signature)
+	return signed
+}
+
+func TestProposalVerifier_VerifySignature(t *testing.T) {
+	verifier := NewProposalVerifier(&registry.FakeRegistry{}, 1, true)
+
+	signed := signedProposal(t)
+	assert.NoError(t, verifier.VerifySignature(signed))
+
+	signed.Quality.Quality = 2
+	signed.ID = 42
+	assert.NoError(t, verifier.VerifySignature(signed), "discovery assigned fields are not signed")
+
+	tampered := signed
+	tampered.Location.Country = "US"
+	assert.True(t, errors.Is(verifier.VerifySignature(tampered), ErrProposalSignatureInvalid))
+
+	unsigned := signed
+	unsigned.Signature = ""
+	assert.True(t, errors.Is(verifier.VerifySignature(unsigned), ErrProposalNotSigned))
+	assert.NoError(t, NewProposalVerifier(&registry.FakeRegistry{}, 1, false).VerifySignature(unsigned))
+}
+
+func TestProposalVerifier_Verify(t *testing.T) {
+	fakeRegistry := &registry.FakeRegistry{RegistrationStatus: registry.Unregistered}
+	verifier := NewProposalVerifier(fakeRegistry, 1, true)
+	signed := signedProposal(t)
+
+	assert.True(t, errors.Is(verifier.Verify(signed), ErrProviderNotRegistered))
+
+	fakeRegistry.RegistrationStatus = registry.Registered
+	assert.NoError(t, verifier.Verify(signed))
+}
+
+func TestVerifiedRepository_Proposals(t *testing.T) {
+	signed := signedProposal(t)
+	tampered := signed
+	tampered.ServiceType = "openvpn"
+
+	fakeRegistry := &registry.FakeRegistry{RegistrationStatus: registry.Unregistered}
+	repo := NewVerifiedRepository(
+		&mockRepository{proposalsToReturn: []market.ServiceProposal{signed, tampered}},
+		NewProposalVerifier(fakeRegistry, 1, false),
+	)
+
+	proposals, err := repo.Proposals(&proposal.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []market.ServiceProposal{signed}, proposals)
+
+	_, err = repo.Proposals(&proposal.Filter{ProviderID: signingProvider})
+	assert.True(t, errors.Is(err, ErrProviderNotRegistered))
+
+	fakeRegistry.RegistrationStatus = registry.Registered
+	proposals, err = repo.Proposals(&proposal.Filter{ProviderID: signingProvider})
+	assert.NoError(t, err)
+	assert.Equal(t, []market.ServiceProposal{signed}, proposals)
+}
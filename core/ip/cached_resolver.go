@@ -22,12 +22,15 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
 )
 
 // CachedResolver resolves IP and caches for some duration.
 type CachedResolver struct {
 	resolver      Resolver
 	cacheDuration time.Duration
+	publisher     eventbus.Publisher
 
 	outboundIP         string
 	outboundIPLock     sync.Mutex
@@ -36,13 +39,15 @@ type CachedResolver struct {
 	publicIP         string
 	publicIPLock     sync.Mutex
 	publicIPCachedAt time.Time
+	lastPublicIP     string
 }
 
-// NewCachedResolver creates ip resolver with cache duration.
-func NewCachedResolver(resolver Resolver, cacheDuration time.Duration) *CachedResolver {
+// NewCachedResolver creates ip resolver with cache duration. Public IP changes are published to the given publisher.
+func NewCachedResolver(resolver Resolver, cacheDuration time.Duration, publisher eventbus.Publisher) *CachedResolver {
 	return &CachedResolver{
 		resolver:      resolver,
 		cacheDuration: cacheDuration,
+		publisher:     publisher,
 	}
 }
 
@@ -77,12 +82,30 @@ func (r *CachedResolver) GetPublicIP() (string, error) {
 	}
 
 	log.Debug().Msg("Public IP cache is empty, fetching IP")
+	return r.fetchPublicIP()
+}
+
+// RefreshPublicIP fetches public IP bypassing the cache.
+func (r *CachedResolver) RefreshPublicIP() (string, error) {
+	r.publicIPLock.Lock()
+	defer r.publicIPLock.Unlock()
+
+	return r.fetchPublicIP()
+}
+
+func (r *CachedResolver) fetchPublicIP() (string, error) {
 	publicIP, err := r.resolver.GetPublicIP()
 	if err != nil {
 		return "", err
 	}
 	r.publicIPCachedAt = time.Now()
 	r.publicIP = publicIP
+
+	if publicIP != r.lastPublicIP {
+		log.Info().Msgf("Public IP changed from %q to %q", r.lastPublicIP, publicIP)
+		r.publisher.Publish(AppTopicPublicIPChanged, AppEventPublicIPChanged{Previous: r.lastPublicIP, Current: publicIP})
+		r.lastPublicIP = publicIP
+	}
 	return r.publicIP, nil
 }
 
@@ -51,7 +51,7 @@ func TestCachedResolverCachesPublicIP(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mr := &mockRealResolver{}
-			cr := NewCachedResolver(mr, test.cacheDuration)
+			cr := NewCachedResolver(mr, test.cacheDuration, &mockPublisher{})
 
 			var actualIP string
 			var err error
@@ -95,7 +95,7 @@ func TestCachedResolverCachesOutboundIP(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mr := &mockRealResolver{}
-			cr := NewCachedResolver(mr, test.cacheDuration)
+			cr := NewCachedResolver(mr, test.cacheDuration, &mockPublisher{})
 
 			var actualIP string
 			var err error
@@ -111,6 +111,33 @@ func TestCachedResolverCachesOutboundIP(t *testing.T) {
 	}
 }
 
+func TestCachedResolverPublishesPublicIPChanges(t *testing.T) {
+	mr := NewResolverMockMultiple("192.168.1.2", "1.1.1.1", "1.1.1.1", "2.2.2.2")
+	publisher := &mockPublisher{}
+	cr := NewCachedResolver(mr, time.Hour, publisher)
+
+	for i := 0; i < 3; i++ {
+		_, err := cr.GetPublicIP()
+		assert.NoError(t, err)
+		cr.ClearCache()
+	}
+
+	assert.Equal(t, []interface{}{
+		AppEventPublicIPChanged{Previous: "", Current: "1.1.1.1"},
+		AppEventPublicIPChanged{Previous: "1.1.1.1", Current: "2.2.2.2"},
+	}, publisher.events)
+}
+
+type mockPublisher struct {
+	events []interface{}
+}
+
+func (m *mockPublisher) Publish(topic string, data interface{}) {
+	if topic == AppTopicPublicIPChanged {
+		m.events = append(m.events, data)
+	}
+}
+
 type mockRealResolver struct {
 	getOutboundIPCalls int
 	getPublicIPCalls   int
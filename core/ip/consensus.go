@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ip
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultQueries is the number of public IP sources queried in parallel.
+const DefaultQueries = 3
+
+const defaultSourceTimeout = 10 * time.Second
+
+// Consensus is the public IP reported by the majority of queried sources.
+type Consensus struct {
+	IP string
+	// Votes is the number of sources which reported the IP.
+	Votes int
+	// Queried is the number of sources asked, including failed ones.
+	Queried int
+	// Confidence is the share of queried sources which reported the IP.
+	Confidence float64
+}
+
+// ResolvePublicIP queries sources in parallel batches until the majority of responses agree on the public IP.
+// When sources keep failing or disagreeing the remaining ones are queried, and the most reported IP wins.
+func (r *ResolverImpl) ResolvePublicIP(ctx context.Context) (Consensus, error) {
+	if len(r.sources) == 0 {
+		return Consensus{}, errors.New("no public IP sources configured")
+	}
+
+	votes := make(map[string]int)
+	var best string
+	var queried, responses int
+
+	sources := shuffleSources(r.sources)
+	for len(sources) > 0 && ctx.Err() == nil {
+		n := r.queries
+		if n > len(sources) {
+			n = len(sources)
+		}
+		batch := sources[:n]
+		sources = sources[n:]
+		queried += n
+
+		for _, ip := range r.queryBatch(ctx, batch) {
+			responses++
+			votes[ip]++
+			if votes[ip] > votes[best] {
+				best = ip
+			}
+		}
+
+		if best != "" && votes[best]*2 > responses {
+			break
+		}
+	}
+
+	if best == "" {
+		return Consensus{}, errors.New("none of public IP sources responded")
+	}
+	if len(votes) > 1 {
+		log.Warn().Interface("votes", votes).Msgf("Public IP sources disagree, using %s", best)
+	}
+
+	return Consensus{
+		IP:         best,
+		Votes:      votes[best],
+		Queried:    queried,
+		Confidence: float64(votes[best]) / float64(queried),
+	}, nil
+}
+
+func (r *ResolverImpl) queryBatch(ctx context.Context, sources []Source) []string {
+	ctx, cancel := context.WithTimeout(ctx, r.sourceTimeout)
+	defer cancel()
+
+	res := make(chan string, len(sources))
+	for _, source := range sources {
+		go func(source Source) {
+			ip, err := source.PublicIP(ctx)
+			if err != nil {
+				log.Debug().Err(err).Str("source", source.Name()).Msg("Public IP source failed")
+			}
+			res <- ip
+		}(source)
+	}
+
+	var ips []string
+	for range sources {
+		if ip := <-res; ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+func shuffleSources(sources []Source) []Source {
+	tmp := make([]Source, len(sources))
+	copy(tmp, sources)
+	rng.Shuffle(len(tmp), func(i, j int) {
+		tmp[i], tmp[j] = tmp[j], tmp[i]
+	})
+	return tmp
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ip
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSource struct {
+	ip    string
+	err   error
+	calls int32
+}
+
+func (s *mockSource) Name() string {
+	return "mock"
+}
+
+func (s *mockSource) PublicIP(_ context.Context) (string, error) {
+	atomic.AddInt32(&s.calls, 1)
+	return s.ip, s.err
+}
+
+func newTestChainResolver(queries int, sources ...Source) *ResolverImpl {
+	return NewChainResolver(nil, "", "", nil, sources, queries)
+}
+
+func TestResolvePublicIP_Consensus(t *testing.T) {
+	resolver := newTestChainResolver(3,
+		&mockSource{ip: "1.1.1.1"},
+		&mockSource{ip: "1.1.1.1"},
+		&mockSource{ip: "2.2.2.2"},
+	)
+
+	consensus, err := resolver.ResolvePublicIP(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.1.1", consensus.IP)
+	assert.Equal(t, 2, consensus.Votes)
+	assert.Equal(t, 3, consensus.Queried)
+	assert.InDelta(t, 2.0/3.0, consensus.Confidence, 0.001)
+}
+
+func TestResolvePublicIP_QueriesNextBatchWhenSourcesFail(t *testing.T) {
+	failing := &mockSource{err: errors.New("down")}
+	working := &mockSource{ip: "1.1.1.1"}
+	resolver := newTestChainResolver(1, failing, failing, failing, working)
+
+	consensus, err := resolver.ResolvePublicIP(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.1.1", consensus.IP)
+	assert.Equal(t, int32(1), working.calls)
+}
+
+func TestResolvePublicIP_StopsOnceMajorityAgrees(t *testing.T) {
+	sources := []*mockSource{{ip: "1.1.1.1"}, {ip: "1.1.1.1"}, {ip: "1.1.1.1"}, {ip: "1.1.1.1"}}
+	resolver := newTestChainResolver(2, sources[0], sources[1], sources[2], sources[3])
+
+	consensus, err := resolver.ResolvePublicIP(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, consensus.Queried)
+
+	var calls int32
+	for _, s := range sources {
+		calls += s.calls
+	}
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestResolvePublicIP_AllSourcesFail(t *testing.T) {
+	resolver := newTestChainResolver(2, &mockSource{err: errors.New("down")}, &mockSource{err: errors.New("down")})
+
+	_, err := resolver.ResolvePublicIP(context.Background())
+	assert.Error(t, err)
+
+	_, err = newTestChainResolver(2).ResolvePublicIP(context.Background())
+	assert.Error(t, err)
+}
+
+type blockingSource struct{}
+
+func (s *blockingSource) Name() string {
+	return "blocking"
+}
+
+func (s *blockingSource) PublicIP(ctx context.Context) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestResolvePublicIP_SlowSourceTimesOut(t *testing.T) {
+	resolver := newTestChainResolver(2, &blockingSource{}, &mockSource{ip: "1.1.1.1"})
+	resolver.sourceTimeout = 10 * time.Millisecond
+
+	consensus, err := resolver.ResolvePublicIP(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.1.1", consensus.IP)
+	assert.Equal(t, 0.5, consensus.Confidence)
+}
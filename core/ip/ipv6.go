@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Well known addresses used to check whether the node has a route in the given address family.
+// No packets are sent, dialing UDP only consults the routing table.
+const (
+	routeCheckIPv4 = "1.1.1.1:53"
+	routeCheckIPv6 = "[2606:4700:4700::1111]:53"
+)
+
+// ErrNoGlobalIPv6 is returned when the node has no global unicast IPv6 address.
+var ErrNoGlobalIPv6 = errors.New("no global IPv6 address found")
+
+// HasIPv4Route returns true if the node has a route to the IPv4 internet.
+func HasIPv4Route() bool {
+	return hasRoute("udp4", routeCheckIPv4)
+}
+
+// HasIPv6Route returns true if the node has a route to the IPv6 internet.
+func HasIPv6Route() bool {
+	return hasRoute("udp6", routeCheckIPv6)
+}
+
+func hasRoute(network, address string) bool {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// GlobalIPv6 returns the first global unicast IPv6 address of the node. Unlike IPv4 such address
+// is usually reachable without NAT, so it is used as is instead of asking external service.
+func GlobalIPv6() (net.IP, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.To4() != nil {
+			continue
+		}
+		if isGlobalIPv6(ipNet.IP) {
+			return ipNet.IP, nil
+		}
+	}
+
+	return nil, ErrNoGlobalIPv6
+}
+
+var uniqueLocalIPv6 = &net.IPNet{IP: net.ParseIP("fc00::"), Mask: net.CIDRMask(7, 128)}
+
+func isGlobalIPv6(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !uniqueLocalIPv6.Contains(ip)
+}
+
+// SynthesizeNAT64 embeds IPv4 address into the NAT64 prefix as described in RFC 6052,
+// e.g. 192.0.2.33 with prefix 64:ff9b::/96 becomes 64:ff9b::c000:221.
+func SynthesizeNAT64(prefix *net.IPNet, ipv4 net.IP) (net.IP, error) {
+	v4 := ipv4.To4()
+	if v4 == nil {
+		return nil, fmt.Errorf("not an IPv4 address: %s", ipv4)
+	}
+	ones, bits := prefix.Mask.Size()
+	if bits != 8*net.IPv6len || prefix.IP.To4() != nil {
+		return nil, fmt.Errorf("not an IPv6 prefix: %s", prefix)
+	}
+
+	res := make(net.IP, net.IPv6len)
+	copy(res, prefix.IP.To16())
+
+	// Bits 64-71 (the "u" octet) must stay zero, so the IPv4 address is split around it for shorter prefixes.
+	var offsets []int
+	switch ones {
+	case 32:
+		offsets = []int{4, 5, 6, 7}
+	case 40:
+		offsets = []int{5, 6, 7, 9}
+	case 48:
+		offsets = []int{6, 7, 9, 10}
+	case 56:
+		offsets = []int{7, 9, 10, 11}
+	case 64:
+		offsets = []int{9, 10, 11, 12}
+	case 96:
+		offsets = []int{12, 13, 14, 15}
+	default:
+		return nil, fmt.Errorf("unsupported NAT64 prefix length: %d", ones)
+	}
+	for i := ones / 8; i < net.IPv6len; i++ {
+		res[i] = 0
+	}
+	for i, offset := range offsets {
+		res[offset] = v4[i]
+	}
+
+	return res, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ip
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynthesizeNAT64(t *testing.T) {
+	// Examples from RFC 6052 section 2.4.
+	tests := []struct {
+		prefix   string
+		expected string
+	}{
+		{"2001:db8::/32", "2001:db8:c000:221::"},
+		{"2001:db8:100::/40", "2001:db8:1c0:2:21::"},
+		{"2001:db8:122::/48", "2001:db8:122:c000:2:2100::"},
+		{"2001:db8:122:300::/56", "2001:db8:122:3c0:0:221::"},
+		{"2001:db8:122:344::/64", "2001:db8:122:344:c0:2:2100:0"},
+		{"64:ff9b::/96", "64:ff9b::c000:221"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			_, prefix, err := net.ParseCIDR(tt.prefix)
+			assert.NoError(t, err)
+
+			res, err := SynthesizeNAT64(prefix, net.ParseIP("192.0.2.33"))
+			assert.NoError(t, err)
+			assert.Equal(t, net.ParseIP(tt.expected).String(), res.String())
+		})
+	}
+}
+
+func TestSynthesizeNAT64_InvalidInput(t *testing.T) {
+	_, prefix, _ := net.ParseCIDR("64:ff9b::/80")
+	_, err := SynthesizeNAT64(prefix, net.ParseIP("192.0.2.33"))
+	assert.Error(t, err)
+
+	_, prefix, _ = net.ParseCIDR("64:ff9b::/96")
+	_, err = SynthesizeNAT64(prefix, net.ParseIP("2001:db8::1"))
+	assert.Error(t, err)
+}
+
+func TestIsGlobalIPv6(t *testing.T) {
+	assert.True(t, isGlobalIPv6(net.ParseIP("2001:db8::1")))
+	assert.False(t, isGlobalIPv6(net.ParseIP("fd00::1")))
+	assert.False(t, isGlobalIPv6(net.ParseIP("fe80::1")))
+	assert.False(t, isGlobalIPv6(net.ParseIP("::1")))
+}
@@ -18,8 +18,10 @@
 package ip
 
 import (
+	"context"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -42,15 +44,33 @@ type ResolverImpl struct {
 	url         string
 	httpClient  *requests.HTTPClient
 	fallbacks   []string
+
+	sources       []Source
+	queries       int
+	sourceTimeout time.Duration
 }
 
-// NewResolver creates new ip-detector resolver with default timeout of one minute
+// NewResolver creates new ip-detector resolver which resolves public IP from the IP detector service,
+// HTTP fallbacks and DNS based sources.
 func NewResolver(httpClient *requests.HTTPClient, bindAddress, url string, fallbacks []string) *ResolverImpl {
+	sources := DefaultSources(httpClient, bindAddress, url, fallbacks, nil)
+	return NewChainResolver(httpClient, bindAddress, url, fallbacks, sources, DefaultQueries)
+}
+
+// NewChainResolver creates new ip-detector resolver which resolves public IP by consensus of given sources,
+// querying the given number of them in parallel. Proxy IP is still resolved via the IP detector service and fallbacks.
+func NewChainResolver(httpClient *requests.HTTPClient, bindAddress, url string, fallbacks []string, sources []Source, queries int) *ResolverImpl {
+	if queries <= 0 {
+		queries = DefaultQueries
+	}
 	return &ResolverImpl{
-		bindAddress: bindAddress,
-		url:         url,
-		httpClient:  httpClient,
-		fallbacks:   fallbacks,
+		bindAddress:   bindAddress,
+		url:           url,
+		httpClient:    httpClient,
+		fallbacks:     fallbacks,
+		sources:       sources,
+		queries:       queries,
+		sourceTimeout: defaultSourceTimeout,
 	}
 }
 
@@ -85,25 +105,13 @@ func (r *ResolverImpl) getOutboundIP() (net.IP, error) {
 	return conn.LocalAddr().(*net.UDPAddr).IP, nil
 }
 
-// GetPublicIP returns current public IP
+// GetPublicIP returns current public IP agreed on by the majority of sources
 func (r *ResolverImpl) GetPublicIP() (string, error) {
-	var ipResponse ipResponse
-
-	request, err := requests.NewGetRequest(r.url, "", nil)
+	consensus, err := r.ResolvePublicIP(context.Background())
 	if err != nil {
-		log.Error().Err(err).Msg("")
 		return "", err
 	}
-	request.Header.Set("User-Agent", apiClient)
-	request.Header.Set("Accept", "application/json")
-
-	err = r.httpClient.DoRequestAndParseResponse(request, &ipResponse)
-	if err != nil {
-		log.Err(err).Msg("could not reach location service, will use fallbacks")
-		return r.findPublicIPViaFallbacks()
-	}
-
-	return ipResponse.IP, nil
+	return consensus.IP, nil
 }
 
 // GetProxyIP returns proxy public IP
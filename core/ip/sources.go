@@ -0,0 +1,217 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ip
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pion/stun"
+	"github.com/pkg/errors"
+
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+// Source resolves public IP using a single external service.
+type Source interface {
+	Name() string
+	PublicIP(ctx context.Context) (string, error)
+}
+
+// DefaultDNSSources are the DNS servers answering with the address of the asking client.
+var DefaultDNSSources = []struct {
+	Server string
+	Name   string
+}{
+	{Server: "resolver1.opendns.com:53", Name: "myip.opendns.com"},
+	{Server: "resolver2.opendns.com:53", Name: "myip.opendns.com"},
+}
+
+// DefaultSources builds the sources used for public IP consensus: the IP detector service,
+// plain HTTP fallbacks, STUN servers and DNS based lookups.
+func DefaultSources(httpClient *requests.HTTPClient, bindAddress, url string, fallbacks, stunServers []string) []Source {
+	var sources []Source
+	if url != "" {
+		sources = append(sources, NewHTTPSource(httpClient, url))
+	}
+	for _, fallback := range fallbacks {
+		sources = append(sources, NewHTTPSource(httpClient, fallback))
+	}
+	for _, server := range stunServers {
+		sources = append(sources, NewSTUNSource(bindAddress, server))
+	}
+	for _, dns := range DefaultDNSSources {
+		sources = append(sources, NewDNSSource(bindAddress, dns.Server, dns.Name))
+	}
+	return sources
+}
+
+type httpSource struct {
+	client *requests.HTTPClient
+	url    string
+}
+
+// NewHTTPSource returns a source querying given URL. Both plain text and JSON ({"IP": "..."}) responses are understood.
+func NewHTTPSource(client *requests.HTTPClient, url string) Source {
+	return &httpSource{client: client, url: url}
+}
+
+func (s *httpSource) Name() string {
+	return s.url
+}
+
+func (s *httpSource) PublicIP(ctx context.Context) (string, error) {
+	req, err := requests.NewGetRequest(s.url, "", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", apiClient)
+
+	res, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response status: %d", res.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	body = bytes.TrimSpace(body)
+
+	text := string(body)
+	if bytes.HasPrefix(body, []byte("{")) {
+		var ipResponse ipResponse
+		if err := json.Unmarshal(body, &ipResponse); err != nil {
+			return "", errors.Wrap(err, "could not parse ip response")
+		}
+		text = ipResponse.IP
+	}
+	return parseIP(text)
+}
+
+type stunSource struct {
+	bindAddress string
+	server      string
+}
+
+// NewSTUNSource returns a source sending a binding request to given STUN server.
+func NewSTUNSource(bindAddress, server string) Source {
+	return &stunSource{bindAddress: bindAddress, server: server}
+}
+
+func (s *stunSource) Name() string {
+	return "stun://" + s.server
+}
+
+func (s *stunSource) PublicIP(ctx context.Context) (string, error) {
+	dialer := net.Dialer{LocalAddr: &net.UDPAddr{IP: net.ParseIP(s.bindAddress)}}
+	conn, err := dialer.DialContext(ctx, "udp4", s.server)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(requests.DefaultTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", err
+	}
+
+	request := stun.MustBuild(stun.TransactionID, stun.BindingRequest)
+	if _, err := conn.Write(request.Raw); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return "", err
+		}
+
+		response := &stun.Message{Raw: buf[:n]}
+		if err := response.Decode(); err != nil || response.TransactionID != request.TransactionID {
+			continue
+		}
+
+		var xorAddr stun.XORMappedAddress
+		if err := xorAddr.GetFrom(response); err == nil {
+			return parseIP(xorAddr.IP.String())
+		}
+		var mappedAddr stun.MappedAddress
+		if err := mappedAddr.GetFrom(response); err != nil {
+			return "", errors.Wrap(err, "mapped address missing in STUN response")
+		}
+		return parseIP(mappedAddr.IP.String())
+	}
+}
+
+type dnsSource struct {
+	bindAddress string
+	server      string
+	name        string
+}
+
+// NewDNSSource returns a source resolving given name on a DNS server which answers with the address of the client.
+func NewDNSSource(bindAddress, server, name string) Source {
+	return &dnsSource{bindAddress: bindAddress, server: server, name: name}
+}
+
+func (s *dnsSource) Name() string {
+	return "dns://" + s.server + "/" + s.name
+}
+
+func (s *dnsSource) PublicIP(ctx context.Context) (string, error) {
+	resolver := net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			dialer := net.Dialer{LocalAddr: &net.UDPAddr{IP: net.ParseIP(s.bindAddress)}}
+			return dialer.DialContext(ctx, "udp4", s.server)
+		},
+	}
+
+	ips, err := resolver.LookupIP(ctx, "ip4", s.name)
+	if err != nil {
+		return "", err
+	}
+	if len(ips) == 0 {
+		return "", errors.New("no addresses in DNS response")
+	}
+	return ips[0].String(), nil
+}
+
+func parseIP(text string) (string, error) {
+	ip := net.ParseIP(text)
+	if ip == nil {
+		return "", errors.New("could not parse ip response")
+	}
+	return ip.String(), nil
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ip
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+func TestHTTPSource(t *testing.T) {
+	tests := map[string]struct {
+		status     int
+		body       string
+		expectedIP string
+	}{
+		"plain text": {status: http.StatusOK, body: "1.2.3.4\n", expectedIP: "1.2.3.4"},
+		"json":       {status: http.StatusOK, body: `{"IP": "1.2.3.4", "Country": "LT"}`, expectedIP: "1.2.3.4"},
+		"garbage":    {status: http.StatusOK, body: "<html></html>"},
+		"error":      {status: http.StatusServiceUnavailable, body: "1.2.3.4"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			source := NewHTTPSource(requests.NewHTTPClient("127.0.0.1", requests.DefaultTimeout), server.URL)
+			ip, err := source.PublicIP(context.Background())
+			if test.expectedIP == "" {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedIP, ip)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type publicIPRefresher interface {
+	RefreshPublicIP() (string, error)
+}
+
+// Watcher periodically re-resolves public IP so that changes caused by DHCP renewals
+// or ISP reassignments are noticed and published as AppEventPublicIPChanged by the cached resolver.
+type Watcher struct {
+	resolver publicIPRefresher
+	interval time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewWatcher returns a new public IP watcher.
+func NewWatcher(resolver publicIPRefresher, interval time.Duration) *Watcher {
+	return &Watcher{
+		resolver: resolver,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start starts watching public IP in the background.
+func (w *Watcher) Start() {
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				if _, err := w.resolver.RefreshPublicIP(); err != nil {
+					log.Warn().Err(err).Msg("Failed to check public IP for changes")
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops watching public IP.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+}
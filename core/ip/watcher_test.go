@@ -0,0 +1,67 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ip
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRefresher struct {
+	calls int32
+}
+
+func (m *mockRefresher) RefreshPublicIP() (string, error) {
+	atomic.AddInt32(&m.calls, 1)
+	return "1.1.1.1", nil
+}
+
+func TestWatcherRefreshesPublicIP(t *testing.T) {
+	refresher := &mockRefresher{}
+	watcher := NewWatcher(refresher, time.Millisecond)
+	watcher.Start()
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&refresher.calls) >= 3
+	}, time.Second, time.Millisecond)
+
+	watcher.Stop()
+	watcher.Stop()
+}
+
+func TestCachedResolverRefreshPublicIPBypassesCache(t *testing.T) {
+	mr := NewResolverMockMultiple("192.168.1.2", "1.1.1.1", "2.2.2.2")
+	publisher := &mockPublisher{}
+	cr := NewCachedResolver(mr, time.Hour, publisher)
+
+	ip, err := cr.GetPublicIP()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.1.1", ip)
+
+	ip, err = cr.RefreshPublicIP()
+	assert.NoError(t, err)
+	assert.Equal(t, "2.2.2.2", ip)
+
+	ip, err = cr.GetPublicIP()
+	assert.NoError(t, err)
+	assert.Equal(t, "2.2.2.2", ip)
+	assert.Len(t, publisher.events, 2)
+}
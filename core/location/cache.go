@@ -24,6 +24,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/ip"
 	"github.com/mysteriumnetwork/node/core/location/locationstate"
 	nodevent "github.com/mysteriumnetwork/node/core/node/event"
 )
@@ -115,6 +116,23 @@ func (c *Cache) HandleConnectionEvent(se connectionstate.AppEventConnectionState
 	}
 }
 
+// HandlePublicIPChanged re-fetches the location once public IP of the node changes,
+// so that proposals are republished with the location of the new IP.
+func (c *Cache) HandlePublicIPChanged(e ip.AppEventPublicIPChanged) {
+	if e.Previous == "" {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, err := c.fetchAndSave()
+	if err != nil {
+		log.Error().Err(err).Msg("Location update failed")
+		c.lastFetched = time.Time{}
+	}
+}
+
 // HandleNodeEvent handles node state change and fetches the location info accordingly.
 func (c *Cache) HandleNodeEvent(se nodevent.Payload) {
 	c.lock.Lock()
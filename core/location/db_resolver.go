@@ -18,7 +18,10 @@
 package location
 
 import (
+	"io/ioutil"
 	"net"
+	"os"
+	"sync"
 
 	"github.com/oschwald/geoip2-golang"
 	"github.com/pkg/errors"
@@ -30,6 +33,7 @@ import (
 
 // DBResolver struct represents ip -> country resolver which uses geoip2 data reader
 type DBResolver struct {
+	dbLock     sync.RWMutex
 	dbReader   *geoip2.Reader
 	ipResolver ip.Resolver
 }
@@ -47,6 +51,29 @@ func NewExternalDBResolver(databasePath string, ipResolver ip.Resolver) (*DBReso
 	}, nil
 }
 
+// NewOfflineResolver returns Resolver which uses country database downloaded to the given path,
+// falling back to the built in database when it is missing or broken.
+func NewOfflineResolver(databasePath string, ipResolver ip.Resolver) (*DBResolver, error) {
+	data, err := ioutil.ReadFile(databasePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn().Err(err).Msg("Failed to read downloaded location database, using built in one")
+		}
+		return NewBuiltInResolver(ipResolver)
+	}
+
+	db, err := geoip2.FromBytes(data)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load downloaded location database, using built in one")
+		return NewBuiltInResolver(ipResolver)
+	}
+
+	return &DBResolver{
+		dbReader:   db,
+		ipResolver: ipResolver,
+	}, nil
+}
+
 // DetectLocation detects current IP-address provides location information for the IP.
 func (r *DBResolver) DetectLocation() (loc locationstate.Location, err error) {
 	ipAddress, err := r.ipResolver.GetPublicIP()
@@ -67,12 +94,19 @@ func (r *DBResolver) DetectProxyLocation(proxyPort int) (loc locationstate.Locat
 	return r.detectLocation(ipAddress)
 }
 
+// LocateIP provides location information for the given IP-address.
+func (r *DBResolver) LocateIP(ipAddress string) (locationstate.Location, error) {
+	return r.detectLocation(ipAddress)
+}
+
 func (r *DBResolver) detectLocation(ipAddress string) (loc locationstate.Location, err error) {
 	log.Debug().Msg("Detecting with DB resolver")
 
 	ip := net.ParseIP(ipAddress)
 
+	r.dbLock.RLock()
 	countryRecord, err := r.dbReader.Country(ip)
+	r.dbLock.RUnlock()
 	if err != nil {
 		return loc, errors.Wrap(err, "failed to get a country")
 	}
@@ -89,3 +123,14 @@ func (r *DBResolver) detectLocation(ipAddress string) (loc locationstate.Locatio
 	loc.Country = country
 	return loc, nil
 }
+
+func (r *DBResolver) reload(dbReader *geoip2.Reader) {
+	r.dbLock.Lock()
+	old := r.dbReader
+	r.dbReader = dbReader
+	r.dbLock.Unlock()
+
+	if err := old.Close(); err != nil {
+		log.Warn().Err(err).Msg("Failed to close previous location database")
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package location
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oschwald/geoip2-golang"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+// maxDBSize limits the size of downloaded database, uncompressed city databases are about 70MB.
+const maxDBSize = 128 << 20
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// DBUpdater periodically downloads the country database used by offline resolver and reloads it.
+type DBUpdater struct {
+	httpClient *requests.HTTPClient
+	url        string
+	path       string
+	interval   time.Duration
+	resolver   *DBResolver
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDBUpdater returns a new database updater which keeps the database at the given path up to date.
+func NewDBUpdater(httpClient *requests.HTTPClient, url, path string, interval time.Duration, resolver *DBResolver) *DBUpdater {
+	return &DBUpdater{
+		httpClient: httpClient,
+		url:        url,
+		path:       path,
+		interval:   interval,
+		resolver:   resolver,
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start checks for database updates in the background. The first check is done
+// once the previously downloaded database becomes older than the update interval.
+func (u *DBUpdater) Start() {
+	delay := time.Duration(0)
+	if info, err := os.Stat(u.path); err == nil {
+		delay = u.interval - time.Since(info.ModTime())
+	}
+
+	go func() {
+		for {
+			select {
+			case <-u.stop:
+				return
+			case <-time.After(delay):
+			}
+
+			if err := u.Update(); err != nil {
+				log.Warn().Err(err).Msg("Failed to update location database")
+			}
+			delay = u.interval
+		}
+	}()
+}
+
+// Stop stops database updates.
+func (u *DBUpdater) Stop() {
+	u.stopOnce.Do(func() {
+		close(u.stop)
+	})
+}
+
+// Update downloads the database unless it was not modified since the last download,
+// validates it and replaces the one used by the resolver.
+func (u *DBUpdater) Update() error {
+	req, err := requests.NewGetRequest(u.url, "", nil)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(u.path)
+	if err == nil {
+		req.Header.Set("If-Modified-Since", info.ModTime().UTC().Format(http.TimeFormat))
+	}
+
+	res, err := u.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to download location database")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified {
+		log.Debug().Msg("Location database is up to date")
+		now := time.Now()
+		return os.Chtimes(u.path, now, now)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected location database response status: %d", res.StatusCode)
+	}
+
+	data, err := readDB(res.Body)
+	if err != nil {
+		return err
+	}
+
+	dbReader, err := geoip2.FromBytes(data)
+	if err != nil {
+		return errors.Wrap(err, "downloaded location database is invalid")
+	}
+	if _, err := dbReader.Country(net.ParseIP("8.8.8.8")); err != nil {
+		dbReader.Close()
+		return errors.Wrap(err, "downloaded location database has no country data")
+	}
+
+	tmpPath := u.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		dbReader.Close()
+		return errors.Wrap(err, "failed to save location database")
+	}
+	if err := os.Rename(tmpPath, u.path); err != nil {
+		dbReader.Close()
+		return errors.Wrap(err, "failed to save location database")
+	}
+
+	u.resolver.reload(dbReader)
+	log.Info().Msgf("Location database updated from %s", u.url)
+	return nil
+}
+
+func readDB(r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxDBSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download location database")
+	}
+	if len(data) > maxDBSize {
+		return nil, errors.New("location database is too large")
+	}
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decompress location database")
+	}
+	defer gz.Close()
+
+	data, err = ioutil.ReadAll(io.LimitReader(gz, maxDBSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decompress location database")
+	}
+	if len(data) > maxDBSize {
+		return nil, errors.New("location database is too large")
+	}
+	return data, nil
+}
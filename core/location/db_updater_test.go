@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package location
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/ip"
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+func TestDBUpdater_Update(t *testing.T) {
+	db, err := ioutil.ReadFile("db/GeoLite2-Country.mmdb")
+	require.NoError(t, err)
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err = gz.Write(db)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	tests := map[string][]byte{
+		"plain":      db,
+		"compressed": compressed.Bytes(),
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Write(body)
+			}))
+			defer server.Close()
+
+			path := filepath.Join(t.TempDir(), "location.mmdb")
+			resolver, err := NewOfflineResolver(path, ip.NewResolverMock("8.8.8.8"))
+			require.NoError(t, err)
+
+			updater := NewDBUpdater(requests.NewHTTPClient("127.0.0.1", requests.DefaultTimeout), server.URL, path, time.Hour, resolver)
+			require.NoError(t, updater.Update())
+
+			saved, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, db, saved)
+
+			loc, err := resolver.DetectLocation()
+			require.NoError(t, err)
+			assert.Equal(t, "US", loc.Country)
+		})
+	}
+}
+
+func TestDBUpdater_NotModified(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "location.mmdb")
+	db, err := ioutil.ReadFile("db/GeoLite2-Country.mmdb")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, db, 0600))
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	var ifModifiedSince string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifModifiedSince = r.Header.Get("If-Modified-Since")
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	resolver, err := NewOfflineResolver(path, ip.NewResolverMock("8.8.8.8"))
+	require.NoError(t, err)
+
+	updater := NewDBUpdater(requests.NewHTTPClient("127.0.0.1", requests.DefaultTimeout), server.URL, path, time.Hour, resolver)
+	require.NoError(t, updater.Update())
+
+	assert.Equal(t, old.UTC().Format(http.TimeFormat), ifModifiedSince)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().After(old))
+}
+
+func TestDBUpdater_KeepsDatabaseWhenDownloadIsInvalid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("not a database"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "location.mmdb")
+	resolver, err := NewOfflineResolver(path, ip.NewResolverMock("8.8.8.8"))
+	require.NoError(t, err)
+
+	updater := NewDBUpdater(requests.NewHTTPClient("127.0.0.1", requests.DefaultTimeout), server.URL, path, time.Hour, resolver)
+	assert.Error(t, updater.Update())
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	loc, err := resolver.DetectLocation()
+	require.NoError(t, err)
+	assert.Equal(t, "US", loc.Country)
+}
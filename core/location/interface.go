@@ -27,6 +27,11 @@ type Resolver interface {
 	DetectProxyLocation(proxyPort int) (locationstate.Location, error)
 }
 
+// IPLocator resolves location of the given IP address.
+type IPLocator interface {
+	LocateIP(ipAddress string) (locationstate.Location, error)
+}
+
 // OriginResolver fetches the original country
 type OriginResolver interface {
 	GetOrigin() locationstate.Location
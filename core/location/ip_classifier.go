@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package location
+
+import (
+	"strings"
+
+	"github.com/mysteriumnetwork/node/core/location/locationstate"
+)
+
+// IP types assigned by the classifier, matching the ones reported by location oracle.
+const (
+	IPTypeResidential = "residential"
+	IPTypeHosting     = "hosting"
+	IPTypeCellular    = "cellular"
+)
+
+// hostingASNs are autonomous systems of major cloud and hosting providers.
+var hostingASNs = []int{
+	16509,  // Amazon
+	14618,  // Amazon
+	15169,  // Google
+	396982, // Google Cloud
+	8075,   // Microsoft
+	14061,  // DigitalOcean
+	16276,  // OVH
+	24940,  // Hetzner
+	63949,  // Linode
+	20473,  // Vultr
+	51167,  // Contabo
+	45102,  // Alibaba
+	31898,  // Oracle
+	13335,  // Cloudflare
+	12876,  // Scaleway
+	60781,  // Leaseweb
+	28753,  // Leaseweb
+	9009,   // M247
+	36352,  // ColoCrossing
+	53667,  // FranTech
+	197540, // netcup
+}
+
+// cellularASNs are autonomous systems of mobile network operators.
+var cellularASNs = []int{
+	21928, // T-Mobile USA
+	22394, // Verizon Wireless
+	20057, // AT&T Mobility
+	3651,  // Sprint
+	12430, // Vodafone Spain
+	9644,  // SK Telecom
+	45609, // Bharti Airtel mobile
+	55836, // Reliance Jio
+}
+
+var hostingKeywords = []string{"hosting", "cloud", "data center", "datacenter", "server", "vps", "colocation", "colo "}
+
+var cellularKeywords = []string{"mobile", "wireless", "cellular", "gsm", "moviles"}
+
+// IPClassifier classifies IP addresses as residential, hosting or cellular by their ASN and ISP name.
+type IPClassifier struct {
+	hosting  map[int]struct{}
+	cellular map[int]struct{}
+}
+
+// NewIPClassifier returns a classifier using the built in ASN lists.
+func NewIPClassifier() *IPClassifier {
+	c := &IPClassifier{
+		hosting:  make(map[int]struct{}, len(hostingASNs)),
+		cellular: make(map[int]struct{}, len(cellularASNs)),
+	}
+	for _, asn := range hostingASNs {
+		c.hosting[asn] = struct{}{}
+	}
+	for _, asn := range cellularASNs {
+		c.cellular[asn] = struct{}{}
+	}
+	return c
+}
+
+// Classify returns IP type of the location, or empty string when neither ASN nor ISP are known.
+func (c *IPClassifier) Classify(loc locationstate.Location) string {
+	if loc.ASN == 0 && loc.ISP == "" {
+		return ""
+	}
+	if _, ok := c.hosting[loc.ASN]; ok {
+		return IPTypeHosting
+	}
+	if _, ok := c.cellular[loc.ASN]; ok {
+		return IPTypeCellular
+	}
+
+	isp := strings.ToLower(loc.ISP)
+	if containsAny(isp, cellularKeywords) {
+		return IPTypeCellular
+	}
+	if containsAny(isp, hostingKeywords) {
+		return IPTypeHosting
+	}
+	return IPTypeResidential
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, substr := range substrings {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassifyingResolver fills in IP type of locations which the underlying resolver could not classify.
+type ClassifyingResolver struct {
+	resolver   Resolver
+	classifier *IPClassifier
+}
+
+// NewClassifyingResolver returns a resolver classifying IP type of locations resolved by the given resolver.
+func NewClassifyingResolver(resolver Resolver, classifier *IPClassifier) *ClassifyingResolver {
+	return &ClassifyingResolver{
+		resolver:   resolver,
+		classifier: classifier,
+	}
+}
+
+// DetectLocation detects current location and classifies its IP type.
+func (r *ClassifyingResolver) DetectLocation() (locationstate.Location, error) {
+	loc, err := r.resolver.DetectLocation()
+	return r.classify(loc), err
+}
+
+// DetectProxyLocation detects proxy location and classifies its IP type.
+func (r *ClassifyingResolver) DetectProxyLocation(proxyPort int) (locationstate.Location, error) {
+	loc, err := r.resolver.DetectProxyLocation(proxyPort)
+	return r.classify(loc), err
+}
+
+func (r *ClassifyingResolver) classify(loc locationstate.Location) locationstate.Location {
+	if loc.IPType == "" {
+		loc.IPType = r.classifier.Classify(loc)
+	}
+	return loc
+}
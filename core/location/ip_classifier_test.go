@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package location
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/ip"
+	"github.com/mysteriumnetwork/node/core/location/locationstate"
+)
+
+func TestIPClassifier_Classify(t *testing.T) {
+	tests := map[string]struct {
+		loc  locationstate.Location
+		want string
+	}{
+		"unknown":               {loc: locationstate.Location{Country: "LT"}, want: ""},
+		"hosting ASN":           {loc: locationstate.Location{ASN: 16509, ISP: "Amazon.com, Inc."}, want: IPTypeHosting},
+		"cellular ASN":          {loc: locationstate.Location{ASN: 21928}, want: IPTypeCellular},
+		"cellular by ISP name":  {loc: locationstate.Location{ASN: 1, ISP: "China Mobile"}, want: IPTypeCellular},
+		"hosting by ISP name":   {loc: locationstate.Location{ASN: 1, ISP: "Example Hosting Ltd"}, want: IPTypeHosting},
+		"residential":           {loc: locationstate.Location{ASN: 62179, ISP: "Telia Lietuva, AB"}, want: IPTypeResidential},
+		"residential ASN alone": {loc: locationstate.Location{ASN: 62179}, want: IPTypeResidential},
+	}
+
+	classifier := NewIPClassifier()
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, classifier.Classify(test.loc))
+		})
+	}
+}
+
+type mockASNResolver struct {
+	loc locationstate.Location
+	err error
+}
+
+func (r *mockASNResolver) DetectLocation() (locationstate.Location, error) {
+	return r.loc, r.err
+}
+
+func (r *mockASNResolver) DetectProxyLocation(_ int) (locationstate.Location, error) {
+	return r.loc, r.err
+}
+
+func TestClassifyingResolver(t *testing.T) {
+	classifier := NewIPClassifier()
+
+	resolver := NewClassifyingResolver(&mockASNResolver{loc: locationstate.Location{ASN: 14061}}, classifier)
+	loc, err := resolver.DetectLocation()
+	assert.NoError(t, err)
+	assert.Equal(t, IPTypeHosting, loc.IPType)
+
+	loc, err = resolver.DetectProxyLocation(1234)
+	assert.NoError(t, err)
+	assert.Equal(t, IPTypeHosting, loc.IPType)
+
+	// IP type reported by the underlying resolver is kept.
+	resolver = NewClassifyingResolver(NewStaticResolver("LT", "Vilnius", IPTypeResidential, ip.NewResolverMock("1.2.3.4")), classifier)
+	loc, err = resolver.DetectLocation()
+	assert.NoError(t, err)
+	assert.Equal(t, IPTypeResidential, loc.IPType)
+
+	resolver = NewClassifyingResolver(&mockASNResolver{err: errors.New("failed")}, classifier)
+	loc, err = resolver.DetectLocation()
+	assert.Error(t, err)
+	assert.Empty(t, loc.IPType)
+}
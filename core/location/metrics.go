@@ -0,0 +1,65 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package location
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/mysteriumnetwork/node/metrics"
+)
+
+type prefixCacheCollector struct {
+	stats func() PrefixCacheStats
+
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	evictions *prometheus.Desc
+	size      *prometheus.Desc
+}
+
+// NewPrefixCacheCollector exposes location prefix cache statistics as metrics.
+func NewPrefixCacheCollector(stats func() PrefixCacheStats) prometheus.Collector {
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "location_cache", metric), help, nil, nil)
+	}
+
+	return &prefixCacheCollector{
+		stats:     stats,
+		hits:      desc("hits_total", "Total number of location lookups served from cache."),
+		misses:    desc("misses_total", "Total number of location lookups passed to the resolver."),
+		evictions: desc("evictions_total", "Total number of prefixes evicted from a full cache."),
+		size:      desc("prefixes", "Number of cached prefixes."),
+	}
+}
+
+// Describe sends descriptors of the cache metrics.
+func (c *prefixCacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.evictions
+	ch <- c.size
+}
+
+// Collect sends current values of the cache metrics.
+func (c *prefixCacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions))
+	ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(stats.Size))
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package location
+
+import (
+	"container/list"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/ip"
+	"github.com/mysteriumnetwork/node/core/location/locationstate"
+)
+
+const (
+	ipv4PrefixBits = 24
+	ipv6PrefixBits = 48
+)
+
+// PrefixCacheConfig configures the location prefix cache.
+type PrefixCacheConfig struct {
+	// Size is a maximum number of cached prefixes.
+	Size int
+	// TTL is a time successful lookups are kept for.
+	TTL time.Duration
+	// NegativeTTL is a time failed lookups are kept for.
+	NegativeTTL time.Duration
+}
+
+// DefaultPrefixCacheConfig returns default prefix cache configuration.
+func DefaultPrefixCacheConfig() PrefixCacheConfig {
+	return PrefixCacheConfig{
+		Size:        4096,
+		TTL:         6 * time.Hour,
+		NegativeTTL: 5 * time.Minute,
+	}
+}
+
+// PrefixCacheStats represents prefix cache metrics.
+type PrefixCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int
+}
+
+// HitRate returns a share of lookups served from cache.
+func (s PrefixCacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type prefixEntry struct {
+	prefix    string
+	location  locationstate.Location
+	err       error
+	expiresAt time.Time
+}
+
+// PrefixCache is an LRU cache of location lookups keyed by /24 IPv4 and /48 IPv6 prefixes.
+// Failed lookups are cached for a shorter time to avoid hammering the underlying resolver.
+type PrefixCache struct {
+	resolver   Resolver
+	ipResolver ip.Resolver
+	config     PrefixCacheConfig
+	now        func() time.Time
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	stats   PrefixCacheStats
+}
+
+// NewPrefixCache returns a new instance of prefix cache.
+// Lookups of the resolver are keyed by public or proxy IP reported by ipResolver.
+func NewPrefixCache(resolver Resolver, ipResolver ip.Resolver, config PrefixCacheConfig) *PrefixCache {
+	return &PrefixCache{
+		resolver:   resolver,
+		ipResolver: ipResolver,
+		config:     config,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// DetectLocation detects current IP-address and provides location information for it.
+func (pc *PrefixCache) DetectLocation() (locationstate.Location, error) {
+	ipAddress, err := pc.ipResolver.GetPublicIP()
+	if err != nil {
+		return locationstate.Location{}, errors.Wrap(err, "failed to get public IP")
+	}
+
+	return pc.lookup(ipAddress, pc.resolver.DetectLocation)
+}
+
+// DetectProxyLocation detects proxy IP-address and provides location information for it.
+func (pc *PrefixCache) DetectProxyLocation(proxyPort int) (locationstate.Location, error) {
+	ipAddress, err := pc.ipResolver.GetProxyIP(proxyPort)
+	if err != nil {
+		return locationstate.Location{}, errors.Wrap(err, "failed to get proxy IP")
+	}
+
+	return pc.lookup(ipAddress, func() (locationstate.Location, error) {
+		return pc.resolver.DetectProxyLocation(proxyPort)
+	})
+}
+
+// LocateIP returns location of the given IP-address, from cache if its prefix was resolved recently.
+// It fails if the underlying resolver can't locate arbitrary addresses.
+func (pc *PrefixCache) LocateIP(ipAddress string) (locationstate.Location, error) {
+	locator, ok := pc.resolver.(IPLocator)
+	if !ok {
+		return locationstate.Location{}, errors.New("location resolver can't locate the given IP")
+	}
+
+	return pc.lookup(ipAddress, func() (locationstate.Location, error) {
+		return locator.LocateIP(ipAddress)
+	})
+}
+
+func (pc *PrefixCache) lookup(ipAddress string, resolve func() (locationstate.Location, error)) (locationstate.Location, error) {
+	prefix, ok := ipPrefix(ipAddress)
+	if !ok {
+		return locationstate.Location{}, errors.Errorf("invalid IP address: %s", ipAddress)
+	}
+
+	if entry, ok := pc.get(prefix); ok {
+		loc := entry.location
+		loc.IP = ipAddress
+		return loc, entry.err
+	}
+
+	loc, err := resolve()
+	pc.put(prefix, loc, err)
+	return loc, err
+}
+
+// Stats returns cache metrics.
+func (pc *PrefixCache) Stats() PrefixCacheStats {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	stats := pc.stats
+	stats.Size = pc.order.Len()
+	return stats
+}
+
+func (pc *PrefixCache) get(prefix string) (prefixEntry, bool) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	el, ok := pc.entries[prefix]
+	if ok {
+		entry := el.Value.(*prefixEntry)
+		if pc.now().Before(entry.expiresAt) {
+			pc.order.MoveToFront(el)
+			pc.stats.Hits++
+			return *entry, true
+		}
+		pc.remove(el)
+	}
+
+	pc.stats.Misses++
+	return prefixEntry{}, false
+}
+
+func (pc *PrefixCache) put(prefix string, loc locationstate.Location, err error) {
+	ttl := pc.config.TTL
+	if err != nil {
+		ttl = pc.config.NegativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	if el, ok := pc.entries[prefix]; ok {
+		pc.remove(el)
+	}
+	pc.entries[prefix] = pc.order.PushFront(&prefixEntry{
+		prefix:    prefix,
+		location:  loc,
+		err:       err,
+		expiresAt: pc.now().Add(ttl),
+	})
+
+	for pc.config.Size > 0 && pc.order.Len() > pc.config.Size {
+		pc.remove(pc.order.Back())
+		pc.stats.Evictions++
+	}
+
+	log.Trace().Msgf("Location prefix cache hit rate: %.2f", pc.stats.HitRate())
+}
+
+func (pc *PrefixCache) remove(el *list.Element) {
+	pc.order.Remove(el)
+	delete(pc.entries, el.Value.(*prefixEntry).prefix)
+}
+
+func ipPrefix(ipAddress string) (string, bool) {
+	parsed := net.ParseIP(ipAddress)
+	if parsed == nil {
+		return "", false
+	}
+
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(ipv4PrefixBits, 32)).String() + "/24", true
+	}
+	return parsed.Mask(net.CIDRMask(ipv6PrefixBits, 128)).String() + "/48", true
+}
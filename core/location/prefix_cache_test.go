@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package location
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/ip"
+	"github.com/mysteriumnetwork/node/core/location/locationstate"
+)
+
+type mockIPLocator struct {
+	calls int
+	err   error
+}
+
+func (m *mockIPLocator) DetectLocation() (locationstate.Location, error) {
+	return m.LocateIP("")
+}
+
+func (m *mockIPLocator) DetectProxyLocation(_ int) (locationstate.Location, error) {
+	return m.LocateIP("")
+}
+
+func (m *mockIPLocator) LocateIP(ipAddress string) (locationstate.Location, error) {
+	m.calls++
+	if m.err != nil {
+		return locationstate.Location{}, m.err
+	}
+	return locationstate.Location{IP: ipAddress, Country: "LT"}, nil
+}
+
+func TestPrefixCache_LocateIP(t *testing.T) {
+	locator := &mockIPLocator{}
+	cache := NewPrefixCache(locator, nil, PrefixCacheConfig{Size: 2, TTL: time.Hour, NegativeTTL: time.Minute})
+
+	loc, err := cache.LocateIP("1.2.3.4")
+	assert.NoError(t, err)
+	assert.Equal(t, "LT", loc.Country)
+
+	// same /24 is served from cache
+	loc, err = cache.LocateIP("1.2.3.200")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3.200", loc.IP)
+	assert.Equal(t, 1, locator.calls)
+
+	// same /48 is served from cache
+	_, _ = cache.LocateIP("2001:db8:1::1")
+	_, _ = cache.LocateIP("2001:db8:1:ffff::2")
+	assert.Equal(t, 2, locator.calls)
+
+	// least recently used prefix is evicted
+	_, _ = cache.LocateIP("5.6.7.8")
+	_, _ = cache.LocateIP("2001:db8:1::3")
+	_, _ = cache.LocateIP("1.2.3.4")
+	assert.Equal(t, 4, locator.calls)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(3), stats.Hits)
+	assert.Equal(t, uint64(4), stats.Misses)
+	assert.Equal(t, uint64(2), stats.Evictions)
+	assert.Equal(t, 2, stats.Size)
+	assert.InDelta(t, 3.0/7.0, stats.HitRate(), 0.001)
+}
+
+func TestPrefixCache_NegativeCaching(t *testing.T) {
+	now := time.Now()
+	locator := &mockIPLocator{err: errors.New("boom")}
+	cache := NewPrefixCache(locator, nil, PrefixCacheConfig{Size: 10, TTL: time.Hour, NegativeTTL: time.Minute})
+	cache.now = func() time.Time { return now }
+
+	_, err := cache.LocateIP("1.2.3.4")
+	assert.Error(t, err)
+	_, err = cache.LocateIP("1.2.3.5")
+	assert.Error(t, err)
+	assert.Equal(t, 1, locator.calls)
+
+	locator.err = nil
+	now = now.Add(2 * time.Minute)
+	loc, err := cache.LocateIP("1.2.3.5")
+	assert.NoError(t, err)
+	assert.Equal(t, "LT", loc.Country)
+	assert.Equal(t, 2, locator.calls)
+
+	_, err = cache.LocateIP("invalid")
+	assert.Error(t, err)
+}
+
+func TestPrefixCache_DetectLocation(t *testing.T) {
+	resolver := &mockIPLocator{}
+	ipResolver := ip.NewResolverMockMultiple("", "1.2.3.4", "1.2.3.5", "1.2.3.6")
+	cache := NewPrefixCache(resolver, ipResolver, DefaultPrefixCacheConfig())
+
+	loc, err := cache.DetectLocation()
+	assert.NoError(t, err)
+	assert.Equal(t, "LT", loc.Country)
+
+	loc, err = cache.DetectLocation()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3.5", loc.IP)
+	assert.Equal(t, 1, resolver.calls)
+
+	_, err = NewPrefixCache(&StaticResolver{}, ipResolver, DefaultPrefixCacheConfig()).LocateIP("1.2.3.4")
+	assert.Error(t, err)
+}
@@ -81,11 +81,20 @@ type Options struct {
 	Payments OptionsPayments
 
 	Consumer bool
+	// Mobile marks node running on a mobile device, its proposals are advertised as mobile.
+	Mobile bool
+	// Embedded marks node running inside another program which owns the process,
+	// so the node binary is neither updated nor restarted.
+	Embedded bool
 
 	SwarmDialerDNSHeadstart time.Duration
 	PilvytisAddress         string
 	ObserverAddress         string
 	SSE                     OptionsSSE
+	ProposalsFeed           OptionsProposalsFeed
+	Capacity                OptionsCapacity
+	Monitoring              OptionsMonitoring
+	RemoteManagement        OptionsRemoteManagement
 }
 
 // GetOptions retrieves node options from the app configuration.
@@ -125,14 +134,27 @@ func GetOptions() *Options {
 		SwarmDialerDNSHeadstart: config.GetDuration(config.FlagDNSResolutionHeadstart),
 		FeedbackURL:             config.GetString(config.FlagFeedbackURL),
 		Keystore: OptionsKeystore{
-			UseLightweight: config.GetBool(config.FlagKeystoreLightweight),
+			UseLightweight:      config.GetBool(config.FlagKeystoreLightweight),
+			Keychain:            config.GetBool(config.FlagKeystoreKeychain),
+			HardwareWallet:      config.GetString(config.FlagKeystoreHardwareWallet),
+			HardwareWalletPaths: config.GetStringSlice(config.FlagKeystoreHardwareWalletPaths),
+			RemoteSigner: OptionsRemoteSigner{
+				Address:    config.GetString(config.FlagKeystoreRemoteSigner),
+				CACert:     config.GetString(config.FlagKeystoreRemoteSignerCA),
+				ClientCert: config.GetString(config.FlagKeystoreRemoteSignerCert),
+				ClientKey:  config.GetString(config.FlagKeystoreRemoteSignerKey),
+				Timeout:    config.GetDuration(config.FlagKeystoreRemoteSignerTimeout),
+			},
 		},
 		LogOptions:     *GetLogOptions(),
 		OptionsNetwork: network,
 		Discovery:      *GetDiscoveryOptions(),
 		Quality: OptionsQuality{
-			Type:    QualityType(config.GetString(config.FlagQualityType)),
-			Address: config.GetString(config.FlagQualityAddress),
+			Type:            QualityType(config.GetString(config.FlagQualityType)),
+			Address:         config.GetString(config.FlagQualityAddress),
+			RefreshInterval: config.GetDuration(config.FlagQualityRefreshInterval),
+			ReportURL:       config.GetString(config.FlagQualityReportURL),
+			ReportInterval:  config.GetDuration(config.FlagQualityReportInterval),
 		},
 		Location: OptionsLocation{
 			IPDetectorURL: config.GetString(config.FlagIPDetectorURL),
@@ -141,6 +163,9 @@ func GetOptions() *Options {
 			Country:       config.GetString(config.FlagLocationCountry),
 			City:          config.GetString(config.FlagLocationCity),
 			IPType:        config.GetString(config.FlagLocationIPType),
+
+			DBUpdateURL:      config.GetString(config.FlagLocationDBUpdateURL),
+			DBUpdateInterval: config.GetDuration(config.FlagLocationDBUpdateInterval),
 		},
 		Transactor: OptionsTransactor{
 			TransactorEndpointAddress:       config.GetString(config.FlagTransactorAddress),
@@ -165,12 +190,18 @@ func GetOptions() *Options {
 			RegistryTransactorPollTimeout:  config.GetDuration(config.FlagPaymentsRegistryTransactorPollTimeout),
 			ConsumerDataLeewayMegabytes:    config.GetUInt64(config.FlagPaymentsConsumerDataLeewayMegabytes),
 			HermesStatusRecheckInterval:    config.GetDuration(config.FlagPaymentsHermesStatusRecheckInterval),
+			HermesMinChannelSize:           config.GetBigInt(config.FlagPaymentsHermesMinChannelSize),
 			MinAutoSettleAmount:            config.GetFloat64(config.FlagPaymentsZeroStakeUnsettledAmount),
 
 			ProviderInvoiceFrequency:      config.GetDuration(config.FlagPaymentsProviderInvoiceFrequency),
 			ProviderLimitInvoiceFrequency: config.GetDuration(config.FlagPaymentsLimitProviderInvoiceFrequency),
 			MaxUnpaidInvoiceValue:         config.GetBigInt(config.FlagPaymentsUnpaidInvoiceValue),
 			LimitUnpaidInvoiceValue:       config.GetBigInt(config.FlagPaymentsLimitUnpaidInvoiceValue),
+
+			PromptPaymentLatency:    config.GetDuration(config.FlagPaymentsPromptPaymentLatency),
+			TrustedConsumerPayments: config.GetInt(config.FlagPaymentsTrustedConsumerPayments),
+
+			ConsumerSessionKeyTTL: config.GetDuration(config.FlagPaymentsConsumerSessionKeyTTL),
 		},
 		Chains: OptionsChains{
 			Chain1: metadata.ChainDefinition{
@@ -202,6 +233,37 @@ func GetOptions() *Options {
 		SSE: OptionsSSE{
 			Enabled: config.GetBool(config.FlagSSEEnable),
 		},
+		ProposalsFeed: OptionsProposalsFeed{
+			Targets:     config.GetStringSlice(config.FlagProposalsFeedTargets),
+			Interval:    config.GetDuration(config.FlagProposalsFeedInterval),
+			ServiceType: config.GetString(config.FlagProposalsFeedServiceType),
+			Country:     config.GetString(config.FlagProposalsFeedCountry),
+			QualityMin:  config.GetFloat64(config.FlagProposalsFeedQualityMin),
+			S3Endpoint:  config.GetString(config.FlagProposalsFeedS3Endpoint),
+			S3Region:    config.GetString(config.FlagProposalsFeedS3Region),
+		},
+		Capacity: OptionsCapacity{
+			DownloadURLs:      config.GetStringSlice(config.FlagCapacityDownloadURLs),
+			UploadURLs:        config.GetStringSlice(config.FlagCapacityUploadURLs),
+			SpeedTestInterval: config.GetDuration(config.FlagCapacitySpeedTestInterval),
+		},
+		Monitoring: OptionsMonitoring{
+			Interval:        config.GetDuration(config.FlagMonitoringInterval),
+			CPUPercent:      config.GetFloat64(config.FlagMonitoringCPUThreshold),
+			MemoryBytes:     config.GetUInt64(config.FlagMonitoringMemoryThreshold) * 1024 * 1024,
+			FileDescriptors: config.GetInt(config.FlagMonitoringFDThreshold),
+			Goroutines:      config.GetInt(config.FlagMonitoringGoroutineThreshold),
+		},
+		RemoteManagement: OptionsRemoteManagement{
+			Enabled:      config.GetBool(config.FlagRemoteManagementEnabled),
+			Address:      config.GetString(config.FlagRemoteManagementAddress),
+			Port:         config.GetInt(config.FlagRemoteManagementPort),
+			CertFile:     config.GetString(config.FlagRemoteManagementCertFile),
+			KeyFile:      config.GetString(config.FlagRemoteManagementKeyFile),
+			ACMEDomain:   config.GetString(config.FlagRemoteManagementACMEDomain),
+			ClientCAFile: config.GetString(config.FlagRemoteManagementClientCA),
+			AllowedIPs:   config.GetStringSlice(config.FlagRemoteManagementAllowedIPs),
+		},
 	}
 }
 
@@ -236,7 +298,10 @@ func GetDiscoveryOptions() *OptionsDiscovery {
 		PingInterval:  config.GetDuration(config.FlagDiscoveryPingInterval),
 		FetchEnabled:  true,
 		FetchInterval: config.GetDuration(config.FlagDiscoveryFetchInterval),
+		CacheTTL:      config.GetDuration(config.FlagDiscoveryCacheTTL),
 		DHT:           *GetDHTOptions(),
+
+		RequireSignedProposals: config.GetBool(config.FlagDiscoveryRequireSignedProposals),
 	}
 }
 
@@ -253,4 +318,20 @@ func GetDHTOptions() *OptionsDHT {
 // OptionsKeystore stores the keystore configuration
 type OptionsKeystore struct {
 	UseLightweight bool
+	// Keychain keeps identity passphrases in the platform keychain to unlock identities on boot.
+	Keychain bool
+	// HardwareWallet selects hardware wallet type, hardware signing is disabled when empty.
+	HardwareWallet      string
+	HardwareWalletPaths []string
+	// RemoteSigner moves signing to the remote service, it is disabled when address is empty.
+	RemoteSigner OptionsRemoteSigner
+}
+
+// OptionsRemoteSigner stores the remote signing service configuration.
+type OptionsRemoteSigner struct {
+	Address    string
+	CACert     string
+	ClientCert string
+	ClientKey  string
+	Timeout    time.Duration
 }
@@ -38,7 +38,10 @@ type OptionsDiscovery struct {
 	PingInterval  time.Duration
 	FetchEnabled  bool
 	FetchInterval time.Duration
+	CacheTTL      time.Duration
 	DHT           OptionsDHT
+
+	RequireSignedProposals bool
 }
 
 // OptionsDHT describes possible parameters of DHT configuration.
@@ -17,13 +17,15 @@
 
 package node
 
+import "time"
+
 // LocationType identifies location type
 type LocationType string
 
 const (
 	// LocationTypeManual defines type which resolves location from manually entered values
 	LocationTypeManual = LocationType("manual")
-	// LocationTypeBuiltin defines type which resolves location from built in DB
+	// LocationTypeBuiltin defines type which resolves location from built in DB, or the periodically downloaded one
 	LocationTypeBuiltin = LocationType("builtin")
 	// LocationTypeMMDB defines type which resolves location from given MMDB file
 	LocationTypeMMDB = LocationType("mmdb")
@@ -40,4 +42,8 @@ type OptionsLocation struct {
 	Country string
 	City    string
 	IPType  string
+
+	// DBUpdateURL is the URL of country database used by builtin resolver, updates are disabled when empty.
+	DBUpdateURL      string
+	DBUpdateInterval time.Duration
 }
@@ -32,6 +32,7 @@ type OptionsPayments struct {
 	SettlementRecheckInterval      time.Duration
 	ConsumerDataLeewayMegabytes    uint64
 	HermesStatusRecheckInterval    time.Duration
+	HermesMinChannelSize           *big.Int
 	BalanceFastPollInterval        time.Duration
 	BalanceFastPollTimeout         time.Duration
 	BalanceLongPollInterval        time.Duration
@@ -45,4 +46,9 @@ type OptionsPayments struct {
 
 	MaxUnpaidInvoiceValue   *big.Int
 	LimitUnpaidInvoiceValue *big.Int
+
+	PromptPaymentLatency    time.Duration
+	TrustedConsumerPayments int
+
+	ConsumerSessionKeyTTL time.Duration
 }
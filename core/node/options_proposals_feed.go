@@ -0,0 +1,34 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package node
+
+import "time"
+
+// OptionsProposalsFeed describes export of proposals to a static feed.
+type OptionsProposalsFeed struct {
+	// Targets are directories or "s3://bucket/prefix" URLs, export is disabled when empty.
+	Targets  []string
+	Interval time.Duration
+
+	ServiceType string
+	Country     string
+	QualityMin  float64
+
+	S3Endpoint string
+	S3Region   string
+}
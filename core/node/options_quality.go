@@ -17,6 +17,8 @@
 
 package node
 
+import "time"
+
 // QualityType identifies Quality Oracle provider
 type QualityType string
 
@@ -33,4 +35,9 @@ const (
 type OptionsQuality struct {
 	Type    QualityType
 	Address string
+	// RefreshInterval is how often proposal quality scores are re-fetched, zero disables refresh.
+	RefreshInterval time.Duration
+	// ReportURL is the collector of session quality samples, empty disables reporting.
+	ReportURL      string
+	ReportInterval time.Duration
 }
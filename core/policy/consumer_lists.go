@@ -0,0 +1,327 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package policy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+const (
+	// ConsumerAllowlist lists the only consumer identities allowed to start sessions, when it's not empty.
+	ConsumerAllowlist = "allow"
+	// ConsumerBlocklist lists consumer identities which are not allowed to start sessions.
+	ConsumerBlocklist = "block"
+
+	// DefaultConsumerListsUpdateInterval is how often remote consumer lists are downloaded again.
+	DefaultConsumerListsUpdateInterval = time.Hour
+
+	consumerListMaxSize = 8 << 20
+)
+
+// ConsumerListsState describes local and remote consumer identity lists.
+type ConsumerListsState struct {
+	Allow         []string
+	Block         []string
+	RemoteAllow   int
+	RemoteBlock   int
+	Sources       map[string][]string
+	UpdatedAt     time.Time
+	LastUpdateErr string
+}
+
+// ConsumerLists decides which consumer identities may start sessions with the provider.
+// Local lists are managed through the API and persisted, remote ones are loaded from URLs or files
+// with an identity per line and reloaded periodically.
+type ConsumerLists struct {
+	file    string
+	sources map[string][]string
+	client  *http.Client
+
+	mu        sync.RWMutex
+	local     map[string]map[string]struct{}
+	remote    map[string]map[string]struct{}
+	updatedAt time.Time
+	updateErr string
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewConsumerLists returns consumer lists keeping local lists in the given directory
+// and loading remote ones from the given allowlist and blocklist sources.
+func NewConsumerLists(dir string, allowSources, blockSources []string, client *http.Client) (*ConsumerLists, error) {
+	l := &ConsumerLists{
+		file:    filepath.Join(dir, "consumer-lists.json"),
+		sources: map[string][]string{ConsumerAllowlist: allowSources, ConsumerBlocklist: blockSources},
+		client:  client,
+		local:   newConsumerListSets(),
+		remote:  newConsumerListSets(),
+		stop:    make(chan struct{}),
+	}
+	if err := l.read(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func newConsumerListSets() map[string]map[string]struct{} {
+	return map[string]map[string]struct{}{
+		ConsumerAllowlist: make(map[string]struct{}),
+		ConsumerBlocklist: make(map[string]struct{}),
+	}
+}
+
+// Start loads remote lists and keeps reloading them with the given interval until stopped.
+func (l *ConsumerLists) Start(interval time.Duration) {
+	if len(l.sources[ConsumerAllowlist])+len(l.sources[ConsumerBlocklist]) == 0 {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultConsumerListsUpdateInterval
+	}
+
+	go func() {
+		for {
+			if err := l.Update(); err != nil {
+				log.Warn().Err(err).Msg("Failed to update consumer lists")
+			}
+
+			select {
+			case <-l.stop:
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+// Stop stops periodic remote list updates.
+func (l *ConsumerLists) Stop() {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+}
+
+// Update downloads remote lists. A list keeps its previous content if any of its sources fails,
+// so a temporarily unreachable blocklist doesn't let blocked consumers in.
+func (l *ConsumerLists) Update() error {
+	remote := newConsumerListSets()
+	failed := make(map[string]bool)
+	var errs []string
+	for list, sources := range l.sources {
+		for _, source := range sources {
+			if err := l.load(source, remote[list]); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %s", source, err))
+				failed[list] = true
+			}
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for list := range l.sources {
+		if !failed[list] {
+			l.remote[list] = remote[list]
+		}
+	}
+	l.updatedAt = time.Now()
+	l.updateErr = strings.Join(errs, "; ")
+	if len(errs) > 0 {
+		return fmt.Errorf("could not load consumer lists: %s", l.updateErr)
+	}
+	return nil
+}
+
+func (l *ConsumerLists) load(source string, identities map[string]struct{}) error {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		file, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		return ParseConsumerList(file, identities)
+	}
+
+	resp, err := l.client.Get(source)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return ParseConsumerList(io.LimitReader(resp.Body, consumerListMaxSize), identities)
+}
+
+// ParseConsumerList reads identities listed one per line into the given set. Text after "#" is ignored.
+func ParseConsumerList(r io.Reader, identities map[string]struct{}) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !common.IsHexAddress(line) {
+			return fmt.Errorf("invalid identity %q", line)
+		}
+		identities[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// IsIdentityAllowed checks that the consumer is not blocklisted and, if any allowlist is set, that it is allowlisted.
+func (l *ConsumerLists) IsIdentityAllowed(id identity.Identity) bool {
+	address := strings.ToLower(id.Address)
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.listed(ConsumerBlocklist, address) {
+		return false
+	}
+	if len(l.local[ConsumerAllowlist])+len(l.remote[ConsumerAllowlist]) == 0 {
+		return true
+	}
+	return l.listed(ConsumerAllowlist, address)
+}
+
+func (l *ConsumerLists) listed(list, address string) bool {
+	if _, ok := l.local[list][address]; ok {
+		return true
+	}
+	_, ok := l.remote[list][address]
+	return ok
+}
+
+// Add adds the identity to the local list.
+func (l *ConsumerLists) Add(list, address string) error {
+	return l.modify(list, address, func(set map[string]struct{}, address string) {
+		set[address] = struct{}{}
+	})
+}
+
+// Remove removes the identity from the local list.
+func (l *ConsumerLists) Remove(list, address string) error {
+	return l.modify(list, address, func(set map[string]struct{}, address string) {
+		delete(set, address)
+	})
+}
+
+func (l *ConsumerLists) modify(list, address string, fn func(map[string]struct{}, string)) error {
+	if list != ConsumerAllowlist && list != ConsumerBlocklist {
+		return fmt.Errorf("unknown consumer list %q", list)
+	}
+	if !common.IsHexAddress(address) {
+		return fmt.Errorf("invalid identity %q", address)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fn(l.local[list], strings.ToLower(address))
+	return l.write()
+}
+
+// State returns local lists and the size of remote ones.
+func (l *ConsumerLists) State() ConsumerListsState {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return ConsumerListsState{
+		Allow:         sortedIdentities(l.local[ConsumerAllowlist]),
+		Block:         sortedIdentities(l.local[ConsumerBlocklist]),
+		RemoteAllow:   len(l.remote[ConsumerAllowlist]),
+		RemoteBlock:   len(l.remote[ConsumerBlocklist]),
+		Sources:       l.sources,
+		UpdatedAt:     l.updatedAt,
+		LastUpdateErr: l.updateErr,
+	}
+}
+
+func sortedIdentities(set map[string]struct{}) []string {
+	identities := make([]string, 0, len(set))
+	for address := range set {
+		identities = append(identities, address)
+	}
+	sort.Strings(identities)
+	return identities
+}
+
+type storedConsumerLists struct {
+	Allow []string `json:"allow"`
+	Block []string `json:"block"`
+}
+
+func (l *ConsumerLists) read() error {
+	data, err := os.ReadFile(l.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read consumer lists: %w", err)
+	}
+
+	var stored storedConsumerLists
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("could not parse consumer lists: %w", err)
+	}
+	for _, address := range stored.Allow {
+		l.local[ConsumerAllowlist][strings.ToLower(address)] = struct{}{}
+	}
+	for _, address := range stored.Block {
+		l.local[ConsumerBlocklist][strings.ToLower(address)] = struct{}{}
+	}
+	return nil
+}
+
+func (l *ConsumerLists) write() error {
+	data, err := json.MarshalIndent(storedConsumerLists{
+		Allow: sortedIdentities(l.local[ConsumerAllowlist]),
+		Block: sortedIdentities(l.local[ConsumerBlocklist]),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(l.file, data, 0600); err != nil {
+		return fmt.Errorf("could not write consumer lists: %w", err)
+	}
+	return nil
+}
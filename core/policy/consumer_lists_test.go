@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package policy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+const (
+	consumerA = "0x000000000000000000000000000000000000000a"
+	consumerB = "0x000000000000000000000000000000000000000b"
+)
+
+func TestConsumerLists_Local(t *testing.T) {
+	dir := t.TempDir()
+	lists, err := NewConsumerLists(dir, nil, nil, http.DefaultClient)
+	assert.NoError(t, err)
+
+	assert.True(t, lists.IsIdentityAllowed(identity.FromAddress(consumerA)))
+
+	assert.NoError(t, lists.Add(ConsumerBlocklist, consumerA))
+	assert.False(t, lists.IsIdentityAllowed(identity.FromAddress(consumerA)))
+	assert.True(t, lists.IsIdentityAllowed(identity.FromAddress(consumerB)))
+
+	assert.NoError(t, lists.Add(ConsumerAllowlist, "0x000000000000000000000000000000000000000C"))
+	assert.False(t, lists.IsIdentityAllowed(identity.FromAddress(consumerB)))
+	assert.True(t, lists.IsIdentityAllowed(identity.FromAddress("0x000000000000000000000000000000000000000c")))
+
+	assert.Error(t, lists.Add("unknown", consumerA))
+	assert.Error(t, lists.Add(ConsumerBlocklist, "0x1"))
+
+	reloaded, err := NewConsumerLists(dir, nil, nil, http.DefaultClient)
+	assert.NoError(t, err)
+	state := reloaded.State()
+	assert.Equal(t, []string{"0x000000000000000000000000000000000000000c"}, state.Allow)
+	assert.Equal(t, []string{consumerA}, state.Block)
+
+	assert.NoError(t, reloaded.Remove(ConsumerAllowlist, "0x000000000000000000000000000000000000000c"))
+	assert.True(t, reloaded.IsIdentityAllowed(identity.FromAddress(consumerB)))
+}
+
+func TestConsumerLists_Remote(t *testing.T) {
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, "# blocked consumers\n%s\n\n%s # abuse\n", consumerA, consumerB)
+	}))
+	defer server.Close()
+
+	lists, err := NewConsumerLists(t.TempDir(), nil, []string{server.URL}, server.Client())
+	assert.NoError(t, err)
+
+	assert.NoError(t, lists.Update())
+	assert.False(t, lists.IsIdentityAllowed(identity.FromAddress(consumerA)))
+	assert.False(t, lists.IsIdentityAllowed(identity.FromAddress(consumerB)))
+	assert.Equal(t, 2, lists.State().RemoteBlock)
+
+	fail = true
+	assert.Error(t, lists.Update())
+	assert.False(t, lists.IsIdentityAllowed(identity.FromAddress(consumerA)))
+	assert.NotEmpty(t, lists.State().LastUpdateErr)
+}
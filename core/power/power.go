@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package power
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// AppTopicPowerMode is published when the host switches the node in or out of low-power mode.
+const AppTopicPowerMode = "power-mode"
+
+// AppEventPowerMode represents the power mode reported by the host.
+type AppEventPowerMode struct {
+	LowPower bool
+}
+
+// Mode tracks whether the node runs in low-power mode. Zero value is ready to use.
+type Mode struct {
+	lowPower int32
+}
+
+// Subscribe starts following power mode changes.
+func (m *Mode) Subscribe(bus eventbus.Subscriber) error {
+	return bus.Subscribe(AppTopicPowerMode, m.handle)
+}
+
+func (m *Mode) handle(e AppEventPowerMode) {
+	m.Set(e.LowPower)
+}
+
+// Set switches low-power mode on or off.
+func (m *Mode) Set(lowPower bool) {
+	var v int32
+	if lowPower {
+		v = 1
+	}
+	atomic.StoreInt32(&m.lowPower, v)
+}
+
+// LowPower returns whether low-power mode is on.
+func (m *Mode) LowPower() bool {
+	return atomic.LoadInt32(&m.lowPower) == 1
+}
+
+// Interval picks the interval matching the current mode, falling back to normal when no low-power interval is set.
+func (m *Mode) Interval(normal, lowPower time.Duration) time.Duration {
+	if m.LowPower() && lowPower > normal {
+		return lowPower
+	}
+	return normal
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package power
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+func TestMode_FollowsPowerModeEvents(t *testing.T) {
+	bus := eventbus.New()
+	var mode Mode
+	assert.NoError(t, mode.Subscribe(bus))
+	assert.False(t, mode.LowPower())
+
+	bus.Publish(AppTopicPowerMode, AppEventPowerMode{LowPower: true})
+	assert.True(t, mode.LowPower())
+
+	bus.Publish(AppTopicPowerMode, AppEventPowerMode{LowPower: false})
+	assert.False(t, mode.LowPower())
+}
+
+func TestMode_Interval(t *testing.T) {
+	var mode Mode
+	assert.Equal(t, 5*time.Second, mode.Interval(5*time.Second, 30*time.Second))
+
+	mode.Set(true)
+	assert.Equal(t, 30*time.Second, mode.Interval(5*time.Second, 30*time.Second))
+	assert.Equal(t, 5*time.Second, mode.Interval(5*time.Second, 0))
+}
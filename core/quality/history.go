@@ -0,0 +1,328 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package quality
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+const (
+	historyMaxQuality = 3.
+	// historyMaxWeight is the largest share of the blended score given to the local history.
+	historyMaxWeight = 0.5
+	// historyWeightSessions is the number of sessions at which local history gets half of its max weight.
+	historyWeightSessions = 5
+	// historyReferenceMbps is the throughput at which provider is not penalized for being slow.
+	historyReferenceMbps = 10
+	// historySpeedTests is the number of most recent speed tests kept per provider.
+	historySpeedTests = 10
+)
+
+// Disconnect reasons recorded in the local session history.
+const (
+	DisconnectReasonConnectionFailed = "connection_failed"
+	DisconnectReasonIPNotChanged     = "ip_not_changed"
+)
+
+// ProviderHistory aggregates outcomes of the consumer's own sessions with the provider.
+type ProviderHistory struct {
+	ProposalID
+	Attempts      int            `json:"attempts"`
+	Successes     int            `json:"successes"`
+	Disconnects   map[string]int `json:"disconnects"`
+	BytesReceived uint64         `json:"bytes_received"`
+	Connected     time.Duration  `json:"connected"`
+	LastSessionAt time.Time      `json:"last_session_at"`
+	// LocationMismatches counts sessions which exited in a country other than the declared one.
+	LocationMismatches int `json:"location_mismatches"`
+	// SpeedTests are the most recent speed tests run through connections to the provider, oldest first.
+	SpeedTests []SpeedTest `json:"speed_tests,omitempty"`
+}
+
+// SpeedTest is a throughput measurement run through a connection to the provider.
+type SpeedTest struct {
+	At           time.Time     `json:"at"`
+	Latency      time.Duration `json:"latency"`
+	DownloadMbps float64       `json:"download_mbps"`
+	UploadMbps   float64       `json:"upload_mbps"`
+}
+
+// ThroughputMbps returns average download throughput of the sessions.
+func (h ProviderHistory) ThroughputMbps() float64 {
+	if h.Connected <= 0 {
+		return 0
+	}
+	return float64(h.BytesReceived) * 8 / h.Connected.Seconds() / 1e6
+}
+
+// score rates the provider on the oracle quality scale, Laplace smoothing the rates for few sessions.
+func (h ProviderHistory) score() float64 {
+	successRate := float64(h.Successes+1) / float64(h.Attempts+2)
+
+	var drops int
+	for _, count := range h.Disconnects {
+		drops += count
+	}
+	stability := 1 - 0.5*math.Min(1, float64(drops)/math.Max(1, float64(h.Successes)))
+	accuracy := 1 - 0.5*math.Min(1, float64(h.LocationMismatches)/math.Max(1, float64(h.Successes)))
+
+	throughput := 1.
+	if h.Connected > 0 {
+		throughput = 0.5 + 0.5*math.Min(1, h.ThroughputMbps()/historyReferenceMbps)
+	}
+
+	return historyMaxQuality * successRate * stability * accuracy * throughput
+}
+
+type historySession struct {
+	id            ProposalID
+	connected     bool
+	connectedAt   time.Time
+	bytesReceived uint64
+}
+
+// History keeps outcomes of the consumer's past sessions per provider and blends them into proposal quality,
+// so that providers which actually worked for the consumer are preferred.
+type History struct {
+	file string
+	now  func() time.Time
+
+	mu        sync.Mutex
+	providers map[ProposalID]*ProviderHistory
+	sessions  map[string]*historySession
+}
+
+// NewHistory creates session history persisted in the given directory.
+func NewHistory(dir string) (*History, error) {
+	h := &History{
+		file:      filepath.Join(dir, "session-history-quality.json"),
+		now:       time.Now,
+		providers: make(map[ProposalID]*ProviderHistory),
+		sessions:  make(map[string]*historySession),
+	}
+	if err := h.read(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Subscribe starts recording consumer connections.
+func (h *History) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(connectionstate.AppTopicConnectionState, h.handleConnectionState); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(connectionstate.AppTopicConnectionStatistics, h.handleConnectionStatistics); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(connectionstate.AppTopicLocationMismatch, h.handleLocationMismatch); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(connectionstate.AppTopicProviderSwitched, h.handleProviderSwitched)
+}
+
+func (h *History) handleConnectionState(e connectionstate.AppEventConnectionState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	session, ok := h.sessions[e.UUID]
+	switch e.State {
+	case connectionstate.Connecting:
+		if ok {
+			return
+		}
+		p := e.SessionInfo.Proposal
+		id := ProposalID{ProviderID: p.ProviderID, ServiceType: p.ServiceType}
+		if id.ProviderID == "" {
+			return
+		}
+		h.sessions[e.UUID] = &historySession{id: id}
+		h.provider(id).Attempts++
+	case connectionstate.Connected:
+		if !ok || session.connected {
+			return
+		}
+		session.connected = true
+		session.connectedAt = h.now()
+		h.provider(session.id).Successes++
+	case connectionstate.StateConnectionFailed:
+		if ok && session.connected {
+			h.provider(session.id).Disconnects[DisconnectReasonConnectionFailed]++
+		}
+	case connectionstate.StateIPNotChanged:
+		if ok {
+			h.provider(session.id).Disconnects[DisconnectReasonIPNotChanged]++
+		}
+	case connectionstate.NotConnected:
+		if !ok {
+			return
+		}
+		delete(h.sessions, e.UUID)
+
+		provider := h.provider(session.id)
+		provider.LastSessionAt = h.now()
+		if session.connected {
+			provider.Connected += h.now().Sub(session.connectedAt)
+			provider.BytesReceived += session.bytesReceived
+		}
+		if err := h.write(); err != nil {
+			log.Warn().Err(err).Msg("Failed to save session history")
+		}
+	}
+}
+
+func (h *History) handleConnectionStatistics(e connectionstate.AppEventConnectionStatistics) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if session, ok := h.sessions[e.UUID]; ok {
+		session.bytesReceived = e.Stats.BytesReceived
+	}
+}
+
+func (h *History) handleLocationMismatch(e connectionstate.AppEventLocationMismatch) {
+	if e.Proposal.ProviderID == "" {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.provider(ProposalID{ProviderID: e.Proposal.ProviderID, ServiceType: e.Proposal.ServiceType}).LocationMismatches++
+}
+
+func (h *History) handleProviderSwitched(e connectionstate.AppEventProviderSwitched) {
+	if e.From.ProviderID == "" {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.provider(ProposalID{ProviderID: e.From.ProviderID, ServiceType: e.From.ServiceType}).Disconnects[e.Reason]++
+}
+
+func (h *History) provider(id ProposalID) *ProviderHistory {
+	provider, ok := h.providers[id]
+	if !ok {
+		provider = &ProviderHistory{ProposalID: id, Disconnects: make(map[string]int)}
+		h.providers[id] = provider
+	}
+	return provider
+}
+
+// AddSpeedTest records the speed test run through a connection to the provider.
+func (h *History) AddSpeedTest(providerID, serviceType string, test SpeedTest) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	provider := h.provider(ProposalID{ProviderID: providerID, ServiceType: serviceType})
+	provider.SpeedTests = append(provider.SpeedTests, test)
+	if len(provider.SpeedTests) > historySpeedTests {
+		provider.SpeedTests = provider.SpeedTests[len(provider.SpeedTests)-historySpeedTests:]
+	}
+	return h.write()
+}
+
+// Get returns the session history with the provider.
+func (h *History) Get(providerID, serviceType string) (ProviderHistory, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	provider, ok := h.providers[ProposalID{ProviderID: providerID, ServiceType: serviceType}]
+	if !ok {
+		return ProviderHistory{}, false
+	}
+	return *provider, true
+}
+
+// List returns session history with all providers, most recent first.
+func (h *History) List() []ProviderHistory {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	list := make([]ProviderHistory, 0, len(h.providers))
+	for _, provider := range h.providers {
+		list = append(list, *provider)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].LastSessionAt.After(list[j].LastSessionAt)
+	})
+	return list
+}
+
+// Blend mixes the quality reported by the oracle with the score of local sessions with the provider.
+// Local history weighs more the more sessions there were, but never more than the oracle.
+func (h *History) Blend(providerID, serviceType string, quality float64) float64 {
+	provider, ok := h.Get(providerID, serviceType)
+	if !ok || provider.Attempts == 0 {
+		return quality
+	}
+
+	weight := historyMaxWeight * float64(provider.Attempts) / float64(provider.Attempts+historyWeightSessions)
+	return (1-weight)*quality + weight*provider.score()
+}
+
+func (h *History) read() error {
+	data, err := os.ReadFile(h.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read session history: %w", err)
+	}
+
+	var providers []*ProviderHistory
+	if err := json.Unmarshal(data, &providers); err != nil {
+		return fmt.Errorf("could not parse session history: %w", err)
+	}
+	for _, provider := range providers {
+		if provider.Disconnects == nil {
+			provider.Disconnects = make(map[string]int)
+		}
+		h.providers[provider.ProposalID] = provider
+	}
+	return nil
+}
+
+func (h *History) write() error {
+	providers := make([]*ProviderHistory, 0, len(h.providers))
+	for _, provider := range h.providers {
+		providers = append(providers, provider)
+	}
+
+	data, err := json.Marshal(providers)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(h.file, data, 0600); err != nil {
+		return fmt.Errorf("could not write session history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package quality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+)
+
+func connectionState(uuid string, state connectionstate.State, providerID string) connectionstate.AppEventConnectionState {
+	return connectionstate.AppEventConnectionState{
+		UUID:        uuid,
+		State:       state,
+		SessionInfo: connectionstate.Status{Proposal: pricedProposal(providerID, 0)},
+	}
+}
+
+func TestHistory_RecordsSessionOutcomes(t *testing.T) {
+	dir := t.TempDir()
+	history, err := NewHistory(dir)
+	require.NoError(t, err)
+
+	now := time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC)
+	history.now = func() time.Time { return now }
+
+	history.handleConnectionState(connectionState("1", connectionstate.Connecting, "0x1"))
+	history.handleConnectionState(connectionState("1", connectionstate.Connected, "0x1"))
+	history.handleConnectionStatistics(connectionstate.AppEventConnectionStatistics{
+		UUID:  "1",
+		Stats: connectionstate.Statistics{BytesReceived: 10_000_000},
+	})
+	history.handleConnectionState(connectionState("1", connectionstate.StateConnectionFailed, "0x1"))
+	now = now.Add(4 * time.Second)
+	history.handleConnectionState(connectionState("1", connectionstate.NotConnected, "0x1"))
+
+	history.handleConnectionState(connectionState("2", connectionstate.Connecting, "0x2"))
+	history.handleConnectionState(connectionState("2", connectionstate.NotConnected, "0x2"))
+
+	provider, ok := history.Get("0x1", "wireguard")
+	require.True(t, ok)
+	assert.Equal(t, 1, provider.Attempts)
+	assert.Equal(t, 1, provider.Successes)
+	assert.Equal(t, map[string]int{DisconnectReasonConnectionFailed: 1}, provider.Disconnects)
+	assert.Equal(t, 4*time.Second, provider.Connected)
+	assert.Equal(t, 20., provider.ThroughputMbps())
+
+	provider, ok = history.Get("0x2", "wireguard")
+	require.True(t, ok)
+	assert.Equal(t, 1, provider.Attempts)
+	assert.Equal(t, 0, provider.Successes)
+
+	restored, err := NewHistory(dir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, history.List(), restored.List())
+}
+
+func TestHistory_Blend(t *testing.T) {
+	history, err := NewHistory(t.TempDir())
+	require.NoError(t, err)
+	history.now = func() time.Time { return time.Date(2022, 8, 1, 12, 0, 0, 0, time.UTC) }
+
+	assert.Equal(t, 2., history.Blend("0x1", "wireguard", 2))
+
+	for i := 0; i < 5; i++ {
+		uuid := string(rune('a' + i))
+		history.handleConnectionState(connectionState(uuid, connectionstate.Connecting, "0x1"))
+		history.handleConnectionState(connectionState(uuid, connectionstate.Connected, "0x1"))
+		history.handleConnectionState(connectionState(uuid, connectionstate.NotConnected, "0x1"))
+
+		history.handleConnectionState(connectionState(uuid, connectionstate.Connecting, "0x2"))
+		history.handleConnectionState(connectionState(uuid, connectionstate.NotConnected, "0x2"))
+	}
+
+	working := history.Blend("0x1", "wireguard", 2)
+	failing := history.Blend("0x2", "wireguard", 2)
+	assert.Greater(t, working, 2.)
+	assert.Less(t, failing, 2.)
+	assert.InDelta(t, 0.75*2+0.25*3*6/7., working, 1e-9)
+}
+
+func TestHistory_LocationMismatch(t *testing.T) {
+	history, err := NewHistory(t.TempDir())
+	require.NoError(t, err)
+
+	for _, providerID := range []string{"0x1", "0x2"} {
+		history.handleConnectionState(connectionState("1"+providerID, connectionstate.Connecting, providerID))
+		history.handleConnectionState(connectionState("1"+providerID, connectionstate.Connected, providerID))
+		history.handleConnectionState(connectionState("1"+providerID, connectionstate.NotConnected, providerID))
+	}
+	history.handleLocationMismatch(connectionstate.AppEventLocationMismatch{
+		Proposal:        pricedProposal("0x2", 0),
+		DeclaredCountry: "US",
+		ExitCountry:     "LT",
+	})
+
+	provider, ok := history.Get("0x2", "wireguard")
+	require.True(t, ok)
+	assert.Equal(t, 1, provider.LocationMismatches)
+	assert.Less(t, history.Blend("0x2", "wireguard", 2), history.Blend("0x1", "wireguard", 2))
+}
+
+func TestHistory_SpeedTests(t *testing.T) {
+	dir := t.TempDir()
+	history, err := NewHistory(dir)
+	require.NoError(t, err)
+
+	at := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < historySpeedTests+2; i++ {
+		require.NoError(t, history.AddSpeedTest("0x1", "wireguard", SpeedTest{At: at.Add(time.Duration(i) * time.Minute), DownloadMbps: float64(i)}))
+	}
+
+	reloaded, err := NewHistory(dir)
+	require.NoError(t, err)
+	provider, ok := reloaded.Get("0x1", "wireguard")
+	require.True(t, ok)
+	require.Len(t, provider.SpeedTests, historySpeedTests)
+	assert.Equal(t, 2., provider.SpeedTests[0].DownloadMbps)
+	assert.Equal(t, float64(historySpeedTests+1), provider.SpeedTests[historySpeedTests-1].DownloadMbps)
+}
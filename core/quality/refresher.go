@@ -0,0 +1,252 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package quality
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/power"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+const (
+	// AppTopicQualityUpdated is published when the oracle reports changed proposal quality scores.
+	AppTopicQualityUpdated = "ProposalQualityUpdated"
+	// AppTopicProviderSwitchSuggested is published when quality of the connected provider collapses.
+	AppTopicProviderSwitchSuggested = "ProviderSwitchSuggested"
+)
+
+// AppEventQualityUpdated holds quality score changes since the previous fetch.
+type AppEventQualityUpdated struct {
+	Deltas []QualityDelta
+}
+
+// AppEventProviderSwitchSuggested suggests leaving the current provider, with the best alternative if one was found.
+type AppEventProviderSwitchSuggested struct {
+	UUID            string
+	ConsumerID      identity.Identity
+	Current         proposal.PricedServiceProposal
+	PreviousQuality float64
+	Quality         float64
+	Suggested       *proposal.PricedServiceProposal
+}
+
+// RefreshConfig defines how often quality scores are fetched and when the provider quality is considered collapsed.
+type RefreshConfig struct {
+	Interval time.Duration
+	// MinDelta is the smallest score change reported in AppTopicQualityUpdated.
+	MinDelta float64
+	// CollapseBelow is the score under which switching the provider is suggested.
+	CollapseBelow float64
+	// CollapseRatio is the relative drop from the score at connect time after which switching is suggested.
+	CollapseRatio float64
+}
+
+// DefaultRefreshConfig returns default quality refresh settings.
+func DefaultRefreshConfig() RefreshConfig {
+	return RefreshConfig{
+		Interval:      time.Minute,
+		MinDelta:      0.1,
+		CollapseBelow: 1,
+		CollapseRatio: 0.5,
+	}
+}
+
+type qualityOracle interface {
+	ProposalsQuality() []ProposalQuality
+}
+
+type proposalLister interface {
+	Proposals(filter *proposal.Filter) ([]proposal.PricedServiceProposal, error)
+}
+
+type watchedConnection struct {
+	status    connectionstate.Status
+	baseline  float64
+	suggested bool
+}
+
+// Refresher periodically fetches proposal quality scores and watches quality of connected providers.
+type Refresher struct {
+	oracle    qualityOracle
+	scores    *Scores
+	proposals proposalLister
+	publisher eventbus.Publisher
+	config    RefreshConfig
+
+	mu          sync.Mutex
+	connections map[string]*watchedConnection
+
+	power power.Mode
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewRefresher creates quality refresher updating the given scores.
+func NewRefresher(oracle qualityOracle, scores *Scores, proposals proposalLister, publisher eventbus.Publisher, config RefreshConfig) *Refresher {
+	return &Refresher{
+		oracle:      oracle,
+		scores:      scores,
+		proposals:   proposals,
+		publisher:   publisher,
+		config:      config,
+		connections: make(map[string]*watchedConnection),
+		stop:        make(chan struct{}),
+	}
+}
+
+// Subscribe starts watching consumer connections and power mode changes.
+func (r *Refresher) Subscribe(bus eventbus.Subscriber) error {
+	if err := r.power.Subscribe(bus); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(connectionstate.AppTopicConnectionState, r.handleConnectionState)
+}
+
+func (r *Refresher) handleConnectionState(e connectionstate.AppEventConnectionState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch e.State {
+	case connectionstate.Connected:
+		if _, ok := r.connections[e.UUID]; ok {
+			return
+		}
+		p := e.SessionInfo.Proposal
+		baseline, ok := r.scores.Get(p.ProviderID, p.ServiceType)
+		if !ok {
+			baseline = p.Quality.Quality
+		}
+		r.connections[e.UUID] = &watchedConnection{status: e.SessionInfo, baseline: baseline}
+	case connectionstate.NotConnected, connectionstate.Disconnecting, connectionstate.Canceled:
+		delete(r.connections, e.UUID)
+	}
+}
+
+// Start fetches quality scores periodically until stopped. Fetching is paused in low-power mode.
+func (r *Refresher) Start() {
+	go func() {
+		ticker := time.NewTicker(r.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			if !r.power.LowPower() {
+				r.Refresh()
+			}
+
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops periodic refresh.
+func (r *Refresher) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// Refresh fetches quality scores, reports their changes and checks connected providers.
+func (r *Refresher) Refresh() {
+	qualities := r.oracle.ProposalsQuality()
+	if len(qualities) == 0 {
+		return
+	}
+
+	deltas := r.scores.update(qualities, r.config.MinDelta)
+	if len(deltas) > 0 {
+		log.Debug().Msgf("Quality oracle reported %d changed proposal scores", len(deltas))
+		r.publisher.Publish(AppTopicQualityUpdated, AppEventQualityUpdated{Deltas: deltas})
+	}
+
+	r.checkConnections()
+}
+
+func (r *Refresher) checkConnections() {
+	r.mu.Lock()
+	var collapsed []AppEventProviderSwitchSuggested
+	for uuid, conn := range r.connections {
+		p := conn.status.Proposal
+		score, ok := r.scores.Get(p.ProviderID, p.ServiceType)
+		if !ok || conn.suggested || !r.collapsed(conn.baseline, score) {
+			continue
+		}
+		conn.suggested = true
+		collapsed = append(collapsed, AppEventProviderSwitchSuggested{
+			UUID:            uuid,
+			ConsumerID:      conn.status.ConsumerID,
+			Current:         p,
+			PreviousQuality: conn.baseline,
+			Quality:         score,
+		})
+	}
+	r.mu.Unlock()
+
+	for _, event := range collapsed {
+		event.Suggested = r.alternative(event.Current, event.Quality)
+		log.Warn().Msgf("Quality of provider %s dropped from %.2f to %.2f, suggesting a switch", event.Current.ProviderID, event.PreviousQuality, event.Quality)
+		r.publisher.Publish(AppTopicProviderSwitchSuggested, event)
+	}
+}
+
+func (r *Refresher) collapsed(baseline, score float64) bool {
+	if score < r.config.CollapseBelow {
+		return true
+	}
+	return baseline > 0 && score < baseline*(1-r.config.CollapseRatio)
+}
+
+// alternative returns the best provider of the same service type and country scoring better than the current one.
+func (r *Refresher) alternative(current proposal.PricedServiceProposal, score float64) *proposal.PricedServiceProposal {
+	if r.proposals == nil {
+		return nil
+	}
+
+	proposals, err := r.proposals.Proposals(&proposal.Filter{
+		ServiceType:        current.ServiceType,
+		LocationCountry:    current.Location.Country,
+		ExcludeUnsupported: true,
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to find alternative provider")
+		return nil
+	}
+
+	var best *proposal.PricedServiceProposal
+	for i := range proposals {
+		p := proposals[i]
+		if p.ProviderID == current.ProviderID || p.Quality.Quality <= score {
+			continue
+		}
+		if best == nil || p.Quality.Quality > best.Quality.Quality {
+			best = &p
+		}
+	}
+	return best
+}
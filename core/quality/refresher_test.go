@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package quality
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/mocks"
+)
+
+type mockOracle struct {
+	qualities []ProposalQuality
+}
+
+func (mo *mockOracle) ProposalsQuality() []ProposalQuality {
+	return mo.qualities
+}
+
+type mockProposalLister struct {
+	proposals []proposal.PricedServiceProposal
+}
+
+func (ml *mockProposalLister) Proposals(_ *proposal.Filter) ([]proposal.PricedServiceProposal, error) {
+	return ml.proposals, nil
+}
+
+func pricedProposal(providerID string, quality float64) proposal.PricedServiceProposal {
+	return proposal.PricedServiceProposal{
+		ServiceProposal: market.ServiceProposal{
+			ProviderID:  providerID,
+			ServiceType: "wireguard",
+			Quality:     market.Quality{Quality: quality},
+		},
+	}
+}
+
+func proposalQuality(providerID string, quality float64) ProposalQuality {
+	return ProposalQuality{ProposalID: ProposalID{ProviderID: providerID, ServiceType: "wireguard"}, Quality: quality}
+}
+
+func TestScores_UpdateReportsSignificantDeltas(t *testing.T) {
+	scores := NewScores()
+
+	deltas := scores.update([]ProposalQuality{proposalQuality("0x1", 2), proposalQuality("0x2", 3)}, 0.5)
+	assert.Len(t, deltas, 2)
+
+	deltas = scores.update([]ProposalQuality{proposalQuality("0x1", 2.2), proposalQuality("0x2", 1)}, 0.5)
+	assert.Equal(t, []QualityDelta{{ProposalID: ProposalID{ProviderID: "0x2", ServiceType: "wireguard"}, Previous: 3, Current: 1}}, deltas)
+
+	score, ok := scores.Get("0x1", "wireguard")
+	assert.True(t, ok)
+	assert.Equal(t, 2.2, score)
+}
+
+func TestRefresher_SuggestsSwitchWhenProviderQualityCollapses(t *testing.T) {
+	oracle := &mockOracle{qualities: []ProposalQuality{proposalQuality("0x1", 2.5), proposalQuality("0x2", 2)}}
+	lister := &mockProposalLister{proposals: []proposal.PricedServiceProposal{pricedProposal("0x1", 0.5), pricedProposal("0x2", 2)}}
+	bus := mocks.NewEventBus()
+	refresher := NewRefresher(oracle, NewScores(), lister, bus, DefaultRefreshConfig())
+
+	refresher.Refresh()
+	refresher.handleConnectionState(connectionstate.AppEventConnectionState{
+		UUID:        "conn",
+		State:       connectionstate.Connected,
+		SessionInfo: connectionstate.Status{Proposal: pricedProposal("0x1", 2.5)},
+	})
+	bus.Clear()
+
+	oracle.qualities = []ProposalQuality{proposalQuality("0x1", 0.5), proposalQuality("0x2", 2)}
+	refresher.Refresh()
+	refresher.Refresh()
+
+	history := bus.GetEventHistory()
+	assert.Len(t, history, 2)
+	assert.Equal(t, AppTopicQualityUpdated, history[0].Topic)
+	assert.Equal(t, AppTopicProviderSwitchSuggested, history[1].Topic)
+
+	suggestion := history[1].Event.(AppEventProviderSwitchSuggested)
+	assert.Equal(t, 2.5, suggestion.PreviousQuality)
+	assert.Equal(t, 0.5, suggestion.Quality)
+	if assert.NotNil(t, suggestion.Suggested) {
+		assert.Equal(t, "0x2", suggestion.Suggested.ProviderID)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	maxBufferedSamples = 1000
+	batchSize          = 100
+)
+
+// buffer keeps samples not shipped yet in a file, so that they survive restarts while offline.
+// When full, the oldest samples are dropped.
+type buffer struct {
+	file string
+	max  int
+
+	mu      sync.Mutex
+	samples []Sample
+}
+
+func newBuffer(dir string, max int) (*buffer, error) {
+	b := &buffer{
+		file: filepath.Join(dir, "quality-samples.json"),
+		max:  max,
+	}
+
+	data, err := os.ReadFile(b.file)
+	if os.IsNotExist(err) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read buffered quality samples: %w", err)
+	}
+	if err := json.Unmarshal(data, &b.samples); err != nil {
+		return nil, fmt.Errorf("could not parse buffered quality samples: %w", err)
+	}
+	return b, nil
+}
+
+func (b *buffer) push(sample Sample) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.samples = append(b.samples, sample)
+	if len(b.samples) > b.max {
+		b.samples = b.samples[len(b.samples)-b.max:]
+	}
+	return b.write()
+}
+
+func (b *buffer) peek(n int) []Sample {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n > len(b.samples) {
+		n = len(b.samples)
+	}
+	return append([]Sample(nil), b.samples[:n]...)
+}
+
+// drop removes n oldest samples once they were shipped.
+func (b *buffer) drop(n int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n > len(b.samples) {
+		n = len(b.samples)
+	}
+	b.samples = b.samples[n:]
+	return b.write()
+}
+
+func (b *buffer) len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.samples)
+}
+
+func (b *buffer) write() error {
+	data, err := json.Marshal(b.samples)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(b.file, data, 0600); err != nil {
+		return fmt.Errorf("could not write buffered quality samples: %w", err)
+	}
+	return nil
+}
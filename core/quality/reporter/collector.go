@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+// Collector receives batches of session quality samples.
+type Collector interface {
+	Send(samples []Sample) error
+}
+
+type batch struct {
+	Samples []Sample `json:"samples"`
+}
+
+// HTTPCollector posts samples as JSON to the collector endpoint.
+type HTTPCollector struct {
+	httpClient *requests.HTTPClient
+	url        string
+}
+
+// NewHTTPCollector returns a new collector posting samples to the given URL.
+func NewHTTPCollector(httpClient *requests.HTTPClient, url string) *HTTPCollector {
+	return &HTTPCollector{
+		httpClient: httpClient,
+		url:        url,
+	}
+}
+
+// Send posts the batch of samples.
+func (c *HTTPCollector) Send(samples []Sample) error {
+	body, err := json.Marshal(batch{Samples: samples})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package reporter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// Disconnect reasons of reported sessions.
+const (
+	DisconnectReasonUser             = "user"
+	DisconnectReasonCanceled         = "canceled"
+	DisconnectReasonConnectionFailed = "connection_failed"
+	DisconnectReasonIPNotChanged     = "ip_not_changed"
+)
+
+// Throughput buckets of reported sessions, in Mbps.
+const (
+	ThroughputNone     = "none"
+	ThroughputBelow1   = "<1"
+	ThroughputBelow5   = "1-5"
+	ThroughputBelow20  = "5-20"
+	ThroughputBelow100 = "20-100"
+	ThroughputAbove100 = ">100"
+)
+
+// Sample is an anonymized quality report of a single consumer session. It identifies neither
+// the consumer nor the provider, only the service and countries they were in.
+type Sample struct {
+	ServiceType     string `json:"service_type"`
+	ProviderCountry string `json:"provider_country"`
+	ConsumerCountry string `json:"consumer_country"`
+	Connected       bool   `json:"connected"`
+	// ConnectTime is the time it took to establish the connection, in milliseconds.
+	ConnectTime int64 `json:"connect_time"`
+	// TimeToFirstByte is the time since connection until the first byte was received, in milliseconds.
+	// It is measured with the granularity of connection statistics.
+	TimeToFirstByte  int64  `json:"time_to_first_byte"`
+	Throughput       string `json:"throughput"`
+	DisconnectReason string `json:"disconnect_reason"`
+	// Duration is the time connection was up, in whole minutes.
+	Duration    int64  `json:"duration"`
+	AppVersion  string `json:"app_version"`
+	ReportedDay string `json:"reported_day"`
+}
+
+type session struct {
+	sample        Sample
+	startedAt     time.Time
+	connectedAt   time.Time
+	firstByteAt   time.Time
+	bytesReceived uint64
+}
+
+// Reporter collects session quality samples from connection events and ships them to the collector
+// in batches. Samples are buffered on disk while the collector is not reachable.
+type Reporter struct {
+	collector  Collector
+	buffer     *buffer
+	appVersion string
+	interval   time.Duration
+	now        func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*session
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewReporter returns a new reporter shipping samples every interval and buffering them in the given directory.
+func NewReporter(collector Collector, dir, appVersion string, interval time.Duration) (*Reporter, error) {
+	buffer, err := newBuffer(dir, maxBufferedSamples)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reporter{
+		collector:  collector,
+		buffer:     buffer,
+		appVersion: appVersion,
+		interval:   interval,
+		now:        time.Now,
+		sessions:   make(map[string]*session),
+		stop:       make(chan struct{}),
+	}, nil
+}
+
+// Subscribe starts collecting samples of consumer connections.
+func (r *Reporter) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(connectionstate.AppTopicConnectionState, r.handleConnectionState); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(connectionstate.AppTopicConnectionStatistics, r.handleConnectionStatistics); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(connectionstate.AppTopicProviderSwitched, r.handleProviderSwitched)
+}
+
+// Start starts shipping samples in the background.
+func (r *Reporter) Start() {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				if err := r.Flush(); err != nil {
+					log.Debug().Err(err).Msgf("Failed to ship session quality samples, %d buffered", r.buffer.len())
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops shipping samples. Samples which were not shipped yet stay buffered on disk.
+func (r *Reporter) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// Flush ships all buffered samples.
+func (r *Reporter) Flush() error {
+	for {
+		batch := r.buffer.peek(batchSize)
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := r.collector.Send(batch); err != nil {
+			return err
+		}
+		if err := r.buffer.drop(len(batch)); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *Reporter) handleConnectionState(e connectionstate.AppEventConnectionState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.sessions[e.UUID]
+	switch e.State {
+	case connectionstate.Connecting:
+		if ok {
+			return
+		}
+		p := e.SessionInfo.Proposal
+		r.sessions[e.UUID] = &session{
+			sample: Sample{
+				ServiceType:     p.ServiceType,
+				ProviderCountry: p.Location.Country,
+				ConsumerCountry: e.SessionInfo.ConsumerLocation.Country,
+			},
+			startedAt: r.now(),
+		}
+	case connectionstate.Connected:
+		if !ok || s.sample.Connected {
+			return
+		}
+		s.sample.Connected = true
+		s.connectedAt = r.now()
+		s.sample.ConnectTime = s.connectedAt.Sub(s.startedAt).Milliseconds()
+	case connectionstate.Canceled:
+		if ok {
+			s.disconnected(DisconnectReasonCanceled)
+		}
+	case connectionstate.StateConnectionFailed:
+		if ok {
+			s.disconnected(DisconnectReasonConnectionFailed)
+		}
+	case connectionstate.StateIPNotChanged:
+		if ok {
+			s.disconnected(DisconnectReasonIPNotChanged)
+		}
+	case connectionstate.NotConnected:
+		if !ok {
+			return
+		}
+		delete(r.sessions, e.UUID)
+
+		s.disconnected(DisconnectReasonUser)
+		if err := r.buffer.push(r.finish(s)); err != nil {
+			log.Warn().Err(err).Msg("Failed to buffer session quality sample")
+		}
+	}
+}
+
+func (r *Reporter) handleConnectionStatistics(e connectionstate.AppEventConnectionStatistics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.sessions[e.UUID]
+	if !ok || !s.sample.Connected {
+		return
+	}
+	if s.firstByteAt.IsZero() && e.Stats.BytesReceived > 0 {
+		s.firstByteAt = r.now()
+	}
+	s.bytesReceived = e.Stats.BytesReceived
+}
+
+func (r *Reporter) handleProviderSwitched(e connectionstate.AppEventProviderSwitched) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.sessions[e.UUID]; ok {
+		s.disconnected(e.Reason)
+	}
+}
+
+// disconnected records the first reason of the session end, later ones are consequences.
+func (s *session) disconnected(reason string) {
+	if s.sample.DisconnectReason == "" {
+		s.sample.DisconnectReason = reason
+	}
+}
+
+func (r *Reporter) finish(s *session) Sample {
+	now := r.now()
+	sample := s.sample
+	sample.AppVersion = r.appVersion
+	sample.ReportedDay = now.UTC().Format("2006-01-02")
+	sample.Throughput = ThroughputNone
+
+	if !sample.Connected {
+		return sample
+	}
+	if !s.firstByteAt.IsZero() {
+		sample.TimeToFirstByte = s.firstByteAt.Sub(s.connectedAt).Milliseconds()
+	}
+	connected := now.Sub(s.connectedAt)
+	sample.Duration = int64(connected.Round(time.Minute) / time.Minute)
+	if connected > 0 {
+		sample.Throughput = throughputBucket(float64(s.bytesReceived) * 8 / connected.Seconds() / 1e6)
+	}
+	return sample
+}
+
+func throughputBucket(mbps float64) string {
+	switch {
+	case mbps <= 0:
+		return ThroughputNone
+	case mbps < 1:
+		return ThroughputBelow1
+	case mbps < 5:
+		return ThroughputBelow5
+	case mbps < 20:
+		return ThroughputBelow20
+	case mbps < 100:
+		return ThroughputBelow100
+	default:
+		return ThroughputAbove100
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package reporter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+type mockCollector struct {
+	err     error
+	batches [][]Sample
+}
+
+func (c *mockCollector) Send(samples []Sample) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.batches = append(c.batches, samples)
+	return nil
+}
+
+func TestReporterBuildsAnonymizedSample(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	r, err := NewReporter(&mockCollector{}, t.TempDir(), "1.0.0", time.Minute)
+	require.NoError(t, err)
+	r.now = func() time.Time { return now }
+
+	info := connectionstate.Status{
+		Proposal: proposal.PricedServiceProposal{ServiceProposal: market.ServiceProposal{
+			ProviderID:  "0x1",
+			ServiceType: "wireguard",
+			Location:    market.Location{Country: "DE"},
+		}},
+	}
+	info.ConsumerLocation.Country = "LT"
+
+	r.handleConnectionState(connectionstate.AppEventConnectionState{UUID: "c1", State: connectionstate.Connecting, SessionInfo: info})
+	now = now.Add(1500 * time.Millisecond)
+	r.handleConnectionState(connectionstate.AppEventConnectionState{UUID: "c1", State: connectionstate.Connected})
+	now = now.Add(time.Second)
+	r.handleConnectionStatistics(connectionstate.AppEventConnectionStatistics{UUID: "c1", Stats: connectionstate.Statistics{BytesReceived: 1000}})
+	now = now.Add(10 * time.Minute)
+	r.handleConnectionStatistics(connectionstate.AppEventConnectionStatistics{UUID: "c1", Stats: connectionstate.Statistics{BytesReceived: 600 * 1e6}})
+	r.handleConnectionState(connectionstate.AppEventConnectionState{UUID: "c1", State: connectionstate.StateConnectionFailed})
+	r.handleConnectionState(connectionstate.AppEventConnectionState{UUID: "c1", State: connectionstate.NotConnected})
+
+	assert.Equal(t, []Sample{{
+		ServiceType:      "wireguard",
+		ProviderCountry:  "DE",
+		ConsumerCountry:  "LT",
+		Connected:        true,
+		ConnectTime:      1500,
+		TimeToFirstByte:  1000,
+		Throughput:       ThroughputBelow20,
+		DisconnectReason: DisconnectReasonConnectionFailed,
+		Duration:         10,
+		AppVersion:       "1.0.0",
+		ReportedDay:      "2022-10-01",
+	}}, r.buffer.peek(10))
+}
+
+func TestReporterBuffersSamplesWhileOffline(t *testing.T) {
+	dir := t.TempDir()
+	collector := &mockCollector{err: errors.New("offline")}
+	r, err := NewReporter(collector, dir, "1.0.0", time.Minute)
+	require.NoError(t, err)
+
+	for i := 0; i < batchSize+1; i++ {
+		r.handleConnectionState(connectionstate.AppEventConnectionState{UUID: "c", State: connectionstate.Connecting})
+		r.handleConnectionState(connectionstate.AppEventConnectionState{UUID: "c", State: connectionstate.Canceled})
+		r.handleConnectionState(connectionstate.AppEventConnectionState{UUID: "c", State: connectionstate.NotConnected})
+	}
+	assert.Error(t, r.Flush())
+
+	restarted, err := NewReporter(collector, dir, "1.0.0", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, batchSize+1, restarted.buffer.len())
+
+	collector.err = nil
+	require.NoError(t, restarted.Flush())
+	require.Len(t, collector.batches, 2)
+	assert.Len(t, collector.batches[0], batchSize)
+	assert.Equal(t, DisconnectReasonCanceled, collector.batches[1][0].DisconnectReason)
+	assert.Equal(t, ThroughputNone, collector.batches[1][0].Throughput)
+	assert.Zero(t, restarted.buffer.len())
+}
+
+func TestBufferDropsOldestSamplesWhenFull(t *testing.T) {
+	b, err := newBuffer(t.TempDir(), 2)
+	require.NoError(t, err)
+
+	require.NoError(t, b.push(Sample{ServiceType: "a"}))
+	require.NoError(t, b.push(Sample{ServiceType: "b"}))
+	require.NoError(t, b.push(Sample{ServiceType: "c"}))
+
+	assert.Equal(t, []Sample{{ServiceType: "b"}, {ServiceType: "c"}}, b.peek(10))
+}
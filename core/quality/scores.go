@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package quality
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// QualityDelta describes a change of proposal quality score reported by the oracle.
+type QualityDelta struct {
+	ProposalID ProposalID
+	Previous   float64
+	Current    float64
+}
+
+// Scores keeps the latest proposal quality scores fetched from the oracle.
+type Scores struct {
+	mu     sync.RWMutex
+	scores map[ProposalID]float64
+}
+
+// NewScores creates empty quality scores store.
+func NewScores() *Scores {
+	return &Scores{scores: make(map[ProposalID]float64)}
+}
+
+// Get returns the latest quality score of the proposal.
+func (s *Scores) Get(providerID, serviceType string) (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	score, ok := s.scores[ProposalID{ProviderID: providerID, ServiceType: serviceType}]
+	return score, ok
+}
+
+// update replaces scores and returns changes of at least minDelta, including new proposals.
+func (s *Scores) update(qualities []ProposalQuality, minDelta float64) []QualityDelta {
+	scores := make(map[ProposalID]float64, len(qualities))
+	for _, q := range qualities {
+		scores[q.ProposalID] = q.Quality
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deltas []QualityDelta
+	for id, current := range scores {
+		previous, ok := s.scores[id]
+		if ok && math.Abs(current-previous) < minDelta {
+			continue
+		}
+		deltas = append(deltas, QualityDelta{ProposalID: id, Previous: previous, Current: current})
+	}
+	s.scores = scores
+
+	return deltas
+}
+
+func (s *Scores) apply(p *market.ServiceProposal) {
+	if score, ok := s.Get(p.ProviderID, p.ServiceType); ok {
+		p.Quality.Quality = score
+	}
+}
+
+// RankedRepository overrides proposal quality with the latest oracle scores and ranks proposals by it,
+// so that cached proposals follow quality changes without waiting for the next discovery refresh.
+// Scores are blended with the local session history, if given.
+type RankedRepository struct {
+	base    proposal.Repository
+	scores  *Scores
+	history *History
+}
+
+// NewRankedRepository creates proposal repository ranked by the latest quality scores.
+func NewRankedRepository(base proposal.Repository, scores *Scores, history *History) *RankedRepository {
+	return &RankedRepository{base: base, scores: scores, history: history}
+}
+
+func (rr *RankedRepository) apply(p *market.ServiceProposal) {
+	rr.scores.apply(p)
+	if rr.history != nil {
+		p.Quality.Quality = rr.history.Blend(p.ProviderID, p.ServiceType, p.Quality.Quality)
+	}
+}
+
+// Proposal returns a single proposal by its ID.
+func (rr *RankedRepository) Proposal(id market.ProposalID) (*market.ServiceProposal, error) {
+	p, err := rr.base.Proposal(id)
+	if err != nil || p == nil {
+		return p, err
+	}
+
+	ranked := *p
+	rr.apply(&ranked)
+	return &ranked, nil
+}
+
+// Proposals returns proposals matching the filter, best quality first.
+func (rr *RankedRepository) Proposals(filter *proposal.Filter) ([]market.ServiceProposal, error) {
+	proposals, err := rr.base.Proposals(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range proposals {
+		rr.apply(&proposals[i])
+	}
+	sort.SliceStable(proposals, func(i, j int) bool {
+		return proposals[i].Quality.Quality > proposals[j].Quality.Quality
+	})
+
+	return proposals, nil
+}
+
+// Countries returns number of proposals per country.
+func (rr *RankedRepository) Countries(filter *proposal.Filter) (map[string]int, error) {
+	return rr.base.Countries(filter)
+}
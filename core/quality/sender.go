@@ -31,6 +31,7 @@ import (
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
 	"github.com/mysteriumnetwork/node/core/discovery"
+	"github.com/mysteriumnetwork/node/core/power"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
@@ -87,6 +88,8 @@ type Sender struct {
 
 	sessionsMu     sync.RWMutex
 	sessionsActive map[string]sessionContext
+
+	power power.Mode
 }
 
 // Event contains data about event, which is sent using transport
@@ -203,7 +206,7 @@ func (s *Sender) Subscribe(bus eventbus.Subscriber) error {
 		}
 	}
 
-	return nil
+	return s.power.Subscribe(bus)
 }
 
 func (s *Sender) sendNATtraversalMethod(method p2pnat.NATTraversalMethod) {
@@ -435,6 +438,11 @@ func (s *Sender) SendNATMappingFailEvent(id, stage string, gateways []map[string
 }
 
 func (s *Sender) sendEvent(eventName string, context interface{}) {
+	if s.power.LowPower() {
+		log.Trace().Msg("Low-power mode, skipping metric: " + eventName)
+		return
+	}
+
 	guestOS := runtime.GOOS
 	if _, err := os.Stat("/.dockerenv"); err == nil {
 		guestOS += "(docker)"
@@ -0,0 +1,222 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// ErrRuleNotFound is returned when rule with a given ID does not exist.
+var ErrRuleNotFound = errors.New("rule not found")
+
+// Cooldown is a minimum time between two consecutive actions of the same rule.
+// It keeps chatty triggers, e.g. earnings changes or a reconnect causing another
+// connection loss, from running the action over and over.
+const Cooldown = 5 * time.Minute
+
+// Event is passed to actions of the matched rule.
+type Event struct {
+	RuleID   string      `json:"rule_id"`
+	RuleName string      `json:"rule_name"`
+	Trigger  string      `json:"trigger"`
+	Time     time.Time   `json:"time"`
+	Payload  interface{} `json:"payload"`
+}
+
+// Executor runs an action with given params.
+type Executor func(params map[string]string, event Event) error
+
+type ruleStorage interface {
+	Store(rule Rule) error
+	List() ([]Rule, error)
+	Delete(rule Rule) error
+}
+
+// Engine evaluates rules against event bus events and runs actions of the matching ones.
+type Engine struct {
+	storage   ruleStorage
+	executors map[string]Executor
+	cooldown  time.Duration
+	now       func() time.Time
+
+	lock  sync.RWMutex
+	rules []Rule
+
+	runsLock sync.Mutex
+	lastRuns map[string]time.Time
+}
+
+// NewEngine returns a new instance of Engine running actions with given executors.
+func NewEngine(storage ruleStorage, executors map[string]Executor) *Engine {
+	return &Engine{
+		storage:   storage,
+		executors: executors,
+		cooldown:  Cooldown,
+		now:       time.Now,
+		lastRuns:  make(map[string]time.Time),
+	}
+}
+
+// Load loads stored rules.
+func (e *Engine) Load() error {
+	rules, err := e.storage.List()
+	if err != nil {
+		return err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.rules = rules
+	return nil
+}
+
+// Subscribe subscribes the engine to topics of all triggers.
+func (e *Engine) Subscribe(bus eventbus.Subscriber) error {
+	for name, t := range triggers {
+		if err := bus.SubscribeAsync(t.topic, e.handleFunc(name, t)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rules returns configured rules.
+func (e *Engine) Rules() []Rule {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	result := make([]Rule, len(e.rules))
+	copy(result, e.rules)
+	return result
+}
+
+// Actions returns names of actions the engine can take.
+func (e *Engine) Actions() []string {
+	result := make([]string, 0, len(e.executors))
+	for name := range e.executors {
+		result = append(result, name)
+	}
+	return result
+}
+
+// Add validates and stores a new rule.
+func (e *Engine) Add(rule Rule) (Rule, error) {
+	if err := rule.Validate(); err != nil {
+		return rule, err
+	}
+	if _, ok := e.executors[rule.Action.Type]; !ok {
+		return rule, fmt.Errorf("unknown action: %q", rule.Action.Type)
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return rule, err
+	}
+	rule.ID = id.String()
+	rule.CreatedAt = time.Now().UTC()
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if err := e.storage.Store(rule); err != nil {
+		return rule, err
+	}
+	e.rules = append(e.rules, rule)
+	return rule, nil
+}
+
+// Remove deletes a rule with the given ID.
+func (e *Engine) Remove(id string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for i, rule := range e.rules {
+		if rule.ID != id {
+			continue
+		}
+		if err := e.storage.Delete(rule); err != nil {
+			return err
+		}
+		e.rules = append(e.rules[:i], e.rules[i+1:]...)
+
+		e.runsLock.Lock()
+		delete(e.lastRuns, id)
+		e.runsLock.Unlock()
+		return nil
+	}
+	return ErrRuleNotFound
+}
+
+func (e *Engine) handleFunc(name string, t trigger) func(data interface{}) {
+	return func(data interface{}) {
+		if t.fires != nil && !t.fires(data) {
+			return
+		}
+
+		for _, rule := range e.Rules() {
+			if !rule.Enabled || rule.Trigger != name || !rule.Matches(data) {
+				continue
+			}
+			e.run(rule, data)
+		}
+	}
+}
+
+func (e *Engine) run(rule Rule, data interface{}) {
+	execute, ok := e.executors[rule.Action.Type]
+	if !ok {
+		log.Warn().Msgf("Rule %s has unknown action %q", rule.ID, rule.Action.Type)
+		return
+	}
+	if !e.coolDown(rule) {
+		log.Debug().Msgf("Rule %q matched %s, but it is cooling down", rule.Name, rule.Trigger)
+		return
+	}
+
+	log.Info().Msgf("Rule %q matched %s, running %s action", rule.Name, rule.Trigger, rule.Action.Type)
+	err := execute(rule.Action.Params, Event{
+		RuleID:   rule.ID,
+		RuleName: rule.Name,
+		Trigger:  rule.Trigger,
+		Time:     e.now().UTC(),
+		Payload:  data,
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Rule %q action %s failed", rule.Name, rule.Action.Type)
+	}
+}
+
+// coolDown records the rule run and returns false if the rule ran less than cooldown ago.
+func (e *Engine) coolDown(rule Rule) bool {
+	e.runsLock.Lock()
+	defer e.runsLock.Unlock()
+
+	now := e.now()
+	if last, ok := e.lastRuns[rule.ID]; ok && now.Sub(last) < e.cooldown {
+		return false
+	}
+	e.lastRuns[rule.ID] = now
+	return true
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rules
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/identity"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+type mockStorage struct {
+	rules []Rule
+}
+
+func (ms *mockStorage) Store(rule Rule) error {
+	ms.rules = append(ms.rules, rule)
+	return nil
+}
+
+func (ms *mockStorage) List() ([]Rule, error) {
+	return ms.rules, nil
+}
+
+func (ms *mockStorage) Delete(rule Rule) error {
+	for i, r := range ms.rules {
+		if r.ID == rule.ID {
+			ms.rules = append(ms.rules[:i], ms.rules[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func earningsEvent(unsettled int64) pingpongEvent.AppEventEarningsChanged {
+	return pingpongEvent.AppEventEarningsChanged{
+		Identity: identity.FromAddress("0x1"),
+		Current: pingpongEvent.EarningsDetailed{
+			Total: pingpongEvent.Earnings{
+				LifetimeBalance:  big.NewInt(unsettled),
+				UnsettledBalance: big.NewInt(unsettled),
+			},
+		},
+	}
+}
+
+func TestRule_Matches(t *testing.T) {
+	rule := Rule{
+		Trigger: TriggerEarningsChanged,
+		Conditions: []Condition{
+			{Field: "Current.Total.UnsettledBalance", Op: OpGreaterOrEqual, Value: "5000000000000000000"},
+			{Field: "identity.address", Op: OpEqual, Value: "0x1"},
+		},
+	}
+
+	assert.True(t, rule.Matches(earningsEvent(5000000000000000000)))
+	assert.False(t, rule.Matches(earningsEvent(10)))
+	assert.False(t, Rule{Conditions: []Condition{{Field: "Missing", Op: OpEqual, Value: ""}}}.Matches(earningsEvent(10)))
+}
+
+func TestRule_Validate(t *testing.T) {
+	assert.NoError(t, Rule{Trigger: TriggerNATFailed, Action: Action{Type: ActionReconnect}}.Validate())
+	assert.Error(t, Rule{Trigger: "unknown"}.Validate())
+	assert.Error(t, Rule{Trigger: TriggerNATFailed, Conditions: []Condition{{Field: "Stage", Op: "like"}}}.Validate())
+	assert.Error(t, Rule{Trigger: TriggerNATFailed, Action: Action{Type: ActionWebhook}}.Validate())
+	assert.NoError(t, Rule{Trigger: TriggerNATFailed, Action: Action{Type: ActionReconnect, Params: map[string]string{"connection": "1"}}}.Validate())
+	assert.Error(t, Rule{Trigger: TriggerNATFailed, Action: Action{Type: ActionReconnect, Params: map[string]string{"connection": "first"}}}.Validate())
+}
+
+func TestEngine_RunsMatchingRules(t *testing.T) {
+	var executed []Event
+	engine := NewEngine(&mockStorage{}, map[string]Executor{
+		ActionReconnect: func(_ map[string]string, event Event) error {
+			executed = append(executed, event)
+			return nil
+		},
+	})
+
+	_, err := engine.Add(Rule{Name: "unknown action", Enabled: true, Trigger: TriggerConnectionLost, Action: Action{Type: ActionSettle}})
+	assert.Error(t, err)
+
+	rule, err := engine.Add(Rule{Name: "reconnect", Enabled: true, Trigger: TriggerConnectionLost, Action: Action{Type: ActionReconnect}})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, rule.ID)
+	_, err = engine.Add(Rule{Name: "disabled", Enabled: false, Trigger: TriggerConnectionLost, Action: Action{Type: ActionReconnect}})
+	assert.NoError(t, err)
+
+	handle := engine.handleFunc(TriggerConnectionLost, triggers[TriggerConnectionLost])
+	handle(connectionstate.AppEventConnectionState{State: connectionstate.Connected})
+	assert.Len(t, executed, 0)
+
+	handle(connectionstate.AppEventConnectionState{State: connectionstate.Reconnecting})
+	assert.Len(t, executed, 1)
+	assert.Equal(t, rule.ID, executed[0].RuleID)
+
+	assert.NoError(t, engine.Remove(rule.ID))
+	assert.Equal(t, ErrRuleNotFound, engine.Remove(rule.ID))
+	handle(connectionstate.AppEventConnectionState{State: connectionstate.Reconnecting})
+	assert.Len(t, executed, 1)
+	assert.Len(t, engine.Rules(), 1)
+}
+
+func TestEngine_RuleCoolsDown(t *testing.T) {
+	var executed int
+	engine := NewEngine(&mockStorage{}, map[string]Executor{
+		ActionSettle: func(_ map[string]string, _ Event) error {
+			executed++
+			return nil
+		},
+	})
+	now := time.Now()
+	engine.now = func() time.Time { return now }
+
+	_, err := engine.Add(Rule{Name: "settle", Enabled: true, Trigger: TriggerEarningsChanged, Action: Action{Type: ActionSettle}})
+	assert.NoError(t, err)
+
+	handle := engine.handleFunc(TriggerEarningsChanged, triggers[TriggerEarningsChanged])
+	handle(earningsEvent(10))
+	handle(earningsEvent(20))
+	assert.Equal(t, 1, executed, "cooldown should prevent another action")
+
+	now = now.Add(Cooldown)
+	handle(earningsEvent(30))
+	assert.Equal(t, 2, executed)
+}
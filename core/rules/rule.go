@@ -0,0 +1,197 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Triggers rules can be bound to.
+const (
+	TriggerEarningsChanged = "earnings_changed"
+	TriggerConnectionLost  = "connection_lost"
+	TriggerNATFailed       = "nat_failed"
+)
+
+// Actions rules can take.
+const (
+	ActionReconnect    = "reconnect"
+	ActionSettle       = "settle"
+	ActionPauseService = "pause_service"
+	ActionWebhook      = "webhook"
+)
+
+// Condition operators.
+const (
+	OpEqual          = "eq"
+	OpNotEqual       = "ne"
+	OpGreater        = "gt"
+	OpGreaterOrEqual = "gte"
+	OpLess           = "lt"
+	OpLessOrEqual    = "lte"
+	OpContains       = "contains"
+)
+
+// Rule runs an action once its trigger fires and all conditions hold for the event payload.
+type Rule struct {
+	ID         string      `json:"id" storm:"id"`
+	Name       string      `json:"name"`
+	Enabled    bool        `json:"enabled"`
+	Trigger    string      `json:"trigger"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	Action     Action      `json:"action"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// Condition compares a payload field with the given value.
+// Field is a dot separated path in the JSON form of the event payload, e.g. "Current.Total.UnsettledBalance".
+type Condition struct {
+	Field string `json:"field"`
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+// Action describes what is done once the rule matches.
+type Action struct {
+	Type   string            `json:"type"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// Validate checks whether the rule is well formed.
+func (r Rule) Validate() error {
+	if _, ok := triggers[r.Trigger]; !ok {
+		return fmt.Errorf("unknown trigger: %q", r.Trigger)
+	}
+	for _, c := range r.Conditions {
+		if c.Field == "" {
+			return fmt.Errorf("condition field is required")
+		}
+		switch c.Op {
+		case OpEqual, OpNotEqual, OpGreater, OpGreaterOrEqual, OpLess, OpLessOrEqual, OpContains:
+		default:
+			return fmt.Errorf("unknown condition operator: %q", c.Op)
+		}
+	}
+	if r.Action.Type == ActionWebhook && r.Action.Params["url"] == "" {
+		return fmt.Errorf("webhook action requires url param")
+	}
+	if connection, ok := r.Action.Params["connection"]; ok && r.Action.Type == ActionReconnect {
+		if _, err := strconv.Atoi(connection); err != nil {
+			return fmt.Errorf("reconnect action requires numeric connection param: %q", connection)
+		}
+	}
+	return nil
+}
+
+// Matches returns true if all rule conditions hold for the given payload.
+func (r Rule) Matches(payload interface{}) bool {
+	if len(r.Conditions) == 0 {
+		return true
+	}
+
+	doc, err := toDocument(payload)
+	if err != nil {
+		return false
+	}
+	for _, c := range r.Conditions {
+		if !c.holds(doc) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Condition) holds(doc interface{}) bool {
+	value, ok := lookup(doc, c.Field)
+	if !ok {
+		return false
+	}
+
+	actual := fmt.Sprint(value)
+	switch c.Op {
+	case OpEqual:
+		return actual == c.Value
+	case OpNotEqual:
+		return actual != c.Value
+	case OpContains:
+		return strings.Contains(actual, c.Value)
+	}
+
+	a, okA := new(big.Rat).SetString(actual)
+	b, okB := new(big.Rat).SetString(c.Value)
+	if !okA || !okB {
+		return false
+	}
+	cmp := a.Cmp(b)
+	switch c.Op {
+	case OpGreater:
+		return cmp > 0
+	case OpGreaterOrEqual:
+		return cmp >= 0
+	case OpLess:
+		return cmp < 0
+	case OpLessOrEqual:
+		return cmp <= 0
+	}
+	return false
+}
+
+// toDocument converts payload to generic JSON document keeping numbers exact, e.g. big.Int balances.
+func toDocument(payload interface{}) (interface{}, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc interface{}
+	err = dec.Decode(&doc)
+	return doc, err
+}
+
+// lookup walks the dot separated path, keys are matched case insensitively as a fallback.
+func lookup(doc interface{}, path string) (interface{}, bool) {
+	current := doc
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		next, ok := obj[key]
+		if !ok {
+			for k, v := range obj {
+				if strings.EqualFold(k, key) {
+					next, ok = v, true
+					break
+				}
+			}
+		}
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
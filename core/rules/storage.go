@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rules
+
+import (
+	"errors"
+
+	"github.com/asdine/storm/v3"
+)
+
+const bucketName = "rules"
+
+type persistentStorage interface {
+	Store(bucket string, data interface{}) error
+	GetAllFrom(bucket string, data interface{}) error
+	Delete(bucket string, data interface{}) error
+}
+
+// Storage keeps configured rules.
+type Storage struct {
+	storage persistentStorage
+}
+
+// NewStorage returns a new instance of Storage.
+func NewStorage(storage persistentStorage) *Storage {
+	return &Storage{
+		storage: storage,
+	}
+}
+
+// Store stores a given rule.
+func (s *Storage) Store(rule Rule) error {
+	return s.storage.Store(bucketName, &rule)
+}
+
+// List returns all stored rules.
+func (s *Storage) List() ([]Rule, error) {
+	var result []Rule
+	err := s.storage.GetAllFrom(bucketName, &result)
+	if errors.Is(err, storm.ErrNotFound) {
+		return []Rule{}, nil
+	}
+	return result, err
+}
+
+// Delete removes a given rule.
+func (s *Storage) Delete(rule Rule) error {
+	return s.storage.Delete(bucketName, &rule)
+}
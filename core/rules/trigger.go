@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rules
+
+import (
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	natEvent "github.com/mysteriumnetwork/node/nat/event"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+type trigger struct {
+	topic string
+	// fires filters events of the topic which should trigger rules, all events fire if nil.
+	fires func(data interface{}) bool
+}
+
+var triggers = map[string]trigger{
+	TriggerEarningsChanged: {
+		topic: pingpongEvent.AppTopicEarningsChanged,
+	},
+	TriggerConnectionLost: {
+		topic: connectionstate.AppTopicConnectionState,
+		fires: func(data interface{}) bool {
+			e, ok := data.(connectionstate.AppEventConnectionState)
+			if !ok {
+				return false
+			}
+			switch e.State {
+			case connectionstate.Reconnecting, connectionstate.StateConnectionFailed, connectionstate.StateOnHold:
+				return true
+			}
+			return false
+		},
+	},
+	TriggerNATFailed: {
+		topic: natEvent.AppTopicTraversal,
+		fires: func(data interface{}) bool {
+			e, ok := data.(natEvent.Event)
+			return ok && !e.Successful
+		},
+	},
+}
+
+// Triggers returns names of all supported triggers.
+func Triggers() []string {
+	return []string{TriggerEarningsChanged, TriggerConnectionLost, TriggerNATFailed}
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const maxFeedSize = 1 << 20
+
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Feed provides the current value of an external signal, e.g. spot electricity price.
+type Feed interface {
+	Value(ctx context.Context) (float64, error)
+}
+
+// HTTPFeed polls a JSON document and reads a number found at the given path.
+type HTTPFeed struct {
+	client httpDoer
+	url    string
+	path   string
+}
+
+// NewHTTPFeed returns a new instance of HTTPFeed.
+// Path is a dot separated list of object keys and array indexes, e.g. "data.0.price".
+func NewHTTPFeed(client httpDoer, url, path string) *HTTPFeed {
+	return &HTTPFeed{
+		client: client,
+		url:    url,
+		path:   path,
+	}
+}
+
+// Value fetches the document and returns the value found at the path.
+func (f *HTTPFeed) Value(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("signal feed responded with status: %s", resp.Status)
+	}
+
+	var doc interface{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxFeedSize)).Decode(&doc); err != nil {
+		return 0, fmt.Errorf("could not parse signal feed: %w", err)
+	}
+
+	return lookupNumber(doc, f.path)
+}
+
+func lookupNumber(doc interface{}, path string) (float64, error) {
+	value := doc
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			switch node := value.(type) {
+			case map[string]interface{}:
+				v, ok := node[key]
+				if !ok {
+					return 0, fmt.Errorf("key %q not found in signal feed", key)
+				}
+				value = v
+			case []interface{}:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(node) {
+					return 0, fmt.Errorf("index %q is out of signal feed array bounds", key)
+				}
+				value = node[i]
+			default:
+				return 0, fmt.Errorf("can not look up %q in signal feed value %v", key, node)
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	}
+
+	return 0, fmt.Errorf("signal feed value at %q is not a number: %v", path, value)
+}
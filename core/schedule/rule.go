@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Actions which can be mapped to signal ranges.
+const (
+	ActionServe = "serve"
+	ActionPause = "pause"
+)
+
+// Rule maps a range of signal values to an action. Min is inclusive, Max is exclusive.
+type Rule struct {
+	Min    *float64 `json:"min,omitempty"`
+	Max    *float64 `json:"max,omitempty"`
+	Action string   `json:"action"`
+}
+
+// Matches checks whether the value falls into the rule range.
+func (r Rule) Matches(value float64) bool {
+	if r.Min != nil && value < *r.Min {
+		return false
+	}
+	if r.Max != nil && value >= *r.Max {
+		return false
+	}
+	return true
+}
+
+// String returns the rule in the same format it is parsed from.
+func (r Rule) String() string {
+	bound := func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%s..%s=%s", bound(r.Min), bound(r.Max), r.Action)
+}
+
+// ParseRule parses a rule in "<min>..<max>=<action>" format, e.g. "..0.15=serve" or "0.15..=pause".
+// Either bound may be omitted.
+func ParseRule(s string) (Rule, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "=", 2)
+	if len(parts) != 2 {
+		return Rule{}, fmt.Errorf("rule %q must be in <min>..<max>=<action> format", s)
+	}
+
+	rule := Rule{Action: strings.TrimSpace(parts[1])}
+	if rule.Action != ActionServe && rule.Action != ActionPause {
+		return Rule{}, fmt.Errorf("rule %q has unknown action %q", s, rule.Action)
+	}
+
+	bounds := strings.SplitN(parts[0], "..", 2)
+	if len(bounds) != 2 {
+		return Rule{}, fmt.Errorf("rule %q must be in <min>..<max>=<action> format", s)
+	}
+
+	var err error
+	if rule.Min, err = parseBound(bounds[0]); err != nil {
+		return Rule{}, fmt.Errorf("rule %q has invalid min: %w", s, err)
+	}
+	if rule.Max, err = parseBound(bounds[1]); err != nil {
+		return Rule{}, fmt.Errorf("rule %q has invalid max: %w", s, err)
+	}
+	if rule.Min != nil && rule.Max != nil && *rule.Min >= *rule.Max {
+		return Rule{}, fmt.Errorf("rule %q has empty range", s)
+	}
+
+	return rule, nil
+}
+
+// ParseRules parses a list of rules.
+func ParseRules(list []string) ([]Rule, error) {
+	rules := make([]Rule, 0, len(list))
+	for _, s := range list {
+		rule, err := ParseRule(s)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseBound(s string) (*float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// decide returns the action of the first rule matching the value.
+func decide(rules []Rule, value float64) (string, bool) {
+	for _, rule := range rules {
+		if rule.Matches(value) {
+			return rule.Action, true
+		}
+	}
+	return "", false
+}
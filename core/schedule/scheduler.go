@@ -0,0 +1,169 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schedule
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// AppTopicScheduleChanged is a topic for publishing provider mode changes caused by the signal feed.
+const AppTopicScheduleChanged = "Provider schedule changed"
+
+// Controller pauses and resumes provided services.
+type Controller interface {
+	Pause() error
+	Resume() error
+}
+
+// Config holds scheduler settings.
+type Config struct {
+	// Interval is how often the signal feed is polled.
+	Interval time.Duration
+	// Timeout is a time given for a single feed poll.
+	Timeout time.Duration
+	// Rules map signal values to actions, the first matching rule wins.
+	// Current mode is kept if no rule matches or the feed is unavailable.
+	Rules []Rule
+}
+
+// Status is a snapshot of the scheduler state.
+type Status struct {
+	Enabled   bool      `json:"enabled"`
+	Paused    bool      `json:"paused"`
+	Value     *float64  `json:"value,omitempty"`
+	Action    string    `json:"action,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+	Rules     []Rule    `json:"rules"`
+}
+
+// Scheduler follows an external signal feed and serves only while the signal allows it.
+type Scheduler struct {
+	config     Config
+	feed       Feed
+	controller Controller
+	publisher  eventbus.Publisher
+
+	mu     sync.Mutex
+	status Status
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewScheduler returns a new instance of Scheduler.
+func NewScheduler(config Config, feed Feed, controller Controller, publisher eventbus.Publisher) *Scheduler {
+	return &Scheduler{
+		config:     config,
+		feed:       feed,
+		controller: controller,
+		publisher:  publisher,
+		status:     Status{Enabled: true, Rules: config.Rules},
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start polls the feed periodically until stopped.
+func (s *Scheduler) Start() {
+	s.Check()
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.Check()
+		}
+	}
+}
+
+// Stop stops the scheduler. Services paused by the scheduler are left paused.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// Status returns the current scheduler state. Nil scheduler reports disabled scheduling.
+func (s *Scheduler) Status() Status {
+	if s == nil {
+		return Status{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.status
+}
+
+// Check polls the feed once and pauses or resumes services according to the rules.
+func (s *Scheduler) Check() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	value, err := s.feed.Value(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.CheckedAt = time.Now().UTC()
+	if err != nil {
+		log.Warn().Err(err).Msg("Could not read signal feed, keeping current provider mode")
+		s.status.Error = err.Error()
+		return
+	}
+	s.status.Error = ""
+	s.status.Value = &value
+
+	action, ok := decide(s.config.Rules, value)
+	if !ok {
+		log.Debug().Msgf("No schedule rule matches signal value %v, keeping current provider mode", value)
+		return
+	}
+	s.status.Action = action
+
+	paused := action == ActionPause
+	if paused == s.status.Paused {
+		return
+	}
+
+	if paused {
+		log.Info().Msgf("Signal value %v is outside serving range, pausing services", value)
+		err = s.controller.Pause()
+	} else {
+		log.Info().Msgf("Signal value %v is within serving range, resuming services", value)
+		err = s.controller.Resume()
+	}
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not %s services", action)
+		s.status.Error = err.Error()
+		return
+	}
+
+	s.status.Paused = paused
+	s.publisher.Publish(AppTopicScheduleChanged, s.status)
+}
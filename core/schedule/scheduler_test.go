@@ -0,0 +1,142 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schedule
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRule(t *testing.T) {
+	rule, err := ParseRule("..0.15=serve")
+	assert.NoError(t, err)
+	assert.Nil(t, rule.Min)
+	assert.Equal(t, 0.15, *rule.Max)
+	assert.Equal(t, ActionServe, rule.Action)
+	assert.Equal(t, "..0.15=serve", rule.String())
+
+	rule, err = ParseRule("0.15..0.3=pause")
+	assert.NoError(t, err)
+	assert.True(t, rule.Matches(0.15))
+	assert.False(t, rule.Matches(0.3))
+
+	for _, invalid := range []string{"0.15=serve", "..0.15", "..0.15=stop", "a..=serve", "0.3..0.1=pause"} {
+		_, err := ParseRule(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestHTTPFeed_Value(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"price": 0.21}, {"price": "0.12"}]}`))
+	}))
+	defer server.Close()
+
+	value, err := NewHTTPFeed(http.DefaultClient, server.URL, "data.0.price").Value(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0.21, value)
+
+	value, err = NewHTTPFeed(http.DefaultClient, server.URL, "data.1.price").Value(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0.12, value)
+
+	_, err = NewHTTPFeed(http.DefaultClient, server.URL, "data.2.price").Value(context.Background())
+	assert.Error(t, err)
+	_, err = NewHTTPFeed(http.DefaultClient, server.URL, "data").Value(context.Background())
+	assert.Error(t, err)
+}
+
+type mockFeed struct {
+	value float64
+	err   error
+}
+
+func (f *mockFeed) Value(_ context.Context) (float64, error) {
+	return f.value, f.err
+}
+
+type mockController struct {
+	pauses, resumes int
+}
+
+func (c *mockController) Pause() error {
+	c.pauses++
+	return nil
+}
+
+func (c *mockController) Resume() error {
+	c.resumes++
+	return nil
+}
+
+type mockPublisher struct {
+	published []interface{}
+}
+
+func (p *mockPublisher) Publish(_ string, data interface{}) {
+	p.published = append(p.published, data)
+}
+
+func TestScheduler_FollowsSignal(t *testing.T) {
+	rules, err := ParseRules([]string{"..0.15=serve", "0.3..=pause"})
+	assert.NoError(t, err)
+
+	feed := &mockFeed{value: 0.1}
+	controller := &mockController{}
+	publisher := &mockPublisher{}
+	scheduler := NewScheduler(Config{Rules: rules}, feed, controller, publisher)
+
+	// Serving already, nothing to do.
+	scheduler.Check()
+	assert.Equal(t, 0, controller.pauses)
+	assert.False(t, scheduler.Status().Paused)
+
+	feed.value = 0.5
+	scheduler.Check()
+	scheduler.Check()
+	assert.Equal(t, 1, controller.pauses)
+	assert.True(t, scheduler.Status().Paused)
+
+	// No rule matches, current mode is kept.
+	feed.value = 0.2
+	scheduler.Check()
+	assert.True(t, scheduler.Status().Paused)
+
+	// Feed is down, current mode is kept.
+	feed.err = errors.New("feed is down")
+	scheduler.Check()
+	assert.True(t, scheduler.Status().Paused)
+	assert.Equal(t, "feed is down", scheduler.Status().Error)
+
+	feed.err = nil
+	feed.value = 0.05
+	scheduler.Check()
+	assert.Equal(t, 1, controller.resumes)
+	assert.False(t, scheduler.Status().Paused)
+	assert.Len(t, publisher.published, 2)
+}
+
+func TestScheduler_NilStatus(t *testing.T) {
+	var scheduler *Scheduler
+	assert.False(t, scheduler.Status().Enabled)
+}
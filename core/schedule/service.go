@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schedule
+
+import (
+	"sync"
+
+	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/utils"
+)
+
+type serviceManager interface {
+	List(includeAll bool) []*service.Instance
+	Start(providerID identity.Identity, serviceType string, policyIDs []string, options service.Options) (service.ID, error)
+	Stop(id service.ID) error
+}
+
+type pausedService struct {
+	providerID  identity.Identity
+	serviceType string
+	policyIDs   []string
+	options     service.Options
+}
+
+// ServiceController stops running services on pause and starts them again with the same options on resume.
+type ServiceController struct {
+	manager serviceManager
+
+	mu     sync.Mutex
+	paused []pausedService
+}
+
+// NewServiceController returns a new instance of ServiceController.
+func NewServiceController(manager serviceManager) *ServiceController {
+	return &ServiceController{manager: manager}
+}
+
+// Pause stops all running services and remembers how to start them again.
+func (c *ServiceController) Pause() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errs := utils.ErrorCollection{}
+	for _, instance := range c.manager.List(false) {
+		var policyIDs []string
+		if instance.Proposal.AccessPolicies != nil {
+			for _, p := range *instance.Proposal.AccessPolicies {
+				policyIDs = append(policyIDs, p.ID)
+			}
+		}
+
+		if err := c.manager.Stop(instance.ID); err != nil {
+			errs.Add(err)
+			continue
+		}
+		c.paused = append(c.paused, pausedService{
+			providerID:  instance.ProviderID,
+			serviceType: instance.Type,
+			policyIDs:   policyIDs,
+			options:     instance.Options,
+		})
+	}
+
+	return errs.Errorf("ErrorCollection(%s)", ", ")
+}
+
+// Resume starts services stopped by the previous Pause.
+func (c *ServiceController) Resume() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errs := utils.ErrorCollection{}
+	failed := c.paused[:0]
+	for _, s := range c.paused {
+		if _, err := c.manager.Start(s.providerID, s.serviceType, s.policyIDs, s.options); err != nil {
+			errs.Add(err)
+			failed = append(failed, s)
+		}
+	}
+	c.paused = failed
+
+	return errs.Errorf("ErrorCollection(%s)", ", ")
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/p2p"
+)
+
+// AppTopicDialogThrottled is used in event bus to announce throttled consumer dialog messages.
+const AppTopicDialogThrottled = "Dialog throttled"
+
+// AppEventDialogThrottled is published when consumer message is rejected by the dialog rate limits.
+type AppEventDialogThrottled struct {
+	ConsumerID  identity.Identity
+	Topic       string
+	BannedUntil time.Time
+}
+
+// ErrDialogThrottled is returned to consumers exceeding dialog rate limits.
+var ErrDialogThrottled = errors.New("too many requests, try again later")
+
+// DialogLimit is a token bucket limit of consumer messages.
+type DialogLimit struct {
+	Rate  rate.Limit
+	Burst int
+}
+
+// DialogThrottleConfig configures per consumer dialog rate limits.
+type DialogThrottleConfig struct {
+	// SessionRequests limits session create, status, acknowledge and destroy requests.
+	SessionRequests DialogLimit
+	// Payments limits payment exchange messages.
+	Payments DialogLimit
+	// BanAfter is the number of rejected messages after which the consumer is banned.
+	BanAfter int
+	// BanDuration is how long banned consumer messages are rejected.
+	BanDuration time.Duration
+}
+
+// DefaultDialogThrottleConfig returns limits which are not reached by well behaving consumers.
+func DefaultDialogThrottleConfig() DialogThrottleConfig {
+	return DialogThrottleConfig{
+		SessionRequests: DialogLimit{Rate: 1, Burst: 10},
+		Payments:        DialogLimit{Rate: 2, Burst: 20},
+		BanAfter:        50,
+		BanDuration:     10 * time.Minute,
+	}
+}
+
+type consumerDialog struct {
+	limiters    map[string]*rate.Limiter
+	rejected    int
+	bannedUntil time.Time
+	lastSeen    time.Time
+}
+
+// DialogThrottler rate limits consumer dialog messages per consumer identity
+// and temporarily bans consumers flooding the provider.
+type DialogThrottler struct {
+	config    DialogThrottleConfig
+	publisher Publisher
+	now       func() time.Time
+
+	mu          sync.Mutex
+	consumers   map[identity.Identity]*consumerDialog
+	lastCleanup time.Time
+}
+
+// NewDialogThrottler returns a new dialog throttler.
+func NewDialogThrottler(config DialogThrottleConfig, publisher Publisher) *DialogThrottler {
+	return &DialogThrottler{
+		config:    config,
+		publisher: publisher,
+		now:       time.Now,
+		consumers: make(map[identity.Identity]*consumerDialog),
+	}
+}
+
+// Allow checks whether the consumer message on the given topic is within the limits.
+func (t *DialogThrottler) Allow(consumerID identity.Identity, topic string) bool {
+	limit, ok := t.limitFor(topic)
+	if !ok {
+		return true
+	}
+
+	t.mu.Lock()
+	now := t.now()
+	t.forgetIdle(now)
+
+	dialog, ok := t.consumers[consumerID]
+	if !ok {
+		dialog = &consumerDialog{limiters: make(map[string]*rate.Limiter)}
+		t.consumers[consumerID] = dialog
+	}
+	dialog.lastSeen = now
+
+	if now.Before(dialog.bannedUntil) {
+		t.mu.Unlock()
+		return false
+	}
+
+	class := t.classOf(topic)
+	limiter, ok := dialog.limiters[class]
+	if !ok {
+		limiter = rate.NewLimiter(limit.Rate, limit.Burst)
+		dialog.limiters[class] = limiter
+	}
+	if limiter.AllowN(now, 1) {
+		t.mu.Unlock()
+		return true
+	}
+
+	dialog.rejected++
+	if t.config.BanAfter > 0 && dialog.rejected >= t.config.BanAfter {
+		dialog.rejected = 0
+		dialog.bannedUntil = now.Add(t.config.BanDuration)
+	}
+	bannedUntil := dialog.bannedUntil
+	t.mu.Unlock()
+
+	log.Warn().Msgf("Throttled %q message of consumer %s", topic, consumerID.Address)
+	t.publisher.Publish(AppTopicDialogThrottled, AppEventDialogThrottled{
+		ConsumerID:  consumerID,
+		Topic:       topic,
+		BannedUntil: bannedUntil,
+	})
+	return false
+}
+
+func (t *DialogThrottler) limitFor(topic string) (DialogLimit, bool) {
+	switch t.classOf(topic) {
+	case dialogClassSession:
+		return t.config.SessionRequests, true
+	case dialogClassPayment:
+		return t.config.Payments, true
+	default:
+		return DialogLimit{}, false
+	}
+}
+
+const (
+	dialogClassSession = "session"
+	dialogClassPayment = "payment"
+)
+
+func (t *DialogThrottler) classOf(topic string) string {
+	switch topic {
+	case p2p.TopicSessionCreate, p2p.TopicSessionStatus, p2p.TopicSessionAcknowledge, p2p.TopicSessionDestroy:
+		return dialogClassSession
+	case p2p.TopicPaymentMessage:
+		return dialogClassPayment
+	default:
+		return ""
+	}
+}
+
+// forgetIdle drops state of consumers which are neither banned nor seen for the ban duration.
+func (t *DialogThrottler) forgetIdle(now time.Time) {
+	if now.Sub(t.lastCleanup) < time.Minute {
+		return
+	}
+	t.lastCleanup = now
+
+	for id, dialog := range t.consumers {
+		if now.After(dialog.bannedUntil) && now.Sub(dialog.lastSeen) > t.config.BanDuration {
+			delete(t.consumers, id)
+		}
+	}
+}
+
+// throttledHandler rejects handled messages of consumers exceeding dialog rate limits.
+type throttledHandler struct {
+	p2p.ChannelHandler
+	throttler *DialogThrottler
+}
+
+func (h *throttledHandler) Handle(topic string, handler p2p.HandlerFunc) {
+	h.ChannelHandler.Handle(topic, func(c p2p.Context) error {
+		if !h.throttler.Allow(c.PeerID(), topic) {
+			return c.Error(ErrDialogThrottled)
+		}
+		return handler(c)
+	})
+}
+
+func throttleChannel(ch p2p.ChannelHandler, throttler *DialogThrottler) p2p.ChannelHandler {
+	if throttler == nil {
+		return ch
+	}
+	return &throttledHandler{ChannelHandler: ch, throttler: throttler}
+}
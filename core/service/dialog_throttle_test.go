@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/p2p"
+)
+
+func newTestThrottler(publisher Publisher) (*DialogThrottler, *time.Time) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	throttler := NewDialogThrottler(DialogThrottleConfig{
+		SessionRequests: DialogLimit{Rate: 1, Burst: 2},
+		Payments:        DialogLimit{Rate: 1, Burst: 1},
+		BanAfter:        3,
+		BanDuration:     time.Minute,
+	}, publisher)
+	throttler.now = func() time.Time { return now }
+	return throttler, &now
+}
+
+func TestDialogThrottler_LimitsPerConsumerAndClass(t *testing.T) {
+	publisher := &mockPublisher{}
+	throttler, now := newTestThrottler(publisher)
+	consumer := identity.FromAddress("0x1")
+
+	assert.True(t, throttler.Allow(consumer, p2p.TopicSessionCreate))
+	assert.True(t, throttler.Allow(consumer, p2p.TopicSessionStatus))
+	assert.False(t, throttler.Allow(consumer, p2p.TopicSessionCreate))
+
+	// Payments and other consumers have their own buckets.
+	assert.True(t, throttler.Allow(consumer, p2p.TopicPaymentMessage))
+	assert.True(t, throttler.Allow(identity.FromAddress("0x2"), p2p.TopicSessionCreate))
+	// Topics without limits are never throttled.
+	assert.True(t, throttler.Allow(consumer, p2p.TopicKeepAlive))
+
+	*now = now.Add(time.Second)
+	assert.True(t, throttler.Allow(consumer, p2p.TopicSessionCreate))
+
+	assert.Equal(t, AppTopicDialogThrottled, publisher.publishedTopic)
+	assert.Equal(t, []interface{}{
+		AppEventDialogThrottled{ConsumerID: consumer, Topic: p2p.TopicSessionCreate},
+	}, publisher.publishedData)
+}
+
+func TestDialogThrottler_BansFloodingConsumer(t *testing.T) {
+	publisher := &mockPublisher{}
+	throttler, now := newTestThrottler(publisher)
+	consumer := identity.FromAddress("0x1")
+
+	assert.True(t, throttler.Allow(consumer, p2p.TopicPaymentMessage))
+	for i := 0; i < 3; i++ {
+		assert.False(t, throttler.Allow(consumer, p2p.TopicPaymentMessage))
+	}
+	last := publisher.publishedData[len(publisher.publishedData)-1].(AppEventDialogThrottled)
+	assert.Equal(t, now.Add(time.Minute), last.BannedUntil)
+
+	// Banned consumer is rejected on every topic class until the ban expires.
+	*now = now.Add(30 * time.Second)
+	assert.False(t, throttler.Allow(consumer, p2p.TopicSessionCreate))
+
+	*now = now.Add(31 * time.Second)
+	assert.True(t, throttler.Allow(consumer, p2p.TopicSessionCreate))
+}
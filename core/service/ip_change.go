@@ -0,0 +1,152 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/ip"
+	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+const endpointSendTimeout = 10 * time.Second
+
+type ipChangeServices interface {
+	List(includeAll bool) []*Instance
+	Restart(id ID) (ID, error)
+	Reannounce(id ID) error
+}
+
+type sessionLister interface {
+	GetAll() []*Session
+}
+
+// PublicIPChangeHandler reacts to provider public IP changes. Idle services are restarted to bind the new IP,
+// services with sessions keep running: consumers are sent the new endpoint over the p2p channel and proposals
+// are republished.
+type PublicIPChangeHandler struct {
+	services ipChangeServices
+	sessions sessionLister
+
+	mu        sync.Mutex
+	publicIP  string
+	connected bool
+}
+
+// NewPublicIPChangeHandler returns a new public IP change handler.
+func NewPublicIPChangeHandler(services ipChangeServices, sessions sessionLister) *PublicIPChangeHandler {
+	return &PublicIPChangeHandler{
+		services: services,
+		sessions: sessions,
+	}
+}
+
+// Subscribe subscribes to public IP changes and consumer connection state.
+func (h *PublicIPChangeHandler) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(connectionstate.AppTopicConnectionState, h.handleConnectionState); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(ip.AppTopicPublicIPChanged, h.handlePublicIPChanged)
+}
+
+func (h *PublicIPChangeHandler) handleConnectionState(e connectionstate.AppEventConnectionState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.connected = e.State != connectionstate.NotConnected
+}
+
+func (h *PublicIPChangeHandler) handlePublicIPChanged(e ip.AppEventPublicIPChanged) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// While connected as a consumer, public IP is the one of the VPN exit, not of this provider.
+	if h.connected {
+		return
+	}
+	// Changes back to the known IP, e.g. after consumer disconnects, are not changes for the provider.
+	if h.publicIP == "" {
+		h.publicIP = e.Previous
+	}
+	if h.publicIP == "" || h.publicIP == e.Current {
+		h.publicIP = e.Current
+		return
+	}
+	h.publicIP = e.Current
+
+	for _, instance := range h.services.List(false) {
+		if instance.State() != servicestate.Running {
+			continue
+		}
+
+		sessions := h.sessionsOf(instance.ID)
+		if len(sessions) == 0 {
+			log.Info().Msgf("Public IP changed, restarting idle service %s", instance.ID)
+			if _, err := h.services.Restart(instance.ID); err != nil {
+				log.Error().Err(err).Msgf("Failed to restart service %s", instance.ID)
+			}
+			continue
+		}
+
+		log.Info().Msgf("Public IP changed, sending new endpoint to %d session(s) of service %s", len(sessions), instance.ID)
+		for _, sess := range sessions {
+			if err := sendEndpoint(sess, e.Current); err != nil {
+				log.Warn().Err(err).Msgf("Could not send new endpoint for session %s", sess.ID)
+			}
+		}
+		if err := h.services.Reannounce(instance.ID); err != nil {
+			log.Error().Err(err).Msgf("Failed to republish proposal of service %s", instance.ID)
+		}
+	}
+}
+
+func (h *PublicIPChangeHandler) sessionsOf(id ID) (sessions []*Session) {
+	for _, session := range h.sessions.GetAll() {
+		if session.ServiceID == string(id) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+func sendEndpoint(sess *Session, endpoint string) error {
+	if sess.channel == nil {
+		return fmt.Errorf("session %s has no p2p channel", sess.ID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), endpointSendTimeout)
+	defer cancel()
+
+	msg := &pb.SessionEndpoint{
+		SessionID: string(sess.ID),
+		Endpoint:  endpoint,
+	}
+	if _, err := sess.channel.Send(ctx, p2p.TopicSessionEndpoint, p2p.ProtoMessage(msg)); err != nil {
+		return fmt.Errorf("could not send endpoint: %w", err)
+	}
+	return nil
+}
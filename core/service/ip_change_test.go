@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/ip"
+	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+type mockIPChangeServices struct {
+	instances   []*Instance
+	restarted   []ID
+	reannounced []ID
+}
+
+func (m *mockIPChangeServices) List(_ bool) []*Instance {
+	return m.instances
+}
+
+func (m *mockIPChangeServices) Restart(id ID) (ID, error) {
+	m.restarted = append(m.restarted, id)
+	return id + "-restarted", nil
+}
+
+func (m *mockIPChangeServices) Reannounce(id ID) error {
+	m.reannounced = append(m.reannounced, id)
+	return nil
+}
+
+type mockSessionLister []*Session
+
+func (m mockSessionLister) GetAll() []*Session {
+	return m
+}
+
+type endpointChannel struct {
+	mockP2PChannel
+	topics   []string
+	messages []*p2p.Message
+}
+
+func (m *endpointChannel) Send(_ context.Context, topic string, msg *p2p.Message) (*p2p.Message, error) {
+	m.topics = append(m.topics, topic)
+	m.messages = append(m.messages, msg)
+	return nil, nil
+}
+
+func TestPublicIPChangeHandler(t *testing.T) {
+	services := &mockIPChangeServices{instances: []*Instance{
+		{ID: "idle", state: servicestate.Running},
+		{ID: "busy", state: servicestate.Running},
+		{ID: "starting", state: servicestate.Starting},
+	}}
+	channel := &endpointChannel{}
+	handler := NewPublicIPChangeHandler(services, mockSessionLister{{ID: "session", ServiceID: "busy", channel: channel}})
+
+	handler.handlePublicIPChanged(ip.AppEventPublicIPChanged{Current: "1.1.1.1"})
+	assert.Empty(t, services.restarted)
+	assert.Empty(t, services.reannounced)
+
+	handler.handlePublicIPChanged(ip.AppEventPublicIPChanged{Previous: "1.1.1.1", Current: "2.2.2.2"})
+	assert.Equal(t, []ID{"idle"}, services.restarted)
+	assert.Equal(t, []ID{"busy"}, services.reannounced)
+
+	require.Equal(t, []string{p2p.TopicSessionEndpoint}, channel.topics)
+	var msg pb.SessionEndpoint
+	require.NoError(t, channel.messages[0].UnmarshalProto(&msg))
+	assert.Equal(t, "session", msg.GetSessionID())
+	assert.Equal(t, "2.2.2.2", msg.GetEndpoint())
+}
+
+func TestPublicIPChangeHandler_IgnoresConsumerConnections(t *testing.T) {
+	services := &mockIPChangeServices{instances: []*Instance{{ID: "idle", state: servicestate.Running}}}
+	handler := NewPublicIPChangeHandler(services, mockSessionLister{})
+
+	handler.handlePublicIPChanged(ip.AppEventPublicIPChanged{Current: "1.1.1.1"})
+
+	handler.handleConnectionState(connectionstate.AppEventConnectionState{State: connectionstate.Connected})
+	handler.handlePublicIPChanged(ip.AppEventPublicIPChanged{Previous: "1.1.1.1", Current: "9.9.9.9"})
+
+	handler.handleConnectionState(connectionstate.AppEventConnectionState{State: connectionstate.NotConnected})
+	handler.handlePublicIPChanged(ip.AppEventPublicIPChanged{Previous: "9.9.9.9", Current: "1.1.1.1"})
+
+	assert.Empty(t, services.restarted)
+	assert.Empty(t, services.reannounced)
+}
+
+func TestPublicIPChangeHandler_MissedInitialResolution(t *testing.T) {
+	services := &mockIPChangeServices{instances: []*Instance{{ID: "idle", state: servicestate.Running}}}
+	handler := NewPublicIPChangeHandler(services, mockSessionLister{})
+
+	handler.handlePublicIPChanged(ip.AppEventPublicIPChanged{Previous: "1.1.1.1", Current: "2.2.2.2"})
+	assert.Equal(t, []ID{"idle"}, services.restarted)
+}
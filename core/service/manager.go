@@ -30,6 +30,7 @@ import (
 	"github.com/mysteriumnetwork/node/core/service/servicestate"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/monitoring/resources"
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/services/datatransfer"
 	"github.com/mysteriumnetwork/node/services/scraping"
@@ -46,6 +47,8 @@ var (
 	ErrUnsupportedServiceType = errors.New("unsupported service type")
 	// ErrUnsupportedAccessPolicy indicates that manager tried to create service with unsupported access policy
 	ErrUnsupportedAccessPolicy = errors.New("unsupported access policy")
+	// ErrIdentityLocked indicates that manager tried to start service under identity which is not unlocked
+	ErrIdentityLocked = errors.New("provider identity is locked")
 )
 
 const (
@@ -74,6 +77,21 @@ type locationResolver interface {
 	DetectLocation() (locationstate.Location, error)
 }
 
+// cgnatStatus reports whether provider is behind carrier-grade NAT.
+type cgnatStatus interface {
+	BehindCGNAT() bool
+}
+
+// capacityReporter reports the measured provider capacity.
+type capacityReporter interface {
+	Capacity() *market.Capacity
+}
+
+// unlockChecker checks whether the identity is unlocked to provide services.
+type unlockChecker interface {
+	IsUnlocked(address string) bool
+}
+
 // WaitForNATHole blocks until NAT hole is punched towards consumer through local NAT or until hole punching failed
 type WaitForNATHole func() error
 
@@ -87,6 +105,12 @@ func NewManager(
 	sessionManager func(service *Instance, channel p2p.Channel) *SessionManager,
 	statusStorage connectivity.StatusStorage,
 	location locationResolver,
+	cgnat cgnatStatus,
+	throttler *DialogThrottler,
+	identities unlockChecker,
+	capacity capacityReporter,
+	mobile bool,
+	egress *market.EgressPolicy,
 ) *Manager {
 	return &Manager{
 		serviceRegistry:  serviceRegistry,
@@ -98,6 +122,12 @@ func NewManager(
 		sessionManager:   sessionManager,
 		statusStorage:    statusStorage,
 		location:         location,
+		cgnat:            cgnat,
+		throttler:        throttler,
+		identities:       identities,
+		capacity:         capacity,
+		mobile:           mobile,
+		egress:           egress,
 	}
 }
 
@@ -114,10 +144,17 @@ type Manager struct {
 	sessionManager func(service *Instance, channel p2p.Channel) *SessionManager
 	statusStorage  connectivity.StatusStorage
 	location       locationResolver
+	cgnat          cgnatStatus
+	throttler      *DialogThrottler
+	identities     unlockChecker
+	capacity       capacityReporter
+	mobile         bool
+	egress         *market.EgressPolicy
 }
 
 // Start starts an instance of the given service type if knows one in service registry.
 // It passes the options to the start method of the service.
+// Instances of the same type may run under different provider identities, each of them has to be unlocked.
 // If an error occurs in the underlying service, the error is then returned.
 func (manager *Manager) Start(providerID identity.Identity, serviceType string, policyIDs []string, options Options) (id ID, err error) {
 	log.Debug().Fields(map[string]interface{}{
@@ -126,6 +163,10 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 		"policyIDs":   policyIDs,
 		"options":     options,
 	}).Msg("Starting service")
+	if manager.identities != nil && !manager.identities.IsUnlocked(providerID.Address) {
+		return id, ErrIdentityLocked
+	}
+
 	service, err := manager.serviceRegistry.Create(serviceType, options)
 	if err != nil {
 		return id, err
@@ -147,9 +188,14 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 	}
 
 	proposal := market.NewProposal(providerID.Address, serviceType, market.NewProposalOpts{
-		Location:       market.NewLocation(location),
-		AccessPolicies: accessPolicies,
-		Contacts:       []market.Contact{manager.p2pListener.GetContact()},
+		Location:        market.NewLocation(location),
+		AccessPolicies:  accessPolicies,
+		Contacts:        []market.Contact{manager.p2pListener.GetContact()},
+		AddressFamilies: p2p.ReachableAddressFamilies(),
+		BehindCGNAT:     manager.cgnat != nil && manager.cgnat.BehindCGNAT(),
+		Capacity:        currentCapacity(manager.capacity),
+		Mobile:          manager.mobile,
+		Egress:          manager.egress,
 	})
 
 	discovery := manager.discoveryFactory()
@@ -171,6 +217,8 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 		discovery:      discovery,
 		eventPublisher: manager.eventPublisher,
 		location:       manager.location,
+		cgnat:          manager.cgnat,
+		capacity:       manager.capacity,
 	}
 
 	discovery.Start(providerID, instance.proposalWithCurrentLocation)
@@ -184,11 +232,12 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 		})
 		instance.addP2PChannel(ch)
 		mng := manager.sessionManager(instance, ch)
-		subscribeSessionCreate(mng, ch)
-		subscribeSessionStatus(ch, manager.statusStorage)
-		subscribeSessionAcknowledge(mng, ch)
-		subscribeSessionDestroy(mng, ch)
-		subscribeSessionPayments(mng, ch)
+		handler := throttleChannel(ch, manager.throttler)
+		subscribeSessionCreate(mng, handler)
+		subscribeSessionStatus(handler, manager.statusStorage)
+		subscribeSessionAcknowledge(mng, handler)
+		subscribeSessionDestroy(mng, handler)
+		subscribeSessionPayments(mng, handler)
 	}
 	stopP2PListener, err := manager.p2pListener.Listen(providerID, serviceType, channelHandlers)
 	if err != nil {
@@ -200,10 +249,13 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 	go func() {
 		instance.setState(servicestate.Running)
 
-		serveErr := service.Serve(instance)
-		if serveErr != nil {
-			log.Error().Err(serveErr).Msg("Service serve failed")
-		}
+		// Goroutines started by the service inherit its label, so they are counted in its resource usage.
+		resources.Do(string(id), func() {
+			serveErr := service.Serve(instance)
+			if serveErr != nil {
+				log.Error().Err(serveErr).Msg("Service serve failed")
+			}
+		})
 
 		stopP2PListener()
 
@@ -212,7 +264,7 @@ func (manager *Manager) Start(providerID identity.Identity, serviceType string,
 			log.Error().Err(stopErr).Msg("Service stop failed")
 		}
 
-		discovery.Wait()
+		instance.currentDiscovery().Wait()
 	}()
 
 	netutil.LogNetworkStats()
@@ -276,6 +328,46 @@ func (manager *Manager) Stop(id ID) error {
 	return nil
 }
 
+// Restart stops the service and starts it again with the same options and access policies.
+// Restarted service gets a new ID.
+func (manager *Manager) Restart(id ID) (ID, error) {
+	instance := manager.servicePool.Instance(id)
+	if instance == nil {
+		return "", ErrNoSuchInstance
+	}
+
+	var policyIDs []string
+	if instance.Proposal.AccessPolicies != nil {
+		for _, p := range *instance.Proposal.AccessPolicies {
+			policyIDs = append(policyIDs, p.ID)
+		}
+	}
+
+	if err := manager.Stop(id); err != nil {
+		return "", err
+	}
+
+	return manager.Start(instance.ProviderID, instance.Type, policyIDs, instance.Options)
+}
+
+// Reannounce unregisters the service proposal and registers it again from scratch.
+func (manager *Manager) Reannounce(id ID) error {
+	instance := manager.servicePool.Instance(id)
+	if instance == nil {
+		return ErrNoSuchInstance
+	}
+
+	discovery := manager.discoveryFactory()
+	old := instance.replaceDiscovery(discovery)
+	if old != nil {
+		old.Stop()
+		old.Wait()
+	}
+	discovery.Start(instance.ProviderID, instance.proposalWithCurrentLocation)
+
+	return nil
+}
+
 // Service returns a service instance by requested id.
 func (manager *Manager) Service(id ID) *Instance {
 	return manager.servicePool.Instance(id)
@@ -58,7 +58,7 @@ func TestManager_StartRemovesServiceFromPoolIfServiceCrashes(t *testing.T) {
 		discoveryFactory,
 		mocks.NewEventBus(),
 		mockPolicyOracle,
-		&mockP2PListener{}, nil, nil, mockLocationResolver{},
+		&mockP2PListener{}, nil, nil, mockLocationResolver{}, nil, nil, nil, nil, false, nil,
 	)
 	_, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{})
 	assert.Nil(t, err)
@@ -84,6 +84,12 @@ func TestManager_StartDoesNotCrashIfStoppedByUser(t *testing.T) {
 		mockPolicyOracle,
 		&mockP2PListener{}, nil, nil,
 		mockLocationResolver{},
+		nil,
+		nil,
+		nil,
+		nil,
+		false,
+		nil,
 	)
 	id, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{})
 	assert.Nil(t, err)
@@ -111,6 +117,12 @@ func TestManager_StopSendsEvent_SucceedsAndPublishesEvent(t *testing.T) {
 		mockPolicyOracle,
 		&mockP2PListener{}, nil, nil,
 		mockLocationResolver{},
+		nil,
+		nil,
+		nil,
+		nil,
+		false,
+		nil,
 	)
 
 	id, err := manager.Start(identity.FromAddress(proposalMock.ProviderID), serviceType, nil, struct{}{})
@@ -148,6 +160,50 @@ func TestManager_StopSendsEvent_SucceedsAndPublishesEvent(t *testing.T) {
 	assert.True(t, matchFound)
 }
 
+func TestManager_StartRunsServicesUnderUnlockedIdentitiesOnly(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(serviceType, func(options Options) (Service, error) {
+		mockCopy := *serviceMock
+		mockCopy.mockProcess = make(chan struct{})
+		return &mockCopy, nil
+	})
+
+	discovery := mockDiscovery{}
+	manager := NewManager(
+		registry,
+		MockDiscoveryFactoryFunc(&discovery),
+		mocks.NewEventBus(),
+		mockPolicyOracle,
+		&mockP2PListener{}, nil, nil,
+		mockLocationResolver{},
+		nil,
+		nil,
+		mockUnlockChecker{"0x1": true, "0x2": true},
+		nil,
+		false,
+		nil,
+	)
+
+	first, err := manager.Start(identity.FromAddress("0x1"), serviceType, nil, struct{}{})
+	assert.NoError(t, err)
+	second, err := manager.Start(identity.FromAddress("0x2"), serviceType, nil, struct{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, "0x1", manager.Service(first).ProviderID.Address)
+	assert.Equal(t, "0x2", manager.Service(second).ProviderID.Address)
+
+	_, err = manager.Start(identity.FromAddress("0x3"), serviceType, nil, struct{}{})
+	assert.Equal(t, ErrIdentityLocked, err)
+	assert.Len(t, manager.List(false), 2)
+
+	assert.NoError(t, manager.Kill())
+}
+
+type mockUnlockChecker map[string]bool
+
+func (m mockUnlockChecker) IsUnlocked(address string) bool {
+	return m[address]
+}
+
 type mockP2PListener struct {
 }
 
@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/session"
+	"github.com/mysteriumnetwork/node/session/notice"
+)
+
+const noticeSendTimeout = 10 * time.Second
+
+// NoticeSender pushes short human-readable notices to connected consumers.
+type NoticeSender struct {
+	sessions *SessionPool
+	interval time.Duration
+}
+
+// NewNoticeSender returns a new instance of NoticeSender.
+func NewNoticeSender(sessions *SessionPool) *NoticeSender {
+	return &NoticeSender{
+		sessions: sessions,
+		interval: notice.DefaultInterval,
+	}
+}
+
+// Send sends notice to consumer of the given session.
+func (ns *NoticeSender) Send(sessionID string, message string) error {
+	if err := notice.Validate(message); err != nil {
+		return err
+	}
+
+	sess, found := ns.sessions.Find(session.ID(sessionID))
+	if !found {
+		return ErrorSessionNotExists
+	}
+
+	return ns.send(sess, message)
+}
+
+// Broadcast sends notice to consumers of all active sessions. Returns a number of consumers notified.
+func (ns *NoticeSender) Broadcast(message string) (int, error) {
+	if err := notice.Validate(message); err != nil {
+		return 0, err
+	}
+
+	var sent int
+	for _, sess := range ns.sessions.GetAll() {
+		if err := ns.send(sess, message); err != nil {
+			log.Warn().Err(err).Msgf("Could not send notice for session %s", sess.ID)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (ns *NoticeSender) send(sess *Session, message string) error {
+	if sess.channel == nil {
+		return fmt.Errorf("session %s has no p2p channel", sess.ID)
+	}
+
+	sess.noticeLock.Lock()
+	defer sess.noticeLock.Unlock()
+
+	if !sess.lastNoticeAt.IsZero() && time.Since(sess.lastNoticeAt) < ns.interval {
+		return notice.ErrRateLimited
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), noticeSendTimeout)
+	defer cancel()
+
+	msg := &pb.SessionNotice{
+		SessionID: string(sess.ID),
+		Message:   message,
+	}
+	if _, err := sess.channel.Send(ctx, p2p.TopicSessionNotice, p2p.ProtoMessage(msg)); err != nil {
+		return fmt.Errorf("could not send notice: %w", err)
+	}
+	sess.lastNoticeAt = time.Now()
+
+	return nil
+}
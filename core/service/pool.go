@@ -157,12 +157,16 @@ type Instance struct {
 	Options         Options
 	service         Service
 	Proposal        market.ServiceProposal
+	proposalLock    sync.Mutex
 	policies        *policy.Repository
+	discoveryLock   sync.Mutex
 	discovery       Discovery
 	eventPublisher  Publisher
 	p2pChannelsLock sync.Mutex
 	p2pChannels     []p2p.Channel
 	location        locationResolver
+	cgnat           cgnatStatus
+	capacity        capacityReporter
 }
 
 // Service returns the running service implementation.
@@ -183,6 +187,15 @@ func (i *Instance) State() servicestate.State {
 }
 
 func (i *Instance) proposalWithCurrentLocation() market.ServiceProposal {
+	i.proposalLock.Lock()
+	defer i.proposalLock.Unlock()
+
+	// CGNAT detection runs in background at startup and may finish after the service was started.
+	if i.cgnat != nil {
+		i.Proposal.BehindCGNAT = i.cgnat.BehindCGNAT()
+	}
+	i.Proposal.Capacity = currentCapacity(i.capacity)
+
 	location, err := i.location.DetectLocation()
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to get current location for proposal, using last known location")
@@ -194,6 +207,13 @@ func (i *Instance) proposalWithCurrentLocation() market.ServiceProposal {
 	return i.Proposal
 }
 
+func currentCapacity(capacity capacityReporter) *market.Capacity {
+	if capacity == nil {
+		return nil
+	}
+	return capacity.Capacity()
+}
+
 func (i *Instance) setState(newState servicestate.State) {
 	i.stateLock.Lock()
 	defer i.stateLock.Unlock()
@@ -209,10 +229,24 @@ func (i *Instance) addP2PChannel(ch p2p.Channel) {
 	i.p2pChannels = append(i.p2pChannels, ch)
 }
 
+func (i *Instance) currentDiscovery() Discovery {
+	i.discoveryLock.Lock()
+	defer i.discoveryLock.Unlock()
+	return i.discovery
+}
+
+func (i *Instance) replaceDiscovery(discovery Discovery) Discovery {
+	i.discoveryLock.Lock()
+	defer i.discoveryLock.Unlock()
+	old := i.discovery
+	i.discovery = discovery
+	return old
+}
+
 func (i *Instance) stop() error {
 	errStop := utils.ErrorCollection{}
-	if i.discovery != nil {
-		i.discovery.Stop()
+	if discovery := i.currentDiscovery(); discovery != nil {
+		discovery.Stop()
 	}
 	if i.service != nil {
 		errStop.Add(i.service.Stop())
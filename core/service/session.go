@@ -22,11 +22,11 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/gofrs/uuid"
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/node/session"
 	"github.com/mysteriumnetwork/node/session/event"
@@ -44,10 +44,17 @@ type Session struct {
 	CreatedAt        time.Time
 	request          *pb.SessionRequest
 	done             chan struct{}
+	acknowledged     chan struct{}
+	reservationLock  sync.Mutex
+	reservation      reservationState
+	commit           CommitCallback
 	cleanupLock      sync.Mutex
 	cleanup          []func() error
 	tracer           *trace.Tracer
 	once             sync.Once
+	channel          p2p.ChannelSender
+	noticeLock       sync.Mutex
+	lastNoticeAt     time.Time
 }
 
 // Close ends session.
@@ -74,6 +81,54 @@ func (s *Session) Done() <-chan struct{} {
 	return s.done
 }
 
+// Acknowledged returns readonly channel which is closed once consumer confirms config receipt and reservation is committed.
+func (s *Session) Acknowledged() <-chan struct{} {
+	return s.acknowledged
+}
+
+type reservationState int
+
+const (
+	reservationReserved reservationState = iota
+	reservationCommitted
+	reservationReleased
+)
+
+// commitReservation allocates resources deferred until consumer acknowledges the session.
+// Returns false if the reservation was already committed.
+func (s *Session) commitReservation() (bool, error) {
+	s.reservationLock.Lock()
+	defer s.reservationLock.Unlock()
+
+	switch s.reservation {
+	case reservationCommitted:
+		return false, nil
+	case reservationReleased:
+		return false, ErrorReservationExpired
+	}
+
+	if s.commit != nil {
+		if err := s.commit(); err != nil {
+			return false, err
+		}
+	}
+	s.reservation = reservationCommitted
+	close(s.acknowledged)
+	return true, nil
+}
+
+// releaseReservation marks uncommitted reservation as released. Returns false if it was committed already.
+func (s *Session) releaseReservation() bool {
+	s.reservationLock.Lock()
+	defer s.reservationLock.Unlock()
+
+	if s.reservation != reservationReserved {
+		return false
+	}
+	s.reservation = reservationReleased
+	return true
+}
+
 func (s *Session) addCleanup(fn func() error) {
 	s.cleanupLock.Lock()
 	defer s.cleanupLock.Unlock()
@@ -108,20 +163,18 @@ func (s *Session) toEvent(status event.Status) event.AppEventSession {
 	}
 }
 
-// NewSession creates a blank new session with an ID.
+// NewSession creates a blank new session with a random ID.
 func NewSession(service *Instance, request *pb.SessionRequest, tracer *trace.Tracer) (*Session, error) {
-	uid, err := uuid.NewV4()
-	if err != nil {
-		return nil, err
-	}
+	return newSession(service, request, tracer, &UUIDv4SessionIDGenerator{})
+}
 
+func newSession(service *Instance, request *pb.SessionRequest, tracer *trace.Tracer, idGenerator SessionIDGenerator) (*Session, error) {
 	var consumerLocation market.Location
 	if location := request.GetConsumer().GetLocation(); location != nil {
 		consumerLocation.Country = location.GetCountry()
 	}
 
-	return &Session{
-		ID:               session.ID(uid.String()),
+	s := &Session{
 		ConsumerID:       identity.FromAddress(request.GetConsumer().GetId()),
 		ConsumerLocation: consumerLocation,
 		HermesID:         common.HexToAddress(request.GetConsumer().GetHermesID()),
@@ -130,7 +183,25 @@ func NewSession(service *Instance, request *pb.SessionRequest, tracer *trace.Tra
 		CreatedAt:        time.Now().UTC(),
 		request:          request,
 		done:             make(chan struct{}),
+		acknowledged:     make(chan struct{}),
 		cleanup:          make([]func() error, 0),
 		tracer:           tracer,
-	}, nil
+	}
+
+	id, err := idGenerator.Generate(s.idInput(0))
+	if err != nil {
+		return nil, err
+	}
+	s.ID = id
+
+	return s, nil
+}
+
+func (s *Session) idInput(attempt int) SessionIDInput {
+	return SessionIDInput{
+		ConsumerID: s.ConsumerID,
+		ServiceID:  s.ServiceID,
+		CreatedAt:  s.CreatedAt,
+		Attempt:    attempt,
+	}
 }
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/session"
+)
+
+const (
+	// SessionIDUUIDv4 generates random UUIDv4 session IDs.
+	SessionIDUUIDv4 = "uuidv4"
+	// SessionIDUUIDv7 generates time ordered UUIDv7 session IDs.
+	SessionIDUUIDv7 = "uuidv7"
+	// SessionIDHMAC derives session IDs from session attributes using operator's secret key.
+	SessionIDHMAC = "hmac"
+
+	// maxSessionIDAttempts is a number of times session ID is regenerated on collision.
+	maxSessionIDAttempts = 5
+)
+
+// ErrSessionIDExists is returned when session with the same ID is already stored.
+var ErrSessionIDExists = errors.New("session with the same ID already exists")
+
+// SessionIDInput holds session attributes the session ID may be derived from.
+type SessionIDInput struct {
+	ConsumerID identity.Identity
+	ServiceID  string
+	CreatedAt  time.Time
+	// Attempt is increased each time the previously generated ID collides with an existing session.
+	Attempt int
+}
+
+// SessionIDGenerator generates IDs for new provider sessions.
+type SessionIDGenerator interface {
+	Generate(input SessionIDInput) (session.ID, error)
+}
+
+// NewSessionIDGenerator returns session ID generator of the given kind.
+// Secret is required by HMAC generator only.
+func NewSessionIDGenerator(kind, secret string) (SessionIDGenerator, error) {
+	switch kind {
+	case "", SessionIDUUIDv4:
+		return &UUIDv4SessionIDGenerator{}, nil
+	case SessionIDUUIDv7:
+		return &UUIDv7SessionIDGenerator{now: time.Now}, nil
+	case SessionIDHMAC:
+		if secret == "" {
+			return nil, errors.New("HMAC session ID generator requires a secret")
+		}
+		return NewHMACSessionIDGenerator([]byte(secret)), nil
+	}
+
+	return nil, fmt.Errorf("unknown session ID generator: %q", kind)
+}
+
+// UUIDv4SessionIDGenerator generates random session IDs.
+type UUIDv4SessionIDGenerator struct{}
+
+// Generate returns a new random session ID.
+func (g *UUIDv4SessionIDGenerator) Generate(_ SessionIDInput) (session.ID, error) {
+	uid, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+
+	return session.ID(uid.String()), nil
+}
+
+// UUIDv7SessionIDGenerator generates session IDs which sort by creation time.
+type UUIDv7SessionIDGenerator struct {
+	now func() time.Time
+}
+
+// Generate returns a new UUIDv7 session ID.
+func (g *UUIDv7SessionIDGenerator) Generate(_ SessionIDInput) (session.ID, error) {
+	var uid uuid.UUID
+	if _, err := rand.Read(uid[6:]); err != nil {
+		return "", err
+	}
+
+	// 48 bit big-endian unix timestamp in milliseconds, followed by version and variant bits.
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(g.now().UnixMilli()))
+	copy(uid[:6], ts[2:])
+	uid[6] = (uid[6] & 0x0f) | 0x70
+	uid.SetVariant(uuid.VariantRFC4122)
+
+	return session.ID(uid.String()), nil
+}
+
+// HMACSessionIDGenerator derives session IDs from session attributes, so that operators
+// holding the secret can correlate IDs with their own records.
+type HMACSessionIDGenerator struct {
+	secret []byte
+}
+
+// NewHMACSessionIDGenerator returns a new instance of HMACSessionIDGenerator.
+func NewHMACSessionIDGenerator(secret []byte) *HMACSessionIDGenerator {
+	return &HMACSessionIDGenerator{secret: secret}
+}
+
+// Generate returns session ID derived from the given session attributes.
+func (g *HMACSessionIDGenerator) Generate(input SessionIDInput) (session.ID, error) {
+	mac := hmac.New(sha256.New, g.secret)
+	fmt.Fprintf(mac, "%s|%s|%d|%d", input.ConsumerID.Address, input.ServiceID, input.CreatedAt.UnixNano(), input.Attempt)
+
+	var uid uuid.UUID
+	copy(uid[:], mac.Sum(nil))
+	// Custom UUIDv8 layout keeps IDs in the same format as random ones.
+	uid[6] = (uid[6] & 0x0f) | 0x80
+	uid.SetVariant(uuid.VariantRFC4122)
+
+	return session.ID(uid.String()), nil
+}
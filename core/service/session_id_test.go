@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/session"
+)
+
+func TestNewSessionIDGenerator(t *testing.T) {
+	for _, kind := range []string{"", SessionIDUUIDv4, SessionIDUUIDv7} {
+		_, err := NewSessionIDGenerator(kind, "")
+		assert.NoError(t, err, kind)
+	}
+
+	_, err := NewSessionIDGenerator(SessionIDHMAC, "")
+	assert.Error(t, err)
+	_, err = NewSessionIDGenerator(SessionIDHMAC, "secret")
+	assert.NoError(t, err)
+	_, err = NewSessionIDGenerator("sequential", "")
+	assert.Error(t, err)
+}
+
+func TestUUIDv7SessionIDGenerator_Generate(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	generator := &UUIDv7SessionIDGenerator{now: func() time.Time { return now }}
+
+	first, err := generator.Generate(SessionIDInput{})
+	assert.NoError(t, err)
+	now = now.Add(time.Millisecond)
+	second, err := generator.Generate(SessionIDInput{})
+	assert.NoError(t, err)
+
+	uid, err := uuid.FromString(string(first))
+	assert.NoError(t, err)
+	assert.Equal(t, byte(7), uid.Version())
+	assert.Equal(t, uuid.VariantRFC4122, uid.Variant())
+	assert.True(t, first < second)
+}
+
+func TestHMACSessionIDGenerator_Generate(t *testing.T) {
+	input := SessionIDInput{
+		ConsumerID: identity.FromAddress("0x1"),
+		ServiceID:  "service-1",
+		CreatedAt:  time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	id, err := NewHMACSessionIDGenerator([]byte("secret")).Generate(input)
+	assert.NoError(t, err)
+	same, _ := NewHMACSessionIDGenerator([]byte("secret")).Generate(input)
+	assert.Equal(t, id, same)
+
+	otherSecret, _ := NewHMACSessionIDGenerator([]byte("other")).Generate(input)
+	assert.NotEqual(t, id, otherSecret)
+
+	input.Attempt = 1
+	retried, _ := NewHMACSessionIDGenerator([]byte("secret")).Generate(input)
+	assert.NotEqual(t, id, retried)
+
+	_, err = uuid.FromString(string(id))
+	assert.NoError(t, err)
+}
+
+type sequenceIDGenerator struct {
+	ids []session.ID
+}
+
+func (g *sequenceIDGenerator) Generate(_ SessionIDInput) (session.ID, error) {
+	id := g.ids[0]
+	g.ids = g.ids[1:]
+	return id, nil
+}
+
+func TestSessionManager_AddSession_RegeneratesCollidingID(t *testing.T) {
+	pool := NewSessionPool(&mockPublisher{})
+	assert.NoError(t, pool.Add(&Session{ID: "taken"}))
+
+	manager := &SessionManager{
+		sessionStorage: pool,
+		config:         Config{IDGenerator: &sequenceIDGenerator{ids: []session.ID{"free"}}},
+	}
+
+	sess := &Session{ID: "taken"}
+	assert.NoError(t, manager.addSession(sess))
+	assert.Equal(t, session.ID("free"), sess.ID)
+	stored, _ := pool.Find("free")
+	assert.Exactly(t, sess, stored)
+}
+
+func TestSessionManager_AddSession_GivesUp(t *testing.T) {
+	pool := NewSessionPool(&mockPublisher{})
+	assert.NoError(t, pool.Add(&Session{ID: "taken"}))
+
+	ids := make([]session.ID, maxSessionIDAttempts)
+	for i := range ids {
+		ids[i] = "taken"
+	}
+	manager := &SessionManager{
+		sessionStorage: pool,
+		config:         Config{IDGenerator: &sequenceIDGenerator{ids: ids}},
+	}
+
+	err := manager.addSession(&Session{ID: "taken"})
+	assert.ErrorIs(t, err, ErrSessionIDExists)
+}
@@ -30,6 +30,8 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/abuse"
+	"github.com/mysteriumnetwork/node/core/policy"
 	"github.com/mysteriumnetwork/node/core/quality"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
@@ -37,6 +39,7 @@ import (
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/node/session"
+	"github.com/mysteriumnetwork/node/session/accounting"
 	sevent "github.com/mysteriumnetwork/node/session/event"
 	"github.com/mysteriumnetwork/node/utils/reftracker"
 	"github.com/mysteriumnetwork/payments/crypto"
@@ -47,8 +50,16 @@ var (
 	ErrorInvalidProposal = errors.New("proposal does not exist")
 	// ErrorSessionNotExists returned when consumer tries to destroy session that does not exists
 	ErrorSessionNotExists = errors.New("session does not exists")
+	// ErrorTrafficCapReached returned when the node has used its monthly traffic allowance
+	ErrorTrafficCapReached = errors.New("monthly traffic cap is reached")
+	// ErrorSessionLimitReached returned when the service already serves the maximum number of consumers
+	ErrorSessionLimitReached = errors.New("service session limit is reached")
+	// ErrorConsumerBanned returned when consumer is banned for abusive traffic
+	ErrorConsumerBanned = errors.New("consumer is banned for abuse")
 	// ErrorWrongSessionOwner returned when consumer tries to destroy session that does not belongs to him
 	ErrorWrongSessionOwner = errors.New("wrong session owner")
+	// ErrorReservationExpired returned when consumer acknowledges session after its reservation was released
+	ErrorReservationExpired = errors.New("session reservation expired")
 )
 
 // IDGenerator defines method for session id generation
@@ -57,6 +68,7 @@ type IDGenerator func() (session.ID, error)
 // ConfigParams session configuration parameters
 type ConfigParams struct {
 	SessionServiceConfig   ServiceConfiguration
+	SessionCommitCallback  CommitCallback
 	SessionDestroyCallback DestroyCallback
 }
 
@@ -78,6 +90,23 @@ type KeepAliveConfig struct {
 // Config contains common configuration options for session manager.
 type Config struct {
 	KeepAlive KeepAliveConfig
+	// ReservationTTL is a time given for consumer to acknowledge config receipt and commit session reservation.
+	// Reserved session resources are released if consumer doesn't acknowledge in time. Zero disables the expiry.
+	ReservationTTL time.Duration
+	// IDGenerator generates IDs for new sessions.
+	IDGenerator SessionIDGenerator
+	// Delegations keeps session keys consumers pay with. Payments signed by session keys are rejected when nil.
+	Delegations *identity.Delegations
+	// ConsumerLists are node-wide consumer allow and block lists checked in addition to service access policies.
+	ConsumerLists *policy.ConsumerLists
+	// TrafficMeter rejects new sessions once the monthly traffic cap is reached, traffic is not capped when nil.
+	TrafficMeter *accounting.TrafficMeter
+	// MaxSessions returns the number of concurrent consumers allowed per service, sessions are not limited when nil or zero.
+	MaxSessions func() int
+	// Abuse watches flows of sessions and terminates abusive ones, flows are not watched when nil.
+	Abuse *abuse.Detector
+	// Bans keeps consumers of sessions terminated for abuse, consumers are not banned when nil.
+	Bans *abuse.BanList
 }
 
 // DefaultConfig returns default params.
@@ -88,6 +117,8 @@ func DefaultConfig() Config {
 			SendTimeout:     5 * time.Second,
 			MaxSendErrCount: 5,
 		},
+		ReservationTTL: 2 * time.Minute,
+		IDGenerator:    &UUIDv4SessionIDGenerator{},
 	}
 }
 
@@ -96,6 +127,9 @@ type ConfigProvider interface {
 	ProvideConfig(sessionID string, sessionConfig json.RawMessage, conn *net.UDPConn) (*ConfigParams, error)
 }
 
+// CommitCallback allocates session resources which are deferred until consumer acknowledges the session.
+type CommitCallback func() error
+
 // DestroyCallback cleanups session
 type DestroyCallback func()
 
@@ -161,10 +195,12 @@ type SessionManager struct {
 	priceValidator       PriceValidator
 }
 
-// Start starts a session on the provider side for the given consumer.
-// Multiple sessions per peerID is possible in case different services are used
+// Start validates session request and reserves a session on the provider side for the given consumer.
+// Multiple sessions per peerID is possible in case different services are used.
+// Reservation has to be committed with Acknowledge once consumer receives the config,
+// otherwise it is released after the reservation TTL.
 func (manager *SessionManager) Start(request *pb.SessionRequest) (_ pb.SessionResponse, err error) {
-	session, err := NewSession(manager.service, request, manager.channel.Tracer())
+	session, err := newSession(manager.service, request, manager.channel.Tracer(), manager.idGenerator())
 	if err != nil {
 		return pb.SessionResponse{}, fmt.Errorf("cannot create new session: %w", err)
 	}
@@ -192,6 +228,8 @@ func (manager *SessionManager) Start(request *pb.SessionRequest) (_ pb.SessionRe
 	trace := session.tracer.StartStage("Provider session create")
 	defer func() {
 		session.tracer.EndStage(trace)
+		session.tracer.SetAttribute("service.type", manager.service.Type)
+		session.tracer.SetError(err)
 		traceResult := session.tracer.Finish(manager.publisher, string(session.ID))
 		log.Debug().Msgf("Provider connection trace: %s", traceResult)
 	}()
@@ -208,6 +246,31 @@ func (manager *SessionManager) Start(request *pb.SessionRequest) (_ pb.SessionRe
 	return manager.providerService(session, manager.channel)
 }
 
+func (manager *SessionManager) idGenerator() SessionIDGenerator {
+	if manager.config.IDGenerator == nil {
+		return &UUIDv4SessionIDGenerator{}
+	}
+	return manager.config.IDGenerator
+}
+
+// addSession stores the session, regenerating its ID if it collides with an existing session.
+func (manager *SessionManager) addSession(session *Session) error {
+	for attempt := 1; ; attempt++ {
+		err := manager.sessionStorage.Add(session)
+		if !errors.Is(err, ErrSessionIDExists) {
+			return err
+		}
+		if attempt >= maxSessionIDAttempts {
+			return fmt.Errorf("could not generate unique session ID in %d attempts: %w", attempt, err)
+		}
+
+		log.Warn().Msgf("Session ID %s collides with an existing session, regenerating", session.ID)
+		if session.ID, err = manager.idGenerator().Generate(session.idInput(attempt)); err != nil {
+			return err
+		}
+	}
+}
+
 func (manager *SessionManager) validatePrice(in market.Price, nodeType, country, serviceType string) error {
 	if !manager.priceValidator.IsPriceValid(in, nodeType, country, serviceType) {
 		return errors.New("consumer asking for invalid price")
@@ -231,7 +294,7 @@ func (manager *SessionManager) remapPricing(in *pb.Pricing) market.Price {
 	}
 }
 
-// Acknowledge marks the session as successfully established as far as the consumer is concerned.
+// Acknowledge commits session reservation once consumer confirms config receipt.
 func (manager *SessionManager) Acknowledge(consumerID identity.Identity, sessionID string) error {
 	session, found := manager.sessionStorage.Find(session.ID(sessionID))
 	if !found {
@@ -241,10 +304,41 @@ func (manager *SessionManager) Acknowledge(consumerID identity.Identity, session
 		return ErrorWrongSessionOwner
 	}
 
+	committed, err := session.commitReservation()
+	if err != nil {
+		log.Err(err).Msgf("Could not commit session %s reservation, disconnecting", session.ID)
+		session.Close()
+		return err
+	}
+	if !committed {
+		log.Debug().Msgf("Session %s is already acknowledged", session.ID)
+		return nil
+	}
+
 	manager.publisher.Publish(sevent.AppTopicSession, session.toEvent(sevent.AcknowledgedStatus))
 	return nil
 }
 
+// expireReservation releases reserved session resources if consumer vanishes before acknowledging the session.
+func (manager *SessionManager) expireReservation(session *Session) {
+	if manager.config.ReservationTTL <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(manager.config.ReservationTTL)
+	defer timer.Stop()
+
+	select {
+	case <-session.Acknowledged():
+	case <-session.Done():
+	case <-timer.C:
+		if session.releaseReservation() {
+			log.Warn().Msgf("Session %s was not acknowledged by consumer in %s, releasing reservation", session.ID, manager.config.ReservationTTL)
+			session.Close()
+		}
+	}
+}
+
 func (manager *SessionManager) startSession(session *Session, prices market.Price) error {
 	trace := session.tracer.StartStage("Provider session create (start)")
 	defer session.tracer.EndStage(trace)
@@ -255,12 +349,25 @@ func (manager *SessionManager) startSession(session *Session, prices market.Pric
 
 	manager.clearStaleSession(session.ConsumerID, manager.service.Type)
 
-	manager.sessionStorage.Add(session)
+	session.channel = manager.channel
+	if err := manager.addSession(session); err != nil {
+		return err
+	}
 	session.addCleanup(func() error {
 		manager.sessionStorage.Remove(session.ID)
 		return nil
 	})
+	if detector := manager.config.Abuse; detector != nil {
+		detector.Track(string(session.ID), func() {
+			manager.terminateAbusive(session)
+		})
+		session.addCleanup(func() error {
+			detector.Untrack(string(session.ID))
+			return nil
+		})
+	}
 
+	go manager.expireReservation(session)
 	go manager.keepAliveLoop(session, manager.channel)
 
 	return nil
@@ -270,10 +377,52 @@ func (manager *SessionManager) validateSession(session *Session, prices market.P
 	if !manager.service.Policies().IsIdentityAllowed(session.ConsumerID) {
 		return fmt.Errorf("consumer identity is not allowed: %s", session.ConsumerID.Address)
 	}
+	if lists := manager.config.ConsumerLists; lists != nil && !lists.IsIdentityAllowed(session.ConsumerID) {
+		return fmt.Errorf("consumer identity is not allowed by consumer lists: %s", session.ConsumerID.Address)
+	}
+	if bans := manager.config.Bans; bans != nil && bans.IsBanned(session.ConsumerID) {
+		return ErrorConsumerBanned
+	}
+	if meter := manager.config.TrafficMeter; meter != nil && meter.CapReached() {
+		return ErrorTrafficCapReached
+	}
+	if manager.sessionLimitReached(session) {
+		return ErrorSessionLimitReached
+	}
 
 	return manager.validatePrice(prices, manager.service.Proposal.Location.IPType, manager.service.Proposal.Location.Country, manager.service.Proposal.ServiceType)
 }
 
+// sessionLimitReached checks sessions of other consumers, the stale session of the same consumer is replaced.
+func (manager *SessionManager) sessionLimitReached(session *Session) bool {
+	if manager.config.MaxSessions == nil {
+		return false
+	}
+	limit := manager.config.MaxSessions()
+	if limit <= 0 {
+		return false
+	}
+
+	count := 0
+	for _, s := range manager.sessionStorage.GetAll() {
+		if s.ServiceID == session.ServiceID && s.ConsumerID != session.ConsumerID {
+			count++
+		}
+	}
+	return count >= limit
+}
+
+// terminateAbusive bans the consumer and closes the session which exceeded abuse limits too many times.
+func (manager *SessionManager) terminateAbusive(session *Session) {
+	if bans := manager.config.Bans; bans != nil {
+		if err := bans.Ban(session.ConsumerID.Address); err != nil {
+			log.Err(err).Msgf("Could not ban consumer %s", session.ConsumerID.Address)
+		}
+	}
+	log.Warn().Msgf("Terminating session %s of consumer %s for abuse", session.ID, session.ConsumerID.Address)
+	session.Close()
+}
+
 func (manager *SessionManager) clearStaleSession(consumerID identity.Identity, serviceType string) {
 	// Reading stale session before starting the clean up in goroutine.
 	// This is required to make sure we are not cleaning the newly created session.
@@ -325,18 +474,27 @@ func (manager *SessionManager) paymentLoop(session *Session, price market.Price)
 		err := engine.Start()
 		if err != nil {
 			log.Error().Err(err).Msg("Payment engine error")
+			manager.publishPaymentFailed(session, err)
 			session.Close()
 		}
 	}()
 
 	log.Info().Msg("Waiting for a first invoice to be paid")
 	if err := engine.WaitFirstInvoice(30 * time.Second); err != nil {
+		manager.publishPaymentFailed(session, err)
 		return fmt.Errorf("first invoice was not paid: %w", err)
 	}
 
 	return nil
 }
 
+func (manager *SessionManager) publishPaymentFailed(session *Session, err error) {
+	manager.publisher.Publish(sevent.AppTopicPaymentFailed, sevent.AppEventPaymentFailed{
+		SessionID: string(session.ID),
+		Error:     err.Error(),
+	})
+}
+
 func (manager *SessionManager) providerService(session *Session, channel p2p.Channel) (pb.SessionResponse, error) {
 	trace := session.tracer.StartStage("Provider session create (configure)")
 	defer session.tracer.EndStage(trace)
@@ -352,6 +510,9 @@ func (manager *SessionManager) providerService(session *Session, channel p2p.Cha
 			return nil
 		})
 	}
+	session.reservationLock.Lock()
+	session.commit = config.SessionCommitCallback
+	session.reservationLock.Unlock()
 
 	data, err := json.Marshal(config.SessionServiceConfig)
 	if err != nil {
@@ -19,23 +19,29 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"net"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/mysteriumnetwork/node/core/abuse"
 	"github.com/mysteriumnetwork/node/core/policy"
 	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/core/storage/memory"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/mocks"
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pb"
+	"github.com/mysteriumnetwork/node/session"
+	"github.com/mysteriumnetwork/node/session/accounting"
 	sessionEvent "github.com/mysteriumnetwork/node/session/event"
 	"github.com/mysteriumnetwork/node/trace"
 	"github.com/mysteriumnetwork/node/utils/reftracker"
@@ -177,7 +183,7 @@ func TestManager_Start_DisconnectsOnPaymentError(t *testing.T) {
 	assert.EqualError(t, err, "first invoice was not paid: sorry, your money ended")
 	assert.Eventually(t, func() bool {
 		history := publisher.GetEventHistory()
-		if len(history) != 6 {
+		if len(history) != 7 {
 			return false
 		}
 
@@ -188,24 +194,28 @@ func TestManager_Start_DisconnectsOnPaymentError(t *testing.T) {
 		assert.Equal(t, hermesID, startEvent.Session.HermesID)
 		assert.Equal(t, currentProposal, startEvent.Session.Proposal)
 
-		assert.Equal(t, trace.AppTopicTraceEvent, history[1].Topic)
-		traceEvent1 := history[1].Event.(trace.Event)
-		assert.Equal(t, "Provider connect", traceEvent1.Key)
+		assert.Equal(t, sessionEvent.AppTopicPaymentFailed, history[1].Topic)
+		paymentEvent := history[1].Event.(sessionEvent.AppEventPaymentFailed)
+		assert.Equal(t, "sorry, your money ended", paymentEvent.Error)
 
 		assert.Equal(t, trace.AppTopicTraceEvent, history[2].Topic)
-		traceEvent2 := history[2].Event.(trace.Event)
-		assert.Equal(t, "Provider session create", traceEvent2.Key)
+		traceEvent1 := history[2].Event.(trace.Event)
+		assert.Equal(t, "Provider connect", traceEvent1.Key)
 
 		assert.Equal(t, trace.AppTopicTraceEvent, history[3].Topic)
-		traceEvent3 := history[3].Event.(trace.Event)
-		assert.Equal(t, "Provider session create (start)", traceEvent3.Key)
+		traceEvent2 := history[3].Event.(trace.Event)
+		assert.Equal(t, "Provider session create", traceEvent2.Key)
 
 		assert.Equal(t, trace.AppTopicTraceEvent, history[4].Topic)
-		traceEvent4 := history[4].Event.(trace.Event)
+		traceEvent3 := history[4].Event.(trace.Event)
+		assert.Equal(t, "Provider session create (start)", traceEvent3.Key)
+
+		assert.Equal(t, trace.AppTopicTraceEvent, history[5].Topic)
+		traceEvent4 := history[5].Event.(trace.Event)
 		assert.Equal(t, "Provider session create (payment)", traceEvent4.Key)
 
-		assert.Equal(t, sessionEvent.AppTopicSession, history[5].Topic)
-		closeEvent := history[5].Event.(sessionEvent.AppEventSession)
+		assert.Equal(t, sessionEvent.AppTopicSession, history[6].Topic)
+		closeEvent := history[6].Event.(sessionEvent.AppEventSession)
 		assert.Equal(t, sessionEvent.RemovedStatus, closeEvent.Status)
 		assert.Equal(t, consumerID, closeEvent.Session.ConsumerID)
 		assert.Equal(t, hermesID, closeEvent.Session.HermesID)
@@ -306,6 +316,166 @@ func TestManager_AcknowledgeSession_PublishesEvent(t *testing.T) {
 	}, 2*time.Second, 10*time.Millisecond)
 }
 
+func TestManager_Start_ReleasesUnacknowledgedSession(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	manager := newManager(currentService, sessionStore, publisher, &mockBalanceTracker{}, true)
+	manager.config.ReservationTTL = 50 * time.Millisecond
+
+	_, err := manager.Start(&pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumerID.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, sessionStore.GetAll(), 1)
+
+	assert.Eventually(t, func() bool {
+		return len(sessionStore.GetAll()) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_Start_KeepsAcknowledgedSession(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	manager := newManager(currentService, sessionStore, publisher, &mockBalanceTracker{}, true)
+	manager.config.ReservationTTL = 50 * time.Millisecond
+
+	resp, err := manager.Start(&pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumerID.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	})
+	assert.NoError(t, err)
+
+	err = manager.Acknowledge(consumerID, resp.ID)
+	assert.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, sessionStore.GetAll(), 1)
+}
+
+// reservingService defers part of session resources allocation until reservation commit.
+type reservingService struct {
+	mockService
+	commitErr error
+	committed chan struct{}
+	destroyed chan struct{}
+}
+
+func newReservingService(commitErr error) *reservingService {
+	return &reservingService{
+		commitErr: commitErr,
+		committed: make(chan struct{}, 1),
+		destroyed: make(chan struct{}, 1),
+	}
+}
+
+func (rs *reservingService) ProvideConfig(_ string, _ json.RawMessage, _ *net.UDPConn) (*ConfigParams, error) {
+	return &ConfigParams{
+		SessionCommitCallback: func() error {
+			rs.committed <- struct{}{}
+			return rs.commitErr
+		},
+		SessionDestroyCallback: func() {
+			rs.destroyed <- struct{}{}
+		},
+	}, nil
+}
+
+func newReservingInstance(service *reservingService) *Instance {
+	return NewInstance(
+		identity.FromAddress(currentProposal.ProviderID),
+		currentProposal.ServiceType,
+		struct{}{},
+		currentProposal,
+		servicestate.Running,
+		service,
+		policy.NewRepository(),
+		&mockDiscovery{},
+	)
+}
+
+func startRequest() *pb.SessionRequest {
+	return &pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumerID.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	}
+}
+
+func TestManager_Acknowledge_CommitsReservation(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	service := newReservingService(nil)
+	manager := newManager(newReservingInstance(service), sessionStore, publisher, &mockBalanceTracker{}, true)
+
+	resp, err := manager.Start(startRequest())
+	assert.NoError(t, err)
+	assert.Len(t, service.committed, 0)
+
+	assert.NoError(t, manager.Acknowledge(consumerID, resp.ID))
+	assert.Len(t, service.committed, 1)
+
+	// Repeated acknowledge does not allocate resources again.
+	assert.NoError(t, manager.Acknowledge(consumerID, resp.ID))
+	assert.Len(t, service.committed, 1)
+	assert.Len(t, sessionStore.GetAll(), 1)
+}
+
+func TestManager_Acknowledge_ClosesSessionWhenCommitFails(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	service := newReservingService(errors.New("no NAT for you"))
+	manager := newManager(newReservingInstance(service), sessionStore, publisher, &mockBalanceTracker{}, true)
+
+	resp, err := manager.Start(startRequest())
+	assert.NoError(t, err)
+
+	assert.EqualError(t, manager.Acknowledge(consumerID, resp.ID), "no NAT for you")
+	assert.Len(t, service.destroyed, 1)
+	assert.Len(t, sessionStore.GetAll(), 0)
+}
+
+func TestManager_Acknowledge_RejectsExpiredReservation(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	service := newReservingService(nil)
+	manager := newManager(newReservingInstance(service), sessionStore, publisher, &mockBalanceTracker{}, true)
+	manager.config.ReservationTTL = 50 * time.Millisecond
+
+	resp, err := manager.Start(startRequest())
+	assert.NoError(t, err)
+	reserved, found := sessionStore.Find(session.ID(resp.ID))
+	assert.True(t, found)
+
+	assert.Eventually(t, func() bool {
+		return len(service.destroyed) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_, err = reserved.commitReservation()
+	assert.ErrorIs(t, err, ErrorReservationExpired)
+	assert.Len(t, service.committed, 0)
+}
+
 func newManager(service *Instance, sessions *SessionPool, publisher publisher, paymentEngine PaymentEngine, isPriceValid bool) *SessionManager {
 	ch := &mockP2PChannel{tracer: trace.NewTracer("Provider connect")}
 	m := NewSessionManager(
@@ -352,3 +522,133 @@ type mockPriceValidator struct {
 func (mpv *mockPriceValidator) IsPriceValid(in market.Price, nodeType, country, ServiceType string) bool {
 	return mpv.toReturn
 }
+
+func TestManager_Start_RejectsConsumerNotAllowedByLists(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	paymentEngine := &mockBalanceTracker{paymentError: errors.New("payments should not start")}
+	manager := newManager(currentService, sessionStore, publisher, paymentEngine, true)
+
+	lists, err := policy.NewConsumerLists(t.TempDir(), nil, nil, http.DefaultClient)
+	assert.NoError(t, err)
+	assert.NoError(t, lists.Add(policy.ConsumerAllowlist, "0x000000000000000000000000000000000000000a"))
+	manager.config.ConsumerLists = lists
+
+	_, err = manager.Start(&pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumerID.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	})
+	assert.EqualError(t, err, "consumer identity is not allowed by consumer lists: deadbeef")
+	assert.Len(t, sessionStore.GetAll(), 0)
+}
+
+func TestManager_Start_RejectsSessionsWhenTrafficCapIsReached(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	paymentEngine := &mockBalanceTracker{paymentError: errors.New("payments should not start")}
+	manager := newManager(currentService, sessionStore, publisher, paymentEngine, true)
+
+	traffic := accounting.NewTrafficStorage(memory.NewStorage())
+	today := time.Now().UTC()
+	assert.NoError(t, traffic.Store(accounting.DailyTraffic{
+		Day:  today.Format("2006-01-02"),
+		Date: today.Truncate(24 * time.Hour),
+		Sent: 100,
+	}))
+	meter := accounting.NewTrafficMeter(traffic, accounting.CapConfig{MonthlyBytes: 100}, publisher, nil)
+	assert.NoError(t, meter.Load())
+	manager.config.TrafficMeter = meter
+
+	_, err := manager.Start(&pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumerID.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	})
+	assert.ErrorIs(t, err, ErrorTrafficCapReached)
+	assert.Len(t, sessionStore.GetAll(), 0)
+}
+
+func TestManager_Start_RejectsSessionsWhenLimitIsReached(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	manager := newManager(currentService, sessionStore, publisher, &mockBalanceTracker{}, true)
+	manager.config.MaxSessions = func() int { return 1 }
+
+	request := func(consumer identity.Identity) *pb.SessionRequest {
+		return &pb.SessionRequest{
+			Consumer: &pb.ConsumerInfo{
+				Id:       consumer.Address,
+				HermesID: hermesID.String(),
+				Pricing: &pb.Pricing{
+					PerGib:  big.NewInt(1).Bytes(),
+					PerHour: big.NewInt(1).Bytes(),
+				},
+			},
+			ProposalID: int64(currentProposalID),
+		}
+	}
+
+	_, err := manager.Start(request(consumerID))
+	assert.NoError(t, err)
+
+	_, err = manager.Start(request(identity.FromAddress("0xbeef")))
+	assert.ErrorIs(t, err, ErrorSessionLimitReached)
+
+	_, err = manager.Start(request(consumerID))
+	assert.NoError(t, err, "same consumer replaces its stale session")
+}
+
+func TestManager_Start_TerminatesAbusiveSessionAndBansConsumer(t *testing.T) {
+	publisher := mocks.NewEventBus()
+	sessionStore := NewSessionPool(publisher)
+	manager := newManager(currentService, sessionStore, publisher, &mockBalanceTracker{}, true)
+
+	detector := abuse.NewDetector(abuse.Limits{NewFlows: 1, Window: time.Minute, Strikes: 1})
+	bans, err := abuse.NewBanList(t.TempDir(), time.Hour)
+	assert.NoError(t, err)
+	manager.config.Abuse = detector
+	manager.config.Bans = bans
+
+	consumer := identity.FromAddress("0x000000000000000000000000000000000000000a")
+	request := &pb.SessionRequest{
+		Consumer: &pb.ConsumerInfo{
+			Id:       consumer.Address,
+			HermesID: hermesID.String(),
+			Pricing: &pb.Pricing{
+				PerGib:  big.NewInt(1).Bytes(),
+				PerHour: big.NewInt(1).Bytes(),
+			},
+		},
+		ProposalID: int64(currentProposalID),
+	}
+
+	_, err = manager.Start(request)
+	assert.NoError(t, err)
+	sessions := sessionStore.GetAll()
+	assert.Len(t, sessions, 1)
+	sessionID := string(sessions[0].ID)
+	assert.Equal(t, []string{sessionID}, detector.Sessions())
+
+	assert.True(t, detector.Allow(sessionID, net.ParseIP("1.1.1.1"), 22))
+	assert.False(t, detector.Allow(sessionID, net.ParseIP("1.1.1.1"), 23))
+	assert.Eventually(t, func() bool {
+		return len(sessionStore.GetAll()) == 0 && len(detector.Sessions()) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.True(t, bans.IsBanned(consumer))
+
+	_, err = manager.Start(request)
+	assert.ErrorIs(t, err, ErrorConsumerBanned)
+}
@@ -43,13 +43,19 @@ type SessionPool struct {
 }
 
 // Add puts given session to storage and publishes a creation event.
-// Multiple sessions per peerID is possible in case different services are used
-func (sp *SessionPool) Add(instance *Session) {
+// Multiple sessions per peerID is possible in case different services are used.
+// ErrSessionIDExists is returned if another session with the same ID is already stored.
+func (sp *SessionPool) Add(instance *Session) error {
 	sp.lock.Lock()
 	defer sp.lock.Unlock()
 
+	if _, found := sp.sessions[instance.ID]; found {
+		return ErrSessionIDExists
+	}
+
 	sp.sessions[instance.ID] = instance
 	sp.publisher.Publish(event.AppTopicSession, instance.toEvent(event.CreatedStatus))
+	return nil
 }
 
 // GetAll returns all sessions in storage
@@ -67,6 +67,15 @@ func TestSessionPool_Add(t *testing.T) {
 	)
 }
 
+func TestSessionPool_Add_RejectsDuplicateID(t *testing.T) {
+	pool := mockPool(mocks.NewEventBus(), sessionExisting)
+	duplicate := &Session{ID: sessionExisting.ID}
+
+	assert.Equal(t, ErrSessionIDExists, pool.Add(duplicate))
+	instance, _ := pool.Find(sessionExisting.ID)
+	assert.Exactly(t, sessionExisting, instance)
+}
+
 func TestSessionPool_Add_PublishesEvents(t *testing.T) {
 	// given
 	session, _ := NewSession(&Instance{}, &pb.SessionRequest{}, trace.NewTracer(""))
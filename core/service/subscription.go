@@ -18,6 +18,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -147,6 +148,12 @@ func subscribeSessionPayments(mng *SessionManager, ch p2p.ChannelHandler) {
 		}
 		log.Debug().Msgf("Received P2P message for %q: %s", p2p.TopicPaymentMessage, msg.String())
 
+		if d := msg.GetDelegation(); d != nil {
+			if err := addSessionKeyDelegation(mng.config.Delegations, c.PeerID(), d); err != nil {
+				return fmt.Errorf("invalid session key delegation: %w", err)
+			}
+		}
+
 		amount, ok := new(big.Int).SetString(msg.GetPromise().GetAmount(), bigIntBase)
 		if !ok {
 			return fmt.Errorf("could not unmarshal field amount of value %v", amount)
@@ -188,3 +195,21 @@ func subscribeSessionPayments(mng *SessionManager, ch p2p.ChannelHandler) {
 		return nil
 	})
 }
+
+func addSessionKeyDelegation(delegations *identity.Delegations, peerID identity.Identity, d *pb.Delegation) error {
+	if delegations == nil {
+		return errors.New("session keys are not accepted")
+	}
+
+	delegation := identity.Delegation{
+		Identity:     d.GetIdentity(),
+		SessionKey:   d.GetSessionKey(),
+		ValidUntil:   time.Unix(d.GetValidUntil(), 0).UTC(),
+		Signature:    d.GetSignature(),
+		KeySignature: d.GetKeySignature(),
+	}
+	if identity.FromAddress(delegation.Identity) != peerID {
+		return fmt.Errorf("delegation is issued by %s instead of %s", delegation.Identity, peerID.Address)
+	}
+	return delegations.Add(delegation)
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package slo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/asdine/storm/v3/q"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const auditBucket = "slo-actions"
+
+// ActionRecord is an audit entry of a self-healing action taken by the monitor.
+type ActionRecord struct {
+	ID       string `storm:"id"`
+	Time     time.Time
+	Action   string
+	Breaches []string
+	Error    string
+}
+
+// AuditStorage keeps records of self-healing actions.
+type AuditStorage struct {
+	storage storage.Store
+}
+
+// NewAuditStorage returns a new instance of AuditStorage.
+func NewAuditStorage(storage storage.Store) *AuditStorage {
+	return &AuditStorage{
+		storage: storage,
+	}
+}
+
+// Store stores a given action record.
+func (as *AuditStorage) Store(record ActionRecord) error {
+	return as.storage.Store(auditBucket, &record)
+}
+
+// List returns stored action records, newest first.
+func (as *AuditStorage) List() (result []ActionRecord, err error) {
+	query := storage.Query{
+		OrderBy: "Time",
+		Reverse: true,
+	}
+
+	err = as.storage.Find(auditBucket, query, &result)
+	if errors.Is(err, storage.ErrNotFound) {
+		return []ActionRecord{}, nil
+	}
+	return result, err
+}
+
+// PruneBefore deletes action records older than the given time.
+func (as *AuditStorage) PruneBefore(before time.Time) error {
+	return as.storage.DeleteMatching(auditBucket, q.Lt("Time", before.UTC()), new(ActionRecord))
+}
@@ -0,0 +1,340 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package slo
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+)
+
+// AppTopicActionTaken is a topic for publishing self-healing actions taken by the monitor.
+const AppTopicActionTaken = "SLO action taken"
+
+// Names of the self-healing actions known to the node.
+const (
+	ActionRedetectNAT        = "redetect-nat"
+	ActionReregisterProposal = "reregister-proposal"
+	ActionRestartService     = "restart-service"
+)
+
+// Action is a self-healing action run when service level objectives are breached.
+type Action func() error
+
+// Config holds service level objectives and monitor settings.
+type Config struct {
+	// MinSessionSuccessRate is the lowest acceptable share of sessions acknowledged by consumers.
+	MinSessionSuccessRate float64
+	// MaxMedianTTFB is the highest acceptable median time from session creation to the first transferred byte.
+	MaxMedianTTFB time.Duration
+	// MaxPaymentFailureRate is the highest acceptable share of sessions with failed payments.
+	MaxPaymentFailureRate float64
+	// Window is a period of finished sessions taken into account.
+	Window time.Duration
+	// MinSessions is a number of finished sessions in the window needed before objectives are evaluated.
+	MinSessions int
+	// CheckInterval is how often objectives are evaluated.
+	CheckInterval time.Duration
+	// Cooldown is a minimum time between two consecutive actions.
+	Cooldown time.Duration
+	// Actions are action names run one per breach, escalating in the given order.
+	Actions []string
+}
+
+// DefaultConfig returns default monitor settings.
+func DefaultConfig() Config {
+	return Config{
+		MinSessionSuccessRate: 0.5,
+		MaxMedianTTFB:         15 * time.Second,
+		MaxPaymentFailureRate: 0.3,
+		Window:                time.Hour,
+		MinSessions:           10,
+		CheckInterval:         time.Minute,
+		Cooldown:              15 * time.Minute,
+		Actions:               []string{ActionRedetectNAT, ActionReregisterProposal, ActionRestartService},
+	}
+}
+
+// Report is a snapshot of measured service level indicators.
+type Report struct {
+	Sessions           int           `json:"sessions"`
+	SessionSuccessRate float64       `json:"session_success_rate"`
+	MedianTTFB         time.Duration `json:"median_ttfb"`
+	PaymentFailureRate float64       `json:"payment_failure_rate"`
+	Breaches           []string      `json:"breaches"`
+}
+
+// Breached returns true if any of the objectives is not met.
+func (r Report) Breached() bool {
+	return len(r.Breaches) > 0
+}
+
+type auditStorage interface {
+	Store(record ActionRecord) error
+}
+
+type activeSession struct {
+	createdAt     time.Time
+	ttfb          time.Duration
+	acknowledged  bool
+	paymentFailed bool
+}
+
+type sessionOutcome struct {
+	finishedAt    time.Time
+	ttfb          time.Duration
+	acknowledged  bool
+	paymentFailed bool
+}
+
+// Monitor tracks provider sessions against service level objectives and runs self-healing actions on breach.
+type Monitor struct {
+	config    Config
+	actions   map[string]Action
+	audit     auditStorage
+	publisher eventbus.Publisher
+	now       func() time.Time
+
+	mu         sync.Mutex
+	active     map[string]*activeSession
+	outcomes   []sessionOutcome
+	escalation int
+	lastAction time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMonitor returns a new instance of Monitor.
+func NewMonitor(config Config, actions map[string]Action, audit auditStorage, publisher eventbus.Publisher) *Monitor {
+	return &Monitor{
+		config:    config,
+		actions:   actions,
+		audit:     audit,
+		publisher: publisher,
+		now:       time.Now,
+		active:    make(map[string]*activeSession),
+		stop:      make(chan struct{}),
+	}
+}
+
+// Subscribe subscribes the monitor to provider session events.
+func (m *Monitor) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(sessionEvent.AppTopicSession, m.consumeSessionEvent); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(sessionEvent.AppTopicDataTransferred, m.consumeDataTransferredEvent); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(sessionEvent.AppTopicPaymentFailed, m.consumePaymentFailedEvent)
+}
+
+// Start periodically evaluates objectives until stopped.
+func (m *Monitor) Start() {
+	ticker := time.NewTicker(m.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.Check()
+		}
+	}
+}
+
+// Stop stops the monitor.
+func (m *Monitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+// Report returns service level indicators measured over the configured window.
+func (m *Monitor) Report() Report {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.report()
+}
+
+// Check evaluates objectives and runs the next self-healing action if any of them is breached.
+func (m *Monitor) Check() {
+	m.mu.Lock()
+	report := m.report()
+	if !report.Breached() {
+		m.escalation = 0
+		m.mu.Unlock()
+		return
+	}
+	now := m.now()
+	if len(m.config.Actions) == 0 || now.Sub(m.lastAction) < m.config.Cooldown {
+		m.mu.Unlock()
+		return
+	}
+	name := m.config.Actions[m.escalation%len(m.config.Actions)]
+	m.escalation++
+	m.lastAction = now
+	// Sessions measured before the action say nothing about its result.
+	m.outcomes = nil
+	m.mu.Unlock()
+
+	m.runAction(name, report)
+}
+
+func (m *Monitor) runAction(name string, report Report) {
+	record := ActionRecord{
+		Time:     m.now().UTC(),
+		Action:   name,
+		Breaches: report.Breaches,
+	}
+	record.ID = fmt.Sprintf("%d-%s", record.Time.UnixNano(), name)
+
+	log.Warn().Msgf("Service level objectives breached (%v), running action %q", report.Breaches, name)
+	action, ok := m.actions[name]
+	if !ok {
+		record.Error = "unknown action"
+	} else if err := action(); err != nil {
+		record.Error = err.Error()
+	}
+	if record.Error != "" {
+		log.Error().Msgf("Self-healing action %q failed: %s", name, record.Error)
+	}
+
+	if err := m.audit.Store(record); err != nil {
+		log.Error().Err(err).Msg("Failed to store self-healing action record")
+	}
+	m.publisher.Publish(AppTopicActionTaken, record)
+}
+
+func (m *Monitor) report() Report {
+	from := m.now().Add(-m.config.Window)
+	var (
+		total, acknowledged, paymentFailed int
+		ttfbs                              []time.Duration
+	)
+	kept := m.outcomes[:0]
+	for _, o := range m.outcomes {
+		if o.finishedAt.Before(from) {
+			continue
+		}
+		kept = append(kept, o)
+
+		total++
+		if o.acknowledged {
+			acknowledged++
+		}
+		if o.paymentFailed {
+			paymentFailed++
+		}
+		if o.ttfb > 0 {
+			ttfbs = append(ttfbs, o.ttfb)
+		}
+	}
+	m.outcomes = kept
+
+	report := Report{Sessions: total}
+	if total == 0 {
+		return report
+	}
+	report.SessionSuccessRate = float64(acknowledged) / float64(total)
+	report.PaymentFailureRate = float64(paymentFailed) / float64(total)
+	report.MedianTTFB = median(ttfbs)
+
+	if total < m.config.MinSessions {
+		return report
+	}
+	if report.SessionSuccessRate < m.config.MinSessionSuccessRate {
+		report.Breaches = append(report.Breaches, fmt.Sprintf("session success rate %.2f < %.2f", report.SessionSuccessRate, m.config.MinSessionSuccessRate))
+	}
+	if m.config.MaxMedianTTFB > 0 && report.MedianTTFB > m.config.MaxMedianTTFB {
+		report.Breaches = append(report.Breaches, fmt.Sprintf("median TTFB %s > %s", report.MedianTTFB, m.config.MaxMedianTTFB))
+	}
+	if report.PaymentFailureRate > m.config.MaxPaymentFailureRate {
+		report.Breaches = append(report.Breaches, fmt.Sprintf("payment failure rate %.2f > %.2f", report.PaymentFailureRate, m.config.MaxPaymentFailureRate))
+	}
+
+	return report
+}
+
+func (m *Monitor) consumeSessionEvent(e sessionEvent.AppEventSession) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch e.Status {
+	case sessionEvent.CreatedStatus:
+		m.active[e.Session.ID] = &activeSession{createdAt: m.now()}
+	case sessionEvent.AcknowledgedStatus:
+		if s, ok := m.active[e.Session.ID]; ok {
+			s.acknowledged = true
+		}
+	case sessionEvent.RemovedStatus:
+		s, ok := m.active[e.Session.ID]
+		if !ok {
+			return
+		}
+		delete(m.active, e.Session.ID)
+		m.outcomes = append(m.outcomes, sessionOutcome{
+			finishedAt:    m.now(),
+			ttfb:          s.ttfb,
+			acknowledged:  s.acknowledged,
+			paymentFailed: s.paymentFailed,
+		})
+	}
+}
+
+func (m *Monitor) consumeDataTransferredEvent(e sessionEvent.AppEventDataTransferred) {
+	if e.Up+e.Down == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.active[e.ID]; ok && s.ttfb == 0 {
+		s.ttfb = m.now().Sub(s.createdAt)
+	}
+}
+
+func (m *Monitor) consumePaymentFailedEvent(e sessionEvent.AppEventPaymentFailed) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.active[e.SessionID]; ok {
+		s.paymentFailed = true
+	}
+}
+
+func median(values []time.Duration) time.Duration {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package slo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/mocks"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+)
+
+type mockAudit struct {
+	records []ActionRecord
+}
+
+func (ma *mockAudit) Store(record ActionRecord) error {
+	ma.records = append(ma.records, record)
+	return nil
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (tc *testClock) Now() time.Time {
+	return tc.now
+}
+
+func newTestMonitor(actions map[string]Action) (*Monitor, *mockAudit, *testClock) {
+	config := DefaultConfig()
+	config.MinSessions = 4
+	config.Actions = []string{ActionRedetectNAT, ActionRestartService}
+
+	audit := &mockAudit{}
+	clock := &testClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+	monitor := NewMonitor(config, actions, audit, mocks.NewEventBus())
+	monitor.now = clock.Now
+	return monitor, audit, clock
+}
+
+func runSession(m *Monitor, clock *testClock, id string, ttfb time.Duration, acknowledged, paymentFailed bool) {
+	m.consumeSessionEvent(sessionEvent.AppEventSession{Status: sessionEvent.CreatedStatus, Session: sessionEvent.SessionContext{ID: id}})
+	clock.now = clock.now.Add(ttfb)
+	m.consumeDataTransferredEvent(sessionEvent.AppEventDataTransferred{ID: id, Up: 10, Down: 100})
+	if acknowledged {
+		m.consumeSessionEvent(sessionEvent.AppEventSession{Status: sessionEvent.AcknowledgedStatus, Session: sessionEvent.SessionContext{ID: id}})
+	}
+	if paymentFailed {
+		m.consumePaymentFailedEvent(sessionEvent.AppEventPaymentFailed{SessionID: id})
+	}
+	m.consumeSessionEvent(sessionEvent.AppEventSession{Status: sessionEvent.RemovedStatus, Session: sessionEvent.SessionContext{ID: id}})
+}
+
+func TestMonitor_Report(t *testing.T) {
+	monitor, _, clock := newTestMonitor(nil)
+
+	runSession(monitor, clock, "1", time.Second, true, false)
+	runSession(monitor, clock, "2", 3*time.Second, true, false)
+	runSession(monitor, clock, "3", 2*time.Second, false, true)
+
+	report := monitor.Report()
+	assert.Equal(t, 3, report.Sessions)
+	assert.InDelta(t, 0.66, report.SessionSuccessRate, 0.01)
+	assert.InDelta(t, 0.33, report.PaymentFailureRate, 0.01)
+	assert.Equal(t, 2*time.Second, report.MedianTTFB)
+	assert.False(t, report.Breached(), "not enough sessions to evaluate")
+
+	clock.now = clock.now.Add(2 * time.Hour)
+	assert.Zero(t, monitor.Report().Sessions)
+}
+
+func TestMonitor_Check_EscalatesActionsOnBreach(t *testing.T) {
+	var ran []string
+	monitor, audit, clock := newTestMonitor(map[string]Action{
+		ActionRedetectNAT: func() error {
+			ran = append(ran, ActionRedetectNAT)
+			return nil
+		},
+		ActionRestartService: func() error {
+			ran = append(ran, ActionRestartService)
+			return errors.New("boom")
+		},
+	})
+	breach := func() {
+		for i := 0; i < 4; i++ {
+			runSession(monitor, clock, fmt.Sprint(i), time.Second, false, false)
+		}
+	}
+
+	breach()
+	monitor.Check()
+	assert.Equal(t, []string{ActionRedetectNAT}, ran)
+
+	breach()
+	monitor.Check()
+	assert.Len(t, ran, 1, "cooldown should prevent another action")
+
+	clock.now = clock.now.Add(monitor.config.Cooldown)
+	monitor.Check()
+	assert.Equal(t, []string{ActionRedetectNAT, ActionRestartService}, ran)
+
+	assert.Len(t, audit.records, 2)
+	assert.Equal(t, ActionRedetectNAT, audit.records[0].Action)
+	assert.Empty(t, audit.records[0].Error)
+	assert.NotEmpty(t, audit.records[0].Breaches)
+	assert.Equal(t, ActionRestartService, audit.records[1].Action)
+	assert.Equal(t, "boom", audit.records[1].Error)
+}
+
+func TestMonitor_Check_NoActionWhenHealthy(t *testing.T) {
+	var ran int
+	monitor, audit, clock := newTestMonitor(map[string]Action{
+		ActionRedetectNAT: func() error {
+			ran++
+			return nil
+		},
+	})
+	for i := 0; i < 5; i++ {
+		runSession(monitor, clock, fmt.Sprint(i), time.Second, true, false)
+	}
+
+	monitor.Check()
+	assert.Zero(t, ran)
+	assert.Empty(t, audit.records)
+}
@@ -288,7 +288,7 @@ func Test_ConsumesServiceEvents(t *testing.T) {
 	}
 
 	expected := service.Instance{
-		Proposal: market.NewProposal("0xbeef", "wireguard", market.NewProposalOpts{}),
+		Proposal: market.NewProposal("0xbeef", "wireguard", market.NewProposalOpts{AddressFamilies: []string{market.AddressFamilyIPv4}}),
 	}
 	var id service.ID
 
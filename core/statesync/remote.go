@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrNotFound is returned when the remote storage has no state yet.
+var ErrNotFound = errors.New("state not found in remote storage")
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Remote keeps encrypted state objects.
+type Remote interface {
+	Get(ctx context.Context, name string) ([]byte, error)
+	Put(ctx context.Context, name string, data []byte) error
+}
+
+// RemoteOptions holds credentials of remote storages.
+type RemoteOptions struct {
+	Username string
+	Password string
+
+	S3AccessKey string
+	S3SecretKey string
+	S3Region    string
+	S3Endpoint  string
+}
+
+// NewRemote returns WebDAV remote for http(s) URLs and S3 remote for s3://bucket/prefix URLs.
+func NewRemote(client httpClient, rawURL string, opts RemoteOptions) (Remote, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sync URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return NewWebDAV(client, u, opts.Username, opts.Password), nil
+	case "s3":
+		if u.Host == "" {
+			return nil, errors.New("S3 sync URL must name a bucket")
+		}
+		return NewS3(client, u.Host, strings.Trim(u.Path, "/"), opts.S3Region, opts.S3Endpoint, opts.S3AccessKey, opts.S3SecretKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported sync URL scheme %q", u.Scheme)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package statesync
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/connection/profile"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/storage/encrypted"
+)
+
+const envelopeVersion = 1
+
+// Snapshot is the state shared by devices of the same consumer.
+type Snapshot struct {
+	UpdatedAt     time.Time
+	UpdatedBy     string
+	Profiles      []profile.Profile
+	FilterPresets []FilterPreset
+	// Spending is keyed by device ID, each device reports only its own spending.
+	Spending map[string]DeviceSpending
+}
+
+// FilterPreset is a user defined proposal filter preset.
+type FilterPreset struct {
+	Name   string
+	IPType proposal.IPType
+}
+
+// DeviceSpending summarizes sessions consumed on a single device.
+type DeviceSpending struct {
+	Device       string
+	UpdatedAt    time.Time
+	Sessions     int
+	DataSent     uint64
+	DataReceived uint64
+	Duration     time.Duration
+	Tokens       *big.Int
+}
+
+// sharedHash identifies the part of the snapshot which is merged between devices.
+func (s Snapshot) sharedHash() (string, error) {
+	profiles := append([]profile.Profile(nil), s.Profiles...)
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	presets := append([]FilterPreset(nil), s.FilterPresets...)
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+
+	encoded, err := json.Marshal(struct {
+		Profiles      []profile.Profile
+		FilterPresets []FilterPreset
+	}{profiles, presets})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+type envelope struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Data    []byte `json:"data"`
+}
+
+// seal encrypts the snapshot with a key derived from the passphrase and a fresh salt.
+func seal(snapshot Snapshot, passphrase string) ([]byte, error) {
+	plain, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	cipher, err := encrypted.DeriveCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	data, err := cipher.Seal(plain, nil)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope{Version: envelopeVersion, Salt: salt, Data: data})
+}
+
+// open decrypts the snapshot sealed by another device.
+func open(sealed []byte, passphrase string) (Snapshot, error) {
+	var env envelope
+	if err := json.Unmarshal(sealed, &env); err != nil {
+		return Snapshot{}, fmt.Errorf("malformed remote state: %w", err)
+	}
+	if env.Version != envelopeVersion {
+		return Snapshot{}, fmt.Errorf("unsupported remote state version %d", env.Version)
+	}
+
+	cipher, err := encrypted.DeriveCipher(passphrase, env.Salt)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	plain, err := cipher.Open(env.Data, nil)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("could not decrypt remote state, check the sync passphrase: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(plain, &snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("malformed remote state: %w", err)
+	}
+	return snapshot, nil
+}
+
+// merge returns the union of both snapshots, preferring entries of the primary one.
+func merge(primary, secondary Snapshot) Snapshot {
+	result := primary
+
+	profiles := make(map[string]bool, len(primary.Profiles))
+	for _, p := range primary.Profiles {
+		profiles[p.Name] = true
+	}
+	result.Profiles = append([]profile.Profile(nil), primary.Profiles...)
+	for _, p := range secondary.Profiles {
+		if !profiles[p.Name] {
+			result.Profiles = append(result.Profiles, p)
+		}
+	}
+
+	presets := make(map[string]bool, len(primary.FilterPresets))
+	for _, p := range primary.FilterPresets {
+		presets[p.Name] = true
+	}
+	result.FilterPresets = append([]FilterPreset(nil), primary.FilterPresets...)
+	for _, p := range secondary.FilterPresets {
+		if !presets[p.Name] {
+			result.FilterPresets = append(result.FilterPresets, p)
+		}
+	}
+
+	return result
+}
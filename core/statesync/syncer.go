@@ -0,0 +1,360 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package statesync
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/connection/profile"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const (
+	stateBucket = "state-sync"
+	stateKey    = "state"
+	objectName  = "state.json"
+)
+
+type persistentStorage interface {
+	GetValue(bucket string, key interface{}, to interface{}) error
+	SetValue(bucket string, key interface{}, to interface{}) error
+}
+
+type profileStorage interface {
+	List() ([]profile.Profile, error)
+	Replace(list []profile.Profile) error
+}
+
+type presetStorage interface {
+	List() (*proposal.FilterPresets, error)
+	Save(preset proposal.FilterPreset) error
+	Delete(id int) error
+}
+
+type sessionStats interface {
+	Stats(filter *session.Filter) (session.Stats, error)
+}
+
+// localState is what this device remembers between syncs.
+type localState struct {
+	Device   string
+	LastHash string
+}
+
+// Status describes the outcome of the last sync.
+type Status struct {
+	Device    string
+	LastSync  time.Time
+	LastError string
+	Spending  map[string]DeviceSpending
+}
+
+// Syncer replicates profiles, filter presets and spending summaries between devices of the
+// same consumer through a remote storage. State is encrypted before it leaves the device.
+type Syncer struct {
+	remote     Remote
+	passphrase string
+	interval   time.Duration
+	storage    persistentStorage
+	profiles   profileStorage
+	presets    presetStorage
+	sessions   sessionStats
+	now        func() time.Time
+
+	syncMu sync.Mutex
+
+	mu     sync.Mutex
+	status Status
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewSyncer returns a new syncer, syncing every interval once started.
+func NewSyncer(
+	remote Remote,
+	passphrase string,
+	interval time.Duration,
+	storage persistentStorage,
+	profiles profileStorage,
+	presets presetStorage,
+	sessions sessionStats,
+) *Syncer {
+	return &Syncer{
+		remote:     remote,
+		passphrase: passphrase,
+		interval:   interval,
+		storage:    storage,
+		profiles:   profiles,
+		presets:    presets,
+		sessions:   sessions,
+		now:        time.Now,
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start starts syncing in the background.
+func (s *Syncer) Start() {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if err := s.Sync(ctx); err != nil {
+				log.Warn().Err(err).Msg("Failed to sync state")
+			}
+			cancel()
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops syncing.
+func (s *Syncer) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// Status returns the outcome of the last sync.
+func (s *Syncer) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.status
+}
+
+// Sync merges local state with the remote one and uploads the result. Changes made only on one
+// side win, when both sides changed since the last sync the union is kept, local entries first.
+func (s *Syncer) Sync(ctx context.Context) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	result, err := s.sync(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.status.LastError = err.Error()
+		return err
+	}
+	s.status = Status{
+		Device:   result.UpdatedBy,
+		LastSync: result.UpdatedAt,
+		Spending: result.Spending,
+	}
+	return nil
+}
+
+func (s *Syncer) sync(ctx context.Context) (Snapshot, error) {
+	state, err := s.loadState()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	local, err := s.localSnapshot(state.Device)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	localHash, err := local.sharedHash()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	result := local
+	sealed, err := s.remote.Get(ctx, objectName)
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return Snapshot{}, err
+	default:
+		remote, err := open(sealed, s.passphrase)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		remoteHash, err := remote.sharedHash()
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		switch {
+		case remoteHash == state.LastHash:
+		case localHash == state.LastHash:
+			result = remote
+		default:
+			result = merge(local, remote)
+		}
+		result.Spending = make(map[string]DeviceSpending, len(remote.Spending)+1)
+		for device, spending := range remote.Spending {
+			result.Spending[device] = spending
+		}
+	}
+	if result.Spending == nil {
+		result.Spending = make(map[string]DeviceSpending, 1)
+	}
+	result.Spending[state.Device] = local.Spending[state.Device]
+	result.UpdatedAt = s.now().UTC()
+	result.UpdatedBy = state.Device
+
+	resultHash, err := result.sharedHash()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if resultHash != localHash {
+		if err := s.apply(result); err != nil {
+			return Snapshot{}, err
+		}
+	}
+
+	sealed, err = seal(result, s.passphrase)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if err := s.remote.Put(ctx, objectName, sealed); err != nil {
+		return Snapshot{}, err
+	}
+
+	state.LastHash = resultHash
+	return result, s.storage.SetValue(stateBucket, stateKey, state)
+}
+
+func (s *Syncer) loadState() (localState, error) {
+	var state localState
+	err := s.storage.GetValue(stateBucket, stateKey, &state)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return state, err
+	}
+	if state.Device != "" {
+		return state, nil
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return state, err
+	}
+	state.Device = hex.EncodeToString(id)
+	return state, s.storage.SetValue(stateBucket, stateKey, state)
+}
+
+func (s *Syncer) localSnapshot(device string) (Snapshot, error) {
+	profiles, err := s.profiles.List()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	presets, err := s.userPresets()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var shared []FilterPreset
+	for _, p := range presets {
+		shared = append(shared, FilterPreset{Name: p.Name, IPType: p.IPType})
+	}
+
+	stats, err := s.sessions.Stats(session.NewFilter().SetDirection(session.DirectionConsumed))
+	if err != nil {
+		return Snapshot{}, err
+	}
+	tokens := stats.SumTokens
+	if tokens == nil {
+		tokens = new(big.Int)
+	}
+
+	return Snapshot{
+		Profiles:      profiles,
+		FilterPresets: shared,
+		Spending: map[string]DeviceSpending{
+			device: {
+				Device:       device,
+				UpdatedAt:    s.now().UTC(),
+				Sessions:     stats.Count,
+				DataSent:     stats.SumDataSent,
+				DataReceived: stats.SumDataReceived,
+				Duration:     stats.SumDuration,
+				Tokens:       tokens,
+			},
+		},
+	}, nil
+}
+
+func (s *Syncer) userPresets() ([]proposal.FilterPreset, error) {
+	presets, err := s.presets.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []proposal.FilterPreset
+	for _, p := range presets.Entries {
+		if !p.IsSystem() {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// apply replaces local profiles and filter presets with the synced ones.
+func (s *Syncer) apply(snapshot Snapshot) error {
+	if err := s.profiles.Replace(snapshot.Profiles); err != nil {
+		return err
+	}
+
+	local, err := s.userPresets()
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]FilterPreset, len(snapshot.FilterPresets))
+	for _, p := range snapshot.FilterPresets {
+		wanted[p.Name] = p
+	}
+	for _, p := range local {
+		if w, ok := wanted[p.Name]; ok && w.IPType == p.IPType {
+			delete(wanted, p.Name)
+			continue
+		}
+		if err := s.presets.Delete(p.ID); err != nil {
+			return err
+		}
+	}
+	for _, p := range snapshot.FilterPresets {
+		if _, ok := wanted[p.Name]; !ok {
+			continue
+		}
+		if err := s.presets.Save(proposal.FilterPreset{Name: p.Name, IPType: p.IPType}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package statesync
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/connection/profile"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/storage/memory"
+)
+
+type mockDAV struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (m *mockDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if user, pass, _ := r.BasicAuth(); user != "user" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := m.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		m.objects[r.URL.Path] = data
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+type mockProfiles struct {
+	list []profile.Profile
+}
+
+func (m *mockProfiles) List() ([]profile.Profile, error) {
+	return m.list, nil
+}
+
+func (m *mockProfiles) Replace(list []profile.Profile) error {
+	m.list = list
+	return nil
+}
+
+type mockPresets struct {
+	entries []proposal.FilterPreset
+	nextID  int
+}
+
+func (m *mockPresets) List() (*proposal.FilterPresets, error) {
+	return &proposal.FilterPresets{Entries: append([]proposal.FilterPreset{{ID: 1, Name: "system"}}, m.entries...)}, nil
+}
+
+func (m *mockPresets) Save(preset proposal.FilterPreset) error {
+	m.nextID++
+	preset.ID = 100 + m.nextID
+	m.entries = append(m.entries, preset)
+	return nil
+}
+
+func (m *mockPresets) Delete(id int) error {
+	for i, p := range m.entries {
+		if p.ID == id {
+			m.entries = append(m.entries[:i], m.entries[i+1:]...)
+		}
+	}
+	return nil
+}
+
+type mockSessions struct {
+	stats session.Stats
+}
+
+func (m *mockSessions) Stats(_ *session.Filter) (session.Stats, error) {
+	return m.stats, nil
+}
+
+type device struct {
+	*Syncer
+	profiles *mockProfiles
+	presets  *mockPresets
+}
+
+func newDevice(t *testing.T, server *httptest.Server, tokens int64) device {
+	base, err := url.Parse(server.URL + "/mysterium/")
+	require.NoError(t, err)
+
+	d := device{profiles: &mockProfiles{}, presets: &mockPresets{}}
+	d.Syncer = NewSyncer(
+		NewWebDAV(server.Client(), base, "user", "secret"),
+		"sync passphrase",
+		0,
+		memory.NewStorage(),
+		d.profiles,
+		d.presets,
+		&mockSessions{stats: session.Stats{Count: 1, SumTokens: big.NewInt(tokens)}},
+	)
+	return d
+}
+
+func TestSyncerReplicatesStateBetweenDevices(t *testing.T) {
+	dav := &mockDAV{objects: make(map[string][]byte)}
+	server := httptest.NewServer(dav)
+	defer server.Close()
+
+	laptop := newDevice(t, server, 10)
+	phone := newDevice(t, server, 5)
+
+	laptop.profiles.list = []profile.Profile{{Name: "work", CountryCode: "DE"}}
+	require.NoError(t, laptop.presets.Save(proposal.FilterPreset{Name: "cheap", IPType: proposal.Residential}))
+	require.NoError(t, laptop.Sync(context.Background()))
+
+	require.Contains(t, dav.objects, "/mysterium/state.json")
+	assert.NotContains(t, string(dav.objects["/mysterium/state.json"]), "work")
+
+	require.NoError(t, phone.Sync(context.Background()))
+	assert.Equal(t, laptop.profiles.list, phone.profiles.list)
+	require.Len(t, phone.presets.entries, 1)
+	assert.Equal(t, "cheap", phone.presets.entries[0].Name)
+
+	spending := phone.Status().Spending
+	assert.Len(t, spending, 2)
+	assert.Equal(t, big.NewInt(10), spending[laptop.Status().Device].Tokens)
+
+	// Profile removed on the phone is removed on the laptop too.
+	phone.profiles.list = nil
+	require.NoError(t, phone.Sync(context.Background()))
+	require.NoError(t, laptop.Sync(context.Background()))
+	assert.Empty(t, laptop.profiles.list)
+	assert.Len(t, laptop.presets.entries, 1)
+}
+
+func TestSyncerMergesConcurrentChanges(t *testing.T) {
+	server := httptest.NewServer(&mockDAV{objects: make(map[string][]byte)})
+	defer server.Close()
+
+	laptop := newDevice(t, server, 0)
+	phone := newDevice(t, server, 0)
+	require.NoError(t, laptop.Sync(context.Background()))
+	require.NoError(t, phone.Sync(context.Background()))
+
+	laptop.profiles.list = []profile.Profile{{Name: "home", CountryCode: "LT"}, {Name: "work", CountryCode: "DE"}}
+	phone.profiles.list = []profile.Profile{{Name: "work", CountryCode: "US"}, {Name: "travel"}}
+
+	require.NoError(t, laptop.Sync(context.Background()))
+	require.NoError(t, phone.Sync(context.Background()))
+	assert.Equal(t, []profile.Profile{{Name: "work", CountryCode: "US"}, {Name: "travel"}, {Name: "home", CountryCode: "LT"}}, phone.profiles.list)
+
+	require.NoError(t, laptop.Sync(context.Background()))
+	assert.Equal(t, phone.profiles.list, laptop.profiles.list)
+}
+
+func TestSyncerRejectsWrongPassphrase(t *testing.T) {
+	server := httptest.NewServer(&mockDAV{objects: make(map[string][]byte)})
+	defer server.Close()
+
+	laptop := newDevice(t, server, 0)
+	require.NoError(t, laptop.Sync(context.Background()))
+
+	phone := newDevice(t, server, 0)
+	phone.passphrase = "wrong"
+	assert.Error(t, phone.Sync(context.Background()))
+	assert.NotEmpty(t, phone.Status().LastError)
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const maxObjectSize = 16 << 20
+
+// WebDAV keeps state objects in a WebDAV collection.
+type WebDAV struct {
+	httpClient httpClient
+	base       *url.URL
+	username   string
+	password   string
+}
+
+// NewWebDAV returns a new WebDAV remote keeping objects in the collection at base URL.
+func NewWebDAV(client httpClient, base *url.URL, username, password string) *WebDAV {
+	return &WebDAV{
+		httpClient: client,
+		base:       base,
+		username:   username,
+		password:   password,
+	}
+}
+
+// Get downloads the object.
+func (w *WebDAV) Get(ctx context.Context, name string) ([]byte, error) {
+	resp, err := w.do(ctx, http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("WebDAV GET failed: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxObjectSize))
+}
+
+// Put uploads the object.
+func (w *WebDAV) Put(ctx context.Context, name string, data []byte) error {
+	resp, err := w.do(ctx, http.MethodPut, name, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("WebDAV PUT failed: %s", resp.Status)
+	}
+	return nil
+}
+
+func (w *WebDAV) do(ctx context.Context, method, name string, body []byte) (*http.Response, error) {
+	target := *w.base
+	target.Path = strings.TrimSuffix(target.Path, "/") + "/" + name
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if w.username != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+	return w.httpClient.Do(req)
+}
@@ -18,29 +18,48 @@
 package boltdb
 
 import (
+	"io"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/asdine/storm/v3"
+	"github.com/asdine/storm/v3/q"
 	"github.com/pkg/errors"
+	"go.etcd.io/bbolt"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const (
+	// FileName is the name of the database file in the storage directory.
+	FileName = "myst.db"
+
+	metadataKey = "__storm_metadata"
 )
 
 // Bolt is a wrapper around boltdb
 type Bolt struct {
-	mux sync.RWMutex
-	db  *storm.DB
+	mux     sync.RWMutex
+	file    string
+	options []func(*storm.Options) error
+	db      *storm.DB
 }
 
 // NewStorage creates a new BoltDB storage for service promises
-func NewStorage(path string) (*Bolt, error) {
-	return openDB(filepath.Join(path, "myst.db"))
+func NewStorage(path string, options ...func(*storm.Options) error) (*Bolt, error) {
+	return openDB(filepath.Join(path, FileName), options...)
 }
 
 // openDB creates new or open existing BoltDB
-func openDB(name string) (*Bolt, error) {
-	db, err := storm.Open(name)
+func openDB(name string, options ...func(*storm.Options) error) (*Bolt, error) {
+	db, err := storm.Open(name, options...)
 	return &Bolt{
-		db: db,
+		file:    name,
+		options: options,
+		db:      db,
 	}, errors.Wrap(err, "failed to open boltDB")
 }
 
@@ -58,6 +77,36 @@ func (b *Bolt) SetValue(bucket string, key interface{}, to interface{}) error {
 	return b.db.Set(bucket, key, to)
 }
 
+// GetAllValues gets all key values of the bucket
+func (b *Bolt) GetAllValues(bucket string, to interface{}) error {
+	ref := reflect.ValueOf(to)
+	if ref.Kind() != reflect.Ptr || ref.Elem().Kind() != reflect.Slice {
+		return errors.New("provided target must be a pointer to a slice")
+	}
+	slice := ref.Elem()
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return b.db.Bolt.View(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket([]byte(bucket))
+		if bkt == nil {
+			return nil
+		}
+		return bkt.ForEach(func(k, v []byte) error {
+			if string(k) == metadataKey || v == nil {
+				return nil
+			}
+			item := reflect.New(slice.Type().Elem())
+			if err := b.db.Codec().Unmarshal(v, item.Interface()); err != nil {
+				return err
+			}
+			slice.Set(reflect.Append(slice, item.Elem()))
+			return nil
+		})
+	})
+}
+
 // Store allows to keep struct grouped by the bucket
 func (b *Bolt) Store(bucket string, data interface{}) error {
 	b.mux.Lock()
@@ -107,6 +156,39 @@ func (b *Bolt) GetLast(bucket string, to interface{}) error {
 	return b.db.From(bucket).Select().Reverse().First(to)
 }
 
+// Find returns structs from the bucket selected by the query
+func (b *Bolt) Find(bucket string, query storage.Query, to interface{}) error {
+	var matchers []q.Matcher
+	if query.Where != nil {
+		matchers = append(matchers, query.Where)
+	}
+
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	sq := b.db.From(bucket).Select(matchers...)
+	if query.OrderBy != "" {
+		sq = sq.OrderBy(query.OrderBy)
+	}
+	if query.Reverse {
+		sq = sq.Reverse()
+	}
+	if query.Limit > 0 {
+		sq = sq.Limit(query.Limit)
+	}
+	return sq.Find(to)
+}
+
+// DeleteMatching removes structs of the given kind matched by the matcher from the bucket
+func (b *Bolt) DeleteMatching(bucket string, matcher q.Matcher, kind interface{}) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	err := b.db.From(bucket).Select(matcher).Delete(kind)
+	if errors.Is(err, storm.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
 // GetBuckets returns a list of buckets
 func (b *Bolt) GetBuckets() []string {
 	b.mux.RLock()
@@ -119,6 +201,81 @@ func (b *Bolt) DB() *storm.DB {
 	return b.db
 }
 
+// Backup writes a consistent copy of the database to the file
+func (b *Bolt) Backup(file string) error {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return b.db.Bolt.View(func(tx *bbolt.Tx) error {
+		return tx.CopyFile(file, 0600)
+	})
+}
+
+// Restore closes the database, replaces it with the backup file and opens it again
+func (b *Bolt) Restore(file string) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if err := b.db.Close(); err != nil {
+		return err
+	}
+	if err := copyFile(file, b.file); err != nil {
+		return errors.Wrap(err, "failed to restore boltDB backup")
+	}
+
+	db, err := storm.Open(b.file, b.options...)
+	if err != nil {
+		return errors.Wrap(err, "failed to open boltDB")
+	}
+	b.db = db
+	return nil
+}
+
+// Compact rewrites the database into a new file without free pages, bolt never shrinks files by itself
+func (b *Bolt) Compact() error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	tmp := b.file + ".compact"
+	dst, err := bbolt.Open(tmp, 0600, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create compacted boltDB")
+	}
+	err = b.db.Bolt.View(func(src *bbolt.Tx) error {
+		return dst.Update(func(tx *bbolt.Tx) error {
+			return src.ForEach(func(name []byte, bkt *bbolt.Bucket) error {
+				copied, err := tx.CreateBucket(name)
+				if err != nil {
+					return err
+				}
+				return copyBucket(string(name), bkt, copied, nil)
+			})
+		})
+	})
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "failed to compact boltDB")
+	}
+
+	if err := b.db.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	renameErr := os.Rename(tmp, b.file)
+	if renameErr != nil {
+		os.Remove(tmp)
+	}
+
+	db, err := storm.Open(b.file, b.options...)
+	if err != nil {
+		return errors.Wrap(err, "failed to open boltDB")
+	}
+	b.db = db
+	return errors.Wrap(renameErr, "failed to replace boltDB with compacted one")
+}
+
 // Close closes database
 func (b *Bolt) Close() error {
 	b.mux.Lock()
@@ -145,3 +302,79 @@ func (b *Bolt) Lock() {
 func (b *Bolt) Unlock() {
 	b.mux.Unlock()
 }
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Reencode copies the database file into a new one, transforming values stored by storm codec
+func Reencode(src, dst string, transform func(bucket string, key, value []byte) ([]byte, error)) error {
+	in, err := bbolt.Open(src, 0600, &bbolt.Options{ReadOnly: true})
+	if err != nil {
+		return errors.Wrap(err, "failed to open boltDB")
+	}
+	defer in.Close()
+
+	out, err := bbolt.Open(dst, 0600, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create boltDB")
+	}
+	err = in.View(func(srcTx *bbolt.Tx) error {
+		return out.Update(func(tx *bbolt.Tx) error {
+			return srcTx.ForEach(func(name []byte, bkt *bbolt.Bucket) error {
+				copied, err := tx.CreateBucket(name)
+				if err != nil {
+					return err
+				}
+				return copyBucket(string(name), bkt, copied, transform)
+			})
+		})
+	})
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// copyBucket copies the bucket recursively, applying transform to values if it is set.
+// Nested bucket names are joined with slashes. Storm metadata and index buckets are not codec encoded and are copied as is.
+func copyBucket(name string, src, dst *bbolt.Bucket, transform func(bucket string, key, value []byte) ([]byte, error)) error {
+	if err := dst.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			if transform != nil {
+				transformed, err := transform(name, k, v)
+				if err != nil {
+					return err
+				}
+				v = transformed
+			}
+			return dst.Put(k, v)
+		}
+
+		nested, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(string(k), "__storm") {
+			return copyBucket(name+"/"+string(k), src.Bucket(k), nested, nil)
+		}
+		return copyBucket(name+"/"+string(k), src.Bucket(k), nested, transform)
+	})
+}
@@ -113,3 +113,27 @@ func Test_StorageGetLast_ErrsOnEmpty(t *testing.T) {
 	err = storage.GetLast(bucket, &result)
 	assert.Equal(t, "not found", err.Error())
 }
+
+func Test_StorageCompactKeepsData(t *testing.T) {
+	storage, close, err := createMockStorage(t)
+	assert.Nil(t, err)
+	defer close()
+
+	for i := int64(1); i <= 100; i++ {
+		assert.Nil(t, storage.Store(bucket, &myTestType{ID: i}))
+	}
+	for i := int64(1); i <= 90; i++ {
+		assert.Nil(t, storage.Delete(bucket, &myTestType{ID: i}))
+	}
+	assert.Nil(t, storage.SetValue("values", "key", "value"))
+
+	assert.Nil(t, storage.Compact())
+
+	var result []myTestType
+	assert.Nil(t, storage.GetAllFrom(bucket, &result))
+	assert.Len(t, result, 10)
+
+	var value string
+	assert.Nil(t, storage.GetValue("values", "key", &value))
+	assert.Equal(t, "value", value)
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package encrypted
+
+import (
+	"github.com/mysteriumnetwork/node/core/storage/records"
+)
+
+type backend struct {
+	records.Backend
+	cipher *Cipher
+}
+
+// NewBackend returns a records backend keeping values of the inner one encrypted.
+// Every value is bound to its bucket and key, bucket names and keys themselves are kept in plaintext.
+func NewBackend(inner records.Backend, cipher *Cipher) records.Backend {
+	return &backend{Backend: inner, cipher: cipher}
+}
+
+func (b *backend) Get(bucket string, key []byte) ([]byte, error) {
+	sealed, err := b.Backend.Get(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	return b.cipher.Open(sealed, RecordAAD(bucket, key))
+}
+
+func (b *backend) Put(bucket string, key, value []byte) error {
+	sealed, err := b.cipher.Seal(value, RecordAAD(bucket, key))
+	if err != nil {
+		return err
+	}
+	return b.Backend.Put(bucket, key, sealed)
+}
+
+func (b *backend) ForEach(bucket string, fn func(key, value []byte) error) error {
+	return b.Backend.ForEach(bucket, func(key, sealed []byte) error {
+		value, err := b.cipher.Open(sealed, RecordAAD(bucket, key))
+		if err != nil {
+			return err
+		}
+		return fn(key, value)
+	})
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package encrypted
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// ErrCorrupted is returned when a record can not be decrypted, either because of a wrong key or tampering.
+var ErrCorrupted = errors.New("encrypted record is corrupted or the key is wrong")
+
+// Cipher encrypts records with AES-GCM, each with a random nonce.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher returns a new cipher for the 32 byte key.
+func NewCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Seal encrypts the record, prefixing it with the nonce.
+// Additional data is authenticated but not stored, the same has to be given to Open.
+func (c *Cipher) Seal(plain, additional []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plain)+c.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plain, additional), nil
+}
+
+// Open decrypts the record sealed by Seal with the same additional data.
+func (c *Cipher) Open(sealed, additional []byte) ([]byte, error) {
+	if len(sealed) < c.aead.NonceSize() {
+		return nil, ErrCorrupted
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, ciphertext, additional)
+	if err != nil {
+		return nil, ErrCorrupted
+	}
+	return plain, nil
+}
+
+// RecordAAD binds the record to its bucket and key, so that values can not be swapped between records.
+func RecordAAD(bucket string, key []byte) []byte {
+	aad := make([]byte, 0, len(bucket)+1+len(key))
+	aad = append(aad, bucket...)
+	aad = append(aad, 0)
+	return append(aad, key...)
+}
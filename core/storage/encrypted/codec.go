@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package encrypted
+
+import (
+	"github.com/asdine/storm/v3/codec"
+)
+
+type stormCodec struct {
+	cipher *Cipher
+	inner  codec.MarshalUnmarshaler
+}
+
+// Codec returns a storm codec encrypting whatever the inner codec produces.
+// Storm does not pass bucket and key to the codec, so values are authenticated but not bound to the record
+// they are stored in. Bucket names, record IDs and indexed field values are kept in plaintext by storm.
+func Codec(cipher *Cipher, inner codec.MarshalUnmarshaler) codec.MarshalUnmarshaler {
+	return &stormCodec{cipher: cipher, inner: inner}
+}
+
+func (c *stormCodec) Marshal(v interface{}) ([]byte, error) {
+	plain, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return c.cipher.Seal(plain, nil)
+}
+
+func (c *stormCodec) Unmarshal(b []byte, v interface{}) error {
+	plain, err := c.cipher.Open(b, nil)
+	if err != nil {
+		return err
+	}
+	return c.inner.Unmarshal(plain, v)
+}
+
+// Name keeps the name of the inner codec, as storm refuses buckets written by a codec with another name.
+func (c *stormCodec) Name() string {
+	return c.inner.Name()
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package encrypted
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keyFileName = "storage.key"
+	keyLength   = 32
+	saltLength  = 16
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var checkValue = []byte("mysterium-storage")
+
+// ErrWrongPassphrase is returned when the passphrase does not match the one the store was encrypted with.
+var ErrWrongPassphrase = errors.New("wrong storage passphrase")
+
+// Reencoder rewrites the database file into another one, transforming every stored value.
+type Reencoder func(src, dst string, transform func(bucket string, key, value []byte) ([]byte, error)) error
+
+// AAD returns additional data a record value is bound to, nil if values are not bound to records.
+type AAD func(bucket string, key []byte) []byte
+
+// keyFile keeps parameters of the key derivation, never the key itself.
+type keyFile struct {
+	Salt  []byte `json:"salt"`
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	Check []byte `json:"check"`
+}
+
+// Setup returns the cipher of the database file in dir derived from the passphrase. The first time
+// it is called for a database, the existing plaintext file is encrypted with reencode, binding values with aad.
+func Setup(dir, file, passphrase string, reencode Reencoder, aad AAD) (*Cipher, error) {
+	if passphrase == "" {
+		return nil, errors.New("storage passphrase is empty")
+	}
+
+	keyPath := filepath.Join(dir, keyFileName)
+	pending := file + ".encrypted"
+
+	if _, err := os.Stat(keyPath); err == nil {
+		c, err := loadKey(keyPath, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		// The encrypted copy is only left behind if the node stopped right after the key was written.
+		if _, err := os.Stat(pending); err == nil {
+			if err := os.Rename(pending, file); err != nil {
+				return nil, fmt.Errorf("could not finish storage encryption: %w", err)
+			}
+		}
+		return c, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, c, err := newKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = os.Stat(file)
+	existing := err == nil
+	if existing {
+		log.Info().Msgf("Encrypting storage %s", file)
+		os.Remove(pending)
+		seal := func(bucket string, key, value []byte) ([]byte, error) {
+			if aad == nil {
+				return c.Seal(value, nil)
+			}
+			return c.Seal(value, aad(bucket, key))
+		}
+		if err := reencode(file, pending, seal); err != nil {
+			os.Remove(pending)
+			return nil, fmt.Errorf("could not encrypt storage: %w", err)
+		}
+	}
+
+	if err := writeKey(keyPath, key); err != nil {
+		return nil, err
+	}
+	if existing {
+		if err := os.Rename(pending, file); err != nil {
+			return nil, fmt.Errorf("could not replace storage with the encrypted one: %w", err)
+		}
+		log.Warn().Msgf("Storage encrypted, backups made before in %s are left unencrypted", dir)
+	}
+	return c, nil
+}
+
+func newKey(passphrase string) (keyFile, *Cipher, error) {
+	key := keyFile{
+		Salt: make([]byte, saltLength),
+		N:    scryptN,
+		R:    scryptR,
+		P:    scryptP,
+	}
+	if _, err := io.ReadFull(rand.Reader, key.Salt); err != nil {
+		return keyFile{}, nil, err
+	}
+
+	c, err := deriveCipher(key, passphrase)
+	if err != nil {
+		return keyFile{}, nil, err
+	}
+	if key.Check, err = c.Seal(checkValue, nil); err != nil {
+		return keyFile{}, nil, err
+	}
+	return key, c, nil
+}
+
+func loadKey(path, passphrase string) (*Cipher, error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var key keyFile
+	if err := json.Unmarshal(encoded, &key); err != nil {
+		return nil, fmt.Errorf("could not read storage key file: %w", err)
+	}
+
+	c, err := deriveCipher(key, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	check, err := c.Open(key.Check, nil)
+	if err != nil || !bytes.Equal(check, checkValue) {
+		return nil, ErrWrongPassphrase
+	}
+	return c, nil
+}
+
+func deriveCipher(key keyFile, passphrase string) (*Cipher, error) {
+	derived, err := scrypt.Key([]byte(passphrase), key.Salt, key.N, key.R, key.P, keyLength)
+	if err != nil {
+		return nil, err
+	}
+	return NewCipher(derived)
+}
+
+// DeriveCipher returns the cipher with a key derived from the passphrase and salt.
+func DeriveCipher(passphrase string, salt []byte) (*Cipher, error) {
+	return deriveCipher(keyFile{Salt: salt, N: scryptN, R: scryptR, P: scryptP}, passphrase)
+}
+
+func writeKey(path string, key keyFile) error {
+	encoded, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, encoded, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package encrypted
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/memory"
+	"github.com/mysteriumnetwork/node/core/storage/records"
+)
+
+func TestSetupFinishesInterruptedEncryption(t *testing.T) {
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "db")
+	require.NoError(t, os.WriteFile(file, []byte("plain"), 0600))
+
+	reencode := func(src, dst string, transform func(bucket string, key, value []byte) ([]byte, error)) error {
+		return os.WriteFile(dst, []byte("encrypted"), 0600)
+	}
+	_, err = Setup(dir, file, "passphrase", reencode, nil)
+	require.NoError(t, err)
+	content, _ := os.ReadFile(file)
+	assert.Equal(t, "encrypted", string(content))
+
+	// the node stopped after writing the key, before replacing the database
+	require.NoError(t, os.WriteFile(file+".encrypted", []byte("encrypted again"), 0600))
+	_, err = Setup(dir, file, "passphrase", nil, nil)
+	require.NoError(t, err)
+	content, _ = os.ReadFile(file)
+	assert.Equal(t, "encrypted again", string(content))
+}
+
+func TestBackendEncryptsValues(t *testing.T) {
+	c, err := NewCipher(make([]byte, keyLength))
+	require.NoError(t, err)
+
+	inner := memory.NewBackend()
+	st := records.New(NewBackend(inner, c))
+	require.NoError(t, st.SetValue("values", "key", "secret"))
+
+	raw, err := inner.Get("values", []byte("key"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "secret")
+
+	var value string
+	assert.NoError(t, st.GetValue("values", "key", &value))
+	assert.Equal(t, "secret", value)
+}
+
+func TestBackendBindsValuesToRecords(t *testing.T) {
+	c, err := NewCipher(make([]byte, keyLength))
+	require.NoError(t, err)
+
+	inner := memory.NewBackend()
+	st := records.New(NewBackend(inner, c))
+	require.NoError(t, st.SetValue("values", "first", "secret"))
+	require.NoError(t, st.SetValue("values", "second", "another secret"))
+
+	raw, err := inner.Get("values", []byte("first"))
+	require.NoError(t, err)
+	require.NoError(t, inner.Put("values", []byte("second"), raw))
+	require.NoError(t, inner.Put("other", []byte("first"), raw))
+
+	var value string
+	assert.Equal(t, ErrCorrupted, st.GetValue("values", "second", &value))
+	assert.Equal(t, ErrCorrupted, st.GetValue("other", "first", &value))
+	assert.NoError(t, st.GetValue("values", "first", &value))
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package encrypted
+
+import (
+	"path/filepath"
+
+	"github.com/asdine/storm/v3"
+	"github.com/asdine/storm/v3/codec/json"
+
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/core/storage/records"
+	"github.com/mysteriumnetwork/node/core/storage/sqlite"
+)
+
+// NewBoltStorage creates a new BoltDB storage in the given directory, keeping records encrypted.
+// Values are not bound to their records, see Codec.
+func NewBoltStorage(path, passphrase string) (*boltdb.Bolt, error) {
+	cipher, err := Setup(path, filepath.Join(path, boltdb.FileName), passphrase, boltdb.Reencode, nil)
+	if err != nil {
+		return nil, err
+	}
+	return boltdb.NewStorage(path, storm.Codec(Codec(cipher, json.Codec)))
+}
+
+// NewSQLiteStorage creates a new SQLite storage in the given directory, keeping records encrypted.
+// Values are bound to their bucket and key, see NewBackend.
+func NewSQLiteStorage(path, passphrase string) (*records.Store, error) {
+	if !sqlite.Supported {
+		return nil, sqlite.ErrNotSupported
+	}
+	file := filepath.Join(path, sqlite.FileName)
+	cipher, err := Setup(path, file, passphrase, sqlite.Reencode, RecordAAD)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := sqlite.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	return records.New(NewBackend(backend, cipher)), nil
+}
@@ -0,0 +1,63 @@
+//go:build sqlite
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package encrypted
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/sqlite"
+)
+
+func TestSQLiteStorageEncryptsExistingData(t *testing.T) {
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	plain, err := sqlite.NewStorage(dir)
+	require.NoError(t, err)
+	require.NoError(t, plain.SetValue("values", "key", "secret value"))
+	require.NoError(t, plain.Close())
+
+	st, err := NewSQLiteStorage(dir, "passphrase")
+	require.NoError(t, err)
+	var value string
+	assert.NoError(t, st.GetValue("values", "key", &value))
+	assert.Equal(t, "secret value", value)
+	require.NoError(t, st.SetValue("values", "other", "another secret"))
+	require.NoError(t, st.Close())
+
+	raw, err := os.ReadFile(filepath.Join(dir, sqlite.FileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "secret")
+
+	_, err = NewSQLiteStorage(dir, "wrong")
+	assert.Equal(t, ErrWrongPassphrase, err)
+
+	st, err = NewSQLiteStorage(dir, "passphrase")
+	require.NoError(t, err)
+	defer st.Close()
+	assert.NoError(t, st.GetValue("values", "other", &value))
+	assert.Equal(t, "another secret", value)
+}
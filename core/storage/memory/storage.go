@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+	"github.com/mysteriumnetwork/node/core/storage/records"
+)
+
+// NewStorage creates a storage keeping data in memory only.
+func NewStorage() *records.Store {
+	return records.New(NewBackend())
+}
+
+// Backend keeps buckets in maps.
+type Backend struct {
+	mu      sync.RWMutex
+	buckets map[string]map[string][]byte
+}
+
+// NewBackend returns an empty in-memory backend.
+func NewBackend() *Backend {
+	return &Backend{buckets: make(map[string]map[string][]byte)}
+}
+
+// Get returns the value of the key.
+func (b *Backend) Get(bucket string, key []byte) ([]byte, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	value, ok := b.buckets[bucket][string(key)]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return value, nil
+}
+
+// Put sets the value of the key.
+func (b *Backend) Put(bucket string, key, value []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bkt, ok := b.buckets[bucket]
+	if !ok {
+		bkt = make(map[string][]byte)
+		b.buckets[bucket] = bkt
+	}
+	bkt[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete removes the key.
+func (b *Backend) Delete(bucket string, key []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.buckets[bucket][string(key)]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(b.buckets[bucket], string(key))
+	return nil
+}
+
+// ForEach calls fn for a snapshot of the bucket, in byte order of keys.
+func (b *Backend) ForEach(bucket string, fn func(key, value []byte) error) error {
+	b.mu.RLock()
+	keys := make([]string, 0, len(b.buckets[bucket]))
+	values := make(map[string][]byte, len(b.buckets[bucket]))
+	for k, v := range b.buckets[bucket] {
+		keys = append(keys, k)
+		values[k] = v
+	}
+	b.mu.RUnlock()
+
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := fn([]byte(k), values[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backup writes buckets to the file.
+func (b *Backend) Backup(file string) error {
+	var buf bytes.Buffer
+	b.mu.RLock()
+	err := gob.NewEncoder(&buf).Encode(b.buckets)
+	b.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, buf.Bytes(), 0600)
+}
+
+// Restore replaces buckets with the ones from the backup file.
+func (b *Backend) Restore(file string) error {
+	encoded, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	buckets := make(map[string]map[string][]byte)
+	if err := gob.NewDecoder(bytes.NewReader(encoded)).Decode(&buckets); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buckets = buckets
+	return nil
+}
+
+// Compact does nothing, deleted keys are not kept.
+func (b *Backend) Compact() error {
+	return nil
+}
+
+// Close drops all buckets.
+func (b *Backend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buckets = make(map[string]map[string][]byte)
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asdine/storm/v3/q"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+type entry struct {
+	ID      int `storm:"id,increment"`
+	Name    string
+	Created time.Time
+}
+
+const bucket = "test"
+
+func Test_StorageValues(t *testing.T) {
+	st := NewStorage()
+
+	var value string
+	assert.Equal(t, storage.ErrNotFound, st.GetValue(bucket, "key", &value))
+
+	assert.NoError(t, st.SetValue(bucket, "key", "value"))
+	assert.NoError(t, st.SetValue(bucket, "other", "other value"))
+	assert.NoError(t, st.GetValue(bucket, "key", &value))
+	assert.Equal(t, "value", value)
+
+	var values []string
+	assert.NoError(t, st.GetAllValues(bucket, &values))
+	assert.Equal(t, []string{"value", "other value"}, values)
+
+	assert.NoError(t, st.DeleteKey(bucket, "key"))
+	assert.Equal(t, storage.ErrNotFound, st.GetValue(bucket, "key", &value))
+}
+
+func Test_StorageStructs(t *testing.T) {
+	st := NewStorage()
+	now := time.Now().UTC()
+
+	for i, name := range []string{"b", "a", "c"} {
+		assert.NoError(t, st.Store(bucket, &entry{Name: name, Created: now.Add(time.Duration(i) * time.Minute)}))
+	}
+
+	var all []entry
+	assert.NoError(t, st.GetAllFrom(bucket, &all))
+	assert.Len(t, all, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{all[0].ID, all[1].ID, all[2].ID})
+
+	var last entry
+	assert.NoError(t, st.GetLast(bucket, &last))
+	assert.Equal(t, "c", last.Name)
+
+	var one entry
+	assert.NoError(t, st.GetOneByField(bucket, "Name", "a", &one))
+	assert.Equal(t, 2, one.ID)
+	assert.Equal(t, storage.ErrNotFound, st.GetOneByField(bucket, "Name", "d", &one))
+
+	assert.NoError(t, st.Update(bucket, &entry{ID: 2, Name: "aa"}))
+	assert.NoError(t, st.GetOneByField(bucket, "ID", 2, &one))
+	assert.Equal(t, "aa", one.Name)
+	assert.True(t, now.Add(time.Minute).Equal(one.Created))
+
+	assert.NoError(t, st.Delete(bucket, &entry{ID: 2}))
+	assert.Equal(t, storage.ErrNotFound, st.GetOneByField(bucket, "ID", 2, &one))
+
+	assert.NoError(t, st.Store(bucket, &entry{Name: "d"}))
+	assert.NoError(t, st.GetLast(bucket, &last))
+	assert.Equal(t, 4, last.ID)
+}
+
+func Test_StorageFind(t *testing.T) {
+	st := NewStorage()
+	now := time.Now().UTC()
+
+	for i, name := range []string{"b", "a", "c", "a"} {
+		assert.NoError(t, st.Store(bucket, &entry{Name: name, Created: now.Add(-time.Duration(i) * time.Minute)}))
+	}
+
+	var result []entry
+	assert.NoError(t, st.Find(bucket, storage.Query{OrderBy: "Created", Reverse: true, Limit: 3}, &result))
+	assert.Equal(t, []int{1, 2, 3}, []int{result[0].ID, result[1].ID, result[2].ID})
+
+	assert.NoError(t, st.Find(bucket, storage.Query{Where: q.Eq("Name", "a"), OrderBy: "Created"}, &result))
+	assert.Equal(t, []int{4, 2}, []int{result[0].ID, result[1].ID})
+
+	assert.Equal(t, storage.ErrNotFound, st.Find(bucket, storage.Query{Where: q.Eq("Name", "d")}, &result))
+
+	assert.NoError(t, st.DeleteMatching(bucket, q.Lt("Created", now.Add(-90*time.Second)), new(entry)))
+	assert.NoError(t, st.GetAllFrom(bucket, &result))
+	assert.Equal(t, []int{1, 2}, []int{result[0].ID, result[1].ID})
+}
@@ -0,0 +1,419 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package records
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/asdine/storm/v3"
+	"github.com/asdine/storm/v3/q"
+	"github.com/pkg/errors"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+// sequenceKey keeps the last auto incremented ID of a bucket.
+const sequenceKey = "__records_sequence"
+
+// Backend is a key value store of buckets, iterated in byte order of keys.
+// Implementations must be safe for concurrent use.
+type Backend interface {
+	// Get returns the value of the key or storage.ErrNotFound.
+	Get(bucket string, key []byte) ([]byte, error)
+	// Put sets the value of the key.
+	Put(bucket string, key, value []byte) error
+	// Delete removes the key or returns storage.ErrNotFound.
+	Delete(bucket string, key []byte) error
+	// ForEach calls fn for every key of the bucket.
+	ForEach(bucket string, fn func(key, value []byte) error) error
+	// Backup writes a consistent copy of the backend to the file.
+	Backup(file string) error
+	// Restore replaces contents of the backend with the backup file.
+	Restore(file string) error
+	// Compact reclaims space left by deleted data.
+	Compact() error
+	// Close releases the backend.
+	Close() error
+}
+
+// Store implements storage.Store on top of a key value backend, keeping values and structs JSON encoded.
+// Writes are serialized, reads go straight to the backend.
+type Store struct {
+	backend Backend
+	writeMu sync.Mutex
+}
+
+// New returns a store keeping data in the given backend.
+func New(backend Backend) *Store {
+	return &Store{backend: backend}
+}
+
+// GetValue gets the value of the key.
+func (s *Store) GetValue(bucket string, key interface{}, to interface{}) error {
+	k, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+	value, err := s.backend.Get(bucket, k)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(value, to)
+}
+
+// SetValue sets the value of the key.
+func (s *Store) SetValue(bucket string, key interface{}, value interface{}) error {
+	k, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrap(err, "could not encode value")
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.backend.Put(bucket, k, encoded)
+}
+
+// GetAllValues gets all values of the bucket into a slice pointer.
+func (s *Store) GetAllValues(bucket string, to interface{}) error {
+	slice, err := sliceValue(to)
+	if err != nil {
+		return err
+	}
+	return s.collect(bucket, slice, nil)
+}
+
+// DeleteKey removes the key.
+func (s *Store) DeleteKey(bucket string, key interface{}) error {
+	k, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.backend.Delete(bucket, k)
+}
+
+// Store saves the struct, replacing the one with the same ID.
+func (s *Store) Store(bucket string, data interface{}) error {
+	value, err := structValue(data)
+	if err != nil {
+		return err
+	}
+	id, err := findID(value.Type())
+	if err != nil {
+		return err
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	field := value.Field(id.index)
+	if field.IsZero() {
+		if !id.increment {
+			return storm.ErrZeroID
+		}
+		if err := s.increment(bucket, field); err != nil {
+			return err
+		}
+	}
+
+	return s.put(bucket, field.Interface(), data)
+}
+
+// Update updates non zero fields of the stored struct with the same ID.
+func (s *Store) Update(bucket string, data interface{}) error {
+	value, err := structValue(data)
+	if err != nil {
+		return err
+	}
+	id, err := findID(value.Type())
+	if err != nil {
+		return err
+	}
+	field := value.Field(id.index)
+	if field.IsZero() {
+		return storm.ErrNoID
+	}
+	key, err := encodeKey(field.Interface())
+	if err != nil {
+		return err
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	stored, err := s.backend.Get(bucket, key)
+	if err != nil {
+		return err
+	}
+	current := reflect.New(value.Type())
+	if err := json.Unmarshal(stored, current.Interface()); err != nil {
+		return err
+	}
+	mergeNonZero(current.Elem(), value)
+
+	return s.put(bucket, field.Interface(), current.Interface())
+}
+
+// Delete removes the struct with the same ID.
+func (s *Store) Delete(bucket string, data interface{}) error {
+	value, err := structValue(data)
+	if err != nil {
+		return err
+	}
+	id, err := findID(value.Type())
+	if err != nil {
+		return err
+	}
+	field := value.Field(id.index)
+	if field.IsZero() {
+		return storm.ErrNoID
+	}
+
+	return s.DeleteKey(bucket, field.Interface())
+}
+
+// GetAllFrom gets all structs of the bucket into a slice pointer.
+func (s *Store) GetAllFrom(bucket string, to interface{}) error {
+	return s.GetAllValues(bucket, to)
+}
+
+// GetOneByField gets the first struct with the field equal to the key.
+func (s *Store) GetOneByField(bucket string, fieldName string, key interface{}, to interface{}) error {
+	value, err := structValue(to)
+	if err != nil {
+		return err
+	}
+	if _, ok := value.Type().FieldByName(fieldName); !ok {
+		return storage.ErrNotFound
+	}
+
+	if id, err := findID(value.Type()); err == nil && value.Type().Field(id.index).Name == fieldName {
+		return s.GetValue(bucket, key, to)
+	}
+
+	matcher := q.Eq(fieldName, key)
+	found := false
+	err = s.backend.ForEach(bucket, func(k, v []byte) error {
+		if found || string(k) == sequenceKey {
+			return nil
+		}
+		item := reflect.New(value.Type())
+		if err := json.Unmarshal(v, item.Interface()); err != nil {
+			return err
+		}
+		ok, err := matcher.Match(item.Interface())
+		if err != nil || !ok {
+			return err
+		}
+		value.Set(item.Elem())
+		found = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// GetLast gets the struct with the greatest ID.
+func (s *Store) GetLast(bucket string, to interface{}) error {
+	var last []byte
+	err := s.backend.ForEach(bucket, func(k, v []byte) error {
+		if string(k) != sequenceKey {
+			last = v
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if last == nil {
+		return storage.ErrNotFound
+	}
+	return json.Unmarshal(last, to)
+}
+
+// Find gets the structs selected by the query into a slice pointer.
+func (s *Store) Find(bucket string, query storage.Query, to interface{}) error {
+	slice, err := sliceValue(to)
+	if err != nil {
+		return err
+	}
+	if err := s.collect(bucket, slice, query.Where); err != nil {
+		return err
+	}
+
+	if query.OrderBy != "" {
+		if err := sortByField(slice, query.OrderBy); err != nil {
+			return err
+		}
+	}
+	if query.Reverse {
+		reverse(slice)
+	}
+	if query.Limit > 0 && slice.Len() > query.Limit {
+		slice.Set(slice.Slice(0, query.Limit))
+	}
+
+	if slice.Len() == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteMatching removes the structs of the given type matched by the matcher.
+func (s *Store) DeleteMatching(bucket string, matcher q.Matcher, kind interface{}) error {
+	value, err := structValue(kind)
+	if err != nil {
+		return err
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	var keys [][]byte
+	err = s.backend.ForEach(bucket, func(k, v []byte) error {
+		if string(k) == sequenceKey {
+			return nil
+		}
+		item := reflect.New(value.Type())
+		if err := json.Unmarshal(v, item.Interface()); err != nil {
+			return err
+		}
+		ok, err := matcher.Match(item.Interface())
+		if ok {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if err := s.backend.Delete(bucket, k); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backup writes a consistent copy of the store to the file.
+func (s *Store) Backup(file string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.backend.Backup(file)
+}
+
+// Restore replaces contents of the store with the backup file.
+func (s *Store) Restore(file string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.backend.Restore(file)
+}
+
+// Compact reclaims space left by deleted data.
+func (s *Store) Compact() error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.backend.Compact()
+}
+
+// Close closes the backend.
+func (s *Store) Close() error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.backend.Close()
+}
+
+// collect decodes the bucket into the slice, keeping the items matched by the matcher if it is set.
+func (s *Store) collect(bucket string, slice reflect.Value, matcher q.Matcher) error {
+	result := reflect.MakeSlice(slice.Type(), 0, 0)
+	elem := slice.Type().Elem()
+	err := s.backend.ForEach(bucket, func(k, v []byte) error {
+		if string(k) == sequenceKey {
+			return nil
+		}
+		item := reflect.New(elem)
+		if err := json.Unmarshal(v, item.Interface()); err != nil {
+			return err
+		}
+		if matcher != nil {
+			ok, err := matcher.Match(item.Interface())
+			if err != nil || !ok {
+				return err
+			}
+		}
+		result = reflect.Append(result, item.Elem())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slice.Set(result)
+	return nil
+}
+
+// increment sets the field to the next ID of the bucket.
+func (s *Store) increment(bucket string, field reflect.Value) error {
+	var last uint64
+	stored, err := s.backend.Get(bucket, []byte(sequenceKey))
+	if err == nil {
+		err = json.Unmarshal(stored, &last)
+	}
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return errors.Wrap(err, "could not read sequence")
+	}
+	last++
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		field.SetInt(int64(last))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		field.SetUint(last)
+	default:
+		return storm.ErrIncompatibleValue
+	}
+
+	encoded, _ := json.Marshal(last)
+	return s.backend.Put(bucket, []byte(sequenceKey), encoded)
+}
+
+func (s *Store) put(bucket string, id interface{}, data interface{}) error {
+	key, err := encodeKey(id)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "could not encode value")
+	}
+	return s.backend.Put(bucket, key, encoded)
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package records
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asdine/storm/v3"
+	"github.com/pkg/errors"
+)
+
+// idField describes the field identifying a struct.
+type idField struct {
+	index     int
+	increment bool
+}
+
+// findID finds the field tagged `storm:"id"` or, failing that, named ID.
+func findID(t reflect.Type) (idField, error) {
+	byName := -1
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tags := strings.Split(field.Tag.Get("storm"), ",")
+		for _, tag := range tags {
+			if tag == "id" {
+				return idField{index: i, increment: hasTag(tags, "increment")}, nil
+			}
+		}
+		if field.Name == "ID" {
+			byName = i
+		}
+	}
+	if byName < 0 {
+		return idField{}, storm.ErrNoID
+	}
+
+	tags := strings.Split(t.Field(byName).Tag.Get("storm"), ",")
+	return idField{index: byName, increment: hasTag(tags, "increment")}, nil
+}
+
+func hasTag(tags []string, name string) bool {
+	for _, tag := range tags {
+		if tag == name {
+			return true
+		}
+	}
+	return false
+}
+
+// structValue dereferences a pointer to a struct.
+func structValue(data interface{}) (reflect.Value, error) {
+	ref := reflect.ValueOf(data)
+	if ref.Kind() != reflect.Ptr || ref.IsNil() || ref.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, storm.ErrStructPtrNeeded
+	}
+	return ref.Elem(), nil
+}
+
+// sliceValue dereferences a pointer to a slice.
+func sliceValue(to interface{}) (reflect.Value, error) {
+	ref := reflect.ValueOf(to)
+	if ref.Kind() != reflect.Ptr || ref.IsNil() || ref.Elem().Kind() != reflect.Slice {
+		return reflect.Value{}, storm.ErrSlicePtrNeeded
+	}
+	return ref.Elem(), nil
+}
+
+// encodeKey encodes the key so that byte order of numeric keys follows their numeric order.
+func encodeKey(key interface{}) ([]byte, error) {
+	switch k := key.(type) {
+	case []byte:
+		return k, nil
+	case string:
+		return []byte(k), nil
+	}
+
+	ref := reflect.ValueOf(key)
+	buf := make([]byte, 8)
+	switch ref.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		binary.BigEndian.PutUint64(buf, uint64(ref.Int())^(1<<63))
+		return buf, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		binary.BigEndian.PutUint64(buf, ref.Uint()^(1<<63))
+		return buf, nil
+	case reflect.String:
+		return []byte(ref.String()), nil
+	}
+
+	encoded, err := json.Marshal(key)
+	return encoded, errors.Wrap(err, "could not encode key")
+}
+
+// mergeNonZero copies non zero fields of src to dst.
+func mergeNonZero(dst, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		if !dst.Field(i).CanSet() || src.Field(i).IsZero() {
+			continue
+		}
+		dst.Field(i).Set(src.Field(i))
+	}
+}
+
+// sortByField sorts the slice of structs by the field.
+func sortByField(slice reflect.Value, field string) error {
+	elem := slice.Type().Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if _, ok := elem.FieldByName(field); !ok {
+		return fmt.Errorf("field %s not found", field)
+	}
+
+	value := func(i int) reflect.Value {
+		return reflect.Indirect(slice.Index(i)).FieldByName(field)
+	}
+	sort.SliceStable(slice.Interface(), func(i, j int) bool {
+		return compare(value(i), value(j)) < 0
+	})
+	return nil
+}
+
+func compare(a, b reflect.Value) int {
+	if t, ok := a.Interface().(time.Time); ok {
+		u := b.Interface().(time.Time)
+		switch {
+		case t.Before(u):
+			return -1
+		case t.After(u):
+			return 1
+		}
+		return 0
+	}
+
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(a.Int() < b.Int(), a.Int() > b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return compareOrdered(a.Uint() < b.Uint(), a.Uint() > b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(a.Float() < b.Float(), a.Float() > b.Float())
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case reflect.Bool:
+		return compareOrdered(!a.Bool() && b.Bool(), a.Bool() && !b.Bool())
+	}
+	return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+func reverse(slice reflect.Value) {
+	swap := reflect.Swapper(slice.Interface())
+	for i, j := 0, slice.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
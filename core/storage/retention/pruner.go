@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package retention
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const (
+	maintenanceBucket = "storage-maintenance"
+	lastCompactionKey = "last-compaction"
+)
+
+// Policy deletes records older than MaxAge. Records are kept forever if MaxAge is zero.
+type Policy struct {
+	Name   string
+	MaxAge time.Duration
+	Prune  func(before time.Time) error
+}
+
+// Pruner periodically applies retention policies and compacts the store, so that the data
+// directory of long-running nodes does not grow without bounds.
+type Pruner struct {
+	store           storage.Store
+	policies        []Policy
+	interval        time.Duration
+	compactInterval time.Duration
+	now             func() time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPruner returns a new pruner applying policies every interval. The store is compacted
+// once per compactInterval, never if it is zero.
+func NewPruner(store storage.Store, interval, compactInterval time.Duration, policies ...Policy) *Pruner {
+	return &Pruner{
+		store:           store,
+		policies:        policies,
+		interval:        interval,
+		compactInterval: compactInterval,
+		now:             time.Now,
+		stop:            make(chan struct{}),
+	}
+}
+
+// Start starts pruning in the background.
+func (p *Pruner) Start() {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			p.Run()
+
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run applies retention policies and compacts the store if it is due.
+func (p *Pruner) Run() {
+	now := p.now()
+	for _, policy := range p.policies {
+		if policy.MaxAge <= 0 {
+			continue
+		}
+		if err := policy.Prune(now.Add(-policy.MaxAge)); err != nil {
+			log.Warn().Err(err).Msgf("Failed to prune %s", policy.Name)
+		}
+	}
+
+	if p.compactInterval <= 0 {
+		return
+	}
+	var last time.Time
+	err := p.store.GetValue(maintenanceBucket, lastCompactionKey, &last)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Warn().Err(err).Msg("Failed to get last storage compaction time")
+		return
+	}
+	if now.Sub(last) < p.compactInterval {
+		return
+	}
+
+	log.Info().Msg("Compacting storage")
+	if err := p.store.Compact(); err != nil {
+		log.Warn().Err(err).Msg("Failed to compact storage")
+		return
+	}
+	if err := p.store.SetValue(maintenanceBucket, lastCompactionKey, now.UTC()); err != nil {
+		log.Warn().Err(err).Msg("Failed to save storage compaction time")
+	}
+}
+
+// Stop stops pruning.
+func (p *Pruner) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+	"github.com/mysteriumnetwork/node/core/storage/memory"
+)
+
+type store = storage.Store
+
+type compactCounter struct {
+	store
+	compactions int
+}
+
+func (c *compactCounter) Compact() error {
+	c.compactions++
+	return c.store.Compact()
+}
+
+func TestPrunerAppliesPolicies(t *testing.T) {
+	now := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	var sessionsBefore time.Time
+	pruned := false
+
+	pruner := NewPruner(memory.NewStorage(), time.Hour, 0,
+		Policy{Name: "sessions", MaxAge: 90 * 24 * time.Hour, Prune: func(before time.Time) error {
+			sessionsBefore = before
+			return nil
+		}},
+		Policy{Name: "forever", Prune: func(before time.Time) error {
+			pruned = true
+			return nil
+		}},
+	)
+	pruner.now = func() time.Time { return now }
+
+	pruner.Run()
+
+	assert.Equal(t, now.Add(-90*24*time.Hour), sessionsBefore)
+	assert.False(t, pruned)
+}
+
+func TestPrunerCompactsWhenDue(t *testing.T) {
+	now := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	counter := &compactCounter{store: memory.NewStorage()}
+
+	pruner := NewPruner(counter, time.Hour, 24*time.Hour)
+	pruner.now = func() time.Time { return now }
+
+	pruner.Run()
+	assert.Equal(t, 1, counter.compactions)
+
+	now = now.Add(time.Hour)
+	pruner.Run()
+	assert.Equal(t, 1, counter.compactions)
+
+	now = now.Add(24 * time.Hour)
+	pruner.Run()
+	assert.Equal(t, 2, counter.compactions)
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const (
+	schemaBucket = "schema"
+	versionKey   = "version"
+)
+
+// Migration changes the format of stored records.
+type Migration struct {
+	// Version is the schema version once the migration is applied.
+	Version     int
+	Description string
+	Migrate     func(storage.Store) error
+}
+
+// Migrator applies pending migrations to the store and restores it from a backup if any of them fails.
+type Migrator struct {
+	store     storage.Store
+	backupDir string
+	now       func() time.Time
+}
+
+// NewMigrator returns a new migrator keeping backups in the given directory.
+func NewMigrator(store storage.Store, backupDir string) *Migrator {
+	return &Migrator{
+		store:     store,
+		backupDir: backupDir,
+		now:       time.Now,
+	}
+}
+
+// Version returns the schema version of the store, zero if no migrations were applied.
+func (m *Migrator) Version() (int, error) {
+	var version int
+	err := m.store.GetValue(schemaBucket, versionKey, &version)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, nil
+	}
+	return version, err
+}
+
+// Migrate applies migrations newer than the schema version of the store, in order of versions.
+// The store is backed up first and restored from the backup if a migration fails.
+func (m *Migrator) Migrate(sequence []Migration) error {
+	current, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("could not get storage schema version: %w", err)
+	}
+
+	pending := make([]Migration, 0)
+	for _, migration := range sequence {
+		if migration.Version > current {
+			pending = append(pending, migration)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].Version < pending[j].Version
+	})
+	for i := 1; i < len(pending); i++ {
+		if pending[i].Version == pending[i-1].Version {
+			return fmt.Errorf("duplicate storage migration version %d", pending[i].Version)
+		}
+	}
+
+	backup := filepath.Join(m.backupDir, fmt.Sprintf("backup-v%d-%s", current, m.now().UTC().Format("20060102T150405")))
+	log.Info().Msgf("Backing up storage to %s before migrating from schema version %d", backup, current)
+	if err := m.store.Backup(backup); err != nil {
+		return fmt.Errorf("could not back up storage: %w", err)
+	}
+
+	for _, migration := range pending {
+		log.Info().Msgf("Running storage migration %d: %s", migration.Version, migration.Description)
+		err := migration.Migrate(m.store)
+		if err == nil {
+			err = m.store.SetValue(schemaBucket, versionKey, migration.Version)
+		}
+		if err != nil {
+			log.Error().Err(err).Msgf("Storage migration %d failed, restoring backup %s", migration.Version, backup)
+			if restoreErr := m.store.Restore(backup); restoreErr != nil {
+				return fmt.Errorf("storage migration %d failed: %v, could not restore backup %s: %w", migration.Version, err, backup, restoreErr)
+			}
+			return fmt.Errorf("storage migration %d failed, storage restored from backup: %w", migration.Version, err)
+		}
+	}
+
+	log.Info().Msgf("Storage migrated to schema version %d", pending[len(pending)-1].Version)
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package schema
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+	"github.com/mysteriumnetwork/node/core/storage/memory"
+)
+
+func setValue(key, value string) func(storage.Store) error {
+	return func(st storage.Store) error {
+		return st.SetValue("test", key, value)
+	}
+}
+
+func TestMigratorAppliesPendingMigrationsInOrder(t *testing.T) {
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st := memory.NewStorage()
+	migrator := NewMigrator(st, dir)
+
+	sequence := []Migration{
+		{Version: 2, Migrate: setValue("key", "second")},
+		{Version: 1, Migrate: setValue("key", "first")},
+	}
+	require.NoError(t, migrator.Migrate(sequence))
+
+	version, err := migrator.Version()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	var value string
+	assert.NoError(t, st.GetValue("test", "key", &value))
+	assert.Equal(t, "second", value)
+
+	sequence = append(sequence, Migration{Version: 3, Migrate: setValue("other", "third")})
+	require.NoError(t, migrator.Migrate(sequence))
+	assert.NoError(t, st.GetValue("test", "key", &value))
+	assert.Equal(t, "second", value)
+	assert.NoError(t, st.GetValue("test", "other", &value))
+	assert.Equal(t, "third", value)
+}
+
+func TestMigratorRestoresBackupOnFailure(t *testing.T) {
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st := memory.NewStorage()
+	migrator := NewMigrator(st, dir)
+	require.NoError(t, st.SetValue("test", "key", "original"))
+
+	failure := errors.New("boom")
+	sequence := []Migration{
+		{Version: 1, Migrate: setValue("key", "changed")},
+		{Version: 2, Migrate: func(storage.Store) error { return failure }},
+	}
+	err = migrator.Migrate(sequence)
+	assert.ErrorIs(t, err, failure)
+
+	version, err := migrator.Version()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, version)
+
+	var value string
+	assert.NoError(t, st.GetValue("test", "key", &value))
+	assert.Equal(t, "original", value)
+}
+
+func TestMigratorRejectsDuplicateVersions(t *testing.T) {
+	migrator := NewMigrator(memory.NewStorage(), os.TempDir())
+
+	err := migrator.Migrate([]Migration{
+		{Version: 1, Migrate: setValue("key", "a")},
+		{Version: 1, Migrate: setValue("key", "b")},
+	})
+	assert.Error(t, err)
+}
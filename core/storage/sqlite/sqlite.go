@@ -0,0 +1,27 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package sqlite keeps records in SQLite, it is only built with -tags sqlite as the driver requires cgo.
+package sqlite
+
+import "errors"
+
+// FileName is the name of the database file in the storage directory.
+const FileName = "myst.sqlite"
+
+// ErrNotSupported is returned when the node is built without SQLite.
+var ErrNotSupported = errors.New("SQLite storage is not supported by this build, rebuild with -tags sqlite")
@@ -0,0 +1,252 @@
+//go:build sqlite
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	// registers the sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+	"github.com/mysteriumnetwork/node/core/storage/records"
+)
+
+// Supported reports whether the node is built with SQLite.
+const Supported = true
+
+const schema = `CREATE TABLE IF NOT EXISTS records (
+	bucket TEXT NOT NULL,
+	key BLOB NOT NULL,
+	value BLOB NOT NULL,
+	PRIMARY KEY (bucket, key)
+) WITHOUT ROWID`
+
+// recordsStore is embedded under another name, so that the field does not hide Store method.
+type recordsStore = records.Store
+
+// Store keeps records in SQLite and lets callers aggregate them with SQL queries.
+type Store struct {
+	*recordsStore
+	backend *Backend
+}
+
+// NewStorage creates a new SQLite storage in the given directory.
+func NewStorage(path string) (*Store, error) {
+	backend, err := Open(filepath.Join(path, FileName))
+	if err != nil {
+		return nil, err
+	}
+	return &Store{recordsStore: records.New(backend), backend: backend}, nil
+}
+
+// QueryRecords runs the query against the records table.
+func (s *Store) QueryRecords(query string, args ...interface{}) (*sql.Rows, error) {
+	return s.backend.db.Query(query, args...)
+}
+
+// Backend keeps buckets in a single SQLite table.
+type Backend struct {
+	file string
+	db   *sql.DB
+}
+
+// Open creates new or opens existing SQLite database in WAL mode, so that reads are not blocked by writes.
+func Open(file string) (*Backend, error) {
+	db, err := openDB(file)
+	if err != nil {
+		return nil, err
+	}
+	return &Backend{file: file, db: db}, nil
+}
+
+func openDB(file string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:%s?_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=5000", file)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
+	return db, nil
+}
+
+// Get returns the value of the key.
+func (b *Backend) Get(bucket string, key []byte) ([]byte, error) {
+	var value []byte
+	err := b.db.QueryRow("SELECT value FROM records WHERE bucket = ? AND key = ?", bucket, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrNotFound
+	}
+	return value, err
+}
+
+// Put sets the value of the key.
+func (b *Backend) Put(bucket string, key, value []byte) error {
+	_, err := b.db.Exec(
+		"INSERT INTO records (bucket, key, value) VALUES (?, ?, ?) ON CONFLICT (bucket, key) DO UPDATE SET value = excluded.value",
+		bucket, key, value,
+	)
+	return err
+}
+
+// Delete removes the key.
+func (b *Backend) Delete(bucket string, key []byte) error {
+	res, err := b.db.Exec("DELETE FROM records WHERE bucket = ? AND key = ?", bucket, key)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return storage.ErrNotFound
+	}
+	return nil
+}
+
+// ForEach calls fn for every key of the bucket, in byte order of keys.
+func (b *Backend) ForEach(bucket string, fn func(key, value []byte) error) error {
+	rows, err := b.db.Query("SELECT key, value FROM records WHERE bucket = ? ORDER BY key", bucket)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value []byte
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Backup writes a consistent copy of the database to the file.
+func (b *Backend) Backup(file string) error {
+	_, err := b.db.Exec("VACUUM INTO ?", file)
+	return err
+}
+
+// Restore closes the database, replaces it with the backup file and opens it again.
+func (b *Backend) Restore(file string) error {
+	if err := b.db.Close(); err != nil {
+		return err
+	}
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(b.file + suffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := copyFile(file, b.file); err != nil {
+		return fmt.Errorf("failed to restore SQLite backup: %w", err)
+	}
+
+	db, err := openDB(b.file)
+	if err != nil {
+		return err
+	}
+	b.db = db
+	return nil
+}
+
+// Compact checkpoints the write-ahead log and rebuilds the database file without free pages.
+func (b *Backend) Compact() error {
+	if _, err := b.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return err
+	}
+	_, err := b.db.Exec("VACUUM")
+	return err
+}
+
+// Close closes the database.
+func (b *Backend) Close() error {
+	return b.db.Close()
+}
+
+// Reencode copies the database file into a new one, transforming every stored value.
+func Reencode(src, dst string, transform func(bucket string, key, value []byte) ([]byte, error)) error {
+	in, err := openDB(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := openDB(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	rows, err := in.Query("SELECT bucket, key, value FROM records")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tx, err := out.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for rows.Next() {
+		var bucket string
+		var key, value []byte
+		if err := rows.Scan(&bucket, &key, &value); err != nil {
+			return err
+		}
+		if value, err = transform(bucket, key, value); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("INSERT INTO records (bucket, key, value) VALUES (?, ?, ?)", bucket, key, value); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,97 @@
+//go:build sqlite
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/asdine/storm/v3/q"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+type entry struct {
+	ID   int64 `storm:"id"`
+	Name string
+}
+
+func Test_Storage(t *testing.T) {
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st, err := NewStorage(dir)
+	require.NoError(t, err)
+
+	assert.NoError(t, st.SetValue("values", "key", 42))
+	for _, id := range []int64{300, 2, 10} {
+		assert.NoError(t, st.Store("entries", &entry{ID: id, Name: "entry"}))
+	}
+	assert.NoError(t, st.Store("entries", &entry{ID: 10, Name: "updated"}))
+	require.NoError(t, st.Close())
+
+	st, err = NewStorage(dir)
+	require.NoError(t, err)
+	defer st.Close()
+
+	var value int
+	assert.NoError(t, st.GetValue("values", "key", &value))
+	assert.Equal(t, 42, value)
+
+	var all []entry
+	assert.NoError(t, st.GetAllFrom("entries", &all))
+	assert.Equal(t, []entry{{2, "entry"}, {10, "updated"}, {300, "entry"}}, all)
+
+	var last entry
+	assert.NoError(t, st.GetLast("entries", &last))
+	assert.Equal(t, int64(300), last.ID)
+
+	assert.NoError(t, st.Find("entries", storage.Query{Where: q.Eq("Name", "entry"), Reverse: true}, &all))
+	assert.Equal(t, []entry{{300, "entry"}, {2, "entry"}}, all)
+
+	assert.Equal(t, storage.ErrNotFound, st.DeleteKey("values", "missing"))
+}
+
+func Test_StorageBackupRestore(t *testing.T) {
+	dir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	st, err := NewStorage(dir)
+	require.NoError(t, err)
+	defer st.Close()
+
+	backup := filepath.Join(dir, "backup")
+	require.NoError(t, st.SetValue("values", "key", "original"))
+	require.NoError(t, st.Backup(backup))
+	require.NoError(t, st.SetValue("values", "key", "changed"))
+	require.NoError(t, st.SetValue("values", "other", "added"))
+
+	require.NoError(t, st.Restore(backup))
+
+	var value string
+	assert.NoError(t, st.GetValue("values", "key", &value))
+	assert.Equal(t, "original", value)
+	assert.Equal(t, storage.ErrNotFound, st.GetValue("values", "other", &value))
+}
@@ -0,0 +1,53 @@
+//go:build !sqlite
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package sqlite
+
+import "github.com/mysteriumnetwork/node/core/storage/records"
+
+// Supported reports whether the node is built with SQLite.
+const Supported = false
+
+// recordsStore is embedded under another name, so that the field does not hide Store method.
+type recordsStore = records.Store
+
+// Store keeps records in SQLite.
+type Store struct {
+	*recordsStore
+}
+
+// NewStorage creates a new SQLite storage in the given directory.
+func NewStorage(path string) (*Store, error) {
+	return nil, ErrNotSupported
+}
+
+// Backend keeps buckets in a single SQLite table.
+type Backend struct {
+	records.Backend
+}
+
+// Open creates new or opens existing SQLite database.
+func Open(file string) (*Backend, error) {
+	return nil, ErrNotSupported
+}
+
+// Reencode copies the database file into a new one, transforming every stored value.
+func Reencode(src, dst string, transform func(bucket string, key, value []byte) ([]byte, error)) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/asdine/storm/v3/q"
+)
+
+// Backend names a persistence backend implementing Store.
+type Backend string
+
+const (
+	// BackendBolt keeps data in a single boltdb file.
+	BackendBolt = Backend("bolt")
+	// BackendSQLite keeps data in a SQLite database in WAL mode.
+	BackendSQLite = Backend("sqlite")
+	// BackendMemory keeps data in memory only, nothing survives a restart.
+	BackendMemory = Backend("memory")
+)
+
+// Query selects structs from a bucket.
+type Query struct {
+	// Where filters structs, all structs are selected if nil.
+	Where q.Matcher
+	// OrderBy is the name of the field to sort by, structs are sorted by ID if empty.
+	OrderBy string
+	// Reverse reverses the order.
+	Reverse bool
+	// Limit limits the number of structs returned, unlimited if zero.
+	Limit int
+}
+
+// Store is the persistence layer of the node.
+//
+// Key values are kept with GetValue and SetValue, structs are kept with Store and
+// are identified by a field tagged `storm:"id"` or, failing that, named ID.
+type Store interface {
+	// GetValue gets the value of the key.
+	GetValue(bucket string, key interface{}, to interface{}) error
+	// SetValue sets the value of the key.
+	SetValue(bucket string, key interface{}, value interface{}) error
+	// GetAllValues gets all values of the bucket into a slice pointer.
+	GetAllValues(bucket string, to interface{}) error
+	// DeleteKey removes the key.
+	DeleteKey(bucket string, key interface{}) error
+
+	// Store saves the struct, replacing the one with the same ID.
+	Store(bucket string, data interface{}) error
+	// Update updates non zero fields of the stored struct with the same ID.
+	Update(bucket string, data interface{}) error
+	// Delete removes the struct with the same ID.
+	Delete(bucket string, data interface{}) error
+	// GetAllFrom gets all structs of the bucket into a slice pointer.
+	GetAllFrom(bucket string, to interface{}) error
+	// GetOneByField gets the first struct with the field equal to the key.
+	GetOneByField(bucket string, fieldName string, key interface{}, to interface{}) error
+	// GetLast gets the struct with the greatest ID.
+	GetLast(bucket string, to interface{}) error
+	// Find gets the structs selected by the query into a slice pointer, ErrNotFound is returned if none match.
+	Find(bucket string, query Query, to interface{}) error
+	// DeleteMatching removes the structs of the given type matched by the matcher.
+	DeleteMatching(bucket string, matcher q.Matcher, kind interface{}) error
+
+	// Backup writes a consistent copy of the store to the file.
+	Backup(file string) error
+	// Restore replaces contents of the store with the backup file. It must not be used concurrently with other methods.
+	Restore(file string) error
+	// Compact reclaims space left by deleted data.
+	Compact() error
+
+	// Close closes the store.
+	Close() error
+}
+
+// SQLQuerier is implemented by stores keeping structs as JSON documents in SQL table
+// `records (bucket, key, value)`, so that they can be aggregated without loading every struct.
+type SQLQuerier interface {
+	// QueryRecords runs the query against the records table.
+	QueryRecords(query string, args ...interface{}) (*sql.Rows, error)
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package updater
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+const (
+	signatureSuffix  = ".sig"
+	maxBinarySize    = 512 << 20
+	maxSignatureSize = 4 << 10
+	smokeTestTimeout = 30 * time.Second
+)
+
+// ErrInvalidSignature is returned when a downloaded binary is not signed with the release key.
+var ErrInvalidSignature = errors.New("binary signature is invalid")
+
+// download saves the resource at url to file, returning SHA-256 digest of its content.
+func download(client httpClient, url, file string, limit int64) ([]byte, error) {
+	out, err := os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0700)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+
+	digest := sha256.New()
+	if err := fetch(client, url, io.MultiWriter(out, digest), limit); err != nil {
+		return nil, err
+	}
+	if err := out.Sync(); err != nil {
+		return nil, err
+	}
+	return digest.Sum(nil), out.Close()
+}
+
+func fetch(client httpClient, url string, w io.Writer, limit int64) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer res.Body.Close()
+
+	if err := requests.ParseResponseError(res); err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+
+	n, err := io.Copy(w, io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if n > limit {
+		return fmt.Errorf("failed to download %s: larger than %d bytes", url, limit)
+	}
+	return nil
+}
+
+// verifySignature checks the base64 encoded Ed25519 signature of the binary SHA-256 digest.
+func verifySignature(key ed25519.PublicKey, digest, signature []byte) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || !ed25519.Verify(key, digest, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// smokeTest makes sure the binary runs on this platform and reports the expected version.
+func smokeTest(binary, version string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), smokeTestTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, binary, "version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("new binary failed to run: %w", err)
+	}
+	if !bytes.Contains(out, []byte(version)) {
+		return fmt.Errorf("new binary does not report version %s", version)
+	}
+	return nil
+}
+
+// copyFile copies src to dst keeping file mode, dst is synced to disk before returning.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// replaceFile atomically replaces dst with src. Windows does not allow replacing a running executable,
+// so dst is moved aside first and the move is undone if src can't take its place.
+func replaceFile(src, dst string) error {
+	if runtime.GOOS != "windows" {
+		return os.Rename(src, dst)
+	}
+
+	aside := dst + ".replaced"
+	_ = os.Remove(aside)
+	if err := os.Rename(dst, aside); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		_ = os.Rename(aside, dst)
+		return err
+	}
+	// Fails while the replaced executable is still running, it is removed with the next update.
+	_ = os.Remove(aside)
+	return nil
+}
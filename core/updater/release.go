@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package updater
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+const (
+	apiURI       = "https://api.github.com"
+	releasesPath = "repos/mysteriumnetwork/node/releases"
+	releasesPage = 30
+)
+
+// Channel is a release channel node updates are taken from.
+type Channel string
+
+const (
+	// ChannelStable contains releases only.
+	ChannelStable Channel = "stable"
+	// ChannelBeta contains releases and pre-releases.
+	ChannelBeta Channel = "beta"
+)
+
+// ParseChannel parses a release channel name.
+func ParseChannel(name string) (Channel, error) {
+	switch channel := Channel(strings.ToLower(name)); channel {
+	case ChannelStable, ChannelBeta:
+		return channel, nil
+	default:
+		return "", fmt.Errorf("unknown release channel %q, expected %s or %s", name, ChannelStable, ChannelBeta)
+	}
+}
+
+// Release is a node release published for the current platform.
+type Release struct {
+	Version      string
+	Prerelease   bool
+	BinaryURL    string
+	SignatureURL string
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type githubRelease struct {
+	TagName    string        `json:"tag_name"`
+	Draft      bool          `json:"draft"`
+	Prerelease bool          `json:"prerelease"`
+	Assets     []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+type releaseSource struct {
+	http   httpClient
+	apiURI string
+	path   string
+}
+
+func newReleaseSource(client httpClient) *releaseSource {
+	return &releaseSource{http: client, apiURI: apiURI, path: releasesPath}
+}
+
+// latest returns the newest release of the channel which ships a signed binary for the current platform.
+func (s *releaseSource) latest(channel Channel) (Release, bool, error) {
+	req, err := requests.NewGetRequest(s.apiURI, s.path, url.Values{"per_page": []string{fmt.Sprint(releasesPage)}})
+	if err != nil {
+		return Release{}, false, fmt.Errorf("failed to create node releases fetch request: %w", err)
+	}
+
+	res, err := s.http.Do(req)
+	if err != nil {
+		return Release{}, false, fmt.Errorf("failed to fetch node releases: %w", err)
+	}
+	defer res.Body.Close()
+
+	if err := requests.ParseResponseError(res); err != nil {
+		return Release{}, false, fmt.Errorf("response error: %w", err)
+	}
+
+	var releases []githubRelease
+	if err := requests.ParseResponseJSON(res, &releases); err != nil {
+		return Release{}, false, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var latest Release
+	found := false
+	for _, r := range releases {
+		if r.Draft || (r.Prerelease && channel != ChannelBeta) || !semver.IsValid(canonicalVersion(r.TagName)) {
+			continue
+		}
+		release, ok := platformRelease(r)
+		if !ok {
+			continue
+		}
+		if !found || isNewer(release.Version, latest.Version) {
+			latest, found = release, true
+		}
+	}
+	return latest, found, nil
+}
+
+func platformRelease(r githubRelease) (Release, bool) {
+	binary := binaryAssetName(runtime.GOOS, runtime.GOARCH)
+	release := Release{Version: strings.TrimPrefix(r.TagName, "v"), Prerelease: r.Prerelease}
+	for _, asset := range r.Assets {
+		switch asset.Name {
+		case binary:
+			release.BinaryURL = asset.BrowserDownloadURL
+		case binary + signatureSuffix:
+			release.SignatureURL = asset.BrowserDownloadURL
+		}
+	}
+	return release, release.BinaryURL != "" && release.SignatureURL != ""
+}
+
+func binaryAssetName(goos, goarch string) string {
+	name := fmt.Sprintf("myst_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// isNewer returns true if version is newer than current. Versions of development builds, e.g. "source.1a2b3c4d",
+// are older than any release.
+func isNewer(version, current string) bool {
+	return semver.Compare(canonicalVersion(version), canonicalVersion(current)) > 0
+}
+
+func canonicalVersion(version string) string {
+	return "v" + strings.TrimPrefix(version, "v")
+}
@@ -0,0 +1,421 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package updater
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// State is a state of the updater.
+type State string
+
+const (
+	// StateIdle means no update was checked for yet.
+	StateIdle State = "idle"
+	// StateChecking means the release channel is being checked.
+	StateChecking State = "checking"
+	// StateUpToDate means the node runs the latest release of the channel.
+	StateUpToDate State = "up_to_date"
+	// StateAvailable means a newer release was found but not installed.
+	StateAvailable State = "available"
+	// StateDownloading means a newer release is being downloaded and verified.
+	StateDownloading State = "downloading"
+	// StateRestarting means the new binary is installed and the node restarts.
+	StateRestarting State = "restarting"
+	// StateFailed means the last check or update failed.
+	StateFailed State = "failed"
+)
+
+var (
+	// ErrUpdateInProgress is returned when an update is requested while another one runs.
+	ErrUpdateInProgress = errors.New("node update is already in progress")
+	// ErrNoPublicKey is returned when an update is requested without the release signing key configured.
+	ErrNoPublicKey = errors.New("release signing key is not configured")
+	// ErrRolledBack is returned on start when the updated node failed to start too many times
+	// and the previous binary was restored. The node must be restarted to run it.
+	ErrRolledBack = errors.New("node update rolled back to the previous binary")
+)
+
+// Status describes the last update check or attempt.
+type Status struct {
+	State          State
+	Channel        Channel
+	CurrentVersion string
+	LatestVersion  string
+	Error          string
+}
+
+// Config configures the updater.
+type Config struct {
+	Channel Channel
+	// PublicKey release binaries must be signed with.
+	PublicKey ed25519.PublicKey
+	// DataDir keeps the state of installed updates between restarts.
+	DataDir string
+	// HealthCheckDelay is how long an updated node runs before its health is checked.
+	HealthCheckDelay time.Duration
+	// MaxStartAttempts is how many times an updated node may fail to start before the previous binary is restored.
+	MaxStartAttempts int
+}
+
+// pendingUpdate is an installed update which was not confirmed healthy yet.
+type pendingUpdate struct {
+	Version         string `json:"version"`
+	PreviousVersion string `json:"previous_version"`
+	Executable      string `json:"executable"`
+	Backup          string `json:"backup"`
+	Attempts        int    `json:"attempts"`
+}
+
+const pendingFile = "update.json"
+
+// RestartExitCode is the exit code of a node restarting to run the installed or restored binary.
+// Any non-zero code makes service managers, e.g. systemd with Restart=on-failure, start the node again.
+const RestartExitCode = 75
+
+// Updater checks the release channel, installs signed node binaries and restores the previous binary
+// when the updated node fails to start or to pass the health check. Installed binaries are started by
+// restarting the node, which relies on the service manager (e.g. systemd) restarting it on exit.
+type Updater struct {
+	config  Config
+	version string
+	http    httpClient
+	source  *releaseSource
+	restart func()
+
+	executable func() (string, error)
+	smokeTest  func(binary, version string) error
+
+	mu     sync.Mutex
+	status Status
+	busy   bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// New returns a new updater of the node running version. Restart is called after the new binary is installed.
+func New(client httpClient, version string, config Config, restart func()) *Updater {
+	return &Updater{
+		config:     config,
+		version:    version,
+		http:       client,
+		source:     newReleaseSource(client),
+		restart:    restart,
+		executable: executablePath,
+		smokeTest:  smokeTest,
+		status:     Status{State: StateIdle, Channel: config.Channel, CurrentVersion: version},
+		stop:       make(chan struct{}),
+	}
+}
+
+// Status returns the last update check or attempt.
+func (u *Updater) Status() Status {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.status
+}
+
+// Check checks the release channel, the configured one if empty, for a newer release without installing it.
+func (u *Updater) Check(channel Channel) (Status, error) {
+	channel = u.channelOrDefault(channel)
+	if err := u.begin(channel); err != nil {
+		return Status{}, err
+	}
+	defer u.end()
+
+	if _, err := u.check(channel); err != nil {
+		u.fail(err)
+	}
+	return u.Status(), nil
+}
+
+// Update checks the release channel, the configured one if empty, and installs a newer release
+// in the background, restarting the node once the new binary is in place.
+func (u *Updater) Update(channel Channel) error {
+	if len(u.config.PublicKey) != ed25519.PublicKeySize {
+		return ErrNoPublicKey
+	}
+	channel = u.channelOrDefault(channel)
+	if err := u.begin(channel); err != nil {
+		return err
+	}
+
+	go func() {
+		defer u.end()
+
+		if err := u.update(channel); err != nil {
+			log.Error().Err(err).Msg("Node update failed")
+			u.fail(err)
+		}
+	}()
+	return nil
+}
+
+func (u *Updater) channelOrDefault(channel Channel) Channel {
+	if channel == "" {
+		return u.config.Channel
+	}
+	return channel
+}
+
+func (u *Updater) begin(channel Channel) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.busy {
+		return ErrUpdateInProgress
+	}
+	u.busy = true
+	u.status = Status{State: StateChecking, Channel: channel, CurrentVersion: u.version}
+	return nil
+}
+
+func (u *Updater) end() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.busy = false
+}
+
+func (u *Updater) setState(state State, latest string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.status.State = state
+	u.status.LatestVersion = latest
+}
+
+func (u *Updater) fail(err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.status.State = StateFailed
+	u.status.Error = err.Error()
+}
+
+func (u *Updater) check(channel Channel) (Release, error) {
+	release, found, err := u.source.latest(channel)
+	if err != nil {
+		return Release{}, err
+	}
+	if !found || !isNewer(release.Version, u.version) {
+		u.setState(StateUpToDate, release.Version)
+		return Release{}, nil
+	}
+	u.setState(StateAvailable, release.Version)
+	return release, nil
+}
+
+func (u *Updater) update(channel Channel) error {
+	release, err := u.check(channel)
+	if err != nil || release.Version == "" {
+		return err
+	}
+	u.setState(StateDownloading, release.Version)
+	log.Info().Msgf("Updating node from %s to %s", u.version, release.Version)
+
+	exe, err := u.executable()
+	if err != nil {
+		return fmt.Errorf("could not locate node executable: %w", err)
+	}
+
+	// Downloaded next to the executable so that it can be renamed in its place.
+	binary := exe + ".new"
+	defer os.Remove(binary)
+
+	digest, err := download(u.http, release.BinaryURL, binary, maxBinarySize)
+	if err != nil {
+		return err
+	}
+	var signature bytes.Buffer
+	if err := fetch(u.http, release.SignatureURL, &signature, maxSignatureSize); err != nil {
+		return err
+	}
+	if err := verifySignature(u.config.PublicKey, digest, signature.Bytes()); err != nil {
+		return err
+	}
+	if err := u.smokeTest(binary, release.Version); err != nil {
+		return err
+	}
+
+	pending := pendingUpdate{
+		Version:         release.Version,
+		PreviousVersion: u.version,
+		Executable:      exe,
+		Backup:          exe + ".old",
+	}
+	if err := copyFile(exe, pending.Backup); err != nil {
+		return fmt.Errorf("could not back up node executable: %w", err)
+	}
+	// Saved before the executable is replaced, so that a crash in between leaves a record which is
+	// discarded on start as it does not match the running version.
+	if err := u.savePending(pending); err != nil {
+		return fmt.Errorf("could not save update state: %w", err)
+	}
+	if err := replaceFile(binary, exe); err != nil {
+		_ = u.clearPending()
+		return fmt.Errorf("could not replace node executable: %w", err)
+	}
+
+	log.Info().Msgf("Node updated to %s, restarting", release.Version)
+	u.setState(StateRestarting, release.Version)
+	go u.restart()
+	return nil
+}
+
+// Recover must be called on start before the node is bootstrapped. It counts starts of an updated node which
+// was not confirmed healthy yet and restores the previous binary once they exceed the limit.
+func (u *Updater) Recover() error {
+	pending, ok, err := u.loadPending()
+	if err != nil || !ok {
+		return err
+	}
+	if pending.Version != u.version {
+		// The running binary is not the installed update, e.g. it was replaced manually.
+		return u.clearPending()
+	}
+
+	pending.Attempts++
+	if pending.Attempts > u.config.MaxStartAttempts {
+		log.Error().Msgf("Node %s failed to start %d times, restoring %s", pending.Version, pending.Attempts-1, pending.PreviousVersion)
+		if err := u.rollback(pending); err != nil {
+			return err
+		}
+		return ErrRolledBack
+	}
+	return u.savePending(pending)
+}
+
+// ConfirmHealthy checks health of an updated node after the configured delay. The update is confirmed if
+// the check passes, otherwise the previous binary is restored and the node restarted.
+func (u *Updater) ConfirmHealthy(check func(ctx context.Context) error) {
+	pending, ok, err := u.loadPending()
+	if err != nil {
+		log.Error().Err(err).Msg("Could not load update state")
+		return
+	}
+	if !ok {
+		return
+	}
+
+	go func() {
+		select {
+		case <-u.stop:
+			return
+		case <-time.After(u.config.HealthCheckDelay):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := check(ctx); err != nil {
+			log.Error().Err(err).Msgf("Node %s failed the health check, restoring %s", pending.Version, pending.PreviousVersion)
+			if err := u.rollback(pending); err != nil {
+				log.Error().Err(err).Msg("Could not restore the previous node binary")
+				return
+			}
+			u.setState(StateRestarting, pending.PreviousVersion)
+			go u.restart()
+			return
+		}
+
+		if err := os.Remove(pending.Backup); err != nil && !os.IsNotExist(err) {
+			log.Warn().Err(err).Msg("Could not remove the previous node binary")
+		}
+		if err := u.clearPending(); err != nil {
+			log.Error().Err(err).Msg("Could not clear update state")
+			return
+		}
+		log.Info().Msgf("Node update to %s confirmed", pending.Version)
+	}()
+}
+
+// Stop stops the pending health check.
+func (u *Updater) Stop() {
+	u.stopOnce.Do(func() {
+		close(u.stop)
+	})
+}
+
+func (u *Updater) rollback(pending pendingUpdate) error {
+	if err := replaceFile(pending.Backup, pending.Executable); err != nil {
+		return fmt.Errorf("could not restore node executable: %w", err)
+	}
+	return u.clearPending()
+}
+
+func (u *Updater) pendingPath() string {
+	return filepath.Join(u.config.DataDir, pendingFile)
+}
+
+func (u *Updater) loadPending() (pendingUpdate, bool, error) {
+	data, err := os.ReadFile(u.pendingPath())
+	if os.IsNotExist(err) {
+		return pendingUpdate{}, false, nil
+	}
+	if err != nil {
+		return pendingUpdate{}, false, err
+	}
+
+	var pending pendingUpdate
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return pendingUpdate{}, false, fmt.Errorf("could not parse update state: %w", err)
+	}
+	return pending, true, nil
+}
+
+func (u *Updater) savePending(pending pendingUpdate) error {
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+
+	tmp := u.pendingPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, u.pendingPath())
+}
+
+func (u *Updater) clearPending() error {
+	if err := os.Remove(u.pendingPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func executablePath() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
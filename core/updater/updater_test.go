@@ -0,0 +1,267 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package updater
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type releaseServer struct {
+	*httptest.Server
+	releases []githubRelease
+	binaries map[string][]byte
+}
+
+func newReleaseServer(t *testing.T) *releaseServer {
+	s := &releaseServer{binaries: make(map[string][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/"+releasesPath {
+			assert.NoError(t, json.NewEncoder(w).Encode(s.releases))
+			return
+		}
+		content, ok := s.binaries[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *releaseServer) publish(version string, prerelease bool, binary, signature []byte) {
+	name := binaryAssetName(runtime.GOOS, runtime.GOARCH)
+	s.binaries["/"+version+"/"+name] = binary
+	s.binaries["/"+version+"/"+name+signatureSuffix] = signature
+	s.releases = append(s.releases, githubRelease{
+		TagName:    version,
+		Prerelease: prerelease,
+		Assets: []githubAsset{
+			{Name: name, BrowserDownloadURL: s.URL + "/" + version + "/" + name},
+			{Name: name + signatureSuffix, BrowserDownloadURL: s.URL + "/" + version + "/" + name + signatureSuffix},
+		},
+	})
+}
+
+func sign(key ed25519.PrivateKey, binary []byte) []byte {
+	digest := sha256.Sum256(binary)
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, digest[:])))
+}
+
+type testUpdater struct {
+	*Updater
+	exe      string
+	restarts chan struct{}
+}
+
+func newTestUpdater(t *testing.T, server *releaseServer, version string, key ed25519.PublicKey) *testUpdater {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "myst")
+	require.NoError(t, os.WriteFile(exe, []byte("old binary"), 0700))
+
+	restarts := make(chan struct{}, 1)
+	u := New(server.Client(), version, Config{
+		Channel:          ChannelStable,
+		PublicKey:        key,
+		DataDir:          dir,
+		HealthCheckDelay: time.Millisecond,
+		MaxStartAttempts: 2,
+	}, func() { restarts <- struct{}{} })
+	u.source.apiURI = server.URL
+	u.executable = func() (string, error) { return exe, nil }
+	u.smokeTest = func(string, string) error { return nil }
+
+	return &testUpdater{Updater: u, exe: exe, restarts: restarts}
+}
+
+func (u *testUpdater) waitFor(t *testing.T, state State) {
+	assert.Eventually(t, func() bool { return u.Status().State == state }, time.Second, 5*time.Millisecond)
+}
+
+func readFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestUpdater_CheckFollowsChannel(t *testing.T) {
+	server := newReleaseServer(t)
+	server.publish("1.1.0", false, []byte("1.1.0"), []byte("sig"))
+	server.publish("1.2.0-rc1", true, []byte("1.2.0-rc1"), []byte("sig"))
+	server.publish("0.9.0", false, []byte("0.9.0"), []byte("sig"))
+	u := newTestUpdater(t, server, "1.0.0", nil)
+
+	status, err := u.Check(ChannelStable)
+	require.NoError(t, err)
+	assert.Equal(t, Status{State: StateAvailable, Channel: ChannelStable, CurrentVersion: "1.0.0", LatestVersion: "1.1.0"}, status)
+
+	status, err = u.Check(ChannelBeta)
+	require.NoError(t, err)
+	assert.Equal(t, StateAvailable, status.State)
+	assert.Equal(t, "1.2.0-rc1", status.LatestVersion)
+
+	u.version = "1.1.0"
+	status, err = u.Check(ChannelStable)
+	require.NoError(t, err)
+	assert.Equal(t, StateUpToDate, status.State)
+}
+
+func TestUpdater_InstallsSignedRelease(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	server := newReleaseServer(t)
+	server.publish("1.1.0", false, []byte("new binary"), sign(key, []byte("new binary")))
+	u := newTestUpdater(t, server, "1.0.0", pub)
+
+	require.NoError(t, u.Update(ChannelStable))
+	select {
+	case <-u.restarts:
+	case <-time.After(time.Second):
+		t.Fatal("node was not restarted")
+	}
+
+	assert.Equal(t, StateRestarting, u.Status().State)
+	assert.Equal(t, "new binary", readFile(t, u.exe))
+	assert.Equal(t, "old binary", readFile(t, u.exe+".old"))
+	pending, ok, err := u.loadPending()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, pendingUpdate{Version: "1.1.0", PreviousVersion: "1.0.0", Executable: u.exe, Backup: u.exe + ".old"}, pending)
+}
+
+func TestUpdater_RejectsInvalidBinaries(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for name, test := range map[string]struct {
+		signature []byte
+		smokeTest error
+		expected  error
+	}{
+		"signed with other key": {signature: sign(otherKey, []byte("new binary")), expected: ErrInvalidSignature},
+		"signature malformed":   {signature: []byte("not base64"), expected: ErrInvalidSignature},
+		"failed to run":         {signature: sign(key, []byte("new binary")), smokeTest: errors.New("exec format error")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := newReleaseServer(t)
+			server.publish("1.1.0", false, []byte("new binary"), test.signature)
+			u := newTestUpdater(t, server, "1.0.0", pub)
+			u.smokeTest = func(string, string) error { return test.smokeTest }
+
+			require.NoError(t, u.Update(ChannelStable))
+			u.waitFor(t, StateFailed)
+
+			expected := test.expected
+			if expected == nil {
+				expected = test.smokeTest
+			}
+			assert.Equal(t, expected.Error(), u.Status().Error)
+			assert.Equal(t, "old binary", readFile(t, u.exe))
+			assert.NoFileExists(t, u.exe+".new")
+			_, ok, err := u.loadPending()
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestUpdater_RequiresPublicKey(t *testing.T) {
+	u := newTestUpdater(t, newReleaseServer(t), "1.0.0", nil)
+	assert.Equal(t, ErrNoPublicKey, u.Update(ChannelStable))
+}
+
+func TestUpdater_RecoverRollsBackAfterFailedStarts(t *testing.T) {
+	u := newTestUpdater(t, newReleaseServer(t), "1.1.0", nil)
+	require.NoError(t, os.WriteFile(u.exe+".old", []byte("previous binary"), 0700))
+	require.NoError(t, u.savePending(pendingUpdate{Version: "1.1.0", PreviousVersion: "1.0.0", Executable: u.exe, Backup: u.exe + ".old"}))
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		require.NoError(t, u.Recover(), fmt.Sprintf("attempt %d", attempt))
+	}
+	assert.Equal(t, ErrRolledBack, u.Recover())
+
+	assert.Equal(t, "previous binary", readFile(t, u.exe))
+	_, ok, err := u.loadPending()
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestUpdater_RecoverDiscardsUpdateOfOtherVersion(t *testing.T) {
+	u := newTestUpdater(t, newReleaseServer(t), "1.0.0", nil)
+	require.NoError(t, u.savePending(pendingUpdate{Version: "1.1.0", PreviousVersion: "1.0.0", Executable: u.exe, Backup: u.exe + ".old"}))
+
+	require.NoError(t, u.Recover())
+	assert.Equal(t, "old binary", readFile(t, u.exe))
+	_, ok, err := u.loadPending()
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestUpdater_ConfirmHealthy(t *testing.T) {
+	for name, test := range map[string]struct {
+		health     error
+		executable string
+		restarted  bool
+	}{
+		"healthy":   {executable: "old binary"},
+		"unhealthy": {health: errors.New("storage is not writable"), executable: "previous binary", restarted: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			u := newTestUpdater(t, newReleaseServer(t), "1.1.0", nil)
+			require.NoError(t, os.WriteFile(u.exe+".old", []byte("previous binary"), 0700))
+			require.NoError(t, u.savePending(pendingUpdate{Version: "1.1.0", PreviousVersion: "1.0.0", Executable: u.exe, Backup: u.exe + ".old"}))
+
+			u.ConfirmHealthy(func(context.Context) error { return test.health })
+			assert.Eventually(t, func() bool {
+				_, ok, err := u.loadPending()
+				return err == nil && !ok
+			}, time.Second, 5*time.Millisecond)
+
+			assert.Equal(t, test.executable, readFile(t, u.exe))
+			assert.NoFileExists(t, u.exe+".old")
+			if test.restarted {
+				select {
+				case <-u.restarts:
+				case <-time.After(time.Second):
+					t.Fatal("node was not restarted")
+				}
+			}
+		})
+	}
+}
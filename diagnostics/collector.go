@@ -0,0 +1,293 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/metadata"
+	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/nat/mapping"
+)
+
+const (
+	probeTimeout      = 10 * time.Second
+	defaultBrokerPort = "4222"
+	// logTailSize limits the amount of the most recent log included in the bundle.
+	logTailSize = 2 << 20
+)
+
+type natProber interface {
+	Probe(context.Context) (nat.NATType, error)
+}
+
+type portMappingLister interface {
+	Mappings() []mapping.Mapping
+	Stats() []mapping.ProtocolStats
+}
+
+type brokerConnection interface {
+	Servers() []string
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Probe is the result of a reachability check.
+type Probe struct {
+	Target    string `json:"target"`
+	Reachable bool   `json:"reachable"`
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// NATReport describes the detected NAT type.
+type NATReport struct {
+	Type  string `json:"type,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// PortMappingReport describes the port mappings made on the router.
+type PortMappingReport struct {
+	Mappings []mapping.Mapping       `json:"mappings"`
+	Stats    []mapping.ProtocolStats `json:"stats"`
+}
+
+// Report is the connectivity summary included in the diagnostics bundle.
+type Report struct {
+	GeneratedAt      time.Time         `json:"generated_at"`
+	Version          string            `json:"version"`
+	OS               string            `json:"os"`
+	Arch             string            `json:"arch"`
+	NAT              NATReport         `json:"nat"`
+	PortMapping      PortMappingReport `json:"port_mapping"`
+	Broker           []Probe           `json:"broker"`
+	Discovery        Probe             `json:"discovery"`
+	ConnectionErrors []ConnectionError `json:"connection_errors"`
+	LogError         string            `json:"log_error,omitempty"`
+}
+
+// Collector gathers connectivity diagnostics and recent logs for support.
+type Collector struct {
+	natProber        natProber
+	portMapper       portMappingLister
+	broker           brokerConnection
+	discoveryAddress string
+	httpClient       httpClient
+	errors           *ErrorRecorder
+	logFilepath      string
+}
+
+// NewCollector creates a diagnostics collector. Broker is not probed when its connection is nil
+// and logs are omitted when log file path is empty.
+func NewCollector(
+	natProber natProber,
+	portMapper portMappingLister,
+	broker brokerConnection,
+	discoveryAddress string,
+	httpClient httpClient,
+	errorRecorder *ErrorRecorder,
+	logFilepath string,
+) *Collector {
+	return &Collector{
+		natProber:        natProber,
+		portMapper:       portMapper,
+		broker:           broker,
+		discoveryAddress: discoveryAddress,
+		httpClient:       httpClient,
+		errors:           errorRecorder,
+		logFilepath:      logFilepath,
+	}
+}
+
+// Report runs connectivity checks and summarizes their results.
+func (c *Collector) Report(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	report := Report{
+		GeneratedAt:      time.Now().UTC(),
+		Version:          metadata.VersionAsString(),
+		OS:               runtime.GOOS,
+		Arch:             runtime.GOARCH,
+		ConnectionErrors: c.errors.Errors(),
+		PortMapping: PortMappingReport{
+			Mappings: c.portMapper.Mappings(),
+			Stats:    c.portMapper.Stats(),
+		},
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if natType, err := c.natProber.Probe(ctx); err != nil {
+			report.NAT.Error = err.Error()
+		} else {
+			report.NAT.Type = string(natType)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		report.Discovery = c.probeHTTP(ctx, c.discoveryAddress)
+	}()
+
+	var servers []string
+	if c.broker != nil {
+		servers = c.broker.Servers()
+	}
+	report.Broker = make([]Probe, len(servers))
+	for i, server := range servers {
+		wg.Add(1)
+		go func(i int, server string) {
+			defer wg.Done()
+			report.Broker[i] = probeTCP(ctx, server)
+		}(i, server)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// Bundle writes a ZIP archive with the diagnostics report and sanitized recent logs.
+func (c *Collector) Bundle(ctx context.Context, w io.Writer) error {
+	report := c.Report(ctx)
+
+	logs, err := c.recentLogs()
+	if err != nil {
+		report.LogError = err.Error()
+	}
+
+	archive := zip.NewWriter(w)
+
+	reportFile, err := archive.Create("report.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(reportFile)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	if logs != nil {
+		logFile, err := archive.Create("node.log")
+		if err != nil {
+			return err
+		}
+		if _, err := logFile.Write(Sanitize(logs)); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func (c *Collector) recentLogs() ([]byte, error) {
+	if c.logFilepath == "" {
+		return nil, errors.New("file logging is disabled")
+	}
+
+	file, err := os.Open(c.logFilepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := info.Size() - logTailSize
+	if offset < 0 {
+		offset = 0
+	}
+	logs := make([]byte, info.Size()-offset)
+	if _, err := file.ReadAt(logs, offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	// Drop the line cut in the middle.
+	if offset > 0 {
+		if i := bytes.IndexByte(logs, '\n'); i >= 0 {
+			logs = logs[i+1:]
+		}
+	}
+	return logs, nil
+}
+
+func (c *Collector) probeHTTP(ctx context.Context, address string) Probe {
+	probe := Probe{Target: address}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+
+	started := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	resp.Body.Close()
+
+	// Any HTTP response means the service is reachable.
+	probe.Reachable = true
+	probe.LatencyMs = time.Since(started).Milliseconds()
+	return probe
+}
+
+func probeTCP(ctx context.Context, server string) Probe {
+	probe := Probe{Target: server}
+
+	address := server
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		address = u.Host
+		if u.Port() == "" {
+			address = net.JoinHostPort(u.Hostname(), defaultBrokerPort)
+		}
+	}
+
+	started := time.Now()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	conn.Close()
+
+	probe.Reachable = true
+	probe.LatencyMs = time.Since(started).Milliseconds()
+	return probe
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package diagnostics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// DefaultErrorHistory is the number of connection errors kept by the recorder.
+const DefaultErrorHistory = 20
+
+// ConnectionError describes a failed connection attempt.
+type ConnectionError struct {
+	Time        time.Time `json:"time"`
+	Stage       string    `json:"stage"`
+	ProviderID  string    `json:"provider_id,omitempty"`
+	ServiceType string    `json:"service_type,omitempty"`
+	Error       string    `json:"error"`
+}
+
+// ErrorRecorder keeps the most recent connection errors.
+type ErrorRecorder struct {
+	size int
+	now  func() time.Time
+
+	mu     sync.Mutex
+	errors []ConnectionError
+}
+
+// NewErrorRecorder returns a recorder keeping up to size connection errors.
+func NewErrorRecorder(size int) *ErrorRecorder {
+	return &ErrorRecorder{
+		size: size,
+		now:  time.Now,
+	}
+}
+
+// Subscribe subscribes to connection events.
+func (r *ErrorRecorder) Subscribe(bus eventbus.Subscriber) error {
+	return bus.SubscribeAsync(quality.AppTopicConnectionEvents, r.consumeConnectionEvent)
+}
+
+func (r *ErrorRecorder) consumeConnectionEvent(e quality.ConnectionEvent) {
+	if e.Error == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errors = append(r.errors, ConnectionError{
+		Time:        r.now().UTC(),
+		Stage:       e.Stage,
+		ProviderID:  e.ProviderID,
+		ServiceType: e.ServiceType,
+		Error:       e.Error,
+	})
+	if len(r.errors) > r.size {
+		r.errors = r.errors[len(r.errors)-r.size:]
+	}
+}
+
+// Errors returns recorded connection errors, oldest first.
+func (r *ErrorRecorder) Errors() []ConnectionError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]ConnectionError(nil), r.errors...)
+}
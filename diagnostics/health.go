@@ -0,0 +1,249 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	healthCheckTimeout = 5 * time.Second
+	healthBucket       = "health"
+)
+
+// Check verifies a single node dependency, returning an error if it is unhealthy.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// CheckResult is the status of a single dependency.
+type CheckResult struct {
+	Name      string
+	Healthy   bool
+	LatencyMs int64
+	Error     string
+}
+
+// HealthReport is the status of all checked dependencies.
+type HealthReport struct {
+	Healthy bool
+	Checks  []CheckResult
+}
+
+// HealthChecker runs dependency checks for liveness and readiness probes.
+// Liveness checks cover local dependencies the node cannot recover without a restart,
+// readiness checks add remote services required to serve traffic.
+type HealthChecker struct {
+	mu        sync.RWMutex
+	liveness  []namedCheck
+	readiness []namedCheck
+}
+
+// NewHealthChecker creates a health checker without any checks.
+func NewHealthChecker() *HealthChecker {
+	return &HealthChecker{}
+}
+
+// AddLivenessCheck adds a check run for both liveness and readiness probes.
+func (h *HealthChecker) AddLivenessCheck(name string, check Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.liveness = append(h.liveness, namedCheck{name: name, check: check})
+}
+
+// AddReadinessCheck adds a check run for readiness probes only.
+func (h *HealthChecker) AddReadinessCheck(name string, check Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readiness = append(h.readiness, namedCheck{name: name, check: check})
+}
+
+// Live runs liveness checks.
+func (h *HealthChecker) Live(ctx context.Context) HealthReport {
+	h.mu.RLock()
+	checks := append([]namedCheck(nil), h.liveness...)
+	h.mu.RUnlock()
+
+	return runChecks(ctx, checks)
+}
+
+// Ready runs liveness and readiness checks.
+func (h *HealthChecker) Ready(ctx context.Context) HealthReport {
+	h.mu.RLock()
+	checks := append(append([]namedCheck(nil), h.liveness...), h.readiness...)
+	h.mu.RUnlock()
+
+	return runChecks(ctx, checks)
+}
+
+func runChecks(ctx context.Context, checks []namedCheck) HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	report := HealthReport{Healthy: true, Checks: make([]CheckResult, len(checks))}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c namedCheck) {
+			defer wg.Done()
+			report.Checks[i] = runCheck(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if !result.Healthy {
+			report.Healthy = false
+		}
+	}
+	return report
+}
+
+// runCheck returns once the check completes or the context expires, whichever is first.
+func runCheck(ctx context.Context, c namedCheck) CheckResult {
+	result := CheckResult{Name: c.name}
+
+	started := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result.LatencyMs = time.Since(started).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Healthy = true
+	}
+	return result
+}
+
+// KeystoreCheck verifies that the keystore directory can be read.
+func KeystoreCheck(directory string) Check {
+	return func(context.Context) error {
+		_, err := os.ReadDir(directory)
+		return err
+	}
+}
+
+type valueStorage interface {
+	GetValue(bucket string, key interface{}, to interface{}) error
+	SetValue(bucket string, key interface{}, to interface{}) error
+}
+
+// StorageCheck verifies that the storage accepts writes by writing and reading back a probe value.
+func StorageCheck(storage valueStorage) Check {
+	return func(context.Context) error {
+		written := time.Now().UnixNano()
+		if err := storage.SetValue(healthBucket, "probe", written); err != nil {
+			return err
+		}
+
+		var read int64
+		if err := storage.GetValue(healthBucket, "probe", &read); err != nil {
+			return err
+		}
+		if read != written {
+			return errors.New("read value differs from the written one")
+		}
+		return nil
+	}
+}
+
+// BrokerCheck verifies the broker connection. Connections which do not report their state
+// are considered healthy if any of the broker servers accepts TCP connections.
+func BrokerCheck(broker brokerConnection) Check {
+	return func(ctx context.Context) error {
+		if broker == nil {
+			return errors.New("broker connection is not established")
+		}
+		if conn, ok := broker.(interface{ IsConnected() bool }); ok {
+			if !conn.IsConnected() {
+				return errors.New("disconnected from broker")
+			}
+			return nil
+		}
+
+		for _, server := range broker.Servers() {
+			if probeTCP(ctx, server).Reachable {
+				return nil
+			}
+		}
+		return fmt.Errorf("none of broker servers %v is reachable", broker.Servers())
+	}
+}
+
+// DiscoveryCheck verifies that the discovery API responds without a server error.
+func DiscoveryCheck(client httpClient, address string) Check {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("discovery API responded with %s", resp.Status)
+		}
+		return nil
+	}
+}
+
+type registryNonceGetter interface {
+	GetLastRegistryNonce(chainID int64, registry common.Address) (*big.Int, error)
+}
+
+type registryAddressProvider interface {
+	GetRegistryAddress(chainID int64) (common.Address, error)
+}
+
+// BlockchainCheck verifies blockchain RPC by reading the registry contract on the given chain.
+func BlockchainCheck(bc registryNonceGetter, addresses registryAddressProvider, chainID int64) Check {
+	return func(context.Context) error {
+		registry, err := addresses.GetRegistryAddress(chainID)
+		if err != nil {
+			return err
+		}
+		_, err = bc.GetLastRegistryNonce(chainID, registry)
+		return err
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthChecker(t *testing.T) {
+	checker := NewHealthChecker()
+	checker.AddLivenessCheck("storage", func(context.Context) error { return nil })
+	checker.AddReadinessCheck("broker", func(context.Context) error { return errors.New("disconnected from broker") })
+	checker.AddReadinessCheck("discovery", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	live := checker.Live(context.Background())
+	assert.True(t, live.Healthy)
+	require.Len(t, live.Checks, 1)
+	assert.Equal(t, "storage", live.Checks[0].Name)
+	assert.True(t, live.Checks[0].Healthy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	ready := checker.Ready(ctx)
+	assert.False(t, ready.Healthy)
+	require.Len(t, ready.Checks, 3)
+	assert.Equal(t, "storage", ready.Checks[0].Name)
+	assert.Equal(t, "broker", ready.Checks[1].Name)
+	assert.Equal(t, "disconnected from broker", ready.Checks[1].Error)
+	assert.Equal(t, "discovery", ready.Checks[2].Name)
+	assert.Equal(t, context.DeadlineExceeded.Error(), ready.Checks[2].Error)
+}
+
+type mockValueStorage map[string]interface{}
+
+func (m mockValueStorage) GetValue(bucket string, key interface{}, to interface{}) error {
+	*to.(*int64) = m[bucket+key.(string)].(int64)
+	return nil
+}
+
+func (m mockValueStorage) SetValue(bucket string, key interface{}, value interface{}) error {
+	m[bucket+key.(string)] = value
+	return nil
+}
+
+func TestStorageCheck(t *testing.T) {
+	assert.NoError(t, StorageCheck(mockValueStorage{})(context.Background()))
+}
+
+func TestKeystoreCheck(t *testing.T) {
+	assert.NoError(t, KeystoreCheck(t.TempDir())(context.Background()))
+	assert.Error(t, KeystoreCheck(t.TempDir()+"/missing")(context.Background()))
+}
+
+type mockBrokerState struct {
+	servers   []string
+	connected bool
+}
+
+func (m *mockBrokerState) Servers() []string {
+	return m.servers
+}
+
+func (m *mockBrokerState) IsConnected() bool {
+	return m.connected
+}
+
+func TestBrokerCheck(t *testing.T) {
+	assert.NoError(t, BrokerCheck(&mockBrokerState{connected: true})(context.Background()))
+	assert.Error(t, BrokerCheck(&mockBrokerState{connected: false})(context.Background()))
+	assert.Error(t, BrokerCheck(nil)(context.Background()))
+}
+
+func TestDiscoveryCheck(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, DiscoveryCheck(http.DefaultClient, server.URL)(context.Background()))
+
+	status = http.StatusBadGateway
+	assert.Error(t, DiscoveryCheck(http.DefaultClient, server.URL)(context.Background()))
+}
+
+type mockRegistry struct {
+	err error
+}
+
+func (m *mockRegistry) GetLastRegistryNonce(chainID int64, registry common.Address) (*big.Int, error) {
+	return big.NewInt(1), m.err
+}
+
+func (m *mockRegistry) GetRegistryAddress(chainID int64) (common.Address, error) {
+	return common.HexToAddress("0x1"), nil
+}
+
+func TestBlockchainCheck(t *testing.T) {
+	assert.NoError(t, BlockchainCheck(&mockRegistry{}, &mockRegistry{}, 137)(context.Background()))
+	assert.Error(t, BlockchainCheck(&mockRegistry{err: errors.New("rpc down")}, &mockRegistry{}, 137)(context.Background()))
+}
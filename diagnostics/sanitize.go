@@ -0,0 +1,52 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package diagnostics
+
+import (
+	"regexp"
+)
+
+var sanitizers = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{
+		pattern:     regexp.MustCompile(`(?i)("?(?:password|passphrase|token|api[_-]?key|secret)"?\s*[:=]\s*)("[^"]*"|[^\s,}]+)`),
+		replacement: `${1}"[REDACTED]"`,
+	},
+	{
+		pattern:     regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+		replacement: `${1}[REDACTED]`,
+	},
+	{
+		pattern:     regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+		replacement: `[EMAIL]`,
+	},
+	{
+		pattern:     regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`),
+		replacement: `[IP]`,
+	},
+}
+
+// Sanitize removes credentials, e-mail and IP addresses from log output.
+func Sanitize(text []byte) []byte {
+	for _, s := range sanitizers {
+		text = s.pattern.ReplaceAll(text, []byte(s.replacement))
+	}
+	return text
+}
@@ -0,0 +1,307 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dns
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultBlocklistUpdateInterval is how often blocklist sources are downloaded again.
+	DefaultBlocklistUpdateInterval = 24 * time.Hour
+
+	blocklistMaxSize   = 64 << 20
+	blocklistTopDomain = 10
+)
+
+// BlockedDomain is a count of blocked queries of a single domain.
+type BlockedDomain struct {
+	Domain string
+	Count  uint64
+}
+
+// BlocklistStats describes loaded blocklist and queries filtered by it.
+type BlocklistStats struct {
+	Sources    []string
+	Domains    int
+	UpdatedAt  time.Time
+	Queries    uint64
+	Blocked    uint64
+	TopBlocked []BlockedDomain
+}
+
+// Blocklist is a set of domains loaded from hosts-format or RPZ lists.
+// A listed domain blocks all of its subdomains too.
+type Blocklist struct {
+	sources []string
+	client  *http.Client
+
+	mu        sync.RWMutex
+	domains   map[string]struct{}
+	updatedAt time.Time
+	queries   uint64
+	blocked   uint64
+	hits      map[string]uint64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewBlocklist creates blocklist loaded from the given sources, which are either HTTP(S) URLs or local file paths.
+func NewBlocklist(sources []string, client *http.Client) *Blocklist {
+	return &Blocklist{
+		sources: sources,
+		client:  client,
+		domains: make(map[string]struct{}),
+		hits:    make(map[string]uint64),
+		stop:    make(chan struct{}),
+	}
+}
+
+// NewDomainBlocklist creates blocklist of the given domains without any sources to load.
+func NewDomainBlocklist(domains []string) *Blocklist {
+	b := NewBlocklist(nil, nil)
+	for _, name := range domains {
+		addBlockedDomain(b.domains, name)
+	}
+	b.updatedAt = time.Now()
+	return b
+}
+
+// Start loads blocklist sources and keeps reloading them with the given interval until stopped.
+func (b *Blocklist) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultBlocklistUpdateInterval
+	}
+
+	go func() {
+		for {
+			if err := b.Update(); err != nil {
+				log.Warn().Err(err).Msg("Failed to update DNS blocklist")
+			}
+
+			select {
+			case <-b.stop:
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+// Stop stops periodic blocklist updates.
+func (b *Blocklist) Stop() {
+	b.stopOnce.Do(func() {
+		close(b.stop)
+	})
+}
+
+// Update downloads all sources and replaces the blocked domain set.
+// Sources failing to load are skipped, the previous set is kept only if all of them fail.
+func (b *Blocklist) Update() error {
+	if len(b.sources) == 0 {
+		return nil
+	}
+
+	domains := make(map[string]struct{})
+	var loaded int
+	for _, source := range b.sources {
+		if err := b.load(source, domains); err != nil {
+			log.Warn().Err(err).Msgf("Failed to load DNS blocklist %s", source)
+			continue
+		}
+		loaded++
+	}
+	if loaded == 0 {
+		return errors.New("none of DNS blocklist sources could be loaded")
+	}
+
+	b.mu.Lock()
+	b.domains = domains
+	b.updatedAt = time.Now()
+	b.mu.Unlock()
+
+	log.Info().Msgf("DNS blocklist updated with %d domains from %d sources", len(domains), loaded)
+	return nil
+}
+
+func (b *Blocklist) load(source string, domains map[string]struct{}) error {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		file, err := os.Open(source)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		return ParseBlocklist(file, domains)
+	}
+
+	resp, err := b.client.Get(source)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return ParseBlocklist(io.LimitReader(resp.Body, blocklistMaxSize), domains)
+}
+
+// Blocked checks whether domain or any of its parent domains is blocklisted.
+func (b *Blocklist) Blocked(name string) bool {
+	_, ok := b.match(name)
+	return ok
+}
+
+func (b *Blocklist) match(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for name != "" {
+		if _, ok := b.domains[name]; ok {
+			return name, true
+		}
+		i := strings.IndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[i+1:]
+	}
+	return "", false
+}
+
+// filter checks the queried name and updates query counters.
+func (b *Blocklist) filter(name string) bool {
+	domain, blocked := b.match(name)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.queries++
+	if blocked {
+		b.blocked++
+		b.hits[domain]++
+	}
+	return blocked
+}
+
+// Stats returns blocklist size and counters of filtered queries.
+func (b *Blocklist) Stats() BlocklistStats {
+	if b == nil {
+		return BlocklistStats{}
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	top := make([]BlockedDomain, 0, len(b.hits))
+	for domain, count := range b.hits {
+		top = append(top, BlockedDomain{Domain: domain, Count: count})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count == top[j].Count {
+			return top[i].Domain < top[j].Domain
+		}
+		return top[i].Count > top[j].Count
+	})
+	if len(top) > blocklistTopDomain {
+		top = top[:blocklistTopDomain]
+	}
+
+	return BlocklistStats{
+		Sources:    b.sources,
+		Domains:    len(b.domains),
+		UpdatedAt:  b.updatedAt,
+		Queries:    b.queries,
+		Blocked:    b.blocked,
+		TopBlocked: top,
+	}
+}
+
+// ParseBlocklist reads blocked domains into the given set. Supported formats are
+// hosts files ("0.0.0.0 example.com"), RPZ zones ("example.com CNAME .") and plain domain lists.
+func ParseBlocklist(r io.Reader, domains map[string]struct{}) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexAny(line, "#;"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "$") {
+			continue
+		}
+
+		switch {
+		case net.ParseIP(fields[0]) != nil:
+			for _, name := range fields[1:] {
+				addBlockedDomain(domains, name)
+			}
+		case len(fields) == 1:
+			addBlockedDomain(domains, fields[0])
+		default:
+			if rpzBlocks(fields) {
+				addBlockedDomain(domains, strings.TrimPrefix(fields[0], "*."))
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+// rpzBlocks checks whether RPZ record rewrites the name to NXDOMAIN or NODATA.
+func rpzBlocks(fields []string) bool {
+	for i := 1; i < len(fields)-1; i++ {
+		if strings.EqualFold(fields[i], "CNAME") {
+			target := fields[i+1]
+			return target == "." || target == "*."
+		}
+	}
+	return false
+}
+
+func addBlockedDomain(domains map[string]struct{}, name string) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	switch name {
+	case "localhost", "localhost.localdomain", "local", "broadcasthost", "ip6-localhost", "ip6-loopback":
+		return
+	}
+	if !strings.Contains(name, ".") {
+		return
+	}
+	if _, ok := dns.IsDomainName(name); !ok {
+		return
+	}
+	domains[name] = struct{}{}
+}
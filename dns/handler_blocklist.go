@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dns
+
+import (
+	"github.com/miekg/dns"
+)
+
+// BlockDomains creates a DNS handler which answers NXDOMAIN to queries of blocklisted domains
+// and passes all other queries to the resolver.
+func BlockDomains(resolver dns.Handler, list *Blocklist) dns.Handler {
+	return &blocklistHandler{
+		resolver: resolver,
+		list:     list,
+	}
+}
+
+type blocklistHandler struct {
+	resolver dns.Handler
+	list     *Blocklist
+}
+
+func (bh *blocklistHandler) ServeDNS(writer dns.ResponseWriter, req *dns.Msg) {
+	if len(req.Question) > 0 && bh.list.filter(req.Question[0].Name) {
+		resp := &dns.Msg{}
+		resp.SetRcode(req, dns.RcodeNameError)
+		writer.WriteMsg(resp)
+		return
+	}
+
+	bh.resolver.ServeDNS(writer, req)
+}
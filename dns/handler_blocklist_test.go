@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dns
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBlocklist = `
+# hosts format
+0.0.0.0 ads.example.com
+127.0.0.1 localhost
+127.0.0.1 tracker.example.org metrics.example.org # trailing comment
+
+; RPZ format
+$TTL 300
+@ SOA localhost. root.localhost. 1 3600 600 86400 300
+malware.test CNAME .
+*.phishing.test 300 IN CNAME *.
+allowed.test CNAME allowed.example.net.
+
+plain-domain.net
+`
+
+func Test_ParseBlocklist(t *testing.T) {
+	domains := make(map[string]struct{})
+	err := ParseBlocklist(strings.NewReader(testBlocklist), domains)
+	assert.NoError(t, err)
+
+	var names []string
+	for name := range domains {
+		names = append(names, name)
+	}
+	assert.Len(t, names, 6)
+	for _, name := range []string{"ads.example.com", "tracker.example.org", "metrics.example.org", "malware.test", "phishing.test", "plain-domain.net"} {
+		assert.Contains(t, names, name)
+	}
+}
+
+func Test_BlockDomains(t *testing.T) {
+	list := NewBlocklist(nil, nil)
+	assert.NoError(t, ParseBlocklist(strings.NewReader(testBlocklist), list.domains))
+
+	handler := BlockDomains(dns.HandlerFunc(func(writer dns.ResponseWriter, req *dns.Msg) {
+		resp := &dns.Msg{}
+		resp.SetReply(req)
+		writer.WriteMsg(resp)
+	}), list)
+
+	tests := []struct {
+		name  string
+		rcode int
+	}{
+		{"ads.example.com.", dns.RcodeNameError},
+		{"sub.ADS.example.com.", dns.RcodeNameError},
+		{"example.com.", dns.RcodeSuccess},
+		{"deep.sub.phishing.test.", dns.RcodeNameError},
+		{"allowed.test.", dns.RcodeSuccess},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &dns.Msg{}
+			req.SetQuestion(tt.name, dns.TypeA)
+
+			writer := &recordingWriter{}
+			handler.ServeDNS(writer, req)
+			assert.Equal(t, tt.rcode, writer.responseMsg.Rcode)
+		})
+	}
+
+	stats := list.Stats()
+	assert.Equal(t, uint64(5), stats.Queries)
+	assert.Equal(t, uint64(3), stats.Blocked)
+	assert.Equal(t, []BlockedDomain{{Domain: "ads.example.com", Count: 2}, {Domain: "phishing.test", Count: 1}}, stats.TopBlocked)
+}
+
+func Test_NewDomainBlocklist(t *testing.T) {
+	list := NewDomainBlocklist([]string{"Example.com.", "localhost"})
+
+	assert.True(t, list.Blocked("mail.example.com."))
+	assert.False(t, list.Blocked("localhost"))
+	assert.Equal(t, 1, list.Stats().Domains)
+}
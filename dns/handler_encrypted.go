@@ -0,0 +1,237 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package dns
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// SchemeDoH is an URL scheme of DNS-over-HTTPS upstreams.
+	SchemeDoH = "https"
+	// SchemeDoT is an URL scheme of DNS-over-TLS upstreams.
+	SchemeDoT = "tls"
+
+	dohContentType = "application/dns-message"
+	dohDefaultPort = "443"
+	dotDefaultPort = "853"
+	dohMaxRespSize = 65535
+)
+
+// ResolveViaEncrypted creates DNS handler which forwards queries to DNS-over-HTTPS or DNS-over-TLS upstreams,
+// e.g. "https://cloudflare-dns.com/dns-query" or "tls://1.1.1.1".
+// Upstream hosts are resolved on creation, so the handler keeps working once it becomes the system resolver.
+func ResolveViaEncrypted(upstreams []string) (dns.Handler, error) {
+	handler := &encryptedHandler{}
+	for _, upstream := range upstreams {
+		u, err := newEncryptedUpstream(upstream)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to configure encrypted DNS upstream "+upstream)
+		}
+		handler.upstreams = append(handler.upstreams, u)
+	}
+	if len(handler.upstreams) == 0 {
+		return nil, errors.New("no encrypted DNS upstreams given")
+	}
+
+	return handler, nil
+}
+
+type encryptedUpstream interface {
+	exchange(req *dns.Msg) (*dns.Msg, error)
+	String() string
+}
+
+type encryptedHandler struct {
+	upstreams []encryptedUpstream
+}
+
+func (eh *encryptedHandler) ServeDNS(writer dns.ResponseWriter, req *dns.Msg) {
+	for _, upstream := range eh.upstreams {
+		resp, err := upstream.exchange(req)
+		if err != nil {
+			log.Error().Err(err).Msg("Error proxying DNS query to " + upstream.String())
+			continue
+		}
+
+		writer.WriteMsg(resp)
+		return
+	}
+
+	resp := &dns.Msg{}
+	resp.SetRcode(req, dns.RcodeServerFailure)
+	writer.WriteMsg(resp)
+}
+
+func newEncryptedUpstream(upstream string) (encryptedUpstream, error) {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return nil, err
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return nil, errors.New("upstream host is missing")
+	}
+	ip, err := lookupUpstreamIP(host)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case SchemeDoH:
+		port := u.Port()
+		if port == "" {
+			port = dohDefaultPort
+		}
+		return newDoHUpstream(u.String(), net.JoinHostPort(ip, port)), nil
+	case SchemeDoT:
+		port := u.Port()
+		if port == "" {
+			port = dotDefaultPort
+		}
+		return newDoTUpstream(host, net.JoinHostPort(ip, port)), nil
+	}
+
+	return nil, fmt.Errorf("unsupported upstream scheme: %s", u.Scheme)
+}
+
+func lookupUpstreamIP(host string) (string, error) {
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to resolve upstream host")
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip.String(), nil
+		}
+	}
+	if len(ips) == 0 {
+		return "", errors.New("upstream host has no addresses: " + host)
+	}
+
+	return ips[0].String(), nil
+}
+
+type dohUpstream struct {
+	url    string
+	client *http.Client
+}
+
+func newDoHUpstream(endpoint, addr string) *dohUpstream {
+	dialer := &net.Dialer{Timeout: dnsTimeout}
+	return &dohUpstream{
+		url: endpoint,
+		client: &http.Client{
+			Timeout: dnsTimeout,
+			Transport: &http.Transport{
+				// Always dial pre-resolved address, host name is still used for TLS verification.
+				DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+					return dialer.DialContext(ctx, network, addr)
+				},
+				ForceAttemptHTTP2: true,
+			},
+		},
+	}
+}
+
+func (u *dohUpstream) exchange(req *dns.Msg) (*dns.Msg, error) {
+	// RFC 8484 recommends zero ID for better HTTP caching.
+	query := req.Copy()
+	query.Id = 0
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to pack DNS query")
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, u.url, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", dohContentType)
+	httpReq.Header.Set("Accept", dohContentType)
+
+	httpResp, err := u.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected DoH response status: %s", httpResp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, dohMaxRespSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read DoH response")
+	}
+
+	resp := &dns.Msg{}
+	if err := resp.Unpack(body); err != nil {
+		return nil, errors.Wrap(err, "failed to unpack DoH response")
+	}
+	resp.Id = req.Id
+
+	return resp, nil
+}
+
+func (u *dohUpstream) String() string {
+	return u.url
+}
+
+type dotUpstream struct {
+	addr   string
+	client *dns.Client
+}
+
+func newDoTUpstream(serverName, addr string) *dotUpstream {
+	return &dotUpstream{
+		addr: addr,
+		client: &dns.Client{
+			Net:          "tcp-tls",
+			TLSConfig:    &tls.Config{ServerName: serverName},
+			DialTimeout:  dnsTimeout,
+			ReadTimeout:  dnsTimeout,
+			WriteTimeout: dnsTimeout,
+		},
+	}
+}
+
+func (u *dotUpstream) exchange(req *dns.Msg) (*dns.Msg, error) {
+	resp, _, err := u.client.Exchange(req, u.addr)
+	return resp, err
+}
+
+func (u *dotUpstream) String() string {
+	return SchemeDoT + "://" + u.addr
+}
@@ -41,6 +41,22 @@ func ResolveViaSystem() (dns.Handler, error) {
 	return handler, nil
 }
 
+// ResolveViaServers creates proxying DNS handler which forwards queries to the given DNS server IPs.
+func ResolveViaServers(servers []string) dns.Handler {
+	handler := &proxyHandler{
+		client: &dns.Client{
+			DialTimeout:  dnsTimeout,
+			ReadTimeout:  dnsTimeout,
+			WriteTimeout: dnsTimeout,
+		},
+	}
+	for _, server := range servers {
+		handler.proxyAddrs = append(handler.proxyAddrs, net.JoinHostPort(server, "53"))
+	}
+
+	return handler
+}
+
 type proxyHandler struct {
 	proxyAddrs []string
 	client     *dns.Client
@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/eventbus"
+	natprobe "github.com/mysteriumnetwork/node/nat/behavior"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+// SchemaVersion is a version of exported event envelope, it is bumped on incompatible payload changes.
+const SchemaVersion = 1
+
+// Groups of internal topics which can be exported.
+const (
+	GroupSessions     = "sessions"
+	GroupEarnings     = "earnings"
+	GroupConnectivity = "connectivity"
+)
+
+var groupTopics = map[string][]string{
+	GroupSessions: {
+		sessionEvent.AppTopicSession,
+		sessionEvent.AppTopicDataTransferred,
+		sessionEvent.AppTopicTokensEarned,
+		connectionstate.AppTopicConnectionSession,
+	},
+	GroupEarnings: {
+		pingpongEvent.AppTopicEarningsChanged,
+		pingpongEvent.AppTopicBalanceChanged,
+		pingpongEvent.AppTopicSettlementComplete,
+	},
+	GroupConnectivity: {
+		connectionstate.AppTopicConnectionState,
+		servicestate.AppTopicServiceStatus,
+		natprobe.AppTopicNATTypeDetected,
+	},
+}
+
+// Envelope wraps exported event payload.
+type Envelope struct {
+	SchemaVersion int         `json:"schema_version"`
+	Group         string      `json:"group"`
+	Topic         string      `json:"topic"`
+	Timestamp     time.Time   `json:"timestamp"`
+	Payload       interface{} `json:"payload"`
+}
+
+type publisher interface {
+	Publish(subject string, payload []byte) error
+}
+
+// Exporter republishes selected internal event bus topics to NATS subjects.
+type Exporter struct {
+	conn   publisher
+	prefix string
+	groups []string
+}
+
+// NewExporter returns a new instance of Exporter publishing events of given groups under the subject prefix.
+func NewExporter(conn publisher, prefix string, groups []string) *Exporter {
+	return &Exporter{
+		conn:   conn,
+		prefix: strings.TrimSuffix(prefix, "."),
+		groups: groups,
+	}
+}
+
+// Subscribe subscribes the exporter to topics of configured groups.
+func (e *Exporter) Subscribe(bus eventbus.Subscriber) error {
+	for _, group := range e.groups {
+		topics, ok := groupTopics[group]
+		if !ok {
+			return fmt.Errorf("unknown event group: %s", group)
+		}
+
+		for _, topic := range topics {
+			if err := bus.SubscribeAsync(topic, e.exportFunc(group, topic)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Subject returns NATS subject events of the given internal topic are published to.
+func (e *Exporter) Subject(group, topic string) string {
+	return e.prefix + "." + group + "." + subjectToken(topic)
+}
+
+func (e *Exporter) exportFunc(group, topic string) func(data interface{}) {
+	subject := e.Subject(group, topic)
+	return func(data interface{}) {
+		payload, err := json.Marshal(Envelope{
+			SchemaVersion: SchemaVersion,
+			Group:         group,
+			Topic:         topic,
+			Timestamp:     time.Now().UTC(),
+			Payload:       data,
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not marshal %q event for export", topic)
+			return
+		}
+
+		if err := e.conn.Publish(subject, payload); err != nil {
+			log.Warn().Err(err).Msgf("Could not export event to %s", subject)
+		}
+	}
+}
+
+// subjectToken turns internal topic name to a single NATS subject token, e.g. "Session data transferred" to "session_data_transferred".
+func subjectToken(topic string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '.', '*', '>', '-':
+			return '_'
+		}
+		return r
+	}, strings.ToLower(topic))
+}
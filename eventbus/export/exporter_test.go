@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package export
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+)
+
+type publishedMsg struct {
+	subject string
+	payload []byte
+}
+
+type mockPublisher struct {
+	published chan publishedMsg
+}
+
+func (mp *mockPublisher) Publish(subject string, payload []byte) error {
+	mp.published <- publishedMsg{subject: subject, payload: payload}
+	return nil
+}
+
+func TestExporter_RepublishesGroupTopics(t *testing.T) {
+	bus := eventbus.New()
+	conn := &mockPublisher{published: make(chan publishedMsg, 1)}
+	exporter := NewExporter(conn, "myst.events.", []string{GroupSessions})
+	require.NoError(t, exporter.Subscribe(bus))
+
+	bus.Publish(sessionEvent.AppTopicDataTransferred, sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 1, Down: 2})
+
+	var msg publishedMsg
+	select {
+	case msg = <-conn.published:
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not exported")
+	}
+	assert.Equal(t, "myst.events.sessions.session_data_transferred", msg.subject)
+
+	var envelope struct {
+		SchemaVersion int    `json:"schema_version"`
+		Group         string `json:"group"`
+		Topic         string `json:"topic"`
+		Payload       struct {
+			ID   string
+			Up   uint64
+			Down uint64
+		} `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(msg.payload, &envelope))
+	assert.Equal(t, SchemaVersion, envelope.SchemaVersion)
+	assert.Equal(t, GroupSessions, envelope.Group)
+	assert.Equal(t, sessionEvent.AppTopicDataTransferred, envelope.Topic)
+	assert.Equal(t, "session-1", envelope.Payload.ID)
+	assert.Equal(t, uint64(2), envelope.Payload.Down)
+}
+
+func TestExporter_UnknownGroup(t *testing.T) {
+	exporter := NewExporter(&mockPublisher{}, "myst.events", []string{"unknown"})
+	assert.Error(t, exporter.Subscribe(eventbus.New()))
+}
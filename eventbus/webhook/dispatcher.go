@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/monitoring/selfcheck"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+// Events which can be delivered to webhooks.
+const (
+	EventSessionStarted  = "session_started"
+	EventSessionEnded    = "session_ended"
+	EventSettlementDone  = "settlement_done"
+	EventBalanceLow      = "balance_low"
+	EventServiceStopped  = "service_stopped"
+	EventSelfCheckFailed = "self_check_failed"
+)
+
+// Events lists all events which can be delivered to webhooks.
+var Events = []string{EventSessionStarted, EventSessionEnded, EventSettlementDone, EventBalanceLow, EventServiceStopped, EventSelfCheckFailed}
+
+// Headers of delivery requests.
+const (
+	HeaderEvent     = "X-Myst-Event"
+	HeaderDelivery  = "X-Myst-Delivery"
+	HeaderTimestamp = "X-Myst-Timestamp"
+	// HeaderSignature holds "sha256=" followed by hex encoded HMAC-SHA256 of "<timestamp>.<body>" keyed by the webhook secret.
+	HeaderSignature = "X-Myst-Signature"
+)
+
+const (
+	deliveryAttempts = 5
+	deliveryTimeout  = 10 * time.Second
+	retryDelay       = 2 * time.Second
+)
+
+// Delivery is the JSON body posted to webhooks.
+type Delivery struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Payload   interface{} `json:"payload"`
+}
+
+type webhookLister interface {
+	List() ([]Webhook, error)
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Dispatcher delivers selected event bus events to configured webhooks as signed JSON POST requests.
+type Dispatcher struct {
+	webhooks         webhookLister
+	client           httpClient
+	balanceThreshold *big.Int
+	retryDelay       time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewDispatcher returns a new webhook dispatcher. Balance low event is delivered when the balance
+// drops below the given threshold.
+func NewDispatcher(webhooks webhookLister, client httpClient, balanceThreshold *big.Int) *Dispatcher {
+	return &Dispatcher{
+		webhooks:         webhooks,
+		client:           client,
+		balanceThreshold: balanceThreshold,
+		retryDelay:       retryDelay,
+		stop:             make(chan struct{}),
+	}
+}
+
+// Subscribe subscribes the dispatcher to events delivered to webhooks.
+func (d *Dispatcher) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(sessionEvent.AppTopicSession, d.consumeSession); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(pingpongEvent.AppTopicSettlementComplete, d.consumeSettlement); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(pingpongEvent.AppTopicBalanceChanged, d.consumeBalance); err != nil {
+		return err
+	}
+	if err := bus.SubscribeAsync(servicestate.AppTopicServiceStatus, d.consumeServiceStatus); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(selfcheck.AppTopicSelfCheckFailed, d.consumeSelfCheckFailed)
+}
+
+// Stop aborts pending retries and waits for ongoing deliveries to finish.
+func (d *Dispatcher) Stop() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+	})
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) consumeSession(e sessionEvent.AppEventSession) {
+	switch e.Status {
+	case sessionEvent.CreatedStatus:
+		d.dispatch(EventSessionStarted, e)
+	case sessionEvent.RemovedStatus:
+		d.dispatch(EventSessionEnded, e)
+	}
+}
+
+func (d *Dispatcher) consumeSettlement(e pingpongEvent.AppEventSettlementComplete) {
+	d.dispatch(EventSettlementDone, e)
+}
+
+// consumeBalance dispatches the event only when the balance crosses the threshold, not on every change below it.
+func (d *Dispatcher) consumeBalance(e pingpongEvent.AppEventBalanceChanged) {
+	if d.balanceThreshold == nil || e.Current == nil || e.Current.Cmp(d.balanceThreshold) >= 0 {
+		return
+	}
+	if e.Previous != nil && e.Previous.Cmp(d.balanceThreshold) < 0 {
+		return
+	}
+	d.dispatch(EventBalanceLow, e)
+}
+
+func (d *Dispatcher) consumeServiceStatus(e servicestate.AppEventServiceStatus) {
+	if e.Status == string(servicestate.NotRunning) {
+		d.dispatch(EventServiceStopped, e)
+	}
+}
+
+func (d *Dispatcher) consumeSelfCheckFailed(e selfcheck.AppEventSelfCheckFailed) {
+	d.dispatch(EventSelfCheckFailed, e)
+}
+
+func (d *Dispatcher) dispatch(event string, payload interface{}) {
+	webhooks, err := d.webhooks.List()
+	if err != nil {
+		log.Error().Err(err).Msg("Could not list webhooks")
+		return
+	}
+
+	for _, w := range webhooks {
+		if !w.Subscribed(event) {
+			continue
+		}
+
+		id, err := randomHex(8)
+		if err != nil {
+			log.Error().Err(err).Msg("Could not generate webhook delivery ID")
+			return
+		}
+		body, err := json.Marshal(Delivery{
+			ID:        id,
+			Event:     event,
+			Timestamp: time.Now().UTC(),
+			Payload:   payload,
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("Could not marshal %q webhook payload", event)
+			return
+		}
+
+		d.wg.Add(1)
+		go func(w Webhook, id string) {
+			defer d.wg.Done()
+			d.deliver(w, event, id, body)
+		}(w, id)
+	}
+}
+
+// deliver posts the body to the webhook, retrying with exponential backoff on network errors,
+// server errors and rate limiting.
+func (d *Dispatcher) deliver(w Webhook, event, id string, body []byte) {
+	delay := d.retryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := d.post(w, event, id, body)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == deliveryAttempts {
+			log.Warn().Err(err).Msgf("Could not deliver %q event to webhook %s", event, w.ID)
+			return
+		}
+
+		log.Debug().Err(err).Msgf("Webhook %s delivery %s failed, retrying in %s", w.ID, id, delay)
+		select {
+		case <-d.stop:
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (d *Dispatcher) post(w Webhook, event, id string, body []byte) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, id)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(w.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook responded with %s", resp.Status)
+}
+
+// Sign returns the signature header value of the delivery body sent at the given unix timestamp.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/service/servicestate"
+	"github.com/mysteriumnetwork/node/identity"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+type staticWebhooks []Webhook
+
+func (s staticWebhooks) List() ([]Webhook, error) {
+	return s, nil
+}
+
+type receivedDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+type webhookReceiver struct {
+	mu         sync.Mutex
+	deliveries []receivedDelivery
+	failures   int
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, receivedDelivery{header: req.Header, body: body})
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusBadGateway)
+	}
+}
+
+func (r *webhookReceiver) received() []receivedDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedDelivery(nil), r.deliveries...)
+}
+
+func TestDispatcher_DeliversSignedEvents(t *testing.T) {
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	d := NewDispatcher(staticWebhooks{
+		{ID: "1", URL: server.URL, Events: []string{EventServiceStopped}, Secret: "secret"},
+		{ID: "2", URL: server.URL, Events: []string{EventSessionStarted}, Secret: "other"},
+	}, http.DefaultClient, nil)
+
+	d.consumeServiceStatus(servicestate.AppEventServiceStatus{ID: "service", Status: string(servicestate.Running)})
+	d.consumeServiceStatus(servicestate.AppEventServiceStatus{ID: "service", Status: string(servicestate.NotRunning)})
+	d.Stop()
+
+	deliveries := receiver.received()
+	require.Len(t, deliveries, 1)
+	header, body := deliveries[0].header, deliveries[0].body
+
+	assert.Equal(t, EventServiceStopped, header.Get(HeaderEvent))
+	assert.Equal(t, Sign("secret", header.Get(HeaderTimestamp), body), header.Get(HeaderSignature))
+
+	var delivery struct {
+		ID      string                             `json:"id"`
+		Event   string                             `json:"event"`
+		Payload servicestate.AppEventServiceStatus `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(body, &delivery))
+	assert.Equal(t, header.Get(HeaderDelivery), delivery.ID)
+	assert.Equal(t, EventServiceStopped, delivery.Event)
+	assert.Equal(t, "service", delivery.Payload.ID)
+}
+
+func TestDispatcher_RetriesFailedDeliveries(t *testing.T) {
+	receiver := &webhookReceiver{failures: 2}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	d := NewDispatcher(staticWebhooks{
+		{ID: "1", URL: server.URL, Events: []string{EventSessionStarted, EventSessionEnded}, Secret: "secret"},
+	}, http.DefaultClient, nil)
+	d.retryDelay = time.Millisecond
+
+	d.consumeSession(sessionEvent.AppEventSession{Status: sessionEvent.CreatedStatus})
+	assert.Eventually(t, func() bool {
+		return len(receiver.received()) == 3
+	}, time.Second, 10*time.Millisecond)
+	d.Stop()
+
+	deliveries := receiver.received()
+	require.Len(t, deliveries, 3)
+	assert.Equal(t, deliveries[0].header.Get(HeaderDelivery), deliveries[2].header.Get(HeaderDelivery))
+	assert.Equal(t, EventSessionStarted, deliveries[2].header.Get(HeaderEvent))
+}
+
+func TestDispatcher_BalanceLowOnCrossingThreshold(t *testing.T) {
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	d := NewDispatcher(staticWebhooks{
+		{ID: "1", URL: server.URL, Events: []string{EventBalanceLow}, Secret: "secret"},
+	}, http.DefaultClient, big.NewInt(100))
+
+	id := identity.FromAddress("0x1")
+	d.consumeBalance(pingpongEvent.AppEventBalanceChanged{Identity: id, Previous: big.NewInt(300), Current: big.NewInt(200)})
+	d.consumeBalance(pingpongEvent.AppEventBalanceChanged{Identity: id, Previous: big.NewInt(200), Current: big.NewInt(90)})
+	d.consumeBalance(pingpongEvent.AppEventBalanceChanged{Identity: id, Previous: big.NewInt(90), Current: big.NewInt(80)})
+	d.Stop()
+
+	assert.Len(t, receiver.received(), 1)
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const (
+	bucketName  = "webhooks"
+	webhooksKey = "webhooks"
+)
+
+// ErrNotFound is returned when there's no webhook with the requested ID.
+var ErrNotFound = errors.New("webhook not found")
+
+// Webhook is an HTTP endpoint node events are delivered to.
+type Webhook struct {
+	ID     string
+	URL    string
+	Events []string
+	// Secret signs delivered payloads, so that the receiver can verify they come from the node.
+	Secret string
+}
+
+// Subscribed checks whether the webhook receives the given event.
+func (w Webhook) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type persistentStorage interface {
+	GetValue(bucket string, key interface{}, to interface{}) error
+	SetValue(bucket string, key interface{}, to interface{}) error
+}
+
+// Storage keeps configured webhooks.
+type Storage struct {
+	storage persistentStorage
+	mu      sync.Mutex
+}
+
+// NewStorage returns a new webhook storage.
+func NewStorage(storage persistentStorage) *Storage {
+	return &Storage{storage: storage}
+}
+
+// List returns all webhooks ordered by ID.
+func (s *Storage) List() ([]Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhooks, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Webhook, 0, len(webhooks))
+	for _, w := range webhooks {
+		list = append(list, w)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list, nil
+}
+
+// Add stores a new webhook. ID and secret are generated, unless the secret is given.
+func (s *Storage) Add(w Webhook) (Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhooks, err := s.load()
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	if w.ID, err = randomHex(8); err != nil {
+		return Webhook{}, err
+	}
+	if w.Secret == "" {
+		if w.Secret, err = randomHex(32); err != nil {
+			return Webhook{}, err
+		}
+	}
+
+	webhooks[w.ID] = w
+	return w, s.storage.SetValue(bucketName, webhooksKey, webhooks)
+}
+
+// Delete removes a webhook by ID.
+func (s *Storage) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhooks, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := webhooks[id]; !ok {
+		return ErrNotFound
+	}
+	delete(webhooks, id)
+	return s.storage.SetValue(bucketName, webhooksKey, webhooks)
+}
+
+func (s *Storage) load() (map[string]Webhook, error) {
+	webhooks := make(map[string]Webhook)
+	err := s.storage.GetValue(bucketName, webhooksKey, &webhooks)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func randomHex(size int) (string, error) {
+	random := make([]byte, size)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random), nil
+}
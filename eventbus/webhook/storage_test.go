@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package webhook
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+)
+
+func TestStorage(t *testing.T) {
+	dir, err := os.MkdirTemp("", "webhookStorageTest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bolt, err := boltdb.NewStorage(dir)
+	require.NoError(t, err)
+	defer bolt.Close()
+	storage := NewStorage(bolt)
+
+	list, err := storage.List()
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+
+	generated, err := storage.Add(Webhook{URL: "https://hooks.example.com/a", Events: []string{EventSessionStarted}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, generated.ID)
+	assert.Len(t, generated.Secret, 64)
+
+	given, err := storage.Add(Webhook{URL: "https://hooks.example.com/b", Events: []string{EventBalanceLow}, Secret: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, "secret", given.Secret)
+
+	list, err = storage.List()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []Webhook{generated, given}, list)
+
+	assert.NoError(t, storage.Delete(generated.ID))
+	assert.ErrorIs(t, storage.Delete(generated.ID), ErrNotFound)
+
+	list, err = storage.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []Webhook{given}, list)
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package firewall
+
+import (
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/firewall/iptables"
+	"github.com/mysteriumnetwork/node/firewall/nftables"
+)
+
+// Backend is a packet filtering framework used to program firewall and NAT rules on Linux.
+type Backend string
+
+const (
+	// BackendAuto detects backend available on the system.
+	BackendAuto Backend = "auto"
+	// BackendIptables programs rules via iptables tool.
+	BackendIptables Backend = "iptables"
+	// BackendNftables programs rules via nft tool.
+	BackendNftables Backend = "nftables"
+)
+
+var (
+	detectOnce sync.Once
+	detected   Backend
+)
+
+// DetectBackend returns backend configured for the node. In auto mode iptables is preferred when installed,
+// as it is backed by nftables on modern systems anyway, and nftables is used on systems shipped without it.
+func DetectBackend() Backend {
+	switch backend := Backend(config.GetString(config.FlagFirewallBackend)); backend {
+	case BackendIptables, BackendNftables:
+		return backend
+	case BackendAuto, "":
+	default:
+		log.Warn().Msgf("Unknown firewall backend %q, detecting it automatically", backend)
+	}
+
+	detectOnce.Do(func() {
+		detected = BackendIptables
+		if !iptables.Available() && nftables.Available() {
+			detected = BackendNftables
+		}
+		log.Info().Msgf("Detected firewall backend: %s", detected)
+	})
+	return detected
+}
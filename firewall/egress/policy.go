@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package egress restricts destinations consumer traffic can reach through the provider.
+package egress
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// Policy lists destinations blocked for consumer traffic.
+type Policy struct {
+	Ports    []int
+	Networks []*net.IPNet
+	Domains  []string
+}
+
+// NewPolicy parses the given ports, CIDR networks and domains, skipping invalid entries.
+func NewPolicy(ports, networks, domains []string) Policy {
+	var p Policy
+	for _, s := range ports {
+		port, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || port <= 0 || port > 65535 {
+			log.Error().Msgf("Invalid egress policy port: %q", s)
+			continue
+		}
+		p.Ports = append(p.Ports, port)
+	}
+	for _, s := range networks {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(s))
+		if err != nil {
+			log.Error().Err(err).Msg("Invalid egress policy network")
+			continue
+		}
+		p.Networks = append(p.Networks, network)
+	}
+	for _, s := range domains {
+		domain := strings.ToLower(strings.Trim(strings.TrimSpace(s), "."))
+		if domain == "" {
+			continue
+		}
+		p.Domains = append(p.Domains, domain)
+	}
+	return p
+}
+
+// PolicyFromConfig returns the egress policy configured for the node.
+func PolicyFromConfig() Policy {
+	return NewPolicy(
+		config.GetStringSlice(config.FlagEgressBlockedPorts),
+		config.GetStringSlice(config.FlagEgressBlockedNetworks),
+		config.GetStringSlice(config.FlagEgressBlockedDomains),
+	)
+}
+
+// Empty checks whether policy blocks nothing.
+func (p Policy) Empty() bool {
+	return len(p.Ports) == 0 && len(p.Networks) == 0 && len(p.Domains) == 0
+}
+
+// Blocks checks whether connections to the given destination are blocked.
+// Domains are not resolved here, they are blocked when resolving names via provider DNS.
+func (p Policy) Blocks(ip net.IP, port int) bool {
+	for _, blocked := range p.Ports {
+		if blocked == port {
+			return true
+		}
+	}
+	for _, network := range p.Networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockedNetworks returns blocked networks together with current addresses of blocked domains.
+func (p Policy) BlockedNetworks() []*net.IPNet {
+	networks := append([]*net.IPNet(nil), p.Networks...)
+	for _, domain := range p.Domains {
+		ips, err := net.LookupIP(domain)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Could not resolve egress policy domain %s", domain)
+			continue
+		}
+		for _, ip := range ips {
+			if v4 := ip.To4(); v4 != nil {
+				networks = append(networks, &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)})
+			} else {
+				networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)})
+			}
+		}
+	}
+	return networks
+}
+
+// Proposal returns the policy as advertised in service proposals, nil if nothing is blocked.
+func (p Policy) Proposal() *market.EgressPolicy {
+	if p.Empty() {
+		return nil
+	}
+
+	advertised := &market.EgressPolicy{
+		BlockedPorts:   p.Ports,
+		BlockedDomains: p.Domains,
+	}
+	for _, network := range p.Networks {
+		advertised.BlockedNetworks = append(advertised.BlockedNetworks, network.String())
+	}
+	return advertised
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package egress
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+func TestNewPolicy_SkipsInvalidEntries(t *testing.T) {
+	p := NewPolicy([]string{"25", "smtp", "70000"}, []string{"10.0.0.0/8", "10.0.0.1"}, []string{"Example.COM.", ""})
+
+	assert.Equal(t, []int{25}, p.Ports)
+	assert.Len(t, p.Networks, 1)
+	assert.Equal(t, []string{"example.com"}, p.Domains)
+}
+
+func TestPolicy_Blocks(t *testing.T) {
+	p := NewPolicy([]string{"25"}, []string{"192.168.0.0/16"}, nil)
+
+	assert.True(t, p.Blocks(net.ParseIP("1.1.1.1"), 25))
+	assert.True(t, p.Blocks(net.ParseIP("192.168.1.1"), 443))
+	assert.False(t, p.Blocks(net.ParseIP("1.1.1.1"), 443))
+}
+
+func TestPolicy_Proposal(t *testing.T) {
+	assert.Nil(t, NewPolicy(nil, nil, nil).Proposal())
+
+	p := NewPolicy([]string{"25"}, []string{"192.168.0.0/16"}, []string{"example.com"})
+	assert.Equal(t, &market.EgressPolicy{
+		BlockedPorts:    []int{25},
+		BlockedNetworks: []string{"192.168.0.0/16"},
+		BlockedDomains:  []string{"example.com"},
+	}, p.Proposal())
+}
@@ -1,4 +1,4 @@
-//go:build !linux
+//go:build !linux && !windows
 
 /*
  * Copyright (C) 2020 The "MysteriumNetwork/node" Authors.
@@ -21,21 +21,30 @@ package firewall
 
 // NewOutgoingTrafficFirewall creates firewall instance for outgoing traffic.
 func NewOutgoingTrafficFirewall(enabled bool) OutgoingTrafficFirewall {
-	if enabled {
-		return &outgoingFirewallIptables{
+	if !enabled {
+		return &outgoingFirewallNoop{}
+	}
+
+	if DetectBackend() == BackendNftables {
+		return &outgoingFirewallNftables{
 			referenceTracker: make(map[string]refCount),
 			trafficLockScope: none,
 		}
 	}
-
-	return &outgoingFirewallNoop{}
+	return &outgoingFirewallIptables{
+		referenceTracker: make(map[string]refCount),
+		trafficLockScope: none,
+	}
 }
 
 // NewIncomingTrafficFirewall creates firewall instance for incoming traffic.
 func NewIncomingTrafficFirewall(enabled bool) IncomingTrafficFirewall {
-	if enabled {
-		return &incomingFirewallIptables{}
+	if !enabled {
+		return &incomingFirewallNoop{}
 	}
 
-	return &incomingFirewallNoop{}
+	if DetectBackend() == BackendNftables {
+		return &incomingFirewallNftables{}
+	}
+	return &incomingFirewallIptables{}
 }
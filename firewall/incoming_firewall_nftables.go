@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package firewall
+
+import (
+	"net"
+	"net/url"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/firewall/nftables"
+)
+
+const (
+	incomingFirewallTable        = "myst_provider_firewall"
+	incomingFirewallForwardChain = "forward"
+	incomingFirewallNftChain     = "firewall"
+	incomingFirewallSet          = "dst_whitelist"
+)
+
+// incomingFirewallNftables allows incoming traffic blocking in IP granularity.
+type incomingFirewallNftables struct{}
+
+func (ibn *incomingFirewallNftables) Setup() error {
+	// Clean up setups from previous runs, just in case
+	if err := nftables.DeleteTable("ip", incomingFirewallTable); err != nil {
+		return err
+	}
+	return ibn.setupFirewallTable()
+}
+
+func (ibn *incomingFirewallNftables) Teardown() {
+	if err := nftables.DeleteTable("ip", incomingFirewallTable); err != nil {
+		log.Warn().Err(err).Msg("Error cleaning up nftables rules, you might want to do it yourself")
+	}
+}
+
+func (ibn *incomingFirewallNftables) BlockIncomingTraffic(network net.IPNet) (IncomingRuleRemove, error) {
+	remover, err := nftables.AddRuleWithRemoval(
+		nftables.AppendTo("ip", incomingFirewallTable, incomingFirewallForwardChain).Expr("ip", "saddr", network.String(), "jump", incomingFirewallNftChain),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		remover()
+		return nil
+	}, nil
+}
+
+// AllowURLAccess adds URL based exception.
+func (ibn *incomingFirewallNftables) AllowURLAccess(rawURLs ...string) (IncomingRuleRemove, error) {
+	var ruleRemovers []func()
+	removeAll := func() error {
+		for _, ruleRemover := range ruleRemovers {
+			ruleRemover()
+		}
+		return nil
+	}
+
+	for _, rawURL := range rawURLs {
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			removeAll()
+			return nil, err
+		}
+
+		ips, err := resolveIPs(parsed.Hostname())
+		if err != nil {
+			removeAll()
+			return nil, err
+		}
+
+		for _, ip := range ips {
+			if ip.To4() == nil {
+				continue
+			}
+			remover, err := nftables.AddRuleWithRemoval(
+				nftables.InsertTo("ip", incomingFirewallTable, incomingFirewallNftChain).Expr("ip", "daddr", ip.String(), "accept"),
+			)
+			if err != nil {
+				removeAll()
+				return nil, err
+			}
+			ruleRemovers = append(ruleRemovers, remover)
+		}
+	}
+	return removeAll, nil
+}
+
+func (ibn *incomingFirewallNftables) AllowIPAccess(ip net.IP) (IncomingRuleRemove, error) {
+	if _, err := nftables.Exec("add", "element", "ip", incomingFirewallTable, incomingFirewallSet, "{", ip.String(), "}"); err != nil {
+		return nil, err
+	}
+	return func() error {
+		_, err := nftables.Exec("delete", "element", "ip", incomingFirewallTable, incomingFirewallSet, "{", ip.String(), "}")
+		return err
+	}, nil
+}
+
+func (ibn *incomingFirewallNftables) setupFirewallTable() error {
+	commands := [][]string{
+		{"add", "table", "ip", incomingFirewallTable},
+		{"add", "set", "ip", incomingFirewallTable, incomingFirewallSet, "{", "type", "ipv4_addr", ";", "flags", "timeout", ";", "timeout", "24h", ";", "}"},
+		{"add", "chain", "ip", incomingFirewallTable, incomingFirewallForwardChain, "{", "type", "filter", "hook", "forward", "priority", "0", ";", "policy", "accept", ";", "}"},
+		{"add", "chain", "ip", incomingFirewallTable, incomingFirewallNftChain},
+		// Packets going to firewall with these destination IPs are whitelisted
+		{"add", "rule", "ip", incomingFirewallTable, incomingFirewallNftChain, "ip", "daddr", "@" + incomingFirewallSet, "accept"},
+		// By default all packets going to firewall chain are rejected
+		{"add", "rule", "ip", incomingFirewallTable, incomingFirewallNftChain, "reject"},
+	}
+	for _, args := range commands {
+		if _, err := nftables.Exec(args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ IncomingTrafficFirewall = &incomingFirewallNftables{}
@@ -20,17 +20,20 @@ package iptables
 import (
 	"bufio"
 	"bytes"
+	"os"
 
 	"github.com/mysteriumnetwork/node/utils/cmdutil"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 )
 
+const iptablesPath = "/usr/sbin/iptables"
+
 // Exec executes given args
 var Exec = defaultExec
 
 func defaultExec(args ...string) ([]string, error) {
-	args = append([]string{"sudo", "/usr/sbin/iptables"}, args...)
+	args = append([]string{"sudo", iptablesPath}, args...)
 	output, err := cmdutil.ExecOutput(args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "iptables cmd error")
@@ -44,6 +47,12 @@ func defaultExec(args ...string) ([]string, error) {
 	return lines, outputScanner.Err()
 }
 
+// Available checks whether iptables tool is installed.
+func Available() bool {
+	_, err := os.Stat(iptablesPath)
+	return err == nil
+}
+
 // AddRuleWithRemoval activates given rule
 func AddRuleWithRemoval(rule Rule) (func(), error) {
 	if _, err := Exec(rule.ApplyArgs()...); err != nil {
@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nftables
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/utils/cmdutil"
+)
+
+const nftPath = "/usr/sbin/nft"
+
+// Exec executes given args
+var Exec = defaultExec
+
+func defaultExec(args ...string) ([]string, error) {
+	args = append([]string{"sudo", nftPath}, args...)
+	output, err := cmdutil.ExecOutput(args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "nft cmd error")
+	}
+
+	outputScanner := bufio.NewScanner(bytes.NewBufferString(output))
+	var lines []string
+	for outputScanner.Scan() {
+		lines = append(lines, outputScanner.Text())
+	}
+	return lines, outputScanner.Err()
+}
+
+// Available checks whether nft tool is installed.
+func Available() bool {
+	_, err := os.Stat(nftPath)
+	return err == nil
+}
+
+var handleRegex = regexp.MustCompile(`# handle (\d+)$`)
+
+// AddRule adds given rule and returns it together with the handle assigned by the kernel.
+func AddRule(rule Rule) (Rule, error) {
+	output, err := Exec(rule.ApplyArgs()...)
+	if err != nil {
+		return rule, err
+	}
+	for _, line := range output {
+		if match := handleRegex.FindStringSubmatch(line); match != nil {
+			rule.handle, err = strconv.Atoi(match[1])
+			return rule, err
+		}
+	}
+	return rule, errors.Errorf("no handle returned for rule: %v", rule.ApplyArgs())
+}
+
+// DeleteRule deletes rule which was previously added.
+func DeleteRule(rule Rule) error {
+	_, err := Exec(rule.RemoveArgs()...)
+	return err
+}
+
+// AddRuleWithRemoval activates given rule
+func AddRuleWithRemoval(rule Rule) (func(), error) {
+	rule, err := AddRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		if err := DeleteRule(rule); err != nil {
+			log.Warn().Err(err).Msgf("Error executing rule: %v you might wanna do it yourself", rule.RemoveArgs())
+		}
+	}, nil
+}
+
+// DeleteTable deletes table together with its chains, rules and sets, missing table is ignored.
+func DeleteTable(family, table string) error {
+	output, err := Exec("list", "tables", family)
+	if err != nil {
+		return err
+	}
+	for _, line := range output {
+		if line == "table "+family+" "+table {
+			_, err := Exec("delete", "table", family, table)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nftables
+
+import "strconv"
+
+// Rule is a rule of nftables chain.
+type Rule struct {
+	family string
+	table  string
+	chain  string
+	verb   string
+	expr   []string
+	handle int
+}
+
+// AppendTo creates a new rule to be appended to the specified chain.
+func AppendTo(family, table, chain string) Rule {
+	return Rule{family: family, table: table, chain: chain, verb: "add"}
+}
+
+// InsertTo creates a new rule to be inserted at the beginning of the specified chain.
+func InsertTo(family, table, chain string) Rule {
+	return Rule{family: family, table: table, chain: chain, verb: "insert"}
+}
+
+// Expr sets the rule statements (see `man nft`).
+func (r Rule) Expr(expr ...string) Rule {
+	r.expr = expr
+	return r
+}
+
+// ApplyArgs returns an argument list to be passed to the nft executable to APPLY the rule.
+// Added rule is echoed back together with its handle.
+func (r Rule) ApplyArgs() []string {
+	return append([]string{"--echo", "--handle", r.verb, "rule", r.family, r.table, r.chain}, r.expr...)
+}
+
+// RemoveArgs returns an argument list to be passed to the nft executable to REMOVE the rule.
+func (r Rule) RemoveArgs() []string {
+	return []string{"delete", "rule", r.family, r.table, r.chain, "handle", strconv.Itoa(r.handle)}
+}
+
+// Equals checks if two Rules are equal.
+func (r Rule) Equals(another Rule) bool {
+	return r.family == another.family &&
+		r.table == another.table &&
+		r.chain == another.chain &&
+		r.handle == another.handle
+}
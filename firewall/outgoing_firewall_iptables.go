@@ -28,11 +28,6 @@ import (
 
 const killswitchChain = "MYST_CONSUMER_KILL_SWITCH"
 
-type refCount struct {
-	count int
-	f     func()
-}
-
 type outgoingFirewallIptables struct {
 	lock             sync.Mutex
 	trafficLockScope Scope
@@ -174,37 +169,7 @@ func (obi *outgoingFirewallIptables) cleanupStaleRules() error {
 }
 
 func (obi *outgoingFirewallIptables) trackingReferenceCall(ref string, actualCall func() (OutgoingRuleRemove, error)) (OutgoingRuleRemove, error) {
-	obi.lock.Lock()
-	defer obi.lock.Unlock()
-
-	refCount := obi.referenceTracker[ref]
-	if refCount.count == 0 {
-		removeRule, err := actualCall()
-		if err != nil {
-			return nil, err
-		}
-		refCount.f = removeRule
-
-		refCount.count++
-		obi.referenceTracker[ref] = refCount
-	}
-
-	return obi.decreaseRefCall(ref), nil
-}
-
-func (obi *outgoingFirewallIptables) decreaseRefCall(ref string) OutgoingRuleRemove {
-	return func() {
-		obi.lock.Lock()
-		defer obi.lock.Unlock()
-
-		refCount := obi.referenceTracker[ref]
-		if refCount.count == 1 {
-			refCount.f()
-
-			refCount.count--
-			obi.referenceTracker[ref] = refCount
-		}
-	}
+	return trackingReferenceCall(&obi.lock, obi.referenceTracker, ref, actualCall)
 }
 
 var _ OutgoingTrafficFirewall = &outgoingFirewallIptables{}
@@ -0,0 +1,161 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package firewall
+
+import (
+	"net"
+	"net/url"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/firewall/nftables"
+)
+
+const (
+	killswitchTable       = "myst_consumer_kill_switch"
+	killswitchOutputChain = "output"
+	killswitchNftChain    = "kill_switch"
+)
+
+type outgoingFirewallNftables struct {
+	lock             sync.Mutex
+	trafficLockScope Scope
+	referenceTracker map[string]refCount
+}
+
+// Setup removes rules left by previous runs and creates kill switch table.
+func (obn *outgoingFirewallNftables) Setup() error {
+	if err := nftables.DeleteTable("inet", killswitchTable); err != nil {
+		return err
+	}
+	return obn.setupKillSwitchTable()
+}
+
+// Teardown tries to cleanup all changes made by setup and leave system in the state before setup.
+func (obn *outgoingFirewallNftables) Teardown() {
+	if err := nftables.DeleteTable("inet", killswitchTable); err != nil {
+		log.Warn().Err(err).Msg("Error cleaning up nftables rules, you might want to do it yourself")
+	}
+}
+
+// BlockOutgoingTraffic effectively disallows any outgoing traffic from consumer node with specified scope.
+func (obn *outgoingFirewallNftables) BlockOutgoingTraffic(scope Scope, outboundIP string) (OutgoingRuleRemove, error) {
+	if obn.trafficLockScope == Global {
+		// nothing can override global lock
+		return func() {}, nil
+	}
+	ip := net.ParseIP(outboundIP)
+	if ip == nil {
+		return nil, errors.Errorf("invalid outbound IP: %q", outboundIP)
+	}
+	obn.trafficLockScope = scope
+	return obn.trackingReferenceCall("block-traffic", func() (OutgoingRuleRemove, error) {
+		// Take kill switch chain into effect for packets leaving via outbound IP
+		return nftables.AddRuleWithRemoval(
+			nftables.AppendTo("inet", killswitchTable, killswitchOutputChain).Expr(ipFamily(ip), "saddr", ip.String(), "jump", killswitchNftChain),
+		)
+	})
+}
+
+// AllowIPAccess adds exception to blocked traffic for specified IP or host name.
+func (obn *outgoingFirewallNftables) AllowIPAccess(ip string) (OutgoingRuleRemove, error) {
+	return obn.trackingReferenceCall("allow:"+ip, func() (OutgoingRuleRemove, error) {
+		ips, err := resolveIPs(ip)
+		if err != nil {
+			return nil, err
+		}
+
+		var removers []func()
+		removeAll := func() {
+			for _, remove := range removers {
+				remove()
+			}
+		}
+		for _, addr := range ips {
+			remove, err := nftables.AddRuleWithRemoval(
+				nftables.InsertTo("inet", killswitchTable, killswitchNftChain).Expr(ipFamily(addr), "daddr", addr.String(), "accept"),
+			)
+			if err != nil {
+				removeAll()
+				return nil, err
+			}
+			removers = append(removers, remove)
+		}
+		return removeAll, nil
+	})
+}
+
+// AllowURLAccess adds URL based exception.
+func (obn *outgoingFirewallNftables) AllowURLAccess(rawURLs ...string) (OutgoingRuleRemove, error) {
+	var ruleRemovers []func()
+	removeAll := func() {
+		for _, ruleRemover := range ruleRemovers {
+			ruleRemover()
+		}
+	}
+	for _, rawURL := range rawURLs {
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			removeAll()
+			return nil, err
+		}
+
+		remover, err := obn.AllowIPAccess(parsed.Hostname())
+		if err != nil {
+			removeAll()
+			return nil, err
+		}
+		ruleRemovers = append(ruleRemovers, remover)
+	}
+	return removeAll, nil
+}
+
+func (obn *outgoingFirewallNftables) setupKillSwitchTable() error {
+	commands := [][]string{
+		{"add", "table", "inet", killswitchTable},
+		{"add", "chain", "inet", killswitchTable, killswitchOutputChain, "{", "type", "filter", "hook", "output", "priority", "0", ";", "policy", "accept", ";", "}"},
+		{"add", "chain", "inet", killswitchTable, killswitchNftChain},
+		// By default all new connections going to kill switch chain are rejected
+		{"add", "rule", "inet", killswitchTable, killswitchNftChain, "ct", "state", "new", "reject"},
+		// TODO for now always allow outgoing DNS traffic, BUT it should be exposed as separate firewall call
+		{"insert", "rule", "inet", killswitchTable, killswitchNftChain, "udp", "dport", "53", "accept"},
+		{"insert", "rule", "inet", killswitchTable, killswitchNftChain, "tcp", "dport", "53", "accept"},
+	}
+	for _, args := range commands {
+		if _, err := nftables.Exec(args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (obn *outgoingFirewallNftables) trackingReferenceCall(ref string, actualCall func() (OutgoingRuleRemove, error)) (OutgoingRuleRemove, error) {
+	return trackingReferenceCall(&obn.lock, obn.referenceTracker, ref, actualCall)
+}
+
+// ipFamily returns nftables payload protocol matching addresses of the given IP.
+func ipFamily(ip net.IP) string {
+	if ip.To4() != nil {
+		return "ip"
+	}
+	return "ip6"
+}
+
+var _ OutgoingTrafficFirewall = &outgoingFirewallNftables{}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package firewall
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/firewall/nftables"
+)
+
+func Test_outgoingFirewallNftables_BlocksAllOutgoingTraffic(t *testing.T) {
+	blockArgs := []string{"--echo", "--handle", "add", "rule", "inet", killswitchTable, killswitchOutputChain, "ip", "saddr", "1.1.1.1", "jump", killswitchNftChain}
+	mockedExec := iptablesExecMock{
+		mocks: map[string]iptablesExecResult{
+			argsToKey(blockArgs...): {output: []string{"add rule inet myst_consumer_kill_switch output ip saddr 1.1.1.1 jump kill_switch # handle 7"}},
+		},
+	}
+	nftables.Exec = mockedExec.Exec
+
+	fw := &outgoingFirewallNftables{
+		referenceTracker: make(map[string]refCount),
+	}
+
+	removeRuleFunc, err := fw.BlockOutgoingTraffic("test-scope", "1.1.1.1")
+	assert.NoError(t, err)
+	assert.True(t, mockedExec.VerifyCalledWithArgs(blockArgs...))
+
+	removeRuleFunc()
+	assert.True(t, mockedExec.VerifyCalledWithArgs("delete", "rule", "inet", killswitchTable, killswitchOutputChain, "handle", "7"))
+}
+
+func Test_outgoingFirewallNftables_AllowIPAccessFailsWithoutRuleHandle(t *testing.T) {
+	mockedExec := iptablesExecMock{
+		mocks: map[string]iptablesExecResult{},
+	}
+	nftables.Exec = mockedExec.Exec
+
+	fw := &outgoingFirewallNftables{
+		referenceTracker: make(map[string]refCount),
+	}
+
+	_, err := fw.AllowIPAccess("2.2.2.2")
+	assert.Error(t, err)
+	assert.True(t, mockedExec.VerifyCalledWithArgs("--echo", "--handle", "insert", "rule", "inet", killswitchTable, killswitchNftChain, "ip", "daddr", "2.2.2.2", "accept"))
+	assert.Equal(t, 0, fw.referenceTracker["allow:2.2.2.2"].count)
+}
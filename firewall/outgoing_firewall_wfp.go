@@ -0,0 +1,193 @@
+//go:build windows
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package firewall
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+
+	"github.com/mysteriumnetwork/node/firewall/wfp"
+	"github.com/rs/zerolog/log"
+)
+
+// Permit filters must outweigh the block filter within the kill switch sublayer.
+const (
+	wfpBlockWeight  = 0
+	wfpDNSWeight    = 10
+	wfpPermitWeight = 15
+)
+
+type outgoingFirewallWFP struct {
+	lock             sync.Mutex
+	trafficLockScope Scope
+	referenceTracker map[string]refCount
+	engine           *wfp.Engine
+}
+
+// Setup removes filters left behind by a previous run and installs kill switch sublayer.
+func (ow *outgoingFirewallWFP) Setup() error {
+	engine, err := wfp.Open()
+	if err != nil {
+		return err
+	}
+	ow.engine = engine
+
+	if err := ow.engine.Uninstall(); err != nil {
+		return err
+	}
+	if err := ow.engine.Install(); err != nil {
+		return err
+	}
+	return ow.setupKillSwitchFilters()
+}
+
+// Teardown tries to cleanup all changes made by setup and leave system in the state before setup.
+func (ow *outgoingFirewallWFP) Teardown() {
+	if ow.engine == nil {
+		return
+	}
+	if err := ow.engine.Uninstall(); err != nil {
+		log.Warn().Err(err).Msg("Error cleaning up WFP filters, you might want to do it yourself")
+	}
+	if err := ow.engine.Close(); err != nil {
+		log.Warn().Err(err).Msg("Error closing WFP engine")
+	}
+	ow.engine = nil
+}
+
+// BlockOutgoingTraffic effectively disallows any outgoing traffic from consumer node with specified scope.
+func (ow *outgoingFirewallWFP) BlockOutgoingTraffic(scope Scope, outboundIP string) (OutgoingRuleRemove, error) {
+	if ow.trafficLockScope == Global {
+		// nothing can override global lock
+		return func() {}, nil
+	}
+	ip := net.ParseIP(outboundIP)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid outbound IP: %q", outboundIP)
+	}
+	ow.trafficLockScope = scope
+	return ow.trackingReferenceCall("block-traffic", func() (OutgoingRuleRemove, error) {
+		return ow.addFilterWithRemoval(wfp.Filter{
+			Name:    "Block outgoing traffic",
+			Weight:  wfpBlockWeight,
+			LocalIP: ip,
+		})
+	})
+}
+
+// AllowIPAccess adds exception to blocked traffic for specified IP or host name.
+func (ow *outgoingFirewallWFP) AllowIPAccess(ip string) (OutgoingRuleRemove, error) {
+	return ow.trackingReferenceCall("allow:"+ip, func() (OutgoingRuleRemove, error) {
+		ips, err := resolveIPs(ip)
+		if err != nil {
+			return nil, err
+		}
+
+		var removers []OutgoingRuleRemove
+		removeAll := func() {
+			for _, remove := range removers {
+				remove()
+			}
+		}
+		for _, addr := range ips {
+			remove, err := ow.addFilterWithRemoval(wfp.Filter{
+				Name:     "Allow " + ip,
+				Permit:   true,
+				Weight:   wfpPermitWeight,
+				RemoteIP: addr,
+			})
+			if err != nil {
+				removeAll()
+				return nil, err
+			}
+			removers = append(removers, remove)
+		}
+		return removeAll, nil
+	})
+}
+
+// AllowURLAccess adds URL based exception.
+func (ow *outgoingFirewallWFP) AllowURLAccess(rawURLs ...string) (OutgoingRuleRemove, error) {
+	var ruleRemovers []func()
+	removeAll := func() {
+		for _, ruleRemover := range ruleRemovers {
+			ruleRemover()
+		}
+	}
+	for _, rawURL := range rawURLs {
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			removeAll()
+			return nil, err
+		}
+
+		remover, err := ow.AllowIPAccess(parsed.Hostname())
+		if err != nil {
+			removeAll()
+			return nil, err
+		}
+		ruleRemovers = append(ruleRemovers, remover)
+	}
+	return removeAll, nil
+}
+
+func (ow *outgoingFirewallWFP) setupKillSwitchFilters() error {
+	// TODO for now always allow outgoing DNS traffic, BUT it should be exposed as separate firewall call
+	for _, protocol := range []uint8{wfp.ProtocolUDP, wfp.ProtocolTCP} {
+		_, err := ow.engine.AddFilter(wfp.Filter{
+			Name:       "Allow DNS",
+			Permit:     true,
+			Weight:     wfpDNSWeight,
+			Protocol:   protocol,
+			RemotePort: 53,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ow *outgoingFirewallWFP) addFilterWithRemoval(filter wfp.Filter) (OutgoingRuleRemove, error) {
+	engine := ow.engine
+	if engine == nil {
+		return nil, fmt.Errorf("WFP firewall is not set up")
+	}
+
+	ids, err := engine.AddFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		for _, id := range ids {
+			if err := engine.DeleteFilter(id); err != nil {
+				log.Warn().Err(err).Msgf("Error deleting WFP filter %q, you might wanna do it yourself", filter.Name)
+			}
+		}
+	}, nil
+}
+
+func (ow *outgoingFirewallWFP) trackingReferenceCall(ref string, actualCall func() (OutgoingRuleRemove, error)) (OutgoingRuleRemove, error) {
+	return trackingReferenceCall(&ow.lock, ow.referenceTracker, ref, actualCall)
+}
+
+var _ OutgoingTrafficFirewall = &outgoingFirewallWFP{}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package firewall
+
+import (
+	"net"
+	"sync"
+)
+
+type refCount struct {
+	count int
+	f     func()
+}
+
+// trackingReferenceCall applies the rule on the first reference only and returns the function releasing the reference.
+func trackingReferenceCall(lock sync.Locker, tracker map[string]refCount, ref string, actualCall func() (OutgoingRuleRemove, error)) (OutgoingRuleRemove, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	refCount := tracker[ref]
+	if refCount.count == 0 {
+		removeRule, err := actualCall()
+		if err != nil {
+			return nil, err
+		}
+		refCount.f = removeRule
+
+		refCount.count++
+		tracker[ref] = refCount
+	}
+
+	return decreaseRefCall(lock, tracker, ref), nil
+}
+
+func decreaseRefCall(lock sync.Locker, tracker map[string]refCount, ref string) OutgoingRuleRemove {
+	return func() {
+		lock.Lock()
+		defer lock.Unlock()
+
+		refCount := tracker[ref]
+		if refCount.count == 1 {
+			refCount.f()
+
+			refCount.count--
+			tracker[ref] = refCount
+		}
+	}
+}
+
+// resolveIPs returns the given IP or addresses of the given host name.
+func resolveIPs(host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	return net.LookupIP(host)
+}
@@ -0,0 +1,116 @@
+//go:build windows
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package wfp
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modfwpuclnt = windows.NewLazySystemDLL("fwpuclnt.dll")
+
+	procFwpmEngineOpen0              = modfwpuclnt.NewProc("FwpmEngineOpen0")
+	procFwpmEngineClose0             = modfwpuclnt.NewProc("FwpmEngineClose0")
+	procFwpmTransactionBegin0        = modfwpuclnt.NewProc("FwpmTransactionBegin0")
+	procFwpmTransactionCommit0       = modfwpuclnt.NewProc("FwpmTransactionCommit0")
+	procFwpmTransactionAbort0        = modfwpuclnt.NewProc("FwpmTransactionAbort0")
+	procFwpmProviderAdd0             = modfwpuclnt.NewProc("FwpmProviderAdd0")
+	procFwpmProviderDeleteByKey0     = modfwpuclnt.NewProc("FwpmProviderDeleteByKey0")
+	procFwpmSubLayerAdd0             = modfwpuclnt.NewProc("FwpmSubLayerAdd0")
+	procFwpmSubLayerDeleteByKey0     = modfwpuclnt.NewProc("FwpmSubLayerDeleteByKey0")
+	procFwpmFilterAdd0               = modfwpuclnt.NewProc("FwpmFilterAdd0")
+	procFwpmFilterDeleteByID0        = modfwpuclnt.NewProc("FwpmFilterDeleteById0")
+	procFwpmFilterCreateEnumHandle0  = modfwpuclnt.NewProc("FwpmFilterCreateEnumHandle0")
+	procFwpmFilterEnum0              = modfwpuclnt.NewProc("FwpmFilterEnum0")
+	procFwpmFilterDestroyEnumHandle0 = modfwpuclnt.NewProc("FwpmFilterDestroyEnumHandle0")
+	procFwpmFreeMemory0              = modfwpuclnt.NewProc("FwpmFreeMemory0")
+)
+
+// WFP management functions return the error code instead of setting the last error.
+func callErr(proc *windows.LazyProc, args ...uintptr) error {
+	r1, _, _ := syscall.SyscallN(proc.Addr(), args...)
+	if r1 != 0 {
+		return syscall.Errno(r1)
+	}
+	return nil
+}
+
+func fwpmEngineOpen0(session *fwpmSession0, engineHandle *uintptr) error {
+	return callErr(procFwpmEngineOpen0, 0, rpcCAuthnWinNT, 0, uintptr(unsafe.Pointer(session)), uintptr(unsafe.Pointer(engineHandle)))
+}
+
+func fwpmEngineClose0(engineHandle uintptr) error {
+	return callErr(procFwpmEngineClose0, engineHandle)
+}
+
+func fwpmTransactionBegin0(engineHandle uintptr) error {
+	return callErr(procFwpmTransactionBegin0, engineHandle, 0)
+}
+
+func fwpmTransactionCommit0(engineHandle uintptr) error {
+	return callErr(procFwpmTransactionCommit0, engineHandle)
+}
+
+func fwpmTransactionAbort0(engineHandle uintptr) error {
+	return callErr(procFwpmTransactionAbort0, engineHandle)
+}
+
+func fwpmProviderAdd0(engineHandle uintptr, provider *fwpmProvider0) error {
+	return callErr(procFwpmProviderAdd0, engineHandle, uintptr(unsafe.Pointer(provider)), 0)
+}
+
+func fwpmProviderDeleteByKey0(engineHandle uintptr, key *windows.GUID) error {
+	return callErr(procFwpmProviderDeleteByKey0, engineHandle, uintptr(unsafe.Pointer(key)))
+}
+
+func fwpmSubLayerAdd0(engineHandle uintptr, subLayer *fwpmSublayer0) error {
+	return callErr(procFwpmSubLayerAdd0, engineHandle, uintptr(unsafe.Pointer(subLayer)), 0)
+}
+
+func fwpmSubLayerDeleteByKey0(engineHandle uintptr, key *windows.GUID) error {
+	return callErr(procFwpmSubLayerDeleteByKey0, engineHandle, uintptr(unsafe.Pointer(key)))
+}
+
+func fwpmFilterAdd0(engineHandle uintptr, filter *fwpmFilter0, id *uint64) error {
+	return callErr(procFwpmFilterAdd0, engineHandle, uintptr(unsafe.Pointer(filter)), 0, uintptr(unsafe.Pointer(id)))
+}
+
+func fwpmFilterDeleteByID0(engineHandle uintptr, id uint64) error {
+	return callErr(procFwpmFilterDeleteByID0, append([]uintptr{engineHandle}, uint64Args(id)...)...)
+}
+
+func fwpmFilterCreateEnumHandle0(engineHandle uintptr, template *fwpmFilterEnumTemplate0, enumHandle *uintptr) error {
+	return callErr(procFwpmFilterCreateEnumHandle0, engineHandle, uintptr(unsafe.Pointer(template)), uintptr(unsafe.Pointer(enumHandle)))
+}
+
+func fwpmFilterEnum0(engineHandle, enumHandle uintptr, requested uint32, entries ***fwpmFilter0, returned *uint32) error {
+	return callErr(procFwpmFilterEnum0, engineHandle, enumHandle, uintptr(requested), uintptr(unsafe.Pointer(entries)), uintptr(unsafe.Pointer(returned)))
+}
+
+func fwpmFilterDestroyEnumHandle0(engineHandle, enumHandle uintptr) error {
+	return callErr(procFwpmFilterDestroyEnumHandle0, engineHandle, enumHandle)
+}
+
+func fwpmFreeMemory0(p unsafe.Pointer) {
+	syscall.SyscallN(procFwpmFreeMemory0.Addr(), uintptr(unsafe.Pointer(&p)))
+}
@@ -0,0 +1,120 @@
+//go:build windows
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package wfp
+
+import "golang.org/x/sys/windows"
+
+// Constants and structures below mirror the definitions from fwptypes.h and fwpmtypes.h.
+const (
+	rpcCAuthnWinNT = 10
+
+	fwpActionFlagTerminating = 0x00001000
+	fwpActionBlock           = 0x00000001 | fwpActionFlagTerminating
+	fwpActionPermit          = 0x00000002 | fwpActionFlagTerminating
+
+	fwpMatchEqual = 0
+
+	fwpUint8                 = 1
+	fwpUint16                = 2
+	fwpUint32                = 3
+	fwpByteArray16Type       = 11
+	fwpFilterEnumOverlapping = 1
+
+	errFilterNotFound   = windows.Errno(0x80320003)
+	errProviderNotFound = windows.Errno(0x80320005)
+	errSublayerNotFound = windows.Errno(0x80320007)
+	errAlreadyExists    = windows.Errno(0x80320009)
+)
+
+var (
+	layerALEAuthConnectV4 = windows.GUID{Data1: 0xc38d57d1, Data2: 0x05a7, Data3: 0x4c33, Data4: [8]byte{0x90, 0x4f, 0x7f, 0xbc, 0xee, 0xe6, 0x0e, 0x82}}
+	layerALEAuthConnectV6 = windows.GUID{Data1: 0x4a72393b, Data2: 0x319f, Data3: 0x44bc, Data4: [8]byte{0x84, 0xc3, 0xba, 0x54, 0xdb, 0xb3, 0xb6, 0xb4}}
+
+	conditionIPLocalAddress  = windows.GUID{Data1: 0xd9ee00de, Data2: 0xc1ef, Data3: 0x4617, Data4: [8]byte{0xbf, 0xe3, 0xff, 0xd8, 0xf5, 0xa0, 0x89, 0x57}}
+	conditionIPRemoteAddress = windows.GUID{Data1: 0xb235ae9a, Data2: 0x1d64, Data3: 0x49b8, Data4: [8]byte{0xa4, 0x4c, 0x5f, 0xf3, 0xd9, 0x09, 0x50, 0x45}}
+	conditionIPRemotePort    = windows.GUID{Data1: 0xc35a604d, Data2: 0xd22b, Data3: 0x4e1a, Data4: [8]byte{0x91, 0xb4, 0x68, 0xf6, 0x74, 0xee, 0x67, 0x4b}}
+	conditionIPProtocol      = windows.GUID{Data1: 0x3971ef2b, Data2: 0x623e, Data3: 0x4f9a, Data4: [8]byte{0x8c, 0xb1, 0x6e, 0x79, 0xb8, 0x06, 0xb9, 0xa7}}
+)
+
+type fwpmDisplayData0 struct {
+	name        *uint16
+	description *uint16
+}
+
+type fwpByteBlob struct {
+	size uint32
+	data *uint8
+}
+
+type fwpValue0 struct {
+	_type uint32
+	value uintptr
+}
+
+type fwpmAction0 struct {
+	_type      uint32
+	filterType windows.GUID
+}
+
+type fwpmSession0 struct {
+	sessionKey           windows.GUID
+	displayData          fwpmDisplayData0
+	flags                uint32
+	txnWaitTimeoutInMSec uint32
+	processID            uint32
+	sid                  *windows.SID
+	username             *uint16
+	kernelMode           int32
+}
+
+type fwpmProvider0 struct {
+	providerKey  windows.GUID
+	displayData  fwpmDisplayData0
+	flags        uint32
+	providerData fwpByteBlob
+	serviceName  *uint16
+}
+
+type fwpmSublayer0 struct {
+	subLayerKey  windows.GUID
+	displayData  fwpmDisplayData0
+	flags        uint32
+	providerKey  *windows.GUID
+	providerData fwpByteBlob
+	weight       uint16
+}
+
+type fwpmFilterCondition0 struct {
+	fieldKey       windows.GUID
+	matchType      uint32
+	conditionValue fwpValue0
+}
+
+type fwpmFilterEnumTemplate0 struct {
+	providerKey             *windows.GUID
+	layerKey                windows.GUID
+	enumType                uint32
+	flags                   uint32
+	providerContextTemplate uintptr
+	numFilterConditions     uint32
+	filterCondition         *fwpmFilterCondition0
+	actionMask              uint32
+	calloutKey              *windows.GUID
+}
@@ -0,0 +1,318 @@
+//go:build windows
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package wfp manages Windows Filtering Platform filters of the node.
+package wfp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Protocols which can be matched by a filter.
+const (
+	ProtocolTCP uint8 = 6
+	ProtocolUDP uint8 = 17
+)
+
+// Provider and sublayer keys are fixed, so that filters left behind by a crashed node
+// can be found and removed on the next start.
+var (
+	providerKey = windows.GUID{Data1: 0x6d3f1e52, Data2: 0x8b4a, Data3: 0x4c1e, Data4: [8]byte{0x9a, 0x61, 0x2f, 0x0b, 0x7c, 0x35, 0xd4, 0x18}}
+	sublayerKey = windows.GUID{Data1: 0x2b9c7a40, Data2: 0x51e3, Data3: 0x4f8d, Data4: [8]byte{0xb2, 0x07, 0x6e, 0x93, 0xc1, 0x4a, 0x58, 0xf6}}
+)
+
+// Filter describes a rule applied to outbound connections.
+type Filter struct {
+	Name       string
+	Permit     bool
+	Weight     uint8
+	LocalIP    net.IP
+	RemoteIP   net.IP
+	Protocol   uint8
+	RemotePort uint16
+}
+
+// Engine is a session to the Windows Filtering Platform engine.
+type Engine struct {
+	handle uintptr
+}
+
+// Open opens a session to the filtering engine.
+// Session is not dynamic, added filters stay in effect until they are deleted.
+func Open() (*Engine, error) {
+	displayData, err := newDisplayData("Mysterium node")
+	if err != nil {
+		return nil, err
+	}
+
+	session := fwpmSession0{
+		displayData:          *displayData,
+		txnWaitTimeoutInMSec: windows.INFINITE,
+	}
+
+	var handle uintptr
+	if err := fwpmEngineOpen0(&session, &handle); err != nil {
+		return nil, fmt.Errorf("could not open filtering engine: %w", err)
+	}
+
+	return &Engine{handle: handle}, nil
+}
+
+// Close closes the session to the filtering engine.
+func (e *Engine) Close() error {
+	return fwpmEngineClose0(e.handle)
+}
+
+// Install registers the provider and the sublayer which hold all filters of the node.
+func (e *Engine) Install() error {
+	return e.transaction(func() error {
+		displayData, err := newDisplayData("Mysterium node")
+		if err != nil {
+			return err
+		}
+
+		provider := fwpmProvider0{
+			providerKey: providerKey,
+			displayData: *displayData,
+		}
+		if err := fwpmProviderAdd0(e.handle, &provider); err != nil && !errors.Is(err, errAlreadyExists) {
+			return fmt.Errorf("could not add provider: %w", err)
+		}
+
+		displayData, err = newDisplayData("Mysterium node kill switch")
+		if err != nil {
+			return err
+		}
+
+		sublayer := fwpmSublayer0{
+			subLayerKey: sublayerKey,
+			displayData: *displayData,
+			providerKey: &providerKey,
+			weight:      ^uint16(0),
+		}
+		if err := fwpmSubLayerAdd0(e.handle, &sublayer); err != nil && !errors.Is(err, errAlreadyExists) {
+			return fmt.Errorf("could not add sublayer: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Uninstall removes all filters of the node together with its sublayer and provider.
+func (e *Engine) Uninstall() error {
+	return e.transaction(func() error {
+		for _, layer := range []windows.GUID{layerALEAuthConnectV4, layerALEAuthConnectV6} {
+			ids, err := e.filterIDs(layer)
+			if err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				if err := e.DeleteFilter(id); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := fwpmSubLayerDeleteByKey0(e.handle, &sublayerKey); err != nil && !errors.Is(err, errSublayerNotFound) {
+			return fmt.Errorf("could not delete sublayer: %w", err)
+		}
+
+		if err := fwpmProviderDeleteByKey0(e.handle, &providerKey); err != nil && !errors.Is(err, errProviderNotFound) {
+			return fmt.Errorf("could not delete provider: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// AddFilter adds filter to the sublayer of the node and returns IDs of created filters.
+// Filter without IP conditions is added for both IPv4 and IPv6 connections.
+func (e *Engine) AddFilter(filter Filter) ([]uint64, error) {
+	var conditions []fwpmFilterCondition0
+	var ip4, ip6 bool
+	var keepAlive []*[16]byte
+
+	addressCondition := func(key windows.GUID, ip net.IP) {
+		condition := fwpmFilterCondition0{fieldKey: key, matchType: fwpMatchEqual}
+		if v4 := ip.To4(); v4 != nil {
+			ip4 = true
+			condition.conditionValue = fwpValue0{
+				_type: fwpUint32,
+				value: uintptr(uint32(v4[0])<<24 | uint32(v4[1])<<16 | uint32(v4[2])<<8 | uint32(v4[3])),
+			}
+		} else {
+			ip6 = true
+			var addr [16]byte
+			copy(addr[:], ip.To16())
+			keepAlive = append(keepAlive, &addr)
+			condition.conditionValue = fwpValue0{_type: fwpByteArray16Type, value: uintptr(unsafe.Pointer(&addr))}
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if filter.LocalIP != nil {
+		addressCondition(conditionIPLocalAddress, filter.LocalIP)
+	}
+	if filter.RemoteIP != nil {
+		addressCondition(conditionIPRemoteAddress, filter.RemoteIP)
+	}
+	if ip4 && ip6 {
+		return nil, errors.New("filter addresses must be of the same IP family")
+	}
+	if filter.Protocol != 0 {
+		conditions = append(conditions, fwpmFilterCondition0{
+			fieldKey:       conditionIPProtocol,
+			matchType:      fwpMatchEqual,
+			conditionValue: fwpValue0{_type: fwpUint8, value: uintptr(filter.Protocol)},
+		})
+	}
+	if filter.RemotePort != 0 {
+		conditions = append(conditions, fwpmFilterCondition0{
+			fieldKey:       conditionIPRemotePort,
+			matchType:      fwpMatchEqual,
+			conditionValue: fwpValue0{_type: fwpUint16, value: uintptr(filter.RemotePort)},
+		})
+	}
+
+	var layers []windows.GUID
+	if !ip6 {
+		layers = append(layers, layerALEAuthConnectV4)
+	}
+	if !ip4 {
+		layers = append(layers, layerALEAuthConnectV6)
+	}
+
+	displayData, err := newDisplayData(filter.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	action := uint32(fwpActionBlock)
+	if filter.Permit {
+		action = fwpActionPermit
+	}
+
+	wfpFilter := fwpmFilter0{
+		displayData:         *displayData,
+		providerKey:         &providerKey,
+		subLayerKey:         sublayerKey,
+		weight:              fwpValue0{_type: fwpUint8, value: uintptr(filter.Weight)},
+		numFilterConditions: uint32(len(conditions)),
+		action:              fwpmAction0{_type: action},
+	}
+	if len(conditions) > 0 {
+		wfpFilter.filterCondition = &conditions[0]
+	}
+
+	var ids []uint64
+	err = e.transaction(func() error {
+		for _, layer := range layers {
+			wfpFilter.layerKey = layer
+
+			var id uint64
+			if err := fwpmFilterAdd0(e.handle, &wfpFilter, &id); err != nil {
+				return fmt.Errorf("could not add filter %q: %w", filter.Name, err)
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	runtime.KeepAlive(keepAlive)
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// DeleteFilter deletes filter with the given ID, missing filters are ignored.
+func (e *Engine) DeleteFilter(id uint64) error {
+	if err := fwpmFilterDeleteByID0(e.handle, id); err != nil && !errors.Is(err, errFilterNotFound) {
+		return fmt.Errorf("could not delete filter %d: %w", id, err)
+	}
+	return nil
+}
+
+func (e *Engine) filterIDs(layer windows.GUID) ([]uint64, error) {
+	template := fwpmFilterEnumTemplate0{
+		providerKey: &providerKey,
+		layerKey:    layer,
+		enumType:    fwpFilterEnumOverlapping,
+		actionMask:  0xffffffff,
+	}
+
+	var enumHandle uintptr
+	if err := fwpmFilterCreateEnumHandle0(e.handle, &template, &enumHandle); err != nil {
+		return nil, fmt.Errorf("could not enumerate filters: %w", err)
+	}
+	defer fwpmFilterDestroyEnumHandle0(e.handle, enumHandle)
+
+	var ids []uint64
+	for {
+		var entries **fwpmFilter0
+		var returned uint32
+		if err := fwpmFilterEnum0(e.handle, enumHandle, 64, &entries, &returned); err != nil {
+			return nil, fmt.Errorf("could not enumerate filters: %w", err)
+		}
+		if returned == 0 {
+			return ids, nil
+		}
+
+		for _, entry := range unsafe.Slice(entries, returned) {
+			if entry.subLayerKey == sublayerKey {
+				ids = append(ids, entry.filterID)
+			}
+		}
+		fwpmFreeMemory0(unsafe.Pointer(entries))
+	}
+}
+
+func (e *Engine) transaction(operation func() error) error {
+	if err := fwpmTransactionBegin0(e.handle); err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+
+	if err := operation(); err != nil {
+		fwpmTransactionAbort0(e.handle)
+		return err
+	}
+
+	if err := fwpmTransactionCommit0(e.handle); err != nil {
+		fwpmTransactionAbort0(e.handle)
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func newDisplayData(name string) (*fwpmDisplayData0, error) {
+	namePtr, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fwpmDisplayData0{name: namePtr}, nil
+}
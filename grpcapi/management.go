@@ -0,0 +1,380 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/consumer/session"
+	"github.com/mysteriumnetwork/node/core/connection"
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	stateEvent "github.com/mysteriumnetwork/node/core/state/event"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+// streamBuffer is the number of events kept for a slow stream before they are dropped.
+const streamBuffer = 64
+
+type hermesProvider interface {
+	GetActiveHermes(chainID int64) (common.Address, error)
+}
+
+type registrationStatusProvider interface {
+	GetRegistrationStatus(chainID int64, id identity.Identity) (registry.RegistrationStatus, error)
+}
+
+type stateProvider interface {
+	GetState() stateEvent.State
+}
+
+type proposalRepository interface {
+	Proposals(filter *proposal.Filter) ([]proposal.PricedServiceProposal, error)
+}
+
+type sessionStorage interface {
+	List(*session.Filter) ([]session.History, error)
+}
+
+// Management implements the Management service defined in pb/management.proto.
+type Management struct {
+	pb.UnimplementedManagementServer
+
+	connections connection.MultiManager
+	hermes      hermesProvider
+	registry    registrationStatusProvider
+	state       stateProvider
+	proposals   proposalRepository
+	sessions    sessionStorage
+
+	states     *broadcaster
+	statistics *broadcaster
+}
+
+// NewManagement returns a new management service.
+func NewManagement(
+	connections connection.MultiManager,
+	hermes hermesProvider,
+	registry registrationStatusProvider,
+	state stateProvider,
+	proposals proposalRepository,
+	sessions sessionStorage,
+) *Management {
+	return &Management{
+		connections: connections,
+		hermes:      hermes,
+		registry:    registry,
+		state:       state,
+		proposals:   proposals,
+		sessions:    sessions,
+		states:      newBroadcaster(),
+		statistics:  newBroadcaster(),
+	}
+}
+
+// Subscribe subscribes to connection events streamed to clients.
+func (m *Management) Subscribe(bus eventbus.Subscriber) error {
+	err := bus.SubscribeAsync(connectionstate.AppTopicConnectionState, func(e connectionstate.AppEventConnectionState) {
+		status := toConnectionStatus(e.SessionInfo)
+		status.State = string(e.State)
+		m.states.publish(status)
+	})
+	if err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(connectionstate.AppTopicConnectionStatistics, func(e connectionstate.AppEventConnectionStatistics) {
+		m.statistics.publish(toConnectionStatistics(e.SessionInfo, e.Stats))
+	})
+}
+
+// Register registers the management service on the server.
+func (m *Management) Register(s *Server) {
+	pb.RegisterManagementServer(s.grpc, m)
+}
+
+// Connect connects the consumer to a provider matching the request.
+func (m *Management) Connect(_ context.Context, req *pb.ConnectRequest) (*pb.ConnectionStatus, error) {
+	if req.ConsumerId == "" || req.ServiceType == "" {
+		return nil, status.Error(codes.InvalidArgument, "consumer_id and service_type are required")
+	}
+	dns, err := connection.NewDNSOption(req.Dns)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid dns: %v", err)
+	}
+	if dns == "" {
+		dns = connection.DNSOptionAuto
+	}
+
+	chainID := config.GetInt64(config.FlagChainID)
+	hermesID, err := m.hermes.GetActiveHermes(chainID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not get active hermes: %v", err)
+	}
+
+	consumerID := identity.FromAddress(req.ConsumerId)
+	registration, err := m.registry.GetRegistrationStatus(chainID, consumerID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not check identity registration: %v", err)
+	}
+	switch registration {
+	case registry.Unregistered, registry.RegistrationError, registry.Unknown:
+		return nil, status.Errorf(codes.FailedPrecondition, "identity %q is not registered", req.ConsumerId)
+	}
+
+	filter := &proposal.Filter{
+		ServiceType:     req.ServiceType,
+		LocationCountry: req.Country,
+		AccessPolicy:    "all",
+		AddressFamilies: p2p.ReachableAddressFamilies(),
+	}
+	if req.ProviderId != "" {
+		filter.ProviderIDs = []string{req.ProviderId}
+	}
+	params := connection.ConnectParams{
+		DisableKillSwitch: req.DisableKillSwitch,
+		DNS:               dns,
+		ProxyPort:         int(req.Id),
+	}
+
+	err = m.connections.Connect(consumerID, hermesID, connection.FilteredProposals(filter, "", m.proposals), params)
+	switch {
+	case errors.Is(err, connection.ErrAlreadyExists):
+		return nil, status.Error(codes.AlreadyExists, "connection already exists")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "could not connect: %v", err)
+	}
+
+	return toConnectionStatus(m.connections.Status(int(req.Id))), nil
+}
+
+// Disconnect closes the consumer connection.
+func (m *Management) Disconnect(_ context.Context, req *pb.ConnectionRequest) (*pb.ManagementEmpty, error) {
+	err := m.connections.Disconnect(int(req.Id))
+	switch {
+	case errors.Is(err, connection.ErrNoConnection):
+		return nil, status.Error(codes.NotFound, "no connection exists")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "could not disconnect: %v", err)
+	}
+	return &pb.ManagementEmpty{}, nil
+}
+
+// GetConnection returns the status of the consumer connection.
+func (m *Management) GetConnection(_ context.Context, req *pb.ConnectionRequest) (*pb.ConnectionStatus, error) {
+	return toConnectionStatus(m.connections.Status(int(req.Id))), nil
+}
+
+// ListIdentities lists identities of the node.
+func (m *Management) ListIdentities(_ context.Context, _ *pb.ManagementEmpty) (*pb.IdentityList, error) {
+	res := &pb.IdentityList{}
+	for _, id := range m.state.GetState().Identities {
+		res.Identities = append(res.Identities, &pb.IdentityInfo{
+			Address:            id.Address,
+			RegistrationStatus: id.RegistrationStatus.String(),
+			ChannelAddress:     id.ChannelAddress.Hex(),
+			Balance:            amount(id.Balance),
+			Earnings:           amount(id.Earnings),
+			EarningsTotal:      amount(id.EarningsTotal),
+		})
+	}
+	return res, nil
+}
+
+// ListProposals lists proposals matching the request.
+func (m *Management) ListProposals(_ context.Context, req *pb.ProposalsRequest) (*pb.ProposalList, error) {
+	filter := &proposal.Filter{
+		ServiceType:     req.ServiceType,
+		LocationCountry: req.Country,
+		ProviderID:      req.ProviderId,
+	}
+	proposals, err := m.proposals.Proposals(filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not get proposals: %v", err)
+	}
+
+	res := &pb.ProposalList{}
+	for _, p := range proposals {
+		res.Proposals = append(res.Proposals, &pb.ProposalInfo{
+			ProviderId:   p.ProviderID,
+			ServiceType:  p.ServiceType,
+			Country:      p.Location.Country,
+			IpType:       p.Location.IPType,
+			Quality:      p.Quality.Quality,
+			PricePerHour: amount(p.Price.PricePerHour),
+			PricePerGib:  amount(p.Price.PricePerGiB),
+		})
+	}
+	return res, nil
+}
+
+// ListSessions lists sessions matching the request.
+func (m *Management) ListSessions(_ context.Context, req *pb.SessionsRequest) (*pb.SessionList, error) {
+	filter := session.NewFilter()
+	if req.Direction != "" {
+		filter.SetDirection(req.Direction)
+	}
+	if req.ServiceType != "" {
+		filter.SetServiceType(req.ServiceType)
+	}
+	if req.Status != "" {
+		filter.SetStatus(req.Status)
+	}
+	sessions, err := m.sessions.List(filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not get sessions: %v", err)
+	}
+
+	res := &pb.SessionList{}
+	for _, se := range sessions {
+		res.Sessions = append(res.Sessions, &pb.SessionRecord{
+			Id:           string(se.SessionID),
+			Direction:    se.Direction,
+			ConsumerId:   se.ConsumerID.Address,
+			ProviderId:   se.ProviderID.Address,
+			ServiceType:  se.ServiceType,
+			Status:       se.Status,
+			DataSent:     se.DataSent,
+			DataReceived: se.DataReceived,
+			Tokens:       amount(se.Tokens),
+			StartedAt:    se.Started.Unix(),
+			UpdatedAt:    se.Updated.Unix(),
+		})
+	}
+	return res, nil
+}
+
+// StreamConnectionState streams the current and all further connection states.
+func (m *Management) StreamConnectionState(_ *pb.ManagementEmpty, stream pb.Management_StreamConnectionStateServer) error {
+	events := m.states.subscribe()
+	defer m.states.unsubscribe(events)
+
+	if err := stream.Send(toConnectionStatus(m.connections.Status(0))); err != nil {
+		return err
+	}
+	return forward(stream.Context(), events, func(e proto.Message) error {
+		return stream.Send(e.(*pb.ConnectionStatus))
+	})
+}
+
+// StreamStatistics streams connection statistics.
+func (m *Management) StreamStatistics(_ *pb.ManagementEmpty, stream pb.Management_StreamStatisticsServer) error {
+	events := m.statistics.subscribe()
+	defer m.statistics.unsubscribe(events)
+
+	return forward(stream.Context(), events, func(e proto.Message) error {
+		return stream.Send(e.(*pb.ConnectionStatistics))
+	})
+}
+
+// forward sends events to the client until the call is cancelled.
+func forward(ctx context.Context, events <-chan proto.Message, send func(proto.Message) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-events:
+			if err := send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toConnectionStatus(s connectionstate.Status) *pb.ConnectionStatus {
+	status := &pb.ConnectionStatus{
+		State:       string(s.State),
+		SessionId:   string(s.SessionID),
+		ConsumerId:  s.ConsumerID.Address,
+		ProviderId:  s.Proposal.ProviderID,
+		ServiceType: s.Proposal.ServiceType,
+	}
+	if s.HermesID != (common.Address{}) {
+		status.HermesId = s.HermesID.Hex()
+	}
+	if !s.StartedAt.IsZero() {
+		status.StartedAt = s.StartedAt.Unix()
+	}
+	return status
+}
+
+func toConnectionStatistics(s connectionstate.Status, stats connectionstate.Statistics) *pb.ConnectionStatistics {
+	return &pb.ConnectionStatistics{
+		SessionId:     string(s.SessionID),
+		BytesSent:     stats.BytesSent,
+		BytesReceived: stats.BytesReceived,
+		At:            stats.At.Unix(),
+	}
+}
+
+func amount(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
+
+// broadcaster fans out events to streams, dropping events for streams which do not keep up.
+type broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan proto.Message]struct{}
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{subscribers: make(map[chan proto.Message]struct{})}
+}
+
+func (b *broadcaster) subscribe() chan proto.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan proto.Message, streamBuffer)
+	b.subscribers[ch] = struct{}{}
+	return ch
+}
+
+func (b *broadcaster) unsubscribe(ch chan proto.Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subscribers, ch)
+}
+
+func (b *broadcaster) publish(e proto.Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type tokenValidator interface {
+	ValidateToken(token string) (bool, error)
+}
+
+// Server serves gRPC calls without TLS, so it should listen on a local address only.
+type Server struct {
+	listener net.Listener
+	auth     tokenValidator
+	grpc     *grpc.Server
+}
+
+// NewServer returns a new gRPC server. Calls have to carry a tequilapi JWT token
+// in the "authorization" metadata unless token validator is nil.
+func NewServer(listener net.Listener, auth tokenValidator) *Server {
+	s := &Server{
+		listener: listener,
+		auth:     auth,
+	}
+	s.grpc = grpc.NewServer(
+		grpc.UnaryInterceptor(s.authorizeUnary),
+		grpc.StreamInterceptor(s.authorizeStream),
+	)
+	return s
+}
+
+// Serve serves calls until the server is stopped.
+func (s *Server) Serve() error {
+	log.Info().Msgf("gRPC API started on: %s", s.listener.Addr())
+	err := s.grpc.Serve(s.listener)
+	if errors.Is(err, grpc.ErrServerStopped) {
+		return nil
+	}
+	return err
+}
+
+// Stop stops the server and closes open streams.
+func (s *Server) Stop() {
+	s.grpc.Stop()
+}
+
+func (s *Server) authorizeUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) authorizeStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+func (s *Server) authorize(ctx context.Context) error {
+	if s.auth == nil {
+		return nil
+	}
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = strings.TrimPrefix(values[0], "Bearer ")
+		}
+	}
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "authorization token is missing")
+	}
+	if ok, err := s.auth.ValidateToken(token); !ok || err != nil {
+		return status.Error(codes.Unauthenticated, "authorization token is invalid")
+	}
+	return nil
+}
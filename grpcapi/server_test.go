@@ -0,0 +1,131 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+type mockTokenValidator struct {
+	token string
+}
+
+func (v *mockTokenValidator) ValidateToken(token string) (bool, error) {
+	return token == v.token, nil
+}
+
+type mockManagement struct {
+	pb.UnimplementedManagementServer
+}
+
+func (m *mockManagement) GetConnection(_ context.Context, req *pb.ConnectionRequest) (*pb.ConnectionStatus, error) {
+	if req.Id != 1 {
+		return nil, status.Errorf(codes.NotFound, "no connection %d", req.Id)
+	}
+	return &pb.ConnectionStatus{State: "Connected"}, nil
+}
+
+func (m *mockManagement) StreamStatistics(_ *pb.ManagementEmpty, stream pb.Management_StreamStatisticsServer) error {
+	for i := uint64(1); i <= 2; i++ {
+		if err := stream.Send(&pb.ConnectionStatistics{BytesSent: i}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startServer(t *testing.T) pb.ManagementClient {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := NewServer(listener, &mockTokenValidator{token: "secret"})
+	pb.RegisterManagementServer(server.grpc, &mockManagement{})
+	go server.Serve()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewManagementClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestServer_Unary(t *testing.T) {
+	client := startServer(t)
+
+	res, err := client.GetConnection(withToken("secret"), &pb.ConnectionRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "Connected", res.State)
+
+	_, err = client.GetConnection(withToken("secret"), &pb.ConnectionRequest{Id: 2})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "no connection 2", status.Convert(err).Message())
+}
+
+func TestServer_Stream(t *testing.T) {
+	client := startServer(t)
+
+	stream, err := client.StreamStatistics(withToken("secret"), &pb.ManagementEmpty{})
+	require.NoError(t, err)
+
+	var received []uint64
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		received = append(received, res.BytesSent)
+	}
+	assert.Equal(t, []uint64{1, 2}, received)
+}
+
+func TestServer_Errors(t *testing.T) {
+	client := startServer(t)
+
+	_, err := client.GetConnection(context.Background(), &pb.ConnectionRequest{Id: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.GetConnection(withToken("wrong"), &pb.ConnectionRequest{Id: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream, err := client.StreamStatistics(withToken("wrong"), &pb.ManagementEmpty{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.ListIdentities(withToken("secret"), &pb.ManagementEmpty{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
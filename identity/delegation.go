@@ -0,0 +1,244 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MaxDelegationTTL is the longest time a session key can be delegated for.
+const MaxDelegationTTL = 24 * time.Hour
+
+// Delegation is a certificate by which the identity authorizes a short-lived session key
+// to sign payment messages on its behalf.
+type Delegation struct {
+	Identity   string    `json:"identity"`
+	SessionKey string    `json:"session_key"`
+	ValidUntil time.Time `json:"valid_until"`
+	Signature  string    `json:"signature"`
+	// KeySignature proves that the delegation is presented by the holder of the session key.
+	KeySignature string `json:"key_signature"`
+}
+
+// NewDelegation creates a delegation for the session key signed by the identity and by the session key itself.
+func NewDelegation(id Identity, sessionKey *ecdsa.PrivateKey, validUntil time.Time, signer Signer) (Delegation, error) {
+	delegation := Delegation{
+		Identity:   strings.ToLower(id.Address),
+		SessionKey: strings.ToLower(crypto.PubkeyToAddress(sessionKey.PublicKey).Hex()),
+		ValidUntil: validUntil.UTC().Truncate(time.Second),
+	}
+
+	signature, err := signer.Sign(delegation.Message())
+	if err != nil {
+		return Delegation{}, fmt.Errorf("could not sign delegation: %w", err)
+	}
+	delegation.Signature = signature.Base64()
+
+	keySignature, err := crypto.Sign(messageHash(delegation.ProofMessage()), sessionKey)
+	if err != nil {
+		return Delegation{}, fmt.Errorf("could not sign delegation with session key: %w", err)
+	}
+	proof := SignatureBytes(keySignature)
+	delegation.KeySignature = proof.Base64()
+
+	return delegation, nil
+}
+
+// Message returns the part of the delegation signed by the identity.
+func (d Delegation) Message() []byte {
+	return []byte(fmt.Sprintf("Mysterium session key delegation\nidentity: %s\nsession key: %s\nvalid until: %d", d.Identity, d.SessionKey, d.ValidUntil.Unix()))
+}
+
+// ProofMessage returns the part of the delegation signed by the session key.
+func (d Delegation) ProofMessage() []byte {
+	return []byte(fmt.Sprintf("Mysterium session key possession\nidentity: %s\nsession key: %s\nvalid until: %d", d.Identity, d.SessionKey, d.ValidUntil.Unix()))
+}
+
+// Verify checks that the delegation is signed by the identity and by the session key,
+// is not expired at the given time and does not outlive MaxDelegationTTL.
+func (d Delegation) Verify(now time.Time) error {
+	if !common.IsHexAddress(d.SessionKey) {
+		return fmt.Errorf("invalid session key %q", d.SessionKey)
+	}
+	if ok, _ := NewVerifierIdentity(FromAddress(d.Identity)).Verify(d.Message(), SignatureBase64(d.Signature)); !ok {
+		return errors.New("delegation is not signed by the identity")
+	}
+	if ok, _ := NewVerifierIdentity(FromAddress(d.SessionKey)).Verify(d.ProofMessage(), SignatureBase64(d.KeySignature)); !ok {
+		return errors.New("delegation is not signed by the session key")
+	}
+	if !now.Before(d.ValidUntil) {
+		return fmt.Errorf("delegation expired at %s", d.ValidUntil)
+	}
+	if d.ValidUntil.Sub(now) > MaxDelegationTTL {
+		return fmt.Errorf("delegation is valid until %s, longer than %s", d.ValidUntil, MaxDelegationTTL)
+	}
+	return nil
+}
+
+// SessionKey is an ephemeral key signing payment messages instead of the identity key.
+type SessionKey struct {
+	key        *ecdsa.PrivateKey
+	address    common.Address
+	delegation Delegation
+}
+
+// Address returns the address of the session key.
+func (k *SessionKey) Address() common.Address {
+	return k.address
+}
+
+// Delegation returns the certificate authorizing the session key.
+func (k *SessionKey) Delegation() Delegation {
+	return k.delegation
+}
+
+// SignHash signs the given hash with the session key. The produced signature is in the [R || S || V] format where V is 0 or 1.
+func (k *SessionKey) SignHash(a accounts.Account, hash []byte) ([]byte, error) {
+	if a.Address != k.address {
+		return nil, fmt.Errorf("session key %s can not sign for %s", k.address.Hex(), a.Address.Hex())
+	}
+	return crypto.Sign(hash, k.key)
+}
+
+// Sign signs the given message with the session key.
+func (k *SessionKey) Sign(message []byte) (Signature, error) {
+	signature, err := crypto.Sign(messageHash(message), k.key)
+	if err != nil {
+		return Signature{}, err
+	}
+	return SignatureBytes(signature), nil
+}
+
+// SessionKeys issues session keys for identities and renews them before they expire.
+type SessionKeys struct {
+	signerFactory SignerFactory
+	ttl           time.Duration
+	now           func() time.Time
+
+	mu   sync.Mutex
+	keys map[string]*SessionKey
+}
+
+// NewSessionKeys returns a new session key issuer. Every key is valid for the given time, up to MaxDelegationTTL.
+func NewSessionKeys(signerFactory SignerFactory, ttl time.Duration) *SessionKeys {
+	if ttl > MaxDelegationTTL {
+		ttl = MaxDelegationTTL
+	}
+	return &SessionKeys{
+		signerFactory: signerFactory,
+		ttl:           ttl,
+		now:           time.Now,
+		keys:          make(map[string]*SessionKey),
+	}
+}
+
+// Get returns a session key of the identity. A new key is issued once the last one
+// has less than a quarter of its lifetime left, so peers always get a usable delegation.
+func (s *SessionKeys) Get(id Identity) (*SessionKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	address := strings.ToLower(id.Address)
+	if key, ok := s.keys[address]; ok && key.delegation.ValidUntil.Sub(s.now()) > s.ttl/4 {
+		return key, nil
+	}
+
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate session key: %w", err)
+	}
+
+	key := &SessionKey{
+		key:     privateKey,
+		address: crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+	key.delegation, err = NewDelegation(id, privateKey, s.now().Add(s.ttl), s.signerFactory(id))
+	if err != nil {
+		return nil, err
+	}
+
+	s.keys[address] = key
+	return key, nil
+}
+
+// Delegations keeps verified delegations of session keys used by the peers.
+type Delegations struct {
+	now func() time.Time
+
+	mu          sync.Mutex
+	delegations map[delegationKey]Delegation
+}
+
+// delegationKey identifies a delegation, so a session key claimed by one identity
+// can not replace or be used for a delegation of another.
+type delegationKey struct {
+	identity   common.Address
+	sessionKey common.Address
+}
+
+// NewDelegations returns an empty delegation registry.
+func NewDelegations() *Delegations {
+	return &Delegations{
+		now:         time.Now,
+		delegations: make(map[delegationKey]Delegation),
+	}
+}
+
+// Add verifies the delegation and remembers it until it expires.
+func (d *Delegations) Add(delegation Delegation) error {
+	now := d.now()
+	if err := delegation.Verify(now); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key, existing := range d.delegations {
+		if !now.Before(existing.ValidUntil) {
+			delete(d.delegations, key)
+		}
+	}
+	key := delegationKey{
+		identity:   common.HexToAddress(delegation.Identity),
+		sessionKey: common.HexToAddress(delegation.SessionKey),
+	}
+	d.delegations[key] = delegation
+	return nil
+}
+
+// Get returns an unexpired delegation of the session key by the identity.
+func (d *Delegations) Get(id, sessionKey common.Address) (Delegation, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delegation, ok := d.delegations[delegationKey{identity: id, sessionKey: sessionKey}]
+	if !ok || !d.now().Before(delegation.ValidUntil) {
+		return Delegation{}, false
+	}
+	return delegation, true
+}
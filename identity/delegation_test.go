@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionKeys_Get(t *testing.T) {
+	ks := NewMockKeystoreWith(MockKeys)
+	id := FromAddress("0x53a835143c0ef3bbcbfa796d7eb738ca7dd28f68")
+	assert.NoError(t, ks.Unlock(identityToAccount(id), ""))
+
+	now := time.Unix(1660000000, 0)
+	keys := NewSessionKeys(func(id Identity) Signer { return NewSigner(ks, id) }, time.Hour)
+	keys.now = func() time.Time { return now }
+
+	key, err := keys.Get(id)
+	assert.NoError(t, err)
+	assert.Equal(t, id.Address, key.Delegation().Identity)
+	assert.Equal(t, key.Address(), common.HexToAddress(key.Delegation().SessionKey))
+	assert.NoError(t, key.Delegation().Verify(now))
+
+	now = now.Add(30 * time.Minute)
+	same, err := keys.Get(id)
+	assert.NoError(t, err)
+	assert.Equal(t, key.Address(), same.Address())
+
+	now = now.Add(20 * time.Minute)
+	renewed, err := keys.Get(id)
+	assert.NoError(t, err)
+	assert.NotEqual(t, key.Address(), renewed.Address())
+
+	signature, err := renewed.Sign([]byte("message"))
+	assert.NoError(t, err)
+	signer, err := NewExtractor().Extract([]byte("message"), signature)
+	assert.NoError(t, err)
+	assert.Equal(t, renewed.Address().Hex(), signer.ToCommonAddress().Hex())
+
+	_, err = renewed.SignHash(accounts.Account{Address: id.ToCommonAddress()}, messageHash([]byte("message")))
+	assert.Error(t, err)
+}
+
+func TestDelegations(t *testing.T) {
+	ks := NewMockKeystoreWith(MockKeys)
+	id := FromAddress("0x53a835143c0ef3bbcbfa796d7eb738ca7dd28f68")
+	assert.NoError(t, ks.Unlock(identityToAccount(id), ""))
+
+	now := time.Unix(1660000000, 0)
+	privateKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	sessionKey := crypto.PubkeyToAddress(privateKey.PublicKey)
+	delegation, err := NewDelegation(id, privateKey, now.Add(time.Hour), NewSigner(ks, id))
+	assert.NoError(t, err)
+
+	delegations := NewDelegations()
+	delegations.now = func() time.Time { return now }
+
+	tampered := delegation
+	tampered.ValidUntil = tampered.ValidUntil.Add(time.Hour)
+	assert.EqualError(t, delegations.Add(tampered), "delegation is not signed by the identity")
+
+	assert.NoError(t, delegations.Add(delegation))
+	got, ok := delegations.Get(id.ToCommonAddress(), sessionKey)
+	assert.True(t, ok)
+	assert.Equal(t, delegation, got)
+	_, ok = delegations.Get(common.HexToAddress("0x1234567890123456789012345678901234567890"), sessionKey)
+	assert.False(t, ok)
+
+	now = now.Add(time.Hour)
+	_, ok = delegations.Get(id.ToCommonAddress(), sessionKey)
+	assert.False(t, ok)
+	assert.Error(t, delegations.Add(delegation))
+}
+
+func TestDelegation_Verify(t *testing.T) {
+	ks := NewMockKeystoreWith(MockKeys)
+	id := FromAddress("0x53a835143c0ef3bbcbfa796d7eb738ca7dd28f68")
+	assert.NoError(t, ks.Unlock(identityToAccount(id), ""))
+
+	now := time.Unix(1660000000, 0)
+	privateKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	delegation, err := NewDelegation(id, privateKey, now.Add(MaxDelegationTTL), NewSigner(ks, id))
+	assert.NoError(t, err)
+	assert.NoError(t, delegation.Verify(now))
+
+	withoutProof := delegation
+	withoutProof.KeySignature = ""
+	assert.EqualError(t, withoutProof.Verify(now), "delegation is not signed by the session key")
+
+	otherKey, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	foreign, err := NewDelegation(id, otherKey, now.Add(time.Hour), NewSigner(ks, id))
+	assert.NoError(t, err)
+	stolen := delegation
+	stolen.KeySignature = foreign.KeySignature
+	assert.EqualError(t, stolen.Verify(now), "delegation is not signed by the session key")
+
+	tooLong, err := NewDelegation(id, privateKey, now.Add(MaxDelegationTTL+time.Second), NewSigner(ks, id))
+	assert.NoError(t, err)
+	assert.Error(t, tooLong.Verify(now))
+
+	keys := NewSessionKeys(func(id Identity) Signer { return NewSigner(ks, id) }, 7*24*time.Hour)
+	keys.now = func() time.Time { return now }
+	key, err := keys.Get(id)
+	assert.NoError(t, err)
+	assert.True(t, now.Add(MaxDelegationTTL).Equal(key.Delegation().ValidUntil))
+	assert.NoError(t, key.Delegation().Verify(now))
+}
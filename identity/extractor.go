@@ -18,10 +18,14 @@
 package identity
 
 import (
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 )
 
+// textSignatureRecoveryOffset is added to recovery id of signatures made over personal message hash.
+const textSignatureRecoveryOffset = 27
+
 // Extractor is able to message signer's identity
 type Extractor interface {
 	Extract(message []byte, signature Signature) (Identity, error)
@@ -41,7 +45,15 @@ func (extractor *extractor) Extract(message []byte, signature Signature) (Identi
 		return Identity{}, errors.New("empty signature")
 	}
 
-	recoveredKey, err := crypto.Ecrecover(messageHash(message), signatureBytes)
+	hash := messageHash(message)
+	if len(signatureBytes) == crypto.SignatureLength && signatureBytes[crypto.RecoveryIDOffset] >= textSignatureRecoveryOffset {
+		// Personal message signed by a hardware wallet, see HardwareWallets.Sign.
+		hash = accounts.TextHash(message)
+		signatureBytes = append([]byte{}, signatureBytes...)
+		signatureBytes[crypto.RecoveryIDOffset] -= textSignatureRecoveryOffset
+	}
+
+	recoveredKey, err := crypto.Ecrecover(hash, signatureBytes)
 	if err != nil {
 		return Identity{}, err
 	}
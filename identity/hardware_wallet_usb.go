@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	"github.com/pkg/errors"
+)
+
+const (
+	// HardwareWalletLedger is a Ledger wallet connected over USB HID.
+	HardwareWalletLedger = "ledger"
+	// HardwareWalletTrezor is a Trezor wallet connected over USB HID.
+	HardwareWalletTrezor = "trezor"
+)
+
+// NewUSBHardwareWallets returns identity lookup over Ledger or Trezor wallets connected over USB.
+func NewUSBHardwareWallets(walletType string, derivationPaths []string) (*HardwareWallets, error) {
+	paths := make([]accounts.DerivationPath, 0, len(derivationPaths))
+	for _, p := range derivationPaths {
+		path, err := accounts.ParseDerivationPath(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid derivation path %q", p)
+		}
+		paths = append(paths, path)
+	}
+
+	switch walletType {
+	case HardwareWalletLedger:
+		hub, err := usbwallet.NewLedgerHub()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not start Ledger hub")
+		}
+		return NewHardwareWallets(hub, paths), nil
+	case HardwareWalletTrezor:
+		hub, err := usbwallet.NewTrezorHubWithHID()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not start Trezor hub")
+		}
+		return NewHardwareWallets(hub, paths), nil
+	default:
+		return nil, fmt.Errorf("unsupported hardware wallet %q", walletType)
+	}
+}
@@ -44,6 +44,7 @@ type ethKeystore interface {
 	Find(a accounts.Account) (accounts.Account, error)
 	Export(a accounts.Account, passphrase, newPassphrase string) ([]byte, error)
 	Import(keyJSON []byte, passphrase, newPassphrase string) (accounts.Account, error)
+	ImportECDSA(priv *ecdsa.PrivateKey, passphrase string) (accounts.Account, error)
 }
 
 // NewKeystoreFilesystem create new keystore, which keeps keys in filesystem.
@@ -18,6 +18,7 @@
 package identity
 
 import (
+	"crypto/ecdsa"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/accounts"
@@ -136,6 +137,11 @@ func (ekm *ethKeystoreMock) Import(keyJSON []byte, passphrase, newPassphrase str
 	return ekm.account, nil
 }
 
+func (ekm *ethKeystoreMock) ImportECDSA(priv *ecdsa.PrivateKey, passphrase string) (accounts.Account, error) {
+	ekm.account = accounts.Account{Address: crypto.PubkeyToAddress(priv.PublicKey)}
+	return ekm.account, nil
+}
+
 func (ekm *ethKeystoreMock) Accounts() []accounts.Account {
 	return []accounts.Account{ekm.account}
 }
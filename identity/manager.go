@@ -76,14 +76,24 @@ func NewIdentityManager(keystore keystore, eventBus eventbus.EventBus, residentC
 	}
 }
 
-// GetUnlockedIdentity retrieves unlocked identity
+// GetUnlockedIdentity retrieves unlocked identity, the first one is returned when several are unlocked
 func (idm *identityManager) GetUnlockedIdentity() (Identity, bool) {
+	unlocked := idm.GetUnlockedIdentities()
+	if len(unlocked) == 0 {
+		return Identity{}, false
+	}
+	return unlocked[0], true
+}
+
+// GetUnlockedIdentities retrieves all unlocked identities, services may run under any of them
+func (idm *identityManager) GetUnlockedIdentities() []Identity {
+	var unlocked []Identity
 	for _, identity := range idm.GetIdentities() {
 		if idm.IsUnlocked(identity.Address) {
-			return identity, true
+			unlocked = append(unlocked, identity)
 		}
 	}
-	return Identity{}, false
+	return unlocked
 }
 
 // IsUnlocked checks if the given identity is unlocked or not
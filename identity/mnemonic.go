@@ -0,0 +1,205 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/ethereum/go-ethereum/accounts"
+	ethKs "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/scrypt"
+)
+
+// DefaultDerivationPath is the BIP-44 path of the first Ethereum account.
+const DefaultDerivationPath = "m/44'/60'/0'/0/0"
+
+const (
+	mnemonicEntropyBits = 256
+	privateKeyLength    = 32
+
+	// Backup is encoded as version, random salt, encrypted key and GCM tag,
+	// split into two chunks of BIP-39 entropy, each of them encoded as 24 words.
+	backupVersion      = 1
+	backupSaltLength   = 15
+	backupTagLength    = 16
+	backupNonceLength  = 12
+	backupLength       = 1 + backupSaltLength + privateKeyLength + backupTagLength
+	backupChunkLength  = 32
+	backupChunkWords   = 24
+	backupScryptR      = 8
+	backupCipherKeyLen = 32
+)
+
+// Backup key is derived with the same scrypt cost as keys in the standard keystore.
+var (
+	backupScryptN = ethKs.StandardScryptN
+	backupScryptP = ethKs.StandardScryptP
+)
+
+// ErrBackupPassphrase is returned when the backup can not be decrypted with the given passphrase.
+var ErrBackupPassphrase = errors.New("could not decrypt backup, wrong passphrase")
+
+// NewMnemonic generates a new 24 words BIP-39 mnemonic.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// KeyFromMnemonic derives a private key from BIP-39 mnemonic and optional password at BIP-44 derivation path.
+func KeyFromMnemonic(mnemonic, password, derivationPath string) (*ecdsa.PrivateKey, error) {
+	path, err := accounts.ParseDerivationPath(derivationPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid derivation path")
+	}
+
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid mnemonic")
+	}
+	defer zeroBytes(seed)
+
+	return deriveKey(seed, path)
+}
+
+// deriveKey derives BIP-32 private key of the given path from the seed.
+func deriveKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	key, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not derive master key")
+	}
+
+	for _, index := range path {
+		child, err := key.Derive(index)
+		key.Zero()
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not derive key at %s", path)
+		}
+		key = child
+	}
+	defer key.Zero()
+
+	privateKey, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.ToECDSA(privateKey.Serialize())
+}
+
+// NewBackupMnemonic encodes the private key as 48 words mnemonic encrypted with the passphrase.
+// Unlike BIP-39 mnemonics, it can hold keys which were not derived from a mnemonic.
+func NewBackupMnemonic(key *ecdsa.PrivateKey, passphrase string) (string, error) {
+	backup := make([]byte, backupLength)
+	defer zeroBytes(backup)
+
+	backup[0] = backupVersion
+	salt := backup[1 : 1+backupSaltLength]
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.Wrap(err, "could not generate backup salt")
+	}
+
+	aead, nonce, err := backupCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+
+	plain := math.PaddedBigBytes(key.D, privateKeyLength)
+	defer zeroBytes(plain)
+	aead.Seal(backup[:1+backupSaltLength], nonce, plain, backup[:1])
+
+	words := make([]string, 0, backupLength/backupChunkLength)
+	for chunk := 0; chunk < backupLength; chunk += backupChunkLength {
+		mnemonic, err := bip39.NewMnemonic(backup[chunk : chunk+backupChunkLength])
+		if err != nil {
+			return "", err
+		}
+		words = append(words, mnemonic)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// KeyFromBackupMnemonic decrypts the private key from the mnemonic created with NewBackupMnemonic.
+func KeyFromBackupMnemonic(mnemonic, passphrase string) (*ecdsa.PrivateKey, error) {
+	words := strings.Fields(mnemonic)
+	if len(words) != backupLength/backupChunkLength*backupChunkWords {
+		return nil, errors.New("mnemonic is not an identity backup")
+	}
+
+	backup := make([]byte, 0, backupLength)
+	defer func() { zeroBytes(backup) }()
+	for chunk := 0; chunk < len(words); chunk += backupChunkWords {
+		entropy, err := bip39.EntropyFromMnemonic(strings.Join(words[chunk:chunk+backupChunkWords], " "))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid mnemonic")
+		}
+		backup = append(backup, entropy...)
+	}
+	if len(backup) != backupLength || backup[0] != backupVersion {
+		return nil, errors.New("unsupported identity backup version")
+	}
+
+	aead, nonce, err := backupCipher(passphrase, backup[1:1+backupSaltLength])
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := aead.Open(nil, nonce, backup[1+backupSaltLength:], backup[:1])
+	if err != nil {
+		return nil, ErrBackupPassphrase
+	}
+	defer zeroBytes(plain)
+	return crypto.ToECDSA(plain)
+}
+
+// backupCipher derives AES-GCM key and nonce from the passphrase.
+// Salt is random for every backup, so a key and nonce pair is never reused.
+func backupCipher(passphrase string, salt []byte) (cipher.AEAD, []byte, error) {
+	derived, err := scrypt.Key([]byte(passphrase), salt, backupScryptN, backupScryptR, backupScryptP, backupCipherKeyLen+backupNonceLength)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer zeroBytes(derived[:backupCipherKeyLen])
+
+	block, err := aes.NewCipher(derived[:backupCipherKeyLen])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCMWithTagSize(block, backupTagLength)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, derived[backupCipherKeyLen:], nil
+}
+
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
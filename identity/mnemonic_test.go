@@ -0,0 +1,119 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tyler-smith/go-bip39"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic()
+	require.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 24)
+
+	_, err = KeyFromMnemonic(mnemonic, "", DefaultDerivationPath)
+	assert.NoError(t, err)
+}
+
+func TestKeyFromMnemonic(t *testing.T) {
+	key, err := KeyFromMnemonic(testMnemonic, "", DefaultDerivationPath)
+	require.NoError(t, err)
+	assert.Equal(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+	key, err = KeyFromMnemonic(testMnemonic, "", "m/44'/60'/0'/0/1")
+	require.NoError(t, err)
+	assert.Equal(t, "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0", crypto.PubkeyToAddress(key.PublicKey).Hex())
+}
+
+func TestKeyFromMnemonic_Invalid(t *testing.T) {
+	_, err := KeyFromMnemonic("abandon abandon abandon", "", DefaultDerivationPath)
+	assert.Error(t, err)
+
+	_, err = KeyFromMnemonic(testMnemonic, "", "m/invalid")
+	assert.Error(t, err)
+}
+
+func TestBackupMnemonic(t *testing.T) {
+	mnemonic, err := NewBackupMnemonic(encryptionKey, "backup")
+	require.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 48)
+
+	restored, err := KeyFromBackupMnemonic(mnemonic, "backup")
+	require.NoError(t, err)
+	assert.Equal(t, encryptionKey.D, restored.D)
+
+	_, err = KeyFromBackupMnemonic(mnemonic, "wrong")
+	assert.Equal(t, ErrBackupPassphrase, err)
+
+	_, err = KeyFromBackupMnemonic(testMnemonic, "backup")
+	assert.Error(t, err, "12 words mnemonic can not hold a key")
+}
+
+func TestBackupMnemonic_UsesRandomSalt(t *testing.T) {
+	first, err := NewBackupMnemonic(encryptionKey, "backup")
+	require.NoError(t, err)
+	second, err := NewBackupMnemonic(encryptionKey, "backup")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestBackupMnemonic_Tampered(t *testing.T) {
+	mnemonic, err := NewBackupMnemonic(encryptionKey, "backup")
+	require.NoError(t, err)
+
+	words := strings.Fields(mnemonic)
+	backup, err := bip39.EntropyFromMnemonic(strings.Join(words[24:], " "))
+	require.NoError(t, err)
+	backup[0] ^= 1
+	tampered, err := bip39.NewMnemonic(backup)
+	require.NoError(t, err)
+
+	_, err = KeyFromBackupMnemonic(strings.Join(words[:24], " ")+" "+tampered, "backup")
+	assert.Equal(t, ErrBackupPassphrase, err)
+}
+
+func TestMoverMnemonicImport(t *testing.T) {
+	ks := &ethKeystoreMock{}
+	m := NewMover(ks, eventbus.New(), fakeSignerFactory)
+
+	id, err := m.ImportMnemonic(testMnemonic, "", DefaultDerivationPath, "pass")
+	require.NoError(t, err)
+	assert.Equal(t, "0x9858effd232b4033e47d90003d41ec34ecaeda94", id.Address)
+	assert.True(t, ks.unlocked)
+
+	mnemonic, err := NewBackupMnemonic(encryptionKey, "backup")
+	require.NoError(t, err)
+
+	_, err = m.ImportBackupMnemonic(mnemonic, "backup", "0x0000000000000000000000000000000000000001", "pass")
+	assert.Error(t, err)
+
+	id, err = m.ImportBackupMnemonic(mnemonic, "backup", encryptionAddress.Hex(), "pass")
+	require.NoError(t, err)
+	assert.Equal(t, FromAddress(encryptionAddress.Hex()), id)
+}
@@ -18,11 +18,15 @@
 package identity
 
 import (
+	"crypto/ecdsa"
 	"errors"
+	"fmt"
 
 	"github.com/mysteriumnetwork/node/eventbus"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	ethKs "github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // Mover is wrapper on both the Exporter and Importer
@@ -37,6 +41,7 @@ type moverKeystore interface {
 	Find(a accounts.Account) (accounts.Account, error)
 	Export(a accounts.Account, passphrase, newPassphrase string) ([]byte, error)
 	Import(keyJSON []byte, passphrase, newPassphrase string) (accounts.Account, error)
+	ImportECDSA(priv *ecdsa.PrivateKey, passphrase string) (accounts.Account, error)
 }
 
 type moverIdentityHandler interface {
@@ -75,7 +80,46 @@ func (i *Importer) Import(blob []byte, currPass, newPass string) (Identity, erro
 		return Identity{}, err
 	}
 
-	if err := i.ks.Unlock(acc, newPass); err != nil {
+	return i.imported(acc, newPass)
+}
+
+// ImportMnemonic imports an identity derived from BIP-39 mnemonic and password at BIP-44 derivation path.
+func (i *Importer) ImportMnemonic(mnemonic, password, derivationPath, newPass string) (Identity, error) {
+	key, err := KeyFromMnemonic(mnemonic, password, derivationPath)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	return i.importKey(key, newPass)
+}
+
+// ImportBackupMnemonic imports an identity from the encrypted mnemonic backup.
+// When address is given, it is used to check that the backup passphrase is correct.
+func (i *Importer) ImportBackupMnemonic(mnemonic, backupPass, address, newPass string) (Identity, error) {
+	key, err := KeyFromBackupMnemonic(mnemonic, backupPass)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	restored := FromAddress(crypto.PubkeyToAddress(key.PublicKey).Hex())
+	if address != "" && restored != FromAddress(address) {
+		return Identity{}, fmt.Errorf("backup restores identity %s instead of %s, check the backup passphrase", restored.Address, address)
+	}
+
+	return i.importKey(key, newPass)
+}
+
+func (i *Importer) importKey(key *ecdsa.PrivateKey, newPass string) (Identity, error) {
+	acc, err := i.ks.ImportECDSA(key, newPass)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	return i.imported(acc, newPass)
+}
+
+func (i *Importer) imported(acc accounts.Account, pass string) (Identity, error) {
+	if err := i.ks.Unlock(acc, pass); err != nil {
 		return Identity{}, err
 	}
 
@@ -106,3 +150,19 @@ func (e *Exporter) Export(address, currPass, newPass string) ([]byte, error) {
 
 	return e.ks.Export(acc, currPass, newPass)
 }
+
+// ExportMnemonic exports a given identity as a mnemonic encrypted with the backup passphrase.
+func (e *Exporter) ExportMnemonic(address, currPass, backupPass string) (string, error) {
+	blob, err := e.Export(address, currPass, currPass)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := ethKs.DecryptKey(blob, currPass)
+	if err != nil {
+		return "", err
+	}
+	defer zeroKey(key.PrivateKey)
+
+	return NewBackupMnemonic(key.PrivateKey, backupPass)
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package remote
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+// Options configure the connection to the remote signing service.
+type Options struct {
+	// Address of the signing service, e.g. signer.example.com:8443.
+	Address string
+	// CACert is a path to PEM encoded CA certificate of the signing service, system roots are used if empty.
+	CACert string
+	// ClientCert and ClientKey are paths to PEM encoded certificate and key the node authenticates with
+	// using mutual TLS. Both are optional, the connection uses server-authenticated TLS without them.
+	ClientCert string
+	ClientKey  string
+	Timeout    time.Duration
+}
+
+// Client calls the remote signing service over gRPC secured with TLS.
+type Client struct {
+	conn    *grpc.ClientConn
+	signer  pb.RemoteSignerClient
+	timeout time.Duration
+}
+
+// NewClient returns a new remote signer client. The connection is established lazily on the first call.
+func NewClient(opts Options) (*Client, error) {
+	tlsConfig, err := clientTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(opts.Address, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not set up remote signer connection")
+	}
+
+	return &Client{
+		conn:    conn,
+		signer:  pb.NewRemoteSignerClient(conn),
+		timeout: opts.Timeout,
+	}, nil
+}
+
+func clientTLSConfig(opts Options) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load client certificate")
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.CACert != "" {
+		caPEM, err := ioutil.ReadFile(opts.CACert)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read CA certificate")
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("could not parse CA certificate")
+		}
+		config.RootCAs = roots
+	}
+
+	return config, nil
+}
+
+// Sign asks the remote signing service to sign the message with the identity key.
+func (c *Client) Sign(ctx context.Context, id identity.Identity, message []byte) (identity.Signature, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	res, err := c.signer.Sign(ctx, &pb.SignRequest{Identity: id.Address, Message: message})
+	if err != nil {
+		s := status.Convert(err)
+		return identity.Signature{}, errors.Errorf("remote signer failed with gRPC status %s: %s", s.Code(), s.Message())
+	}
+
+	return identity.SignatureBytes(res.Signature), nil
+}
+
+// Close closes the connection to the remote signing service.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+type signer struct {
+	client *Client
+	id     identity.Identity
+}
+
+// NewSignerFactory returns SignerFactory which signs all identities with the remote signing service.
+func NewSignerFactory(client *Client) identity.SignerFactory {
+	return func(id identity.Identity) identity.Signer {
+		return &signer{client: client, id: id}
+	}
+}
+
+// Sign signs the message with the remote signing service.
+func (s *signer) Sign(message []byte) (identity.Signature, error) {
+	return s.client.Sign(context.Background(), s.id, message)
+}
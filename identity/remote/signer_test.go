@@ -0,0 +1,188 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package remote
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+type mockSignerServer struct {
+	pb.UnimplementedRemoteSignerServer
+	sign func(req *pb.SignRequest) (*pb.SignResponse, error)
+}
+
+func (s *mockSignerServer) Sign(_ context.Context, req *pb.SignRequest) (*pb.SignResponse, error) {
+	return s.sign(req)
+}
+
+type testPKI struct {
+	dir    string
+	ca     *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+	caPath string
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pki := &testPKI{dir: t.TempDir(), ca: ca, caKey: key}
+	pki.caPath = pki.write(t, "ca.pem", "CERTIFICATE", der)
+	return pki
+}
+
+// issue returns paths to a certificate and key signed by the test CA.
+func (p *testPKI) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, p.ca, &key.PublicKey, p.caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return p.write(t, name+".pem", "CERTIFICATE", der), p.write(t, name+"-key.pem", "EC PRIVATE KEY", keyDER)
+}
+
+func (p *testPKI) write(t *testing.T, name, blockType string, der []byte) string {
+	path := filepath.Join(p.dir, name)
+	require.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+	return path
+}
+
+// startSignerServer starts a signing service which requires clients to authenticate with a certificate of the test CA.
+func startSignerServer(t *testing.T, pki *testPKI, sign func(req *pb.SignRequest) (*pb.SignResponse, error)) string {
+	certPath, keyPath := pki.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(pki.ca)
+
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	pb.RegisterRemoteSignerServer(server, &mockSignerServer{sign: sign})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestSigner_Sign(t *testing.T) {
+	pki := newTestPKI(t)
+	address := startSignerServer(t, pki, func(req *pb.SignRequest) (*pb.SignResponse, error) {
+		assert.Equal(t, "0x1", req.Identity)
+		assert.Equal(t, []byte("message"), req.Message)
+		return &pb.SignResponse{Signature: []byte("signature")}, nil
+	})
+	clientCert, clientKey := pki.issue(t, "client", x509.ExtKeyUsageClientAuth)
+
+	client, err := NewClient(Options{Address: address, CACert: pki.caPath, ClientCert: clientCert, ClientKey: clientKey, Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer client.Close()
+
+	signature, err := NewSignerFactory(client)(identity.FromAddress("0x1")).Sign([]byte("message"))
+	require.NoError(t, err)
+	assert.Equal(t, identity.SignatureBytes([]byte("signature")), signature)
+}
+
+func TestSigner_SignFailed(t *testing.T) {
+	pki := newTestPKI(t)
+	address := startSignerServer(t, pki, func(req *pb.SignRequest) (*pb.SignResponse, error) {
+		return nil, status.Error(codes.PermissionDenied, "identity is not allowed")
+	})
+	clientCert, clientKey := pki.issue(t, "client", x509.ExtKeyUsageClientAuth)
+
+	client, err := NewClient(Options{Address: address, CACert: pki.caPath, ClientCert: clientCert, ClientKey: clientKey, Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = NewSignerFactory(client)(identity.FromAddress("0x1")).Sign([]byte("message"))
+	assert.EqualError(t, err, "remote signer failed with gRPC status PermissionDenied: identity is not allowed")
+}
+
+func TestSigner_SignWithoutClientCertificateRejected(t *testing.T) {
+	pki := newTestPKI(t)
+	address := startSignerServer(t, pki, func(req *pb.SignRequest) (*pb.SignResponse, error) {
+		return &pb.SignResponse{Signature: []byte("signature")}, nil
+	})
+
+	client, err := NewClient(Options{Address: address, CACert: pki.caPath, Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = NewSignerFactory(client)(identity.FromAddress("0x1")).Sign([]byte("message"))
+	assert.Error(t, err)
+}
+
+func TestNewClient_RequiresCertificateWithKey(t *testing.T) {
+	pki := newTestPKI(t)
+	clientCert, _ := pki.issue(t, "client", x509.ExtKeyUsageClientAuth)
+
+	_, err := NewClient(Options{Address: "127.0.0.1:1", CACert: pki.caPath, ClientCert: clientCert})
+	assert.Error(t, err)
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// AppTopicIdentityRotated is published once provider migrates to a new identity key.
+const AppTopicIdentityRotated = "identity-rotated"
+
+// AppEventIdentityRotated represents the payload that is sent on identity rotation.
+type AppEventIdentityRotated struct {
+	ChainID  int64
+	Rotation Rotation
+}
+
+// Rotation is a migration message from the old identity key to the new one.
+// It is signed by both keys: the old one authorizes the migration and the new one proves its possession.
+type Rotation struct {
+	From          string    `json:"from"`
+	To            string    `json:"to"`
+	RotatedAt     time.Time `json:"rotated_at"`
+	FromSignature string    `json:"from_signature"`
+	ToSignature   string    `json:"to_signature"`
+}
+
+// NewRotation creates a migration message signed by both identities.
+func NewRotation(from, to Identity, signerFactory SignerFactory, rotatedAt time.Time) (Rotation, error) {
+	rotation := Rotation{
+		From:      strings.ToLower(from.Address),
+		To:        strings.ToLower(to.Address),
+		RotatedAt: rotatedAt.UTC().Truncate(time.Second),
+	}
+
+	fromSignature, err := signerFactory(from).Sign(rotation.Message())
+	if err != nil {
+		return Rotation{}, fmt.Errorf("could not sign rotation with old identity: %w", err)
+	}
+	toSignature, err := signerFactory(to).Sign(rotation.Message())
+	if err != nil {
+		return Rotation{}, fmt.Errorf("could not sign rotation with new identity: %w", err)
+	}
+
+	rotation.FromSignature = fromSignature.Base64()
+	rotation.ToSignature = toSignature.Base64()
+	return rotation, nil
+}
+
+// Message returns the signed part of the rotation.
+func (r Rotation) Message() []byte {
+	return []byte(fmt.Sprintf("Mysterium identity rotation\nfrom: %s\nto: %s\nat: %d", r.From, r.To, r.RotatedAt.Unix()))
+}
+
+// Verify checks that the rotation is signed by both identities.
+func (r Rotation) Verify() error {
+	if ok, _ := NewVerifierIdentity(FromAddress(r.From)).Verify(r.Message(), SignatureBase64(r.FromSignature)); !ok {
+		return errors.New("rotation is not signed by the old identity")
+	}
+	if ok, _ := NewVerifierIdentity(FromAddress(r.To)).Verify(r.Message(), SignatureBase64(r.ToSignature)); !ok {
+		return errors.New("rotation is not signed by the new identity")
+	}
+	return nil
+}
+
+// Rotator migrates identities to new keys. Old identities stay unlocked,
+// so services and sessions running under them keep running until they are stopped.
+type Rotator struct {
+	manager       Manager
+	signerFactory SignerFactory
+	publisher     eventbus.Publisher
+	file          string
+	now           func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewRotator returns a new rotator keeping rotations in the given directory.
+func NewRotator(manager Manager, signerFactory SignerFactory, publisher eventbus.Publisher, dir string) *Rotator {
+	return &Rotator{
+		manager:       manager,
+		signerFactory: signerFactory,
+		publisher:     publisher,
+		file:          filepath.Join(dir, "rotations.json"),
+		now:           time.Now,
+	}
+}
+
+// Rotate creates a new identity protected by the given passphrase and migrates the unlocked identity to it.
+func (r *Rotator) Rotate(chainID int64, from Identity, passphrase string) (Rotation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.manager.IsUnlocked(from.Address) {
+		return Rotation{}, fmt.Errorf("identity %s is locked", from.Address)
+	}
+	if to, ok := r.successor(from.Address); ok {
+		return Rotation{}, fmt.Errorf("identity %s is already rotated to %s", from.Address, to.Address)
+	}
+
+	to, err := r.manager.CreateNewIdentity(passphrase)
+	if err != nil {
+		return Rotation{}, fmt.Errorf("could not create new identity: %w", err)
+	}
+	if err := r.manager.Unlock(chainID, to.Address, passphrase); err != nil {
+		return Rotation{}, fmt.Errorf("could not unlock new identity: %w", err)
+	}
+
+	rotation, err := NewRotation(from, to, r.signerFactory, r.now())
+	if err != nil {
+		return Rotation{}, err
+	}
+
+	rotations, err := r.read()
+	if err != nil {
+		return Rotation{}, err
+	}
+	if err := r.write(append(rotations, rotation)); err != nil {
+		return Rotation{}, err
+	}
+
+	r.publisher.Publish(AppTopicIdentityRotated, AppEventIdentityRotated{
+		ChainID:  chainID,
+		Rotation: rotation,
+	})
+	return rotation, nil
+}
+
+// Rotations returns all rotations of the identity and its successors in the order they happened.
+func (r *Rotator) Rotations(address string) ([]Rotation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rotations, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var chain []Rotation
+	current := strings.ToLower(address)
+	for _, rotation := range rotations {
+		if rotation.From == current {
+			chain = append(chain, rotation)
+			current = rotation.To
+		}
+	}
+	return chain, nil
+}
+
+// Successor returns the identity the given one was rotated to.
+func (r *Rotator) Successor(address string) (Identity, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.successor(address)
+}
+
+func (r *Rotator) successor(address string) (Identity, bool) {
+	rotations, err := r.read()
+	if err != nil {
+		return Identity{}, false
+	}
+	for _, rotation := range rotations {
+		if rotation.From == strings.ToLower(address) {
+			return FromAddress(rotation.To), true
+		}
+	}
+	return Identity{}, false
+}
+
+func (r *Rotator) read() ([]Rotation, error) {
+	data, err := os.ReadFile(r.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read rotations: %w", err)
+	}
+
+	var rotations []Rotation
+	if err := json.Unmarshal(data, &rotations); err != nil {
+		return nil, fmt.Errorf("could not parse rotations: %w", err)
+	}
+	return rotations, nil
+}
+
+func (r *Rotator) write(rotations []Rotation) error {
+	data, err := json.MarshalIndent(rotations, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.file, data, 0600)
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+func TestRotator_Rotate(t *testing.T) {
+	ks := NewMockKeystoreWith(MockKeys)
+	bus := eventbus.New()
+	manager := NewIdentityManager(ks, bus, NewResidentCountry(bus, newMockLocationResolver("LT")))
+	signerFactory := func(id Identity) Signer { return NewSigner(ks, id) }
+
+	var rotated AppEventIdentityRotated
+	err := bus.Subscribe(AppTopicIdentityRotated, func(e AppEventIdentityRotated) { rotated = e })
+	assert.NoError(t, err)
+
+	from := FromAddress("0x53a835143c0ef3bbcbfa796d7eb738ca7dd28f68")
+	rotator := NewRotator(manager, signerFactory, bus, t.TempDir())
+	rotator.now = func() time.Time { return time.Unix(1660000000, 0) }
+
+	_, err = rotator.Rotate(1, from, "new")
+	assert.EqualError(t, err, "identity 0x53a835143c0ef3bbcbfa796d7eb738ca7dd28f68 is locked")
+
+	assert.NoError(t, manager.Unlock(1, from.Address, ""))
+	rotation, err := rotator.Rotate(1, from, "new")
+	assert.NoError(t, err)
+	assert.Equal(t, from.Address, rotation.From)
+	assert.NotEqual(t, from.Address, rotation.To)
+	assert.NoError(t, rotation.Verify())
+	assert.Equal(t, AppEventIdentityRotated{ChainID: 1, Rotation: rotation}, rotated)
+
+	assert.True(t, manager.IsUnlocked(from.Address))
+	assert.True(t, manager.IsUnlocked(rotation.To))
+
+	successor, ok := rotator.Successor(from.Address)
+	assert.True(t, ok)
+	assert.Equal(t, FromAddress(rotation.To), successor)
+
+	rotations, err := rotator.Rotations(from.Address)
+	assert.NoError(t, err)
+	assert.Equal(t, []Rotation{rotation}, rotations)
+
+	_, err = rotator.Rotate(1, from, "new")
+	assert.Error(t, err)
+}
+
+func TestRotation_VerifyFailsWhenTampered(t *testing.T) {
+	ks := NewMockKeystoreWith(MockKeys)
+	from := FromAddress("0x53a835143c0ef3bbcbfa796d7eb738ca7dd28f68")
+	assert.NoError(t, ks.Unlock(identityToAccount(from), ""))
+	to, err := ks.NewAccount("")
+	assert.NoError(t, err)
+	assert.NoError(t, ks.Unlock(to, ""))
+
+	rotation, err := NewRotation(from, accountToIdentity(to), func(id Identity) Signer { return NewSigner(ks, id) }, time.Now())
+	assert.NoError(t, err)
+	assert.NoError(t, rotation.Verify())
+
+	tampered := rotation
+	tampered.To = "0x0000000000000000000000000000000000000001"
+	assert.EqualError(t, tampered.Verify(), "rotation is not signed by the old identity")
+
+	tampered = rotation
+	tampered.ToSignature = rotation.FromSignature
+	assert.EqualError(t, tampered.Verify(), "rotation is not signed by the new identity")
+}
@@ -31,18 +31,22 @@ type handler struct {
 	manager       identity.Manager
 	cache         identity.IdentityCacheInterface
 	signerFactory identity.SignerFactory
+	unlock        identity.UnlockProvider
 }
 
-//NewHandler creates new identity handler used by node
+// NewHandler creates new identity handler used by node.
+// Passphrases are taken from the unlock provider when it is set and passphrase is not given.
 func NewHandler(
 	manager identity.Manager,
 	cache identity.IdentityCacheInterface,
 	signerFactory identity.SignerFactory,
+	unlock identity.UnlockProvider,
 ) *handler {
 	return &handler{
 		manager:       manager,
 		cache:         cache,
 		signerFactory: signerFactory,
+		unlock:        unlock,
 	}
 }
 
@@ -86,7 +90,7 @@ func (h *handler) useExisting(address, passphrase string, chainID int64) (id ide
 		return id, err
 	}
 
-	if err = h.manager.Unlock(chainID, id.Address, passphrase); err != nil {
+	if err = h.unlockIdentity(chainID, id.Address, passphrase); err != nil {
 		return id, fmt.Errorf("failed to unlock identity: %w", err)
 	}
 
@@ -102,7 +106,7 @@ func (h *handler) useLast(passphrase string, chainID int64) (identity identity.I
 	}
 	log.Debug().Msg("Found identity in cache: " + identity.Address)
 
-	if err = h.manager.Unlock(chainID, identity.Address, passphrase); err != nil {
+	if err = h.unlockIdentity(chainID, identity.Address, passphrase); err != nil {
 		return identity, errors.Wrap(err, "failed to unlock identity")
 	}
 	log.Debug().Msg("Unlocked identity: " + identity.Address)
@@ -118,10 +122,39 @@ func (h *handler) useNew(passphrase string, chainID int64) (id identity.Identity
 		return id, errors.Wrap(err, "failed to create identity")
 	}
 
-	if err = h.manager.Unlock(chainID, id.Address, passphrase); err != nil {
+	if err = h.unlockIdentity(chainID, id.Address, passphrase); err != nil {
 		return id, errors.Wrap(err, "failed to unlock identity")
 	}
 
 	err = h.cache.StoreIdentity(id)
 	return id, errors.Wrap(err, "failed to store identity in cache")
 }
+
+// unlockIdentity unlocks the identity with the given passphrase or with the one kept by the unlock provider.
+// Given passphrase is stored in the unlock provider once it unlocks the identity.
+func (h *handler) unlockIdentity(chainID int64, address, passphrase string) error {
+	if h.unlock == nil {
+		return h.manager.Unlock(chainID, address, passphrase)
+	}
+
+	if passphrase == "" {
+		stored, err := h.unlock.Passphrase(address)
+		if err == nil {
+			if err = h.manager.Unlock(chainID, address, stored); err == nil {
+				log.Debug().Msg("Unlocked identity with stored passphrase: " + address)
+				return nil
+			}
+			log.Warn().Err(err).Msg("Stored passphrase does not unlock identity: " + address)
+		} else if !errors.Is(err, identity.ErrPassphraseNotFound) {
+			log.Warn().Err(err).Msg("Failed to get stored passphrase for identity: " + address)
+		}
+	}
+
+	if err := h.manager.Unlock(chainID, address, passphrase); err != nil {
+		return err
+	}
+	if err := h.unlock.StorePassphrase(address, passphrase); err != nil {
+		log.Warn().Err(err).Msg("Failed to store passphrase for identity: " + address)
+	}
+	return nil
+}
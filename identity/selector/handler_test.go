@@ -36,7 +36,7 @@ func TestUseOrCreateSucceeds(t *testing.T) {
 	registry := &mockRegistry{}
 	cache := identity.NewIdentityCacheFake()
 
-	handler := NewHandler(identityManager, cache, fakeSignerFactory)
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, nil)
 
 	id, err := handler.UseOrCreate(existingIdentity.Address, "pass", chainID)
 	assert.Equal(t, existingIdentity, id)
@@ -53,7 +53,7 @@ func TestUseOrCreateFailsWhenUnlockFails(t *testing.T) {
 	identityManager.MarkUnlockToFail()
 	cache := identity.NewIdentityCacheFake()
 
-	handler := NewHandler(identityManager, cache, fakeSignerFactory)
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, nil)
 
 	_, err := handler.UseOrCreate(existingIdentity.Address, "pass", chainID)
 	assert.Error(t, err)
@@ -68,7 +68,7 @@ func TestUseOrCreateReturnsFirstIdentity(t *testing.T) {
 	cache := identity.NewIdentityCacheFake()
 	_ = cache.StoreIdentity(existingIdentity)
 
-	handler := NewHandler(identityManager, cache, fakeSignerFactory)
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, nil)
 
 	id, err := handler.UseOrCreate("", "pass", chainID)
 	assert.Equal(t, existingIdentity, id)
@@ -79,7 +79,7 @@ func TestUseOrCreateFailsWhenIdentityNotFound(t *testing.T) {
 	identityManager := identity.NewIdentityManagerFake([]identity.Identity{existingIdentity}, newIdentity)
 	cache := identity.NewIdentityCacheFake()
 
-	handler := NewHandler(identityManager, cache, fakeSignerFactory)
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, nil)
 	_, err := handler.UseOrCreate("does-not-exist", "pass", chainID)
 	assert.NotNil(t, err)
 }
@@ -88,7 +88,7 @@ func TestUseFailsWhenIdentityNotFound(t *testing.T) {
 	identityManager := identity.NewIdentityManagerFake([]identity.Identity{existingIdentity}, newIdentity)
 	cache := identity.NewIdentityCacheFake()
 
-	handler := NewHandler(identityManager, cache, fakeSignerFactory)
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, nil)
 
 	_, err := handler.UseOrCreate("does-not-exist", "pass", chainID)
 	assert.NotNil(t, err)
@@ -102,7 +102,7 @@ func TestUseLastSucceeds(t *testing.T) {
 	fakeIdentity := identity.FromAddress("abc")
 	_ = cache.StoreIdentity(fakeIdentity)
 
-	handler := NewHandler(identityManager, cache, fakeSignerFactory)
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, nil)
 
 	id, err := handler.useLast("pass", chainID)
 	assert.Equal(t, fakeIdentity, id)
@@ -124,7 +124,7 @@ func TestUseLastFailsWhenUnlockFails(t *testing.T) {
 	fakeIdentity := identity.FromAddress("abc")
 	_ = cache.StoreIdentity(fakeIdentity)
 
-	handler := NewHandler(identityManager, cache, fakeSignerFactory)
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, nil)
 
 	_, err := handler.useLast("pass", chainID)
 	assert.Error(t, err)
@@ -140,7 +140,7 @@ func TestUseNewSucceeds(t *testing.T) {
 	identityManager := identity.NewIdentityManagerFake([]identity.Identity{existingIdentity}, newIdentity)
 	cache := identity.NewIdentityCacheFake()
 
-	handler := NewHandler(identityManager, cache, fakeSignerFactory)
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, nil)
 
 	id, err := handler.useNew("pass", chainID)
 	assert.Equal(t, newIdentity, id)
@@ -156,7 +156,7 @@ func TestUseNewFailsWhenUnlockFails(t *testing.T) {
 	identityManager.MarkUnlockToFail()
 	cache := identity.NewIdentityCacheFake()
 
-	handler := NewHandler(identityManager, cache, fakeSignerFactory)
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, nil)
 
 	_, err := handler.useNew("pass", chainID)
 	assert.Error(t, err)
@@ -166,6 +166,62 @@ func TestUseNewFailsWhenUnlockFails(t *testing.T) {
 	assert.Equal(t, chainID, identityManager.LastUnlockChainID)
 }
 
+func TestUseOrCreateUnlocksWithStoredPassphrase(t *testing.T) {
+	identityManager := identity.NewIdentityManagerFake([]identity.Identity{existingIdentity}, newIdentity)
+	cache := identity.NewIdentityCacheFake()
+	unlock := &fakeUnlockProvider{passphrases: map[string]string{existingIdentity.Address: "stored"}}
+
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, unlock)
+
+	id, err := handler.UseOrCreate(existingIdentity.Address, "", chainID)
+	assert.NoError(t, err)
+	assert.Equal(t, existingIdentity, id)
+	assert.Equal(t, "stored", identityManager.LastUnlockPassphrase)
+}
+
+func TestUseOrCreateStoresGivenPassphrase(t *testing.T) {
+	identityManager := identity.NewIdentityManagerFake([]identity.Identity{existingIdentity}, newIdentity)
+	cache := identity.NewIdentityCacheFake()
+	unlock := &fakeUnlockProvider{passphrases: map[string]string{existingIdentity.Address: "stored"}}
+
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, unlock)
+
+	_, err := handler.UseOrCreate(existingIdentity.Address, "pass", chainID)
+	assert.NoError(t, err)
+	assert.Equal(t, "pass", identityManager.LastUnlockPassphrase)
+	assert.Equal(t, "pass", unlock.passphrases[existingIdentity.Address])
+}
+
+func TestUseOrCreateDoesNotStorePassphraseWhenUnlockFails(t *testing.T) {
+	identityManager := identity.NewIdentityManagerFake([]identity.Identity{existingIdentity}, newIdentity)
+	identityManager.MarkUnlockToFail()
+	cache := identity.NewIdentityCacheFake()
+	unlock := &fakeUnlockProvider{passphrases: map[string]string{}}
+
+	handler := NewHandler(identityManager, cache, fakeSignerFactory, unlock)
+
+	_, err := handler.UseOrCreate(existingIdentity.Address, "pass", chainID)
+	assert.Error(t, err)
+	assert.Empty(t, unlock.passphrases)
+}
+
+type fakeUnlockProvider struct {
+	passphrases map[string]string
+}
+
+func (fup *fakeUnlockProvider) Passphrase(address string) (string, error) {
+	passphrase, ok := fup.passphrases[address]
+	if !ok {
+		return "", identity.ErrPassphraseNotFound
+	}
+	return passphrase, nil
+}
+
+func (fup *fakeUnlockProvider) StorePassphrase(address, passphrase string) error {
+	fup.passphrases[address] = passphrase
+	return nil
+}
+
 type fakeSigner struct {
 }
 
@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrHardwareWalletNotFound is returned when no connected hardware wallet holds the identity.
+var ErrHardwareWalletNotFound = errors.New("hardware wallet holding the identity is not connected")
+
+// HardwareWallets looks up identities held on hardware wallets.
+type HardwareWallets struct {
+	backend accounts.Backend
+	paths   []accounts.DerivationPath
+
+	mu      sync.Mutex
+	wallets map[common.Address]accounts.Wallet
+}
+
+// NewHardwareWallets returns identity lookup over wallets of the given backend.
+// Only accounts at the given derivation paths are considered.
+func NewHardwareWallets(backend accounts.Backend, paths []accounts.DerivationPath) *HardwareWallets {
+	return &HardwareWallets{
+		backend: backend,
+		paths:   paths,
+		wallets: make(map[common.Address]accounts.Wallet),
+	}
+}
+
+// Contains checks if the identity is held on one of connected hardware wallets.
+func (hw *HardwareWallets) Contains(id Identity) bool {
+	_, err := hw.wallet(id)
+	return err == nil
+}
+
+// Sign signs the message as an Ethereum personal message with the identity key held on the hardware wallet.
+// Hardware wallets refuse to sign arbitrary hashes, so the message is prefixed as in personal_sign,
+// the returned signature has recovery id of 27 or 28 which lets Extractor pick the matching hash.
+func (hw *HardwareWallets) Sign(id Identity, message []byte) ([]byte, error) {
+	wallet, err := hw.wallet(id)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := wallet.SignText(identityToAccount(id), message)
+	if errors.Is(err, accounts.ErrNotSupported) {
+		return nil, errors.Wrapf(err, "hardware wallet %s can not sign node messages", wallet.URL())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("hardware wallet %s returned invalid signature length %d", wallet.URL(), len(signature))
+	}
+	if signature[crypto.RecoveryIDOffset] < textSignatureRecoveryOffset {
+		signature[crypto.RecoveryIDOffset] += textSignatureRecoveryOffset
+	}
+	return signature, nil
+}
+
+func (hw *HardwareWallets) wallet(id Identity) (accounts.Wallet, error) {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+
+	account := identityToAccount(id)
+	if wallet, ok := hw.wallets[account.Address]; ok && wallet.Contains(account) {
+		return wallet, nil
+	}
+	delete(hw.wallets, account.Address)
+
+	for _, wallet := range hw.backend.Wallets() {
+		if err := wallet.Open(""); err != nil && !errors.Is(err, accounts.ErrWalletAlreadyOpen) {
+			log.Warn().Err(err).Msgf("Could not open hardware wallet %s", wallet.URL())
+			continue
+		}
+
+		for _, path := range hw.paths {
+			derived, err := wallet.Derive(path, true)
+			if err != nil {
+				log.Warn().Err(err).Msgf("Could not derive account %s on hardware wallet %s", path, wallet.URL())
+				break
+			}
+			if derived.Address == account.Address {
+				hw.wallets[account.Address] = wallet
+				return wallet, nil
+			}
+		}
+	}
+
+	return nil, ErrHardwareWalletNotFound
+}
+
+type hardwareSigner struct {
+	wallets *HardwareWallets
+	id      Identity
+}
+
+// NewHardwareSigner returns Signer which signs with the identity key held on a hardware wallet.
+func NewHardwareSigner(wallets *HardwareWallets, id Identity) Signer {
+	return &hardwareSigner{
+		wallets: wallets,
+		id:      id,
+	}
+}
+
+// Sign signs given message on the hardware wallet. The device may ask the operator to confirm the signature.
+func (hwSigner *hardwareSigner) Sign(message []byte) (Signature, error) {
+	signature, err := hwSigner.wallets.Sign(hwSigner.id, message)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	return SignatureBytes(signature), nil
+}
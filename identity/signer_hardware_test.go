@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+var signerPath = accounts.DefaultBaseDerivationPath
+
+type fakeHardwareWallet struct {
+	accounts.Wallet
+	opened  bool
+	derived bool
+}
+
+func (w *fakeHardwareWallet) URL() accounts.URL {
+	return accounts.URL{Scheme: "ledger", Path: "fake"}
+}
+
+func (w *fakeHardwareWallet) Open(_ string) error {
+	if w.opened {
+		return accounts.ErrWalletAlreadyOpen
+	}
+	w.opened = true
+	return nil
+}
+
+func (w *fakeHardwareWallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	if path.String() != signerPath.String() {
+		return accounts.Account{URL: w.URL()}, nil
+	}
+	w.derived = w.derived || pin
+	return signerAccount, nil
+}
+
+func (w *fakeHardwareWallet) Contains(account accounts.Account) bool {
+	return w.derived && account.Address == signerAccount.Address
+}
+
+// SignData mirrors usbwallet which signs only EIP-712 typed data.
+func (w *fakeHardwareWallet) SignData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	if mimeType != accounts.MimetypeTypedData {
+		return nil, accounts.ErrNotSupported
+	}
+	return crypto.Sign(crypto.Keccak256(data), signerKey)
+}
+
+func (w *fakeHardwareWallet) SignText(account accounts.Account, text []byte) ([]byte, error) {
+	return crypto.Sign(accounts.TextHash(text), signerKey)
+}
+
+type fakeHardwareBackend struct {
+	accounts.Backend
+	wallets []accounts.Wallet
+}
+
+func (b *fakeHardwareBackend) Wallets() []accounts.Wallet {
+	return b.wallets
+}
+
+func TestHardwareWallets_Contains(t *testing.T) {
+	wallets := NewHardwareWallets(&fakeHardwareBackend{wallets: []accounts.Wallet{&fakeHardwareWallet{}}}, []accounts.DerivationPath{signerPath})
+
+	assert.True(t, wallets.Contains(FromAddress(signerAddress)))
+	assert.True(t, wallets.Contains(FromAddress(signerAddress)), "opened wallet must be reused")
+	assert.False(t, wallets.Contains(FromAddress("0x0000000000000000000000000000000000000001")))
+}
+
+func TestHardwareSigner_Sign(t *testing.T) {
+	wallets := NewHardwareWallets(&fakeHardwareBackend{wallets: []accounts.Wallet{&fakeHardwareWallet{}}}, []accounts.DerivationPath{signerPath})
+	message := []byte("MystVpnSessionId:Boop!")
+
+	signature, err := NewHardwareSigner(wallets, FromAddress(signerAddress)).Sign(message)
+	assert.NoError(t, err)
+
+	expected, err := crypto.Sign(accounts.TextHash(message), signerKey)
+	assert.NoError(t, err)
+	expected[crypto.RecoveryIDOffset] += 27
+	assert.Equal(t, SignatureBytes(expected), signature)
+
+	ok, _ := NewVerifierIdentity(FromAddress("0x"+signerAddress)).Verify(message, signature)
+	assert.True(t, ok)
+	ok, signer := NewVerifierSigned().Verify(message, signature)
+	assert.True(t, ok)
+	assert.Equal(t, FromAddress("0x"+signerAddress), signer)
+	ok, _ = NewVerifierIdentity(FromAddress("0x"+signerAddress)).Verify([]byte("MystVpnSessionId:Changed!"), signature)
+	assert.False(t, ok)
+}
+
+func TestHardwareSigner_SignWithoutWallet(t *testing.T) {
+	wallets := NewHardwareWallets(&fakeHardwareBackend{}, []accounts.DerivationPath{signerPath})
+
+	_, err := NewHardwareSigner(wallets, FromAddress(signerAddress)).Sign([]byte("message"))
+	assert.Equal(t, ErrHardwareWalletNotFound, err)
+}
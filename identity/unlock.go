@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrPassphraseNotFound is returned when no passphrase is stored for the identity.
+var ErrPassphraseNotFound = errors.New("passphrase not found")
+
+// UnlockProvider stores keystore passphrases so identities can be unlocked without supplying them.
+type UnlockProvider interface {
+	Passphrase(address string) (string, error)
+	StorePassphrase(address, passphrase string) error
+}
+
+const keychainService = "mysterium-node"
+
+// Keychain keeps keystore passphrases in the platform keychain:
+// Keychain on macOS, libsecret on Linux and DPAPI protected files on Windows.
+type Keychain struct {
+	service string
+	dir     string
+}
+
+// NewKeychain returns keychain backed unlock provider.
+// Directory is used by the platforms which keep protected passphrases in files.
+func NewKeychain(dir string) *Keychain {
+	return &Keychain{
+		service: keychainService,
+		dir:     dir,
+	}
+}
+
+// Passphrase returns the passphrase stored for the identity.
+func (k *Keychain) Passphrase(address string) (string, error) {
+	return k.get(strings.ToLower(address))
+}
+
+// StorePassphrase stores the passphrase of the identity, replacing the previous one.
+func (k *Keychain) StorePassphrase(address, passphrase string) error {
+	return k.set(strings.ToLower(address), passphrase)
+}
@@ -0,0 +1,54 @@
+//go:build darwin
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// errSecItemNotFound is the exit code of `security` when the item does not exist.
+const errSecItemNotFound = 44
+
+func (k *Keychain) get(account string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("security", "find-generic-password", "-s", k.service, "-a", account, "-w")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == errSecItemNotFound {
+		return "", ErrPassphraseNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read passphrase from keychain: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (k *Keychain) set(account, passphrase string) error {
+	out, err := exec.Command("security", "add-generic-password", "-U", "-s", k.service, "-a", account, "-w", passphrase).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("could not store passphrase in keychain: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
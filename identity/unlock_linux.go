@@ -0,0 +1,57 @@
+//go:build linux
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func (k *Keychain) get(account string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", k.service, "account", account)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	// secret-tool exits with 1 and no output when the secret does not exist.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && stderr.Len() == 0 {
+		return "", ErrPassphraseNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read passphrase from secret service: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+func (k *Keychain) set(account, passphrase string) error {
+	cmd := exec.Command(
+		"secret-tool", "store",
+		"--label", fmt.Sprintf("Mysterium node identity %s", account),
+		"service", k.service, "account", account,
+	)
+	cmd.Stdin = strings.NewReader(passphrase)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("could not store passphrase in secret service: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
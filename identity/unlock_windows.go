@@ -0,0 +1,91 @@
+//go:build windows
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package identity
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+func (k *Keychain) get(account string) (string, error) {
+	protected, err := os.ReadFile(k.path(account))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrPassphraseNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read protected passphrase: %w", err)
+	}
+
+	passphrase, err := dpapiUnprotect(protected)
+	if err != nil {
+		return "", fmt.Errorf("could not unprotect passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
+
+func (k *Keychain) set(account, passphrase string) error {
+	protected, err := dpapiProtect([]byte(passphrase))
+	if err != nil {
+		return fmt.Errorf("could not protect passphrase: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(k.path(account)), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(k.path(account), protected, 0600)
+}
+
+func (k *Keychain) path(account string) string {
+	return filepath.Join(k.dir, "keychain", account+".dpapi")
+}
+
+func dpapiProtect(data []byte) ([]byte, error) {
+	var out windows.DataBlob
+	if err := windows.CryptProtectData(newDataBlob(data), nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	return takeDataBlob(&out), nil
+}
+
+func dpapiUnprotect(data []byte) ([]byte, error) {
+	var out windows.DataBlob
+	if err := windows.CryptUnprotectData(newDataBlob(data), nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	return takeDataBlob(&out), nil
+}
+
+func newDataBlob(data []byte) *windows.DataBlob {
+	if len(data) == 0 {
+		return &windows.DataBlob{}
+	}
+	return &windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+}
+
+// takeDataBlob copies the blob allocated by DPAPI and releases it.
+func takeDataBlob(blob *windows.DataBlob) []byte {
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(blob.Data)))
+	return append([]byte(nil), unsafe.Slice(blob.Data, blob.Size)...)
+}
@@ -71,6 +71,12 @@ func SetLogLevel(level zerolog.Level) {
 // Configure configures logger using app config (console + file, level).
 func Configure(opts *LogOptions) {
 	CurrentLogOptions = *opts
+	console := consoleWriter()
+	if opts.Writer != nil {
+		console = opts.Writer
+		logger := makeLogger(console)
+		setGlobalLogger(&logger)
+	}
 	log.Info().Msgf("Log level: %s", opts.LogLevel)
 	if opts.Filepath != "" {
 		log.Info().Msgf("Log file path: %s", opts.Filepath)
@@ -78,7 +84,7 @@ func Configure(opts *LogOptions) {
 		if err != nil {
 			log.Err(err).Msg("Failed to configure file logger")
 		} else {
-			multiWriter := io.MultiWriter(consoleWriter(), zeroLogger(rollingWriter.Writer))
+			multiWriter := io.MultiWriter(console, zeroLogger(rollingWriter.Writer))
 			logger := makeLogger(multiWriter)
 			setGlobalLogger(&logger)
 		}
@@ -18,6 +18,8 @@
 package logconfig
 
 import (
+	"io"
+
 	"github.com/rs/zerolog"
 )
 
@@ -26,6 +28,8 @@ type LogOptions struct {
 	LogLevel zerolog.Level
 	LogHTTP  bool
 	Filepath string
+	// Writer receives log events as JSON lines instead of the console, e.g. to pass them to the program embedding the node.
+	Writer io.Writer
 }
 
 // CurrentLogOptions stores global LogOptions.
@@ -0,0 +1,27 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package market
+
+// Capacity is the network capacity and availability of the provider measured by the provider itself.
+type Capacity struct {
+	// UplinkMbps and DownlinkMbps are the measured upload and download speeds.
+	UplinkMbps   float64 `json:"uplink_mbps,omitempty"`
+	DownlinkMbps float64 `json:"downlink_mbps,omitempty"`
+	// Uptime is the percentage of time the provider node was running during the recent period.
+	Uptime float64 `json:"uptime"`
+}
@@ -0,0 +1,28 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package market
+
+// EgressPolicy describes destinations provider does not let consumer traffic reach.
+type EgressPolicy struct {
+	// BlockedPorts are destination ports blocked for both TCP and UDP.
+	BlockedPorts []int `json:"blocked_ports,omitempty"`
+	// BlockedNetworks are destination networks in CIDR notation.
+	BlockedNetworks []string `json:"blocked_networks,omitempty"`
+	// BlockedDomains are blocked together with their subdomains.
+	BlockedDomains []string `json:"blocked_domains,omitempty"`
+}
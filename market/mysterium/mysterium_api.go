@@ -18,6 +18,11 @@
 package mysterium
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
@@ -67,6 +72,158 @@ func (mApi *MysteriumAPI) QueryProposals(query ProposalsQuery) ([]market.Service
 	return supported, nil
 }
 
+// ProposalsSnapshot holds proposals along with validators used to request them again only if they change.
+type ProposalsSnapshot struct {
+	Proposals    []market.ServiceProposal
+	ETag         string
+	LastModified string
+	// NotModified marks that proposals did not change since the previous snapshot and were not returned.
+	NotModified bool
+}
+
+// QueryProposalsSince returns active service proposals unless they are not modified since
+// the snapshot with the given ETag and Last-Modified validators.
+func (mApi *MysteriumAPI) QueryProposalsSince(query ProposalsQuery, etag, lastModified string) (ProposalsSnapshot, error) {
+	req, err := requests.NewGetRequest(mApi.discoveryAPIAddress, "proposals", query.ToURLValues())
+	if err != nil {
+		return ProposalsSnapshot{}, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	res, err := mApi.httpClient.Do(req)
+	if err != nil {
+		return ProposalsSnapshot{}, errors.Wrap(err, "cannot fetch proposals")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified {
+		return ProposalsSnapshot{ETag: etag, LastModified: lastModified, NotModified: true}, nil
+	}
+	if err := requests.ParseResponseError(res); err != nil {
+		return ProposalsSnapshot{}, err
+	}
+
+	var proposals []market.ServiceProposal
+	if err := requests.ParseResponseJSON(res, &proposals); err != nil {
+		return ProposalsSnapshot{}, errors.Wrap(err, "cannot parse proposals response")
+	}
+
+	return ProposalsSnapshot{
+		Proposals:    supportedProposalsOnly(proposals),
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+	}, nil
+}
+
+// DefaultProposalsPageSize is the number of proposals requested per page when streaming.
+const DefaultProposalsPageSize = 500
+
+// NextCursorHeader holds the cursor of the next proposals page, it is absent on the last page.
+const NextCursorHeader = "X-Next-Cursor"
+
+// ProposalsPage is a single page of active service proposals.
+type ProposalsPage struct {
+	Proposals []market.ServiceProposal
+	// NextCursor requests the following page, it is empty on the last page.
+	NextCursor string
+}
+
+// QueryProposalsPage returns at most limit active service proposals starting at the cursor.
+// Empty cursor requests the first page. Discovery ignoring pagination returns all proposals in a single page.
+func (mApi *MysteriumAPI) QueryProposalsPage(query ProposalsQuery, cursor string, limit int) (ProposalsPage, error) {
+	var page ProposalsPage
+	next, err := mApi.queryProposalsPage(query, cursor, limit, func(p market.ServiceProposal) error {
+		page.Proposals = append(page.Proposals, p)
+		return nil
+	})
+	if err != nil {
+		return ProposalsPage{}, err
+	}
+	page.NextCursor = next
+	return page, nil
+}
+
+// StreamProposals passes active service proposals to the callback one by one as pages arrive,
+// so that the whole proposal list is never held in memory. Streaming stops at the first callback error.
+func (mApi *MysteriumAPI) StreamProposals(query ProposalsQuery, pageSize int, fn func(market.ServiceProposal) error) error {
+	var cursor string
+	for {
+		next, err := mApi.queryProposalsPage(query, cursor, pageSize, fn)
+		if err != nil {
+			return err
+		}
+		if next == "" || next == cursor {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+func (mApi *MysteriumAPI) queryProposalsPage(query ProposalsQuery, cursor string, limit int, fn func(market.ServiceProposal) error) (string, error) {
+	values := query.ToURLValues()
+	if cursor != "" {
+		values.Set("cursor", cursor)
+	}
+	if limit > 0 {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+	req, err := requests.NewGetRequest(mApi.discoveryAPIAddress, "proposals", values)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := mApi.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "cannot fetch proposals")
+	}
+	defer res.Body.Close()
+
+	if err := requests.ParseResponseError(res); err != nil {
+		return "", err
+	}
+	if err := decodeProposals(res.Body, fn); err != nil {
+		return "", err
+	}
+	return res.Header.Get(NextCursorHeader), nil
+}
+
+// decodeProposals decodes a JSON array of proposals element by element, skipping unsupported ones.
+func decodeProposals(r io.Reader, fn func(market.ServiceProposal) error) error {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err == io.EOF || err == nil && token == nil {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "cannot parse proposals response")
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return errors.New("cannot parse proposals response: array expected")
+	}
+
+	for decoder.More() {
+		var proposal market.ServiceProposal
+		if err := decoder.Decode(&proposal); err != nil {
+			return errors.Wrap(err, "cannot parse proposals response")
+		}
+		if proposal.Validate() != nil || !proposal.IsSupported() {
+			continue
+		}
+		if err := fn(proposal); err != nil {
+			return err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return errors.Wrap(err, "cannot parse proposals response")
+	}
+	return nil
+}
+
 // QueryCountries returns active service proposals number per country.
 func (mApi *MysteriumAPI) QueryCountries(query ProposalsQuery) (map[string]int, error) {
 	req, err := requests.NewGetRequest(mApi.discoveryAPIAddress, "countries", query.ToURLValues())
@@ -18,17 +18,34 @@
 package mysterium
 
 import (
+	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
 	"testing"
 	"time"
 
+	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/requests"
 	"github.com/stretchr/testify/assert"
 )
 
 const bindAllAddress = "0.0.0.0"
 
+func init() {
+	market.RegisterServiceType("mock_service")
+	market.RegisterContactUnserializer("mock_contact", func(*json.RawMessage) (market.ContactDefinition, error) {
+		return struct{}{}, nil
+	})
+}
+
+func proposalJSON(providerID, serviceType string) string {
+	return fmt.Sprintf(
+		`{"format": "service-proposal/v3", "provider_id": %q, "service_type": %q, "location": {"country": "LT"}, "contacts": [{"type": "mock_contact"}]}`,
+		providerID, serviceType,
+	)
+}
+
 func TestHttpTransportDoesntBlockForeverIfServerFailsToSendAnyResponse(t *testing.T) {
 
 	address, err := createHTTPServer(func(writer http.ResponseWriter, request *http.Request) {
@@ -65,3 +82,62 @@ func createHTTPServer(handlerFunc http.HandlerFunc) (address string, err error)
 	go http.Serve(listener, handlerFunc)
 	return listener.Addr().String(), nil
 }
+
+func TestQueryProposalsSince(t *testing.T) {
+	address, err := createHTTPServer(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("If-None-Match") == `"v1"` {
+			writer.WriteHeader(http.StatusNotModified)
+			return
+		}
+		writer.Header().Set("ETag", `"v1"`)
+		writer.Header().Set("Last-Modified", "Mon, 15 Aug 2022 10:00:00 GMT")
+		writer.Write([]byte("[" + proposalJSON("0x1", "mock_service") + "]"))
+	})
+	assert.NoError(t, err)
+
+	api := NewClient(requests.NewHTTPClient(bindAllAddress, time.Second), "http://"+address)
+
+	snapshot, err := api.QueryProposalsSince(ProposalsQuery{}, "", "")
+	assert.NoError(t, err)
+	assert.False(t, snapshot.NotModified)
+	assert.Len(t, snapshot.Proposals, 1)
+	assert.Equal(t, `"v1"`, snapshot.ETag)
+	assert.Equal(t, "Mon, 15 Aug 2022 10:00:00 GMT", snapshot.LastModified)
+
+	snapshot, err = api.QueryProposalsSince(ProposalsQuery{}, `"v1"`, "Mon, 15 Aug 2022 10:00:00 GMT")
+	assert.NoError(t, err)
+	assert.True(t, snapshot.NotModified)
+	assert.Empty(t, snapshot.Proposals)
+	assert.Equal(t, `"v1"`, snapshot.ETag)
+}
+
+func TestStreamProposals(t *testing.T) {
+	address, err := createHTTPServer(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "2", request.URL.Query().Get("limit"))
+		switch request.URL.Query().Get("cursor") {
+		case "":
+			writer.Header().Set(NextCursorHeader, "c1")
+			writer.Write([]byte("[" + proposalJSON("0x1", "mock_service") + "," + proposalJSON("0x2", "unknown") + "]"))
+		case "c1":
+			writer.Write([]byte("[" + proposalJSON("0x3", "mock_service") + "]"))
+		default:
+			writer.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	assert.NoError(t, err)
+
+	api := NewClient(requests.NewHTTPClient(bindAllAddress, time.Second), "http://"+address)
+
+	page, err := api.QueryProposalsPage(ProposalsQuery{}, "", 2)
+	assert.NoError(t, err)
+	assert.Len(t, page.Proposals, 1)
+	assert.Equal(t, "c1", page.NextCursor)
+
+	var providers []string
+	err = api.StreamProposals(ProposalsQuery{}, 2, func(p market.ServiceProposal) error {
+		providers = append(providers, p.ProviderID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0x1", "0x3"}, providers)
+}
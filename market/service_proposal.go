@@ -29,6 +29,13 @@ const (
 	proposalFormat = "service-proposal/v3"
 )
 
+const (
+	// AddressFamilyIPv4 marks provider reachable over IPv4.
+	AddressFamilyIPv4 = "ipv4"
+	// AddressFamilyIPv6 marks provider reachable over IPv6.
+	AddressFamilyIPv6 = "ipv6"
+)
+
 // ServiceProposal is top level structure which is presented to marketplace by service provider, and looked up by service consumer
 // service proposal can be marked as unsupported by deserializer, because of unknown service, payment method, or contact type
 type ServiceProposal struct {
@@ -56,6 +63,24 @@ type ServiceProposal struct {
 
 	// Quality represents the service quality.
 	Quality Quality `json:"quality"`
+
+	// AddressFamilies lists IP address families provider is reachable over, IPv4 is assumed when empty
+	AddressFamilies []string `json:"address_families,omitempty"`
+
+	// BehindCGNAT marks providers behind carrier-grade NAT which are reachable via relay only
+	BehindCGNAT bool `json:"behind_cgnat,omitempty"`
+
+	// Mobile marks providers running on mobile devices with constrained uptime and peer count
+	Mobile bool `json:"mobile,omitempty"`
+
+	// Signature of the provider over the proposal, see SignedMessage
+	Signature string `json:"signature,omitempty"`
+
+	// Capacity advertised by the provider
+	Capacity *Capacity `json:"capacity,omitempty"`
+
+	// Egress lists destinations blocked for consumer traffic by the provider
+	Egress *EgressPolicy `json:"egress,omitempty"`
 }
 
 // NewProposalOpts optional params for the new proposal creation.
@@ -64,6 +89,16 @@ type NewProposalOpts struct {
 	AccessPolicies []AccessPolicy
 	Contacts       []Contact
 	Quality        *Quality
+	// AddressFamilies lists IP address families provider is reachable over.
+	AddressFamilies []string
+	// BehindCGNAT marks provider as reachable via relay only.
+	BehindCGNAT bool
+	// Mobile marks provider as running on a mobile device.
+	Mobile bool
+	// Capacity is the self-measured provider capacity.
+	Capacity *Capacity
+	// Egress is the policy applied to consumer traffic leaving the provider.
+	Egress *EgressPolicy
 }
 
 // NewProposal creates a new proposal.
@@ -89,6 +124,13 @@ func NewProposal(providerID, serviceType string, opts NewProposalOpts) ServicePr
 	if q := opts.Quality; q != nil {
 		p.Quality = *q
 	}
+	if af := opts.AddressFamilies; len(af) > 0 {
+		p.AddressFamilies = af
+	}
+	p.BehindCGNAT = opts.BehindCGNAT
+	p.Mobile = opts.Mobile
+	p.Capacity = opts.Capacity
+	p.Egress = opts.Egress
 	return p
 }
 
@@ -114,15 +156,21 @@ func (proposal *ServiceProposal) UniqueID() ProposalID {
 // UnmarshalJSON is custom json unmarshaler to dynamically fill in ServiceProposal values
 func (proposal *ServiceProposal) UnmarshalJSON(data []byte) error {
 	var jsonData struct {
-		ID             int64            `json:"id"`
-		Format         string           `json:"format"`
-		ProviderID     string           `json:"provider_id"`
-		ServiceType    string           `json:"service_type"`
-		Compatibility  int              `json:"compatibility"`
-		Location       Location         `json:"location"`
-		Contacts       *json.RawMessage `json:"contacts"`
-		AccessPolicies *[]AccessPolicy  `json:"access_policies,omitempty"`
-		Quality        Quality          `json:"quality"`
+		ID              int64            `json:"id"`
+		Format          string           `json:"format"`
+		ProviderID      string           `json:"provider_id"`
+		ServiceType     string           `json:"service_type"`
+		Compatibility   int              `json:"compatibility"`
+		Location        Location         `json:"location"`
+		Contacts        *json.RawMessage `json:"contacts"`
+		AccessPolicies  *[]AccessPolicy  `json:"access_policies,omitempty"`
+		Quality         Quality          `json:"quality"`
+		AddressFamilies []string         `json:"address_families,omitempty"`
+		BehindCGNAT     bool             `json:"behind_cgnat,omitempty"`
+		Mobile          bool             `json:"mobile,omitempty"`
+		Signature       string           `json:"signature,omitempty"`
+		Capacity        *Capacity        `json:"capacity,omitempty"`
+		Egress          *EgressPolicy    `json:"egress,omitempty"`
 	}
 	if err := json.Unmarshal(data, &jsonData); err != nil {
 		return err
@@ -139,10 +187,68 @@ func (proposal *ServiceProposal) UnmarshalJSON(data []byte) error {
 	proposal.Contacts = unserializeContacts(jsonData.Contacts)
 	proposal.AccessPolicies = jsonData.AccessPolicies
 	proposal.Quality = jsonData.Quality
+	proposal.AddressFamilies = jsonData.AddressFamilies
+	proposal.BehindCGNAT = jsonData.BehindCGNAT
+	proposal.Mobile = jsonData.Mobile
+	proposal.Signature = jsonData.Signature
+	proposal.Capacity = jsonData.Capacity
+	proposal.Egress = jsonData.Egress
 
 	return nil
 }
 
+// SignedMessage returns the part of the proposal signed by the provider.
+// ID and quality are assigned by discovery, so they are not signed.
+func (proposal *ServiceProposal) SignedMessage() ([]byte, error) {
+	contacts := proposal.Contacts
+	if contacts == nil {
+		// Missing contacts are unserialized as an empty list.
+		contacts = ContactList{}
+	}
+
+	return json.Marshal(struct {
+		Format          string          `json:"format"`
+		Compatibility   int             `json:"compatibility"`
+		ProviderID      string          `json:"provider_id"`
+		ServiceType     string          `json:"service_type"`
+		Location        Location        `json:"location"`
+		Contacts        ContactList     `json:"contacts"`
+		AccessPolicies  *[]AccessPolicy `json:"access_policies,omitempty"`
+		AddressFamilies []string        `json:"address_families,omitempty"`
+		BehindCGNAT     bool            `json:"behind_cgnat,omitempty"`
+		Mobile          bool            `json:"mobile,omitempty"`
+		Capacity        *Capacity       `json:"capacity,omitempty"`
+		Egress          *EgressPolicy   `json:"egress,omitempty"`
+	}{
+		Format:          proposal.Format,
+		Compatibility:   proposal.Compatibility,
+		ProviderID:      proposal.ProviderID,
+		ServiceType:     proposal.ServiceType,
+		Location:        proposal.Location,
+		Contacts:        contacts,
+		AccessPolicies:  proposal.AccessPolicies,
+		AddressFamilies: proposal.AddressFamilies,
+		BehindCGNAT:     proposal.BehindCGNAT,
+		Mobile:          proposal.Mobile,
+		Capacity:        proposal.Capacity,
+		Egress:          proposal.Egress,
+	})
+}
+
+// SupportsAddressFamily returns true if provider is reachable over the given IP address family.
+// Proposals without address families are from providers which predate IPv6 support and are IPv4 only.
+func (proposal *ServiceProposal) SupportsAddressFamily(family string) bool {
+	if len(proposal.AddressFamilies) == 0 {
+		return family == AddressFamilyIPv4
+	}
+	for _, f := range proposal.AddressFamilies {
+		if f == family {
+			return true
+		}
+	}
+	return false
+}
+
 // IsSupported returns true if this service proposal can be used for connections by service consumer
 // can be used as a filter to filter out all proposals which are unsupported for any reason
 func (proposal *ServiceProposal) IsSupported() bool {
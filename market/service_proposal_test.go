@@ -150,3 +150,40 @@ func Test_ServiceProposal_UnserializeAccessPolicy(t *testing.T) {
 	assert.Equal(t, expected, actual)
 	assert.True(t, actual.IsSupported())
 }
+
+func Test_ServiceProposal_SupportsAddressFamily(t *testing.T) {
+	legacy := ServiceProposal{}
+	assert.True(t, legacy.SupportsAddressFamily(AddressFamilyIPv4))
+	assert.False(t, legacy.SupportsAddressFamily(AddressFamilyIPv6))
+
+	v6only := ServiceProposal{AddressFamilies: []string{AddressFamilyIPv6}}
+	assert.False(t, v6only.SupportsAddressFamily(AddressFamilyIPv4))
+	assert.True(t, v6only.SupportsAddressFamily(AddressFamilyIPv6))
+}
+
+func Test_ServiceProposal_SignedMessageSurvivesSerialization(t *testing.T) {
+	p := NewProposal("0x1", "mock_service", NewProposalOpts{
+		Location: &Location{Country: "LT", IPType: "residential"},
+		Quality:  &Quality{Quality: 2},
+		Mobile:   true,
+		Egress:   &EgressPolicy{BlockedPorts: []int{25}},
+	})
+	p.Signature = "signature"
+
+	expected, err := p.SignedMessage()
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(p)
+	assert.NoError(t, err)
+	var actual ServiceProposal
+	assert.NoError(t, json.Unmarshal(data, &actual))
+	assert.Equal(t, "signature", actual.Signature)
+	assert.True(t, actual.Mobile)
+	assert.Equal(t, []int{25}, actual.Egress.BlockedPorts)
+
+	actual.ID = 1
+	actual.Quality.Quality = 3
+	message, err := actual.SignedMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, message)
+}
@@ -0,0 +1,387 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mysterium
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/session"
+)
+
+const (
+	dataSaverFilename = "data-saver.json"
+	// dataSaverSaveInterval limits how often usage is persisted while connected.
+	dataSaverSaveInterval = time.Minute
+	// networkTypeUnknown accounts traffic before the platform reports a network.
+	networkTypeUnknown = "unknown"
+)
+
+// errDataCapReached is returned when connecting on a network whose data cap is reached.
+var errDataCapReached = errors.New("data cap reached")
+
+// DataSaverConfig limits traffic through the connection per network type.
+type DataSaverConfig struct {
+	// NeverAutoConnectOnCellular stops on-demand connections and reconnects after wake up on cellular networks.
+	// Connections started by the user are not affected.
+	NeverAutoConnectOnCellular bool
+	// CellularCapMB disconnects and blocks connections on cellular networks once reached in the current period, 0 disables it.
+	CellularCapMB int64
+	// WiFiCapMB disconnects and blocks connections on Wi-Fi networks once reached in the current period, 0 disables it.
+	WiFiCapMB int64
+	// PeriodStartDay is the day of month, from 1 to 28, on which usage is reset.
+	PeriodStartDay int
+}
+
+// DefaultDataSaverConfig returns data saver configuration without limits.
+func DefaultDataSaverConfig() *DataSaverConfig {
+	return &DataSaverConfig{
+		PeriodStartDay: 1,
+	}
+}
+
+func (cfg DataSaverConfig) capBytes(networkType string) uint64 {
+	var mb int64
+	switch networkType {
+	case NetworkTypeCellular:
+		mb = cfg.CellularCapMB
+	case NetworkTypeWiFi:
+		mb = cfg.WiFiCapMB
+	}
+	if mb <= 0 {
+		return 0
+	}
+	return uint64(mb) * 1024 * 1024
+}
+
+// DataUsage represents traffic through the connection on a network type in the current period.
+type DataUsage struct {
+	NetworkType   string
+	BytesReceived int64
+	BytesSent     int64
+	// CapBytes is the data cap of the network type, 0 if there is none.
+	CapBytes int64
+	// PeriodStart is the unix time the current period started at.
+	PeriodStart int64
+}
+
+// DataSaverCallback is notified when the connection is stopped because of a data cap.
+type DataSaverCallback interface {
+	OnDataCapReached(networkType string, usedBytes int64, capBytes int64)
+}
+
+type networkUsage struct {
+	BytesReceived uint64 `json:"bytes_received"`
+	BytesSent     uint64 `json:"bytes_sent"`
+}
+
+func (u networkUsage) total() uint64 {
+	return u.BytesReceived + u.BytesSent
+}
+
+type dataSaverState struct {
+	Config      DataSaverConfig          `json:"config"`
+	PeriodStart time.Time                `json:"period_start"`
+	Usage       map[string]*networkUsage `json:"usage"`
+}
+
+// dataSaver accounts connection traffic per network type and enforces data saver rules.
+type dataSaver struct {
+	path       string
+	disconnect func() error
+	state      func() connectionstate.State
+	now        func() time.Time
+
+	mu          sync.Mutex
+	saved       dataSaverState
+	savedAt     time.Time
+	networkType string
+	sessionID   session.ID
+	lastStats   connectionstate.Statistics
+	capHandled  map[string]bool
+	callback    DataSaverCallback
+}
+
+func newDataSaver(dataDir string, disconnect func() error, state func() connectionstate.State) *dataSaver {
+	s := &dataSaver{
+		path:        dataDir + "/" + dataSaverFilename,
+		disconnect:  disconnect,
+		state:       state,
+		now:         time.Now,
+		networkType: networkTypeUnknown,
+		capHandled:  make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err == nil {
+		err = json.Unmarshal(data, &s.saved)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn().Err(err).Msg("Could not load data saver state, starting over")
+		s.saved = dataSaverState{}
+	}
+	if s.saved.Config.PeriodStartDay == 0 {
+		s.saved.Config = *DefaultDataSaverConfig()
+	}
+	if s.saved.Usage == nil {
+		s.saved.Usage = make(map[string]*networkUsage)
+	}
+	return s
+}
+
+func (s *dataSaver) setConfig(cfg DataSaverConfig) error {
+	if cfg.PeriodStartDay < 1 || cfg.PeriodStartDay > 28 {
+		return errors.New("period start day must be from 1 to 28")
+	}
+	if cfg.CellularCapMB < 0 || cfg.WiFiCapMB < 0 {
+		return errors.New("data cap can not be negative")
+	}
+
+	s.mu.Lock()
+	s.saved.Config = cfg
+	s.capHandled = make(map[string]bool)
+	s.rollover()
+	err := s.save()
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.enforce()
+	return nil
+}
+
+func (s *dataSaver) getConfig() DataSaverConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saved.Config
+}
+
+func (s *dataSaver) setCallback(cb DataSaverCallback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callback = cb
+}
+
+func (s *dataSaver) usage(networkType string) DataUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rollover()
+
+	u := networkUsage{}
+	if saved, ok := s.saved.Usage[networkType]; ok {
+		u = *saved
+	}
+	return DataUsage{
+		NetworkType:   networkType,
+		BytesReceived: int64(u.BytesReceived),
+		BytesSent:     int64(u.BytesSent),
+		CapBytes:      int64(s.saved.Config.capBytes(networkType)),
+		PeriodStart:   s.saved.PeriodStart.Unix(),
+	}
+}
+
+func (s *dataSaver) reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved.Usage = make(map[string]*networkUsage)
+	s.capHandled = make(map[string]bool)
+	return s.save()
+}
+
+// networkChanged starts accounting traffic to the given network type and stops the connection if its cap is reached.
+func (s *dataSaver) networkChanged(networkType string) {
+	if networkType == "" {
+		networkType = networkTypeUnknown
+	}
+
+	s.mu.Lock()
+	s.networkType = networkType
+	s.mu.Unlock()
+
+	s.enforce()
+}
+
+// allowConnect checks whether the connection can be started on the current network.
+func (s *dataSaver) allowConnect() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rollover()
+
+	if used, limit, reached := s.capReached(s.networkType); reached {
+		return fmt.Errorf("%w on %s network: %d of %d bytes used", errDataCapReached, s.networkType, used, limit)
+	}
+	return nil
+}
+
+// allowAutoConnect checks whether the connection can be started without user interaction on the current network.
+func (s *dataSaver) allowAutoConnect() error {
+	if err := s.allowConnect(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saved.Config.NeverAutoConnectOnCellular && s.networkType == NetworkTypeCellular {
+		return errors.New("automatic connections on cellular networks are disabled")
+	}
+	return nil
+}
+
+func (s *dataSaver) consumeStatisticsEvent(e connectionstate.AppEventConnectionStatistics) {
+	s.mu.Lock()
+	if e.SessionInfo.SessionID != s.sessionID {
+		s.sessionID = e.SessionInfo.SessionID
+		s.lastStats = connectionstate.Statistics{}
+	}
+	diff := s.lastStats.Diff(e.Stats)
+	s.lastStats = e.Stats
+
+	s.rollover()
+	u, ok := s.saved.Usage[s.networkType]
+	if !ok {
+		u = &networkUsage{}
+		s.saved.Usage[s.networkType] = u
+	}
+	u.BytesReceived += diff.BytesReceived
+	u.BytesSent += diff.BytesSent
+
+	if s.now().Sub(s.savedAt) >= dataSaverSaveInterval {
+		if err := s.save(); err != nil {
+			log.Warn().Err(err).Msg("Could not save data usage")
+		}
+	}
+	s.mu.Unlock()
+
+	s.enforce()
+}
+
+func (s *dataSaver) consumeConnectionStateEvent(e connectionstate.AppEventConnectionState) {
+	if e.State != connectionstate.NotConnected {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.save(); err != nil {
+		log.Warn().Err(err).Msg("Could not save data usage")
+	}
+}
+
+// enforce stops the connection once the cap of the current network is reached.
+func (s *dataSaver) enforce() {
+	s.mu.Lock()
+	networkType := s.networkType
+	used, limit, reached := s.capReached(networkType)
+	if !reached || s.capHandled[networkType] || !isActiveState(s.state()) {
+		s.mu.Unlock()
+		return
+	}
+	s.capHandled[networkType] = true
+	cb := s.callback
+	if err := s.save(); err != nil {
+		log.Warn().Err(err).Msg("Could not save data usage")
+	}
+	s.mu.Unlock()
+
+	log.Info().Msgf("Data cap of %s network reached, %d of %d bytes used, disconnecting", networkType, used, limit)
+	if err := s.disconnect(); err != nil {
+		log.Error().Err(err).Msg("Could not disconnect after reaching data cap")
+	}
+	if cb != nil {
+		cb.OnDataCapReached(networkType, int64(used), int64(limit))
+	}
+}
+
+func (s *dataSaver) capReached(networkType string) (used, limit uint64, reached bool) {
+	limit = s.saved.Config.capBytes(networkType)
+	if u, ok := s.saved.Usage[networkType]; ok {
+		used = u.total()
+	}
+	return used, limit, limit > 0 && used >= limit
+}
+
+// rollover resets usage once a new period starts.
+func (s *dataSaver) rollover() {
+	start := periodStart(s.now(), s.saved.Config.PeriodStartDay)
+	if !s.saved.PeriodStart.Before(start) {
+		return
+	}
+
+	s.saved.PeriodStart = start
+	s.saved.Usage = make(map[string]*networkUsage)
+	s.capHandled = make(map[string]bool)
+}
+
+func (s *dataSaver) save() error {
+	data, err := json.Marshal(s.saved)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("could not save data saver state: %w", err)
+	}
+	s.savedAt = s.now()
+	return nil
+}
+
+// periodStart returns the start of the period the given time belongs to.
+func periodStart(now time.Time, day int) time.Time {
+	start := time.Date(now.Year(), now.Month(), day, 0, 0, 0, 0, now.Location())
+	if now.Before(start) {
+		start = start.AddDate(0, -1, 0)
+	}
+	return start
+}
+
+// SetDataSaverConfig saves data saver configuration.
+func (mb *MobileNode) SetDataSaverConfig(cfg *DataSaverConfig) error {
+	if cfg == nil {
+		return errors.New("data saver configuration is required")
+	}
+	return mb.dataSaver.setConfig(*cfg)
+}
+
+// GetDataSaverConfig returns current data saver configuration.
+func (mb *MobileNode) GetDataSaverConfig() *DataSaverConfig {
+	cfg := mb.dataSaver.getConfig()
+	return &cfg
+}
+
+// GetDataUsage returns traffic through the connection on the given network type, one of NetworkType*, in the current period.
+func (mb *MobileNode) GetDataUsage(networkType string) *DataUsage {
+	usage := mb.dataSaver.usage(networkType)
+	return &usage
+}
+
+// ResetDataUsage resets traffic accounted on all network types in the current period.
+func (mb *MobileNode) ResetDataUsage() error {
+	return mb.dataSaver.reset()
+}
+
+// RegisterDataSaverCallback registers callback which is notified when the connection is stopped because of a data cap.
+func (mb *MobileNode) RegisterDataSaverCallback(cb DataSaverCallback) {
+	mb.dataSaver.setCallback(cb)
+}
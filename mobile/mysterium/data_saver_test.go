@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mysterium
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/session"
+)
+
+type dataSaverRecorder struct {
+	state connectionstate.State
+	calls []string
+}
+
+func (r *dataSaverRecorder) OnDataCapReached(networkType string, usedBytes int64, capBytes int64) {
+	r.calls = append(r.calls, "cap reached "+networkType)
+}
+
+func newTestDataSaver(t *testing.T, dir string) (*dataSaver, *dataSaverRecorder) {
+	rec := &dataSaverRecorder{state: connectionstate.Connected}
+	s := newDataSaver(
+		dir,
+		func() error {
+			rec.calls = append(rec.calls, "disconnect")
+			rec.state = connectionstate.NotConnected
+			return nil
+		},
+		func() connectionstate.State { return rec.state },
+	)
+	s.now = func() time.Time { return time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC) }
+	s.setCallback(rec)
+	return s, rec
+}
+
+func statisticsEvent(sessionID string, received, sent uint64) connectionstate.AppEventConnectionStatistics {
+	return connectionstate.AppEventConnectionStatistics{
+		Stats:       connectionstate.Statistics{BytesReceived: received, BytesSent: sent},
+		SessionInfo: connectionstate.Status{SessionID: session.ID(sessionID)},
+	}
+}
+
+func TestDataSaver_AccountsTrafficPerNetworkType(t *testing.T) {
+	s, _ := newTestDataSaver(t, t.TempDir())
+
+	s.networkChanged(NetworkTypeWiFi)
+	s.consumeStatisticsEvent(statisticsEvent("s1", 100, 10))
+	s.consumeStatisticsEvent(statisticsEvent("s1", 300, 30))
+
+	s.networkChanged(NetworkTypeCellular)
+	s.consumeStatisticsEvent(statisticsEvent("s1", 350, 40))
+	s.consumeStatisticsEvent(statisticsEvent("s2", 50, 5))
+
+	wifi := s.usage(NetworkTypeWiFi)
+	assert.Equal(t, int64(300), wifi.BytesReceived)
+	assert.Equal(t, int64(30), wifi.BytesSent)
+
+	cellular := s.usage(NetworkTypeCellular)
+	assert.Equal(t, int64(100), cellular.BytesReceived)
+	assert.Equal(t, int64(15), cellular.BytesSent)
+	assert.Equal(t, time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC).Unix(), cellular.PeriodStart)
+}
+
+func TestDataSaver_EnforcesCellularCap(t *testing.T) {
+	dir := t.TempDir()
+	s, rec := newTestDataSaver(t, dir)
+	require.NoError(t, s.setConfig(DataSaverConfig{CellularCapMB: 1, PeriodStartDay: 1}))
+
+	s.networkChanged(NetworkTypeCellular)
+	s.consumeStatisticsEvent(statisticsEvent("s1", 512*1024, 0))
+	assert.Empty(t, rec.calls)
+	assert.NoError(t, s.allowConnect())
+
+	s.consumeStatisticsEvent(statisticsEvent("s1", 1024*1024, 0))
+	assert.Equal(t, []string{"disconnect", "cap reached cellular"}, rec.calls)
+	assert.ErrorIs(t, s.allowConnect(), errDataCapReached)
+
+	s.networkChanged(NetworkTypeWiFi)
+	assert.NoError(t, s.allowConnect())
+
+	restored, _ := newTestDataSaver(t, dir)
+	restored.networkChanged(NetworkTypeCellular)
+	assert.ErrorIs(t, restored.allowConnect(), errDataCapReached)
+
+	restored.now = func() time.Time { return time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC) }
+	assert.NoError(t, restored.allowConnect())
+}
+
+func TestDataSaver_AllowAutoConnect(t *testing.T) {
+	s, _ := newTestDataSaver(t, t.TempDir())
+	require.NoError(t, s.setConfig(DataSaverConfig{NeverAutoConnectOnCellular: true, PeriodStartDay: 1}))
+
+	s.networkChanged(NetworkTypeWiFi)
+	assert.NoError(t, s.allowAutoConnect())
+
+	s.networkChanged(NetworkTypeCellular)
+	assert.Error(t, s.allowAutoConnect())
+	assert.NoError(t, s.allowConnect())
+}
+
+func TestPeriodStart(t *testing.T) {
+	assert.Equal(t, time.Date(2022, 5, 15, 0, 0, 0, 0, time.UTC), periodStart(time.Date(2022, 5, 20, 8, 0, 0, 0, time.UTC), 15))
+	assert.Equal(t, time.Date(2022, 4, 15, 0, 0, 0, 0, time.UTC), periodStart(time.Date(2022, 5, 10, 8, 0, 0, 0, time.UTC), 15))
+	assert.Equal(t, time.Date(2021, 12, 15, 0, 0, 0, 0, time.UTC), periodStart(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), 15))
+}
@@ -40,7 +40,9 @@ import (
 	"github.com/mysteriumnetwork/node/core/ip"
 	"github.com/mysteriumnetwork/node/core/location"
 	"github.com/mysteriumnetwork/node/core/node"
+	"github.com/mysteriumnetwork/node/core/power"
 	"github.com/mysteriumnetwork/node/core/quality"
+	"github.com/mysteriumnetwork/node/core/service"
 	"github.com/mysteriumnetwork/node/core/state"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/feedback"
@@ -50,6 +52,7 @@ import (
 	"github.com/mysteriumnetwork/node/logconfig"
 	"github.com/mysteriumnetwork/node/metadata"
 	natprobe "github.com/mysteriumnetwork/node/nat/behavior"
+	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pilvytis"
 	"github.com/mysteriumnetwork/node/requests"
 	"github.com/mysteriumnetwork/node/router"
@@ -92,6 +95,13 @@ type MobileNode struct {
 	residentCountry           *identity.ResidentCountry
 	filterPresetStorage       *proposal.FilterPresetStorage
 	hermesMigrator            *migration.HermesMigrator
+	onDemand                  *onDemand
+	dataSaver                 *dataSaver
+	powerMode                 *power.Mode
+	batcher                   *callbackBatcher
+	servicesManager           *service.Manager
+	serviceSessions           *service.SessionPool
+	provider                  *provider
 }
 
 // MobileNodeOptions contains common mobile node options.
@@ -119,6 +129,8 @@ type MobileNodeOptions struct {
 	ChannelImplementationSCAddress string
 	CacheTTLSeconds                int
 	ObserverAddress                string
+	// ProviderMode bootstraps provider services, so that the device can serve consumers as a wireguard exit.
+	ProviderMode bool
 }
 
 // ConsumerPaymentConfig defines consumer side payment configuration
@@ -196,6 +208,9 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 		return nil, err
 	}
 
+	if options.ProviderMode {
+		setProviderDefaults()
+	}
 	config.Current.SetDefault(config.FlagChainID.Name, options.ActiveChainID)
 	config.Current.SetDefault(config.FlagKeepConnectedOnFail.Name, options.KeepConnectedOnFail)
 	config.Current.SetDefault(config.FlagAutoReconnect.Name, "true")
@@ -262,6 +277,7 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 			Types:        []node.DiscoveryType{node.DiscoveryTypeAPI},
 			Address:      network.DiscoveryAddress,
 			FetchEnabled: false,
+			CacheTTL:     24 * time.Hour,
 			DHT: node.OptionsDHT{
 				Address:        "0.0.0.0",
 				Port:           0,
@@ -308,12 +324,16 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 			},
 		},
 		Consumer:        true,
+		Embedded:        true,
 		PilvytisAddress: options.PilvytisAddress,
 		ObserverAddress: options.ObserverAddress,
 		SSE: node.OptionsSSE{
 			Enabled: true,
 		},
 	}
+	if options.ProviderMode {
+		setProviderOptions(&nodeOptions)
+	}
 
 	err = di.Bootstrap(nodeOptions)
 	if err != nil {
@@ -343,6 +363,7 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 			di.FilterPresetStorage,
 			di.NATProber,
 			time.Duration(options.CacheTTLSeconds)*time.Second,
+			di.PowerMode,
 		),
 		pilvytis:            di.PilvytisAPI,
 		pilvytisOrderIssuer: di.PilvytisOrderIssuer,
@@ -357,6 +378,26 @@ func NewNode(appPath string, options *MobileNodeOptions) (*MobileNode, error) {
 		residentCountry:     di.ResidentCountry,
 		filterPresetStorage: di.FilterPresetStorage,
 		hermesMigrator:      di.HermesMigrator,
+		powerMode:           di.PowerMode,
+		batcher:             newCallbackBatcher(lowPowerBatchInterval),
+		servicesManager:     di.ServicesManager,
+		serviceSessions:     di.ServiceSessions,
+	}
+	connectionState := func() connectionstate.State {
+		return mobileNode.connectionManager.Status(0).State
+	}
+	mobileNode.dataSaver = newDataSaver(dataDir, mobileNode.Disconnect, connectionState)
+	if err := di.EventBus.SubscribeAsync(connectionstate.AppTopicConnectionStatistics, mobileNode.dataSaver.consumeStatisticsEvent); err != nil {
+		return nil, err
+	}
+	if err := di.EventBus.SubscribeAsync(connectionstate.AppTopicConnectionState, mobileNode.dataSaver.consumeConnectionStateEvent); err != nil {
+		return nil, err
+	}
+	mobileNode.onDemand = newOnDemand(dataDir, mobileNode.Connect, mobileNode.Disconnect, connectionState)
+	mobileNode.onDemand.allowConnect = mobileNode.dataSaver.allowAutoConnect
+	if options.ProviderMode {
+		mobileNode.provider = newProvider(dataDir, mobileNode.startProviderService, mobileNode.stopProviderService, mobileNode.providerSessions)
+		config.Current.SetUser(config.FlagServiceMaxSessions.Name, mobileNode.provider.getConfig().MaxPeers)
 	}
 
 	return mobileNode, nil
@@ -450,8 +491,9 @@ type StatisticsChangeCallback interface {
 }
 
 // RegisterStatisticsChangeCallback registers callback which is called on active connection
-// statistics change.
+// statistics change. In low-power mode only the latest statistics are delivered periodically.
 func (mb *MobileNode) RegisterStatisticsChangeCallback(cb StatisticsChangeCallback) {
+	key := mb.batcher.newKey()
 	_ = mb.eventBus.SubscribeAsync(connectionstate.AppTopicConnectionStatistics, func(e connectionstate.AppEventConnectionStatistics) {
 		var tokensSpent float64
 		agreementTotal := mb.stateKeeper.GetConnection("").Invoice.AgreementTotal
@@ -459,7 +501,9 @@ func (mb *MobileNode) RegisterStatisticsChangeCallback(cb StatisticsChangeCallba
 			tokensSpent = units.BigIntWeiToFloatEth(agreementTotal)
 		}
 
-		cb.OnChange(int64(e.SessionInfo.Duration().Seconds()), int64(e.Stats.BytesReceived), int64(e.Stats.BytesSent), tokensSpent)
+		mb.batcher.deliver(key, func() {
+			cb.OnChange(int64(e.SessionInfo.Duration().Seconds()), int64(e.Stats.BytesReceived), int64(e.Stats.BytesSent), tokensSpent)
+		})
 	})
 }
 
@@ -482,10 +526,14 @@ type BalanceChangeCallback interface {
 }
 
 // RegisterBalanceChangeCallback registers callback which is called on identity balance change.
+// In low-power mode only the latest balance is delivered periodically.
 func (mb *MobileNode) RegisterBalanceChangeCallback(cb BalanceChangeCallback) {
+	key := mb.batcher.newKey()
 	_ = mb.eventBus.SubscribeAsync(event.AppTopicBalanceChanged, func(e event.AppEventBalanceChanged) {
 		balance := crypto.BigMystToFloat(e.Current)
-		cb.OnChange(e.Identity.Address, balance)
+		mb.batcher.deliver(key, func() {
+			cb.OnChange(e.Identity.Address, balance)
+		})
 	})
 }
 
@@ -495,6 +543,7 @@ func (mb *MobileNode) RegisterBalanceChangeCallback(cb BalanceChangeCallback) {
  *	- "auto" (default) tries the following with fallbacks: provider's DNS -> client's system DNS -> public DNS
  *  - "provider" uses DNS servers from provider's system configuration
  *  - "system" uses DNS servers from client's system configuration
+ *  - comma separated DNS server IPs, e.g. "1.1.1.1,8.8.8.8"
  */
 type ConnectRequest struct {
 	Providers               string // comma separated list of providers that will be used for the connection.
@@ -509,7 +558,15 @@ type ConnectRequest struct {
 
 func (cr *ConnectRequest) dnsOption() (connection.DNSOption, error) {
 	if len(cr.DNSOption) > 0 {
-		return connection.NewDNSOption(cr.DNSOption)
+		opt, err := connection.NewDNSOption(cr.DNSOption)
+		if err != nil {
+			return "", err
+		}
+		// Local DNS stub can't be bound inside mobile VPN sandbox.
+		if _, ok := opt.Encrypted(); ok {
+			return "", errors.New("encrypted DNS servers are not supported on mobile")
+		}
+		return opt, nil
 	}
 
 	return connection.DNSOptionAuto, nil
@@ -524,11 +581,19 @@ type ConnectResponse struct {
 const (
 	connectErrInvalidProposal     = "InvalidProposal"
 	connectErrInsufficientBalance = "InsufficientBalance"
+	connectErrDataCapReached      = "DataCapReached"
 	connectErrUnknown             = "Unknown"
 )
 
 // Connect connects to given provider.
 func (mb *MobileNode) Connect(req *ConnectRequest) *ConnectResponse {
+	if err := mb.dataSaver.allowConnect(); err != nil {
+		return &ConnectResponse{
+			ErrorCode:    connectErrDataCapReached,
+			ErrorMessage: err.Error(),
+		}
+	}
+
 	var providers []string
 	if len(req.Providers) > 0 {
 		providers = strings.Split(req.Providers, ",")
@@ -541,6 +606,7 @@ func (mb *MobileNode) Connect(req *ConnectRequest) *ConnectResponse {
 		IPType:                  req.IPType,
 		IncludeMonitoringFailed: req.IncludeMonitoringFailed,
 		ExcludeUnsupported:      true,
+		AddressFamilies:         p2p.ReachableAddressFamilies(),
 	}
 
 	proposalLookup := connection.FilteredProposals(f, req.SortBy, mb.proposalsManager.repository)
@@ -592,6 +658,7 @@ func (mb *MobileNode) Connect(req *ConnectRequest) *ConnectResponse {
 
 	qualityEvent.Stage = quality.StageConnectionOK
 	mb.eventBus.Publish(quality.AppTopicConnectionEvents, qualityEvent)
+	mb.onDemand.connected(req)
 
 	return &ConnectResponse{}
 }
@@ -607,6 +674,7 @@ func (mb *MobileNode) Disconnect() error {
 	if err := mb.connectionManager.Disconnect(0); err != nil {
 		return fmt.Errorf("could not disconnect: %w", err)
 	}
+	mb.onDemand.disconnected()
 
 	return nil
 }
@@ -675,7 +743,7 @@ func (mb *MobileNode) WaitUntilDies() error {
 func (mb *MobileNode) OverrideWireguardConnection(wgTunnelSetup WireguardTunnelSetup) {
 	wireguard.Bootstrap()
 
-	factory := func() (connection.Connection, error) {
+	factory := func(states connection.StateHandler) (connection.Connection, error) {
 		opts := wireGuardOptions{
 			statsUpdateInterval: 1 * time.Second,
 			handshakeTimeout:    1 * time.Minute,
@@ -683,6 +751,7 @@ func (mb *MobileNode) OverrideWireguardConnection(wgTunnelSetup WireguardTunnelS
 
 		return NewWireGuardConnection(
 			opts,
+			states,
 			newWireguardDevice(wgTunnelSetup),
 			mb.ipResolver,
 			wireguard_connection.NewHandshakeWaiter(),
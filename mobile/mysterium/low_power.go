@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mysterium
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/power"
+)
+
+const (
+	// lowPowerBatchInterval is how often batched callbacks are delivered in low-power mode.
+	lowPowerBatchInterval = 30 * time.Second
+	// lowPowerProposalsCacheTTL is how long proposals are served from cache in low-power mode.
+	lowPowerProposalsCacheTTL = 5 * time.Minute
+)
+
+// SetLowPowerMode should be called when the host app enters or leaves battery-saver state.
+// In low-power mode keepalive, statistics and invoice intervals are lengthened, frequent callbacks
+// (statistics, balance) are delivered in batches and proposal refresh and quality reporting are paused.
+func (mb *MobileNode) SetLowPowerMode(enabled bool) {
+	mb.batcher.setLowPower(enabled)
+	mb.eventBus.Publish(power.AppTopicPowerMode, power.AppEventPowerMode{LowPower: enabled})
+}
+
+// IsLowPowerMode returns whether the node runs in low-power mode.
+func (mb *MobileNode) IsLowPowerMode() bool {
+	return mb.powerMode.LowPower()
+}
+
+// callbackBatcher delivers callbacks immediately, or only the latest one of each kind periodically in low-power mode.
+type callbackBatcher struct {
+	interval time.Duration
+
+	mu       sync.Mutex
+	nextKey  int
+	pending  map[int]func()
+	order    []int
+	lowPower bool
+	stop     chan struct{}
+}
+
+func newCallbackBatcher(interval time.Duration) *callbackBatcher {
+	return &callbackBatcher{
+		interval: interval,
+		pending:  make(map[int]func()),
+	}
+}
+
+// newKey returns a key under which deliveries of a single callback are coalesced.
+func (b *callbackBatcher) newKey() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextKey++
+	return b.nextKey
+}
+
+func (b *callbackBatcher) deliver(key int, fn func()) {
+	b.mu.Lock()
+	if !b.lowPower {
+		b.mu.Unlock()
+		fn()
+		return
+	}
+
+	if _, ok := b.pending[key]; !ok {
+		b.order = append(b.order, key)
+	}
+	b.pending[key] = fn
+	b.mu.Unlock()
+}
+
+func (b *callbackBatcher) setLowPower(enabled bool) {
+	b.mu.Lock()
+	if b.lowPower == enabled {
+		b.mu.Unlock()
+		return
+	}
+	b.lowPower = enabled
+
+	if enabled {
+		b.stop = make(chan struct{})
+		go b.run(b.stop)
+		b.mu.Unlock()
+		return
+	}
+
+	close(b.stop)
+	b.mu.Unlock()
+	b.flush()
+}
+
+func (b *callbackBatcher) run(stop chan struct{}) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			b.flush()
+		}
+	}
+}
+
+func (b *callbackBatcher) flush() {
+	b.mu.Lock()
+	fns := make([]func(), 0, len(b.order))
+	for _, key := range b.order {
+		fns = append(fns, b.pending[key])
+	}
+	b.pending = make(map[int]func())
+	b.order = nil
+	b.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mysterium
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallbackBatcher_DeliversImmediatelyInNormalMode(t *testing.T) {
+	b := newCallbackBatcher(time.Hour)
+	key := b.newKey()
+
+	var got []int
+	b.deliver(key, func() { got = append(got, 1) })
+	b.deliver(key, func() { got = append(got, 2) })
+
+	assert.Equal(t, []int{1, 2}, got)
+}
+
+func TestCallbackBatcher_CoalescesInLowPowerMode(t *testing.T) {
+	b := newCallbackBatcher(time.Hour)
+	stats, balance := b.newKey(), b.newKey()
+	b.setLowPower(true)
+
+	var got []string
+	b.deliver(stats, func() { got = append(got, "stats 1") })
+	b.deliver(balance, func() { got = append(got, "balance 1") })
+	b.deliver(stats, func() { got = append(got, "stats 2") })
+	assert.Empty(t, got)
+
+	b.flush()
+	assert.Equal(t, []string{"stats 2", "balance 1"}, got)
+
+	b.deliver(stats, func() { got = append(got, "stats 3") })
+	b.setLowPower(false)
+	assert.Equal(t, []string{"stats 2", "balance 1", "stats 3"}, got)
+
+	b.deliver(stats, func() { got = append(got, "stats 4") })
+	assert.Equal(t, []string{"stats 2", "balance 1", "stats 3", "stats 4"}, got)
+}
@@ -0,0 +1,446 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mysterium
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+)
+
+const onDemandConfigFilename = "on-demand.json"
+
+// Network types reported by the platform to NetworkChanged.
+const (
+	NetworkTypeWiFi     = "wifi"
+	NetworkTypeCellular = "cellular"
+	NetworkTypeEthernet = "ethernet"
+	NetworkTypeNone     = "none"
+)
+
+// Actions taken by the node without user interaction.
+const (
+	OnDemandActionConnect    = "connect"
+	OnDemandActionDisconnect = "disconnect"
+	OnDemandActionReconnect  = "reconnect"
+	OnDemandActionIgnore     = "ignore"
+)
+
+// OnDemandConfig describes when the node connects or disconnects without user interaction.
+type OnDemandConfig struct {
+	Enabled bool
+	// TrustedSSIDs is a comma separated list of Wi-Fi networks on which the connection is stopped.
+	TrustedSSIDs string
+	// UntrustedSSIDs is a comma separated list of Wi-Fi networks on which the connection is started.
+	// If empty, the connection is started on any Wi-Fi network which is not trusted.
+	UntrustedSSIDs string
+	// ConnectOnCellular starts the connection on cellular networks.
+	ConnectOnCellular bool
+	// Domains is a comma separated list of domains, including their subdomains,
+	// requests to which start the connection, e.g. "example.com,*.example.org".
+	Domains string
+	// ReconnectAfterSleepSeconds forces a reconnect on wake up if the device slept longer, 0 disables it.
+	// The connection is always restored on wake up if it was lost during sleep.
+	ReconnectAfterSleepSeconds int
+	// Request is used to connect if the node was not connected before.
+	Request *ConnectRequest
+}
+
+// DefaultOnDemandConfig returns disabled on-demand configuration.
+func DefaultOnDemandConfig() *OnDemandConfig {
+	return &OnDemandConfig{
+		ReconnectAfterSleepSeconds: 120,
+		Request:                    &ConnectRequest{},
+	}
+}
+
+// OnDemandCallback is notified about actions taken by the node without user interaction,
+// so that the app can bring up VpnService or NETunnelProviderManager, or update its UI.
+type OnDemandCallback interface {
+	// OnAction is called before the action is taken, with the reason of it, e.g. "ssid:Cafe", "domain:example.com" or "wake".
+	OnAction(action, reason string)
+	// OnActionFailed is called if the action could not be taken.
+	OnActionFailed(action, reason, errorMessage string)
+}
+
+// onDemandRule is an on-demand rule shaped after NEOnDemandRule.
+type onDemandRule struct {
+	Action        string   `json:"action"`
+	InterfaceType string   `json:"interface_type,omitempty"`
+	SSIDs         []string `json:"ssids,omitempty"`
+	Domains       []string `json:"domains,omitempty"`
+}
+
+// onDemand takes on-demand actions and restores connections after sleep.
+type onDemand struct {
+	path       string
+	connect    func(req *ConnectRequest) *ConnectResponse
+	disconnect func() error
+	state      func() connectionstate.State
+	run        func(func())
+	// allowConnect checks whether the connection can be started without user interaction.
+	allowConnect func() error
+
+	mu            sync.Mutex
+	config        OnDemandConfig
+	callback      OnDemandCallback
+	lastRequest   *ConnectRequest
+	wantConnected bool
+	sleptAt       time.Time
+
+	actions sync.Mutex
+}
+
+func newOnDemand(dataDir string, connect func(req *ConnectRequest) *ConnectResponse, disconnect func() error, state func() connectionstate.State) *onDemand {
+	o := &onDemand{
+		path:       dataDir + "/" + onDemandConfigFilename,
+		connect:    connect,
+		disconnect: disconnect,
+		state:      state,
+		run:        func(action func()) { go action() },
+		config:     *DefaultOnDemandConfig(),
+	}
+
+	data, err := os.ReadFile(o.path)
+	if err == nil {
+		err = json.Unmarshal(data, &o.config)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn().Err(err).Msg("Could not load on-demand configuration, it is disabled")
+		o.config = *DefaultOnDemandConfig()
+	}
+	return o
+}
+
+func (o *onDemand) setConfig(cfg OnDemandConfig) error {
+	if cfg.ReconnectAfterSleepSeconds < 0 {
+		return errors.New("reconnect after sleep can not be negative")
+	}
+	if cfg.Request == nil {
+		cfg.Request = &ConnectRequest{}
+	}
+	if cfg.Enabled && cfg.Request.IdentityAddress == "" {
+		return errors.New("on-demand connection request must have an identity")
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(o.path, data, 0600); err != nil {
+		return fmt.Errorf("could not save on-demand configuration: %w", err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.config = cfg
+	return nil
+}
+
+func (o *onDemand) getConfig() OnDemandConfig {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	cfg := o.config
+	request := *cfg.Request
+	cfg.Request = &request
+	return cfg
+}
+
+func (o *onDemand) setCallback(cb OnDemandCallback) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.callback = cb
+}
+
+// connected remembers the request of the connection established by the user or on demand.
+func (o *onDemand) connected(req *ConnectRequest) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	request := *req
+	o.lastRequest = &request
+	o.wantConnected = true
+}
+
+func (o *onDemand) disconnected() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.wantConnected = false
+}
+
+// evaluate decides what to do after switching to the given network.
+func (cfg OnDemandConfig) evaluate(networkType, ssid string) (action, reason string) {
+	if !cfg.Enabled {
+		return OnDemandActionIgnore, ""
+	}
+
+	switch networkType {
+	case NetworkTypeWiFi:
+		if containsItem(cfg.TrustedSSIDs, ssid) {
+			return OnDemandActionDisconnect, "ssid:" + ssid
+		}
+		if cfg.UntrustedSSIDs == "" || containsItem(cfg.UntrustedSSIDs, ssid) {
+			return OnDemandActionConnect, "ssid:" + ssid
+		}
+	case NetworkTypeCellular:
+		if cfg.ConnectOnCellular {
+			return OnDemandActionConnect, "cellular"
+		}
+	}
+	return OnDemandActionIgnore, ""
+}
+
+// matchesDomain checks whether the domain or one of its parents is listed.
+func (cfg OnDemandConfig) matchesDomain(domain string) bool {
+	if !cfg.Enabled {
+		return false
+	}
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	for _, listed := range splitItems(cfg.Domains) {
+		listed = strings.TrimPrefix(strings.ToLower(listed), "*.")
+		if domain == listed || strings.HasSuffix(domain, "."+listed) {
+			return true
+		}
+	}
+	return false
+}
+
+// rules returns the configuration as NEOnDemandRule list, evaluated in order.
+func (cfg OnDemandConfig) rules() []onDemandRule {
+	rules := []onDemandRule{}
+	if !cfg.Enabled {
+		return rules
+	}
+
+	if trusted := splitItems(cfg.TrustedSSIDs); len(trusted) > 0 {
+		rules = append(rules, onDemandRule{Action: OnDemandActionDisconnect, InterfaceType: NetworkTypeWiFi, SSIDs: trusted})
+	}
+	rules = append(rules, onDemandRule{Action: OnDemandActionConnect, InterfaceType: NetworkTypeWiFi, SSIDs: splitItems(cfg.UntrustedSSIDs)})
+	if cfg.ConnectOnCellular {
+		rules = append(rules, onDemandRule{Action: OnDemandActionConnect, InterfaceType: NetworkTypeCellular})
+	}
+	if domains := splitItems(cfg.Domains); len(domains) > 0 {
+		rules = append(rules, onDemandRule{Action: "evaluate", Domains: domains})
+	}
+	return append(rules, onDemandRule{Action: OnDemandActionIgnore})
+}
+
+func (o *onDemand) networkChanged(networkType, ssid string) string {
+	action, reason := o.getConfig().evaluate(networkType, ssid)
+	switch action {
+	case OnDemandActionConnect:
+		if o.isActive() {
+			return OnDemandActionIgnore
+		}
+		o.takeAction(action, reason)
+	case OnDemandActionDisconnect:
+		if !o.isActive() {
+			return OnDemandActionIgnore
+		}
+		o.takeAction(action, reason)
+	}
+	return action
+}
+
+func (o *onDemand) domainRequested(domain string) bool {
+	if !o.getConfig().matchesDomain(domain) {
+		return false
+	}
+	if !o.isActive() {
+		o.takeAction(OnDemandActionConnect, "domain:"+domain)
+	}
+	return true
+}
+
+func (o *onDemand) sleep() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.sleptAt = time.Now()
+}
+
+func (o *onDemand) wake() {
+	o.mu.Lock()
+	sleptAt, wantConnected := o.sleptAt, o.wantConnected
+	reconnectAfter := time.Duration(o.config.ReconnectAfterSleepSeconds) * time.Second
+	o.sleptAt = time.Time{}
+	o.mu.Unlock()
+
+	if !wantConnected {
+		return
+	}
+
+	if !o.isActive() {
+		o.takeAction(OnDemandActionConnect, "wake")
+		return
+	}
+	if reconnectAfter > 0 && !sleptAt.IsZero() && time.Since(sleptAt) >= reconnectAfter {
+		o.takeAction(OnDemandActionReconnect, "wake")
+	}
+}
+
+func (o *onDemand) isActive() bool {
+	return isActiveState(o.state())
+}
+
+func isActiveState(state connectionstate.State) bool {
+	switch state {
+	case connectionstate.Connected, connectionstate.Connecting, connectionstate.Reconnecting, connectionstate.StateOnHold:
+		return true
+	default:
+		return false
+	}
+}
+
+// request returns the request of the last connection, or the configured one.
+func (o *onDemand) request() *ConnectRequest {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	req := *o.config.Request
+	if o.lastRequest != nil {
+		req = *o.lastRequest
+	}
+	return &req
+}
+
+func (o *onDemand) takeAction(action, reason string) {
+	o.mu.Lock()
+	cb := o.callback
+	o.mu.Unlock()
+
+	log.Info().Msgf("Taking on-demand action %s: %s", action, reason)
+	if cb != nil {
+		cb.OnAction(action, reason)
+	}
+
+	o.run(func() {
+		o.actions.Lock()
+		defer o.actions.Unlock()
+
+		if err := o.perform(action); err != nil {
+			log.Error().Err(err).Msgf("Could not take on-demand action %s", action)
+			if cb != nil {
+				cb.OnActionFailed(action, reason, err.Error())
+			}
+		}
+	})
+}
+
+func (o *onDemand) perform(action string) error {
+	if action == OnDemandActionConnect || action == OnDemandActionReconnect {
+		if o.allowConnect != nil {
+			if err := o.allowConnect(); err != nil {
+				return err
+			}
+		}
+	}
+	if action == OnDemandActionDisconnect || action == OnDemandActionReconnect {
+		if err := o.disconnect(); err != nil {
+			return err
+		}
+	}
+	if action == OnDemandActionConnect || action == OnDemandActionReconnect {
+		req := o.request()
+		if req.IdentityAddress == "" {
+			return errors.New("no connection to restore")
+		}
+		if resp := o.connect(req); resp.ErrorCode != "" {
+			return fmt.Errorf("%s: %s", resp.ErrorCode, resp.ErrorMessage)
+		}
+	}
+	return nil
+}
+
+func splitItems(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func containsItem(list, item string) bool {
+	for _, listed := range splitItems(list) {
+		if listed == item {
+			return true
+		}
+	}
+	return false
+}
+
+// SetOnDemandConfig saves on-demand configuration.
+func (mb *MobileNode) SetOnDemandConfig(cfg *OnDemandConfig) error {
+	if cfg == nil {
+		return errors.New("on-demand configuration is required")
+	}
+	return mb.onDemand.setConfig(*cfg)
+}
+
+// GetOnDemandConfig returns current on-demand configuration.
+func (mb *MobileNode) GetOnDemandConfig() *OnDemandConfig {
+	cfg := mb.onDemand.getConfig()
+	return &cfg
+}
+
+// GetOnDemandRules returns on-demand configuration as a JSON list of rules evaluated in order,
+// ready to be converted into NEOnDemandRule objects: "connect", "disconnect" and "ignore" rules
+// match interface type and SSIDs, "evaluate" rules connect if needed when one of domains is requested.
+func (mb *MobileNode) GetOnDemandRules() ([]byte, error) {
+	return json.Marshal(mb.onDemand.getConfig().rules())
+}
+
+// RegisterOnDemandCallback registers callback which is notified about on-demand actions.
+func (mb *MobileNode) RegisterOnDemandCallback(cb OnDemandCallback) {
+	mb.onDemand.setCallback(cb)
+}
+
+// NetworkChanged must be called by the platform when the device switches to another network,
+// with one of NetworkType* types and SSID of Wi-Fi network. It returns the action taken.
+// The provider service is started or stopped to match the network constraints.
+func (mb *MobileNode) NetworkChanged(networkType, ssid string) string {
+	mb.dataSaver.networkChanged(networkType)
+	if mb.provider != nil {
+		mb.provider.networkChanged(networkType)
+	}
+	return mb.onDemand.networkChanged(networkType, ssid)
+}
+
+// DomainRequested must be called by the platform when a domain is resolved while disconnected.
+// It returns true if the domain starts the connection.
+func (mb *MobileNode) DomainRequested(domain string) bool {
+	return mb.onDemand.domainRequested(domain)
+}
+
+// DeviceSleep must be called by the platform when the device goes to sleep.
+func (mb *MobileNode) DeviceSleep() {
+	mb.onDemand.sleep()
+}
+
+// DeviceWake must be called by the platform when the device wakes up.
+// The connection is restored if it was lost during sleep.
+func (mb *MobileNode) DeviceWake() {
+	mb.onDemand.wake()
+}
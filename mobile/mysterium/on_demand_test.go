@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mysterium
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+)
+
+type onDemandRecorder struct {
+	state connectionstate.State
+	calls []string
+}
+
+func (r *onDemandRecorder) OnAction(action, reason string) {
+	r.calls = append(r.calls, "on "+action+" "+reason)
+}
+
+func (r *onDemandRecorder) OnActionFailed(action, reason, errorMessage string) {
+	r.calls = append(r.calls, "failed "+action+" "+errorMessage)
+}
+
+func newTestOnDemand(t *testing.T) (*onDemand, *onDemandRecorder) {
+	rec := &onDemandRecorder{state: connectionstate.NotConnected}
+	var o *onDemand
+	o = newOnDemand(
+		t.TempDir(),
+		func(req *ConnectRequest) *ConnectResponse {
+			rec.calls = append(rec.calls, "connect "+req.IdentityAddress)
+			rec.state = connectionstate.Connected
+			o.connected(req)
+			return &ConnectResponse{}
+		},
+		func() error {
+			rec.calls = append(rec.calls, "disconnect")
+			rec.state = connectionstate.NotConnected
+			o.disconnected()
+			return nil
+		},
+		func() connectionstate.State { return rec.state },
+	)
+	o.run = func(action func()) { action() }
+	o.setCallback(rec)
+	return o, rec
+}
+
+func TestOnDemandConfig_Evaluate(t *testing.T) {
+	cfg := OnDemandConfig{Enabled: true, TrustedSSIDs: "Home, Office"}
+
+	action, reason := cfg.evaluate(NetworkTypeWiFi, "Office")
+	assert.Equal(t, OnDemandActionDisconnect, action)
+	assert.Equal(t, "ssid:Office", reason)
+
+	action, _ = cfg.evaluate(NetworkTypeWiFi, "Cafe")
+	assert.Equal(t, OnDemandActionConnect, action)
+
+	action, _ = cfg.evaluate(NetworkTypeCellular, "")
+	assert.Equal(t, OnDemandActionIgnore, action)
+
+	cfg.UntrustedSSIDs = "Airport"
+	cfg.ConnectOnCellular = true
+	action, _ = cfg.evaluate(NetworkTypeWiFi, "Cafe")
+	assert.Equal(t, OnDemandActionIgnore, action)
+	action, _ = cfg.evaluate(NetworkTypeWiFi, "Airport")
+	assert.Equal(t, OnDemandActionConnect, action)
+	action, _ = cfg.evaluate(NetworkTypeCellular, "")
+	assert.Equal(t, OnDemandActionConnect, action)
+
+	cfg.Enabled = false
+	action, _ = cfg.evaluate(NetworkTypeWiFi, "Airport")
+	assert.Equal(t, OnDemandActionIgnore, action)
+}
+
+func TestOnDemandConfig_MatchesDomain(t *testing.T) {
+	cfg := OnDemandConfig{Enabled: true, Domains: "example.com, *.example.org"}
+
+	assert.True(t, cfg.matchesDomain("example.com"))
+	assert.True(t, cfg.matchesDomain("www.Example.com."))
+	assert.True(t, cfg.matchesDomain("api.example.org"))
+	assert.False(t, cfg.matchesDomain("badexample.com"))
+	assert.False(t, cfg.matchesDomain("example.net"))
+}
+
+func TestOnDemandConfig_Rules(t *testing.T) {
+	cfg := OnDemandConfig{Enabled: true, TrustedSSIDs: "Home", ConnectOnCellular: true, Domains: "example.com"}
+
+	data, err := json.Marshal(cfg.rules())
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"action": "disconnect", "interface_type": "wifi", "ssids": ["Home"]},
+		{"action": "connect", "interface_type": "wifi"},
+		{"action": "connect", "interface_type": "cellular"},
+		{"action": "evaluate", "domains": ["example.com"]},
+		{"action": "ignore"}
+	]`, string(data))
+
+	assert.Empty(t, OnDemandConfig{}.rules())
+}
+
+func TestOnDemand_NetworkChanged(t *testing.T) {
+	o, rec := newTestOnDemand(t)
+	require.NoError(t, o.setConfig(OnDemandConfig{
+		Enabled:      true,
+		TrustedSSIDs: "Home",
+		Domains:      "example.com",
+		Request:      &ConnectRequest{IdentityAddress: "0x1"},
+	}))
+
+	assert.Equal(t, OnDemandActionConnect, o.networkChanged(NetworkTypeWiFi, "Cafe"))
+	assert.Equal(t, OnDemandActionIgnore, o.networkChanged(NetworkTypeWiFi, "Cafe"))
+	assert.Equal(t, OnDemandActionDisconnect, o.networkChanged(NetworkTypeWiFi, "Home"))
+	assert.True(t, o.domainRequested("www.example.com"))
+	assert.False(t, o.domainRequested("example.net"))
+
+	assert.Equal(t, []string{
+		"on connect ssid:Cafe", "connect 0x1",
+		"on disconnect ssid:Home", "disconnect",
+		"on connect domain:www.example.com", "connect 0x1",
+	}, rec.calls)
+}
+
+func TestOnDemand_ConnectNotAllowed(t *testing.T) {
+	o, rec := newTestOnDemand(t)
+	o.allowConnect = func() error { return errors.New("not allowed") }
+	require.NoError(t, o.setConfig(OnDemandConfig{
+		Enabled:           true,
+		ConnectOnCellular: true,
+		Request:           &ConnectRequest{IdentityAddress: "0x1"},
+	}))
+
+	assert.Equal(t, OnDemandActionConnect, o.networkChanged(NetworkTypeCellular, ""))
+	assert.Equal(t, []string{"on connect cellular", "failed connect not allowed"}, rec.calls)
+}
+
+func TestOnDemand_Wake(t *testing.T) {
+	o, rec := newTestOnDemand(t)
+
+	// not connected before sleep
+	o.sleep()
+	o.wake()
+	assert.Empty(t, rec.calls)
+
+	// connection lost during sleep
+	o.connected(&ConnectRequest{IdentityAddress: "0x2"})
+	o.sleep()
+	o.wake()
+	assert.Equal(t, []string{"on connect wake", "connect 0x2"}, rec.calls)
+
+	// short sleep keeps the connection
+	rec.calls = nil
+	o.sleep()
+	o.wake()
+	assert.Empty(t, rec.calls)
+
+	// long sleep forces reconnect
+	o.sleep()
+	o.sleptAt = time.Now().Add(-time.Hour)
+	o.wake()
+	assert.Equal(t, []string{"on reconnect wake", "disconnect", "connect 0x2"}, rec.calls)
+}
+
+func TestOnDemand_ConfigPersisted(t *testing.T) {
+	o, _ := newTestOnDemand(t)
+	assert.Error(t, o.setConfig(OnDemandConfig{Enabled: true}))
+	require.NoError(t, o.setConfig(OnDemandConfig{Enabled: true, Domains: "example.com", Request: &ConnectRequest{IdentityAddress: "0x1"}}))
+
+	loaded := newOnDemand(o.path[:len(o.path)-len(onDemandConfigFilename)-1], nil, nil, nil).getConfig()
+	assert.True(t, loaded.Enabled)
+	assert.Equal(t, "example.com", loaded.Domains)
+	assert.Equal(t, "0x1", loaded.Request.IdentityAddress)
+}
@@ -24,10 +24,12 @@ import (
 	"time"
 
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/power"
 	"github.com/mysteriumnetwork/node/core/quality"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/money"
 	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/services/datatransfer"
 	"github.com/mysteriumnetwork/node/services/openvpn"
 	"github.com/mysteriumnetwork/node/services/scraping"
@@ -75,6 +77,7 @@ func (r GetProposalsRequest) toFilter() *proposal.Filter {
 		QualityMin:         r.QualityMin,
 		ExcludeUnsupported: true,
 		NATCompatibility:   nat.NATType(r.NATCompatibility),
+		AddressFamilies:    p2p.ReachableAddressFamilies(),
 	}
 }
 
@@ -128,12 +131,14 @@ func newProposalsManager(
 	filterPresetStorage *proposal.FilterPresetStorage,
 	natProber natProber,
 	cacheTTL time.Duration,
+	powerMode *power.Mode,
 ) *proposalsManager {
 	return &proposalsManager{
 		repository:          repository,
 		filterPresetStorage: filterPresetStorage,
 		cacheTTL:            cacheTTL,
 		natProber:           natProber,
+		powerMode:           powerMode,
 	}
 }
 
@@ -144,10 +149,15 @@ type proposalsManager struct {
 	cacheTTL            time.Duration
 	filterPresetStorage *proposal.FilterPresetStorage
 	natProber           natProber
+	powerMode           *power.Mode
 }
 
 func (m *proposalsManager) isCacheStale() bool {
-	return time.Now().After(m.cachedAt.Add(m.cacheTTL))
+	ttl := m.cacheTTL
+	if m.powerMode != nil {
+		ttl = m.powerMode.Interval(ttl, lowPowerProposalsCacheTTL)
+	}
+	return time.Now().After(m.cachedAt.Add(ttl))
 }
 
 func (m *proposalsManager) getCountries(req *GetProposalsRequest) (getCountriesResponse, error) {
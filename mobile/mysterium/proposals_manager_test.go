@@ -47,6 +47,7 @@ func (s *proposalManagerTestSuite) SetupTest() {
 		nil,
 		&mockNATProber{"none", nil},
 		60*time.Second,
+		nil,
 	)
 }
 
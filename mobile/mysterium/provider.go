@@ -0,0 +1,421 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mysterium
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/node"
+	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/services/wireguard"
+	wireguard_service "github.com/mysteriumnetwork/node/services/wireguard/service"
+)
+
+const providerConfigFilename = "provider.json"
+
+// Reasons why an enabled provider service is not running.
+const (
+	ProviderReasonNetwork = "network"
+	ProviderReasonBattery = "battery"
+)
+
+// providerDNSUpstream is used to resolve consumer queries as Android does not expose system DNS configuration.
+var providerDNSUpstream = []string{"1.1.1.1", "8.8.8.8"}
+
+// ProviderConfig constrains the provider service running on the device.
+type ProviderConfig struct {
+	// MaxPeers limits the number of consumers served at once, 0 means unlimited.
+	MaxPeers int
+	// WiFiOnly stops the service while the device is not on Wi-Fi or ethernet.
+	WiFiOnly bool
+	// ChargingOnly stops the service while the device is on battery.
+	ChargingOnly bool
+}
+
+// DefaultProviderConfig returns configuration serving few peers on Wi-Fi while charging.
+func DefaultProviderConfig() *ProviderConfig {
+	return &ProviderConfig{
+		MaxPeers:     2,
+		WiFiOnly:     true,
+		ChargingOnly: true,
+	}
+}
+
+// ProviderStatus describes the provider service.
+type ProviderStatus struct {
+	// Enabled is true if the service was started by the user.
+	Enabled bool
+	// Running is true while the service accepts consumers.
+	Running bool
+	// Reason is one of ProviderReason* constants if the enabled service is not running.
+	Reason string
+	// Error is the last error of starting or stopping the service.
+	Error string
+	// Sessions is the number of consumers being served.
+	Sessions int
+}
+
+type providerState struct {
+	Config   ProviderConfig `json:"config"`
+	Enabled  bool           `json:"enabled"`
+	Identity string         `json:"identity,omitempty"`
+}
+
+// provider starts and stops the wireguard service as the device conditions change.
+type provider struct {
+	path     string
+	start    func(identityAddress string) (string, error)
+	stop     func(serviceID string) error
+	sessions func(serviceID string) int
+	run      func(func())
+
+	mu          sync.Mutex
+	state       providerState
+	serviceID   string
+	lastErr     error
+	networkType string
+	charging    bool
+
+	actions sync.Mutex
+}
+
+func newProvider(dataDir string, start func(identityAddress string) (string, error), stop func(serviceID string) error, sessions func(serviceID string) int) *provider {
+	p := &provider{
+		path:     dataDir + "/" + providerConfigFilename,
+		start:    start,
+		stop:     stop,
+		sessions: sessions,
+		run:      func(action func()) { go action() },
+		state:    providerState{Config: *DefaultProviderConfig()},
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err == nil {
+		err = json.Unmarshal(data, &p.state)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn().Err(err).Msg("Could not load provider configuration, it is disabled")
+		p.state = providerState{Config: *DefaultProviderConfig()}
+	}
+	return p
+}
+
+func (p *provider) setConfig(cfg ProviderConfig) error {
+	if cfg.MaxPeers < 0 {
+		return errors.New("max peers can not be negative")
+	}
+
+	p.mu.Lock()
+	state := p.state
+	state.Config = cfg
+	p.mu.Unlock()
+
+	return p.save(state)
+}
+
+func (p *provider) getConfig() ProviderConfig {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state.Config
+}
+
+func (p *provider) enable(identityAddress string) error {
+	if identityAddress == "" {
+		return errors.New("provider identity is required")
+	}
+
+	p.mu.Lock()
+	state := p.state
+	state.Enabled = true
+	state.Identity = identityAddress
+	p.mu.Unlock()
+
+	if err := p.save(state); err != nil {
+		return err
+	}
+	return p.apply()
+}
+
+func (p *provider) disable() error {
+	p.mu.Lock()
+	state := p.state
+	state.Enabled = false
+	p.mu.Unlock()
+
+	if err := p.save(state); err != nil {
+		return err
+	}
+	return p.apply()
+}
+
+func (p *provider) save(state providerState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(p.path, data, 0600); err != nil {
+		return fmt.Errorf("could not save provider configuration: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state = state
+	return nil
+}
+
+func (p *provider) networkChanged(networkType string) {
+	p.mu.Lock()
+	p.networkType = networkType
+	p.mu.Unlock()
+	p.reevaluate()
+}
+
+func (p *provider) powerSourceChanged(charging bool) {
+	p.mu.Lock()
+	p.charging = charging
+	p.mu.Unlock()
+	p.reevaluate()
+}
+
+func (p *provider) reevaluate() {
+	p.run(func() {
+		if err := p.apply(); err != nil {
+			log.Error().Err(err).Msg("Could not apply provider conditions")
+		}
+	})
+}
+
+// reason returns why the service can not run in the current device conditions.
+func (p *provider) reason() string {
+	cfg := p.state.Config
+	if p.networkType == NetworkTypeNone || p.networkType == "" {
+		return ProviderReasonNetwork
+	}
+	if cfg.WiFiOnly && p.networkType != NetworkTypeWiFi && p.networkType != NetworkTypeEthernet {
+		return ProviderReasonNetwork
+	}
+	if cfg.ChargingOnly && !p.charging {
+		return ProviderReasonBattery
+	}
+	return ""
+}
+
+// apply starts or stops the service to match the configuration and device conditions.
+func (p *provider) apply() error {
+	p.actions.Lock()
+	defer p.actions.Unlock()
+
+	p.mu.Lock()
+	shouldRun := p.state.Enabled && p.reason() == ""
+	identityAddress, serviceID := p.state.Identity, p.serviceID
+	p.mu.Unlock()
+
+	var err error
+	switch {
+	case shouldRun && serviceID == "":
+		log.Info().Msgf("Starting provider service for %s", identityAddress)
+		serviceID, err = p.start(identityAddress)
+	case !shouldRun && serviceID != "":
+		log.Info().Msgf("Stopping provider service %s", serviceID)
+		if err = p.stop(serviceID); err == nil {
+			serviceID = ""
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.serviceID = serviceID
+	p.lastErr = err
+	return err
+}
+
+func (p *provider) status() ProviderStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := ProviderStatus{
+		Enabled: p.state.Enabled,
+		Running: p.serviceID != "",
+	}
+	if status.Enabled && !status.Running {
+		status.Reason = p.reason()
+	}
+	if p.lastErr != nil {
+		status.Error = p.lastErr.Error()
+	}
+	if status.Running {
+		status.Sessions = p.sessions(p.serviceID)
+	}
+	return status
+}
+
+// setProviderDefaults sets defaults of the configuration read by provider services,
+// which are given by command line flags on other platforms.
+func setProviderDefaults() {
+	flags := []cli.Flag{
+		&config.FlagPaymentsHermesPromiseSettleThreshold,
+		&config.FlagPaymentsPromiseSettleMaxFeeThreshold,
+		&config.FlagPaymentsUnsettledMaxAmount,
+		&config.FlagPaymentsHermesPromiseSettleCheckInterval,
+		&config.FlagPaymentsZeroStakeUnsettledAmount,
+		&config.FlagPaymentsHermesMinChannelSize,
+		&config.FlagPaymentsProviderInvoiceFrequency,
+		&config.FlagPaymentsLimitProviderInvoiceFrequency,
+		&config.FlagPaymentsUnpaidInvoiceValue,
+		&config.FlagPaymentsLimitUnpaidInvoiceValue,
+		&config.FlagPaymentsPromptPaymentLatency,
+		&config.FlagPaymentsTrustedConsumerPayments,
+		&config.FlagPortMapping,
+		&config.FlagNATHolePunching,
+		&config.FlagTraversal,
+		&config.FlagPortCheckServers,
+		&config.FlagP2PCGNATDetection,
+		&config.FlagDNSListenPort,
+		&config.FlagSessionIDGenerator,
+		&config.FlagSessionIDSecret,
+	}
+	config.RegisterFlagsPolicy(&flags)
+	config.RegisterFlagsTraffic(&flags)
+	config.RegisterFlagsMonitoring(&flags)
+	config.RegisterFlagsServiceWireguard(&flags)
+	config.RegisterFlagsEgress(&flags)
+	config.RegisterFlagsAbuse(&flags)
+	config.Current.SetDefaultsFromFlags(flags)
+
+	// Services run without root permissions, forwarding consumer traffic in userspace.
+	config.Current.SetDefault(config.FlagUserspace.Name, true)
+	config.Current.SetDefault(config.FlagDNSUpstream.Name, providerDNSUpstream)
+}
+
+// setProviderOptions configures the node to run provider services on the mobile device.
+func setProviderOptions(options *node.Options) {
+	options.Consumer = false
+	options.Mobile = true
+
+	options.Payments.HermesPromiseSettlingThreshold = config.GetFloat64(config.FlagPaymentsHermesPromiseSettleThreshold)
+	options.Payments.MaxFeeSettlingThreshold = config.GetFloat64(config.FlagPaymentsPromiseSettleMaxFeeThreshold)
+	options.Payments.MaxUnSettledAmount = config.GetFloat64(config.FlagPaymentsUnsettledMaxAmount)
+	options.Payments.SettlementRecheckInterval = config.GetDuration(config.FlagPaymentsHermesPromiseSettleCheckInterval)
+	options.Payments.MinAutoSettleAmount = config.GetFloat64(config.FlagPaymentsZeroStakeUnsettledAmount)
+	options.Payments.HermesMinChannelSize = config.GetBigInt(config.FlagPaymentsHermesMinChannelSize)
+	options.Payments.ProviderInvoiceFrequency = config.GetDuration(config.FlagPaymentsProviderInvoiceFrequency)
+	options.Payments.ProviderLimitInvoiceFrequency = config.GetDuration(config.FlagPaymentsLimitProviderInvoiceFrequency)
+	options.Payments.MaxUnpaidInvoiceValue = config.GetBigInt(config.FlagPaymentsUnpaidInvoiceValue)
+	options.Payments.LimitUnpaidInvoiceValue = config.GetBigInt(config.FlagPaymentsLimitUnpaidInvoiceValue)
+	options.Payments.PromptPaymentLatency = config.GetDuration(config.FlagPaymentsPromptPaymentLatency)
+	options.Payments.TrustedConsumerPayments = config.GetInt(config.FlagPaymentsTrustedConsumerPayments)
+
+	options.Monitoring = node.OptionsMonitoring{
+		Interval:        config.GetDuration(config.FlagMonitoringInterval),
+		CPUPercent:      config.GetFloat64(config.FlagMonitoringCPUThreshold),
+		MemoryBytes:     config.GetUInt64(config.FlagMonitoringMemoryThreshold) * 1024 * 1024,
+		FileDescriptors: config.GetInt(config.FlagMonitoringFDThreshold),
+		Goroutines:      config.GetInt(config.FlagMonitoringGoroutineThreshold),
+	}
+}
+
+func (mb *MobileNode) startProviderService(identityAddress string) (string, error) {
+	id, err := mb.servicesManager.Start(identity.FromAddress(identityAddress), wireguard.ServiceType, nil, wireguard_service.GetOptions())
+	return string(id), err
+}
+
+func (mb *MobileNode) stopProviderService(serviceID string) error {
+	err := mb.servicesManager.Stop(service.ID(serviceID))
+	if errors.Is(err, service.ErrNoSuchInstance) {
+		return nil
+	}
+	return err
+}
+
+func (mb *MobileNode) providerSessions(serviceID string) int {
+	count := 0
+	for _, s := range mb.serviceSessions.GetAll() {
+		if s.ServiceID == serviceID {
+			count++
+		}
+	}
+	return count
+}
+
+var errProviderModeDisabled = errors.New("node was not started in provider mode")
+
+// SetProviderConfig saves provider service constraints, they are applied immediately.
+func (mb *MobileNode) SetProviderConfig(cfg *ProviderConfig) error {
+	if mb.provider == nil {
+		return errProviderModeDisabled
+	}
+	if cfg == nil {
+		return errors.New("provider configuration is required")
+	}
+	if err := mb.provider.setConfig(*cfg); err != nil {
+		return err
+	}
+	config.Current.SetUser(config.FlagServiceMaxSessions.Name, cfg.MaxPeers)
+	return mb.provider.apply()
+}
+
+// GetProviderConfig returns current provider service constraints.
+func (mb *MobileNode) GetProviderConfig() *ProviderConfig {
+	if mb.provider == nil {
+		return DefaultProviderConfig()
+	}
+	cfg := mb.provider.getConfig()
+	return &cfg
+}
+
+// StartProvider enables the wireguard provider service for the given unlocked identity.
+// The service runs while the device conditions allow it, including after the node restarts.
+func (mb *MobileNode) StartProvider(identityAddress string) error {
+	if mb.provider == nil {
+		return errProviderModeDisabled
+	}
+	return mb.provider.enable(identityAddress)
+}
+
+// StopProvider disables the provider service.
+func (mb *MobileNode) StopProvider() error {
+	if mb.provider == nil {
+		return errProviderModeDisabled
+	}
+	return mb.provider.disable()
+}
+
+// GetProviderStatus returns provider service status.
+func (mb *MobileNode) GetProviderStatus() *ProviderStatus {
+	if mb.provider == nil {
+		return &ProviderStatus{}
+	}
+	status := mb.provider.status()
+	return &status
+}
+
+// PowerSourceChanged must be called by the platform when the device is plugged in or unplugged.
+func (mb *MobileNode) PowerSourceChanged(charging bool) {
+	if mb.provider != nil {
+		mb.provider.powerSourceChanged(charging)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mysterium
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type providerRecorder struct {
+	calls    []string
+	startErr error
+}
+
+func newTestProvider(t *testing.T, dataDir string) (*provider, *providerRecorder) {
+	rec := &providerRecorder{}
+	p := newProvider(
+		dataDir,
+		func(identityAddress string) (string, error) {
+			rec.calls = append(rec.calls, "start "+identityAddress)
+			if rec.startErr != nil {
+				return "", rec.startErr
+			}
+			return "service-1", nil
+		},
+		func(serviceID string) error {
+			rec.calls = append(rec.calls, "stop "+serviceID)
+			return nil
+		},
+		func(serviceID string) int { return 1 },
+	)
+	p.run = func(action func()) { action() }
+	return p, rec
+}
+
+func TestProvider_RunsOnlyInAllowedConditions(t *testing.T) {
+	p, rec := newTestProvider(t, t.TempDir())
+
+	require.NoError(t, p.enable("0x1"))
+	assert.Empty(t, rec.calls)
+	assert.Equal(t, ProviderStatus{Enabled: true, Reason: ProviderReasonNetwork}, p.status())
+
+	p.networkChanged(NetworkTypeWiFi)
+	assert.Empty(t, rec.calls)
+	assert.Equal(t, ProviderReasonBattery, p.status().Reason)
+
+	p.powerSourceChanged(true)
+	assert.Equal(t, []string{"start 0x1"}, rec.calls)
+	assert.Equal(t, ProviderStatus{Enabled: true, Running: true, Sessions: 1}, p.status())
+
+	p.networkChanged(NetworkTypeCellular)
+	assert.Equal(t, []string{"start 0x1", "stop service-1"}, rec.calls)
+	assert.Equal(t, ProviderReasonNetwork, p.status().Reason)
+
+	require.NoError(t, p.setConfig(ProviderConfig{MaxPeers: 1}))
+	require.NoError(t, p.apply())
+	assert.Equal(t, []string{"start 0x1", "stop service-1", "start 0x1"}, rec.calls)
+
+	require.NoError(t, p.disable())
+	assert.Equal(t, []string{"start 0x1", "stop service-1", "start 0x1", "stop service-1"}, rec.calls)
+	assert.Equal(t, ProviderStatus{}, p.status())
+}
+
+func TestProvider_ReportsStartError(t *testing.T) {
+	p, rec := newTestProvider(t, t.TempDir())
+	rec.startErr = errors.New("provider identity is locked")
+	p.networkChanged(NetworkTypeEthernet)
+	p.powerSourceChanged(true)
+
+	assert.EqualError(t, p.enable("0x1"), "provider identity is locked")
+	assert.Equal(t, ProviderStatus{Enabled: true, Error: "provider identity is locked"}, p.status())
+}
+
+func TestProvider_PersistsState(t *testing.T) {
+	dataDir := t.TempDir()
+	p, _ := newTestProvider(t, dataDir)
+	assert.Equal(t, *DefaultProviderConfig(), p.getConfig())
+	assert.Error(t, p.setConfig(ProviderConfig{MaxPeers: -1}))
+
+	require.NoError(t, p.setConfig(ProviderConfig{MaxPeers: 3, ChargingOnly: true}))
+	require.NoError(t, p.enable("0x1"))
+
+	restored, rec := newTestProvider(t, dataDir)
+	assert.Equal(t, ProviderConfig{MaxPeers: 3, ChargingOnly: true}, restored.getConfig())
+
+	restored.networkChanged(NetworkTypeCellular)
+	restored.powerSourceChanged(true)
+	assert.Equal(t, []string{"start 0x1"}, rec.calls)
+}
@@ -67,7 +67,7 @@ type wireGuardOptions struct {
 }
 
 // NewWireGuardConnection creates a new wireguard connection
-func NewWireGuardConnection(opts wireGuardOptions, device wireguardDevice, ipResolver ip.Resolver, handshakeWaiter wireguard_connection.HandshakeWaiter) (connection.Connection, error) {
+func NewWireGuardConnection(opts wireGuardOptions, states connection.StateHandler, device wireguardDevice, ipResolver ip.Resolver, handshakeWaiter wireguard_connection.HandshakeWaiter) (connection.Connection, error) {
 	privateKey, err := key.GeneratePrivateKey()
 	if err != nil {
 		return nil, err
@@ -75,7 +75,7 @@ func NewWireGuardConnection(opts wireGuardOptions, device wireguardDevice, ipRes
 
 	return &wireguardConnection{
 		done:            make(chan struct{}),
-		stateCh:         make(chan connectionstate.State, 100),
+		states:          states,
 		opts:            opts,
 		device:          device,
 		privateKey:      privateKey,
@@ -88,7 +88,7 @@ type wireguardConnection struct {
 	ports           []int
 	closeOnce       sync.Once
 	done            chan struct{}
-	stateCh         chan connectionstate.State
+	states          connection.StateHandler
 	opts            wireGuardOptions
 	privateKey      string
 	device          wireguardDevice
@@ -98,10 +98,6 @@ type wireguardConnection struct {
 
 var _ connection.Connection = &wireguardConnection{}
 
-func (c *wireguardConnection) State() <-chan connectionstate.State {
-	return c.stateCh
-}
-
 func (c *wireguardConnection) Statistics() (connectionstate.Statistics, error) {
 	stats, err := c.device.Stats()
 	if err != nil {
@@ -125,7 +121,7 @@ func (c *wireguardConnection) Start(ctx context.Context, options connection.Conn
 		return errors.Wrap(err, "could not parse wireguard session config")
 	}
 
-	c.stateCh <- connectionstate.Connecting
+	c.states.Handle(connectionstate.Connecting)
 
 	defer func() {
 		if err != nil {
@@ -148,17 +144,17 @@ func (c *wireguardConnection) Start(ctx context.Context, options connection.Conn
 	}
 
 	log.Debug().Msg("Connected successfully")
-	c.stateCh <- connectionstate.Connected
+	c.states.Handle(connectionstate.Connected)
 	return nil
 }
 
 func (c *wireguardConnection) Stop() {
 	c.closeOnce.Do(func() {
-		c.stateCh <- connectionstate.Disconnecting
+		c.states.Handle(connectionstate.Disconnecting)
 		c.device.Stop()
-		c.stateCh <- connectionstate.NotConnected
+		c.states.Handle(connectionstate.NotConnected)
 
-		close(c.stateCh)
+		c.states.Close()
 		close(c.done)
 	})
 }
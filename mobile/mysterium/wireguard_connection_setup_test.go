@@ -35,7 +35,7 @@ import (
 )
 
 func TestConnectionStartStop(t *testing.T) {
-	conn := newConn(t)
+	conn, states := newConn(t)
 
 	// Start connection.
 	sessionConfig, _ := json.Marshal(newServiceConfig())
@@ -45,8 +45,8 @@ func TestConnectionStartStop(t *testing.T) {
 	})
 
 	assert.NoError(t, err)
-	assert.Equal(t, connectionstate.Connecting, <-conn.State())
-	assert.Equal(t, connectionstate.Connected, <-conn.State())
+	assert.Equal(t, connectionstate.Connecting, nextState(states))
+	assert.Equal(t, connectionstate.Connected, nextState(states))
 	stats, err := conn.Statistics()
 	assert.NoError(t, err)
 	assert.EqualValues(t, 10, stats.BytesSent)
@@ -60,20 +60,20 @@ func TestConnectionStartStop(t *testing.T) {
 }
 
 func TestConnectionStopAfterHandshakeError(t *testing.T) {
-	conn := newConn(t)
+	conn, states := newConn(t)
 	handshakeTimeoutErr := errors.New("handshake timeout")
 	conn.handshakeWaiter = &mockHandshakeWaiter{err: handshakeTimeoutErr}
 	sessionConfig, _ := json.Marshal(newServiceConfig())
 
 	err := conn.Start(context.Background(), connection.ConnectOptions{SessionConfig: sessionConfig})
 	assert.Error(t, handshakeTimeoutErr, err)
-	assert.Equal(t, connectionstate.Connecting, <-conn.State())
-	assert.Equal(t, connectionstate.Disconnecting, <-conn.State())
-	assert.Equal(t, connectionstate.NotConnected, <-conn.State())
+	assert.Equal(t, connectionstate.Connecting, nextState(states))
+	assert.Equal(t, connectionstate.Disconnecting, nextState(states))
+	assert.Equal(t, connectionstate.NotConnected, nextState(states))
 }
 
 func TestConnectionStopOnceAfterHandshakeErrorAndStopCall(t *testing.T) {
-	conn := newConn(t)
+	conn, states := newConn(t)
 	handshakeTimeoutErr := errors.New("handshake timeout")
 	conn.handshakeWaiter = &mockHandshakeWaiter{err: handshakeTimeoutErr}
 	sessionConfig, _ := json.Marshal(newServiceConfig())
@@ -88,18 +88,19 @@ func TestConnectionStopOnceAfterHandshakeErrorAndStopCall(t *testing.T) {
 	<-stopCh
 
 	assert.Error(t, handshakeTimeoutErr, err)
-	assert.Equal(t, connectionstate.Connecting, <-conn.State())
-	assert.Equal(t, connectionstate.Disconnecting, <-conn.State())
-	assert.Equal(t, connectionstate.NotConnected, <-conn.State())
+	assert.Equal(t, connectionstate.Connecting, nextState(states))
+	assert.Equal(t, connectionstate.Disconnecting, nextState(states))
+	assert.Equal(t, connectionstate.NotConnected, nextState(states))
 }
 
-func newConn(t *testing.T) *wireguardConnection {
+func newConn(t *testing.T) (*wireguardConnection, *connection.StateQueue) {
 	opts := wireGuardOptions{
 		statsUpdateInterval: 1 * time.Millisecond,
 	}
-	conn, err := NewWireGuardConnection(opts, &mockWireGuardDevice{}, ip.NewResolverMock("172.44.1.12"), &mockHandshakeWaiter{})
+	states := connection.NewStateQueue(connection.DefaultStateQueueSize)
+	conn, err := NewWireGuardConnection(opts, states, &mockWireGuardDevice{}, ip.NewResolverMock("172.44.1.12"), &mockHandshakeWaiter{})
 	assert.NoError(t, err)
-	return conn.(*wireguardConnection)
+	return conn.(*wireguardConnection), states
 }
 
 func newServiceConfig() wg.ServiceConfig {
@@ -147,3 +148,8 @@ type mockHandshakeWaiter struct {
 func (m *mockHandshakeWaiter) Wait(ctx context.Context, statsFetch func() (wgcfg.Stats, error), timeout time.Duration, stop <-chan struct{}) error {
 	return m.err
 }
+
+func nextState(states *connection.StateQueue) connectionstate.State {
+	state, _ := states.Next(context.Background())
+	return state
+}
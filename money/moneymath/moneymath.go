@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package moneymath provides arithmetic on token amounts which never mutates its arguments.
+// Nil amounts are treated as zero and every result is a newly allocated value,
+// so results can be stored or modified without affecting the operands.
+package moneymath
+
+import (
+	"math/big"
+)
+
+// Zero returns a new zero amount.
+func Zero() *big.Int {
+	return new(big.Int)
+}
+
+// Clone returns a copy of the given amount.
+func Clone(a *big.Int) *big.Int {
+	if a == nil {
+		return Zero()
+	}
+	return new(big.Int).Set(a)
+}
+
+// Add returns a + b.
+func Add(a, b *big.Int) *big.Int {
+	return new(big.Int).Add(orZero(a), orZero(b))
+}
+
+// Sum returns sum of all given amounts.
+func Sum(amounts ...*big.Int) *big.Int {
+	res := Zero()
+	for _, a := range amounts {
+		res.Add(res, orZero(a))
+	}
+	return res
+}
+
+// Sub returns a - b, the result may be negative.
+func Sub(a, b *big.Int) *big.Int {
+	return new(big.Int).Sub(orZero(a), orZero(b))
+}
+
+// SubFloor returns a - b or zero if b is greater than a.
+func SubFloor(a, b *big.Int) *big.Int {
+	res := Sub(a, b)
+	if res.Sign() < 0 {
+		return Zero()
+	}
+	return res
+}
+
+// Abs returns absolute value of a.
+func Abs(a *big.Int) *big.Int {
+	return new(big.Int).Abs(orZero(a))
+}
+
+// MulFloat returns a * f truncated towards zero.
+func MulFloat(a *big.Int, f float64) *big.Int {
+	res, _ := new(big.Float).Mul(new(big.Float).SetInt(orZero(a)), big.NewFloat(f)).Int(nil)
+	return res
+}
+
+// DivInt returns a / d truncated towards zero. It panics if d is zero.
+func DivInt(a *big.Int, d int64) *big.Int {
+	return new(big.Int).Quo(orZero(a), big.NewInt(d))
+}
+
+// Min returns a copy of the smaller amount.
+func Min(a, b *big.Int) *big.Int {
+	if Cmp(a, b) <= 0 {
+		return Clone(a)
+	}
+	return Clone(b)
+}
+
+// Max returns a copy of the greater amount.
+func Max(a, b *big.Int) *big.Int {
+	if Cmp(a, b) >= 0 {
+		return Clone(a)
+	}
+	return Clone(b)
+}
+
+// Cmp compares a and b like big.Int.Cmp does.
+func Cmp(a, b *big.Int) int {
+	return orZero(a).Cmp(orZero(b))
+}
+
+// IsZero returns true if amount is nil or zero.
+func IsZero(a *big.Int) bool {
+	return a == nil || a.Sign() == 0
+}
+
+var zero = big.NewInt(0)
+
+// orZero returns given amount or shared zero value, callers must never modify the result.
+func orZero(a *big.Int) *big.Int {
+	if a == nil {
+		return zero
+	}
+	return a
+}
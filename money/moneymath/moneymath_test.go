@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package moneymath
+
+import (
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// amount is a random, possibly nil or negative token amount used in property tests.
+type amount struct {
+	v *big.Int
+}
+
+func (amount) Generate(r *rand.Rand, _ int) reflect.Value {
+	if r.Intn(10) == 0 {
+		return reflect.ValueOf(amount{})
+	}
+	// Amounts up to ~10^27 wei cover the whole MYST supply.
+	v := new(big.Int).Rand(r, new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil))
+	if r.Intn(4) == 0 {
+		v.Neg(v)
+	}
+	return reflect.ValueOf(amount{v: v})
+}
+
+func snapshot(amounts ...amount) []string {
+	res := make([]string, len(amounts))
+	for i, a := range amounts {
+		if a.v != nil {
+			res[i] = a.v.String()
+		}
+	}
+	return res
+}
+
+func notAliased(res *big.Int, amounts ...amount) bool {
+	for _, a := range amounts {
+		if res == a.v {
+			return false
+		}
+	}
+	return res != zero
+}
+
+func TestOperationsDoNotMutateOrAliasOperands(t *testing.T) {
+	ops := map[string]func(a, b amount) *big.Int{
+		"Add":      func(a, b amount) *big.Int { return Add(a.v, b.v) },
+		"Sum":      func(a, b amount) *big.Int { return Sum(a.v, b.v, a.v) },
+		"Sub":      func(a, b amount) *big.Int { return Sub(a.v, b.v) },
+		"SubFloor": func(a, b amount) *big.Int { return SubFloor(a.v, b.v) },
+		"Abs":      func(a, _ amount) *big.Int { return Abs(a.v) },
+		"Clone":    func(a, _ amount) *big.Int { return Clone(a.v) },
+		"MulFloat": func(a, _ amount) *big.Int { return MulFloat(a.v, 1.1) },
+		"DivInt":   func(a, _ amount) *big.Int { return DivInt(a.v, 3) },
+		"Min":      func(a, b amount) *big.Int { return Min(a.v, b.v) },
+		"Max":      func(a, b amount) *big.Int { return Max(a.v, b.v) },
+	}
+
+	for name, op := range ops {
+		t.Run(name, func(t *testing.T) {
+			property := func(a, b amount) bool {
+				before := snapshot(a, b)
+				res := op(a, b)
+				if !notAliased(res, a, b) {
+					return false
+				}
+
+				// Modifying the result must not leak into operands.
+				res.Add(res, big.NewInt(1))
+				return assert.ObjectsAreEqual(before, snapshot(a, b))
+			}
+			assert.NoError(t, quick.Check(property, nil))
+		})
+	}
+}
+
+func TestSubFloorIsNeverNegative(t *testing.T) {
+	property := func(a, b amount) bool {
+		res := SubFloor(a.v, b.v)
+		if res.Sign() < 0 {
+			return false
+		}
+		if Cmp(a.v, b.v) >= 0 {
+			return Add(res, b.v).Cmp(orZero(a.v)) == 0
+		}
+		return res.Sign() == 0
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestAddSubRoundTrip(t *testing.T) {
+	property := func(a, b amount) bool {
+		return Sub(Add(a.v, b.v), b.v).Cmp(orZero(a.v)) == 0 &&
+			Add(a.v, b.v).Cmp(Add(b.v, a.v)) == 0 &&
+			Sum(a.v, b.v).Cmp(Add(a.v, b.v)) == 0
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestMinMax(t *testing.T) {
+	property := func(a, b amount) bool {
+		return Cmp(Min(a.v, b.v), Max(a.v, b.v)) <= 0 &&
+			Add(Min(a.v, b.v), Max(a.v, b.v)).Cmp(Add(a.v, b.v)) == 0
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
+
+func TestNilIsZero(t *testing.T) {
+	assert.Equal(t, 0, Add(nil, nil).Sign())
+	assert.Equal(t, big.NewInt(5), Sub(big.NewInt(5), nil))
+	assert.Equal(t, big.NewInt(0), SubFloor(nil, big.NewInt(5)))
+	assert.Equal(t, big.NewInt(5), Sum(nil, big.NewInt(2), nil, big.NewInt(3)))
+	assert.True(t, IsZero(nil))
+	assert.True(t, IsZero(Zero()))
+	assert.False(t, IsZero(big.NewInt(1)))
+	assert.Equal(t, big.NewInt(11), MulFloat(big.NewInt(10), 1.1))
+	assert.Equal(t, big.NewInt(3), DivInt(big.NewInt(10), 3))
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package capacity
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpeedTester(t *testing.T) {
+	var uploaded int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/down":
+			w.Write(make([]byte, 1000))
+		case "/up":
+			uploaded, _ = io.Copy(io.Discard, r.Body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tester := NewSpeedTester(server.Client(), []string{server.URL + "/missing", server.URL + "/down"}, []string{server.URL + "/up"})
+	tester.uploadBytes = 500
+	clock := time.Unix(0, 0)
+	tester.now = func() time.Time {
+		clock = clock.Add(time.Millisecond)
+		return clock
+	}
+
+	downlink, err := tester.Downlink()
+	require.NoError(t, err)
+	assert.Equal(t, 8.0, downlink)
+
+	uplink, err := tester.Uplink()
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, uplink)
+	assert.Equal(t, int64(500), uploaded)
+}
+
+func TestSpeedTesterFails(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	tester := NewSpeedTester(server.Client(), []string{server.URL}, nil)
+
+	_, err := tester.Downlink()
+	assert.Error(t, err)
+
+	_, err = tester.Uplink()
+	assert.ErrorIs(t, err, ErrNoTargets)
+}
+
+func TestUptimeTracker(t *testing.T) {
+	dir := t.TempDir()
+	clock := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tracker, err := NewUptimeTracker(dir, 24*time.Hour, 2*time.Minute)
+	require.NoError(t, err)
+	tracker.now = func() time.Time { return clock }
+
+	for i := 0; i < 60; i++ {
+		require.NoError(t, tracker.Tick())
+		clock = clock.Add(time.Minute)
+	}
+	// Node is down for an hour.
+	clock = clock.Add(time.Hour)
+	tracker, err = NewUptimeTracker(dir, 24*time.Hour, 2*time.Minute)
+	require.NoError(t, err)
+	tracker.now = func() time.Time { return clock }
+
+	for i := 0; i < 60; i++ {
+		require.NoError(t, tracker.Tick())
+		clock = clock.Add(time.Minute)
+	}
+	clock = clock.Add(-time.Minute)
+
+	assert.InDelta(t, 118.0/179*100, tracker.Uptime(), 0.01)
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package capacity
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// uptimeTickInterval is how often the uptime is accounted.
+const uptimeTickInterval = time.Minute
+
+// Monitor periodically measures the provider capacity advertised in its proposals.
+type Monitor struct {
+	tester   *SpeedTester
+	uptime   *UptimeTracker
+	interval time.Duration
+
+	mu           sync.RWMutex
+	uplinkMbps   float64
+	downlinkMbps float64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMonitor returns a new capacity monitor. Speed tests run every interval, zero interval disables them.
+func NewMonitor(client *http.Client, downloadURLs, uploadURLs []string, interval time.Duration, dataDir string) (*Monitor, error) {
+	uptime, err := NewUptimeTracker(dataDir, DefaultUptimeWindow, 2*uptimeTickInterval)
+	if err != nil {
+		return nil, err
+	}
+	return &Monitor{
+		tester:   NewSpeedTester(client, downloadURLs, uploadURLs),
+		uptime:   uptime,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}, nil
+}
+
+// Start starts accounting uptime and measuring speed until stopped.
+func (m *Monitor) Start() {
+	go m.trackUptime()
+	if m.interval > 0 {
+		go m.measureSpeed()
+	}
+}
+
+// Stop stops the monitor.
+func (m *Monitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+// Capacity returns the latest measured capacity rounded to whole Mbps and percent,
+// so that small fluctuations do not change the proposal.
+func (m *Monitor) Capacity() *market.Capacity {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return &market.Capacity{
+		UplinkMbps:   math.Round(m.uplinkMbps),
+		DownlinkMbps: math.Round(m.downlinkMbps),
+		Uptime:       math.Round(m.uptime.Uptime()),
+	}
+}
+
+// Measure runs the speed test against the configured targets.
+func (m *Monitor) Measure() {
+	downlink, err := m.tester.Downlink()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to measure downlink capacity")
+	}
+	uplink, err := m.tester.Uplink()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to measure uplink capacity")
+	}
+	log.Info().Msgf("Measured capacity: downlink %.1f Mbps, uplink %.1f Mbps", downlink, uplink)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if downlink > 0 {
+		m.downlinkMbps = downlink
+	}
+	if uplink > 0 {
+		m.uplinkMbps = uplink
+	}
+}
+
+func (m *Monitor) trackUptime() {
+	for {
+		if err := m.uptime.Tick(); err != nil {
+			log.Warn().Err(err).Msg("Failed to account uptime")
+		}
+		select {
+		case <-m.stop:
+			return
+		case <-time.After(uptimeTickInterval):
+		}
+	}
+}
+
+func (m *Monitor) measureSpeed() {
+	for {
+		m.Measure()
+		select {
+		case <-m.stop:
+			return
+		case <-time.After(m.interval):
+		}
+	}
+}
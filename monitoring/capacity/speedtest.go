@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package capacity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// maxDownloadBytes limits the amount of data read from a download target.
+	maxDownloadBytes = 100 << 20
+	// uploadBytes is the amount of data sent to an upload target.
+	uploadBytes = 10 << 20
+)
+
+// ErrNoTargets is returned when no speed test targets are configured.
+var ErrNoTargets = errors.New("no speed test targets")
+
+// SpeedTester measures the link speed by transferring data to and from the configured targets.
+type SpeedTester struct {
+	client       *http.Client
+	downloadURLs []string
+	uploadURLs   []string
+	uploadBytes  int64
+	now          func() time.Time
+}
+
+// NewSpeedTester returns a new speed tester.
+func NewSpeedTester(client *http.Client, downloadURLs, uploadURLs []string) *SpeedTester {
+	return &SpeedTester{
+		client:       client,
+		downloadURLs: downloadURLs,
+		uploadURLs:   uploadURLs,
+		uploadBytes:  uploadBytes,
+		now:          time.Now,
+	}
+}
+
+// Downlink returns the best download speed in Mbps among the download targets.
+func (t *SpeedTester) Downlink() (float64, error) {
+	return t.best(t.downloadURLs, t.download)
+}
+
+// Uplink returns the best upload speed in Mbps among the upload targets.
+func (t *SpeedTester) Uplink() (float64, error) {
+	return t.best(t.uploadURLs, t.upload)
+}
+
+func (t *SpeedTester) best(urls []string, measure func(url string) (float64, error)) (float64, error) {
+	if len(urls) == 0 {
+		return 0, ErrNoTargets
+	}
+
+	var best float64
+	var lastErr error
+	for _, url := range urls {
+		mbps, err := measure(url)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if mbps > best {
+			best = mbps
+		}
+	}
+	if best == 0 && lastErr != nil {
+		return 0, lastErr
+	}
+	return best, nil
+}
+
+func (t *SpeedTester) download(url string) (float64, error) {
+	started := t.now()
+	resp, err := t.client.Get(url)
+	if err != nil {
+		return 0, fmt.Errorf("could not download from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("could not download from %s: unexpected status %s", url, resp.Status)
+	}
+	n, err := io.Copy(io.Discard, io.LimitReader(resp.Body, maxDownloadBytes))
+	if err != nil {
+		return 0, fmt.Errorf("could not download from %s: %w", url, err)
+	}
+	return mbps(n, t.now().Sub(started)), nil
+}
+
+func (t *SpeedTester) upload(url string) (float64, error) {
+	body := bytes.NewReader(make([]byte, t.uploadBytes))
+	started := t.now()
+	resp, err := t.client.Post(url, "application/octet-stream", body)
+	if err != nil {
+		return 0, fmt.Errorf("could not upload to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("could not upload to %s: unexpected status %s", url, resp.Status)
+	}
+	return mbps(t.uploadBytes, t.now().Sub(started)), nil
+}
+
+func mbps(bytes int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(bytes) * 8 / elapsed.Seconds() / 1e6
+}
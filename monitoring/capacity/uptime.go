@@ -0,0 +1,155 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package capacity
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultUptimeWindow is the period over which uptime is calculated.
+const DefaultUptimeWindow = 30 * 24 * time.Hour
+
+type uptimeRecord struct {
+	FirstSeen time.Time `json:"first_seen"`
+	// Online holds seconds the node was running, keyed by the unix time of the hour.
+	Online map[int64]float64 `json:"online"`
+}
+
+// UptimeTracker keeps the rolling uptime of the node. Time is accounted only
+// between consecutive ticks, so periods when the node was down are not counted.
+type UptimeTracker struct {
+	window  time.Duration
+	maxStep time.Duration
+	file    string
+	now     func() time.Time
+
+	mu       sync.Mutex
+	record   uptimeRecord
+	lastTick time.Time
+}
+
+// NewUptimeTracker returns an uptime tracker persisting its state in the given directory.
+// Ticks further apart than maxStep are treated as downtime.
+func NewUptimeTracker(dir string, window, maxStep time.Duration) (*UptimeTracker, error) {
+	t := &UptimeTracker{
+		window:  window,
+		maxStep: maxStep,
+		file:    filepath.Join(dir, "uptime.json"),
+		now:     time.Now,
+		record:  uptimeRecord{Online: make(map[int64]float64)},
+	}
+	if err := t.read(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Tick marks the node as running since the previous tick.
+func (t *UptimeTracker) Tick() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if t.record.FirstSeen.IsZero() {
+		t.record.FirstSeen = now
+	}
+	if !t.lastTick.IsZero() {
+		if step := now.Sub(t.lastTick); step > 0 && step <= t.maxStep {
+			t.record.Online[hour(now)] += step.Seconds()
+		}
+	}
+	t.lastTick = now
+
+	expired := hour(now.Add(-t.window))
+	for slot := range t.record.Online {
+		if slot < expired {
+			delete(t.record.Online, slot)
+		}
+	}
+	return t.write()
+}
+
+// Uptime returns the percentage of time the node was running within the window.
+func (t *UptimeTracker) Uptime() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.record.FirstSeen.IsZero() {
+		return 0
+	}
+	now := t.now()
+	since := now.Add(-t.window)
+	if t.record.FirstSeen.After(since) {
+		since = t.record.FirstSeen
+	}
+	period := now.Sub(since).Seconds()
+	if period <= 0 {
+		return 0
+	}
+
+	var online float64
+	for slot, seconds := range t.record.Online {
+		if slot >= hour(since) {
+			online += seconds
+		}
+	}
+	if online >= period {
+		return 100
+	}
+	return online / period * 100
+}
+
+func hour(t time.Time) int64 {
+	return t.Truncate(time.Hour).Unix()
+}
+
+func (t *UptimeTracker) read() error {
+	data, err := os.ReadFile(t.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read uptime: %w", err)
+	}
+
+	var record uptimeRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return fmt.Errorf("could not parse uptime: %w", err)
+	}
+	if record.Online == nil {
+		record.Online = make(map[int64]float64)
+	}
+	t.record = record
+	return nil
+}
+
+func (t *UptimeTracker) write() error {
+	data, err := json.Marshal(t.record)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(t.file, data, 0600); err != nil {
+		return fmt.Errorf("could not write uptime: %w", err)
+	}
+	return nil
+}
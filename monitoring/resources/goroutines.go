@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package resources
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"regexp"
+	"runtime/pprof"
+	"strconv"
+)
+
+// serviceLabel is the goroutine profiler label goroutines of services are tagged with.
+const serviceLabel = "service"
+
+var (
+	profileCount = regexp.MustCompile(`^(\d+) @`)
+	profileLabel = regexp.MustCompile(`"` + serviceLabel + `":"([^"]*)"`)
+)
+
+// Do runs f with goroutine labels of the service, goroutines started by f inherit them
+// and are accounted to the service.
+func Do(serviceID string, f func()) {
+	pprof.Do(context.Background(), pprof.Labels(serviceLabel, serviceID), func(context.Context) {
+		f()
+	})
+}
+
+// goroutinesByService counts labelled goroutines per service ID.
+func goroutinesByService() (map[string]int, error) {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	count := 0
+	scanner := bufio.NewScanner(&buf)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := profileCount.FindStringSubmatch(line); m != nil {
+			count, _ = strconv.Atoi(m[1])
+			continue
+		}
+		if m := profileLabel.FindStringSubmatch(line); m != nil {
+			counts[m[1]] += count
+			count = 0
+		}
+	}
+	return counts, scanner.Err()
+}
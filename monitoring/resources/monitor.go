@@ -0,0 +1,210 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package resources
+
+import (
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// clockTicks is the USER_HZ, the unit of process CPU times, which is 100 on all supported architectures.
+const clockTicks = 100
+
+// processStat is resource usage of a process read from the OS.
+type processStat struct {
+	pid      int
+	ppid     int
+	name     string
+	cpuTicks uint64
+	rss      uint64
+	fds      int
+}
+
+type cpuSample struct {
+	ticks uint64
+	at    time.Time
+}
+
+type breachKey struct {
+	scope    string
+	resource string
+}
+
+// Monitor periodically collects resource usage of the node and its services, and publishes
+// events when usage crosses the thresholds, so that capacity can be planned before it runs out.
+type Monitor struct {
+	services   func() []Service
+	publisher  eventbus.Publisher
+	thresholds Thresholds
+	interval   time.Duration
+	now        func() time.Time
+
+	mu       sync.RWMutex
+	last     Snapshot
+	cpu      map[int]cpuSample
+	breached map[breachKey]bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMonitor returns a new resource monitor collecting usage of the listed services every interval.
+func NewMonitor(services func() []Service, publisher eventbus.Publisher, thresholds Thresholds, interval time.Duration) *Monitor {
+	return &Monitor{
+		services:   services,
+		publisher:  publisher,
+		thresholds: thresholds,
+		interval:   interval,
+		now:        time.Now,
+		cpu:        make(map[int]cpuSample),
+		breached:   make(map[breachKey]bool),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start starts collecting usage until stopped.
+func (m *Monitor) Start() {
+	go func() {
+		for {
+			m.Collect()
+			select {
+			case <-m.stop:
+				return
+			case <-time.After(m.interval):
+			}
+		}
+	}()
+}
+
+// Stop stops the monitor.
+func (m *Monitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+// Snapshot returns the last collected usage.
+func (m *Monitor) Snapshot() Snapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.last
+}
+
+// Collect collects resource usage and checks it against the thresholds.
+func (m *Monitor) Collect() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	measured := make(map[int]bool)
+	snapshot := Snapshot{At: now, Node: Usage{Goroutines: runtime.NumGoroutine()}}
+
+	self, err := readProcess(os.Getpid())
+	if err != nil {
+		snapshot.Error = err.Error()
+	} else {
+		snapshot.Node.add(m.measure(self, now, measured))
+	}
+
+	// Node usage includes usage of all its child processes.
+	children, err := readChildren(os.Getpid())
+	if err != nil && snapshot.Error == "" {
+		snapshot.Error = err.Error()
+	}
+	childUsage := make(map[int]Usage, len(children))
+	for _, child := range children {
+		usage := m.measure(child, now, measured)
+		childUsage[child.pid] = usage
+		snapshot.Node.add(usage)
+	}
+
+	goroutines, err := goroutinesByService()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to count goroutines of services")
+	}
+
+	accounted := make(map[int]bool)
+	for _, service := range m.services() {
+		usage := ServiceUsage{Service: service, Usage: Usage{Goroutines: goroutines[service.ID]}}
+		for _, child := range children {
+			if service.Process != "" && child.name == service.Process && !accounted[child.pid] {
+				accounted[child.pid] = true
+				usage.add(childUsage[child.pid])
+			}
+		}
+		snapshot.Services = append(snapshot.Services, usage)
+	}
+
+	for pid := range m.cpu {
+		if !measured[pid] {
+			delete(m.cpu, pid)
+		}
+	}
+
+	m.checkThresholds("", "", snapshot.Node)
+	for _, usage := range snapshot.Services {
+		m.checkThresholds(usage.ID, usage.Type, usage.Usage)
+	}
+
+	m.last = snapshot
+	return snapshot
+}
+
+// measure returns usage of the process, CPU usage is averaged since the previous collection.
+func (m *Monitor) measure(p processStat, now time.Time, measured map[int]bool) Usage {
+	measured[p.pid] = true
+	usage := Usage{MemoryBytes: p.rss, FileDescriptors: p.fds, Processes: 1}
+
+	if prev, ok := m.cpu[p.pid]; ok && now.After(prev.at) && p.cpuTicks >= prev.ticks {
+		usage.CPUPercent = float64(p.cpuTicks-prev.ticks) / clockTicks / now.Sub(prev.at).Seconds() * 100
+	}
+	m.cpu[p.pid] = cpuSample{ticks: p.cpuTicks, at: now}
+	return usage
+}
+
+func (m *Monitor) checkThresholds(serviceID, serviceType string, usage Usage) {
+	for resource, values := range m.thresholds.check(usage) {
+		key := breachKey{scope: serviceID, resource: resource}
+		breached := values[0] > values[1]
+		if breached == m.breached[key] {
+			continue
+		}
+		if breached {
+			m.breached[key] = true
+			log.Warn().Msgf("Resource %s usage %.0f is above the threshold %.0f (service %q)", resource, values[0], values[1], serviceID)
+		} else {
+			delete(m.breached, key)
+		}
+
+		m.publisher.Publish(AppTopicThreshold, AppEventThreshold{
+			ServiceID:   serviceID,
+			ServiceType: serviceType,
+			Resource:    resource,
+			Value:       values[0],
+			Limit:       values[1],
+			Breached:    breached,
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package resources
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPublisher struct {
+	mu     sync.Mutex
+	events []AppEventThreshold
+}
+
+func (p *mockPublisher) Publish(topic string, data interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if topic == AppTopicThreshold {
+		p.events = append(p.events, data.(AppEventThreshold))
+	}
+}
+
+func TestMonitor_CountsServiceGoroutines(t *testing.T) {
+	done := make(chan struct{})
+	defer close(done)
+	started := make(chan struct{})
+	Do("service-1", func() {
+		for i := 0; i < 3; i++ {
+			go func() {
+				started <- struct{}{}
+				<-done
+			}()
+		}
+	})
+	for i := 0; i < 3; i++ {
+		<-started
+	}
+
+	services := func() []Service {
+		return []Service{{ID: "service-1", Type: "wireguard"}, {ID: "service-2", Type: "openvpn", Process: "openvpn"}}
+	}
+	monitor := NewMonitor(services, &mockPublisher{}, Thresholds{}, time.Minute)
+
+	snapshot := monitor.Collect()
+	require.Len(t, snapshot.Services, 2)
+	assert.Equal(t, "wireguard", snapshot.Services[0].Type)
+	assert.Equal(t, 3, snapshot.Services[0].Goroutines)
+	assert.Equal(t, 0, snapshot.Services[1].Goroutines)
+	assert.GreaterOrEqual(t, snapshot.Node.Goroutines, 3)
+	assert.Equal(t, snapshot, monitor.Snapshot())
+}
+
+func TestMonitor_PublishesThresholdCrossing(t *testing.T) {
+	publisher := &mockPublisher{}
+	monitor := NewMonitor(func() []Service { return nil }, publisher, Thresholds{Goroutines: 1}, time.Minute)
+
+	monitor.Collect()
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, ResourceGoroutines, publisher.events[0].Resource)
+	assert.Equal(t, "", publisher.events[0].ServiceID)
+	assert.Equal(t, 1.0, publisher.events[0].Limit)
+	assert.True(t, publisher.events[0].Breached)
+
+	// Breach is published only once while it lasts.
+	monitor.Collect()
+	assert.Len(t, publisher.events, 1)
+
+	monitor.thresholds.Goroutines = 1 << 20
+	monitor.Collect()
+	require.Len(t, publisher.events, 2)
+	assert.False(t, publisher.events[1].Breached)
+}
+
+func TestMonitor_MeasuresCPU(t *testing.T) {
+	monitor := NewMonitor(func() []Service { return nil }, &mockPublisher{}, Thresholds{}, time.Minute)
+	start := time.Unix(0, 0)
+	measured := make(map[int]bool)
+
+	usage := monitor.measure(processStat{pid: 1, cpuTicks: 100, rss: 1024, fds: 5}, start, measured)
+	assert.Equal(t, Usage{MemoryBytes: 1024, FileDescriptors: 5, Processes: 1}, usage)
+
+	usage = monitor.measure(processStat{pid: 1, cpuTicks: 150, rss: 1024, fds: 5}, start.Add(2*time.Second), measured)
+	assert.Equal(t, 25.0, usage.CPUPercent)
+	assert.True(t, measured[1])
+}
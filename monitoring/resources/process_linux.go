@@ -0,0 +1,82 @@
+//go:build linux
+
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package resources
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func readProcess(pid int) (processStat, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return processStat{}, err
+	}
+
+	// Process name is in parentheses and may contain spaces, other fields follow the last parenthesis.
+	stat := string(data)
+	open, closing := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || closing < open {
+		return processStat{}, fmt.Errorf("malformed stat of process %d", pid)
+	}
+	fields := strings.Fields(stat[closing+1:])
+	if len(fields) < 22 {
+		return processStat{}, fmt.Errorf("malformed stat of process %d", pid)
+	}
+
+	p := processStat{pid: pid, name: stat[open+1 : closing]}
+	p.ppid, _ = strconv.Atoi(fields[1])
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	p.cpuTicks = utime + stime
+	rssPages, _ := strconv.ParseUint(fields[21], 10, 64)
+	p.rss = rssPages * uint64(os.Getpagesize())
+
+	if fds, err := os.ReadDir(fmt.Sprintf("/proc/%d/fd", pid)); err == nil {
+		p.fds = len(fds)
+	}
+	return p, nil
+}
+
+// readChildren returns the child processes of the given one.
+func readChildren(ppid int) ([]processStat, error) {
+	paths, err := filepath.Glob("/proc/[0-9]*")
+	if err != nil {
+		return nil, err
+	}
+
+	var children []processStat
+	for _, path := range paths {
+		pid, err := strconv.Atoi(filepath.Base(path))
+		if err != nil {
+			continue
+		}
+		// Processes may exit while they are listed.
+		p, err := readProcess(pid)
+		if err != nil || p.ppid != ppid {
+			continue
+		}
+		children = append(children, p)
+	}
+	return children, nil
+}
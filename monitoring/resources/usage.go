@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package resources
+
+import "time"
+
+// AppTopicThreshold is the topic resource threshold breaches and recoveries are published to.
+const AppTopicThreshold = "ResourceThreshold"
+
+// Resources limited by thresholds.
+const (
+	ResourceCPU             = "cpu"
+	ResourceMemory          = "memory"
+	ResourceFileDescriptors = "file_descriptors"
+	ResourceGoroutines      = "goroutines"
+)
+
+// Usage of resources by the node or by a single service. CPU, memory and file descriptors of
+// services are known only for the ones running child processes, goroutines only for in-process ones.
+type Usage struct {
+	CPUPercent      float64
+	MemoryBytes     uint64
+	FileDescriptors int
+	Goroutines      int
+	Processes       int
+}
+
+// Service identifies a running service.
+type Service struct {
+	ID   string
+	Type string
+	// Process is the name of child processes run by the service, empty if it runs in-process only.
+	Process string
+}
+
+// ServiceUsage is resource usage of a single service.
+type ServiceUsage struct {
+	Service
+	Usage
+}
+
+// Snapshot is resource usage collected at once.
+type Snapshot struct {
+	At       time.Time
+	Node     Usage
+	Services []ServiceUsage
+	// Error explains why process usage is missing, e.g. on platforms it's not supported on.
+	Error string
+}
+
+// Thresholds are limits of resources usage, zero values are not limited.
+type Thresholds struct {
+	CPUPercent      float64
+	MemoryBytes     uint64
+	FileDescriptors int
+	Goroutines      int
+}
+
+// AppEventThreshold is published when usage crosses a threshold, both when breaching and recovering.
+type AppEventThreshold struct {
+	// ServiceID is empty for usage of the whole node.
+	ServiceID   string
+	ServiceType string
+	Resource    string
+	Value       float64
+	Limit       float64
+	Breached    bool
+}
+
+// add adds process usage, goroutines are counted separately.
+func (u *Usage) add(other Usage) {
+	u.CPUPercent += other.CPUPercent
+	u.MemoryBytes += other.MemoryBytes
+	u.FileDescriptors += other.FileDescriptors
+	u.Processes += other.Processes
+}
+
+// check returns the used value and the limit of each limited resource.
+func (t Thresholds) check(u Usage) map[string][2]float64 {
+	limits := make(map[string][2]float64)
+	if t.CPUPercent > 0 {
+		limits[ResourceCPU] = [2]float64{u.CPUPercent, t.CPUPercent}
+	}
+	if t.MemoryBytes > 0 {
+		limits[ResourceMemory] = [2]float64{float64(u.MemoryBytes), float64(t.MemoryBytes)}
+	}
+	if t.FileDescriptors > 0 {
+		limits[ResourceFileDescriptors] = [2]float64{float64(u.FileDescriptors), float64(t.FileDescriptors)}
+	}
+	if t.Goroutines > 0 {
+		limits[ResourceGoroutines] = [2]float64{float64(u.Goroutines), float64(t.Goroutines)}
+	}
+	return limits
+}
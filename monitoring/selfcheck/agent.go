@@ -0,0 +1,270 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package selfcheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// AppTopicSelfCheckFailed is a topic for publishing repeated self-check failures of a service.
+const AppTopicSelfCheckFailed = "Self-check failed"
+
+// Target is a provider service checked by the agent.
+type Target struct {
+	ProviderID  string
+	ServiceType string
+}
+
+// Result is an outcome of a single self-check.
+type Result struct {
+	OK bool
+	// Stage is the last stage run, which is the failed one if the check did not pass.
+	Stage string
+	RTT   time.Duration
+	Error string
+}
+
+func (r Result) failed(err error) Result {
+	r.Error = err.Error()
+	return r
+}
+
+// AppEventSelfCheckFailed is published once the self-check of a service fails the configured number of times in a row.
+type AppEventSelfCheckFailed struct {
+	ProviderID  string `json:"provider_id"`
+	ServiceType string `json:"service_type"`
+	Failures    int    `json:"failures"`
+	Stage       string `json:"stage"`
+	Error       string `json:"error"`
+}
+
+// Config holds self-check agent settings.
+type Config struct {
+	// Interval is how often services are checked.
+	Interval time.Duration
+	// Timeout limits a single check of a service.
+	Timeout time.Duration
+	// FailureThreshold is a number of consecutive failures of a service after which the failure is published.
+	FailureThreshold int
+}
+
+// Status is the latest self-check state of a service.
+type Status struct {
+	Target
+	LastCheck           time.Time
+	LastResult          Result
+	ConsecutiveFailures int
+}
+
+// Uptime summarizes self-check history.
+type Uptime struct {
+	Since   time.Time
+	Checks  int
+	Passed  int
+	Records []Record
+}
+
+// Ratio returns the share of passed checks, zero if there were no checks.
+func (u Uptime) Ratio() float64 {
+	if u.Checks == 0 {
+		return 0
+	}
+	return float64(u.Passed) / float64(u.Checks)
+}
+
+type checker interface {
+	Check(ctx context.Context, target Target) Result
+}
+
+type recordStorage interface {
+	Store(record Record) error
+	Since(from time.Time) ([]Record, error)
+}
+
+// Agent periodically checks that running provider services are reachable by consumers,
+// records the outcomes and publishes repeated failures.
+type Agent struct {
+	config    Config
+	targets   func() []Target
+	checker   checker
+	storage   recordStorage
+	publisher eventbus.Publisher
+	now       func() time.Time
+
+	mu       sync.Mutex
+	statuses map[Target]*Status
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewAgent returns a new self-check agent checking services returned by targets.
+func NewAgent(config Config, targets func() []Target, checker checker, storage recordStorage, publisher eventbus.Publisher) *Agent {
+	return &Agent{
+		config:    config,
+		targets:   targets,
+		checker:   checker,
+		storage:   storage,
+		publisher: publisher,
+		now:       time.Now,
+		statuses:  make(map[Target]*Status),
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start runs self-checks periodically until the agent is stopped.
+func (a *Agent) Start() {
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			a.Check()
+		}
+	}
+}
+
+// Stop stops the agent.
+func (a *Agent) Stop() {
+	a.stopOnce.Do(func() {
+		close(a.stop)
+	})
+}
+
+// Check runs the self-check of all running services once.
+func (a *Agent) Check() {
+	targets := a.targets()
+
+	a.mu.Lock()
+	running := make(map[Target]struct{}, len(targets))
+	for _, target := range targets {
+		running[target] = struct{}{}
+	}
+	for target := range a.statuses {
+		if _, ok := running[target]; !ok {
+			delete(a.statuses, target)
+		}
+	}
+	a.mu.Unlock()
+
+	for _, target := range targets {
+		a.check(target)
+	}
+}
+
+func (a *Agent) check(target Target) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.Timeout)
+	defer cancel()
+
+	result := a.checker.Check(ctx, target)
+	now := a.now().UTC()
+
+	record := Record{
+		Time:        now,
+		ProviderID:  target.ProviderID,
+		ServiceType: target.ServiceType,
+		OK:          result.OK,
+		Stage:       result.Stage,
+		RTT:         result.RTT,
+		Error:       result.Error,
+	}
+	record.ID = fmt.Sprintf("%d-%s", now.UnixNano(), target.ServiceType)
+	if err := a.storage.Store(record); err != nil {
+		log.Error().Err(err).Msg("Could not store self-check record")
+	}
+
+	a.mu.Lock()
+	status, ok := a.statuses[target]
+	if !ok {
+		status = &Status{Target: target}
+		a.statuses[target] = status
+	}
+	recovered := result.OK && status.ConsecutiveFailures >= a.config.FailureThreshold
+	status.LastCheck = now
+	status.LastResult = result
+	if result.OK {
+		status.ConsecutiveFailures = 0
+	} else {
+		status.ConsecutiveFailures++
+	}
+	failures := status.ConsecutiveFailures
+	a.mu.Unlock()
+
+	if result.OK {
+		if recovered {
+			log.Info().Msgf("Self-check of %s service passed again", target.ServiceType)
+		}
+		return
+	}
+
+	log.Warn().Msgf("Self-check of %s service failed at %s stage: %s", target.ServiceType, result.Stage, result.Error)
+	if failures == a.config.FailureThreshold {
+		a.publisher.Publish(AppTopicSelfCheckFailed, AppEventSelfCheckFailed{
+			ProviderID:  target.ProviderID,
+			ServiceType: target.ServiceType,
+			Failures:    failures,
+			Stage:       result.Stage,
+			Error:       result.Error,
+		})
+	}
+}
+
+// Statuses returns the latest self-check state of running services.
+func (a *Agent) Statuses() []Status {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	statuses := make([]Status, 0, len(a.statuses))
+	for _, status := range a.statuses {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].ServiceType != statuses[j].ServiceType {
+			return statuses[i].ServiceType < statuses[j].ServiceType
+		}
+		return statuses[i].ProviderID < statuses[j].ProviderID
+	})
+	return statuses
+}
+
+// History returns self-checks run after the given time.
+func (a *Agent) History(since time.Time) (Uptime, error) {
+	records, err := a.storage.Since(since)
+	if err != nil {
+		return Uptime{}, err
+	}
+
+	uptime := Uptime{Since: since.UTC(), Records: records}
+	for _, record := range records {
+		uptime.Checks++
+		if record.OK {
+			uptime.Passed++
+		}
+	}
+	return uptime, nil
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package selfcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/mocks"
+)
+
+type mockChecker struct {
+	results map[Target]Result
+}
+
+func (mc *mockChecker) Check(_ context.Context, target Target) Result {
+	return mc.results[target]
+}
+
+type mockStorage struct {
+	records []Record
+}
+
+func (ms *mockStorage) Store(record Record) error {
+	ms.records = append(ms.records, record)
+	return nil
+}
+
+func (ms *mockStorage) Since(from time.Time) ([]Record, error) {
+	var result []Record
+	for _, record := range ms.records {
+		if !record.Time.Before(from) {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+func TestAgent_PublishesRepeatedFailures(t *testing.T) {
+	wireguard := Target{ProviderID: "0x1", ServiceType: "wireguard"}
+	checker := &mockChecker{results: map[Target]Result{
+		wireguard: {Stage: StageDial, Error: "timeout"},
+	}}
+	bus := mocks.NewEventBus()
+	agent := NewAgent(
+		Config{Interval: time.Minute, Timeout: time.Second, FailureThreshold: 3},
+		func() []Target { return []Target{wireguard} },
+		checker,
+		&mockStorage{},
+		bus,
+	)
+
+	agent.Check()
+	agent.Check()
+	assert.Empty(t, bus.GetEventHistory())
+
+	agent.Check()
+	require.Len(t, bus.GetEventHistory(), 1)
+	assert.Equal(t, AppTopicSelfCheckFailed, bus.GetEventHistory()[0].Topic)
+	assert.Equal(t, AppEventSelfCheckFailed{
+		ProviderID:  "0x1",
+		ServiceType: "wireguard",
+		Failures:    3,
+		Stage:       StageDial,
+		Error:       "timeout",
+	}, bus.GetEventHistory()[0].Event)
+
+	// The failure is published once until the service passes the check again.
+	agent.Check()
+	assert.Len(t, bus.GetEventHistory(), 1)
+
+	checker.results[wireguard] = Result{OK: true, Stage: StagePing, RTT: 20 * time.Millisecond}
+	agent.Check()
+	checker.results[wireguard] = Result{Stage: StageDiscover, Error: ErrNotDiscoverable.Error()}
+	agent.Check()
+	agent.Check()
+	assert.Len(t, bus.GetEventHistory(), 1)
+	agent.Check()
+	assert.Len(t, bus.GetEventHistory(), 2)
+}
+
+func TestAgent_StatusesAndHistory(t *testing.T) {
+	wireguard := Target{ProviderID: "0x1", ServiceType: "wireguard"}
+	scraping := Target{ProviderID: "0x1", ServiceType: "scraping"}
+	checker := &mockChecker{results: map[Target]Result{
+		wireguard: {OK: true, Stage: StagePing, RTT: 20 * time.Millisecond},
+		scraping:  {Stage: StagePing, Error: "invalid pong payload"},
+	}}
+	targets := []Target{wireguard, scraping}
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	agent := NewAgent(
+		Config{Interval: time.Minute, Timeout: time.Second, FailureThreshold: 3},
+		func() []Target { return targets },
+		checker,
+		&mockStorage{},
+		mocks.NewEventBus(),
+	)
+	agent.now = func() time.Time { return now }
+
+	agent.Check()
+	now = now.Add(time.Hour)
+	agent.Check()
+
+	statuses := agent.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, scraping, statuses[0].Target)
+	assert.Equal(t, 2, statuses[0].ConsecutiveFailures)
+	assert.Equal(t, wireguard, statuses[1].Target)
+	assert.Equal(t, 0, statuses[1].ConsecutiveFailures)
+	assert.Equal(t, now, statuses[1].LastCheck)
+
+	uptime, err := agent.History(now.Add(-2 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 4, uptime.Checks)
+	assert.Equal(t, 2, uptime.Passed)
+	assert.Equal(t, 0.5, uptime.Ratio())
+
+	uptime, err = agent.History(now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, uptime.Checks)
+
+	// Services which are not running anymore are forgotten.
+	targets = []Target{wireguard}
+	agent.Check()
+	statuses = agent.Statuses()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, wireguard, statuses[0].Target)
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package selfcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/p2p"
+	"github.com/mysteriumnetwork/node/trace"
+)
+
+// Stages of the self-check, in the order they are run.
+const (
+	StageDiscover = "discover"
+	StageDial     = "dial"
+	StagePing     = "ping"
+)
+
+// ErrNotDiscoverable indicates that the proposal of the service is not found by discovery.
+var ErrNotDiscoverable = errors.New("proposal is not discoverable")
+
+type proposalRepository interface {
+	Proposal(id market.ProposalID) (*proposal.PricedServiceProposal, error)
+}
+
+// Prober checks the provider service the way consumers reach it: the proposal is looked up
+// in discovery, p2p channel is dialed from the test consumer identity and the provider is pinged.
+type Prober struct {
+	proposals proposalRepository
+	dialer    p2p.Dialer
+	pinger    p2p.Pinger
+	consumer  identity.Identity
+}
+
+// NewProber returns a new prober dialing providers from the given test consumer identity.
+func NewProber(proposals proposalRepository, dialer p2p.Dialer, pinger p2p.Pinger, consumer identity.Identity) *Prober {
+	return &Prober{
+		proposals: proposals,
+		dialer:    dialer,
+		pinger:    pinger,
+		consumer:  consumer,
+	}
+}
+
+// Check runs all stages of the self-check against the target service.
+func (p *Prober) Check(ctx context.Context, target Target) Result {
+	providerID := identity.FromAddress(target.ProviderID)
+
+	result := Result{Stage: StageDiscover}
+	prop, err := p.proposals.Proposal(market.ProposalID{ProviderID: target.ProviderID, ServiceType: target.ServiceType})
+	if err != nil {
+		return result.failed(fmt.Errorf("could not get proposal: %w", err))
+	}
+	if prop == nil {
+		return result.failed(ErrNotDiscoverable)
+	}
+	contact, err := p2p.ParseContact(prop.Contacts)
+	if err != nil {
+		return result.failed(fmt.Errorf("invalid proposal contact: %w", err))
+	}
+
+	result.Stage = StageDial
+	channel, err := p.dialer.Dial(ctx, p.consumer, providerID, target.ServiceType, contact, trace.NewTracer("Self-check"))
+	if err != nil {
+		return result.failed(err)
+	}
+	defer channel.Close()
+
+	result.Stage = StagePing
+	rtt, err := p.pinger.Ping(ctx, providerID, target.ServiceType, contact)
+	if err != nil {
+		return result.failed(err)
+	}
+
+	result.OK = true
+	result.RTT = rtt
+	return result
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package selfcheck
+
+import (
+	"errors"
+	"time"
+
+	"github.com/asdine/storm/v3/q"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const recordBucket = "self-checks"
+
+// Record is a stored outcome of a single self-check.
+type Record struct {
+	ID          string `storm:"id"`
+	Time        time.Time
+	ProviderID  string
+	ServiceType string
+	OK          bool
+	Stage       string
+	RTT         time.Duration
+	Error       string
+}
+
+// Storage keeps self-check history.
+type Storage struct {
+	storage storage.Store
+}
+
+// NewStorage returns a new instance of Storage.
+func NewStorage(storage storage.Store) *Storage {
+	return &Storage{
+		storage: storage,
+	}
+}
+
+// Store stores a given self-check record.
+func (s *Storage) Store(record Record) error {
+	return s.storage.Store(recordBucket, &record)
+}
+
+// Since returns records of self-checks run after the given time, oldest first.
+func (s *Storage) Since(from time.Time) (result []Record, err error) {
+	query := storage.Query{
+		Where:   q.Gte("Time", from.UTC()),
+		OrderBy: "Time",
+	}
+
+	err = s.storage.Find(recordBucket, query, &result)
+	if errors.Is(err, storage.ErrNotFound) {
+		return []Record{}, nil
+	}
+	return result, err
+}
+
+// PruneBefore deletes self-check records older than the given time.
+func (s *Storage) PruneBefore(before time.Time) error {
+	return s.storage.DeleteMatching(recordBucket, q.Lt("Time", before.UTC()), new(Record))
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nat
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mysteriumnetwork/node/firewall/iptables"
+	"github.com/mysteriumnetwork/node/utils/cmdutil"
+)
+
+const (
+	tableMangle = "mangle"
+
+	sessionCommentPrefix = "myst-session:"
+	directionSent        = "sent"
+	directionReceived    = "received"
+
+	// sessionMarkBase keeps session connmarks in a range recognisable by external tools, e.g. `conntrack -L --mark`.
+	sessionMarkBase = 0x4d000000
+	sessionMarkMask = 0x00ffffff
+)
+
+// SessionCounters is the session traffic counted by the kernel, from the provider point of view.
+type SessionCounters struct {
+	Sent     uint64
+	Received uint64
+}
+
+// SessionAccounter reads per session traffic counters of session tagged firewall rules.
+type SessionAccounter interface {
+	SessionCounters() (map[string]SessionCounters, error)
+}
+
+// SessionMark returns the connmark used to tag conntrack entries of the given session.
+func SessionMark(sessionID string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(sessionID))
+	return sessionMarkBase | h.Sum32()&sessionMarkMask
+}
+
+// makeSessionAccountingRules tags the session traffic in the mangle table. Rule counters give the bytes
+// counted by the kernel, while connmark tags the conntrack entries of the session.
+func makeSessionAccountingRules(opts Options) (rules []iptables.Rule) {
+	vpnNetwork := opts.VPNNetwork.String()
+	mark := fmt.Sprintf("0x%x", SessionMark(opts.SessionID))
+
+	rules = append(rules, iptables.AppendTo(chainForward).RuleSpec(
+		"--source", vpnNetwork,
+		"--match", "comment", "--comment", sessionComment(opts.SessionID, directionReceived),
+		"--jump", "CONNMARK", "--set-mark", mark,
+		"--table", tableMangle,
+	))
+	rules = append(rules, iptables.AppendTo(chainForward).RuleSpec(
+		"--destination", vpnNetwork,
+		"--match", "comment", "--comment", sessionComment(opts.SessionID, directionSent),
+		"--jump", "CONNMARK", "--set-mark", mark,
+		"--table", tableMangle,
+	))
+
+	return rules
+}
+
+func sessionComment(sessionID, direction string) string {
+	return sessionCommentPrefix + sessionID + ":" + direction
+}
+
+// SessionCounters returns kernel counters of all sessions having accounting rules set up.
+func (svc *serviceIPTables) SessionCounters() (map[string]SessionCounters, error) {
+	out, err := cmdutil.ExecOutput("sudo", "/usr/sbin/iptables", "--table", tableMangle, "--list", chainForward, "--verbose", "--exact", "--numeric")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list session accounting rules")
+	}
+
+	return parseSessionCounters(out), nil
+}
+
+var sessionCommentRegex = regexp.MustCompile(`/\* ` + sessionCommentPrefix + `(\S+):(` + directionSent + `|` + directionReceived + `) \*/`)
+
+// parseSessionCounters parses the output of `iptables --list --verbose --exact`,
+// where the second column of every rule line is a byte counter.
+func parseSessionCounters(output string) map[string]SessionCounters {
+	counters := make(map[string]SessionCounters)
+	for _, line := range strings.Split(output, "\n") {
+		match := sessionCommentRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		bytes, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		c := counters[match[1]]
+		if match[2] == directionSent {
+			c.Sent += bytes
+		} else {
+			c.Received += bytes
+		}
+		counters[match[1]] = c
+	}
+
+	return counters
+}
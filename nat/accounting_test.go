@@ -0,0 +1,67 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nat
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeSessionAccountingRules(t *testing.T) {
+	_, vpnNetwork, _ := net.ParseCIDR("10.182.0.0/24")
+	rules := makeSessionAccountingRules(Options{VPNNetwork: *vpnNetwork, SessionID: "session-1"})
+
+	mark := fmt.Sprintf("0x%x", SessionMark("session-1"))
+	assert.Len(t, rules, 2)
+	assert.Equal(t, []string{
+		"-A", "FORWARD", "--source", "10.182.0.0/24",
+		"--match", "comment", "--comment", "myst-session:session-1:received",
+		"--jump", "CONNMARK", "--set-mark", mark,
+		"--table", "mangle",
+	}, rules[0].ApplyArgs())
+	assert.Equal(t, []string{
+		"-D", "FORWARD", "--destination", "10.182.0.0/24",
+		"--match", "comment", "--comment", "myst-session:session-1:sent",
+		"--jump", "CONNMARK", "--set-mark", mark,
+		"--table", "mangle",
+	}, rules[1].RemoveArgs())
+}
+
+func TestSessionMark(t *testing.T) {
+	assert.Equal(t, SessionMark("session-1"), SessionMark("session-1"))
+	assert.NotEqual(t, SessionMark("session-1"), SessionMark("session-2"))
+	assert.Equal(t, uint32(sessionMarkBase), SessionMark("session-1")&^sessionMarkMask)
+}
+
+func TestParseSessionCounters(t *testing.T) {
+	output := `Chain FORWARD (policy ACCEPT 0 packets, 0 bytes)
+    pkts      bytes target     prot opt in     out     source               destination
+      12     1840 CONNMARK   all  --  *      *       10.182.0.0/24        0.0.0.0/0            /* myst-session:session-1:received */ CONNMARK set 0x4d1a2b3c
+      30    42000 CONNMARK   all  --  *      *       0.0.0.0/0            10.182.0.0/24        /* myst-session:session-1:sent */ CONNMARK set 0x4d1a2b3c
+       1      100 ACCEPT     all  --  *      *       0.0.0.0/0            0.0.0.0/0            /* unrelated */
+       3      300 CONNMARK   all  --  *      *       10.182.1.0/24        0.0.0.0/0            /* myst-session:session-2:received */ CONNMARK set 0x4d000001
+`
+
+	assert.Equal(t, map[string]SessionCounters{
+		"session-1": {Sent: 42000, Received: 1840},
+		"session-2": {Received: 300},
+	}, parseSessionCounters(output))
+}
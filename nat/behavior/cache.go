@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package behavior
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/nat"
+)
+
+// DefaultCacheTTL is a time NAT type detection result is reused for.
+const DefaultCacheTTL = 30 * time.Minute
+
+// Detection is a result of NAT type detection.
+type Detection struct {
+	Type       nat.NATType
+	DetectedAt time.Time
+}
+
+// CachedNATProber reuses the last NAT type detection result, so that repeated queries
+// do not hit STUN servers and still get an answer while VPN connection is established.
+type CachedNATProber struct {
+	next NATProber
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu   sync.Mutex
+	last *Detection
+}
+
+// NewCachedNATProber returns a new instance of CachedNATProber.
+func NewCachedNATProber(next NATProber, ttl time.Duration) *CachedNATProber {
+	return &CachedNATProber{
+		next: next,
+		ttl:  ttl,
+		now:  time.Now,
+	}
+}
+
+// Probe returns cached NAT type if it is fresh enough, otherwise detects it again.
+func (p *CachedNATProber) Probe(ctx context.Context) (nat.NATType, error) {
+	if last, ok := p.Last(); ok && p.now().Sub(last.DetectedAt) < p.ttl {
+		return last.Type, nil
+	}
+
+	return p.Refresh(ctx)
+}
+
+// Refresh detects NAT type ignoring the cached value. If detection is not possible
+// at the moment due to active connection, the last known NAT type is returned.
+func (p *CachedNATProber) Refresh(ctx context.Context) (nat.NATType, error) {
+	natType, err := p.next.Probe(ctx)
+	if err != nil {
+		if last, ok := p.Last(); ok && errors.Is(err, ErrInappropriateState) {
+			return last.Type, nil
+		}
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.last = &Detection{Type: natType, DetectedAt: p.now()}
+
+	return natType, nil
+}
+
+// Last returns the last successful detection result.
+func (p *CachedNATProber) Last() (Detection, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.last == nil {
+		return Detection{}, false
+	}
+	return *p.last, true
+}
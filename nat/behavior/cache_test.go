@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package behavior
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/nat"
+)
+
+type mockProber struct {
+	natType nat.NATType
+	err     error
+	calls   int
+}
+
+func (mp *mockProber) Probe(_ context.Context) (nat.NATType, error) {
+	mp.calls++
+	return mp.natType, mp.err
+}
+
+func TestCachedNATProber_Probe(t *testing.T) {
+	next := &mockProber{natType: nat.NATTypeSymmetric}
+	prober := NewCachedNATProber(next, time.Minute)
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	prober.now = func() time.Time { return now }
+
+	res, err := prober.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, nat.NATTypeSymmetric, res)
+
+	next.natType = nat.NATTypeFullCone
+	res, err = prober.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, nat.NATTypeSymmetric, res)
+	assert.Equal(t, 1, next.calls)
+
+	now = now.Add(time.Minute)
+	res, err = prober.Probe(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, nat.NATTypeFullCone, res)
+	assert.Equal(t, 2, next.calls)
+
+	last, ok := prober.Last()
+	assert.True(t, ok)
+	assert.Equal(t, now, last.DetectedAt)
+}
+
+func TestCachedNATProber_Refresh_FallsBackWhileConnected(t *testing.T) {
+	next := &mockProber{natType: nat.NATTypeRestrictedCone}
+	prober := NewCachedNATProber(next, time.Minute)
+
+	_, err := prober.Refresh(context.Background())
+	assert.NoError(t, err)
+
+	next.err = ErrInappropriateState
+	res, err := prober.Refresh(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, nat.NATTypeRestrictedCone, res)
+
+	next.err = errors.New("stun failed")
+	_, err = prober.Refresh(context.Background())
+	assert.Error(t, err)
+}
+
+func TestCachedNATProber_Refresh_FailsWithoutCache(t *testing.T) {
+	prober := NewCachedNATProber(&mockProber{err: ErrInappropriateState}, time.Minute)
+
+	_, err := prober.Probe(context.Background())
+	assert.ErrorIs(t, err, ErrInappropriateState)
+}
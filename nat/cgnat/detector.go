@@ -0,0 +1,224 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cgnat
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Hairpin check results.
+const (
+	HairpinOK      = "ok"
+	HairpinFailed  = "failed"
+	HairpinSkipped = "skipped"
+)
+
+var sharedAddressSpace = mustParseCIDR("100.64.0.0/10")
+
+var privateNetworks = []*net.IPNet{
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+}
+
+// Detection is a result of carrier-grade NAT detection.
+type Detection struct {
+	// BehindCGNAT is true when node addresses prove it shares public IP with other ISP subscribers.
+	BehindCGNAT bool
+	// Suspected is true when inbound connections are unlikely to work, but addresses do not prove CGNAT.
+	Suspected   bool
+	PublicIP    string
+	OutboundIP  string
+	RouterIP    string
+	Hairpin     string
+	Reasons     []string
+	Remediation []string
+	DetectedAt  time.Time
+}
+
+type ipResolver interface {
+	GetOutboundIP() (string, error)
+	GetPublicIP() (string, error)
+}
+
+type routerIPProvider interface {
+	RouterIP() (net.IP, error)
+}
+
+// HairpinChecker checks if packets sent to node's own public address are looped back by NAT.
+type HairpinChecker func(ctx context.Context) error
+
+// Detector detects if node is running behind carrier-grade NAT and keeps the last result.
+type Detector struct {
+	resolver ipResolver
+	router   routerIPProvider
+	hairpin  HairpinChecker
+	relays   []string
+	now      func() time.Time
+
+	mu   sync.Mutex
+	last *Detection
+}
+
+// NewDetector returns a new instance of Detector. Router and hairpin checker are optional.
+func NewDetector(resolver ipResolver, router routerIPProvider, hairpin HairpinChecker, relays []string) *Detector {
+	return &Detector{
+		resolver: resolver,
+		router:   router,
+		hairpin:  hairpin,
+		relays:   relays,
+		now:      time.Now,
+	}
+}
+
+// Detect compares local, router and public addresses of the node and runs hairpin check
+// if addresses alone are not conclusive.
+func (d *Detector) Detect(ctx context.Context) Detection {
+	detection := Detection{
+		Hairpin:    HairpinSkipped,
+		DetectedAt: d.now(),
+	}
+
+	if ip, err := d.resolver.GetOutboundIP(); err != nil {
+		log.Warn().Err(err).Msg("CGNAT detection: could not get outbound IP")
+	} else {
+		detection.OutboundIP = ip
+	}
+	if ip, err := d.resolver.GetPublicIP(); err != nil {
+		log.Warn().Err(err).Msg("CGNAT detection: could not get public IP")
+	} else {
+		detection.PublicIP = ip
+	}
+	if d.router != nil {
+		if ip, err := d.router.RouterIP(); err != nil {
+			log.Debug().Err(err).Msg("CGNAT detection: could not get router WAN IP")
+		} else {
+			detection.RouterIP = ip.String()
+		}
+	}
+
+	if outboundIP := net.ParseIP(detection.OutboundIP); outboundIP != nil && sharedAddressSpace.Contains(outboundIP) {
+		detection.BehindCGNAT = true
+		detection.Reasons = append(detection.Reasons, fmt.Sprintf("Local address %s belongs to shared address space %s used by carrier-grade NAT", outboundIP, sharedAddressSpace))
+	}
+	if routerIP := net.ParseIP(detection.RouterIP); routerIP != nil {
+		publicIP := net.ParseIP(detection.PublicIP)
+		switch {
+		case sharedAddressSpace.Contains(routerIP):
+			detection.BehindCGNAT = true
+			detection.Reasons = append(detection.Reasons, fmt.Sprintf("Router WAN address %s belongs to shared address space %s used by carrier-grade NAT", routerIP, sharedAddressSpace))
+		case isPrivate(routerIP):
+			detection.Suspected = true
+			detection.Reasons = append(detection.Reasons, fmt.Sprintf("Router WAN address %s is private, router is behind another NAT", routerIP))
+		case publicIP != nil && !routerIP.Equal(publicIP):
+			detection.BehindCGNAT = true
+			detection.Reasons = append(detection.Reasons, fmt.Sprintf("Router WAN address %s differs from public IP %s", routerIP, publicIP))
+		}
+	}
+
+	behindNAT := detection.PublicIP == "" || detection.OutboundIP != detection.PublicIP
+	if !detection.BehindCGNAT && behindNAT && d.hairpin != nil {
+		if err := d.hairpin(ctx); err != nil {
+			detection.Hairpin = HairpinFailed
+			detection.Suspected = true
+			detection.Reasons = append(detection.Reasons, fmt.Sprintf("Packets sent to own public address are not looped back: %v", err))
+		} else {
+			detection.Hairpin = HairpinOK
+		}
+	}
+	if detection.BehindCGNAT {
+		detection.Suspected = false
+	}
+	detection.Remediation = d.remediation(detection)
+
+	if detection.BehindCGNAT {
+		log.Warn().Strs("reasons", detection.Reasons).Msg("Node is behind carrier-grade NAT, p2p connections will prefer relay")
+	} else if detection.Suspected {
+		log.Info().Strs("reasons", detection.Reasons).Msg("Node might be behind carrier-grade NAT")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.last = &detection
+
+	return detection
+}
+
+// Last returns the last detection result.
+func (d *Detector) Last() (Detection, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.last == nil {
+		return Detection{}, false
+	}
+	return *d.last, true
+}
+
+// BehindCGNAT returns true if the last detection proved that node is behind carrier-grade NAT.
+func (d *Detector) BehindCGNAT() bool {
+	last, ok := d.Last()
+	return ok && last.BehindCGNAT
+}
+
+func (d *Detector) remediation(detection Detection) (steps []string) {
+	if !detection.BehindCGNAT && !detection.Suspected {
+		return nil
+	}
+
+	if detection.BehindCGNAT {
+		if len(d.relays) > 0 {
+			steps = append(steps, "Consumers can't reach the node directly, p2p connections are established via relay")
+		} else {
+			steps = append(steps, "Configure p2p relays with --p2p.relays, otherwise most consumers won't be able to connect")
+		}
+		steps = append(steps, "Ask your ISP for a dedicated public IPv4 address")
+	}
+	if detection.RouterIP != "" && isPrivate(net.ParseIP(detection.RouterIP)) {
+		steps = append(steps, "Switch the upstream modem to bridge mode or forward UDP ports on it")
+	}
+	if detection.Hairpin == HairpinFailed {
+		steps = append(steps, "Enable NAT loopback (hairpinning) and UPnP on your router")
+	}
+	steps = append(steps, "Enable IPv6 on your router, peers having IPv6 connect directly without NAT")
+
+	return steps
+}
+
+func isPrivate(ip net.IP) bool {
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cgnat
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockResolver struct {
+	outboundIP, publicIP string
+}
+
+func (mr *mockResolver) GetOutboundIP() (string, error) {
+	return mr.outboundIP, nil
+}
+
+func (mr *mockResolver) GetPublicIP() (string, error) {
+	return mr.publicIP, nil
+}
+
+type mockRouter struct {
+	ip string
+}
+
+func (mr *mockRouter) RouterIP() (net.IP, error) {
+	if mr.ip == "" {
+		return nil, errors.New("no router")
+	}
+	return net.ParseIP(mr.ip), nil
+}
+
+func hairpin(err error) HairpinChecker {
+	return func(_ context.Context) error {
+		return err
+	}
+}
+
+func TestDetector_Detect(t *testing.T) {
+	tests := []struct {
+		name        string
+		resolver    *mockResolver
+		routerIP    string
+		hairpinErr  error
+		behindCGNAT bool
+		suspected   bool
+		hairpin     string
+	}{
+		{
+			name:     "public IP on interface",
+			resolver: &mockResolver{outboundIP: "1.2.3.4", publicIP: "1.2.3.4"},
+			hairpin:  HairpinSkipped,
+		},
+		{
+			name:     "home NAT with public router address",
+			resolver: &mockResolver{outboundIP: "192.168.1.10", publicIP: "1.2.3.4"},
+			routerIP: "1.2.3.4",
+			hairpin:  HairpinOK,
+		},
+		{
+			name:        "shared address on interface",
+			resolver:    &mockResolver{outboundIP: "100.64.12.1", publicIP: "1.2.3.4"},
+			behindCGNAT: true,
+			hairpin:     HairpinSkipped,
+		},
+		{
+			name:        "shared router WAN address",
+			resolver:    &mockResolver{outboundIP: "192.168.1.10", publicIP: "1.2.3.4"},
+			routerIP:    "100.100.1.1",
+			behindCGNAT: true,
+			hairpin:     HairpinSkipped,
+		},
+		{
+			name:        "router WAN address differs from public IP",
+			resolver:    &mockResolver{outboundIP: "192.168.1.10", publicIP: "1.2.3.4"},
+			routerIP:    "5.6.7.8",
+			behindCGNAT: true,
+			hairpin:     HairpinSkipped,
+		},
+		{
+			name:      "double NAT",
+			resolver:  &mockResolver{outboundIP: "192.168.1.10", publicIP: "1.2.3.4"},
+			routerIP:  "10.0.0.2",
+			suspected: true,
+			hairpin:   HairpinOK,
+		},
+		{
+			name:       "hairpin failure alone",
+			resolver:   &mockResolver{outboundIP: "192.168.1.10", publicIP: "1.2.3.4"},
+			hairpinErr: errors.New("timeout"),
+			suspected:  true,
+			hairpin:    HairpinFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewDetector(tt.resolver, &mockRouter{ip: tt.routerIP}, hairpin(tt.hairpinErr), []string{"relay:4000"})
+
+			detection := detector.Detect(context.Background())
+			assert.Equal(t, tt.behindCGNAT, detection.BehindCGNAT)
+			assert.Equal(t, tt.suspected, detection.Suspected)
+			assert.Equal(t, tt.hairpin, detection.Hairpin)
+			assert.Equal(t, tt.behindCGNAT || tt.suspected, len(detection.Reasons) > 0)
+			assert.Equal(t, tt.behindCGNAT || tt.suspected, len(detection.Remediation) > 0)
+			assert.Equal(t, tt.behindCGNAT, detector.BehindCGNAT())
+		})
+	}
+}
+
+func TestDetector_RemediationWithoutRelays(t *testing.T) {
+	detector := NewDetector(&mockResolver{outboundIP: "100.64.0.5", publicIP: "1.2.3.4"}, nil, nil, nil)
+
+	_, ok := detector.Last()
+	assert.False(t, ok)
+	assert.False(t, detector.BehindCGNAT())
+
+	detection := detector.Detect(context.Background())
+	assert.True(t, detection.BehindCGNAT)
+	assert.Contains(t, detection.Remediation[0], "--p2p.relays")
+
+	last, ok := detector.Last()
+	assert.True(t, ok)
+	assert.Equal(t, detection, last)
+}
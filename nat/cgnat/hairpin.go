@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cgnat
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/pion/stun"
+)
+
+const hairpinPayload = "MYST-HAIRPIN"
+
+// NewSTUNHairpinChecker returns hairpin checker which learns public address of one local socket
+// from STUN server and sends a packet to it from another local socket.
+func NewSTUNHairpinChecker(servers []string, timeout time.Duration) HairpinChecker {
+	return func(ctx context.Context) error {
+		if len(servers) == 0 {
+			return errors.New("no STUN servers configured")
+		}
+
+		deadline := time.Now().Add(timeout)
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+
+		target, err := net.ListenUDP("udp4", nil)
+		if err != nil {
+			return fmt.Errorf("could not listen UDP: %w", err)
+		}
+		defer target.Close()
+
+		sender, err := net.ListenUDP("udp4", nil)
+		if err != nil {
+			return fmt.Errorf("could not listen UDP: %w", err)
+		}
+		defer sender.Close()
+
+		if err := target.SetDeadline(deadline); err != nil {
+			return err
+		}
+
+		var mapped *net.UDPAddr
+		for _, server := range servers {
+			if mapped, err = mappedAddress(target, server); err == nil {
+				break
+			}
+		}
+		if mapped == nil {
+			return fmt.Errorf("could not get public address from STUN servers: %w", err)
+		}
+
+		if _, err := sender.WriteToUDP([]byte(hairpinPayload), mapped); err != nil {
+			return fmt.Errorf("could not send hairpin packet: %w", err)
+		}
+
+		buf := make([]byte, 1024)
+		for {
+			n, _, err := target.ReadFromUDP(buf)
+			if err != nil {
+				return fmt.Errorf("hairpin packet to %s not received: %w", mapped, err)
+			}
+			if bytes.Equal(buf[:n], []byte(hairpinPayload)) {
+				return nil
+			}
+		}
+	}
+}
+
+func mappedAddress(conn *net.UDPConn, server string) (*net.UDPAddr, error) {
+	serverAddr, err := net.ResolveUDPAddr("udp4", server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve STUN server address: %w", err)
+	}
+
+	req := stun.MustBuild(stun.TransactionID, stun.BindingRequest)
+	if _, err := conn.WriteToUDP(req.Raw, serverAddr); err != nil {
+		return nil, fmt.Errorf("failed to send binding request to STUN server: %w", err)
+	}
+
+	buf := make([]byte, 1024)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read message from STUN server: %w", err)
+		}
+		if !from.IP.Equal(serverAddr.IP) || !stun.IsMessage(buf[:n]) {
+			continue
+		}
+
+		resp := &stun.Message{Raw: append([]byte{}, buf[:n]...)}
+		if err := resp.Decode(); err != nil {
+			return nil, fmt.Errorf("failed to decode STUN server message: %w", err)
+		}
+		if resp.TransactionID != req.TransactionID {
+			continue
+		}
+
+		var xorAddr stun.XORMappedAddress
+		if err := xorAddr.GetFrom(resp); err != nil {
+			return nil, fmt.Errorf("failed to get address from STUN server message: %w", err)
+		}
+		return &net.UDPAddr{IP: xorAddr.IP, Port: xorAddr.Port}, nil
+	}
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nat
+
+import (
+	"strconv"
+
+	"github.com/mysteriumnetwork/node/firewall/iptables"
+)
+
+// makeEgressRules rejects forwarded session traffic to destinations blocked by the egress policy.
+// Rules are inserted at the top of the FORWARD chain to take precedence over the accepting ones.
+func makeEgressRules(opts Options) (rules []iptables.Rule) {
+	vpnNetwork := opts.VPNNetwork.String()
+
+	for _, network := range opts.Egress.BlockedNetworks() {
+		if network.IP.To4() == nil {
+			continue
+		}
+		rules = append(rules, iptables.InsertAt(chainForward, 1).RuleSpec(
+			"--source", vpnNetwork, "--destination", network.String(), "--jump", "REJECT"))
+	}
+
+	for _, port := range opts.Egress.Ports {
+		for _, protocol := range []string{"tcp", "udp"} {
+			rules = append(rules, iptables.InsertAt(chainForward, 1).RuleSpec(
+				"--source", vpnNetwork, "--protocol", protocol, "--dport", strconv.Itoa(port), "--jump", "REJECT"))
+		}
+	}
+
+	return rules
+}
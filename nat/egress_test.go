@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nat
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/firewall/egress"
+)
+
+func TestMakeEgressRules(t *testing.T) {
+	_, vpnNetwork, _ := net.ParseCIDR("10.182.0.0/24")
+	policy := egress.NewPolicy([]string{"25"}, []string{"192.168.0.0/16", "fd00::/8"}, nil)
+	rules := makeEgressRules(Options{VPNNetwork: *vpnNetwork, Egress: policy})
+
+	assert.Len(t, rules, 3)
+	assert.Equal(t, []string{
+		"-I", "FORWARD", "1", "--source", "10.182.0.0/24", "--destination", "192.168.0.0/16", "--jump", "REJECT",
+	}, rules[0].ApplyArgs())
+	assert.Equal(t, []string{
+		"-D", "FORWARD", "--source", "10.182.0.0/24", "--protocol", "tcp", "--dport", "25", "--jump", "REJECT",
+	}, rules[1].RemoveArgs())
+	assert.Equal(t, []string{
+		"-I", "FORWARD", "1", "--source", "10.182.0.0/24", "--protocol", "udp", "--dport", "25", "--jump", "REJECT",
+	}, rules[2].ApplyArgs())
+}
+
+func TestMakeEgressRules_EmptyPolicy(t *testing.T) {
+	_, vpnNetwork, _ := net.ParseCIDR("10.182.0.0/24")
+	assert.Empty(t, makeEgressRules(Options{VPNNetwork: *vpnNetwork}))
+}
@@ -21,21 +21,24 @@ import (
 	"os/exec"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/firewall"
 )
 
-// NewService returns linux os specific nat service based on ip tables
+// NewService returns linux os specific nat service based on iptables or nftables
 func NewService() NATService {
 	if config.GetBool(config.FlagUserspace) {
 		return &serviceNoop{}
 	}
-	return &serviceIPTables{
-		ipForward: serviceIPForward{
-			CommandFactory: func(name string, arg ...string) Command {
-				return exec.Command(name, arg...)
-			},
-			CommandEnable:  []string{"sudo", "/sbin/sysctl", "-w", "net.ipv4.ip_forward=1"},
-			CommandDisable: []string{"sudo", "/sbin/sysctl", "-w", "net.ipv4.ip_forward=0"},
-			CommandRead:    []string{"/sbin/sysctl", "-n", "net.ipv4.ip_forward"},
+	ipForward := serviceIPForward{
+		CommandFactory: func(name string, arg ...string) Command {
+			return exec.Command(name, arg...)
 		},
+		CommandEnable:  []string{"sudo", "/sbin/sysctl", "-w", "net.ipv4.ip_forward=1"},
+		CommandDisable: []string{"sudo", "/sbin/sysctl", "-w", "net.ipv4.ip_forward=0"},
+		CommandRead:    []string{"/sbin/sysctl", "-n", "net.ipv4.ip_forward"},
 	}
+	if firewall.DetectBackend() == firewall.BackendNftables {
+		return &serviceNftables{ipForward: ipForward}
+	}
+	return &serviceIPTables{ipForward: ipForward}
 }
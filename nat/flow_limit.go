@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nat
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/mysteriumnetwork/node/firewall/iptables"
+)
+
+// flowLimitName names the rate limit table of the session, consumer addresses are its keys.
+// Kernel keeps the rate of the first rule using a table, so sessions must not share them.
+// The name is kept short to fit the 15 characters hashlimit allows.
+func flowLimitName(sessionID string) string {
+	if sessionID == "" {
+		return "myst_flows"
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(sessionID))
+	return fmt.Sprintf("myst_%08x", h.Sum32())
+}
+
+// makeFlowLimitRules rejects new forwarded flows of the session exceeding the flow rate, throttling
+// consumers scanning or flooding through the provider.
+func makeFlowLimitRules(opts Options) (rules []iptables.Rule) {
+	if opts.FlowRate <= 0 {
+		return nil
+	}
+
+	rate := strconv.Itoa(opts.FlowRate)
+	return append(rules, iptables.InsertAt(chainForward, 1).RuleSpec(
+		"--source", opts.VPNNetwork.String(),
+		"--match", "conntrack", "--ctstate", "NEW",
+		"--match", "hashlimit", "--hashlimit-above", rate+"/sec", "--hashlimit-burst", rate,
+		"--hashlimit-mode", "srcip", "--hashlimit-name", flowLimitName(opts.SessionID),
+		"--jump", "REJECT",
+	))
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nat
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeFlowLimitRules(t *testing.T) {
+	_, vpnNetwork, _ := net.ParseCIDR("10.182.0.0/24")
+	rules := makeFlowLimitRules(Options{VPNNetwork: *vpnNetwork, FlowRate: 20})
+
+	assert.Len(t, rules, 1)
+	assert.Equal(t, []string{
+		"-I", "FORWARD", "1", "--source", "10.182.0.0/24",
+		"--match", "conntrack", "--ctstate", "NEW",
+		"--match", "hashlimit", "--hashlimit-above", "20/sec", "--hashlimit-burst", "20",
+		"--hashlimit-mode", "srcip", "--hashlimit-name", "myst_flows",
+		"--jump", "REJECT",
+	}, rules[0].ApplyArgs())
+
+	assert.Empty(t, makeFlowLimitRules(Options{VPNNetwork: *vpnNetwork}))
+}
+
+func TestFlowLimitName(t *testing.T) {
+	name := flowLimitName("session-1")
+	assert.Len(t, name, 13)
+	assert.Equal(t, name, flowLimitName("session-1"))
+	assert.NotEqual(t, name, flowLimitName("session-2"))
+	assert.Equal(t, "myst_flows", flowLimitName(""))
+}
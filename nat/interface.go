@@ -17,7 +17,11 @@
 
 package nat
 
-import "net"
+import (
+	"net"
+
+	"github.com/mysteriumnetwork/node/firewall/egress"
+)
 
 // NATService routes internet traffic through provider and
 // sets up firewall rules for security
@@ -33,4 +37,10 @@ type Options struct {
 	VPNNetwork    net.IPNet
 	ProviderExtIP net.IP
 	DNSIP         net.IP
+	// SessionID (optional) tags the session traffic for kernel side accounting.
+	SessionID string
+	// Egress lists destinations blocked for the session traffic.
+	Egress egress.Policy
+	// FlowRate (optional) limits new flows per second the session may open, excessive flows are rejected.
+	FlowRate int
 }
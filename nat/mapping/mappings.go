@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mapping
+
+import (
+	"time"
+)
+
+// AppTopicPortMapping is the topic port mapping lifecycle events are published on.
+const AppTopicPortMapping = "PortMapping"
+
+// Port mapping lifecycle statuses.
+const (
+	StatusActive        = "active"
+	StatusRenewalFailed = "renewal_failed"
+	StatusFailed        = "failed"
+	StatusReleased      = "released"
+)
+
+// Port mapping lifecycle event actions.
+const (
+	ActionCreated       = "created"
+	ActionFailed        = "failed"
+	ActionRenewed       = "renewed"
+	ActionRenewalFailed = "renewal_failed"
+	ActionReleased      = "released"
+)
+
+// maxMappingHistory is a number of failed and released mappings kept for inspection.
+const maxMappingHistory = 50
+
+// Mapping represents lifecycle of a single port mapping attempt.
+type Mapping struct {
+	ID uint64 `json:"id"`
+	// Protocol is a transport protocol of the mapped port, e.g. UDP.
+	Protocol string `json:"protocol"`
+	// MapProtocol is a port mapping protocol which created the mapping, e.g. upnp.
+	MapProtocol     string    `json:"map_protocol,omitempty"`
+	Name            string    `json:"name"`
+	InternalPort    int       `json:"internal_port"`
+	ExternalPort    int       `json:"external_port"`
+	Status          string    `json:"status"`
+	Permanent       bool      `json:"permanent"`
+	CreatedAt       time.Time `json:"created_at"`
+	RenewedAt       time.Time `json:"renewed_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	ReleasedAt      time.Time `json:"released_at"`
+	Renewals        uint64    `json:"renewals"`
+	RenewalFailures uint64    `json:"renewal_failures"`
+	LastError       string    `json:"last_error,omitempty"`
+}
+
+// AppEventPortMapping represents a port mapping lifecycle change.
+type AppEventPortMapping struct {
+	Action  string  `json:"action"`
+	Mapping Mapping `json:"mapping"`
+}
+
+func (p *portMapper) Mappings() []Mapping {
+	p.mappingsLock.Lock()
+	defer p.mappingsLock.Unlock()
+
+	res := make([]Mapping, 0, len(p.mappings))
+	for _, m := range p.mappings {
+		res = append(res, *m)
+	}
+	return res
+}
+
+func (p *portMapper) newMapping(protocol string, port int, name string) *Mapping {
+	p.mappingsLock.Lock()
+	defer p.mappingsLock.Unlock()
+
+	p.lastMappingID++
+	return &Mapping{
+		ID:           p.lastMappingID,
+		Protocol:     protocol,
+		Name:         name,
+		InternalPort: port,
+		ExternalPort: port,
+		CreatedAt:    p.now(),
+	}
+}
+
+func (p *portMapper) mappingCreated(m *Mapping, mapProtocol string, permanent bool) {
+	p.updateMapping(m, ActionCreated, func(m *Mapping) {
+		m.MapProtocol = mapProtocol
+		m.Status = StatusActive
+		m.Permanent = permanent
+		m.LastError = ""
+		if !permanent {
+			m.ExpiresAt = m.CreatedAt.Add(p.config.MapLifetime)
+		}
+	})
+}
+
+func (p *portMapper) mappingFailed(m *Mapping, err error) {
+	p.updateMapping(m, ActionFailed, func(m *Mapping) {
+		m.Status = StatusFailed
+		m.LastError = err.Error()
+	})
+}
+
+func (p *portMapper) mappingRenewed(m *Mapping, err error) {
+	p.mappingsLock.Lock()
+	released := m.Status == StatusReleased
+	p.mappingsLock.Unlock()
+	if released {
+		return
+	}
+
+	if err != nil {
+		p.updateMapping(m, ActionRenewalFailed, func(m *Mapping) {
+			m.Status = StatusRenewalFailed
+			m.RenewalFailures++
+			m.LastError = err.Error()
+		})
+		return
+	}
+
+	p.updateMapping(m, ActionRenewed, func(m *Mapping) {
+		m.Status = StatusActive
+		m.Renewals++
+		m.RenewedAt = p.now()
+		m.ExpiresAt = m.RenewedAt.Add(p.config.MapLifetime)
+	})
+}
+
+func (p *portMapper) mappingReleased(m *Mapping) {
+	p.updateMapping(m, ActionReleased, func(m *Mapping) {
+		m.Status = StatusReleased
+		m.ReleasedAt = p.now()
+		m.ExpiresAt = time.Time{}
+	})
+}
+
+func (p *portMapper) updateMapping(m *Mapping, action string, update func(m *Mapping)) {
+	p.mappingsLock.Lock()
+	update(m)
+	if _, ok := p.mappingIndex(m.ID); !ok {
+		p.mappings = append(p.mappings, m)
+	}
+	p.trimMappings()
+	e := AppEventPortMapping{Action: action, Mapping: *m}
+	p.mappingsLock.Unlock()
+
+	p.publisher.Publish(AppTopicPortMapping, e)
+}
+
+func (p *portMapper) mappingIndex(id uint64) (int, bool) {
+	for i, m := range p.mappings {
+		if m.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// trimMappings drops the oldest finished mappings so that history does not grow unbounded.
+func (p *portMapper) trimMappings() {
+	var finished int
+	for _, m := range p.mappings {
+		if m.Status == StatusFailed || m.Status == StatusReleased {
+			finished++
+		}
+	}
+
+	kept := p.mappings[:0]
+	for _, m := range p.mappings {
+		if finished > maxMappingHistory && (m.Status == StatusFailed || m.Status == StatusReleased) {
+			finished--
+			continue
+		}
+		kept = append(kept, m)
+	}
+	p.mappings = kept
+}
@@ -18,6 +18,9 @@
 package mapping
 
 import (
+	"errors"
+	"net"
+
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/nat/event"
 	"github.com/rs/zerolog/log"
@@ -42,3 +45,15 @@ func (p *noopPortMapper) Map(id, protocol string, port int, name string) (releas
 		log.Debug().Msgf("Noop port mapping released: %d", port)
 	}, false
 }
+
+func (p *noopPortMapper) Stats() []ProtocolStats {
+	return nil
+}
+
+func (p *noopPortMapper) Mappings() []Mapping {
+	return nil
+}
+
+func (p *noopPortMapper) RouterIP() (net.IP, error) {
+	return nil, errors.New("port mapping is disabled")
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mapping
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	portmap "github.com/ethereum/go-ethereum/p2p/nat"
+)
+
+// PCP message layout as defined in RFC 6887.
+const (
+	pcpPort             = 5351
+	pcpVersion          = 2
+	pcpOpMap            = 1
+	pcpResponseBit      = 0x80
+	pcpHeaderSize       = 24
+	pcpMapPayloadSize   = 36
+	pcpNonceSize        = 12
+	pcpResultSuccess    = 0
+	pcpMaxRetries       = 3
+	pcpInitialRetryWait = 250 * time.Millisecond
+
+	// pcpProbePort is a discard port used to learn the external IP address.
+	pcpProbePort     = 9
+	pcpProbeLifetime = 30 * time.Second
+)
+
+var errPCPPermanentLease = errors.New("PCP does not support permanent leases")
+
+// pcpMapping is a mapping granted by the gateway.
+type pcpMapping struct {
+	externalIP   net.IP
+	externalPort int
+	lifetime     time.Duration
+}
+
+// pcp implements port mapping via Port Control Protocol.
+type pcp struct {
+	gateway net.IP
+
+	mu         sync.Mutex
+	externalIP net.IP
+}
+
+// NewPCP returns port mapping interface which uses PCP to talk to the given gateway.
+func NewPCP(gateway net.IP) portmap.Interface {
+	return &pcp{gateway: gateway}
+}
+
+func (p *pcp) AddMapping(protocol string, extport, intport int, name string, lifetime time.Duration) error {
+	if lifetime <= 0 {
+		return errPCPPermanentLease
+	}
+
+	mapping, err := p.mapPort(protocol, extport, intport, lifetime)
+	if err != nil {
+		return err
+	}
+
+	// Callers announce the requested port and renew it on their own schedule,
+	// so a different port or a shorter lease would silently break the mapping.
+	if mapping.externalPort != extport || mapping.lifetime < lifetime {
+		_, _ = p.mapPort(protocol, mapping.externalPort, intport, 0)
+		return fmt.Errorf("PCP granted port %d for %v, requested port %d for %v", mapping.externalPort, mapping.lifetime, extport, lifetime)
+	}
+
+	p.mu.Lock()
+	p.externalIP = mapping.externalIP
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *pcp) DeleteMapping(protocol string, extport, intport int) error {
+	_, err := p.mapPort(protocol, extport, intport, 0)
+	return err
+}
+
+func (p *pcp) ExternalIP() (net.IP, error) {
+	p.mu.Lock()
+	cached := p.externalIP
+	p.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	// PCP has no dedicated request for external address, it is learned from a short-lived mapping.
+	mapping, err := p.mapPort("UDP", pcpProbePort, pcpProbePort, pcpProbeLifetime)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = p.mapPort("UDP", mapping.externalPort, pcpProbePort, 0)
+
+	p.mu.Lock()
+	p.externalIP = mapping.externalIP
+	p.mu.Unlock()
+	return mapping.externalIP, nil
+}
+
+func (p *pcp) String() string {
+	return fmt.Sprintf("PCP(%v)", p.gateway)
+}
+
+func (p *pcp) mapPort(protocol string, extport, intport int, lifetime time.Duration) (pcpMapping, error) {
+	proto, err := pcpProtocolNumber(protocol)
+	if err != nil {
+		return pcpMapping{}, err
+	}
+
+	conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: p.gateway, Port: pcpPort})
+	if err != nil {
+		return pcpMapping{}, err
+	}
+	defer conn.Close()
+
+	nonce := make([]byte, pcpNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return pcpMapping{}, err
+	}
+
+	req := pcpMapRequest(conn.LocalAddr().(*net.UDPAddr).IP, nonce, proto, intport, extport, lifetime)
+	resp := make([]byte, 1100)
+
+	wait := pcpInitialRetryWait
+	for i := 0; i < pcpMaxRetries; i++ {
+		if _, err := conn.Write(req); err != nil {
+			return pcpMapping{}, err
+		}
+
+		conn.SetReadDeadline(time.Now().Add(wait))
+		n, err := conn.Read(resp)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				wait *= 2
+				continue
+			}
+			return pcpMapping{}, err
+		}
+
+		return parsePCPMapResponse(resp[:n], nonce)
+	}
+
+	return pcpMapping{}, fmt.Errorf("no PCP response from gateway %v", p.gateway)
+}
+
+func pcpProtocolNumber(protocol string) (byte, error) {
+	switch strings.ToUpper(protocol) {
+	case "UDP":
+		return 17, nil
+	case "TCP":
+		return 6, nil
+	}
+	return 0, fmt.Errorf("unsupported protocol: %s", protocol)
+}
+
+func pcpMapRequest(clientIP net.IP, nonce []byte, proto byte, intport, extport int, lifetime time.Duration) []byte {
+	req := make([]byte, pcpHeaderSize+pcpMapPayloadSize)
+	req[0] = pcpVersion
+	req[1] = pcpOpMap
+	binary.BigEndian.PutUint32(req[4:8], uint32(lifetime/time.Second))
+	copy(req[8:24], clientIP.To16())
+
+	payload := req[pcpHeaderSize:]
+	copy(payload[0:12], nonce)
+	payload[12] = proto
+	binary.BigEndian.PutUint16(payload[16:18], uint16(intport))
+	binary.BigEndian.PutUint16(payload[18:20], uint16(extport))
+	// Suggest any IPv4 external address.
+	copy(payload[20:36], net.IPv4zero.To16())
+
+	return req
+}
+
+func parsePCPMapResponse(resp []byte, nonce []byte) (pcpMapping, error) {
+	if len(resp) < pcpHeaderSize+pcpMapPayloadSize {
+		return pcpMapping{}, fmt.Errorf("PCP response too short: %d bytes", len(resp))
+	}
+	if resp[0] != pcpVersion {
+		return pcpMapping{}, fmt.Errorf("unsupported PCP version: %d", resp[0])
+	}
+	if resp[1] != pcpResponseBit|pcpOpMap {
+		return pcpMapping{}, fmt.Errorf("unexpected PCP opcode: %d", resp[1])
+	}
+	if resp[3] != pcpResultSuccess {
+		return pcpMapping{}, fmt.Errorf("PCP request failed with result code %d", resp[3])
+	}
+
+	payload := resp[pcpHeaderSize:]
+	if string(payload[0:12]) != string(nonce) {
+		return pcpMapping{}, errors.New("PCP response nonce mismatch")
+	}
+
+	ip := net.IP(append([]byte(nil), payload[20:36]...))
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	return pcpMapping{
+		externalIP:   ip,
+		externalPort: int(binary.BigEndian.Uint16(payload[18:20])),
+		lifetime:     time.Duration(binary.BigEndian.Uint32(resp[4:8])) * time.Second,
+	}, nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package mapping
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPCPMapRequest(t *testing.T) {
+	nonce := []byte("0123456789ab")
+	req := pcpMapRequest(net.ParseIP("192.168.1.2"), nonce, 17, 51334, 51335, 20*time.Minute)
+
+	assert.Len(t, req, pcpHeaderSize+pcpMapPayloadSize)
+	assert.Equal(t, byte(pcpVersion), req[0])
+	assert.Equal(t, byte(pcpOpMap), req[1])
+	assert.Equal(t, uint32(1200), binary.BigEndian.Uint32(req[4:8]))
+	assert.Equal(t, net.ParseIP("192.168.1.2").To16(), net.IP(req[8:24]))
+	assert.Equal(t, nonce, req[24:36])
+	assert.Equal(t, byte(17), req[36])
+	assert.Equal(t, uint16(51334), binary.BigEndian.Uint16(req[40:42]))
+	assert.Equal(t, uint16(51335), binary.BigEndian.Uint16(req[42:44]))
+}
+
+func TestParsePCPMapResponse(t *testing.T) {
+	nonce := []byte("0123456789ab")
+	resp := make([]byte, pcpHeaderSize+pcpMapPayloadSize)
+	resp[0] = pcpVersion
+	resp[1] = pcpResponseBit | pcpOpMap
+	copy(resp[24:36], nonce)
+	binary.BigEndian.PutUint32(resp[4:8], 1200)
+	binary.BigEndian.PutUint16(resp[42:44], 51335)
+	copy(resp[44:60], net.ParseIP("1.2.3.4").To16())
+
+	mapping, err := parsePCPMapResponse(resp, nonce)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3.4", mapping.externalIP.String())
+	assert.Equal(t, 51335, mapping.externalPort)
+	assert.Equal(t, 20*time.Minute, mapping.lifetime)
+
+	_, err = parsePCPMapResponse(resp, []byte("ba9876543210"))
+	assert.Error(t, err)
+
+	resp[3] = 2 // NOT_AUTHORIZED
+	_, err = parsePCPMapResponse(resp, nonce)
+	assert.Error(t, err)
+
+	_, err = parsePCPMapResponse(resp[:10], nonce)
+	assert.Error(t, err)
+}
+
+func TestPCP_PermanentLeaseNotSupported(t *testing.T) {
+	assert.Equal(t, errPCPPermanentLease, NewPCP(net.ParseIP("192.168.1.1")).AddMapping("UDP", 1, 1, "", 0))
+}
+
+func TestPCP_RejectsDifferentExternalPort(t *testing.T) {
+	gateway, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: pcpPort})
+	if err != nil {
+		t.Skipf("PCP port is not available: %v", err)
+	}
+	defer gateway.Close()
+
+	var requests [][]byte
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, 1100)
+		for len(requests) < 2 {
+			n, addr, err := gateway.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			req := append([]byte(nil), buf[:n]...)
+			requests = append(requests, req)
+
+			resp := append([]byte(nil), req...)
+			resp[1] |= pcpResponseBit
+			binary.BigEndian.PutUint16(resp[pcpHeaderSize+18:pcpHeaderSize+20], 40000)
+			gateway.WriteToUDP(resp, addr)
+		}
+	}()
+
+	err = NewPCP(net.ParseIP("127.0.0.1")).AddMapping("UDP", 51335, 51334, "", 20*time.Minute)
+	assert.EqualError(t, err, "PCP granted port 40000 for 20m0s, requested port 51335 for 20m0s")
+
+	<-done
+	// The mapping which was granted instead is released.
+	assert.Equal(t, uint32(0), binary.BigEndian.Uint32(requests[1][4:8]))
+	assert.Equal(t, uint16(40000), binary.BigEndian.Uint16(requests[1][pcpHeaderSize+18:pcpHeaderSize+20]))
+}
@@ -20,9 +20,12 @@ package mapping
 import (
 	"errors"
 	"net"
+	"strings"
+	"sync"
 	"time"
 
 	portmap "github.com/ethereum/go-ethereum/p2p/nat"
+	"github.com/jackpal/gateway"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/nat/event"
 	"github.com/rs/zerolog/log"
@@ -31,28 +34,86 @@ import (
 // StageName is used to indicate port mapping NAT traversal stage
 const StageName = "port_mapping"
 
+// Port mapping protocol names.
+const (
+	ProtocolUPnP   = "upnp"
+	ProtocolPCP    = "pcp"
+	ProtocolNATPMP = "nat-pmp"
+)
+
 // DefaultConfig returns default port mapping config.
 func DefaultConfig() *Config {
 	return &Config{
-		MapInterface:      portmap.Any(),
+		Protocols:         DefaultProtocols(),
 		MapLifetime:       20 * time.Minute,
 		MapUpdateInterval: 15 * time.Minute,
 	}
 }
 
+// DefaultProtocols returns port mapping protocols in the order they are tried: UPnP, PCP and NAT-PMP.
+// PCP and NAT-PMP are skipped if default gateway can't be discovered.
+func DefaultProtocols() []Protocol {
+	protocols := []Protocol{{Name: ProtocolUPnP, Interface: portmap.UPnP()}}
+
+	gw, err := gateway.DiscoverGateway()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to discover default gateway, skipping PCP and NAT-PMP port mapping")
+		return protocols
+	}
+
+	return append(protocols,
+		Protocol{Name: ProtocolPCP, Interface: NewPCP(gw)},
+		Protocol{Name: ProtocolNATPMP, Interface: portmap.PMP(gw)},
+	)
+}
+
+// Protocol represents a single port mapping protocol.
+type Protocol struct {
+	Name      string
+	Interface portmap.Interface
+}
+
 // Config represents port mapping config.
 type Config struct {
+	// Protocols are tried in the given order until one of them succeeds.
+	Protocols []Protocol
+	// MapInterface is used when no protocols are given.
 	MapInterface      portmap.Interface
 	MapLifetime       time.Duration
 	MapUpdateInterval time.Duration
 }
 
-// PortMapper tries to map port using router's uPnP or NAT-PMP depending on given config map interface.
+func (c *Config) protocols() []Protocol {
+	if len(c.Protocols) > 0 {
+		return c.Protocols
+	}
+	if c.MapInterface == nil {
+		return nil
+	}
+	return []Protocol{{Name: c.MapInterface.String(), Interface: c.MapInterface}}
+}
+
+// ProtocolStats represents port mapping metrics of a single protocol.
+type ProtocolStats struct {
+	Protocol  string
+	Attempts  uint64
+	Successes uint64
+	Renewals  uint64
+	Failures  uint64
+}
+
+// PortMapper tries to map port using router's uPnP, PCP or NAT-PMP depending on given config.
 type PortMapper interface {
 	// Map maps port for given protocol. It returns release func which
 	// must be called when port no longer needed and ok which is true if
 	// port mapping was successful.
 	Map(id, protocol string, port int, name string) (release func(), ok bool)
+	// Stats returns port mapping metrics per protocol.
+	Stats() []ProtocolStats
+	// Mappings returns active mappings and the most recent failed or released ones.
+	Mappings() []Mapping
+	// RouterIP returns WAN address of the router reported by the first port mapping protocol which answers.
+	RouterIP() (net.IP, error)
 }
 
 // NewPortMapper returns port mapper instance.
@@ -60,34 +121,83 @@ func NewPortMapper(config *Config, publisher eventbus.Publisher) PortMapper {
 	return &portMapper{
 		config:    config,
 		publisher: publisher,
+		stats:     make(map[string]*ProtocolStats),
+		now:       time.Now,
 	}
 }
 
 type portMapper struct {
 	config    *Config
 	publisher eventbus.Publisher
+
+	statsLock sync.Mutex
+	stats     map[string]*ProtocolStats
+
+	mappingsLock  sync.Mutex
+	mappings      []*Mapping
+	lastMappingID uint64
+	now           func() time.Time
 }
 
 func (p *portMapper) Map(id, protocol string, port int, name string) (release func(), ok bool) {
-	if !p.routerIPPublic() {
-		err := errors.New("failed to find router public IP")
-		log.Info().Err(err).Msg("Port mapping is useless, skipping it.")
-		p.notify(id, err)
+	m := p.newMapping(protocol, port, name)
 
-		return nil, false
+	err := errors.New("no port mapping protocols configured")
+	var errs []string
+	for _, mapProtocol := range p.config.protocols() {
+		release, err = p.mapWith(id, m, mapProtocol, protocol, port, name)
+		if err == nil {
+			p.notify(id, nil)
+			return release, true
+		}
+		log.Debug().Err(err).Msgf("Port mapping via %s failed", mapProtocol.Name)
+		errs = append(errs, mapProtocol.Name+": "+err.Error())
+	}
+
+	if len(errs) > 0 {
+		p.mappingFailed(m, errors.New(strings.Join(errs, "; ")))
+	} else {
+		p.mappingFailed(m, err)
+	}
+	p.notify(id, err)
+	return nil, false
+}
+
+func (p *portMapper) Stats() []ProtocolStats {
+	p.statsLock.Lock()
+	defer p.statsLock.Unlock()
+
+	res := make([]ProtocolStats, 0, len(p.stats))
+	for _, mapProtocol := range p.config.protocols() {
+		if stats, ok := p.stats[mapProtocol.Name]; ok {
+			res = append(res, *stats)
+		}
+	}
+	return res
+}
+
+func (p *portMapper) mapWith(id string, m *Mapping, mapProtocol Protocol, protocol string, port int, name string) (release func(), err error) {
+	if !p.routerIPPublic(mapProtocol.Interface) {
+		log.Info().Msgf("Port mapping via %s is useless, skipping it.", mapProtocol.Name)
+		return nil, errors.New("failed to find router public IP")
 	}
 
 	// Try add mapping first to determine if it is supported and
 	// if permanent lease only is supported.
-	permanent, err := p.addMapping(protocol, port, port, name)
-	p.notify(id, err)
+	permanent, err := p.addMapping(mapProtocol.Interface, protocol, port, port, name)
+	p.record(mapProtocol.Name, err, false)
 	if err != nil {
-		return nil, false
+		return nil, err
 	}
+	log.Info().Msgf("Mapped network port %d via %s", port, mapProtocol.Name)
+	p.mappingCreated(m, mapProtocol.Name, permanent)
 
 	// If only permanent lease is supported we don't need to update it in intervals.
 	if permanent {
-		return func() { p.deleteMapping(protocol, port, port) }, true
+		return func() {
+			p.deleteMapping(mapProtocol.Interface, protocol, port, port)
+			p.mappingReleased(m)
+		}, nil
 	}
 
 	stopUpdate := make(chan struct{})
@@ -97,20 +207,62 @@ func (p *portMapper) Map(id, protocol string, port int, name string) (release fu
 			case <-stopUpdate:
 				return
 			case <-time.After(p.config.MapUpdateInterval):
-				_, err := p.addMapping(protocol, port, port, name)
+				_, err := p.addMapping(mapProtocol.Interface, protocol, port, port, name)
+				p.record(mapProtocol.Name, err, true)
+				p.mappingRenewed(m, err)
 				p.notify(id, err)
 			}
 		}
 	}()
 
 	return func() {
-		p.deleteMapping(protocol, port, port)
 		close(stopUpdate)
-	}, true
+		p.deleteMapping(mapProtocol.Interface, protocol, port, port)
+		p.mappingReleased(m)
+	}, nil
+}
+
+func (p *portMapper) record(protocol string, err error, renewal bool) {
+	p.statsLock.Lock()
+	defer p.statsLock.Unlock()
+
+	stats, ok := p.stats[protocol]
+	if !ok {
+		stats = &ProtocolStats{Protocol: protocol}
+		p.stats[protocol] = stats
+	}
+
+	switch {
+	case err != nil:
+		stats.Failures++
+	case renewal:
+		stats.Renewals++
+	default:
+		stats.Successes++
+	}
+	if !renewal {
+		stats.Attempts++
+	}
+}
+
+func (p *portMapper) RouterIP() (net.IP, error) {
+	var errs []string
+	for _, mapProtocol := range p.config.protocols() {
+		ip, err := mapProtocol.Interface.ExternalIP()
+		if err == nil {
+			return ip, nil
+		}
+		errs = append(errs, mapProtocol.Name+": "+err.Error())
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("no port mapping protocols configured")
+	}
+
+	return nil, errors.New(strings.Join(errs, "; "))
 }
 
-func (p *portMapper) routerIPPublic() bool {
-	ip, err := p.config.MapInterface.ExternalIP()
+func (p *portMapper) routerIPPublic(mapInterface portmap.Interface) bool {
+	ip, err := mapInterface.ExternalIP()
 	if err != nil {
 		log.Warn().Err(err).Msg("Couldn't detect router IP address")
 		return false
@@ -143,23 +295,22 @@ func (p *portMapper) notify(id string, err error) {
 	}
 }
 
-func (p *portMapper) addMapping(protocol string, extPort, intPort int, name string) (permanent bool, err error) {
-	if err := p.config.MapInterface.AddMapping(protocol, extPort, intPort, name, p.config.MapLifetime); err != nil {
+func (p *portMapper) addMapping(mapInterface portmap.Interface, protocol string, extPort, intPort int, name string) (permanent bool, err error) {
+	if err := mapInterface.AddMapping(protocol, extPort, intPort, name, p.config.MapLifetime); err != nil {
 		log.Warn().Err(err).Msgf("Couldn't add port mapping for port %d: retrying with permanent lease", extPort)
-		if err := p.config.MapInterface.AddMapping(protocol, extPort, intPort, name, 0); err != nil {
+		if err := mapInterface.AddMapping(protocol, extPort, intPort, name, 0); err != nil {
 			// some gateways support only permanent leases
 			log.Warn().Err(err).Msgf("Couldn't add port mapping for port %d", extPort)
 			return false, err
 		}
 		return true, nil
 	}
-	log.Info().Msgf("Mapped network port: %d", extPort)
 	return false, nil
 }
 
-func (p *portMapper) deleteMapping(protocol string, extPort, intPort int) {
+func (p *portMapper) deleteMapping(mapInterface portmap.Interface, protocol string, extPort, intPort int) {
 	log.Debug().Msgf("Deleting port mapping for port: %d", extPort)
-	if err := p.config.MapInterface.DeleteMapping(protocol, extPort, intPort); err != nil {
+	if err := mapInterface.DeleteMapping(protocol, extPort, intPort); err != nil {
 		log.Warn().Err(err).Msg("Couldn't delete port mapping")
 	}
 }
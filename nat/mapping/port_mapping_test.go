@@ -179,3 +179,77 @@ func (m *mockRouter) ExternalIP() (net.IP, error) {
 func (m *mockRouter) String() string {
 	return ""
 }
+
+func TestMap_FallsBackToNextProtocol(t *testing.T) {
+	upnp := &mockRouter{uPnPEnabled: false}
+	pcp := &mockRouter{uPnPEnabled: true}
+	config := &Config{
+		Protocols: []Protocol{
+			{Name: ProtocolUPnP, Interface: upnp},
+			{Name: ProtocolPCP, Interface: pcp},
+		},
+		MapUpdateInterval: time.Hour,
+		MapLifetime:       time.Hour,
+	}
+	portMapper := NewPortMapper(config, mocks.NewEventBus())
+
+	release, ok := portMapper.Map("id", "UDP", 51334, "Test")
+	defer release()
+
+	assert.True(t, ok)
+	assert.Equal(t, mapping{}, upnp.addedMapping())
+	assert.Equal(t, 51334, pcp.addedMapping().extport)
+	assert.Equal(t, []ProtocolStats{
+		{Protocol: ProtocolUPnP, Attempts: 1, Failures: 1},
+		{Protocol: ProtocolPCP, Attempts: 1, Successes: 1},
+	}, portMapper.Stats())
+}
+
+func TestMap_TracksMappingLifecycle(t *testing.T) {
+	upnp := &mockRouter{uPnPEnabled: false}
+	pcp := &mockRouter{uPnPEnabled: true}
+	config := &Config{
+		Protocols: []Protocol{
+			{Name: ProtocolUPnP, Interface: upnp},
+			{Name: ProtocolPCP, Interface: pcp},
+		},
+		MapUpdateInterval: time.Hour,
+		MapLifetime:       time.Hour,
+	}
+	bus := mocks.NewEventBus()
+	portMapper := NewPortMapper(config, bus)
+
+	release, ok := portMapper.Map("id", "UDP", 51334, "Test")
+	assert.True(t, ok)
+
+	mappings := portMapper.Mappings()
+	assert.Len(t, mappings, 1)
+	assert.Equal(t, ProtocolPCP, mappings[0].MapProtocol)
+	assert.Equal(t, StatusActive, mappings[0].Status)
+	assert.Equal(t, 51334, mappings[0].ExternalPort)
+	assert.WithinDuration(t, mappings[0].CreatedAt.Add(time.Hour), mappings[0].ExpiresAt, time.Second)
+
+	release()
+	mappings = portMapper.Mappings()
+	assert.Equal(t, StatusReleased, mappings[0].Status)
+	assert.False(t, mappings[0].ReleasedAt.IsZero())
+
+	pcp.Lock()
+	pcp.uPnPEnabled = false
+	pcp.Unlock()
+	_, ok = portMapper.Map("id", "UDP", 51335, "Test")
+	assert.False(t, ok)
+
+	mappings = portMapper.Mappings()
+	assert.Len(t, mappings, 2)
+	assert.Equal(t, StatusFailed, mappings[1].Status)
+	assert.Equal(t, "upnp: uPnP not supported; pcp: uPnP not supported", mappings[1].LastError)
+
+	var actions []string
+	for _, e := range bus.GetEventHistory() {
+		if e.Topic == AppTopicPortMapping {
+			actions = append(actions, e.Event.(AppEventPortMapping).Action)
+		}
+	}
+	assert.Equal(t, []string{ActionCreated, ActionReleased, ActionFailed}, actions)
+}
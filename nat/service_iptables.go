@@ -217,10 +217,17 @@ func makeIPTablesRules(opts Options) (rules []iptables.Rule) {
 		"--table", "nat")
 	rules = append(rules, rule)
 
+	rules = append(rules, makeEgressRules(opts)...)
+	rules = append(rules, makeFlowLimitRules(opts)...)
+
 	// ACCEPT forwarding rules
 	rules = append(rules, iptables.AppendTo(chainForward).RuleSpec("--source", vpnNetwork, "--jump", "ACCEPT"))
 	rules = append(rules, iptables.AppendTo(chainForward).RuleSpec("--destination", vpnNetwork, "--jump", "ACCEPT"))
 
+	if opts.SessionID != "" {
+		rules = append(rules, makeSessionAccountingRules(opts)...)
+	}
+
 	return rules
 }
 
@@ -0,0 +1,300 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nat
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/firewall/nftables"
+	"github.com/mysteriumnetwork/node/utils"
+)
+
+const (
+	nftFamily          = "ip"
+	nftTable           = "myst_nat"
+	nftChainMyst       = "myst"
+	nftChainPreRouting = "prerouting"
+	nftChainPostRoute  = "postrouting"
+	nftChainForward    = "forward"
+	nftChainAccounting = "accounting"
+)
+
+// serviceNftables sets up the same NAT/Firewall rules as serviceIPTables, keeping all of them in a single nftables table.
+type serviceNftables struct {
+	mu        sync.Mutex
+	ipForward serviceIPForward
+}
+
+// Setup sets NAT/Firewall rules for the given NATOptions.
+func (svc *serviceNftables) Setup(opts Options) (appliedRules []interface{}, err error) {
+	log.Info().Msg("Setting up NAT/Firewall rules")
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	// Store applied rules so we can remove if setup exits prematurely (one of the latter rules fails to apply)
+	var applied []nftables.Rule
+	var sets []nftSet
+	defer func() {
+		if err == nil {
+			return
+		}
+		log.Warn().Msg("Error detected, clearing up rules that were already setup")
+		for _, rule := range applied {
+			if err := nftables.DeleteRule(rule); err != nil {
+				log.Error().Err(err).Msg("Could not remove rule")
+			}
+		}
+		for _, set := range sets {
+			if err := set.delete(); err != nil {
+				log.Error().Err(err).Msg("Could not remove set")
+			}
+		}
+	}()
+
+	if opts.FlowRate > 0 {
+		set := nftSet(flowLimitName(opts.SessionID))
+		if err := set.add("ipv4_addr"); err != nil {
+			return nil, fmt.Errorf("failed to create flow limit set: %w", err)
+		}
+		sets = append(sets, set)
+	}
+
+	for _, rule := range makeNftablesRules(opts) {
+		rule, err := nftables.AddRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		applied = append(applied, rule)
+	}
+	log.Info().Msg("Setting up NAT/Firewall rules... done")
+
+	// Sets go last, they can only be deleted once no rule refers to them.
+	rules := make([]interface{}, 0, len(applied)+len(sets))
+	for i := range applied {
+		rules = append(rules, applied[i])
+	}
+	for i := range sets {
+		rules = append(rules, sets[i])
+	}
+	return rules, nil
+}
+
+// Del removes given NAT/Firewall rules that were previously set up.
+func (svc *serviceNftables) Del(rules []interface{}) (err error) {
+	log.Info().Msg("Deleting NAT/Firewall rules")
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	errs := utils.ErrorCollection{}
+	for _, rule := range rules {
+		log.Trace().Msgf("Deleting rule: %v", rule)
+		switch rule := rule.(type) {
+		case nftables.Rule:
+			errs.Add(nftables.DeleteRule(rule))
+		case nftSet:
+			errs.Add(rule.delete())
+		}
+	}
+	err = errs.Error()
+	log.Info().Err(err).Msg("Deleting NAT/Firewall rules... done")
+	return err
+}
+
+// Enable enables NAT service.
+func (svc *serviceNftables) Enable() error {
+	if config.GetBool(config.FlagUserMode) || config.GetBool(config.FlagUserspace) {
+		log.Info().Msg("Usermode active, nothing to do with nftables")
+		return nil
+	}
+
+	err := svc.prepare()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to prepare nftables setup")
+	}
+
+	err = svc.ipForward.Enable()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to enable IP forwarding")
+	}
+	return err
+}
+
+// Disable disables NAT service and deletes all rules.
+func (svc *serviceNftables) Disable() error {
+	if config.GetBool(config.FlagUserMode) || config.GetBool(config.FlagUserspace) {
+		log.Info().Msg("Usermode active, nothing to do with nftables")
+		return nil
+	}
+
+	svc.ipForward.Disable()
+	if err := nftables.DeleteTable(nftFamily, nftTable); err != nil {
+		return fmt.Errorf("failed to cleanup nftables table: %w", err)
+	}
+
+	return nil
+}
+
+func (svc *serviceNftables) prepare() error {
+	// Clean up setups from previous runs, just in case
+	if err := nftables.DeleteTable(nftFamily, nftTable); err != nil {
+		return fmt.Errorf("failed to delete stale nftables table: %w", err)
+	}
+
+	commands := [][]string{
+		{"add", "table", nftFamily, nftTable},
+		{"add", "chain", nftFamily, nftTable, nftChainMyst},
+		{"add", "chain", nftFamily, nftTable, nftChainPreRouting, "{", "type", "nat", "hook", "prerouting", "priority", "-100", ";", "}"},
+		{"add", "chain", nftFamily, nftTable, nftChainPostRoute, "{", "type", "nat", "hook", "postrouting", "priority", "100", ";", "}"},
+		{"add", "chain", nftFamily, nftTable, nftChainAccounting, "{", "type", "filter", "hook", "forward", "priority", "-150", ";", "}"},
+		{"add", "chain", nftFamily, nftTable, nftChainForward, "{", "type", "filter", "hook", "forward", "priority", "0", ";", "}"},
+	}
+	for _, args := range commands {
+		if _, err := nftables.Exec(args...); err != nil {
+			return fmt.Errorf("failed to create MYST nftables table: %w", err)
+		}
+	}
+
+	for _, ipNet := range protectedNetworks() {
+		// Protect private networks rule
+		_, err := nftables.Exec("add", "rule", nftFamily, nftTable, nftChainMyst, "ip", "daddr", ipNet.String(), "dnat", "to", "240.0.0.1")
+		if err != nil {
+			return fmt.Errorf("failed to create blackhole rule in the MYST nftables chain: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func makeNftablesRules(opts Options) (rules []nftables.Rule) {
+	vpnNetwork := opts.VPNNetwork.String()
+	dnsPort := ":" + strconv.Itoa(config.GetInt(config.FlagDNSListenPort))
+
+	rules = append(rules, nftables.InsertTo(nftFamily, nftTable, nftChainPreRouting).Expr(
+		"ip", "saddr", vpnNetwork, "jump", nftChainMyst))
+
+	// DNS port redirect rules
+	for _, protocol := range []string{"udp", "tcp"} {
+		rules = append(rules, nftables.InsertTo(nftFamily, nftTable, nftChainMyst).Expr(
+			"ip", "daddr", opts.DNSIP.String(), protocol, "dport", "53", "redirect", "to", dnsPort))
+	}
+
+	// NAT forwarding rule
+	rules = append(rules, nftables.AppendTo(nftFamily, nftTable, nftChainPostRoute).Expr(
+		"ip", "saddr", vpnNetwork, "ip", "daddr", "!=", vpnNetwork, "snat", "to", opts.ProviderExtIP.String()))
+
+	// Egress policy rules take precedence over the accepting ones
+	for _, network := range opts.Egress.BlockedNetworks() {
+		if network.IP.To4() == nil {
+			continue
+		}
+		rules = append(rules, nftables.InsertTo(nftFamily, nftTable, nftChainForward).Expr(
+			"ip", "saddr", vpnNetwork, "ip", "daddr", network.String(), "reject"))
+	}
+	for _, port := range opts.Egress.Ports {
+		for _, protocol := range []string{"tcp", "udp"} {
+			rules = append(rules, nftables.InsertTo(nftFamily, nftTable, nftChainForward).Expr(
+				"ip", "saddr", vpnNetwork, protocol, "dport", strconv.Itoa(port), "reject"))
+		}
+	}
+
+	// New flows over the rate are rejected, throttling consumers scanning or flooding through the provider.
+	// Rate is tracked per consumer address in the set of the session, like hashlimit srcip mode does.
+	if opts.FlowRate > 0 {
+		rate := strconv.Itoa(opts.FlowRate)
+		rules = append(rules, nftables.InsertTo(nftFamily, nftTable, nftChainForward).Expr(
+			"ip", "saddr", vpnNetwork, "ct", "state", "new",
+			"update", "@"+flowLimitName(opts.SessionID), "{", "ip", "saddr", "limit", "rate", "over", rate+"/second", "burst", rate, "packets", "}",
+			"reject"))
+	}
+
+	// ACCEPT forwarding rules
+	rules = append(rules, nftables.AppendTo(nftFamily, nftTable, nftChainForward).Expr("ip", "saddr", vpnNetwork, "accept"))
+	rules = append(rules, nftables.AppendTo(nftFamily, nftTable, nftChainForward).Expr("ip", "daddr", vpnNetwork, "accept"))
+
+	if opts.SessionID != "" {
+		// Session traffic accounting, rule counters give the bytes counted by the kernel
+		mark := fmt.Sprintf("0x%x", SessionMark(opts.SessionID))
+		rules = append(rules, nftables.AppendTo(nftFamily, nftTable, nftChainAccounting).Expr(
+			"ip", "saddr", vpnNetwork, "counter", "ct", "mark", "set", mark,
+			"comment", strconv.Quote(sessionComment(opts.SessionID, directionReceived))))
+		rules = append(rules, nftables.AppendTo(nftFamily, nftTable, nftChainAccounting).Expr(
+			"ip", "daddr", vpnNetwork, "counter", "ct", "mark", "set", mark,
+			"comment", strconv.Quote(sessionComment(opts.SessionID, directionSent))))
+	}
+
+	return rules
+}
+
+// nftSet is a dynamic set of MYST nftables table.
+type nftSet string
+
+func (s nftSet) add(keyType string) error {
+	_, err := nftables.Exec("add", "set", nftFamily, nftTable, string(s), "{", "type", keyType, ";", "flags", "dynamic", ";", "}")
+	return err
+}
+
+func (s nftSet) delete() error {
+	_, err := nftables.Exec("delete", "set", nftFamily, nftTable, string(s))
+	return err
+}
+
+// SessionCounters returns kernel counters of all sessions having accounting rules set up.
+func (svc *serviceNftables) SessionCounters() (map[string]SessionCounters, error) {
+	out, err := nftables.Exec("list", "chain", nftFamily, nftTable, nftChainAccounting)
+	if err != nil {
+		return nil, fmt.Errorf("could not list session accounting rules: %w", err)
+	}
+
+	return parseNftSessionCounters(strings.Join(out, "\n")), nil
+}
+
+var nftSessionCounterRegex = regexp.MustCompile(`counter packets \d+ bytes (\d+) .*comment "` + sessionCommentPrefix + `(\S+):(` + directionSent + `|` + directionReceived + `)"`)
+
+// parseNftSessionCounters parses the output of `nft list chain`, where every accounting rule has a counter.
+func parseNftSessionCounters(output string) map[string]SessionCounters {
+	counters := make(map[string]SessionCounters)
+	for _, line := range strings.Split(output, "\n") {
+		match := nftSessionCounterRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		bytes, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		c := counters[match[2]]
+		if match[3] == directionSent {
+			c.Sent += bytes
+		} else {
+			c.Received += bytes
+		}
+		counters[match[2]] = c
+	}
+
+	return counters
+}
+
+var _ SessionAccounter = &serviceNftables{}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package nat
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/firewall/egress"
+	"github.com/mysteriumnetwork/node/firewall/nftables"
+)
+
+func TestMakeNftablesRules(t *testing.T) {
+	_, vpnNetwork, _ := net.ParseCIDR("10.182.0.0/24")
+	rules := makeNftablesRules(Options{
+		VPNNetwork:    *vpnNetwork,
+		ProviderExtIP: net.ParseIP("1.2.3.4"),
+		DNSIP:         net.ParseIP("10.182.0.1"),
+		SessionID:     "session-1",
+		Egress:        egress.NewPolicy([]string{"25"}, nil, nil),
+		FlowRate:      20,
+	})
+
+	assert.Len(t, rules, 11)
+	assert.Equal(t, []string{
+		"--echo", "--handle", "add", "rule", "ip", "myst_nat", "postrouting",
+		"ip", "saddr", "10.182.0.0/24", "ip", "daddr", "!=", "10.182.0.0/24", "snat", "to", "1.2.3.4",
+	}, rules[3].ApplyArgs())
+	assert.Equal(t, []string{
+		"--echo", "--handle", "insert", "rule", "ip", "myst_nat", "forward",
+		"ip", "saddr", "10.182.0.0/24", "tcp", "dport", "25", "reject",
+	}, rules[4].ApplyArgs())
+	assert.Equal(t, []string{
+		"--echo", "--handle", "insert", "rule", "ip", "myst_nat", "forward",
+		"ip", "saddr", "10.182.0.0/24", "ct", "state", "new",
+		"update", "@" + flowLimitName("session-1"), "{", "ip", "saddr", "limit", "rate", "over", "20/second", "burst", "20", "packets", "}",
+		"reject",
+	}, rules[6].ApplyArgs())
+	assert.Equal(t, []string{
+		"--echo", "--handle", "add", "rule", "ip", "myst_nat", "accounting",
+		"ip", "saddr", "10.182.0.0/24", "counter", "ct", "mark", "set", fmt.Sprintf("0x%x", SessionMark("session-1")),
+		"comment", `"myst-session:session-1:received"`,
+	}, rules[9].ApplyArgs())
+}
+
+func TestServiceNftables_FlowLimitSet(t *testing.T) {
+	var commands []string
+	defer func(exec func(args ...string) ([]string, error)) { nftables.Exec = exec }(nftables.Exec)
+	nftables.Exec = func(args ...string) ([]string, error) {
+		commands = append(commands, strings.Join(args, " "))
+		return []string{"# handle 1"}, nil
+	}
+
+	_, vpnNetwork, _ := net.ParseCIDR("10.182.0.0/24")
+	svc := &serviceNftables{}
+	rules, err := svc.Setup(Options{
+		VPNNetwork:    *vpnNetwork,
+		ProviderExtIP: net.ParseIP("1.2.3.4"),
+		DNSIP:         net.ParseIP("10.182.0.1"),
+		SessionID:     "session-1",
+		FlowRate:      20,
+	})
+	assert.NoError(t, err)
+	set := flowLimitName("session-1")
+	assert.Equal(t, "add set ip myst_nat "+set+" { type ipv4_addr ; flags dynamic ; }", commands[0])
+
+	commands = nil
+	assert.NoError(t, svc.Del(rules))
+	assert.Len(t, commands, len(rules))
+	assert.Equal(t, "delete set ip myst_nat "+set, commands[len(commands)-1])
+}
+
+func TestParseNftSessionCounters(t *testing.T) {
+	output := `table ip myst_nat {
+	chain accounting {
+		type filter hook forward priority mangle; policy accept;
+		ip saddr 10.182.0.0/24 counter packets 12 bytes 1840 ct mark set 0x4d1a2b3c comment "myst-session:session-1:received"
+		ip daddr 10.182.0.0/24 counter packets 30 bytes 42000 ct mark set 0x4d1a2b3c comment "myst-session:session-1:sent"
+		ip saddr 10.182.1.0/24 counter packets 3 bytes 300 ct mark set 0x4d000001 comment "myst-session:session-2:received"
+	}
+}`
+
+	assert.Equal(t, map[string]SessionCounters{
+		"session-1": {Sent: 42000, Received: 1840},
+		"session-2": {Received: 300},
+	}, parseNftSessionCounters(output))
+}
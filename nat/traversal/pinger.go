@@ -23,12 +23,14 @@ import (
 	"fmt"
 	"net"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
 
 	"github.com/mysteriumnetwork/node/core/port"
 	"github.com/mysteriumnetwork/node/eventbus"
@@ -40,12 +42,13 @@ import (
 const StageName = "hole_punching"
 
 const (
-	bufferLen = 64
+	bufferLen = 128
 
 	maxTTL            = 128
 	msgOK             = "OK"
 	msgOKACK          = "OK_ACK"
 	msgPing           = "continuously pinging to "
+	msgPingIDSep      = " #"
 	sendRetryInterval = 5 * time.Millisecond
 	sendRetries       = 10
 )
@@ -65,6 +68,8 @@ type PingConfig struct {
 	Interval            time.Duration
 	Timeout             time.Duration
 	SendConnACKInterval time.Duration
+	// Strategies are raced on every local port, only advertised ports are pinged if empty.
+	Strategies []PortStrategy
 }
 
 // DefaultPingConfig returns default NAT pinger config.
@@ -73,6 +78,7 @@ func DefaultPingConfig() *PingConfig {
 		Interval:            5 * time.Millisecond,
 		Timeout:             10 * time.Second,
 		SendConnACKInterval: 100 * time.Millisecond,
+		Strategies:          DefaultPortStrategies(),
 	}
 }
 
@@ -140,7 +146,7 @@ func (p *Pinger) PingConsumerPeer(ctx context.Context, id string, remoteIP strin
 				continue
 			}
 
-			if err := ipv4.NewConn(res.conn).SetTTL(maxTTL); err != nil {
+			if err := setTTL(res.conn, maxTTL); err != nil {
 				res.conn.Close()
 				log.Warn().Err(res.err).Msg("Failed to set connection TTL")
 				continue
@@ -206,7 +212,7 @@ func (p *Pinger) PingProviderPeer(ctx context.Context, localIP, remoteIP string,
 				continue
 			}
 
-			if err := ipv4.NewConn(res.conn).SetTTL(maxTTL); err != nil {
+			if err := setTTL(res.conn, maxTTL); err != nil {
 				res.conn.Close()
 				log.Warn().Err(res.err).Msg("Failed to set connection TTL")
 				continue
@@ -318,8 +324,10 @@ func (p *Pinger) sendMsg(conn *net.UDPConn, msg string) {
 	}
 }
 
-func (p *Pinger) ping(ctx context.Context, conn *net.UDPConn, remoteAddr *net.UDPAddr, ttl int) error {
-	err := ipv4.NewConn(conn).SetTTL(ttl)
+// ping sends pings to all candidate addresses at once, so that every port prediction strategy
+// gets a chance to open NAT hole without waiting for the others to time out.
+func (p *Pinger) ping(ctx context.Context, conn *net.UDPConn, remoteAddrs []*net.UDPAddr, ttl, id int) error {
+	err := setTTL(conn, ttl)
 	if err != nil {
 		return fmt.Errorf("pinger setting ttl failed: %w", err)
 	}
@@ -329,17 +337,38 @@ func (p *Pinger) ping(ctx context.Context, conn *net.UDPConn, remoteAddr *net.UD
 		case <-ctx.Done():
 			return nil
 		case <-time.After(p.pingConfig.Interval):
-			_, err := conn.WriteToUDP([]byte(msgPing+remoteAddr.String()), remoteAddr)
-			if ctx.Err() != nil {
-				return nil
-			}
-			if err != nil {
-				return fmt.Errorf("pinging request failed: %w", err)
+			for _, remoteAddr := range remoteAddrs {
+				_, err := conn.WriteToUDP([]byte(pingMessage(remoteAddr, id)), remoteAddr)
+				if ctx.Err() != nil {
+					return nil
+				}
+				if err != nil {
+					return fmt.Errorf("pinging request failed: %w", err)
+				}
 			}
 		}
 	}
 }
 
+// pingMessage builds ping payload. Index of the port pair is appended so that predicted ports
+// hitting other sockets of the same peer are not paired crosswise. Older peers only check the prefix.
+func pingMessage(remoteAddr *net.UDPAddr, id int) string {
+	return msgPing + remoteAddr.String() + msgPingIDSep + strconv.Itoa(id)
+}
+
+// pingID returns port pair index of the ping payload if it has one.
+func pingID(msg string) (int, bool) {
+	i := strings.LastIndex(msg, msgPingIDSep)
+	if i < 0 {
+		return 0, false
+	}
+	id, err := strconv.Atoi(msg[i+len(msgPingIDSep):])
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
 func readFromConnWithContext(ctx context.Context, conn net.Conn, buf []byte) (n int, err error) {
 	readDone := make(chan struct{})
 	go func() {
@@ -376,7 +405,7 @@ func readFromUDPWithContext(ctx context.Context, conn *net.UDPConn, buf []byte)
 	}
 }
 
-func (p *Pinger) pingReceiver(ctx context.Context, conn *net.UDPConn) (*net.UDPAddr, error) {
+func (p *Pinger) pingReceiver(ctx context.Context, conn *net.UDPConn, id int) (*net.UDPAddr, error) {
 	buf := make([]byte, bufferLen)
 
 	for {
@@ -393,7 +422,14 @@ func (p *Pinger) pingReceiver(ctx context.Context, conn *net.UDPConn) (*net.UDPA
 		msg := string(buf[:n])
 		log.Debug().Msgf("Remote peer data received, len: %d", n)
 
-		if msg == msgOK || strings.HasPrefix(msg, msgPing) {
+		if msg == msgOK {
+			return raddr, nil
+		}
+		if strings.HasPrefix(msg, msgPing) {
+			if pid, ok := pingID(msg); ok && pid != id {
+				log.Debug().Msgf("Ping for port pair %d received on pair %d - attempting to continue", pid, id)
+				continue
+			}
 			return raddr, nil
 		}
 
@@ -427,7 +463,7 @@ func (p *Pinger) multiPingN(ctx context.Context, localIP, remoteIP string, local
 
 		go func(i, ttl int) {
 			defer wg.Done()
-			conn, err := p.singlePing(ctx, localIP, remoteIP, localPorts[i], remotePorts[i], ttl)
+			conn, err := p.singlePing(ctx, localIP, remoteIP, localPorts[i], remotePorts[i], ttl, i)
 			ch <- pingResponse{conn: conn, err: err, id: i}
 		}(i, ttl)
 
@@ -445,8 +481,9 @@ func (p *Pinger) multiPingN(ctx context.Context, localIP, remoteIP string, local
 	return ch, nil
 }
 
-func (p *Pinger) singlePing(ctx context.Context, localIP, remoteIP string, localPort, remotePort, ttl int) (*net.UDPConn, error) {
-	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP(localIP), Port: localPort})
+func (p *Pinger) singlePing(ctx context.Context, localIP, remoteIP string, localPort, remotePort, ttl, id int) (*net.UDPConn, error) {
+	network := udpNetwork(remoteIP)
+	conn, err := net.ListenUDP(network, &net.UDPAddr{IP: net.ParseIP(localIP), Port: localPort})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get connection: %w", err)
 	}
@@ -459,29 +496,35 @@ func (p *Pinger) singlePing(ctx context.Context, localIP, remoteIP string, local
 
 	log.Debug().Msgf("Local socket: %s", conn.LocalAddr())
 
-	remoteAddr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("%s:%d", remoteIP, remotePort))
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve remote address: %w", err)
+	candidates := predictPorts(p.pingConfig.Strategies, remotePort)
+	remoteAddrs := make([]*net.UDPAddr, 0, len(candidates))
+	for _, c := range candidates {
+		remoteAddr, err := net.ResolveUDPAddr(network, net.JoinHostPort(remoteIP, strconv.Itoa(c.port)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve remote address: %w", err)
+		}
+		remoteAddrs = append(remoteAddrs, remoteAddr)
 	}
 
 	ctx1, cl := context.WithCancel(ctx)
 	go func() {
-		err := p.ping(ctx1, conn, remoteAddr, ttl)
+		err := p.ping(ctx1, conn, remoteAddrs, ttl, id)
 		if err != nil {
 			log.Warn().Err(err).Msg("Error while pinging")
 		}
 	}()
 
 	laddr := conn.LocalAddr().(*net.UDPAddr)
-	raddr, err := p.pingReceiver(ctx, conn)
+	raddr, err := p.pingReceiver(ctx, conn, id)
 	cl()
 	if err != nil {
 		return nil, fmt.Errorf("ping receiver error: %w", err)
 	}
+	log.Debug().Msgf("Hole punched to %s using %s port strategy", raddr, punchStrategy(candidates, raddr.Port))
 	// need to dial same connection further
 	conn.Close()
 
-	newConn, err := net.DialUDP("udp4", laddr, raddr)
+	newConn, err := net.DialUDP(network, laddr, raddr)
 	if err != nil {
 		return nil, err
 	}
@@ -493,3 +536,19 @@ func (p *Pinger) singlePing(ctx context.Context, localIP, remoteIP string, local
 
 	return newConn, nil
 }
+
+// udpNetwork returns UDP network matching address family of the remote IP.
+func udpNetwork(remoteIP string) string {
+	if ip := net.ParseIP(remoteIP); ip != nil && ip.To4() == nil {
+		return "udp6"
+	}
+	return "udp4"
+}
+
+// setTTL sets TTL or hop limit depending on the address family of the connection.
+func setTTL(conn *net.UDPConn, ttl int) error {
+	if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok && addr.IP.To4() == nil {
+		return ipv6.NewConn(conn).SetHopLimit(ttl)
+	}
+	return ipv4.NewConn(conn).SetTTL(ttl)
+}
@@ -77,6 +77,43 @@ func TestPinger_PingPeer_N_Connections(t *testing.T) {
 	assert.Equal(t, conn2.RemoteAddr().(*net.UDPAddr).Port, peerConn2.LocalAddr().(*net.UDPAddr).Port)
 }
 
+func TestPinger_PingPeer_PredictedPorts(t *testing.T) {
+	pingConfig := &PingConfig{
+		Interval:            5 * time.Millisecond,
+		SendConnACKInterval: 5 * time.Millisecond,
+		Timeout:             5 * time.Second,
+		Strategies:          DefaultPortStrategies(),
+	}
+	provider := newPinger(pingConfig)
+	consumer := newPinger(pingConfig)
+
+	ports, err := port.NewFixedRangePool(port.Range{Start: 10000, End: 60000}).AcquireMultiple(4)
+	assert.NoError(t, err)
+	pPorts := []int{ports[0].Num(), ports[1].Num()}
+	cPorts := []int{ports[2].Num(), ports[3].Num()}
+	// Consumer sees provider ports shifted as if provider NAT allocated them sequentially.
+	advertised := []int{pPorts[0] - 1, pPorts[1] + 2}
+
+	peerConns := make(chan []*net.UDPConn, 1)
+	go func() {
+		conns, err := consumer.PingProviderPeer(context.Background(), "", "127.0.0.1", cPorts, advertised, 128, 2)
+		assert.NoError(t, err)
+		peerConns <- conns
+	}()
+	conns, err := provider.PingConsumerPeer(context.Background(), "id", "127.0.0.1", pPorts, cPorts, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, conns, 2)
+
+	consumerConns := <-peerConns
+	require.Len(t, consumerConns, 2)
+	for i := range conns {
+		assert.Equal(t, pPorts[i], consumerConns[i].RemoteAddr().(*net.UDPAddr).Port)
+		assert.Equal(t, cPorts[i], conns[i].RemoteAddr().(*net.UDPAddr).Port)
+		conns[i].Close()
+		consumerConns[i].Close()
+	}
+}
+
 func TestPinger_PingPeer_Not_Enough_Connections_Timeout(t *testing.T) {
 	pingConfig := &PingConfig{
 		Interval: 10 * time.Millisecond,
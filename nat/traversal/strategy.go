@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package traversal
+
+const (
+	// StrategyDirect pings the port advertised by peer.
+	StrategyDirect = "direct"
+	// StrategySequentialUp pings ports following the advertised one, for NATs allocating ports incrementally.
+	StrategySequentialUp = "sequential-up"
+	// StrategySequentialDown pings ports preceding the advertised one, for NATs allocating ports decrementally.
+	StrategySequentialDown = "sequential-down"
+
+	defaultPredictionRange = 3
+)
+
+// PortStrategy predicts ports peer NAT may have mapped for the advertised port.
+// All strategies are raced on the same local socket and the first port which answers wins.
+// Only UDP paths are punched since p2p channels are built on top of UDP connections.
+type PortStrategy struct {
+	Name    string
+	Predict func(port int) []int
+}
+
+// DirectPortStrategy returns strategy which pings the advertised port only.
+func DirectPortStrategy() PortStrategy {
+	return PortStrategy{
+		Name:    StrategyDirect,
+		Predict: func(port int) []int { return []int{port} },
+	}
+}
+
+// SequentialPortStrategy returns strategy which pings count ports next to the advertised one moving by step.
+func SequentialPortStrategy(name string, step, count int) PortStrategy {
+	return PortStrategy{
+		Name: name,
+		Predict: func(port int) []int {
+			ports := make([]int, 0, count)
+			for i := 1; i <= count; i++ {
+				ports = append(ports, port+i*step)
+			}
+			return ports
+		},
+	}
+}
+
+// DefaultPortStrategies returns port prediction strategies raced during hole punching by default.
+func DefaultPortStrategies() []PortStrategy {
+	return []PortStrategy{
+		DirectPortStrategy(),
+		SequentialPortStrategy(StrategySequentialUp, 1, defaultPredictionRange),
+		SequentialPortStrategy(StrategySequentialDown, -1, defaultPredictionRange),
+	}
+}
+
+type portCandidate struct {
+	port     int
+	strategy string
+}
+
+// predictPorts returns unique valid candidate ports for the advertised port in strategy order.
+// Advertised port is always tried first even if no strategy includes it.
+func predictPorts(strategies []PortStrategy, port int) []portCandidate {
+	candidates := []portCandidate{{port: port, strategy: StrategyDirect}}
+	seen := map[int]bool{port: true}
+	for _, s := range strategies {
+		for _, p := range s.Predict(port) {
+			if p <= 0 || p > 65535 || seen[p] {
+				continue
+			}
+			seen[p] = true
+			candidates = append(candidates, portCandidate{port: p, strategy: s.Name})
+		}
+	}
+	return candidates
+}
+
+// punchStrategy returns name of the strategy which predicted the port peer answered from.
+func punchStrategy(candidates []portCandidate, port int) string {
+	for _, c := range candidates {
+		if c.port == port {
+			return c.strategy
+		}
+	}
+	return "peer initiated"
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package traversal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredictPorts(t *testing.T) {
+	candidates := predictPorts(DefaultPortStrategies(), 1000)
+
+	var ports []int
+	for _, c := range candidates {
+		ports = append(ports, c.port)
+	}
+	assert.Equal(t, []int{1000, 1001, 1002, 1003, 999, 998, 997}, ports)
+	assert.Equal(t, StrategyDirect, punchStrategy(candidates, 1000))
+	assert.Equal(t, StrategySequentialUp, punchStrategy(candidates, 1002))
+	assert.Equal(t, StrategySequentialDown, punchStrategy(candidates, 997))
+}
+
+func TestPredictPorts_SkipsInvalidPorts(t *testing.T) {
+	candidates := predictPorts(DefaultPortStrategies(), 65535)
+
+	for _, c := range candidates {
+		assert.LessOrEqual(t, c.port, 65535)
+	}
+	assert.Len(t, candidates, 4)
+}
+
+func TestPingID(t *testing.T) {
+	id, ok := pingID(msgPing + "127.0.0.1:1000" + msgPingIDSep + "3")
+	assert.True(t, ok)
+	assert.Equal(t, 3, id)
+
+	_, ok = pingID(msgPing + "127.0.0.1:1000")
+	assert.False(t, ok)
+}
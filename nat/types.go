@@ -37,3 +37,12 @@ var HumanReadableTypes = map[NATType]string{
 	NATTypePortRestrictedCone: "Port Restricted Cone",
 	NATTypeSymmetric:          "Symmetric",
 }
+
+// ReachabilityDescriptions explains for every NAT type whether consumers can reach the node directly.
+var ReachabilityDescriptions = map[NATType]string{
+	NATTypeNone:               "Node has a public IP address, consumers connect directly",
+	NATTypeFullCone:           "Consumers can reach the node directly once a port is mapped",
+	NATTypeRestrictedCone:     "Consumers can reach the node after it sends a packet to their IP address, hole punching works",
+	NATTypePortRestrictedCone: "Consumers can reach the node after it sends a packet to their IP address and port, hole punching usually works",
+	NATTypeSymmetric:          "Mapped port changes for every destination, hole punching fails with symmetric NAT consumers and traffic needs a relay",
+}
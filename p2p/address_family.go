@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"net"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/ip"
+	"github.com/mysteriumnetwork/node/market"
+)
+
+// localIPv6 returns global IPv6 address usable for p2p connections or empty string if there is none.
+func localIPv6() string {
+	if !config.GetBool(config.FlagP2PIPv6) || !ip.HasIPv6Route() {
+		return ""
+	}
+
+	addr, err := ip.GlobalIPv6()
+	if err != nil {
+		log.Debug().Err(err).Msg("No global IPv6 address for p2p")
+		return ""
+	}
+	return addr.String()
+}
+
+// nat64Prefix returns configured NAT64 prefix or nil if NAT64 is not used.
+func nat64Prefix() *net.IPNet {
+	prefix := config.GetString(config.FlagP2PNAT64Prefix)
+	if prefix == "" {
+		return nil
+	}
+
+	_, network, err := net.ParseCIDR(prefix)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Invalid NAT64 prefix %q", prefix)
+		return nil
+	}
+	return network
+}
+
+// synthesizeNAT64 maps IPv4 peer address into configured NAT64 prefix when this node has no IPv4 connectivity.
+// Original address is returned if no translation is needed or possible.
+func synthesizeNAT64(peerIP string) string {
+	prefix := nat64Prefix()
+	if prefix == nil || ip.HasIPv4Route() {
+		return peerIP
+	}
+
+	addr := net.ParseIP(peerIP)
+	if addr == nil || addr.To4() == nil {
+		return peerIP
+	}
+
+	synthesized, err := ip.SynthesizeNAT64(prefix, addr)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed to synthesize NAT64 address for %s", peerIP)
+		return peerIP
+	}
+	return synthesized.String()
+}
+
+// ReachableAddressFamilies returns IP address families this node can use for p2p connections.
+// IPv4 peers are reachable from IPv6-only networks when NAT64 prefix is configured.
+func ReachableAddressFamilies() []string {
+	var families []string
+	if ip.HasIPv4Route() || nat64Prefix() != nil {
+		families = append(families, market.AddressFamilyIPv4)
+	}
+	if localIPv6() != "" {
+		families = append(families, market.AddressFamilyIPv6)
+	}
+	return families
+}
+
+// udpNetwork returns UDP network name matching address family of the given IP.
+func udpNetwork(addr string) string {
+	if parsed := net.ParseIP(addr); parsed != nil && parsed.To4() == nil {
+		return "udp6"
+	}
+	return "udp4"
+}
@@ -31,6 +31,7 @@ import (
 	kcp "github.com/xtaci/kcp-go/v5"
 	"golang.org/x/crypto/nacl/box"
 
+	"github.com/mysteriumnetwork/node/communication"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/router"
 	"github.com/mysteriumnetwork/node/trace"
@@ -169,6 +170,9 @@ type channel struct {
 	// peer is remote peer holding it's public key and address.
 	peer *peer
 
+	// codec wraps request and reply payloads into envelopes of the message version negotiated during config exchange.
+	codec communication.Codec
+
 	// localSessionAddr is KCP UDP conn address to which packets are written from remote conn.
 	localSessionAddr *net.UDPAddr
 
@@ -185,7 +189,7 @@ type channel struct {
 
 // newChannel creates new p2p channel with initialized crypto primitives for data encryption
 // and starts listening for connections.
-func newChannel(remoteConn *net.UDPConn, privateKey PrivateKey, peerPubKey PublicKey, peerCompatibility int) (*channel, error) {
+func newChannel(remoteConn *net.UDPConn, privateKey PrivateKey, peerPubKey PublicKey, peerCompatibility int, messageVersion int) (*channel, error) {
 	peerAddr := remoteConn.RemoteAddr().(*net.UDPAddr)
 	localAddr := remoteConn.LocalAddr().(*net.UDPAddr)
 	remoteConn, err := reopenConn(remoteConn)
@@ -231,6 +235,7 @@ func newChannel(remoteConn *net.UDPConn, privateKey PrivateKey, peerPubKey Publi
 		streams:          make(map[uint64]*stream),
 		privateKey:       privateKey,
 		peer:             &peer,
+		codec:            communication.NewCodecVersioned(communication.NewCodecBytes(), messageVersion),
 		localSessionAddr: localConn.LocalAddr().(*net.UDPAddr),
 		serviceConn:      nil,
 		stop:             make(chan struct{}, 1),
@@ -414,9 +419,18 @@ func (c *channel) handleRequest(msg *transportMsg) {
 		return
 	}
 
+	var data []byte
+	if err := c.codec.Unpack(msg.data, &data); err != nil {
+		log.Err(err).Msgf("Could not unpack %q request", msg.topic)
+		resMsg.statusCode = statusCodeInternalErr
+		resMsg.msg = err.Error()
+		c.sendQueue <- &resMsg
+		return
+	}
+
 	ctx := defaultContext{
 		req: &Message{
-			Data: msg.data,
+			Data: data,
 		},
 		peerID: c.peerID,
 	}
@@ -431,8 +445,13 @@ func (c *channel) handleRequest(msg *transportMsg) {
 		resMsg.data = []byte(ctx.publicError.Error())
 	} else {
 		resMsg.statusCode = statusCodeOK
+		var data []byte
 		if ctx.res != nil {
-			resMsg.data = ctx.res.Data
+			data = ctx.res.Data
+		}
+		if resMsg.data, err = c.codec.Pack(data); err != nil {
+			resMsg.statusCode = statusCodeInternalErr
+			resMsg.msg = err.Error()
 		}
 	}
 	c.sendQueue <- &resMsg
@@ -519,11 +538,16 @@ func (c *channel) Handle(topic string, handler HandlerFunc) {
 
 // sendRequest sends message to send queue and waits for response.
 func (c *channel) sendRequest(ctx context.Context, topic string, m *Message) (*Message, error) {
+	data, err := c.codec.Pack(m.Data)
+	if err != nil {
+		return nil, fmt.Errorf("could not pack request to %q: %w", topic, err)
+	}
+
 	s := c.addStream()
 	defer c.deleteStream(s.id)
 
 	// Send request.
-	c.sendQueue <- &transportMsg{id: s.id, topic: topic, data: m.Data}
+	c.sendQueue <- &transportMsg{id: s.id, topic: topic, data: data}
 
 	// Wait for response.
 	select {
@@ -539,7 +563,11 @@ func (c *channel) sendRequest(ctx context.Context, topic string, m *Message) (*M
 			}
 			return nil, fmt.Errorf("peer error: %w", errors.New(res.msg))
 		}
-		return &Message{Data: res.data}, nil
+		var data []byte
+		if err := c.codec.Unpack(res.data, &data); err != nil {
+			return nil, fmt.Errorf("could not unpack reply to %q: %w", topic, err)
+		}
+		return &Message{Data: data}, nil
 	}
 }
 
@@ -592,7 +620,8 @@ func (c *channel) setUpnpPortsRelease(release func()) {
 func reopenConn(conn *net.UDPConn) (*net.UDPConn, error) {
 	// conn first must be closed to prevent use of WriteTo with pre-connected connection error.
 	conn.Close()
-	conn, err := net.ListenUDP("udp4", conn.LocalAddr().(*net.UDPAddr))
+	laddr := conn.LocalAddr().(*net.UDPAddr)
+	conn, err := net.ListenUDP(udpNetwork(laddr.IP.String()), laddr)
 	if err != nil {
 		return nil, fmt.Errorf("could not listen UDP: %w", err)
 	}
@@ -28,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mysteriumnetwork/node/communication"
 	"github.com/mysteriumnetwork/node/core/port"
 	"github.com/mysteriumnetwork/node/pb"
 )
@@ -284,7 +285,7 @@ func reopenChannel(c *channel, addr *net.UDPAddr) (*channel, error) {
 	if err != nil {
 		return nil, err
 	}
-	ch, err := newChannel(punchedConn, c.privateKey, c.peer.publicKey, 1)
+	ch, err := newChannel(punchedConn, c.privateKey, c.peer.publicKey, 1, communication.MessageVersionCurrent)
 	if err != nil {
 		return nil, err
 	}
@@ -297,7 +298,7 @@ func reopenChannelWithNewLocalAddr(c *channel) (*channel, error) {
 	if err != nil {
 		return nil, err
 	}
-	ch, err := newChannel(punchedConn, c.privateKey, c.peer.publicKey, 1)
+	ch, err := newChannel(punchedConn, c.privateKey, c.peer.publicKey, 1, communication.MessageVersionCurrent)
 	if err != nil {
 		return nil, err
 	}
@@ -305,6 +306,42 @@ func reopenChannelWithNewLocalAddr(c *channel) (*channel, error) {
 	return ch, err
 }
 
+func TestChannel_LegacyPeerSendsBarePayloads(t *testing.T) {
+	ports, err := acquirePorts(2)
+	require.NoError(t, err)
+	providerConn, err := net.DialUDP("udp4", &net.UDPAddr{Port: ports[0]}, &net.UDPAddr{Port: ports[1]})
+	require.NoError(t, err)
+	consumerConn, err := net.DialUDP("udp4", &net.UDPAddr{Port: ports[1]}, &net.UDPAddr{Port: ports[0]})
+	require.NoError(t, err)
+	providerPublicKey, providerPrivateKey, err := GenerateKey()
+	require.NoError(t, err)
+	consumerPublicKey, consumerPrivateKey, err := GenerateKey()
+	require.NoError(t, err)
+
+	// Peers which negotiated legacy version talk the way older nodes do.
+	provider, err := newChannel(providerConn, providerPrivateKey, consumerPublicKey, 1, communication.MessageVersionLegacy)
+	require.NoError(t, err)
+	provider.launchReadSendLoops()
+	defer provider.Close()
+	consumer, err := newChannel(consumerConn, consumerPrivateKey, providerPublicKey, 1, communication.MessageVersionLegacy)
+	require.NoError(t, err)
+	consumer.launchReadSendLoops()
+	defer consumer.Close()
+
+	received := make(chan []byte, 1)
+	provider.Handle("test", func(c Context) error {
+		received <- c.Request().Data
+		return c.OkWithReply(&Message{Data: []byte("pong")})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	reply, err := consumer.Send(ctx, "test", &Message{Data: []byte("ping")})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ping"), <-received)
+	assert.Equal(t, []byte("pong"), reply.Data)
+}
+
 func createTestChannels() (Channel, Channel, error) {
 	ports, err := acquirePorts(2)
 	if err != nil {
@@ -332,13 +369,13 @@ func createTestChannels() (Channel, Channel, error) {
 		return nil, nil, err
 	}
 
-	provider, err := newChannel(providerConn, providerPrivateKey, consumerPublicKey, 1)
+	provider, err := newChannel(providerConn, providerPrivateKey, consumerPublicKey, 1, communication.MessageVersionCurrent)
 	if err != nil {
 		return nil, nil, err
 	}
 	provider.launchReadSendLoops()
 
-	consumer, err := newChannel(consumerConn, consumerPrivateKey, providerPublicKey, 1)
+	consumer, err := newChannel(consumerConn, consumerPrivateKey, providerPublicKey, 1, communication.MessageVersionCurrent)
 	if err != nil {
 		return nil, nil, err
 	}
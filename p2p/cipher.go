@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mysteriumnetwork/node/communication"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+// offerCipher starts dialog cipher negotiation on consumer side, the offer travels with the first exchange message.
+func offerCipher(signer identity.Signer) (*communication.CipherNegotiator, []byte, error) {
+	negotiator, err := communication.NewCipherNegotiator(signer, communication.SupportedCiphers())
+	if err != nil {
+		return nil, nil, err
+	}
+	offer, err := negotiator.Offer()
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := json.Marshal(offer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not marshal cipher offer: %w", err)
+	}
+	return negotiator, data, nil
+}
+
+// negotiatedCipher is the outcome of cipher negotiation: codec sealing exchanged configs
+// and message version used by the channel.
+type negotiatedCipher struct {
+	codec          communication.Codec
+	messageVersion int
+}
+
+// legacyCipher is used with peers predating cipher negotiation, their configs are sealed with exchange keys
+// and channel messages are sent bare.
+var legacyCipher = negotiatedCipher{messageVersion: communication.MessageVersionLegacy}
+
+// acceptCipher answers consumer's cipher offer. Consumers predating negotiation send no offer and get no answer.
+func acceptCipher(signer identity.Signer, peerID identity.Identity, offer []byte) ([]byte, negotiatedCipher, error) {
+	if len(offer) == 0 {
+		return nil, legacyCipher, nil
+	}
+
+	var cipherOffer communication.CipherOffer
+	if err := json.Unmarshal(offer, &cipherOffer); err != nil {
+		return nil, negotiatedCipher{}, fmt.Errorf("could not unmarshal cipher offer: %w", err)
+	}
+	negotiator, err := communication.NewCipherNegotiator(signer, communication.SupportedCiphers())
+	if err != nil {
+		return nil, negotiatedCipher{}, err
+	}
+	answer, cipher, err := negotiator.Accept(cipherOffer, identity.NewVerifierIdentity(peerID))
+	if err != nil {
+		return nil, negotiatedCipher{}, fmt.Errorf("could not accept cipher offer: %w", err)
+	}
+	data, err := json.Marshal(answer)
+	if err != nil {
+		return nil, negotiatedCipher{}, fmt.Errorf("could not marshal cipher answer: %w", err)
+	}
+	return data, newNegotiatedCipher(negotiator, cipher), nil
+}
+
+// completeCipher verifies provider's answer to the consumer's offer. Providers predating negotiation send no answer.
+func completeCipher(negotiator *communication.CipherNegotiator, peer identity.Verifier, answer []byte) (negotiatedCipher, error) {
+	if len(answer) == 0 {
+		return legacyCipher, nil
+	}
+
+	var cipherAnswer communication.CipherAnswer
+	if err := json.Unmarshal(answer, &cipherAnswer); err != nil {
+		return negotiatedCipher{}, fmt.Errorf("could not unmarshal cipher answer: %w", err)
+	}
+	cipher, err := negotiator.Complete(cipherAnswer, peer)
+	if err != nil {
+		return negotiatedCipher{}, fmt.Errorf("could not complete cipher negotiation: %w", err)
+	}
+	return newNegotiatedCipher(negotiator, cipher), nil
+}
+
+func newNegotiatedCipher(negotiator *communication.CipherNegotiator, cipher communication.Cipher) negotiatedCipher {
+	return negotiatedCipher{
+		codec:          negotiator.Codec(communication.NewCodecBytes(), cipher),
+		messageVersion: negotiator.MessageVersion(),
+	}
+}
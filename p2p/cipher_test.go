@@ -0,0 +1,120 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/communication"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/pb"
+)
+
+type exchangePeer struct {
+	id         identity.Identity
+	signer     identity.Signer
+	publicKey  PublicKey
+	privateKey PrivateKey
+}
+
+func newExchangePeer(t *testing.T) exchangePeer {
+	id := identity.FromAddress("0x53a835143c0ef3bbcbfa796d7eb738ca7dd28f68")
+	ks := identity.NewMockKeystoreWith(identity.MockKeys)
+	require.NoError(t, ks.Unlock(accounts.Account{Address: id.ToCommonAddress()}, ""))
+
+	publicKey, privateKey, err := GenerateKey()
+	require.NoError(t, err)
+
+	return exchangePeer{id: id, signer: identity.NewSigner(ks, id), publicKey: publicKey, privateKey: privateKey}
+}
+
+// exchangeConfigs mimics config exchange between dialer and listener, returning configs received by both sides.
+func exchangeConfigs(t *testing.T, consumerNegotiates, providerNegotiates bool) (providerCipher, consumerCipher negotiatedCipher, toConsumer, toProvider *pb.P2PConnectConfig) {
+	consumer, provider := newExchangePeer(t), newExchangePeer(t)
+
+	var offer []byte
+	var negotiator *communication.CipherNegotiator
+	if consumerNegotiates {
+		var err error
+		negotiator, offer, err = offerCipher(consumer.signer)
+		require.NoError(t, err)
+	}
+
+	var answer []byte
+	if providerNegotiates {
+		var err error
+		answer, providerCipher, err = acceptCipher(provider.signer, consumer.id, offer)
+		require.NoError(t, err)
+	}
+	sealed, err := encryptConnConfigMsg(&pb.P2PConnectConfig{PublicIP: "1.1.1.1"}, providerCipher.codec, provider.privateKey, consumer.publicKey)
+	require.NoError(t, err)
+
+	if consumerNegotiates {
+		consumerCipher, err = completeCipher(negotiator, identity.NewVerifierIdentity(provider.id), answer)
+		require.NoError(t, err)
+	}
+	toConsumer, err = decryptConnConfigMsg(sealed, consumerCipher.codec, consumer.privateKey, provider.publicKey)
+	require.NoError(t, err)
+
+	sealed, err = encryptConnConfigMsg(&pb.P2PConnectConfig{PublicIP: "2.2.2.2"}, consumerCipher.codec, consumer.privateKey, provider.publicKey)
+	require.NoError(t, err)
+	toProvider, err = decryptConnConfigMsg(sealed, providerCipher.codec, provider.privateKey, consumer.publicKey)
+	require.NoError(t, err)
+
+	return providerCipher, consumerCipher, toConsumer, toProvider
+}
+
+func TestConfigExchange_NegotiatesCipher(t *testing.T) {
+	providerCipher, consumerCipher, toConsumer, toProvider := exchangeConfigs(t, true, true)
+	assert.NotNil(t, providerCipher.codec)
+	assert.NotNil(t, consumerCipher.codec)
+	assert.Equal(t, communication.MessageVersionCurrent, providerCipher.messageVersion)
+	assert.Equal(t, communication.MessageVersionCurrent, consumerCipher.messageVersion)
+	assert.Equal(t, "1.1.1.1", toConsumer.PublicIP)
+	assert.Equal(t, "2.2.2.2", toProvider.PublicIP)
+}
+
+func TestConfigExchange_FallsBackForOlderPeers(t *testing.T) {
+	for name, peers := range map[string][2]bool{
+		"older consumer": {false, true},
+		"older provider": {true, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			providerCipher, consumerCipher, toConsumer, toProvider := exchangeConfigs(t, peers[0], peers[1])
+			assert.Nil(t, providerCipher.codec)
+			assert.Nil(t, consumerCipher.codec)
+			assert.Equal(t, communication.MessageVersionLegacy, providerCipher.messageVersion)
+			assert.Equal(t, communication.MessageVersionLegacy, consumerCipher.messageVersion)
+			assert.Equal(t, "1.1.1.1", toConsumer.PublicIP)
+			assert.Equal(t, "2.2.2.2", toProvider.PublicIP)
+		})
+	}
+}
+
+func TestConfigExchange_RejectsForeignOffer(t *testing.T) {
+	consumer, provider := newExchangePeer(t), newExchangePeer(t)
+	_, offer, err := offerCipher(&identity.SignerFake{})
+	require.NoError(t, err)
+
+	_, _, err = acceptCipher(provider.signer, consumer.id, offer)
+	assert.Error(t, err)
+}
@@ -26,6 +26,7 @@ import (
 
 	"google.golang.org/protobuf/proto"
 
+	"github.com/mysteriumnetwork/node/communication"
 	"github.com/mysteriumnetwork/node/communication/nats"
 	"github.com/mysteriumnetwork/node/core/port"
 	"github.com/mysteriumnetwork/node/identity"
@@ -104,12 +105,16 @@ func unpackSignedMsg(verifier identity.Verifier, b []byte) (*pb.P2PSignedMsg, id
 	return &signedMsg, id, nil
 }
 
-// encryptConnConfigMsg encrypts proto message and returns bytes.
-func encryptConnConfigMsg(msg *pb.P2PConnectConfig, privateKey PrivateKey, peerPubKey PublicKey) ([]byte, error) {
+// encryptConnConfigMsg encrypts proto message and returns bytes. Configs are sealed with the negotiated codec,
+// or with exchange keys if peer predates cipher negotiation and codec is nil.
+func encryptConnConfigMsg(msg *pb.P2PConnectConfig, codec communication.Codec, privateKey PrivateKey, peerPubKey PublicKey) ([]byte, error) {
 	protoBytes, err := proto.Marshal(msg)
 	if err != nil {
 		return nil, err
 	}
+	if codec != nil {
+		return codec.Pack(protoBytes)
+	}
 	ciphertext, err := privateKey.Encrypt(peerPubKey, protoBytes)
 	if err != nil {
 		return nil, err
@@ -118,8 +123,14 @@ func encryptConnConfigMsg(msg *pb.P2PConnectConfig, privateKey PrivateKey, peerP
 }
 
 // decryptConnConfigMsg decrypts bytes to connect config.
-func decryptConnConfigMsg(ciphertext []byte, privateKey PrivateKey, peerPubKey PublicKey) (*pb.P2PConnectConfig, error) {
-	peerConnectConfigProtoBytes, err := privateKey.Decrypt(peerPubKey, ciphertext)
+func decryptConnConfigMsg(ciphertext []byte, codec communication.Codec, privateKey PrivateKey, peerPubKey PublicKey) (*pb.P2PConnectConfig, error) {
+	var peerConnectConfigProtoBytes []byte
+	var err error
+	if codec != nil {
+		err = codec.Unpack(ciphertext, &peerConnectConfigProtoBytes)
+	} else {
+		peerConnectConfigProtoBytes, err = privateKey.Decrypt(peerPubKey, ciphertext)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("could not decrypt config to proto bytes: %w", err)
 	}
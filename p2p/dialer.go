@@ -25,10 +25,10 @@ import (
 	"sync"
 	"time"
 
-	nats_lib "github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/mysteriumnetwork/node/communication"
 	"github.com/mysteriumnetwork/node/communication/nats"
 	"github.com/mysteriumnetwork/node/core/ip"
 	"github.com/mysteriumnetwork/node/core/port"
@@ -37,12 +37,16 @@ import (
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/nat/traversal"
 	"github.com/mysteriumnetwork/node/p2p/compat"
+	"github.com/mysteriumnetwork/node/p2p/relay"
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/node/router"
 	"github.com/mysteriumnetwork/node/trace"
 )
 
-const maxBrokerConnectAttempts = 25
+const (
+	maxBrokerConnectAttempts = 25
+	relayDialTimeout         = 30 * time.Second
+)
 
 // Dialer knows how to exchange p2p keys and encrypted configuration and creates ready to use p2p channels.
 type Dialer interface {
@@ -52,9 +56,11 @@ type Dialer interface {
 }
 
 // NewDialer creates new p2p communication dialer which is used on consumer side.
-func NewDialer(broker brokerConnector, signer identity.SignerFactory, verifierFactory identity.VerifierFactory, ipResolver ip.Resolver, portPool port.ServicePortSupplier, eventBus eventbus.EventBus) Dialer {
+// Configuration is exchanged through peer-to-peer transport first if it is given, falling back to provider's broker.
+func NewDialer(broker brokerConnector, peers communication.Transport, signer identity.SignerFactory, verifierFactory identity.VerifierFactory, ipResolver ip.Resolver, portPool port.ServicePortSupplier, eventBus eventbus.EventBus) Dialer {
 	return &dialer{
 		broker:          broker,
+		peers:           peers,
 		ipResolver:      ipResolver,
 		signer:          signer,
 		verifierFactory: verifierFactory,
@@ -68,6 +74,7 @@ func NewDialer(broker brokerConnector, signer identity.SignerFactory, verifierFa
 type dialer struct {
 	portPool        port.ServicePortSupplier
 	broker          brokerConnector
+	peers           communication.Transport
 	consumerPinger  natConsumerPinger
 	signer          identity.SignerFactory
 	verifierFactory identity.VerifierFactory
@@ -87,9 +94,13 @@ func (m *dialer) Dial(ctx context.Context, consumerID, providerID identity.Ident
 	}
 	defer brokerConn.Close()
 
+	brokerTransport := nats.NewTransport(brokerConn)
+	defer brokerTransport.Close()
+	transport := m.transport(brokerTransport)
+
 	peerReady := make(chan struct{})
 	var once sync.Once
-	_, err = brokerConn.Subscribe(channelHandlersReadySubject(providerID, serviceType), func(msg *nats_lib.Msg) {
+	readySub, err := transport.Subscribe(channelHandlersReadySubject(providerID, serviceType), func(msg *communication.TransportMessage) {
 		defer once.Do(func() { close(peerReady) })
 		if err := m.channelHandlersReady(msg); err != nil {
 			log.Err(err).Msg("Channel handlers ready handler setup failed")
@@ -99,8 +110,9 @@ func (m *dialer) Dial(ctx context.Context, consumerID, providerID identity.Ident
 	if err != nil {
 		return nil, fmt.Errorf("could not subscribe to ready subject: %w", err)
 	}
+	defer readySub.Unsubscribe()
 
-	config, err = m.startConfigExchange(config, ctx, brokerConn, providerID, serviceType, consumerID)
+	config, err = m.startConfigExchange(config, ctx, transport, providerID, serviceType, consumerID)
 	if err != nil {
 		return nil, fmt.Errorf("could not exchange config: %w", err)
 	}
@@ -109,14 +121,8 @@ func (m *dialer) Dial(ctx context.Context, consumerID, providerID identity.Ident
 		return nil, fmt.Errorf("peer using compatibility version lower than 2: %d", config.compatibility)
 	}
 
-	if serviceType != "openvpn" { // OpenVPN does this automatically, we don't need to perform it manually.
-		if err := router.ExcludeIP(net.ParseIP(config.peerIP())); err != nil {
-			return nil, fmt.Errorf("failed to exclude peer IP from default routes: %w", err)
-		}
-	}
-
-	if _, err := firewall.AllowIPAccess(config.peerPublicIP); err != nil {
-		return nil, fmt.Errorf("could not add peer IP firewall rule: %w", err)
+	if err := m.allowPeer(serviceType, config); err != nil {
+		return nil, err
 	}
 
 	config.publicIP, config.localPorts, err = m.prepareLocalPorts(config)
@@ -127,18 +133,53 @@ func (m *dialer) Dial(ctx context.Context, consumerID, providerID identity.Ident
 	config.publicPorts = stunPorts(consumerID, m.eventBus, config.localPorts...)
 
 	// Finally send consumer encrypted and signed connect config in ack message.
-	err = m.ackConfigExchange(config, ctx, brokerConn, providerID, serviceType, consumerID)
+	err = m.ackConfigExchange(config, ctx, transport, providerID, serviceType, consumerID)
 	if err != nil {
 		return nil, fmt.Errorf("could not ack config: %w", err)
 	}
 
-	dial := m.dialPinger
-	if len(config.peerPorts) == requiredConnCount {
-		dial = m.dialDirect
+	var conn1, conn2 *net.UDPConn
+	if preferIPv6Direct(config) {
+		conn1, conn2, err = m.dialIPv6(ctx, config)
+		if err != nil {
+			log.Warn().Err(err).Msg("Could not connect to provider over IPv6, falling back to IPv4")
+			config.disableIPv6()
+			config.peerPublicIP = synthesizeNAT64(config.peerPublicIP)
+			if err := m.allowPeer(serviceType, config); err != nil {
+				return nil, err
+			}
+		}
 	}
-	conn1, conn2, err := dial(ctx, providerID, config)
-	if err != nil {
-		return nil, fmt.Errorf("could not dial p2p channel: %w", err)
+
+	if conn1 == nil && preferLANDirect(config) {
+		conn1, conn2, err = m.dialLAN(ctx, config)
+		if err != nil {
+			log.Warn().Err(err).Msg("Could not connect to provider over LAN, falling back to public address")
+			config.disableLAN()
+		}
+	}
+
+	if conn1 == nil && config.preferRelay && config.relay != "" {
+		log.Debug().Msgf("Provider is behind CGNAT, connecting via relay %s", config.relay)
+		conn1, conn2, err = m.dialRelay(ctx, config)
+		if err != nil {
+			return nil, fmt.Errorf("could not dial p2p channel via relay: %w", err)
+		}
+	}
+
+	if conn1 == nil {
+		dial := m.dialPinger
+		if len(config.remotePorts()) == requiredConnCount {
+			dial = m.dialDirect
+		}
+		conn1, conn2, err = dial(ctx, providerID, config)
+		if err != nil && config.relay != "" {
+			log.Warn().Err(err).Msgf("Could not dial provider directly, falling back to relay %s", config.relay)
+			conn1, conn2, err = m.dialRelay(ctx, config)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not dial p2p channel: %w", err)
+		}
 	}
 
 	// Wait until provider confirms that channel handlers are ready.
@@ -150,7 +191,7 @@ func (m *dialer) Dial(ctx context.Context, consumerID, providerID identity.Ident
 		return nil, errors.New("timeout while performing configuration exchange")
 	}
 
-	channel, err := newChannel(conn1, config.privateKey, config.peerPubKey, config.compatibility)
+	channel, err := newChannel(conn1, config.privateKey, config.peerPubKey, config.compatibility, config.cipher.messageVersion)
 	if err != nil {
 		return nil, fmt.Errorf("could not create p2p channel during dial: %w", err)
 	}
@@ -163,6 +204,24 @@ func (m *dialer) Dial(ctx context.Context, consumerID, providerID identity.Ident
 	return channel, nil
 }
 
+// allowPeer lets traffic to the peer address bypass VPN routes and firewall.
+func (m *dialer) allowPeer(serviceType string, config *p2pConnectConfig) error {
+	if config.peerPublicAddr() == "" {
+		return errors.New("provider is not reachable over any of local address families")
+	}
+
+	if serviceType != "openvpn" { // OpenVPN does this automatically, we don't need to perform it manually.
+		if err := router.ExcludeIP(net.ParseIP(config.peerIP())); err != nil {
+			return fmt.Errorf("failed to exclude peer IP from default routes: %w", err)
+		}
+	}
+
+	if _, err := firewall.AllowIPAccess(config.peerPublicAddr()); err != nil {
+		return fmt.Errorf("could not add peer IP firewall rule: %w", err)
+	}
+	return nil
+}
+
 func (m *dialer) connect(contactDef ContactDefinition, tracer *trace.Tracer) (conn nats.Connection, err error) {
 	trace := tracer.StartStage("Consumer P2P connect")
 	defer tracer.EndStage(trace)
@@ -185,7 +244,7 @@ func (m *dialer) connect(contactDef ContactDefinition, tracer *trace.Tracer) (co
 	return conn, err
 }
 
-func (m *dialer) startConfigExchange(config *p2pConnectConfig, ctx context.Context, brokerConn nats.Connection, providerID identity.Identity, serviceType string, consumerID identity.Identity) (*p2pConnectConfig, error) {
+func (m *dialer) startConfigExchange(config *p2pConnectConfig, ctx context.Context, transport communication.Transport, providerID identity.Identity, serviceType string, consumerID identity.Identity) (*p2pConnectConfig, error) {
 	trace := config.tracer.StartStage("Consumer P2P exchange")
 	defer config.tracer.EndStage(trace)
 
@@ -194,15 +253,22 @@ func (m *dialer) startConfigExchange(config *p2pConnectConfig, ctx context.Conte
 		return nil, fmt.Errorf("could not generate consumer p2p keys: %w", err)
 	}
 
+	negotiator, cipherOffer, err := offerCipher(m.signer(consumerID))
+	if err != nil {
+		return nil, fmt.Errorf("could not offer ciphers: %w", err)
+	}
+
 	beginExchangeMsg := &pb.P2PConfigExchangeMsg{
-		PublicKey: pubKey.Hex(),
+		PublicKey:         pubKey.Hex(),
+		Traceparent:       config.tracer.SpanContext().Traceparent(),
+		CipherNegotiation: cipherOffer,
 	}
 	log.Debug().Msgf("Consumer %s sending public key %s to provider %s", consumerID.Address, beginExchangeMsg.PublicKey, providerID.Address)
 	packedMsg, err := packSignedMsg(m.signer, consumerID, beginExchangeMsg)
 	if err != nil {
 		return nil, fmt.Errorf("could not pack signed message: %v", err)
 	}
-	exchangeMsgBrokerReply, err := m.sendSignedMsg(ctx, configExchangeSubject(providerID, serviceType), packedMsg, brokerConn)
+	exchangeMsgBrokerReply, err := m.sendSignedMsg(ctx, configExchangeSubject(providerID, serviceType), packedMsg, transport)
 	if err != nil {
 		return nil, fmt.Errorf("could not send signed message: %w", err)
 	}
@@ -220,21 +286,44 @@ func (m *dialer) startConfigExchange(config *p2pConnectConfig, ctx context.Conte
 	if err != nil {
 		return nil, err
 	}
-	peerConnConfig, err := decryptConnConfigMsg(exchangeMsgReply.ConfigCiphertext, privateKey, peerPubKey)
+	cipher, err := completeCipher(negotiator, m.verifierFactory(providerID), exchangeMsgReply.CipherNegotiation)
+	if err != nil {
+		return nil, err
+	}
+	peerConnConfig, err := decryptConnConfigMsg(exchangeMsgReply.ConfigCiphertext, cipher.codec, privateKey, peerPubKey)
 	if err != nil {
 		return nil, fmt.Errorf("could not decrypt peer conn config: %w", err)
 	}
 
 	config.publicKey = pubKey
+	config.cipher = cipher
 	config.compatibility = int(peerConnConfig.Compatibility)
 	config.privateKey = privateKey
 	config.peerPubKey = peerPubKey
 	config.peerPublicIP = peerConnConfig.PublicIP
 	config.peerPorts = int32ToIntSlice(peerConnConfig.Ports)
+	if len(peerConnConfig.PortsIPv6) >= requiredConnCount {
+		config.peerPublicIPv6 = peerConnConfig.PublicIPv6
+		config.peerPortsIPv6 = int32ToIntSlice(peerConnConfig.PortsIPv6)
+	}
+	config.publicIPv6 = localIPv6()
+	config.peerLANIP = peerConnConfig.LanIP
+	config.peerPortsLAN = int32ToIntSlice(peerConnConfig.PortsLAN)
+	if !config.useIPv6() && config.peerPublicIP != "" {
+		// IPv6-only consumers reach IPv4 providers via NAT64 gateway.
+		config.peerPublicIP = synthesizeNAT64(config.peerPublicIP)
+	}
+	config.peerRelays = peerConnConfig.Relays
+	config.preferRelay = peerConnConfig.PreferRelay
+	if config.relay = chooseRelay(config.peerRelays, relayAddresses()); config.relay != "" {
+		if config.relayToken, err = relay.NewToken(); err != nil {
+			return nil, fmt.Errorf("could not generate relay token: %w", err)
+		}
+	}
 	return config, nil
 }
 
-func (m *dialer) ackConfigExchange(config *p2pConnectConfig, ctx context.Context, brokerConn nats.Connection, providerID identity.Identity, serviceType string, consumerID identity.Identity) error {
+func (m *dialer) ackConfigExchange(config *p2pConnectConfig, ctx context.Context, transport communication.Transport, providerID identity.Identity, serviceType string, consumerID identity.Identity) error {
 	trace := config.tracer.StartStage("Consumer P2P exchange ack")
 	defer config.tracer.EndStage(trace)
 
@@ -243,7 +332,23 @@ func (m *dialer) ackConfigExchange(config *p2pConnectConfig, ctx context.Context
 		Ports:         intToInt32Slice(config.publicPorts),
 		Compatibility: compat.Compatibility,
 	}
-	connConfigCiphertext, err := encryptConnConfigMsg(connConfig, config.privateKey, config.peerPubKey)
+	if config.useIPv6() {
+		connConfig.PublicIPv6 = config.publicIPv6
+		connConfig.PortsIPv6 = intToInt32Slice(config.localPorts)
+	}
+	if config.sameNAT() {
+		// LAN address is only revealed to peers sharing the same public IP.
+		config.lanIP = localLANIP(m.ipResolver)
+		if config.lanIP != "" {
+			connConfig.LanIP = config.lanIP
+			connConfig.PortsLAN = intToInt32Slice(config.localPorts)
+		}
+	}
+	if config.relay != "" {
+		connConfig.Relays = []string{config.relay}
+		connConfig.RelayToken = config.relayToken[:]
+	}
+	connConfigCiphertext, err := encryptConnConfigMsg(connConfig, config.cipher.codec, config.privateKey, config.peerPubKey)
 	if err != nil {
 		return fmt.Errorf("could not encrypt config msg: %v", err)
 	}
@@ -261,7 +366,7 @@ func (m *dialer) ackConfigExchange(config *p2pConnectConfig, ctx context.Context
 	//  until provider receives consumer config ( IP, ports ) and starts pinging Consumer first.
 	// This is why we use broker Request method to be sure that Provider processed our given configuration.
 	// To improve speed here investigate options to reduce broker communication round trip.
-	_, err = m.sendSignedMsg(ctx, configExchangeACKSubject(providerID, serviceType), packedMsg, brokerConn)
+	_, err = m.sendSignedMsg(ctx, configExchangeACKSubject(providerID, serviceType), packedMsg, transport)
 
 	if err != nil {
 		return fmt.Errorf("could not send signed msg: %v", err)
@@ -277,10 +382,13 @@ func (m *dialer) prepareLocalPorts(config *p2pConnectConfig) (string, []int, err
 	// Finally send consumer encrypted and signed connect config in ack message.
 	publicIP, err := m.ipResolver.GetPublicIP()
 	if err != nil {
-		return "", nil, fmt.Errorf("could not get public IP: %v", err)
+		if !config.useIPv6() {
+			return "", nil, fmt.Errorf("could not get public IP: %v", err)
+		}
+		log.Warn().Err(err).Msg("Could not get public IPv4, connecting over IPv6")
 	}
 
-	localPorts, err := acquireLocalPorts(m.portPool, len(config.peerPorts))
+	localPorts, err := acquireLocalPorts(m.portPool, len(config.remotePorts()))
 	if err != nil {
 		return publicIP, nil, fmt.Errorf("could not acquire local ports: %v", err)
 	}
@@ -294,12 +402,12 @@ func (m *dialer) dialDirect(ctx context.Context, providerID identity.Identity, c
 
 	log.Debug().Msg("Skipping provider ping")
 
-	ip := defaultInterfaceAddress()
-	conn1, err := net.DialUDP("udp4", &net.UDPAddr{IP: net.ParseIP(ip), Port: config.localPorts[0]}, &net.UDPAddr{IP: net.ParseIP(config.peerIP()), Port: config.peerPorts[0]})
+	ip, network, peerPorts := config.localIP(), udpNetwork(config.peerIP()), config.remotePorts()
+	conn1, err := net.DialUDP(network, &net.UDPAddr{IP: net.ParseIP(ip), Port: config.localPorts[0]}, &net.UDPAddr{IP: net.ParseIP(config.peerIP()), Port: peerPorts[0]})
 	if err != nil {
 		return nil, nil, fmt.Errorf("could not create UDP conn for p2p channel: %w", err)
 	}
-	conn2, err := net.DialUDP("udp4", &net.UDPAddr{IP: net.ParseIP(ip), Port: config.localPorts[1]}, &net.UDPAddr{IP: net.ParseIP(config.peerIP()), Port: config.peerPorts[1]})
+	conn2, err := net.DialUDP(network, &net.UDPAddr{IP: net.ParseIP(ip), Port: config.localPorts[1]}, &net.UDPAddr{IP: net.ParseIP(config.peerIP()), Port: peerPorts[1]})
 	if err != nil {
 		return nil, nil, fmt.Errorf("could not create UDP conn for service: %w", err)
 	}
@@ -315,32 +423,95 @@ func (m *dialer) dialDirect(ctx context.Context, providerID identity.Identity, c
 	return conn1, conn2, err
 }
 
+func (m *dialer) dialIPv6(ctx context.Context, config *p2pConnectConfig) (*net.UDPConn, *net.UDPConn, error) {
+	trace := config.tracer.StartStage("Consumer P2P dial (IPv6)")
+	defer config.tracer.EndStage(trace)
+
+	conns, err := dialIPv6Direct(ctx, config.publicIPv6, config.peerPublicIPv6, config.localPorts, config.peerPortsIPv6)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conns[0], conns[1], nil
+}
+
+func (m *dialer) dialLAN(ctx context.Context, config *p2pConnectConfig) (*net.UDPConn, *net.UDPConn, error) {
+	trace := config.tracer.StartStage("Consumer P2P dial (LAN)")
+	defer config.tracer.EndStage(trace)
+
+	if _, err := firewall.AllowIPAccess(config.peerLANIP); err != nil {
+		return nil, nil, fmt.Errorf("could not add peer LAN IP firewall rule: %w", err)
+	}
+
+	conns, err := dialLANDirect(ctx, config.peerLANIP, config.localPorts, config.peerPortsLAN)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conns[0], conns[1], nil
+}
+
 func (m *dialer) dialPinger(ctx context.Context, providerID identity.Identity, config *p2pConnectConfig) (*net.UDPConn, *net.UDPConn, error) {
 	trace := config.tracer.StartStage("Consumer P2P dial (pinger)")
 	defer config.tracer.EndStage(trace)
 
-	if _, err := firewall.AllowIPAccess(config.peerPublicIP); err != nil {
+	if _, err := firewall.AllowIPAccess(config.peerPublicAddr()); err != nil {
 		return nil, nil, fmt.Errorf("could not add peer IP firewall rule: %w", err)
 	}
 
-	ip := defaultInterfaceAddress()
-	log.Debug().Msgf("Pinging provider %s  using ports %v:%v", providerID.Address, config.localPorts, config.peerPorts)
-	conns, err := m.consumerPinger.PingProviderPeer(ctx, ip, config.peerIP(), config.localPorts, config.peerPorts, consumerInitialTTL, requiredConnCount)
+	log.Debug().Msgf("Pinging provider %s  using ports %v:%v", providerID.Address, config.localPorts, config.remotePorts())
+	conns, err := m.consumerPinger.PingProviderPeer(ctx, config.localIP(), config.peerIP(), config.localPorts, config.remotePorts(), consumerInitialTTL, requiredConnCount)
 	if err != nil {
 		return nil, nil, fmt.Errorf("could not ping peer: %w", err)
 	}
 	return conns[0], conns[1], nil
 }
 
-func (m *dialer) sendSignedMsg(ctx context.Context, subject string, msg []byte, brokerConn nats.Connection) ([]byte, error) {
-	reply, err := brokerConn.RequestWithContext(ctx, subject, msg)
+func (m *dialer) dialRelay(ctx context.Context, config *p2pConnectConfig) (*net.UDPConn, *net.UDPConn, error) {
+	trace := config.tracer.StartStage("Consumer P2P dial (relay)")
+	defer config.tracer.EndStage(trace)
+
+	host, _, err := net.SplitHostPort(config.relay)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid relay address: %w", err)
+	}
+	relayIPs, err := net.LookupIP(host)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not resolve relay: %w", err)
+	}
+	for _, ip := range relayIPs {
+		if err := router.ExcludeIP(ip); err != nil {
+			return nil, nil, fmt.Errorf("failed to exclude relay IP from default routes: %w", err)
+		}
+		if _, err := firewall.AllowIPAccess(ip.String()); err != nil {
+			return nil, nil, fmt.Errorf("could not add relay IP firewall rule: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, relayDialTimeout)
+	defer cancel()
+	conns, err := dialRelay(ctx, config.relay, config.relayToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conns[0], conns[1], nil
+}
+
+// transport prefers peer-to-peer transport if it is enabled and falls back to provider's broker.
+func (m *dialer) transport(broker communication.Transport) communication.Transport {
+	if m.peers == nil {
+		return broker
+	}
+	return communication.NewFallbackTransport(m.peers, broker)
+}
+
+func (m *dialer) sendSignedMsg(ctx context.Context, subject string, msg []byte, transport communication.Transport) ([]byte, error) {
+	reply, err := transport.Request(ctx, communication.NewTransportMessage(subject, nil, msg, nil))
 	if err != nil {
 		return nil, fmt.Errorf("could not send broker request to subject %s: %v", subject, err)
 	}
 	return reply.Data, nil
 }
 
-func (m *dialer) channelHandlersReady(msg *nats_lib.Msg) error {
+func (m *dialer) channelHandlersReady(msg *communication.TransportMessage) error {
 	var handlersReady pb.P2PChannelHandlersReady
 	if err := proto.Unmarshal(msg.Data, &handlersReady); err != nil {
 		return fmt.Errorf("failed to unmarshal handlers ready message: %w", err)
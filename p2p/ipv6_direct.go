@@ -0,0 +1,187 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/router"
+)
+
+const (
+	directProbeTimeout     = 3 * time.Second
+	directProbeInterval    = 100 * time.Millisecond
+	directProbeDoneRepeats = 3
+)
+
+// Probe payloads keep the names of IPv6 path they were introduced for, LAN path uses them as well.
+var (
+	directProbe     = []byte("MYST-V6-PROBE")
+	directProbeAck  = []byte("MYST-V6-ACK")
+	directProbeDone = []byte("MYST-V6-DONE")
+)
+
+// preferIPv6Direct returns true if peers having global IPv6 addresses should connect directly without hole punching.
+func preferIPv6Direct(c *p2pConnectConfig) bool {
+	return c.useIPv6() && config.GetBool(config.FlagP2PIPv6Direct)
+}
+
+// disableIPv6 makes peers fall back to IPv4 connection.
+func (c *p2pConnectConfig) disableIPv6() {
+	c.publicIPv6 = ""
+	c.peerPublicIPv6 = ""
+	c.peerPortsIPv6 = nil
+}
+
+// dialIPv6Direct connects to the peer global IPv6 address skipping NAT hole punching.
+// Both connections are probed in both directions, so that firewalls dropping unsolicited
+// IPv6 traffic make peers fall back to IPv4 instead of ending up with a dead channel.
+func dialIPv6Direct(ctx context.Context, localIP, peerIP string, localPorts, peerPorts []int) ([]*net.UDPConn, error) {
+	conns, err := dialProbed(ctx, "udp6", localIP, peerIP, localPorts, peerPorts)
+	if err != nil {
+		return nil, fmt.Errorf("IPv6 path: %w", err)
+	}
+	return conns, nil
+}
+
+// dialProbed connects to the peer address without NAT hole punching and makes sure the path works both ways.
+func dialProbed(ctx context.Context, network, localIP, peerIP string, localPorts, peerPorts []int) ([]*net.UDPConn, error) {
+	if len(localPorts) < requiredConnCount || len(peerPorts) < requiredConnCount {
+		return nil, errors.New("not enough ports for direct connections")
+	}
+
+	conns := make([]*net.UDPConn, 0, requiredConnCount)
+	closeAll := func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+	for i := 0; i < requiredConnCount; i++ {
+		conn, err := net.DialUDP(network, &net.UDPAddr{IP: net.ParseIP(localIP), Port: localPorts[i]}, &net.UDPAddr{IP: net.ParseIP(peerIP), Port: peerPorts[i]})
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("could not create UDP conn: %w", err)
+		}
+		conns = append(conns, conn)
+
+		if err := router.ProtectUDPConn(conn); err != nil {
+			closeAll()
+			return nil, fmt.Errorf("failed to protect udp connection: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, directProbeTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(conns))
+	for _, conn := range conns {
+		wg.Add(1)
+		go func(conn *net.UDPConn) {
+			defer wg.Done()
+			errs <- probeConn(ctx, conn)
+		}(conn)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("probe failed: %w", err)
+		}
+	}
+	return conns, nil
+}
+
+// probeConn sends probes until the peer both acknowledges one and sends its own, then keeps
+// confirming it until the peer confirms too, so that a lost acknowledgement does not leave
+// one peer on the direct path while the other one falls back.
+func probeConn(ctx context.Context, conn *net.UDPConn) error {
+	var mu sync.Mutex
+	var acked, probed, confirmed bool
+	ready := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return acked && probed
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			if ready() {
+				conn.Write(directProbeDone)
+			} else {
+				conn.Write(directProbe)
+			}
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-time.After(directProbeInterval):
+			}
+		}
+	}()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetReadDeadline(deadline)
+		defer conn.SetReadDeadline(time.Time{})
+	}
+
+	buf := make([]byte, 64)
+	for !ready() || !confirmed {
+		n, err := conn.Read(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return err
+			}
+			// Port unreachable errors are reported until peer opens its socket.
+			continue
+		}
+
+		mu.Lock()
+		switch {
+		case bytes.Equal(buf[:n], directProbe):
+			probed = true
+			conn.Write(directProbeAck)
+		case bytes.Equal(buf[:n], directProbeAck):
+			acked = true
+		case bytes.Equal(buf[:n], directProbeDone):
+			// Peer is done only after hearing our probe and sending its own.
+			acked, probed, confirmed = true, true, true
+			conn.Write(directProbeDone)
+		}
+		mu.Unlock()
+	}
+
+	// Peer stops probing once it gets our confirmation, repeat it in case some are lost.
+	for i := 0; i < directProbeDoneRepeats; i++ {
+		conn.Write(directProbeDone)
+	}
+	return nil
+}
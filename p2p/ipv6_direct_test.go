@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialIPv6Direct(t *testing.T) {
+	if conn, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback}); err != nil {
+		t.Skip("IPv6 loopback is not available")
+	} else {
+		conn.Close()
+	}
+
+	providerPorts := []int{51301, 51302}
+	consumerPorts := []int{51303, 51304}
+
+	var wg sync.WaitGroup
+	var providerConns []*net.UDPConn
+	var providerErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		providerConns, providerErr = dialIPv6Direct(context.Background(), "::1", "::1", providerPorts, consumerPorts)
+	}()
+
+	consumerConns, err := dialIPv6Direct(context.Background(), "::1", "::1", consumerPorts, providerPorts)
+	wg.Wait()
+	require.NoError(t, err)
+	require.NoError(t, providerErr)
+	defer func() {
+		for _, conn := range append(providerConns, consumerConns...) {
+			conn.Close()
+		}
+	}()
+
+	assert.Len(t, consumerConns, requiredConnCount)
+	assert.Len(t, providerConns, requiredConnCount)
+}
+
+func TestDialIPv6Direct_NoPeer(t *testing.T) {
+	if conn, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback}); err != nil {
+		t.Skip("IPv6 loopback is not available")
+	} else {
+		conn.Close()
+	}
+
+	conns, err := dialIPv6Direct(context.Background(), "::1", "::1", []int{51305, 51306}, []int{51307, 51308})
+	assert.Error(t, err)
+	assert.Nil(t, conns)
+}
+
+func TestProbeConn_WaitsForPeerConfirmation(t *testing.T) {
+	peer, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer peer.Close()
+
+	conn, err := net.DialUDP("udp4", nil, peer.LocalAddr().(*net.UDPAddr))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Peer acknowledges our probe and sends its own, but its confirmation gets lost.
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	go answerProbes(peer, false)
+	assert.Error(t, probeConn(ctx, conn))
+
+	ctx, cancel = context.WithTimeout(context.Background(), directProbeTimeout)
+	defer cancel()
+	go answerProbes(peer, true)
+	assert.NoError(t, probeConn(ctx, conn))
+}
+
+func answerProbes(peer *net.UDPConn, confirm bool) {
+	buf := make([]byte, 64)
+	peer.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	defer peer.SetReadDeadline(time.Time{})
+	for {
+		n, addr, err := peer.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		switch string(buf[:n]) {
+		case string(directProbe):
+			peer.WriteToUDP(directProbeAck, addr)
+			peer.WriteToUDP(directProbe, addr)
+		case string(directProbeDone):
+			if confirm {
+				peer.WriteToUDP(directProbeDone, addr)
+				return
+			}
+		}
+	}
+}
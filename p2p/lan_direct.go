@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/ip"
+)
+
+// sameNAT returns true if peers share public IPv4 address and are likely behind the same router.
+func (c *p2pConnectConfig) sameNAT() bool {
+	return !c.useIPv6() && c.publicIP != "" && c.publicIP == c.peerPublicIP
+}
+
+// useLAN returns true if peers behind the same NAT exchanged their LAN addresses.
+func (c *p2pConnectConfig) useLAN() bool {
+	return c.sameNAT() && c.lanIP != "" && c.peerLANIP != "" && len(c.peerPortsLAN) >= requiredConnCount
+}
+
+// preferLANDirect returns true if peers behind the same NAT should connect over LAN avoiding hairpinning.
+func preferLANDirect(c *p2pConnectConfig) bool {
+	return c.useLAN() && config.GetBool(config.FlagP2PLANDirect)
+}
+
+// disableLAN makes peers fall back to their public addresses. Peer LAN address is kept,
+// so that peers are not mistaken for ones predating LAN address exchange.
+func (c *p2pConnectConfig) disableLAN() {
+	c.peerPortsLAN = nil
+}
+
+// localLANIP returns private address of the outbound interface or empty string if node has public address.
+func localLANIP(resolver ip.Resolver) string {
+	if !config.GetBool(config.FlagP2PLANDirect) {
+		return ""
+	}
+
+	addr, err := resolver.GetOutboundIP()
+	if err != nil {
+		log.Debug().Err(err).Msg("No outbound IP for LAN p2p connections")
+		return ""
+	}
+	if ip := net.ParseIP(addr); ip == nil || ip.To4() == nil || !ip.IsPrivate() {
+		return ""
+	}
+	return addr
+}
+
+// dialLANDirect connects to the peer LAN address skipping NAT hole punching.
+func dialLANDirect(ctx context.Context, peerIP string, localPorts, peerPorts []int) ([]*net.UDPConn, error) {
+	conns, err := dialProbed(ctx, "udp4", "", peerIP, localPorts, peerPorts)
+	if err != nil {
+		return nil, fmt.Errorf("LAN path: %w", err)
+	}
+	return conns, nil
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestP2PConnectConfig_LANAddressSelection(t *testing.T) {
+	config := &p2pConnectConfig{
+		publicIP:     "1.2.3.4",
+		peerPublicIP: "1.2.3.4",
+		peerPorts:    []int{1000, 1001},
+		lanIP:        "192.168.1.10",
+		peerLANIP:    "192.168.1.20",
+		peerPortsLAN: []int{2000, 2001},
+	}
+	assert.True(t, config.useLAN())
+	assert.Equal(t, "192.168.1.20", config.peerIP())
+	assert.Equal(t, []int{2000, 2001}, config.remotePorts())
+
+	config.disableLAN()
+	assert.False(t, config.useLAN())
+	assert.Equal(t, "1.2.3.4", config.peerIP())
+	assert.Equal(t, []int{1000, 1001}, config.remotePorts())
+
+	legacy := &p2pConnectConfig{publicIP: "1.2.3.4", peerPublicIP: "1.2.3.4", lanIP: "192.168.1.10"}
+	assert.False(t, legacy.useLAN())
+	assert.Equal(t, "127.0.0.1", legacy.peerIP())
+
+	differentNAT := &p2pConnectConfig{publicIP: "1.2.3.4", peerPublicIP: "5.6.7.8", lanIP: "192.168.1.10", peerLANIP: "192.168.1.20", peerPortsLAN: []int{2000, 2001}}
+	assert.False(t, differentNAT.useLAN())
+	assert.Equal(t, "5.6.7.8", differentNAT.peerIP())
+}
+
+func TestDialLANDirect(t *testing.T) {
+	providerPorts := []int{51311, 51312}
+	consumerPorts := []int{51313, 51314}
+
+	var wg sync.WaitGroup
+	var providerConns []*net.UDPConn
+	var providerErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		providerConns, providerErr = dialLANDirect(context.Background(), "127.0.0.1", providerPorts, consumerPorts)
+	}()
+
+	consumerConns, err := dialLANDirect(context.Background(), "127.0.0.1", consumerPorts, providerPorts)
+	wg.Wait()
+	require.NoError(t, err)
+	require.NoError(t, providerErr)
+	defer func() {
+		for _, conn := range append(providerConns, consumerConns...) {
+			conn.Close()
+		}
+	}()
+
+	assert.Len(t, consumerConns, requiredConnCount)
+	assert.Len(t, providerConns, requiredConnCount)
+}
@@ -24,18 +24,20 @@ import (
 	"sync"
 	"time"
 
-	nats_lib "github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/mysteriumnetwork/node/communication"
 	"github.com/mysteriumnetwork/node/communication/nats"
 	"github.com/mysteriumnetwork/node/core/ip"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/nat/mapping"
 	"github.com/mysteriumnetwork/node/nat/traversal"
 	"github.com/mysteriumnetwork/node/p2p/compat"
 	"github.com/mysteriumnetwork/node/p2p/nat"
+	"github.com/mysteriumnetwork/node/p2p/relay"
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/node/trace"
 )
@@ -51,15 +53,25 @@ type Listener interface {
 	GetContact() market.Contact
 }
 
+// cgnatStatus reports whether provider is behind carrier-grade NAT.
+type cgnatStatus interface {
+	BehindCGNAT() bool
+}
+
 // NewListener creates new p2p communication listener which is used on provider side.
-func NewListener(brokerConn nats.Connection, signer identity.SignerFactory, verifier identity.Verifier, ipResolver ip.Resolver, eventBus eventbus.EventBus) Listener {
+// Consumers are accepted through the broker and through peer-to-peer transport if it is given.
+func NewListener(brokerConn nats.Connection, peers communication.Transport, signer identity.SignerFactory, verifier identity.Verifier, ipResolver ip.Resolver, eventBus eventbus.EventBus, portMapper mapping.PortMapper, cgnat cgnatStatus) Listener {
 	return &listener{
 		brokerConn:     brokerConn,
+		broker:         nats.NewTransport(brokerConn),
+		peers:          peers,
 		pendingConfigs: map[PublicKey]p2pConnectConfig{},
 		ipResolver:     ipResolver,
 		signer:         signer,
 		verifier:       verifier,
 		eventBus:       eventBus,
+		portMapper:     portMapper,
+		cgnat:          cgnat,
 	}
 }
 
@@ -67,9 +79,13 @@ func NewListener(brokerConn nats.Connection, signer identity.SignerFactory, veri
 type listener struct {
 	eventBus   eventbus.EventBus
 	brokerConn nats.Connection
+	broker     communication.Transport
+	peers      communication.Transport
 	signer     identity.SignerFactory
 	verifier   identity.Verifier
 	ipResolver ip.Resolver
+	portMapper mapping.PortMapper
+	cgnat      cgnatStatus
 
 	// Keys holds pendingConfigs temporary configs for provider side since it
 	// need to handle key exchange in two steps.
@@ -80,8 +96,14 @@ type listener struct {
 type p2pConnectConfig struct {
 	publicIP         string
 	peerPublicIP     string
+	publicIPv6       string
+	peerPublicIPv6   string
 	compatibility    int
 	peerPorts        []int
+	peerPortsIPv6    []int
+	lanIP            string
+	peerLANIP        string
+	peerPortsLAN     []int
 	localPorts       []int
 	publicPorts      []int
 	publicKey        PublicKey
@@ -91,16 +113,59 @@ type p2pConnectConfig struct {
 	upnpPortsRelease func()
 	start            nat.StartPorts
 	peerID           identity.Identity
+	peerRelays       []string
+	relay            string
+	relayToken       relay.Token
+	preferRelay      bool
+	cipher           negotiatedCipher
+}
+
+// useIPv6 returns true if both peers have global IPv6 addresses and can connect without NAT traversal.
+func (c *p2pConnectConfig) useIPv6() bool {
+	return c.publicIPv6 != "" && c.peerPublicIPv6 != ""
 }
 
 func (c *p2pConnectConfig) peerIP() string {
-	if c.publicIP == c.peerPublicIP {
-		// Assume that both peers are on the same network.
+	if c.useIPv6() {
+		return c.peerPublicIPv6
+	}
+	if c.useLAN() {
+		return c.peerLANIP
+	}
+	if c.sameNAT() && c.peerLANIP == "" {
+		// Peer predates LAN address exchange, assume that both peers are on the same host.
 		return "127.0.0.1"
 	}
 	return c.peerPublicIP
 }
 
+// localIP returns local address to bind p2p connections to.
+func (c *p2pConnectConfig) localIP() string {
+	if c.useIPv6() {
+		return c.publicIPv6
+	}
+	return defaultInterfaceAddress()
+}
+
+// peerPublicAddr returns public address of the peer in the address family used for connection.
+func (c *p2pConnectConfig) peerPublicAddr() string {
+	if c.useIPv6() {
+		return c.peerPublicIPv6
+	}
+	return c.peerPublicIP
+}
+
+// remotePorts returns peer ports in the address family used for connection.
+func (c *p2pConnectConfig) remotePorts() []int {
+	if c.useIPv6() {
+		return c.peerPortsIPv6
+	}
+	if c.useLAN() {
+		return c.peerPortsLAN
+	}
+	return c.peerPorts
+}
+
 func (m *listener) GetContact() market.Contact {
 	return market.Contact{
 		Type:       ContactTypeV1,
@@ -111,12 +176,7 @@ func (m *listener) GetContact() market.Contact {
 // Listen listens for incoming peer connections to establish new p2p channels. Establishes p2p channel and passes it
 // to channelHandlers.
 func (m *listener) Listen(providerID identity.Identity, serviceType string, channelHandlers func(ch Channel)) (func(), error) {
-	configSignedSubject, err := nats.SignedSubject(m.signer(providerID), configExchangeSubject(providerID, serviceType))
-	if err != nil {
-		return func() {}, fmt.Errorf("cannot sign config topic: %w", err)
-	}
-
-	configSub, err := m.brokerConn.Subscribe(configSignedSubject, func(msg *nats_lib.Msg) {
+	configSub, err := m.subscribe(providerID, configExchangeSubject(providerID, serviceType), func(msg *communication.TransportMessage) {
 		if err := m.providerStartConfigExchange(providerID, msg); err != nil {
 			log.Err(err).Msg("Could not handle initial exchange")
 			return
@@ -126,12 +186,7 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 		return func() {}, fmt.Errorf("could not get subscribe to config exchange topic: %w", err)
 	}
 
-	ackSignedSubject, err := nats.SignedSubject(m.signer(providerID), configExchangeACKSubject(providerID, serviceType))
-	if err != nil {
-		return func() {}, fmt.Errorf("cannot sign ack topic: %w", err)
-	}
-
-	ackSub, err := m.brokerConn.Subscribe(ackSignedSubject, func(msg *nats_lib.Msg) {
+	ackSub, err := m.subscribe(providerID, configExchangeACKSubject(providerID, serviceType), func(msg *communication.TransportMessage) {
 		config, err := m.providerAckConfigExchange(msg)
 		if err != nil {
 			log.Err(err).Msg("Could not handle exchange ack")
@@ -142,7 +197,7 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 		// Send ack in separate goroutine and start pinging.
 		// It is important that provider starts sending pings first otherwise
 		// providers router can think that consumer is sending DDoS packets.
-		go func(reply string) {
+		go func() {
 			// race condition still happens when consumer starts to ping until provider did not manage to complete required number of pings
 			// this might be provider / consumer performance dependent
 			// make sleep time dependent on pinger interval and wait for 2 ping iterations
@@ -151,18 +206,56 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 			log.Debug().Msgf("Delaying pings from consumer for %v ms", dur)
 			time.Sleep(time.Duration(dur) * time.Millisecond)
 
-			if err := m.brokerConn.Publish(reply, []byte("OK")); err != nil {
+			if err := msg.Respond([]byte("OK")); err != nil {
 				log.Err(err).Msg("Could not publish exchange ack")
 			}
 			config.tracer.EndStage(trace)
-		}(msg.Reply)
+		}()
 
 		var conn1, conn2 *net.UDPConn
-		if config.start != nil {
+		if preferIPv6Direct(config) {
+			traceDial := config.tracer.StartStage("Provider P2P dial (IPv6)")
+			conns, err := dialIPv6Direct(context.Background(), "", config.peerPublicIPv6, config.localPorts, config.peerPortsIPv6)
+			if err != nil {
+				log.Warn().Err(err).Msg("Could not connect to consumer over IPv6, falling back to IPv4")
+				config.disableIPv6()
+			} else {
+				conn1, conn2 = conns[0], conns[1]
+			}
+			config.tracer.EndStage(traceDial)
+		}
+
+		if conn1 == nil && preferLANDirect(config) {
+			traceDial := config.tracer.StartStage("Provider P2P dial (LAN)")
+			conns, err := dialLANDirect(context.Background(), config.peerLANIP, config.localPorts, config.peerPortsLAN)
+			if err != nil {
+				log.Warn().Err(err).Msg("Could not connect to consumer over LAN, falling back to public address")
+				config.disableLAN()
+			} else {
+				conn1, conn2 = conns[0], conns[1]
+			}
+			config.tracer.EndStage(traceDial)
+		}
+
+		if conn1 != nil {
+			log.Debug().Msg("Connected to consumer directly, skipping NAT traversal")
+		} else if config.preferRelay && config.relay != "" {
+			log.Debug().Msgf("Provider is behind CGNAT, connecting via relay %s", config.relay)
+			conns, err := m.providerDialRelay(providerID, config)
+			if err != nil {
+				log.Err(err).Msg("Could not connect to relay")
+				return
+			}
+			conn1, conn2 = conns[0], conns[1]
+		} else if config.start != nil {
 			traceDial := config.tracer.StartStage("Provider P2P dial (preparation)")
-			log.Debug().Msgf("Pinging consumer using ports %v:%v initial ttl: %v", config.localPorts, config.peerPorts, 1)
+			log.Debug().Msgf("Pinging consumer using ports %v:%v initial ttl: %v", config.localPorts, config.remotePorts(), 1)
 
-			conns, err := config.start(context.Background(), config.peerIP(), config.peerPorts, config.localPorts)
+			conns, err := config.start(context.Background(), config.peerIP(), config.remotePorts(), config.localPorts)
+			if err != nil && config.relay != "" {
+				log.Warn().Err(err).Msgf("Could not ping peer, falling back to relay %s", config.relay)
+				conns, err = m.providerDialRelay(providerID, config)
+			}
 			if err != nil {
 				log.Err(err).Msg("Could not ping peer")
 				return
@@ -179,12 +272,13 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 		} else {
 			traceDial := config.tracer.StartStage("Provider P2P dial (direct)")
 			log.Debug().Msg("Skipping consumer ping")
-			conn1, err = net.DialUDP("udp4", &net.UDPAddr{Port: config.localPorts[0]}, &net.UDPAddr{IP: net.ParseIP(config.peerIP()), Port: config.peerPorts[0]})
+			network, peerPorts := udpNetwork(config.peerIP()), config.remotePorts()
+			conn1, err = net.DialUDP(network, &net.UDPAddr{Port: config.localPorts[0]}, &net.UDPAddr{IP: net.ParseIP(config.peerIP()), Port: peerPorts[0]})
 			if err != nil {
 				log.Err(err).Msg("Could not create UDP conn for p2p channel")
 				return
 			}
-			conn2, err = net.DialUDP("udp4", &net.UDPAddr{Port: config.localPorts[1]}, &net.UDPAddr{IP: net.ParseIP(config.peerIP()), Port: config.peerPorts[1]})
+			conn2, err = net.DialUDP(network, &net.UDPAddr{Port: config.localPorts[1]}, &net.UDPAddr{IP: net.ParseIP(config.peerIP()), Port: peerPorts[1]})
 			if err != nil {
 				log.Err(err).Msg("Could not create UDP conn for service")
 				return
@@ -193,7 +287,7 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 		}
 
 		traceAck := config.tracer.StartStage("Provider P2P dial ack")
-		channel, err := newChannel(conn1, config.privateKey, config.peerPubKey, config.compatibility)
+		channel, err := newChannel(conn1, config.privateKey, config.peerPubKey, config.compatibility, config.cipher.messageVersion)
 		if err != nil {
 			log.Err(err).Msg("Could not create channel")
 			return
@@ -222,6 +316,12 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 		return func() {}, fmt.Errorf("could not get subscribe to config exchange acknowledge topic: %w", err)
 	}
 
+	pingSub, err := m.listenPing(providerID, serviceType)
+	if err != nil {
+		// Ping is optional for consumers, channel can still be established without it.
+		log.Warn().Err(err).Msg("Could not subscribe to ping topic")
+	}
+
 	return func() {
 		if err := configSub.Unsubscribe(); err != nil {
 			log.Err(err).Msg("Failed to unsubscribe from config exchange topic")
@@ -229,14 +329,19 @@ func (m *listener) Listen(providerID identity.Identity, serviceType string, chan
 		if err := ackSub.Unsubscribe(); err != nil {
 			log.Err(err).Msg("Failed to unsubscribe from config exchange acknowledge topic")
 		}
+		if pingSub != nil {
+			if err := pingSub.Unsubscribe(); err != nil {
+				log.Err(err).Msg("Failed to unsubscribe from ping topic")
+			}
+		}
 	}, nil
 }
 
-func (m *listener) providerStartConfigExchange(providerID identity.Identity, msg *nats_lib.Msg) error {
+func (m *listener) providerStartConfigExchange(providerID identity.Identity, msg *communication.TransportMessage) error {
 	tracer := trace.NewTracer("Provider whole Connect")
 
-	trace := tracer.StartStage("Provider P2P exchange")
-	defer tracer.EndStage(trace)
+	traceExchange := tracer.StartStage("Provider P2P exchange")
+	defer tracer.EndStage(traceExchange)
 
 	pubKey, privateKey, err := GenerateKey()
 	if err != nil {
@@ -252,19 +357,31 @@ func (m *listener) providerStartConfigExchange(providerID identity.Identity, msg
 	if err := proto.Unmarshal(signedMsg.Data, &peerExchangeMsg); err != nil {
 		return err
 	}
+	if parent, err := trace.ParseTraceparent(peerExchangeMsg.Traceparent); err == nil {
+		tracer.SetParent(parent)
+	}
+	tracer.SetAttribute("consumer.id", peerID.Address)
 	peerPubKey, err := DecodePublicKey(peerExchangeMsg.PublicKey)
 	if err != nil {
 		return err
 	}
 	log.Debug().Msgf("Received consumer public key %s", peerPubKey.Hex())
+	cipherAnswer, cipher, err := acceptCipher(m.signer(providerID), peerID, peerExchangeMsg.CipherNegotiation)
+	if err != nil {
+		return err
+	}
 
-	publicIP, localPorts, portsRelease, start, err := m.prepareLocalPorts(providerID.Address, tracer)
+	publicIPv6 := localIPv6()
+	lanIP := localLANIP(m.ipResolver)
+	publicIP, localPorts, portsRelease, start, err := m.prepareLocalPorts(providerID.Address, publicIPv6 != "", tracer)
 	if err != nil {
 		return fmt.Errorf("could not prepare ports: %w", err)
 	}
 
 	p2pConnConfig := p2pConnectConfig{
 		publicIP:         publicIP,
+		publicIPv6:       publicIPv6,
+		lanIP:            lanIP,
 		localPorts:       localPorts,
 		publicPorts:      stunPorts(providerID, m.eventBus, localPorts...),
 		publicKey:        pubKey,
@@ -276,6 +393,8 @@ func (m *listener) providerStartConfigExchange(providerID identity.Identity, msg
 		peerPorts:        nil,
 		start:            start,
 		peerID:           peerID,
+		preferRelay:      m.preferRelay(),
+		cipher:           cipher,
 	}
 	m.setPendingConfig(p2pConnConfig)
 
@@ -283,21 +402,32 @@ func (m *listener) providerStartConfigExchange(providerID identity.Identity, msg
 		PublicIP:      publicIP,
 		Ports:         intToInt32Slice(p2pConnConfig.publicPorts),
 		Compatibility: compat.Compatibility,
+		Relays:        relayAddresses(),
+		PreferRelay:   p2pConnConfig.preferRelay,
 	}
-	configCiphertext, err := encryptConnConfigMsg(&config, privateKey, peerPubKey)
+	if publicIPv6 != "" {
+		config.PublicIPv6 = publicIPv6
+		config.PortsIPv6 = intToInt32Slice(localPorts)
+	}
+	if lanIP != "" {
+		config.LanIP = lanIP
+		config.PortsLAN = intToInt32Slice(localPorts)
+	}
+	configCiphertext, err := encryptConnConfigMsg(&config, cipher.codec, privateKey, peerPubKey)
 	if err != nil {
 		return fmt.Errorf("could not encrypt config msg: %w", err)
 	}
 	exchangeMsg := pb.P2PConfigExchangeMsg{
-		PublicKey:        pubKey.Hex(),
-		ConfigCiphertext: configCiphertext,
+		PublicKey:         pubKey.Hex(),
+		ConfigCiphertext:  configCiphertext,
+		CipherNegotiation: cipherAnswer,
 	}
 	log.Debug().Msgf("Sending reply with public key %s and encrypted config to consumer", exchangeMsg.PublicKey)
 	packedMsg, err := packSignedMsg(m.signer, providerID, &exchangeMsg)
 	if err != nil {
 		return fmt.Errorf("could not pack signed message: %w", err)
 	}
-	err = m.brokerConn.Publish(msg.Reply, packedMsg)
+	err = msg.Respond(packedMsg)
 	if err != nil {
 		return fmt.Errorf("could not publish message via broker: %w", err)
 	}
@@ -307,17 +437,20 @@ func (m *listener) providerStartConfigExchange(providerID identity.Identity, msg
 // prepareLocalPorts acquires ports for p2p connections. It tries to acquire only
 // required ports count for actual p2p and service connections and fallback to
 // acquiring extra ports for nat pinger if provider is behind nat, port mapping failed
-// and no manual port forwarding is enabled.
-func (m *listener) prepareLocalPorts(id string, tracer *trace.Tracer) (string, []int, func(), nat.StartPorts, error) {
+// and no manual port forwarding is enabled. Public IPv4 is optional for providers having global IPv6 address.
+func (m *listener) prepareLocalPorts(id string, hasIPv6 bool, tracer *trace.Tracer) (string, []int, func(), nat.StartPorts, error) {
 	trace := tracer.StartStage("Provider P2P exchange (ports)")
 	defer tracer.EndStage(trace)
 
 	publicIP, err := m.ipResolver.GetPublicIP()
 	if err != nil {
-		return "", nil, nil, nil, fmt.Errorf("could not get public IP: %w", err)
+		if !hasIPv6 {
+			return "", nil, nil, nil, fmt.Errorf("could not get public IP: %w", err)
+		}
+		log.Warn().Err(err).Msg("Could not get public IPv4, accepting IPv6 connections only")
 	}
 
-	for _, p := range nat.OrderedPortProviders() {
+	for _, p := range nat.OrderedPortProviders(m.portMapper) {
 		ports, release, start, err := p.Provider.PreparePorts()
 		if err == nil {
 			m.eventBus.Publish(nat.AppTopicNATTraversalMethod, nat.NATTraversalMethod{
@@ -338,7 +471,7 @@ func (m *listener) prepareLocalPorts(id string, tracer *trace.Tracer) (string, [
 	return "", nil, nil, nil, fmt.Errorf("failed to prepare local ports")
 }
 
-func (m *listener) providerAckConfigExchange(msg *nats_lib.Msg) (*p2pConnectConfig, error) {
+func (m *listener) providerAckConfigExchange(msg *communication.TransportMessage) (*p2pConnectConfig, error) {
 	signedMsg, peerID, err := unpackSignedMsg(m.verifier, msg.Data)
 	if err != nil {
 		return nil, fmt.Errorf("could not unpack signed msg: %w", err)
@@ -361,14 +494,36 @@ func (m *listener) providerAckConfigExchange(msg *nats_lib.Msg) (*p2pConnectConf
 		return nil, fmt.Errorf("acknowledged config signed by unexpected identity: %s", peerID.ToCommonAddress())
 	}
 
-	peerConfig, err := decryptConnConfigMsg(peerExchangeMsg.ConfigCiphertext, config.privateKey, peerPubKey)
+	peerConfig, err := decryptConnConfigMsg(peerExchangeMsg.ConfigCiphertext, config.cipher.codec, config.privateKey, peerPubKey)
 	if err != nil {
 		return nil, fmt.Errorf("could not decrypt peer conn config: %w", err)
 	}
 
+	var relayAddr string
+	token, hasToken := relay.TokenFromBytes(peerConfig.RelayToken)
+	if len(peerConfig.Relays) > 0 && hasToken {
+		// Never follow consumer to relays which were not offered.
+		if containsRelay(relayAddresses(), peerConfig.Relays[0]) {
+			relayAddr = peerConfig.Relays[0]
+		} else {
+			log.Warn().Msgf("Consumer chose relay %s which was not offered, ignoring it", peerConfig.Relays[0])
+		}
+	}
+
+	peerPublicIPv6 := peerConfig.PublicIPv6
+	if len(peerConfig.PortsIPv6) < requiredConnCount {
+		peerPublicIPv6 = ""
+	}
+
 	return &p2pConnectConfig{
 		peerPublicIP:     peerConfig.PublicIP,
 		peerPorts:        int32ToIntSlice(peerConfig.Ports),
+		publicIPv6:       config.publicIPv6,
+		peerPublicIPv6:   peerPublicIPv6,
+		peerPortsIPv6:    int32ToIntSlice(peerConfig.PortsIPv6),
+		lanIP:            config.lanIP,
+		peerLANIP:        peerConfig.LanIP,
+		peerPortsLAN:     int32ToIntSlice(peerConfig.PortsLAN),
 		compatibility:    int(peerConfig.Compatibility),
 		localPorts:       config.localPorts,
 		publicKey:        config.publicKey,
@@ -379,9 +534,34 @@ func (m *listener) providerAckConfigExchange(msg *nats_lib.Msg) (*p2pConnectConf
 		upnpPortsRelease: config.upnpPortsRelease,
 		start:            config.start,
 		peerID:           config.peerID,
+		relay:            relayAddr,
+		relayToken:       token,
+		preferRelay:      config.preferRelay,
+		cipher:           config.cipher,
 	}, nil
 }
 
+// preferRelay returns true if consumers should skip hole punching and connect via relay right away.
+func (m *listener) preferRelay() bool {
+	return m.cgnat != nil && m.cgnat.BehindCGNAT() && len(relayAddresses()) > 0
+}
+
+func (m *listener) providerDialRelay(providerID identity.Identity, config *p2pConnectConfig) ([]*net.UDPConn, error) {
+	trace := config.tracer.StartStage("Provider P2P dial (relay)")
+	defer config.tracer.EndStage(trace)
+
+	ctx, cancel := context.WithTimeout(context.Background(), relayDialTimeout)
+	defer cancel()
+
+	conns, err := dialRelay(ctx, config.relay, config.relayToken)
+	m.eventBus.Publish(nat.AppTopicNATTraversalMethod, nat.NATTraversalMethod{
+		Identity: providerID.Address,
+		Method:   nat.MethodRelay,
+		Success:  err == nil,
+	})
+	return conns, err
+}
+
 func (m *listener) providerChannelHandlersReady(providerID identity.Identity, serviceType string) error {
 	handlersReadyMsg := pb.P2PChannelHandlersReady{Value: "HANDLERS READY"}
 
@@ -390,13 +570,61 @@ func (m *listener) providerChannelHandlersReady(providerID identity.Identity, se
 		return fmt.Errorf("could not marshal exchange msg: %w", err)
 	}
 
-	signedSubject, err := nats.SignedSubject(m.signer(providerID), channelHandlersReadySubject(providerID, serviceType))
+	log.Debug().Msgf("Sending handlers ready message")
+	return m.publish(providerID, channelHandlersReadySubject(providerID, serviceType), message)
+}
+
+// subscribe receives messages of the subject from the broker, which accepts only subjects signed by provider,
+// and from peer-to-peer transport if it is enabled.
+func (m *listener) subscribe(providerID identity.Identity, subject string, handler communication.TransportHandler) (communication.TransportSubscription, error) {
+	signedSubject, err := nats.SignedSubject(m.signer(providerID), subject)
 	if err != nil {
-		return fmt.Errorf("unable to sign p2p-channel-handlers-ready subject: %w", err)
+		return nil, fmt.Errorf("cannot sign subject %s: %w", subject, err)
 	}
 
-	log.Debug().Msgf("Sending handlers ready message")
-	return m.brokerConn.Publish(signedSubject, message)
+	brokerSub, err := m.broker.Subscribe(signedSubject, handler)
+	if err != nil {
+		return nil, err
+	}
+	if m.peers == nil {
+		return brokerSub, nil
+	}
+
+	peerSub, err := m.peers.Subscribe(subject, handler)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not subscribe to %s on peer-to-peer transport", subject)
+		return brokerSub, nil
+	}
+	return subscriptions{brokerSub, peerSub}, nil
+}
+
+// publish sends the message through the broker and through peer-to-peer transport if it is enabled.
+func (m *listener) publish(providerID identity.Identity, subject string, data []byte) error {
+	signedSubject, err := nats.SignedSubject(m.signer(providerID), subject)
+	if err != nil {
+		return fmt.Errorf("cannot sign subject %s: %w", subject, err)
+	}
+
+	err = m.broker.Publish(signedSubject, data)
+	if m.peers != nil {
+		if peerErr := m.peers.Publish(subject, data); peerErr != nil {
+			log.Debug().Err(peerErr).Msgf("Could not publish %s on peer-to-peer transport", subject)
+		} else {
+			err = nil
+		}
+	}
+	return err
+}
+
+type subscriptions []communication.TransportSubscription
+
+func (s subscriptions) Unsubscribe() (err error) {
+	for _, sub := range s {
+		if unsubErr := sub.Unsubscribe(); unsubErr != nil {
+			err = unsubErr
+		}
+	}
+	return err
 }
 
 func (m *listener) pendingConfig(peerPubKey PublicKey) (p2pConnectConfig, bool) {
@@ -34,6 +34,10 @@ const (
 	TopicSessionStatus = "p2p-session-connectivity-status"
 	// TopicSessionDestroy is a session destroy endpoint for p2p communication.
 	TopicSessionDestroy = "p2p-session-destroy"
+	// TopicSessionNotice is a provider notice endpoint for p2p communication.
+	TopicSessionNotice = "p2p-session-notice"
+	// TopicSessionEndpoint is a provider endpoint change notification for p2p communication.
+	TopicSessionEndpoint = "p2p-session-endpoint"
 
 	// TopicPaymentMessage is a payment messages endpoint for p2p communication.
 	TopicPaymentMessage = "p2p-payment-message"
@@ -21,6 +21,9 @@ const (
 	// AppTopicNATTraversalMethod represent NAT traversal method topic.
 	AppTopicNATTraversalMethod = "NAT-traversal-method"
 
+	// MethodRelay is a traversal method name reported when traffic falls back to a relay.
+	MethodRelay = "relay"
+
 	requiredConnCount = 2
 	pingMaxPorts      = 20
 )
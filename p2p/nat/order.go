@@ -23,6 +23,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/nat/mapping"
 )
 
 // NamedPortProvider contains information of the NAT traversal method.
@@ -36,14 +37,14 @@ type PortProvider interface {
 	PreparePorts() (ports []int, release func(), start StartPorts, err error)
 }
 
-var traversalOptions map[string]func() PortProvider = map[string]func() PortProvider{
-	"manual":       NewManualPortProvider,
-	"upnp":         NewUPnPPortProvider,
-	"holepunching": NewNATHolePunchingPortProvider,
-}
-
 // OrderedPortProviders returns a ordered list of the port providers.
-func OrderedPortProviders() (list []NamedPortProvider) {
+func OrderedPortProviders(portMapper mapping.PortMapper) (list []NamedPortProvider) {
+	traversalOptions := map[string]func() PortProvider{
+		"manual":       NewManualPortProvider,
+		"upnp":         func() PortProvider { return NewUPnPPortProvider(portMapper) },
+		"holepunching": NewNATHolePunchingPortProvider,
+	}
+
 	methods := strings.Split(config.GetString(config.FlagTraversal), ",")
 
 	for _, m := range methods {
@@ -59,7 +60,7 @@ func OrderedPortProviders() (list []NamedPortProvider) {
 
 		return []NamedPortProvider{
 			{"manual", NewManualPortProvider()},
-			{"upnp", NewUPnPPortProvider()},
+			{"upnp", NewUPnPPortProvider(portMapper)},
 			{"holepunching", NewNATHolePunchingPortProvider()},
 		}
 	}
@@ -24,7 +24,6 @@ import (
 
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/port"
-	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/nat/mapping"
 )
 
@@ -34,7 +33,7 @@ type upnpPort struct {
 }
 
 // NewUPnPPortProvider returns a new instance of the UPnP port provider.
-func NewUPnPPortProvider() PortProvider {
+func NewUPnPPortProvider(portMapper mapping.PortMapper) PortProvider {
 	udpPortRange, err := port.ParseRange(config.GetString(config.FlagUDPListenPorts))
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to parse UDP listen port range, using default value")
@@ -47,7 +46,7 @@ func NewUPnPPortProvider() PortProvider {
 
 	return &upnpPort{
 		pool:       port.NewFixedRangePool(udpPortRange),
-		portMapper: mapping.NewPortMapper(mapping.DefaultConfig(), eventbus.New()),
+		portMapper: portMapper,
 	}
 }
 
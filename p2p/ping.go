@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/communication"
+	"github.com/mysteriumnetwork/node/communication/nats"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+const pingPayloadSize = 16
+
+// ErrInvalidPong indicates that provider replied with unexpected ping payload.
+var ErrInvalidPong = errors.New("invalid pong payload")
+
+// Pinger measures round trip time to a provider p2p listener without establishing p2p channel.
+type Pinger interface {
+	// Ping sends ping message to provider listener and returns measured round trip time.
+	Ping(ctx context.Context, providerID identity.Identity, serviceType string, contactDef ContactDefinition) (time.Duration, error)
+}
+
+// NewPinger creates new p2p pinger which is used on consumer side.
+func NewPinger(broker brokerConnector) Pinger {
+	return &pinger{broker: broker}
+}
+
+type pinger struct {
+	broker brokerConnector
+}
+
+// Ping sends ping message to provider listener and returns measured round trip time.
+func (p *pinger) Ping(ctx context.Context, providerID identity.Identity, serviceType string, contactDef ContactDefinition) (time.Duration, error) {
+	serverURLs, err := nats.ParseServerURIs(contactDef.BrokerAddresses)
+	if err != nil {
+		return 0, err
+	}
+
+	conn, err := p.broker.Connect(serverURLs...)
+	if err != nil {
+		return 0, fmt.Errorf("could not open broker conn: %w", err)
+	}
+	defer conn.Close()
+
+	transport := nats.NewTransport(conn)
+	defer transport.Close()
+
+	payload := make([]byte, pingPayloadSize)
+	if _, err := rand.Read(payload); err != nil {
+		return 0, fmt.Errorf("could not generate ping payload: %w", err)
+	}
+
+	start := time.Now()
+	reply, err := transport.Request(ctx, communication.NewTransportMessage(pingSubject(providerID, serviceType), nil, payload, nil))
+	if err != nil {
+		return 0, fmt.Errorf("could not ping provider %s: %w", providerID.Address, err)
+	}
+	rtt := time.Since(start)
+
+	if !bytes.Equal(reply.Data, payload) {
+		return 0, ErrInvalidPong
+	}
+
+	return rtt, nil
+}
+
+func pingSubject(providerID identity.Identity, serviceType string) string {
+	return fmt.Sprintf("%s.%s.p2p-ping", providerID.Address, serviceType)
+}
+
+// listenPing replies to consumer pings with the same payload so consumer can measure round trip time.
+func (m *listener) listenPing(providerID identity.Identity, serviceType string) (communication.TransportSubscription, error) {
+	return m.subscribe(providerID, pingSubject(providerID, serviceType), func(msg *communication.TransportMessage) {
+		if len(msg.Data) != pingPayloadSize {
+			log.Debug().Msgf("Ignoring ping with unexpected payload size: %d", len(msg.Data))
+			return
+		}
+		if err := msg.Respond(msg.Data); err != nil {
+			log.Err(err).Msg("Could not publish pong")
+		}
+	})
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+const bindInterval = 250 * time.Millisecond
+
+// Dial binds to the relay allocation identified by the token and lane and blocks until the peer binds too.
+// Returned connection sends everything to the relay which forwards it to the peer.
+func Dial(ctx context.Context, relayAddr string, token Token, lane byte) (*net.UDPConn, error) {
+	raddr, err := net.ResolveUDPAddr("udp4", relayAddr)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve relay address: %w", err)
+	}
+	conn, err := net.DialUDP("udp4", nil, raddr)
+	if err != nil {
+		return nil, fmt.Errorf("could not dial relay: %w", err)
+	}
+
+	key := allocationKey{token: token, lane: lane}
+	bind := controlPacket(bindMagic, key)
+	ack := controlPacket(ackMagic, key)
+	buf := make([]byte, controlPacketSize)
+	for {
+		if _, err := conn.Write(bind); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("could not send relay bind: %w", err)
+		}
+
+		conn.SetReadDeadline(time.Now().Add(bindInterval))
+		n, err := conn.Read(buf)
+		if err == nil && bytes.Equal(buf[:n], ack) {
+			conn.SetReadDeadline(time.Time{})
+			return conn, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			return nil, fmt.Errorf("relay peer did not bind: %w", ctx.Err())
+		default:
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relay
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// TokenSize is a size of the relay allocation token.
+const TokenSize = 16
+
+// Lanes used by p2p peers, the first one carries p2p channel and the second one service traffic.
+const (
+	LaneChannel byte = 0
+	LaneService byte = 1
+)
+
+var (
+	bindMagic = []byte("MYRB")
+	ackMagic  = []byte("MYRA")
+)
+
+const controlPacketSize = 4 + TokenSize + 1
+
+// Token identifies relay allocation shared by two peers. It is generated by the consumer
+// and passed to the provider over encrypted p2p config exchange.
+type Token [TokenSize]byte
+
+// NewToken generates a new random allocation token.
+func NewToken() (Token, error) {
+	var t Token
+	_, err := rand.Read(t[:])
+	return t, err
+}
+
+// String returns hex representation of the token.
+func (t Token) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// TokenFromBytes converts given bytes to a token, ok is false if size does not match.
+func TokenFromBytes(b []byte) (t Token, ok bool) {
+	if len(b) != TokenSize {
+		return t, false
+	}
+	copy(t[:], b)
+	return t, true
+}
+
+type allocationKey struct {
+	token Token
+	lane  byte
+}
+
+func controlPacket(magic []byte, key allocationKey) []byte {
+	pkt := make([]byte, 0, controlPacketSize)
+	pkt = append(pkt, magic...)
+	pkt = append(pkt, key.token[:]...)
+	return append(pkt, key.lane)
+}
+
+func parseControlPacket(magic, pkt []byte) (allocationKey, bool) {
+	if len(pkt) != controlPacketSize || !bytes.HasPrefix(pkt, magic) {
+		return allocationKey{}, false
+	}
+
+	var key allocationKey
+	copy(key.token[:], pkt[len(magic):len(magic)+TokenSize])
+	key.lane = pkt[controlPacketSize-1]
+	return key, true
+}
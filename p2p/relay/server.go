@@ -0,0 +1,263 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relay
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+)
+
+const maxPacketSize = 65535
+
+// ServerConfig holds relay server limits.
+type ServerConfig struct {
+	// MaxRate is a number of bytes per second forwarded for a single allocation, zero means unlimited.
+	MaxRate int
+	// MaxBytes is a total number of bytes forwarded for a single allocation, zero means unlimited.
+	MaxBytes uint64
+	// IdleTimeout is a time after which allocation without traffic is released.
+	IdleTimeout time.Duration
+}
+
+// DefaultServerConfig returns default relay server limits.
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		MaxRate:     1024 * 1024,
+		MaxBytes:    0,
+		IdleTimeout: 2 * time.Minute,
+	}
+}
+
+// AllocationStats holds bandwidth accounting of a single relay allocation.
+type AllocationStats struct {
+	Token     string    `json:"token"`
+	Lane      byte      `json:"lane"`
+	CreatedAt time.Time `json:"created_at"`
+	Paired    bool      `json:"paired"`
+	// BytesForwarded is a number of bytes forwarded from the first and the second bound peer respectively.
+	BytesForwarded [2]uint64 `json:"bytes_forwarded"`
+	BytesDropped   uint64    `json:"bytes_dropped"`
+}
+
+type allocation struct {
+	key      allocationKey
+	peers    []*net.UDPAddr
+	limiter  *rate.Limiter
+	lastSeen time.Time
+	stats    AllocationStats
+}
+
+func (a *allocation) peerIndex(addr *net.UDPAddr) int {
+	for i, p := range a.peers {
+		if p.String() == addr.String() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (a *allocation) forwarded() uint64 {
+	return a.stats.BytesForwarded[0] + a.stats.BytesForwarded[1]
+}
+
+// Server is a community relay which forwards UDP traffic between two peers which failed to punch a hole
+// through their NATs. Every pair of peers gets its own allocation with bandwidth limits and accounting.
+type Server struct {
+	conn   *net.UDPConn
+	config ServerConfig
+
+	mu          sync.Mutex
+	allocations map[allocationKey]*allocation
+	byAddr      map[string]*allocation
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewServer returns a new instance of relay Server listening on the given UDP port.
+func NewServer(port int, config ServerConfig) (*Server, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: port})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		conn:        conn,
+		config:      config,
+		allocations: make(map[allocationKey]*allocation),
+		byAddr:      make(map[string]*allocation),
+		stop:        make(chan struct{}),
+	}, nil
+}
+
+// Addr returns local address the server listens on.
+func (s *Server) Addr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+// Serve forwards relayed traffic until the server is stopped.
+func (s *Server) Serve() error {
+	go s.releaseIdle()
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-s.stop:
+				return nil
+			default:
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			log.Debug().Err(err).Msg("Relay read failed")
+			continue
+		}
+
+		if key, ok := parseControlPacket(bindMagic, buf[:n]); ok {
+			s.bind(key, addr)
+			continue
+		}
+		s.forward(buf[:n], addr)
+	}
+}
+
+// Stop stops the server.
+func (s *Server) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		s.conn.Close()
+	})
+}
+
+// Stats returns bandwidth accounting of active allocations.
+func (s *Server) Stats() []AllocationStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]AllocationStats, 0, len(s.allocations))
+	for _, a := range s.allocations {
+		res = append(res, a.stats)
+	}
+	return res
+}
+
+func (s *Server) bind(key allocationKey, addr *net.UDPAddr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.allocations[key]
+	if !ok {
+		a = &allocation{
+			key: key,
+			stats: AllocationStats{
+				Token:     key.token.String(),
+				Lane:      key.lane,
+				CreatedAt: time.Now(),
+			},
+		}
+		if s.config.MaxRate > 0 {
+			a.limiter = rate.NewLimiter(rate.Limit(s.config.MaxRate), s.config.MaxRate)
+		}
+		s.allocations[key] = a
+	}
+	a.lastSeen = time.Now()
+
+	if a.peerIndex(addr) < 0 {
+		if len(a.peers) == 2 {
+			log.Warn().Msgf("Relay allocation %s is already paired, ignoring bind from %s", a.stats.Token, addr)
+			return
+		}
+		a.peers = append(a.peers, addr)
+		s.byAddr[addr.String()] = a
+	}
+
+	if len(a.peers) < 2 {
+		return
+	}
+	if !a.stats.Paired {
+		log.Info().Msgf("Relay allocation %s lane %d paired %s with %s", a.stats.Token, key.lane, a.peers[0], a.peers[1])
+		a.stats.Paired = true
+	}
+
+	// Peers keep binding until they get acknowledgement, so it is safe to resend it on every bind.
+	ack := controlPacket(ackMagic, key)
+	for _, p := range a.peers {
+		if _, err := s.conn.WriteToUDP(ack, p); err != nil {
+			log.Debug().Err(err).Msgf("Could not send relay ack to %s", p)
+		}
+	}
+}
+
+func (s *Server) forward(pkt []byte, from *net.UDPAddr) {
+	s.mu.Lock()
+	a, ok := s.byAddr[from.String()]
+	if !ok || !a.stats.Paired {
+		s.mu.Unlock()
+		return
+	}
+	idx := a.peerIndex(from)
+	to := a.peers[1-idx]
+	size := uint64(len(pkt))
+	overQuota := s.config.MaxBytes > 0 && a.forwarded()+size > s.config.MaxBytes
+	if overQuota || (a.limiter != nil && !a.limiter.AllowN(time.Now(), len(pkt))) {
+		a.stats.BytesDropped += size
+		s.mu.Unlock()
+		return
+	}
+	a.stats.BytesForwarded[idx] += size
+	a.lastSeen = time.Now()
+	s.mu.Unlock()
+
+	if _, err := s.conn.WriteToUDP(pkt, to); err != nil {
+		log.Debug().Err(err).Msgf("Could not relay packet to %s", to)
+	}
+}
+
+func (s *Server) releaseIdle() {
+	ticker := time.NewTicker(s.config.IdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		for key, a := range s.allocations {
+			if time.Since(a.lastSeen) < s.config.IdleTimeout {
+				continue
+			}
+			log.Info().Msgf("Releasing idle relay allocation %s lane %d, forwarded %d bytes, dropped %d bytes",
+				a.stats.Token, key.lane, a.forwarded(), a.stats.BytesDropped)
+			for _, p := range a.peers {
+				delete(s.byAddr, p.String())
+			}
+			delete(s.allocations, key)
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package relay
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startTestServer(t *testing.T, config ServerConfig) (*Server, string) {
+	server, err := NewServer(0, config)
+	require.NoError(t, err)
+	go server.Serve()
+	t.Cleanup(server.Stop)
+
+	port := server.Addr().(*net.UDPAddr).Port
+	return server, net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+}
+
+func dialPair(t *testing.T, addr string, token Token) (*net.UDPConn, *net.UDPConn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	type result struct {
+		conn *net.UDPConn
+		err  error
+	}
+	results := make(chan result, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			conn, err := Dial(ctx, addr, token, LaneChannel)
+			results <- result{conn, err}
+		}()
+	}
+
+	var conns []*net.UDPConn
+	for i := 0; i < 2; i++ {
+		r := <-results
+		require.NoError(t, r.err)
+		t.Cleanup(func() { r.conn.Close() })
+		conns = append(conns, r.conn)
+	}
+	return conns[0], conns[1]
+}
+
+func TestServer_ForwardsBetweenPairedPeers(t *testing.T) {
+	server, addr := startTestServer(t, DefaultServerConfig())
+	token, err := NewToken()
+	require.NoError(t, err)
+
+	conn1, conn2 := dialPair(t, addr, token)
+
+	_, err = conn1.Write([]byte("hello"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 100)
+	conn2.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn2.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf[:n]))
+
+	_, err = conn2.Write([]byte("world!"))
+	require.NoError(t, err)
+	conn1.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err = conn1.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "world!", string(buf[:n]))
+
+	stats := server.Stats()
+	require.Len(t, stats, 1)
+	assert.True(t, stats[0].Paired)
+	assert.Equal(t, token.String(), stats[0].Token)
+	assert.Equal(t, uint64(11), stats[0].BytesForwarded[0]+stats[0].BytesForwarded[1])
+}
+
+func TestServer_DropsTrafficOverQuota(t *testing.T) {
+	config := DefaultServerConfig()
+	config.MaxBytes = 10
+	server, addr := startTestServer(t, config)
+	token, err := NewToken()
+	require.NoError(t, err)
+
+	conn1, conn2 := dialPair(t, addr, token)
+
+	_, err = conn1.Write([]byte("0123456789"))
+	require.NoError(t, err)
+	buf := make([]byte, 100)
+	conn2.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = conn2.Read(buf)
+	require.NoError(t, err)
+
+	_, err = conn1.Write([]byte("over"))
+	require.NoError(t, err)
+	conn2.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+	_, err = conn2.Read(buf)
+	assert.Error(t, err)
+
+	stats := server.Stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint64(4), stats[0].BytesDropped)
+}
+
+func TestDial_TimesOutWithoutPeer(t *testing.T) {
+	_, addr := startTestServer(t, DefaultServerConfig())
+	token, err := NewToken()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Millisecond)
+	defer cancel()
+	_, err = Dial(ctx, addr, token, LaneService)
+	assert.Error(t, err)
+}
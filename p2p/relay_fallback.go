@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/p2p/relay"
+	"github.com/mysteriumnetwork/node/router"
+)
+
+// relayAddresses returns relays this node is willing to use when hole punching fails.
+func relayAddresses() []string {
+	return config.GetStringSlice(config.FlagP2PRelays)
+}
+
+// chooseRelay picks one of the relays offered by provider. Consumer prefers relays it trusts itself
+// and takes the first provider's relay if it has none configured.
+func chooseRelay(offered, own []string) string {
+	if len(offered) == 0 {
+		return ""
+	}
+	if len(own) == 0 {
+		return offered[0]
+	}
+
+	for _, r := range own {
+		if containsRelay(offered, r) {
+			return r
+		}
+	}
+	return ""
+}
+
+func containsRelay(relays []string, addr string) bool {
+	for _, r := range relays {
+		if r == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// dialRelay binds both p2p channel and service lanes of the relay allocation.
+func dialRelay(ctx context.Context, relayAddr string, token relay.Token) ([]*net.UDPConn, error) {
+	lanes := []byte{relay.LaneChannel, relay.LaneService}
+	conns := make([]*net.UDPConn, len(lanes))
+	errs := make(chan error, len(lanes))
+	for i, lane := range lanes {
+		go func(i int, lane byte) {
+			conn, err := relay.Dial(ctx, relayAddr, token, lane)
+			if err == nil {
+				err = router.ProtectUDPConn(conn)
+			}
+			conns[i] = conn
+			errs <- err
+		}(i, lane)
+	}
+
+	var dialErr error
+	for range lanes {
+		if err := <-errs; err != nil && dialErr == nil {
+			dialErr = err
+		}
+	}
+	if dialErr != nil {
+		for _, conn := range conns {
+			if conn != nil {
+				conn.Close()
+			}
+		}
+		return nil, fmt.Errorf("could not dial relay %s: %w", relayAddr, dialErr)
+	}
+
+	return conns, nil
+}
@@ -0,0 +1,412 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.15.8
+// source: pb/management.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ManagementClient is the client API for Management service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ManagementClient interface {
+	Connect(ctx context.Context, in *ConnectRequest, opts ...grpc.CallOption) (*ConnectionStatus, error)
+	Disconnect(ctx context.Context, in *ConnectionRequest, opts ...grpc.CallOption) (*ManagementEmpty, error)
+	GetConnection(ctx context.Context, in *ConnectionRequest, opts ...grpc.CallOption) (*ConnectionStatus, error)
+	ListIdentities(ctx context.Context, in *ManagementEmpty, opts ...grpc.CallOption) (*IdentityList, error)
+	ListProposals(ctx context.Context, in *ProposalsRequest, opts ...grpc.CallOption) (*ProposalList, error)
+	ListSessions(ctx context.Context, in *SessionsRequest, opts ...grpc.CallOption) (*SessionList, error)
+	StreamConnectionState(ctx context.Context, in *ManagementEmpty, opts ...grpc.CallOption) (Management_StreamConnectionStateClient, error)
+	StreamStatistics(ctx context.Context, in *ManagementEmpty, opts ...grpc.CallOption) (Management_StreamStatisticsClient, error)
+}
+
+type managementClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewManagementClient(cc grpc.ClientConnInterface) ManagementClient {
+	return &managementClient{cc}
+}
+
+func (c *managementClient) Connect(ctx context.Context, in *ConnectRequest, opts ...grpc.CallOption) (*ConnectionStatus, error) {
+	out := new(ConnectionStatus)
+	err := c.cc.Invoke(ctx, "/pb.Management/Connect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) Disconnect(ctx context.Context, in *ConnectionRequest, opts ...grpc.CallOption) (*ManagementEmpty, error) {
+	out := new(ManagementEmpty)
+	err := c.cc.Invoke(ctx, "/pb.Management/Disconnect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) GetConnection(ctx context.Context, in *ConnectionRequest, opts ...grpc.CallOption) (*ConnectionStatus, error) {
+	out := new(ConnectionStatus)
+	err := c.cc.Invoke(ctx, "/pb.Management/GetConnection", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListIdentities(ctx context.Context, in *ManagementEmpty, opts ...grpc.CallOption) (*IdentityList, error) {
+	out := new(IdentityList)
+	err := c.cc.Invoke(ctx, "/pb.Management/ListIdentities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListProposals(ctx context.Context, in *ProposalsRequest, opts ...grpc.CallOption) (*ProposalList, error) {
+	out := new(ProposalList)
+	err := c.cc.Invoke(ctx, "/pb.Management/ListProposals", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListSessions(ctx context.Context, in *SessionsRequest, opts ...grpc.CallOption) (*SessionList, error) {
+	out := new(SessionList)
+	err := c.cc.Invoke(ctx, "/pb.Management/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) StreamConnectionState(ctx context.Context, in *ManagementEmpty, opts ...grpc.CallOption) (Management_StreamConnectionStateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Management_ServiceDesc.Streams[0], "/pb.Management/StreamConnectionState", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &managementStreamConnectionStateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Management_StreamConnectionStateClient interface {
+	Recv() (*ConnectionStatus, error)
+	grpc.ClientStream
+}
+
+type managementStreamConnectionStateClient struct {
+	grpc.ClientStream
+}
+
+func (x *managementStreamConnectionStateClient) Recv() (*ConnectionStatus, error) {
+	m := new(ConnectionStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *managementClient) StreamStatistics(ctx context.Context, in *ManagementEmpty, opts ...grpc.CallOption) (Management_StreamStatisticsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Management_ServiceDesc.Streams[1], "/pb.Management/StreamStatistics", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &managementStreamStatisticsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Management_StreamStatisticsClient interface {
+	Recv() (*ConnectionStatistics, error)
+	grpc.ClientStream
+}
+
+type managementStreamStatisticsClient struct {
+	grpc.ClientStream
+}
+
+func (x *managementStreamStatisticsClient) Recv() (*ConnectionStatistics, error) {
+	m := new(ConnectionStatistics)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ManagementServer is the server API for Management service.
+// All implementations must embed UnimplementedManagementServer
+// for forward compatibility
+type ManagementServer interface {
+	Connect(context.Context, *ConnectRequest) (*ConnectionStatus, error)
+	Disconnect(context.Context, *ConnectionRequest) (*ManagementEmpty, error)
+	GetConnection(context.Context, *ConnectionRequest) (*ConnectionStatus, error)
+	ListIdentities(context.Context, *ManagementEmpty) (*IdentityList, error)
+	ListProposals(context.Context, *ProposalsRequest) (*ProposalList, error)
+	ListSessions(context.Context, *SessionsRequest) (*SessionList, error)
+	StreamConnectionState(*ManagementEmpty, Management_StreamConnectionStateServer) error
+	StreamStatistics(*ManagementEmpty, Management_StreamStatisticsServer) error
+	mustEmbedUnimplementedManagementServer()
+}
+
+// UnimplementedManagementServer must be embedded to have forward compatible implementations.
+type UnimplementedManagementServer struct {
+}
+
+func (UnimplementedManagementServer) Connect(context.Context, *ConnectRequest) (*ConnectionStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+func (UnimplementedManagementServer) Disconnect(context.Context, *ConnectionRequest) (*ManagementEmpty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Disconnect not implemented")
+}
+func (UnimplementedManagementServer) GetConnection(context.Context, *ConnectionRequest) (*ConnectionStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConnection not implemented")
+}
+func (UnimplementedManagementServer) ListIdentities(context.Context, *ManagementEmpty) (*IdentityList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIdentities not implemented")
+}
+func (UnimplementedManagementServer) ListProposals(context.Context, *ProposalsRequest) (*ProposalList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProposals not implemented")
+}
+func (UnimplementedManagementServer) ListSessions(context.Context, *SessionsRequest) (*SessionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedManagementServer) StreamConnectionState(*ManagementEmpty, Management_StreamConnectionStateServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamConnectionState not implemented")
+}
+func (UnimplementedManagementServer) StreamStatistics(*ManagementEmpty, Management_StreamStatisticsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamStatistics not implemented")
+}
+func (UnimplementedManagementServer) mustEmbedUnimplementedManagementServer() {}
+
+// UnsafeManagementServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ManagementServer will
+// result in compilation errors.
+type UnsafeManagementServer interface {
+	mustEmbedUnimplementedManagementServer()
+}
+
+func RegisterManagementServer(s grpc.ServiceRegistrar, srv ManagementServer) {
+	s.RegisterService(&Management_ServiceDesc, srv)
+}
+
+func _Management_Connect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).Connect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Management/Connect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).Connect(ctx, req.(*ConnectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_Disconnect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).Disconnect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Management/Disconnect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).Disconnect(ctx, req.(*ConnectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_GetConnection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).GetConnection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Management/GetConnection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).GetConnection(ctx, req.(*ConnectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListIdentities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ManagementEmpty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListIdentities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Management/ListIdentities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListIdentities(ctx, req.(*ManagementEmpty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListProposals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProposalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListProposals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Management/ListProposals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListProposals(ctx, req.(*ProposalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Management/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListSessions(ctx, req.(*SessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_StreamConnectionState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ManagementEmpty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ManagementServer).StreamConnectionState(m, &managementStreamConnectionStateServer{stream})
+}
+
+type Management_StreamConnectionStateServer interface {
+	Send(*ConnectionStatus) error
+	grpc.ServerStream
+}
+
+type managementStreamConnectionStateServer struct {
+	grpc.ServerStream
+}
+
+func (x *managementStreamConnectionStateServer) Send(m *ConnectionStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Management_StreamStatistics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ManagementEmpty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ManagementServer).StreamStatistics(m, &managementStreamStatisticsServer{stream})
+}
+
+type Management_StreamStatisticsServer interface {
+	Send(*ConnectionStatistics) error
+	grpc.ServerStream
+}
+
+type managementStreamStatisticsServer struct {
+	grpc.ServerStream
+}
+
+func (x *managementStreamStatisticsServer) Send(m *ConnectionStatistics) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Management_ServiceDesc is the grpc.ServiceDesc for Management service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Management_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Management",
+	HandlerType: (*ManagementServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Connect",
+			Handler:    _Management_Connect_Handler,
+		},
+		{
+			MethodName: "Disconnect",
+			Handler:    _Management_Disconnect_Handler,
+		},
+		{
+			MethodName: "GetConnection",
+			Handler:    _Management_GetConnection_Handler,
+		},
+		{
+			MethodName: "ListIdentities",
+			Handler:    _Management_ListIdentities_Handler,
+		},
+		{
+			MethodName: "ListProposals",
+			Handler:    _Management_ListProposals_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _Management_ListSessions_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamConnectionState",
+			Handler:       _Management_StreamConnectionState_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamStatistics",
+			Handler:       _Management_StreamStatistics_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pb/management.proto",
+}
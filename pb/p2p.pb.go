@@ -85,8 +85,10 @@ type P2PConfigExchangeMsg struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PublicKey        string `protobuf:"bytes,1,opt,name=publicKey,proto3" json:"publicKey,omitempty"`               // Public key field which is send from both provider and consumer.
-	ConfigCiphertext []byte `protobuf:"bytes,2,opt,name=configCiphertext,proto3" json:"configCiphertext,omitempty"` // Encrypted P2PConnectConfig data.
+	PublicKey         string `protobuf:"bytes,1,opt,name=publicKey,proto3" json:"publicKey,omitempty"`                 // Public key field which is send from both provider and consumer.
+	ConfigCiphertext  []byte `protobuf:"bytes,2,opt,name=configCiphertext,proto3" json:"configCiphertext,omitempty"`   // Encrypted P2PConnectConfig data.
+	Traceparent       string `protobuf:"bytes,3,opt,name=traceparent,proto3" json:"traceparent,omitempty"`             // W3C trace context of the consumer connect, empty if it is not traced.
+	CipherNegotiation []byte `protobuf:"bytes,4,opt,name=cipherNegotiation,proto3" json:"cipherNegotiation,omitempty"` // Cipher offer of consumer or answer of provider, empty for peers predating negotiation.
 }
 
 func (x *P2PConfigExchangeMsg) Reset() {
@@ -135,14 +137,35 @@ func (x *P2PConfigExchangeMsg) GetConfigCiphertext() []byte {
 	return nil
 }
 
+func (x *P2PConfigExchangeMsg) GetTraceparent() string {
+	if x != nil {
+		return x.Traceparent
+	}
+	return ""
+}
+
+func (x *P2PConfigExchangeMsg) GetCipherNegotiation() []byte {
+	if x != nil {
+		return x.CipherNegotiation
+	}
+	return nil
+}
+
 type P2PConnectConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PublicIP      string  `protobuf:"bytes,1,opt,name=publicIP,proto3" json:"publicIP,omitempty"`
-	Ports         []int32 `protobuf:"varint,2,rep,packed,name=ports,proto3" json:"ports,omitempty"`
-	Compatibility int32   `protobuf:"varint,3,opt,name=compatibility,proto3" json:"compatibility,omitempty"`
+	PublicIP      string   `protobuf:"bytes,1,opt,name=publicIP,proto3" json:"publicIP,omitempty"`
+	Ports         []int32  `protobuf:"varint,2,rep,packed,name=ports,proto3" json:"ports,omitempty"`
+	Compatibility int32    `protobuf:"varint,3,opt,name=compatibility,proto3" json:"compatibility,omitempty"`
+	Relays        []string `protobuf:"bytes,4,rep,name=relays,proto3" json:"relays,omitempty"`               // Relay addresses offered by provider or the one chosen by consumer.
+	RelayToken    []byte   `protobuf:"bytes,5,opt,name=relayToken,proto3" json:"relayToken,omitempty"`       // Relay allocation token generated by consumer.
+	PublicIPv6    string   `protobuf:"bytes,6,opt,name=publicIPv6,proto3" json:"publicIPv6,omitempty"`       // Global IPv6 address, empty if peer has no IPv6 connectivity.
+	PortsIPv6     []int32  `protobuf:"varint,7,rep,packed,name=portsIPv6,proto3" json:"portsIPv6,omitempty"` // Local ports reachable over IPv6.
+	PreferRelay   bool     `protobuf:"varint,8,opt,name=preferRelay,proto3" json:"preferRelay,omitempty"`    // Provider is behind carrier-grade NAT and asks to skip hole punching when relay is chosen.
+	LanIP         string   `protobuf:"bytes,9,opt,name=lanIP,proto3" json:"lanIP,omitempty"`                 // Private address of the outbound interface, used when both peers are behind the same NAT.
+	PortsLAN      []int32  `protobuf:"varint,10,rep,packed,name=portsLAN,proto3" json:"portsLAN,omitempty"`  // Local ports reachable over LAN.
 }
 
 func (x *P2PConnectConfig) Reset() {
@@ -198,6 +221,55 @@ func (x *P2PConnectConfig) GetCompatibility() int32 {
 	return 0
 }
 
+func (x *P2PConnectConfig) GetRelays() []string {
+	if x != nil {
+		return x.Relays
+	}
+	return nil
+}
+
+func (x *P2PConnectConfig) GetRelayToken() []byte {
+	if x != nil {
+		return x.RelayToken
+	}
+	return nil
+}
+
+func (x *P2PConnectConfig) GetPublicIPv6() string {
+	if x != nil {
+		return x.PublicIPv6
+	}
+	return ""
+}
+
+func (x *P2PConnectConfig) GetPortsIPv6() []int32 {
+	if x != nil {
+		return x.PortsIPv6
+	}
+	return nil
+}
+
+func (x *P2PConnectConfig) GetPreferRelay() bool {
+	if x != nil {
+		return x.PreferRelay
+	}
+	return false
+}
+
+func (x *P2PConnectConfig) GetLanIP() string {
+	if x != nil {
+		return x.LanIP
+	}
+	return ""
+}
+
+func (x *P2PConnectConfig) GetPortsLAN() []int32 {
+	if x != nil {
+		return x.PortsLAN
+	}
+	return nil
+}
+
 type P2PKeepAlivePing struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -379,35 +451,53 @@ var file_pb_p2p_proto_rawDesc = []byte{
 	0x73, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
 	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
 	0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
-	0x74, 0x75, 0x72, 0x65, 0x22, 0x60, 0x0a, 0x14, 0x50, 0x32, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x45, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x1c, 0x0a, 0x09,
-	0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x10, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x43, 0x69, 0x70, 0x68,
-	0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x22, 0x6a, 0x0a, 0x10, 0x50, 0x32, 0x50, 0x43, 0x6f, 0x6e,
-	0x6e, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x75,
-	0x62, 0x6c, 0x69, 0x63, 0x49, 0x50, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x75,
-	0x62, 0x6c, 0x69, 0x63, 0x49, 0x50, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x05, 0x52, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x0d,
-	0x63, 0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69, 0x62, 0x69, 0x6c, 0x69,
-	0x74, 0x79, 0x22, 0x30, 0x0a, 0x10, 0x50, 0x32, 0x50, 0x4b, 0x65, 0x65, 0x70, 0x41, 0x6c, 0x69,
-	0x76, 0x65, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69,
-	0x6f, 0x6e, 0x49, 0x44, 0x22, 0x2f, 0x0a, 0x17, 0x50, 0x32, 0x50, 0x43, 0x68, 0x61, 0x6e, 0x6e,
-	0x65, 0x6c, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x72, 0x73, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x80, 0x01, 0x0a, 0x12, 0x50, 0x32, 0x50, 0x43, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02,
-	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05,
-	0x74, 0x6f, 0x70, 0x69, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x70,
-	0x69, 0x63, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x6d, 0x73, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x42, 0x06, 0x5a, 0x04, 0x2e, 0x3b, 0x70, 0x62,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x75, 0x72, 0x65, 0x22, 0xb0, 0x01, 0x0a, 0x14, 0x50, 0x32, 0x50, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x45, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x1c, 0x0a,
+	0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x10, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x43, 0x69, 0x70,
+	0x68, 0x65, 0x72, 0x74, 0x65, 0x78, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x63, 0x65,
+	0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x72,
+	0x61, 0x63, 0x65, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x11, 0x63, 0x69, 0x70,
+	0x68, 0x65, 0x72, 0x4e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4e, 0x65, 0x67, 0x6f,
+	0x74, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xb4, 0x02, 0x0a, 0x10, 0x50, 0x32, 0x50, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x50, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x50, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x72, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x24,
+	0x0a, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x72, 0x65, 0x6c, 0x61, 0x79, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1e, 0x0a, 0x0a,
+	0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x50, 0x76, 0x36, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x49, 0x50, 0x76, 0x36, 0x12, 0x1c, 0x0a, 0x09,
+	0x70, 0x6f, 0x72, 0x74, 0x73, 0x49, 0x50, 0x76, 0x36, 0x18, 0x07, 0x20, 0x03, 0x28, 0x05, 0x52,
+	0x09, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x49, 0x50, 0x76, 0x36, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0b, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x6c, 0x61, 0x6e, 0x49, 0x50, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x6e,
+	0x49, 0x50, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x4c, 0x41, 0x4e, 0x18, 0x0a,
+	0x20, 0x03, 0x28, 0x05, 0x52, 0x08, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x4c, 0x41, 0x4e, 0x22, 0x30,
+	0x0a, 0x10, 0x50, 0x32, 0x50, 0x4b, 0x65, 0x65, 0x70, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x50, 0x69,
+	0x6e, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x44,
+	0x22, 0x2f, 0x0a, 0x17, 0x50, 0x32, 0x50, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x48, 0x61,
+	0x6e, 0x64, 0x6c, 0x65, 0x72, 0x73, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x22, 0x80, 0x01, 0x0a, 0x12, 0x50, 0x32, 0x50, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69,
+	0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x10,
+	0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x42, 0x06, 0x5a, 0x04, 0x2e, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -0,0 +1,200 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package node allows embedding the Mysterium node into another Go program.
+//
+//	n, err := node.New(node.Options{DataDir: "/var/lib/myapp/mysterium"})
+//	if err != nil {
+//		return err
+//	}
+//	n.Options.Consumer = true
+//	if err := n.Start(); err != nil {
+//		return err
+//	}
+//	defer n.Stop()
+package node
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"github.com/urfave/cli/v2"
+
+	"github.com/mysteriumnetwork/node/cmd"
+	"github.com/mysteriumnetwork/node/config"
+	corenode "github.com/mysteriumnetwork/node/core/node"
+	"github.com/mysteriumnetwork/node/logconfig"
+)
+
+// Options describes the environment of the embedded node.
+type Options struct {
+	// DataDir keeps identities, databases and other node data.
+	DataDir string
+	// ConfigFile is an optional TOML file with configuration values named after command line flags.
+	ConfigFile string
+	// Network is the blockchain network the node runs on, mainnet is used when empty.
+	Network string
+	// LogWriter receives node logs as JSON lines, they are written to the standard error when nil.
+	LogWriter io.Writer
+	// LogLevel filters node logs, debug logs are included by default.
+	LogLevel zerolog.Level
+}
+
+type nodeState int
+
+const (
+	stateCreated nodeState = iota
+	stateStarted
+	stateStopped
+)
+
+var (
+	// ErrAlreadyStarted is returned when starting a node which was started before.
+	ErrAlreadyStarted = errors.New("node was already started")
+	// ErrNotStarted is returned when waiting for a node which is not running.
+	ErrNotStarted = errors.New("node is not started")
+)
+
+// Node is a Mysterium node running inside another program.
+// Only one node can run in a process, as the node configuration is global.
+type Node struct {
+	// Options are used to bootstrap the node, they can be adjusted before Start.
+	Options corenode.Options
+
+	mu    sync.Mutex
+	state nodeState
+	di    cmd.Dependencies
+}
+
+// New creates a node keeping its data in the given directory. Node options are resolved from
+// command line flag defaults, the network and the config file, as if the node binary was started
+// with the data directory. Tequilapi and the web UI are disabled unless enabled in Options before Start.
+func New(options Options) (*Node, error) {
+	if options.DataDir == "" {
+		return nil, errors.New("node data directory is required")
+	}
+
+	logconfig.Bootstrap()
+	logOptions := logconfig.LogOptions{
+		LogLevel: options.LogLevel,
+		Writer:   options.LogWriter,
+	}
+	logconfig.Configure(&logOptions)
+
+	if err := setDefaults(options); err != nil {
+		return nil, err
+	}
+	if options.ConfigFile != "" {
+		if err := config.Current.LoadUserConfig(options.ConfigFile); err != nil {
+			return nil, err
+		}
+	}
+
+	nodeOptions := corenode.GetOptions()
+	nodeOptions.LogOptions = logOptions
+	nodeOptions.TequilapiEnabled = false
+	nodeOptions.UI.UIEnabled = false
+	nodeOptions.Embedded = true
+
+	return &Node{Options: *nodeOptions}, nil
+}
+
+// setDefaults sets configuration defaults the command line sets from flags.
+func setDefaults(options Options) error {
+	var flags []cli.Flag
+	if err := config.RegisterFlagsNode(&flags); err != nil {
+		return err
+	}
+	config.RegisterFlagsServiceStart(&flags)
+	config.RegisterFlagsServiceOpenvpn(&flags)
+	config.RegisterFlagsServiceWireguard(&flags)
+	config.RegisterFlagsServiceNoop(&flags)
+	config.Current.SetDefaultsFromFlags(flags)
+
+	network := config.Mainnet
+	if options.Network != "" {
+		var err error
+		network, err = config.ParseBlockchainNetwork(options.Network)
+		if err != nil {
+			return fmt.Errorf("invalid network: %w", err)
+		}
+	}
+	config.Current.SetDefault(config.FlagBlockchainNetwork.Name, string(network))
+	config.Current.SetDefaultsByNetwork(network)
+
+	config.Current.SetDefault(config.FlagDataDir.Name, options.DataDir)
+	config.Current.SetDefault(config.FlagConfigDir.Name, options.DataDir)
+	config.Current.SetDefault(config.FlagLogDir.Name, filepath.Join(options.DataDir, "logs"))
+	config.Current.SetDefault(config.FlagRuntimeDir.Name, options.DataDir)
+	config.Current.SetDefault(config.FlagScriptDir.Name, filepath.Join(options.DataDir, "config"))
+	config.Current.SetDefault(config.FlagNodeUIDir.Name, filepath.Join(options.DataDir, "nodeui"))
+	return nil
+}
+
+// Start bootstraps and starts the node. A stopped node can not be started again.
+func (n *Node) Start() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.state != stateCreated {
+		return ErrAlreadyStarted
+	}
+	n.state = stateStarted
+
+	if err := n.di.Bootstrap(n.Options); err != nil {
+		if shutdownErr := n.di.Shutdown(); shutdownErr != nil {
+			err = fmt.Errorf("%w, shutdown failed: %v", err, shutdownErr)
+		}
+		n.state = stateStopped
+		return fmt.Errorf("could not bootstrap node: %w", err)
+	}
+	return nil
+}
+
+// Stop stops the node, disconnecting the active connections and stopping running services.
+func (n *Node) Stop() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.state != stateStarted {
+		return nil
+	}
+	n.state = stateStopped
+	return n.di.Shutdown()
+}
+
+// Wait blocks until the node is stopped.
+func (n *Node) Wait() error {
+	n.mu.Lock()
+	if n.state != stateStarted {
+		n.mu.Unlock()
+		return ErrNotStarted
+	}
+	node := n.di.Node
+	n.mu.Unlock()
+
+	return node.Wait()
+}
+
+// Dependencies returns components of the started node, e.g. to manage identities, connections and services.
+func (n *Node) Dependencies() *cmd.Dependencies {
+	return &n.di
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package node
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_ResolvesOptionsInDataDir(t *testing.T) {
+	dataDir := t.TempDir()
+	var logs bytes.Buffer
+
+	n, err := New(Options{DataDir: dataDir, Network: "testnet", LogWriter: &logs})
+	require.NoError(t, err)
+
+	assert.True(t, n.Options.Embedded)
+	assert.False(t, n.Options.TequilapiEnabled)
+	assert.False(t, n.Options.UI.UIEnabled)
+	assert.True(t, n.Options.OptionsNetwork.Network.IsTestnet())
+	assert.Equal(t, dataDir, n.Options.Directories.Data)
+	assert.Equal(t, filepath.Join(dataDir, "keystore"), n.Options.Directories.Keystore)
+	assert.Equal(t, filepath.Join(dataDir, "testnet", "db"), n.Options.Directories.Storage)
+	assert.Equal(t, &logs, n.Options.LogOptions.Writer)
+
+	log.Info().Msg("embedded node log")
+	assert.Contains(t, logs.String(), `"message":"embedded node log"`)
+
+	assert.ErrorIs(t, n.Wait(), ErrNotStarted)
+	assert.NoError(t, n.Stop())
+}
+
+func TestNew_ValidatesOptions(t *testing.T) {
+	_, err := New(Options{})
+	assert.EqualError(t, err, "node data directory is required")
+
+	_, err = New(Options{DataDir: t.TempDir(), Network: "moonnet"})
+	assert.Error(t, err)
+}
@@ -27,25 +27,20 @@ import (
 )
 
 // NewConnection creates a new noop connnection
-func NewConnection() (connection.Connection, error) {
+func NewConnection(states connection.StateHandler) (connection.Connection, error) {
 	return &Connection{
-		stateCh: make(chan connectionstate.State, 100),
+		states: states,
 	}, nil
 }
 
 // Connection which does no real tunneling
 type Connection struct {
 	isRunning bool
-	stateCh   chan connectionstate.State
+	states    connection.StateHandler
 }
 
 var _ connection.Connection = &Connection{}
 
-// State returns connection state channel.
-func (c *Connection) State() <-chan connectionstate.State {
-	return c.stateCh
-}
-
 // Statistics returns connection statistics channel.
 func (c *Connection) Statistics() (connectionstate.Statistics, error) {
 	return connectionstate.Statistics{At: time.Now()}, nil
@@ -60,10 +55,10 @@ func (c *Connection) Reconnect(ctx context.Context, options connection.ConnectOp
 func (c *Connection) Start(ctx context.Context, params connection.ConnectOptions) error {
 	c.isRunning = true
 
-	c.stateCh <- connectionstate.Connecting
+	c.states.Handle(connectionstate.Connecting)
 
 	time.Sleep(5 * time.Second)
-	c.stateCh <- connectionstate.Connected
+	c.states.Handle(connectionstate.Connected)
 	return nil
 }
 
@@ -74,10 +69,10 @@ func (c *Connection) Stop() {
 	}
 
 	c.isRunning = false
-	c.stateCh <- connectionstate.Disconnecting
+	c.states.Handle(connectionstate.Disconnecting)
 	time.Sleep(2 * time.Second)
-	c.stateCh <- connectionstate.NotConnected
-	close(c.stateCh)
+	c.states.Handle(connectionstate.NotConnected)
+	c.states.Close()
 }
 
 // GetConfig returns the consumer configuration for session creation
@@ -49,15 +49,15 @@ type processFactory func(options connection.ConnectOptions, sessionConfig VPNCon
 
 // NewClient creates a new openvpn connection
 func NewClient(openvpnBinary, scriptDir, runtimeDir string,
+	states connection.StateHandler,
 	signerFactory identity.SignerFactory,
 	ipResolver ip.Resolver,
 ) (connection.Connection, error) {
-	stateCh := make(chan connectionstate.State, 100)
 	client := &Client{
 		scriptDir:           scriptDir,
 		runtimeDir:          runtimeDir,
 		signerFactory:       signerFactory,
-		stateCh:             stateCh,
+		states:              states,
 		ipResolver:          ipResolver,
 		removeAllowedIPRule: func() {},
 	}
@@ -70,7 +70,7 @@ func NewClient(openvpnBinary, scriptDir, runtimeDir string,
 
 		signer := signerFactory(options.ConsumerID)
 
-		stateMiddleware := newStateMiddleware(stateCh)
+		stateMiddleware := newStateMiddleware(states)
 		authMiddleware := newAuthMiddleware(options.SessionID, signer)
 		byteCountMiddleware := openvpn_bytescount.NewMiddleware(client.OnStats, config.GetDuration(config.FlagStatsReportInterval))
 		proc := openvpn.CreateNewProcess(openvpnBinary, vpnClientConfig.GenericConfig, stateMiddleware, byteCountMiddleware, authMiddleware)
@@ -86,23 +86,19 @@ type Client struct {
 	scriptDir           string
 	runtimeDir          string
 	signerFactory       identity.SignerFactory
-	stateCh             chan connectionstate.State
+	states              connection.StateHandler
 	stats               connectionstate.Statistics
 	statsMu             sync.RWMutex
 	process             openvpn.Process
 	processFactory      processFactory
 	ipResolver          ip.Resolver
 	removeAllowedIPRule func()
+	stopDNSStub         func() error
 	stopOnce            sync.Once
 }
 
 var _ connection.Connection = &Client{}
 
-// State returns connection state channel.
-func (c *Client) State() <-chan connectionstate.State {
-	return c.stateCh
-}
-
 // Statistics returns connection statistics channel.
 func (c *Client) Statistics() (connectionstate.Statistics, error) {
 	c.statsMu.RLock()
@@ -130,9 +126,18 @@ func (c *Client) Start(ctx context.Context, options connection.ConnectOptions) e
 		return errors.Wrap(err, "failed to add allowed IP address")
 	}
 
+	if options.UsesDNSStub() {
+		c.stopDNSStub, err = options.StartDNSStub(sessionConfig.DNSIPs)
+		if err != nil {
+			c.removeAllowedIPRule()
+			return errors.Wrap(err, "failed to start DNS stub")
+		}
+	}
+
 	proc, clientConfig, err := c.processFactory(options, sessionConfig)
 	if err != nil {
 		log.Info().Err(err).Msg("Client config factory error")
+		c.stopDNS()
 		return errors.Wrap(err, "client config factory error")
 	}
 	c.process = proc
@@ -141,6 +146,7 @@ func (c *Client) Start(ctx context.Context, options connection.ConnectOptions) e
 	err = c.process.Start()
 	if err != nil {
 		c.removeAllowedIPRule()
+		c.stopDNS()
 	}
 	return errors.Wrap(err, "failed to start client process")
 }
@@ -152,9 +158,20 @@ func (c *Client) Stop() {
 			c.process.Stop()
 		}
 		c.removeAllowedIPRule()
+		c.stopDNS()
 	})
 }
 
+func (c *Client) stopDNS() {
+	if c.stopDNSStub == nil {
+		return
+	}
+	if err := c.stopDNSStub(); err != nil {
+		log.Error().Err(err).Msg("Failed to stop DNS stub")
+	}
+	c.stopDNSStub = nil
+}
+
 // OnStats updates connection statistics.
 func (c *Client) OnStats(cnt openvpn_bytescount.Bytecount) error {
 	c.statsMu.Lock()
@@ -187,22 +204,22 @@ func newAuthMiddleware(sessionID session.ID, signer identity.Signer) management.
 	return auth.NewMiddleware(credentialsProvider)
 }
 
-func newStateMiddleware(stateChannel connection.StateChannel) management.Middleware {
-	stateCallback := getStateCallback(stateChannel)
+func newStateMiddleware(states connection.StateHandler) management.Middleware {
+	stateCallback := getStateCallback(states)
 	return state.NewMiddleware(stateCallback)
 }
 
 // getStateCallback returns the callback for working with openvpn state
-func getStateCallback(stateChannel connection.StateChannel) func(openvpnState openvpn.State) {
+func getStateCallback(states connection.StateHandler) func(openvpnState openvpn.State) {
 	return func(openvpnState openvpn.State) {
 		connectionState := openVpnStateCallbackToConnectionState(openvpnState)
 		if connectionState != connectionstate.Unknown {
-			stateChannel <- connectionState
+			states.Handle(connectionState)
 		}
 
-		// this is the last state - close channel (according to best practices of go - channel writer controls channel)
+		// this is the last state - close handler (according to best practices of go - channel writer controls channel)
 		if openvpnState == openvpn.ProcessExited {
-			close(stateChannel)
+			states.Close()
 		}
 	}
 }
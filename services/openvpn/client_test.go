@@ -32,7 +32,7 @@ func fakeSignerFactory(_ identity.Identity) identity.Signer {
 }
 
 func TestConnection_ErrorsOnInvalidConfig(t *testing.T) {
-	conn, err := NewClient("./", "./", "./", fakeSignerFactory, ip.NewResolverMock("1.1.1.1"))
+	conn, err := NewClient("./", "./", "./", connection.NewStateQueue(connection.DefaultStateQueueSize), fakeSignerFactory, ip.NewResolverMock("1.1.1.1"))
 	connectionOptions := connection.ConnectOptions{}
 	assert.Nil(t, err)
 	err = conn.Start(context.Background(), connectionOptions)
@@ -40,7 +40,7 @@ func TestConnection_ErrorsOnInvalidConfig(t *testing.T) {
 }
 
 func TestConnection_CreatesConnection(t *testing.T) {
-	conn, err := NewClient("./", "./", "./", fakeSignerFactory, ip.NewResolverMock("1.1.1.1"))
+	conn, err := NewClient("./", "./", "./", connection.NewStateQueue(connection.DefaultStateQueueSize), fakeSignerFactory, ip.NewResolverMock("1.1.1.1"))
 	assert.Nil(t, err)
 	assert.NotNil(t, conn)
 }
@@ -18,9 +18,11 @@
 package openvpn
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
+	"github.com/mysteriumnetwork/node/core/connection"
 	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
 	"github.com/stretchr/testify/assert"
 
@@ -28,19 +30,23 @@ import (
 )
 
 func TestGetStateCallbackReturnsCorrectState(t *testing.T) {
-	channel := make(chan connectionstate.State, 1)
-	callback := getStateCallback(channel)
+	states := connection.NewStateQueue(1)
+	callback := getStateCallback(states)
 	callback(openvpn.ConnectedState)
-	assert.Equal(t, connectionstate.Connected, <-channel)
+	state, _ := states.Next(context.Background())
+	assert.Equal(t, connectionstate.Connected, state)
 }
 
-func TestGetStateCallbackClosesChannelOnProcessExit(t *testing.T) {
-	channel := make(chan connectionstate.State, 1)
-	callback := getStateCallback(channel)
+func TestGetStateCallbackClosesHandlerOnProcessExit(t *testing.T) {
+	states := connection.NewStateQueue(2)
+	callback := getStateCallback(states)
 	callback(openvpn.ExitingState)
-	res, ok := <-channel
+	callback(openvpn.ProcessExited)
+	res, ok := states.Next(context.Background())
 	assert.Equal(t, connectionstate.Disconnecting, res)
 	assert.True(t, ok)
+	_, ok = states.Next(context.Background())
+	assert.False(t, ok)
 }
 
 func TestOpenVpnStateCallbackToConnectionState(t *testing.T) {
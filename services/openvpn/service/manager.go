@@ -38,6 +38,7 @@ import (
 	"github.com/mysteriumnetwork/node/dns"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/firewall"
+	"github.com/mysteriumnetwork/node/firewall/egress"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/nat"
@@ -141,6 +142,7 @@ func (m *Manager) Serve(instance *service.Instance) (err error) {
 		VPNNetwork:    m.vpnNetwork,
 		ProviderExtIP: net.ParseIP(m.outboundIP),
 		DNSIP:         m.dnsIP,
+		Egress:        egress.PolicyFromConfig(),
 	}); err != nil {
 		return fmt.Errorf("failed to setup NAT/firewall rules: %w", err)
 	}
@@ -46,7 +46,7 @@ type Options struct {
 }
 
 // NewConnection returns new WireGuard connection.
-func NewConnection(opts Options, ipResolver ip.Resolver, endpointFactory wg.EndpointFactory, handshakeWaiter HandshakeWaiter) (connection.Connection, error) {
+func NewConnection(opts Options, states connection.StateHandler, ipResolver ip.Resolver, endpointFactory wg.EndpointFactory, handshakeWaiter HandshakeWaiter) (connection.Connection, error) {
 	privateKey, err := key.GeneratePrivateKey()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not generate private key")
@@ -54,7 +54,7 @@ func NewConnection(opts Options, ipResolver ip.Resolver, endpointFactory wg.Endp
 
 	return &Connection{
 		done:                make(chan struct{}),
-		stateCh:             make(chan connectionstate.State, 100),
+		states:              states,
 		privateKey:          privateKey,
 		opts:                opts,
 		ipResolver:          ipResolver,
@@ -67,24 +67,22 @@ func NewConnection(opts Options, ipResolver ip.Resolver, endpointFactory wg.Endp
 type Connection struct {
 	stopOnce sync.Once
 	done     chan struct{}
-	stateCh  chan connectionstate.State
+	states   connection.StateHandler
 
 	ports               []int
 	privateKey          string
 	ipResolver          ip.Resolver
 	connectionEndpoint  wg.ConnectionEndpoint
+	deviceConfig        wgcfg.DeviceConfig
 	removeAllowedIPRule func()
+	stopDNSStub         func() error
 	opts                Options
 	connEndpointFactory wg.EndpointFactory
 	handshakeWaiter     HandshakeWaiter
 }
 
 var _ connection.Connection = &Connection{}
-
-// State returns connection state channel.
-func (c *Connection) State() <-chan connectionstate.State {
-	return c.stateCh
-}
+var _ connection.EndpointUpdater = &Connection{}
 
 // Statistics returns connection statistics channel.
 func (c *Connection) Statistics() (connectionstate.Statistics, error) {
@@ -128,7 +126,7 @@ func (c *Connection) start(ctx context.Context, start startConn, options connect
 		}
 	}()
 
-	c.stateCh <- connectionstate.Connecting
+	c.states.Handle(connectionstate.Connecting)
 
 	if options.ProviderNATConn != nil {
 		options.ProviderNATConn.Close()
@@ -137,14 +135,19 @@ func (c *Connection) start(ctx context.Context, start startConn, options connect
 	}
 
 	var dnsIPs []string
-	dnsIPs, err = options.Params.DNS.ResolveIPs(config.Consumer.DNSIPs)
+	dnsIPs, err = options.ResolveDNSIPs(config.Consumer.DNSIPs)
 	if err != nil {
 		return errors.Wrap(err, "could not resolve DNS IPs")
 	}
+	if options.UsesDNSStub() && c.stopDNSStub == nil {
+		c.stopDNSStub, err = options.StartDNSStub(config.Consumer.DNSIPs)
+		if err != nil {
+			return errors.Wrap(err, "could not start DNS stub")
+		}
+	}
 
 	log.Info().Msg("Starting new connection")
-	var conn wg.ConnectionEndpoint
-	conn, err = start(wgcfg.DeviceConfig{
+	c.deviceConfig = wgcfg.DeviceConfig{
 		IfaceName:    "", // Interface name will be generated by connection endpoint.
 		Subnet:       config.Consumer.IPAddress,
 		PrivateKey:   c.privateKey,
@@ -159,7 +162,9 @@ func (c *Connection) start(ctx context.Context, start startConn, options connect
 		},
 		ReplacePeers: true,
 		ProxyPort:    options.Params.ProxyPort,
-	})
+	}
+	var conn wg.ConnectionEndpoint
+	conn, err = start(c.deviceConfig)
 	if err != nil {
 		return errors.Wrap(err, "could not start new connection")
 	}
@@ -170,7 +175,7 @@ func (c *Connection) start(ctx context.Context, start startConn, options connect
 		return errors.Wrap(err, "failed while waiting for a peer handshake")
 	}
 
-	c.stateCh <- connectionstate.Connected
+	c.states.Handle(connectionstate.Connected)
 	return nil
 }
 
@@ -207,6 +212,32 @@ func (c *Connection) restartConn(conf wgcfg.DeviceConfig) (wg.ConnectionEndpoint
 	return conn, nil
 }
 
+// UpdateEndpoint points the established tunnel to a new provider IP, keeping the session and peer port.
+func (c *Connection) UpdateEndpoint(ip net.IP) error {
+	if c.connectionEndpoint == nil || c.deviceConfig.Peer.Endpoint == nil {
+		return errors.New("connection is not established")
+	}
+
+	removeAllowedIPRule, err := firewall.AllowIPAccess(ip.String())
+	if err != nil {
+		return errors.Wrap(err, "failed to add firewall exception for wireguard remote IP")
+	}
+
+	config := c.deviceConfig
+	config.Peer.Endpoint = &net.UDPAddr{IP: ip, Port: c.deviceConfig.Peer.Endpoint.Port}
+	if err := c.connectionEndpoint.ReconfigureConsumerMode(config); err != nil {
+		removeAllowedIPRule()
+		return fmt.Errorf("failed to update peer endpoint: %w", err)
+	}
+
+	if c.removeAllowedIPRule != nil {
+		c.removeAllowedIPRule()
+	}
+	c.removeAllowedIPRule = removeAllowedIPRule
+	c.deviceConfig = config
+	return nil
+}
+
 // GetConfig returns the consumer configuration for session creation
 func (c *Connection) GetConfig() (connection.ConsumerConfig, error) {
 	publicKey, err := key.PrivateKeyToPublicKey(c.privateKey)
@@ -224,7 +255,7 @@ func (c *Connection) GetConfig() (connection.ConsumerConfig, error) {
 func (c *Connection) Stop() {
 	c.stopOnce.Do(func() {
 		log.Info().Msg("Stopping WireGuard connection")
-		c.stateCh <- connectionstate.Disconnecting
+		c.states.Handle(connectionstate.Disconnecting)
 
 		if c.removeAllowedIPRule != nil {
 			c.removeAllowedIPRule()
@@ -236,9 +267,15 @@ func (c *Connection) Stop() {
 			}
 		}
 
-		c.stateCh <- connectionstate.NotConnected
+		if c.stopDNSStub != nil {
+			if err := c.stopDNSStub(); err != nil {
+				log.Error().Err(err).Msg("Failed to stop DNS stub")
+			}
+		}
+
+		c.states.Handle(connectionstate.NotConnected)
 
-		close(c.stateCh)
+		c.states.Close()
 		close(c.done)
 	})
 }
@@ -35,7 +35,7 @@ import (
 )
 
 func TestConnectionStartStop(t *testing.T) {
-	conn := newConn(t)
+	conn, states := newConn(t)
 
 	// Start connection.
 	sessionConfig, _ := json.Marshal(newServiceConfig())
@@ -45,8 +45,8 @@ func TestConnectionStartStop(t *testing.T) {
 	})
 
 	assert.NoError(t, err)
-	assert.Equal(t, connectionstate.Connecting, <-conn.State())
-	assert.Equal(t, connectionstate.Connected, <-conn.State())
+	assert.Equal(t, connectionstate.Connecting, nextState(states))
+	assert.Equal(t, connectionstate.Connected, nextState(states))
 	stats, err := conn.Statistics()
 	assert.NoError(t, err)
 	assert.EqualValues(t, 10, stats.BytesSent)
@@ -70,20 +70,20 @@ func TestConnectionStartStop(t *testing.T) {
 }
 
 func TestConnectionStopAfterHandshakeError(t *testing.T) {
-	conn := newConn(t)
+	conn, states := newConn(t)
 	handshakeTimeoutErr := errors.New("handshake timeout")
 	conn.handshakeWaiter = &mockHandshakeWaiter{err: handshakeTimeoutErr}
 	sessionConfig, _ := json.Marshal(newServiceConfig())
 
 	err := conn.Start(context.Background(), connection.ConnectOptions{SessionConfig: sessionConfig})
 	assert.Error(t, handshakeTimeoutErr, err)
-	assert.Equal(t, connectionstate.Connecting, <-conn.State())
-	assert.Equal(t, connectionstate.Disconnecting, <-conn.State())
-	assert.Equal(t, connectionstate.NotConnected, <-conn.State())
+	assert.Equal(t, connectionstate.Connecting, nextState(states))
+	assert.Equal(t, connectionstate.Disconnecting, nextState(states))
+	assert.Equal(t, connectionstate.NotConnected, nextState(states))
 }
 
 func TestConnectionStopOnceAfterHandshakeErrorAndStopCall(t *testing.T) {
-	conn := newConn(t)
+	conn, states := newConn(t)
 	handshakeTimeoutErr := errors.New("handshake timeout")
 	conn.handshakeWaiter = &mockHandshakeWaiter{err: handshakeTimeoutErr}
 	sessionConfig, _ := json.Marshal(newServiceConfig())
@@ -98,21 +98,35 @@ func TestConnectionStopOnceAfterHandshakeErrorAndStopCall(t *testing.T) {
 	<-stopCh
 
 	assert.Error(t, handshakeTimeoutErr, err)
-	assert.Equal(t, connectionstate.Connecting, <-conn.State())
-	assert.Equal(t, connectionstate.Disconnecting, <-conn.State())
-	assert.Equal(t, connectionstate.NotConnected, <-conn.State())
+	assert.Equal(t, connectionstate.Connecting, nextState(states))
+	assert.Equal(t, connectionstate.Disconnecting, nextState(states))
+	assert.Equal(t, connectionstate.NotConnected, nextState(states))
 }
 
-func newConn(t *testing.T) *Connection {
+func TestConnectionUpdateEndpoint(t *testing.T) {
+	conn, _ := newConn(t)
+	assert.Error(t, conn.UpdateEndpoint(net.ParseIP("1.2.3.4")))
+
+	sessionConfig, _ := json.Marshal(newServiceConfig())
+	err := conn.Start(context.Background(), connection.ConnectOptions{SessionConfig: sessionConfig})
+	assert.NoError(t, err)
+
+	assert.NoError(t, conn.UpdateEndpoint(net.ParseIP("1.2.3.4")))
+	assert.Equal(t, "1.2.3.4:51001", conn.deviceConfig.Peer.Endpoint.String())
+	conn.Stop()
+}
+
+func newConn(t *testing.T) (*Connection, *connection.StateQueue) {
 	endpointFactory := func() (wg.ConnectionEndpoint, error) {
 		return &mockConnectionEndpoint{}, nil
 	}
 	opts := Options{
 		DNSScriptDir: "/dns/dir",
 	}
-	conn, err := NewConnection(opts, ip.NewResolverMock("172.44.1.12"), endpointFactory, &mockHandshakeWaiter{})
+	states := connection.NewStateQueue(connection.DefaultStateQueueSize)
+	conn, err := NewConnection(opts, states, ip.NewResolverMock("172.44.1.12"), endpointFactory, &mockHandshakeWaiter{})
 	assert.NoError(t, err)
-	return conn.(*Connection)
+	return conn.(*Connection), states
 }
 
 func newServiceConfig() wg.ServiceConfig {
@@ -166,3 +180,8 @@ type mockHandshakeWaiter struct {
 func (m *mockHandshakeWaiter) Wait(ctx context.Context, statsFetch func() (wgcfg.Stats, error), timeout time.Duration, stop <-chan struct{}) error {
 	return m.err
 }
+
+func nextState(states *connection.StateQueue) connectionstate.State {
+	state, _ := states.Next(context.Background())
+	return state
+}
@@ -29,6 +29,8 @@ import (
 	"time"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/abuse"
+	"github.com/mysteriumnetwork/node/firewall/egress"
 
 	"github.com/rs/zerolog/log"
 	"golang.org/x/time/rate"
@@ -58,6 +60,8 @@ type netTun struct {
 
 	limiter           *rate.Limiter
 	privateIPv4Blocks []*net.IPNet
+	egress            egress.Policy
+	abuse             *abuse.Detector
 }
 
 type (
@@ -148,6 +152,8 @@ func CreateNetTUN(localAddresses []netip.Addr, dnsPort, mtu int) (tun.Device, *N
 		localAddresses:    localAddresses,
 		limiter:           limiter,
 		privateIPv4Blocks: privateIPv4Blocks,
+		egress:            egress.PolicyFromConfig(),
+		abuse:             abuse.Singleton(),
 	}
 
 	tcpFwd := tcp.NewForwarder(dev.stack, 0, 10000, dev.acceptTCP)
@@ -273,6 +279,17 @@ func (tun *netTun) acceptTCP(r *tcp.ForwarderRequest) {
 		return
 	}
 
+	if tun.isEgressBlocked(net.IP(reqDetails.LocalAddress), reqDetails.LocalPort) {
+		log.Debug().Msgf("Access is blocked by egress policy: %s:%d", reqDetails.LocalAddress, reqDetails.LocalPort)
+		r.Complete(true)
+		return
+	}
+
+	if !tun.abuse.AllowFrom(net.IP(reqDetails.RemoteAddress), net.IP(reqDetails.LocalAddress), reqDetails.LocalPort) {
+		r.Complete(true)
+		return
+	}
+
 	tun.addAddress(reqDetails.LocalAddress)
 
 	var wq waiter.Queue
@@ -333,6 +350,15 @@ func (tun *netTun) acceptUDP(req *udp.ForwarderRequest) {
 		return
 	}
 
+	if tun.isEgressBlocked(net.IP(sess.LocalAddress), sess.LocalPort) {
+		log.Debug().Msgf("Access is blocked by egress policy: %s:%d", sess.LocalAddress, sess.LocalPort)
+		return
+	}
+
+	if !tun.abuse.AllowFrom(net.IP(sess.RemoteAddress), net.IP(sess.LocalAddress), sess.LocalPort) {
+		return
+	}
+
 	tun.addAddress(sess.LocalAddress)
 
 	var wq waiter.Queue
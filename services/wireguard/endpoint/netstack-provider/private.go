@@ -50,3 +50,12 @@ func (tun *netTun) isPrivateIP(ip net.IP) bool {
 	}
 	return false
 }
+
+// isEgressBlocked checks whether egress policy blocks the destination, local address of the provider is always reachable.
+func (tun *netTun) isEgressBlocked(ip net.IP, port uint16) bool {
+	if tun.isLocal(tcpip.Address(ip)) {
+		return false
+	}
+
+	return tun.egress.Blocks(ip, int(port))
+}
@@ -28,12 +28,14 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/abuse"
 	"github.com/mysteriumnetwork/node/core/ip"
 	"github.com/mysteriumnetwork/node/core/service"
 	"github.com/mysteriumnetwork/node/core/shaper"
 	"github.com/mysteriumnetwork/node/dns"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/firewall"
+	"github.com/mysteriumnetwork/node/firewall/egress"
 	"github.com/mysteriumnetwork/node/nat"
 	wg "github.com/mysteriumnetwork/node/services/wireguard"
 	"github.com/mysteriumnetwork/node/services/wireguard/endpoint"
@@ -127,35 +129,57 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 		return nil, errors.Wrap(err, "could not get peer config")
 	}
 
-	var dnsIP net.IP
+	dnsIP := netutil.FirstIP(config.Consumer.IPAddress)
+	config.Consumer.DNSIPs = dnsIP.String()
+
+	// Flows forwarded in userspace are attributed to the session by the consumer address.
+	detector := abuse.Singleton()
+	detector.Bind(sessionID, config.Consumer.IPAddress.IP)
+
+	// Firewall and NAT rules, stats and shaping are set up only once consumer commits the session,
+	// so that consumers vanishing during negotiation leave nothing but the reserved tunnel behind.
+	var committedMu sync.Mutex
 	var releaseTrafficFirewall firewall.IncomingRuleRemove
-	if m.serviceInstance.Policies().HasDNSRules() {
-		releaseTrafficFirewall, err = m.trafficFirewall.BlockIncomingTraffic(providerConfig.Subnet)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to enable traffic blocking")
+	var natRules []interface{}
+	var natApplied bool
+	var stats *statsPublisher
+	var s shaper.Shaper
+	ifaceName := conn.InterfaceName()
+
+	commit := func() error {
+		committedMu.Lock()
+		defer committedMu.Unlock()
+
+		if m.serviceInstance.Policies().HasDNSRules() {
+			releaseTrafficFirewall, err = m.trafficFirewall.BlockIncomingTraffic(providerConfig.Subnet)
+			if err != nil {
+				return errors.Wrap(err, "failed to enable traffic blocking")
+			}
 		}
-	}
 
-	dnsIP = netutil.FirstIP(config.Consumer.IPAddress)
-	config.Consumer.DNSIPs = dnsIP.String()
+		natRules, err = m.natService.Setup(nat.Options{
+			VPNNetwork:    config.Consumer.IPAddress,
+			DNSIP:         dnsIP,
+			ProviderExtIP: net.ParseIP(m.outboundIP),
+			SessionID:     sessionID,
+			Egress:        egress.PolicyFromConfig(),
+			FlowRate:      detector.Limits().FlowRate(),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to setup NAT/firewall rules")
+		}
 
-	natRules, err := m.natService.Setup(nat.Options{
-		VPNNetwork:    config.Consumer.IPAddress,
-		DNSIP:         dnsIP,
-		ProviderExtIP: net.ParseIP(m.outboundIP),
-	})
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to setup NAT/firewall rules")
-	}
+		natApplied = true
 
-	statsPublisher := newStatsPublisher(m.eventBus, time.Second)
-	go statsPublisher.start(sessionID, conn)
+		publisher := newStatsPublisher(m.eventBus, time.Second)
+		stats = &publisher
+		go stats.start(sessionID, conn)
 
-	ifaceName := conn.InterfaceName()
-	s := shaper.New(m.eventBus)
-	err = s.Start(ifaceName)
-	if err != nil {
-		log.Error().Err(err).Msg("Could not start traffic shaper")
+		s = shaper.New(m.eventBus)
+		if err := s.Start(ifaceName); err != nil {
+			log.Error().Err(err).Msg("Could not start traffic shaper")
+		}
+		return nil
 	}
 
 	destroy := func() {
@@ -163,15 +187,23 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 		m.sessionCleanupMu.Lock()
 		_, ok := m.sessionCleanup[sessionID]
 		if !ok {
+			m.sessionCleanupMu.Unlock()
 			log.Info().Msgf("Session '%s' was already cleaned up, returning without changes", sessionID)
 			return
 		}
 		delete(m.sessionCleanup, sessionID)
 		m.sessionCleanupMu.Unlock()
 
-		statsPublisher.stop()
+		committedMu.Lock()
+		defer committedMu.Unlock()
 
-		s.Clear(ifaceName)
+		if stats != nil {
+			stats.stop()
+		}
+
+		if s != nil {
+			s.Clear(ifaceName)
+		}
 
 		if releaseTrafficFirewall != nil {
 			if err := releaseTrafficFirewall(); err != nil {
@@ -179,9 +211,11 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 			}
 		}
 
-		log.Trace().Msg("Deleting nat rules")
-		if err := m.natService.Del(natRules); err != nil {
-			log.Error().Err(err).Msg("Failed to delete NAT rules")
+		if natApplied {
+			log.Trace().Msg("Deleting nat rules")
+			if err := m.natService.Del(natRules); err != nil {
+				log.Error().Err(err).Msg("Failed to delete NAT rules")
+			}
 		}
 
 		log.Trace().Msg("Stopping connection endpoint")
@@ -198,7 +232,7 @@ func (m *Manager) ProvideConfig(sessionID string, sessionConfig json.RawMessage,
 	m.sessionCleanup[sessionID] = destroy
 	m.sessionCleanupMu.Unlock()
 
-	return &service.ConfigParams{SessionServiceConfig: config, SessionDestroyCallback: destroy}, nil
+	return &service.ConfigParams{SessionServiceConfig: config, SessionCommitCallback: commit, SessionDestroyCallback: destroy}, nil
 }
 
 func (m *Manager) createProviderConfig(listenPort int, peerPublicKey string) (wgcfg.DeviceConfig, error) {
@@ -0,0 +1,311 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accounting
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+)
+
+// AppTopicTrafficCap is the topic the monthly traffic cap being reached and reset is published to.
+const AppTopicTrafficCap = "TrafficCap"
+
+// Actions taken when the monthly traffic cap is reached.
+const (
+	// CapActionReject rejects new sessions, established ones are kept.
+	CapActionReject = "reject"
+	// CapActionStop rejects new sessions and stops running services.
+	CapActionStop = "stop"
+)
+
+const trafficFlushInterval = time.Minute
+
+// CapConfig limits provider traffic within a month.
+type CapConfig struct {
+	// MonthlyBytes is the traffic allowed in a month, it is unlimited if zero.
+	MonthlyBytes uint64
+	// Action is either CapActionReject or CapActionStop.
+	Action string
+	// BillingDay is the day of month, from 1 to 28, the monthly traffic is counted from.
+	BillingDay int
+}
+
+// AppEventTrafficCap is published when the monthly traffic cap is reached, and when it is reset by a new month.
+type AppEventTrafficCap struct {
+	Used    uint64
+	Limit   uint64
+	Reached bool
+}
+
+// TrafficTotals is the traffic of all provider sessions in the current day and month.
+type TrafficTotals struct {
+	Today      DailyTraffic
+	MonthStart time.Time
+	Month      Counters
+	Cap        CapConfig
+	CapReached bool
+}
+
+// TrafficMeter aggregates traffic of all provider sessions into daily and monthly totals,
+// so that operators on metered connections can stay within their monthly allowance.
+type TrafficMeter struct {
+	storage      *TrafficStorage
+	cap          CapConfig
+	publisher    eventbus.Publisher
+	stopServices func() error
+	now          func() time.Time
+
+	mu         sync.Mutex
+	sessions   map[string]Counters
+	today      DailyTraffic
+	dirty      bool
+	monthStart time.Time
+	// month is the traffic of the current month, excluding today.
+	month      Counters
+	capReached bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTrafficMeter returns a new traffic meter. Services are stopped with stopServices when the cap is
+// reached and the cap action is CapActionStop, they are not restarted by a new month.
+func NewTrafficMeter(storage *TrafficStorage, cap CapConfig, publisher eventbus.Publisher, stopServices func() error) *TrafficMeter {
+	if cap.BillingDay < 1 || cap.BillingDay > 28 {
+		cap.BillingDay = 1
+	}
+	return &TrafficMeter{
+		storage:      storage,
+		cap:          cap,
+		publisher:    publisher,
+		stopServices: stopServices,
+		now:          time.Now,
+		sessions:     make(map[string]Counters),
+		stop:         make(chan struct{}),
+	}
+}
+
+// Load restores the traffic of the current day and month from storage.
+func (m *TrafficMeter) Load() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.monthStart = monthStart(now, m.cap.BillingDay)
+	m.today = newDailyTraffic(now)
+	m.month = Counters{}
+
+	days, err := m.storage.Since(m.monthStart)
+	if err != nil {
+		return err
+	}
+	for _, day := range days {
+		if day.Day == m.today.Day {
+			m.today = day
+			continue
+		}
+		m.month.Sent += day.Sent
+		m.month.Received += day.Received
+	}
+	m.capReached = m.cap.MonthlyBytes > 0 && m.monthTotal() >= m.cap.MonthlyBytes
+	return nil
+}
+
+// Subscribe subscribes to session traffic and lifecycle events.
+func (m *TrafficMeter) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(sessionEvent.AppTopicDataTransferred, m.consumeDataTransferred); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(sessionEvent.AppTopicSession, m.consumeSession)
+}
+
+// Start starts saving traffic totals periodically.
+func (m *TrafficMeter) Start() {
+	go func() {
+		ticker := time.NewTicker(trafficFlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				m.mu.Lock()
+				m.advance(m.now())
+				m.flush()
+				m.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// Stop stops the meter and saves traffic totals.
+func (m *TrafficMeter) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.flush()
+	})
+}
+
+// CapReached returns true if the traffic of the current month has reached the cap.
+func (m *TrafficMeter) CapReached() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.advance(m.now())
+	return m.capReached
+}
+
+// Totals returns the traffic of the current day and month.
+func (m *TrafficMeter) Totals() TrafficTotals {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.advance(m.now())
+	return TrafficTotals{
+		Today:      m.today,
+		MonthStart: m.monthStart,
+		Month:      Counters{Sent: m.month.Sent + m.today.Sent, Received: m.month.Received + m.today.Received},
+		Cap:        m.cap,
+		CapReached: m.capReached,
+	}
+}
+
+// History returns the traffic of the last days, oldest first.
+func (m *TrafficMeter) History(days int) ([]DailyTraffic, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.advance(now)
+	m.flush()
+	return m.storage.Since(newDailyTraffic(now).Date.AddDate(0, 0, 1-days))
+}
+
+func (m *TrafficMeter) consumeDataTransferred(e sessionEvent.AppEventDataTransferred) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.advance(m.now())
+
+	// Session counters are cumulative, they start over if the session tunnel is recreated.
+	prev := m.sessions[e.ID]
+	current := Counters{Sent: e.Up, Received: e.Down}
+	m.sessions[e.ID] = current
+	if current.Sent < prev.Sent || current.Received < prev.Received {
+		prev = Counters{}
+	}
+	m.today.Sent += current.Sent - prev.Sent
+	m.today.Received += current.Received - prev.Received
+	m.dirty = true
+
+	m.checkCap()
+}
+
+func (m *TrafficMeter) consumeSession(e sessionEvent.AppEventSession) {
+	if e.Status != sessionEvent.RemovedStatus {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, e.Session.ID)
+}
+
+// advance moves totals to the day and month of the given time.
+func (m *TrafficMeter) advance(now time.Time) {
+	day := newDailyTraffic(now)
+	if day.Day == m.today.Day {
+		return
+	}
+
+	m.flush()
+	m.month.Sent += m.today.Sent
+	m.month.Received += m.today.Received
+	m.today = day
+
+	start := monthStart(now, m.cap.BillingDay)
+	if start.Equal(m.monthStart) {
+		return
+	}
+	m.monthStart = start
+	m.month = Counters{}
+	if m.capReached {
+		m.capReached = false
+		log.Info().Msg("Monthly traffic cap is reset, new sessions are accepted")
+		m.publisher.Publish(AppTopicTrafficCap, AppEventTrafficCap{Used: 0, Limit: m.cap.MonthlyBytes, Reached: false})
+	}
+}
+
+func (m *TrafficMeter) checkCap() {
+	if m.cap.MonthlyBytes == 0 || m.capReached {
+		return
+	}
+	used := m.monthTotal()
+	if used < m.cap.MonthlyBytes {
+		return
+	}
+
+	m.capReached = true
+	log.Warn().Msgf("Monthly traffic cap of %d bytes is reached, new sessions are rejected", m.cap.MonthlyBytes)
+	m.publisher.Publish(AppTopicTrafficCap, AppEventTrafficCap{Used: used, Limit: m.cap.MonthlyBytes, Reached: true})
+
+	if m.cap.Action == CapActionStop && m.stopServices != nil {
+		// Stopping services publishes events, so it must not block while the meter is locked.
+		go func() {
+			log.Warn().Msg("Stopping services as the monthly traffic cap is reached")
+			if err := m.stopServices(); err != nil {
+				log.Error().Err(err).Msg("Failed to stop services")
+			}
+		}()
+	}
+}
+
+func (m *TrafficMeter) flush() {
+	if !m.dirty {
+		return
+	}
+	if err := m.storage.Store(m.today); err != nil {
+		log.Warn().Err(err).Msg("Failed to save provider traffic")
+		return
+	}
+	m.dirty = false
+}
+
+func (m *TrafficMeter) monthTotal() uint64 {
+	return m.month.Total() + m.today.Total()
+}
+
+// monthStart returns the start of the billing month the given time is in.
+func monthStart(t time.Time, billingDay int) time.Time {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), billingDay, 0, 0, 0, 0, time.UTC)
+	if t.Before(start) {
+		start = start.AddDate(0, -1, 0)
+	}
+	return start
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accounting
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/storage/memory"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+)
+
+type mockPublisher struct {
+	mu     sync.Mutex
+	events []AppEventTrafficCap
+}
+
+func (p *mockPublisher) Publish(topic string, data interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if topic == AppTopicTrafficCap {
+		p.events = append(p.events, data.(AppEventTrafficCap))
+	}
+}
+
+func TestTrafficMeter_AggregatesSessions(t *testing.T) {
+	now := time.Date(2022, 5, 14, 23, 0, 0, 0, time.UTC)
+	storage := NewTrafficStorage(memory.NewStorage())
+	require.NoError(t, storage.Store(DailyTraffic{Day: "2022-05-10", Date: time.Date(2022, 5, 10, 0, 0, 0, 0, time.UTC), Sent: 1000, Received: 100}))
+	require.NoError(t, storage.Store(DailyTraffic{Day: "2022-05-01", Date: time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC), Sent: 5000}))
+
+	meter := NewTrafficMeter(storage, CapConfig{BillingDay: 5}, &mockPublisher{}, nil)
+	meter.now = func() time.Time { return now }
+	require.NoError(t, meter.Load())
+
+	meter.consumeDataTransferred(sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 10, Down: 20})
+	meter.consumeDataTransferred(sessionEvent.AppEventDataTransferred{ID: "session-2", Up: 5, Down: 5})
+	meter.consumeDataTransferred(sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 30, Down: 40})
+
+	totals := meter.Totals()
+	assert.Equal(t, DailyTraffic{Day: "2022-05-14", Date: time.Date(2022, 5, 14, 0, 0, 0, 0, time.UTC), Sent: 35, Received: 45}, totals.Today)
+	assert.Equal(t, time.Date(2022, 5, 5, 0, 0, 0, 0, time.UTC), totals.MonthStart)
+	assert.Equal(t, Counters{Sent: 1035, Received: 145}, totals.Month)
+
+	// Traffic of the previous day is saved when the day changes.
+	now = now.Add(2 * time.Hour)
+	meter.consumeDataTransferred(sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 31, Down: 40})
+
+	history, err := meter.History(2)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, uint64(80), history[0].Total())
+	assert.Equal(t, DailyTraffic{Day: "2022-05-15", Date: time.Date(2022, 5, 15, 0, 0, 0, 0, time.UTC), Sent: 1}, history[1])
+	assert.Equal(t, Counters{Sent: 1036, Received: 145}, meter.Totals().Month)
+}
+
+func TestTrafficMeter_Cap(t *testing.T) {
+	now := time.Date(2022, 5, 31, 12, 0, 0, 0, time.UTC)
+	publisher := &mockPublisher{}
+	stopped := make(chan struct{})
+	meter := NewTrafficMeter(NewTrafficStorage(memory.NewStorage()), CapConfig{MonthlyBytes: 100, Action: CapActionStop}, publisher, func() error {
+		close(stopped)
+		return nil
+	})
+	meter.now = func() time.Time { return now }
+	require.NoError(t, meter.Load())
+
+	meter.consumeDataTransferred(sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 50, Down: 49})
+	assert.False(t, meter.CapReached())
+
+	meter.consumeDataTransferred(sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 50, Down: 50})
+	assert.True(t, meter.CapReached())
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("services were not stopped")
+	}
+
+	// Cap is reset by a new month.
+	now = now.Add(24 * time.Hour)
+	assert.False(t, meter.CapReached())
+	assert.Equal(t, []AppEventTrafficCap{
+		{Used: 100, Limit: 100, Reached: true},
+		{Used: 0, Limit: 100, Reached: false},
+	}, publisher.events)
+}
+
+func TestTrafficMeter_RestoresCapReached(t *testing.T) {
+	now := time.Date(2022, 5, 31, 12, 0, 0, 0, time.UTC)
+	storage := NewTrafficStorage(memory.NewStorage())
+	require.NoError(t, storage.Store(DailyTraffic{Day: "2022-05-30", Date: time.Date(2022, 5, 30, 0, 0, 0, 0, time.UTC), Received: 100}))
+
+	meter := NewTrafficMeter(storage, CapConfig{MonthlyBytes: 100}, &mockPublisher{}, nil)
+	meter.now = func() time.Time { return now }
+	require.NoError(t, meter.Load())
+	assert.True(t, meter.CapReached())
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accounting
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/nat"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+)
+
+// Counters is the session traffic, from the provider point of view.
+type Counters struct {
+	Sent     uint64
+	Received uint64
+}
+
+// Total returns bytes sent and received.
+func (c Counters) Total() uint64 {
+	return c.Sent + c.Received
+}
+
+// Entry compares traffic of a single session reported by the tunnel with the one counted by the kernel.
+type Entry struct {
+	SessionID string
+	Tunnel    Counters
+	Kernel    Counters
+	// SentDrift and ReceivedDrift are relative differences of tunnel counters from kernel ones.
+	SentDrift     float64
+	ReceivedDrift float64
+}
+
+// Report is the reconciliation of tunnel and kernel session traffic counters.
+type Report struct {
+	GeneratedAt time.Time
+	Sessions    []Entry
+	// KernelError explains why kernel counters are missing from the report.
+	KernelError string
+}
+
+// Reconciler tracks tunnel counters of provider sessions and compares them with kernel counters.
+type Reconciler struct {
+	kernel nat.SessionAccounter
+	now    func() time.Time
+
+	mu     sync.Mutex
+	tunnel map[string]Counters
+}
+
+// NewReconciler returns a new reconciler. Kernel counters are omitted when kernel accounter is nil.
+func NewReconciler(kernel nat.SessionAccounter) *Reconciler {
+	return &Reconciler{
+		kernel: kernel,
+		now:    time.Now,
+		tunnel: make(map[string]Counters),
+	}
+}
+
+// Subscribe subscribes to session traffic and lifecycle events.
+func (r *Reconciler) Subscribe(bus eventbus.Subscriber) error {
+	if err := bus.SubscribeAsync(sessionEvent.AppTopicDataTransferred, r.consumeDataTransferred); err != nil {
+		return err
+	}
+	return bus.SubscribeAsync(sessionEvent.AppTopicSession, r.consumeSession)
+}
+
+func (r *Reconciler) consumeDataTransferred(e sessionEvent.AppEventDataTransferred) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tunnel[e.ID] = Counters{Sent: e.Up, Received: e.Down}
+}
+
+func (r *Reconciler) consumeSession(e sessionEvent.AppEventSession) {
+	if e.Status != sessionEvent.RemovedStatus {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.tunnel, e.Session.ID)
+}
+
+// Report compares tunnel counters of active sessions with kernel ones.
+func (r *Reconciler) Report() Report {
+	report := Report{GeneratedAt: r.now()}
+
+	var kernel map[string]nat.SessionCounters
+	if r.kernel == nil {
+		report.KernelError = "kernel accounting is not supported"
+	} else if counters, err := r.kernel.SessionCounters(); err != nil {
+		report.KernelError = err.Error()
+	} else {
+		kernel = counters
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, tunnel := range r.tunnel {
+		entry := Entry{SessionID: id, Tunnel: tunnel}
+		if k, ok := kernel[id]; ok {
+			entry.Kernel = Counters{Sent: k.Sent, Received: k.Received}
+			entry.SentDrift = drift(tunnel.Sent, k.Sent)
+			entry.ReceivedDrift = drift(tunnel.Received, k.Received)
+		}
+		report.Sessions = append(report.Sessions, entry)
+	}
+	sort.Slice(report.Sessions, func(i, j int) bool {
+		return report.Sessions[i].SessionID < report.Sessions[j].SessionID
+	})
+
+	return report
+}
+
+func drift(tunnel, kernel uint64) float64 {
+	if kernel == 0 {
+		return 0
+	}
+	return (float64(tunnel) - float64(kernel)) / float64(kernel)
+}
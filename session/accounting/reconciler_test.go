@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accounting
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/nat"
+	sessionEvent "github.com/mysteriumnetwork/node/session/event"
+)
+
+type mockAccounter struct {
+	counters map[string]nat.SessionCounters
+	err      error
+}
+
+func (m *mockAccounter) SessionCounters() (map[string]nat.SessionCounters, error) {
+	return m.counters, m.err
+}
+
+func TestReconciler_Report(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	reconciler := NewReconciler(&mockAccounter{counters: map[string]nat.SessionCounters{
+		"session-1": {Sent: 1000, Received: 200},
+	}})
+	reconciler.now = func() time.Time { return now }
+
+	reconciler.consumeDataTransferred(sessionEvent.AppEventDataTransferred{ID: "session-2", Up: 10, Down: 20})
+	reconciler.consumeDataTransferred(sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 1100, Down: 150})
+
+	assert.Equal(t, Report{
+		GeneratedAt: now,
+		Sessions: []Entry{
+			{
+				SessionID:     "session-1",
+				Tunnel:        Counters{Sent: 1100, Received: 150},
+				Kernel:        Counters{Sent: 1000, Received: 200},
+				SentDrift:     0.1,
+				ReceivedDrift: -0.25,
+			},
+			{
+				SessionID: "session-2",
+				Tunnel:    Counters{Sent: 10, Received: 20},
+			},
+		},
+	}, reconciler.Report())
+}
+
+func TestReconciler_ForgetsRemovedSessions(t *testing.T) {
+	reconciler := NewReconciler(nil)
+	reconciler.consumeDataTransferred(sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 10, Down: 20})
+	reconciler.consumeSession(sessionEvent.AppEventSession{
+		Status:  sessionEvent.RemovedStatus,
+		Session: sessionEvent.SessionContext{ID: "session-1"},
+	})
+
+	report := reconciler.Report()
+	assert.Empty(t, report.Sessions)
+	assert.NotEmpty(t, report.KernelError)
+}
+
+func TestReconciler_ReportsKernelError(t *testing.T) {
+	reconciler := NewReconciler(&mockAccounter{err: errors.New("iptables failed")})
+	reconciler.consumeDataTransferred(sessionEvent.AppEventDataTransferred{ID: "session-1", Up: 10, Down: 20})
+
+	report := reconciler.Report()
+	assert.Equal(t, "iptables failed", report.KernelError)
+	assert.Equal(t, []Entry{{SessionID: "session-1", Tunnel: Counters{Sent: 10, Received: 20}}}, report.Sessions)
+}
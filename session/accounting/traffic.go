@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package accounting
+
+import (
+	"errors"
+	"time"
+
+	"github.com/asdine/storm/v3/q"
+
+	"github.com/mysteriumnetwork/node/core/storage"
+)
+
+const (
+	trafficBucket = "provider-traffic"
+	dayLayout     = "2006-01-02"
+)
+
+// DailyTraffic is the traffic of all provider sessions within a UTC day.
+type DailyTraffic struct {
+	// Day is formatted as 2006-01-02.
+	Day      string `storm:"id"`
+	Date     time.Time
+	Sent     uint64
+	Received uint64
+}
+
+// Total returns bytes sent and received.
+func (t DailyTraffic) Total() uint64 {
+	return t.Sent + t.Received
+}
+
+// TrafficStorage keeps daily provider traffic totals.
+type TrafficStorage struct {
+	storage storage.Store
+}
+
+// NewTrafficStorage returns a new instance of TrafficStorage.
+func NewTrafficStorage(storage storage.Store) *TrafficStorage {
+	return &TrafficStorage{
+		storage: storage,
+	}
+}
+
+// Store stores traffic of a day, replacing the stored one.
+func (ts *TrafficStorage) Store(day DailyTraffic) error {
+	return ts.storage.Store(trafficBucket, &day)
+}
+
+// Since returns traffic of days starting at or after the given time, oldest first.
+func (ts *TrafficStorage) Since(from time.Time) (result []DailyTraffic, err error) {
+	query := storage.Query{
+		Where:   q.Gte("Date", from.UTC()),
+		OrderBy: "Date",
+	}
+
+	err = ts.storage.Find(trafficBucket, query, &result)
+	if errors.Is(err, storage.ErrNotFound) {
+		return []DailyTraffic{}, nil
+	}
+	return result, err
+}
+
+// PruneBefore deletes traffic of days older than the given time.
+func (ts *TrafficStorage) PruneBefore(before time.Time) error {
+	return ts.storage.DeleteMatching(trafficBucket, q.Lt("Date", before.UTC()), new(DailyTraffic))
+}
+
+func newDailyTraffic(t time.Time) DailyTraffic {
+	t = t.UTC()
+	return DailyTraffic{
+		Day:  t.Format(dayLayout),
+		Date: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC),
+	}
+}
@@ -34,6 +34,8 @@ const (
 	AppTopicDataTransferred = "Session data transferred"
 	// AppTopicTokensEarned is a topic for publish events about tokens earned as a provider.
 	AppTopicTokensEarned = "SessionTokensEarned"
+	// AppTopicPaymentFailed is a topic for publish events about failed session payments as a provider.
+	AppTopicPaymentFailed = "Session payment failed"
 )
 
 // AppEventDataTransferred represents the data transfer event
@@ -49,6 +51,12 @@ type AppEventTokensEarned struct {
 	Total      *big.Int
 }
 
+// AppEventPaymentFailed is published when payments of the provider session fail
+type AppEventPaymentFailed struct {
+	SessionID string
+	Error     string
+}
+
 // Status represents the different actions that might happen on a session
 type Status string
 
@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package notice
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+const (
+	// AppTopicNoticeReceived is a topic on which notices received from provider are published.
+	AppTopicNoticeReceived = "session-notice-received"
+
+	// MaxMessageLength is a maximum notice message length in bytes.
+	MaxMessageLength = 256
+	// DefaultInterval is a minimum interval between notices within the same session.
+	DefaultInterval = 10 * time.Second
+)
+
+var (
+	// ErrEmptyMessage is returned when notice message is blank.
+	ErrEmptyMessage = errors.New("notice message is empty")
+	// ErrMessageTooLong is returned when notice message exceeds MaxMessageLength.
+	ErrMessageTooLong = errors.New("notice message is too long")
+	// ErrRateLimited is returned when notices are sent too often within a session.
+	ErrRateLimited = errors.New("notice rate limit exceeded")
+)
+
+// Notice is a short human-readable message pushed by provider to a connected consumer.
+type Notice struct {
+	PeerID       identity.Identity
+	SessionID    string
+	Message      string
+	CreatedAtUTC time.Time
+}
+
+// AppEventNoticeReceived is published when consumer receives a notice from provider.
+type AppEventNoticeReceived struct {
+	Notice Notice
+}
+
+// Validate checks that notice message is not empty and fits into the size cap.
+func Validate(message string) error {
+	if strings.TrimSpace(message) == "" {
+		return ErrEmptyMessage
+	}
+	if len(message) > MaxMessageLength {
+		return ErrMessageTooLong
+	}
+	return nil
+}
+
+// Truncate cuts message to MaxMessageLength without breaking multi-byte characters.
+func Truncate(message string) string {
+	if len(message) <= MaxMessageLength {
+		return message
+	}
+	cut := MaxMessageLength
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut]
+}
+
+// Limiter allows a single notice per interval for every session.
+type Limiter struct {
+	interval time.Duration
+	now      func() time.Time
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// NewLimiter returns a new instance of Limiter.
+func NewLimiter(interval time.Duration) *Limiter {
+	return &Limiter{
+		interval: interval,
+		now:      time.Now,
+		last:     make(map[string]time.Time),
+	}
+}
+
+// Allow reports whether a notice can be delivered within the given session now.
+func (l *Limiter) Allow(sessionID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if last, ok := l.last[sessionID]; ok && now.Sub(last) < l.interval {
+		return false
+	}
+	l.last[sessionID] = now
+	return true
+}
+
+// Forget removes session from the limiter.
+func (l *Limiter) Forget(sessionID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.last, sessionID)
+}
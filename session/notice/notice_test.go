@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package notice
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("Maintenance in 5 minutes"))
+	assert.Equal(t, ErrEmptyMessage, Validate("  "))
+	assert.Equal(t, ErrMessageTooLong, Validate(strings.Repeat("a", MaxMessageLength+1)))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", Truncate("short"))
+
+	truncated := Truncate(strings.Repeat("a", MaxMessageLength-1) + "ą")
+	assert.Equal(t, strings.Repeat("a", MaxMessageLength-1), truncated)
+	assert.NoError(t, Validate(truncated))
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Now()
+	limiter := NewLimiter(time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	assert.True(t, limiter.Allow("s1"))
+	assert.False(t, limiter.Allow("s1"))
+	assert.True(t, limiter.Allow("s2"))
+
+	now = now.Add(time.Minute)
+	assert.True(t, limiter.Allow("s1"))
+
+	limiter.Forget("s2")
+	assert.True(t, limiter.Allow("s2"))
+}
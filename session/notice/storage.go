@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package notice
+
+import (
+	"sync"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+)
+
+// maxEntries is a number of the latest notices kept in memory.
+const maxEntries = 50
+
+// Storage keeps the latest notices received from providers.
+type Storage struct {
+	mu      sync.RWMutex
+	entries []Notice
+}
+
+// NewStorage returns new Storage instance.
+func NewStorage() *Storage {
+	return &Storage{}
+}
+
+// Subscribe subscribes storage to received notices.
+func (s *Storage) Subscribe(bus eventbus.Subscriber) error {
+	return bus.SubscribeAsync(AppTopicNoticeReceived, s.consumeNoticeEvent)
+}
+
+func (s *Storage) consumeNoticeEvent(e AppEventNoticeReceived) {
+	s.Add(e.Notice)
+}
+
+// Add stores a notice, dropping the oldest one if storage is full.
+func (s *Storage) Add(n Notice) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, n)
+	if len(s.entries) > maxEntries {
+		s.entries = s.entries[len(s.entries)-maxEntries:]
+	}
+}
+
+// List returns stored notices, newest first.
+func (s *Storage) List() []Notice {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]Notice, 0, len(s.entries))
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		res = append(res, s.entries[i])
+	}
+	return res
+}
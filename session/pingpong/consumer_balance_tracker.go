@@ -33,6 +33,7 @@ import (
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/money/moneymath"
 	pevent "github.com/mysteriumnetwork/node/pilvytis"
 	"github.com/mysteriumnetwork/node/session/pingpong/event"
 	"github.com/mysteriumnetwork/payments/client"
@@ -359,23 +360,20 @@ func (cbt *ConsumerBalanceTracker) alignWithHermes(chainID int64, id identity.Id
 		if err != nil {
 			var syntax *json.SyntaxError
 			if errors.As(err, &syntax) {
-				cancel()
 				log.Err(err).Msg("hermes response is malformed JSON can't check if offchain")
-				return err
+				return backoff.Permanent(err)
 			}
 
 			if errors.Is(err, ErrHermesNotFound) {
-				// Hermes doesn't know about this identity meaning it's not offchain. Cancel.
-				cancel()
-				return errBalanceNotOffchain
+				// Hermes doesn't know about this identity meaning it's not offchain. Stop retrying.
+				return backoff.Permanent(errBalanceNotOffchain)
 			}
 
 			return err
 		}
 		if !consumer.IsOffchain {
-			// Hermes knows about this identity, but it's not offchain. Cancel.
-			cancel()
-			return errBalanceNotOffchain
+			// Hermes knows about this identity, but it's not offchain. Stop retrying.
+			return backoff.Permanent(errBalanceNotOffchain)
 		}
 
 		if consumer.LatestPromise.Amount != nil {
@@ -762,17 +760,6 @@ func (cbt *ConsumerBalanceTracker) identityRegistrationStatus(ctx context.Contex
 	return data, backoff.Retry(toRetry, boff)
 }
 
-func safeSub(a, b *big.Int) *big.Int {
-	if a == nil || b == nil {
-		return new(big.Int)
-	}
-
-	if a.Cmp(b) >= 0 {
-		return new(big.Int).Sub(a, b)
-	}
-	return new(big.Int)
-}
-
 // ConsumerBalance represents the consumer balance
 type ConsumerBalance struct {
 	BCBalance          *big.Int
@@ -804,5 +791,5 @@ func (cb ConsumerBalance) OffchainNeedsSync() bool {
 // GetBalance returns the current balance
 func (cb ConsumerBalance) GetBalance() *big.Int {
 	// Balance (to spend) = BCBalance - (hermesPromised - BCSettled)
-	return safeSub(cb.BCBalance, safeSub(cb.GrandTotalPromised, cb.BCSettled))
+	return moneymath.SubFloor(cb.BCBalance, moneymath.SubFloor(cb.GrandTotalPromised, cb.BCSettled))
 }
@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+// ConsumerPaymentHistory remembers how promptly consumers pay invoices, so that
+// new sessions of consumers with a good track record skip the small invoice warm up.
+type ConsumerPaymentHistory struct {
+	promptLatency time.Duration
+	trustAfter    int
+
+	mu        sync.Mutex
+	consumers map[string]*consumerPayments
+}
+
+type consumerPayments struct {
+	promptInRow  int
+	chargePeriod time.Duration
+	maxUnpaid    *big.Int
+}
+
+// NewConsumerPaymentHistory returns a new consumer payment history. Payments faster than the prompt latency
+// count as prompt, and trustAfter prompt payments in a row make the consumer trusted.
+func NewConsumerPaymentHistory(promptLatency time.Duration, trustAfter int) *ConsumerPaymentHistory {
+	return &ConsumerPaymentHistory{
+		promptLatency: promptLatency,
+		trustAfter:    trustAfter,
+		consumers:     make(map[string]*consumerPayments),
+	}
+}
+
+// recordPayment records the invoice payment latency and returns whether the payment was prompt.
+func (h *ConsumerPaymentHistory) recordPayment(consumer identity.Identity, latency time.Duration) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	payments := h.get(consumer)
+	prompt := latency <= h.promptLatency
+	if prompt {
+		payments.promptInRow++
+	} else {
+		payments.promptInRow = 0
+	}
+
+	return prompt
+}
+
+// recordMissedPayment makes the consumer start from the smallest invoice window again.
+func (h *ConsumerPaymentHistory) recordMissedPayment(consumer identity.Identity) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.consumers, consumer.Address)
+}
+
+// rememberWindow stores the invoice window reached by the consumer session.
+func (h *ConsumerPaymentHistory) rememberWindow(consumer identity.Identity, chargePeriod time.Duration, maxUnpaid *big.Int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	payments := h.get(consumer)
+	payments.chargePeriod = chargePeriod
+	if maxUnpaid != nil {
+		payments.maxUnpaid = new(big.Int).Set(maxUnpaid)
+	}
+}
+
+// startingWindow returns the invoice window a new session of a trusted consumer starts with.
+func (h *ConsumerPaymentHistory) startingWindow(consumer identity.Identity) (time.Duration, *big.Int, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	payments, ok := h.consumers[consumer.Address]
+	if !ok || payments.promptInRow < h.trustAfter || payments.chargePeriod == 0 {
+		return 0, nil, false
+	}
+
+	var maxUnpaid *big.Int
+	if payments.maxUnpaid != nil {
+		maxUnpaid = new(big.Int).Set(payments.maxUnpaid)
+	}
+	return payments.chargePeriod, maxUnpaid, true
+}
+
+func (h *ConsumerPaymentHistory) get(consumer identity.Identity) *consumerPayments {
+	payments, ok := h.consumers[consumer.Address]
+	if !ok {
+		payments = &consumerPayments{}
+		h.consumers[consumer.Address] = payments
+	}
+	return payments
+}
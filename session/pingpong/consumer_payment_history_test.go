@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+func newWindowTracker(history *ConsumerPaymentHistory) *InvoiceTracker {
+	return NewInvoiceTracker(InvoiceTrackerDeps{
+		Peer:                identity.FromAddress("0x1"),
+		ChargePeriod:        30 * time.Second,
+		LimitChargePeriod:   time.Minute,
+		MaxNotPaidInvoice:   big.NewInt(300),
+		LimitNotPaidInvoice: big.NewInt(1000),
+		PaymentHistory:      history,
+	})
+}
+
+func TestInvoiceTracker_WindowGrowsWithPromptPayments(t *testing.T) {
+	tracker := newWindowTracker(NewConsumerPaymentHistory(time.Second, 10))
+
+	tracker.adaptInvoiceWindow(100 * time.Millisecond)
+	assert.Equal(t, 40*time.Second, tracker.deps.ChargePeriod)
+	assert.Equal(t, big.NewInt(400), tracker.deps.MaxNotPaidInvoice)
+
+	for i := 0; i < 10; i++ {
+		tracker.adaptInvoiceWindow(100 * time.Millisecond)
+	}
+	assert.Equal(t, time.Minute, tracker.deps.ChargePeriod, "charge period should grow up to limit")
+	assert.Equal(t, big.NewInt(1000), tracker.deps.MaxNotPaidInvoice, "max unpaid invoice should grow up to limit")
+}
+
+func TestInvoiceTracker_WindowShrinksWithSlowPayments(t *testing.T) {
+	tracker := newWindowTracker(NewConsumerPaymentHistory(time.Second, 10))
+	tracker.deps.ChargePeriod = time.Minute
+	tracker.deps.MaxNotPaidInvoice = big.NewInt(900)
+
+	tracker.adaptInvoiceWindow(5 * time.Second)
+	assert.Equal(t, 40*time.Second, tracker.deps.ChargePeriod)
+	assert.Equal(t, big.NewInt(600), tracker.deps.MaxNotPaidInvoice)
+
+	tracker.adaptInvoiceWindow(5 * time.Second)
+	tracker.adaptInvoiceWindow(5 * time.Second)
+	assert.Equal(t, 30*time.Second, tracker.deps.ChargePeriod, "charge period should not shrink below the start")
+	assert.Equal(t, big.NewInt(300), tracker.deps.MaxNotPaidInvoice, "max unpaid invoice should not shrink below the start")
+}
+
+func TestInvoiceTracker_MissedPaymentResetsWindow(t *testing.T) {
+	history := NewConsumerPaymentHistory(time.Second, 1)
+	tracker := newWindowTracker(history)
+	tracker.adaptInvoiceWindow(100 * time.Millisecond)
+
+	tracker.resetInvoiceWindow()
+	assert.Equal(t, 30*time.Second, tracker.deps.ChargePeriod)
+	assert.Equal(t, big.NewInt(300), tracker.deps.MaxNotPaidInvoice)
+
+	_, _, trusted := history.startingWindow(tracker.deps.Peer)
+	assert.False(t, trusted)
+}
+
+func TestInvoiceTracker_TrustedConsumerStartsWithReachedWindow(t *testing.T) {
+	history := NewConsumerPaymentHistory(time.Second, 2)
+
+	previous := newWindowTracker(history)
+	previous.adaptInvoiceWindow(100 * time.Millisecond)
+	next := newWindowTracker(history)
+	next.applyStartingWindow()
+	assert.Equal(t, 30*time.Second, next.deps.ChargePeriod, "consumer is not trusted yet")
+
+	previous.adaptInvoiceWindow(100 * time.Millisecond)
+	next = newWindowTracker(history)
+	next.applyStartingWindow()
+	assert.Equal(t, previous.deps.ChargePeriod, next.deps.ChargePeriod)
+	assert.Equal(t, previous.deps.MaxNotPaidInvoice, next.deps.MaxNotPaidInvoice)
+
+	stranger := NewInvoiceTracker(InvoiceTrackerDeps{
+		Peer:              identity.FromAddress("0x2"),
+		ChargePeriod:      30 * time.Second,
+		LimitChargePeriod: time.Minute,
+		PaymentHistory:    history,
+	})
+	stranger.applyStartingWindow()
+	assert.Equal(t, 30*time.Second, stranger.deps.ChargePeriod)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/money/moneymath"
 	"github.com/mysteriumnetwork/node/session/pingpong/event"
 	"github.com/rs/zerolog/log"
 )
@@ -80,11 +81,11 @@ func (cts *ConsumerTotalsStorage) Store(chainID int64, id identity.Identity, her
 		}).Msg("tried to save a lower grand total amount")
 		return nil
 	}
-	element.amount = amount
+	element.amount = moneymath.Clone(amount)
 
 	go cts.bus.Publish(event.AppTopicGrandTotalChanged, event.AppEventGrandTotalChanged{
 		ChainID:    chainID,
-		Current:    amount,
+		Current:    moneymath.Clone(amount),
 		HermesID:   hermesID,
 		ConsumerID: id,
 	})
@@ -100,11 +101,10 @@ func (cts *ConsumerTotalsStorage) Get(chainID int64, id identity.Identity, herme
 	}
 	element.lock.RLock()
 	defer element.lock.RUnlock()
-	res := element.amount
-	if res == nil {
+	if element.amount == nil {
 		return nil, ErrNotFound
 	}
-	return res, nil
+	return moneymath.Clone(element.amount), nil
 }
 
 // Add adds the given amount as promised for the given channel.
@@ -127,16 +127,11 @@ func (cts *ConsumerTotalsStorage) Add(chainID int64, id identity.Identity, herme
 	}
 	element.lock.Lock()
 	defer element.lock.Unlock()
-	oldAmount := element.amount
-	if oldAmount == nil {
-		oldAmount = big.NewInt(0)
-	}
-	newAmount := new(big.Int).Add(oldAmount, amount)
-	element.amount = newAmount
+	element.amount = moneymath.Add(element.amount, amount)
 
 	go cts.bus.Publish(event.AppTopicGrandTotalChanged, event.AppEventGrandTotalChanged{
 		ChainID:    chainID,
-		Current:    newAmount,
+		Current:    moneymath.Clone(element.amount),
 		HermesID:   hermesID,
 		ConsumerID: id,
 	})
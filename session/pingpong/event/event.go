@@ -41,6 +41,8 @@ const (
 	AppTopicSettlementComplete = "provider_settlement_complete"
 	// AppTopicWithdrawalRequested topic for succesfull withdrawal requests.
 	AppTopicWithdrawalRequested = "provider_withdrawal_requested"
+	// AppTopicHermesTermsChanged topic for events related to changed hermes terms.
+	AppTopicHermesTermsChanged = "hermes_terms_changed"
 )
 
 // AppEventSettlementRequest represents the payload that is sent on the AppTopicSettlementRequest topic.
@@ -115,3 +117,20 @@ type AppEventWithdrawalRequested struct {
 	HermesID           common.Address
 	FromChain, ToChain int64
 }
+
+// HermesTerms represents the terms hermes offers to providers.
+type HermesTerms struct {
+	Fee            uint16
+	MaxChannelSize *big.Int
+}
+
+// AppEventHermesTermsChanged represents a change of hermes terms.
+// Paused is set when the new terms were not accepted and the hermes is no longer used.
+type AppEventHermesTermsChanged struct {
+	HermesID common.Address
+	ChainID  int64
+	Previous HermesTerms
+	Current  HermesTerms
+	Paused   bool
+	Reason   string
+}
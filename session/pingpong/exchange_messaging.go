@@ -21,6 +21,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/pb"
 	"github.com/mysteriumnetwork/payments/crypto"
@@ -46,8 +47,8 @@ func NewExchangeSender(ch p2p.ChannelSender) *ExchangeSender {
 	}
 }
 
-// Send sends the given exchange message.
-func (es *ExchangeSender) Send(em crypto.ExchangeMessage) error {
+// Send sends the given exchange message along with the delegation of the session key that signed it, if any.
+func (es *ExchangeSender) Send(em crypto.ExchangeMessage, delegation *identity.Delegation) error {
 	pMessage := &pb.ExchangeMessage{
 		Promise: &pb.Promise{
 			ChannelID: em.Promise.ChannelID,
@@ -65,6 +66,15 @@ func (es *ExchangeSender) Send(em crypto.ExchangeMessage) error {
 		HermesID:       em.HermesID,
 		ChainID:        em.ChainID,
 	}
+	if delegation != nil {
+		pMessage.Delegation = &pb.Delegation{
+			Identity:     delegation.Identity,
+			SessionKey:   delegation.SessionKey,
+			ValidUntil:   delegation.ValidUntil.Unix(),
+			Signature:    delegation.Signature,
+			KeySignature: delegation.KeySignature,
+		}
+	}
 	log.Debug().Msgf("Sending P2P message to %q: %s", p2p.TopicPaymentMessage, pMessage.String())
 
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
@@ -28,6 +28,7 @@ import (
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/connection"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
+	"github.com/mysteriumnetwork/node/core/power"
 	"github.com/mysteriumnetwork/node/core/service"
 	"github.com/mysteriumnetwork/node/datasize"
 	"github.com/mysteriumnetwork/node/eventbus"
@@ -59,11 +60,15 @@ func InvoiceFactoryCreator(
 	maxHermesFailureCount uint64,
 	maxAllowedHermesFee uint16,
 	maxUnpaidInvoiceValue, limitUnpaidInvoiceValue *big.Int,
+	paymentHistory *ConsumerPaymentHistory,
 	hermesStatusChecker hermesStatusChecker,
+	hermesTermsChecker hermesTermsChecker,
 	eventBus eventbus.EventBus,
 	promiseHandler promiseHandler,
 	addressProvider addressProvider,
 	observer observerApi,
+	delegations *identity.Delegations,
+	powerMode *power.Mode,
 ) func(identity.Identity, identity.Identity, int64, common.Address, string, chan crypto.ExchangeMessage, market.Price) (service.PaymentEngine, error) {
 	return func(providerID, consumerID identity.Identity, chainID int64, hermesID common.Address, sessionID string, exchangeChan chan crypto.ExchangeMessage, price market.Price) (service.PaymentEngine, error) {
 		timeTracker := session.NewTracker(mbtime.Now)
@@ -80,6 +85,7 @@ func InvoiceFactoryCreator(
 			MaxHermesFailureCount:      maxHermesFailureCount,
 			MaxAllowedHermesFee:        maxAllowedHermesFee,
 			HermesStatusChecker:        hermesStatusChecker,
+			HermesTermsChecker:         hermesTermsChecker,
 			EventBus:                   eventBus,
 			SessionID:                  sessionID,
 			PromiseHandler:             promiseHandler,
@@ -87,10 +93,13 @@ func InvoiceFactoryCreator(
 			AddressProvider:            addressProvider,
 			MaxNotPaidInvoice:          maxUnpaidInvoiceValue,
 			LimitNotPaidInvoice:        limitUnpaidInvoiceValue,
+			PaymentHistory:             paymentHistory,
 			ChargePeriod:               balanceSendPeriod,
 			LimitChargePeriod:          limitBalanceSendPeriod,
 			ChargePeriodLeeway:         2 * time.Minute,
 			Observer:                   observer,
+			Delegations:                delegations,
+			PowerMode:                  powerMode,
 		}
 		paymentEngine := NewInvoiceTracker(deps)
 		return paymentEngine, nil
@@ -105,6 +114,7 @@ func ExchangeFactoryFunc(
 	addressProvider addressProvider,
 	eventBus eventbus.EventBus,
 	dataLeewayMegabytes uint64,
+	sessionKeys *identity.SessionKeys,
 ) func(senderUUID string, channel p2p.Channel, consumer, provider identity.Identity, hermes common.Address, proposal proposal.PricedServiceProposal, price market.Price) (connection.PaymentIssuer, error) {
 	return func(senderUUID string, channel p2p.Channel, consumer, provider identity.Identity, hermes common.Address, proposal proposal.PricedServiceProposal, price market.Price) (connection.PaymentIssuer, error) {
 		invoices, err := invoiceReceiver(channel)
@@ -127,6 +137,7 @@ func ExchangeFactoryFunc(
 			HermesAddress:             hermes,
 			DataLeeway:                datasize.MiB * datasize.BitSize(dataLeewayMegabytes),
 			ChainID:                   config.GetInt64(config.FlagChainID),
+			SessionKeys:               sessionKeys,
 		}
 		return NewInvoicePayer(deps), nil
 	}
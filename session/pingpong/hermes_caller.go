@@ -18,7 +18,6 @@
 package pingpong
 
 import (
-	"context"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -132,6 +131,7 @@ type RequestPromise struct {
 	ExchangeMessage crypto.ExchangeMessage `json:"exchange_message"`
 	TransactorFee   *big.Int               `json:"transactor_fee"`
 	RRecoveryData   string                 `json:"r_recovery_data"`
+	Delegation      *identity.Delegation   `json:"delegation,omitempty"`
 }
 
 // RequestPromise requests a promise from hermes.
@@ -142,17 +142,13 @@ func (ac *HermesCaller) RequestPromise(rp RequestPromise) (crypto.Promise, error
 func (ac *HermesCaller) promiseRequest(rp RequestPromise, endpoint string) (crypto.Promise, error) {
 	eback := backoff.NewConstantBackOff(time.Millisecond * 500)
 	boff := backoff.WithMaxRetries(eback, 3)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	boff = backoff.WithContext(boff, ctx)
 
 	res := crypto.Promise{}
 
 	return res, backoff.Retry(func() error {
 		req, err := requests.NewPostRequest(ac.hermesBaseURI, endpoint, rp)
 		if err != nil {
-			cancel()
-			return fmt.Errorf("could not form %v request: %w", endpoint, err)
+			return backoff.Permanent(fmt.Errorf("could not form %v request: %w", endpoint, err))
 		}
 
 		err = ac.doRequest(req, &res)
@@ -162,8 +158,7 @@ func (ac *HermesCaller) promiseRequest(rp RequestPromise, endpoint string) (cryp
 				return err
 			}
 			// otherwise, do not retry anymore and return the error
-			cancel()
-			return fmt.Errorf("could not request promise: %w", err)
+			return backoff.Permanent(fmt.Errorf("could not request promise: %w", err))
 		}
 		return nil
 	}, boff)
@@ -205,9 +200,6 @@ type RevealObject struct {
 func (ac *HermesCaller) RevealR(r, provider string, agreementID *big.Int) error {
 	eback := backoff.NewConstantBackOff(time.Millisecond * 500)
 	boff := backoff.WithMaxRetries(eback, 3)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	boff = backoff.WithContext(boff, ctx)
 	return backoff.Retry(func() error {
 		req, err := requests.NewPostRequest(ac.hermesBaseURI, "reveal_r", RevealObject{
 			R:           r,
@@ -215,8 +207,7 @@ func (ac *HermesCaller) RevealR(r, provider string, agreementID *big.Int) error
 			AgreementID: agreementID,
 		})
 		if err != nil {
-			cancel()
-			return fmt.Errorf("could not form reveal_r request: %w", err)
+			return backoff.Permanent(fmt.Errorf("could not form reveal_r request: %w", err))
 		}
 
 		err = ac.doRequest(req, &RevealSuccess{})
@@ -226,8 +217,7 @@ func (ac *HermesCaller) RevealR(r, provider string, agreementID *big.Int) error
 				return err
 			}
 			// otherwise, do not retry anymore and return the error
-			cancel()
-			return fmt.Errorf("could not reveal R for hermes: %w", err)
+			return backoff.Permanent(fmt.Errorf("could not reveal R for hermes: %w", err))
 		}
 		return nil
 	}, boff)
@@ -246,6 +236,27 @@ func (ac *HermesCaller) IsIdentityOffchain(chainID int64, id string) (bool, erro
 	return data.IsOffchain, nil
 }
 
+// HermesFeatures lists optional capabilities of hermes.
+type HermesFeatures struct {
+	// SessionKeys tells that hermes accepts promises signed by session keys delegated by consumers.
+	SessionKeys bool `json:"session_keys"`
+}
+
+// SupportsSessionKeys checks whether hermes accepts promises signed by delegated session keys.
+// Hermes which does not list its features supports none of them.
+func (ac *HermesCaller) SupportsSessionKeys() (bool, error) {
+	req, err := requests.NewGetRequest(ac.hermesBaseURI, "features", nil)
+	if err != nil {
+		return false, fmt.Errorf("could not form features request: %w", err)
+	}
+
+	var features HermesFeatures
+	if err := ac.doRequest(req, &features); err != nil {
+		return false, fmt.Errorf("could not request features from hermes: %w", err)
+	}
+	return features.SessionKeys, nil
+}
+
 type syncPromiseRequest struct {
 	ChannelID string   `json:"channel_id"`
 	ChainID   int64    `json:"chain_id"`
@@ -268,3 +268,24 @@ func TestLatestPromise_isValid(t *testing.T) {
 		})
 	}
 }
+
+func TestHermesCaller_SupportsSessionKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/features" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"session_keys": true}`))
+	}))
+	defer server.Close()
+
+	c := requests.NewHTTPClient("0.0.0.0", time.Second)
+	supported, err := NewHermesCaller(c, server.URL).SupportsSessionKeys()
+	assert.NoError(t, err)
+	assert.True(t, supported)
+
+	supported, err = NewHermesCaller(c, server.URL+"/v1").SupportsSessionKeys()
+	assert.Error(t, err)
+	assert.False(t, supported)
+}
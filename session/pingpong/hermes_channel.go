@@ -22,6 +22,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/money/moneymath"
 	"github.com/mysteriumnetwork/payments/client"
 )
 
@@ -49,45 +50,18 @@ type HermesChannel struct {
 
 // LifetimeBalance returns earnings of all history.
 func (hc HermesChannel) LifetimeBalance() *big.Int {
-	if hc.lastPromise.Promise.Amount == nil {
-		return new(big.Int)
-	}
-	return hc.lastPromise.Promise.Amount
+	return moneymath.Clone(hc.lastPromise.Promise.Amount)
 }
 
 // UnsettledBalance returns current unsettled earnings.
 func (hc HermesChannel) UnsettledBalance() *big.Int {
-	settled := new(big.Int)
-	if hc.Channel.Settled != nil {
-		settled = hc.Channel.Settled
-	}
-
-	lastPromise := new(big.Int)
-	if hc.lastPromise.Promise.Amount != nil {
-		lastPromise = hc.lastPromise.Promise.Amount
-	}
-
-	return safeSub(lastPromise, settled)
+	return moneymath.SubFloor(hc.lastPromise.Promise.Amount, hc.Channel.Settled)
 }
 
 func (hc HermesChannel) availableBalance() *big.Int {
-	balance := new(big.Int)
-	if hc.Channel.Stake != nil {
-		balance = hc.Channel.Stake
-	}
-
-	settled := new(big.Int)
-	if hc.Channel.Settled != nil {
-		settled = hc.Channel.Settled
-	}
-
-	return new(big.Int).Add(balance, settled)
+	return moneymath.Add(hc.Channel.Stake, hc.Channel.Settled)
 }
 
 func (hc HermesChannel) balance() *big.Int {
-	promised := new(big.Int)
-	if hc.lastPromise.Promise.Amount != nil {
-		promised = hc.lastPromise.Promise.Amount
-	}
-	return safeSub(hc.availableBalance(), promised)
+	return moneymath.SubFloor(hc.availableBalance(), hc.lastPromise.Promise.Amount)
 }
@@ -32,6 +32,7 @@ import (
 	nodeEvent "github.com/mysteriumnetwork/node/core/node/event"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/money/moneymath"
 	pingEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
 	"github.com/mysteriumnetwork/payments/client"
 	"github.com/mysteriumnetwork/payments/crypto"
@@ -176,12 +177,9 @@ func (hcr *HermesChannelRepository) sumChannelsDetailed(chainID int64, id identi
 	}
 
 	add := func(current pingEvent.Earnings, channel HermesChannel) pingEvent.Earnings {
-		life := new(big.Int).Add(current.LifetimeBalance, channel.LifetimeBalance())
-		unset := new(big.Int).Add(current.UnsettledBalance, channel.UnsettledBalance())
-
 		// Save total globally per all hermeses
-		current.LifetimeBalance = life
-		current.UnsettledBalance = unset
+		current.LifetimeBalance = moneymath.Add(current.LifetimeBalance, channel.LifetimeBalance())
+		current.UnsettledBalance = moneymath.Add(current.UnsettledBalance, channel.UnsettledBalance())
 
 		return current
 	}
@@ -220,8 +218,8 @@ func (hcr *HermesChannelRepository) sumChannels(chainID int64, id identity.Ident
 
 	for _, channel := range v {
 		if channel.Identity == id {
-			lifetimeBalance = new(big.Int).Add(lifetimeBalance, channel.LifetimeBalance())
-			unsettledBalance = new(big.Int).Add(unsettledBalance, channel.UnsettledBalance())
+			lifetimeBalance = moneymath.Add(lifetimeBalance, channel.LifetimeBalance())
+			unsettledBalance = moneymath.Add(unsettledBalance, channel.UnsettledBalance())
 		}
 	}
 
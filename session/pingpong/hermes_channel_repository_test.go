@@ -24,6 +24,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/mocks"
+	"github.com/mysteriumnetwork/node/money/moneymath"
 	"github.com/mysteriumnetwork/node/session/pingpong/event"
 	"github.com/mysteriumnetwork/payments/client"
 	"github.com/mysteriumnetwork/payments/crypto"
@@ -120,10 +121,9 @@ func TestHermesChannelRepository_Fetch_takes_promise_into_account(t *testing.T)
 	assert.NoError(t, err)
 
 	// then
-	added := new(big.Int).Add(expectedChannelStatus.Stake, expectedChannelStatus.Settled)
-	expectedBalance := added.Sub(added, expectedPromise.Promise.Amount)
-	assert.Equal(t, expectedBalance, channel.balance())
-	assert.Equal(t, new(big.Int).Add(expectedChannelStatus.Stake, expectedChannelStatus.Settled), channel.availableBalance())
+	available := moneymath.Add(expectedChannelStatus.Stake, expectedChannelStatus.Settled)
+	assert.Equal(t, moneymath.Sub(available, expectedPromise.Promise.Amount), channel.balance())
+	assert.Equal(t, available, channel.availableBalance())
 }
 
 func TestHermesChannelRepository_Fetch_publishesEarningChanges(t *testing.T) {
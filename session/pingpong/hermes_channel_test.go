@@ -19,7 +19,9 @@ package pingpong
 
 import (
 	"math/big"
+	"math/rand"
 	"testing"
+	"testing/quick"
 
 	"github.com/mysteriumnetwork/payments/client"
 	"github.com/mysteriumnetwork/payments/crypto"
@@ -53,3 +55,53 @@ func TestHermesChannel_balance(t *testing.T) {
 	assert.Equal(t, big.NewInt(94), channel.balance())
 	assert.Equal(t, big.NewInt(6), channel.UnsettledBalance())
 }
+
+func TestHermesChannel_BalancesDoNotAliasChannelState(t *testing.T) {
+	channel := HermesChannel{
+		Channel: client.ProviderChannel{
+			Stake:   big.NewInt(100),
+			Settled: big.NewInt(10),
+		},
+		lastPromise: HermesPromise{
+			Promise: crypto.Promise{Amount: big.NewInt(15)},
+		},
+	}
+
+	for _, b := range []*big.Int{channel.LifetimeBalance(), channel.UnsettledBalance(), channel.availableBalance(), channel.balance()} {
+		b.Add(b, big.NewInt(1000))
+	}
+
+	assert.Equal(t, big.NewInt(100), channel.Channel.Stake)
+	assert.Equal(t, big.NewInt(10), channel.Channel.Settled)
+	assert.Equal(t, big.NewInt(15), channel.lastPromise.Promise.Amount)
+	assert.Equal(t, big.NewInt(15), channel.LifetimeBalance())
+}
+
+func TestHermesChannel_BalancesAreNeverNegative(t *testing.T) {
+	randAmount := func(r *rand.Rand) *big.Int {
+		if r.Intn(10) == 0 {
+			return nil
+		}
+		return new(big.Int).Rand(r, new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil))
+	}
+
+	property := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		channel := HermesChannel{
+			Channel: client.ProviderChannel{
+				Stake:   randAmount(r),
+				Settled: randAmount(r),
+			},
+			lastPromise: HermesPromise{
+				Promise: crypto.Promise{Amount: randAmount(r)},
+			},
+		}
+
+		return channel.LifetimeBalance().Sign() >= 0 &&
+			channel.UnsettledBalance().Sign() >= 0 &&
+			channel.availableBalance().Sign() >= 0 &&
+			channel.balance().Sign() >= 0 &&
+			channel.UnsettledBalance().Cmp(channel.LifetimeBalance()) <= 0
+	}
+	assert.NoError(t, quick.Check(property, nil))
+}
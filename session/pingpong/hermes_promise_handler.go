@@ -77,6 +77,7 @@ type HermesPromiseHandlerDeps struct {
 	HermesCallerFactory  HermesCallerFactory
 	Signer               identity.SignerFactory
 	Chains               []int64
+	Delegations          *identity.Delegations
 }
 
 // HermesPromiseHandler handles the hermes promises for ongoing sessions.
@@ -108,6 +109,7 @@ type enqueuedRequest struct {
 	r           []byte
 	em          crypto.ExchangeMessage
 	providerID  identity.Identity
+	consumerID  identity.Identity
 	requestFunc func(rp RequestPromise) (crypto.Promise, error)
 	sessionID   string
 }
@@ -117,11 +119,12 @@ type hermesURLGetter interface {
 }
 
 // RequestPromise adds the request to the queue.
-func (aph *HermesPromiseHandler) RequestPromise(r []byte, em crypto.ExchangeMessage, providerID identity.Identity, sessionID string) <-chan error {
+func (aph *HermesPromiseHandler) RequestPromise(r []byte, em crypto.ExchangeMessage, providerID, consumerID identity.Identity, sessionID string) <-chan error {
 	er := enqueuedRequest{
 		r:          r,
 		em:         em,
 		providerID: providerID,
+		consumerID: consumerID,
 		errChan:    make(chan error),
 		sessionID:  sessionID,
 	}
@@ -309,6 +312,7 @@ func (aph *HermesPromiseHandler) requestPromise(er enqueuedRequest) {
 		ExchangeMessage: er.em,
 		TransactorFee:   fee,
 		RRecoveryData:   hex.EncodeToString(encrypted),
+		Delegation:      aph.delegation(er.consumerID, er.em),
 	}
 
 	promise, err := er.requestFunc(request)
@@ -415,6 +419,25 @@ func (aph *HermesPromiseHandler) revealR(hermesPromise HermesPromise) error {
 var errRrecovered = errors.New("R recovered")
 var errPreviuosPromise = errors.New("action cannot be performed as previuos promise is invalid")
 
+// delegation returns the delegation of the session key that signed the consumer promise,
+// so hermes can accept promises not signed by the consumer identity itself.
+func (aph *HermesPromiseHandler) delegation(consumerID identity.Identity, em crypto.ExchangeMessage) *identity.Delegation {
+	if aph.deps.Delegations == nil || consumerID.Address == "" {
+		return nil
+	}
+
+	signer, err := em.Promise.RecoverSigner()
+	if err != nil {
+		return nil
+	}
+
+	delegation, ok := aph.deps.Delegations.Get(consumerID.ToCommonAddress(), signer)
+	if !ok {
+		return nil
+	}
+	return &delegation
+}
+
 func (aph *HermesPromiseHandler) handleHermesError(err error, providerID identity.Identity, chainID int64, hermesID common.Address) error {
 	if err == nil {
 		return nil
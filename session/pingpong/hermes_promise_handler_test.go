@@ -62,7 +62,7 @@ func TestHermesPromiseHandler_RequestPromise(t *testing.T) {
 		Promise: crypto.Promise{},
 	}
 
-	ch := aph.RequestPromise(r, em, identity.FromAddress("0x0000000000000000000000000000000000000001"), "session")
+	ch := aph.RequestPromise(r, em, identity.FromAddress("0x0000000000000000000000000000000000000001"), identity.FromAddress("0x0000000000000000000000000000000000000002"), "session")
 
 	err, more := <-ch
 	assert.False(t, more)
@@ -105,7 +105,7 @@ func TestHermesPromiseHandler_RequestPromise_BubblesErrors(t *testing.T) {
 		Promise: crypto.Promise{},
 	}
 
-	ch := aph.RequestPromise(r, em, identity.FromAddress("0x0000000000000000000000000000000000000001"), "session")
+	ch := aph.RequestPromise(r, em, identity.FromAddress("0x0000000000000000000000000000000000000001"), identity.FromAddress("0x0000000000000000000000000000000000000002"), "session")
 
 	err, more := <-ch
 	assert.True(t, more)
@@ -38,6 +38,7 @@ import (
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/money/moneymath"
 	"github.com/mysteriumnetwork/node/session/pingpong/event"
 	"github.com/mysteriumnetwork/payments/bindings"
 	"github.com/mysteriumnetwork/payments/client"
@@ -269,9 +270,32 @@ func (aps *hermesPromiseSettler) Subscribe(bus eventbus.Subscriber) error {
 	if err != nil {
 		return fmt.Errorf("could not subscribe to hermes promise event: %w", err)
 	}
+
+	for _, key := range []string{
+		config.FlagPaymentsHermesPromiseSettleThreshold.Name,
+		config.FlagPaymentsPromiseSettleMaxFeeThreshold.Name,
+		config.FlagPaymentsZeroStakeUnsettledAmount.Name,
+		config.FlagPaymentsUnsettledMaxAmount.Name,
+	} {
+		err = bus.SubscribeAsync(config.AppTopicConfig(key), aps.handleThresholdsChanged)
+		if err != nil {
+			return fmt.Errorf("could not subscribe to %s config changes: %w", key, err)
+		}
+	}
 	return nil
 }
 
+// handleThresholdsChanged picks up settling thresholds changed while the node is running.
+func (aps *hermesPromiseSettler) handleThresholdsChanged(_ interface{}) {
+	aps.lock.Lock()
+	defer aps.lock.Unlock()
+
+	aps.config.BalanceThreshold = config.GetFloat64(config.FlagPaymentsHermesPromiseSettleThreshold)
+	aps.config.MaxFeeThreshold = config.GetFloat64(config.FlagPaymentsPromiseSettleMaxFeeThreshold)
+	aps.config.MinAutoSettleAmount = config.GetFloat64(config.FlagPaymentsZeroStakeUnsettledAmount)
+	aps.config.MaxUnSettledAmount = config.GetFloat64(config.FlagPaymentsUnsettledMaxAmount)
+}
+
 func (aps *hermesPromiseSettler) handleSettlementEvent(event event.AppEventSettlementRequest) {
 	err := aps.ForceSettle(event.ChainID, event.ProviderID, event.HermesID)
 	if err != nil {
@@ -536,11 +560,7 @@ func (aps *hermesPromiseSettler) SettleWithBeneficiary(chainID int64, providerID
 		}
 
 		if hchannel.lastPromise.Promise.Amount != nil {
-			settled := hchannel.Channel.Settled
-			if settled == nil {
-				settled = big.NewInt(0)
-			}
-			unsettledAmount := new(big.Int).Sub(hchannel.lastPromise.Promise.Amount, settled)
+			unsettledAmount := moneymath.Sub(hchannel.lastPromise.Promise.Amount, hchannel.Channel.Settled)
 			if unsettledAmount.Cmp(maxUnsettled) > 0 {
 				maxUnsettled = unsettledAmount
 				channel = &hchannel
@@ -741,7 +761,7 @@ func (aps *hermesPromiseSettler) CheckLatestWithdrawal(
 		return nil, chid, err
 	}
 
-	return new(big.Int).Sub(latestPromise.Promise.Amount, withdrawalChannel.Settled), chid, nil
+	return moneymath.Sub(latestPromise.Promise.Amount, withdrawalChannel.Settled), chid, nil
 }
 
 func (aps *hermesPromiseSettler) RetryWithdrawLatest(
@@ -821,13 +841,7 @@ func (aps *hermesPromiseSettler) calculateAmountToWithdrawFromPreviousPromise(pr
 	if err != nil {
 		return nil, err
 	}
-	if ch.Settled == nil {
-		ch.Settled = big.NewInt(0)
-	}
-
-	diff := big.NewInt(0).Sub(promiseFromStorage.Promise.Amount, ch.Settled)
-	diff = diff.Abs(diff)
-	return diff, nil
+	return moneymath.Abs(moneymath.Sub(promiseFromStorage.Promise.Amount, ch.Settled)), nil
 }
 
 func (aps *hermesPromiseSettler) validateWithdrawalAmount(amount *big.Int, toChain int64) error {
@@ -946,7 +960,7 @@ func (aps *hermesPromiseSettler) issueSelfPromise(fromChain, toChain int64, amou
 	}
 	invoice.Provider = providerID.ToCommonAddress().Hex()
 
-	promise, err := crypto.CreatePromise(consumerChannelAddress.Hex(), fromChain, big.NewInt(0).Add(amount, previousPromiseAmount), big.NewInt(0), invoice.Hashlock, aps.ks, providerID.ToCommonAddress())
+	promise, err := crypto.CreatePromise(consumerChannelAddress.Hex(), fromChain, moneymath.Add(amount, previousPromiseAmount), big.NewInt(0), invoice.Hashlock, aps.ks, providerID.ToCommonAddress())
 	if err != nil {
 		return nil, fmt.Errorf("could not create promise: %w", err)
 	}
@@ -1037,7 +1051,7 @@ func (aps *hermesPromiseSettler) settle(
 		settled = new(big.Int)
 	}
 
-	amountToSettle := new(big.Int).Sub(updatedPromise.Amount, settled)
+	amountToSettle := moneymath.Sub(updatedPromise.Amount, settled)
 	if amountToSettle.Cmp(big.NewInt(0)) <= 0 {
 		log.Warn().Msgf("Tried to settle for %s MYST", amountToSettle.String())
 		return nil
@@ -1049,7 +1063,7 @@ func (aps *hermesPromiseSettler) settle(
 		return err
 	}
 
-	totalFees := new(big.Int).Add(fee, updatedPromise.Fee)
+	totalFees := moneymath.Add(fee, updatedPromise.Fee)
 	if totalFees.Cmp(amountToSettle) > 0 {
 		log.Error().Fields(map[string]interface{}{
 			"amountToSettle": amountToSettle.String(),
@@ -1357,19 +1371,15 @@ func (aps *hermesPromiseSettler) needsSettling(ss settlementState, balanceThresh
 				return false, nil
 			}
 			//set max fee to 10% more than current
-			maxFee, _ := new(big.Float).Mul(new(big.Float).SetInt(settleFees.Fee), big.NewFloat(1.1)).Int(nil)
-			unsettledBalance := new(big.Float).SetInt(channel.UnsettledBalance())
-			calculatedFeesThreshold := new(big.Float).Mul(big.NewFloat(feeThreshold), unsettledBalance)
-			calculatedFeesThresholdInt, _ := calculatedFeesThreshold.Int(nil)
-			return settleFees.Fee.Cmp(calculatedFeesThresholdInt) < 0, maxFee
+			maxFee := moneymath.MulFloat(settleFees.Fee, 1.1)
+			calculatedFeesThreshold := moneymath.MulFloat(channel.UnsettledBalance(), feeThreshold)
+			return settleFees.Fee.Cmp(calculatedFeesThreshold) < 0, maxFee
 		}
 		return false, nil
 	}
 
-	floated := new(big.Float).SetInt(channel.availableBalance())
-	calculatedThreshold := new(big.Float).Mul(big.NewFloat(balanceThreshold), floated)
+	i := moneymath.MulFloat(channel.availableBalance(), balanceThreshold)
 	possibleEarnings := channel.UnsettledBalance()
-	i, _ := calculatedThreshold.Int(nil)
 	if possibleEarnings.Cmp(i) == -1 {
 		return false, nil
 	}
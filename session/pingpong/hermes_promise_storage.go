@@ -23,12 +23,10 @@ import (
 	"math/big"
 	"sync"
 
-	"github.com/asdine/storm/v3/codec/json"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/core/storage"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/payments/crypto"
-	"go.etcd.io/bbolt"
 )
 
 const hermesPromiseBucketName = "hermes_promises"
@@ -38,14 +36,14 @@ var ErrAttemptToOverwrite = errors.New("attempted to overwrite a promise with an
 
 // HermesPromiseStorage allows for storing of hermes promises.
 type HermesPromiseStorage struct {
-	lock sync.Mutex
-	bolt *boltdb.Bolt
+	lock    sync.Mutex
+	storage storage.Store
 }
 
 // NewHermesPromiseStorage returns a new instance of the hermes promise storage.
-func NewHermesPromiseStorage(bolt *boltdb.Bolt) *HermesPromiseStorage {
+func NewHermesPromiseStorage(storage storage.Store) *HermesPromiseStorage {
 	return &HermesPromiseStorage{
-		bolt: bolt,
+		storage: storage,
 	}
 }
 
@@ -78,7 +76,7 @@ func (aps *HermesPromiseStorage) Store(promise HermesPromise) error {
 		return ErrAttemptToOverwrite
 	}
 
-	if err := aps.bolt.SetValue(aps.getBucketName(promise.Promise.ChainID), promise.ChannelID, promise); err != nil {
+	if err := aps.storage.SetValue(aps.getBucketName(promise.Promise.ChainID), promise.ChannelID, promise); err != nil {
 		return fmt.Errorf("could not store hermes promise: %w", err)
 	}
 	return nil
@@ -98,12 +96,12 @@ func (aps *HermesPromiseStorage) shouldOverride(old, new HermesPromise) bool {
 
 // Delete deletes the given hermes promise.
 func (aps *HermesPromiseStorage) Delete(promise HermesPromise) error {
-	return aps.bolt.DeleteKey(aps.getBucketName(promise.Promise.ChainID), promise.ChannelID)
+	return aps.storage.DeleteKey(aps.getBucketName(promise.Promise.ChainID), promise.ChannelID)
 }
 
 func (aps *HermesPromiseStorage) get(chainID int64, channelID string) (HermesPromise, error) {
 	result := &HermesPromise{}
-	err := aps.bolt.GetValue(aps.getBucketName(chainID), channelID, result)
+	err := aps.storage.GetValue(aps.getBucketName(chainID), channelID, result)
 	if err != nil {
 		if err.Error() == errBoltNotFound {
 			err = ErrNotFound
@@ -137,42 +135,25 @@ func (aps *HermesPromiseStorage) List(filter HermesPromiseFilter) ([]HermesPromi
 	aps.lock.Lock()
 	defer aps.lock.Unlock()
 
-	result := make([]HermesPromise, 0)
-	aps.bolt.RLock()
-	defer aps.bolt.RUnlock()
-	err := aps.bolt.DB().Bolt.View(func(tx *bbolt.Tx) error {
-		bucket := tx.Bucket([]byte(aps.getBucketName(filter.ChainID)))
-		if bucket == nil {
-			return nil
-		}
-
-		return bucket.ForEach(func(k, v []byte) error {
-			if string(k) == "__storm_metadata" {
-				return nil
-			}
-
-			var entry HermesPromise
-			if err := json.Codec.Unmarshal(v, &entry); err != nil {
-				return err
-			}
+	var entries []HermesPromise
+	if err := aps.storage.GetAllValues(aps.getBucketName(filter.ChainID), &entries); err != nil {
+		return nil, fmt.Errorf("could not list hermes promises: %w", err)
+	}
 
-			if filter.Identity != nil {
-				if *filter.Identity != entry.Identity {
-					return nil
-				}
+	result := make([]HermesPromise, 0)
+	for _, entry := range entries {
+		if filter.Identity != nil {
+			if *filter.Identity != entry.Identity {
+				continue
 			}
-			if filter.HermesID != nil {
-				if *filter.HermesID != entry.HermesID {
-					return nil
-				}
+		}
+		if filter.HermesID != nil {
+			if *filter.HermesID != entry.HermesID {
+				continue
 			}
+		}
 
-			result = append(result, entry)
-			return nil
-		})
-	})
-	if err != nil {
-		return nil, fmt.Errorf("could not list hermes promises: %w", err)
+		result = append(result, entry)
 	}
 
 	return result, nil
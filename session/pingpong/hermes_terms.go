@@ -0,0 +1,238 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+// ErrHermesPaused indicates that hermes terms changed beyond the operator configured bounds and the hermes is not used.
+var ErrHermesPaused = errors.New("hermes usage is paused due to changed terms")
+
+// HermesTermsBounds represents the operator configured limits of acceptable hermes terms.
+type HermesTermsBounds struct {
+	MaxFee         uint16
+	MinChannelSize *big.Int
+}
+
+func (htb HermesTermsBounds) check(terms event.HermesTerms) error {
+	if terms.Fee > htb.MaxFee {
+		return fmt.Errorf("fee %v exceeds the limit of %v", terms.Fee, htb.MaxFee)
+	}
+	if htb.MinChannelSize != nil && htb.MinChannelSize.Sign() > 0 && terms.MaxChannelSize != nil && terms.MaxChannelSize.Cmp(htb.MinChannelSize) < 0 {
+		return fmt.Errorf("max channel size %v is below the limit of %v", terms.MaxChannelSize, htb.MinChannelSize)
+	}
+	return nil
+}
+
+type hermesTermsSource interface {
+	GetHermesFee(chainID int64, hermesAddress common.Address) (uint16, error)
+	GetStakeThresholds(chainID int64, hermesAddress common.Address) (min, max *big.Int, err error)
+}
+
+type hermesAgreement struct {
+	chainID  int64
+	hermesID common.Address
+	terms    event.HermesTerms
+	paused   bool
+	reason   string
+}
+
+// HermesTermsMonitor keeps track of the terms accepted for every hermes in use.
+// Whenever hermes publishes new terms, they are re-accepted if they fit into the operator configured bounds,
+// otherwise the hermes is paused until its terms are acceptable again.
+type HermesTermsMonitor struct {
+	source    hermesTermsSource
+	publisher eventbus.Publisher
+	bounds    HermesTermsBounds
+	interval  time.Duration
+
+	agreements map[string]*hermesAgreement
+	lock       sync.Mutex
+	stop       chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewHermesTermsMonitor creates a new instance of hermes terms monitor.
+func NewHermesTermsMonitor(source hermesTermsSource, publisher eventbus.Publisher, bounds HermesTermsBounds, interval time.Duration) *HermesTermsMonitor {
+	return &HermesTermsMonitor{
+		source:     source,
+		publisher:  publisher,
+		bounds:     bounds,
+		interval:   interval,
+		agreements: make(map[string]*hermesAgreement),
+		stop:       make(chan struct{}),
+	}
+}
+
+// Check returns ErrHermesPaused if the given hermes terms are not acceptable.
+// Hermes seen for the first time has its terms fetched and is watched from then on.
+func (htm *HermesTermsMonitor) Check(chainID int64, hermesID common.Address) error {
+	htm.lock.Lock()
+	agreement, ok := htm.agreements[htm.formKey(chainID, hermesID)]
+	htm.lock.Unlock()
+
+	if !ok {
+		terms, err := htm.fetchTerms(chainID, hermesID)
+		if err != nil {
+			// Hermes status is verified separately, failing to fetch terms should not block sessions.
+			log.Warn().Err(err).Msg("Skipping hermes terms check")
+			return nil
+		}
+		agreement = htm.accept(chainID, hermesID, terms)
+	}
+
+	htm.lock.Lock()
+	defer htm.lock.Unlock()
+	if agreement.paused {
+		return fmt.Errorf("%w: %s", ErrHermesPaused, agreement.reason)
+	}
+	return nil
+}
+
+// Start periodically re-checks terms of the known hermeses. Blocks until stopped.
+func (htm *HermesTermsMonitor) Start() {
+	for {
+		select {
+		case <-htm.stop:
+			return
+		case <-time.After(htm.interval):
+			htm.refresh()
+		}
+	}
+}
+
+// Stop stops the monitor.
+func (htm *HermesTermsMonitor) Stop() {
+	htm.stopOnce.Do(func() {
+		close(htm.stop)
+	})
+}
+
+func (htm *HermesTermsMonitor) refresh() {
+	htm.lock.Lock()
+	agreements := make([]hermesAgreement, 0, len(htm.agreements))
+	for _, agreement := range htm.agreements {
+		agreements = append(agreements, *agreement)
+	}
+	htm.lock.Unlock()
+
+	for _, agreement := range agreements {
+		terms, err := htm.fetchTerms(agreement.chainID, agreement.hermesID)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Could not refresh hermes(%v) terms", agreement.hermesID.Hex())
+			continue
+		}
+		htm.renegotiate(agreement.chainID, agreement.hermesID, terms)
+	}
+}
+
+func (htm *HermesTermsMonitor) fetchTerms(chainID int64, hermesID common.Address) (event.HermesTerms, error) {
+	fee, err := htm.source.GetHermesFee(chainID, hermesID)
+	if err != nil {
+		return event.HermesTerms{}, fmt.Errorf("could not check hermes(%v) fee on chain %v: %w", hermesID.Hex(), chainID, err)
+	}
+
+	_, maxStake, err := htm.source.GetStakeThresholds(chainID, hermesID)
+	if err != nil {
+		return event.HermesTerms{}, fmt.Errorf("could not check hermes(%v) stake thresholds on chain %v: %w", hermesID.Hex(), chainID, err)
+	}
+
+	return event.HermesTerms{Fee: fee, MaxChannelSize: maxStake}, nil
+}
+
+func (htm *HermesTermsMonitor) accept(chainID int64, hermesID common.Address, terms event.HermesTerms) *hermesAgreement {
+	htm.lock.Lock()
+	defer htm.lock.Unlock()
+
+	key := htm.formKey(chainID, hermesID)
+	if agreement, ok := htm.agreements[key]; ok {
+		return agreement
+	}
+
+	agreement := &hermesAgreement{
+		chainID:  chainID,
+		hermesID: hermesID,
+		terms:    terms,
+	}
+	if err := htm.bounds.check(terms); err != nil {
+		agreement.paused = true
+		agreement.reason = err.Error()
+		log.Error().Msgf("Hermes(%v) terms are not acceptable, pausing: %s", hermesID.Hex(), agreement.reason)
+	}
+	htm.agreements[key] = agreement
+	return agreement
+}
+
+func (htm *HermesTermsMonitor) renegotiate(chainID int64, hermesID common.Address, terms event.HermesTerms) {
+	htm.lock.Lock()
+	agreement, ok := htm.agreements[htm.formKey(chainID, hermesID)]
+	if !ok || termsEqual(agreement.terms, terms) {
+		htm.lock.Unlock()
+		return
+	}
+
+	previous := agreement.terms
+	agreement.terms = terms
+	agreement.paused = false
+	agreement.reason = ""
+	if err := htm.bounds.check(terms); err != nil {
+		agreement.paused = true
+		agreement.reason = err.Error()
+	}
+	ev := event.AppEventHermesTermsChanged{
+		HermesID: hermesID,
+		ChainID:  chainID,
+		Previous: previous,
+		Current:  terms,
+		Paused:   agreement.paused,
+		Reason:   agreement.reason,
+	}
+	htm.lock.Unlock()
+
+	if ev.Paused {
+		log.Error().Msgf("Hermes(%v) changed its terms beyond the configured bounds, pausing: %s", hermesID.Hex(), ev.Reason)
+	} else {
+		log.Info().Msgf("Hermes(%v) changed its terms, re-accepted fee %v and max channel size %v", hermesID.Hex(), terms.Fee, terms.MaxChannelSize)
+	}
+	htm.publisher.Publish(event.AppTopicHermesTermsChanged, ev)
+}
+
+func (htm *HermesTermsMonitor) formKey(chainID int64, hermesID common.Address) string {
+	return fmt.Sprintf("%v_%v", hermesID.Hex(), chainID)
+}
+
+func termsEqual(a, b event.HermesTerms) bool {
+	if a.Fee != b.Fee {
+		return false
+	}
+	if a.MaxChannelSize == nil || b.MaxChannelSize == nil {
+		return a.MaxChannelSize == b.MaxChannelSize
+	}
+	return a.MaxChannelSize.Cmp(b.MaxChannelSize) == 0
+}
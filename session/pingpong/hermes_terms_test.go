@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+type mockHermesTermsSource struct {
+	fee      uint16
+	maxStake *big.Int
+	err      error
+}
+
+func (m *mockHermesTermsSource) GetHermesFee(chainID int64, hermesAddress common.Address) (uint16, error) {
+	return m.fee, m.err
+}
+
+func (m *mockHermesTermsSource) GetStakeThresholds(chainID int64, hermesAddress common.Address) (min, max *big.Int, err error) {
+	return big.NewInt(0), m.maxStake, m.err
+}
+
+func TestHermesTermsMonitor_Renegotiation(t *testing.T) {
+	hermesID := common.HexToAddress("0x1")
+	source := &mockHermesTermsSource{fee: 2000, maxStake: big.NewInt(100)}
+	publisher := &mockPublisher{publicationChan: make(chan testEvent, 10)}
+	monitor := NewHermesTermsMonitor(source, publisher, HermesTermsBounds{MaxFee: 3000, MinChannelSize: big.NewInt(50)}, time.Minute)
+
+	assert.NoError(t, monitor.Check(1, hermesID))
+
+	// terms changed within bounds are re-accepted
+	source.fee = 2500
+	monitor.refresh()
+	assert.NoError(t, monitor.Check(1, hermesID))
+	ev := <-publisher.publicationChan
+	assert.Equal(t, event.AppTopicHermesTermsChanged, ev.name)
+	changed := ev.value.(event.AppEventHermesTermsChanged)
+	assert.False(t, changed.Paused)
+	assert.Equal(t, uint16(2000), changed.Previous.Fee)
+	assert.Equal(t, uint16(2500), changed.Current.Fee)
+
+	// unchanged terms are not announced
+	monitor.refresh()
+	assert.Len(t, publisher.publicationChan, 0)
+
+	// terms beyond bounds pause the hermes
+	source.maxStake = big.NewInt(10)
+	monitor.refresh()
+	err := monitor.Check(1, hermesID)
+	assert.True(t, errors.Is(err, ErrHermesPaused))
+	changed = (<-publisher.publicationChan).value.(event.AppEventHermesTermsChanged)
+	assert.True(t, changed.Paused)
+	assert.NotEmpty(t, changed.Reason)
+
+	// hermes is resumed once terms are acceptable again
+	source.maxStake = big.NewInt(100)
+	monitor.refresh()
+	assert.NoError(t, monitor.Check(1, hermesID))
+}
+
+func TestHermesTermsMonitor_FirstCheck(t *testing.T) {
+	source := &mockHermesTermsSource{fee: 4000, maxStake: big.NewInt(100)}
+	monitor := NewHermesTermsMonitor(source, &mockPublisher{}, HermesTermsBounds{MaxFee: 3000}, time.Minute)
+	assert.True(t, errors.Is(monitor.Check(1, common.HexToAddress("0x1")), ErrHermesPaused))
+
+	source = &mockHermesTermsSource{err: errors.New("boom")}
+	monitor = NewHermesTermsMonitor(source, &mockPublisher{}, HermesTermsBounds{MaxFee: 3000}, time.Minute)
+	assert.NoError(t, monitor.Check(1, common.HexToAddress("0x2")))
+}
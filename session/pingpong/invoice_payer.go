@@ -35,6 +35,7 @@ import (
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/money/moneymath"
 	"github.com/mysteriumnetwork/node/session/pingpong/event"
 	"github.com/mysteriumnetwork/payments/crypto"
 )
@@ -54,7 +55,7 @@ const consumerInvoiceBasicTolerance = 1.11
 
 // PeerExchangeMessageSender allows for sending of exchange messages.
 type PeerExchangeMessageSender interface {
-	Send(crypto.ExchangeMessage) error
+	Send(crypto.ExchangeMessage, *identity.Delegation) error
 }
 
 type consumerTotalsStorage interface {
@@ -107,6 +108,7 @@ type InvoicePayerDeps struct {
 	HermesAddress             common.Address
 	DataLeeway                datasize.BitSize
 	ChainID                   int64
+	SessionKeys               *identity.SessionKeys
 }
 
 // NewInvoicePayer returns a new instance of exchange message tracker.
@@ -184,7 +186,7 @@ func (ip *InvoicePayer) isInvoiceOK(invoice crypto.Invoice) error {
 	shouldBe := CalculatePaymentAmount(ip.deps.TimeTracker.Elapsed(), transferred, ip.deps.AgreedPrice)
 	estimatedTolerance := estimateInvoiceTolerance(ip.deps.TimeTracker.Elapsed(), transferred)
 
-	upperBound, _ := new(big.Float).Mul(new(big.Float).SetInt(shouldBe), big.NewFloat(estimatedTolerance)).Int(nil)
+	upperBound := moneymath.MulFloat(shouldBe, estimatedTolerance)
 
 	log.Debug().Msgf("Estimated tolerance %.4v, upper bound %v", estimatedTolerance, upperBound)
 
@@ -224,7 +226,7 @@ func estimateInvoiceTolerance(elapsed time.Duration, transferred DataTransferred
 }
 
 func (ip *InvoicePayer) calculateAmountToPromise(invoice crypto.Invoice) (toPromise *big.Int, diff *big.Int, err error) {
-	diff = safeSub(invoice.AgreementTotal, ip.lastInvoice.AgreementTotal)
+	diff = moneymath.SubFloor(invoice.AgreementTotal, ip.lastInvoice.AgreementTotal)
 	totalPromised, err := ip.deps.ConsumerTotalsStorage.Get(ip.chainID(), ip.deps.Identity, ip.deps.HermesAddress)
 	if err != nil {
 		if err != ErrNotFound {
@@ -236,12 +238,12 @@ func (ip *InvoicePayer) calculateAmountToPromise(invoice crypto.Invoice) (toProm
 
 	// This is a new agreement, we need to take in the agreement total and just add it to total promised
 	if ip.lastInvoice.AgreementID.Cmp(invoice.AgreementID) != 0 {
-		diff = invoice.AgreementTotal
+		diff = moneymath.Clone(invoice.AgreementTotal)
 	}
 
 	log.Debug().Msgf("Loaded previous state: already promised: %v", totalPromised)
 	log.Debug().Msgf("Incrementing promised amount by %v", diff)
-	amountToPromise := new(big.Int).Add(totalPromised, diff)
+	amountToPromise := moneymath.Add(totalPromised, diff)
 	return amountToPromise, diff, nil
 }
 
@@ -255,12 +257,24 @@ func (ip *InvoicePayer) issueExchangeMessage(invoice crypto.Invoice) error {
 		return errors.Wrap(err, "could not calculate amount to promise")
 	}
 
-	msg, err := crypto.CreateExchangeMessage(ip.chainID(), invoice, amountToPromise, ip.channelAddress.Address, ip.deps.HermesAddress.Hex(), ip.deps.Ks, common.HexToAddress(ip.deps.Identity.Address))
+	var ks hashSigner = ip.deps.Ks
+	signer := common.HexToAddress(ip.deps.Identity.Address)
+	var delegation *identity.Delegation
+	if ip.deps.SessionKeys != nil {
+		key, err := ip.deps.SessionKeys.Get(ip.deps.Identity)
+		if err != nil {
+			return errors.Wrap(err, "could not get session key")
+		}
+		d := key.Delegation()
+		ks, signer, delegation = key, key.Address(), &d
+	}
+
+	msg, err := crypto.CreateExchangeMessage(ip.chainID(), invoice, amountToPromise, ip.channelAddress.Address, ip.deps.HermesAddress.Hex(), ks, signer)
 	if err != nil {
 		return errors.Wrap(err, "could not create exchange message")
 	}
 
-	err = ip.deps.PeerExchangeMessageSender.Send(*msg)
+	err = ip.deps.PeerExchangeMessageSender.Send(*msg, delegation)
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to send exchange message")
 	}
@@ -44,7 +44,7 @@ type MockPeerExchangeMessageSender struct {
 	chanToWriteTo chan crypto.ExchangeMessage
 }
 
-func (mpems *MockPeerExchangeMessageSender) Send(em crypto.ExchangeMessage) error {
+func (mpems *MockPeerExchangeMessageSender) Send(em crypto.ExchangeMessage, _ *identity.Delegation) error {
 	if mpems.chanToWriteTo != nil {
 		mpems.chanToWriteTo <- em
 	}
@@ -34,9 +34,11 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/power"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/money/moneymath"
 	"github.com/mysteriumnetwork/node/p2p"
 	sessionEvent "github.com/mysteriumnetwork/node/session/event"
 	"github.com/mysteriumnetwork/node/session/pingpong/event"
@@ -78,6 +80,10 @@ type hermesStatusChecker interface {
 	GetHermesStatus(chainID int64, registryAddress common.Address, hermesID common.Address) (HermesStatus, error)
 }
 
+type hermesTermsChecker interface {
+	Check(chainID int64, hermesID common.Address) error
+}
+
 type providerInvoiceStorage interface {
 	Get(providerIdentity, consumerIdentity identity.Identity) (crypto.Invoice, error)
 	Store(providerIdentity, consumerIdentity identity.Identity, invoice crypto.Invoice) error
@@ -86,13 +92,15 @@ type providerInvoiceStorage interface {
 }
 
 type promiseHandler interface {
-	RequestPromise(r []byte, em crypto.ExchangeMessage, providerID identity.Identity, sessionID string) <-chan error
+	RequestPromise(r []byte, em crypto.ExchangeMessage, providerID, consumerID identity.Identity, sessionID string) <-chan error
 }
 
 type sentInvoice struct {
 	invoice    crypto.Invoice
 	r          []byte
 	isCritical bool
+	// sentAt is the session time the invoice was sent at.
+	sentAt time.Duration
 }
 
 // DataTransferred represents the data transferred in a session.
@@ -134,6 +142,12 @@ type InvoiceTracker struct {
 
 	lastExchangeMessage     crypto.ExchangeMessage
 	lastExchangeMessageLock sync.Mutex
+
+	// Invoice window starts at the configured charge period and max unpaid value,
+	// and grows up to their limits while the consumer pays promptly.
+	windowLock       sync.Mutex
+	baseChargePeriod time.Duration
+	baseMaxUnpaid    *big.Int
 }
 
 // InvoiceTrackerDeps contains all the deps needed for invoice tracker.
@@ -152,6 +166,7 @@ type InvoiceTrackerDeps struct {
 	MaxHermesFailureCount      uint64
 	MaxAllowedHermesFee        uint16
 	HermesStatusChecker        hermesStatusChecker
+	HermesTermsChecker         hermesTermsChecker
 	EventBus                   eventbus.EventBus
 	SessionID                  string
 	PromiseHandler             promiseHandler
@@ -160,7 +175,11 @@ type InvoiceTrackerDeps struct {
 	LimitChargePeriod          time.Duration
 	LimitNotPaidInvoice        *big.Int
 	MaxNotPaidInvoice          *big.Int
+	PaymentHistory             *ConsumerPaymentHistory
 	Observer                   observerApi
+	Delegations                *identity.Delegations
+	// PowerMode stretches the charge period to its limit while the node runs in low-power mode.
+	PowerMode *power.Mode
 }
 
 // NewInvoiceTracker creates a new instance of invoice tracker.
@@ -185,6 +204,8 @@ func NewInvoiceTracker(
 		criticalInvoiceErrors:          make(chan error),
 		invoiceChannel:                 make(chan bool),
 		invoiceDebounceRate:            time.Second * 5,
+		baseChargePeriod:               itd.ChargePeriod,
+		baseMaxUnpaid:                  itd.MaxNotPaidInvoice,
 	}
 }
 
@@ -256,10 +277,8 @@ func (it *InvoiceTracker) handleExchangeMessage(em crypto.ExchangeMessage) error
 		return err
 	}
 
-	it.saveLastExchangeMessage(em)
-	it.markInvoicePaid(em.Promise.Hashlock)
-	it.resetNotReceivedExchangeMessageCount()
-	it.resetNotSentExchangeMessageCount()
+	it.markExchangeMessageReceived(em)
+	it.adaptInvoiceWindow(it.deps.TimeTracker.Elapsed() - invoice.sentAt)
 
 	// incase of zero payment, we'll just skip going to the hermes
 	if it.deps.AgreedPrice.IsFree() {
@@ -270,8 +289,30 @@ func (it *InvoiceTracker) handleExchangeMessage(em crypto.ExchangeMessage) error
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("could not store r: %s", hex.EncodeToString(invoice.r)))
 	}
-	errChan := it.deps.PromiseHandler.RequestPromise(invoice.r, em, it.deps.ProviderID, it.deps.SessionID)
+	errChan := it.deps.PromiseHandler.RequestPromise(invoice.r, em, it.deps.ProviderID, it.deps.Peer, it.deps.SessionID)
 	go it.handlePromiseErrors(errChan)
+
+	// The promise for the paid invoice is claimed anyway, only the further service depends on hermes terms.
+	return it.checkHermesTerms()
+}
+
+// markExchangeMessageReceived marks the invoice as paid with the given exchange message.
+func (it *InvoiceTracker) markExchangeMessageReceived(em crypto.ExchangeMessage) {
+	it.saveLastExchangeMessage(em)
+	it.markInvoicePaid(em.Promise.Hashlock)
+	it.resetNotReceivedExchangeMessageCount()
+	it.resetNotSentExchangeMessageCount()
+}
+
+func (it *InvoiceTracker) checkHermesTerms() error {
+	if it.deps.HermesTermsChecker == nil {
+		return nil
+	}
+
+	if err := it.deps.HermesTermsChecker.Check(it.deps.ChainID, it.deps.ConsumersHermesID); err != nil {
+		log.Error().Err(err).Msgf("Hermes(%v) terms check failed", it.deps.ConsumersHermesID.Hex())
+		return err
+	}
 	return nil
 }
 
@@ -304,7 +345,12 @@ func (it *InvoiceTracker) Start() error {
 		return ErrHermesFeeTooLarge
 	}
 
+	if err := it.checkHermesTerms(); err != nil {
+		return err
+	}
+
 	it.generateAgreementID()
+	it.applyStartingWindow()
 
 	emErrors := make(chan error)
 	go func() {
@@ -353,40 +399,110 @@ func (it *InvoiceTracker) sendInvoicesWhenNeeded(interval time.Duration) {
 		case <-it.stop:
 			return
 		case <-time.After(interval):
-			currentlyElapsed := it.deps.TimeTracker.Elapsed()
-			shouldBe := CalculatePaymentAmount(currentlyElapsed, it.getDataTransferred(), it.deps.AgreedPrice)
-			lastEM := it.getLastExchangeMessage()
-			diff := safeSub(shouldBe, lastEM.AgreementTotal)
-			if diff.Cmp(it.deps.MaxNotPaidInvoice) >= 0 && currentlyElapsed-it.lastInvoiceSent > it.invoiceDebounceRate {
-				it.lastInvoiceSent = it.deps.TimeTracker.Elapsed()
-				it.invoiceChannel <- true
-
-				it.updateMaxUnpaid()
-			} else if currentlyElapsed-it.lastInvoiceSent > it.deps.ChargePeriod {
-				it.lastInvoiceSent = it.deps.TimeTracker.Elapsed()
-				it.invoiceChannel <- false
-
-				it.updateTimer()
+			if due, critical := it.invoiceDue(); due {
+				it.invoiceChannel <- critical
 			}
 		}
 	}
 }
 
+// invoiceDue checks if it's time to send the next invoice and whether the invoice is critical.
+func (it *InvoiceTracker) invoiceDue() (due, critical bool) {
+	currentlyElapsed := it.deps.TimeTracker.Elapsed()
+	shouldBe := CalculatePaymentAmount(currentlyElapsed, it.getDataTransferred(), it.deps.AgreedPrice)
+	lastEM := it.getLastExchangeMessage()
+	diff := moneymath.SubFloor(shouldBe, lastEM.AgreementTotal)
+	chargePeriod, maxUnpaid := it.invoiceWindow()
+	if diff.Cmp(maxUnpaid) >= 0 && currentlyElapsed-it.lastInvoiceSent > it.invoiceDebounceRate {
+		it.lastInvoiceSent = currentlyElapsed
+		return true, true
+	} else if currentlyElapsed-it.lastInvoiceSent > chargePeriod {
+		it.lastInvoiceSent = currentlyElapsed
+		return true, false
+	}
+	return false, false
+}
+
 const sessionInvoiceIncreaseSlope = 3
 
-func (it *InvoiceTracker) updateMaxUnpaid() {
-	limit := it.deps.LimitNotPaidInvoice
-	if limit == nil || it.deps.MaxNotPaidInvoice.Cmp(limit) >= 0 {
+func (it *InvoiceTracker) invoiceWindow() (time.Duration, *big.Int) {
+	it.windowLock.Lock()
+	defer it.windowLock.Unlock()
+
+	chargePeriod := it.deps.ChargePeriod
+	if it.deps.PowerMode != nil {
+		chargePeriod = it.deps.PowerMode.Interval(chargePeriod, it.deps.LimitChargePeriod)
+	}
+	return chargePeriod, it.deps.MaxNotPaidInvoice
+}
+
+// applyStartingWindow lets sessions of trusted consumers start with the window reached in their previous sessions.
+func (it *InvoiceTracker) applyStartingWindow() {
+	if it.deps.PaymentHistory == nil {
+		return
+	}
+
+	chargePeriod, maxUnpaid, ok := it.deps.PaymentHistory.startingWindow(it.deps.Peer)
+	if !ok {
 		return
 	}
 
-	add := new(big.Int).Div(it.deps.MaxNotPaidInvoice, new(big.Int).SetInt64(sessionInvoiceIncreaseSlope))
-	bigger := new(big.Int).Add(it.deps.MaxNotPaidInvoice, add)
-	if bigger.Cmp(limit) > 0 {
-		bigger = limit
+	it.windowLock.Lock()
+	defer it.windowLock.Unlock()
+
+	if chargePeriod > it.deps.ChargePeriod {
+		it.deps.ChargePeriod = minDuration(chargePeriod, it.deps.LimitChargePeriod)
+	}
+	if maxUnpaid != nil && it.deps.LimitNotPaidInvoice != nil && maxUnpaid.Cmp(it.deps.MaxNotPaidInvoice) > 0 {
+		it.deps.MaxNotPaidInvoice = moneymath.Min(maxUnpaid, it.deps.LimitNotPaidInvoice)
+	}
+	log.Debug().Msgf("Consumer %s is trusted, starting with charge period %s and max unpaid %s", it.deps.Peer.Address, it.deps.ChargePeriod, it.deps.MaxNotPaidInvoice)
+}
+
+// adaptInvoiceWindow grows the invoice window after a prompt payment and shrinks it back after a slow one.
+func (it *InvoiceTracker) adaptInvoiceWindow(latency time.Duration) {
+	prompt := true
+	if it.deps.PaymentHistory != nil {
+		prompt = it.deps.PaymentHistory.recordPayment(it.deps.Peer, latency)
+	}
+
+	it.windowLock.Lock()
+	if prompt {
+		it.updateMaxUnpaid()
+		it.updateTimer()
+	} else {
+		it.shrinkWindow()
 	}
+	chargePeriod, maxUnpaid := it.deps.ChargePeriod, it.deps.MaxNotPaidInvoice
+	it.windowLock.Unlock()
 
-	it.deps.MaxNotPaidInvoice = bigger
+	if it.deps.PaymentHistory != nil {
+		it.deps.PaymentHistory.rememberWindow(it.deps.Peer, chargePeriod, maxUnpaid)
+	}
+}
+
+// resetInvoiceWindow returns to the starting window once the consumer fails to pay an invoice.
+func (it *InvoiceTracker) resetInvoiceWindow() {
+	if it.deps.PaymentHistory != nil {
+		it.deps.PaymentHistory.recordMissedPayment(it.deps.Peer)
+	}
+
+	it.windowLock.Lock()
+	defer it.windowLock.Unlock()
+
+	it.deps.ChargePeriod = it.baseChargePeriod
+	it.deps.MaxNotPaidInvoice = it.baseMaxUnpaid
+	log.Debug().Msgf("Consumer %s missed a payment, invoice window reset", it.deps.Peer.Address)
+}
+
+func (it *InvoiceTracker) updateMaxUnpaid() {
+	limit := it.deps.LimitNotPaidInvoice
+	if limit == nil || it.deps.MaxNotPaidInvoice == nil || it.deps.MaxNotPaidInvoice.Cmp(limit) >= 0 {
+		return
+	}
+
+	add := moneymath.DivInt(it.deps.MaxNotPaidInvoice, sessionInvoiceIncreaseSlope)
+	it.deps.MaxNotPaidInvoice = moneymath.Min(moneymath.Add(it.deps.MaxNotPaidInvoice, add), limit)
 	log.Debug().Str("invoice_amount", it.deps.MaxNotPaidInvoice.String()).Msg("Max invoice amount increased")
 }
 
@@ -404,6 +520,29 @@ func (it *InvoiceTracker) updateTimer() {
 	log.Debug().Int64("change_period (ms)", it.deps.ChargePeriod.Milliseconds()).Msg("Max charge period increased")
 }
 
+func (it *InvoiceTracker) shrinkWindow() {
+	it.deps.ChargePeriod = maxDuration(it.deps.ChargePeriod-it.deps.ChargePeriod/sessionInvoiceIncreaseSlope, it.baseChargePeriod)
+	if it.deps.MaxNotPaidInvoice != nil && it.baseMaxUnpaid != nil {
+		sub := moneymath.DivInt(it.deps.MaxNotPaidInvoice, sessionInvoiceIncreaseSlope)
+		it.deps.MaxNotPaidInvoice = moneymath.Max(moneymath.SubFloor(it.deps.MaxNotPaidInvoice, sub), it.baseMaxUnpaid)
+	}
+	log.Debug().Int64("change_period (ms)", it.deps.ChargePeriod.Milliseconds()).Msg("Consumer paid slowly, invoice window decreased")
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 // WaitFirstInvoice waits for a first invoice to be paid.
 func (it *InvoiceTracker) WaitFirstInvoice(wait time.Duration) error {
 	timeout := time.After(wait)
@@ -483,7 +622,8 @@ func (it *InvoiceTracker) chainID() int64 {
 	return config.GetInt64(config.FlagChainID)
 }
 
-func (it *InvoiceTracker) sendInvoice(isCritical bool) error {
+// checkExchangeMessageCounts returns an error if too many exchange messages were not sent or received.
+func (it *InvoiceTracker) checkExchangeMessageCounts() error {
 	if it.getNotSentExchangeMessageCount() >= it.maxNotSentExchangeMessages {
 		return ErrInvoiceSendMaxFailCountReached
 	}
@@ -491,7 +631,11 @@ func (it *InvoiceTracker) sendInvoice(isCritical bool) error {
 	if it.getNotReceivedExchangeMessageCount() >= it.maxNotReceivedExchangeMessages {
 		return ErrExchangeWaitTimeout
 	}
+	return nil
+}
 
+// invoiceAmount calculates the agreement total of the next invoice.
+func (it *InvoiceTracker) invoiceAmount() *big.Int {
 	shouldBe := CalculatePaymentAmount(it.deps.TimeTracker.Elapsed(), it.getDataTransferred(), it.deps.AgreedPrice)
 
 	lastEm := it.getLastExchangeMessage()
@@ -500,6 +644,15 @@ func (it *InvoiceTracker) sendInvoice(isCritical bool) error {
 		shouldBe = providerFirstInvoiceValue
 		log.Debug().Msgf("Being lenient for the first payment, asking for %v", shouldBe)
 	}
+	return shouldBe
+}
+
+func (it *InvoiceTracker) sendInvoice(isCritical bool) error {
+	if err := it.checkExchangeMessageCounts(); err != nil {
+		return err
+	}
+
+	shouldBe := it.invoiceAmount()
 
 	r, err := crypto.GenerateR()
 	if err != nil {
@@ -520,6 +673,7 @@ func (it *InvoiceTracker) sendInvoice(isCritical bool) error {
 		invoice:    invoice,
 		r:          r,
 		isCritical: isCritical,
+		sentAt:     it.deps.TimeTracker.Elapsed(),
 	})
 
 	hlock, err := hex.DecodeString(invoice.Hashlock)
@@ -536,25 +690,33 @@ func (it *InvoiceTracker) sendInvoice(isCritical bool) error {
 func (it *InvoiceTracker) waitForInvoicePayment(hlock []byte) {
 	select {
 	case <-time.After(it.deps.ExchangeMessageWaitTimeout):
-		inv, ok := it.getMarkedInvoice(hlock)
-		if !ok {
-			return
+		if err := it.expireInvoice(hlock); err != nil {
+			it.criticalInvoiceErrors <- err
 		}
-
-		if inv.isCritical {
-			log.Info().Msgf("did not get paid for invoice with hashlock %v, invoice is critical. Aborting.", inv.invoice.Hashlock)
-			it.criticalInvoiceErrors <- fmt.Errorf("did not get paid for critical invoice with hashlock %v", inv.invoice.Hashlock)
-			return
-		}
-
-		log.Info().Msgf("did not get paid for invoice with hashlock %v, incrementing failure count", inv.invoice.Hashlock)
-		it.markInvoicePaid(hlock)
-		it.markExchangeMessageNotReceived()
 	case <-it.stop:
 		return
 	}
 }
 
+// expireInvoice gives up waiting for the invoice payment. An error is returned if the invoice was critical.
+func (it *InvoiceTracker) expireInvoice(hlock []byte) error {
+	inv, ok := it.getMarkedInvoice(hlock)
+	if !ok {
+		return nil
+	}
+
+	if inv.isCritical {
+		log.Info().Msgf("did not get paid for invoice with hashlock %v, invoice is critical. Aborting.", inv.invoice.Hashlock)
+		return fmt.Errorf("did not get paid for critical invoice with hashlock %v", inv.invoice.Hashlock)
+	}
+
+	log.Info().Msgf("did not get paid for invoice with hashlock %v, incrementing failure count", inv.invoice.Hashlock)
+	it.markInvoicePaid(hlock)
+	it.markExchangeMessageNotReceived()
+	it.resetInvoiceWindow()
+	return nil
+}
+
 func (it *InvoiceTracker) handleHermesError(err error) error {
 	if err == nil {
 		it.resetHermesFailureCount()
@@ -622,9 +784,9 @@ func (it *InvoiceTracker) resetHermesFailureCount() {
 }
 
 func (it *InvoiceTracker) validateExchangeMessage(em crypto.ExchangeMessage) error {
-	peerAddr := common.HexToAddress(it.deps.Peer.Address)
-	if res := em.IsMessageValid(peerAddr); !res {
-		return ErrExchangeValidationFailed
+	signerAddr, err := it.exchangeMessageSigner(em)
+	if err != nil {
+		return err
 	}
 
 	if em.ChainID != it.chainID() {
@@ -636,14 +798,12 @@ func (it *InvoiceTracker) validateExchangeMessage(em crypto.ExchangeMessage) err
 		return errors.Wrap(err, "could not recover promise signature")
 	}
 
-	if signer.Hex() != peerAddr.Hex() {
+	if signer.Hex() != signerAddr.Hex() {
 		return errors.New("identity missmatch")
 	}
 
-	lastEm := it.getLastExchangeMessage()
-	if em.Promise.Amount.Cmp(lastEm.Promise.Amount) == -1 {
-		log.Warn().Msgf("Consumer sent an invalid amount. Expected < %v, got %v", lastEm.Promise.Amount, em.Promise.Amount)
-		return errors.Wrap(ErrConsumerPromiseValidationFailed, "invalid amount")
+	if err := it.validatePromiseAmount(em); err != nil {
+		return err
 	}
 
 	registry, err := it.deps.AddressProvider.GetRegistryAddress(em.ChainID)
@@ -679,6 +839,38 @@ func (it *InvoiceTracker) validateExchangeMessage(em crypto.ExchangeMessage) err
 	return nil
 }
 
+// exchangeMessageSigner returns the key that signed the exchange message: either the peer identity
+// or a session key the peer has delegated payments to.
+func (it *InvoiceTracker) exchangeMessageSigner(em crypto.ExchangeMessage) (common.Address, error) {
+	peerAddr := common.HexToAddress(it.deps.Peer.Address)
+	if em.IsMessageValid(peerAddr) {
+		return peerAddr, nil
+	}
+	if it.deps.Delegations == nil {
+		return common.Address{}, ErrExchangeValidationFailed
+	}
+
+	sessionKey, err := em.RecoverConsumerIdentity()
+	if err != nil {
+		return common.Address{}, ErrExchangeValidationFailed
+	}
+
+	if _, ok := it.deps.Delegations.Get(peerAddr, sessionKey); !ok || !em.IsMessageValid(sessionKey) {
+		return common.Address{}, ErrExchangeValidationFailed
+	}
+	return sessionKey, nil
+}
+
+// validatePromiseAmount checks that the consumer does not decrease the promised amount.
+func (it *InvoiceTracker) validatePromiseAmount(em crypto.ExchangeMessage) error {
+	lastEm := it.getLastExchangeMessage()
+	if em.Promise.Amount.Cmp(lastEm.Promise.Amount) == -1 {
+		log.Warn().Msgf("Consumer sent an invalid amount. Expected < %v, got %v", lastEm.Promise.Amount, em.Promise.Amount)
+		return errors.Wrap(ErrConsumerPromiseValidationFailed, "invalid amount")
+	}
+	return nil
+}
+
 // Stop stops the invoice tracker.
 func (it *InvoiceTracker) Stop() {
 	it.once.Do(func() {
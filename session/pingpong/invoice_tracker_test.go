@@ -511,9 +511,8 @@ func Test_sendsInvoiceIfTimePassed(t *testing.T) {
 	invoiceTracker.Stop()
 
 	<-wait
-	// Test that MaxUnpaid and ChargePeriod Increased
-	assert.Equal(t, time.Millisecond*3, invoiceTracker.deps.ChargePeriod, "charge period should increase up to limit")
-
+	// Invoice window grows only after the consumer pays.
+	assert.Equal(t, time.Millisecond*2, invoiceTracker.deps.ChargePeriod)
 }
 
 func Test_sendsInvoiceIfDataUsed(t *testing.T) {
@@ -549,9 +548,8 @@ func Test_sendsInvoiceIfDataUsed(t *testing.T) {
 	invoiceTracker.Stop()
 
 	<-wait
-	// Test that MaxUnpaid and ChargePeriod Increased
-	assert.Equal(t, big.NewInt(140), invoiceTracker.deps.MaxNotPaidInvoice, "max unpaid invoice should increase up to limit")
-
+	// Invoice window grows only after the consumer pays.
+	assert.Equal(t, big.NewInt(100), invoiceTracker.deps.MaxNotPaidInvoice)
 }
 
 func Test_calculateMaxNotReceivedExchangeMessageCount(t *testing.T) {
@@ -704,8 +702,10 @@ func TestInvoiceTracker_receiveExchangeMessageOrTimeout(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			tracker := session.NewTracker(mbtime.Now)
 			deps := InvoiceTrackerDeps{
 				Peer:                       tt.fields.peer,
+				TimeTracker:                &tracker,
 				ExchangeMessageChan:        tt.fields.exchangeMessageChan,
 				ExchangeMessageWaitTimeout: tt.fields.exchangeMessageWaitTimeout,
 				ConsumersHermesID:          tt.fields.hermesID,
@@ -730,6 +730,69 @@ func TestInvoiceTracker_receiveExchangeMessageOrTimeout(t *testing.T) {
 	}
 }
 
+func TestInvoiceTracker_handleExchangeMessage_ClaimsPromiseWhenHermesTermsFail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "invoice_tracker_test")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	bolt, err := boltdb.NewStorage(dir)
+	assert.Nil(t, err)
+	defer bolt.Close()
+
+	em, addr := generateExchangeMessage(t, big.NewInt(10), crypto.Invoice{AgreementTotal: big.NewInt(10), AgreementID: new(big.Int), TransactorFee: new(big.Int), Hashlock: "0x441Da57A51e42DAB7Daf55909Af93A9b00eEF23C"}, "")
+	r := []byte("r")
+	promises := &mockInvoiceTrackerPromiseHandler{}
+	invoiceStorage := NewProviderInvoiceStorage(NewInvoiceStorage(bolt))
+	tracker := session.NewTracker(mbtime.Now)
+	providerID := identity.FromAddress("0x1")
+	it := &InvoiceTracker{
+		deps: InvoiceTrackerDeps{
+			Peer:               identity.FromAddress(addr),
+			ProviderID:         providerID,
+			TimeTracker:        &tracker,
+			EventBus:           mocks.NewEventBus(),
+			InvoiceStorage:     invoiceStorage,
+			AddressProvider:    &mockAddressProvider{addrToReturn: common.BytesToAddress(em.Promise.ChannelID)},
+			AgreedPrice:        *market.NewPrice(1, 1),
+			PromiseHandler:     promises,
+			HermesTermsChecker: &mockHermesTermsChecker{err: errors.New("hermes is paused")},
+		},
+		agreementID: big.NewInt(1),
+		lastExchangeMessage: crypto.ExchangeMessage{
+			Promise:        crypto.Promise{Amount: new(big.Int), Fee: new(big.Int)},
+			AgreementID:    new(big.Int),
+			AgreementTotal: new(big.Int),
+		},
+		invoicesSent: map[string]sentInvoice{
+			hex.EncodeToString(em.Promise.Hashlock): {r: r, invoice: crypto.Invoice{Hashlock: hex.EncodeToString(em.Promise.Hashlock)}},
+		},
+	}
+
+	assert.EqualError(t, it.handleExchangeMessage(em), "hermes is paused")
+
+	storedR, err := invoiceStorage.GetR(providerID, big.NewInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(r), storedR)
+	assert.Equal(t, 1, promises.requested)
+}
+
+type mockInvoiceTrackerPromiseHandler struct {
+	requested int
+}
+
+func (m *mockInvoiceTrackerPromiseHandler) RequestPromise(r []byte, em crypto.ExchangeMessage, providerID, consumerID identity.Identity, sessionID string) <-chan error {
+	m.requested++
+	return make(chan error)
+}
+
+type mockHermesTermsChecker struct {
+	err error
+}
+
+func (m *mockHermesTermsChecker) Check(chainID int64, hermesID common.Address) error {
+	return m.err
+}
+
 func TestInvoiceTracker_handleHermesError(t *testing.T) {
 	tests := []struct {
 		name                  string
@@ -25,6 +25,7 @@ import (
 
 	"github.com/mysteriumnetwork/node/datasize"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/money/moneymath"
 )
 
 // CalculatePaymentAmount calculates the required payment amount.
@@ -48,7 +49,7 @@ func CalculatePaymentAmount(timePassed time.Duration, bytesTransferred DataTrans
 	tc, _ := timeComponent.Int(nil)
 	bc, _ := dataComponent.Int(nil)
 
-	total := new(big.Int).Add(tc, bc)
+	total := moneymath.Add(tc, bc)
 	log.Debug().Msgf("Calculated price %v. Time component: %v, data component: %v. Transferred: %v, duration: %v. Price %v",
 		total, timeComponent, dataComponent, bytesTransferred.sum(), timePassed.Seconds(), price.String())
 	return total
@@ -22,10 +22,9 @@ import (
 	"math/big"
 	"time"
 
-	"github.com/asdine/storm/v3"
 	"github.com/asdine/storm/v3/q"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/mysteriumnetwork/node/core/storage/boltdb"
+	"github.com/mysteriumnetwork/node/core/storage"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/payments/crypto"
 )
@@ -43,13 +42,13 @@ const (
 
 // SettlementHistoryStorage stores the settlement events for historical purposes.
 type SettlementHistoryStorage struct {
-	bolt *boltdb.Bolt
+	storage storage.Store
 }
 
 // NewSettlementHistoryStorage returns a new instance of the SettlementHistoryStorage.
-func NewSettlementHistoryStorage(bolt *boltdb.Bolt) *SettlementHistoryStorage {
+func NewSettlementHistoryStorage(storage storage.Store) *SettlementHistoryStorage {
 	return &SettlementHistoryStorage{
-		bolt: bolt,
+		storage: storage,
 	}
 }
 
@@ -74,9 +73,7 @@ const settlementHistoryBucket = "settlement-history"
 
 // Store stores a given settlement history entry.
 func (shs *SettlementHistoryStorage) Store(she SettlementHistoryEntry) error {
-	shs.bolt.Lock()
-	defer shs.bolt.Unlock()
-	return shs.bolt.DB().From(settlementHistoryBucket).Save(&she)
+	return shs.storage.Store(settlementHistoryBucket, &she)
 }
 
 // SettlementHistoryFilter defines all flags for filtering in settlement history storage.
@@ -113,22 +110,25 @@ func (shs *SettlementHistoryStorage) List(filter SettlementHistoryFilter) (resul
 		}
 	}
 
-	shs.bolt.RLock()
-	defer shs.bolt.RUnlock()
-	sq := shs.bolt.DB().
-		From(settlementHistoryBucket).
-		Select(q.And(where...)).
-		OrderBy("Time").
-		Reverse()
+	query := storage.Query{
+		Where:   q.And(where...),
+		OrderBy: "Time",
+		Reverse: true,
+	}
 
-	err = sq.Find(&result)
-	if errors.Is(err, storm.ErrNotFound) {
+	err = shs.storage.Find(settlementHistoryBucket, query, &result)
+	if errors.Is(err, storage.ErrNotFound) {
 		return []SettlementHistoryEntry{}, nil
 	}
 
 	return result, err
 }
 
+// PruneBefore deletes entries settled before the given time.
+func (shs *SettlementHistoryStorage) PruneBefore(before time.Time) error {
+	return shs.storage.DeleteMatching(settlementHistoryBucket, q.Lt("Time", before.UTC()), new(SettlementHistoryEntry))
+}
+
 func contains(sources []HistoryType, target HistoryType) bool {
 	for _, source := range sources {
 		if source == target {
@@ -0,0 +1,482 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"container/heap"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/mysteriumnetwork/node/datasize"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/money/moneymath"
+	"github.com/mysteriumnetwork/node/session"
+	"github.com/mysteriumnetwork/node/session/mbtime"
+	"github.com/mysteriumnetwork/payments/crypto"
+)
+
+// Scripted peer behaviours available in payment simulations.
+const (
+	// SimLatePayment delays consumer payments by SimBehavior.Delay.
+	SimLatePayment = "late_payment"
+	// SimDuplicatePromise makes consumer send every exchange message twice.
+	SimDuplicatePromise = "duplicate_promise"
+	// SimHermesOutage makes hermes fail promise requests with SimBehavior.Err.
+	SimHermesOutage = "hermes_outage"
+)
+
+var (
+	simProviderID = identity.FromAddress("0x0000000000000000000000000000000000000001")
+	simConsumerID = identity.FromAddress("0x0000000000000000000000000000000000000002")
+	simHermesID   = common.HexToAddress("0x0000000000000000000000000000000000000003")
+)
+
+// SimBehavior is a peer behaviour active during the given period of simulated session.
+type SimBehavior struct {
+	Type string
+	// From and To limit the period the behaviour is active in, zero To means until the end of session.
+	From, To time.Duration
+	Delay    time.Duration
+	Err      error
+}
+
+func (b SimBehavior) activeAt(at time.Duration) bool {
+	return at >= b.From && (b.To == 0 || at < b.To)
+}
+
+// SimScenario describes a payment session replayed by the simulation.
+// Scenarios with the same seed always produce the same trace.
+type SimScenario struct {
+	Name     string
+	Seed     int64
+	Duration time.Duration
+	Price    market.Price
+	// TrafficRate is an average amount of bytes transferred per second.
+	TrafficRate uint64
+	// NetworkLatency is a maximum one way latency between peers.
+	NetworkLatency time.Duration
+	HermesLatency  time.Duration
+
+	ChargePeriod               time.Duration
+	LimitChargePeriod          time.Duration
+	ChargePeriodLeeway         time.Duration
+	ExchangeMessageWaitTimeout time.Duration
+	MaxNotPaidInvoice          *big.Int
+	LimitNotPaidInvoice        *big.Int
+	MaxHermesFailureCount      uint64
+	DataLeeway                 datasize.BitSize
+
+	Behaviors []SimBehavior
+}
+
+// DefaultSimScenario returns a scenario configured with default payment settings.
+func DefaultSimScenario(name string, seed int64) SimScenario {
+	return SimScenario{
+		Name:     name,
+		Seed:     seed,
+		Duration: 30 * time.Minute,
+		Price: market.Price{
+			PricePerHour: big.NewInt(100_000_000_000_000),
+			PricePerGiB:  big.NewInt(1_000_000_000_000_000),
+		},
+		TrafficRate:                512 * 1024,
+		NetworkLatency:             200 * time.Millisecond,
+		HermesLatency:              500 * time.Millisecond,
+		ChargePeriod:               5 * time.Second,
+		LimitChargePeriod:          5 * time.Minute,
+		ChargePeriodLeeway:         2 * time.Minute,
+		ExchangeMessageWaitTimeout: PromiseWaitTimeout,
+		MaxNotPaidInvoice:          big.NewInt(3_000_000_000_000_000),
+		LimitNotPaidInvoice:        big.NewInt(30_000_000_000_000_000),
+		MaxHermesFailureCount:      DefaultHermesFailureCount,
+		DataLeeway:                 20 * datasize.MiB,
+	}
+}
+
+// SimTraceEntry is a single step of the simulated session.
+type SimTraceEntry struct {
+	At      time.Duration
+	Actor   string
+	Event   string
+	Details string
+}
+
+func (e SimTraceEntry) String() string {
+	return fmt.Sprintf("%12s %-8s %-22s %s", e.At, e.Actor, e.Event, e.Details)
+}
+
+// SimTrace is a sequence of simulation steps.
+type SimTrace []SimTraceEntry
+
+func (t SimTrace) String() string {
+	var sb strings.Builder
+	for _, e := range t {
+		sb.WriteString(e.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Count returns the number of trace entries with the given event.
+func (t SimTrace) Count(event string) int {
+	var n int
+	for _, e := range t {
+		if e.Event == event {
+			n++
+		}
+	}
+	return n
+}
+
+// SimResult is an outcome of the payment simulation.
+type SimResult struct {
+	Trace SimTrace
+	// Err is the error session was terminated with, nil if the session lasted for the whole scenario duration.
+	Err      error
+	Elapsed  time.Duration
+	Invoiced *big.Int
+	Paid     *big.Int
+}
+
+// RunSimulation replays the scenario against invoice tracker and invoice payer using virtual time.
+// Peers communicate through a simulated network instead of p2p channels, signatures are not checked.
+func RunSimulation(scenario SimScenario) SimResult {
+	sim := newSimulation(scenario)
+	sim.run()
+
+	return SimResult{
+		Trace:    sim.trace,
+		Err:      sim.err,
+		Elapsed:  sim.clock.now,
+		Invoiced: sim.invoiced,
+		Paid:     sim.tracker.getLastExchangeMessage().AgreementTotal,
+	}
+}
+
+type simEvent struct {
+	at  time.Duration
+	seq uint64
+	fn  func()
+}
+
+type simEventQueue []*simEvent
+
+func (q simEventQueue) Len() int { return len(q) }
+func (q simEventQueue) Less(i, j int) bool {
+	if q[i].at == q[j].at {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].at < q[j].at
+}
+func (q simEventQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *simEventQueue) Push(x interface{}) { *q = append(*q, x.(*simEvent)) }
+func (q *simEventQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// simClock is a virtual clock which executes scheduled events in order.
+type simClock struct {
+	now   time.Duration
+	seq   uint64
+	queue simEventQueue
+}
+
+func (c *simClock) Now() mbtime.Time {
+	return mbtime.New(0, int64(c.now))
+}
+
+func (c *simClock) schedule(after time.Duration, fn func()) {
+	c.seq++
+	heap.Push(&c.queue, &simEvent{at: c.now + after, seq: c.seq, fn: fn})
+}
+
+func (c *simClock) step() bool {
+	if len(c.queue) == 0 {
+		return false
+	}
+	e := heap.Pop(&c.queue).(*simEvent)
+	c.now = e.at
+	e.fn()
+	return true
+}
+
+type simAddressProvider struct {
+	addressProvider
+}
+
+func (ap *simAddressProvider) GetActiveHermes(chainID int64) (common.Address, error) {
+	return simHermesID, nil
+}
+
+type simulation struct {
+	scenario SimScenario
+	clock    *simClock
+	rng      *rand.Rand
+	tracker  *InvoiceTracker
+	payer    *InvoicePayer
+
+	transferred DataTransferred
+	invoiceSeq  int
+	invoiceNums map[string]int
+	invoiced    *big.Int
+	promised    *big.Int
+
+	trace SimTrace
+	done  bool
+	err   error
+}
+
+func newSimulation(scenario SimScenario) *simulation {
+	clock := &simClock{}
+	providerTime := session.NewTracker(clock.Now)
+	consumerTime := session.NewTracker(clock.Now)
+
+	tracker := NewInvoiceTracker(InvoiceTrackerDeps{
+		AgreedPrice:                scenario.Price,
+		Peer:                       simConsumerID,
+		TimeTracker:                &providerTime,
+		ChargePeriodLeeway:         scenario.ChargePeriodLeeway,
+		ExchangeMessageWaitTimeout: scenario.ExchangeMessageWaitTimeout,
+		ProviderID:                 simProviderID,
+		ConsumersHermesID:          simHermesID,
+		AddressProvider:            &simAddressProvider{},
+		MaxHermesFailureCount:      scenario.MaxHermesFailureCount,
+		EventBus:                   eventbus.New(),
+		ChargePeriod:               scenario.ChargePeriod,
+		LimitChargePeriod:          scenario.LimitChargePeriod,
+		LimitNotPaidInvoice:        scenario.LimitNotPaidInvoice,
+		MaxNotPaidInvoice:          scenario.MaxNotPaidInvoice,
+	})
+	payer := NewInvoicePayer(InvoicePayerDeps{
+		TimeTracker: &consumerTime,
+		Identity:    simConsumerID,
+		Peer:        simProviderID,
+		AgreedPrice: scenario.Price,
+		DataLeeway:  scenario.DataLeeway,
+	})
+
+	return &simulation{
+		scenario:    scenario,
+		clock:       clock,
+		rng:         rand.New(rand.NewSource(scenario.Seed)),
+		tracker:     tracker,
+		payer:       payer,
+		invoiceNums: make(map[string]int),
+		invoiced:    new(big.Int),
+		promised:    new(big.Int),
+	}
+}
+
+func (s *simulation) run() {
+	s.record("provider", "session_started", s.scenario.Name)
+	s.tracker.deps.TimeTracker.StartTracking()
+	s.payer.deps.TimeTracker.StartTracking()
+	s.tracker.agreementID = new(big.Int).SetBytes(s.randomBytes(32))
+
+	s.sendInvoice(true)
+	s.clock.schedule(time.Second, s.tick)
+	s.clock.schedule(s.scenario.Duration, func() {
+		s.record("provider", "session_finished", "")
+		s.done = true
+	})
+
+	for !s.done {
+		if !s.clock.step() {
+			return
+		}
+	}
+}
+
+func (s *simulation) record(actor, event, details string) {
+	s.trace = append(s.trace, SimTraceEntry{
+		At:      s.clock.now,
+		Actor:   actor,
+		Event:   event,
+		Details: details,
+	})
+}
+
+func (s *simulation) fail(actor string, err error) {
+	s.record(actor, "session_terminated", err.Error())
+	s.err = err
+	s.done = true
+}
+
+func (s *simulation) behavior(kind string) (SimBehavior, bool) {
+	for _, b := range s.scenario.Behaviors {
+		if b.Type == kind && b.activeAt(s.clock.now) {
+			return b, true
+		}
+	}
+	return SimBehavior{}, false
+}
+
+func (s *simulation) randomBytes(n int) []byte {
+	b := make([]byte, n)
+	s.rng.Read(b)
+	return b
+}
+
+func (s *simulation) latency() time.Duration {
+	if s.scenario.NetworkLatency <= 0 {
+		return 0
+	}
+	return time.Duration(s.rng.Int63n(int64(s.scenario.NetworkLatency))) + 1
+}
+
+// tick mirrors the periodic invoice check of the provider, traffic is accounted on each tick.
+func (s *simulation) tick() {
+	if s.scenario.TrafficRate > 0 {
+		jitter := s.rng.Int63n(int64(s.scenario.TrafficRate)/2+1) - int64(s.scenario.TrafficRate)/4
+		down := uint64(int64(s.scenario.TrafficRate) + jitter)
+		s.transferred.Down += down - down/10
+		s.transferred.Up += down / 10
+		s.tracker.updateDataTransfer(s.transferred.Up, s.transferred.Down)
+		s.payer.updateDataTransfer(s.transferred.Up, s.transferred.Down)
+	}
+
+	if due, critical := s.tracker.invoiceDue(); due {
+		s.sendInvoice(critical)
+	}
+	s.clock.schedule(time.Second, s.tick)
+}
+
+func (s *simulation) sendInvoice(critical bool) {
+	if err := s.tracker.checkExchangeMessageCounts(); err != nil {
+		s.fail("provider", err)
+		return
+	}
+
+	amount := s.tracker.invoiceAmount()
+	r := s.randomBytes(32)
+	invoice, err := crypto.CreateInvoice(s.tracker.agreementID, amount, new(big.Int), r, s.tracker.chainID())
+	if err != nil {
+		s.fail("provider", err)
+		return
+	}
+	invoice.Provider = simProviderID.Address
+
+	s.invoiceSeq++
+	num := s.invoiceSeq
+	s.invoiceNums[invoice.Hashlock] = num
+	s.invoiced = amount
+	s.tracker.markInvoiceSent(sentInvoice{invoice: invoice, r: r, isCritical: critical, sentAt: s.tracker.deps.TimeTracker.Elapsed()})
+	s.record("provider", "invoice_sent", fmt.Sprintf("#%d total=%v critical=%v", num, amount, critical))
+
+	hlock, _ := hex.DecodeString(invoice.Hashlock)
+	s.clock.schedule(s.scenario.ExchangeMessageWaitTimeout, func() {
+		if _, ok := s.tracker.getMarkedInvoice(hlock); !ok {
+			return
+		}
+		if err := s.tracker.expireInvoice(hlock); err != nil {
+			s.fail("provider", err)
+			return
+		}
+		s.record("provider", "invoice_expired", fmt.Sprintf("#%d", num))
+	})
+	s.clock.schedule(s.latency(), func() { s.receiveInvoice(invoice) })
+}
+
+func (s *simulation) receiveInvoice(invoice crypto.Invoice) {
+	num := s.invoiceNums[invoice.Hashlock]
+	if err := s.payer.isInvoiceOK(invoice); err != nil {
+		s.fail("consumer", fmt.Errorf("invoice #%d not valid: %w", num, err))
+		return
+	}
+
+	diff := moneymath.SubFloor(invoice.AgreementTotal, s.payer.lastInvoice.AgreementTotal)
+	s.promised = new(big.Int).Add(s.promised, diff)
+	s.payer.lastInvoice = invoice
+
+	hlock, _ := hex.DecodeString(invoice.Hashlock)
+	em := crypto.ExchangeMessage{
+		Promise: crypto.Promise{
+			Amount:   s.promised,
+			Fee:      new(big.Int),
+			Hashlock: hlock,
+		},
+		AgreementID:    invoice.AgreementID,
+		AgreementTotal: invoice.AgreementTotal,
+		Provider:       invoice.Provider,
+		HermesID:       simHermesID.Hex(),
+		ChainID:        invoice.ChainID,
+	}
+
+	delay := s.latency()
+	if b, ok := s.behavior(SimLatePayment); ok {
+		delay += b.Delay
+		s.record("consumer", "payment_delayed", fmt.Sprintf("#%d by %v", num, b.Delay))
+	}
+	s.record("consumer", "promise_issued", fmt.Sprintf("#%d amount=%v", num, s.promised))
+	s.clock.schedule(delay, func() { s.receiveExchangeMessage(em, num) })
+
+	if _, ok := s.behavior(SimDuplicatePromise); ok {
+		s.record("consumer", "promise_duplicated", fmt.Sprintf("#%d", num))
+		s.clock.schedule(delay+s.latency(), func() { s.receiveExchangeMessage(em, num) })
+	}
+}
+
+func (s *simulation) receiveExchangeMessage(em crypto.ExchangeMessage, num int) {
+	invoice, ok := s.tracker.getMarkedInvoice(em.Promise.Hashlock)
+	if !ok {
+		s.record("provider", "promise_skipped", fmt.Sprintf("#%d: %v", num, ErrInvoiceExpired))
+		return
+	}
+	if err := s.tracker.validatePromiseAmount(em); err != nil {
+		s.fail("provider", err)
+		return
+	}
+
+	s.tracker.markExchangeMessageReceived(em)
+	s.tracker.adaptInvoiceWindow(s.tracker.deps.TimeTracker.Elapsed() - invoice.sentAt)
+	s.record("provider", "invoice_paid", fmt.Sprintf("#%d total=%v", num, em.AgreementTotal))
+
+	if s.tracker.deps.AgreedPrice.IsFree() {
+		return
+	}
+
+	s.clock.schedule(s.scenario.HermesLatency, func() { s.hermesResponse(num) })
+}
+
+func (s *simulation) hermesResponse(num int) {
+	var hermesErr error
+	if b, ok := s.behavior(SimHermesOutage); ok {
+		hermesErr = b.Err
+		if hermesErr == nil {
+			hermesErr = ErrHermesInternal
+		}
+		s.record("hermes", "promise_failed", fmt.Sprintf("#%d: %v", num, hermesErr))
+	} else {
+		s.record("hermes", "promise_exchanged", fmt.Sprintf("#%d", num))
+	}
+
+	if err := s.tracker.handleHermesError(hermesErr); err != nil {
+		s.fail("provider", err)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package pingpong
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSimulation_IsReproducible(t *testing.T) {
+	scenario := DefaultSimScenario("reproducible", 42)
+	scenario.Behaviors = []SimBehavior{
+		{Type: SimDuplicatePromise, From: time.Minute, To: 2 * time.Minute},
+		{Type: SimHermesOutage, From: 3 * time.Minute, To: 4 * time.Minute},
+	}
+
+	first := RunSimulation(scenario)
+	second := RunSimulation(scenario)
+
+	assert.Equal(t, first.Trace.String(), second.Trace.String())
+	assert.Equal(t, first.Invoiced, second.Invoiced)
+	assert.Equal(t, first.Paid, second.Paid)
+}
+
+func TestRunSimulation_HappyPath(t *testing.T) {
+	result := RunSimulation(DefaultSimScenario("happy path", 1))
+
+	assert.NoError(t, result.Err, result.Trace.String())
+	assert.Equal(t, 30*time.Minute, result.Elapsed)
+	assert.Equal(t, 1, result.Trace.Count("session_finished"))
+	assert.Equal(t, result.Trace.Count("invoice_sent"), result.Trace.Count("invoice_paid"))
+	assert.Zero(t, result.Trace.Count("invoice_expired"))
+	assert.Equal(t, 1, result.Paid.Sign())
+	assert.True(t, result.Paid.Cmp(result.Invoiced) <= 0)
+}
+
+func TestRunSimulation_LatePayments(t *testing.T) {
+	t.Run("first invoice not paid in time", func(t *testing.T) {
+		scenario := DefaultSimScenario("late first payment", 1)
+		scenario.Behaviors = []SimBehavior{
+			{Type: SimLatePayment, Delay: time.Minute},
+		}
+
+		result := RunSimulation(scenario)
+
+		assert.Error(t, result.Err)
+		assert.Contains(t, result.Err.Error(), "critical invoice")
+		assert.Equal(t, scenario.ExchangeMessageWaitTimeout, result.Elapsed)
+	})
+
+	t.Run("late payments are skipped", func(t *testing.T) {
+		scenario := DefaultSimScenario("late payments", 1)
+		scenario.Behaviors = []SimBehavior{
+			{Type: SimLatePayment, From: time.Minute, To: 3 * time.Minute, Delay: 2 * time.Minute},
+		}
+
+		result := RunSimulation(scenario)
+
+		assert.NoError(t, result.Err, result.Trace.String())
+		assert.NotZero(t, result.Trace.Count("invoice_expired"))
+		assert.Equal(t, result.Trace.Count("invoice_expired"), result.Trace.Count("promise_skipped"))
+	})
+
+	t.Run("late payments beyond leeway terminate session", func(t *testing.T) {
+		scenario := DefaultSimScenario("sustained late payments", 1)
+		scenario.Behaviors = []SimBehavior{
+			{Type: SimLatePayment, From: time.Minute, To: 5 * time.Minute, Delay: 2 * time.Minute},
+		}
+
+		result := RunSimulation(scenario)
+
+		assert.True(t, errors.Is(result.Err, ErrExchangeWaitTimeout), result.Trace.String())
+		assert.Less(t, result.Elapsed, 5*time.Minute)
+	})
+}
+
+func TestRunSimulation_DuplicatePromises(t *testing.T) {
+	scenario := DefaultSimScenario("duplicate promises", 1)
+	scenario.Behaviors = []SimBehavior{
+		{Type: SimDuplicatePromise},
+	}
+
+	result := RunSimulation(scenario)
+
+	assert.NoError(t, result.Err, result.Trace.String())
+	assert.Equal(t, result.Trace.Count("promise_duplicated"), result.Trace.Count("promise_skipped"))
+	assert.Equal(t, result.Trace.Count("invoice_paid"), result.Trace.Count("promise_issued"))
+}
+
+func TestRunSimulation_HermesOutage(t *testing.T) {
+	t.Run("short outage is tolerated", func(t *testing.T) {
+		scenario := DefaultSimScenario("short hermes outage", 1)
+		scenario.Behaviors = []SimBehavior{
+			{Type: SimHermesOutage, From: time.Minute, To: 2 * time.Minute},
+		}
+
+		result := RunSimulation(scenario)
+
+		assert.NoError(t, result.Err, result.Trace.String())
+		assert.NotZero(t, result.Trace.Count("promise_failed"))
+	})
+
+	t.Run("long outage terminates session", func(t *testing.T) {
+		scenario := DefaultSimScenario("long hermes outage", 1)
+		scenario.MaxHermesFailureCount = 3
+		scenario.Behaviors = []SimBehavior{
+			{Type: SimHermesOutage, From: time.Minute},
+		}
+
+		result := RunSimulation(scenario)
+
+		assert.True(t, errors.Is(result.Err, ErrHermesInternal))
+		assert.Equal(t, 4, result.Trace.Count("promise_failed"))
+	})
+
+	t.Run("critical hermes error terminates session", func(t *testing.T) {
+		scenario := DefaultSimScenario("consumer overspends", 1)
+		scenario.Behaviors = []SimBehavior{
+			{Type: SimHermesOutage, From: time.Minute, Err: ErrHermesOverspend},
+		}
+
+		result := RunSimulation(scenario)
+
+		assert.True(t, errors.Is(result.Err, ErrHermesOverspend))
+		assert.Equal(t, 1, result.Trace.Count("promise_failed"))
+	})
+}
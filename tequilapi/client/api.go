@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+//go:generate go run generator/generator.go --spec ../docs/swagger.json --output api_generated.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mysteriumnetwork/node/requests"
+)
+
+// API is a typed Tequilapi client. Its operations are generated from the
+// Swagger specification, so every documented endpoint is available here.
+type API struct {
+	http      httpRequestInterface
+	baseURL   string
+	ua        string
+	authToken string
+}
+
+// NewAPI returns a new typed Tequilapi client for the given base URL, e.g. http://127.0.0.1:4050.
+func NewAPI(baseURL string) *API {
+	return &API{
+		http:    requests.NewHTTPClient("0.0.0.0", 100*time.Second),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		ua:      "goclient-v0.1",
+	}
+}
+
+// SetToken sets the bearer token sent with each request, either a UI session token or a scoped API token.
+func (api *API) SetToken(token string) {
+	api.authToken = token
+}
+
+// do executes the request and decodes successful response into result. Error responses are
+// decoded into result too when their status is listed in resultStatuses.
+func (api *API) do(ctx context.Context, method, path string, query url.Values, body, result interface{}, resultStatuses ...int) error {
+	fullPath := api.baseURL + path
+	if params := query.Encode(); params != "" {
+		fullPath += "?" + params
+	}
+
+	var payload io.Reader
+	if body != nil {
+		payloadJSON, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(payloadJSON)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, fullPath, payload)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("User-Agent", api.ua)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	if api.authToken != "" {
+		request.Header.Set("Authorization", "Bearer "+api.authToken)
+	}
+
+	response, err := api.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if !containsStatus(resultStatuses, response.StatusCode) {
+		if err := parseResponseError(response); err != nil {
+			return err
+		}
+	}
+
+	switch result := result.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*result, err = io.ReadAll(response.Body)
+		return err
+	default:
+		if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+			return fmt.Errorf("could not decode %s %s response: %w", method, path, err)
+		}
+		return nil
+	}
+}
+
+func pathParam(value interface{}) string {
+	return url.PathEscape(fmt.Sprint(value))
+}
+
+func containsStatus(statuses []int, status int) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
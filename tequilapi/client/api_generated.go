@@ -0,0 +1,1700 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Code generated by generator.go from Tequilapi Swagger specification. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+// AccessPolicies calls GET /access-policies: Returns access policies.
+func (api *API) AccessPolicies(ctx context.Context) (result AccessPolicies, err error) {
+	err = api.do(ctx, http.MethodGet, "/access-policies", nil, nil, &result)
+	return result, err
+}
+
+// ConsumerLists calls GET /access-policy/consumers: Returns consumer lists.
+func (api *API) ConsumerLists(ctx context.Context) (result contract.ConsumerListsDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/access-policy/consumers", nil, nil, &result)
+	return result, err
+}
+
+// RemoveConsumerFromListParams holds parameters of RemoveConsumerFromList operation.
+type RemoveConsumerFromListParams struct {
+	List string
+	ID   string
+}
+
+// RemoveConsumerFromList calls DELETE /access-policy/consumers/{list}/{id}: Removes consumer from the list.
+func (api *API) RemoveConsumerFromList(ctx context.Context, params RemoveConsumerFromListParams) error {
+	return api.do(ctx, http.MethodDelete, "/access-policy/consumers/"+pathParam(params.List)+"/"+pathParam(params.ID), nil, nil, nil)
+}
+
+// AddConsumerToListParams holds parameters of AddConsumerToList operation.
+type AddConsumerToListParams struct {
+	List string
+	ID   string
+}
+
+// AddConsumerToList calls PUT /access-policy/consumers/{list}/{id}: Adds consumer to the list.
+func (api *API) AddConsumerToList(ctx context.Context, params AddConsumerToListParams) (result contract.ConsumerListsDTO, err error) {
+	err = api.do(ctx, http.MethodPut, "/access-policy/consumers/"+pathParam(params.List)+"/"+pathParam(params.ID), nil, nil, &result)
+	return result, err
+}
+
+// AffiliatorTokenRewardParams holds parameters of AffiliatorTokenReward operation.
+type AffiliatorTokenRewardParams struct {
+	Token string
+}
+
+// AffiliatorTokenReward calls POST /affiliator/token/{token}/reward: Returns the amount of reward for a token (affiliator).
+func (api *API) AffiliatorTokenReward(ctx context.Context, params AffiliatorTokenRewardParams) (result contract.TokenRewardAmount, err error) {
+	err = api.do(ctx, http.MethodPost, "/affiliator/token/"+pathParam(params.Token)+"/reward", nil, nil, &result)
+	return result, err
+}
+
+// AuditListParams holds parameters of AuditList operation.
+type AuditListParams struct {
+	Since *time.Time
+	Until *time.Time
+	Actor *string
+	Limit *int64
+}
+
+// AuditList calls GET /audit: Returns audit log.
+func (api *API) AuditList(ctx context.Context, params AuditListParams) (result contract.AuditEntryListDTO, err error) {
+	query := url.Values{}
+	if params.Since != nil {
+		query.Set("since", (*params.Since).Format(time.RFC3339))
+	}
+	if params.Until != nil {
+		query.Set("until", (*params.Until).Format(time.RFC3339))
+	}
+	if params.Actor != nil {
+		query.Set("actor", *params.Actor)
+	}
+	if params.Limit != nil {
+		query.Set("limit", strconv.FormatInt(*params.Limit, 10))
+	}
+	err = api.do(ctx, http.MethodGet, "/audit", query, nil, &result)
+	return result, err
+}
+
+// AuthenticateParams holds parameters of Authenticate operation.
+type AuthenticateParams struct {
+	Body contract.AuthRequest
+}
+
+// Authenticate calls POST /auth/authenticate: Authenticate.
+func (api *API) Authenticate(ctx context.Context, params AuthenticateParams) (result contract.AuthResponse, err error) {
+	err = api.do(ctx, http.MethodPost, "/auth/authenticate", nil, params.Body, &result)
+	return result, err
+}
+
+// LoginParams holds parameters of Login operation.
+type LoginParams struct {
+	Body contract.AuthRequest
+}
+
+// Login calls POST /auth/login: Login.
+func (api *API) Login(ctx context.Context, params LoginParams) (result contract.AuthResponse, err error) {
+	err = api.do(ctx, http.MethodPost, "/auth/login", nil, params.Body, &result)
+	return result, err
+}
+
+// Logout calls DELETE /auth/logout: Logout.
+func (api *API) Logout(ctx context.Context) error {
+	return api.do(ctx, http.MethodDelete, "/auth/logout", nil, nil, nil)
+}
+
+// ChangePasswordParams holds parameters of ChangePassword operation.
+type ChangePasswordParams struct {
+	Body contract.ChangePasswordRequest
+}
+
+// ChangePassword calls PUT /auth/password: Change password.
+func (api *API) ChangePassword(ctx context.Context, params ChangePasswordParams) error {
+	return api.do(ctx, http.MethodPut, "/auth/password", nil, params.Body, nil)
+}
+
+// ListTokens calls GET /auth/tokens: List API tokens.
+func (api *API) ListTokens(ctx context.Context) (result contract.APITokenListResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/auth/tokens", nil, nil, &result)
+	return result, err
+}
+
+// IssueTokenParams holds parameters of IssueToken operation.
+type IssueTokenParams struct {
+	Body contract.APITokenRequest
+}
+
+// IssueToken calls POST /auth/tokens: Issue API token.
+func (api *API) IssueToken(ctx context.Context, params IssueTokenParams) (result contract.APITokenResponse, err error) {
+	err = api.do(ctx, http.MethodPost, "/auth/tokens", nil, params.Body, &result)
+	return result, err
+}
+
+// RevokeTokenParams holds parameters of RevokeToken operation.
+type RevokeTokenParams struct {
+	ID string
+}
+
+// RevokeToken calls DELETE /auth/tokens/{id}: Revoke API token.
+func (api *API) RevokeToken(ctx context.Context, params RevokeTokenParams) error {
+	return api.do(ctx, http.MethodDelete, "/auth/tokens/"+pathParam(params.ID), nil, nil, nil)
+}
+
+// RotateTokenParams holds parameters of RotateToken operation.
+type RotateTokenParams struct {
+	ID   string
+	Body contract.AuthRequest
+}
+
+// RotateToken calls POST /auth/tokens/{id}/rotate: Rotate API token.
+func (api *API) RotateToken(ctx context.Context, params RotateTokenParams) (result contract.APITokenResponse, err error) {
+	err = api.do(ctx, http.MethodPost, "/auth/tokens/"+pathParam(params.ID)+"/rotate", nil, params.Body, &result)
+	return result, err
+}
+
+// GetConfig calls GET /config: Returns current configuration values.
+func (api *API) GetConfig(ctx context.Context) (result ConfigPayload, err error) {
+	err = api.do(ctx, http.MethodGet, "/config", nil, nil, &result)
+	return result, err
+}
+
+// GetDefaultConfig calls GET /config/default: Returns default configuration.
+func (api *API) GetDefaultConfig(ctx context.Context) (result ConfigPayload, err error) {
+	err = api.do(ctx, http.MethodGet, "/config/default", nil, nil, &result)
+	return result, err
+}
+
+// SetConfigProfileParams holds parameters of SetConfigProfile operation.
+type SetConfigProfileParams struct {
+	Body contract.ConfigProfileRequest
+}
+
+// SetConfigProfile calls PUT /config/profile: Selects configuration profile.
+func (api *API) SetConfigProfile(ctx context.Context, params SetConfigProfileParams) (result contract.ConfigProfilesDTO, err error) {
+	err = api.do(ctx, http.MethodPut, "/config/profile", nil, params.Body, &result)
+	return result, err
+}
+
+// GetConfigProfiles calls GET /config/profiles: Returns configuration profiles.
+func (api *API) GetConfigProfiles(ctx context.Context) (result contract.ConfigProfilesDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/config/profiles", nil, nil, &result)
+	return result, err
+}
+
+// GetUserConfig calls GET /config/user: Returns current user configuration.
+func (api *API) GetUserConfig(ctx context.Context) (result ConfigPayload, err error) {
+	err = api.do(ctx, http.MethodGet, "/config/user", nil, nil, &result)
+	return result, err
+}
+
+// SerUserConfigParams holds parameters of SerUserConfig operation.
+type SerUserConfigParams struct {
+	Body ConfigPayload
+}
+
+// SerUserConfig calls POST /config/user: Sets and returns user configuration.
+func (api *API) SerUserConfig(ctx context.Context, params SerUserConfigParams) (result ConfigPayload, err error) {
+	err = api.do(ctx, http.MethodPost, "/config/user", nil, params.Body, &result)
+	return result, err
+}
+
+// ListWebhooks calls GET /config/webhooks: Returns registered webhooks.
+func (api *API) ListWebhooks(ctx context.Context) (result contract.WebhookListDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/config/webhooks", nil, nil, &result)
+	return result, err
+}
+
+// CreateWebhookParams holds parameters of CreateWebhook operation.
+type CreateWebhookParams struct {
+	Body contract.WebhookRequest
+}
+
+// CreateWebhook calls POST /config/webhooks: Registers a webhook.
+func (api *API) CreateWebhook(ctx context.Context, params CreateWebhookParams) (result contract.WebhookDTO, err error) {
+	err = api.do(ctx, http.MethodPost, "/config/webhooks", nil, params.Body, &result)
+	return result, err
+}
+
+// DeleteWebhookParams holds parameters of DeleteWebhook operation.
+type DeleteWebhookParams struct {
+	ID string
+}
+
+// DeleteWebhook calls DELETE /config/webhooks/{id}: Removes a webhook.
+func (api *API) DeleteWebhook(ctx context.Context, params DeleteWebhookParams) error {
+	return api.do(ctx, http.MethodDelete, "/config/webhooks/"+pathParam(params.ID), nil, nil, nil)
+}
+
+// ConnectionCancel calls DELETE /connection: Stops connection.
+func (api *API) ConnectionCancel(ctx context.Context) error {
+	return api.do(ctx, http.MethodDelete, "/connection", nil, nil, nil)
+}
+
+// ConnectionStatus calls GET /connection: Returns connection status.
+func (api *API) ConnectionStatus(ctx context.Context) (result contract.ConnectionInfoDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/connection", nil, nil, &result)
+	return result, err
+}
+
+// ConnectionCreateParams holds parameters of ConnectionCreate operation.
+type ConnectionCreateParams struct {
+	Body contract.ConnectionCreateRequest
+}
+
+// ConnectionCreate calls PUT /connection: Starts new connection.
+func (api *API) ConnectionCreate(ctx context.Context, params ConnectionCreateParams) (result contract.ConnectionInfoDTO, err error) {
+	err = api.do(ctx, http.MethodPut, "/connection", nil, params.Body, &result)
+	return result, err
+}
+
+// GetConnectionIP calls GET /connection/ip: Returns IP address.
+func (api *API) GetConnectionIP(ctx context.Context) (result contract.IPDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/connection/ip", nil, nil, &result)
+	return result, err
+}
+
+// GetConnectionLocation calls GET /connection/location: Returns connection location.
+func (api *API) GetConnectionLocation(ctx context.Context) (result contract.LocationDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/connection/location", nil, nil, &result)
+	return result, err
+}
+
+// ConnectionNotices calls GET /connection/notices: Returns provider notices.
+func (api *API) ConnectionNotices(ctx context.Context) (result contract.ListNoticesResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/connection/notices", nil, nil, &result)
+	return result, err
+}
+
+// DeleteConnectionProfileParams holds parameters of DeleteConnectionProfile operation.
+type DeleteConnectionProfileParams struct {
+	Name string
+}
+
+// DeleteConnectionProfile calls DELETE /connection/profile/{name}: Removes connection profile.
+func (api *API) DeleteConnectionProfile(ctx context.Context, params DeleteConnectionProfileParams) error {
+	return api.do(ctx, http.MethodDelete, "/connection/profile/"+pathParam(params.Name), nil, nil, nil)
+}
+
+// GetConnectionProfileParams holds parameters of GetConnectionProfile operation.
+type GetConnectionProfileParams struct {
+	Name string
+}
+
+// GetConnectionProfile calls GET /connection/profile/{name}: Returns connection profile.
+func (api *API) GetConnectionProfile(ctx context.Context, params GetConnectionProfileParams) (result contract.ConnectionProfileDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/connection/profile/"+pathParam(params.Name), nil, nil, &result)
+	return result, err
+}
+
+// SaveConnectionProfileParams holds parameters of SaveConnectionProfile operation.
+type SaveConnectionProfileParams struct {
+	Name string
+	Body contract.ConnectionProfileDTO
+}
+
+// SaveConnectionProfile calls PUT /connection/profile/{name}: Creates or replaces connection profile.
+func (api *API) SaveConnectionProfile(ctx context.Context, params SaveConnectionProfileParams) (result contract.ConnectionProfileDTO, err error) {
+	err = api.do(ctx, http.MethodPut, "/connection/profile/"+pathParam(params.Name), nil, params.Body, &result)
+	return result, err
+}
+
+// ActivateConnectionProfileParams holds parameters of ActivateConnectionProfile operation.
+type ActivateConnectionProfileParams struct {
+	Name string
+}
+
+// ActivateConnectionProfile calls PUT /connection/profile/{name}/activate: Activates connection profile.
+func (api *API) ActivateConnectionProfile(ctx context.Context, params ActivateConnectionProfileParams) (result contract.ConnectionInfoDTO, err error) {
+	err = api.do(ctx, http.MethodPut, "/connection/profile/"+pathParam(params.Name)+"/activate", nil, nil, &result)
+	return result, err
+}
+
+// ListConnectionProfiles calls GET /connection/profiles: Returns connection profiles.
+func (api *API) ListConnectionProfiles(ctx context.Context) (result contract.ConnectionProfileListDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/connection/profiles", nil, nil, &result)
+	return result, err
+}
+
+// ReplaceConnectionProfilesParams holds parameters of ReplaceConnectionProfiles operation.
+type ReplaceConnectionProfilesParams struct {
+	Body contract.ConnectionProfileListDTO
+}
+
+// ReplaceConnectionProfiles calls PUT /connection/profiles: Replaces connection profiles.
+func (api *API) ReplaceConnectionProfiles(ctx context.Context, params ReplaceConnectionProfilesParams) (result contract.ConnectionProfileListDTO, err error) {
+	err = api.do(ctx, http.MethodPut, "/connection/profiles", nil, params.Body, &result)
+	return result, err
+}
+
+// GetProxyIP calls GET /connection/proxy/ip: Returns IP address.
+func (api *API) GetProxyIP(ctx context.Context) (result contract.IPDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/connection/proxy/ip", nil, nil, &result)
+	return result, err
+}
+
+// GetProxyLocation calls GET /connection/proxy/location: Returns proxy connection location.
+func (api *API) GetProxyLocation(ctx context.Context) (result contract.LocationDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/connection/proxy/location", nil, nil, &result)
+	return result, err
+}
+
+// ConnectionSpeedTestParams holds parameters of ConnectionSpeedTest operation.
+type ConnectionSpeedTestParams struct {
+	ID *int
+}
+
+// ConnectionSpeedTest calls POST /connection/speedtest: Runs a speed test through the current connection.
+func (api *API) ConnectionSpeedTest(ctx context.Context, params ConnectionSpeedTestParams) (result contract.SpeedTestResultDTO, err error) {
+	query := url.Values{}
+	if params.ID != nil {
+		query.Set("id", strconv.Itoa(*params.ID))
+	}
+	err = api.do(ctx, http.MethodPost, "/connection/speedtest", query, nil, &result)
+	return result, err
+}
+
+// ConnectionStatistics calls GET /connection/statistics: Returns connection statistics.
+func (api *API) ConnectionStatistics(ctx context.Context) (result contract.ConnectionStatisticsDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/connection/statistics", nil, nil, &result)
+	return result, err
+}
+
+// ConnectionTraffic calls GET /connection/traffic: Returns connection traffic information.
+func (api *API) ConnectionTraffic(ctx context.Context) (result contract.ConnectionTrafficDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/connection/traffic", nil, nil, &result)
+	return result, err
+}
+
+// DiagnosticsBundle calls POST /diagnostics/bundle: Creates diagnostics bundle.
+func (api *API) DiagnosticsBundle(ctx context.Context) (result []byte, err error) {
+	err = api.do(ctx, http.MethodPost, "/diagnostics/bundle", nil, nil, &result)
+	return result, err
+}
+
+// DNSBlocklist calls GET /dns/blocklist: Returns domain blocklist state.
+func (api *API) DNSBlocklist(ctx context.Context) (result contract.DNSBlocklistDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/dns/blocklist", nil, nil, &result)
+	return result, err
+}
+
+// EstimateParams holds parameters of Estimate operation.
+type EstimateParams struct {
+	Amount int
+}
+
+// Estimate calls GET /entertainment: Estimate entertainment durations/data cap for the MYST amount specified.
+func (api *API) Estimate(ctx context.Context, params EstimateParams) (result contract.EntertainmentEstimateResponse, err error) {
+	query := url.Values{}
+	query.Set("amount", strconv.Itoa(params.Amount))
+	err = api.do(ctx, http.MethodGet, "/entertainment", query, nil, &result)
+	return result, err
+}
+
+// StreamEventsSSEParams holds parameters of StreamEventsSSE operation.
+type StreamEventsSSEParams struct {
+	Topics      *string
+	LastEventID *string
+}
+
+// StreamEventsSSE calls GET /events/stream: Streams node events as server-sent events.
+func (api *API) StreamEventsSSE(ctx context.Context, params StreamEventsSSEParams) error {
+	query := url.Values{}
+	if params.Topics != nil {
+		query.Set("topics", *params.Topics)
+	}
+	if params.LastEventID != nil {
+		query.Set("last_event_id", *params.LastEventID)
+	}
+	return api.do(ctx, http.MethodGet, "/events/stream", query, nil, nil)
+}
+
+// StreamEventsParams holds parameters of StreamEvents operation.
+type StreamEventsParams struct {
+	Topics *string
+}
+
+// StreamEvents calls GET /events/ws: Streams node events over WebSocket.
+func (api *API) StreamEvents(ctx context.Context, params StreamEventsParams) error {
+	query := url.Values{}
+	if params.Topics != nil {
+		query.Set("topics", *params.Topics)
+	}
+	return api.do(ctx, http.MethodGet, "/events/ws", query, nil, nil)
+}
+
+// ExchangeMystParams holds parameters of ExchangeMyst operation.
+type ExchangeMystParams struct {
+	Currency string
+}
+
+// ExchangeMyst calls GET /exchange/myst/{currency}: Returns the myst price in the given currency.
+func (api *API) ExchangeMyst(ctx context.Context, params ExchangeMystParams) (result contract.CurrencyExchangeDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/exchange/myst/"+pathParam(params.Currency), nil, nil, &result)
+	return result, err
+}
+
+// ReportIssueGithubParams holds parameters of ReportIssueGithub operation.
+type ReportIssueGithubParams struct {
+	Body ReportIssueRequest
+}
+
+// ReportIssueGithub calls POST /feedback/issue: Reports user issue to github.
+func (api *API) ReportIssueGithub(ctx context.Context, params ReportIssueGithubParams) (result ReportIssueSuccess, err error) {
+	err = api.do(ctx, http.MethodPost, "/feedback/issue", nil, params.Body, &result)
+	return result, err
+}
+
+// ReportIssueIntercomParams holds parameters of ReportIssueIntercom operation.
+type ReportIssueIntercomParams struct {
+	Body ReportIntercomIssueRequest
+}
+
+// ReportIssueIntercom calls POST /feedback/issue/intercom: Reports user issue to intercom.
+func (api *API) ReportIssueIntercom(ctx context.Context, params ReportIssueIntercomParams) error {
+	return api.do(ctx, http.MethodPost, "/feedback/issue/intercom", nil, params.Body, nil)
+}
+
+// HealthCheck calls GET /healthcheck: Returns information about client.
+func (api *API) HealthCheck(ctx context.Context) (result contract.HealthCheckDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/healthcheck", nil, nil, &result)
+	return result, err
+}
+
+// Healthz calls GET /healthz: Liveness probe.
+func (api *API) Healthz(ctx context.Context) (result contract.HealthStatusDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/healthz", nil, nil, &result, 503)
+	return result, err
+}
+
+// ListIdentities calls GET /identities: Returns identities.
+func (api *API) ListIdentities(ctx context.Context) (result contract.ListIdentitiesResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/identities", nil, nil, &result)
+	return result, err
+}
+
+// CreateIdentityParams holds parameters of CreateIdentity operation.
+type CreateIdentityParams struct {
+	Body contract.IdentityCreateRequest
+}
+
+// CreateIdentity calls POST /identities: Creates new identity.
+func (api *API) CreateIdentity(ctx context.Context, params CreateIdentityParams) (result contract.IdentityRefDTO, err error) {
+	err = api.do(ctx, http.MethodPost, "/identities", nil, params.Body, &result)
+	return result, err
+}
+
+// ImportIdentityParams holds parameters of ImportIdentity operation.
+type ImportIdentityParams struct {
+	Body contract.IdentityImportRequest
+}
+
+// ImportIdentity calls POST /identities-import: Imports a given identity.
+func (api *API) ImportIdentity(ctx context.Context, params ImportIdentityParams) (result contract.IdentityRefDTO, err error) {
+	err = api.do(ctx, http.MethodPost, "/identities-import", nil, params.Body, &result)
+	return result, err
+}
+
+// ImportIdentityBundleParams holds parameters of ImportIdentityBundle operation.
+type ImportIdentityBundleParams struct {
+}
+
+// ImportIdentityBundle calls POST /identities-import-bundle: Imports an identity bundle.
+func (api *API) ImportIdentityBundle(ctx context.Context, params ImportIdentityBundleParams) (result contract.IdentityBundleImportResponse, err error) {
+	err = api.do(ctx, http.MethodPost, "/identities-import-bundle", nil, nil, &result)
+	return result, err
+}
+
+// NewIdentityMnemonic calls GET /identities-mnemonic: Generates a new BIP-39 mnemonic.
+func (api *API) NewIdentityMnemonic(ctx context.Context) (result contract.IdentityMnemonicResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/identities-mnemonic", nil, nil, &result)
+	return result, err
+}
+
+// RestoreIdentityParams holds parameters of RestoreIdentity operation.
+type RestoreIdentityParams struct {
+}
+
+// RestoreIdentity calls POST /identities-restore: Restores an identity from a mnemonic.
+func (api *API) RestoreIdentity(ctx context.Context, params RestoreIdentityParams) (result contract.IdentityRefDTO, err error) {
+	err = api.do(ctx, http.MethodPost, "/identities-restore", nil, nil, &result)
+	return result, err
+}
+
+// CurrentIdentityParams holds parameters of CurrentIdentity operation.
+type CurrentIdentityParams struct {
+	Body contract.IdentityCurrentRequest
+}
+
+// CurrentIdentity calls PUT /identities/current: Returns my current identity.
+func (api *API) CurrentIdentity(ctx context.Context, params CurrentIdentityParams) (result contract.IdentityRefDTO, err error) {
+	err = api.do(ctx, http.MethodPut, "/identities/current", nil, params.Body, &result)
+	return result, err
+}
+
+// ProviderEligibility calls GET /identities/provider/eligibility: Checks if provider is eligible for free registration.
+func (api *API) ProviderEligibility(ctx context.Context) (result EligibilityResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/identities/provider/eligibility", nil, nil, &result)
+	return result, err
+}
+
+// GetIdentityParams holds parameters of GetIdentity operation.
+type GetIdentityParams struct {
+	ID string
+}
+
+// GetIdentity calls GET /identities/{id}: Get identity.
+func (api *API) GetIdentity(ctx context.Context, params GetIdentityParams) (result contract.IdentityRefDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/identities/"+pathParam(params.ID), nil, nil, &result)
+	return result, err
+}
+
+// BackupIdentityParams holds parameters of BackupIdentity operation.
+type BackupIdentityParams struct {
+	ID string
+}
+
+// BackupIdentity calls POST /identities/{id}/backup: Backs up an identity.
+func (api *API) BackupIdentity(ctx context.Context, params BackupIdentityParams) (result contract.IdentityMnemonicResponse, err error) {
+	err = api.do(ctx, http.MethodPost, "/identities/"+pathParam(params.ID)+"/backup", nil, nil, &result)
+	return result, err
+}
+
+// BalanceParams holds parameters of Balance operation.
+type BalanceParams struct {
+	ID string
+}
+
+// Balance calls PUT /identities/{id}/balance/refresh: Refresh balance of given identity.
+func (api *API) Balance(ctx context.Context, params BalanceParams) (result contract.BalanceDTO, err error) {
+	err = api.do(ctx, http.MethodPut, "/identities/"+pathParam(params.ID)+"/balance/refresh", nil, nil, &result)
+	return result, err
+}
+
+// AddressParams holds parameters of Address operation.
+type AddressParams struct {
+	ID string
+}
+
+// Address calls GET /identities/{id}/beneficiary: Provide identity beneficiary address.
+func (api *API) Address(ctx context.Context, params AddressParams) (result contract.IdentityBeneficiaryResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/identities/"+pathParam(params.ID)+"/beneficiary", nil, nil, &result)
+	return result, err
+}
+
+// ExportIdentityParams holds parameters of ExportIdentity operation.
+type ExportIdentityParams struct {
+	ID string
+}
+
+// ExportIdentity calls POST /identities/{id}/export: Exports an identity.
+func (api *API) ExportIdentity(ctx context.Context, params ExportIdentityParams) (result contract.IdentityBundle, err error) {
+	err = api.do(ctx, http.MethodPost, "/identities/"+pathParam(params.ID)+"/export", nil, nil, &result)
+	return result, err
+}
+
+// RegisterIdentityParams holds parameters of RegisterIdentity operation.
+type RegisterIdentityParams struct {
+	ID   string
+	Body contract.IdentityRegisterRequest
+}
+
+// RegisterIdentity calls POST /identities/{id}/register: Registers identity.
+func (api *API) RegisterIdentity(ctx context.Context, params RegisterIdentityParams) error {
+	return api.do(ctx, http.MethodPost, "/identities/"+pathParam(params.ID)+"/register", nil, params.Body, nil)
+}
+
+// IdentityRegistrationParams holds parameters of IdentityRegistration operation.
+type IdentityRegistrationParams struct {
+	ID string
+}
+
+// IdentityRegistration calls GET /identities/{id}/registration: Provide identity registration status.
+func (api *API) IdentityRegistration(ctx context.Context, params IdentityRegistrationParams) (result contract.IdentityRegistrationResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/identities/"+pathParam(params.ID)+"/registration", nil, nil, &result)
+	return result, err
+}
+
+// RotateIdentityParams holds parameters of RotateIdentity operation.
+type RotateIdentityParams struct {
+	ID string
+}
+
+// RotateIdentity calls POST /identities/{id}/rotate: Rotates identity key.
+func (api *API) RotateIdentity(ctx context.Context, params RotateIdentityParams) (result contract.IdentityRotationDTO, err error) {
+	err = api.do(ctx, http.MethodPost, "/identities/"+pathParam(params.ID)+"/rotate", nil, nil, &result)
+	return result, err
+}
+
+// IdentityRotationsParams holds parameters of IdentityRotations operation.
+type IdentityRotationsParams struct {
+	ID string
+}
+
+// IdentityRotations calls GET /identities/{id}/rotations: Lists identity key rotations.
+func (api *API) IdentityRotations(ctx context.Context, params IdentityRotationsParams) (result contract.IdentityRotationsResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/identities/"+pathParam(params.ID)+"/rotations", nil, nil, &result)
+	return result, err
+}
+
+// UnlockIdentityParams holds parameters of UnlockIdentity operation.
+type UnlockIdentityParams struct {
+	ID   string
+	Body contract.IdentityUnlockRequest
+}
+
+// UnlockIdentity calls PUT /identities/{id}/unlock: Unlocks identity.
+func (api *API) UnlockIdentity(ctx context.Context, params UnlockIdentityParams) error {
+	return api.do(ctx, http.MethodPut, "/identities/"+pathParam(params.ID)+"/unlock", nil, params.Body, nil)
+}
+
+// GetOriginLocation calls GET /location: Returns original location.
+func (api *API) GetOriginLocation(ctx context.Context) (result contract.LocationDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/location", nil, nil, &result)
+	return result, err
+}
+
+// Metrics calls GET /metrics: Returns node runtime metrics.
+func (api *API) Metrics(ctx context.Context) error {
+	return api.do(ctx, http.MethodGet, "/metrics", nil, nil, nil)
+}
+
+// ClearAPIKey calls DELETE /mmn/api-key: Clears MMN's API key from config.
+func (api *API) ClearAPIKey(ctx context.Context) error {
+	return api.do(ctx, http.MethodDelete, "/mmn/api-key", nil, nil, nil)
+}
+
+// SetAPIKeyParams holds parameters of SetAPIKey operation.
+type SetAPIKeyParams struct {
+	Body contract.MMNApiKeyRequest
+}
+
+// SetAPIKey calls POST /mmn/api-key: sets MMN's API key.
+func (api *API) SetAPIKey(ctx context.Context, params SetAPIKeyParams) error {
+	return api.do(ctx, http.MethodPost, "/mmn/api-key", nil, params.Body, nil)
+}
+
+// GetAPIKey calls GET /mmn/report: returns MMN's API key.
+func (api *API) GetAPIKey(ctx context.Context) (result contract.MMNApiKeyRequest, err error) {
+	err = api.do(ctx, http.MethodGet, "/mmn/report", nil, nil, &result)
+	return result, err
+}
+
+// PortMappingsResponse calls GET /nat/mappings: Lists port mappings.
+func (api *API) PortMappingsResponse(ctx context.Context) (result contract.PortMappingsResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/nat/mappings", nil, nil, &result)
+	return result, err
+}
+
+// NATTypeDTOParams holds parameters of NATTypeDTO operation.
+type NATTypeDTOParams struct {
+	Refresh *bool
+}
+
+// NATTypeDTO calls GET /nat/type: Shows NAT type in terms of traversal capabilities.
+func (api *API) NATTypeDTO(ctx context.Context, params NATTypeDTOParams) (result contract.NATTypeDTO, err error) {
+	query := url.Values{}
+	if params.Refresh != nil {
+		query.Set("refresh", strconv.FormatBool(*params.Refresh))
+	}
+	err = api.do(ctx, http.MethodGet, "/nat/type", query, nil, &result)
+	return result, err
+}
+
+// GetLatestRelease calls GET /node/latest-release: Latest Node release information.
+func (api *API) GetLatestRelease(ctx context.Context) (result contract.LatestReleaseResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/node/latest-release", nil, nil, &result)
+	return result, err
+}
+
+// MonitoringAgentStatuses calls GET /node/monitoring-agent-statuses: Provides Node connectivity statuses from monitoring agent.
+func (api *API) MonitoringAgentStatuses(ctx context.Context) (result contract.MonitoringAgentResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/node/monitoring-agent-statuses", nil, nil, &result)
+	return result, err
+}
+
+// NodeStatus calls GET /node/monitoring-status: Provides Node proposal status.
+func (api *API) NodeStatus(ctx context.Context) (result contract.NodeStatusResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/node/monitoring-status", nil, nil, &result)
+	return result, err
+}
+
+// GetProviderActivityStats calls GET /node/provider/activity-stats: Provides Node activity stats.
+func (api *API) GetProviderActivityStats(ctx context.Context) (result contract.ActivityStatsResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/node/provider/activity-stats", nil, nil, &result)
+	return result, err
+}
+
+// GetProviderConsumersCountParams holds parameters of GetProviderConsumersCount operation.
+type GetProviderConsumersCountParams struct {
+	Range *string
+}
+
+// GetProviderConsumersCount calls GET /node/provider/consumers-count: Provides Node consumers number served during a period of time.
+func (api *API) GetProviderConsumersCount(ctx context.Context, params GetProviderConsumersCountParams) (result contract.ProviderConsumersCountResponse, err error) {
+	query := url.Values{}
+	if params.Range != nil {
+		query.Set("range", *params.Range)
+	}
+	err = api.do(ctx, http.MethodGet, "/node/provider/consumers-count", query, nil, &result)
+	return result, err
+}
+
+// GetProviderQuality calls GET /node/provider/quality: Provides Node quality.
+func (api *API) GetProviderQuality(ctx context.Context) (result contract.QualityInfoResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/node/provider/quality", nil, nil, &result)
+	return result, err
+}
+
+// GetProviderTransferredDataSeriesParams holds parameters of GetProviderTransferredDataSeries operation.
+type GetProviderTransferredDataSeriesParams struct {
+	Range *string
+}
+
+// GetProviderTransferredDataSeries calls GET /node/provider/series/data: Provides Node data series metrics of transferred bytes.
+func (api *API) GetProviderTransferredDataSeries(ctx context.Context, params GetProviderTransferredDataSeriesParams) (result contract.ProviderTransferredDataSeriesResponse, err error) {
+	query := url.Values{}
+	if params.Range != nil {
+		query.Set("range", *params.Range)
+	}
+	err = api.do(ctx, http.MethodGet, "/node/provider/series/data", query, nil, &result)
+	return result, err
+}
+
+// GetProviderEarningsSeriesParams holds parameters of GetProviderEarningsSeries operation.
+type GetProviderEarningsSeriesParams struct {
+	Range *string
+}
+
+// GetProviderEarningsSeries calls GET /node/provider/series/earnings: Provides Node  time series metrics of earnings during a period of time.
+func (api *API) GetProviderEarningsSeries(ctx context.Context, params GetProviderEarningsSeriesParams) (result contract.ProviderEarningsSeriesResponse, err error) {
+	query := url.Values{}
+	if params.Range != nil {
+		query.Set("range", *params.Range)
+	}
+	err = api.do(ctx, http.MethodGet, "/node/provider/series/earnings", query, nil, &result)
+	return result, err
+}
+
+// GetProviderSessionsSeriesParams holds parameters of GetProviderSessionsSeries operation.
+type GetProviderSessionsSeriesParams struct {
+	Range *string
+}
+
+// GetProviderSessionsSeries calls GET /node/provider/series/sessions: Provides Node data series metrics of sessions started during a period of time.
+func (api *API) GetProviderSessionsSeries(ctx context.Context, params GetProviderSessionsSeriesParams) (result contract.ProviderSessionsSeriesResponse, err error) {
+	query := url.Values{}
+	if params.Range != nil {
+		query.Set("range", *params.Range)
+	}
+	err = api.do(ctx, http.MethodGet, "/node/provider/series/sessions", query, nil, &result)
+	return result, err
+}
+
+// GetProviderServiceEarnings calls GET /node/provider/service-earnings: Provides Node earnings per service and total earnings in the all network.
+func (api *API) GetProviderServiceEarnings(ctx context.Context) (result contract.EarningsPerServiceResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/node/provider/service-earnings", nil, nil, &result)
+	return result, err
+}
+
+// GetProviderSessionsParams holds parameters of GetProviderSessions operation.
+type GetProviderSessionsParams struct {
+	Range *string
+}
+
+// GetProviderSessions calls GET /node/provider/sessions: Provides Node sessions data during a period of time.
+func (api *API) GetProviderSessions(ctx context.Context, params GetProviderSessionsParams) (result contract.ProviderSessionsResponse, err error) {
+	query := url.Values{}
+	if params.Range != nil {
+		query.Set("range", *params.Range)
+	}
+	err = api.do(ctx, http.MethodGet, "/node/provider/sessions", query, nil, &result)
+	return result, err
+}
+
+// GetProviderSessionsCountParams holds parameters of GetProviderSessionsCount operation.
+type GetProviderSessionsCountParams struct {
+	Range *string
+}
+
+// GetProviderSessionsCount calls GET /node/provider/sessions-count: Provides Node sessions number during a period of time.
+func (api *API) GetProviderSessionsCount(ctx context.Context, params GetProviderSessionsCountParams) (result contract.ProviderSessionsCountResponse, err error) {
+	query := url.Values{}
+	if params.Range != nil {
+		query.Set("range", *params.Range)
+	}
+	err = api.do(ctx, http.MethodGet, "/node/provider/sessions-count", query, nil, &result)
+	return result, err
+}
+
+// GetProviderTransferredDataParams holds parameters of GetProviderTransferredData operation.
+type GetProviderTransferredDataParams struct {
+	Range *string
+}
+
+// GetProviderTransferredData calls GET /node/provider/transferred-data: Provides total traffic served by the provider during a period of time.
+func (api *API) GetProviderTransferredData(ctx context.Context, params GetProviderTransferredDataParams) (result contract.ProviderTransferredDataResponse, err error) {
+	query := url.Values{}
+	if params.Range != nil {
+		query.Set("range", *params.Range)
+	}
+	err = api.do(ctx, http.MethodGet, "/node/provider/transferred-data", query, nil, &result)
+	return result, err
+}
+
+// NodeSelfCheckParams holds parameters of NodeSelfCheck operation.
+type NodeSelfCheckParams struct {
+	Hours *int
+}
+
+// NodeSelfCheck calls GET /node/self-check: Returns provider self-check results.
+func (api *API) NodeSelfCheck(ctx context.Context, params NodeSelfCheckParams) (result contract.SelfCheckDTO, err error) {
+	query := url.Values{}
+	if params.Hours != nil {
+		query.Set("hours", strconv.Itoa(*params.Hours))
+	}
+	err = api.do(ctx, http.MethodGet, "/node/self-check", query, nil, &result)
+	return result, err
+}
+
+// NodeSummary calls GET /node/status: Returns consolidated node status.
+func (api *API) NodeSummary(ctx context.Context) (result contract.NodeSummaryDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/node/status", nil, nil, &result)
+	return result, err
+}
+
+// NodeUpdateStatus calls GET /node/update: Returns node update state.
+func (api *API) NodeUpdateStatus(ctx context.Context) (result contract.NodeUpdateStatusDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/node/update", nil, nil, &result)
+	return result, err
+}
+
+// NodeUpdateParams holds parameters of NodeUpdate operation.
+type NodeUpdateParams struct {
+	Body contract.NodeUpdateRequest
+}
+
+// NodeUpdate calls POST /node/update: Checks for and installs node update.
+func (api *API) NodeUpdate(ctx context.Context, params NodeUpdateParams) (result contract.NodeUpdateStatusDTO, err error) {
+	err = api.do(ctx, http.MethodPost, "/node/update", nil, params.Body, &result)
+	return result, err
+}
+
+// ListProposalsParams holds parameters of ListProposals operation.
+type ListProposalsParams struct {
+	ProviderID         *string
+	ServiceType        *string
+	AccessPolicy       *string
+	AccessPolicySource *string
+	Country            *string
+	IPType             *string
+	CompatibilityMin   *int
+	CompatibilityMax   *int
+	QualityMin         *float64
+	NATCompatibility   *string
+	PricePerHourMax    *string
+	PricePerGibMax     *string
+	BandwidthMin       *float64
+	UptimeMin          *float64
+	Sort               *string
+}
+
+// ListProposals calls GET /proposals: Returns proposals.
+func (api *API) ListProposals(ctx context.Context, params ListProposalsParams) (result contract.ListProposalsResponse, err error) {
+	query := url.Values{}
+	if params.ProviderID != nil {
+		query.Set("provider_id", *params.ProviderID)
+	}
+	if params.ServiceType != nil {
+		query.Set("service_type", *params.ServiceType)
+	}
+	if params.AccessPolicy != nil {
+		query.Set("access_policy", *params.AccessPolicy)
+	}
+	if params.AccessPolicySource != nil {
+		query.Set("access_policy_source", *params.AccessPolicySource)
+	}
+	if params.Country != nil {
+		query.Set("country", *params.Country)
+	}
+	if params.IPType != nil {
+		query.Set("ip_type", *params.IPType)
+	}
+	if params.CompatibilityMin != nil {
+		query.Set("compatibility_min", strconv.Itoa(*params.CompatibilityMin))
+	}
+	if params.CompatibilityMax != nil {
+		query.Set("compatibility_max", strconv.Itoa(*params.CompatibilityMax))
+	}
+	if params.QualityMin != nil {
+		query.Set("quality_min", strconv.FormatFloat(*params.QualityMin, 'f', -1, 64))
+	}
+	if params.NATCompatibility != nil {
+		query.Set("nat_compatibility", *params.NATCompatibility)
+	}
+	if params.PricePerHourMax != nil {
+		query.Set("price_per_hour_max", *params.PricePerHourMax)
+	}
+	if params.PricePerGibMax != nil {
+		query.Set("price_per_gib_max", *params.PricePerGibMax)
+	}
+	if params.BandwidthMin != nil {
+		query.Set("bandwidth_min", strconv.FormatFloat(*params.BandwidthMin, 'f', -1, 64))
+	}
+	if params.UptimeMin != nil {
+		query.Set("uptime_min", strconv.FormatFloat(*params.UptimeMin, 'f', -1, 64))
+	}
+	if params.Sort != nil {
+		query.Set("sort", *params.Sort)
+	}
+	err = api.do(ctx, http.MethodGet, "/proposals", query, nil, &result)
+	return result, err
+}
+
+// ListCountriesParams holds parameters of ListCountries operation.
+type ListCountriesParams struct {
+	ProviderID         *string
+	ServiceType        *string
+	AccessPolicy       *string
+	AccessPolicySource *string
+	Country            *string
+	IPType             *string
+	CompatibilityMin   *int
+	CompatibilityMax   *int
+	QualityMin         *float64
+	NATCompatibility   *string
+}
+
+// ListCountries calls GET /proposals/countries: Returns number of proposals per country.
+func (api *API) ListCountries(ctx context.Context, params ListCountriesParams) (result contract.ListProposalsCountiesResponse, err error) {
+	query := url.Values{}
+	if params.ProviderID != nil {
+		query.Set("provider_id", *params.ProviderID)
+	}
+	if params.ServiceType != nil {
+		query.Set("service_type", *params.ServiceType)
+	}
+	if params.AccessPolicy != nil {
+		query.Set("access_policy", *params.AccessPolicy)
+	}
+	if params.AccessPolicySource != nil {
+		query.Set("access_policy_source", *params.AccessPolicySource)
+	}
+	if params.Country != nil {
+		query.Set("country", *params.Country)
+	}
+	if params.IPType != nil {
+		query.Set("ip_type", *params.IPType)
+	}
+	if params.CompatibilityMin != nil {
+		query.Set("compatibility_min", strconv.Itoa(*params.CompatibilityMin))
+	}
+	if params.CompatibilityMax != nil {
+		query.Set("compatibility_max", strconv.Itoa(*params.CompatibilityMax))
+	}
+	if params.QualityMin != nil {
+		query.Set("quality_min", strconv.FormatFloat(*params.QualityMin, 'f', -1, 64))
+	}
+	if params.NATCompatibility != nil {
+		query.Set("nat_compatibility", *params.NATCompatibility)
+	}
+	err = api.do(ctx, http.MethodGet, "/proposals/countries", query, nil, &result)
+	return result, err
+}
+
+// ProposalFilterPresets calls GET /proposals/filter-presets: Returns proposal filter presets.
+func (api *API) ProposalFilterPresets(ctx context.Context) (result contract.ListProposalFilterPresetsResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/proposals/filter-presets", nil, nil, &result)
+	return result, err
+}
+
+// PingProposalsParams holds parameters of PingProposals operation.
+type PingProposalsParams struct {
+	Body contract.ProposalsPingRequest
+}
+
+// PingProposals calls POST /proposals/ping: Pings providers.
+func (api *API) PingProposals(ctx context.Context, params PingProposalsParams) (result contract.ProposalsPingResponse, err error) {
+	err = api.do(ctx, http.MethodPost, "/proposals/ping", nil, params.Body, &result)
+	return result, err
+}
+
+// Readyz calls GET /readyz: Readiness probe.
+func (api *API) Readyz(ctx context.Context) (result contract.HealthStatusDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/readyz", nil, nil, &result, 503)
+	return result, err
+}
+
+// ListRules calls GET /rules: Returns automation rules.
+func (api *API) ListRules(ctx context.Context) (result contract.RuleListResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/rules", nil, nil, &result)
+	return result, err
+}
+
+// CreateRuleParams holds parameters of CreateRule operation.
+type CreateRuleParams struct {
+	Body contract.RuleCreateRequest
+}
+
+// CreateRule calls POST /rules: Creates automation rule.
+func (api *API) CreateRule(ctx context.Context, params CreateRuleParams) (result contract.RuleDTO, err error) {
+	err = api.do(ctx, http.MethodPost, "/rules", nil, params.Body, &result)
+	return result, err
+}
+
+// DeleteRuleParams holds parameters of DeleteRule operation.
+type DeleteRuleParams struct {
+	ID string
+}
+
+// DeleteRule calls DELETE /rules/{id}: Removes automation rule.
+func (api *API) DeleteRule(ctx context.Context, params DeleteRuleParams) error {
+	return api.do(ctx, http.MethodDelete, "/rules/"+pathParam(params.ID), nil, nil, nil)
+}
+
+// ScheduleStatus calls GET /schedule: Returns provider schedule state.
+func (api *API) ScheduleStatus(ctx context.Context) (result contract.ScheduleStatusDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/schedule", nil, nil, &result)
+	return result, err
+}
+
+// ServiceListResponse calls GET /services: List of services.
+func (api *API) ServiceListResponse(ctx context.Context) (result contract.ServiceListResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/services", nil, nil, &result)
+	return result, err
+}
+
+// ServiceStartParams holds parameters of ServiceStart operation.
+type ServiceStartParams struct {
+	Body contract.ServiceStartRequest
+}
+
+// ServiceStart calls POST /services: Starts service.
+func (api *API) ServiceStart(ctx context.Context, params ServiceStartParams) (result contract.ServiceInfoDTO, err error) {
+	err = api.do(ctx, http.MethodPost, "/services", nil, params.Body, &result)
+	return result, err
+}
+
+// ServiceStop calls DELETE /services/:id: Stops service.
+func (api *API) ServiceStop(ctx context.Context) error {
+	return api.do(ctx, http.MethodDelete, "/services/:id", nil, nil, nil)
+}
+
+// ServiceGet calls GET /services/:id: Information about service.
+func (api *API) ServiceGet(ctx context.Context) (result contract.ServiceInfoDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/services/:id", nil, nil, &result)
+	return result, err
+}
+
+// SessionListParams holds parameters of SessionList operation.
+type SessionListParams struct {
+	PageSize    *int64
+	Page        *int64
+	DateFrom    *string
+	DateTo      *string
+	Direction   *string
+	ConsumerID  *string
+	HermesID    *string
+	ProviderID  *string
+	ServiceType *string
+	Status      *string
+	GroupBy     *string
+}
+
+// SessionList calls GET /sessions: Returns sessions history.
+func (api *API) SessionList(ctx context.Context, params SessionListParams) (result contract.SessionListResponse, err error) {
+	query := url.Values{}
+	if params.PageSize != nil {
+		query.Set("page_size", strconv.FormatInt(*params.PageSize, 10))
+	}
+	if params.Page != nil {
+		query.Set("page", strconv.FormatInt(*params.Page, 10))
+	}
+	if params.DateFrom != nil {
+		query.Set("date_from", *params.DateFrom)
+	}
+	if params.DateTo != nil {
+		query.Set("date_to", *params.DateTo)
+	}
+	if params.Direction != nil {
+		query.Set("direction", *params.Direction)
+	}
+	if params.ConsumerID != nil {
+		query.Set("consumer_id", *params.ConsumerID)
+	}
+	if params.HermesID != nil {
+		query.Set("hermes_id", *params.HermesID)
+	}
+	if params.ProviderID != nil {
+		query.Set("provider_id", *params.ProviderID)
+	}
+	if params.ServiceType != nil {
+		query.Set("service_type", *params.ServiceType)
+	}
+	if params.Status != nil {
+		query.Set("status", *params.Status)
+	}
+	if params.GroupBy != nil {
+		query.Set("group_by", *params.GroupBy)
+	}
+	err = api.do(ctx, http.MethodGet, "/sessions", query, nil, &result)
+	return result, err
+}
+
+// ConnectivityStatus calls GET /sessions-connectivity-status: Returns session connectivity status.
+func (api *API) ConnectivityStatus(ctx context.Context) (result ConnectivityStatus, err error) {
+	err = api.do(ctx, http.MethodGet, "/sessions-connectivity-status", nil, nil, &result)
+	return result, err
+}
+
+// SessionAccounting calls GET /sessions/accounting: Returns provider session traffic reconciliation.
+func (api *API) SessionAccounting(ctx context.Context) (result contract.SessionAccountingReportDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/sessions/accounting", nil, nil, &result)
+	return result, err
+}
+
+// SessionNoticeParams holds parameters of SessionNotice operation.
+type SessionNoticeParams struct {
+	Body contract.SessionNoticeRequest
+}
+
+// SessionNotice calls POST /sessions/notice: Pushes notice to consumers.
+func (api *API) SessionNotice(ctx context.Context, params SessionNoticeParams) (result contract.SessionNoticeResponse, err error) {
+	err = api.do(ctx, http.MethodPost, "/sessions/notice", nil, params.Body, &result)
+	return result, err
+}
+
+// SessionStatsAggregatedParams holds parameters of SessionStatsAggregated operation.
+type SessionStatsAggregatedParams struct {
+	DateFrom    *string
+	DateTo      *string
+	Direction   *string
+	ConsumerID  *string
+	HermesID    *string
+	ProviderID  *string
+	ServiceType *string
+	Status      *string
+}
+
+// SessionStatsAggregated calls GET /sessions/stats-aggregated: Returns sessions stats.
+func (api *API) SessionStatsAggregated(ctx context.Context, params SessionStatsAggregatedParams) (result contract.SessionStatsAggregatedResponse, err error) {
+	query := url.Values{}
+	if params.DateFrom != nil {
+		query.Set("date_from", *params.DateFrom)
+	}
+	if params.DateTo != nil {
+		query.Set("date_to", *params.DateTo)
+	}
+	if params.Direction != nil {
+		query.Set("direction", *params.Direction)
+	}
+	if params.ConsumerID != nil {
+		query.Set("consumer_id", *params.ConsumerID)
+	}
+	if params.HermesID != nil {
+		query.Set("hermes_id", *params.HermesID)
+	}
+	if params.ProviderID != nil {
+		query.Set("provider_id", *params.ProviderID)
+	}
+	if params.ServiceType != nil {
+		query.Set("service_type", *params.ServiceType)
+	}
+	if params.Status != nil {
+		query.Set("status", *params.Status)
+	}
+	err = api.do(ctx, http.MethodGet, "/sessions/stats-aggregated", query, nil, &result)
+	return result, err
+}
+
+// SessionStatsDailyParams holds parameters of SessionStatsDaily operation.
+type SessionStatsDailyParams struct {
+	DateFrom    *string
+	DateTo      *string
+	Direction   *string
+	ConsumerID  *string
+	HermesID    *string
+	ProviderID  *string
+	ServiceType *string
+	Status      *string
+}
+
+// SessionStatsDaily calls GET /sessions/stats-daily: Returns sessions stats.
+func (api *API) SessionStatsDaily(ctx context.Context, params SessionStatsDailyParams) (result contract.SessionStatsDTO, err error) {
+	query := url.Values{}
+	if params.DateFrom != nil {
+		query.Set("date_from", *params.DateFrom)
+	}
+	if params.DateTo != nil {
+		query.Set("date_to", *params.DateTo)
+	}
+	if params.Direction != nil {
+		query.Set("direction", *params.Direction)
+	}
+	if params.ConsumerID != nil {
+		query.Set("consumer_id", *params.ConsumerID)
+	}
+	if params.HermesID != nil {
+		query.Set("hermes_id", *params.HermesID)
+	}
+	if params.ProviderID != nil {
+		query.Set("provider_id", *params.ProviderID)
+	}
+	if params.ServiceType != nil {
+		query.Set("service_type", *params.ServiceType)
+	}
+	if params.Status != nil {
+		query.Set("status", *params.Status)
+	}
+	err = api.do(ctx, http.MethodGet, "/sessions/stats-daily", query, nil, &result)
+	return result, err
+}
+
+// SessionTrafficParams holds parameters of SessionTraffic operation.
+type SessionTrafficParams struct {
+	Days *int
+}
+
+// SessionTraffic calls GET /sessions/traffic: Returns provider traffic totals.
+func (api *API) SessionTraffic(ctx context.Context, params SessionTrafficParams) (result contract.TrafficDTO, err error) {
+	query := url.Values{}
+	if params.Days != nil {
+		query.Set("days", strconv.Itoa(*params.Days))
+	}
+	err = api.do(ctx, http.MethodGet, "/sessions/traffic", query, nil, &result)
+	return result, err
+}
+
+// SettlementListParams holds parameters of SettlementList operation.
+type SettlementListParams struct {
+	PageSize   *int64
+	Page       *int64
+	DateFrom   *string
+	DateTo     *string
+	ProviderID *string
+	HermesID   *string
+	Types      []string
+}
+
+// SettlementList calls GET /settle/history: Returns settlement history.
+func (api *API) SettlementList(ctx context.Context, params SettlementListParams) (result contract.SettlementListResponse, err error) {
+	query := url.Values{}
+	if params.PageSize != nil {
+		query.Set("page_size", strconv.FormatInt(*params.PageSize, 10))
+	}
+	if params.Page != nil {
+		query.Set("page", strconv.FormatInt(*params.Page, 10))
+	}
+	if params.DateFrom != nil {
+		query.Set("date_from", *params.DateFrom)
+	}
+	if params.DateTo != nil {
+		query.Set("date_to", *params.DateTo)
+	}
+	if params.ProviderID != nil {
+		query.Set("provider_id", *params.ProviderID)
+	}
+	if params.HermesID != nil {
+		query.Set("hermes_id", *params.HermesID)
+	}
+	for _, v := range params.Types {
+		query.Add("types", v)
+	}
+	err = api.do(ctx, http.MethodGet, "/settle/history", query, nil, &result)
+	return result, err
+}
+
+// StateSyncStatus calls GET /state-sync: Returns state sync status.
+func (api *API) StateSyncStatus(ctx context.Context) (result contract.StateSyncStatusDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/state-sync", nil, nil, &result)
+	return result, err
+}
+
+// StateSyncNow calls POST /state-sync: Synchronizes state now.
+func (api *API) StateSyncNow(ctx context.Context) (result contract.StateSyncStatusDTO, err error) {
+	err = api.do(ctx, http.MethodPost, "/state-sync", nil, nil, &result)
+	return result, err
+}
+
+// ApplicationStop calls POST /stop: Stops client.
+func (api *API) ApplicationStop(ctx context.Context) error {
+	return api.do(ctx, http.MethodPost, "/stop", nil, nil, nil)
+}
+
+// GetTerms calls GET /terms: Get terms.
+func (api *API) GetTerms(ctx context.Context) (result contract.TermsResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/terms", nil, nil, &result)
+	return result, err
+}
+
+// UpdateTermsParams holds parameters of UpdateTerms operation.
+type UpdateTermsParams struct {
+	Body contract.TermsRequest
+}
+
+// UpdateTerms calls POST /terms: Update terms agreement.
+func (api *API) UpdateTerms(ctx context.Context, params UpdateTermsParams) error {
+	return api.do(ctx, http.MethodPost, "/terms", nil, params.Body, nil)
+}
+
+// Chains calls GET /transactor/chains-summary: Returns available chain map.
+func (api *API) Chains(ctx context.Context) (result contract.ChainSummary, err error) {
+	err = api.do(ctx, http.MethodGet, "/transactor/chains-summary", nil, nil, &result)
+	return result, err
+}
+
+// FeesDTO calls GET /transactor/fees: Returns fees.
+func (api *API) FeesDTO(ctx context.Context) (result contract.FeesDTO, err error) {
+	err = api.do(ctx, http.MethodGet, "/transactor/fees", nil, nil, &result)
+	return result, err
+}
+
+// EligibilityParams holds parameters of Eligibility operation.
+type EligibilityParams struct {
+	ID string
+}
+
+// Eligibility calls GET /transactor/identities/{id}/eligibility: Checks if given id is eligible for free registration.
+func (api *API) Eligibility(ctx context.Context, params EligibilityParams) (result EligibilityResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/transactor/identities/"+pathParam(params.ID)+"/eligibility", nil, nil, &result)
+	return result, err
+}
+
+// SettleAsyncParams holds parameters of SettleAsync operation.
+type SettleAsyncParams struct {
+	Body contract.SettleRequest
+}
+
+// SettleAsync calls POST /transactor/settle/async: forces the settlement of promises for the given provider and hermes.
+func (api *API) SettleAsync(ctx context.Context, params SettleAsyncParams) error {
+	return api.do(ctx, http.MethodPost, "/transactor/settle/async", nil, params.Body, nil)
+}
+
+// SettleSyncParams holds parameters of SettleSync operation.
+type SettleSyncParams struct {
+	Body contract.SettleRequest
+}
+
+// SettleSync calls POST /transactor/settle/sync: Forces the settlement of promises for the given provider and hermes.
+func (api *API) SettleSync(ctx context.Context, params SettleSyncParams) error {
+	return api.do(ctx, http.MethodPost, "/transactor/settle/sync", nil, params.Body, nil)
+}
+
+// WithdrawParams holds parameters of Withdraw operation.
+type WithdrawParams struct {
+	Body contract.WithdrawRequest
+}
+
+// Withdraw calls POST /transactor/settle/withdraw: Asks to perform withdrawal to l1.
+func (api *API) Withdraw(ctx context.Context, params WithdrawParams) error {
+	return api.do(ctx, http.MethodPost, "/transactor/settle/withdraw", nil, params.Body, nil)
+}
+
+// StakeParams holds parameters of Stake operation.
+type StakeParams struct {
+	Body contract.DecreaseStakeRequest
+}
+
+// Stake calls POST /transactor/stake/decrease: Decreases stake.
+func (api *API) Stake(ctx context.Context, params StakeParams) error {
+	return api.do(ctx, http.MethodPost, "/transactor/stake/decrease", nil, params.Body, nil)
+}
+
+// StakeIncreaseAsyncParams holds parameters of StakeIncreaseAsync operation.
+type StakeIncreaseAsyncParams struct {
+	Body contract.SettleRequest
+}
+
+// StakeIncreaseAsync calls POST /transactor/stake/increase/async: forces the settlement with stake increase of promises for the given provider and hermes.
+func (api *API) StakeIncreaseAsync(ctx context.Context, params StakeIncreaseAsyncParams) error {
+	return api.do(ctx, http.MethodPost, "/transactor/stake/increase/async", nil, params.Body, nil)
+}
+
+// StakeIncreaseSyncParams holds parameters of StakeIncreaseSync operation.
+type StakeIncreaseSyncParams struct {
+	Body contract.SettleRequest
+}
+
+// StakeIncreaseSync calls POST /transactor/stake/increase/sync: Forces the settlement with stake increase of promises for the given provider and hermes.
+func (api *API) StakeIncreaseSync(ctx context.Context, params StakeIncreaseSyncParams) error {
+	return api.do(ctx, http.MethodPost, "/transactor/stake/increase/sync", nil, params.Body, nil)
+}
+
+// RewardParams holds parameters of Reward operation.
+type RewardParams struct {
+	Token string
+}
+
+// Reward calls POST /transactor/token/{token}/reward: Returns the amount of reward for a token.
+func (api *API) Reward(ctx context.Context, params RewardParams) (result contract.TokenRewardAmount, err error) {
+	err = api.do(ctx, http.MethodPost, "/transactor/token/"+pathParam(params.Token)+"/reward", nil, nil, &result)
+	return result, err
+}
+
+// UIDownloadStatus calls GET /ui/download-status: Download status.
+func (api *API) UIDownloadStatus(ctx context.Context) (result DownloadStatus, err error) {
+	err = api.do(ctx, http.MethodGet, "/ui/download-status", nil, nil, &result)
+	return result, err
+}
+
+// UIDownload calls POST /ui/download-version: Download.
+func (api *API) UIDownload(ctx context.Context) error {
+	return api.do(ctx, http.MethodPost, "/ui/download-version", nil, nil, nil)
+}
+
+// UI calls GET /ui/info: Node UI information.
+func (api *API) UI(ctx context.Context) (result contract.UI, err error) {
+	err = api.do(ctx, http.MethodGet, "/ui/info", nil, nil, &result)
+	return result, err
+}
+
+// UILocalVersions calls GET /ui/local-versions: List remote.
+func (api *API) UILocalVersions(ctx context.Context) (result contract.LocalVersionsResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/ui/local-versions", nil, nil, &result)
+	return result, err
+}
+
+// UIRemoteVersions calls GET /ui/remote-versions: List local.
+func (api *API) UIRemoteVersions(ctx context.Context) (result contract.RemoteVersionsResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/ui/remote-versions", nil, nil, &result)
+	return result, err
+}
+
+// UISwitchVersion calls POST /ui/switch-version: Switch Version.
+func (api *API) UISwitchVersion(ctx context.Context) error {
+	return api.do(ctx, http.MethodPost, "/ui/switch-version", nil, nil, nil)
+}
+
+// GetPaymentGatewayOrdersParams holds parameters of GetPaymentGatewayOrders operation.
+type GetPaymentGatewayOrdersParams struct {
+	ID string
+}
+
+// GetPaymentGatewayOrders calls GET /v2/identities/{id}/payment-order: Get all orders for identity.
+func (api *API) GetPaymentGatewayOrders(ctx context.Context, params GetPaymentGatewayOrdersParams) (result []contract.PaymentOrderResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/v2/identities/"+pathParam(params.ID)+"/payment-order", nil, nil, &result)
+	return result, err
+}
+
+// GetPaymentGatewayOrderParams holds parameters of GetPaymentGatewayOrder operation.
+type GetPaymentGatewayOrderParams struct {
+	ID      string
+	OrderID int
+}
+
+// GetPaymentGatewayOrder calls GET /v2/identities/{id}/payment-order/{order_id}: Get order.
+func (api *API) GetPaymentGatewayOrder(ctx context.Context, params GetPaymentGatewayOrderParams) (result contract.PaymentOrderResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/v2/identities/"+pathParam(params.ID)+"/payment-order/"+pathParam(params.OrderID), nil, nil, &result)
+	return result, err
+}
+
+// GetPaymentGatewayOrderInvoiceParams holds parameters of GetPaymentGatewayOrderInvoice operation.
+type GetPaymentGatewayOrderInvoiceParams struct {
+	ID      string
+	OrderID int
+}
+
+// GetPaymentGatewayOrderInvoice calls GET /v2/identities/{id}/payment-order/{order_id}/invoice: Get invoice.
+func (api *API) GetPaymentGatewayOrderInvoice(ctx context.Context, params GetPaymentGatewayOrderInvoiceParams) error {
+	return api.do(ctx, http.MethodGet, "/v2/identities/"+pathParam(params.ID)+"/payment-order/"+pathParam(params.OrderID)+"/invoice", nil, nil, nil)
+}
+
+// GetRegistrationPaymentStatusParams holds parameters of GetRegistrationPaymentStatus operation.
+type GetRegistrationPaymentStatusParams struct {
+	ID string
+}
+
+// GetRegistrationPaymentStatus calls GET /v2/identities/{id}/registration-payment: Check for registration payment.
+func (api *API) GetRegistrationPaymentStatus(ctx context.Context, params GetRegistrationPaymentStatusParams) (result contract.RegistrationPaymentResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/v2/identities/"+pathParam(params.ID)+"/registration-payment", nil, nil, &result)
+	return result, err
+}
+
+// CreatePaymentGatewayOrderParams holds parameters of CreatePaymentGatewayOrder operation.
+type CreatePaymentGatewayOrderParams struct {
+	ID   string
+	Gw   string
+	Body contract.PaymentOrderRequest
+}
+
+// CreatePaymentGatewayOrder calls POST /v2/identities/{id}/{gw}/payment-order: Create order.
+func (api *API) CreatePaymentGatewayOrder(ctx context.Context, params CreatePaymentGatewayOrderParams) (result contract.PaymentOrderResponse, err error) {
+	err = api.do(ctx, http.MethodPost, "/v2/identities/"+pathParam(params.ID)+"/"+pathParam(params.Gw)+"/payment-order", nil, params.Body, &result)
+	return result, err
+}
+
+// GetPaymentGatewaysParams holds parameters of GetPaymentGateways operation.
+type GetPaymentGatewaysParams struct {
+	OptionsCurrency *string
+}
+
+// GetPaymentGateways calls GET /v2/payment-order-gateways: Get payment gateway configuration.
+func (api *API) GetPaymentGateways(ctx context.Context, params GetPaymentGatewaysParams) (result []contract.GatewaysResponse, err error) {
+	query := url.Values{}
+	if params.OptionsCurrency != nil {
+		query.Set("options_currency", *params.OptionsCurrency)
+	}
+	err = api.do(ctx, http.MethodGet, "/v2/payment-order-gateways", query, nil, &result)
+	return result, err
+}
+
+// CombinedFeesResponse calls GET /v2/transactor/fees: Returns fees.
+func (api *API) CombinedFeesResponse(ctx context.Context) (result contract.CombinedFeesResponse, err error) {
+	err = api.do(ctx, http.MethodGet, "/v2/transactor/fees", nil, nil, &result)
+	return result, err
+}
+
+// AccessPolicies is the AccessPolicies model of Tequilapi.
+type AccessPolicies struct {
+	Entries []EndpointsAccessPolicy `json:"entries,omitempty"`
+}
+
+// AccessPolicy is the AccessPolicy model of Tequilapi.
+type AccessPolicy struct {
+	ID     string `json:"id,omitempty"`
+	Source string `json:"source,omitempty"`
+}
+
+// Capacity is the Capacity model of Tequilapi.
+type Capacity struct {
+	DownlinkMbps float64 `json:"downlink_mbps,omitempty"`
+	UplinkMbps   float64 `json:"uplink_mbps,omitempty"`
+	Uptime       float64 `json:"uptime,omitempty"`
+}
+
+// ConnectivityStatus is the ConnectivityStatus model of Tequilapi.
+type ConnectivityStatus struct {
+	Entries []SessionConnectivityStatus `json:"entries,omitempty"`
+}
+
+// DNSOption is the DNSOption model of Tequilapi.
+type DNSOption string
+
+// DownloadStatus is the DownloadStatus model of Tequilapi.
+type DownloadStatus struct {
+	Err         string   `json:"error,omitempty"`
+	ProgressPct int64    `json:"progress_percent,omitempty"`
+	Status      DlStatus `json:"status,omitempty"`
+	Tag         string   `json:"tag,omitempty"`
+}
+
+// EgressPolicy is the EgressPolicy model of Tequilapi.
+type EgressPolicy struct {
+	BlockedDomains  []string `json:"blocked_domains,omitempty"`
+	BlockedNetworks []string `json:"blocked_networks,omitempty"`
+	BlockedPorts    []int64  `json:"blocked_ports,omitempty"`
+}
+
+// EligibilityResponse is the EligibilityResponse model of Tequilapi.
+type EligibilityResponse struct {
+	Eligible bool `json:"eligible,omitempty"`
+}
+
+// HistoryType is the HistoryType model of Tequilapi.
+type HistoryType string
+
+// LocalVersion is the LocalVersion model of Tequilapi.
+type LocalVersion struct {
+	Name string `json:"name,omitempty"`
+}
+
+// MonitoringAgentStatuses is the MonitoringAgentStatuses model of Tequilapi.
+type MonitoringAgentStatuses map[string]map[string]int64
+
+// MonitoringStatus is the MonitoringStatus model of Tequilapi.
+type MonitoringStatus string
+
+// NATType is the NATType model of Tequilapi.
+type NATType string
+
+// RemoteVersion is the RemoteVersion model of Tequilapi.
+type RemoteVersion struct {
+	CompatibilityURL string    `json:"compatibility_url,omitempty"`
+	IsPreRelease     bool      `json:"is_pre_release,omitempty"`
+	Name             string    `json:"name,omitempty"`
+	ReleaseNotes     string    `json:"release_notes,omitempty"`
+	PublishedAt      time.Time `json:"released_at,omitempty"`
+}
+
+// ReportIntercomIssueRequest is the ReportIntercomIssueRequest model of Tequilapi.
+type ReportIntercomIssueRequest struct {
+	Description string `json:"description,omitempty"`
+	Email       string `json:"email,omitempty"`
+	UserId      string `json:"user_id,omitempty"`
+	UserType    string `json:"user_type,omitempty"`
+}
+
+// ReportIssueError is the ReportIssueError model of Tequilapi.
+type ReportIssueError struct {
+	Errors []map[string]interface{} `json:"errors,omitempty"`
+}
+
+// ReportIssueRequest is the ReportIssueRequest model of Tequilapi.
+type ReportIssueRequest struct {
+	Description string `json:"description,omitempty"`
+	Email       string `json:"email,omitempty"`
+}
+
+// ReportIssueSuccess is the ReportIssueSuccess model of Tequilapi.
+type ReportIssueSuccess struct {
+	IssueID string `json:"issue_id,omitempty"`
+}
+
+// SettleState is the SettleState model of Tequilapi.
+type SettleState string
+
+// EndpointsAccessPolicy is the accessPolicy model of Tequilapi.
+type EndpointsAccessPolicy struct {
+	Allow       []AccessRule `json:"allow,omitempty"`
+	Description string       `json:"description,omitempty"`
+	ID          string       `json:"id,omitempty"`
+	Title       string       `json:"title,omitempty"`
+}
+
+// AccessRule is the accessRule model of Tequilapi.
+type AccessRule struct {
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// ConfigPayload is the configPayload model of Tequilapi.
+type ConfigPayload struct {
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// DlStatus is the dlStatus model of Tequilapi.
+type DlStatus string
+
+// SessionConnectivityStatus is the sessionConnectivityStatus model of Tequilapi.
+type SessionConnectivityStatus struct {
+	Code         uint32    `json:"code,omitempty"`
+	CreatedAtUTC time.Time `json:"created_at_utc,omitempty"`
+	Message      string    `json:"message,omitempty"`
+	PeerAddress  string    `json:"peer_address,omitempty"`
+	SessionID    string    `json:"session_id,omitempty"`
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+func newTestAPI(t *testing.T, handler http.HandlerFunc) *API {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewAPI(server.URL + "/")
+}
+
+func TestAPI_SendsBodyAndToken(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/auth/authenticate", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var req contract.AuthRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, contract.AuthRequest{Username: "myst", Password: "pass"}, req)
+
+		w.Write([]byte(`{"token": "jwt"}`))
+	})
+	api.SetToken("secret")
+
+	res, err := api.Authenticate(context.Background(), AuthenticateParams{
+		Body: contract.AuthRequest{Username: "myst", Password: "pass"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "jwt", res.Token)
+}
+
+func TestAPI_EncodesPathAndQueryParams(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/sessions", r.URL.Path)
+		assert.Equal(t, "page=2&status=New", r.URL.RawQuery)
+		w.Write([]byte(`{}`))
+	})
+	page, status := int64(2), "New"
+
+	_, err := api.SessionList(context.Background(), SessionListParams{Page: &page, Status: &status})
+	require.NoError(t, err)
+
+	api = newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/identities/0x01%2F", r.URL.RawPath)
+		w.Write([]byte(`{"id": "0x01/"}`))
+	})
+
+	id, err := api.GetIdentity(context.Background(), GetIdentityParams{ID: "0x01/"})
+	require.NoError(t, err)
+	assert.Equal(t, "0x01/", id.Address)
+}
+
+func TestAPI_ReturnsAPIErrors(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", apierror.ContentTypeV1)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error": {"code": "validation_failed", "message": "Request validation failed"}, "status": 422, "path": "/connection"}`))
+	})
+
+	_, err := api.ConnectionCreate(context.Background(), ConnectionCreateParams{})
+
+	var apiErr *apierror.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.Status)
+	assert.Equal(t, "validation_failed", apiErr.Err.Code)
+}
+
+func TestAPI_RespectsContext(t *testing.T) {
+	api := newTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not be sent")
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := api.HealthCheck(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -121,6 +121,38 @@ func (client *Client) ImportIdentity(blob []byte, passphrase string, setDefault
 	return id, err
 }
 
+// ExportIdentity exports the identity as a bundle encrypted with the export passphrase.
+func (client *Client) ExportIdentity(address, currentPassphrase, exportPassphrase string) (bundle contract.IdentityBundle, err error) {
+	response, err := client.http.Post(fmt.Sprintf("identities/%s/export", address), contract.IdentityExportRequest{
+		CurrentPassphrase: currentPassphrase,
+		ExportPassphrase:  exportPassphrase,
+	})
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	err = parseResponseJSON(response, &bundle)
+	return bundle, err
+}
+
+// ImportIdentityBundle imports the identity from a bundle exported with the given passphrase.
+func (client *Client) ImportIdentityBundle(bundle contract.IdentityBundle, passphrase, newPassphrase string, setDefault bool) (res contract.IdentityBundleImportResponse, err error) {
+	response, err := client.http.Post("identities-import-bundle", contract.IdentityBundleImportRequest{
+		Bundle:        &bundle,
+		Passphrase:    passphrase,
+		NewPassphrase: newPassphrase,
+		SetDefault:    setDefault,
+	})
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	err = parseResponseJSON(response, &res)
+	return res, err
+}
+
 // GetIdentities returns a list of client identities
 func (client *Client) GetIdentities() (ids []contract.IdentityRefDTO, err error) {
 	response, err := client.http.Get("identities", url.Values{})
@@ -835,6 +867,24 @@ func (client *Client) MigrateHermesStatus(address string) (contract.MigrationSta
 	return res, err
 }
 
+// RotateIdentity migrates the identity to a new key protected by the given passphrase.
+func (client *Client) RotateIdentity(address, newPassphrase string, setDefault bool) (contract.IdentityRotationDTO, error) {
+	var res contract.IdentityRotationDTO
+
+	response, err := client.http.Post(fmt.Sprintf("identities/%s/rotate", address), contract.IdentityRotateRequest{
+		NewPassphrase: newPassphrase,
+		SetDefault:    setDefault,
+	})
+	if err != nil {
+		return res, err
+	}
+	defer response.Body.Close()
+
+	err = parseResponseJSON(response, &res)
+
+	return res, err
+}
+
 // Beneficiary gets beneficiary address for the provided identity.
 func (client *Client) Beneficiary(address string) (res contract.IdentityBeneficiaryResponse, err error) {
 	response, err := client.http.Get("identities/"+address+"/beneficiary", nil)
@@ -979,6 +1029,30 @@ func (client *Client) FetchConfig() (map[string]interface{}, error) {
 	return config, err
 }
 
+// ConfigProfiles returns configuration profiles and the one in use.
+func (client *Client) ConfigProfiles() (profiles contract.ConfigProfilesDTO, err error) {
+	response, err := client.http.Get("config/profiles", nil)
+	if err != nil {
+		return profiles, err
+	}
+	defer response.Body.Close()
+
+	err = parseResponseJSON(response, &profiles)
+	return profiles, err
+}
+
+// SetConfigProfile selects configuration profile, empty name stops using one.
+func (client *Client) SetConfigProfile(name string) (profiles contract.ConfigProfilesDTO, err error) {
+	response, err := client.http.Put("config/profile", contract.ConfigProfileRequest{Name: name})
+	if err != nil {
+		return profiles, err
+	}
+	defer response.Body.Close()
+
+	err = parseResponseJSON(response, &profiles)
+	return profiles, err
+}
+
 // SetConfig - set user config.
 func (client *Client) SetConfig(data map[string]interface{}) error {
 	req := struct {
@@ -0,0 +1,501 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	contractPackage = "github.com/mysteriumnetwork/node/tequilapi/contract"
+	apierrorPackage = "github.com/mysteriumnetwork/go-rest/apierror"
+)
+
+var specFilename = flag.String("spec", "", "Swagger specification of Tequilapi")
+var outputFilename = flag.String("output", "api_generated.go", "Name of the generated client source file")
+
+func main() {
+	flag.Parse()
+	if *specFilename == "" {
+		fmt.Println("spec parameter expected")
+		os.Exit(1)
+	}
+
+	specJSON, err := ioutil.ReadFile(*specFilename)
+	exitOnError(err)
+
+	var s spec
+	exitOnError(json.Unmarshal(specJSON, &s))
+
+	source, err := generate(s)
+	exitOnError(err)
+
+	exitOnError(ioutil.WriteFile(*outputFilename, source, 0644))
+}
+
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Println("Error: ", err.Error())
+		os.Exit(1)
+	}
+}
+
+type spec struct {
+	Paths       map[string]map[string]operation `json:"paths"`
+	Definitions map[string]schema               `json:"definitions"`
+}
+
+type operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description"`
+	Parameters  []parameter         `json:"parameters"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	GoName   string  `json:"x-go-name"`
+	Schema   *schema `json:"schema"`
+	schema
+}
+
+type response struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string            `json:"$ref"`
+	Type                 string            `json:"type"`
+	Format               string            `json:"format"`
+	Items                *schema           `json:"items"`
+	Properties           map[string]schema `json:"properties"`
+	AdditionalProperties *schema           `json:"additionalProperties"`
+	Title                string            `json:"title"`
+	GoName               string            `json:"x-go-name"`
+	GoPackage            string            `json:"x-go-package"`
+}
+
+type generator struct {
+	spec spec
+	// types maps definition names to the Go types used by the client.
+	types map[string]string
+	// models lists definitions which have no importable Go type and are declared by the client itself.
+	models []string
+	uses   map[string]bool
+	out    bytes.Buffer
+}
+
+func generate(s spec) ([]byte, error) {
+	g := &generator{spec: s, types: make(map[string]string), uses: make(map[string]bool)}
+	g.resolveDefinitions()
+
+	for _, path := range sortedKeys(s.Paths) {
+		for _, method := range sortedKeys(s.Paths[path]) {
+			if err := g.operation(path, method, s.Paths[path][method]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, name := range g.models {
+		g.model(name, s.Definitions[name])
+	}
+
+	var file bytes.Buffer
+	file.WriteString(fileHeader)
+	file.WriteString("import (\n\t\"context\"\n")
+	if g.uses["fmt"] {
+		file.WriteString("\t\"fmt\"\n")
+	}
+	if g.uses["big"] {
+		file.WriteString("\t\"math/big\"\n")
+	}
+	file.WriteString("\t\"net/http\"\n\t\"net/url\"\n")
+	if g.uses["strconv"] {
+		file.WriteString("\t\"strconv\"\n")
+	}
+	if g.uses["time"] {
+		file.WriteString("\t\"time\"\n")
+	}
+	file.WriteString("\n")
+	if g.uses["common"] {
+		file.WriteString("\t\"github.com/ethereum/go-ethereum/common\"\n")
+	}
+	if g.uses["contract"] {
+		file.WriteString("\t\"github.com/mysteriumnetwork/node/tequilapi/contract\"\n")
+	}
+	file.WriteString(")\n\n")
+	file.Write(g.out.Bytes())
+
+	return format.Source(file.Bytes())
+}
+
+// resolveDefinitions maps contract definitions to their Go types and names the remaining ones.
+func (g *generator) resolveDefinitions() {
+	taken := make(map[string]bool)
+	var local []string
+	for _, name := range sortedKeys(g.spec.Definitions) {
+		def := g.spec.Definitions[name]
+		goName := def.GoName
+		if goName == "" {
+			goName = name
+		}
+		switch {
+		case def.GoPackage == contractPackage:
+			g.types[name] = "contract." + goName
+		case def.GoPackage == apierrorPackage:
+			// Errors are parsed by the HTTP layer.
+		case def.GoPackage == "math/big" && goName == "Int":
+			g.types[name] = "*big.Int"
+		case def.GoPackage == "github.com/ethereum/go-ethereum/common" && goName == "Address":
+			g.types[name] = "common.Address"
+		default:
+			local = append(local, name)
+		}
+	}
+
+	for _, name := range local {
+		def := g.spec.Definitions[name]
+		goName := exported(name)
+		if taken[goName] {
+			parts := strings.Split(def.GoPackage, "/")
+			goName = exported(parts[len(parts)-1]) + goName
+		}
+		taken[goName] = true
+		g.types[name] = goName
+		g.models = append(g.models, name)
+	}
+}
+
+func (g *generator) model(name string, def schema) {
+	goName := g.types[name]
+	fmt.Fprintf(&g.out, "// %s is the %s model of Tequilapi.\n", goName, name)
+	if def.Type != "object" || len(def.Properties) == 0 {
+		fmt.Fprintf(&g.out, "type %s %s\n\n", goName, g.typeOf(def))
+		return
+	}
+
+	fmt.Fprintf(&g.out, "type %s struct {\n", goName)
+	for _, prop := range sortedKeys(def.Properties) {
+		p := def.Properties[prop]
+		fieldName := p.GoName
+		if fieldName == "" {
+			fieldName = exported(prop)
+		}
+		fmt.Fprintf(&g.out, "\t%s %s `json:\"%s,omitempty\"`\n", fieldName, g.typeOf(p), prop)
+	}
+	g.out.WriteString("}\n\n")
+}
+
+// typeOf returns Go type of the schema, referencing definitions by their resolved names.
+func (g *generator) typeOf(s schema) string {
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/definitions/")
+		t, ok := g.types[name]
+		if !ok {
+			return "interface{}"
+		}
+		if strings.HasPrefix(t, "contract.") {
+			g.uses["contract"] = true
+		}
+		switch t {
+		case "*big.Int":
+			g.uses["big"] = true
+		case "common.Address":
+			g.uses["common"] = true
+		}
+		return t
+	}
+
+	switch s.Type {
+	case "string":
+		if s.Format == "date-time" {
+			g.uses["time"] = true
+			return "time.Time"
+		}
+		return "string"
+	case "boolean":
+		return "bool"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "integer":
+		switch s.Format {
+		case "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64":
+			return s.Format
+		}
+		return "int"
+	case "array":
+		if s.Items == nil {
+			return "[]interface{}"
+		}
+		return "[]" + g.typeOf(*s.Items)
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "map[string]" + g.typeOf(*s.AdditionalProperties)
+		}
+		if len(s.Properties) > 0 {
+			return "map[string]interface{}"
+		}
+	}
+	return "interface{}"
+}
+
+func (g *generator) operation(path, method string, op operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("operation %s %s has no operationId", strings.ToUpper(method), path)
+	}
+	name := exported(op.OperationID)
+	paramsType := name + "Params"
+
+	var pathParams, queryParams []parameter
+	var body *parameter
+	for i := range op.Parameters {
+		p := op.Parameters[i]
+		switch p.In {
+		case "path":
+			pathParams = append(pathParams, p)
+		case "query":
+			queryParams = append(queryParams, p)
+		case "body":
+			body = &p
+		}
+	}
+	hasParams := len(pathParams)+len(queryParams) > 0 || body != nil
+
+	if hasParams {
+		fmt.Fprintf(&g.out, "// %s holds parameters of %s operation.\n", paramsType, name)
+		fmt.Fprintf(&g.out, "type %s struct {\n", paramsType)
+		for _, p := range pathParams {
+			fmt.Fprintf(&g.out, "\t%s %s\n", paramName(p), g.typeOf(p.schema))
+		}
+		for _, p := range queryParams {
+			t := g.typeOf(p.schema)
+			if !p.Required && !strings.HasPrefix(t, "[]") {
+				t = "*" + t
+			}
+			fmt.Fprintf(&g.out, "\t%s %s\n", paramName(p), t)
+		}
+		if body != nil && body.Schema != nil {
+			fmt.Fprintf(&g.out, "\tBody %s\n", g.typeOf(*body.Schema))
+		}
+		g.out.WriteString("}\n\n")
+	}
+
+	result := ""
+	for _, code := range sortedKeys(op.Responses) {
+		if code >= "200" && code < "300" && op.Responses[code].Schema != nil {
+			if s := op.Responses[code].Schema; s.Type == "file" {
+				result = "[]byte"
+			} else {
+				result = g.typeOf(*s)
+			}
+			break
+		}
+	}
+	// Error responses documented with the result schema (e.g. failed health checks) are results too.
+	var resultCodes []string
+	for _, code := range sortedKeys(op.Responses) {
+		if s := op.Responses[code].Schema; result != "" && result != "[]byte" && code >= "300" && s != nil && g.typeOf(*s) == result {
+			resultCodes = append(resultCodes, code)
+		}
+	}
+
+	fmt.Fprintf(&g.out, "// %s calls %s %s", name, strings.ToUpper(method), path)
+	if summary := strings.TrimSpace(op.Summary); summary != "" {
+		fmt.Fprintf(&g.out, ": %s", strings.TrimSuffix(strings.Split(summary, "\n")[0], "."))
+	}
+	g.out.WriteString(".\n")
+	fmt.Fprintf(&g.out, "func (api *API) %s(ctx context.Context", name)
+	if hasParams {
+		fmt.Fprintf(&g.out, ", params %s", paramsType)
+	}
+	if result != "" {
+		fmt.Fprintf(&g.out, ") (result %s, err error) {\n", result)
+	} else {
+		g.out.WriteString(") error {\n")
+	}
+
+	goPath := fmt.Sprintf("%q", path)
+	for _, p := range pathParams {
+		goPath = strings.Replace(goPath, "{"+p.Name+"}", `"+pathParam(params.`+paramName(p)+`)+"`, 1)
+	}
+	goPath = strings.TrimSuffix(strings.TrimPrefix(goPath, `""+`), `+""`)
+
+	queryArg := "nil"
+	if len(queryParams) > 0 {
+		queryArg = "query"
+		g.out.WriteString("\tquery := url.Values{}\n")
+		for _, p := range queryParams {
+			g.queryParam(p)
+		}
+	}
+
+	bodyArg := "nil"
+	if body != nil && body.Schema != nil {
+		bodyArg = "params.Body"
+	}
+	method = "http.Method" + exported(strings.ToLower(method))
+	if result != "" {
+		resultArg := "&result"
+		for _, code := range resultCodes {
+			resultArg += ", " + code
+		}
+		fmt.Fprintf(&g.out, "\terr = api.do(ctx, %s, %s, %s, %s, %s)\n\treturn result, err\n}\n\n", method, goPath, queryArg, bodyArg, resultArg)
+	} else {
+		fmt.Fprintf(&g.out, "\treturn api.do(ctx, %s, %s, %s, %s, nil)\n}\n\n", method, goPath, queryArg, bodyArg)
+	}
+	return nil
+}
+
+func (g *generator) queryParam(p parameter) {
+	field := "params." + paramName(p)
+	t := g.typeOf(p.schema)
+	if strings.HasPrefix(t, "[]") {
+		fmt.Fprintf(&g.out, "\tfor _, v := range %s {\n\t\tquery.Add(%q, %s)\n\t}\n", field, p.Name, formatValue("v", t[2:], g))
+		return
+	}
+	if p.Required {
+		fmt.Fprintf(&g.out, "\tquery.Set(%q, %s)\n", p.Name, formatValue(field, t, g))
+		return
+	}
+	fmt.Fprintf(&g.out, "\tif %s != nil {\n\t\tquery.Set(%q, %s)\n\t}\n", field, p.Name, formatValue("*"+field, t, g))
+}
+
+func formatValue(v, t string, g *generator) string {
+	switch t {
+	case "string":
+		return v
+	case "bool":
+		g.uses["strconv"] = true
+		return "strconv.FormatBool(" + v + ")"
+	case "int":
+		g.uses["strconv"] = true
+		return "strconv.Itoa(" + v + ")"
+	case "int64":
+		g.uses["strconv"] = true
+		return "strconv.FormatInt(" + v + ", 10)"
+	case "int8", "int16", "int32":
+		g.uses["strconv"] = true
+		return "strconv.FormatInt(int64(" + v + "), 10)"
+	case "uint64":
+		g.uses["strconv"] = true
+		return "strconv.FormatUint(" + v + ", 10)"
+	case "uint8", "uint16", "uint32":
+		g.uses["strconv"] = true
+		return "strconv.FormatUint(uint64(" + v + "), 10)"
+	case "float64":
+		g.uses["strconv"] = true
+		return "strconv.FormatFloat(" + v + ", 'f', -1, 64)"
+	case "float32":
+		g.uses["strconv"] = true
+		return "strconv.FormatFloat(float64(" + v + "), 'f', -1, 32)"
+	case "time.Time":
+		return "(" + v + ").Format(time.RFC3339)"
+	}
+	g.uses["fmt"] = true
+	return "fmt.Sprint(" + v + ")"
+}
+
+func paramName(p parameter) string {
+	if p.GoName != "" {
+		return p.GoName
+	}
+	return exported(p.Name)
+}
+
+var initialisms = map[string]bool{"API": true, "DNS": true, "ID": true, "IP": true, "NAT": true, "URL": true, "UI": true}
+
+// exported converts snake, kebab or camel cased name to exported Go identifier.
+func exported(name string) string {
+	var words []string
+	var word []rune
+	prevLower := false
+	for _, r := range name {
+		if r == '_' || r == '-' || r == '.' || r == ' ' || (prevLower && unicode.IsUpper(r)) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = nil
+		}
+		prevLower = unicode.IsLower(r) || unicode.IsDigit(r)
+		if r != '_' && r != '-' && r != '.' && r != ' ' {
+			word = append(word, r)
+		}
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		r := []rune(w)
+		b.WriteRune(unicode.ToUpper(r[0]))
+		b.WriteString(string(r[1:]))
+	}
+	return b.String()
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var fileHeader = `/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Code generated by generator.go from Tequilapi Swagger specification. DO NOT EDIT.
+
+package client
+
+`
@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedClientMatchesSpec(t *testing.T) {
+	specJSON, err := ioutil.ReadFile("../../docs/swagger.json")
+	require.NoError(t, err)
+	var s spec
+	require.NoError(t, json.Unmarshal(specJSON, &s))
+
+	source, err := generate(s)
+	require.NoError(t, err)
+
+	generated, err := ioutil.ReadFile("../api_generated.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(source), string(generated), `client is out of date, regenerate it with "mage GenerateClient"`)
+}
+
+func TestGenerate_ErrorResponseWithResultSchema(t *testing.T) {
+	var s spec
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"paths": {"/readyz": {"get": {
+			"operationId": "readyz",
+			"responses": {
+				"200": {"schema": {"$ref": "#/definitions/HealthStatusDTO"}},
+				"503": {"schema": {"$ref": "#/definitions/HealthStatusDTO"}}
+			}
+		}}},
+		"definitions": {"HealthStatusDTO": {"type": "object", "x-go-package": "github.com/mysteriumnetwork/node/tequilapi/contract"}}
+	}`), &s))
+
+	source, err := generate(s)
+
+	require.NoError(t, err)
+	assert.Contains(t, string(source), `api.do(ctx, http.MethodGet, "/readyz", nil, nil, &result, 503)`)
+}
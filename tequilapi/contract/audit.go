@@ -0,0 +1,147 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/auth"
+)
+
+const (
+	auditLimitDefault = 100
+	auditLimitMax     = 1000
+)
+
+// NewAuditQuery creates audit query with default values.
+func NewAuditQuery() AuditQuery {
+	return AuditQuery{Limit: auditLimitDefault}
+}
+
+// AuditQuery allows to filter requested audit entries.
+// swagger:parameters auditList
+type AuditQuery struct {
+	// List entries recorded at or after this time. Formatted in RFC3339 e.g. 2022-07-01T00:00:00Z.
+	// in: query
+	Since *time.Time `json:"since"`
+
+	// List entries recorded at or before this time. Formatted in RFC3339 e.g. 2022-07-30T00:00:00Z.
+	// in: query
+	Until *time.Time `json:"until"`
+
+	// List entries of this actor only, e.g. "ui" or "token:<id>".
+	// in: query
+	Actor string `json:"actor"`
+
+	// Maximum number of entries to return, newest first. Defaults to 100, at most 1000.
+	// in: query
+	Limit int `json:"limit"`
+}
+
+// Bind creates and validates query from API request.
+func (q *AuditQuery) Bind(request *http.Request) *apierror.APIError {
+	v := apierror.NewValidator()
+
+	qs := request.URL.Query()
+	if qStr := qs.Get("since"); qStr != "" {
+		if qVal, err := time.Parse(time.RFC3339, qStr); err != nil {
+			v.Invalid("since", "Could not parse 'since'")
+		} else {
+			q.Since = &qVal
+		}
+	}
+	if qStr := qs.Get("until"); qStr != "" {
+		if qVal, err := time.Parse(time.RFC3339, qStr); err != nil {
+			v.Invalid("until", "Could not parse 'until'")
+		} else {
+			q.Until = &qVal
+		}
+	}
+	q.Actor = qs.Get("actor")
+	if qStr := qs.Get("limit"); qStr != "" {
+		if qVal, err := strconv.Atoi(qStr); err != nil || qVal < 1 || qVal > auditLimitMax {
+			v.Invalid("limit", "'limit' must be a number between 1 and "+strconv.Itoa(auditLimitMax))
+		} else {
+			q.Limit = qVal
+		}
+	}
+
+	return v.Err()
+}
+
+// ToFilter converts API query to storage filter.
+func (q *AuditQuery) ToFilter() auth.AuditFilter {
+	return auth.AuditFilter{
+		Since: q.Since,
+		Until: q.Until,
+		Actor: q.Actor,
+		Limit: q.Limit,
+	}
+}
+
+// AuditEntryDTO represents a recorded management request.
+// swagger:model AuditEntryDTO
+type AuditEntryDTO struct {
+	// example: 2022-07-01T12:00:00Z
+	Time time.Time `json:"time"`
+	// Who made the request: "ui" for UI sessions, "token:<id>" for API tokens, empty if no token was presented.
+	// example: token:3f1c9a2b
+	Actor string `json:"actor"`
+	// example: 192.168.1.10
+	RemoteIP string `json:"remote_ip"`
+	// Whether the request was received by the remote management listener.
+	Remote bool `json:"remote"`
+	// example: PUT
+	Method string `json:"method"`
+	// example: /connection
+	Route string `json:"route"`
+	// example: /connection
+	Path string `json:"path"`
+	// example: 200
+	Status     int   `json:"status"`
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// AuditEntryListDTO represents a list of recorded management requests.
+// swagger:model AuditEntryListDTO
+type AuditEntryListDTO struct {
+	Entries []AuditEntryDTO `json:"entries"`
+}
+
+// NewAuditEntryListDTO maps audit entries to the public API.
+func NewAuditEntryListDTO(entries []auth.AuditEntry) AuditEntryListDTO {
+	dto := AuditEntryListDTO{Entries: make([]AuditEntryDTO, 0, len(entries))}
+	for _, e := range entries {
+		dto.Entries = append(dto.Entries, AuditEntryDTO{
+			Time:       e.Time,
+			Actor:      e.Actor,
+			RemoteIP:   e.RemoteIP,
+			Remote:     e.Remote,
+			Method:     e.Method,
+			Route:      e.Route,
+			Path:       e.Path,
+			Status:     e.Status,
+			DurationMs: e.Duration.Milliseconds(),
+		})
+	}
+	return dto
+}
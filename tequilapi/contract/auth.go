@@ -20,6 +20,8 @@ package contract
 import (
 	"time"
 
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
 	"github.com/mysteriumnetwork/node/core/auth"
 )
 
@@ -55,3 +57,95 @@ type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password"`
 	NewPassword string `json:"new_password"`
 }
+
+// APITokenRequest request used to issue a scoped API token, authenticated with UI credentials.
+// swagger:model APITokenRequest
+type APITokenRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// example: monitoring
+	Name string `json:"name"`
+
+	// example: ["read"]
+	Scopes []string `json:"scopes"`
+}
+
+// Validate validates fields in request.
+func (r APITokenRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if r.Name == "" {
+		v.Required("name")
+	}
+	if len(r.Scopes) == 0 {
+		v.Required("scopes")
+	}
+	for _, name := range r.Scopes {
+		if _, err := auth.ParseScope(name); err != nil {
+			v.Invalid("scopes", err.Error())
+			break
+		}
+	}
+	return v.Err()
+}
+
+// ParsedScopes returns requested scopes, skipping unknown ones.
+func (r APITokenRequest) ParsedScopes() []auth.Scope {
+	scopes := make([]auth.Scope, 0, len(r.Scopes))
+	for _, name := range r.Scopes {
+		if scope, err := auth.ParseScope(name); err == nil {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// APITokenDTO describes an issued API token.
+// swagger:model APITokenDTO
+type APITokenDTO struct {
+	// example: 6f1c2b3a4d5e6f70
+	ID string `json:"id"`
+
+	// example: monitoring
+	Name string `json:"name"`
+
+	// example: ["read"]
+	Scopes []string `json:"scopes"`
+
+	// example: 2019-06-06T11:04:43.910035Z
+	CreatedAt string `json:"created_at"`
+
+	// example: 2019-06-06T11:04:43.910035Z
+	RotatedAt string `json:"rotated_at,omitempty"`
+}
+
+// NewAPITokenDTO maps API token details to DTO.
+func NewAPITokenDTO(token auth.APIToken) APITokenDTO {
+	dto := APITokenDTO{
+		ID:        token.ID,
+		Name:      token.Name,
+		Scopes:    make([]string, 0, len(token.Scopes)),
+		CreatedAt: token.CreatedAt.Format(time.RFC3339),
+	}
+	for _, scope := range token.Scopes {
+		dto.Scopes = append(dto.Scopes, string(scope))
+	}
+	if token.RotatedAt != nil {
+		dto.RotatedAt = token.RotatedAt.Format(time.RFC3339)
+	}
+	return dto
+}
+
+// APITokenResponse holds a newly issued or rotated API token. The secret is returned only once.
+// swagger:model APITokenResponse
+type APITokenResponse struct {
+	// example: myst_6f1c2b3a4d5e6f70.3q2-7wAAAAA
+	Token string `json:"token"`
+	APITokenDTO
+}
+
+// APITokenListResponse lists issued API tokens.
+// swagger:model APITokenListResponse
+type APITokenListResponse struct {
+	Tokens []APITokenDTO `json:"tokens"`
+}
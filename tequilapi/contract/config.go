@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"github.com/mysteriumnetwork/node/config"
+)
+
+// ConfigProfileDTO describes a configuration profile.
+// swagger:model ConfigProfileDTO
+type ConfigProfileDTO struct {
+	// example: home-provider
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Configuration values of the profile by their flag names.
+	// example: {"network":"mainnet","shaper.enabled":true}
+	Values map[string]interface{} `json:"values"`
+	// Custom is set for profiles defined in user configuration.
+	Custom bool `json:"custom"`
+}
+
+// ConfigProfilesDTO lists configuration profiles.
+// swagger:model ConfigProfilesDTO
+type ConfigProfilesDTO struct {
+	// Name of the profile in use, empty if none is used.
+	// example: home-provider
+	Current  string             `json:"current"`
+	Profiles []ConfigProfileDTO `json:"profiles"`
+}
+
+// NewConfigProfilesDTO maps configuration profiles to DTO.
+func NewConfigProfilesDTO(current string, profiles []config.Profile) ConfigProfilesDTO {
+	dto := ConfigProfilesDTO{
+		Current:  current,
+		Profiles: make([]ConfigProfileDTO, 0, len(profiles)),
+	}
+	for _, p := range profiles {
+		dto.Profiles = append(dto.Profiles, ConfigProfileDTO{
+			Name:        p.Name,
+			Description: p.Description,
+			Values:      p.Values,
+			Custom:      p.Custom,
+		})
+	}
+	return dto
+}
+
+// ConfigProfileRequest selects a configuration profile.
+// swagger:model ConfigProfileRequest
+type ConfigProfileRequest struct {
+	// Name of the profile to use, empty to stop using one.
+	// example: testnet
+	Name string `json:"name"`
+}
@@ -181,6 +181,10 @@ type ConnectionCreateFilter struct {
 	IPType                  string   `json:"ip_type,omitempty"`
 	IncludeMonitoringFailed bool     `json:"include_monitoring_failed,omitempty"`
 	SortBy                  string   `json:"sort_by,omitempty"`
+	// maximum price per hour in wei
+	PricePerHourMax *big.Int `json:"price_per_hour_max,omitempty"`
+	// maximum price per GiB in wei
+	PricePerGiBMax *big.Int `json:"price_per_gib_max,omitempty"`
 }
 
 // Validate validates fields in request.
@@ -214,8 +218,47 @@ type ConnectOptions struct {
 	// DNS to use
 	// required: false
 	// default: auto
-	// example: auto, provider, system, "1.1.1.1,8.8.8.8"
+	// example: auto, provider, system, "1.1.1.1,8.8.8.8", "https://cloudflare-dns.com/dns-query,tls://9.9.9.9"
 	DNS connection.DNSOption `json:"dns"`
+	// filter DNS queries with the node domain blocklist
+	// required: false
+	// example: true
+	DNSBlocklist bool `json:"dns_blocklist"`
 
 	ProxyPort int `json:"proxy_port"`
 }
+
+// ConnectionWarningLocationMismatch is the warning type of exit country not matching the declared one.
+const ConnectionWarningLocationMismatch = "location_mismatch"
+
+// ConnectionWarningDTO describes a problem noticed with an established connection.
+// swagger:model ConnectionWarningDTO
+type ConnectionWarningDTO struct {
+	// example: location_mismatch
+	Type string `json:"type"`
+
+	// example: 0x0000000000000000000000000000000000000001
+	ProviderID string `json:"provider_id"`
+
+	// example: 4cfb0324-daf6-4ad8-448b-e61fe0a1f918
+	SessionID string `json:"session_id"`
+
+	// country declared in the provider proposal
+	// example: US
+	DeclaredCountry string `json:"declared_country"`
+
+	// country detected through the tunnel
+	// example: LT
+	ExitCountry string `json:"exit_country"`
+}
+
+// NewLocationMismatchWarningDTO maps location mismatch event to a connection warning.
+func NewLocationMismatchWarningDTO(e connectionstate.AppEventLocationMismatch) ConnectionWarningDTO {
+	return ConnectionWarningDTO{
+		Type:            ConnectionWarningLocationMismatch,
+		ProviderID:      e.Proposal.ProviderID,
+		SessionID:       string(e.SessionID),
+		DeclaredCountry: e.DeclaredCountry,
+		ExitCountry:     e.ExitCountry,
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/connection/profile"
+)
+
+var profileNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// ConnectionProfileDTO is a named set of connection parameters.
+// swagger:model ConnectionProfileDTO
+type ConnectionProfileDTO struct {
+	// profile name consisting of letters, digits, '-' and '_'
+	// required: true
+	// example: eu-cheap
+	Name string `json:"name"`
+
+	// consumer identity
+	// required: true
+	// example: 0x0000000000000000000000000000000000000001
+	ConsumerID string `json:"consumer_id"`
+
+	// hermes identity, the active one is used if empty
+	// example: 0x0000000000000000000000000000000000000003
+	HermesID string `json:"hermes_id,omitempty"`
+
+	// service type
+	// example: wireguard
+	ServiceType string `json:"service_type"`
+
+	Filter ConnectionCreateFilter `json:"filter"`
+
+	ConnectOptions ConnectOptions `json:"connect_options"`
+}
+
+// NewConnectionProfileDTO maps connection profile to DTO.
+func NewConnectionProfileDTO(p profile.Profile) ConnectionProfileDTO {
+	return ConnectionProfileDTO{
+		Name:        p.Name,
+		ConsumerID:  p.ConsumerID,
+		HermesID:    p.HermesID,
+		ServiceType: p.ServiceType,
+		Filter: ConnectionCreateFilter{
+			Providers:               p.Providers,
+			CountryCode:             p.CountryCode,
+			IPType:                  p.IPType,
+			IncludeMonitoringFailed: p.IncludeMonitoringFailed,
+			SortBy:                  p.SortBy,
+			PricePerHourMax:         p.PricePerHourMax,
+			PricePerGiBMax:          p.PricePerGiBMax,
+		},
+		ConnectOptions: ConnectOptions{
+			DisableKillSwitch: p.DisableKillSwitch,
+			DNS:               p.DNS,
+			DNSBlocklist:      p.DNSBlocklist,
+			ProxyPort:         p.ProxyPort,
+		},
+	}
+}
+
+// Profile maps DTO to connection profile.
+func (p ConnectionProfileDTO) Profile() profile.Profile {
+	return profile.Profile{
+		Name:                    p.Name,
+		ConsumerID:              p.ConsumerID,
+		HermesID:                p.HermesID,
+		ServiceType:             p.ServiceType,
+		Providers:               p.Filter.Providers,
+		CountryCode:             p.Filter.CountryCode,
+		IPType:                  p.Filter.IPType,
+		IncludeMonitoringFailed: p.Filter.IncludeMonitoringFailed,
+		SortBy:                  p.Filter.SortBy,
+		PricePerHourMax:         p.Filter.PricePerHourMax,
+		PricePerGiBMax:          p.Filter.PricePerGiBMax,
+		DisableKillSwitch:       p.ConnectOptions.DisableKillSwitch,
+		DNS:                     p.ConnectOptions.DNS,
+		DNSBlocklist:            p.ConnectOptions.DNSBlocklist,
+		ProxyPort:               p.ConnectOptions.ProxyPort,
+	}
+}
+
+// ConnectionRequest returns request connecting with the profile parameters.
+func (p ConnectionProfileDTO) ConnectionRequest(defaultHermes string) *ConnectionCreateRequest {
+	cr := &ConnectionCreateRequest{
+		ConsumerID:     p.ConsumerID,
+		HermesID:       p.HermesID,
+		ServiceType:    p.ServiceType,
+		Filter:         p.Filter,
+		ConnectOptions: p.ConnectOptions,
+	}
+	if cr.HermesID == "" {
+		cr.HermesID = defaultHermes
+	}
+	return cr
+}
+
+// Validate validates fields in request.
+func (p ConnectionProfileDTO) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	p.validate(v, "")
+	return v.Err()
+}
+
+func (p ConnectionProfileDTO) validate(v *apierror.Validator, prefix string) {
+	if !profileNamePattern.MatchString(p.Name) {
+		v.Invalid(prefix+"name", "Name must consist of 1-64 letters, digits, '-' or '_'")
+	}
+	if len(p.ConsumerID) == 0 {
+		v.Required(prefix + "consumer_id")
+	}
+	if p.Filter.PricePerHourMax != nil && p.Filter.PricePerHourMax.Sign() < 0 {
+		v.Invalid(prefix+"filter.price_per_hour_max", "Price must not be negative")
+	}
+	if p.Filter.PricePerGiBMax != nil && p.Filter.PricePerGiBMax.Sign() < 0 {
+		v.Invalid(prefix+"filter.price_per_gib_max", "Price must not be negative")
+	}
+}
+
+// ConnectionProfileListDTO holds all connection profiles.
+// swagger:model ConnectionProfileListDTO
+type ConnectionProfileListDTO struct {
+	Profiles []ConnectionProfileDTO `json:"profiles"`
+}
+
+// NewConnectionProfileListDTO maps connection profiles to DTO.
+func NewConnectionProfileListDTO(profiles []profile.Profile) ConnectionProfileListDTO {
+	list := ConnectionProfileListDTO{Profiles: make([]ConnectionProfileDTO, 0, len(profiles))}
+	for _, p := range profiles {
+		list.Profiles = append(list.Profiles, NewConnectionProfileDTO(p))
+	}
+	return list
+}
+
+// Validate validates fields in request.
+func (l ConnectionProfileListDTO) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	names := make(map[string]bool, len(l.Profiles))
+	for i, p := range l.Profiles {
+		prefix := fmt.Sprintf("profiles[%d].", i)
+		p.validate(v, prefix)
+		if names[p.Name] {
+			v.Invalid(prefix+"name", "Profile names must be unique")
+		}
+		names[p.Name] = true
+	}
+	return v.Err()
+}
+
+// Profiles maps DTO to connection profiles.
+func (l ConnectionProfileListDTO) ProfileList() []profile.Profile {
+	profiles := make([]profile.Profile, 0, len(l.Profiles))
+	for _, p := range l.Profiles {
+		profiles = append(profiles, p.Profile())
+	}
+	return profiles
+}
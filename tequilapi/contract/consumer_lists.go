@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/policy"
+)
+
+// ConsumerListsDTO describes consumer identity allow and block lists of the provider.
+// swagger:model ConsumerListsDTO
+type ConsumerListsDTO struct {
+	// Locally managed allowlist. Only allowlisted consumers can start sessions if any allowlist is not empty.
+	// example: ["0x000000000000000000000000000000000000000a"]
+	Allow []string `json:"allow"`
+	// Locally managed blocklist
+	// example: ["0x000000000000000000000000000000000000000b"]
+	Block []string `json:"block"`
+	// Number of identities in remote allowlists
+	// example: 0
+	RemoteAllow int `json:"remote_allow"`
+	// Number of identities in remote blocklists
+	// example: 120
+	RemoteBlock int `json:"remote_block"`
+	// Remote allowlist URLs or file paths
+	AllowSources []string `json:"allow_sources"`
+	// Remote blocklist URLs or file paths
+	BlockSources []string `json:"block_sources"`
+	// Time of the last remote lists update
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// Error of the last remote lists update
+	UpdateError string `json:"update_error,omitempty"`
+}
+
+// NewConsumerListsDTO maps consumer lists state to DTO.
+func NewConsumerListsDTO(state policy.ConsumerListsState) ConsumerListsDTO {
+	dto := ConsumerListsDTO{
+		Allow:        state.Allow,
+		Block:        state.Block,
+		RemoteAllow:  state.RemoteAllow,
+		RemoteBlock:  state.RemoteBlock,
+		AllowSources: state.Sources[policy.ConsumerAllowlist],
+		BlockSources: state.Sources[policy.ConsumerBlocklist],
+		UpdateError:  state.LastUpdateErr,
+	}
+	if dto.AllowSources == nil {
+		dto.AllowSources = []string{}
+	}
+	if dto.BlockSources == nil {
+		dto.BlockSources = []string{}
+	}
+	if !state.UpdatedAt.IsZero() {
+		updatedAt := state.UpdatedAt
+		dto.UpdatedAt = &updatedAt
+	}
+	return dto
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/dns"
+)
+
+// DNSBlocklistDTO describes domain blocklist used by consumer DNS filtering.
+// swagger:model DNSBlocklistDTO
+type DNSBlocklistDTO struct {
+	// Whether any blocklist sources are configured
+	Enabled bool `json:"enabled"`
+	// Blocklist URLs or file paths
+	// example: ["https://example.com/hosts.txt"]
+	Sources []string `json:"sources"`
+	// Number of blocked domains
+	// example: 120000
+	Domains int `json:"domains"`
+	// Time of the last successful update
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// Number of DNS queries filtered
+	// example: 1500
+	Queries uint64 `json:"queries"`
+	// Number of DNS queries blocked
+	// example: 230
+	Blocked uint64 `json:"blocked"`
+	// Most blocked domains
+	TopBlocked []BlockedDomainDTO `json:"top_blocked"`
+}
+
+// BlockedDomainDTO holds count of blocked queries of a domain.
+// swagger:model BlockedDomainDTO
+type BlockedDomainDTO struct {
+	// example: ads.example.com
+	Domain string `json:"domain"`
+	// example: 42
+	Count uint64 `json:"count"`
+}
+
+// NewDNSBlocklistDTO maps blocklist stats to DTO.
+func NewDNSBlocklistDTO(stats dns.BlocklistStats) DNSBlocklistDTO {
+	dto := DNSBlocklistDTO{
+		Enabled:    len(stats.Sources) > 0,
+		Sources:    stats.Sources,
+		Domains:    stats.Domains,
+		Queries:    stats.Queries,
+		Blocked:    stats.Blocked,
+		TopBlocked: []BlockedDomainDTO{},
+	}
+	if dto.Sources == nil {
+		dto.Sources = []string{}
+	}
+	if !stats.UpdatedAt.IsZero() {
+		updatedAt := stats.UpdatedAt
+		dto.UpdatedAt = &updatedAt
+	}
+	for _, domain := range stats.TopBlocked {
+		dto.TopBlocked = append(dto.TopBlocked, BlockedDomainDTO{Domain: domain.Domain, Count: domain.Count})
+	}
+	return dto
+}
@@ -24,6 +24,12 @@ const (
 	// Identity
 
 	ErrCodeIDImport                      = "err_id_import"
+	ErrCodeIDExport                      = "err_id_export"
+	ErrCodeIDBackup                      = "err_id_backup"
+	ErrCodeIDRestore                     = "err_id_restore"
+	ErrCodeIDMnemonic                    = "err_id_mnemonic"
+	ErrCodeIDRotate                      = "err_id_rotate"
+	ErrCodeIDRotations                   = "err_id_rotations"
 	ErrCodeIDSetDefault                  = "err_id_set_default"
 	ErrCodeIDUseOrCreate                 = "err_to_id_use_or_create"
 	ErrCodeIDUnlock                      = "err_id_unlock"
@@ -55,9 +61,27 @@ const (
 	ErrCodeReferralGetToken = "err_referral_get_token"
 	ErrCodeBeneficiaryGet   = "err_beneficiary_get"
 
+	// Auth
+
+	ErrCodeAuthScope      = "err_auth_scope"
+	ErrCodeAuthTokenIssue = "err_auth_token_issue"
+	ErrCodeAuthTokenList  = "err_auth_token_list"
+	ErrCodeRateLimited    = "err_rate_limited"
+
+	// Audit
+
+	ErrCodeAuditQuery = "err_audit_query"
+
 	// Config
 
-	ErrCodeConfigSave = "err_config_save"
+	ErrCodeConfigSave    = "err_config_save"
+	ErrCodeConfigProfile = "err_config_profile"
+
+	// Webhooks
+
+	ErrCodeWebhookList   = "err_webhook_list"
+	ErrCodeWebhookSave   = "err_webhook_save"
+	ErrCodeWebhookDelete = "err_webhook_delete"
 
 	// Connection
 
@@ -66,6 +90,20 @@ const (
 	ErrCodeConnect                 = "err_connect"
 	ErrCodeNoConnectionExists      = "err_no_connection_exists"
 	ErrCodeDisconnect              = "err_disconnect"
+	ErrCodeProposalNotSigned       = "err_proposal_not_signed"
+	ErrCodeProposalSignature       = "err_proposal_signature"
+	ErrCodeProviderNotRegistered   = "err_provider_not_registered"
+	ErrCodeSpeedTest               = "err_speed_test"
+
+	// Connection profiles
+
+	ErrCodeConnectionProfileList   = "err_connection_profile_list"
+	ErrCodeConnectionProfileSave   = "err_connection_profile_save"
+	ErrCodeConnectionProfileDelete = "err_connection_profile_delete"
+
+	// State sync
+
+	ErrCodeStateSync = "err_state_sync"
 
 	// Feedback
 
@@ -81,6 +119,18 @@ const (
 
 	ErrCodeNATProbe = "err_nat_probe"
 
+	// Diagnostics
+
+	ErrCodeDiagnosticsBundle = "err_diagnostics_bundle"
+
+	// Rules
+
+	ErrCodeRulesStore = "err_rules_store"
+
+	// Consumer lists
+
+	ErrCodeConsumerListsUpdate = "err_consumer_lists_update"
+
 	// Proposals
 
 	ErrCodeProposalsQuery          = "err_proposals_query"
@@ -89,6 +139,7 @@ const (
 	ErrCodeProposalsPrices         = "err_proposals_prices"
 	ErrCodeProposalsPresets        = "err_proposals_presets"
 	ErrCodeProposalsServiceType    = "err_proposals_service_type"
+	ErrCodeProposalsPing           = "err_proposals_ping"
 
 	// Service
 
@@ -99,12 +150,24 @@ const (
 	ErrCodeServiceStart    = "err_service_start"
 	ErrCodeServiceStop     = "err_service_stop"
 
+	// Node
+
+	ErrCodeNodeSelfCheck = "err_node_self_check"
+	ErrCodeNodeUpdate    = "err_node_update"
+
 	// Sessions
 
 	ErrCodeSessionList         = "err_session_list"
 	ErrCodeSessionListPaginate = "err_session_list_paginate"
 	ErrCodeSessionStats        = "err_session_stats"
 	ErrCodeSessionStatsDaily   = "err_session_stats_daily"
+	ErrCodeSessionNotice       = "err_session_notice"
+	ErrCodeSessionTraffic      = "err_session_traffic"
+
+	// Events
+
+	ErrCodeEventsTopic  = "err_events_topic"
+	ErrCodeEventsLastID = "err_events_last_id"
 
 	// Transactor
 
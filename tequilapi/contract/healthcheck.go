@@ -17,6 +17,8 @@
 
 package contract
 
+import "github.com/mysteriumnetwork/node/diagnostics"
+
 // HealthCheckDTO holds API healthcheck.
 // swagger:model HealthCheckDTO
 type HealthCheckDTO struct {
@@ -43,3 +45,43 @@ type BuildInfoDTO struct {
 	// example: dev-build
 	BuildNumber string `json:"build_number"`
 }
+
+// HealthStatusDTO holds status of node dependencies.
+// swagger:model HealthStatusDTO
+type HealthStatusDTO struct {
+	// example: true
+	Healthy      bool                  `json:"healthy"`
+	Dependencies []DependencyStatusDTO `json:"dependencies"`
+}
+
+// DependencyStatusDTO holds status of a single node dependency.
+// swagger:model DependencyStatusDTO
+type DependencyStatusDTO struct {
+	// example: broker
+	Name string `json:"name"`
+
+	// example: true
+	Healthy bool `json:"healthy"`
+
+	// example: 12
+	LatencyMs int64 `json:"latency_ms"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// NewHealthStatusDTO maps health report to the public API.
+func NewHealthStatusDTO(report diagnostics.HealthReport) HealthStatusDTO {
+	dto := HealthStatusDTO{
+		Healthy:      report.Healthy,
+		Dependencies: make([]DependencyStatusDTO, 0, len(report.Checks)),
+	}
+	for _, check := range report.Checks {
+		dto.Dependencies = append(dto.Dependencies, DependencyStatusDTO{
+			Name:      check.Name,
+			Healthy:   check.Healthy,
+			LatencyMs: check.LatencyMs,
+			Error:     check.Error,
+		})
+	}
+	return dto
+}
@@ -18,7 +18,9 @@
 package contract
 
 import (
+	"encoding/json"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/mysteriumnetwork/go-rest/apierror"
@@ -203,3 +205,169 @@ func (i *IdentityImportRequest) Validate() *apierror.APIError {
 	}
 	return v.Err()
 }
+
+// IdentityBundleVersion is the version of identity bundles produced by the export endpoint.
+const IdentityBundleVersion = 1
+
+// IdentityExportRequest is received in identity export endpoint.
+// swagger:model IdentityExportRequest
+type IdentityExportRequest struct {
+	CurrentPassphrase string `json:"current_passphrase"`
+	// Passphrase encrypting the key in the bundle, it is required to import the identity.
+	ExportPassphrase string `json:"export_passphrase"`
+}
+
+// Validate validates the export request.
+func (i *IdentityExportRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if len(i.ExportPassphrase) == 0 {
+		v.Required("export_passphrase")
+	}
+	return v.Err()
+}
+
+// IdentityBundle moves an identity between nodes: the key encrypted with the export passphrase
+// along with the registration status and the beneficiary known to the exporting node.
+// swagger:model IdentityBundle
+type IdentityBundle struct {
+	Version int `json:"version"`
+	// example: 0x0000000000000000000000000000000000000001
+	Address string `json:"address"`
+	ChainID int64  `json:"chain_id"`
+	// Ethereum keystore JSON of the identity key.
+	Keystore json.RawMessage `json:"keystore"`
+	// example: Registered
+	RegistrationStatus string `json:"registration_status"`
+	// example: 0x0000000000000000000000000000000000000002
+	Beneficiary string `json:"beneficiary,omitempty"`
+}
+
+// IdentityBundleImportRequest is received in identity bundle import endpoint.
+// swagger:model IdentityBundleImportRequest
+type IdentityBundleImportRequest struct {
+	Bundle *IdentityBundle `json:"bundle"`
+	// Passphrase the bundle was exported with.
+	Passphrase string `json:"passphrase"`
+
+	// Optional. Default values are OK.
+	SetDefault    bool   `json:"set_default"`
+	NewPassphrase string `json:"new_passphrase"`
+}
+
+// Validate validates the bundle import request.
+func (i *IdentityBundleImportRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if i.Bundle == nil || len(i.Bundle.Keystore) == 0 {
+		v.Required("bundle")
+	} else if i.Bundle.Version != IdentityBundleVersion {
+		v.Invalid("bundle", "Unsupported bundle version")
+	}
+	if len(i.Passphrase) == 0 {
+		v.Required("passphrase")
+	}
+	return v.Err()
+}
+
+// IdentityBundleImportResponse represents the imported identity.
+// swagger:model IdentityBundleImportResponse
+type IdentityBundleImportResponse struct {
+	// example: 0x0000000000000000000000000000000000000001
+	Address string `json:"id"`
+	// Registration status on the importing node, taken from the bundle until it can be checked.
+	// example: Registered
+	RegistrationStatus string `json:"registration_status"`
+	Beneficiary        string `json:"beneficiary,omitempty"`
+}
+
+// IdentityMnemonicResponse holds a BIP-39 mnemonic.
+// swagger:model IdentityMnemonicResponse
+type IdentityMnemonicResponse struct {
+	Mnemonic string `json:"mnemonic"`
+}
+
+// IdentityBackupRequest is received in identity backup endpoint.
+// swagger:model IdentityBackupRequest
+type IdentityBackupRequest struct {
+	CurrentPassphrase string `json:"current_passphrase"`
+	// Passphrase encrypting the backup mnemonic, it is required to restore the identity.
+	BackupPassphrase string `json:"backup_passphrase"`
+}
+
+// Validate validates the backup request.
+func (i *IdentityBackupRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if len(i.BackupPassphrase) == 0 {
+		v.Required("backup_passphrase")
+	}
+	return v.Err()
+}
+
+// IdentityRestoreRequest is received in identity restore endpoint.
+// swagger:model IdentityRestoreRequest
+type IdentityRestoreRequest struct {
+	Mnemonic string `json:"mnemonic"`
+	// Backup marks the mnemonic as an encrypted identity backup instead of BIP-39 seed mnemonic.
+	Backup bool `json:"backup"`
+	// Passphrase of the backup, or optional BIP-39 password of the seed mnemonic.
+	Passphrase string `json:"passphrase,omitempty"`
+
+	// Optional. Address of the backed up identity, used to verify the backup passphrase.
+	Address string `json:"address,omitempty"`
+	// Optional. BIP-44 derivation path of the identity derived from seed mnemonic.
+	// example: m/44'/60'/0'/0/0
+	DerivationPath string `json:"derivation_path,omitempty"`
+	SetDefault     bool   `json:"set_default"`
+	NewPassphrase  string `json:"new_passphrase"`
+}
+
+// Validate validates the restore request.
+func (i *IdentityRestoreRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if len(i.Mnemonic) == 0 {
+		v.Required("mnemonic")
+	}
+	if i.Backup && len(i.Passphrase) == 0 {
+		v.Required("passphrase")
+	}
+	return v.Err()
+}
+
+// IdentityRotateRequest is received in identity rotation endpoint.
+// swagger:model IdentityRotateRequest
+type IdentityRotateRequest struct {
+	// Passphrase protecting the new identity key.
+	NewPassphrase string `json:"new_passphrase"`
+	// Use the new identity by default on the next node start.
+	SetDefault bool `json:"set_default"`
+}
+
+// IdentityRotationDTO is a migration of an identity to a new key signed by both keys.
+// swagger:model IdentityRotationDTO
+type IdentityRotationDTO struct {
+	// example: 0x0000000000000000000000000000000000000001
+	From string `json:"from"`
+	// example: 0x0000000000000000000000000000000000000002
+	To        string    `json:"to"`
+	RotatedAt time.Time `json:"rotated_at"`
+	// Base64 encoded signature of the old identity.
+	FromSignature string `json:"from_signature"`
+	// Base64 encoded signature of the new identity.
+	ToSignature string `json:"to_signature"`
+}
+
+// NewIdentityRotationDTO maps to API identity rotation.
+func NewIdentityRotationDTO(rotation identity.Rotation) IdentityRotationDTO {
+	return IdentityRotationDTO{
+		From:          rotation.From,
+		To:            rotation.To,
+		RotatedAt:     rotation.RotatedAt,
+		FromSignature: rotation.FromSignature,
+		ToSignature:   rotation.ToSignature,
+	}
+}
+
+// IdentityRotationsResponse lists rotations of an identity and its successors.
+// swagger:model IdentityRotationsResponse
+type IdentityRotationsResponse struct {
+	Rotations []IdentityRotationDTO `json:"rotations"`
+}
@@ -51,7 +51,7 @@ type LocationDTO struct {
 	// example: Vilnius
 	City string `json:"city"`
 
-	// IP type (data_center, residential, etc.)
+	// IP type (residential, hosting, cellular, etc.), classified by ASN when location source does not report it
 	// example: residential
 	IPType string `json:"ip_type"`
 }
@@ -18,7 +18,11 @@
 package contract
 
 import (
+	"time"
+
 	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/nat/cgnat"
+	"github.com/mysteriumnetwork/node/nat/mapping"
 )
 
 // NATTypeDTO gives information about NAT type in terms of traversal capabilities
@@ -26,4 +30,137 @@ import (
 type NATTypeDTO struct {
 	Type  nat.NATType `json:"type"`
 	Error string      `json:"error,omitempty"`
+	// example: Port Restricted Cone
+	Name string `json:"name,omitempty"`
+	// Explains whether consumers can reach the node directly.
+	Reachability string     `json:"reachability,omitempty"`
+	DetectedAt   *time.Time `json:"detected_at,omitempty"`
+}
+
+// NewNATTypeDTO maps NAT type to NATTypeDTO.
+func NewNATTypeDTO(natType nat.NATType, detectedAt *time.Time) NATTypeDTO {
+	return NATTypeDTO{
+		Type:         natType,
+		Name:         nat.HumanReadableTypes[natType],
+		Reachability: nat.ReachabilityDescriptions[natType],
+		DetectedAt:   detectedAt,
+	}
+}
+
+// PortMappingsResponse lists port mappings attempted by the node.
+// swagger:model PortMappingsResponse
+type PortMappingsResponse struct {
+	Mappings []PortMappingDTO      `json:"mappings"`
+	Stats    []PortMappingStatsDTO `json:"stats"`
+}
+
+// PortMappingDTO represents lifecycle of a single port mapping.
+// swagger:model PortMappingDTO
+type PortMappingDTO struct {
+	ID uint64 `json:"id"`
+	// example: UDP
+	Protocol string `json:"protocol"`
+	// Port mapping protocol used, e.g. upnp, pcp or nat-pmp.
+	// example: upnp
+	MapProtocol  string `json:"map_protocol,omitempty"`
+	Name         string `json:"name"`
+	InternalPort int    `json:"internal_port"`
+	ExternalPort int    `json:"external_port"`
+	// example: active
+	Status          string     `json:"status"`
+	Permanent       bool       `json:"permanent"`
+	CreatedAt       time.Time  `json:"created_at"`
+	RenewedAt       *time.Time `json:"renewed_at,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	ReleasedAt      *time.Time `json:"released_at,omitempty"`
+	Renewals        uint64     `json:"renewals"`
+	RenewalFailures uint64     `json:"renewal_failures"`
+	LastError       string     `json:"last_error,omitempty"`
+}
+
+// PortMappingStatsDTO represents port mapping metrics of a single protocol.
+// swagger:model PortMappingStatsDTO
+type PortMappingStatsDTO struct {
+	// example: upnp
+	Protocol  string `json:"protocol"`
+	Attempts  uint64 `json:"attempts"`
+	Successes uint64 `json:"successes"`
+	Renewals  uint64 `json:"renewals"`
+	Failures  uint64 `json:"failures"`
+}
+
+// NewPortMappingsResponse maps port mappings and their stats to PortMappingsResponse.
+func NewPortMappingsResponse(mappings []mapping.Mapping, stats []mapping.ProtocolStats) PortMappingsResponse {
+	res := PortMappingsResponse{
+		Mappings: make([]PortMappingDTO, 0, len(mappings)),
+		Stats:    make([]PortMappingStatsDTO, 0, len(stats)),
+	}
+	for _, m := range mappings {
+		res.Mappings = append(res.Mappings, PortMappingDTO{
+			ID:              m.ID,
+			Protocol:        m.Protocol,
+			MapProtocol:     m.MapProtocol,
+			Name:            m.Name,
+			InternalPort:    m.InternalPort,
+			ExternalPort:    m.ExternalPort,
+			Status:          m.Status,
+			Permanent:       m.Permanent,
+			CreatedAt:       m.CreatedAt,
+			RenewedAt:       optionalTime(m.RenewedAt),
+			ExpiresAt:       optionalTime(m.ExpiresAt),
+			ReleasedAt:      optionalTime(m.ReleasedAt),
+			Renewals:        m.Renewals,
+			RenewalFailures: m.RenewalFailures,
+			LastError:       m.LastError,
+		})
+	}
+	for _, s := range stats {
+		res.Stats = append(res.Stats, PortMappingStatsDTO{
+			Protocol:  s.Protocol,
+			Attempts:  s.Attempts,
+			Successes: s.Successes,
+			Renewals:  s.Renewals,
+			Failures:  s.Failures,
+		})
+	}
+	return res
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// CGNATDetectionDTO tells whether provider is behind carrier-grade NAT and how to make it reachable directly.
+// swagger:model CGNATDetectionDTO
+type CGNATDetectionDTO struct {
+	// Addresses prove that provider shares public IP with other subscribers, consumers connect via relay.
+	BehindCGNAT bool `json:"behind_cgnat"`
+	// Inbound connections are unlikely to work, but addresses do not prove carrier-grade NAT.
+	Suspected  bool   `json:"suspected"`
+	PublicIP   string `json:"public_ip,omitempty"`
+	OutboundIP string `json:"outbound_ip,omitempty"`
+	RouterIP   string `json:"router_ip,omitempty"`
+	// example: ok
+	Hairpin     string    `json:"hairpin"`
+	Reasons     []string  `json:"reasons,omitempty"`
+	Remediation []string  `json:"remediation,omitempty"`
+	DetectedAt  time.Time `json:"detected_at"`
+}
+
+// NewCGNATDetectionDTO maps CGNAT detection result to CGNATDetectionDTO.
+func NewCGNATDetectionDTO(d cgnat.Detection) *CGNATDetectionDTO {
+	return &CGNATDetectionDTO{
+		BehindCGNAT: d.BehindCGNAT,
+		Suspected:   d.Suspected,
+		PublicIP:    d.PublicIP,
+		OutboundIP:  d.OutboundIP,
+		RouterIP:    d.RouterIP,
+		Hairpin:     d.Hairpin,
+		Reasons:     d.Reasons,
+		Remediation: d.Remediation,
+		DetectedAt:  d.DetectedAt,
+	}
 }
@@ -29,6 +29,8 @@ import (
 // swagger:model NodeStatusResponse
 type NodeStatusResponse struct {
 	Status node.MonitoringStatus `json:"status"`
+	// Carrier-grade NAT detection result, missing until detection finishes.
+	CGNAT *CGNATDetectionDTO `json:"cgnat,omitempty"`
 }
 
 // MonitoringAgentResponse reflects amount of connectivity statuses for each service_type.
@@ -150,3 +152,22 @@ type EarningsPerServiceResponse struct {
 	TotalEarningsVPN      Tokens `json:"total_data_transfer_tokens"`
 	TotalEarningsScraping Tokens `json:"total_scraping_tokens"`
 }
+
+// NodeSummaryDTO combines node state UIs need on startup into a single document.
+// swagger:model NodeSummaryDTO
+type NodeSummaryDTO struct {
+	// example: 25h53m33.540493171s
+	Uptime string `json:"uptime"`
+	// example: 0.0.6
+	Version   string       `json:"version"`
+	BuildInfo BuildInfoDTO `json:"build_info"`
+
+	Services   []ServiceInfoDTO   `json:"services"`
+	Connection ConnectionDTO      `json:"connection"`
+	Monitoring NodeStatusResponse `json:"monitoring"`
+	// Last detected NAT type, missing until detection finishes.
+	NAT        *NATTypeDTO   `json:"nat,omitempty"`
+	Identities []IdentityDTO `json:"identities"`
+	// Resource usage of the node and its services, missing until first collected.
+	Resources *ResourceUsageDTO `json:"resources,omitempty"`
+}
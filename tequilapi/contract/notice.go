@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/session/notice"
+)
+
+// SessionNoticeRequest request used to push notice to connected consumers.
+// swagger:model SessionNoticeRequest
+type SessionNoticeRequest struct {
+	// session to notify, all active sessions are notified if empty
+	// required: false
+	// example: 4cfb0324-daf6-4ad8-448b-e61fe0a1f918
+	SessionID string `json:"session_id"`
+
+	// human-readable notice message
+	// required: true
+	// example: Maintenance in 10 minutes
+	Message string `json:"message"`
+}
+
+// Validate validates fields in request.
+func (r SessionNoticeRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if err := notice.Validate(r.Message); err != nil {
+		v.Invalid("message", err.Error())
+	}
+	return v.Err()
+}
+
+// SessionNoticeResponse holds a number of consumers notified.
+// swagger:model SessionNoticeResponse
+type SessionNoticeResponse struct {
+	// example: 3
+	Sent int `json:"sent"`
+}
+
+// NoticeDTO represents notice received from provider.
+// swagger:model NoticeDTO
+type NoticeDTO struct {
+	// example: 0x0000000000000000000000000000000000000001
+	ProviderID string `json:"provider_id"`
+
+	// example: 4cfb0324-daf6-4ad8-448b-e61fe0a1f918
+	SessionID string `json:"session_id"`
+
+	// example: Maintenance in 10 minutes
+	Message string `json:"message"`
+
+	// example: 2019-06-06T11:04:43.910035Z
+	CreatedAt string `json:"created_at"`
+}
+
+// NewNoticeDTO maps to API notice.
+func NewNoticeDTO(n notice.Notice) NoticeDTO {
+	return NoticeDTO{
+		ProviderID: n.PeerID.Address,
+		SessionID:  n.SessionID,
+		Message:    n.Message,
+		CreatedAt:  n.CreatedAtUTC.Format(time.RFC3339),
+	}
+}
+
+// ListNoticesResponse holds notices received from providers.
+// swagger:model ListNoticesResponse
+type ListNoticesResponse struct {
+	Items []NoticeDTO `json:"items"`
+}
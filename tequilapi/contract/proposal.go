@@ -20,6 +20,8 @@ package contract
 import (
 	"fmt"
 
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/money"
@@ -33,12 +35,17 @@ const AutoNATType = "auto"
 // NewProposalDTO maps to API service proposal.
 func NewProposalDTO(p proposal.PricedServiceProposal) ProposalDTO {
 	return ProposalDTO{
-		Format:         p.Format,
-		Compatibility:  p.Compatibility,
-		ProviderID:     p.ProviderID,
-		ServiceType:    p.ServiceType,
-		Location:       NewServiceLocationsDTO(p.Location),
-		AccessPolicies: p.AccessPolicies,
+		Format:          p.Format,
+		Compatibility:   p.Compatibility,
+		ProviderID:      p.ProviderID,
+		ServiceType:     p.ServiceType,
+		Location:        NewServiceLocationsDTO(p.Location),
+		AccessPolicies:  p.AccessPolicies,
+		AddressFamilies: p.AddressFamilies,
+		BehindCGNAT:     p.BehindCGNAT,
+		Mobile:          p.Mobile,
+		Capacity:        p.Capacity,
+		Egress:          p.Egress,
 		Quality: Quality{
 			Quality:   p.Quality.Quality,
 			Latency:   p.Quality.Latency,
@@ -105,6 +112,24 @@ type ProposalDTO struct {
 
 	// Quality of the service.
 	Quality Quality `json:"quality"`
+
+	// IP address families provider is reachable over
+	// example: ["ipv4","ipv6"]
+	AddressFamilies []string `json:"address_families,omitempty"`
+
+	// Provider is behind carrier-grade NAT and is reachable via relay only
+	// example: false
+	BehindCGNAT bool `json:"behind_cgnat,omitempty"`
+
+	// Provider runs on a mobile device
+	// example: false
+	Mobile bool `json:"mobile,omitempty"`
+
+	// Capacity measured by the provider
+	Capacity *market.Capacity `json:"capacity,omitempty"`
+
+	// Destinations blocked for consumer traffic by the provider
+	Egress *market.EgressPolicy `json:"egress,omitempty"`
 }
 
 // Price represents the service price.
@@ -169,3 +194,66 @@ type Quality struct {
 	Bandwidth float64 `json:"bandwidth"`
 	Uptime    float64 `json:"uptime"`
 }
+
+// ProposalsPingRequest holds providers which should be pinged before connecting.
+// swagger:model ProposalsPingRequest
+type ProposalsPingRequest struct {
+	// type of service provider offers
+	// example: wireguard
+	ServiceType string `json:"service_type"`
+
+	// providers to ping
+	// example: ["0x0000000000000000000000000000000000000001"]
+	ProviderIDs []string `json:"provider_ids"`
+}
+
+// Validate validates fields in request.
+func (r ProposalsPingRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if len(r.ServiceType) == 0 {
+		v.Required("service_type")
+	}
+	if len(r.ProviderIDs) == 0 {
+		v.Required("provider_ids")
+	}
+	if len(r.ProviderIDs) > MaxProposalsPingProviders {
+		v.Invalid("provider_ids", fmt.Sprintf("at most %d providers can be pinged at once", MaxProposalsPingProviders))
+	}
+	return v.Err()
+}
+
+// MaxProposalsPingProviders is a maximum number of providers which can be pinged with a single request.
+const MaxProposalsPingProviders = 50
+
+// ProposalsPingResponse holds measured round trip times to providers.
+// swagger:model ProposalsPingResponse
+type ProposalsPingResponse struct {
+	Results []ProposalPingResult `json:"results"`
+}
+
+// ProposalPingResult holds round trip time measured to a single provider.
+// swagger:model ProposalPingResult
+type ProposalPingResult struct {
+	// example: 0x0000000000000000000000000000000000000001
+	ProviderID string `json:"provider_id"`
+
+	// example: wireguard
+	ServiceType string `json:"service_type"`
+
+	// Whether provider replied to ping
+	Reachable bool `json:"reachable"`
+
+	// Measured round trip time in milliseconds
+	// example: 42
+	RTTMs int64 `json:"rtt_ms"`
+}
+
+// NewProposalPingResult maps to API proposal ping result.
+func NewProposalPingResult(p proposal.PricedServiceProposal) ProposalPingResult {
+	return ProposalPingResult{
+		ProviderID:  p.ProviderID,
+		ServiceType: p.ServiceType,
+		Reachable:   p.MeasuredRTT > 0,
+		RTTMs:       p.MeasuredRTT.Milliseconds(),
+	}
+}
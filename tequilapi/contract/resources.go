@@ -0,0 +1,113 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/monitoring/resources"
+)
+
+// ResourceUsageDTO holds resource usage of the node and its running services.
+// swagger:model ResourceUsageDTO
+type ResourceUsageDTO struct {
+	CollectedAt time.Time                 `json:"collected_at"`
+	Node        ResourceUsageValuesDTO    `json:"node"`
+	Services    []ServiceResourceUsageDTO `json:"services"`
+	// Reason why process usage is missing
+	Error string `json:"error,omitempty"`
+}
+
+// ServiceResourceUsageDTO holds resource usage of a single running service.
+// swagger:model ServiceResourceUsageDTO
+type ServiceResourceUsageDTO struct {
+	// example: 6ba7b810-9dad-11d1-80b4-00c04fd430c8
+	ID string `json:"id"`
+	// example: wireguard
+	Type string `json:"type"`
+	ResourceUsageValuesDTO
+}
+
+// ResourceUsageValuesDTO holds resource usage values.
+// swagger:model ResourceUsageValuesDTO
+type ResourceUsageValuesDTO struct {
+	// CPU usage in percent of a single core
+	// example: 12.5
+	CPUPercent float64 `json:"cpu_percent"`
+	// Resident memory in bytes
+	MemoryBytes uint64 `json:"memory_bytes"`
+	// Open file descriptors
+	FileDescriptors int `json:"file_descriptors"`
+	Goroutines      int `json:"goroutines"`
+	// Number of processes usage is summed over
+	Processes int `json:"processes"`
+}
+
+// ResourceThresholdDTO notifies about resource usage crossing a threshold.
+// swagger:model ResourceThresholdDTO
+type ResourceThresholdDTO struct {
+	// Service is missing for usage of the whole node
+	ServiceID   string `json:"service_id,omitempty"`
+	ServiceType string `json:"service_type,omitempty"`
+	// example: memory
+	Resource string  `json:"resource"`
+	Value    float64 `json:"value"`
+	Limit    float64 `json:"limit"`
+	// False when usage got back below the threshold
+	Breached bool `json:"breached"`
+}
+
+// NewResourceThresholdDTO maps resource threshold event to DTO.
+func NewResourceThresholdDTO(e resources.AppEventThreshold) ResourceThresholdDTO {
+	return ResourceThresholdDTO{
+		ServiceID:   e.ServiceID,
+		ServiceType: e.ServiceType,
+		Resource:    e.Resource,
+		Value:       e.Value,
+		Limit:       e.Limit,
+		Breached:    e.Breached,
+	}
+}
+
+// NewResourceUsageDTO maps resource usage snapshot to DTO.
+func NewResourceUsageDTO(snapshot resources.Snapshot) ResourceUsageDTO {
+	dto := ResourceUsageDTO{
+		CollectedAt: snapshot.At,
+		Node:        newResourceUsageValuesDTO(snapshot.Node),
+		Services:    []ServiceResourceUsageDTO{},
+		Error:       snapshot.Error,
+	}
+	for _, service := range snapshot.Services {
+		dto.Services = append(dto.Services, ServiceResourceUsageDTO{
+			ID:                     service.ID,
+			Type:                   service.Type,
+			ResourceUsageValuesDTO: newResourceUsageValuesDTO(service.Usage),
+		})
+	}
+	return dto
+}
+
+func newResourceUsageValuesDTO(usage resources.Usage) ResourceUsageValuesDTO {
+	return ResourceUsageValuesDTO{
+		CPUPercent:      usage.CPUPercent,
+		MemoryBytes:     usage.MemoryBytes,
+		FileDescriptors: usage.FileDescriptors,
+		Goroutines:      usage.Goroutines,
+		Processes:       usage.Processes,
+	}
+}
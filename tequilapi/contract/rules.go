@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/rules"
+)
+
+// RuleConditionDTO compares event payload field with a value.
+// swagger:model RuleConditionDTO
+type RuleConditionDTO struct {
+	// Dot separated path to payload field
+	// example: Current.Total.UnsettledBalance
+	Field string `json:"field"`
+	// One of eq, ne, gt, gte, lt, lte, contains
+	// example: gte
+	Op string `json:"op"`
+	// example: 5000000000000000000
+	Value string `json:"value"`
+}
+
+// RuleActionDTO describes action taken once the rule matches.
+// swagger:model RuleActionDTO
+type RuleActionDTO struct {
+	// One of reconnect, settle, pause_service, webhook
+	// example: webhook
+	Type   string            `json:"type"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// RuleDTO represents automation rule.
+// swagger:model RuleDTO
+type RuleDTO struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	// One of earnings_changed, connection_lost, nat_failed
+	// example: nat_failed
+	Trigger    string             `json:"trigger"`
+	Conditions []RuleConditionDTO `json:"conditions"`
+	Action     RuleActionDTO      `json:"action"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// RuleCreateRequest request used to create automation rule.
+// swagger:model RuleCreateRequest
+type RuleCreateRequest struct {
+	Name       string             `json:"name"`
+	Enabled    *bool              `json:"enabled,omitempty"`
+	Trigger    string             `json:"trigger"`
+	Conditions []RuleConditionDTO `json:"conditions"`
+	Action     RuleActionDTO      `json:"action"`
+}
+
+// Validate validates fields in request.
+func (r RuleCreateRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if r.Name == "" {
+		v.Required("name")
+	}
+	if r.Trigger == "" {
+		v.Required("trigger")
+	}
+	if r.Action.Type == "" {
+		v.Required("action.type")
+	}
+	return v.Err()
+}
+
+// ToRule maps request to rule, rules are enabled unless stated otherwise.
+func (r RuleCreateRequest) ToRule() rules.Rule {
+	rule := rules.Rule{
+		Name:    r.Name,
+		Enabled: r.Enabled == nil || *r.Enabled,
+		Trigger: r.Trigger,
+		Action:  rules.Action{Type: r.Action.Type, Params: r.Action.Params},
+	}
+	for _, c := range r.Conditions {
+		rule.Conditions = append(rule.Conditions, rules.Condition{Field: c.Field, Op: c.Op, Value: c.Value})
+	}
+	return rule
+}
+
+// NewRuleDTO maps rule to RuleDTO.
+func NewRuleDTO(rule rules.Rule) RuleDTO {
+	dto := RuleDTO{
+		ID:         rule.ID,
+		Name:       rule.Name,
+		Enabled:    rule.Enabled,
+		Trigger:    rule.Trigger,
+		Conditions: []RuleConditionDTO{},
+		Action:     RuleActionDTO{Type: rule.Action.Type, Params: rule.Action.Params},
+		CreatedAt:  rule.CreatedAt,
+	}
+	for _, c := range rule.Conditions {
+		dto.Conditions = append(dto.Conditions, RuleConditionDTO{Field: c.Field, Op: c.Op, Value: c.Value})
+	}
+	return dto
+}
+
+// RuleListResponse lists configured rules together with supported triggers and actions.
+// swagger:model RuleListResponse
+type RuleListResponse struct {
+	Rules    []RuleDTO `json:"rules"`
+	Triggers []string  `json:"triggers"`
+	Actions  []string  `json:"actions"`
+}
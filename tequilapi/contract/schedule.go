@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/schedule"
+)
+
+// ScheduleStatusDTO describes provider mode driven by an external signal feed.
+// swagger:model ScheduleStatusDTO
+type ScheduleStatusDTO struct {
+	// Whether services follow a signal feed
+	Enabled bool `json:"enabled"`
+	// Whether services are paused by the schedule
+	Paused bool `json:"paused"`
+	// Last value read from the feed
+	// example: 0.12
+	Value *float64 `json:"value,omitempty"`
+	// Action of the rule matching the last value
+	// example: serve
+	Action string `json:"action,omitempty"`
+	// Last feed or service control error
+	Error string `json:"error,omitempty"`
+	// Time of the last feed poll
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	// Rules mapping signal ranges to actions, in "<min>..<max>=<action>" format
+	// example: ["..0.15=serve","0.15..=pause"]
+	Rules []string `json:"rules"`
+}
+
+// NewScheduleStatusDTO maps scheduler status to DTO.
+func NewScheduleStatusDTO(status schedule.Status) ScheduleStatusDTO {
+	dto := ScheduleStatusDTO{
+		Enabled: status.Enabled,
+		Paused:  status.Paused,
+		Value:   status.Value,
+		Action:  status.Action,
+		Error:   status.Error,
+		Rules:   []string{},
+	}
+	if !status.CheckedAt.IsZero() {
+		checkedAt := status.CheckedAt
+		dto.CheckedAt = &checkedAt
+	}
+	for _, rule := range status.Rules {
+		dto.Rules = append(dto.Rules, rule.String())
+	}
+	return dto
+}
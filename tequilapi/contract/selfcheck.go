@@ -0,0 +1,93 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/monitoring/selfcheck"
+)
+
+// SelfCheckDTO holds self-check state of running services and uptime history.
+// swagger:model SelfCheckDTO
+type SelfCheckDTO struct {
+	Since  time.Time `json:"since"`
+	Checks int       `json:"checks"`
+	Passed int       `json:"passed"`
+	// Share of passed checks, 0..1
+	// example: 0.98
+	Uptime   float64               `json:"uptime"`
+	Services []SelfCheckServiceDTO `json:"services"`
+	History  []SelfCheckRecordDTO  `json:"history"`
+}
+
+// SelfCheckServiceDTO holds the latest self-check state of a running service.
+// swagger:model SelfCheckServiceDTO
+type SelfCheckServiceDTO struct {
+	ProviderID          string    `json:"provider_id"`
+	ServiceType         string    `json:"service_type"`
+	LastCheck           time.Time `json:"last_check"`
+	OK                  bool      `json:"ok"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+}
+
+// SelfCheckRecordDTO holds an outcome of a single self-check.
+// swagger:model SelfCheckRecordDTO
+type SelfCheckRecordDTO struct {
+	Time        time.Time `json:"time"`
+	ProviderID  string    `json:"provider_id"`
+	ServiceType string    `json:"service_type"`
+	OK          bool      `json:"ok"`
+	// Last stage run, the failed one if the check did not pass
+	// example: dial
+	Stage string `json:"stage"`
+	RTTMs int64  `json:"rtt_ms,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// NewSelfCheckDTO maps service self-check states and uptime history to DTO.
+func NewSelfCheckDTO(statuses []selfcheck.Status, uptime selfcheck.Uptime) SelfCheckDTO {
+	dto := SelfCheckDTO{
+		Since:    uptime.Since,
+		Checks:   uptime.Checks,
+		Passed:   uptime.Passed,
+		Uptime:   uptime.Ratio(),
+		Services: []SelfCheckServiceDTO{},
+		History:  []SelfCheckRecordDTO{},
+	}
+	for _, status := range statuses {
+		dto.Services = append(dto.Services, SelfCheckServiceDTO{
+			ProviderID:          status.ProviderID,
+			ServiceType:         status.ServiceType,
+			LastCheck:           status.LastCheck,
+			OK:                  status.LastResult.OK,
+			ConsecutiveFailures: status.ConsecutiveFailures,
+		})
+	}
+	for _, record := range uptime.Records {
+		dto.History = append(dto.History, SelfCheckRecordDTO{
+			Time:        record.Time,
+			ProviderID:  record.ProviderID,
+			ServiceType: record.ServiceType,
+			OK:          record.OK,
+			Stage:       record.Stage,
+			RTTMs:       record.RTT.Milliseconds(),
+			Error:       record.Error,
+		})
+	}
+	return dto
+}
@@ -20,7 +20,7 @@ package contract
 // ServiceStartRequest request used to start a service.
 // swagger:model ServiceStartRequestDTO
 type ServiceStartRequest struct {
-	// provider identity
+	// provider identity, it has to be unlocked. Services of the same type may run under different identities
 	// required: true
 	// example: 0x0000000000000000000000000000000000000002
 	ProviderID string `json:"provider_id"`
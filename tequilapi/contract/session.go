@@ -18,8 +18,10 @@
 package contract
 
 import (
+	"math"
 	"math/big"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-openapi/strfmt"
@@ -90,6 +92,7 @@ func (q *SessionQuery) Bind(request *http.Request) *apierror.APIError {
 		}
 	}
 	if qStr := qs.Get("direction"); qStr != "" {
+		qStr = normalizeDirection(qStr)
 		q.Direction = &qStr
 	}
 	if qStr := qs.Get("consumer_id"); qStr != "" {
@@ -141,6 +144,15 @@ func (q *SessionQuery) ToFilter() *session.Filter {
 	return filter
 }
 
+func normalizeDirection(direction string) string {
+	for _, known := range []string{session.DirectionProvided, session.DirectionConsumed} {
+		if strings.EqualFold(direction, known) {
+			return known
+		}
+	}
+	return direction
+}
+
 // NewSessionListQuery creates session list with default values.
 func NewSessionListQuery() SessionListQuery {
 	return SessionListQuery{
@@ -153,6 +165,11 @@ func NewSessionListQuery() SessionListQuery {
 type SessionListQuery struct {
 	PaginationQuery
 	SessionQuery
+
+	// Aggregate sessions into groups. Possible values are "day", "provider".
+	// Totals of all filtered sessions are returned along with the groups.
+	// in: query
+	GroupBy *string `json:"group_by"`
 }
 
 // Bind creates and validates query from API request.
@@ -168,6 +185,13 @@ func (q *SessionListQuery) Bind(request *http.Request) *apierror.APIError {
 			v.Fail(field, fieldErr.Code, fieldErr.Message)
 		}
 	}
+	if qStr := request.URL.Query().Get("group_by"); qStr != "" {
+		if qStr != session.GroupByDay && qStr != session.GroupByProvider {
+			v.Invalid("group_by", "Possible values are 'day', 'provider'")
+		} else {
+			q.GroupBy = &qStr
+		}
+	}
 	return v.Err()
 }
 
@@ -184,11 +208,80 @@ func NewSessionListResponse(sessions []session.History, paginator *utils.Paginat
 	}
 }
 
+// WithAggregates adds totals and groups of sessions to the response.
+func (r SessionListResponse) WithAggregates(total session.Aggregate, groups []session.Aggregate) SessionListResponse {
+	totalDTO := NewSessionAggregateDTO(total)
+	r.Totals = &totalDTO
+	r.Groups = make([]SessionAggregateDTO, len(groups))
+	for i, group := range groups {
+		r.Groups[i] = NewSessionAggregateDTO(group)
+	}
+	return r
+}
+
 // SessionListResponse defines session list representable as json.
 // swagger:model SessionListResponse
 type SessionListResponse struct {
 	Items []SessionDTO `json:"items"`
 	PageableDTO
+
+	// Totals of all filtered sessions, returned when sessions are grouped.
+	Totals *SessionAggregateDTO `json:"totals,omitempty"`
+
+	// Groups of filtered sessions, returned when sessions are grouped.
+	Groups []SessionAggregateDTO `json:"groups,omitempty"`
+}
+
+const bytesInGiB = 1 << 30
+
+// NewSessionAggregateDTO maps to API session aggregate.
+func NewSessionAggregateDTO(aggregate session.Aggregate) SessionAggregateDTO {
+	transferred := aggregate.SumDataSent + aggregate.SumDataReceived
+	return SessionAggregateDTO{
+		Key:              aggregate.Key,
+		Count:            aggregate.Count,
+		CountConsumers:   len(aggregate.ConsumerCounts),
+		SumBytesReceived: aggregate.SumDataReceived,
+		SumBytesSent:     aggregate.SumDataSent,
+		SumGiB:           math.Round(float64(transferred)/bytesInGiB*1000) / 1000,
+		SumDuration:      uint64(aggregate.SumDuration.Seconds()),
+		Earnings:         NewTokens(aggregate.SumEarnings),
+		Spendings:        NewTokens(aggregate.SumSpendings),
+	}
+}
+
+// SessionAggregateDTO represents statistics of a group of sessions.
+// swagger:model SessionAggregateDTO
+type SessionAggregateDTO struct {
+	// Day formatted as 2006-01-02 or provider identity, empty for totals.
+	// example: 2020-07-01
+	Key string `json:"key,omitempty"`
+
+	// example: 10
+	Count int `json:"count"`
+
+	// example: 3
+	CountConsumers int `json:"count_consumers"`
+
+	// example: 1024
+	SumBytesReceived uint64 `json:"sum_bytes_received"`
+
+	// example: 1024
+	SumBytesSent uint64 `json:"sum_bytes_sent"`
+
+	// Sent and received data in GiB
+	// example: 1.25
+	SumGiB float64 `json:"sum_gib"`
+
+	// duration in seconds
+	// example: 3600
+	SumDuration uint64 `json:"sum_duration"`
+
+	// Tokens earned in provided sessions
+	Earnings Tokens `json:"earnings"`
+
+	// Tokens spent in consumed sessions
+	Spendings Tokens `json:"spendings"`
 }
 
 // NewSessionStatsAggregatedResponse maps to API aggregated stats.
@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/session/accounting"
+)
+
+// SessionAccountingReportDTO compares session traffic reported by tunnels with traffic counted by the kernel.
+// swagger:model SessionAccountingReportDTO
+type SessionAccountingReportDTO struct {
+	GeneratedAt time.Time                   `json:"generated_at"`
+	Sessions    []SessionAccountingEntryDTO `json:"sessions"`
+	// Reason why kernel counters are missing
+	KernelError string `json:"kernel_error,omitempty"`
+}
+
+// SessionAccountingEntryDTO holds tunnel and kernel traffic counters of a single provider session.
+// swagger:model SessionAccountingEntryDTO
+type SessionAccountingEntryDTO struct {
+	// example: 4cfb0324-daf6-4ad8-448b-e61fe0a1f918
+	SessionID string `json:"session_id"`
+	// Bytes sent to the consumer according to the tunnel
+	TunnelSent uint64 `json:"tunnel_sent"`
+	// Bytes received from the consumer according to the tunnel
+	TunnelReceived uint64 `json:"tunnel_received"`
+	// Bytes sent to the consumer according to the kernel
+	KernelSent uint64 `json:"kernel_sent"`
+	// Bytes received from the consumer according to the kernel
+	KernelReceived uint64 `json:"kernel_received"`
+	// Relative difference of tunnel sent bytes from kernel ones
+	// example: 0.04
+	SentDrift float64 `json:"sent_drift"`
+	// Relative difference of tunnel received bytes from kernel ones
+	// example: 0.04
+	ReceivedDrift float64 `json:"received_drift"`
+}
+
+// NewSessionAccountingReportDTO maps accounting report to DTO.
+func NewSessionAccountingReportDTO(report accounting.Report) SessionAccountingReportDTO {
+	dto := SessionAccountingReportDTO{
+		GeneratedAt: report.GeneratedAt,
+		Sessions:    []SessionAccountingEntryDTO{},
+		KernelError: report.KernelError,
+	}
+	for _, entry := range report.Sessions {
+		dto.Sessions = append(dto.Sessions, SessionAccountingEntryDTO{
+			SessionID:      entry.SessionID,
+			TunnelSent:     entry.Tunnel.Sent,
+			TunnelReceived: entry.Tunnel.Received,
+			KernelSent:     entry.Kernel.Sent,
+			KernelReceived: entry.Kernel.Received,
+			SentDrift:      entry.SentDrift,
+			ReceivedDrift:  entry.ReceivedDrift,
+		})
+	}
+	return dto
+}
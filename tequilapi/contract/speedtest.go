@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/connection/speedtest"
+)
+
+// SpeedTestProgressDTO reports the stage of a running speed test.
+// swagger:model SpeedTestProgressDTO
+type SpeedTestProgressDTO struct {
+	// example: download
+	Stage string `json:"stage"`
+	// Bytes transferred in the stage so far
+	Bytes uint64 `json:"bytes"`
+	// Time elapsed in the stage, in milliseconds
+	Elapsed int64 `json:"elapsed"`
+	// example: 42.5
+	Mbps float64 `json:"mbps"`
+}
+
+// NewSpeedTestProgressDTO maps speed test progress to DTO.
+func NewSpeedTestProgressDTO(p speedtest.Progress) SpeedTestProgressDTO {
+	return SpeedTestProgressDTO{
+		Stage:   p.Stage,
+		Bytes:   p.Bytes,
+		Elapsed: p.Elapsed.Milliseconds(),
+		Mbps:    p.Mbps,
+	}
+}
+
+// SpeedTestResultDTO is the outcome of a speed test.
+// swagger:model SpeedTestResultDTO
+type SpeedTestResultDTO struct {
+	// example: 0x0000000000000000000000000000000000000001
+	ProviderID string `json:"provider_id"`
+	// example: wireguard
+	ServiceType string    `json:"service_type"`
+	StartedAt   time.Time `json:"started_at"`
+	// Time to the first byte of the download, in milliseconds
+	Latency int64 `json:"latency"`
+	// example: 85.3
+	DownloadMbps float64 `json:"download_mbps"`
+	// example: 21.7
+	UploadMbps float64 `json:"upload_mbps"`
+}
+
+// NewSpeedTestResultDTO maps speed test result to DTO.
+func NewSpeedTestResultDTO(r speedtest.Result) SpeedTestResultDTO {
+	return SpeedTestResultDTO{
+		ProviderID:   r.ProviderID,
+		ServiceType:  r.ServiceType,
+		StartedAt:    r.StartedAt,
+		Latency:      r.Latency.Milliseconds(),
+		DownloadMbps: r.DownloadMbps,
+		UploadMbps:   r.UploadMbps,
+	}
+}
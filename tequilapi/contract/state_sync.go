@@ -0,0 +1,103 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/mysteriumnetwork/node/core/statesync"
+)
+
+// StateSyncStatusDTO describes the state synchronized between devices of the consumer.
+// swagger:model StateSyncStatusDTO
+type StateSyncStatusDTO struct {
+	// ID of this device
+	// example: 9f86d081884c7d65
+	Device string `json:"device"`
+	// Time of the last successful sync, empty if state was never synchronized
+	LastSync *time.Time `json:"last_sync,omitempty"`
+	// Error of the last sync attempt
+	LastError string                 `json:"last_error,omitempty"`
+	Spending  []DeviceSpendingDTO    `json:"spending"`
+	Total     DeviceSpendingTotalDTO `json:"total"`
+}
+
+// DeviceSpendingDTO summarizes sessions consumed on a single device.
+// swagger:model DeviceSpendingDTO
+type DeviceSpendingDTO struct {
+	Device    string    `json:"device"`
+	UpdatedAt time.Time `json:"updated_at"`
+	DeviceSpendingTotalDTO
+}
+
+// DeviceSpendingTotalDTO holds consumed session totals.
+// swagger:model DeviceSpendingTotalDTO
+type DeviceSpendingTotalDTO struct {
+	Sessions     int    `json:"sessions"`
+	DataSent     uint64 `json:"data_sent"`
+	DataReceived uint64 `json:"data_received"`
+	// Duration in seconds
+	Duration uint64 `json:"duration"`
+	Tokens   Tokens `json:"tokens"`
+}
+
+// NewStateSyncStatusDTO maps state sync status to DTO.
+func NewStateSyncStatusDTO(status statesync.Status) StateSyncStatusDTO {
+	dto := StateSyncStatusDTO{
+		Device:    status.Device,
+		LastError: status.LastError,
+		Spending:  []DeviceSpendingDTO{},
+	}
+	if !status.LastSync.IsZero() {
+		dto.LastSync = &status.LastSync
+	}
+
+	total := statesync.DeviceSpending{Tokens: new(big.Int)}
+	for _, spending := range status.Spending {
+		dto.Spending = append(dto.Spending, DeviceSpendingDTO{
+			Device:                 spending.Device,
+			UpdatedAt:              spending.UpdatedAt,
+			DeviceSpendingTotalDTO: newDeviceSpendingTotalDTO(spending),
+		})
+		total.Sessions += spending.Sessions
+		total.DataSent += spending.DataSent
+		total.DataReceived += spending.DataReceived
+		total.Duration += spending.Duration
+		if spending.Tokens != nil {
+			total.Tokens.Add(total.Tokens, spending.Tokens)
+		}
+	}
+	sort.Slice(dto.Spending, func(i, j int) bool {
+		return dto.Spending[i].Device < dto.Spending[j].Device
+	})
+	dto.Total = newDeviceSpendingTotalDTO(total)
+
+	return dto
+}
+
+func newDeviceSpendingTotalDTO(spending statesync.DeviceSpending) DeviceSpendingTotalDTO {
+	return DeviceSpendingTotalDTO{
+		Sessions:     spending.Sessions,
+		DataSent:     spending.DataSent,
+		DataReceived: spending.DataReceived,
+		Duration:     uint64(spending.Duration.Seconds()),
+		Tokens:       NewTokens(spending.Tokens),
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"time"
+
+	"github.com/mysteriumnetwork/node/session/accounting"
+)
+
+// TrafficDTO holds traffic of all provider sessions in the current day and month.
+// swagger:model TrafficDTO
+type TrafficDTO struct {
+	Today TrafficPeriodDTO `json:"today"`
+	Month TrafficPeriodDTO `json:"month"`
+	// Monthly traffic cap in bytes, traffic is not capped if zero
+	MonthlyCap uint64 `json:"monthly_cap"`
+	// example: reject
+	CapAction  string `json:"cap_action"`
+	CapReached bool   `json:"cap_reached"`
+	// Traffic of the last days, oldest first
+	Daily []DailyTrafficDTO `json:"daily"`
+}
+
+// TrafficPeriodDTO holds traffic within a period.
+// swagger:model TrafficPeriodDTO
+type TrafficPeriodDTO struct {
+	Start    time.Time `json:"start"`
+	Sent     uint64    `json:"sent"`
+	Received uint64    `json:"received"`
+	Total    uint64    `json:"total"`
+}
+
+// DailyTrafficDTO holds traffic of a single UTC day.
+// swagger:model DailyTrafficDTO
+type DailyTrafficDTO struct {
+	// example: 2022-05-14
+	Day      string `json:"day"`
+	Sent     uint64 `json:"sent"`
+	Received uint64 `json:"received"`
+	Total    uint64 `json:"total"`
+}
+
+// NewTrafficDTO maps traffic totals and daily history to DTO.
+func NewTrafficDTO(totals accounting.TrafficTotals, daily []accounting.DailyTraffic) TrafficDTO {
+	dto := TrafficDTO{
+		Today: TrafficPeriodDTO{
+			Start:    totals.Today.Date,
+			Sent:     totals.Today.Sent,
+			Received: totals.Today.Received,
+			Total:    totals.Today.Total(),
+		},
+		Month: TrafficPeriodDTO{
+			Start:    totals.MonthStart,
+			Sent:     totals.Month.Sent,
+			Received: totals.Month.Received,
+			Total:    totals.Month.Total(),
+		},
+		MonthlyCap: totals.Cap.MonthlyBytes,
+		CapAction:  totals.Cap.Action,
+		CapReached: totals.CapReached,
+		Daily:      []DailyTrafficDTO{},
+	}
+	for _, day := range daily {
+		dto.Daily = append(dto.Daily, DailyTrafficDTO{
+			Day:      day.Day,
+			Sent:     day.Sent,
+			Received: day.Received,
+			Total:    day.Total(),
+		})
+	}
+	return dto
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/updater"
+)
+
+// NodeUpdateRequest request to check for or install a node update.
+// swagger:model NodeUpdateRequest
+type NodeUpdateRequest struct {
+	// Release channel, the configured one if empty
+	// example: stable
+	Channel string `json:"channel"`
+
+	// Only check for a newer release without installing it
+	CheckOnly bool `json:"check_only"`
+}
+
+// Validate validates fields in request.
+func (r NodeUpdateRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+	if r.Channel != "" {
+		if _, err := updater.ParseChannel(r.Channel); err != nil {
+			v.Invalid("channel", err.Error())
+		}
+	}
+	return v.Err()
+}
+
+// NodeUpdateStatusDTO holds the state of the last node update check or attempt.
+// swagger:model NodeUpdateStatusDTO
+type NodeUpdateStatusDTO struct {
+	// example: available
+	State string `json:"state"`
+	// example: stable
+	Channel string `json:"channel"`
+	// example: 1.10.0
+	CurrentVersion string `json:"current_version"`
+	// example: 1.11.0
+	LatestVersion string `json:"latest_version,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// NewNodeUpdateStatusDTO maps updater status to DTO.
+func NewNodeUpdateStatusDTO(status updater.Status) NodeUpdateStatusDTO {
+	return NodeUpdateStatusDTO{
+		State:          string(status.State),
+		Channel:        string(status.Channel),
+		CurrentVersion: status.CurrentVersion,
+		LatestVersion:  status.LatestVersion,
+		Error:          status.Error,
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package contract
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/eventbus/webhook"
+)
+
+// WebhookRequest is received to register a webhook.
+// swagger:model WebhookRequest
+type WebhookRequest struct {
+	// HTTP or HTTPS address events are posted to.
+	// example: https://hooks.example.com/myst
+	URL string `json:"url"`
+
+	// Events delivered to the webhook: session_started, session_ended, settlement_done, balance_low, service_stopped, self_check_failed.
+	// example: ["session_started","service_stopped"]
+	Events []string `json:"events"`
+
+	// Secret signing delivered payloads. Generated if empty.
+	Secret string `json:"secret,omitempty"`
+}
+
+// Validate validates fields in request.
+func (r WebhookRequest) Validate() *apierror.APIError {
+	v := apierror.NewValidator()
+
+	if r.URL == "" {
+		v.Required("url")
+	} else if u, err := url.Parse(r.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.Invalid("url", "URL must be an absolute HTTP or HTTPS address")
+	}
+
+	if len(r.Events) == 0 {
+		v.Required("events")
+	}
+	for i, event := range r.Events {
+		if !knownWebhookEvent(event) {
+			v.Invalid(fmt.Sprintf("events[%d]", i), "Unknown event, expected one of: "+strings.Join(webhook.Events, ", "))
+		}
+	}
+
+	return v.Err()
+}
+
+// Webhook maps request to a webhook.
+func (r WebhookRequest) Webhook() webhook.Webhook {
+	return webhook.Webhook{
+		URL:    r.URL,
+		Events: r.Events,
+		Secret: r.Secret,
+	}
+}
+
+func knownWebhookEvent(event string) bool {
+	for _, e := range webhook.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDTO represents a registered webhook.
+// swagger:model WebhookDTO
+type WebhookDTO struct {
+	// example: 9f86d081884c7d65
+	ID string `json:"id"`
+
+	// example: https://hooks.example.com/myst
+	URL string `json:"url"`
+
+	// example: ["session_started","service_stopped"]
+	Events []string `json:"events"`
+
+	// Secret signing delivered payloads. Returned only when the webhook is registered.
+	Secret string `json:"secret,omitempty"`
+}
+
+// NewWebhookDTO maps webhook to the public API, omitting its secret.
+func NewWebhookDTO(w webhook.Webhook) WebhookDTO {
+	return WebhookDTO{
+		ID:     w.ID,
+		URL:    w.URL,
+		Events: w.Events,
+	}
+}
+
+// WebhookListDTO holds registered webhooks.
+// swagger:model WebhookListDTO
+type WebhookListDTO struct {
+	Webhooks []WebhookDTO `json:"webhooks"`
+}
+
+// NewWebhookListDTO maps webhooks to the public API.
+func NewWebhookListDTO(webhooks []webhook.Webhook) WebhookListDTO {
+	dto := WebhookListDTO{Webhooks: make([]WebhookDTO, 0, len(webhooks))}
+	for _, w := range webhooks {
+		dto.Webhooks = append(dto.Webhooks, NewWebhookDTO(w))
+	}
+	return dto
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/auth"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type auditLister interface {
+	List(filter auth.AuditFilter) ([]auth.AuditEntry, error)
+}
+
+type auditAPI struct {
+	audit auditLister
+}
+
+// List returns recorded management requests.
+// swagger:operation GET /audit Audit auditList
+// ---
+// summary: Returns audit log
+// description: Returns recorded state changing requests and requests rejected by authorization or rate limits, newest first
+// parameters:
+//   - in: query
+//     name: since
+//     description: List entries recorded at or after this time, formatted in RFC3339
+//     type: string
+//   - in: query
+//     name: until
+//     description: List entries recorded at or before this time, formatted in RFC3339
+//     type: string
+//   - in: query
+//     name: actor
+//     description: List entries of this actor only
+//     type: string
+//   - in: query
+//     name: limit
+//     description: Maximum number of entries to return, 100 by default
+//     type: integer
+//
+// responses:
+//   200:
+//     description: Audit log entries
+//     schema:
+//       "$ref": "#/definitions/AuditEntryListDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *auditAPI) List(c *gin.Context) {
+	query := contract.NewAuditQuery()
+	if err := query.Bind(c.Request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	entries, err := api.audit.List(query.ToFilter())
+	if err != nil {
+		c.Error(apierror.Internal("Could not list audit log: "+err.Error(), contract.ErrCodeAuditQuery))
+		return
+	}
+	utils.WriteAsJSON(contract.NewAuditEntryListDTO(entries), c.Writer)
+}
+
+// AddRoutesForAudit registers audit log routes.
+func AddRoutesForAudit(audit auditLister) func(*gin.Engine) error {
+	api := &auditAPI{audit: audit}
+	return func(e *gin.Engine) error {
+		e.GET("/audit", api.List)
+		return nil
+	}
+}
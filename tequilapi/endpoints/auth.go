@@ -19,6 +19,7 @@ package endpoints
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
@@ -34,6 +35,7 @@ import (
 type authenticationAPI struct {
 	jwtAuthenticator jwtAuthenticator
 	authenticator    authenticator
+	tokens           tokenStore
 }
 
 type jwtAuthenticator interface {
@@ -45,6 +47,13 @@ type authenticator interface {
 	ChangePassword(username, oldPassword, newPassword string) error
 }
 
+type tokenStore interface {
+	Issue(name string, scopes []auth.Scope) (string, auth.APIToken, error)
+	Rotate(id string) (string, auth.APIToken, error)
+	Revoke(id string) error
+	List() ([]auth.APIToken, error)
+}
+
 // swagger:operation POST /auth/authenticate Authentication Authenticate
 // ---
 // summary: Authenticate
@@ -196,6 +205,172 @@ func (api *authenticationAPI) ChangePassword(c *gin.Context) {
 	}
 }
 
+// swagger:operation POST /auth/tokens Authentication issueToken
+// ---
+// summary: Issue API token
+// description: Issues a scoped API token, authenticated with UI credentials. Token secret is returned only once.
+// parameters:
+//   - in: body
+//     name: body
+//     schema:
+//       $ref: "#/definitions/APITokenRequest"
+// responses:
+//   200:
+//     description: Token issued
+//     schema:
+//       "$ref": "#/definitions/APITokenResponse"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   401:
+//     description: Authentication failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *authenticationAPI) IssueToken(c *gin.Context) {
+	var req contract.APITokenRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+	if err := api.authenticator.CheckCredentials(req.Username, req.Password); err != nil {
+		c.Error(apierror.Unauthorized())
+		return
+	}
+
+	secret, token, err := api.tokens.Issue(req.Name, req.ParsedScopes())
+	if err != nil {
+		c.Error(apierror.Internal("Failed to issue token: "+err.Error(), contract.ErrCodeAuthTokenIssue))
+		return
+	}
+
+	utils.WriteAsJSON(contract.APITokenResponse{Token: secret, APITokenDTO: contract.NewAPITokenDTO(token)}, c.Writer)
+}
+
+// swagger:operation POST /auth/tokens/{id}/rotate Authentication rotateToken
+// ---
+// summary: Rotate API token
+// description: Replaces the secret of an API token, authenticated with UI credentials. Previous secret stops working immediately.
+// parameters:
+//   - name: id
+//     in: path
+//     description: Token ID
+//     type: string
+//     required: true
+//   - in: body
+//     name: body
+//     schema:
+//       $ref: "#/definitions/AuthRequest"
+// responses:
+//   200:
+//     description: Token rotated
+//     schema:
+//       "$ref": "#/definitions/APITokenResponse"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   401:
+//     description: Authentication failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   404:
+//     description: Token not found
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *authenticationAPI) RotateToken(c *gin.Context) {
+	req, err := toAuthRequest(c.Request)
+	if err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := api.authenticator.CheckCredentials(req.Username, req.Password); err != nil {
+		c.Error(apierror.Unauthorized())
+		return
+	}
+
+	secret, token, err := api.tokens.Rotate(c.Param("id"))
+	if errors.Is(err, auth.ErrTokenNotFound) {
+		c.Error(apierror.NotFound("Token not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Failed to rotate token: "+err.Error(), contract.ErrCodeAuthTokenIssue))
+		return
+	}
+
+	utils.WriteAsJSON(contract.APITokenResponse{Token: secret, APITokenDTO: contract.NewAPITokenDTO(token)}, c.Writer)
+}
+
+// swagger:operation GET /auth/tokens Authentication listTokens
+// ---
+// summary: List API tokens
+// description: Lists issued API tokens without their secrets
+// responses:
+//   200:
+//     description: Issued tokens
+//     schema:
+//       "$ref": "#/definitions/APITokenListResponse"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *authenticationAPI) ListTokens(c *gin.Context) {
+	tokens, err := api.tokens.List()
+	if err != nil {
+		c.Error(apierror.Internal("Failed to list tokens: "+err.Error(), contract.ErrCodeAuthTokenList))
+		return
+	}
+
+	res := contract.APITokenListResponse{Tokens: make([]contract.APITokenDTO, 0, len(tokens))}
+	for _, token := range tokens {
+		res.Tokens = append(res.Tokens, contract.NewAPITokenDTO(token))
+	}
+	utils.WriteAsJSON(res, c.Writer)
+}
+
+// swagger:operation DELETE /auth/tokens/{id} Authentication revokeToken
+// ---
+// summary: Revoke API token
+// description: Revokes an API token
+// parameters:
+//   - name: id
+//     in: path
+//     description: Token ID
+//     type: string
+//     required: true
+// responses:
+//   204:
+//     description: Token revoked
+//   404:
+//     description: Token not found
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *authenticationAPI) RevokeToken(c *gin.Context) {
+	err := api.tokens.Revoke(c.Param("id"))
+	if errors.Is(err, auth.ErrTokenNotFound) {
+		c.Error(apierror.NotFound("Token not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Failed to revoke token: "+err.Error(), contract.ErrCodeAuthTokenList))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
 func toAuthRequest(req *http.Request) (contract.AuthRequest, error) {
 	var request contract.AuthRequest
 	err := json.NewDecoder(req.Body).Decode(&request)
@@ -214,10 +389,12 @@ func toChangePasswordRequest(req *http.Request) (*contract.ChangePasswordRequest
 func AddRoutesForAuthentication(
 	auth authenticator,
 	jwtAuth jwtAuthenticator,
+	tokens tokenStore,
 ) func(*gin.Engine) error {
 	api := &authenticationAPI{
 		authenticator:    auth,
 		jwtAuthenticator: jwtAuth,
+		tokens:           tokens,
 	}
 	return func(e *gin.Engine) error {
 		g := e.Group("/auth")
@@ -226,6 +403,10 @@ func AddRoutesForAuthentication(
 			g.POST("/authenticate", api.Authenticate)
 			g.POST("/login", api.Login)
 			g.DELETE("/logout", api.Logout)
+			g.POST("/tokens", api.IssueToken)
+			g.POST("/tokens/:id/rotate", api.RotateToken)
+			g.GET("/tokens", api.ListTokens)
+			g.DELETE("/tokens/:id", api.RevokeToken)
 		}
 		return nil
 	}
@@ -37,6 +37,9 @@ type configProvider interface {
 	SetUser(key string, value interface{})
 	RemoveUser(key string)
 	SaveUserConfig() error
+	Profiles() []config.Profile
+	Profile() string
+	UseProfile(name string) error
 }
 
 // swagger:model configPayload
@@ -146,6 +149,70 @@ func (api *configAPI) SetUserConfig(c *gin.Context) {
 	api.GetUserConfig(c)
 }
 
+// GetProfiles returns configuration profiles
+// swagger:operation GET /config/profiles Configuration getConfigProfiles
+// ---
+// summary: Returns configuration profiles
+// description: Returns built-in and custom configuration profiles and the name of the one in use
+// responses:
+//   200:
+//     description: Configuration profiles
+//     schema:
+//       "$ref": "#/definitions/ConfigProfilesDTO"
+func (api *configAPI) GetProfiles(c *gin.Context) {
+	res := contract.NewConfigProfilesDTO(api.config.Profile(), api.config.Profiles())
+	utils.WriteAsJSON(res, c.Writer)
+}
+
+// SetProfile selects configuration profile
+// swagger:operation PUT /config/profile Configuration setConfigProfile
+// ---
+// summary: Selects configuration profile
+// description: Layers values of the profile over defaults and remembers it in user configuration. Values read on node start, e.g. network endpoints, take effect after restart.
+// parameters:
+//   - in: body
+//     name: body
+//     description: profile to use
+//     schema:
+//       $ref: "#/definitions/ConfigProfileRequest"
+// responses:
+//   200:
+//     description: Configuration profiles
+//     schema:
+//       "$ref": "#/definitions/ConfigProfilesDTO"
+//   400:
+//     description: Failed to parse or unknown profile
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *configAPI) SetProfile(c *gin.Context) {
+	var req contract.ConfigProfileRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&req)
+	if err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := api.config.UseProfile(req.Name); err != nil {
+		c.Error(apierror.BadRequest(err.Error(), contract.ErrCodeConfigProfile))
+		return
+	}
+
+	if req.Name == "" {
+		api.config.RemoveUser(config.FlagProfile.Name)
+	} else {
+		api.config.SetUser(config.FlagProfile.Name, api.config.Profile())
+	}
+	err = api.config.SaveUserConfig()
+	if err != nil {
+		c.Error(apierror.Internal("Failed to save config", contract.ErrCodeConfigSave))
+		return
+	}
+	api.GetProfiles(c)
+}
+
 func isNil(val interface{}) bool {
 	if val == nil {
 		return true
@@ -168,6 +235,8 @@ func AddRoutesForConfig(
 		g.GET("/default", api.GetDefaultConfig)
 		g.GET("/user", api.GetUserConfig)
 		g.POST("/user", api.SetUserConfig)
+		g.GET("/profiles", api.GetProfiles)
+		g.PUT("/profile", api.SetProfile)
 	}
 	return nil
 }
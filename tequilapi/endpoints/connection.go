@@ -18,7 +18,9 @@
 package endpoints
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -30,12 +32,14 @@ import (
 	"github.com/mysteriumnetwork/go-rest/apierror"
 	"github.com/mysteriumnetwork/node/config"
 	"github.com/mysteriumnetwork/node/core/connection"
+	"github.com/mysteriumnetwork/node/core/discovery"
 	"github.com/mysteriumnetwork/node/core/discovery/proposal"
 	"github.com/mysteriumnetwork/node/core/quality"
 	"github.com/mysteriumnetwork/node/eventbus"
 	"github.com/mysteriumnetwork/node/identity"
 	"github.com/mysteriumnetwork/node/identity/registry"
 	"github.com/mysteriumnetwork/node/market"
+	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
 )
@@ -55,6 +59,10 @@ type identityRegistry interface {
 	GetRegistrationStatus(int64, identity.Identity) (registry.RegistrationStatus, error)
 }
 
+type latencyMeasurer interface {
+	Measure(ctx context.Context, proposals []proposal.PricedServiceProposal) []proposal.PricedServiceProposal
+}
+
 // ConnectionEndpoint struct represents /connection resource and it's subresources
 type ConnectionEndpoint struct {
 	manager       connection.MultiManager
@@ -64,10 +72,11 @@ type ConnectionEndpoint struct {
 	proposalRepository proposalRepository
 	identityRegistry   identityRegistry
 	addressProvider    addressProvider
+	latencyMeasurer    latencyMeasurer
 }
 
 // NewConnectionEndpoint creates and returns connection endpoint
-func NewConnectionEndpoint(manager connection.MultiManager, stateProvider stateProvider, proposalRepository proposalRepository, identityRegistry identityRegistry, publisher eventbus.Publisher, addressProvider addressProvider) *ConnectionEndpoint {
+func NewConnectionEndpoint(manager connection.MultiManager, stateProvider stateProvider, proposalRepository proposalRepository, identityRegistry identityRegistry, publisher eventbus.Publisher, addressProvider addressProvider, latencyMeasurer latencyMeasurer) *ConnectionEndpoint {
 	return &ConnectionEndpoint{
 		manager:            manager,
 		publisher:          publisher,
@@ -75,6 +84,7 @@ func NewConnectionEndpoint(manager connection.MultiManager, stateProvider stateP
 		proposalRepository: proposalRepository,
 		identityRegistry:   identityRegistry,
 		addressProvider:    addressProvider,
+		latencyMeasurer:    latencyMeasurer,
 	}
 }
 
@@ -160,6 +170,11 @@ func (ce *ConnectionEndpoint) Create(c *gin.Context) {
 		return
 	}
 
+	ce.connect(c, cr)
+}
+
+// connect checks consumer registration and connects to a provider matching the validated request.
+func (ce *ConnectionEndpoint) connect(c *gin.Context, cr *contract.ConnectionCreateRequest) {
 	consumerID := identity.FromAddress(cr.ConsumerID)
 	status, err := ce.identityRegistry.GetRegistrationStatus(config.GetInt64(config.FlagChainID), consumerID)
 	if err != nil {
@@ -199,18 +214,34 @@ func (ce *ConnectionEndpoint) Create(c *gin.Context) {
 		IPType:                  cr.Filter.IPType,
 		IncludeMonitoringFailed: cr.Filter.IncludeMonitoringFailed,
 		AccessPolicy:            "all",
+		AddressFamilies:         p2p.ReachableAddressFamilies(),
+		PricePerHourMax:         cr.Filter.PricePerHourMax,
+		PricePerGiBMax:          cr.Filter.PricePerGiBMax,
 	}
 	proposalLookup := connection.FilteredProposals(f, cr.Filter.SortBy, ce.proposalRepository)
+	if cr.Filter.SortBy == proposal.SortTypeMeasuredLatency && ce.latencyMeasurer != nil {
+		repo := discovery.NewLatencyMeasuringRepository(c.Request.Context(), ce.proposalRepository, ce.latencyMeasurer, discovery.DefaultLatencyCandidates)
+		proposalLookup = connection.FilteredProposals(f, cr.Filter.SortBy, repo)
+	}
 
 	err = ce.manager.Connect(consumerID, common.HexToAddress(cr.HermesID), proposalLookup, getConnectOptions(cr))
 	if err != nil {
-		switch err {
-		case connection.ErrAlreadyExists:
+		switch {
+		case errors.Is(err, connection.ErrAlreadyExists):
 			ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageConnectionAlreadyExists, err.Error()))
 			c.Error(apierror.Unprocessable("Connection already exists", contract.ErrCodeConnectionAlreadyExists))
-		case connection.ErrConnectionCancelled:
+		case errors.Is(err, connection.ErrConnectionCancelled):
 			ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageConnectionCanceled, err.Error()))
 			c.Error(apierror.Unprocessable("Connection cancelled", contract.ErrCodeConnectionCancelled))
+		case errors.Is(err, discovery.ErrProposalNotSigned):
+			ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageConnectionUnknownError, err.Error()))
+			c.Error(apierror.Unprocessable("Proposal is not signed by the provider", contract.ErrCodeProposalNotSigned))
+		case errors.Is(err, discovery.ErrProposalSignatureInvalid):
+			ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageConnectionUnknownError, err.Error()))
+			c.Error(apierror.Unprocessable("Proposal signature is invalid", contract.ErrCodeProposalSignature))
+		case errors.Is(err, discovery.ErrProviderNotRegistered):
+			ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageConnectionUnknownError, err.Error()))
+			c.Error(apierror.Unprocessable("Provider identity is not registered", contract.ErrCodeProviderNotRegistered))
 		default:
 			ce.publisher.Publish(quality.AppTopicConnectionEvents, cr.Event(quality.StageConnectionUnknownError, err.Error()))
 			log.Error().Err(err).Msg("Failed to connect")
@@ -340,8 +371,9 @@ func AddRoutesForConnection(
 	identityRegistry identityRegistry,
 	publisher eventbus.Publisher,
 	addressProvider addressProvider,
+	latencyMeasurer latencyMeasurer,
 ) func(*gin.Engine) error {
-	connectionEndpoint := NewConnectionEndpoint(manager, stateProvider, proposalRepository, identityRegistry, publisher, addressProvider)
+	connectionEndpoint := NewConnectionEndpoint(manager, stateProvider, proposalRepository, identityRegistry, publisher, addressProvider, latencyMeasurer)
 	return func(e *gin.Engine) error {
 		connGroup := e.Group("")
 		{
@@ -379,6 +411,7 @@ func getConnectOptions(cr *contract.ConnectionCreateRequest) connection.ConnectP
 	return connection.ConnectParams{
 		DisableKillSwitch: cr.ConnectOptions.DisableKillSwitch,
 		DNS:               dns,
+		DNSBlocklist:      cr.ConnectOptions.DNSBlocklist,
 		ProxyPort:         cr.ConnectOptions.ProxyPort,
 	}
 }
@@ -0,0 +1,331 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/core/connection"
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/connection/profile"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type connectionProfileStorage interface {
+	List() ([]profile.Profile, error)
+	Get(name string) (profile.Profile, error)
+	Save(p profile.Profile) error
+	Replace(profiles []profile.Profile) error
+	Delete(name string) error
+}
+
+type connectionProfileAPI struct {
+	profiles   connectionProfileStorage
+	connection *ConnectionEndpoint
+}
+
+func newConnectionProfileAPI(profiles connectionProfileStorage, connection *ConnectionEndpoint) *connectionProfileAPI {
+	return &connectionProfileAPI{profiles: profiles, connection: connection}
+}
+
+// List returns connection profiles.
+// swagger:operation GET /connection/profiles Connection listConnectionProfiles
+// ---
+// summary: Returns connection profiles
+// responses:
+//   200:
+//     description: List of connection profiles
+//     schema:
+//       "$ref": "#/definitions/ConnectionProfileListDTO"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *connectionProfileAPI) List(c *gin.Context) {
+	profiles, err := api.profiles.List()
+	if err != nil {
+		c.Error(apierror.Internal("Could not list connection profiles: "+err.Error(), contract.ErrCodeConnectionProfileList))
+		return
+	}
+	utils.WriteAsJSON(contract.NewConnectionProfileListDTO(profiles), c.Writer)
+}
+
+// Replace replaces all connection profiles.
+// swagger:operation PUT /connection/profiles Connection replaceConnectionProfiles
+// ---
+// summary: Replaces connection profiles
+// description: Replaces all connection profiles at once. Nothing is changed if any of the profiles is invalid.
+// parameters:
+//   - in: body
+//     name: body
+//     description: Connection profiles
+//     schema:
+//       $ref: "#/definitions/ConnectionProfileListDTO"
+// responses:
+//   200:
+//     description: Connection profiles replaced
+//     schema:
+//       "$ref": "#/definitions/ConnectionProfileListDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *connectionProfileAPI) Replace(c *gin.Context) {
+	var req contract.ConnectionProfileListDTO
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	profiles := req.ProfileList()
+	if err := api.profiles.Replace(profiles); err != nil {
+		c.Error(apierror.Internal("Could not save connection profiles: "+err.Error(), contract.ErrCodeConnectionProfileSave))
+		return
+	}
+	utils.WriteAsJSON(contract.NewConnectionProfileListDTO(profiles), c.Writer)
+}
+
+// Get returns connection profile.
+// swagger:operation GET /connection/profile/{name} Connection getConnectionProfile
+// ---
+// summary: Returns connection profile
+// parameters:
+//   - name: name
+//     in: path
+//     description: Profile name
+//     type: string
+//     required: true
+// responses:
+//   200:
+//     description: Connection profile
+//     schema:
+//       "$ref": "#/definitions/ConnectionProfileDTO"
+//   404:
+//     description: Profile not found
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *connectionProfileAPI) Get(c *gin.Context) {
+	p, ok := api.get(c)
+	if !ok {
+		return
+	}
+	utils.WriteAsJSON(contract.NewConnectionProfileDTO(p), c.Writer)
+}
+
+// Save creates or replaces connection profile.
+// swagger:operation PUT /connection/profile/{name} Connection saveConnectionProfile
+// ---
+// summary: Creates or replaces connection profile
+// parameters:
+//   - name: name
+//     in: path
+//     description: Profile name
+//     type: string
+//     required: true
+//   - in: body
+//     name: body
+//     description: Connection profile, its name is taken from the path
+//     schema:
+//       $ref: "#/definitions/ConnectionProfileDTO"
+// responses:
+//   200:
+//     description: Connection profile saved
+//     schema:
+//       "$ref": "#/definitions/ConnectionProfileDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *connectionProfileAPI) Save(c *gin.Context) {
+	var req contract.ConnectionProfileDTO
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	req.Name = c.Param("name")
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := api.profiles.Save(req.Profile()); err != nil {
+		c.Error(apierror.Internal("Could not save connection profile: "+err.Error(), contract.ErrCodeConnectionProfileSave))
+		return
+	}
+	utils.WriteAsJSON(req, c.Writer)
+}
+
+// Delete removes connection profile.
+// swagger:operation DELETE /connection/profile/{name} Connection deleteConnectionProfile
+// ---
+// summary: Removes connection profile
+// parameters:
+//   - name: name
+//     in: path
+//     description: Profile name
+//     type: string
+//     required: true
+// responses:
+//   204:
+//     description: Connection profile removed
+//   404:
+//     description: Profile not found
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *connectionProfileAPI) Delete(c *gin.Context) {
+	err := api.profiles.Delete(c.Param("name"))
+	if errors.Is(err, profile.ErrNotFound) {
+		c.Error(apierror.NotFound("Connection profile not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Could not remove connection profile: "+err.Error(), contract.ErrCodeConnectionProfileDelete))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Activate connects with the profile parameters, replacing the current connection.
+// swagger:operation PUT /connection/profile/{name}/activate Connection activateConnectionProfile
+// ---
+// summary: Activates connection profile
+// description: Connects with the profile parameters. Existing connection on the profile proxy port is closed first.
+// parameters:
+//   - name: name
+//     in: path
+//     description: Profile name
+//     type: string
+//     required: true
+// responses:
+//   201:
+//     description: Connected
+//     schema:
+//       "$ref": "#/definitions/ConnectionInfoDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   404:
+//     description: Profile not found
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   422:
+//     description: Unable to process the request at this point
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *connectionProfileAPI) Activate(c *gin.Context) {
+	p, ok := api.get(c)
+	if !ok {
+		return
+	}
+
+	hermes, err := api.connection.addressProvider.GetActiveHermes(config.GetInt64(config.FlagChainID))
+	if err != nil {
+		c.Error(apierror.Internal("Failed to get active hermes", contract.ErrCodeActiveHermes))
+		return
+	}
+
+	cr := contract.NewConnectionProfileDTO(p).ConnectionRequest(hermes.Hex())
+	if err := cr.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	manager := api.connection.manager
+	if manager.Status(cr.ConnectOptions.ProxyPort).State != connectionstate.NotConnected {
+		err := manager.Disconnect(cr.ConnectOptions.ProxyPort)
+		if err != nil && !errors.Is(err, connection.ErrNoConnection) {
+			c.Error(apierror.Internal("Could not disconnect: "+err.Error(), contract.ErrCodeDisconnect))
+			return
+		}
+	}
+
+	api.connection.connect(c, cr)
+}
+
+func (api *connectionProfileAPI) get(c *gin.Context) (profile.Profile, bool) {
+	p, err := api.profiles.Get(c.Param("name"))
+	if errors.Is(err, profile.ErrNotFound) {
+		c.Error(apierror.NotFound("Connection profile not found"))
+		return p, false
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Could not get connection profile: "+err.Error(), contract.ErrCodeConnectionProfileList))
+		return p, false
+	}
+	return p, true
+}
+
+// AddRoutesForConnectionProfiles adds connection profile routes to given router
+func AddRoutesForConnectionProfiles(
+	profiles connectionProfileStorage,
+	manager connection.MultiManager,
+	stateProvider stateProvider,
+	proposalRepository proposalRepository,
+	identityRegistry identityRegistry,
+	publisher eventbus.Publisher,
+	addressProvider addressProvider,
+	latencyMeasurer latencyMeasurer,
+) func(*gin.Engine) error {
+	api := newConnectionProfileAPI(
+		profiles,
+		NewConnectionEndpoint(manager, stateProvider, proposalRepository, identityRegistry, publisher, addressProvider, latencyMeasurer),
+	)
+	return func(e *gin.Engine) error {
+		e.GET("/connection/profiles", api.List)
+		e.PUT("/connection/profiles", api.Replace)
+		e.GET("/connection/profile/:name", api.Get)
+		e.PUT("/connection/profile/:name", api.Save)
+		e.DELETE("/connection/profile/:name", api.Delete)
+		e.PUT("/connection/profile/:name/activate", api.Activate)
+		return nil
+	}
+}
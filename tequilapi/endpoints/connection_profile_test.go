@@ -0,0 +1,151 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/connection/profile"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity"
+)
+
+type mockConnectionProfileStorage struct {
+	profiles map[string]profile.Profile
+}
+
+func (s *mockConnectionProfileStorage) List() ([]profile.Profile, error) {
+	var list []profile.Profile
+	for _, p := range s.profiles {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (s *mockConnectionProfileStorage) Get(name string) (profile.Profile, error) {
+	p, ok := s.profiles[name]
+	if !ok {
+		return p, profile.ErrNotFound
+	}
+	return p, nil
+}
+
+func (s *mockConnectionProfileStorage) Save(p profile.Profile) error {
+	s.profiles[p.Name] = p
+	return nil
+}
+
+func (s *mockConnectionProfileStorage) Replace(list []profile.Profile) error {
+	s.profiles = make(map[string]profile.Profile)
+	for _, p := range list {
+		s.profiles[p.Name] = p
+	}
+	return nil
+}
+
+func (s *mockConnectionProfileStorage) Delete(name string) error {
+	if _, ok := s.profiles[name]; !ok {
+		return profile.ErrNotFound
+	}
+	delete(s.profiles, name)
+	return nil
+}
+
+func newConnectionProfileRouter(t *testing.T, storage connectionProfileStorage, manager *mockConnectionManager) *gin.Engine {
+	g := summonTestGin()
+	err := AddRoutesForConnectionProfiles(storage, manager, &mockStateProvider{}, mockRepositoryWithProposal("node1", "wireguard"), mockIdentityRegistryInstance, eventbus.New(), &mockAddressProvider{}, nil)(g)
+	require.NoError(t, err)
+	return g
+}
+
+func serveConnectionProfileRequest(g *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return resp
+}
+
+func TestConnectionProfilesReplaceIsAtomic(t *testing.T) {
+	storage := &mockConnectionProfileStorage{profiles: map[string]profile.Profile{"old": {Name: "old", ConsumerID: "0x1"}}}
+	g := newConnectionProfileRouter(t, storage, &mockConnectionManager{})
+
+	resp := serveConnectionProfileRequest(g, http.MethodPut, "/connection/profiles",
+		`{"profiles": [{"name": "eu", "consumer_id": "0x1"}, {"name": "eu", "consumer_id": "0x2"}]}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, storage.profiles, "old")
+
+	resp = serveConnectionProfileRequest(g, http.MethodPut, "/connection/profiles",
+		`{"profiles": [{"name": "eu", "consumer_id": "0x1", "filter": {"country_code": "DE", "price_per_gib_max": 100}}, {"name": "us", "consumer_id": "0x1"}]}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.NotContains(t, storage.profiles, "old")
+	assert.Equal(t, "DE", storage.profiles["eu"].CountryCode)
+	assert.Equal(t, int64(100), storage.profiles["eu"].PricePerGiBMax.Int64())
+
+	resp = serveConnectionProfileRequest(g, http.MethodGet, "/connection/profiles", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"name":"eu"`)
+	assert.Contains(t, resp.Body.String(), `"name":"us"`)
+}
+
+func TestConnectionProfileSaveGetDelete(t *testing.T) {
+	storage := &mockConnectionProfileStorage{profiles: map[string]profile.Profile{}}
+	g := newConnectionProfileRouter(t, storage, &mockConnectionManager{})
+
+	resp := serveConnectionProfileRequest(g, http.MethodPut, "/connection/profile/eu", `{"consumer_id": "0x1", "connect_options": {"dns": "provider"}}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = serveConnectionProfileRequest(g, http.MethodGet, "/connection/profile/eu", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"dns":"provider"`)
+
+	resp = serveConnectionProfileRequest(g, http.MethodPut, "/connection/profile/bad%20name", `{"consumer_id": "0x1"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = serveConnectionProfileRequest(g, http.MethodDelete, "/connection/profile/eu", "")
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = serveConnectionProfileRequest(g, http.MethodGet, "/connection/profile/eu", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestConnectionProfileActivateReplacesConnection(t *testing.T) {
+	storage := &mockConnectionProfileStorage{profiles: map[string]profile.Profile{
+		"eu": {Name: "eu", ConsumerID: "0x1", ServiceType: "wireguard"},
+	}}
+	manager := &mockConnectionManager{onStatusReturn: connectionstate.Status{State: connectionstate.Connected}}
+	g := newConnectionProfileRouter(t, storage, manager)
+
+	resp := serveConnectionProfileRequest(g, http.MethodPut, "/connection/profile/eu/activate", "")
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, 1, manager.disconnectCount)
+	assert.Equal(t, identity.FromAddress("0x1"), manager.requestedConsumerID)
+	assert.Equal(t, "wireguard", manager.requestedServiceType)
+
+	resp = serveConnectionProfileRequest(g, http.MethodPut, "/connection/profile/unknown/activate", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
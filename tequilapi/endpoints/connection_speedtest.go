@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/speedtest"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+const (
+	// SpeedTestProgressEvent represents the progress of a running speed test
+	SpeedTestProgressEvent EventType = "speedtest-progress"
+	// SpeedTestResultEvent represents the outcome of a speed test
+	SpeedTestResultEvent EventType = "speedtest-result"
+)
+
+type speedTester interface {
+	Run(ctx context.Context, connectionID int, progress func(speedtest.Progress)) (speedtest.Result, error)
+}
+
+type speedTestAPI struct {
+	tester speedTester
+}
+
+// SpeedTest runs a speed test through the current connection.
+// swagger:operation POST /connection/speedtest Connection connectionSpeedTest
+// ---
+// summary: Runs a speed test through the current connection
+// description: Measures latency, download and upload throughput through the tunnel and stores the result in the quality history of the provider. Clients accepting text/event-stream receive progress as server-sent events, the others receive the result only.
+// parameters:
+//   - in: query
+//     name: id
+//     description: Connection ID, the proxy port of the connection
+//     type: integer
+//
+// responses:
+//   200:
+//     description: Speed test result
+//     schema:
+//       "$ref": "#/definitions/SpeedTestResultDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   422:
+//     description: No established connection
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *speedTestAPI) SpeedTest(c *gin.Context) {
+	id := 0
+	if value := c.Query("id"); value != "" {
+		var err error
+		if id, err = strconv.Atoi(value); err != nil {
+			c.Error(apierror.ParseFailed())
+			return
+		}
+	}
+
+	if !strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		result, err := api.tester.Run(c.Request.Context(), id, func(speedtest.Progress) {})
+		if err != nil {
+			c.Error(speedTestError(err))
+			return
+		}
+		utils.WriteAsJSON(contract.NewSpeedTestResultDTO(result), c.Writer)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache,no-transform")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	progress := make(chan speedtest.Progress, 1)
+	done := make(chan struct{})
+	var result speedtest.Result
+	var err error
+	go func() {
+		defer close(done)
+		result, err = api.tester.Run(c.Request.Context(), id, func(p speedtest.Progress) {
+			// Slow clients miss intermediate progress instead of slowing the test down.
+			select {
+			case progress <- p:
+			default:
+			}
+		})
+	}()
+
+	for {
+		select {
+		case p := <-progress:
+			writeSpeedTestEvent(c, SpeedTestProgressEvent, contract.NewSpeedTestProgressDTO(p))
+		case <-done:
+			if err != nil {
+				writeSpeedTestEvent(c, ErrorEvent, err.Error())
+			} else {
+				writeSpeedTestEvent(c, SpeedTestResultEvent, contract.NewSpeedTestResultDTO(result))
+			}
+			return
+		}
+	}
+}
+
+func writeSpeedTestEvent(c *gin.Context, eventType EventType, payload interface{}) {
+	data, err := json.Marshal(Event{Type: eventType, Payload: payload})
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not marshal %q event", eventType)
+		return
+	}
+	if err := writeSSE(c.Writer, streamedEvent{eventType: eventType, data: data}); err != nil {
+		log.Debug().Err(err).Msg("Could not write server-sent event")
+		return
+	}
+	c.Writer.Flush()
+}
+
+func speedTestError(err error) error {
+	if errors.Is(err, speedtest.ErrNotConnected) {
+		return apierror.Unprocessable("No connection exists", contract.ErrCodeNoConnectionExists)
+	}
+	return apierror.Internal("Speed test failed: "+err.Error(), contract.ErrCodeSpeedTest)
+}
+
+// AddRoutesForSpeedTest registers connection speed test routes.
+func AddRoutesForSpeedTest(tester speedTester) func(*gin.Engine) error {
+	api := &speedTestAPI{tester: tester}
+	return func(e *gin.Engine) error {
+		e.POST("/connection/speedtest", api.SpeedTest)
+		return nil
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/policy"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type consumerLists interface {
+	State() policy.ConsumerListsState
+	Add(list, address string) error
+	Remove(list, address string) error
+}
+
+type consumerListsAPI struct {
+	lists consumerLists
+}
+
+// List returns consumer identity lists.
+// swagger:operation GET /access-policy/consumers AccessPolicies consumerLists
+// ---
+// summary: Returns consumer lists
+// description: Returns local consumer allowlist and blocklist together with the state of remote ones
+// responses:
+//   200:
+//     description: Consumer lists
+//     schema:
+//       "$ref": "#/definitions/ConsumerListsDTO"
+func (api *consumerListsAPI) List(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewConsumerListsDTO(api.lists.State()), c.Writer)
+}
+
+// Add adds consumer identity to the local list.
+// swagger:operation PUT /access-policy/consumers/{list}/{id} AccessPolicies addConsumerToList
+// ---
+// summary: Adds consumer to the list
+// description: Adds consumer identity to the local allowlist or blocklist, it applies to new sessions
+// parameters:
+//   - name: list
+//     in: path
+//     description: List name, "allow" or "block"
+//     type: string
+//     required: true
+//   - name: id
+//     in: path
+//     description: Consumer identity
+//     type: string
+//     required: true
+//
+// responses:
+//   200:
+//     description: Consumer lists
+//     schema:
+//       "$ref": "#/definitions/ConsumerListsDTO"
+//   422:
+//     description: Unable to process the request at this point
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *consumerListsAPI) Add(c *gin.Context) {
+	if err := api.lists.Add(c.Param("list"), c.Param("id")); err != nil {
+		c.Error(apierror.Unprocessable("Could not add consumer to the list: "+err.Error(), contract.ErrCodeConsumerListsUpdate))
+		return
+	}
+
+	utils.WriteAsJSON(contract.NewConsumerListsDTO(api.lists.State()), c.Writer)
+}
+
+// Remove removes consumer identity from the local list.
+// swagger:operation DELETE /access-policy/consumers/{list}/{id} AccessPolicies removeConsumerFromList
+// ---
+// summary: Removes consumer from the list
+// parameters:
+//   - name: list
+//     in: path
+//     description: List name, "allow" or "block"
+//     type: string
+//     required: true
+//   - name: id
+//     in: path
+//     description: Consumer identity
+//     type: string
+//     required: true
+//
+// responses:
+//   204:
+//     description: Consumer removed
+//   422:
+//     description: Unable to process the request at this point
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *consumerListsAPI) Remove(c *gin.Context) {
+	if err := api.lists.Remove(c.Param("list"), c.Param("id")); err != nil {
+		c.Error(apierror.Unprocessable("Could not remove consumer from the list: "+err.Error(), contract.ErrCodeConsumerListsUpdate))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddRoutesForConsumerLists registers consumer allowlist and blocklist routes.
+func AddRoutesForConsumerLists(lists consumerLists) func(*gin.Engine) error {
+	api := &consumerListsAPI{lists: lists}
+	return func(e *gin.Engine) error {
+		g := e.Group("/access-policy/consumers")
+		{
+			g.GET("", api.List)
+			g.PUT("/:list/:id", api.Add)
+			g.DELETE("/:list/:id", api.Remove)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+type diagnosticsBundler interface {
+	Bundle(ctx context.Context, w io.Writer) error
+}
+
+type diagnosticsEndpoint struct {
+	bundler diagnosticsBundler
+}
+
+// swagger:operation POST /diagnostics/bundle Diagnostics diagnosticsBundle
+// ---
+// summary: Creates diagnostics bundle
+// description: Gathers NAT type, port mapping status, broker and discovery reachability, recent connection errors and sanitized logs into a ZIP archive for support
+// produces:
+//   - application/zip
+// responses:
+//   200:
+//     description: Diagnostics archive
+//     schema:
+//       type: file
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (e *diagnosticsEndpoint) Bundle(c *gin.Context) {
+	var archive bytes.Buffer
+	if err := e.bundler.Bundle(c.Request.Context(), &archive); err != nil {
+		c.Error(apierror.Internal("Could not create diagnostics bundle: "+err.Error(), contract.ErrCodeDiagnosticsBundle))
+		return
+	}
+
+	filename := "myst-diagnostics-" + time.Now().UTC().Format("20060102-150405") + ".zip"
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/zip", archive.Bytes())
+}
+
+// AddRoutesForDiagnostics attaches diagnostics endpoints to router.
+func AddRoutesForDiagnostics(bundler diagnosticsBundler) func(*gin.Engine) error {
+	endpoint := &diagnosticsEndpoint{bundler: bundler}
+	return func(e *gin.Engine) error {
+		e.POST("/diagnostics/bundle", endpoint.Bundle)
+		return nil
+	}
+}
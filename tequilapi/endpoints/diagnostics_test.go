@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDiagnosticsBundler struct {
+	err error
+}
+
+func (m *mockDiagnosticsBundler) Bundle(_ context.Context, w io.Writer) error {
+	if m.err != nil {
+		return m.err
+	}
+	_, err := w.Write([]byte("archive"))
+	return err
+}
+
+func TestDiagnosticsBundle(t *testing.T) {
+	g := summonTestGin()
+	err := AddRoutesForDiagnostics(&mockDiagnosticsBundler{})(g)
+	assert.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/diagnostics/bundle", nil)
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/zip", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Header().Get("Content-Disposition"), `attachment; filename="myst-diagnostics-`)
+	assert.Equal(t, "archive", resp.Body.String())
+}
+
+func TestDiagnosticsBundle_Error(t *testing.T) {
+	g := summonTestGin()
+	err := AddRoutesForDiagnostics(&mockDiagnosticsBundler{err: errors.New("disk full")})(g)
+	assert.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/diagnostics/bundle", nil)
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, "err_diagnostics_bundle", apierror.Parse(resp.Result()).Err.Code)
+}
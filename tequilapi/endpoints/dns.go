@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mysteriumnetwork/node/dns"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type dnsBlocklist interface {
+	Stats() dns.BlocklistStats
+}
+
+type dnsAPI struct {
+	blocklist dnsBlocklist
+}
+
+// Blocklist returns domain blocklist state and counters of blocked queries.
+// swagger:operation GET /dns/blocklist DNS dnsBlocklist
+// ---
+// summary: Returns domain blocklist state
+// description: Returns blocklist sources, number of blocked domains and counters of queries blocked for connections with DNS filtering enabled
+// responses:
+//   200:
+//     description: Domain blocklist state
+//     schema:
+//       "$ref": "#/definitions/DNSBlocklistDTO"
+func (api *dnsAPI) Blocklist(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewDNSBlocklistDTO(api.blocklist.Stats()), c.Writer)
+}
+
+// AddRoutesForDNS registers consumer DNS routes.
+func AddRoutesForDNS(blocklist dnsBlocklist) func(*gin.Engine) error {
+	api := &dnsAPI{blocklist: blocklist}
+	return func(e *gin.Engine) error {
+		e.GET("/dns/blocklist", api.Blocklist)
+		return nil
+	}
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+const (
+	// ConnectionStateEvent represents the consumer connection state change
+	ConnectionStateEvent EventType = "connection-state"
+	// ConnectionStatisticsEvent represents the consumer connection statistics
+	ConnectionStatisticsEvent EventType = "connection-statistics"
+	// EarningsEvent represents the provider earnings change
+	EarningsEvent EventType = "earnings"
+	// RegistrationEvent represents the identity registration status change
+	RegistrationEvent EventType = "registration"
+	// ErrorEvent represents an error of the event stream itself
+	ErrorEvent EventType = "error"
+)
+
+// eventTopics maps event types streamed to clients to event bus topics.
+var eventTopics = map[EventType]string{
+	ConnectionStateEvent:      connectionstate.AppTopicConnectionState,
+	ConnectionStatisticsEvent: connectionstate.AppTopicConnectionStatistics,
+	EarningsEvent:             pingpongEvent.AppTopicEarningsChanged,
+	RegistrationEvent:         registry.AppTopicIdentityRegistration,
+}
+
+const (
+	eventClientBuffer = 64
+	eventHistorySize  = 256
+)
+
+// streamedEvent is an event marshaled for clients. Its ID grows with each published event.
+type streamedEvent struct {
+	id        uint64
+	eventType EventType
+	data      []byte
+}
+
+type eventClient struct {
+	events chan streamedEvent
+
+	mu sync.RWMutex
+	// topics filters streamed event types, all of them are streamed when empty.
+	topics map[EventType]struct{}
+}
+
+func (c *eventClient) wants(eventType EventType) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.topics) == 0 {
+		return true
+	}
+	_, ok := c.topics[eventType]
+	return ok
+}
+
+func (c *eventClient) setTopics(topics map[EventType]struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.topics = topics
+}
+
+func (c *eventClient) send(e streamedEvent) {
+	// Slow clients miss events instead of blocking the others.
+	select {
+	case c.events <- e:
+	default:
+	}
+}
+
+func (c *eventClient) sendError(message string) {
+	c.send(newErrorEvent(message))
+}
+
+// newErrorEvent returns an error of the stream itself. Such events have no ID, as they are not resumable.
+func newErrorEvent(message string) streamedEvent {
+	data, _ := json.Marshal(Event{Type: ErrorEvent, Payload: message})
+	return streamedEvent{eventType: ErrorEvent, data: data}
+}
+
+// EventBridge bridges event bus topics to streaming clients, keeping recent events so clients can resume.
+type EventBridge struct {
+	mu      sync.RWMutex
+	lastID  uint64
+	history []streamedEvent
+	clients map[*eventClient]struct{}
+}
+
+// NewEventBridge returns a new instance of event bridge.
+func NewEventBridge() *EventBridge {
+	return &EventBridge{
+		clients: make(map[*eventClient]struct{}),
+	}
+}
+
+// Subscribe subscribes to the event bus topics streamed to clients.
+func (b *EventBridge) Subscribe(bus eventbus.Subscriber) error {
+	for eventType, topic := range eventTopics {
+		if err := bus.SubscribeAsync(topic, b.publishFunc(eventType)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *EventBridge) publishFunc(eventType EventType) func(payload interface{}) {
+	return func(payload interface{}) {
+		b.publish(Event{Type: eventType, Payload: payload})
+	}
+}
+
+func (b *EventBridge) publish(e Event) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not marshal %q event", e.Type)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	streamed := streamedEvent{id: b.lastID, eventType: e.Type, data: data}
+	b.history = append(b.history, streamed)
+	if len(b.history) > eventHistorySize {
+		b.history = b.history[len(b.history)-eventHistorySize:]
+	}
+
+	for client := range b.clients {
+		if client.wants(e.Type) {
+			client.send(streamed)
+		}
+	}
+}
+
+// join registers a new client. When resuming, events published after the given ID are replayed
+// to the client before any new ones; ok is false if some of them are no longer kept.
+func (b *EventBridge) join(topics map[EventType]struct{}, resume bool, lastID uint64) (client *eventClient, missed []streamedEvent, ok bool) {
+	client = &eventClient{events: make(chan streamedEvent, eventClientBuffer), topics: topics}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.clients[client] = struct{}{}
+	if !resume || lastID >= b.lastID {
+		return client, nil, true
+	}
+
+	ok = len(b.history) > 0 && b.history[0].id <= lastID+1
+	for _, e := range b.history {
+		if e.id > lastID && client.wants(e.eventType) {
+			missed = append(missed, e)
+		}
+	}
+	return client, missed, ok
+}
+
+func (b *EventBridge) leave(client *eventClient) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.clients, client)
+}
+
+func (b *EventBridge) clientCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return len(b.clients)
+}
+
+// queryEventTopics parses comma separated event types of the "topics" query parameter.
+func queryEventTopics(c *gin.Context) (map[EventType]struct{}, error) {
+	var requested []EventType
+	if topics := c.Query("topics"); topics != "" {
+		for _, topic := range strings.Split(topics, ",") {
+			requested = append(requested, EventType(strings.TrimSpace(topic)))
+		}
+	}
+	return parseEventTopics(requested)
+}
+
+func parseEventTopics(requested []EventType) (map[EventType]struct{}, error) {
+	topics := make(map[EventType]struct{}, len(requested))
+	for _, eventType := range requested {
+		if _, ok := eventTopics[eventType]; !ok {
+			return nil, fmt.Errorf("unknown event topic: %q", eventType)
+		}
+		topics[eventType] = struct{}{}
+	}
+	return topics, nil
+}
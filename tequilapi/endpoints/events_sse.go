@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+const sseKeepAliveInterval = 15 * time.Second
+
+// SSEEventsHandler streams event bus events as server-sent events, for clients which can't use WebSockets.
+type SSEEventsHandler struct {
+	bridge *EventBridge
+}
+
+// NewSSEEventsHandler returns a new instance of server-sent events handler.
+func NewSSEEventsHandler(bridge *EventBridge) *SSEEventsHandler {
+	return &SSEEventsHandler{
+		bridge: bridge,
+	}
+}
+
+// Serve streams events as server-sent events.
+// swagger:operation GET /events/stream Events streamEventsSSE
+// ---
+// summary: Streams node events as server-sent events
+// description: Fallback of /events/ws for environments where WebSockets are blocked. Streams the same events, each of them with an ID. Reconnecting client sends the last received ID in Last-Event-ID header and receives the events it missed first.
+// parameters:
+//   - in: query
+//     name: topics
+//     description: Comma separated event types to stream, all of them are streamed if empty. One of connection-state, connection-statistics, earnings, registration
+//     type: string
+//   - in: header
+//     name: Last-Event-ID
+//     description: ID of the last received event to resume the stream from
+//     type: string
+//   - in: query
+//     name: last_event_id
+//     description: Same as Last-Event-ID header, for clients which can't set headers
+//     type: string
+//
+// responses:
+//   200:
+//     description: Event stream
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (h *SSEEventsHandler) Serve(c *gin.Context) {
+	topics, err := queryEventTopics(c)
+	if err != nil {
+		c.Error(apierror.BadRequest(err.Error(), contract.ErrCodeEventsTopic))
+		return
+	}
+
+	lastID, resume, err := lastEventID(c)
+	if err != nil {
+		c.Error(apierror.BadRequest("Invalid last event ID", contract.ErrCodeEventsLastID))
+		return
+	}
+
+	client, missed, complete := h.bridge.join(topics, resume, lastID)
+	defer h.bridge.leave(client)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache,no-transform")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if !complete {
+		missed = append([]streamedEvent{newErrorEvent(fmt.Sprintf("some events after %d are no longer available", lastID))}, missed...)
+	}
+	for _, e := range missed {
+		if err := writeSSE(c.Writer, e); err != nil {
+			log.Debug().Err(err).Msg("Could not write server-sent event")
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case e := <-client.events:
+			if err := writeSSE(c.Writer, e); err != nil {
+				log.Debug().Err(err).Msg("Could not write server-sent event")
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := io.WriteString(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func lastEventID(c *gin.Context) (id uint64, ok bool, err error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+
+	id, err = strconv.ParseUint(value, 10, 64)
+	return id, err == nil, err
+}
+
+func writeSSE(w io.Writer, e streamedEvent) error {
+	if e.id > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", e.id); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "data: %s\n\n", e.data)
+	return err
+}
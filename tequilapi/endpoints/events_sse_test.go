@@ -0,0 +1,134 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sseFrame struct {
+	id    string
+	event Event
+}
+
+func newSSEEventsServer(t *testing.T) (*EventBridge, string) {
+	bridge := NewEventBridge()
+
+	g := summonTestGin()
+	g.GET("/events/stream", NewSSEEventsHandler(bridge).Serve)
+	server := httptest.NewServer(g)
+	t.Cleanup(server.Close)
+
+	return bridge, server.URL + "/events/stream"
+}
+
+func openSSEStream(t *testing.T, url, lastEventID string) *bufio.Reader {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	return bufio.NewReader(resp.Body)
+}
+
+func readSSEFrame(t *testing.T, r *bufio.Reader) sseFrame {
+	var frame sseFrame
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return frame
+		case strings.HasPrefix(line, "id: "):
+			frame.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &frame.event))
+		}
+	}
+}
+
+func TestSSEEventsResumesFromLastEventID(t *testing.T) {
+	bridge, url := newSSEEventsServer(t)
+	for i := 0; i < 3; i++ {
+		bridge.publish(Event{Type: EarningsEvent, Payload: i})
+	}
+
+	stream := openSSEStream(t, url+"?topics=earnings", "1")
+	waitForEventClients(t, bridge, 1)
+
+	bridge.publish(Event{Type: RegistrationEvent})
+	bridge.publish(Event{Type: EarningsEvent, Payload: 3})
+
+	for _, id := range []string{"2", "3", "5"} {
+		frame := readSSEFrame(t, stream)
+		assert.Equal(t, id, frame.id)
+		assert.Equal(t, EarningsEvent, frame.event.Type)
+	}
+}
+
+func TestSSEEventsReportsMissedHistory(t *testing.T) {
+	bridge, url := newSSEEventsServer(t)
+	for i := 0; i < eventHistorySize+2; i++ {
+		bridge.publish(Event{Type: EarningsEvent, Payload: i})
+	}
+
+	stream := openSSEStream(t, url, "1")
+
+	frame := readSSEFrame(t, stream)
+	assert.Equal(t, "", frame.id)
+	assert.Equal(t, ErrorEvent, frame.event.Type)
+
+	frame = readSSEFrame(t, stream)
+	assert.Equal(t, "3", frame.id)
+}
+
+func TestSSEEventsRejectsInvalidRequest(t *testing.T) {
+	_, url := newSSEEventsServer(t)
+
+	resp, err := http.Get(url + "?topics=unknown")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(url + "?last_event_id=abc")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
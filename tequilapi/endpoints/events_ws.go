@@ -0,0 +1,159 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+const (
+	wsReadLimit    = 4096
+	wsWriteTimeout = 10 * time.Second
+	wsPingInterval = 30 * time.Second
+)
+
+// wsSubscription is sent by a client to change event types it receives.
+type wsSubscription struct {
+	Topics []EventType `json:"topics"`
+}
+
+// WSEventsHandler streams event bus events to WebSocket clients.
+type WSEventsHandler struct {
+	upgrader websocket.Upgrader
+	bridge   *EventBridge
+}
+
+// NewWSEventsHandler returns a new instance of WebSocket events handler.
+func NewWSEventsHandler(bridge *EventBridge) *WSEventsHandler {
+	return &WSEventsHandler{
+		bridge: bridge,
+	}
+}
+
+// Serve upgrades the connection to a WebSocket and streams events to it.
+// swagger:operation GET /events/ws Events streamEvents
+// ---
+// summary: Streams node events over WebSocket
+// description: Upgrades the connection to a WebSocket and streams connection state, connection statistics, earnings and registration events. Client may send {"topics":[...]} to change the streamed event types.
+// parameters:
+//   - in: query
+//     name: topics
+//     description: Comma separated event types to stream, all of them are streamed if empty. One of connection-state, connection-statistics, earnings, registration
+//     type: string
+//
+// responses:
+//   101:
+//     description: Switching protocols
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (h *WSEventsHandler) Serve(c *gin.Context) {
+	topics, err := queryEventTopics(c)
+	if err != nil {
+		c.Error(apierror.BadRequest(err.Error(), contract.ErrCodeEventsTopic))
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Debug().Err(err).Msg("Could not upgrade to WebSocket")
+		return
+	}
+	defer conn.Close()
+
+	client, _, _ := h.bridge.join(topics, false, 0)
+	defer h.bridge.leave(client)
+
+	done := make(chan struct{})
+	go h.readSubscriptions(conn, client, done)
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case e := <-client.events:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, e.data); err != nil {
+				log.Debug().Err(err).Msg("Could not write WebSocket event")
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				log.Debug().Err(err).Msg("Could not ping WebSocket client")
+				return
+			}
+		}
+	}
+}
+
+// readSubscriptions applies subscription changes sent by the client until the connection is closed.
+func (h *WSEventsHandler) readSubscriptions(conn *websocket.Conn, client *eventClient, done chan struct{}) {
+	defer close(done)
+
+	conn.SetReadLimit(wsReadLimit)
+	conn.SetReadDeadline(time.Now().Add(2 * wsPingInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * wsPingInterval))
+	})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var sub wsSubscription
+		if err := json.Unmarshal(message, &sub); err != nil {
+			client.sendError("could not parse subscription")
+			continue
+		}
+
+		topics, err := parseEventTopics(sub.Topics)
+		if err != nil {
+			client.sendError(err.Error())
+			continue
+		}
+		client.setTopics(topics)
+	}
+}
+
+// AddRoutesForEvents adds routes streaming events over WebSocket and server-sent events.
+func AddRoutesForEvents(bus eventbus.Subscriber) func(*gin.Engine) error {
+	return func(e *gin.Engine) error {
+		bridge := NewEventBridge()
+		if err := bridge.Subscribe(bus); err != nil {
+			return err
+		}
+		e.GET("/events/ws", NewWSEventsHandler(bridge).Serve)
+		e.GET("/events/stream", NewSSEEventsHandler(bridge).Serve)
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	pingpongEvent "github.com/mysteriumnetwork/node/session/pingpong/event"
+)
+
+func newWSEventsServer(t *testing.T) (*EventBridge, eventbus.EventBus, string) {
+	bus := eventbus.New()
+	bridge := NewEventBridge()
+	require.NoError(t, bridge.Subscribe(bus))
+
+	g := summonTestGin()
+	g.GET("/events/ws", NewWSEventsHandler(bridge).Serve)
+	server := httptest.NewServer(g)
+	t.Cleanup(server.Close)
+
+	return bridge, bus, "ws" + strings.TrimPrefix(server.URL, "http") + "/events/ws"
+}
+
+func waitForEventClients(t *testing.T, bridge *EventBridge, count int) {
+	assert.Eventually(t, func() bool {
+		return bridge.clientCount() == count
+	}, time.Second, 10*time.Millisecond)
+}
+
+func readWSEvent(t *testing.T, conn *websocket.Conn) Event {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	_, message, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	var e Event
+	require.NoError(t, json.Unmarshal(message, &e))
+	return e
+}
+
+func TestWSEventsFiltersTopics(t *testing.T) {
+	bridge, bus, url := newWSEventsServer(t)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?topics=earnings", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	waitForEventClients(t, bridge, 1)
+
+	bus.Publish(connectionstate.AppTopicConnectionState, connectionstate.AppEventConnectionState{State: connectionstate.Connected})
+	bus.Publish(pingpongEvent.AppTopicEarningsChanged, pingpongEvent.AppEventEarningsChanged{})
+
+	assert.Equal(t, EarningsEvent, readWSEvent(t, conn).Type)
+}
+
+func TestWSEventsChangesSubscription(t *testing.T) {
+	bridge, bus, url := newWSEventsServer(t)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	waitForEventClients(t, bridge, 1)
+
+	require.NoError(t, conn.WriteJSON(wsSubscription{Topics: []EventType{"unknown"}}))
+	assert.Equal(t, ErrorEvent, readWSEvent(t, conn).Type)
+
+	require.NoError(t, conn.WriteJSON(wsSubscription{Topics: []EventType{RegistrationEvent}}))
+	assert.Eventually(t, func() bool {
+		bridge.mu.RLock()
+		defer bridge.mu.RUnlock()
+		for client := range bridge.clients {
+			return !client.wants(EarningsEvent)
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	bus.Publish(pingpongEvent.AppTopicEarningsChanged, pingpongEvent.AppEventEarningsChanged{})
+	bus.Publish(registry.AppTopicIdentityRegistration, registry.AppEventIdentityRegistration{Status: registry.Registered})
+
+	assert.Equal(t, RegistrationEvent, readWSEvent(t, conn).Type)
+}
+
+func TestWSEventsRejectsUnknownTopic(t *testing.T) {
+	_, _, url := newWSEventsServer(t)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?topics=earnings,unknown", nil)
+	assert.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
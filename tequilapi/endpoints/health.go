@@ -0,0 +1,92 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mysteriumnetwork/node/diagnostics"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type healthReporter interface {
+	Live(ctx context.Context) diagnostics.HealthReport
+	Ready(ctx context.Context) diagnostics.HealthReport
+}
+
+type healthEndpoint struct {
+	reporter healthReporter
+}
+
+// Live reports whether local node dependencies work.
+// swagger:operation GET /healthz Client healthz
+// ---
+// summary: Liveness probe
+// description: Checks keystore access and storage writability. Intended for container orchestrator liveness probes
+// responses:
+//   200:
+//     description: All checked dependencies are healthy
+//     schema:
+//       "$ref": "#/definitions/HealthStatusDTO"
+//   503:
+//     description: Some of checked dependencies are unhealthy
+//     schema:
+//       "$ref": "#/definitions/HealthStatusDTO"
+func (e *healthEndpoint) Live(c *gin.Context) {
+	writeHealthStatus(c, e.reporter.Live(c.Request.Context()))
+}
+
+// Ready reports whether the node is able to serve traffic.
+// swagger:operation GET /readyz Client readyz
+// ---
+// summary: Readiness probe
+// description: Checks liveness dependencies, broker connectivity, discovery API reachability and blockchain RPC health. Intended for container orchestrator readiness probes
+// responses:
+//   200:
+//     description: All checked dependencies are healthy
+//     schema:
+//       "$ref": "#/definitions/HealthStatusDTO"
+//   503:
+//     description: Some of checked dependencies are unhealthy
+//     schema:
+//       "$ref": "#/definitions/HealthStatusDTO"
+func (e *healthEndpoint) Ready(c *gin.Context) {
+	writeHealthStatus(c, e.reporter.Ready(c.Request.Context()))
+}
+
+func writeHealthStatus(c *gin.Context, report diagnostics.HealthReport) {
+	status := http.StatusOK
+	if !report.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+	utils.WriteAsJSON(contract.NewHealthStatusDTO(report), c.Writer, status)
+}
+
+// AddRoutesForHealth attaches liveness and readiness probe endpoints to router.
+func AddRoutesForHealth(reporter healthReporter) func(*gin.Engine) error {
+	endpoint := &healthEndpoint{reporter: reporter}
+	return func(e *gin.Engine) error {
+		e.GET("/healthz", endpoint.Live)
+		e.GET("/readyz", endpoint.Ready)
+		return nil
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/diagnostics"
+)
+
+type mockHealthReporter struct {
+	live, ready diagnostics.HealthReport
+}
+
+func (m *mockHealthReporter) Live(context.Context) diagnostics.HealthReport {
+	return m.live
+}
+
+func (m *mockHealthReporter) Ready(context.Context) diagnostics.HealthReport {
+	return m.ready
+}
+
+func TestHealthProbes(t *testing.T) {
+	g := summonTestGin()
+	err := AddRoutesForHealth(&mockHealthReporter{
+		live: diagnostics.HealthReport{
+			Healthy: true,
+			Checks:  []diagnostics.CheckResult{{Name: "storage", Healthy: true, LatencyMs: 1}},
+		},
+		ready: diagnostics.HealthReport{
+			Healthy: false,
+			Checks: []diagnostics.CheckResult{
+				{Name: "storage", Healthy: true, LatencyMs: 1},
+				{Name: "broker", Healthy: false, LatencyMs: 5, Error: "disconnected from broker"},
+			},
+		},
+	})(g)
+	assert.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"healthy": true, "dependencies": [{"name": "storage", "healthy": true, "latency_ms": 1}]}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.JSONEq(t, `{
+		"healthy": false,
+		"dependencies": [
+			{"name": "storage", "healthy": true, "latency_ms": 1},
+			{"name": "broker", "healthy": false, "latency_ms": 5, "error": "disconnected from broker"}
+		]
+	}`, resp.Body.String())
+}
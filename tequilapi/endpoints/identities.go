@@ -60,6 +60,10 @@ type providerChannel interface {
 
 type identityMover interface {
 	Import(blob []byte, currPass, newPass string) (identity.Identity, error)
+	ImportMnemonic(mnemonic, password, derivationPath, newPass string) (identity.Identity, error)
+	ImportBackupMnemonic(mnemonic, backupPass, address, newPass string) (identity.Identity, error)
+	Export(address, currPass, newPass string) ([]byte, error)
+	ExportMnemonic(address, currPass, backupPass string) (string, error)
 }
 
 type identitiesAPI struct {
@@ -75,6 +79,7 @@ type identitiesAPI struct {
 	bprovider        beneficiaryProvider
 	addressStorage   *payout.AddressStorage
 	hermesMigrator   *migration.HermesMigrator
+	rotator          identityRotator
 }
 
 // AddressProvider provides sc addresses.
@@ -707,6 +712,7 @@ func AddRoutesForIdentities(
 	mover identityMover,
 	addressStorage *payout.AddressStorage,
 	hermesMigrator *migration.HermesMigrator,
+	rotator identityRotator,
 ) func(*gin.Engine) error {
 	idAPI := &identitiesAPI{
 		mover:            mover,
@@ -721,6 +727,7 @@ func AddRoutesForIdentities(
 		bprovider:        bprovider,
 		addressStorage:   addressStorage,
 		hermesMigrator:   hermesMigrator,
+		rotator:          rotator,
 	}
 	return func(e *gin.Engine) error {
 		identityGroup := e.Group("/identities")
@@ -738,8 +745,15 @@ func AddRoutesForIdentities(
 			identityGroup.PUT("/:id/balance/refresh", idAPI.BalanceRefresh)
 			identityGroup.POST("/:id/migrate-hermes", idAPI.MigrateHermes)
 			identityGroup.GET("/:id/migrate-hermes/status", idAPI.MigrationHermesStatus)
+			identityGroup.POST("/:id/backup", idAPI.Backup)
+			identityGroup.POST("/:id/export", idAPI.Export)
+			identityGroup.POST("/:id/rotate", idAPI.Rotate)
+			identityGroup.GET("/:id/rotations", idAPI.Rotations)
 		}
 		e.POST("/identities-import", idAPI.Import)
+		e.POST("/identities-import-bundle", idAPI.ImportBundle)
+		e.GET("/identities-mnemonic", idAPI.NewMnemonic)
+		e.POST("/identities-restore", idAPI.Restore)
 		return nil
 	}
 }
@@ -0,0 +1,188 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+// Export exports the identity as a passphrase-encrypted bundle.
+// swagger:operation POST /identities/{id}/export Identities exportIdentity
+// ---
+// summary: Exports an identity
+// description: Exports the identity key encrypted with the export passphrase along with its registration status and beneficiary, so it can be imported on another node
+// parameters:
+//   - in: path
+//     name: id
+//     description: Identity stored in keystore
+//     type: string
+//     required: true
+//   - in: body
+//     name: body
+//     description: Parameter in body used to export an identity.
+//     schema:
+//     $ref: "#/definitions/IdentityExportRequest"
+//
+// responses:
+//   200:
+//     description: Identity bundle
+//     schema:
+//       "$ref": "#/definitions/IdentityBundle"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   404:
+//     description: Identity not found
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   422:
+//     description: Unable to process the request at this point
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (ia *identitiesAPI) Export(c *gin.Context) {
+	var req contract.IdentityExportRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	id, err := ia.idm.GetIdentity(c.Param("id"))
+	if err != nil {
+		c.Error(apierror.NotFound("Identity not found"))
+		return
+	}
+
+	blob, err := ia.mover.Export(id.Address, req.CurrentPassphrase, req.ExportPassphrase)
+	if err != nil {
+		c.Error(apierror.Unprocessable(fmt.Sprintf("Failed to export identity: %s", err), contract.ErrCodeIDExport))
+		return
+	}
+
+	chainID := config.GetInt64(config.FlagChainID)
+	bundle := contract.IdentityBundle{
+		Version:            contract.IdentityBundleVersion,
+		Address:            id.Address,
+		ChainID:            chainID,
+		Keystore:           blob,
+		RegistrationStatus: ia.registrationStatus(chainID, id, registry.Unknown.String()),
+		Beneficiary:        ia.beneficiary(id),
+	}
+	utils.WriteAsJSON(bundle, c.Writer)
+}
+
+// ImportBundle imports the identity from a bundle produced by the export endpoint.
+// swagger:operation POST /identities-import-bundle Identities importIdentityBundle
+// ---
+// summary: Imports an identity bundle
+// description: Imports the identity from a bundle exported by another node
+// parameters:
+//   - in: body
+//     name: body
+//     description: Parameter in body used to import an identity bundle.
+//     schema:
+//     $ref: "#/definitions/IdentityBundleImportRequest"
+//
+// responses:
+//   200:
+//     description: Unlocked identity returned
+//     schema:
+//       "$ref": "#/definitions/IdentityBundleImportResponse"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   422:
+//     description: Unable to process the request at this point
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (ia *identitiesAPI) ImportBundle(c *gin.Context) {
+	var req contract.IdentityBundleImportRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	id, err := ia.mover.Import(req.Bundle.Keystore, req.Passphrase, req.NewPassphrase)
+	if err != nil {
+		c.Error(apierror.Unprocessable(fmt.Sprintf("Failed to import identity: %s", err), contract.ErrCodeIDImport))
+		return
+	}
+
+	if req.SetDefault {
+		if err := ia.selector.SetDefault(id.Address); err != nil {
+			c.Error(apierror.Unprocessable(fmt.Sprintf("Failed to set default identity: %s", err), contract.ErrCodeIDSetDefault))
+			return
+		}
+	}
+
+	beneficiary := ia.beneficiary(id)
+	if beneficiary == "" {
+		beneficiary = req.Bundle.Beneficiary
+	}
+	utils.WriteAsJSON(contract.IdentityBundleImportResponse{
+		Address:            id.Address,
+		RegistrationStatus: ia.registrationStatus(config.GetInt64(config.FlagChainID), id, req.Bundle.RegistrationStatus),
+		Beneficiary:        beneficiary,
+	}, c.Writer)
+}
+
+// registrationStatus returns the registration status of the identity, or the fallback one if it can't be determined.
+func (ia *identitiesAPI) registrationStatus(chainID int64, id identity.Identity, fallback string) string {
+	status, err := ia.registry.GetRegistrationStatus(chainID, id)
+	if err != nil || status == registry.Unknown {
+		log.Warn().Err(err).Msgf("Could not check registration status of %s", id.Address)
+		return fallback
+	}
+	return status.String()
+}
+
+// beneficiary returns the beneficiary of the identity, or an empty string if it is not set or can't be looked up.
+func (ia *identitiesAPI) beneficiary(id identity.Identity) string {
+	beneficiary, err := ia.bprovider.GetBeneficiary(id.ToCommonAddress())
+	if err != nil {
+		log.Warn().Err(err).Msgf("Could not get beneficiary of %s", id.Address)
+		return ""
+	}
+	if beneficiary == (common.Address{}) {
+		return ""
+	}
+	return beneficiary.Hex()
+}
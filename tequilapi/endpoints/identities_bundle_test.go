@@ -0,0 +1,158 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/identity/registry"
+)
+
+type moverFake struct {
+	blob               []byte
+	currPass, newPass  string
+	importErr          error
+	imported           identity.Identity
+	exportedIdentities []string
+}
+
+func (m *moverFake) Import(blob []byte, currPass, newPass string) (identity.Identity, error) {
+	m.blob, m.currPass, m.newPass = blob, currPass, newPass
+	return m.imported, m.importErr
+}
+
+func (m *moverFake) ImportMnemonic(_, _, _, _ string) (identity.Identity, error) {
+	return identity.Identity{}, errors.New("not implemented")
+}
+
+func (m *moverFake) ImportBackupMnemonic(_, _, _, _ string) (identity.Identity, error) {
+	return identity.Identity{}, errors.New("not implemented")
+}
+
+func (m *moverFake) Export(address, currPass, newPass string) ([]byte, error) {
+	m.exportedIdentities = append(m.exportedIdentities, address)
+	m.currPass, m.newPass = currPass, newPass
+	return []byte(`{"address":"000000000000000000000000000000000000000a"}`), nil
+}
+
+func (m *moverFake) ExportMnemonic(_, _, _ string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func TestExportIdentity(t *testing.T) {
+	mover := &moverFake{}
+	endpoint := &identitiesAPI{
+		idm:       identity.NewIdentityManagerFake(existingIdentities, newIdentity),
+		mover:     mover,
+		registry:  &registry.FakeRegistry{RegistrationStatus: registry.Registered},
+		bprovider: &mockBeneficiaryProvider{b: common.HexToAddress("0x0000000000000000000000000000000000001234")},
+	}
+	g := summonTestGin()
+	g.POST("/identities/:id/export", endpoint.Export)
+
+	resp := httptest.NewRecorder()
+	req, err := http.NewRequest(
+		http.MethodPost,
+		"/identities/0x000000000000000000000000000000000000000a/export",
+		bytes.NewBufferString(`{"current_passphrase": "current", "export_passphrase": "export"}`),
+	)
+	assert.NoError(t, err)
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"version": 1,
+		"address": "0x000000000000000000000000000000000000000a",
+		"chain_id": 0,
+		"keystore": {"address": "000000000000000000000000000000000000000a"},
+		"registration_status": "Registered",
+		"beneficiary": "0x0000000000000000000000000000000000001234"
+	}`, resp.Body.String())
+	assert.Equal(t, []string{"0x000000000000000000000000000000000000000a"}, mover.exportedIdentities)
+	assert.Equal(t, "current", mover.currPass)
+	assert.Equal(t, "export", mover.newPass)
+
+	resp = httptest.NewRecorder()
+	req, err = http.NewRequest(
+		http.MethodPost,
+		"/identities/0x00000000000000000000000000000000000000ff/export",
+		bytes.NewBufferString(`{"export_passphrase": "export"}`),
+	)
+	assert.NoError(t, err)
+	g.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestImportIdentityBundle(t *testing.T) {
+	mover := &moverFake{imported: identity.FromAddress("0x000000000000000000000000000000000000000a")}
+	endpoint := &identitiesAPI{
+		mover:     mover,
+		selector:  &selectorFake{},
+		registry:  &registry.FakeRegistry{RegistrationStatus: registry.Unknown},
+		bprovider: &mockBeneficiaryProvider{},
+	}
+	g := summonTestGin()
+	g.POST("/identities-import-bundle", endpoint.ImportBundle)
+
+	resp := httptest.NewRecorder()
+	req, err := http.NewRequest(
+		http.MethodPost,
+		"/identities-import-bundle",
+		bytes.NewBufferString(`{
+			"bundle": {
+				"version": 1,
+				"address": "0x000000000000000000000000000000000000000a",
+				"keystore": {"address": "000000000000000000000000000000000000000a"},
+				"registration_status": "Registered",
+				"beneficiary": "0x0000000000000000000000000000000000001234"
+			},
+			"passphrase": "export",
+			"new_passphrase": "new"
+		}`),
+	)
+	assert.NoError(t, err)
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"id": "0x000000000000000000000000000000000000000a",
+		"registration_status": "Registered",
+		"beneficiary": "0x0000000000000000000000000000000000001234"
+	}`, resp.Body.String())
+	assert.JSONEq(t, `{"address": "000000000000000000000000000000000000000a"}`, string(mover.blob))
+	assert.Equal(t, "export", mover.currPass)
+	assert.Equal(t, "new", mover.newPass)
+
+	resp = httptest.NewRecorder()
+	req, err = http.NewRequest(
+		http.MethodPost,
+		"/identities-import-bundle",
+		bytes.NewBufferString(`{"bundle": {"version": 2, "keystore": {}}, "passphrase": "export"}`),
+	)
+	assert.NoError(t, err)
+	g.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
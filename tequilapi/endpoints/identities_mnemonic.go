@@ -0,0 +1,168 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+// NewMnemonic generates a new BIP-39 mnemonic.
+// swagger:operation GET /identities-mnemonic Identities newIdentityMnemonic
+// ---
+// summary: Generates a new BIP-39 mnemonic
+// description: Generates a new 24 words mnemonic, which can be used to restore an identity. The mnemonic is not stored by the node.
+// responses:
+//   200:
+//     description: Mnemonic generated
+//     schema:
+//       "$ref": "#/definitions/IdentityMnemonicResponse"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (ia *identitiesAPI) NewMnemonic(c *gin.Context) {
+	mnemonic, err := identity.NewMnemonic()
+	if err != nil {
+		c.Error(apierror.Internal("Failed to generate mnemonic", contract.ErrCodeIDMnemonic))
+		return
+	}
+
+	utils.WriteAsJSON(contract.IdentityMnemonicResponse{Mnemonic: mnemonic}, c.Writer)
+}
+
+// Backup exports the identity as an encrypted mnemonic.
+// swagger:operation POST /identities/{id}/backup Identities backupIdentity
+// ---
+// summary: Backs up an identity
+// description: Exports the identity as 48 words mnemonic encrypted with the backup passphrase
+// parameters:
+//   - in: path
+//     name: id
+//     description: Identity stored in keystore
+//     type: string
+//     required: true
+//   - in: body
+//     name: body
+//     description: Parameter in body used to back up an identity.
+//     schema:
+//     $ref: "#/definitions/IdentityBackupRequest"
+//
+// responses:
+//   200:
+//     description: Identity backup mnemonic
+//     schema:
+//       "$ref": "#/definitions/IdentityMnemonicResponse"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   422:
+//     description: Unable to process the request at this point
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (ia *identitiesAPI) Backup(c *gin.Context) {
+	var req contract.IdentityBackupRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	mnemonic, err := ia.mover.ExportMnemonic(c.Param("id"), req.CurrentPassphrase, req.BackupPassphrase)
+	if err != nil {
+		c.Error(apierror.Unprocessable(fmt.Sprintf("Failed to back up identity: %s", err), contract.ErrCodeIDBackup))
+		return
+	}
+
+	utils.WriteAsJSON(contract.IdentityMnemonicResponse{Mnemonic: mnemonic}, c.Writer)
+}
+
+// Restore restores an identity from a mnemonic.
+// swagger:operation POST /identities-restore Identities restoreIdentity
+// ---
+// summary: Restores an identity from a mnemonic
+// description: Restores an identity from the encrypted backup mnemonic or derives it from BIP-39 seed mnemonic
+// parameters:
+//   - in: body
+//     name: body
+//     description: Parameter in body used to restore an identity.
+//     schema:
+//     $ref: "#/definitions/IdentityRestoreRequest"
+//
+// responses:
+//   200:
+//     description: Unlocked identity returned
+//     schema:
+//       "$ref": "#/definitions/IdentityRefDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   422:
+//     description: Unable to process the request at this point
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (ia *identitiesAPI) Restore(c *gin.Context) {
+	var req contract.IdentityRestoreRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	var id identity.Identity
+	var err error
+	if req.Backup {
+		id, err = ia.mover.ImportBackupMnemonic(req.Mnemonic, req.Passphrase, req.Address, req.NewPassphrase)
+	} else {
+		path := req.DerivationPath
+		if path == "" {
+			path = identity.DefaultDerivationPath
+		}
+		id, err = ia.mover.ImportMnemonic(req.Mnemonic, req.Passphrase, path, req.NewPassphrase)
+	}
+	if err != nil {
+		c.Error(apierror.Unprocessable(fmt.Sprintf("Failed to restore identity: %s", err), contract.ErrCodeIDRestore))
+		return
+	}
+
+	if req.SetDefault {
+		if err := ia.selector.SetDefault(id.Address); err != nil {
+			c.Error(apierror.Unprocessable(fmt.Sprintf("Failed to set default identity: %s", err), contract.ErrCodeIDSetDefault))
+			return
+		}
+	}
+
+	utils.WriteAsJSON(contract.NewIdentityDTO(id), c.Writer)
+}
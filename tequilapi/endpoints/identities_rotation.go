@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/config"
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type identityRotator interface {
+	Rotate(chainID int64, from identity.Identity, passphrase string) (identity.Rotation, error)
+	Rotations(address string) ([]identity.Rotation, error)
+}
+
+// Rotate migrates the identity to a new key.
+// swagger:operation POST /identities/{id}/rotate Identities rotateIdentity
+// ---
+// summary: Rotates identity key
+// description: Creates a new identity and signs the migration to it with both keys. Earnings of the old identity are settled to the new one, services running under the old identity keep running.
+// parameters:
+//   - in: path
+//     name: id
+//     description: Unlocked identity to rotate
+//     type: string
+//     required: true
+//   - in: body
+//     name: body
+//     description: Parameter in body used to rotate an identity.
+//     schema:
+//     $ref: "#/definitions/IdentityRotateRequest"
+//
+// responses:
+//   200:
+//     description: Signed identity rotation
+//     schema:
+//       "$ref": "#/definitions/IdentityRotationDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   422:
+//     description: Unable to process the request at this point
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (ia *identitiesAPI) Rotate(c *gin.Context) {
+	var req contract.IdentityRotateRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+
+	from := identity.FromAddress(c.Param("id"))
+	rotation, err := ia.rotator.Rotate(config.GetInt64(config.FlagChainID), from, req.NewPassphrase)
+	if err != nil {
+		c.Error(apierror.Unprocessable(fmt.Sprintf("Failed to rotate identity: %s", err), contract.ErrCodeIDRotate))
+		return
+	}
+
+	if req.SetDefault {
+		if err := ia.selector.SetDefault(rotation.To); err != nil {
+			c.Error(apierror.Unprocessable(fmt.Sprintf("Failed to set default identity: %s", err), contract.ErrCodeIDSetDefault))
+			return
+		}
+	}
+
+	utils.WriteAsJSON(contract.NewIdentityRotationDTO(rotation), c.Writer)
+}
+
+// Rotations lists key rotations of the identity.
+// swagger:operation GET /identities/{id}/rotations Identities identityRotations
+// ---
+// summary: Lists identity key rotations
+// description: Lists rotations of the identity and its successors in the order they happened
+// parameters:
+//   - in: path
+//     name: id
+//     description: Identity stored in keystore
+//     type: string
+//     required: true
+//
+// responses:
+//   200:
+//     description: Identity rotations
+//     schema:
+//       "$ref": "#/definitions/IdentityRotationsResponse"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (ia *identitiesAPI) Rotations(c *gin.Context) {
+	rotations, err := ia.rotator.Rotations(c.Param("id"))
+	if err != nil {
+		c.Error(apierror.Internal(fmt.Sprintf("Failed to list identity rotations: %s", err), contract.ErrCodeIDRotations))
+		return
+	}
+
+	res := contract.IdentityRotationsResponse{Rotations: []contract.IdentityRotationDTO{}}
+	for _, rotation := range rotations {
+		res.Rotations = append(res.Rotations, contract.NewIdentityRotationDTO(rotation))
+	}
+	utils.WriteAsJSON(res, c.Writer)
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type metricsAPI struct {
+	registry *prometheus.Registry
+}
+
+// Metrics returns node runtime metrics.
+// swagger:operation GET /metrics Metrics metrics
+// ---
+// summary: Returns node runtime metrics
+// description: Returns node runtime metrics, e.g. load of broker message workers, in Prometheus text format
+// produces:
+// - text/plain
+// responses:
+//   200:
+//     description: Metrics in Prometheus text format
+func (api *metricsAPI) Metrics(c *gin.Context) {
+	promhttp.HandlerFor(api.registry, promhttp.HandlerOpts{}).ServeHTTP(c.Writer, c.Request)
+}
+
+// AddRoutesForMetrics registers metrics routes.
+func AddRoutesForMetrics(registry *prometheus.Registry) func(*gin.Engine) error {
+	api := &metricsAPI{registry: registry}
+	return func(e *gin.Engine) error {
+		e.GET("/metrics", api.Metrics)
+		return nil
+	}
+}
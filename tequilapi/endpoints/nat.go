@@ -19,6 +19,7 @@ package endpoints
 
 import (
 	"context"
+	"time"
 
 	"github.com/mysteriumnetwork/go-rest/apierror"
 	"github.com/mysteriumnetwork/node/core/node"
@@ -26,6 +27,8 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/mysteriumnetwork/node/nat"
+	natprobe "github.com/mysteriumnetwork/node/nat/behavior"
+	"github.com/mysteriumnetwork/node/nat/mapping"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
 )
@@ -33,22 +36,35 @@ import (
 // NATEndpoint struct represents endpoints about NAT traversal
 type NATEndpoint struct {
 	stateProvider stateProvider
-	natProber     natProber
+	natProber     cachedNATProber
+	portMapper    portMappingLister
 }
 
 type natProber interface {
 	Probe(context.Context) (nat.NATType, error)
 }
 
+type cachedNATProber interface {
+	natProber
+	Refresh(context.Context) (nat.NATType, error)
+	Last() (natprobe.Detection, bool)
+}
+
+type portMappingLister interface {
+	Mappings() []mapping.Mapping
+	Stats() []mapping.ProtocolStats
+}
+
 type nodeStatusProvider interface {
 	Status() node.MonitoringStatus
 }
 
 // NewNATEndpoint creates and returns nat endpoint
-func NewNATEndpoint(stateProvider stateProvider, natProber natProber) *NATEndpoint {
+func NewNATEndpoint(stateProvider stateProvider, natProber cachedNATProber, portMapper portMappingLister) *NATEndpoint {
 	return &NATEndpoint{
 		stateProvider: stateProvider,
 		natProber:     natProber,
+		portMapper:    portMapper,
 	}
 }
 
@@ -56,7 +72,13 @@ func NewNATEndpoint(stateProvider stateProvider, natProber natProber) *NATEndpoi
 // swagger:operation GET /nat/type NAT NATTypeDTO
 // ---
 // summary: Shows NAT type in terms of traversal capabilities.
-// description: Returns NAT type. May produce invalid result while VPN connection is established
+// description: Returns NAT type detected via STUN servers. Result is cached, the last known type is returned while VPN connection is established.
+// parameters:
+//   - in: query
+//     name: refresh
+//     description: Detect NAT type again instead of using the cached result
+//     type: boolean
+//
 // responses:
 //   200:
 //     description: NAT type
@@ -67,25 +89,47 @@ func NewNATEndpoint(stateProvider stateProvider, natProber natProber) *NATEndpoi
 //     schema:
 //       "$ref": "#/definitions/APIError"
 func (ne *NATEndpoint) NATType(c *gin.Context) {
-	res, err := ne.natProber.Probe(c.Request.Context())
+	probe := ne.natProber.Probe
+	if c.Query("refresh") == "true" {
+		probe = ne.natProber.Refresh
+	}
+
+	res, err := probe(c.Request.Context())
 	if err != nil {
 		c.Error(apierror.Internal("NAT probe failed", contract.ErrCodeNATProbe))
 		return
 	}
-	utils.WriteAsJSON(contract.NATTypeDTO{
-		Type:  res,
-		Error: "",
-	}, c.Writer)
+
+	var detectedAt *time.Time
+	if last, ok := ne.natProber.Last(); ok && last.Type == res {
+		detectedAt = &last.DetectedAt
+	}
+	utils.WriteAsJSON(contract.NewNATTypeDTO(res, detectedAt), c.Writer)
+}
+
+// Mappings lists port mappings attempted by the node
+// swagger:operation GET /nat/mappings NAT PortMappingsResponse
+// ---
+// summary: Lists port mappings.
+// description: Returns active port mappings and the most recent failed or released ones together with per protocol statistics.
+// responses:
+//   200:
+//     description: Port mappings
+//     schema:
+//       "$ref": "#/definitions/PortMappingsResponse"
+func (ne *NATEndpoint) Mappings(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewPortMappingsResponse(ne.portMapper.Mappings(), ne.portMapper.Stats()), c.Writer)
 }
 
 // AddRoutesForNAT adds nat routes to given router
-func AddRoutesForNAT(stateProvider stateProvider, natProber natProber) func(*gin.Engine) error {
-	natEndpoint := NewNATEndpoint(stateProvider, natProber)
+func AddRoutesForNAT(stateProvider stateProvider, natProber cachedNATProber, portMapper portMappingLister) func(*gin.Engine) error {
+	natEndpoint := NewNATEndpoint(stateProvider, natProber, portMapper)
 
 	return func(e *gin.Engine) error {
 		v1Group := e.Group("/nat")
 		{
 			v1Group.GET("/type", natEndpoint.NATType)
+			v1Group.GET("/mappings", natEndpoint.Mappings)
 		}
 		return nil
 	}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/nat/mapping"
+)
+
+type mockPortMappingLister struct {
+	mappings []mapping.Mapping
+	stats    []mapping.ProtocolStats
+}
+
+func (m *mockPortMappingLister) Mappings() []mapping.Mapping {
+	return m.mappings
+}
+
+func (m *mockPortMappingLister) Stats() []mapping.ProtocolStats {
+	return m.stats
+}
+
+func Test_NAT_Mappings(t *testing.T) {
+	created := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	lister := &mockPortMappingLister{
+		mappings: []mapping.Mapping{{
+			ID:              1,
+			Protocol:        "UDP",
+			MapProtocol:     mapping.ProtocolUPnP,
+			Name:            "Myst node p2p port mapping",
+			InternalPort:    51334,
+			ExternalPort:    51334,
+			Status:          mapping.StatusRenewalFailed,
+			CreatedAt:       created,
+			ExpiresAt:       created.Add(20 * time.Minute),
+			RenewalFailures: 1,
+			LastError:       "timeout",
+		}},
+		stats: []mapping.ProtocolStats{{Protocol: mapping.ProtocolUPnP, Attempts: 1, Successes: 1, Failures: 1}},
+	}
+	router := summonTestGin()
+	assert.NoError(t, AddRoutesForNAT(nil, nil, lister)(router))
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/nat/mappings", nil)
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"mappings": [{
+			"id": 1,
+			"protocol": "UDP",
+			"map_protocol": "upnp",
+			"name": "Myst node p2p port mapping",
+			"internal_port": 51334,
+			"external_port": 51334,
+			"status": "renewal_failed",
+			"permanent": false,
+			"created_at": "2022-01-01T00:00:00Z",
+			"expires_at": "2022-01-01T00:20:00Z",
+			"renewals": 0,
+			"renewal_failures": 1,
+			"last_error": "timeout"
+		}],
+		"stats": [{"protocol": "upnp", "attempts": 1, "successes": 1, "renewals": 0, "failures": 1}]
+	}`, resp.Body.String())
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/mysteriumnetwork/payments/units"
 
 	"github.com/mysteriumnetwork/node/core/node"
+	"github.com/mysteriumnetwork/node/nat/cgnat"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 	"github.com/mysteriumnetwork/node/tequilapi/launchpad"
 	"github.com/mysteriumnetwork/node/tequilapi/utils"
@@ -48,18 +49,24 @@ type nodeMonitoringAgent interface {
 	EarningsPerService() (node.EarningsPerService, error)
 }
 
+type cgnatDetector interface {
+	Last() (cgnat.Detection, bool)
+}
+
 // NodeEndpoint struct represents endpoints about node status
 type NodeEndpoint struct {
 	nodeStatusProvider  nodeStatusProvider
 	nodeMonitoringAgent nodeMonitoringAgent
+	cgnatDetector       cgnatDetector
 	launchpadAPI        *launchpad.API
 }
 
 // NewNodeEndpoint creates and returns node endpoints
-func NewNodeEndpoint(nodeStatusProvider nodeStatusProvider, nodeMonitoringAgent nodeMonitoringAgent) *NodeEndpoint {
+func NewNodeEndpoint(nodeStatusProvider nodeStatusProvider, nodeMonitoringAgent nodeMonitoringAgent, cgnatDetector cgnatDetector) *NodeEndpoint {
 	return &NodeEndpoint{
 		nodeStatusProvider:  nodeStatusProvider,
 		nodeMonitoringAgent: nodeMonitoringAgent,
+		cgnatDetector:       cgnatDetector,
 		launchpadAPI:        launchpad.New(),
 	}
 }
@@ -68,14 +75,19 @@ func NewNodeEndpoint(nodeStatusProvider nodeStatusProvider, nodeMonitoringAgent
 // swagger:operation GET /node/monitoring-status provider NodeStatus
 // ---
 // summary: Provides Node proposal status
-// description: Node Status as seen by monitoring agent
+// description: Node Status as seen by monitoring agent along with carrier-grade NAT detection result and remediation guidance
 // responses:
 //   200:
 //     description: Node status ("passed"/"failed"/"pending)
 //     schema:
 //       "$ref": "#/definitions/NodeStatusResponse"
 func (ne *NodeEndpoint) NodeStatus(c *gin.Context) {
-	utils.WriteAsJSON(contract.NodeStatusResponse{Status: ne.nodeStatusProvider.Status()}, c.Writer)
+	res := contract.NodeStatusResponse{Status: ne.nodeStatusProvider.Status()}
+	if detection, ok := ne.cgnatDetector.Last(); ok {
+		res.CGNAT = contract.NewCGNATDetectionDTO(detection)
+	}
+
+	utils.WriteAsJSON(res, c.Writer)
 }
 
 // MonitoringAgentStatuses Statuses from monitoring agent
@@ -532,8 +544,8 @@ func (ne *NodeEndpoint) GetProviderServiceEarnings(c *gin.Context) {
 }
 
 // AddRoutesForNode adds nat routes to given router
-func AddRoutesForNode(nodeStatusProvider nodeStatusProvider, nodeMonitoringAgent nodeMonitoringAgent) func(*gin.Engine) error {
-	nodeEndpoints := NewNodeEndpoint(nodeStatusProvider, nodeMonitoringAgent)
+func AddRoutesForNode(nodeStatusProvider nodeStatusProvider, nodeMonitoringAgent nodeMonitoringAgent, cgnatDetector cgnatDetector) func(*gin.Engine) error {
+	nodeEndpoints := NewNodeEndpoint(nodeStatusProvider, nodeMonitoringAgent, cgnatDetector)
 
 	return func(e *gin.Engine) error {
 		nodeGroup := e.Group("/node")
@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mysteriumnetwork/node/metadata"
+	"github.com/mysteriumnetwork/node/monitoring/resources"
+	natprobe "github.com/mysteriumnetwork/node/nat/behavior"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type natDetectionProvider interface {
+	Last() (natprobe.Detection, bool)
+}
+
+type resourceMonitor interface {
+	Snapshot() resources.Snapshot
+}
+
+type nodeSummaryAPI struct {
+	stateProvider      stateProvider
+	nodeStatusProvider nodeStatusProvider
+	cgnatDetector      cgnatDetector
+	natDetection       natDetectionProvider
+	resourceMonitor    resourceMonitor
+	startTime          time.Time
+	currentTimeFunc    func() time.Time
+}
+
+// Summary returns node state UIs need on startup.
+// swagger:operation GET /node/status Node nodeSummary
+// ---
+// summary: Returns consolidated node status
+// description: Returns active services, current connection, NAT and monitoring status, resource usage, identities with their registration state and balances, and version info in a single response. Nothing is probed, the last known values are returned.
+// responses:
+//   200:
+//     description: Node status
+//     schema:
+//       "$ref": "#/definitions/NodeSummaryDTO"
+func (api *nodeSummaryAPI) Summary(c *gin.Context) {
+	state := api.stateProvider.GetState()
+	conn := currentConnection(state)
+
+	services := state.Services
+	if services == nil {
+		services = []contract.ServiceInfoDTO{}
+	}
+
+	res := contract.NodeSummaryDTO{
+		Uptime:  api.currentTimeFunc().Sub(api.startTime).String(),
+		Version: metadata.VersionAsString(),
+		BuildInfo: contract.BuildInfoDTO{
+			Commit:      metadata.BuildCommit,
+			Branch:      metadata.BuildBranch,
+			BuildNumber: metadata.BuildNumber,
+		},
+		Services:   services,
+		Connection: contract.NewConnectionDTO(conn.Session, conn.Statistics, conn.Throughput, conn.Invoice),
+		Monitoring: contract.NodeStatusResponse{Status: api.nodeStatusProvider.Status()},
+		Identities: mapIdentities(state),
+	}
+	if detection, ok := api.cgnatDetector.Last(); ok {
+		res.Monitoring.CGNAT = contract.NewCGNATDetectionDTO(detection)
+	}
+	if detection, ok := api.natDetection.Last(); ok {
+		natType := contract.NewNATTypeDTO(detection.Type, &detection.DetectedAt)
+		res.NAT = &natType
+	}
+	if api.resourceMonitor != nil {
+		if snapshot := api.resourceMonitor.Snapshot(); !snapshot.At.IsZero() {
+			usage := contract.NewResourceUsageDTO(snapshot)
+			res.Resources = &usage
+		}
+	}
+
+	utils.WriteAsJSON(res, c.Writer)
+}
+
+// AddRoutesForNodeSummary attaches consolidated node status endpoint to router.
+func AddRoutesForNodeSummary(stateProvider stateProvider, nodeStatusProvider nodeStatusProvider, cgnatDetector cgnatDetector, natDetection natDetectionProvider, resourceMonitor resourceMonitor) func(*gin.Engine) error {
+	api := &nodeSummaryAPI{
+		stateProvider:      stateProvider,
+		nodeStatusProvider: nodeStatusProvider,
+		cgnatDetector:      cgnatDetector,
+		natDetection:       natDetection,
+		resourceMonitor:    resourceMonitor,
+		startTime:          time.Now(),
+		currentTimeFunc:    time.Now,
+	}
+	return func(e *gin.Engine) error {
+		e.GET("/node/status", api.Summary)
+		return nil
+	}
+}
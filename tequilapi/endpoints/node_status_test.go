@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/connection/connectionstate"
+	"github.com/mysteriumnetwork/node/core/node"
+	stateEvent "github.com/mysteriumnetwork/node/core/state/event"
+	"github.com/mysteriumnetwork/node/identity/registry"
+	"github.com/mysteriumnetwork/node/monitoring/resources"
+	"github.com/mysteriumnetwork/node/nat"
+	natprobe "github.com/mysteriumnetwork/node/nat/behavior"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+type mockNATDetection struct {
+	detection *natprobe.Detection
+}
+
+func (m *mockNATDetection) Last() (natprobe.Detection, bool) {
+	if m.detection == nil {
+		return natprobe.Detection{}, false
+	}
+	return *m.detection, true
+}
+
+type mockResourceMonitor struct {
+	snapshot resources.Snapshot
+}
+
+func (m *mockResourceMonitor) Snapshot() resources.Snapshot {
+	return m.snapshot
+}
+
+func TestNodeSummary(t *testing.T) {
+	detectedAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	state := &mockStateProvider{stateToReturn: stateEvent.State{
+		Services: []contract.ServiceInfoDTO{{ID: "1", ProviderID: "0x1", Type: "wireguard", Status: "Running"}},
+		Connections: map[string]stateEvent.Connection{
+			"": {Session: connectionstate.Status{State: connectionstate.Connected}},
+		},
+		Identities: []stateEvent.Identity{{
+			Address:            "0x1",
+			RegistrationStatus: registry.Registered,
+			Balance:            big.NewInt(10),
+			Earnings:           big.NewInt(1),
+			EarningsTotal:      big.NewInt(2),
+		}},
+	}}
+
+	router := summonTestGin()
+	err := AddRoutesForNodeSummary(
+		state,
+		&mockNodeStatusProvider{status: node.Passed},
+		&mockCGNATDetector{},
+		&mockNATDetection{detection: &natprobe.Detection{Type: nat.NATTypeFullCone, DetectedAt: detectedAt}},
+		&mockResourceMonitor{snapshot: resources.Snapshot{
+			At:   detectedAt,
+			Node: resources.Usage{CPUPercent: 12.5, Goroutines: 100, Processes: 2},
+			Services: []resources.ServiceUsage{{
+				Service: resources.Service{ID: "1", Type: "wireguard"},
+				Usage:   resources.Usage{Goroutines: 10},
+			}},
+		}},
+	)(router)
+	require.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/node/status", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var summary contract.NodeSummaryDTO
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &summary))
+	assert.NotEmpty(t, summary.Version)
+	assert.Equal(t, state.stateToReturn.Services, summary.Services)
+	assert.Equal(t, string(connectionstate.Connected), summary.Connection.Status)
+	assert.Equal(t, node.Passed, summary.Monitoring.Status)
+	assert.Nil(t, summary.Monitoring.CGNAT)
+	require.NotNil(t, summary.NAT)
+	assert.Equal(t, nat.NATTypeFullCone, summary.NAT.Type)
+	assert.Equal(t, detectedAt, *summary.NAT.DetectedAt)
+	require.Len(t, summary.Identities, 1)
+	assert.Equal(t, "0x1", summary.Identities[0].Address)
+	assert.Equal(t, registry.Registered.String(), summary.Identities[0].RegistrationStatus)
+	assert.Equal(t, big.NewInt(10), summary.Identities[0].Balance)
+	require.NotNil(t, summary.Resources)
+	assert.Equal(t, 12.5, summary.Resources.Node.CPUPercent)
+	assert.Equal(t, 2, summary.Resources.Node.Processes)
+	require.Len(t, summary.Resources.Services, 1)
+	assert.Equal(t, "wireguard", summary.Resources.Services[0].Type)
+	assert.Equal(t, 10, summary.Resources.Services[0].Goroutines)
+}
+
+func TestNodeSummary_BeforeDetection(t *testing.T) {
+	router := summonTestGin()
+	err := AddRoutesForNodeSummary(&mockStateProvider{}, &mockNodeStatusProvider{status: node.Pending}, &mockCGNATDetector{}, &mockNATDetection{}, &mockResourceMonitor{})(router)
+	require.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/node/status", nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var summary map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &summary))
+	assert.NotContains(t, summary, "nat")
+	assert.NotContains(t, summary, "resources")
+	assert.Equal(t, []interface{}{}, summary["services"])
+	assert.Equal(t, []interface{}{}, summary["identities"])
+	assert.Equal(t, string(connectionstate.NotConnected), summary["connection"].(map[string]interface{})["status"])
+}
@@ -23,11 +23,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/mysteriumnetwork/node/core/node"
+	"github.com/mysteriumnetwork/node/nat/cgnat"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 )
 
@@ -49,6 +51,17 @@ type mockMonitoringAgent struct {
 	serviceEarnings       node.EarningsPerService
 }
 
+type mockCGNATDetector struct {
+	detection *cgnat.Detection
+}
+
+func (m *mockCGNATDetector) Last() (cgnat.Detection, bool) {
+	if m.detection == nil {
+		return cgnat.Detection{}, false
+	}
+	return *m.detection, true
+}
+
 func (nodeStatusTracker *mockNodeStatusProvider) Status() node.MonitoringStatus {
 	return nodeStatusTracker.status
 }
@@ -103,7 +116,7 @@ func Test_NodeStatus(t *testing.T) {
 	mockMonitoringAgentTracker := &mockMonitoringAgent{}
 
 	router := gin.Default()
-	err := AddRoutesForNode(mockStatusTracker, mockMonitoringAgentTracker)(router)
+	err := AddRoutesForNode(mockStatusTracker, mockMonitoringAgentTracker, &mockCGNATDetector{})(router)
 	assert.NoError(t, err)
 
 	req, err := http.NewRequest(http.MethodGet, "/node/monitoring-status", nil)
@@ -126,3 +139,44 @@ func Test_NodeStatus(t *testing.T) {
 		})
 	}
 }
+
+func Test_NodeStatus_CGNAT(t *testing.T) {
+	// given:
+	detectedAt := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	detector := &mockCGNATDetector{detection: &cgnat.Detection{
+		BehindCGNAT: true,
+		PublicIP:    "1.2.3.4",
+		OutboundIP:  "100.64.0.5",
+		Hairpin:     cgnat.HairpinSkipped,
+		Reasons:     []string{"shared address"},
+		Remediation: []string{"use relay"},
+		DetectedAt:  detectedAt,
+	}}
+
+	router := gin.Default()
+	err := AddRoutesForNode(&mockNodeStatusProvider{status: "passed"}, &mockMonitoringAgent{}, detector)(router)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, "/node/monitoring-status", nil)
+	assert.NoError(t, err)
+	resp := httptest.NewRecorder()
+
+	// when:
+	router.ServeHTTP(resp, req)
+
+	// then:
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"status": "passed",
+		"cgnat": {
+			"behind_cgnat": true,
+			"suspected": false,
+			"public_ip": "1.2.3.4",
+			"outbound_ip": "100.64.0.5",
+			"hairpin": "skipped",
+			"reasons": ["shared address"],
+			"remediation": ["use relay"],
+			"detected_at": "2022-05-01T12:00:00Z"
+		}
+	}`, resp.Body.String())
+}
@@ -18,6 +18,8 @@
 package endpoints
 
 import (
+	"encoding/json"
+	"math/big"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -28,6 +30,7 @@ import (
 	"github.com/mysteriumnetwork/node/core/quality"
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/p2p"
 	"github.com/mysteriumnetwork/node/services/datatransfer"
 	"github.com/mysteriumnetwork/node/services/scraping"
 	"github.com/mysteriumnetwork/node/services/wireguard"
@@ -50,16 +53,18 @@ type proposalsEndpoint struct {
 	locationResolver   location.Resolver
 	filterPresets      proposal.FilterPresetRepository
 	natProber          natProber
+	latencyMeasurer    latencyMeasurer
 }
 
 // NewProposalsEndpoint creates and returns proposal creation endpoint
-func NewProposalsEndpoint(proposalRepository proposalRepository, pricer priceAPI, locationResolver location.Resolver, filterPresetRepository proposal.FilterPresetRepository, natProber natProber) *proposalsEndpoint {
+func NewProposalsEndpoint(proposalRepository proposalRepository, pricer priceAPI, locationResolver location.Resolver, filterPresetRepository proposal.FilterPresetRepository, natProber natProber, latencyMeasurer latencyMeasurer) *proposalsEndpoint {
 	return &proposalsEndpoint{
 		proposalRepository: proposalRepository,
 		pricer:             pricer,
 		locationResolver:   locationResolver,
 		filterPresets:      filterPresetRepository,
 		natProber:          natProber,
+		latencyMeasurer:    latencyMeasurer,
 	}
 }
 
@@ -108,6 +113,26 @@ func NewProposalsEndpoint(proposalRepository proposalRepository, pricer priceAPI
 //     name: nat_compatibility
 //     description: Pick nodes compatible with NAT of specified type. Specify "auto" to probe NAT.
 //     type: string
+//   - in: query
+//     name: price_per_hour_max
+//     description: Maximum price per hour of the proposal, in wei.
+//     type: string
+//   - in: query
+//     name: price_per_gib_max
+//     description: Maximum price per GiB of the proposal, in wei.
+//     type: string
+//   - in: query
+//     name: bandwidth_min
+//     description: Minimum bandwidth of the provider, in Mbps.
+//     type: number
+//   - in: query
+//     name: uptime_min
+//     description: Minimum uptime of the provider.
+//     type: number
+//   - in: query
+//     name: sort
+//     description: Comma separated fields to sort by, "-" prefix sorts descending. Fields are "price_per_hour", "price_per_gib", "quality", "bandwidth", "uptime", "latency" and "country".
+//     type: string
 // responses:
 //   200:
 //     description: List of proposals
@@ -144,6 +169,24 @@ func (pe *proposalsEndpoint) List(c *gin.Context) {
 		}
 	}
 
+	pricePerHourMax, ok := parseWei(req.URL.Query().Get("price_per_hour_max"))
+	if !ok {
+		c.Error(apierror.BadRequestField("'price_per_hour_max' is invalid", apierror.ValidateErrInvalidVal, "price_per_hour_max"))
+		return
+	}
+	pricePerGiBMax, ok := parseWei(req.URL.Query().Get("price_per_gib_max"))
+	if !ok {
+		c.Error(apierror.BadRequestField("'price_per_gib_max' is invalid", apierror.ValidateErrInvalidVal, "price_per_gib_max"))
+		return
+	}
+	sortBy, err := proposal.ParseSortKeys(req.URL.Query().Get("sort"))
+	if err != nil {
+		c.Error(apierror.BadRequestField(err.Error(), apierror.ValidateErrInvalidVal, "sort"))
+		return
+	}
+	bandwidthMin, _ := strconv.ParseFloat(req.URL.Query().Get("bandwidth_min"), 64)
+	uptimeMin, _ := strconv.ParseFloat(req.URL.Query().Get("uptime_min"), 64)
+
 	includeMonitoringFailed, _ := strconv.ParseBool(req.URL.Query().Get("include_monitoring_failed"))
 	proposals, err := pe.proposalRepository.Proposals(&proposal.Filter{
 		PresetID:                presetID,
@@ -159,6 +202,12 @@ func (pe *proposalsEndpoint) List(c *gin.Context) {
 		QualityMin:              qualityMin,
 		ExcludeUnsupported:      true,
 		IncludeMonitoringFailed: includeMonitoringFailed,
+		AddressFamilies:         p2p.ReachableAddressFamilies(),
+		BandwidthMin:            bandwidthMin,
+		UptimeMin:               uptimeMin,
+		PricePerHourMax:         pricePerHourMax,
+		PricePerGiBMax:          pricePerGiBMax,
+		SortBy:                  sortBy,
 	})
 	if err != nil {
 		c.Error(apierror.Internal("Proposal query failed: "+err.Error(), contract.ErrCodeProposalsQuery))
@@ -173,6 +222,18 @@ func (pe *proposalsEndpoint) List(c *gin.Context) {
 	utils.WriteAsJSON(proposalsRes, c.Writer)
 }
 
+// parseWei parses an optional non-negative amount of wei.
+func parseWei(value string) (*big.Int, bool) {
+	if value == "" {
+		return nil, true
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, false
+	}
+	return amount, true
+}
+
 // swagger:operation GET /proposals/countries Countries listCountries
 // ---
 // summary: Returns number of proposals per country
@@ -393,6 +454,65 @@ func (pe *proposalsEndpoint) FilterPresets(c *gin.Context) {
 	utils.WriteAsJSON(presetsRes, c.Writer)
 }
 
+// swagger:operation POST /proposals/ping Proposal pingProposals
+// ---
+// summary: Pings providers
+// description: Measures round trip time to given providers before connecting. Results are sorted by latency, unreachable providers go last.
+// parameters:
+//   - in: body
+//     name: body
+//     description: Providers to ping
+//     schema:
+//       $ref: "#/definitions/ProposalsPingRequest"
+// responses:
+//   200:
+//     description: Measured latencies
+//     schema:
+//       "$ref": "#/definitions/ProposalsPingResponse"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (pe *proposalsEndpoint) Ping(c *gin.Context) {
+	var req contract.ProposalsPingRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+	if pe.latencyMeasurer == nil {
+		c.Error(apierror.Internal("Latency measurement is not available", contract.ErrCodeProposalsPing))
+		return
+	}
+
+	proposals, err := pe.proposalRepository.Proposals(&proposal.Filter{
+		ServiceType:        req.ServiceType,
+		ProviderIDs:        req.ProviderIDs,
+		ExcludeUnsupported: true,
+		AddressFamilies:    p2p.ReachableAddressFamilies(),
+	})
+	if err != nil {
+		c.Error(apierror.Internal("Proposal query failed: "+err.Error(), contract.ErrCodeProposalsQuery))
+		return
+	}
+
+	measured := pe.latencyMeasurer.Measure(c.Request.Context(), proposals)
+	measured = proposal.SortByMeasuredLatency(measured)
+
+	res := contract.ProposalsPingResponse{Results: make([]contract.ProposalPingResult, 0, len(measured))}
+	for _, p := range measured {
+		res.Results = append(res.Results, contract.NewProposalPingResult(p))
+	}
+	utils.WriteAsJSON(res, c.Writer)
+}
+
 // AddRoutesForProposals attaches proposals endpoints to router
 func AddRoutesForProposals(
 	proposalRepository proposalRepository,
@@ -400,14 +520,16 @@ func AddRoutesForProposals(
 	locationResolver location.Resolver,
 	filterPresetRepository proposal.FilterPresetRepository,
 	natProber natProber,
+	latencyMeasurer latencyMeasurer,
 ) func(*gin.Engine) error {
-	pe := NewProposalsEndpoint(proposalRepository, pricer, locationResolver, filterPresetRepository, natProber)
+	pe := NewProposalsEndpoint(proposalRepository, pricer, locationResolver, filterPresetRepository, natProber, latencyMeasurer)
 	return func(e *gin.Engine) error {
 		proposalGroup := e.Group("/proposals")
 		{
 			proposalGroup.GET("", pe.List)
 			proposalGroup.GET("/filter-presets", pe.FilterPresets)
 			proposalGroup.GET("/countries", pe.Countries)
+			proposalGroup.POST("/ping", pe.Ping)
 		}
 
 		e.GET("/prices/current", pe.CurrentPrice)
@@ -35,6 +35,7 @@ import (
 	"github.com/mysteriumnetwork/node/market"
 	"github.com/mysteriumnetwork/node/mocks"
 	"github.com/mysteriumnetwork/node/nat"
+	"github.com/mysteriumnetwork/node/p2p"
 )
 
 var TestLocation = market.Location{ASN: 123, Country: "Lithuania", City: "Vilnius"}
@@ -116,7 +117,7 @@ func TestProposalsEndpointListByNodeId(t *testing.T) {
 	req.URL.RawQuery = query.Encode()
 
 	resp := httptest.NewRecorder()
-	endpoint := NewProposalsEndpoint(repository, nil, nil, &mockFilterPresetRepository{}, mockedNATProber)
+	endpoint := NewProposalsEndpoint(repository, nil, nil, &mockFilterPresetRepository{}, mockedNATProber, nil)
 	g := gin.Default()
 	g.GET(path, endpoint.List)
 	g.ServeHTTP(resp, req)
@@ -166,6 +167,7 @@ func TestProposalsEndpointListByNodeId(t *testing.T) {
 		ProviderID:         "0xProviderId",
 		ExcludeUnsupported: true,
 		CompatibilityMin:   2,
+		AddressFamilies:    p2p.ReachableAddressFamilies(),
 	}, repository.recordedFilter)
 }
 
@@ -187,7 +189,7 @@ func TestProposalsEndpointAcceptsAccessPolicyParams(t *testing.T) {
 	req.URL.RawQuery = query.Encode()
 
 	resp := httptest.NewRecorder()
-	endpoint := NewProposalsEndpoint(repository, nil, nil, &mockFilterPresetRepository{}, mockedNATProber)
+	endpoint := NewProposalsEndpoint(repository, nil, nil, &mockFilterPresetRepository{}, mockedNATProber, nil)
 
 	g := gin.Default()
 	g.GET(path, endpoint.List)
@@ -239,6 +241,7 @@ func TestProposalsEndpointAcceptsAccessPolicyParams(t *testing.T) {
 			AccessPolicySource: "accessPolicySource",
 			ExcludeUnsupported: true,
 			CompatibilityMin:   2,
+			AddressFamilies:    p2p.ReachableAddressFamilies(),
 		},
 		repository.recordedFilter,
 	)
@@ -263,7 +266,7 @@ func TestCurrentPrices(t *testing.T) {
 			PricePerHour: big.NewInt(123_000_000_000_000_000),
 			PricePerGiB:  big.NewInt(456_000_000_000_000_000),
 		},
-	}, &mockResolver{}, presetRepository, mockedNATProber)
+	}, &mockResolver{}, presetRepository, mockedNATProber, nil)
 
 	path := "/prices/current"
 	req, err := http.NewRequest(
@@ -324,7 +327,7 @@ func TestProposalsEndpointFilterByPresetID(t *testing.T) {
 			},
 		}},
 	}
-	endpoint := NewProposalsEndpoint(repository, nil, nil, presetRepository, mockedNATProber)
+	endpoint := NewProposalsEndpoint(repository, nil, nil, presetRepository, mockedNATProber, nil)
 	g := gin.Default()
 	g.GET(path, endpoint.List)
 	g.ServeHTTP(resp, req)
@@ -0,0 +1,164 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/rules"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type rulesEngine interface {
+	Rules() []rules.Rule
+	Actions() []string
+	Add(rule rules.Rule) (rules.Rule, error)
+	Remove(id string) error
+}
+
+type rulesAPI struct {
+	engine rulesEngine
+}
+
+func newRulesAPI(engine rulesEngine) *rulesAPI {
+	return &rulesAPI{engine: engine}
+}
+
+// List returns configured automation rules.
+// swagger:operation GET /rules Rules listRules
+// ---
+// summary: Returns automation rules
+// description: Returns configured automation rules together with supported triggers and actions
+// responses:
+//   200:
+//     description: List of rules
+//     schema:
+//       "$ref": "#/definitions/RuleListResponse"
+func (api *rulesAPI) List(c *gin.Context) {
+	res := contract.RuleListResponse{
+		Rules:    []contract.RuleDTO{},
+		Triggers: rules.Triggers(),
+		Actions:  api.engine.Actions(),
+	}
+	sort.Strings(res.Actions)
+	for _, rule := range api.engine.Rules() {
+		res.Rules = append(res.Rules, contract.NewRuleDTO(rule))
+	}
+	utils.WriteAsJSON(res, c.Writer)
+}
+
+// Create creates a new automation rule.
+// swagger:operation POST /rules Rules createRule
+// ---
+// summary: Creates automation rule
+// description: Creates rule running an action once trigger fires and all conditions hold for the event
+// parameters:
+//   - in: body
+//     name: body
+//     description: Rule to create
+//     schema:
+//       $ref: "#/definitions/RuleCreateRequest"
+// responses:
+//   201:
+//     description: Rule created
+//     schema:
+//       "$ref": "#/definitions/RuleDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   422:
+//     description: Unable to process the request at this point
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *rulesAPI) Create(c *gin.Context) {
+	var req contract.RuleCreateRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	rule, err := api.engine.Add(req.ToRule())
+	if err != nil {
+		c.Error(apierror.Unprocessable("Could not create rule: "+err.Error(), contract.ErrCodeRulesStore))
+		return
+	}
+
+	c.Status(http.StatusCreated)
+	utils.WriteAsJSON(contract.NewRuleDTO(rule), c.Writer)
+}
+
+// Delete removes automation rule.
+// swagger:operation DELETE /rules/{id} Rules deleteRule
+// ---
+// summary: Removes automation rule
+// parameters:
+//   - name: id
+//     in: path
+//     description: Rule ID
+//     type: string
+//     required: true
+// responses:
+//   204:
+//     description: Rule removed
+//   404:
+//     description: Rule not found
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *rulesAPI) Delete(c *gin.Context) {
+	err := api.engine.Remove(c.Param("id"))
+	if errors.Is(err, rules.ErrRuleNotFound) {
+		c.Error(apierror.NotFound("Rule not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Could not remove rule: "+err.Error(), contract.ErrCodeRulesStore))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddRoutesForRules registers automation rules routes.
+func AddRoutesForRules(engine rulesEngine) func(*gin.Engine) error {
+	api := newRulesAPI(engine)
+	return func(e *gin.Engine) error {
+		g := e.Group("/rules")
+		{
+			g.GET("", api.List)
+			g.POST("", api.Create)
+			g.DELETE("/:id", api.Delete)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/rules"
+)
+
+type memoryRuleStorage struct {
+	rules []rules.Rule
+}
+
+func (s *memoryRuleStorage) Store(rule rules.Rule) error {
+	s.rules = append(s.rules, rule)
+	return nil
+}
+
+func (s *memoryRuleStorage) List() ([]rules.Rule, error) {
+	return s.rules, nil
+}
+
+func (s *memoryRuleStorage) Delete(_ rules.Rule) error {
+	return nil
+}
+
+func Test_Rules_CreateListDelete(t *testing.T) {
+	engine := rules.NewEngine(&memoryRuleStorage{}, map[string]rules.Executor{
+		rules.ActionReconnect: func(map[string]string, rules.Event) error { return nil },
+	})
+	router := summonTestGin()
+	assert.NoError(t, AddRoutesForRules(engine)(router))
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/rules", strings.NewReader(`{"name":"reconnect","trigger":"unknown","action":{"type":"reconnect"}}`))
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	resp = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/rules", strings.NewReader(`{"name":"reconnect","trigger":"connection_lost","action":{"type":"reconnect"}}`))
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Len(t, engine.Rules(), 1)
+	assert.True(t, engine.Rules()[0].Enabled)
+
+	resp = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/rules", nil)
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"trigger":"connection_lost"`)
+
+	resp = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodDelete, "/rules/"+engine.Rules()[0].ID, nil)
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Len(t, engine.Rules(), 0)
+
+	resp = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodDelete, "/rules/missing", nil)
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mysteriumnetwork/node/core/schedule"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type scheduler interface {
+	Status() schedule.Status
+}
+
+type scheduleAPI struct {
+	scheduler scheduler
+}
+
+// Status returns provider schedule state.
+// swagger:operation GET /schedule Schedule scheduleStatus
+// ---
+// summary: Returns provider schedule state
+// description: Returns whether services are paused by the external signal feed, the last feed value and mapping rules
+// responses:
+//   200:
+//     description: Schedule state
+//     schema:
+//       "$ref": "#/definitions/ScheduleStatusDTO"
+func (api *scheduleAPI) Status(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewScheduleStatusDTO(api.scheduler.Status()), c.Writer)
+}
+
+// AddRoutesForSchedule registers provider schedule routes.
+func AddRoutesForSchedule(scheduler scheduler) func(*gin.Engine) error {
+	api := &scheduleAPI{scheduler: scheduler}
+	return func(e *gin.Engine) error {
+		e.GET("/schedule", api.Status)
+		return nil
+	}
+}
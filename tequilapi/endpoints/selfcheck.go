@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package endpoints
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/monitoring/selfcheck"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+const (
+	defaultSelfCheckHours = 24
+	maxSelfCheckHours     = 90 * 24
+)
+
+type selfCheckAgent interface {
+	Statuses() []selfcheck.Status
+	History(since time.Time) (selfcheck.Uptime, error)
+}
+
+type selfCheckAPI struct {
+	agent selfCheckAgent
+}
+
+// SelfCheck returns self-check state of running services and uptime history.
+// swagger:operation GET /node/self-check Node nodeSelfCheck
+// ---
+// summary: Returns provider self-check results
+// description: Returns the latest self-check state of running services and the history of self-checks run by a test consumer identity
+// parameters:
+//   - in: query
+//     name: hours
+//     description: Number of hours of history, 24 by default
+//     type: integer
+//
+// responses:
+//   200:
+//     description: Self-check results
+//     schema:
+//       "$ref": "#/definitions/SelfCheckDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *selfCheckAPI) SelfCheck(c *gin.Context) {
+	hours := defaultSelfCheckHours
+	if query := c.Query("hours"); query != "" {
+		n, err := strconv.Atoi(query)
+		if err != nil || n < 1 || n > maxSelfCheckHours {
+			c.Error(apierror.BadRequest("Invalid number of hours", contract.ErrCodeNodeSelfCheck))
+			return
+		}
+		hours = n
+	}
+
+	uptime, err := api.agent.History(time.Now().Add(-time.Duration(hours) * time.Hour))
+	if err != nil {
+		c.Error(apierror.Internal("Could not get self-check history: "+err.Error(), contract.ErrCodeNodeSelfCheck))
+		return
+	}
+	utils.WriteAsJSON(contract.NewSelfCheckDTO(api.agent.Statuses(), uptime), c.Writer)
+}
+
+// AddRoutesForSelfCheck registers provider self-check routes.
+func AddRoutesForSelfCheck(agent selfCheckAgent) func(*gin.Engine) error {
+	api := &selfCheckAPI{agent: agent}
+	return func(e *gin.Engine) error {
+		e.GET("/node/self-check", api.SelfCheck)
+		return nil
+	}
+}
@@ -187,6 +187,9 @@ func (se *ServiceEndpoint) ServiceStart(c *gin.Context) {
 	if err == service.ErrorLocation {
 		c.Error(apierror.Unprocessable("Cannot detect location", contract.ErrCodeServiceLocation))
 		return
+	} else if err == service.ErrIdentityLocked {
+		c.Error(apierror.Unprocessable("Provider identity is locked", contract.ErrCodeIDLocked))
+		return
 	} else if err != nil {
 		c.Error(apierror.Internal("Cannot start service: "+err.Error(), contract.ErrCodeServiceStart))
 		return
@@ -246,12 +249,29 @@ func (se *ServiceEndpoint) ServiceStop(c *gin.Context) {
 
 func (se *ServiceEndpoint) updateActiveServicesInUserConfig() {
 	runningInstances := se.serviceManager.List(false)
-	activeServices := make([]string, len(runningInstances))
-	for i, service := range runningInstances {
-		activeServices[i] = service.Type
+	activeServices := make([]string, 0, len(runningInstances))
+	bindings := make([]string, 0, len(runningInstances))
+	providers := make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, service := range runningInstances {
+		if !seen[service.Type] {
+			activeServices = append(activeServices, service.Type)
+			seen[service.Type] = true
+		}
+		binding := service.Type + "=" + service.ProviderID.Address
+		if !seen[binding] {
+			bindings = append(bindings, binding)
+			seen[binding] = true
+		}
+		providers[service.ProviderID.Address] = true
+	}
+	// Services run under the main identity unless several identities are in use.
+	if len(providers) < 2 {
+		bindings = []string{}
 	}
 	config := map[string]interface{}{
-		config.FlagActiveServices.Name: strings.Join(activeServices, ","),
+		config.FlagActiveServices.Name:    strings.Join(activeServices, ","),
+		config.FlagServiceIdentities.Name: bindings,
 	}
 	se.tequilaApiClient.SetConfig(config)
 }
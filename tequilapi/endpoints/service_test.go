@@ -84,7 +84,10 @@ type fancyServiceOptions struct {
 
 type mockServiceManager struct{}
 
-func (sm *mockServiceManager) Start(_ identity.Identity, serviceType string, _ []string, _ service.Options) (service.ID, error) {
+func (sm *mockServiceManager) Start(providerID identity.Identity, serviceType string, _ []string, _ service.Options) (service.ID, error) {
+	if providerID.Address == "0xlocked" {
+		return "", service.ErrIdentityLocked
+	}
 	if serviceType == serviceTypeWithAccessPolicy {
 		return mockAccessPolicyServiceID, nil
 	}
@@ -368,6 +371,28 @@ func Test_ServiceStartAlreadyRunning(t *testing.T) {
 	assert.Equal(t, "err_service_running", apierror.Parse(resp.Result()).Err.Code)
 }
 
+func Test_ServiceStart_IdentityLocked(t *testing.T) {
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/services",
+		strings.NewReader(`{
+			"type": "testprotocol",
+			"provider_id": "0xlocked",
+			"options": {}
+		}`),
+	)
+	resp := httptest.NewRecorder()
+
+	g := summonTestGin()
+	err := AddRoutesForService(&mockServiceManager{}, fakeOptionsParser, &mockProposalRepository{}, nil)(g)
+	assert.NoError(t, err)
+
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Equal(t, "err_id_locked", apierror.Parse(resp.Result()).Err.Code)
+}
+
 func Test_ServiceStatus_NotFoundIsReturnedWhenNotStarted(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/services/1", nil)
 	resp := httptest.NewRecorder()
@@ -0,0 +1,57 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/mysteriumnetwork/node/session/accounting"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type sessionAccountingReporter interface {
+	Report() accounting.Report
+}
+
+type sessionAccountingAPI struct {
+	reporter sessionAccountingReporter
+}
+
+// Report returns reconciliation of tunnel and kernel traffic counters of provider sessions.
+// swagger:operation GET /sessions/accounting Session sessionAccounting
+// ---
+// summary: Returns provider session traffic reconciliation
+// description: Compares traffic of active provider sessions reported by tunnels with traffic counted by session tagged firewall rules
+// responses:
+//   200:
+//     description: Session accounting report
+//     schema:
+//       "$ref": "#/definitions/SessionAccountingReportDTO"
+func (api *sessionAccountingAPI) Report(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewSessionAccountingReportDTO(api.reporter.Report()), c.Writer)
+}
+
+// AddRoutesForSessionAccounting registers provider session accounting routes.
+func AddRoutesForSessionAccounting(reporter sessionAccountingReporter) func(*gin.Engine) error {
+	api := &sessionAccountingAPI{reporter: reporter}
+	return func(e *gin.Engine) error {
+		e.GET("/sessions/accounting", api.Report)
+		return nil
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/service"
+	"github.com/mysteriumnetwork/node/session/notice"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type noticeSender interface {
+	Send(sessionID string, message string) error
+	Broadcast(message string) (int, error)
+}
+
+type noticeStorage interface {
+	List() []notice.Notice
+}
+
+type sessionNoticeEndpoint struct {
+	sender  noticeSender
+	storage noticeStorage
+}
+
+// swagger:operation POST /sessions/notice Session sessionNotice
+// ---
+// summary: Pushes notice to consumers
+// description: Sends short human-readable notice to consumer of the given session or to all connected consumers
+// parameters:
+//   - in: body
+//     name: body
+//     description: Notice to send
+//     schema:
+//       $ref: "#/definitions/SessionNoticeRequest"
+// responses:
+//   200:
+//     description: Notice sent
+//     schema:
+//       "$ref": "#/definitions/SessionNoticeResponse"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   404:
+//     description: Session not found
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   429:
+//     description: Notices are sent too often
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (e *sessionNoticeEndpoint) Send(c *gin.Context) {
+	var req contract.SessionNoticeRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if req.SessionID == "" {
+		sent, err := e.sender.Broadcast(req.Message)
+		if err != nil {
+			c.Error(apierror.Internal("Failed to send notice: "+err.Error(), contract.ErrCodeSessionNotice))
+			return
+		}
+		utils.WriteAsJSON(contract.SessionNoticeResponse{Sent: sent}, c.Writer)
+		return
+	}
+
+	err := e.sender.Send(req.SessionID, req.Message)
+	switch {
+	case err == nil:
+		utils.WriteAsJSON(contract.SessionNoticeResponse{Sent: 1}, c.Writer)
+	case errors.Is(err, service.ErrorSessionNotExists):
+		c.Error(apierror.NotFound("Session not found"))
+	case errors.Is(err, notice.ErrRateLimited):
+		c.Error(apierror.Error(http.StatusTooManyRequests, "Notices are sent too often", contract.ErrCodeSessionNotice))
+	default:
+		c.Error(apierror.Internal("Failed to send notice: "+err.Error(), contract.ErrCodeSessionNotice))
+	}
+}
+
+// swagger:operation GET /connection/notices Connection connectionNotices
+// ---
+// summary: Returns provider notices
+// description: Returns the latest notices received from providers, newest first
+// responses:
+//   200:
+//     description: List of notices
+//     schema:
+//       "$ref": "#/definitions/ListNoticesResponse"
+func (e *sessionNoticeEndpoint) List(c *gin.Context) {
+	res := contract.ListNoticesResponse{Items: []contract.NoticeDTO{}}
+	for _, n := range e.storage.List() {
+		res.Items = append(res.Items, contract.NewNoticeDTO(n))
+	}
+	utils.WriteAsJSON(res, c.Writer)
+}
+
+// AddRoutesForSessionNotices attaches session notice endpoints to router.
+func AddRoutesForSessionNotices(sender noticeSender, storage noticeStorage) func(*gin.Engine) error {
+	e := &sessionNoticeEndpoint{
+		sender:  sender,
+		storage: storage,
+	}
+	return func(g *gin.Engine) error {
+		g.POST("/sessions/notice", e.Send)
+		g.GET("/connection/notices", e.List)
+		return nil
+	}
+}
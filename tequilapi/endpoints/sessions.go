@@ -50,7 +50,7 @@ func NewSessionsEndpoint(sessionStorage sessionStorage) *sessionsEndpoint {
 // swagger:operation GET /sessions Session sessionList
 // ---
 // summary: Returns sessions history
-// description: Returns list of sessions history filtered by given query
+// description: Returns list of sessions history filtered by given query. When grouping is requested, totals and groups of all filtered sessions are computed as well.
 // responses:
 //   200:
 //     description: List of sessions
@@ -85,6 +85,9 @@ func (endpoint *sessionsEndpoint) List(c *gin.Context) {
 	}
 
 	sessionsDTO := contract.NewSessionListResponse(sessions, p)
+	if query.GroupBy != nil {
+		sessionsDTO = sessionsDTO.WithAggregates(session.AggregateSessions(sessionsAll, *query.GroupBy))
+	}
 	utils.WriteAsJSON(sessionsDTO, c.Writer)
 }
 
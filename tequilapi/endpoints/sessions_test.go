@@ -20,6 +20,7 @@ package endpoints
 import (
 	"encoding/json"
 	"errors"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -150,6 +151,51 @@ func Test_SessionsEndpoint_ListRespectsFilters(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.Code)
 }
 
+func Test_SessionsEndpoint_ListGrouped(t *testing.T) {
+	path := "/sessions"
+	provided := connectionSessionMock
+	provided.Direction = session.DirectionProvided
+	provided.DataSent = 1 << 30
+	provided.Tokens = big.NewInt(1000)
+	ssm := &sessionStorageMock{
+		sessionsToReturn: []session.History{provided, connectionSessionMock},
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, path+"?direction=provided&group_by=day&page_size=1", nil)
+	resp := httptest.NewRecorder()
+	g := summonTestGin()
+	g.GET(path, NewSessionsEndpoint(ssm).List)
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, session.NewFilter().SetDirection(session.DirectionProvided), ssm.calledWithFilter)
+
+	parsedResponse := contract.SessionListResponse{}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &parsedResponse))
+	assert.Len(t, parsedResponse.Items, 1)
+	if assert.NotNil(t, parsedResponse.Totals) {
+		assert.Equal(t, 2, parsedResponse.Totals.Count)
+		assert.Equal(t, 1.0, parsedResponse.Totals.SumGiB)
+		assert.Equal(t, "1000", parsedResponse.Totals.Earnings.Wei)
+		assert.Equal(t, "0", parsedResponse.Totals.Spendings.Wei)
+	}
+	if assert.Len(t, parsedResponse.Groups, 1) {
+		assert.Equal(t, "2010-01-01", parsedResponse.Groups[0].Key)
+		assert.Equal(t, 2, parsedResponse.Groups[0].Count)
+	}
+}
+
+func Test_SessionsEndpoint_ListRejectsUnknownGrouping(t *testing.T) {
+	path := "/sessions"
+	req, _ := http.NewRequest(http.MethodGet, path+"?group_by=country", nil)
+	resp := httptest.NewRecorder()
+	g := summonTestGin()
+	g.GET(path, NewSessionsEndpoint(&sessionStorageMock{}).List)
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
 func Test_SessionsEndpoint_ListBubblesError(t *testing.T) {
 	path := "/sessions"
 	req, err := http.NewRequest(
@@ -34,6 +34,8 @@ import (
 	"github.com/mysteriumnetwork/node/core/state/event"
 	stateEvent "github.com/mysteriumnetwork/node/core/state/event"
 	"github.com/mysteriumnetwork/node/eventbus"
+	"github.com/mysteriumnetwork/node/monitoring/resources"
+	"github.com/mysteriumnetwork/node/session/notice"
 	"github.com/mysteriumnetwork/node/session/pingpong"
 	"github.com/mysteriumnetwork/node/tequilapi/contract"
 )
@@ -54,6 +56,12 @@ const (
 	ServiceStatusEvent EventType = "service-status"
 	// StateChangeEvent represents the state change
 	StateChangeEvent EventType = "state-change"
+	// ProviderNoticeEvent represents notice received from provider
+	ProviderNoticeEvent EventType = "provider-notice"
+	// ConnectionWarningEvent represents a problem noticed with an established connection
+	ConnectionWarningEvent EventType = "connection-warning"
+	// ResourceThresholdEvent represents resource usage crossing a configured threshold
+	ResourceThresholdEvent EventType = "resource-threshold"
 )
 
 // Handler represents an sse handler
@@ -91,6 +99,18 @@ func (h *Handler) Subscribe(bus eventbus.Subscriber) error {
 		return err
 	}
 	err = bus.Subscribe(stateEvent.AppTopicState, h.ConsumeStateEvent)
+	if err != nil {
+		return err
+	}
+	err = bus.Subscribe(notice.AppTopicNoticeReceived, h.ConsumeNoticeEvent)
+	if err != nil {
+		return err
+	}
+	err = bus.Subscribe(connectionstate.AppTopicLocationMismatch, h.ConsumeLocationMismatchEvent)
+	if err != nil {
+		return err
+	}
+	err = bus.Subscribe(resources.AppTopicThreshold, h.ConsumeResourceThresholdEvent)
 	return err
 }
 
@@ -233,6 +253,34 @@ type consumerStateRes struct {
 }
 
 func mapState(state stateEvent.State) stateRes {
+	channelsRes := make([]contract.PaymentChannelDTO, len(state.ProviderChannels))
+	for idx, channel := range state.ProviderChannels {
+		channelsRes[idx] = contract.NewPaymentChannelDTO(channel)
+	}
+
+	sessionsRes := make([]contract.SessionDTO, len(state.Sessions))
+	sessionsStats := session.NewStats()
+	for idx, se := range state.Sessions {
+		sessionsRes[idx] = contract.NewSessionDTO(se)
+		sessionsStats.Add(se)
+	}
+
+	conn := currentConnection(state)
+
+	res := stateRes{
+		Services:      state.Services,
+		Sessions:      sessionsRes,
+		SessionsStats: contract.NewSessionStatsDTO(sessionsStats),
+		Consumer: consumerStateRes{
+			Connection: contract.NewConnectionDTO(conn.Session, conn.Statistics, conn.Throughput, conn.Invoice),
+		},
+		Identities: mapIdentities(state),
+		Channels:   channelsRes,
+	}
+	return res
+}
+
+func mapIdentities(state stateEvent.State) []contract.IdentityDTO {
 	identitiesRes := make([]contract.IdentityDTO, len(state.Identities))
 	for idx, identity := range state.Identities {
 		stake := new(big.Int)
@@ -256,19 +304,11 @@ func mapState(state stateEvent.State) stateRes {
 			EarningsPerHermes:   contract.NewEarningsPerHermesDTO(identity.EarningsPerHermes),
 		}
 	}
+	return identitiesRes
+}
 
-	channelsRes := make([]contract.PaymentChannelDTO, len(state.ProviderChannels))
-	for idx, channel := range state.ProviderChannels {
-		channelsRes[idx] = contract.NewPaymentChannelDTO(channel)
-	}
-
-	sessionsRes := make([]contract.SessionDTO, len(state.Sessions))
-	sessionsStats := session.NewStats()
-	for idx, se := range state.Sessions {
-		sessionsRes[idx] = contract.NewSessionDTO(se)
-		sessionsStats.Add(se)
-	}
-
+// currentConnection returns the consumer connection reported to UIs.
+func currentConnection(state stateEvent.State) stateEvent.Connection {
 	conn := event.Connection{Session: connectionstate.Status{State: connectionstate.NotConnected}}
 
 	for k, c := range state.Connections {
@@ -280,18 +320,7 @@ func mapState(state stateEvent.State) stateRes {
 			break
 		}
 	}
-
-	res := stateRes{
-		Services:      state.Services,
-		Sessions:      sessionsRes,
-		SessionsStats: contract.NewSessionStatsDTO(sessionsStats),
-		Consumer: consumerStateRes{
-			Connection: contract.NewConnectionDTO(conn.Session, conn.Statistics, conn.Throughput, conn.Invoice),
-		},
-		Identities: identitiesRes,
-		Channels:   channelsRes,
-	}
-	return res
+	return conn
 }
 
 func identityChannel(address string, channels []pingpong.HermesChannel) *pingpong.HermesChannel {
@@ -311,3 +340,27 @@ func (h *Handler) ConsumeStateEvent(event stateEvent.State) {
 		Payload: mapState(event),
 	})
 }
+
+// ConsumeLocationMismatchEvent consumes the exit location mismatch event
+func (h *Handler) ConsumeLocationMismatchEvent(e connectionstate.AppEventLocationMismatch) {
+	h.send(Event{
+		Type:    ConnectionWarningEvent,
+		Payload: contract.NewLocationMismatchWarningDTO(e),
+	})
+}
+
+// ConsumeResourceThresholdEvent consumes the resource threshold breach and recovery event
+func (h *Handler) ConsumeResourceThresholdEvent(e resources.AppEventThreshold) {
+	h.send(Event{
+		Type:    ResourceThresholdEvent,
+		Payload: contract.NewResourceThresholdDTO(e),
+	})
+}
+
+// ConsumeNoticeEvent consumes the provider notice event
+func (h *Handler) ConsumeNoticeEvent(event notice.AppEventNoticeReceived) {
+	h.send(Event{
+		Type:    ProviderNoticeEvent,
+		Payload: contract.NewNoticeDTO(event.Notice),
+	})
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/statesync"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type stateSyncer interface {
+	Status() statesync.Status
+	Sync(ctx context.Context) error
+}
+
+type stateSyncAPI struct {
+	syncer stateSyncer
+}
+
+// Status returns the outcome of the last state sync and spending of all devices.
+// swagger:operation GET /state-sync StateSync stateSyncStatus
+// ---
+// summary: Returns state sync status
+// description: Returns the outcome of the last sync of profiles and filter presets, and spending summaries of all devices of the consumer
+// responses:
+//   200:
+//     description: State sync status
+//     schema:
+//       "$ref": "#/definitions/StateSyncStatusDTO"
+func (api *stateSyncAPI) Status(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewStateSyncStatusDTO(api.syncer.Status()), c.Writer)
+}
+
+// Sync synchronizes state with the remote storage.
+// swagger:operation POST /state-sync StateSync stateSyncNow
+// ---
+// summary: Synchronizes state now
+// description: Merges local profiles, filter presets and spending with the remote state and uploads the result
+// responses:
+//   200:
+//     description: State sync status
+//     schema:
+//       "$ref": "#/definitions/StateSyncStatusDTO"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *stateSyncAPI) Sync(c *gin.Context) {
+	if err := api.syncer.Sync(c.Request.Context()); err != nil {
+		c.Error(apierror.Internal("Could not sync state: "+err.Error(), contract.ErrCodeStateSync))
+		return
+	}
+	utils.WriteAsJSON(contract.NewStateSyncStatusDTO(api.syncer.Status()), c.Writer)
+}
+
+// AddRoutesForStateSync registers state sync routes.
+func AddRoutesForStateSync(syncer stateSyncer) func(*gin.Engine) error {
+	api := &stateSyncAPI{syncer: syncer}
+	return func(e *gin.Engine) error {
+		e.GET("/state-sync", api.Status)
+		e.POST("/state-sync", api.Sync)
+		return nil
+	}
+}
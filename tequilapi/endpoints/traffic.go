@@ -0,0 +1,95 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/session/accounting"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+const (
+	defaultTrafficDays = 30
+	maxTrafficDays     = 366
+)
+
+type trafficMeter interface {
+	Totals() accounting.TrafficTotals
+	History(days int) ([]accounting.DailyTraffic, error)
+}
+
+type trafficAPI struct {
+	meter trafficMeter
+}
+
+// Traffic returns traffic of all provider sessions.
+// swagger:operation GET /sessions/traffic Session sessionTraffic
+// ---
+// summary: Returns provider traffic totals
+// description: Returns traffic of all provider sessions in the current day and billing month, the monthly traffic cap and daily history
+// parameters:
+//   - in: query
+//     name: days
+//     description: Number of days of daily history, 30 by default
+//     type: integer
+//
+// responses:
+//   200:
+//     description: Provider traffic
+//     schema:
+//       "$ref": "#/definitions/TrafficDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *trafficAPI) Traffic(c *gin.Context) {
+	days := defaultTrafficDays
+	if query := c.Query("days"); query != "" {
+		n, err := strconv.Atoi(query)
+		if err != nil || n < 1 || n > maxTrafficDays {
+			c.Error(apierror.BadRequest("Invalid number of days", contract.ErrCodeSessionTraffic))
+			return
+		}
+		days = n
+	}
+
+	daily, err := api.meter.History(days)
+	if err != nil {
+		c.Error(apierror.Internal("Could not get provider traffic: "+err.Error(), contract.ErrCodeSessionTraffic))
+		return
+	}
+	utils.WriteAsJSON(contract.NewTrafficDTO(api.meter.Totals(), daily), c.Writer)
+}
+
+// AddRoutesForTraffic registers provider traffic routes.
+func AddRoutesForTraffic(meter trafficMeter) func(*gin.Engine) error {
+	api := &trafficAPI{meter: meter}
+	return func(e *gin.Engine) error {
+		e.GET("/sessions/traffic", api.Traffic)
+		return nil
+	}
+}
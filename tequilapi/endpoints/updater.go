@@ -0,0 +1,136 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/updater"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type nodeUpdater interface {
+	Status() updater.Status
+	Check(channel updater.Channel) (updater.Status, error)
+	Update(channel updater.Channel) error
+}
+
+type updaterAPI struct {
+	updater nodeUpdater
+}
+
+// Status returns the state of the last node update check or attempt.
+// swagger:operation GET /node/update Node nodeUpdateStatus
+// ---
+// summary: Returns node update state
+// description: Returns the state of the last node update check or attempt
+// responses:
+//   200:
+//     description: Node update state
+//     schema:
+//       "$ref": "#/definitions/NodeUpdateStatusDTO"
+func (api *updaterAPI) Status(c *gin.Context) {
+	utils.WriteAsJSON(contract.NewNodeUpdateStatusDTO(api.updater.Status()), c.Writer)
+}
+
+// Update checks for a newer node release and installs it.
+// swagger:operation POST /node/update Node nodeUpdate
+// ---
+// summary: Checks for and installs node update
+// description: Checks the release channel for a newer release. Unless only checking, downloads the release binary, verifies its signature, replaces the running one and restarts the node. The previous binary is restored if the updated node fails to start or to pass the health check.
+// parameters:
+//   - in: body
+//     name: body
+//     schema:
+//       $ref: "#/definitions/NodeUpdateRequest"
+// responses:
+//   200:
+//     description: Update check finished
+//     schema:
+//       "$ref": "#/definitions/NodeUpdateStatusDTO"
+//   202:
+//     description: Update started
+//     schema:
+//       "$ref": "#/definitions/NodeUpdateStatusDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   409:
+//     description: Update is already in progress
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   422:
+//     description: Unable to process the request at this point
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *updaterAPI) Update(c *gin.Context) {
+	var req contract.NodeUpdateRequest
+	if c.Request.ContentLength != 0 {
+		if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+			c.Error(apierror.ParseFailed())
+			return
+		}
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Validated above, empty channel stands for the configured one.
+	channel, _ := updater.ParseChannel(req.Channel)
+
+	if req.CheckOnly {
+		status, err := api.updater.Check(channel)
+		if err != nil {
+			c.Error(updateError(err))
+			return
+		}
+		utils.WriteAsJSON(contract.NewNodeUpdateStatusDTO(status), c.Writer)
+		return
+	}
+
+	if err := api.updater.Update(channel); err != nil {
+		c.Error(updateError(err))
+		return
+	}
+	utils.WriteAsJSON(contract.NewNodeUpdateStatusDTO(api.updater.Status()), c.Writer, http.StatusAccepted)
+}
+
+func updateError(err error) *apierror.APIError {
+	if errors.Is(err, updater.ErrUpdateInProgress) {
+		return apierror.Conflict("Node update is already in progress", contract.ErrCodeNodeUpdate, "update")
+	}
+	return apierror.Unprocessable("Could not update node: "+err.Error(), contract.ErrCodeNodeUpdate)
+}
+
+// AddRoutesForUpdater registers node update routes.
+func AddRoutesForUpdater(nodeUpdater nodeUpdater) func(*gin.Engine) error {
+	api := &updaterAPI{updater: nodeUpdater}
+	return func(e *gin.Engine) error {
+		e.GET("/node/update", api.Status)
+		e.POST("/node/update", api.Update)
+		return nil
+	}
+}
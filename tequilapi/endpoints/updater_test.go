@@ -0,0 +1,91 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/updater"
+)
+
+type mockNodeUpdater struct {
+	status  updater.Status
+	err     error
+	checked updater.Channel
+	updated updater.Channel
+}
+
+func (m *mockNodeUpdater) Status() updater.Status {
+	return m.status
+}
+
+func (m *mockNodeUpdater) Check(channel updater.Channel) (updater.Status, error) {
+	m.checked = channel
+	return m.status, m.err
+}
+
+func (m *mockNodeUpdater) Update(channel updater.Channel) error {
+	m.updated = channel
+	return m.err
+}
+
+func Test_NodeUpdate(t *testing.T) {
+	status := updater.Status{State: updater.StateAvailable, Channel: updater.ChannelBeta, CurrentVersion: "1.0.0", LatestVersion: "1.1.0-rc1"}
+	statusJSON := `{"state":"available","channel":"beta","current_version":"1.0.0","latest_version":"1.1.0-rc1"}`
+
+	for name, test := range map[string]struct {
+		body     string
+		err      error
+		code     int
+		response string
+		checked  updater.Channel
+		updated  updater.Channel
+	}{
+		"check only": {body: `{"channel":"beta","check_only":true}`, code: http.StatusOK, response: statusJSON, checked: updater.ChannelBeta},
+		"update":     {body: `{"channel":"beta"}`, code: http.StatusAccepted, response: statusJSON, updated: updater.ChannelBeta},
+		"no body":    {code: http.StatusAccepted, response: statusJSON},
+		"in progress": {
+			body: `{}`, err: updater.ErrUpdateInProgress, code: http.StatusConflict,
+		},
+		"no signing key": {
+			body: `{}`, err: updater.ErrNoPublicKey, code: http.StatusUnprocessableEntity,
+		},
+		"unknown channel": {body: `{"channel":"nightly"}`, code: http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			nodeUpdater := &mockNodeUpdater{status: status, err: test.err}
+			router := summonTestGin()
+			assert.NoError(t, AddRoutesForUpdater(nodeUpdater)(router))
+
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/node/update", strings.NewReader(test.body)))
+
+			assert.Equal(t, test.code, resp.Code)
+			if test.response != "" {
+				assert.JSONEq(t, test.response, resp.Body.String())
+			}
+			assert.Equal(t, test.checked, nodeUpdater.checked)
+			assert.Equal(t, test.updated, nodeUpdater.updated)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/eventbus/webhook"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+	"github.com/mysteriumnetwork/node/tequilapi/utils"
+)
+
+type webhookStorage interface {
+	List() ([]webhook.Webhook, error)
+	Add(w webhook.Webhook) (webhook.Webhook, error)
+	Delete(id string) error
+}
+
+type webhooksAPI struct {
+	webhooks webhookStorage
+}
+
+// List returns registered webhooks.
+// swagger:operation GET /config/webhooks Configuration listWebhooks
+// ---
+// summary: Returns registered webhooks
+// responses:
+//   200:
+//     description: List of webhooks
+//     schema:
+//       "$ref": "#/definitions/WebhookListDTO"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *webhooksAPI) List(c *gin.Context) {
+	webhooks, err := api.webhooks.List()
+	if err != nil {
+		c.Error(apierror.Internal("Could not list webhooks: "+err.Error(), contract.ErrCodeWebhookList))
+		return
+	}
+	utils.WriteAsJSON(contract.NewWebhookListDTO(webhooks), c.Writer)
+}
+
+// Create registers a webhook.
+// swagger:operation POST /config/webhooks Configuration createWebhook
+// ---
+// summary: Registers a webhook
+// description: Selected events are posted to the webhook as JSON, signed with HMAC-SHA256 of "<X-Myst-Timestamp>.<body>" in X-Myst-Signature header. Failed deliveries are retried.
+// parameters:
+//   - in: body
+//     name: body
+//     description: Webhook
+//     schema:
+//       $ref: "#/definitions/WebhookRequest"
+// responses:
+//   201:
+//     description: Webhook registered
+//     schema:
+//       "$ref": "#/definitions/WebhookDTO"
+//   400:
+//     description: Failed to parse or request validation failed
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *webhooksAPI) Create(c *gin.Context) {
+	var req contract.WebhookRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.Error(apierror.ParseFailed())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	w, err := api.webhooks.Add(req.Webhook())
+	if err != nil {
+		c.Error(apierror.Internal("Could not save webhook: "+err.Error(), contract.ErrCodeWebhookSave))
+		return
+	}
+
+	dto := contract.NewWebhookDTO(w)
+	dto.Secret = w.Secret
+	utils.WriteAsJSON(dto, c.Writer, http.StatusCreated)
+}
+
+// Delete removes a webhook.
+// swagger:operation DELETE /config/webhooks/{id} Configuration deleteWebhook
+// ---
+// summary: Removes a webhook
+// parameters:
+//   - name: id
+//     in: path
+//     description: Webhook ID
+//     type: string
+//     required: true
+// responses:
+//   204:
+//     description: Webhook removed
+//   404:
+//     description: Webhook not found
+//     schema:
+//       "$ref": "#/definitions/APIError"
+//   500:
+//     description: Internal server error
+//     schema:
+//       "$ref": "#/definitions/APIError"
+func (api *webhooksAPI) Delete(c *gin.Context) {
+	err := api.webhooks.Delete(c.Param("id"))
+	if errors.Is(err, webhook.ErrNotFound) {
+		c.Error(apierror.NotFound("Webhook not found"))
+		return
+	}
+	if err != nil {
+		c.Error(apierror.Internal("Could not remove webhook: "+err.Error(), contract.ErrCodeWebhookDelete))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddRoutesForWebhooks attaches webhook configuration endpoints to router.
+func AddRoutesForWebhooks(webhooks webhookStorage) func(*gin.Engine) error {
+	api := &webhooksAPI{webhooks: webhooks}
+	return func(e *gin.Engine) error {
+		g := e.Group("/config/webhooks")
+		{
+			g.GET("", api.List)
+			g.POST("", api.Create)
+			g.DELETE("/:id", api.Delete)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/eventbus/webhook"
+)
+
+type mockWebhookStorage struct {
+	webhooks map[string]webhook.Webhook
+}
+
+func (m *mockWebhookStorage) List() ([]webhook.Webhook, error) {
+	list := make([]webhook.Webhook, 0, len(m.webhooks))
+	for _, w := range m.webhooks {
+		list = append(list, w)
+	}
+	return list, nil
+}
+
+func (m *mockWebhookStorage) Add(w webhook.Webhook) (webhook.Webhook, error) {
+	w.ID = "1"
+	if w.Secret == "" {
+		w.Secret = "generated"
+	}
+	m.webhooks[w.ID] = w
+	return w, nil
+}
+
+func (m *mockWebhookStorage) Delete(id string) error {
+	if _, ok := m.webhooks[id]; !ok {
+		return webhook.ErrNotFound
+	}
+	delete(m.webhooks, id)
+	return nil
+}
+
+func TestWebhooks(t *testing.T) {
+	g := summonTestGin()
+	storage := &mockWebhookStorage{webhooks: map[string]webhook.Webhook{}}
+	assert.NoError(t, AddRoutesForWebhooks(storage)(g))
+
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/config/webhooks", strings.NewReader(
+		`{"url": "https://hooks.example.com/myst", "events": ["session_started", "service_stopped"]}`,
+	)))
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.JSONEq(t, `{"id": "1", "url": "https://hooks.example.com/myst", "events": ["session_started", "service_stopped"], "secret": "generated"}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/config/webhooks", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"webhooks": [{"id": "1", "url": "https://hooks.example.com/myst", "events": ["session_started", "service_stopped"]}]}`, resp.Body.String())
+
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/config/webhooks/1", nil))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "/config/webhooks/1", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestWebhooks_Validation(t *testing.T) {
+	g := summonTestGin()
+	assert.NoError(t, AddRoutesForWebhooks(&mockWebhookStorage{webhooks: map[string]webhook.Webhook{}})(g))
+
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/config/webhooks", strings.NewReader(
+		`{"url": "ftp://hooks.example.com", "events": ["session_started", "unknown"]}`,
+	)))
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	fields := apierror.Parse(resp.Result()).Err.Fields
+	assert.Contains(t, fields, "url")
+	assert.Contains(t, fields, "events[1]")
+}
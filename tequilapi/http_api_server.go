@@ -18,6 +18,8 @@
 package tequilapi
 
 import (
+	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
 	"strings"
@@ -123,7 +125,17 @@ func (server *apiServer) StartServing() {
 }
 
 func (server *apiServer) serve() {
-	server.errorChannel <- http.Serve(server.listener, server.gin)
+	httpServer := &http.Server{
+		Handler: server.gin,
+		ConnContext: func(ctx context.Context, conn net.Conn) context.Context {
+			// Only the remote management listener serves TLS.
+			if _, ok := conn.(*tls.Conn); ok {
+				return middlewares.MarkRemote(ctx)
+			}
+			return ctx
+		},
+	}
+	server.errorChannel <- httpServer.Serve(server.listener)
 }
 
 func extractBoundAddress(listener net.Listener) (string, error) {
@@ -17,7 +17,10 @@
 
 package tequilapi
 
-import "net"
+import (
+	"net"
+	"sync"
+)
 
 // NewListener returns tequilapi listener.
 func NewListener(network, address string) (net.Listener, error) {
@@ -43,3 +46,77 @@ func (n noopListener) Close() error {
 func (n noopListener) Addr() net.Addr {
 	return nil
 }
+
+// NewMultiListener returns a listener accepting connections of all given listeners.
+// Its address is the address of the first listener.
+func NewMultiListener(listeners ...net.Listener) net.Listener {
+	l := &multiListener{
+		listeners: listeners,
+		conns:     make(chan net.Conn),
+		errs:      make(chan error),
+		closed:    make(chan struct{}),
+	}
+	for _, listener := range listeners {
+		go l.accept(listener)
+	}
+	return l
+}
+
+type multiListener struct {
+	listeners []net.Listener
+	conns     chan net.Conn
+	errs      chan error
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *multiListener) accept(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case l.errs <- err:
+			case <-l.closed:
+				return
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+
+		select {
+		case l.conns <- conn:
+		case <-l.closed:
+			conn.Close()
+			return
+		}
+	}
+}
+
+func (l *multiListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case err := <-l.errs:
+		return nil, err
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *multiListener) Close() (err error) {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		for _, listener := range l.listeners {
+			if closeErr := listener.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
+}
+
+func (l *multiListener) Addr() net.Addr {
+	return l.listeners[0].Addr()
+}
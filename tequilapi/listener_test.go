@@ -0,0 +1,53 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package tequilapi
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiListener(t *testing.T) {
+	first, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	second, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	listener := NewMultiListener(first, second)
+	assert.Equal(t, first.Addr(), listener.Addr())
+
+	for _, addr := range []net.Addr{first.Addr(), second.Addr()} {
+		client, err := net.Dial("tcp", addr.String())
+		require.NoError(t, err)
+		defer client.Close()
+
+		conn, err := listener.Accept()
+		require.NoError(t, err)
+		assert.Equal(t, client.LocalAddr().String(), conn.RemoteAddr().String())
+		conn.Close()
+	}
+
+	require.NoError(t, listener.Close())
+	_, err = listener.Accept()
+	assert.Error(t, err)
+	_, err = net.Dial("tcp", second.Addr().String())
+	assert.Error(t, err)
+}
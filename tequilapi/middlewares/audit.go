@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/core/auth"
+)
+
+type auditRecorder interface {
+	Record(entry auth.AuditEntry) error
+}
+
+// NewAuditor returns middleware recording state changing requests, and requests rejected
+// by authorization or rate limits, into the audit log. It must run before other middlewares
+// to see their results.
+func NewAuditor(recorder auditRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := responseStatus(c)
+		if !audited(c.Request.Method, status) {
+			return
+		}
+
+		entry := auth.AuditEntry{
+			Time:     start,
+			Actor:    Actor(c),
+			RemoteIP: RemoteIP(c.Request),
+			Remote:   IsRemote(c.Request),
+			Method:   c.Request.Method,
+			Route:    c.FullPath(),
+			Path:     c.Request.URL.Path,
+			Status:   status,
+			Duration: time.Since(start),
+		}
+		if err := recorder.Record(entry); err != nil {
+			log.Error().Err(err).Msgf("Failed to record audit entry of %s %s", entry.Method, entry.Path)
+		}
+	}
+}
+
+func audited(method string, status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// responseStatus returns the status of the response, including errors not yet written by the error handler.
+func responseStatus(c *gin.Context) int {
+	if c.Writer.Written() || len(c.Errors) == 0 {
+		return c.Writer.Status()
+	}
+
+	var apiErr *apierror.APIError
+	if errors.As(c.Errors[0].Err, &apiErr) {
+		return apiErr.Status
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mysteriumnetwork/node/core/auth"
+)
+
+type mockAuditRecorder struct {
+	entries []auth.AuditEntry
+}
+
+func (m *mockAuditRecorder) Record(entry auth.AuditEntry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func TestAuditor(t *testing.T) {
+	recorder := &mockAuditRecorder{}
+	tokens := mockTokenAuthorizer{"connector": {auth.ScopeConnect}}
+
+	g := gin.New()
+	g.Use(apierror.ErrorHandler)
+	g.Use(NewAuditor(recorder), NewScopeAuthorizer(tokens, mockJWTValidator{token: "session"}, true))
+	g.GET("/proposals", func(c *gin.Context) { c.Status(http.StatusOK) })
+	g.PUT("/connection", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	g.DELETE("/connection", func(c *gin.Context) {
+		c.Error(apierror.BadRequest("no connection", "err_no_connection_exists"))
+	})
+	g.POST("/services", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(method, path, token string) {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		g.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	request(http.MethodGet, "/proposals", "connector")
+	request(http.MethodPut, "/connection", "connector")
+	request(http.MethodDelete, "/connection", "session")
+	request(http.MethodPost, "/services", "connector")
+	request(http.MethodGet, "/proposals", "")
+
+	require.Len(t, recorder.entries, 4)
+
+	assert.Equal(t, "token:connector", recorder.entries[0].Actor)
+	assert.Equal(t, "10.0.0.1", recorder.entries[0].RemoteIP)
+	assert.Equal(t, http.MethodPut, recorder.entries[0].Method)
+	assert.Equal(t, "/connection", recorder.entries[0].Route)
+	assert.Equal(t, http.StatusCreated, recorder.entries[0].Status)
+
+	assert.Equal(t, "ui", recorder.entries[1].Actor)
+	assert.Equal(t, http.StatusBadRequest, recorder.entries[1].Status)
+
+	assert.Equal(t, "token:connector", recorder.entries[2].Actor)
+	assert.Equal(t, "/services", recorder.entries[2].Path)
+	assert.Equal(t, http.StatusForbidden, recorder.entries[2].Status)
+
+	assert.Equal(t, "", recorder.entries[3].Actor)
+	assert.Equal(t, http.MethodGet, recorder.entries[3].Method)
+	assert.Equal(t, http.StatusUnauthorized, recorder.entries[3].Status)
+}
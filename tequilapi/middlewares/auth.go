@@ -0,0 +1,190 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+
+	"github.com/mysteriumnetwork/node/core/auth"
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+// publicRoutes are reachable without any token. Token issuance and rotation are guarded by the UI password instead.
+var publicRoutes = map[string]bool{
+	"GET /healthcheck":             true,
+	"GET /healthz":                 true,
+	"GET /readyz":                  true,
+	"POST /auth/authenticate":      true,
+	"POST /auth/login":             true,
+	"DELETE /auth/logout":          true,
+	"POST /auth/tokens":            true,
+	"POST /auth/tokens/:id/rotate": true,
+}
+
+// routeScopes override scopes of individual routes.
+var routeScopes = map[string]auth.Scope{
+	"GET /auth/tokens":                          auth.ScopeAdmin,
+	"GET /audit":                                auth.ScopeAdmin,
+	"GET /config/webhooks":                      auth.ScopeAdmin,
+	"DELETE /auth/tokens/:id":                   auth.ScopeAdmin,
+	"GET /identities-mnemonic":                  auth.ScopeAdmin,
+	"GET /mmn/api-key":                          auth.ScopeAdmin,
+	"GET /debug/pprof/":                         auth.ScopeAdmin,
+	"GET /debug/pprof/:profile":                 auth.ScopeAdmin,
+	"PUT /identities/current":                   auth.ScopeConnect,
+	"PUT /identities/:id/unlock":                auth.ScopeConnect,
+	"POST /proposals/ping":                      auth.ScopeConnect,
+	"POST /identities/:id/register":             auth.ScopePayments,
+	"POST /identities/:id/beneficiary":          auth.ScopePayments,
+	"PUT /identities/:id/payout-address":        auth.ScopePayments,
+	"PUT /identities/:id/balance/refresh":       auth.ScopePayments,
+	"POST /identities/:id/migrate-hermes":       auth.ScopePayments,
+	"POST /v2/identities/:id/:gw/payment-order": auth.ScopePayments,
+}
+
+// prefixScopes assign scopes to state changing requests by route prefix. Other state changing requests require admin scope.
+var prefixScopes = []struct {
+	prefix string
+	scope  auth.Scope
+}{
+	{prefix: "/connection", scope: auth.ScopeConnect},
+	{prefix: "/transactor/", scope: auth.ScopePayments},
+	{prefix: "/v2/transactor/", scope: auth.ScopePayments},
+}
+
+// RequiredScope returns the scope required to access a route, or false if the route is public.
+func RequiredScope(method, route string) (auth.Scope, bool) {
+	key := method + " " + route
+	if route == "" || method == http.MethodOptions || publicRoutes[key] {
+		return "", false
+	}
+	if scope, ok := routeScopes[key]; ok {
+		return scope, true
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return auth.ScopeRead, true
+	}
+	for _, p := range prefixScopes {
+		if strings.HasPrefix(route, p.prefix) {
+			return p.scope, true
+		}
+	}
+	return auth.ScopeAdmin, true
+}
+
+type remoteRequestKey struct{}
+
+// MarkRemote marks the context of connections accepted by the remote management listener.
+// Requests over such connections always require a token.
+func MarkRemote(ctx context.Context) context.Context {
+	return context.WithValue(ctx, remoteRequestKey{}, true)
+}
+
+// IsRemote checks if the request was received by the remote management listener.
+func IsRemote(req *http.Request) bool {
+	remote, _ := req.Context().Value(remoteRequestKey{}).(bool)
+	return remote
+}
+
+type tokenAuthorizer interface {
+	Authorize(secret string) (auth.APIToken, error)
+}
+
+type jwtValidator interface {
+	ValidateToken(token string) (bool, error)
+}
+
+// NewScopeAuthorizer returns middleware enforcing token scopes on API routes.
+// Tokens issued by UI authentication grant admin scope. Requests without a token
+// are rejected if required is set or they are remote, otherwise they are let through.
+func NewScopeAuthorizer(tokens tokenAuthorizer, jwt jwtValidator, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope, protected := RequiredScope(c.Request.Method, c.FullPath())
+		if !protected {
+			return
+		}
+
+		token, ok := requestToken(c)
+		if !ok {
+			c.Error(apierror.Unauthorized())
+			c.Abort()
+			return
+		}
+		if token == "" {
+			if required || IsRemote(c.Request) {
+				c.Error(apierror.Unauthorized())
+				c.Abort()
+			}
+			return
+		}
+
+		granted, actor, ok := grantedScopes(token, tokens, jwt)
+		if !ok {
+			c.Error(apierror.Unauthorized())
+			c.Abort()
+			return
+		}
+		c.Set(actorKey, actor)
+		if !auth.Allows(granted, scope) {
+			c.Error(apierror.Forbidden("Token does not grant "+string(scope)+" scope", contract.ErrCodeAuthScope))
+			c.Abort()
+			return
+		}
+	}
+}
+
+func grantedScopes(token string, tokens tokenAuthorizer, jwt jwtValidator) ([]auth.Scope, string, bool) {
+	if apiToken, err := tokens.Authorize(token); err == nil {
+		return apiToken.Scopes, "token:" + apiToken.ID, true
+	}
+	if valid, err := jwt.ValidateToken(token); err == nil && valid {
+		return []auth.Scope{auth.ScopeAdmin}, "ui", true
+	}
+	return nil, "", false
+}
+
+const actorKey = "tequilapi.actor"
+
+// Actor returns who made the request: "ui" for UI sessions, "token:<id>" for API tokens,
+// or an empty string if the request was not authenticated.
+func Actor(c *gin.Context) string {
+	return c.GetString(actorKey)
+}
+
+// requestToken returns a bearer token from the Authorization header or the UI cookie.
+// It returns false if the Authorization header is malformed.
+func requestToken(c *gin.Context) (string, bool) {
+	if header := c.GetHeader("Authorization"); header != "" {
+		parts := strings.Fields(header)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+			return "", false
+		}
+		return parts[1], true
+	}
+
+	token, err := c.Cookie(auth.JWTCookieName)
+	if err != nil {
+		return "", true
+	}
+	return token, true
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mysteriumnetwork/node/core/auth"
+)
+
+type mockTokenAuthorizer map[string][]auth.Scope
+
+func (m mockTokenAuthorizer) Authorize(secret string) (auth.APIToken, error) {
+	scopes, ok := m[secret]
+	if !ok {
+		return auth.APIToken{}, auth.ErrUnauthorized
+	}
+	return auth.APIToken{ID: secret, Scopes: scopes}, nil
+}
+
+type mockJWTValidator struct {
+	token string
+}
+
+func (m mockJWTValidator) ValidateToken(token string) (bool, error) {
+	if token != m.token {
+		return false, auth.ErrUnauthorized
+	}
+	return true, nil
+}
+
+func TestRequiredScope(t *testing.T) {
+	for _, tc := range []struct {
+		method, route string
+		scope         auth.Scope
+		protected     bool
+	}{
+		{method: http.MethodGet, route: "/healthcheck", protected: false},
+		{method: http.MethodPost, route: "/auth/tokens", protected: false},
+		{method: http.MethodGet, route: "", protected: false},
+		{method: http.MethodGet, route: "/auth/tokens", scope: auth.ScopeAdmin, protected: true},
+		{method: http.MethodGet, route: "/proposals", scope: auth.ScopeRead, protected: true},
+		{method: http.MethodPut, route: "/connection", scope: auth.ScopeConnect, protected: true},
+		{method: http.MethodPut, route: "/identities/:id/unlock", scope: auth.ScopeConnect, protected: true},
+		{method: http.MethodPost, route: "/transactor/settle/sync", scope: auth.ScopePayments, protected: true},
+		{method: http.MethodPost, route: "/identities/:id/register", scope: auth.ScopePayments, protected: true},
+		{method: http.MethodPost, route: "/services", scope: auth.ScopeAdmin, protected: true},
+	} {
+		t.Run(tc.method+" "+tc.route, func(t *testing.T) {
+			scope, protected := RequiredScope(tc.method, tc.route)
+			assert.Equal(t, tc.scope, scope)
+			assert.Equal(t, tc.protected, protected)
+		})
+	}
+}
+
+func TestScopeAuthorizer(t *testing.T) {
+	tokens := mockTokenAuthorizer{
+		"reader":    {auth.ScopeRead},
+		"connector": {auth.ScopeConnect},
+	}
+	jwt := mockJWTValidator{token: "session"}
+
+	for _, tc := range []struct {
+		name     string
+		required bool
+		method   string
+		path     string
+		header   string
+		status   int
+	}{
+		{name: "public route", required: true, method: http.MethodGet, path: "/healthcheck", status: http.StatusOK},
+		{name: "no token", required: true, method: http.MethodGet, path: "/proposals", status: http.StatusUnauthorized},
+		{name: "no token allowed", required: false, method: http.MethodPost, path: "/services", status: http.StatusOK},
+		{name: "malformed header", required: false, method: http.MethodGet, path: "/proposals", header: "reader", status: http.StatusUnauthorized},
+		{name: "unknown token", required: false, method: http.MethodGet, path: "/proposals", header: "Bearer unknown", status: http.StatusUnauthorized},
+		{name: "read with read scope", required: true, method: http.MethodGet, path: "/proposals", header: "Bearer reader", status: http.StatusOK},
+		{name: "connect with read scope", required: true, method: http.MethodPut, path: "/connection", header: "Bearer reader", status: http.StatusForbidden},
+		{name: "connect with connect scope", required: true, method: http.MethodPut, path: "/connection", header: "Bearer connector", status: http.StatusOK},
+		{name: "read with connect scope", required: true, method: http.MethodGet, path: "/proposals", header: "Bearer connector", status: http.StatusOK},
+		{name: "admin with connect scope", required: false, method: http.MethodPost, path: "/services", header: "Bearer connector", status: http.StatusForbidden},
+		{name: "admin with UI session", required: true, method: http.MethodPost, path: "/services", header: "Bearer session", status: http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gin.New()
+			g.Use(apierror.ErrorHandler)
+			g.Use(NewScopeAuthorizer(tokens, jwt, tc.required))
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			g.GET("/healthcheck", ok)
+			g.GET("/proposals", ok)
+			g.PUT("/connection", ok)
+			g.POST("/services", ok)
+
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			resp := httptest.NewRecorder()
+			g.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.status, resp.Code)
+		})
+	}
+}
+
+func TestScopeAuthorizer_CookieToken(t *testing.T) {
+	g := gin.New()
+	g.Use(apierror.ErrorHandler)
+	g.Use(NewScopeAuthorizer(mockTokenAuthorizer{}, mockJWTValidator{token: "session"}, true))
+	g.POST("/services", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodPost, "/services", nil)
+	req.AddCookie(&http.Cookie{Name: auth.JWTCookieName, Value: "session"})
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestScopeAuthorizer_RemoteRequiresToken(t *testing.T) {
+	g := gin.New()
+	g.Use(apierror.ErrorHandler)
+	g.Use(NewScopeAuthorizer(mockTokenAuthorizer{"reader": {auth.ScopeRead}}, mockJWTValidator{}, false))
+	g.GET("/proposals", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/proposals", nil)
+	req = req.WithContext(MarkRemote(req.Context()))
+	resp := httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	req.Header.Set("Authorization", "Bearer reader")
+	resp = httptest.NewRecorder()
+	g.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
@@ -39,6 +39,11 @@ func NewHostFilter() func(*gin.Context) {
 	whitelist := domain.NewWhitelist(
 		strings.Split(config.GetString(config.FlagTequilapiAllowedHostnames), ","))
 	return func(c *gin.Context) {
+		// Remote requests are token authenticated, which DNS rebinding can not bypass.
+		if IsRemote(c.Request) {
+			return
+		}
+
 		host := c.Request.Host
 		if host == "" {
 			return
@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package middlewares
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"golang.org/x/time/rate"
+
+	"github.com/mysteriumnetwork/node/tequilapi/contract"
+)
+
+// RateLimit limits requests of a single client to matching routes.
+// Method and Route are "*" to match any method or route.
+type RateLimit struct {
+	Method    string
+	Route     string
+	PerMinute int
+}
+
+func (l RateLimit) matches(method, route string) bool {
+	return (l.Method == "*" || l.Method == method) && (l.Route == "*" || l.Route == route)
+}
+
+// ParseRateLimits parses rate limits formatted as "<method> <route>=<requests per minute>",
+// e.g. "POST /auth/login=10" or "* *=600".
+func ParseRateLimits(values []string) ([]RateLimit, error) {
+	limits := make([]RateLimit, 0, len(values))
+	for _, value := range values {
+		endpoint, perMinute, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("rate limit %q: expected <method> <route>=<requests per minute>", value)
+		}
+		parts := strings.Fields(endpoint)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("rate limit %q: expected <method> <route>", value)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(perMinute))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("rate limit %q: requests per minute must be a positive number", value)
+		}
+		limits = append(limits, RateLimit{Method: strings.ToUpper(parts[0]), Route: parts[1], PerMinute: n})
+	}
+	return limits, nil
+}
+
+type rateClient struct {
+	limiters []*rate.Limiter
+	lastSeen time.Time
+}
+
+type rateLimiter struct {
+	limits []RateLimit
+	now    func() time.Time
+
+	mu          sync.Mutex
+	clients     map[string]*rateClient
+	lastCleanup time.Time
+}
+
+// NewRateLimiter returns middleware limiting requests per client. Clients are told apart
+// by the authenticated actor, or by remote IP for requests without a token.
+// Every matching limit is enforced, so wildcard limits cap requests across routes.
+func NewRateLimiter(limits []RateLimit) gin.HandlerFunc {
+	l := &rateLimiter{
+		limits:  limits,
+		now:     time.Now,
+		clients: make(map[string]*rateClient),
+	}
+	return l.handle
+}
+
+func (l *rateLimiter) handle(c *gin.Context) {
+	if len(l.limits) == 0 || c.Request.Method == http.MethodOptions {
+		return
+	}
+
+	if limit, ok := l.allow(clientKey(c), c.Request.Method, c.FullPath()); !ok {
+		c.Header("Retry-After", strconv.Itoa(retryAfter(limit)))
+		c.Error(apierror.Error(http.StatusTooManyRequests, "Too many requests, try again later", contract.ErrCodeRateLimited))
+		c.Abort()
+	}
+}
+
+// allow takes a token from every limit matching the request. It returns the exceeded limit, if any.
+func (l *rateLimiter) allow(client, method, route string) (RateLimit, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.forgetIdle(now)
+
+	rc, ok := l.clients[client]
+	if !ok {
+		rc = &rateClient{limiters: make([]*rate.Limiter, len(l.limits))}
+		l.clients[client] = rc
+	}
+	rc.lastSeen = now
+
+	for i, limit := range l.limits {
+		if !limit.matches(method, route) {
+			continue
+		}
+		if rc.limiters[i] == nil {
+			rc.limiters[i] = rate.NewLimiter(rate.Limit(float64(limit.PerMinute)/60), limit.PerMinute)
+		}
+		if !rc.limiters[i].AllowN(now, 1) {
+			return limit, false
+		}
+	}
+	return RateLimit{}, true
+}
+
+// forgetIdle drops clients not seen for a minute, by which time their buckets are full again.
+func (l *rateLimiter) forgetIdle(now time.Time) {
+	if now.Sub(l.lastCleanup) < time.Minute {
+		return
+	}
+	l.lastCleanup = now
+
+	for key, rc := range l.clients {
+		if now.Sub(rc.lastSeen) > time.Minute {
+			delete(l.clients, key)
+		}
+	}
+}
+
+func clientKey(c *gin.Context) string {
+	if actor := Actor(c); actor != "" {
+		return actor
+	}
+	return "ip:" + RemoteIP(c.Request)
+}
+
+// RemoteIP returns the IP address of the connection the request was received on.
+// Forwarding headers are ignored, as they are set by the client.
+func RemoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+func retryAfter(limit RateLimit) int {
+	seconds := 60 / limit.PerMinute
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
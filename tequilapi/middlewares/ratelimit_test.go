@@ -0,0 +1,97 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mysteriumnetwork/go-rest/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	limits, err := ParseRateLimits([]string{"* *=600", "post /auth/login = 10"})
+	require.NoError(t, err)
+	assert.Equal(t, []RateLimit{
+		{Method: "*", Route: "*", PerMinute: 600},
+		{Method: http.MethodPost, Route: "/auth/login", PerMinute: 10},
+	}, limits)
+
+	for _, value := range []string{"POST /auth/login", "/auth/login=10", "POST /auth/login=0", "POST /auth/login=many"} {
+		_, err := ParseRateLimits([]string{value})
+		assert.Error(t, err, value)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	g := gin.New()
+	g.Use(apierror.ErrorHandler)
+	g.Use(NewRateLimiter([]RateLimit{
+		{Method: "*", Route: "*", PerMinute: 5},
+		{Method: http.MethodPost, Route: "/auth/login", PerMinute: 2},
+	}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	g.GET("/proposals", ok)
+	g.POST("/auth/login", ok)
+
+	request := func(method, path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = remoteAddr
+		resp := httptest.NewRecorder()
+		g.ServeHTTP(resp, req)
+		return resp
+	}
+
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "/auth/login", "10.0.0.1:1000").Code)
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "/auth/login", "10.0.0.1:1001").Code)
+	resp := request(http.MethodPost, "/auth/login", "10.0.0.1:1002")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "30", resp.Header().Get("Retry-After"))
+
+	// Other routes are only limited by the wildcard limit.
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "/proposals", "10.0.0.1:1003").Code)
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "/proposals", "10.0.0.1:1004").Code)
+	assert.Equal(t, http.StatusTooManyRequests, request(http.MethodGet, "/proposals", "10.0.0.1:1005").Code)
+
+	// Other clients have their own limits.
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "/auth/login", "10.0.0.2:1000").Code)
+}
+
+func TestRateLimiter_ForgetsIdleClients(t *testing.T) {
+	now := time.Now()
+	l := &rateLimiter{
+		limits:  []RateLimit{{Method: "*", Route: "*", PerMinute: 1}},
+		now:     func() time.Time { return now },
+		clients: make(map[string]*rateClient),
+	}
+
+	_, ok := l.allow("ip:10.0.0.1", http.MethodGet, "/proposals")
+	assert.True(t, ok)
+	_, ok = l.allow("ip:10.0.0.1", http.MethodGet, "/proposals")
+	assert.False(t, ok)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = l.allow("ip:10.0.0.2", http.MethodGet, "/proposals")
+	assert.True(t, ok)
+	assert.Len(t, l.clients, 1)
+}
@@ -17,23 +17,31 @@
 
 package tequilapi
 
+import "sync"
+
 // NewNoopAPIServer returns noop api server which is used to disable tequilapi HTTP server.
+// Its Wait blocks until the server is stopped, so that a headless node can be waited for like the one serving the API.
 func NewNoopAPIServer() APIServer {
-	return &noopAPIServer{}
+	return &noopAPIServer{stopped: make(chan struct{})}
 }
 
-type noopAPIServer struct{}
+type noopAPIServer struct {
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
 
-func (n noopAPIServer) Wait() error {
+func (n *noopAPIServer) Wait() error {
+	<-n.stopped
 	return nil
 }
 
-func (n noopAPIServer) StartServing() {
+func (n *noopAPIServer) StartServing() {
 }
 
-func (n noopAPIServer) Stop() {
+func (n *noopAPIServer) Stop() {
+	n.stopOnce.Do(func() { close(n.stopped) })
 }
 
-func (n noopAPIServer) Address() (string, error) {
+func (n *noopAPIServer) Address() (string, error) {
 	return "noop", nil
 }
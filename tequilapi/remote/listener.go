@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package remote
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Allowlist restricts remote clients to a set of networks. Empty allowlist allows everyone.
+type Allowlist []*net.IPNet
+
+// ParseAllowlist parses IP addresses and CIDR networks.
+func ParseAllowlist(entries []string) (Allowlist, error) {
+	var allowlist Allowlist
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			allowlist = append(allowlist, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: %w", entry, err)
+		}
+		allowlist = append(allowlist, network)
+	}
+	return allowlist, nil
+}
+
+// Allows checks if the address belongs to one of allowed networks.
+func (a Allowlist) Allows(addr net.Addr) bool {
+	if len(a) == 0 {
+		return true
+	}
+
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, network := range a {
+		if network.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewListener returns a TLS listener of the remote management API, which drops
+// connections from networks outside the allowlist before the TLS handshake.
+func NewListener(address string, cfg *tls.Config, allowlist Allowlist) (net.Listener, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	return tls.NewListener(&filteredListener{Listener: listener, allowlist: allowlist}, cfg), nil
+}
+
+type filteredListener struct {
+	net.Listener
+	allowlist Allowlist
+}
+
+func (l *filteredListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.allowlist.Allows(conn.RemoteAddr()) {
+			return conn, nil
+		}
+
+		log.Warn().Msgf("Rejected remote management connection from %s", conn.RemoteAddr())
+		conn.Close()
+	}
+}
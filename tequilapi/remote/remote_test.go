@@ -0,0 +1,130 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package remote
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSConfig_SelfSignedIsPersisted(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, fingerprint, err := TLSConfig(Options{DataDir: dir, Hosts: []string{"0.0.0.0", "node.local"}})
+	require.NoError(t, err)
+	require.Len(t, cfg.Certificates, 1)
+	assert.Len(t, fingerprint, 95)
+
+	cert, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"localhost", "node.local"}, cert.DNSNames)
+	assert.Len(t, cert.IPAddresses, 1)
+
+	_, again, err := TLSConfig(Options{DataDir: dir})
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, again)
+}
+
+func TestTLSConfig_ClientCA(t *testing.T) {
+	dir := t.TempDir()
+	_, _, err := TLSConfig(Options{DataDir: dir, ClientCAFile: filepath.Join(dir, "missing.pem")})
+	assert.Error(t, err)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0600))
+	_, _, err = TLSConfig(Options{DataDir: dir, ClientCAFile: caFile})
+	assert.Error(t, err)
+
+	certPEM, _, err := generateSelfSigned(nil)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(caFile, certPEM, 0600))
+	cfg, _, err := TLSConfig(Options{DataDir: dir, ClientCAFile: caFile})
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, cfg.ClientAuth)
+}
+
+func TestParseAllowlist(t *testing.T) {
+	allowlist, err := ParseAllowlist([]string{"192.168.1.0/24", " 10.0.0.5 ", "::1", ""})
+	require.NoError(t, err)
+
+	assert.True(t, allowlist.Allows(&net.TCPAddr{IP: net.ParseIP("192.168.1.17")}))
+	assert.True(t, allowlist.Allows(&net.TCPAddr{IP: net.ParseIP("10.0.0.5")}))
+	assert.True(t, allowlist.Allows(&net.TCPAddr{IP: net.ParseIP("::1")}))
+	assert.False(t, allowlist.Allows(&net.TCPAddr{IP: net.ParseIP("10.0.0.6")}))
+	assert.False(t, allowlist.Allows(&net.TCPAddr{IP: net.ParseIP("192.168.2.1")}))
+
+	assert.True(t, Allowlist(nil).Allows(&net.TCPAddr{IP: net.ParseIP("8.8.8.8")}))
+
+	_, err = ParseAllowlist([]string{"10.0.0"})
+	assert.Error(t, err)
+	_, err = ParseAllowlist([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+}
+
+func TestNewListener(t *testing.T) {
+	cfg, fingerprint, err := TLSConfig(Options{DataDir: t.TempDir()})
+	require.NoError(t, err)
+
+	serve := func(allowlist Allowlist) string {
+		listener, err := NewListener("127.0.0.1:0", cfg, allowlist)
+		require.NoError(t, err)
+		t.Cleanup(func() { listener.Close() })
+
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				conn.Write([]byte("ok"))
+				conn.Close()
+			}
+		}()
+		return listener.Addr().String()
+	}
+
+	// Client pins the self-signed certificate by its fingerprint.
+	clientConfig := &tls.Config{
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			assert.Equal(t, fingerprint, Fingerprint(state.PeerCertificates[0].Raw))
+			return nil
+		},
+	}
+
+	allowed, err := ParseAllowlist([]string{"127.0.0.1"})
+	require.NoError(t, err)
+	conn, err := tls.Dial("tcp", serve(allowed), clientConfig)
+	require.NoError(t, err)
+	data, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(data))
+
+	denied, err := ParseAllowlist([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	_, err = tls.Dial("tcp", serve(denied), clientConfig)
+	assert.Error(t, err)
+}
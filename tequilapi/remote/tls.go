@@ -0,0 +1,195 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package remote
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const (
+	selfSignedCertFile = "tequilapi-remote.crt"
+	selfSignedKeyFile  = "tequilapi-remote.key"
+	selfSignedValidity = 10 * 365 * 24 * time.Hour
+	acmeCacheDir       = "tequilapi-acme"
+)
+
+// Options describes the TLS setup of the remote management listener.
+type Options struct {
+	// CertFile and KeyFile point to a custom certificate.
+	CertFile string
+	KeyFile  string
+	// ACMEDomain is the domain to obtain a certificate for via ACME.
+	ACMEDomain string
+	// ClientCAFile enables client certificate authentication when set.
+	ClientCAFile string
+	// DataDir keeps the self-signed certificate and ACME cache.
+	DataDir string
+	// Hosts are the names and IP addresses included in the self-signed certificate.
+	Hosts []string
+}
+
+// TLSConfig returns the TLS configuration of the remote management listener along
+// with the SHA-256 fingerprint of the served certificate, which clients pin when it is
+// self-signed. Fingerprint is empty for ACME certificates, since they get renewed.
+func TLSConfig(opts Options) (*tls.Config, string, error) {
+	var cfg *tls.Config
+	var fingerprint string
+
+	switch {
+	case opts.ACMEDomain != "":
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(opts.ACMEDomain),
+			Cache:      autocert.DirCache(filepath.Join(opts.DataDir, acmeCacheDir)),
+		}
+		cfg = manager.TLSConfig()
+	case opts.CertFile != "" || opts.KeyFile != "":
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not load TLS certificate: %w", err)
+		}
+		cfg = &tls.Config{Certificates: []tls.Certificate{cert}}
+		fingerprint = Fingerprint(cert.Certificate[0])
+	default:
+		cert, err := loadOrCreateSelfSigned(opts.DataDir, opts.Hosts)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not provision self-signed TLS certificate: %w", err)
+		}
+		cfg = &tls.Config{Certificates: []tls.Certificate{cert}}
+		fingerprint = Fingerprint(cert.Certificate[0])
+	}
+	cfg.MinVersion = tls.VersionTLS12
+
+	if opts.ClientCAFile != "" {
+		caPEM, err := os.ReadFile(opts.ClientCAFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, "", errors.New("client CA file contains no certificates")
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return cfg, fingerprint, nil
+}
+
+// Fingerprint returns the SHA-256 fingerprint of a DER encoded certificate, formatted as colon separated hex.
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// loadOrCreateSelfSigned keeps the certificate across restarts, so that its pinned fingerprint stays valid.
+func loadOrCreateSelfSigned(dir string, hosts []string) (tls.Certificate, error) {
+	certPath := filepath.Join(dir, selfSignedCertFile)
+	keyPath := filepath.Join(dir, selfSignedKeyFile)
+
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err == nil {
+		return cert, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return tls.Certificate{}, err
+	}
+
+	certPEM, keyPEM, err := generateSelfSigned(hosts)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return tls.Certificate{}, err
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return tls.Certificate{}, err
+	}
+	if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+func generateSelfSigned(hosts []string) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "Mysterium node", Organization: []string{"Mysterium Network"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	names := append([]string{"localhost", "127.0.0.1"}, hosts...)
+	for _, host := range names {
+		if ip := net.ParseIP(host); ip != nil {
+			if !ip.IsUnspecified() {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			}
+		} else if host != "" {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
@@ -0,0 +1,291 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package devstack
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/mysteriumnetwork/payments/bindings"
+	pc "github.com/mysteriumnetwork/payments/crypto"
+	"github.com/pkg/errors"
+)
+
+const (
+	chainBlockGasLimit = 30_000_000
+	chainTxGasLimit    = 6_721_975
+)
+
+var (
+	// etherBalance is given to every funded account at genesis.
+	etherBalance, _ = new(big.Int).SetString("100000000000000000000", 10)
+	// hermesStake is staked by hermes operator when registering hermes.
+	hermesStake, _ = new(big.Int).SetString("100000000000000000000", 10)
+	// hermesMaxStake is the maximum provider stake accepted by hermes.
+	hermesMaxStake, _ = new(big.Int).SetString("62000000000000000000", 10)
+	// hermesFunds are minted to hermes contract for paying out settlements.
+	hermesFunds, _ = new(big.Int).SetString("1250000000000000000000", 10)
+)
+
+// ContractAddresses holds addresses of payment contracts deployed to the simulated chain.
+type ContractAddresses struct {
+	Myst                  common.Address
+	Registry              common.Address
+	ChannelImplementation common.Address
+	HermesImplementation  common.Address
+	Hermes                common.Address
+}
+
+// Chain is an in-process simulated blockchain with payment contracts deployed,
+// mining a block for every transaction it receives.
+type Chain struct {
+	mu        sync.Mutex
+	backend   *backends.SimulatedBackend
+	chainID   *big.Int
+	deployer  *bind.TransactOpts
+	addresses ContractAddresses
+}
+
+// NewChain starts a simulated chain giving ether to the funded accounts and deploys payment contracts.
+// Hermes is registered by the operator key and announces the given URL.
+func NewChain(funded []common.Address, hermesOperator *ecdsa.PrivateKey, hermesURL string) (*Chain, error) {
+	deployerKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate deployer key")
+	}
+
+	alloc := core.GenesisAlloc{
+		crypto.PubkeyToAddress(deployerKey.PublicKey):    core.GenesisAccount{Balance: etherBalance},
+		crypto.PubkeyToAddress(hermesOperator.PublicKey): core.GenesisAccount{Balance: etherBalance},
+	}
+	for _, address := range funded {
+		alloc[address] = core.GenesisAccount{Balance: etherBalance}
+	}
+
+	backend := backends.NewSimulatedBackend(alloc, chainBlockGasLimit)
+	chainID := backend.Blockchain().Config().ChainID
+	deployer, err := bind.NewKeyedTransactorWithChainID(deployerKey, chainID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create deployer transactor")
+	}
+	deployer.GasLimit = chainTxGasLimit
+
+	c := &Chain{
+		backend:  backend,
+		chainID:  chainID,
+		deployer: deployer,
+	}
+	if err := c.deploy(hermesOperator, hermesURL); err != nil {
+		backend.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// ChainID returns ID of the simulated chain.
+func (c *Chain) ChainID() int64 {
+	return c.chainID.Int64()
+}
+
+// Addresses returns addresses of the deployed payment contracts.
+func (c *Chain) Addresses() ContractAddresses {
+	return c.addresses
+}
+
+// ChannelAddress returns consumer channel address of the identity in devstack hermes.
+func (c *Chain) ChannelAddress(id common.Address) (common.Address, error) {
+	address, err := pc.GenerateChannelAddress(id.Hex(), c.addresses.Hermes.Hex(), c.addresses.Registry.Hex(), c.addresses.ChannelImplementation.Hex())
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.HexToAddress(address), nil
+}
+
+// Close stops the simulated chain.
+func (c *Chain) Close() error {
+	return c.backend.Close()
+}
+
+func (c *Chain) deploy(hermesOperator *ecdsa.PrivateKey, hermesURL string) error {
+	oldToken, tx, _, err := bindings.DeployOldMystToken(c.deployer, c.backend)
+	if err := c.mine(tx, err); err != nil {
+		return errors.Wrap(err, "failed to deploy legacy MYST token")
+	}
+	c.addresses.Myst, tx, _, err = bindings.DeployMystToken(c.deployer, c.backend, oldToken)
+	if err := c.mine(tx, err); err != nil {
+		return errors.Wrap(err, "failed to deploy MYST token")
+	}
+	c.addresses.ChannelImplementation, tx, _, err = bindings.DeployChannelImplementation(c.deployer, c.backend)
+	if err := c.mine(tx, err); err != nil {
+		return errors.Wrap(err, "failed to deploy channel implementation")
+	}
+	c.addresses.HermesImplementation, tx, _, err = bindings.DeployHermesImplementation(c.deployer, c.backend)
+	if err := c.mine(tx, err); err != nil {
+		return errors.Wrap(err, "failed to deploy hermes implementation")
+	}
+	c.addresses.Registry, tx, _, err = bindings.DeployRegistry(c.deployer, c.backend)
+	if err := c.mine(tx, err); err != nil {
+		return errors.Wrap(err, "failed to deploy registry")
+	}
+
+	registry, err := bindings.NewRegistryTransactor(c.addresses.Registry, c.backend)
+	if err != nil {
+		return errors.Wrap(err, "failed to bind registry")
+	}
+	// DEX is not used by the node, so any address will do.
+	dex := common.HexToAddress("0x0000123123123123")
+	tx, err = registry.Initialize(c.deployer, c.addresses.Myst, dex, big.NewInt(0), c.addresses.ChannelImplementation, c.addresses.HermesImplementation, common.Address{})
+	if err := c.mine(tx, err); err != nil {
+		return errors.Wrap(err, "failed to initialize registry")
+	}
+
+	if err := c.registerHermes(hermesOperator, hermesURL); err != nil {
+		return err
+	}
+
+	return c.Mint(c.addresses.Hermes, hermesFunds)
+}
+
+func (c *Chain) registerHermes(operatorKey *ecdsa.PrivateKey, hermesURL string) error {
+	operator, err := bind.NewKeyedTransactorWithChainID(operatorKey, c.chainID)
+	if err != nil {
+		return errors.Wrap(err, "failed to create hermes operator transactor")
+	}
+	operator.GasLimit = chainTxGasLimit
+
+	if err := c.Mint(operator.From, hermesStake); err != nil {
+		return err
+	}
+
+	token, err := bindings.NewMystTokenTransactor(c.addresses.Myst, c.backend)
+	if err != nil {
+		return errors.Wrap(err, "failed to bind MYST token")
+	}
+	tx, err := token.Approve(operator, c.addresses.Registry, hermesStake)
+	if err := c.mine(tx, err); err != nil {
+		return errors.Wrap(err, "failed to approve hermes stake")
+	}
+
+	registry, err := bindings.NewRegistryTransactor(c.addresses.Registry, c.backend)
+	if err != nil {
+		return errors.Wrap(err, "failed to bind registry")
+	}
+	tx, err = registry.RegisterHermes(operator, operator.From, hermesStake, 0, big.NewInt(0), hermesMaxStake, []byte(hermesURL))
+	if err := c.mine(tx, err); err != nil {
+		return errors.Wrap(err, "failed to register hermes")
+	}
+
+	caller, err := bindings.NewRegistryCaller(c.addresses.Registry, c.backend)
+	if err != nil {
+		return errors.Wrap(err, "failed to bind registry")
+	}
+	c.addresses.Hermes, err = caller.GetHermesAddress(&bind.CallOpts{From: operator.From}, operator.From, big.NewInt(0))
+	return errors.Wrap(err, "failed to get hermes address")
+}
+
+// Mint mints MYST tokens to the given address.
+func (c *Chain) Mint(to common.Address, amount *big.Int) error {
+	token, err := bindings.NewMystTokenTransactor(c.addresses.Myst, c.backend)
+	if err != nil {
+		return errors.Wrap(err, "failed to bind MYST token")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx, err := token.Mint(c.deployer, to, amount)
+	return errors.Wrapf(c.mine(tx, err), "failed to mint MYST to %s", to.Hex())
+}
+
+// RegisterIdentity submits identity registration signed by the identity to the registry.
+func (c *Chain) RegisterIdentity(hermesID common.Address, stake, fee *big.Int, beneficiary common.Address, signature []byte) (common.Hash, error) {
+	registry, err := bindings.NewRegistryTransactor(c.addresses.Registry, c.backend)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "failed to bind registry")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx, err := registry.RegisterIdentity(c.deployer, hermesID, stake, fee, beneficiary, signature)
+	if err := c.mine(tx, err); err != nil {
+		return common.Hash{}, errors.Wrap(err, "failed to register identity")
+	}
+	return tx.Hash(), nil
+}
+
+// SettlePromise settles hermes promise issued to the provider.
+func (c *Chain) SettlePromise(provider common.Address, promise pc.Promise) (common.Hash, error) {
+	hermes, err := bindings.NewHermesImplementationTransactor(c.addresses.Hermes, c.backend)
+	if err != nil {
+		return common.Hash{}, errors.Wrap(err, "failed to bind hermes")
+	}
+
+	var preimage [32]byte
+	copy(preimage[:], promise.R)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx, err := hermes.SettlePromise(c.deployer, provider, promise.Amount, promise.Fee, preimage, promise.Signature)
+	if err := c.mine(tx, err); err != nil {
+		return common.Hash{}, errors.Wrap(err, "failed to settle promise")
+	}
+	return tx.Hash(), nil
+}
+
+// mine commits a block with the sent transaction and checks that it succeeded.
+func (c *Chain) mine(tx *types.Transaction, err error) error {
+	if err != nil {
+		return err
+	}
+	c.backend.Commit()
+
+	receipt, err := c.backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return err
+	}
+	if receipt == nil || receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %s reverted", tx.Hash().Hex())
+	}
+	return nil
+}
+
+// Handler returns Ethereum JSON-RPC handler serving the chain.
+func (c *Chain) Handler() (http.Handler, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &ethAPI{chain: c}); err != nil {
+		return nil, err
+	}
+	if err := server.RegisterName("net", &netAPI{chain: c}); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
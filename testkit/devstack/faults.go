@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package devstack
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FaultConfig defines faults injected into requests of a devstack service.
+type FaultConfig struct {
+	// Latency is added before every request is handled.
+	Latency time.Duration
+	// FailureRate is a share of requests, from 0 to 1, answered with 503 Service Unavailable.
+	FailureRate float64
+}
+
+// Validate checks if fault configuration is sane.
+func (fc FaultConfig) Validate() error {
+	if fc.Latency < 0 {
+		return errors.New("latency can not be negative")
+	}
+	if fc.FailureRate < 0 || fc.FailureRate > 1 {
+		return errors.New("failure rate must be between 0 and 1")
+	}
+	return nil
+}
+
+// FaultStats shows configured faults together with request counters of a service.
+type FaultStats struct {
+	FaultConfig
+	Requests uint64
+	Failed   uint64
+}
+
+// Faults injects latency and failures into HTTP requests of a service and counts them.
+type Faults struct {
+	mu       sync.Mutex
+	config   FaultConfig
+	random   *rand.Rand
+	requests uint64
+	failed   uint64
+}
+
+// NewFaults creates fault injector with the given initial configuration.
+func NewFaults(config FaultConfig) *Faults {
+	return &Faults{
+		config: config,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Set replaces injected faults.
+func (f *Faults) Set(config FaultConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.config = config
+	return nil
+}
+
+// Stats returns configured faults and request counters.
+func (f *Faults) Stats() FaultStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return FaultStats{FaultConfig: f.config, Requests: f.requests, Failed: f.failed}
+}
+
+func (f *Faults) next() (time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests++
+	fail := f.config.FailureRate > 0 && f.random.Float64() < f.config.FailureRate
+	if fail {
+		f.failed++
+	}
+	return f.config.Latency, fail
+}
+
+// Wrap injects faults in front of the given handler.
+func (f *Faults) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		latency, fail := f.next()
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fail {
+			http.Error(w, "devstack: injected failure", http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package devstack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaults_Wrap(t *testing.T) {
+	faults := NewFaults(FaultConfig{})
+	handler := faults.Wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve())
+
+	assert.NoError(t, faults.Set(FaultConfig{FailureRate: 1}))
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+
+	assert.NoError(t, faults.Set(FaultConfig{Latency: 20 * time.Millisecond}))
+	started := time.Now()
+	assert.Equal(t, http.StatusOK, serve())
+	assert.True(t, time.Since(started) >= 20*time.Millisecond)
+
+	assert.Equal(t, FaultStats{FaultConfig: FaultConfig{Latency: 20 * time.Millisecond}, Requests: 3, Failed: 1}, faults.Stats())
+}
+
+func TestFaults_SetRejectsInvalidConfig(t *testing.T) {
+	faults := NewFaults(FaultConfig{})
+
+	assert.Error(t, faults.Set(FaultConfig{FailureRate: 1.5}))
+	assert.Error(t, faults.Set(FaultConfig{Latency: -time.Second}))
+	assert.Equal(t, FaultConfig{}, faults.Stats().FaultConfig)
+}
@@ -0,0 +1,394 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package devstack
+
+import (
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gin-gonic/gin"
+	pc "github.com/mysteriumnetwork/payments/crypto"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/identity"
+	"github.com/mysteriumnetwork/node/session/pingpong"
+)
+
+// HermesPath is the path hermes API is served on, the node always talks to hermes at this path.
+const HermesPath = "/api/v2"
+
+type hermesSigner interface {
+	SignHash(a accounts.Account, hash []byte) ([]byte, error)
+}
+
+type hermesConsumer struct {
+	identity common.Address
+	balance  *big.Int
+	latest   pc.Promise
+}
+
+type hermesProvider struct {
+	channelID string
+	promise   pc.Promise
+	settled   *big.Int
+}
+
+// Hermes is a mock hermes which exchanges consumer promises to provider promises 1:1.
+// It keeps consumer balances in memory and does not charge any hermes fee.
+type Hermes struct {
+	chain    *Chain
+	ks       hermesSigner
+	operator common.Address
+
+	mu        sync.Mutex
+	consumers map[common.Address]*hermesConsumer
+	providers map[common.Address]*hermesProvider
+}
+
+// NewHermes creates mock hermes signing promises with the operator key held by the signer.
+func NewHermes(chain *Chain, ks hermesSigner, operator common.Address) *Hermes {
+	return &Hermes{
+		chain:     chain,
+		ks:        ks,
+		operator:  operator,
+		consumers: make(map[common.Address]*hermesConsumer),
+		providers: make(map[common.Address]*hermesProvider),
+	}
+}
+
+// Fund adds the amount to the consumer balance available for promises.
+func (h *Hermes) Fund(id common.Address, amount *big.Int) error {
+	channel, err := h.chain.ChannelAddress(id)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	consumer, ok := h.consumers[channel]
+	if !ok {
+		consumer = &hermesConsumer{
+			identity: id,
+			balance:  new(big.Int),
+			latest:   pc.Promise{ChainID: h.chain.ChainID(), ChannelID: channel.Bytes(), Amount: new(big.Int), Fee: new(big.Int)},
+		}
+		h.consumers[channel] = consumer
+	}
+	consumer.balance.Add(consumer.balance, amount)
+
+	return nil
+}
+
+// settled records a successful settlement of the provider promise.
+func (h *Hermes) settled(provider common.Address, amount *big.Int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if p, ok := h.providers[provider]; ok {
+		p.settled = new(big.Int).Set(amount)
+	}
+}
+
+// Handler returns hermes HTTP API handler.
+func (h *Hermes) Handler() http.Handler {
+	g := gin.New()
+	g.Use(gin.Recovery())
+
+	api := g.Group(HermesPath)
+	api.POST("/request_promise", h.requestPromise)
+	api.POST("/pay_and_settle", h.requestPromise)
+	api.POST("/change_promise_fee", h.changePromiseFee)
+	api.POST("/reveal_r", h.revealR)
+	api.POST("/refresh_promise", h.refreshPromise)
+	api.POST("/provider/sync_promise", h.syncPromise)
+	api.GET("/data/consumer/:id", h.consumerData)
+	api.GET("/data/provider/:id", h.providerData)
+	api.GET("/features", h.features)
+
+	return g
+}
+
+func (h *Hermes) features(c *gin.Context) {
+	c.JSON(http.StatusOK, pingpong.HermesFeatures{SessionKeys: true})
+}
+
+func (h *Hermes) fail(c *gin.Context, status int, err error) {
+	c.JSON(status, gin.H{"cause": err.Error(), "message": err.Error()})
+}
+
+func (h *Hermes) requestPromise(c *gin.Context) {
+	var req pingpong.RequestPromise
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.fail(c, http.StatusBadRequest, pingpong.ErrHermesMalformedJSON)
+		return
+	}
+	em := req.ExchangeMessage
+	if em.Promise.Amount == nil || !common.IsHexAddress(em.Provider) {
+		h.fail(c, http.StatusBadRequest, pingpong.ErrHermesMalformedJSON)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	consumer, ok := h.consumers[common.BytesToAddress(em.Promise.ChannelID)]
+	if !ok {
+		h.fail(c, http.StatusBadRequest, pingpong.ErrConsumerUnregistered)
+		return
+	}
+	if signer, err := em.Promise.RecoverSigner(); err != nil || !h.signedBy(signer, consumer.identity, req.Delegation) {
+		h.fail(c, http.StatusBadRequest, pingpong.ErrHermesInvalidSignature)
+		return
+	}
+
+	diff := new(big.Int).Sub(em.Promise.Amount, consumer.latest.Amount)
+	if diff.Sign() <= 0 {
+		h.fail(c, http.StatusBadRequest, pingpong.ErrHermesPromiseValueTooLow)
+		return
+	}
+	if em.Promise.Amount.Cmp(consumer.balance) > 0 {
+		h.fail(c, http.StatusBadRequest, pingpong.ErrHermesOverspend)
+		return
+	}
+
+	provider := common.HexToAddress(em.Provider)
+	p, err := h.provider(provider)
+	if err != nil {
+		h.fail(c, http.StatusInternalServerError, pingpong.ErrHermesInternal)
+		return
+	}
+
+	fee := req.TransactorFee
+	if fee == nil {
+		fee = new(big.Int)
+	}
+	amount := new(big.Int).Add(p.promise.Amount, diff)
+	promise, err := pc.CreatePromise(p.channelID, h.chain.ChainID(), amount, fee, common.Bytes2Hex(em.Promise.Hashlock), h.ks, h.operator)
+	if err != nil {
+		log.Error().Err(err).Msg("Devstack hermes failed to sign promise")
+		h.fail(c, http.StatusInternalServerError, pingpong.ErrHermesInternal)
+		return
+	}
+
+	consumer.latest = em.Promise
+	p.promise = *promise
+	c.JSON(http.StatusOK, promise)
+}
+
+// signedBy checks that the promise signer is the consumer identity or its delegated session key.
+func (h *Hermes) signedBy(signer, consumer common.Address, delegation *identity.Delegation) bool {
+	if signer == consumer {
+		return true
+	}
+	return delegation != nil &&
+		delegation.Verify(time.Now()) == nil &&
+		common.HexToAddress(delegation.Identity) == consumer &&
+		common.HexToAddress(delegation.SessionKey) == signer
+}
+
+// provider returns provider account, creating it on the first promise.
+func (h *Hermes) provider(id common.Address) (*hermesProvider, error) {
+	if p, ok := h.providers[id]; ok {
+		return p, nil
+	}
+
+	channelID, err := pc.GenerateProviderChannelID(id.Hex(), h.chain.Addresses().Hermes.Hex())
+	if err != nil {
+		return nil, err
+	}
+	p := &hermesProvider{
+		channelID: channelID,
+		promise:   pc.Promise{ChainID: h.chain.ChainID(), ChannelID: common.FromHex(channelID), Amount: new(big.Int), Fee: new(big.Int)},
+		settled:   new(big.Int),
+	}
+	h.providers[id] = p
+
+	return p, nil
+}
+
+func (h *Hermes) changePromiseFee(c *gin.Context) {
+	var req pingpong.SetPromiseFeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.NewFee == nil || req.HermesPromise.Amount == nil {
+		h.fail(c, http.StatusBadRequest, pingpong.ErrHermesMalformedJSON)
+		return
+	}
+
+	old := req.HermesPromise
+	promise, err := pc.CreatePromise(hexutil.Encode(old.ChannelID), old.ChainID, old.Amount, req.NewFee, common.Bytes2Hex(old.Hashlock), h.ks, h.operator)
+	if err != nil {
+		h.fail(c, http.StatusInternalServerError, pingpong.ErrHermesInternal)
+		return
+	}
+	c.JSON(http.StatusOK, promise)
+}
+
+func (h *Hermes) revealR(c *gin.Context) {
+	var req pingpong.RevealObject
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.fail(c, http.StatusBadRequest, pingpong.ErrHermesMalformedJSON)
+		return
+	}
+
+	r, err := hexutil.Decode("0x" + strings.TrimPrefix(req.R, "0x"))
+	if err != nil {
+		h.fail(c, http.StatusBadRequest, pingpong.ErrHermesMalformedJSON)
+		return
+	}
+
+	h.mu.Lock()
+	if p, ok := h.providers[common.HexToAddress(req.Provider)]; ok {
+		p.promise.R = r
+	}
+	h.mu.Unlock()
+
+	c.JSON(http.StatusOK, pingpong.RevealSuccess{Message: "R revealed"})
+}
+
+type hermesRefreshRequest struct {
+	ChainID  int64  `json:"chain_id"`
+	Identity string `json:"identity"`
+	Hashlock string `json:"hashlock"`
+}
+
+func (h *Hermes) refreshPromise(c *gin.Context) {
+	var req hermesRefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.fail(c, http.StatusBadRequest, pingpong.ErrHermesMalformedJSON)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	p, ok := h.providers[common.HexToAddress(req.Identity)]
+	if !ok {
+		h.fail(c, http.StatusNotFound, pingpong.ErrHermesNoPreviousPromise)
+		return
+	}
+
+	promise, err := pc.CreatePromise(p.channelID, h.chain.ChainID(), p.promise.Amount, p.promise.Fee, req.Hashlock, h.ks, h.operator)
+	if err != nil {
+		h.fail(c, http.StatusInternalServerError, pingpong.ErrHermesInternal)
+		return
+	}
+	p.promise = *promise
+	c.JSON(http.StatusOK, promise)
+}
+
+type hermesSyncRequest struct {
+	ChannelID string   `json:"channel_id"`
+	ChainID   int64    `json:"chain_id"`
+	Amount    *big.Int `json:"amount"`
+	Fee       *big.Int `json:"fee"`
+	Hashlock  string   `json:"hashlock"`
+	Signature string   `json:"signature"`
+}
+
+func (h *Hermes) syncPromise(c *gin.Context) {
+	var req hermesSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Amount == nil {
+		h.fail(c, http.StatusBadRequest, pingpong.ErrHermesMalformedJSON)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, p := range h.providers {
+		if strings.EqualFold(strings.TrimPrefix(p.channelID, "0x"), strings.TrimPrefix(req.ChannelID, "0x")) {
+			if req.Amount.Cmp(p.promise.Amount) > 0 {
+				p.promise.Amount = req.Amount
+				p.promise.Fee = req.Fee
+				p.promise.Hashlock = common.FromHex(req.Hashlock)
+				p.promise.Signature = common.FromHex(req.Signature)
+			}
+			c.JSON(http.StatusOK, gin.H{})
+			return
+		}
+	}
+	h.fail(c, http.StatusNotFound, pingpong.ErrHermesNotFound)
+}
+
+func (h *Hermes) consumerData(c *gin.Context) {
+	id := common.HexToAddress(c.Param("id"))
+	channel, err := h.chain.ChannelAddress(id)
+	if err != nil {
+		h.fail(c, http.StatusInternalServerError, pingpong.ErrHermesInternal)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	consumer, ok := h.consumers[channel]
+	if !ok {
+		h.fail(c, http.StatusNotFound, pingpong.ErrHermesNotFound)
+		return
+	}
+
+	info := pingpong.HermesUserInfo{
+		Identity:      id.Hex(),
+		ChannelID:     channel.Hex(),
+		Balance:       new(big.Int).Sub(consumer.balance, consumer.latest.Amount),
+		Settled:       new(big.Int),
+		Stake:         new(big.Int),
+		LatestPromise: latestPromise(consumer.latest),
+	}
+	c.JSON(http.StatusOK, map[int64]pingpong.HermesUserInfo{h.chain.ChainID(): info})
+}
+
+func (h *Hermes) providerData(c *gin.Context) {
+	id := common.HexToAddress(c.Param("id"))
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	p, ok := h.providers[id]
+	if !ok {
+		h.fail(c, http.StatusNotFound, pingpong.ErrHermesNotFound)
+		return
+	}
+
+	info := pingpong.HermesUserInfo{
+		Identity:      id.Hex(),
+		ChannelID:     p.channelID,
+		Balance:       new(big.Int).Sub(p.promise.Amount, p.settled),
+		Settled:       new(big.Int).Set(p.settled),
+		Stake:         new(big.Int),
+		LatestPromise: latestPromise(p.promise),
+	}
+	c.JSON(http.StatusOK, map[int64]pingpong.HermesUserInfo{h.chain.ChainID(): info})
+}
+
+func latestPromise(promise pc.Promise) pingpong.LatestPromise {
+	return pingpong.LatestPromise{
+		ChainID:   promise.ChainID,
+		ChannelID: hexutil.Encode(promise.ChannelID),
+		Amount:    promise.Amount,
+		Fee:       promise.Fee,
+		Hashlock:  hexutil.Encode(promise.Hashlock),
+		Signature: hexutil.Encode(promise.Signature),
+	}
+}
@@ -0,0 +1,275 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package devstack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ethAPI serves the subset of "eth" JSON-RPC namespace used by the node and go-ethereum bindings.
+// Only the latest state is available, historical block numbers are treated as latest.
+type ethAPI struct {
+	chain *Chain
+}
+
+type callArgs struct {
+	From     *common.Address `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      *hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Data     hexutil.Bytes   `json:"data"`
+	Input    hexutil.Bytes   `json:"input"`
+}
+
+func (args callArgs) message() ethereum.CallMsg {
+	msg := ethereum.CallMsg{To: args.To, Data: args.Data}
+	if len(args.Input) > 0 {
+		msg.Data = args.Input
+	}
+	if args.From != nil {
+		msg.From = *args.From
+	}
+	if args.Gas != nil {
+		msg.Gas = uint64(*args.Gas)
+	}
+	if args.GasPrice != nil {
+		msg.GasPrice = args.GasPrice.ToInt()
+	}
+	if args.Value != nil {
+		msg.Value = args.Value.ToInt()
+	}
+	return msg
+}
+
+type filterArgs struct {
+	BlockHash *common.Hash     `json:"blockHash"`
+	FromBlock *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock   *rpc.BlockNumber `json:"toBlock"`
+	Addresses []common.Address `json:"address"`
+	Topics    [][]common.Hash  `json:"topics"`
+}
+
+func blockNumber(number *rpc.BlockNumber) *big.Int {
+	if number == nil || *number < 0 {
+		return nil
+	}
+	return big.NewInt(number.Int64())
+}
+
+func (api *ethAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(api.chain.chainID)
+}
+
+func (api *ethAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.chain.backend.Blockchain().CurrentBlock().NumberU64())
+}
+
+func (api *ethAPI) GetBalance(ctx context.Context, address common.Address, _ rpc.BlockNumber) (*hexutil.Big, error) {
+	balance, err := api.chain.backend.BalanceAt(ctx, address, nil)
+	return (*hexutil.Big)(balance), err
+}
+
+func (api *ethAPI) GetCode(ctx context.Context, address common.Address, _ rpc.BlockNumber) (hexutil.Bytes, error) {
+	return api.chain.backend.CodeAt(ctx, address, nil)
+}
+
+func (api *ethAPI) GetTransactionCount(ctx context.Context, address common.Address, number rpc.BlockNumber) (hexutil.Uint64, error) {
+	var nonce uint64
+	var err error
+	if number == rpc.PendingBlockNumber {
+		nonce, err = api.chain.backend.PendingNonceAt(ctx, address)
+	} else {
+		nonce, err = api.chain.backend.NonceAt(ctx, address, nil)
+	}
+	return hexutil.Uint64(nonce), err
+}
+
+func (api *ethAPI) Call(ctx context.Context, args callArgs, _ rpc.BlockNumber) (hexutil.Bytes, error) {
+	return api.chain.backend.CallContract(ctx, args.message(), nil)
+}
+
+func (api *ethAPI) EstimateGas(ctx context.Context, args callArgs) (hexutil.Uint64, error) {
+	gas, err := api.chain.backend.EstimateGas(ctx, args.message())
+	return hexutil.Uint64(gas), err
+}
+
+func (api *ethAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	price, err := api.chain.backend.SuggestGasPrice(ctx)
+	return (*hexutil.Big)(price), err
+}
+
+func (api *ethAPI) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	tip, err := api.chain.backend.SuggestGasTipCap(ctx)
+	return (*hexutil.Big)(tip), err
+}
+
+func (api *ethAPI) SendRawTransaction(ctx context.Context, input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+
+	api.chain.mu.Lock()
+	defer api.chain.mu.Unlock()
+
+	if err := api.chain.backend.SendTransaction(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	api.chain.backend.Commit()
+
+	return tx.Hash(), nil
+}
+
+func (api *ethAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return api.chain.backend.TransactionReceipt(ctx, hash)
+}
+
+func (api *ethAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	tx, pending, err := api.chain.backend.TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var block *types.Block
+	if !pending {
+		receipt, err := api.chain.backend.TransactionReceipt(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			block, err = api.chain.backend.BlockByHash(ctx, receipt.BlockHash)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return marshalTransaction(tx, block)
+}
+
+func (api *ethAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	block, err := api.chain.backend.BlockByNumber(ctx, blockNumber(&number))
+	if err != nil {
+		return nil, err
+	}
+	return marshalBlock(block, fullTx)
+}
+
+func (api *ethAPI) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (map[string]interface{}, error) {
+	block, err := api.chain.backend.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return marshalBlock(block, fullTx)
+}
+
+func (api *ethAPI) GetLogs(ctx context.Context, args filterArgs) ([]types.Log, error) {
+	query := ethereum.FilterQuery{
+		BlockHash: args.BlockHash,
+		Addresses: args.Addresses,
+		Topics:    args.Topics,
+	}
+	if args.BlockHash == nil {
+		query.FromBlock = blockNumber(args.FromBlock)
+		query.ToBlock = blockNumber(args.ToBlock)
+	}
+
+	logs, err := api.chain.backend.FilterLogs(ctx, query)
+	if logs == nil {
+		logs = []types.Log{}
+	}
+	return logs, err
+}
+
+// netAPI serves "net" JSON-RPC namespace.
+type netAPI struct {
+	chain *Chain
+}
+
+func (api *netAPI) Version() string {
+	return strconv.FormatInt(api.chain.ChainID(), 10)
+}
+
+func marshalFields(v json.Marshaler) (map[string]interface{}, error) {
+	raw, err := v.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	return fields, json.Unmarshal(raw, &fields)
+}
+
+func marshalTransaction(tx *types.Transaction, block *types.Block) (map[string]interface{}, error) {
+	fields, err := marshalFields(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if block != nil {
+		fields["blockHash"] = block.Hash()
+		fields["blockNumber"] = (*hexutil.Big)(block.Number())
+	}
+	if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+		fields["from"] = from
+	}
+	return fields, nil
+}
+
+func marshalBlock(block *types.Block, fullTx bool) (map[string]interface{}, error) {
+	if block == nil {
+		return nil, nil
+	}
+
+	fields, err := marshalFields(block.Header())
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]interface{}, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		if !fullTx {
+			transactions = append(transactions, tx.Hash())
+			continue
+		}
+
+		txFields, err := marshalTransaction(tx, block)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, txFields)
+	}
+	fields["transactions"] = transactions
+	fields["uncles"] = []common.Hash{}
+
+	return fields, nil
+}
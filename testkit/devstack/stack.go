@@ -0,0 +1,350 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package devstack
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gin-gonic/gin"
+	pc "github.com/mysteriumnetwork/payments/crypto"
+	"github.com/mysteriumnetwork/payments/registration"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/metadata"
+)
+
+// Devstack service names used for fault injection.
+const (
+	ServiceChain      = "chain"
+	ServiceHermes     = "hermes"
+	ServiceTransactor = "transactor"
+)
+
+// Config describes devstack services and test identities.
+type Config struct {
+	Host           string
+	ChainPort      int
+	HermesPort     int
+	TransactorPort int
+	ControlPort    int
+	// KeystoreDir holds test identities, existing identities are reused across runs.
+	KeystoreDir string
+	Identities  int
+	// Funds is the amount of MYST each test identity gets in its consumer channel.
+	Funds *big.Int
+	// Faults are injected into all services from the start.
+	Faults FaultConfig
+}
+
+// DefaultConfig returns devstack configuration listening on localhost.
+func DefaultConfig() Config {
+	funds, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	return Config{
+		Host:           "127.0.0.1",
+		ChainPort:      8545,
+		HermesPort:     8889,
+		TransactorPort: 8888,
+		ControlPort:    8890,
+		KeystoreDir:    "devstack-keystore",
+		Identities:     2,
+		Funds:          funds,
+	}
+}
+
+// Stack is a local payments environment: a simulated chain with payment contracts,
+// mock hermes, mock transactor and funded, registered test identities.
+type Stack struct {
+	config     Config
+	chain      *Chain
+	hermes     *Hermes
+	transactor *Transactor
+	identities []common.Address
+	faults     map[string]*Faults
+	servers    []*http.Server
+	hermesDir  string
+}
+
+// New deploys the simulated chain and registers test identities.
+func New(config Config) (*Stack, error) {
+	if err := config.Faults.Validate(); err != nil {
+		return nil, err
+	}
+
+	ks := keystore.NewKeyStore(config.KeystoreDir, keystore.LightScryptN, keystore.LightScryptP)
+	for len(ks.Accounts()) < config.Identities {
+		if _, err := ks.NewAccount(""); err != nil {
+			return nil, errors.Wrap(err, "failed to create test identity")
+		}
+	}
+	identities := ks.Accounts()[:config.Identities]
+
+	hermesDir, err := os.MkdirTemp("", "devstack-hermes")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create hermes keystore")
+	}
+	s := &Stack{
+		config:    config,
+		hermesDir: hermesDir,
+		faults: map[string]*Faults{
+			ServiceChain:      NewFaults(config.Faults),
+			ServiceHermes:     NewFaults(config.Faults),
+			ServiceTransactor: NewFaults(config.Faults),
+		},
+	}
+
+	operatorKey, err := crypto.GenerateKey()
+	if err != nil {
+		s.cleanup()
+		return nil, errors.Wrap(err, "failed to generate hermes operator key")
+	}
+	hermesKs := keystore.NewKeyStore(hermesDir, keystore.LightScryptN, keystore.LightScryptP)
+	operator, err := hermesKs.ImportECDSA(operatorKey, "")
+	if err != nil {
+		s.cleanup()
+		return nil, errors.Wrap(err, "failed to import hermes operator key")
+	}
+	if err := hermesKs.Unlock(operator, ""); err != nil {
+		s.cleanup()
+		return nil, errors.Wrap(err, "failed to unlock hermes operator key")
+	}
+
+	funded := make([]common.Address, 0, len(identities))
+	for _, acc := range identities {
+		funded = append(funded, acc.Address)
+	}
+	s.chain, err = NewChain(funded, operatorKey, s.url(config.HermesPort))
+	if err != nil {
+		s.cleanup()
+		return nil, err
+	}
+	s.hermes = NewHermes(s.chain, hermesKs, operator.Address)
+	s.transactor = NewTransactor(s.chain, s.hermes)
+
+	for _, acc := range identities {
+		if err := s.registerIdentity(ks, acc); err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.identities = append(s.identities, acc.Address)
+	}
+
+	return s, nil
+}
+
+// registerIdentity funds consumer channel of the test identity and registers it in the registry.
+func (s *Stack) registerIdentity(ks *keystore.KeyStore, acc accounts.Account) error {
+	if err := ks.Unlock(acc, ""); err != nil {
+		return errors.Wrapf(err, "failed to unlock test identity %s", acc.Address.Hex())
+	}
+
+	addresses := s.chain.Addresses()
+	channel, err := s.chain.ChannelAddress(acc.Address)
+	if err != nil {
+		return errors.Wrap(err, "failed to calculate channel address")
+	}
+	if err := s.chain.Mint(channel, s.config.Funds); err != nil {
+		return err
+	}
+
+	req := registration.Request{
+		RegistryAddress: strings.ToLower(addresses.Registry.Hex()),
+		HermesID:        strings.ToLower(addresses.Hermes.Hex()),
+		Stake:           new(big.Int),
+		Fee:             new(big.Int),
+		Beneficiary:     strings.ToLower(channel.Hex()),
+		ChainID:         s.chain.ChainID(),
+	}
+	signature, err := ks.SignHash(acc, crypto.Keccak256(req.GetMessage()))
+	if err != nil {
+		return errors.Wrap(err, "failed to sign registration request")
+	}
+	if err := pc.ReformatSignatureVForBC(signature); err != nil {
+		return errors.Wrap(err, "failed to reformat registration signature")
+	}
+
+	hash, err := s.chain.RegisterIdentity(addresses.Hermes, req.Stake, req.Fee, channel, signature)
+	if err != nil {
+		return err
+	}
+	s.transactor.registered(acc.Address, hash)
+
+	return s.hermes.Fund(acc.Address, s.config.Funds)
+}
+
+// Start serves devstack services until closed.
+func (s *Stack) Start() error {
+	chainHandler, err := s.chain.Handler()
+	if err != nil {
+		return errors.Wrap(err, "failed to create chain RPC handler")
+	}
+
+	services := []struct {
+		port    int
+		handler http.Handler
+	}{
+		{s.config.ChainPort, s.faults[ServiceChain].Wrap(chainHandler)},
+		{s.config.HermesPort, s.faults[ServiceHermes].Wrap(s.hermes.Handler())},
+		{s.config.TransactorPort, s.faults[ServiceTransactor].Wrap(s.transactor.Handler())},
+		{s.config.ControlPort, s.controlHandler()},
+	}
+	for _, service := range services {
+		listener, err := net.Listen("tcp", net.JoinHostPort(s.config.Host, strconv.Itoa(service.port)))
+		if err != nil {
+			return errors.Wrap(err, "failed to start devstack service")
+		}
+
+		server := &http.Server{Handler: service.handler}
+		s.servers = append(s.servers, server)
+		go func() {
+			if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+				log.Error().Err(err).Msg("Devstack service stopped")
+			}
+		}()
+	}
+
+	return nil
+}
+
+// Close stops devstack services and the chain.
+func (s *Stack) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, server := range s.servers {
+		server.Shutdown(ctx)
+	}
+	if s.chain != nil {
+		s.chain.Close()
+	}
+	s.cleanup()
+
+	return nil
+}
+
+func (s *Stack) cleanup() {
+	os.RemoveAll(s.hermesDir)
+}
+
+// Identities returns funded and registered test identities.
+func (s *Stack) Identities() []common.Address {
+	return s.identities
+}
+
+// Faults returns fault injector of the service.
+func (s *Stack) Faults(service string) (*Faults, bool) {
+	f, ok := s.faults[service]
+	return f, ok
+}
+
+func (s *Stack) url(port int) string {
+	return fmt.Sprintf("http://%s", net.JoinHostPort(s.config.Host, strconv.Itoa(port)))
+}
+
+// NodeFlags returns node flags pointing both chains of the node to the devstack.
+func (s *Stack) NodeFlags() []string {
+	addresses := s.chain.Addresses()
+	flags := []string{
+		fmt.Sprintf("--%s=%s", metadata.FlagNames.TransactorAddress, s.url(s.config.TransactorPort)+TransactorPath),
+		fmt.Sprintf("--%s=%d", metadata.FlagNames.DefaultChainIDFlag, s.chain.ChainID()),
+	}
+	for _, names := range []metadata.ChainDefinitionFlagNames{metadata.FlagNames.Chain1Flag, metadata.FlagNames.Chain2Flag} {
+		flags = append(flags,
+			fmt.Sprintf("--%s=%d", names.ChainIDFlag, s.chain.ChainID()),
+			fmt.Sprintf("--%s=%s", names.EtherClientRPCFlag, s.url(s.config.ChainPort)),
+			fmt.Sprintf("--%s=%s", names.RegistryAddress, addresses.Registry.Hex()),
+			fmt.Sprintf("--%s=%s", names.HermesID, addresses.Hermes.Hex()),
+			fmt.Sprintf("--%s=%s", names.ChannelImplAddress, addresses.ChannelImplementation.Hex()),
+			fmt.Sprintf("--%s=%s", names.MystAddress, addresses.Myst.Hex()),
+			fmt.Sprintf("--%s=%s", names.KnownHermesesFlag, addresses.Hermes.Hex()),
+		)
+	}
+	return flags
+}
+
+type faultsDTO struct {
+	Latency     string  `json:"latency"`
+	FailureRate float64 `json:"failure_rate"`
+	Requests    uint64  `json:"requests"`
+	Failed      uint64  `json:"failed"`
+}
+
+// controlHandler serves fault injection toggles:
+// GET /faults lists faults and request counters, PUT /faults/:service replaces faults of a service.
+func (s *Stack) controlHandler() http.Handler {
+	g := gin.New()
+	g.Use(gin.Recovery())
+
+	g.GET("/faults", func(c *gin.Context) {
+		res := make(map[string]faultsDTO, len(s.faults))
+		for name, f := range s.faults {
+			stats := f.Stats()
+			res[name] = faultsDTO{
+				Latency:     stats.Latency.String(),
+				FailureRate: stats.FailureRate,
+				Requests:    stats.Requests,
+				Failed:      stats.Failed,
+			}
+		}
+		c.JSON(http.StatusOK, res)
+	})
+	g.PUT("/faults/:service", func(c *gin.Context) {
+		f, ok := s.faults[c.Param("service")]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown service"})
+			return
+		}
+
+		var req faultsDTO
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		config := FaultConfig{FailureRate: req.FailureRate}
+		if req.Latency != "" {
+			latency, err := time.ParseDuration(req.Latency)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			config.Latency = latency
+		}
+		if err := f.Set(config); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+	g.GET("/identities", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.identities)
+	})
+
+	return g
+}
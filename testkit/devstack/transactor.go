@@ -0,0 +1,246 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package devstack
+
+import (
+	"math/big"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	pc "github.com/mysteriumnetwork/payments/crypto"
+	"github.com/rs/zerolog/log"
+
+	"github.com/mysteriumnetwork/node/identity/registry"
+)
+
+// TransactorPath is the path transactor API is served on.
+const TransactorPath = "/api/v1"
+
+// feeValidity is how long the zero fees returned by mock transactor stay valid.
+const feeValidity = time.Hour
+
+// Transactor is a mock transactor which submits registrations and settlements to the simulated chain
+// right away, free of charge.
+type Transactor struct {
+	chain  *Chain
+	hermes *Hermes
+
+	mu            sync.Mutex
+	registrations map[common.Address]registry.TransactorStatusResponse
+	queue         map[string]registry.QueueResponse
+	lastQueueID   int
+}
+
+// NewTransactor creates mock transactor.
+func NewTransactor(chain *Chain, hermes *Hermes) *Transactor {
+	return &Transactor{
+		chain:         chain,
+		hermes:        hermes,
+		registrations: make(map[common.Address]registry.TransactorStatusResponse),
+		queue:         make(map[string]registry.QueueResponse),
+	}
+}
+
+// Handler returns transactor HTTP API handler.
+func (t *Transactor) Handler() http.Handler {
+	g := gin.New()
+	g.Use(gin.Recovery())
+
+	api := g.Group(TransactorPath)
+	api.GET("/fee/:chain", t.combinedFees)
+	api.GET("/fee/:chain/register", t.fee)
+	api.GET("/fee/:chain/settle", t.fee)
+	api.GET("/fee/:chain/stake/decrease", t.fee)
+	api.POST("/identity/register", t.register)
+	api.POST("/identity/register/referer", t.register)
+	api.GET("/identity/register/provider/eligibility", t.eligible)
+	api.GET("/identity/register/eligibility/:id", t.eligible)
+	api.GET("/identity/:id/status", t.registrationStatus)
+	api.POST("/identity/settle_and_rebalance", t.settle)
+	api.POST("/identity/settle/into_stake", t.settle)
+	api.POST("/identity/pay_and_settle", t.settle)
+	api.POST("/identity/settle_with_beneficiary", t.settleWithBeneficiary)
+	api.GET("/queue/:id", t.queueStatus)
+	api.POST("/channel/open", t.ok)
+	api.POST("/channel/status", t.channelStatus)
+	api.POST("/stake/decrease", t.ok)
+
+	return g
+}
+
+func (t *Transactor) ok(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+func (t *Transactor) combinedFees(c *gin.Context) {
+	now := time.Now().UTC()
+	fees := registry.Fees{
+		DecreaseStake: new(big.Int),
+		Settle:        new(big.Int),
+		Register:      new(big.Int),
+		ValidUntil:    now.Add(feeValidity),
+	}
+	c.JSON(http.StatusOK, registry.CombinedFeesResponse{Current: fees, Last: fees, ServerTime: now})
+}
+
+func (t *Transactor) fee(c *gin.Context) {
+	c.JSON(http.StatusOK, registry.FeesResponse{Fee: new(big.Int), ValidUntil: time.Now().UTC().Add(feeValidity)})
+}
+
+func (t *Transactor) eligible(c *gin.Context) {
+	c.JSON(http.StatusOK, registry.EligibilityResponse{Eligible: true})
+}
+
+func (t *Transactor) register(c *gin.Context) {
+	var req registry.IdentityRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := common.HexToAddress(req.Identity)
+	hash, err := t.chain.RegisterIdentity(common.HexToAddress(req.HermesID), req.Stake, req.Fee, common.HexToAddress(req.Beneficiary), common.FromHex(req.Signature))
+	if err != nil {
+		log.Error().Err(err).Msgf("Devstack transactor failed to register identity %s", id.Hex())
+		t.failed(id)
+	} else {
+		t.registered(id, hash)
+		if err := t.hermes.Fund(id, new(big.Int)); err != nil {
+			log.Error().Err(err).Msgf("Devstack transactor failed to open hermes account for %s", id.Hex())
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
+
+// registered records identity registered directly on chain.
+func (t *Transactor) registered(id common.Address, hash common.Hash) {
+	now := time.Now().UTC()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.registrations[id] = registry.TransactorStatusResponse{
+		IdentityID:   id.Hex(),
+		Status:       registry.TransactorRegistrationEntryStatusSucceed,
+		TxHash:       hash.Hex(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		BountyAmount: new(big.Int),
+		ChainID:      t.chain.ChainID(),
+	}
+}
+
+// failed records failed identity registration.
+func (t *Transactor) failed(id common.Address) {
+	now := time.Now().UTC()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.registrations[id] = registry.TransactorStatusResponse{
+		IdentityID: id.Hex(),
+		Status:     registry.TransactorRegistrationEntryStatusFailed,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		ChainID:    t.chain.ChainID(),
+	}
+}
+
+func (t *Transactor) registrationStatus(c *gin.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status, ok := t.registrations[common.HexToAddress(c.Param("id"))]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "identity not found"})
+		return
+	}
+	c.JSON(http.StatusOK, []registry.TransactorStatusResponse{status})
+}
+
+func (t *Transactor) settle(c *gin.Context) {
+	var req registry.PromiseSettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, registry.SettleResponse{ID: t.settlePromise(req)})
+}
+
+func (t *Transactor) settleWithBeneficiary(c *gin.Context) {
+	var req registry.SettleWithBeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, registry.SettleResponse{ID: t.settlePromise(req.Promise)})
+}
+
+// settlePromise settles the promise on chain and returns ID of the finished queue entry.
+func (t *Transactor) settlePromise(req registry.PromiseSettlementRequest) string {
+	provider := common.HexToAddress(req.ProviderID)
+	promise := pc.Promise{
+		ChainID:   req.ChainID,
+		ChannelID: common.FromHex(req.ChannelID),
+		Amount:    req.Amount,
+		Fee:       req.TransactorFee,
+		R:         common.FromHex(req.Preimage),
+		Signature: common.FromHex(req.Signature),
+	}
+
+	entry := registry.QueueResponse{State: "done"}
+	hash, err := t.chain.SettlePromise(provider, promise)
+	if err != nil {
+		log.Error().Err(err).Msgf("Devstack transactor failed to settle promise of %s", provider.Hex())
+		entry.State = "error"
+		entry.Error = err.Error()
+	} else {
+		t.hermes.settled(provider, req.Amount)
+	}
+	entry.Hash = hash.Hex()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lastQueueID++
+	entry.ID = strconv.Itoa(t.lastQueueID)
+	t.queue[entry.ID] = entry
+
+	return entry.ID
+}
+
+func (t *Transactor) queueStatus(c *gin.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.queue[c.Param("id")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "queue entry not found"})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+func (t *Transactor) channelStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, registry.ChannelStatusResponse{Status: registry.ChannelStatusOpen})
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trace
+
+import (
+	"sync"
+	"time"
+)
+
+// Span is a finished tracer stage handed to the exporter.
+type Span struct {
+	SpanContext
+	// Parent is invalid for root spans.
+	Parent     SpanContext
+	Name       string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]string
+	// Error is the reason the traced operation failed, empty if it succeeded.
+	Error string
+}
+
+// Exporter sends finished spans to a tracing backend.
+type Exporter interface {
+	Export(spans []Span)
+}
+
+var (
+	exporterMu sync.RWMutex
+	exporter   Exporter
+)
+
+// SetExporter sets the exporter spans of all finished tracers are sent to, nil disables exporting.
+func SetExporter(e Exporter) {
+	exporterMu.Lock()
+	defer exporterMu.Unlock()
+
+	exporter = e
+}
+
+func currentExporter() Exporter {
+	exporterMu.RLock()
+	defer exporterMu.RUnlock()
+
+	return exporter
+}
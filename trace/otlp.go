@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trace
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	otlpTracesPath  = "/v1/traces"
+	otlpScopeName   = "github.com/mysteriumnetwork/node/trace"
+	otlpStopTimeout = 10 * time.Second
+)
+
+// OTLPExporter sends spans in batches to an OpenTelemetry collector using the OTLP/HTTP trace exporter.
+type OTLPExporter struct {
+	provider *sdktrace.TracerProvider
+	tracer   oteltrace.Tracer
+}
+
+// NewOTLPExporter returns a new exporter sending spans to the collector at endpoint, e.g. http://localhost:4318.
+// Headers, e.g. authentication ones required by hosted collectors, are added to every request.
+func NewOTLPExporter(endpoint string, headers map[string]string, serviceName, serviceVersion string) (*OTLPExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, errors.Errorf("invalid OTLP endpoint %q", endpoint)
+	}
+
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(strings.TrimSuffix(u.Path, "/") + otlpTracesPath),
+		otlptracehttp.WithHeaders(headers),
+	}
+	if u.Scheme == "http" {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	// The exporter only connects on the first export.
+	client, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create OTLP trace exporter")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(client),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithIDGenerator(contextIDs{}),
+		sdktrace.WithResource(resource.NewWithAttributes("",
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", serviceVersion),
+		)),
+	)
+
+	return &OTLPExporter{
+		provider: provider,
+		tracer:   provider.Tracer(otlpScopeName),
+	}, nil
+}
+
+// Export queues spans to be sent with the next batch. Spans are dropped if the collector can't keep up.
+func (e *OTLPExporter) Export(spans []Span) {
+	for _, s := range spans {
+		ctx := withSpanIDs(context.Background(), s.SpanContext)
+		if s.Parent.IsValid() {
+			ctx = oteltrace.ContextWithRemoteSpanContext(ctx, oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+				TraceID:    oteltrace.TraceID(s.Parent.TraceID),
+				SpanID:     oteltrace.SpanID(s.Parent.SpanID),
+				TraceFlags: oteltrace.FlagsSampled,
+				Remote:     true,
+			}))
+		}
+
+		keys := make([]string, 0, len(s.Attributes))
+		for key := range s.Attributes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		attributes := make([]attribute.KeyValue, 0, len(keys))
+		for _, key := range keys {
+			attributes = append(attributes, attribute.String(key, s.Attributes[key]))
+		}
+
+		_, span := e.tracer.Start(ctx, s.Name,
+			oteltrace.WithTimestamp(s.Start),
+			oteltrace.WithSpanKind(oteltrace.SpanKindInternal),
+			oteltrace.WithAttributes(attributes...),
+		)
+		if s.Error != "" {
+			span.SetStatus(codes.Error, s.Error)
+		}
+		span.End(oteltrace.WithTimestamp(s.End))
+	}
+}
+
+// Stop sends the remaining spans and stops the exporter.
+func (e *OTLPExporter) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), otlpStopTimeout)
+	defer cancel()
+
+	if err := e.provider.Shutdown(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to export remaining spans")
+	}
+}
+
+type spanIDsKey struct{}
+
+func withSpanIDs(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanIDsKey{}, sc)
+}
+
+// contextIDs makes the SDK reuse the IDs the tracer generated, so exported spans match the propagated traceparent.
+type contextIDs struct{}
+
+func (contextIDs) NewIDs(ctx context.Context) (oteltrace.TraceID, oteltrace.SpanID) {
+	sc, _ := ctx.Value(spanIDsKey{}).(SpanContext)
+	return oteltrace.TraceID(sc.TraceID), oteltrace.SpanID(sc.SpanID)
+}
+
+func (contextIDs) NewSpanID(ctx context.Context, _ oteltrace.TraceID) oteltrace.SpanID {
+	sc, _ := ctx.Value(spanIDsKey{}).(SpanContext)
+	return oteltrace.SpanID(sc.SpanID)
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (C) 2022 The "MysteriumNetwork/node" Authors.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package trace
+
+import (
+	"compress/gzip"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+
+	for _, value := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba9-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		_, err := ParseTraceparent(value)
+		assert.ErrorIs(t, err, ErrInvalidTraceparent, value)
+	}
+}
+
+func TestOTLPExporter_ExportsTracerStages(t *testing.T) {
+	var requests []*coltracepb.ExportTraceServiceRequest
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("Api-Key"))
+
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = gz
+		}
+		data, err := io.ReadAll(body)
+		assert.NoError(t, err)
+
+		req := &coltracepb.ExportTraceServiceRequest{}
+		assert.NoError(t, proto.Unmarshal(data, req))
+		requests = append(requests, req)
+
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer collector.Close()
+
+	exporter, err := NewOTLPExporter(collector.URL+"/", map[string]string{"Api-Key": "secret"}, "myst", "1.0.0")
+	require.NoError(t, err)
+	SetExporter(exporter)
+	defer SetExporter(nil)
+
+	parent, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+
+	tracer := NewTracer("Provider whole Connect")
+	tracer.SetParent(parent)
+	tracer.SetAttribute("service.type", "wireguard")
+	tracer.EndStage(tracer.StartStage("Provider P2P exchange"))
+	tracer.StartStage("Provider session create")
+	tracer.SetError(errors.New("first invoice was not paid"))
+	tracer.Finish(nil, "session-1")
+	exporter.Stop()
+
+	require.Len(t, requests, 1)
+	require.Len(t, requests[0].ResourceSpans, 1)
+	assert.Equal(t, map[string]string{"service.name": "myst", "service.version": "1.0.0"}, stringAttributes(requests[0].ResourceSpans[0].Resource.Attributes))
+
+	require.Len(t, requests[0].ResourceSpans[0].ScopeSpans, 1)
+	spans := requests[0].ResourceSpans[0].ScopeSpans[0].Spans
+	// Stages which did not end are not exported.
+	require.Len(t, spans, 2)
+	byName := make(map[string]*tracepb.Span)
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+	root, stage := byName["Provider whole Connect"], byName["Provider P2P exchange"]
+	require.NotNil(t, root)
+	require.NotNil(t, stage)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", hex.EncodeToString(root.TraceId))
+	assert.Equal(t, "00f067aa0ba902b7", hex.EncodeToString(root.ParentSpanId))
+	rootID := tracer.SpanContext().SpanID
+	assert.Equal(t, rootID[:], root.SpanId)
+	assert.Equal(t, map[string]string{"service.type": "wireguard", "session.id": "session-1"}, stringAttributes(root.Attributes))
+	assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, root.Status.Code)
+	assert.Equal(t, "first invoice was not paid", root.Status.Message)
+
+	assert.Equal(t, root.TraceId, stage.TraceId)
+	assert.Equal(t, root.SpanId, stage.ParentSpanId)
+	assert.Equal(t, tracepb.Status_STATUS_CODE_UNSET, stage.Status.GetCode())
+}
+
+func stringAttributes(attributes []*commonpb.KeyValue) map[string]string {
+	values := make(map[string]string, len(attributes))
+	for _, kv := range attributes {
+		values[kv.Key] = kv.Value.GetStringValue()
+	}
+	return values
+}
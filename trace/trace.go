@@ -35,7 +35,9 @@ const (
 // NewTracer returns new tracer instance.
 func NewTracer(name string) *Tracer {
 	tracer := &Tracer{
-		stages: make([]*stage, 0),
+		stages:     make([]*stage, 0),
+		traceID:    newTraceID(),
+		attributes: make(map[string]string),
 	}
 	tracer.name = tracer.StartStage(name)
 	return tracer
@@ -43,11 +45,60 @@ func NewTracer(name string) *Tracer {
 
 // Tracer represents tracer which records stages durations. It can be used
 // to record stages times for tracing how long it took time.
+//
+// Stages are exported as spans when the tracer finishes, the whole stage being the root span
+// and other stages its children.
 type Tracer struct {
 	name     string
 	mu       sync.Mutex
 	stages   []*stage
 	finished bool
+
+	traceID    TraceID
+	parent     SpanContext
+	attributes map[string]string
+	err        string
+}
+
+// SpanContext returns the span context of the whole stage, to be propagated to peers.
+func (t *Tracer) SpanContext() SpanContext {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.stages) == 0 {
+		return SpanContext{}
+	}
+	return SpanContext{TraceID: t.traceID, SpanID: t.stages[0].spanID}
+}
+
+// SetParent continues the trace of the peer, it must be set before the tracer finishes.
+func (t *Tracer) SetParent(parent SpanContext) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !parent.IsValid() {
+		return
+	}
+	t.parent = parent
+	t.traceID = parent.TraceID
+}
+
+// SetAttribute sets an attribute of the whole stage.
+func (t *Tracer) SetAttribute(key, value string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.attributes[key] = value
+}
+
+// SetError marks the traced operation as failed.
+func (t *Tracer) SetError(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err != nil {
+		t.err = err.Error()
+	}
 }
 
 // StartStage starts tracing stage for given key.
@@ -65,8 +116,9 @@ func (t *Tracer) StartStage(key string) string {
 	}
 
 	t.stages = append(t.stages, &stage{
-		key:   key,
-		start: time.Now(),
+		key:    key,
+		spanID: newSpanID(),
+		start:  time.Now(),
 	})
 	return key
 }
@@ -106,10 +158,46 @@ func (t *Tracer) Finish(eventPublisher eventbus.Publisher, id string) string {
 			strs = append(strs, fmt.Sprintf("%q did not start", s.key))
 		}
 	}
+	t.export(id)
 
 	return strings.Join(strs, ", ")
 }
 
+func (t *Tracer) export(id string) {
+	exporter := currentExporter()
+	if exporter == nil || len(t.stages) == 0 {
+		return
+	}
+
+	root := SpanContext{TraceID: t.traceID, SpanID: t.stages[0].spanID}
+	spans := make([]Span, 0, len(t.stages))
+	for i, s := range t.stages {
+		if s.end.IsZero() {
+			continue
+		}
+		span := Span{
+			SpanContext: SpanContext{TraceID: t.traceID, SpanID: s.spanID},
+			Parent:      root,
+			Name:        s.key,
+			Start:       s.start,
+			End:         s.end,
+		}
+		if i == 0 {
+			span.Parent = t.parent
+			span.Error = t.err
+			span.Attributes = make(map[string]string, len(t.attributes)+1)
+			for k, v := range t.attributes {
+				span.Attributes[k] = v
+			}
+			if id != "" {
+				span.Attributes["session.id"] = id
+			}
+		}
+		spans = append(spans, span)
+	}
+	exporter.Export(spans)
+}
+
 func (t *Tracer) findStage(key string) (*stage, bool) {
 	for _, s := range t.stages {
 		if s.key == key {
@@ -135,6 +223,7 @@ func (t *Tracer) publishStageEvent(eventPublisher eventbus.Publisher, id string,
 
 type stage struct {
 	key        string
+	spanID     SpanID
 	start, end time.Time
 }
 
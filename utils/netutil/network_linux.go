@@ -67,7 +67,7 @@ func addDefaultRoute(iface string) error {
 }
 
 func logNetworkStats() {
-	for _, args := range [][]string{{"iptables", "-L", "-n"}, {"iptables", "-L", "-n", "-t", "nat"}, {"ip", "route", "list"}, {"ip", "address", "list"}} {
+	for _, args := range [][]string{{"iptables", "-L", "-n"}, {"iptables", "-L", "-n", "-t", "nat"}, {"nft", "list", "ruleset"}, {"ip", "route", "list"}, {"ip", "address", "list"}} {
 		out, err := exec.Command("sudo", args...).CombinedOutput()
 		logOutputToTrace(out, err, args...)
 	}
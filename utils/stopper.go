@@ -36,8 +36,23 @@ func HardKiller(kill Killer) func() {
 	return newStopper(kill, os.Exit)
 }
 
+// RestartKiller invokes provided kill method and forces process to exit with the given non-zero code,
+// so that the service manager starts it again
+func RestartKiller(kill Killer, code int) func() {
+	return newStopper(kill, restartExitter(os.Exit, code))
+}
+
 type exitter func(code int)
 
+func restartExitter(exit exitter, code int) exitter {
+	return func(exitCode int) {
+		if exitCode == 0 {
+			exitCode = code
+		}
+		exit(exitCode)
+	}
+}
+
 func doNothingAfterKill(_ int) {
 
 }
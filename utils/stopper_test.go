@@ -84,3 +84,14 @@ func TestStopperExitsWithErrorWhenKillerFails(t *testing.T) {
 	assert.Equal(t, 1, exiter.LastCode)
 	assert.True(t, fakeKiller.Killed)
 }
+
+func TestRestartStopperExitsWithRestartCode(t *testing.T) {
+	exiter := newFakeExiter()
+	fakeKiller := newFakeKiller(false)
+
+	stopper := newStopper(fakeKiller.Kill, restartExitter(exiter.Exit, 75))
+	stopper()
+
+	assert.Equal(t, 75, exiter.LastCode)
+	assert.True(t, fakeKiller.Killed)
+}